@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"fmt"
+	"slices"
+	"text/tabwriter"
+	"time"
+
+	"github.com/c0tton-fluff/burp-mcp-server/internal/approval"
+	"github.com/spf13/cobra"
+)
+
+var approveCmd = &cobra.Command{
+	Use:   "approve [ID...]",
+	Short: "List or decide tool calls waiting for approval",
+	Long: `Approve tool calls that the running server is holding for confirmation.
+
+Without arguments, lists pending approvals. With IDs, approves them,
+or denies them with --deny. Use --show to print a request's raw bytes.`,
+	RunE: runApprove,
+}
+
+func init() {
+	approveCmd.Flags().Bool("deny", false, "Deny instead of approve")
+	approveCmd.Flags().Bool("show", false, "Print the raw request of each listed or given approval")
+	rootCmd.AddCommand(approveCmd)
+}
+
+func runApprove(cmd *cobra.Command, args []string) error {
+	cfg, err := getConfig(cmd)
+	if err != nil {
+		return err
+	}
+	q := approval.Open(cfg.ApprovalDir())
+	out := cmd.OutOrStdout()
+	show, _ := cmd.Flags().GetBool("show")
+
+	if len(args) == 0 || show {
+		pending, err := q.Pending()
+		if err != nil {
+			return err
+		}
+		if len(pending) == 0 {
+			fmt.Fprintln(out, "No pending approvals.")
+			return nil
+		}
+		w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "ID\tTOOL\tTARGET\tEXPIRES IN\tSUMMARY")
+		for _, r := range pending {
+			if len(args) > 0 && !slices.Contains(args, r.ID) {
+				continue
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", r.ID, r.Tool, r.Target, time.Until(r.ExpiresAt).Round(time.Second), r.Summary)
+			if show {
+				w.Flush()
+				fmt.Fprintf(out, "\n%s\n\n", r.Raw)
+			}
+		}
+		return w.Flush()
+	}
+
+	deny, _ := cmd.Flags().GetBool("deny")
+	for _, id := range args {
+		if err := q.Decide(id, !deny); err != nil {
+			return err
+		}
+		verb := "Approved"
+		if deny {
+			verb = "Denied"
+		}
+		fmt.Fprintf(out, "%s %s\n", verb, id)
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/c0tton-fluff/burp-mcp-server/internal/burp"
+	"github.com/c0tton-fluff/burp-mcp-server/internal/config"
+	"github.com/c0tton-fluff/burp-mcp-server/internal/selftest"
+	"github.com/c0tton-fluff/burp-mcp-server/internal/store"
+	"github.com/c0tton-fluff/burp-mcp-server/internal/tools"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/spf13/cobra"
+)
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Diagnose the config, the Burp connection, and the tools",
+	Long: `Check everything the server depends on and print a pass/fail report:
+
+  - the config file parses and validates, the store is writable, and the
+    scope entries are valid and not redundant
+  - Burp's MCP extension answers at --burp-url
+  - the extension offers every tool the wrappers call
+  - the wrappers work, each called once with a harmless request to a
+    built-in echo endpoint on loopback
+
+Tool calls ignore the config's scope, dry-run, and approval settings,
+which would otherwise block the echo endpoint; nothing leaves the machine.
+Run it first when something doesn't work, and include its output when
+reporting a problem.`,
+	Args: cobra.NoArgs,
+	RunE: runDoctor,
+}
+
+func init() {
+	rootCmd.AddCommand(doctorCmd)
+}
+
+func runDoctor(cmd *cobra.Command, args []string) error {
+	ctx, cancel := context.WithTimeout(cmd.Context(), 2*time.Minute)
+	defer cancel()
+	r := selftest.NewReport(cmd.OutOrStdout())
+
+	cfg, err := getConfig(cmd)
+	cfg = selftest.CheckConfig(r, configPath(cmd), cfg, err)
+
+	burpURL := getBurpURL(cmd)
+	burpClient, err := burp.NewClient(burpURL)
+	if err != nil {
+		return fmt.Errorf("failed to create Burp client: %w", err)
+	}
+	connected := selftest.CheckBurp(ctx, r, burpClient, burpURL)
+	if connected {
+		defer burpClient.Close()
+		selftest.CheckUpstream(ctx, r, burpClient.Session())
+	} else {
+		r.Skip("upstream tools", "Burp not connected")
+	}
+
+	target, err := selftest.StartTarget()
+	if err != nil {
+		return fmt.Errorf("start echo endpoint: %w", err)
+	}
+	defer target.Close()
+	tools.Configure(&config.Config{})
+	dir, err := os.MkdirTemp("", "burp-mcp-doctor")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+	st, err := store.Open(filepath.Join(dir, "store.json"))
+	if err != nil {
+		return err
+	}
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	if _, err := newServer(burpClient, st).Connect(ctx, serverTransport, nil); err != nil {
+		return err
+	}
+	session, err := mcp.NewClient(&mcp.Implementation{Name: "doctor", Version: version}, nil).Connect(ctx, clientTransport, nil)
+	if err != nil {
+		return err
+	}
+	defer session.Close()
+	selftest.CheckWrappers(ctx, r, session, target, connected)
+
+	r.Summary()
+	if r.Failed > 0 {
+		return fmt.Errorf("%d doctor check(s) failed", r.Failed)
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/c0tton-fluff/burp-mcp-server/internal/export"
+	"github.com/c0tton-fluff/burp-mcp-server/internal/store"
+	"github.com/spf13/cobra"
+)
+
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export recorded findings",
+	Long: `Export findings from the local store in a report or interchange format.
+
+Use --list to show the available formats.`,
+	Args: cobra.NoArgs,
+	RunE: runExport,
+}
+
+func init() {
+	exportCmd.Flags().StringP("format", "f", "markdown", "Export format")
+	exportCmd.Flags().StringP("output", "o", "", "Output file (default: stdout)")
+	exportCmd.Flags().Bool("list", false, "List available formats")
+	rootCmd.AddCommand(exportCmd)
+}
+
+func runExport(cmd *cobra.Command, args []string) error {
+	if list, _ := cmd.Flags().GetBool("list"); list {
+		w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+		for _, e := range export.Formats() {
+			fmt.Fprintf(w, "%s\t%s\t%s\n", e.Name(), e.Extension(), e.Description())
+		}
+		return w.Flush()
+	}
+
+	format, _ := cmd.Flags().GetString("format")
+	e, err := export.Get(format)
+	if err != nil {
+		return err
+	}
+
+	cfg, err := getConfig(cmd)
+	if err != nil {
+		return err
+	}
+	st, err := store.Open(cfg.StorePath())
+	if err != nil {
+		return err
+	}
+
+	out := cmd.OutOrStdout()
+	if path, _ := cmd.Flags().GetString("output"); path != "" {
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		out = f
+	}
+	return e.Export(out, st.Findings())
+}
@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/c0tton-fluff/burp-mcp-server/internal/export"
+	"github.com/c0tton-fluff/burp-mcp-server/internal/store"
+	"github.com/spf13/cobra"
+)
+
+var importCmd = &cobra.Command{
+	Use:   "import",
+	Short: "Import data into the local store",
+}
+
+var importIssuesCmd = &cobra.Command{
+	Use:   "issues FILE...",
+	Short: "Import scanner issues from Burp XML issue reports",
+	Long: `Import scanner issues from XML reports exported from Burp's GUI
+(Target > Site map > Issues > Report issues, XML format) into the local store.
+
+burp_get_scanner_issues serves them with source "imported", so findings stay
+available after Burp's scanner results are cleared. Issues already in the
+store (same serial number) are skipped, so re-importing a report is safe.`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runImportIssues,
+}
+
+func init() {
+	importCmd.AddCommand(importIssuesCmd)
+	rootCmd.AddCommand(importCmd)
+}
+
+func runImportIssues(cmd *cobra.Command, args []string) error {
+	cfg, err := getConfig(cmd)
+	if err != nil {
+		return err
+	}
+	st, err := store.Open(cfg.StorePath())
+	if err != nil {
+		return err
+	}
+
+	out := cmd.OutOrStdout()
+	for _, path := range args {
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		issues, err := export.ParseBurpXML(f, filepath.Base(path))
+		f.Close()
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		added, err := st.ImportIssues(issues)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "%s: imported %d of %d issues (%d already in store)\n", path, added, len(issues), len(issues)-added)
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/c0tton-fluff/burp-mcp-server/internal/callback"
+	"github.com/c0tton-fluff/burp-mcp-server/internal/config"
+	"github.com/spf13/cobra"
+)
+
+var listenCmd = &cobra.Command{
+	Use:   "listen",
+	Short: "Run HTTP and DNS listeners that record out-of-band callbacks",
+	Long: `Run a local callback listener for out-of-band testing without Burp
+Collaborator.
+
+Every HTTP request and DNS query received is appended to the callback
+log, which the running server reads with burp_get_callbacks. Point a
+domain's NS record (or a wildcard A record) at this host for DNS
+callbacks; use --dns-answer so lookups resolve back to the HTTP listener.`,
+	Args: cobra.NoArgs,
+	RunE: runListen,
+}
+
+func init() {
+	listenCmd.Flags().String("http", "", `HTTP listen address (default ":8080" unless only DNS is configured)`)
+	listenCmd.Flags().String("dns", "", `UDP listen address for DNS, e.g. ":53" (default: no DNS listener)`)
+	listenCmd.Flags().String("dns-answer", "", "IPv4 address returned for A queries (default: empty answers)")
+	listenCmd.Flags().String("log", "", "Interaction log file (default: callbacks.jsonl next to the store)")
+	rootCmd.AddCommand(listenCmd)
+}
+
+func runListen(cmd *cobra.Command, args []string) error {
+	cfg, err := getConfig(cmd)
+	if err != nil {
+		return err
+	}
+	lc := config.ListenConfig{}
+	if cfg.Listen != nil {
+		lc = *cfg.Listen
+	}
+	for flag, dst := range map[string]*string{"http": &lc.HTTP, "dns": &lc.DNS, "dns-answer": &lc.DNSAnswer, "log": &lc.Log} {
+		if cmd.Flags().Changed(flag) {
+			*dst, _ = cmd.Flags().GetString(flag)
+		}
+	}
+	if lc.HTTP == "" && lc.DNS == "" {
+		lc.HTTP = ":8080"
+	}
+	cfg.Listen = &lc
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+
+	log := callback.Open(cfg.CallbackLog())
+	logErr := func(err error) { fmt.Fprintf(os.Stderr, "callback log: %v\n", err) }
+
+	ctx, cancel := signal.NotifyContext(cmd.Context(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+	errCh := make(chan error, 2)
+
+	if lc.HTTP != "" {
+		ln, err := net.Listen("tcp", lc.HTTP)
+		if err != nil {
+			return fmt.Errorf("http listener: %w", err)
+		}
+		srv := &http.Server{Handler: callback.HTTPHandler(log, logErr), ReadHeaderTimeout: 10 * time.Second}
+		go func() {
+			if err := srv.Serve(ln); !errors.Is(err, http.ErrServerClosed) {
+				errCh <- fmt.Errorf("http listener: %w", err)
+			}
+		}()
+		defer func() {
+			shutdownCtx, done := context.WithTimeout(context.Background(), 2*time.Second)
+			defer done()
+			srv.Shutdown(shutdownCtx)
+		}()
+		fmt.Fprintf(os.Stderr, "HTTP callbacks on %s\n", ln.Addr())
+	}
+	if lc.DNS != "" {
+		conn, err := net.ListenPacket("udp", lc.DNS)
+		if err != nil {
+			return fmt.Errorf("dns listener: %w", err)
+		}
+		answer := net.ParseIP(lc.DNSAnswer)
+		go func() {
+			if err := callback.ServeDNS(ctx, conn, log, answer, logErr); err != nil {
+				errCh <- fmt.Errorf("dns listener: %w", err)
+			}
+		}()
+		fmt.Fprintf(os.Stderr, "DNS callbacks on %s\n", conn.LocalAddr())
+	}
+	fmt.Fprintf(os.Stderr, "Logging interactions to %s (Ctrl-C to stop)\n", log.Path())
+
+	select {
+	case <-ctx.Done():
+		return nil
+	case err := <-errCh:
+		return err
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/c0tton-fluff/burp-mcp-server/internal/config"
 	"github.com/spf13/cobra"
 )
 
@@ -26,6 +27,7 @@ func Execute() {
 
 func init() {
 	rootCmd.PersistentFlags().StringP("burp-url", "u", "", "Burp MCP SSE endpoint URL (or set BURP_MCP_URL env var)")
+	rootCmd.PersistentFlags().StringP("config", "c", "", "Path to JSON config file (or set BURP_MCP_CONFIG env var)")
 }
 
 // getBurpURL returns the Burp SSE URL from flag or environment variable.
@@ -39,3 +41,29 @@ func getBurpURL(cmd *cobra.Command) string {
 	}
 	return url
 }
+
+// getConfig loads the config from flag, environment variable, or the default path.
+// A missing default file is not an error; an explicitly named one is.
+func getConfig(cmd *cobra.Command) (*config.Config, error) {
+	path := configPath(cmd)
+	if path == "" {
+		return &config.Config{}, nil
+	}
+	return config.Load(path)
+}
+
+// configPath returns the config file getConfig loads, or "" when none is
+// named and the default file does not exist.
+func configPath(cmd *cobra.Command) string {
+	path, _ := cmd.Flags().GetString("config")
+	if path == "" {
+		path = os.Getenv("BURP_MCP_CONFIG")
+	}
+	if path == "" {
+		path = config.DefaultPath()
+		if _, err := os.Stat(path); err != nil {
+			return ""
+		}
+	}
+	return path
+}
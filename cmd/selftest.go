@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/c0tton-fluff/burp-mcp-server/internal/burp"
+	"github.com/c0tton-fluff/burp-mcp-server/internal/config"
+	"github.com/c0tton-fluff/burp-mcp-server/internal/selftest"
+	"github.com/c0tton-fluff/burp-mcp-server/internal/store"
+	"github.com/c0tton-fluff/burp-mcp-server/internal/tools"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/spf13/cobra"
+)
+
+var selftestCmd = &cobra.Command{
+	Use:   "selftest",
+	Short: "Run the attack tools against a bundled vulnerable target",
+	Long: `Start a small deliberately vulnerable HTTP server on loopback and run the
+tools against it end-to-end through the MCP layer: reflection, a
+race-prone coupon, ambiguous CL/TE framing, range probing, and dry run.
+
+Checks that go through Burp run only with --burp, using --burp-url.
+The config file is ignored so scope and approval settings can't block
+the checks; nothing leaves the machine.`,
+	Args: cobra.NoArgs,
+	RunE: runSelftest,
+}
+
+func init() {
+	selftestCmd.Flags().Bool("burp", false, "Also run checks that send through Burp")
+	rootCmd.AddCommand(selftestCmd)
+}
+
+func runSelftest(cmd *cobra.Command, args []string) error {
+	ctx, cancel := context.WithTimeout(cmd.Context(), 2*time.Minute)
+	defer cancel()
+
+	target, err := selftest.StartTarget()
+	if err != nil {
+		return fmt.Errorf("start target: %w", err)
+	}
+	defer target.Close()
+
+	tools.Configure(&config.Config{})
+	dir, err := os.MkdirTemp("", "burp-mcp-selftest")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+	st, err := store.Open(filepath.Join(dir, "store.json"))
+	if err != nil {
+		return err
+	}
+
+	withBurp, _ := cmd.Flags().GetBool("burp")
+	burpClient, err := burp.NewClient(getBurpURL(cmd))
+	if err != nil {
+		return fmt.Errorf("failed to create Burp client: %w", err)
+	}
+	if withBurp {
+		if _, err := burpClient.Connect(ctx); err != nil {
+			return fmt.Errorf("failed to connect to Burp MCP: %w", err)
+		}
+		defer burpClient.Close()
+	}
+
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	if _, err := newServer(burpClient, st).Connect(ctx, serverTransport, nil); err != nil {
+		return err
+	}
+	client := mcp.NewClient(&mcp.Implementation{Name: "selftest", Version: version}, nil)
+	session, err := client.Connect(ctx, clientTransport, nil)
+	if err != nil {
+		return err
+	}
+	defer session.Close()
+
+	out := cmd.OutOrStdout()
+	fmt.Fprintf(out, "Target listening on %s:%d\n", target.Host(), target.Port())
+	if failed := selftest.Run(ctx, session, target, out, withBurp); failed > 0 {
+		return fmt.Errorf("%d selftest check(s) failed", failed)
+	}
+	return nil
+}
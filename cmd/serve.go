@@ -3,12 +3,19 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"net"
+	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
 	"github.com/c0tton-fluff/burp-mcp-server/internal/burp"
+	"github.com/c0tton-fluff/burp-mcp-server/internal/config"
+	"github.com/c0tton-fluff/burp-mcp-server/internal/metrics"
+	"github.com/c0tton-fluff/burp-mcp-server/internal/prompts"
+	"github.com/c0tton-fluff/burp-mcp-server/internal/store"
 	"github.com/c0tton-fluff/burp-mcp-server/internal/tools"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 	"github.com/spf13/cobra"
@@ -25,12 +32,58 @@ clean, structured tools to Claude Code via stdio.`,
 }
 
 func init() {
+	serveCmd.Flags().StringSlice("scope", nil, "Allowed targets: hostnames, *.domain wildcards, IPs, or CIDRs (repeatable; adds to config scope)")
+	serveCmd.Flags().Bool("dry-run", false, "Preview outgoing requests instead of sending them")
+	serveCmd.Flags().Int("max-output-bytes", 0, "Cap every tool result at this many bytes, cutting the longest strings (overrides config; 0 = no cap)")
+	serveCmd.Flags().String("record", "", "Write every call to Burp and its response to this file (JSON lines)")
+	serveCmd.Flags().String("replay", "", "Answer calls to Burp from a file written by --record instead of connecting")
+	serveCmd.MarkFlagsMutuallyExclusive("record", "replay")
+	serveCmd.Flags().String("metrics-addr", "", `Serve metrics at http://ADDR/metrics, e.g. "127.0.0.1:9464" (overrides config)`)
 	rootCmd.AddCommand(serveCmd)
 }
 
 func runServe(cmd *cobra.Command, args []string) error {
 	burpURL := getBurpURL(cmd)
 
+	cfg, err := getConfig(cmd)
+	if err != nil {
+		return err
+	}
+	scope, _ := cmd.Flags().GetStringSlice("scope")
+	for _, entry := range scope {
+		if err := config.ValidateScopeEntry(entry); err != nil {
+			return fmt.Errorf("--scope: %w", err)
+		}
+	}
+	cfg.Scope = append(cfg.Scope, scope...)
+	if len(cfg.Scope) > 0 {
+		fmt.Fprintf(os.Stderr, "Scope locked to: %s\n", strings.Join(cfg.Scope, ", "))
+	}
+	if dry, _ := cmd.Flags().GetBool("dry-run"); dry {
+		cfg.DryRun = true
+	}
+	if cfg.DryRun {
+		fmt.Fprintf(os.Stderr, "Dry-run mode: requests are previewed, not sent\n")
+	}
+	if cmd.Flags().Changed("max-output-bytes") {
+		cfg.MaxOutputBytes, _ = cmd.Flags().GetInt("max-output-bytes")
+		if cfg.MaxOutputBytes < 0 {
+			return fmt.Errorf("--max-output-bytes: must not be negative")
+		}
+	}
+	if err := tools.CheckSchemaVersion(cfg.SchemaVersion); err != nil {
+		return fmt.Errorf("config: %w", err)
+	}
+	if cmd.Flags().Changed("metrics-addr") {
+		cfg.MetricsAddr, _ = cmd.Flags().GetString("metrics-addr")
+	}
+	tools.Configure(cfg)
+
+	st, err := store.Open(cfg.StorePath())
+	if err != nil {
+		return err
+	}
+
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
@@ -59,46 +112,190 @@ func runServe(cmd *cobra.Command, args []string) error {
 		}
 	}()
 
+	if cfg.MetricsAddr != "" {
+		ln, err := net.Listen("tcp", cfg.MetricsAddr)
+		if err != nil {
+			return fmt.Errorf("metrics listener: %w", err)
+		}
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", metrics.Default.Handler())
+		srv := &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+		go srv.Serve(ln)
+		defer srv.Close()
+		fmt.Fprintf(os.Stderr, "Metrics on http://%s/metrics\n", ln.Addr())
+	}
+
 	// Connect to Burp's MCP extension via SSE
 	fmt.Fprintf(os.Stderr, "Connecting to Burp MCP at %s...\n", burpURL)
 	burpClient, err := burp.NewClient(burpURL)
 	if err != nil {
 		return fmt.Errorf("failed to create Burp client: %w", err)
 	}
+	if cfg.Retry != nil {
+		policy, err := retryPolicy(cfg.Retry)
+		if err != nil {
+			return err
+		}
+		burpClient.SetRetryPolicy(policy)
+	}
+	for name, endpoint := range cfg.Instances {
+		if err := burpClient.AddInstance(name, endpoint); err != nil {
+			return fmt.Errorf("failed to add Burp instance %s: %w", name, err)
+		}
+	}
 
-	_, err = burpClient.Connect(ctx)
-	if err != nil {
-		return fmt.Errorf("failed to connect to Burp MCP: %w", err)
+	if path, _ := cmd.Flags().GetString("replay"); path != "" {
+		if err := burpClient.Replay(path); err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "Replaying Burp calls from %s\n", path)
+	} else {
+		if path, _ := cmd.Flags().GetString("record"); path != "" {
+			if err := burpClient.Record(path); err != nil {
+				return err
+			}
+			fmt.Fprintf(os.Stderr, "Recording Burp calls to %s\n", path)
+		}
+		if _, err := burpClient.Connect(ctx); err != nil {
+			return fmt.Errorf("failed to connect to Burp MCP: %w", err)
+		}
+		fmt.Fprintf(os.Stderr, "Connected to Burp MCP\n")
 	}
 	defer burpClient.Close()
-	fmt.Fprintf(os.Stderr, "Connected to Burp MCP\n")
 
-	// Create MCP server for Claude Code
+	server := newServer(burpClient, st)
+
+	// Run the server with stdio transport
+	fmt.Fprintf(os.Stderr, "Burp MCP server ready (stdio)\n")
+	if err := server.Run(ctx, &mcp.StdioTransport{}); err != nil {
+		return fmt.Errorf("server error: %w", err)
+	}
+
+	return nil
+}
+
+// newServer creates the MCP server with every tool, resource, and prompt registered.
+func newServer(burpClient *burp.Client, st *store.Store) *mcp.Server {
+	watcher := tools.NewResourceWatcher(burpClient)
 	server := mcp.NewServer(
 		&mcp.Implementation{
 			Name:    "burp-mcp-server",
 			Version: version,
 		},
-		nil,
+		&mcp.ServerOptions{
+			SubscribeHandler:   watcher.Subscribe,
+			UnsubscribeHandler: watcher.Unsubscribe,
+		},
 	)
+	server.AddReceivingMiddleware(tools.Middleware(st)...)
 
-	// Register tools
 	tools.RegisterSendRequestTool(server, burpClient)
 	tools.RegisterBatchSendTool(server, burpClient)
-	tools.RegisterGetProxyHistoryTool(server, burpClient)
+	tools.RegisterGetProxyHistoryTool(server, burpClient, st)
 	tools.RegisterGetRequestTool(server, burpClient)
-	tools.RegisterGetScannerIssuesTool(server, burpClient)
+	tools.RegisterGetScannerIssuesTool(server, burpClient, st)
+	tools.RegisterGetIssueEvidenceTool(server, burpClient, st)
+	tools.RegisterVerifyIssueTool(server, burpClient, st)
+	tools.RegisterSetIssueStatusTool(server, burpClient, st)
+	tools.RegisterRunPassiveChecksTool(server, burpClient, st)
+	tools.RegisterScanSecretsTool(server, burpClient, st)
+	tools.RegisterGetCoverageTool(server, burpClient, st)
+	tools.RegisterListParametersTool(server, burpClient, st)
 	tools.RegisterCreateRepeaterTabTool(server, burpClient)
 	tools.RegisterSendToIntruderTool(server, burpClient)
+	tools.RegisterSendToOrganizerTool(server, burpClient)
+	tools.RegisterConditionalProbeTool(server, burpClient)
+	tools.RegisterRangeProbeTool(server)
+	tools.RegisterListInstancesTool(server, burpClient)
+	tools.RegisterRecordFindingTool(server, st)
+	tools.RegisterRetestFindingTool(server, burpClient, st)
+	tools.RegisterEngagementSummaryTool(server, st)
+	tools.RegisterExportTool(server, st)
 	tools.RegisterEncodeTool(server)
 	tools.RegisterDecodeTool(server)
+	tools.RegisterHexdumpTool(server)
+	tools.RegisterBuildMultipartTool(server)
+	tools.RegisterModifyRequestTool(server)
+	tools.RegisterDecodeProtobufTool(server)
 	tools.RegisterRaceRequestTool(server)
+	tools.RegisterGraphQLParseTool(server)
+	tools.RegisterGraphQLIntrospectTool(server, burpClient)
+	tools.RegisterSAMLDecodeTool(server)
+	tools.RegisterDecodeJWTTool(server)
+	tools.RegisterAnalyzeCookiesTool(server, burpClient)
+	tools.RegisterAuditHeadersTool(server, burpClient)
+	tools.RegisterCORSProbeTool(server, burpClient)
+	tools.RegisterRedirectProbeTool(server, burpClient)
+	tools.RegisterTraversalProbeTool(server, burpClient)
+	tools.RegisterSSTIProbeTool(server, burpClient)
+	tools.RegisterHPPProbeTool(server, burpClient)
+	tools.RegisterCredentialTestTool(server, burpClient)
+	tools.RegisterRateProbeTool(server, burpClient)
+	tools.RegisterHostHeaderProbeTool(server)
+	tools.RegisterMethodProbeTool(server, burpClient)
+	tools.RegisterForbiddenBypassTool(server, burpClient)
+	tools.RegisterCacheProbeTool(server, burpClient)
+	tools.RegisterCrawlTool(server, st)
+	tools.RegisterJSEndpointsTool(server, burpClient)
+	tools.RegisterMetaFilesTool(server)
+	tools.RegisterImportOpenAPITool(server, st)
+	tools.RegisterFingerprintTool(server, burpClient)
+	tools.RegisterWAFDetectTool(server, burpClient)
+	tools.RegisterDNSLookupTool(server)
+	tools.RegisterTLSInfoTool(server)
+	tools.RegisterPortProbeTool(server)
+	tools.RegisterIPEncodeTool(server)
+	tools.RegisterUnicodeVariantsTool(server)
+	tools.RegisterSSRFProbeTool(server, burpClient)
+	tools.RegisterXSSVerifyTool(server, burpClient)
+	tools.RegisterUploadProbeTool(server, burpClient)
+	tools.RegisterDeserPayloadTool(server)
+	tools.RegisterOAuthTokenTool(server, burpClient)
+	tools.RegisterAuthzMatrixTool(server, burpClient)
+	tools.RegisterIDORSweepTool(server, burpClient)
+	tools.RegisterExtractTool(server, burpClient)
+	tools.RegisterGrepResponsesTool(server, burpClient, st)
+	tools.RegisterToCurlTool(server, burpClient)
+	tools.RegisterFromCurlTool(server)
+	tools.RegisterRunNucleiTool(server, st)
+	tools.RegisterExportSqlmapTool(server, burpClient)
+	tools.RegisterGetTaskTool(server)
+	tools.RegisterCancelTaskTool(server)
+	tools.RegisterListTasksTool(server)
+	tools.RegisterGetCallbacksTool(server)
+	tools.RegisterWatchIssuesTool(server, burpClient, st)
+	tools.RegisterUnwatchTool(server)
+	tools.RegisterSaveViewTool(server, st)
+	tools.RegisterListViewsTool(server, st)
+	tools.RegisterSnapshotHistoryTool(server, burpClient, st)
+	tools.RegisterGetSnapshotTool(server, st)
+	tools.RegisterDeleteProxyHistoryTool(server, burpClient, st)
 
-	// Run the server with stdio transport
-	fmt.Fprintf(os.Stderr, "Burp MCP server ready (stdio)\n")
-	if err := server.Run(ctx, &mcp.StdioTransport{}); err != nil {
-		return fmt.Errorf("server error: %w", err)
-	}
+	tools.RegisterResources(server, burpClient, watcher)
+	prompts.Register(server)
+	return server
+}
 
-	return nil
+// retryPolicy converts the config retry section into a Burp client policy.
+// Omitted fields keep their defaults.
+func retryPolicy(rc *config.RetryConfig) (burp.RetryPolicy, error) {
+	p := burp.DefaultRetryPolicy
+	if rc.MaxRetries != nil {
+		p.MaxRetries = *rc.MaxRetries
+	}
+	if rc.BackoffMs > 0 {
+		p.InitialBackoff = time.Duration(rc.BackoffMs) * time.Millisecond
+	}
+	if rc.MaxBackoffMs > 0 {
+		p.MaxBackoff = time.Duration(rc.MaxBackoffMs) * time.Millisecond
+	}
+	if len(rc.RetryOn) > 0 {
+		for _, class := range rc.RetryOn {
+			if !burp.ValidRetryClass(class) {
+				return p, fmt.Errorf("retry.retryOn: unknown class %q (want timeout, traffic_timeout, connection, or bad_gateway)", class)
+			}
+		}
+		p.RetryOn = rc.RetryOn
+	}
+	return p, nil
 }
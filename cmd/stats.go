@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/c0tton-fluff/burp-mcp-server/internal/metrics"
+	"github.com/spf13/cobra"
+)
+
+var statsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Summarize the running server's metrics",
+	Long: `Read the metrics endpoint of a running server and summarize tool calls,
+latencies, error rates, and traffic per target.
+
+The server publishes metrics only when started with --metrics-addr or
+with metricsAddr in the config.`,
+	RunE: runStats,
+}
+
+func init() {
+	statsCmd.Flags().String("addr", "", "Metrics address of the running server (default: metricsAddr from config)")
+	statsCmd.Flags().Bool("json", false, "Print the summary as JSON")
+	rootCmd.AddCommand(statsCmd)
+}
+
+func runStats(cmd *cobra.Command, args []string) error {
+	addr, _ := cmd.Flags().GetString("addr")
+	if addr == "" {
+		cfg, err := getConfig(cmd)
+		if err != nil {
+			return err
+		}
+		addr = cfg.MetricsAddr
+	}
+	if addr == "" {
+		return fmt.Errorf("no metrics address: pass --addr or set metricsAddr in the config")
+	}
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Get("http://" + addr + "/metrics")
+	if err != nil {
+		return fmt.Errorf("read metrics: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("read metrics: %s", resp.Status)
+	}
+	samples, err := metrics.Parse(resp.Body)
+	if err != nil {
+		return fmt.Errorf("parse metrics: %w", err)
+	}
+	summary := metrics.Summarize(samples)
+
+	out := cmd.OutOrStdout()
+	if asJSON, _ := cmd.Flags().GetBool("json"); asJSON {
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(summary)
+	}
+
+	if !summary.Since.IsZero() {
+		fmt.Fprintf(out, "Server up since %s (%s)\n\n", summary.Since.Local().Format(time.DateTime), time.Since(summary.Since).Round(time.Second))
+	}
+	if len(summary.Tools) == 0 {
+		fmt.Fprintln(out, "No tool calls yet.")
+	} else {
+		w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "TOOL\tCALLS\tERRORS\tERROR RATE\tMEAN\tP95\tERROR CODES")
+		for _, t := range summary.Tools {
+			var codes []string
+			for _, code := range slices.Sorted(maps.Keys(t.ErrorCodes)) {
+				codes = append(codes, fmt.Sprintf("%s=%d", code, t.ErrorCodes[code]))
+			}
+			fmt.Fprintf(w, "%s\t%d\t%d\t%.1f%%\t%s\t%s\t%s\n", t.Tool, t.Calls, t.Errors, 100*t.ErrorRate,
+				seconds(t.MeanSeconds), seconds(t.P95Seconds), strings.Join(codes, ", "))
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+	}
+	if len(summary.Targets) > 0 {
+		fmt.Fprintln(out)
+		w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "TARGET\tREQUESTS\tSENT\tRECEIVED")
+		for _, t := range summary.Targets {
+			fmt.Fprintf(w, "%s\t%d\t%d\t%d\n", t.Target, t.Requests, t.BytesSent, t.BytesReceived)
+		}
+		return w.Flush()
+	}
+	return nil
+}
+
+// seconds renders a latency in seconds as a rounded duration.
+func seconds(s float64) string {
+	return time.Duration(s * float64(time.Second)).Round(time.Millisecond).String()
+}
@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/c0tton-fluff/burp-mcp-server/internal/selftest"
+	"github.com/spf13/cobra"
+)
+
+var testserverCmd = &cobra.Command{
+	Use:   "testserver",
+	Short: "Serve deterministic test endpoints for offline development",
+	Long: `Run the target the selftest uses as a standalone HTTP/1.1 server, so tool
+behavior (race timing, redirects, chunking, decompression) can be tried
+without a live target or Burp:
+
+  GET    /search?q=X     reflects X unescaped
+  POST   /echo           reports how the body was framed and what it contained
+  GET    /delay?ms=N     answers after N milliseconds (at most 30s)
+  GET    /redirect?n=N   redirects N times, then answers 200
+  GET    /chunked?n=N    sends its body in N chunks (default 3)
+  GET    /gzip           sends a gzip-compressed body
+  GET    /counter        reports the counter
+  POST   /counter        increments the counter with a check-then-act race
+  POST   /redeem         spends a single-use coupon with a check-then-act race
+  DELETE /counter        resets the counter and the coupon
+
+Its parser is deliberately lenient: it accepts both Content-Length and
+Transfer-Encoding and lets Content-Length win. Serve it on loopback only.`,
+	Args: cobra.NoArgs,
+	RunE: runTestserver,
+}
+
+func init() {
+	testserverCmd.Flags().String("listen", "127.0.0.1:8089", "Listen address")
+	rootCmd.AddCommand(testserverCmd)
+}
+
+func runTestserver(cmd *cobra.Command, args []string) error {
+	addr, _ := cmd.Flags().GetString("listen")
+	target, err := selftest.ListenTarget(addr)
+	if err != nil {
+		return fmt.Errorf("listen: %w", err)
+	}
+	defer target.Close()
+	fmt.Fprintf(os.Stderr, "Test server on http://%s:%d (Ctrl-C to stop)\n", target.Host(), target.Port())
+
+	ctx, cancel := signal.NotifyContext(cmd.Context(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+	<-ctx.Done()
+	return nil
+}
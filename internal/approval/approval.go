@@ -0,0 +1,167 @@
+// Package approval is a file-based queue of tool calls waiting for a human
+// decision. The MCP server submits a request and waits; the approve command,
+// running in another process, lists pending requests and records decisions.
+package approval
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Request is a tool call waiting for approval.
+type Request struct {
+	ID        string    `json:"id"`
+	Tool      string    `json:"tool"`
+	Summary   string    `json:"summary"`
+	Target    string    `json:"target,omitempty"`
+	Raw       string    `json:"raw,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// Decision outcomes returned by Wait.
+var (
+	ErrDenied  = errors.New("denied")
+	ErrExpired = errors.New("not approved in time")
+)
+
+// pollInterval is how often Wait checks for a decision.
+var pollInterval = 250 * time.Millisecond
+
+// Queue stores pending requests as <id>.json files in a directory, and
+// decisions as <id>.approved or <id>.denied next to them.
+type Queue struct {
+	dir string
+}
+
+// Open returns the queue in dir. The directory is created on first submit.
+func Open(dir string) *Queue {
+	return &Queue{dir: dir}
+}
+
+// Dir returns the directory backing the queue.
+func (q *Queue) Dir() string {
+	return q.dir
+}
+
+// Submit adds req to the queue, assigning its ID and timestamps.
+func (q *Queue) Submit(req *Request, timeout time.Duration) error {
+	if err := os.MkdirAll(q.dir, 0o700); err != nil {
+		return fmt.Errorf("create approval directory: %w", err)
+	}
+	var b [4]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return err
+	}
+	req.ID = hex.EncodeToString(b[:])
+	req.CreatedAt = time.Now().UTC()
+	req.ExpiresAt = req.CreatedAt.Add(timeout)
+
+	raw, err := json.MarshalIndent(req, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode approval request: %w", err)
+	}
+	tmp := q.path(req.ID, ".tmp")
+	if err := os.WriteFile(tmp, raw, 0o600); err != nil {
+		return fmt.Errorf("write approval request: %w", err)
+	}
+	return os.Rename(tmp, q.path(req.ID, ".json"))
+}
+
+// Wait blocks until id is approved (nil), denied (ErrDenied), expires
+// (ErrExpired), or ctx ends. The request and its decision are removed.
+func (q *Queue) Wait(ctx context.Context, id string, timeout time.Duration) error {
+	defer q.remove(id)
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+	tick := time.NewTicker(pollInterval)
+	defer tick.Stop()
+	for {
+		if exists(q.path(id, ".approved")) {
+			return nil
+		}
+		if exists(q.path(id, ".denied")) {
+			return ErrDenied
+		}
+		select {
+		case <-tick.C:
+		case <-deadline.C:
+			return ErrExpired
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// Pending returns the requests still waiting for a decision, oldest first.
+func (q *Queue) Pending() ([]Request, error) {
+	entries, err := os.ReadDir(q.dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read approval directory: %w", err)
+	}
+	now := time.Now()
+	var out []Request
+	for _, e := range entries {
+		id, ok := strings.CutSuffix(e.Name(), ".json")
+		if !ok || q.decided(id) {
+			continue
+		}
+		raw, err := os.ReadFile(q.path(id, ".json"))
+		if err != nil {
+			continue // taken back by the server meanwhile
+		}
+		var req Request
+		if err := json.Unmarshal(raw, &req); err != nil || now.After(req.ExpiresAt) {
+			continue
+		}
+		out = append(out, req)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].CreatedAt.Before(out[j].CreatedAt) })
+	return out, nil
+}
+
+// Decide approves or denies a pending request.
+func (q *Queue) Decide(id string, approve bool) error {
+	if strings.ContainsAny(id, `/\.`) || !exists(q.path(id, ".json")) {
+		return fmt.Errorf("no pending approval %q", id)
+	}
+	if q.decided(id) {
+		return fmt.Errorf("approval %q is already decided", id)
+	}
+	ext := ".denied"
+	if approve {
+		ext = ".approved"
+	}
+	return os.WriteFile(q.path(id, ext), nil, 0o600)
+}
+
+func (q *Queue) decided(id string) bool {
+	return exists(q.path(id, ".approved")) || exists(q.path(id, ".denied"))
+}
+
+func (q *Queue) remove(id string) {
+	for _, ext := range []string{".json", ".approved", ".denied"} {
+		os.Remove(q.path(id, ext))
+	}
+}
+
+func (q *Queue) path(id, ext string) string {
+	return filepath.Join(q.dir, id+ext)
+}
+
+func exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
@@ -0,0 +1,65 @@
+package approval
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func init() {
+	pollInterval = 5 * time.Millisecond
+}
+
+func TestQueue_ApproveAndDeny(t *testing.T) {
+	q := Open(t.TempDir())
+
+	for _, approve := range []bool{true, false} {
+		req := &Request{Tool: "burp_race_request", Summary: "race 20x POST /redeem"}
+		if err := q.Submit(req, time.Minute); err != nil {
+			t.Fatal(err)
+		}
+		pending, err := q.Pending()
+		if err != nil || len(pending) != 1 || pending[0].ID != req.ID || pending[0].Tool != "burp_race_request" {
+			t.Fatalf("pending = %+v, err = %v", pending, err)
+		}
+
+		done := make(chan error, 1)
+		go func() { done <- q.Wait(context.Background(), req.ID, time.Minute) }()
+		if err := q.Decide(req.ID, approve); err != nil {
+			t.Fatal(err)
+		}
+		err = <-done
+		if approve && err != nil || !approve && !errors.Is(err, ErrDenied) {
+			t.Errorf("approve=%v: Wait = %v", approve, err)
+		}
+
+		if pending, _ := q.Pending(); len(pending) != 0 {
+			t.Errorf("decided request still pending: %+v", pending)
+		}
+	}
+}
+
+func TestQueue_Expires(t *testing.T) {
+	q := Open(t.TempDir())
+	req := &Request{Tool: "burp_send_to_intruder"}
+	if err := q.Submit(req, 20*time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	if err := q.Wait(context.Background(), req.ID, 20*time.Millisecond); !errors.Is(err, ErrExpired) {
+		t.Errorf("Wait = %v, want ErrExpired", err)
+	}
+	if err := q.Decide(req.ID, true); err == nil {
+		t.Error("expired request should not be approvable")
+	}
+}
+
+func TestQueue_DecideRejectsPaths(t *testing.T) {
+	q := Open(t.TempDir())
+	if err := q.Decide("../x", true); err == nil {
+		t.Error("expected error for path-like ID")
+	}
+	if pending, err := Open(t.TempDir() + "/missing").Pending(); err != nil || pending != nil {
+		t.Errorf("missing dir: %v, %v", pending, err)
+	}
+}
@@ -0,0 +1,108 @@
+package burp
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+)
+
+// IsBinary reports whether body is not readable text: it isn't valid UTF-8,
+// or it contains NUL bytes or more than a sprinkling of control characters.
+func IsBinary(body string) bool {
+	if !utf8.ValidString(trimPartialRunes(body)) {
+		return true
+	}
+	control := 0
+	for i := 0; i < len(body); i++ {
+		c := body[i]
+		if c == 0 {
+			return true
+		}
+		if c < 0x20 && c != '\t' && c != '\n' && c != '\r' && c != '\f' && c != 0x1b || c == 0x7f {
+			control++
+		}
+	}
+	return control*100 > len(body)
+}
+
+// trimPartialRunes drops up to three bytes of a multi-byte character cut off
+// at either end of s, as happens when a body window starts or ends mid-rune.
+func trimPartialRunes(s string) string {
+	for i := 0; i < 3 && len(s) > 0 && !utf8.RuneStart(s[0]); i++ {
+		s = s[1:]
+	}
+	for i := 1; i <= 3 && i <= len(s); i++ {
+		if c := s[len(s)-i]; utf8.RuneStart(c) {
+			if c >= 0x80 && !utf8.FullRuneInString(s[len(s)-i:]) {
+				s = s[:len(s)-i]
+			}
+			break
+		}
+	}
+	return s
+}
+
+// magicTypes are signatures http.DetectContentType doesn't know.
+var magicTypes = []struct {
+	offset    int
+	magic     string
+	mediaType string
+}{
+	{0, "\x7fELF", "application/x-elf"},
+	{0, "MZ", "application/vnd.microsoft.portable-executable"},
+	{0, "BZh", "application/x-bzip2"},
+	{0, "7z\xbc\xaf\x27\x1c", "application/x-7z-compressed"},
+	{0, "\x28\xb5\x2f\xfd", "application/zstd"},
+	{0, "\xca\xfe\xba\xbe", "application/java-vm"},
+	{0, "\xac\xed\x00\x05", "application/x-java-serialized-object"},
+	{0, "SQLite format 3\x00", "application/vnd.sqlite3"},
+	{0, "\x89HDF\r\n\x1a\n", "application/x-hdf5"},
+	{4, "ftypheic", "image/heic"},
+	{0, "\x00\x00\x00\x0cjP  ", "image/jp2"},
+}
+
+// SniffFileType identifies body from its leading magic bytes and returns a
+// media type such as "image/png", or "" when the bytes aren't recognized.
+func SniffFileType(body string) string {
+	for _, m := range magicTypes {
+		if strings.HasPrefix(body[min(m.offset, len(body)):], m.magic) {
+			return m.mediaType
+		}
+	}
+	mt, _, _ := strings.Cut(http.DetectContentType([]byte(body)), ";")
+	// Generic verdicts aren't an identification.
+	if mt == "application/octet-stream" || mt == "text/plain" {
+		return ""
+	}
+	return mt
+}
+
+// Hexdump renders data as a classic hex dump: offset, 16 hex bytes, and
+// their printable ASCII, one line per 16 bytes. Offsets start at base.
+func Hexdump(data []byte, base int) string {
+	var sb strings.Builder
+	for off := 0; off < len(data); off += 16 {
+		line := data[off:min(off+16, len(data))]
+		fmt.Fprintf(&sb, "%08x  ", base+off)
+		for i := 0; i < 16; i++ {
+			if i < len(line) {
+				fmt.Fprintf(&sb, "%02x ", line[i])
+			} else {
+				sb.WriteString("   ")
+			}
+			if i == 7 {
+				sb.WriteByte(' ')
+			}
+		}
+		sb.WriteString(" |")
+		for _, c := range line {
+			if c < 0x20 || c > 0x7e {
+				c = '.'
+			}
+			sb.WriteByte(c)
+		}
+		sb.WriteString("|\n")
+	}
+	return sb.String()
+}
@@ -0,0 +1,53 @@
+package burp
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+func TestIsBinary(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want bool
+	}{
+		{"html", "<html><body>héllo</body></html>\r\n", false},
+		{"empty", "", false},
+		{"cut mid-rune", "price: 10 €"[:12], false},
+		{"starts mid-rune", "€ sign"[1:], false},
+		{"png", "\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR", true},
+		{"invalid utf-8", "caf\xe9 au lait", true},
+		{"protobuf", "\x08\x96\x01\x12\x07testing", true},
+	}
+	for _, tt := range tests {
+		if got := IsBinary(tt.body); got != tt.want {
+			t.Errorf("%s: IsBinary = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestSniffFileType(t *testing.T) {
+	tests := map[string]string{
+		"\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR": "image/png",
+		"\x1f\x8b\x08\x00\x00\x00":            "application/x-gzip",
+		"\x7fELF\x02\x01\x01":                 "application/x-elf",
+		"\xac\xed\x00\x05sr\x00":              "application/x-java-serialized-object",
+		"\x08\x96\x01":                        "",
+		"plain words":                         "",
+	}
+	for body, want := range tests {
+		if got := SniffFileType(body); got != want {
+			t.Errorf("SniffFileType(%q) = %q, want %q", body, got, want)
+		}
+	}
+}
+
+func TestHexdump(t *testing.T) {
+	data := []byte("HTTP/1.1 200 OK\r\n\x00\xffabc")
+	if got, want := Hexdump(data, 0), hex.Dump(data); got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+	if got, want := Hexdump([]byte("AB"), 0x20), "00000020  41 42                                             |AB|\n"; got != want {
+		t.Errorf("based offset:\ngot  %q\nwant %q", got, want)
+	}
+}
@@ -2,8 +2,10 @@ package burp
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -18,8 +20,23 @@ const DefaultToolTimeout = 30 * time.Second
 // Prevents overwhelming the single SSE connection under batch workloads.
 const maxConcurrentCalls = 4
 
+// DefaultInstance is the name of the Burp instance given on the command line.
+const DefaultInstance = "default"
+
+// ErrUnreachable marks a call that failed because Burp's MCP extension could
+// not be reached: the SSE connection failed or dropped and did not come back.
+var ErrUnreachable = errors.New("burp unreachable")
+
+// ErrUpstream marks a call that reached Burp but that Burp's tool failed.
+var ErrUpstream = errors.New("burp error")
+
 // Client wraps the MCP client connection to Burp's SSE endpoint.
 // Automatically reconnects when the SSE connection drops.
+//
+// The client created by NewClient is also a registry of additional named
+// Burp instances (see AddInstance). Calls are routed to an instance via
+// WithInstance on the context; each instance connects lazily on first use
+// and tracks its own health.
 type Client struct {
 	endpoint   string
 	client     *mcp.Client
@@ -28,6 +45,49 @@ type Client struct {
 	mu         sync.Mutex
 	ctx        context.Context
 	sem        chan struct{} // concurrency limiter for SSE calls
+	health     health
+	retry      RetryPolicy
+	recorder   *recorder // set by Record
+	replayer   *replayer // set by Replay
+
+	connectMu sync.Mutex // serializes lazy connects of this instance
+	instMu    sync.Mutex
+	instances map[string]*Client
+}
+
+// health tracks call outcomes for one instance.
+type health struct {
+	lastSuccess time.Time
+	lastFailure time.Time
+	lastError   string
+	failures    int // consecutive
+}
+
+// InstanceHealth is a snapshot of one Burp instance's connection health.
+type InstanceHealth struct {
+	Name                string    `json:"name"`
+	Endpoint            string    `json:"endpoint"`
+	Connected           bool      `json:"connected"`
+	LastSuccess         time.Time `json:"lastSuccess,omitempty"`
+	LastFailure         time.Time `json:"lastFailure,omitempty"`
+	LastError           string    `json:"lastError,omitempty"`
+	ConsecutiveFailures int       `json:"consecutiveFailures"`
+}
+
+type instanceKey struct{}
+
+// WithInstance returns a context that routes Burp calls to the named instance.
+// An empty name or DefaultInstance selects the default connection.
+func WithInstance(ctx context.Context, name string) context.Context {
+	if name == "" || name == DefaultInstance {
+		return ctx
+	}
+	return context.WithValue(ctx, instanceKey{}, name)
+}
+
+func instanceFromContext(ctx context.Context) string {
+	name, _ := ctx.Value(instanceKey{}).(string)
+	return name
 }
 
 // NewClient creates a new Burp MCP client.
@@ -42,9 +102,103 @@ func NewClient(endpoint string) (*Client, error) {
 		endpoint: endpoint,
 		client:   client,
 		sem:      make(chan struct{}, maxConcurrentCalls),
+		retry:    DefaultRetryPolicy,
 	}, nil
 }
 
+// AddInstance registers an additional named Burp endpoint.
+// The connection is established lazily on the first call routed to it.
+func (c *Client) AddInstance(name, endpoint string) error {
+	if name == "" || name == DefaultInstance {
+		return fmt.Errorf("instance name %q is reserved", name)
+	}
+	inst, err := NewClient(endpoint)
+	if err != nil {
+		return err
+	}
+	inst.retry = c.retry
+	c.instMu.Lock()
+	defer c.instMu.Unlock()
+	if c.instances == nil {
+		c.instances = make(map[string]*Client)
+	}
+	c.instances[name] = inst
+	return nil
+}
+
+// instance returns the named instance, connecting it on first use.
+func (c *Client) instance(name string) (*Client, error) {
+	c.instMu.Lock()
+	inst, ok := c.instances[name]
+	c.instMu.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown Burp instance %q", name)
+	}
+
+	inst.connectMu.Lock()
+	defer inst.connectMu.Unlock()
+	if inst.Session() != nil {
+		return inst, nil
+	}
+	ctx := c.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if _, err := inst.Connect(ctx); err != nil {
+		inst.recordResult(err)
+		return nil, fmt.Errorf("instance %s: %w", name, err)
+	}
+	fmt.Fprintf(os.Stderr, "Connected to Burp instance %s at %s\n", name, inst.endpoint)
+	return inst, nil
+}
+
+// Health reports connection health for the default instance and every named one.
+func (c *Client) Health() []InstanceHealth {
+	c.instMu.Lock()
+	instances := make(map[string]*Client, len(c.instances))
+	names := make([]string, 0, len(c.instances))
+	for name, inst := range c.instances {
+		instances[name] = inst
+		names = append(names, name)
+	}
+	c.instMu.Unlock()
+	sort.Strings(names)
+
+	out := []InstanceHealth{c.healthSnapshot(DefaultInstance)}
+	for _, name := range names {
+		out = append(out, instances[name].healthSnapshot(name))
+	}
+	return out
+}
+
+func (c *Client) healthSnapshot(name string) InstanceHealth {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return InstanceHealth{
+		Name:                name,
+		Endpoint:            c.endpoint,
+		Connected:           c.session != nil,
+		LastSuccess:         c.health.lastSuccess,
+		LastFailure:         c.health.lastFailure,
+		LastError:           c.health.lastError,
+		ConsecutiveFailures: c.health.failures,
+	}
+}
+
+// recordResult updates health after a call.
+func (c *Client) recordResult(err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err == nil {
+		c.health.lastSuccess = time.Now()
+		c.health.failures = 0
+		return
+	}
+	c.health.lastFailure = time.Now()
+	c.health.lastError = err.Error()
+	c.health.failures++
+}
+
 // Connect establishes the SSE connection to Burp's MCP extension.
 func (c *Client) Connect(ctx context.Context) (*mcp.ClientSession, error) {
 	c.ctx = ctx
@@ -54,7 +208,7 @@ func (c *Client) Connect(ctx context.Context) (*mcp.ClientSession, error) {
 
 	session, err := c.client.Connect(ctx, transport, nil)
 	if err != nil {
-		return nil, fmt.Errorf("SSE connect failed: %w", err)
+		return nil, fmt.Errorf("SSE connect failed: %w: %w", ErrUnreachable, err)
 	}
 	c.mu.Lock()
 	c.session = session
@@ -93,7 +247,7 @@ func (c *Client) reconnectIfNeeded(failedGen uint64) (*mcp.ClientSession, error)
 
 	session, err := c.client.Connect(c.ctx, transport, nil)
 	if err != nil {
-		return nil, fmt.Errorf("SSE reconnect failed: %w", err)
+		return nil, fmt.Errorf("SSE reconnect failed: %w: %w", ErrUnreachable, err)
 	}
 	c.session = session
 	c.generation++
@@ -124,8 +278,18 @@ func (c *Client) sessionAndGen() (*mcp.ClientSession, uint64) {
 	return c.session, c.generation
 }
 
-// Close terminates the connection.
+// Close terminates the connection and those of any named instances, and
+// closes the recording.
 func (c *Client) Close() {
+	if c.recorder != nil {
+		c.recorder.close()
+	}
+	c.instMu.Lock()
+	for _, inst := range c.instances {
+		inst.Close()
+	}
+	c.instMu.Unlock()
+
 	c.mu.Lock()
 	defer c.mu.Unlock()
 	if c.session != nil {
@@ -145,14 +309,39 @@ func isConnectionError(err error) bool {
 }
 
 // CallTool calls a tool on the Burp MCP extension and returns the text content.
-// Automatically reconnects and retries once on connection errors.
+// Automatically reconnects on connection errors and retries transient
+// failures according to the retry policy.
 func (c *Client) CallTool(ctx context.Context, name string, args map[string]any) (string, error) {
 	return c.CallToolWithTimeout(ctx, name, args, DefaultToolTimeout)
 }
 
 // CallToolWithTimeout calls a tool with a custom timeout.
-// Automatically reconnects and retries once on connection errors.
+// Automatically reconnects on connection errors and retries transient
+// failures according to the retry policy.
+// The call is routed to the instance selected by WithInstance, if any.
 func (c *Client) CallToolWithTimeout(ctx context.Context, name string, args map[string]any, timeout time.Duration) (string, error) {
+	instName := instanceFromContext(ctx)
+	if c.replayer != nil {
+		return c.replayer.call(instName, name, args)
+	}
+	target := c
+	if instName != "" {
+		inst, err := c.instance(instName)
+		if err != nil {
+			return "", err
+		}
+		target = inst
+	}
+	text, err := target.callWithRetry(ctx, name, args, timeout)
+	target.recordResult(err)
+	if c.recorder != nil {
+		c.recorder.record(instName, name, args, text, err)
+	}
+	return text, err
+}
+
+// callTool calls a tool on this instance's session, reconnecting once on connection errors.
+func (c *Client) callTool(ctx context.Context, name string, args map[string]any, timeout time.Duration) (string, error) {
 	session, gen := c.sessionAndGen()
 	text, err := c.callToolThrottled(ctx, session, name, args, timeout)
 	if err != nil && isConnectionError(err) {
@@ -186,13 +375,16 @@ func callToolOnce(ctx context.Context, session *mcp.ClientSession, name string,
 	})
 	if err != nil {
 		if ctx.Err() == context.DeadlineExceeded {
-			return "", fmt.Errorf("call %s: timed out after %s", name, timeout)
+			return "", fmt.Errorf("call %s: %w after %s", name, ErrTimeout, timeout)
+		}
+		if isConnectionError(err) {
+			return "", fmt.Errorf("call %s: %w: %w", name, ErrUnreachable, err)
 		}
 		return "", fmt.Errorf("call %s: %w", name, err)
 	}
 	if result.IsError {
 		text := ExtractText(result)
-		return "", fmt.Errorf("burp error: %s", text)
+		return "", fmt.Errorf("%w: %s", ErrUpstream, text)
 	}
 	return ExtractText(result), nil
 }
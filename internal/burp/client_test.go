@@ -1,6 +1,7 @@
 package burp
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
@@ -88,3 +89,54 @@ func TestClient_ReconnectIfNeeded_SkipsWhenAlreadyReconnected(t *testing.T) {
 		t.Errorf("generation = %d, want 10 (should not increment)", c.generation)
 	}
 }
+
+func TestWithInstance_DefaultIsUnrouted(t *testing.T) {
+	ctx := WithInstance(context.Background(), DefaultInstance)
+	if got := instanceFromContext(ctx); got != "" {
+		t.Errorf("default instance should not be routed, got %q", got)
+	}
+	ctx = WithInstance(context.Background(), "eng-a")
+	if got := instanceFromContext(ctx); got != "eng-a" {
+		t.Errorf("instance = %q, want eng-a", got)
+	}
+}
+
+func TestClient_UnknownInstance(t *testing.T) {
+	c, _ := NewClient("http://127.0.0.1:1/sse")
+	ctx := WithInstance(context.Background(), "missing")
+	if _, err := c.CallTool(ctx, "get_scanner_issues", nil); err == nil {
+		t.Error("expected error for unknown instance")
+	}
+}
+
+func TestClient_AddInstanceReservedName(t *testing.T) {
+	c, _ := NewClient("http://127.0.0.1:1/sse")
+	if err := c.AddInstance(DefaultInstance, "http://127.0.0.1:2/sse"); err == nil {
+		t.Error("expected error for reserved name")
+	}
+}
+
+func TestClient_HealthTracksFailures(t *testing.T) {
+	c, _ := NewClient("http://127.0.0.1:1/sse")
+	if err := c.AddInstance("eng-a", "http://127.0.0.1:2/sse"); err != nil {
+		t.Fatal(err)
+	}
+	c.recordResult(fmt.Errorf("boom"))
+	c.recordResult(fmt.Errorf("boom again"))
+
+	h := c.Health()
+	if len(h) != 2 || h[0].Name != DefaultInstance || h[1].Name != "eng-a" {
+		t.Fatalf("Health() = %+v", h)
+	}
+	if h[0].ConsecutiveFailures != 2 || h[0].LastError != "boom again" {
+		t.Errorf("default health = %+v", h[0])
+	}
+	if h[1].ConsecutiveFailures != 0 || h[1].Connected {
+		t.Errorf("eng-a should be untouched and unconnected, got %+v", h[1])
+	}
+
+	c.recordResult(nil)
+	if got := c.Health()[0].ConsecutiveFailures; got != 0 {
+		t.Errorf("success should reset failures, got %d", got)
+	}
+}
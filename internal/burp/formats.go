@@ -0,0 +1,266 @@
+package burp
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Output formats of Burp's proxy history and scanner issue tools. Current
+// versions of PortSwigger's MCP extension serialize each item as a JSON
+// object; older builds and other bridges print tables, Java toString
+// wrappers, or "Key: value" text, which the other adapters scrape.
+const (
+	FormatJSON    = "json"    // one JSON object per item, separated by blank lines
+	FormatTable   = "table"   // "1 | GET | https://host/path | 200"
+	FormatWrapper = "wrapper" // HttpRequestResponse{httpRequest=..., httpResponse=...}
+	FormatText    = "text"    // "Issue: ...\nSeverity: ..." blocks
+)
+
+// ErrUnknownFormat is returned when Burp's output is not empty but no
+// adapter recognizes it, typically after an extension update changed it.
+var ErrUnknownFormat = errors.New("unrecognized Burp output format")
+
+// historyAdapter parses one proxy history format. detect is a cheap check
+// that the output could be in the format; parse does the work.
+type historyAdapter struct {
+	format string
+	detect func(raw string) bool
+	parse  func(raw string) []ProxyHistoryEntry
+}
+
+// historyAdapters are tried in order; structured formats come first.
+var historyAdapters = []historyAdapter{
+	{FormatJSON, looksLikeJSON, parseProxyHistoryJSON},
+	{FormatTable, proxyEntryRegex.MatchString, parseProxyHistoryTable},
+	{FormatWrapper, func(raw string) bool { return strings.Contains(raw, "HttpRequestResponse{") }, parseProxyHistoryWrapper},
+}
+
+// ParseProxyHistoryFormat parses proxy history output with the first adapter
+// that recognizes it and yields entries, and returns that adapter's format.
+// Empty output (or only the end-of-items marker) is no entries and no error;
+// other output that no adapter parses is ErrUnknownFormat.
+func ParseProxyHistoryFormat(raw string) ([]ProxyHistoryEntry, string, error) {
+	if isEmptyOutput(raw) {
+		return nil, "", nil
+	}
+	for _, a := range historyAdapters {
+		if !a.detect(raw) {
+			continue
+		}
+		if entries := a.parse(raw); len(entries) > 0 {
+			return entries, a.format, nil
+		}
+	}
+	return nil, "", fmt.Errorf("proxy history: %w: %s", ErrUnknownFormat, outputSample(raw))
+}
+
+// issueAdapter parses one scanner issue format.
+type issueAdapter struct {
+	format string
+	detect func(raw string) bool
+	parse  func(raw string, detailLimit int) []ScannerIssue
+}
+
+var issueAdapters = []issueAdapter{
+	{FormatJSON, looksLikeJSON, parseScannerIssuesJSON},
+	{FormatText, hasIssueKeys, parseScannerIssuesText},
+}
+
+// ParseScannerIssuesFormat is ParseProxyHistoryFormat for scanner issues.
+func ParseScannerIssuesFormat(raw string, detailLimit int) ([]ScannerIssue, string, error) {
+	if isEmptyOutput(raw) {
+		return nil, "", nil
+	}
+	for _, a := range issueAdapters {
+		if !a.detect(raw) {
+			continue
+		}
+		if issues := a.parse(raw, detailLimit); len(issues) > 0 {
+			return issues, a.format, nil
+		}
+	}
+	return nil, "", fmt.Errorf("scanner issues: %w: %s", ErrUnknownFormat, outputSample(raw))
+}
+
+func isEmptyOutput(raw string) bool {
+	raw = strings.TrimSpace(raw)
+	return raw == "" || raw == "Reached end of items"
+}
+
+func looksLikeJSON(raw string) bool {
+	raw = strings.TrimSpace(raw)
+	return strings.HasPrefix(raw, "{") || strings.HasPrefix(raw, "[")
+}
+
+// outputSample quotes the start of raw for error messages.
+func outputSample(raw string) string {
+	raw = strings.TrimSpace(raw)
+	if len(raw) > 80 {
+		raw = raw[:80] + "..."
+	}
+	return fmt.Sprintf("%q", raw)
+}
+
+// issueKeys are the field names of the text issue format.
+var issueKeys = []string{"issue:", "name:", "issue name:", "severity:", "confidence:", "url:", "path:", "detail:", "issue detail:"}
+
+func hasIssueKeys(raw string) bool {
+	for _, line := range strings.Split(raw, "\n") {
+		lower := strings.ToLower(strings.TrimSpace(line))
+		for _, k := range issueKeys {
+			if strings.HasPrefix(lower, k) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// issueJSON is a scanner issue as PortSwigger's MCP extension serializes
+// Montoya's AuditIssue. Severity and confidence are enum names (HIGH,
+// CERTAIN); older builds used url and issueDetail.
+type issueJSON struct {
+	Name        string `json:"name"`
+	Detail      string `json:"detail"`
+	IssueDetail string `json:"issueDetail"`
+	BaseURL     string `json:"baseUrl"`
+	URL         string `json:"url"`
+	Severity    string `json:"severity"`
+	Confidence  string `json:"confidence"`
+	Definition  struct {
+		Name string `json:"name"`
+	} `json:"definition"`
+	RequestResponses []IssueEvidence `json:"requestResponses"`
+}
+
+// IssueEvidence is a request and response Burp attached to a scanner issue
+// as evidence.
+type IssueEvidence struct {
+	Request  string `json:"request"`
+	Response string `json:"response"`
+}
+
+func (j issueJSON) issue(detailLimit int) ScannerIssue {
+	detail := j.Detail
+	if detail == "" {
+		detail = j.IssueDetail
+	}
+	issue := ScannerIssue{
+		Name:       j.Name,
+		Severity:   enumName(j.Severity),
+		Confidence: enumName(j.Confidence),
+		URL:        j.BaseURL,
+	}
+	issue.SetDetail(detail, detailLimit)
+	if issue.Name == "" {
+		issue.Name = j.Definition.Name
+	}
+	if issue.URL == "" {
+		issue.URL = j.URL
+	}
+	return issue
+}
+
+// enumName turns a Java enum name such as FALSE_POSITIVE into Burp's
+// display form, "False positive".
+func enumName(s string) string {
+	if s == "" || strings.ToUpper(s) != s {
+		return s
+	}
+	s = strings.ToLower(strings.ReplaceAll(s, "_", " "))
+	return strings.ToUpper(s[:1]) + s[1:]
+}
+
+// parseScannerIssuesJSON parses a JSON array of issues or JSON objects
+// separated by blank lines. Objects the extension cut at its size limit
+// keep the fields that survived.
+func parseScannerIssuesJSON(raw string, detailLimit int) []ScannerIssue {
+	raw = strings.TrimSpace(raw)
+	var items []issueJSON
+	if strings.HasPrefix(raw, "[") {
+		if json.Unmarshal([]byte(raw), &items) != nil {
+			return nil
+		}
+	} else {
+		for _, block := range strings.Split(raw, "\n\n") {
+			block = strings.TrimSpace(block)
+			if !strings.HasPrefix(block, "{") {
+				continue
+			}
+			var item issueJSON
+			if json.Unmarshal([]byte(block), &item) != nil {
+				item = issueJSON{
+					Name:       jsonStringField(block, "name"),
+					Detail:     jsonStringField(block, "detail"),
+					BaseURL:    jsonStringField(block, "baseUrl"),
+					Severity:   jsonStringField(block, "severity"),
+					Confidence: jsonStringField(block, "confidence"),
+				}
+			}
+			items = append(items, item)
+		}
+	}
+	var issues []ScannerIssue
+	for _, item := range items {
+		if issue := item.issue(detailLimit); issue.Name != "" {
+			issues = append(issues, issue)
+		}
+	}
+	return issues
+}
+
+// ParseScannerIssueEvidence parses the first issue in raw, untruncated,
+// with its evidence. Only the JSON format carries evidence; issues in other
+// formats, or cut at the extension's size limit, come back without it.
+func ParseScannerIssueEvidence(raw string) (ScannerIssue, []IssueEvidence, error) {
+	issues, format, err := ParseScannerIssuesFormat(raw, 0)
+	if err != nil || len(issues) == 0 {
+		return ScannerIssue{}, nil, err
+	}
+	if format != FormatJSON {
+		return issues[0], nil, nil
+	}
+	raw = strings.TrimSpace(raw)
+	var item issueJSON
+	if strings.HasPrefix(raw, "[") {
+		var items []issueJSON
+		if json.Unmarshal([]byte(raw), &items) == nil && len(items) > 0 {
+			item = items[0]
+		}
+	} else {
+		block, _, _ := strings.Cut(raw, "\n\n")
+		_ = json.Unmarshal([]byte(block), &item)
+	}
+	var evidence []IssueEvidence
+	for _, rr := range item.RequestResponses {
+		if rr.Request != "" || rr.Response != "" {
+			evidence = append(evidence, rr)
+		}
+	}
+	return issues[0], evidence, nil
+}
+
+// jsonStringField returns the first string value of key in a possibly
+// truncated JSON object, or "" if it is absent.
+func jsonStringField(block, key string) string {
+	marker := `"` + key + `":"`
+	i := strings.Index(block, marker)
+	if i < 0 {
+		return ""
+	}
+	rest := block[i+len(marker):]
+	end := len(rest)
+	for j := 0; j < len(rest); j++ {
+		if rest[j] == '\\' {
+			j++
+			continue
+		}
+		if rest[j] == '"' {
+			end = j
+			break
+		}
+	}
+	return unescapeJSONString(rest[:end])
+}
@@ -0,0 +1,119 @@
+package burp
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParseProxyHistoryFormat(t *testing.T) {
+	tests := []struct {
+		name, raw, format string
+		entries           int
+		method            string
+	}{
+		{"json", `{"request":"GET /a HTTP/1.1\r\nHost: x.test\r\n\r\n","response":"HTTP/1.1 204 No Content\r\n\r\n","notes":""}` + "\n\nReached end of items", FormatJSON, 1, "GET"},
+		{"table", "1 | POST | https://x.test/login | 302\n2 | GET | https://x.test/ | 200", FormatTable, 2, "POST"},
+		{"wrapper", "HttpRequestResponse{httpRequest=PUT /a HTTP/1.1\r\nHost: x.test\r\n\r\n, httpResponse=HTTP/1.1 201 Created\r\n\r\n}", FormatWrapper, 1, "PUT"},
+		{"empty", "\nReached end of items\n", "", 0, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries, format, err := ParseProxyHistoryFormat(tt.raw)
+			if err != nil || format != tt.format || len(entries) != tt.entries {
+				t.Fatalf("got %d entries, format %q, err %v", len(entries), format, err)
+			}
+			if tt.entries > 0 && entries[0].Method != tt.method {
+				t.Errorf("method = %q, want %q", entries[0].Method, tt.method)
+			}
+		})
+	}
+
+	if _, _, err := ParseProxyHistoryFormat("<html>Burp extension error</html>"); !errors.Is(err, ErrUnknownFormat) {
+		t.Errorf("unknown output: err = %v", err)
+	}
+}
+
+func TestParseScannerIssuesFormat(t *testing.T) {
+	montoya := `{"name":"SQL injection","detail":"The q parameter is injectable.","baseUrl":"https://x.test/search","severity":"HIGH","confidence":"FIRM","definition":{"name":"SQL injection","typicalSeverity":"HIGH"}}`
+	truncated := `{"name":"Cross-site scripting (reflected)","detail":"The value of the name request parameter is copied into the HTML docu`
+	issues, format, err := ParseScannerIssuesFormat(montoya+"\n\n"+truncated, 10)
+	if err != nil || format != FormatJSON || len(issues) != 2 {
+		t.Fatalf("json: %d issues, format %q, err %v", len(issues), format, err)
+	}
+	want := ScannerIssue{Name: "SQL injection", Severity: "High", Confidence: "Firm", URL: "https://x.test/search", IssueDetail: "The q para...", DetailSize: 30, DetailTruncated: true}
+	if issues[0] != want || issues[1].Name != "Cross-site scripting (reflected)" {
+		t.Errorf("issues = %+v", issues)
+	}
+
+	issues, format, err = ParseScannerIssuesFormat(`[{"name":"A","severity":"FALSE_POSITIVE"}]`, 0)
+	if err != nil || format != FormatJSON || len(issues) != 1 || issues[0].Severity != "False positive" {
+		t.Errorf("array: %+v, %q, %v", issues, format, err)
+	}
+
+	issues, format, err = ParseScannerIssuesFormat("Issue: XSS\nSeverity: Medium", 0)
+	if err != nil || format != FormatText || len(issues) != 1 {
+		t.Errorf("text: %+v, %q, %v", issues, format, err)
+	}
+
+	if _, _, err := ParseScannerIssuesFormat("java.lang.NullPointerException", 0); !errors.Is(err, ErrUnknownFormat) {
+		t.Errorf("unknown output: err = %v", err)
+	}
+}
+
+func TestParseScannerIssueEvidence(t *testing.T) {
+	withEvidence := `{"name":"SQL injection","detail":"` + strings.Repeat("x", 600) + `","requestResponses":[{"request":"GET /search?q=1 HTTP/1.1\r\nHost: x.test\r\n\r\n","response":"HTTP/1.1 500 Error\r\n\r\n","notes":""}]}`
+	issue, evidence, err := ParseScannerIssueEvidence(withEvidence + "\n\n" + `{"name":"Other"}`)
+	if err != nil || issue.Name != "SQL injection" || len(issue.IssueDetail) != 600 {
+		t.Fatalf("issue = %+v, err = %v", issue, err)
+	}
+	if len(evidence) != 1 || !strings.HasPrefix(evidence[0].Request, "GET /search?q=1") || !strings.HasPrefix(evidence[0].Response, "HTTP/1.1 500") {
+		t.Errorf("evidence = %+v", evidence)
+	}
+
+	issue, evidence, err = ParseScannerIssueEvidence("Issue: XSS\nSeverity: Medium")
+	if err != nil || issue.Name != "XSS" || evidence != nil {
+		t.Errorf("text: %+v, %+v, %v", issue, evidence, err)
+	}
+	if issue, _, err := ParseScannerIssueEvidence("Reached end of items"); err != nil || issue.Name != "" {
+		t.Errorf("empty: %+v, %v", issue, err)
+	}
+}
+
+// checkParsed fails unless a parse result is consistent: entries come with a
+// format, and an error only without entries.
+func checkParsed(t *testing.T, raw string, n int, format string, err error) {
+	t.Helper()
+	switch {
+	case err != nil && (n > 0 || !errors.Is(err, ErrUnknownFormat)):
+		t.Errorf("%q: %d items with error %v", raw, n, err)
+	case n > 0 && format == "":
+		t.Errorf("%q: %d items without a format", raw, n)
+	case err == nil && n == 0 && strings.TrimSpace(raw) != "" && strings.TrimSpace(raw) != "Reached end of items":
+		t.Errorf("%q: no items and no error", raw)
+	}
+}
+
+func FuzzParseProxyHistory(f *testing.F) {
+	f.Add(`{"request":"GET / HTTP/1.1\r\nHost: a\r\n\r\n","response":"HTTP/1.1 200 OK\r\n\r\n","notes":""}`)
+	f.Add(`{"request":"GET / HTTP/1.1\r\nHo`)
+	f.Add("1 | GET | https://a/ | 200")
+	f.Add("HttpRequestResponse{httpRequest=GET / HTTP/1.1\r\nHost: a\r\n\r\n, httpResponse=HTTP/1.1 200 OK\r\n\r\n}")
+	f.Add("HttpRequestResponse{{{")
+	f.Add("Reached end of items")
+	f.Fuzz(func(t *testing.T, raw string) {
+		entries, format, err := ParseProxyHistoryFormat(raw)
+		checkParsed(t, raw, len(entries), format, err)
+	})
+}
+
+func FuzzParseScannerIssues(f *testing.F) {
+	f.Add(`{"name":"A","severity":"HIGH","baseUrl":"https://a/"}`, 10)
+	f.Add(`[{"name":"A"}]`, 0)
+	f.Add(`{"name":"A\`, 0)
+	f.Add("Issue: A\nSeverity: Low\n\n----\nIssue: B", 3)
+	f.Fuzz(func(t *testing.T, raw string, detailLimit int) {
+		issues, format, err := ParseScannerIssuesFormat(raw, detailLimit)
+		checkParsed(t, raw, len(issues), format, err)
+	})
+}
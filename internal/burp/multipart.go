@@ -0,0 +1,90 @@
+package burp
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/textproto"
+	"strings"
+)
+
+// MultipartPart is one part of a multipart/form-data body.
+type MultipartPart struct {
+	Name        string
+	Filename    string
+	ContentType string
+	Data        string
+}
+
+// ParseMultipart splits a multipart/form-data body into its parts. The
+// boundary comes from contentType, the request's Content-Type header value.
+func ParseMultipart(contentType, body string) ([]MultipartPart, error) {
+	mt, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return nil, err
+	}
+	if mt != "multipart/form-data" {
+		return nil, fmt.Errorf("not multipart/form-data: %s", mt)
+	}
+	if params["boundary"] == "" {
+		return nil, errors.New("multipart body without a boundary")
+	}
+
+	r := multipart.NewReader(strings.NewReader(body), params["boundary"])
+	var parts []MultipartPart
+	for {
+		p, err := r.NextRawPart()
+		if err == io.EOF {
+			return parts, nil
+		}
+		if err != nil {
+			return parts, err
+		}
+		data, err := io.ReadAll(p)
+		if err != nil {
+			return parts, err
+		}
+		// Read the disposition directly: FileName strips directories, and a
+		// traversal in an uploaded filename is exactly what we want to see.
+		_, disposition, _ := mime.ParseMediaType(p.Header.Get("Content-Disposition"))
+		parts = append(parts, MultipartPart{
+			Name:        disposition["name"],
+			Filename:    disposition["filename"],
+			ContentType: p.Header.Get("Content-Type"),
+			Data:        string(data),
+		})
+	}
+}
+
+// BuildMultipart assembles parts into a multipart/form-data body under a
+// fresh random boundary and returns it with its Content-Type header value.
+// Names and filenames are written verbatim, quotes and all, so upload
+// filters can be tested with hostile filenames.
+func BuildMultipart(parts []MultipartPart) (body, contentType string, err error) {
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+	for _, p := range parts {
+		disposition := fmt.Sprintf(`form-data; name="%s"`, p.Name)
+		if p.Filename != "" {
+			disposition += fmt.Sprintf(`; filename="%s"`, p.Filename)
+		}
+		h := textproto.MIMEHeader{"Content-Disposition": {disposition}}
+		if p.ContentType != "" {
+			h.Set("Content-Type", p.ContentType)
+		}
+		pw, err := w.CreatePart(h)
+		if err != nil {
+			return "", "", err
+		}
+		if _, err := io.WriteString(pw, p.Data); err != nil {
+			return "", "", err
+		}
+	}
+	if err := w.Close(); err != nil {
+		return "", "", err
+	}
+	return buf.String(), w.FormDataContentType(), nil
+}
@@ -0,0 +1,59 @@
+package burp
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseRawRequest_Multipart(t *testing.T) {
+	raw := "POST /upload HTTP/1.1\r\n" +
+		"Host: shop.example\r\n" +
+		"Content-Type: multipart/form-data; boundary=XyZ\r\n\r\n" +
+		"--XyZ\r\n" +
+		"Content-Disposition: form-data; name=\"title\"\r\n\r\n" +
+		"holiday\r\n" +
+		"--XyZ\r\n" +
+		"Content-Disposition: form-data; name=\"file\"; filename=\"../../shell.php\"\r\n" +
+		"Content-Type: image/png\r\n\r\n" +
+		"<?php system($_GET[0]); ?>\r\n" +
+		"--XyZ--\r\n"
+
+	parts := ParseRawRequest(raw).Parts
+	if len(parts) != 2 {
+		t.Fatalf("got %d parts: %+v", len(parts), parts)
+	}
+	if parts[0].Name != "title" || parts[0].Data != "holiday" || parts[0].Filename != "" {
+		t.Errorf("part 0 = %+v", parts[0])
+	}
+	if parts[1].Filename != "../../shell.php" || parts[1].ContentType != "image/png" || !strings.HasPrefix(parts[1].Data, "<?php") {
+		t.Errorf("part 1 = %+v", parts[1])
+	}
+
+	if got := ParseRawRequest("POST / HTTP/1.1\r\nContent-Type: application/json\r\n\r\n{}").Parts; got != nil {
+		t.Errorf("non-multipart body parsed into %+v", got)
+	}
+}
+
+func TestBuildMultipart_RoundTrip(t *testing.T) {
+	in := []MultipartPart{
+		{Name: "title", Data: "holiday"},
+		{Name: "file", Filename: `a"b.php`, ContentType: "image/gif", Data: "GIF89a\x00\x01"},
+	}
+	body, ct, err := BuildMultipart(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(ct, "multipart/form-data; boundary=") {
+		t.Fatalf("content type %q", ct)
+	}
+	if !strings.Contains(body, `filename="a"b.php"`) {
+		t.Errorf("filename not written verbatim:\n%s", body)
+	}
+	out, err := ParseMultipart(ct, body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(out) != 2 || out[0] != in[0] || out[1].Data != in[1].Data || out[1].ContentType != "image/gif" {
+		t.Errorf("round trip = %+v", out)
+	}
+}
@@ -114,18 +114,17 @@ func extractTruncatedJSON(raw string) (request, response string) {
 
 	// Find end of request value: look for ","response":" boundary
 	const respBoundary = "\",\"response\":\""
-	respIdx := strings.Index(raw, respBoundary)
+	respIdx := strings.Index(raw[len(reqPrefix):], respBoundary)
 	if respIdx < 0 {
 		// Response boundary not found - request value is truncated.
 		// Extract what we have, unescape JSON string.
 		reqEscaped := raw[len(reqPrefix):]
 		// Trim trailing incomplete escape sequences
-		if last := strings.LastIndex(reqEscaped, "\\"); last == len(reqEscaped)-1 {
-			reqEscaped = reqEscaped[:last]
-		}
+		reqEscaped = strings.TrimSuffix(reqEscaped, "\\")
 		return unescapeJSONString(reqEscaped), ""
 	}
 
+	respIdx += len(reqPrefix)
 	reqEscaped := raw[len(reqPrefix):respIdx]
 	request = unescapeJSONString(reqEscaped)
 
@@ -172,17 +171,54 @@ func unescapeJSONString(s string) string {
 	return r.Replace(s)
 }
 
-// ParsedHTTPResponse holds a parsed HTTP response.
+// Header is one header line, as sent.
+type Header struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// HeaderMap groups headers by name for lookups. Names differing only in
+// case share the spelling seen first, so duplicates such as Set-Cookie and
+// set-cookie keep every value, in order.
+func HeaderMap(list []Header) map[string][]string {
+	m := make(map[string][]string, len(list))
+	canonical := make(map[string]string, len(list))
+	for _, h := range list {
+		lower := strings.ToLower(h.Name)
+		name, ok := canonical[lower]
+		if !ok {
+			name = h.Name
+			canonical[lower] = name
+		}
+		m[name] = append(m[name], h.Value)
+	}
+	return m
+}
+
+// FilterHeaderList returns only the security-relevant headers, in order.
+func FilterHeaderList(list []Header) []Header {
+	var filtered []Header
+	for _, h := range list {
+		if SecurityHeaders[strings.ToLower(h.Name)] {
+			filtered = append(filtered, h)
+		}
+	}
+	return filtered
+}
+
+// ParsedHTTPResponse holds a parsed HTTP response. HeaderList keeps every
+// header line in order; Headers is the same headers grouped by name.
 type ParsedHTTPResponse struct {
 	StatusCode int                 `json:"statusCode"`
 	StatusLine string              `json:"statusLine"`
 	Headers    map[string][]string `json:"headers,omitempty"`
+	HeaderList []Header            `json:"headerList,omitempty"`
 	Body       string              `json:"body,omitempty"`
 	BodySize   int                 `json:"bodySize"`
 	Truncated  bool                `json:"truncated,omitempty"`
 }
 
-// SecurityHeaders are headers relevant to pentesting. Used by FilterHeaders.
+// SecurityHeaders are headers relevant to pentesting. Used by FilterHeaders and FilterHeaderList.
 var SecurityHeaders = map[string]bool{
 	"content-type":              true,
 	"set-cookie":                true,
@@ -225,6 +261,16 @@ func FilterHeaders(headers map[string][]string) map[string][]string {
 	return filtered
 }
 
+// GetHeader returns the first value of a header, matching the name case-insensitively.
+func GetHeader(headers map[string][]string, name string) string {
+	for k, v := range headers {
+		if strings.EqualFold(k, name) && len(v) > 0 {
+			return v[0]
+		}
+	}
+	return ""
+}
+
 // FlattenHeaders converts multi-value headers for JSON output.
 // Single-value headers become strings, multi-value become string arrays.
 func FlattenHeaders(headers map[string][]string) map[string]any {
@@ -301,12 +347,13 @@ func ParseHTTPResponse(raw string, bodyOffset, bodyLimit int) *ParsedHTTPRespons
 		if colonIdx > 0 {
 			key := strings.TrimSpace(line[:colonIdx])
 			value := strings.TrimSpace(line[colonIdx+1:])
-			result.Headers[key] = append(result.Headers[key], value)
+			result.HeaderList = append(result.HeaderList, Header{Name: key, Value: value})
 		}
 		if err != nil {
 			break
 		}
 	}
+	result.Headers = HeaderMap(result.HeaderList)
 
 	// Body handling
 	result.BodySize = len(bodyBytes)
@@ -333,16 +380,21 @@ func ParseHTTPResponse(raw string, bodyOffset, bodyLimit int) *ParsedHTTPRespons
 	return result
 }
 
-// ParsedHTTPRequest holds a parsed HTTP request.
+// ParsedHTTPRequest holds a parsed HTTP request. HeaderList keeps every
+// header line in order; Headers is the same headers grouped by name.
 type ParsedHTTPRequest struct {
 	Method  string
 	Path    string
 	Host    string
 	Headers map[string][]string
+	HeaderList []Header
 	Body    string
+	// Parts holds the parts of a multipart/form-data body.
+	Parts   []MultipartPart
 }
 
 // ParseRawRequest parses a raw HTTP request string to extract method, path, host, headers, body.
+// Multipart/form-data bodies are also split into Parts.
 func ParseRawRequest(raw string) *ParsedHTTPRequest {
 	result := &ParsedHTTPRequest{
 		Headers: make(map[string][]string),
@@ -385,12 +437,18 @@ func ParseRawRequest(raw string) *ParsedHTTPRequest {
 		if colonIdx > 0 {
 			key := strings.TrimSpace(line[:colonIdx])
 			value := strings.TrimSpace(line[colonIdx+1:])
-			result.Headers[key] = append(result.Headers[key], value)
+			result.HeaderList = append(result.HeaderList, Header{Name: key, Value: value})
 			if strings.EqualFold(key, "host") {
 				result.Host = value
 			}
 		}
 	}
+	result.Headers = HeaderMap(result.HeaderList)
+
+	if ct := GetHeader(result.Headers, "Content-Type"); strings.HasPrefix(strings.ToLower(ct), "multipart/form-data") {
+		// Best effort: a malformed body keeps whatever parts parsed cleanly.
+		result.Parts, _ = ParseMultipart(ct, body)
+	}
 
 	return result
 }
@@ -419,28 +477,21 @@ type burpHistoryJSON struct {
 
 // ParseProxyHistory parses Burp's proxy history output into structured entries.
 // Supports JSON (PortSwigger MCP extension), pipe-delimited tables, and
-// HttpRequestResponse{} wrapper format.
+// HttpRequestResponse{} wrapper format; see ParseProxyHistoryFormat.
 func ParseProxyHistory(raw string) []ProxyHistoryEntry {
-	if raw == "" {
-		return nil
-	}
-
-	// Strategy 1: JSON format (PortSwigger MCP extension)
-	// Entries are JSON objects separated by \n\n
-	if entries := parseProxyHistoryJSON(raw); len(entries) > 0 {
-		return entries
-	}
+	entries, _, _ := ParseProxyHistoryFormat(raw)
+	return entries
+}
 
+// parseProxyHistoryTable parses pipe-delimited table lines, e.g.
+// "1 | GET | https://example.com/path | 200".
+func parseProxyHistoryTable(raw string) []ProxyHistoryEntry {
 	var entries []ProxyHistoryEntry
-
-	// Strategy 2: Pipe-delimited table format
-	lines := strings.Split(raw, "\n")
-	for _, line := range lines {
+	for _, line := range strings.Split(raw, "\n") {
 		line = strings.TrimSpace(line)
 		if line == "" || strings.HasPrefix(line, "-") || strings.HasPrefix(line, "=") {
 			continue
 		}
-
 		matches := proxyEntryRegex.FindStringSubmatch(line)
 		if len(matches) >= 5 {
 			id, _ := strconv.Atoi(matches[1])
@@ -451,29 +502,30 @@ func ParseProxyHistory(raw string) []ProxyHistoryEntry {
 				URL:        matches[3],
 				StatusCode: statusCode,
 			})
-			continue
 		}
+	}
+	return entries
+}
 
-		// Strategy 3: HttpRequestResponse{} on single lines
-		if strings.Contains(line, "HttpRequestResponse{") {
-			entry := parseHttpRequestResponseEntry(line)
-			if entry != nil {
+// parseProxyHistoryWrapper parses HttpRequestResponse{} entries, one per
+// line or as multi-line blocks.
+func parseProxyHistoryWrapper(raw string) []ProxyHistoryEntry {
+	var entries []ProxyHistoryEntry
+	for _, line := range strings.Split(raw, "\n") {
+		if strings.Contains(line, "HttpRequestResponse{") && strings.Contains(line, "}") {
+			if entry := parseHttpRequestResponseEntry(strings.TrimSpace(line)); entry != nil && entry.Method != "" {
 				entries = append(entries, *entry)
 			}
 		}
 	}
-
-	// Strategy 4: Multi-line HttpRequestResponse{} blocks
-	if len(entries) == 0 && strings.Contains(raw, "HttpRequestResponse{") {
-		blocks := splitHttpRequestResponseBlocks(raw)
-		for i, block := range blocks {
-			entry := parseHttpRequestResponseBlock(block, i+1)
-			if entry != nil {
-				entries = append(entries, *entry)
-			}
+	if len(entries) > 0 {
+		return entries
+	}
+	for i, block := range splitHttpRequestResponseBlocks(raw) {
+		if entry := parseHttpRequestResponseBlock(block, i+1); entry != nil {
+			entries = append(entries, *entry)
 		}
 	}
-
 	return entries
 }
 
@@ -643,15 +695,36 @@ type ScannerIssue struct {
 	Confidence  string `json:"confidence,omitempty"`
 	URL         string `json:"url,omitempty"`
 	IssueDetail string `json:"issueDetail,omitempty"`
+	// DetailSize is the full detail's length when IssueDetail was cut.
+	DetailSize      int  `json:"detailSize,omitempty"`
+	DetailTruncated bool `json:"detailTruncated,omitempty"`
+	// Verdict is the latest verification verdict of an imported issue.
+	Verdict string `json:"verdict,omitempty"`
+	// Status and StatusNote are the issue's triage status, if it has one.
+	Status     string `json:"status,omitempty"`
+	StatusNote string `json:"statusNote,omitempty"`
+}
+
+// SetDetail sets the issue's detail, cut to limit characters (0 = unlimited)
+// with the cut recorded.
+func (i *ScannerIssue) SetDetail(detail string, limit int) {
+	if limit > 0 && len(detail) > limit {
+		i.DetailSize, i.DetailTruncated = len(detail), true
+		detail = detail[:limit] + "..."
+	}
+	i.IssueDetail = detail
 }
 
 // ParseScannerIssues parses Burp's scanner output into structured findings.
 // detailLimit controls the max length of each issue's detail field (0 = unlimited).
+// See ParseScannerIssuesFormat for the formats understood.
 func ParseScannerIssues(raw string, detailLimit int) []ScannerIssue {
-	if raw == "" {
-		return nil
-	}
+	issues, _, _ := ParseScannerIssuesFormat(raw, detailLimit)
+	return issues
+}
 
+// parseScannerIssuesText parses "Key: value" issue blocks.
+func parseScannerIssuesText(raw string, detailLimit int) []ScannerIssue {
 	var issues []ScannerIssue
 
 	// Split by common delimiters between issues
@@ -681,11 +754,7 @@ func ParseScannerIssues(raw string, detailLimit int) []ScannerIssue {
 			} else if strings.HasPrefix(lower, "url:") || strings.HasPrefix(lower, "path:") {
 				issue.URL = extractValue(line)
 			} else if strings.HasPrefix(lower, "detail:") || strings.HasPrefix(lower, "issue detail:") {
-				detail := extractValue(line)
-				if detailLimit > 0 && len(detail) > detailLimit {
-					detail = detail[:detailLimit] + "..."
-				}
-				issue.IssueDetail = detail
+				issue.SetDetail(extractValue(line), detailLimit)
 			}
 		}
 
@@ -1,6 +1,7 @@
 package burp
 
 import (
+	"slices"
 	"strings"
 	"testing"
 )
@@ -192,6 +193,21 @@ func TestParseRawRequest_DuplicateHeaders(t *testing.T) {
 	}
 }
 
+func TestParseHTTPResponse_HeaderOrder(t *testing.T) {
+	raw := "HTTP/1.1 200 OK\r\nSet-Cookie: a=1\r\nVary: Origin\r\nset-cookie: b=2\r\nVary: Accept-Encoding\r\nX-Debug: on\r\n\r\n"
+	resp := ParseHTTPResponse(raw, 0, 0)
+	want := []Header{{"Set-Cookie", "a=1"}, {"Vary", "Origin"}, {"set-cookie", "b=2"}, {"Vary", "Accept-Encoding"}, {"X-Debug", "on"}}
+	if !slices.Equal(resp.HeaderList, want) {
+		t.Errorf("HeaderList = %v", resp.HeaderList)
+	}
+	if got := resp.Headers["Set-Cookie"]; !slices.Equal(got, []string{"a=1", "b=2"}) || len(resp.Headers) != 3 {
+		t.Errorf("Headers = %v", resp.Headers)
+	}
+	if got := FilterHeaderList(resp.HeaderList); len(got) != 2 || got[1].Value != "b=2" {
+		t.Errorf("FilterHeaderList = %v", got)
+	}
+}
+
 // --- ParseProxyHistory ---
 
 func TestParseProxyHistory_TableFormat(t *testing.T) {
@@ -372,6 +388,9 @@ func TestParseScannerIssues_DetailLimit(t *testing.T) {
 	if len(issues[0].IssueDetail) != 13 {
 		t.Errorf("IssueDetail length = %d, want 13", len(issues[0].IssueDetail))
 	}
+	if !issues[0].DetailTruncated || issues[0].DetailSize != 100 {
+		t.Errorf("cut not recorded: %+v", issues[0])
+	}
 }
 
 func TestParseScannerIssues_DetailUnlimited(t *testing.T) {
@@ -382,8 +401,8 @@ func TestParseScannerIssues_DetailUnlimited(t *testing.T) {
 	if len(issues) != 1 {
 		t.Fatalf("got %d issues, want 1", len(issues))
 	}
-	if len(issues[0].IssueDetail) != 1000 {
-		t.Errorf("IssueDetail length = %d, want 1000", len(issues[0].IssueDetail))
+	if len(issues[0].IssueDetail) != 1000 || issues[0].DetailTruncated {
+		t.Errorf("IssueDetail length = %d, truncated %v, want 1000", len(issues[0].IssueDetail), issues[0].DetailTruncated)
 	}
 }
 
@@ -444,3 +463,13 @@ Severity: Low`
 		t.Errorf("issues[1].Name = %q", issues[1].Name)
 	}
 }
+
+func TestGetHeader_CaseInsensitive(t *testing.T) {
+	headers := map[string][]string{"ETag": {`W/"1"`, `"2"`}}
+	if got := GetHeader(headers, "etag"); got != `W/"1"` {
+		t.Errorf("GetHeader(etag) = %q, want first value", got)
+	}
+	if got := GetHeader(headers, "Last-Modified"); got != "" {
+		t.Errorf("GetHeader(missing) = %q, want empty", got)
+	}
+}
@@ -0,0 +1,344 @@
+package burp
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"mime"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// maxProtoDepth bounds how deep length-delimited fields are tried as nested
+// messages.
+const maxProtoDepth = 16
+
+// Proto wire types.
+const (
+	wireVarint     = 0
+	wireFixed64    = 1
+	wireBytes      = 2
+	wireStartGroup = 3
+	wireEndGroup   = 4
+	wireFixed32    = 5
+)
+
+// Protobuf body formats, by Content-Type.
+const (
+	protoNone    = iota
+	protoBare    // a single message
+	protoFramed  // gRPC length-prefixed messages
+	protoWebText // base64 of gRPC-Web framed messages
+)
+
+func protobufFormat(contentType string) int {
+	mt, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return protoNone
+	}
+	switch mt {
+	case "application/grpc", "application/grpc+proto", "application/grpc-web", "application/grpc-web+proto":
+		return protoFramed
+	case "application/grpc-web-text", "application/grpc-web-text+proto":
+		return protoWebText
+	case "application/x-protobuf", "application/protobuf", "application/vnd.google.protobuf", "application/x-google-protobuf":
+		return protoBare
+	}
+	return protoNone
+}
+
+// IsProtobuf reports whether contentType is a gRPC, gRPC-Web, or protobuf
+// media type.
+func IsProtobuf(contentType string) bool {
+	return protobufFormat(contentType) != protoNone
+}
+
+// ProtoMessage is one decoded protobuf message from a body.
+type ProtoMessage struct {
+	// Size is the message length in bytes, as sent (compressed or not).
+	Size       int
+	Compressed bool
+	// Trailers holds the text of a gRPC-Web trailer frame instead of a message.
+	Trailers string
+	// Decoded is the message in protoc --decode_raw style: field numbers,
+	// not names, since there is no schema.
+	Decoded string
+	// Err is why decoding stopped; Decoded holds what came before it.
+	Err error
+}
+
+// DecodeProtobufBody decodes a gRPC, gRPC-Web, or protobuf body.
+// grpcEncoding is the grpc-encoding header, used for compressed frames.
+// An unframing error is returned along with the messages read before it.
+func DecodeProtobufBody(contentType, grpcEncoding, body string) ([]ProtoMessage, error) {
+	data := []byte(body)
+	switch protobufFormat(contentType) {
+	case protoNone:
+		return nil, fmt.Errorf("not a protobuf content type: %q", contentType)
+	case protoBare:
+		return []ProtoMessage{decodeMessage(data)}, nil
+	case protoWebText:
+		var err error
+		if data, err = decodeWebText(body); err != nil {
+			return nil, fmt.Errorf("grpc-web-text body: %w", err)
+		}
+	}
+
+	frames, err := UnframeGRPC(data)
+	msgs := make([]ProtoMessage, 0, len(frames))
+	for _, f := range frames {
+		if f.Trailers {
+			msgs = append(msgs, ProtoMessage{Size: len(f.Data), Trailers: strings.TrimSpace(string(f.Data))})
+			continue
+		}
+		payload, cut := f.Data, false
+		if f.Compressed {
+			var derr error
+			if payload, cut, derr = decompress(grpcEncoding, f.Data); derr != nil {
+				msgs = append(msgs, ProtoMessage{Size: len(f.Data), Compressed: true, Err: derr})
+				continue
+			}
+		}
+		m := decodeMessage(payload)
+		m.Size, m.Compressed = len(f.Data), f.Compressed
+		if cut {
+			m.Err = fmt.Errorf("decompressed message exceeds %d bytes; decoded the first %d", maxDecompressed, maxDecompressed)
+		}
+		msgs = append(msgs, m)
+	}
+	return msgs, err
+}
+
+// decodeWebText decodes a grpc-web-text body, which may be several base64
+// chunks, each with its own padding, concatenated.
+func decodeWebText(body string) ([]byte, error) {
+	body = strings.Join(strings.Fields(body), "")
+	var out []byte
+	for body != "" {
+		end := strings.IndexByte(body, '=')
+		if end < 0 {
+			end = len(body)
+		} else {
+			for end < len(body) && body[end] == '=' {
+				end++
+			}
+		}
+		chunk, err := base64.StdEncoding.DecodeString(body[:end])
+		if err != nil {
+			return out, err
+		}
+		out = append(out, chunk...)
+		body = body[end:]
+	}
+	return out, nil
+}
+
+// maxDecompressed caps a decompressed message, so a small frame from the
+// target can't expand into gigabytes.
+const maxDecompressed = 1 << 20 // 1 MB
+
+// decompress inflates a compressed frame, up to maxDecompressed bytes; cut
+// reports that the message was longer.
+func decompress(encoding string, data []byte) (out []byte, cut bool, err error) {
+	switch strings.ToLower(strings.TrimSpace(encoding)) {
+	case "gzip":
+		r, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, false, fmt.Errorf("gzip: %w", err)
+		}
+		if out, err = io.ReadAll(io.LimitReader(r, maxDecompressed+1)); err != nil {
+			return nil, false, fmt.Errorf("gzip: %w", err)
+		}
+		if len(out) > maxDecompressed {
+			return out[:maxDecompressed], true, nil
+		}
+		return out, false, nil
+	case "":
+		return nil, false, errors.New("compressed frame without a grpc-encoding header")
+	}
+	return nil, false, fmt.Errorf("unsupported grpc-encoding %q", encoding)
+}
+
+func decodeMessage(data []byte) ProtoMessage {
+	m := ProtoMessage{Size: len(data)}
+	m.Decoded, m.Err = DecodeProto(data)
+	return m
+}
+
+// GRPCFrame is one length-prefixed message in a gRPC or gRPC-Web body.
+type GRPCFrame struct {
+	Compressed bool
+	// Trailers marks a gRPC-Web trailer frame.
+	Trailers bool
+	Data     []byte
+}
+
+// UnframeGRPC splits a body into its 5-byte-prefixed frames: a flags byte
+// and a big-endian length. A truncated last frame is an error, returned
+// with the frames before it.
+func UnframeGRPC(body []byte) ([]GRPCFrame, error) {
+	var frames []GRPCFrame
+	for offset := 0; offset < len(body); {
+		if len(body)-offset < 5 {
+			return frames, fmt.Errorf("offset %d: %d bytes left, too short for a frame header", offset, len(body)-offset)
+		}
+		flags := body[offset]
+		n := binary.BigEndian.Uint32(body[offset+1 : offset+5])
+		start := offset + 5
+		if uint64(n) > uint64(len(body)-start) {
+			return frames, fmt.Errorf("offset %d: frame length %d exceeds the %d bytes left", offset, n, len(body)-start)
+		}
+		frames = append(frames, GRPCFrame{
+			Compressed: flags&0x01 != 0,
+			Trailers:   flags&0x80 != 0,
+			Data:       body[start : start+int(n)],
+		})
+		offset = start + int(n)
+	}
+	return frames, nil
+}
+
+type protoField struct {
+	num   uint64
+	wire  int
+	value uint64 // varint and fixed types
+	data  []byte // length-delimited
+	group []protoField
+}
+
+// DecodeProto renders a protobuf message without its schema, like protoc
+// --decode_raw: one field per line, nested messages in braces. Length-
+// delimited fields show as strings when printable, else as nested messages
+// when they parse as one, else as escaped bytes. On malformed input the
+// fields read so far are rendered and the error says where it stopped.
+func DecodeProto(data []byte) (string, error) {
+	fields, _, err := parseProto(data, 0)
+	var sb strings.Builder
+	renderProto(&sb, fields, "", 0)
+	return strings.TrimSuffix(sb.String(), "\n"), err
+}
+
+// parseProto reads fields until b runs out or, inside group number group,
+// until its end tag. It returns the fields and the bytes consumed.
+func parseProto(b []byte, group uint64) ([]protoField, int, error) {
+	var fields []protoField
+	pos := 0
+	for pos < len(b) {
+		tag, n := binary.Uvarint(b[pos:])
+		if n <= 0 {
+			return fields, pos, fmt.Errorf("offset %d: malformed tag", pos)
+		}
+		f := protoField{num: tag >> 3, wire: int(tag & 7)}
+		if f.num == 0 {
+			return fields, pos, fmt.Errorf("offset %d: field number 0", pos)
+		}
+		start := pos
+		pos += n
+		switch f.wire {
+		case wireVarint:
+			v, n := binary.Uvarint(b[pos:])
+			if n <= 0 {
+				return fields, start, fmt.Errorf("offset %d: malformed varint in field %d", pos, f.num)
+			}
+			f.value = v
+			pos += n
+		case wireFixed64:
+			if len(b)-pos < 8 {
+				return fields, start, fmt.Errorf("offset %d: truncated fixed64 in field %d", pos, f.num)
+			}
+			f.value = binary.LittleEndian.Uint64(b[pos:])
+			pos += 8
+		case wireFixed32:
+			if len(b)-pos < 4 {
+				return fields, start, fmt.Errorf("offset %d: truncated fixed32 in field %d", pos, f.num)
+			}
+			f.value = uint64(binary.LittleEndian.Uint32(b[pos:]))
+			pos += 4
+		case wireBytes:
+			l, n := binary.Uvarint(b[pos:])
+			if n <= 0 {
+				return fields, start, fmt.Errorf("offset %d: malformed length in field %d", pos, f.num)
+			}
+			pos += n
+			if l > uint64(len(b)-pos) {
+				return fields, start, fmt.Errorf("offset %d: field %d length %d exceeds the %d bytes left", pos, f.num, l, len(b)-pos)
+			}
+			f.data = b[pos : pos+int(l)]
+			pos += int(l)
+		case wireStartGroup:
+			sub, n, err := parseProto(b[pos:], f.num)
+			if err != nil {
+				return fields, start, err
+			}
+			f.group = sub
+			pos += n
+		case wireEndGroup:
+			if f.num != group {
+				return fields, start, fmt.Errorf("offset %d: unexpected end of group %d", start, f.num)
+			}
+			return fields, pos, nil
+		default:
+			return fields, start, fmt.Errorf("offset %d: invalid wire type %d in field %d", start, f.wire, f.num)
+		}
+		fields = append(fields, f)
+	}
+	if group != 0 {
+		return fields, pos, fmt.Errorf("group %d not terminated", group)
+	}
+	return fields, pos, nil
+}
+
+func renderProto(sb *strings.Builder, fields []protoField, indent string, depth int) {
+	for _, f := range fields {
+		num := strconv.FormatUint(f.num, 10)
+		switch f.wire {
+		case wireVarint:
+			fmt.Fprintf(sb, "%s%s: %d", indent, num, f.value)
+			if int64(f.value) < 0 {
+				fmt.Fprintf(sb, " (int64 %d)", int64(f.value))
+			}
+			sb.WriteString("\n")
+		case wireFixed64:
+			fmt.Fprintf(sb, "%s%s: 0x%016x (double %g)\n", indent, num, f.value, math.Float64frombits(f.value))
+		case wireFixed32:
+			fmt.Fprintf(sb, "%s%s: 0x%08x (float %g)\n", indent, num, f.value, math.Float32frombits(uint32(f.value)))
+		case wireStartGroup:
+			sb.WriteString(indent + num + " {\n")
+			renderProto(sb, f.group, indent+"  ", depth+1)
+			sb.WriteString(indent + "}\n")
+		case wireBytes:
+			if !printable(f.data) && depth < maxProtoDepth {
+				if sub, _, err := parseProto(f.data, 0); err == nil && len(sub) > 0 {
+					sb.WriteString(indent + num + " {\n")
+					renderProto(sb, sub, indent+"  ", depth+1)
+					sb.WriteString(indent + "}\n")
+					continue
+				}
+			}
+			sb.WriteString(indent + num + ": " + strconv.Quote(string(f.data)) + "\n")
+		}
+	}
+}
+
+// printable reports whether b is UTF-8 text without control characters.
+// Nested messages almost always start with a tag byte below 0x20, so this
+// keeps short strings from being misread as messages. Text with newlines
+// falls through to the message attempt, and is quoted when that fails.
+func printable(b []byte) bool {
+	if !utf8.Valid(b) {
+		return false
+	}
+	for _, r := range string(b) {
+		if !unicode.IsPrint(r) {
+			return false
+		}
+	}
+	return true
+}
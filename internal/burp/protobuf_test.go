@@ -0,0 +1,143 @@
+package burp
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/binary"
+	"strings"
+	"testing"
+)
+
+// grpcFrame prefixes msg with a gRPC frame header.
+func grpcFrame(flags byte, msg []byte) []byte {
+	h := make([]byte, 5)
+	h[0] = flags
+	binary.BigEndian.PutUint32(h[1:], uint32(len(msg)))
+	return append(h, msg...)
+}
+
+// A message with a varint, a string, a nested message, fixed32 and fixed64
+// fields, bytes that aren't a message, and a negative int64.
+var testProto = []byte{
+	0x08, 0x96, 0x01, // 1: 150
+	0x12, 0x05, 'a', 'l', 'i', 'c', 'e', // 2: "alice"
+	0x1a, 0x04, 0x08, 0x01, 0x10, 0x02, // 3 { 1: 1  2: 2 }
+	0x25, 0x00, 0x00, 0x80, 0x3f, // 4: float 1
+	0x29, 0, 0, 0, 0, 0, 0, 0xf0, 0x3f, // 5: double 1
+	0x32, 0x02, 0xff, 0x00, // 6: bytes
+	0x38, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x01, // 7: -1
+}
+
+const testProtoDecoded = `1: 150
+2: "alice"
+3 {
+  1: 1
+  2: 2
+}
+4: 0x3f800000 (float 1)
+5: 0x3ff0000000000000 (double 1)
+6: "\xff\x00"
+7: 18446744073709551615 (int64 -1)`
+
+func TestDecodeProto(t *testing.T) {
+	got, err := DecodeProto(testProto)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != testProtoDecoded {
+		t.Errorf("got\n%s\nwant\n%s", got, testProtoDecoded)
+	}
+}
+
+func TestDecodeProto_Group(t *testing.T) {
+	// 1 { 2: 5 } as a group: start tag 0x0b, end tag 0x0c.
+	got, err := DecodeProto([]byte{0x0b, 0x10, 0x05, 0x0c, 0x18, 0x01})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "1 {\n  2: 5\n}\n3: 1"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestDecodeProto_Malformed(t *testing.T) {
+	// A valid field, then a length that runs past the end.
+	got, err := DecodeProto([]byte{0x08, 0x01, 0x12, 0x10, 'x'})
+	if err == nil || !strings.Contains(err.Error(), "exceeds") {
+		t.Errorf("err = %v", err)
+	}
+	if got != "1: 1" {
+		t.Errorf("partial decode = %q", got)
+	}
+	if _, err := DecodeProto([]byte{0x0f}); err == nil {
+		t.Error("expected an error for wire type 7")
+	}
+}
+
+func TestUnframeGRPC(t *testing.T) {
+	body := append(grpcFrame(0, []byte{0x08, 0x01}), grpcFrame(0x80, []byte("grpc-status:0\r\n"))...)
+	frames, err := UnframeGRPC(body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(frames) != 2 || frames[0].Trailers || !frames[1].Trailers {
+		t.Fatalf("frames = %+v", frames)
+	}
+
+	frames, err = UnframeGRPC(append(body, 0, 0, 0, 0, 9, 1))
+	if err == nil || len(frames) != 2 {
+		t.Errorf("truncated frame: %d frames, err %v", len(frames), err)
+	}
+}
+
+func TestDecodeProtobufBody(t *testing.T) {
+	var gz bytes.Buffer
+	w := gzip.NewWriter(&gz)
+	w.Write(testProto)
+	w.Close()
+
+	framed := append(grpcFrame(0, testProto), grpcFrame(1, gz.Bytes())...)
+	tests := []struct {
+		name, contentType, encoding, body string
+		want                              int
+	}{
+		{"grpc", "application/grpc", "gzip", string(framed), 2},
+		{"grpc-web-text", "application/grpc-web-text+proto", "gzip", base64.StdEncoding.EncodeToString(grpcFrame(0, testProto)) + base64.StdEncoding.EncodeToString(grpcFrame(0, testProto)), 2},
+		{"bare", "application/x-protobuf", "", string(testProto), 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msgs, err := DecodeProtobufBody(tt.contentType, tt.encoding, tt.body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(msgs) != tt.want {
+				t.Fatalf("got %d messages", len(msgs))
+			}
+			for i, m := range msgs {
+				if m.Err != nil || m.Decoded != testProtoDecoded {
+					t.Errorf("message %d = %+v", i, m)
+				}
+			}
+		})
+	}
+
+	msgs, _ := DecodeProtobufBody("application/grpc", "", string(grpcFrame(1, gz.Bytes())))
+	if len(msgs) != 1 || !msgs[0].Compressed || msgs[0].Err == nil {
+		t.Errorf("compressed frame without grpc-encoding = %+v", msgs)
+	}
+
+	// A bomb inflates only up to the cap.
+	var bomb bytes.Buffer
+	w = gzip.NewWriter(&bomb)
+	w.Write(make([]byte, 4*maxDecompressed))
+	w.Close()
+	msgs, _ = DecodeProtobufBody("application/grpc", "gzip", string(grpcFrame(1, bomb.Bytes())))
+	if len(msgs) != 1 || msgs[0].Err == nil || !strings.Contains(msgs[0].Err.Error(), "exceeds") {
+		t.Errorf("decompression bomb = %+v", msgs)
+	}
+	if _, err := DecodeProtobufBody("application/json", "", "{}"); err == nil {
+		t.Error("expected an error for a non-protobuf type")
+	}
+}
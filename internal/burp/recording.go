@@ -0,0 +1,176 @@
+package burp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+)
+
+// ErrNotRecorded marks a replayed call that has no recorded counterpart.
+var ErrNotRecorded = errors.New("not recorded")
+
+// Interaction is one upstream call and its outcome, one JSON line in a
+// recording.
+type Interaction struct {
+	Instance string          `json:"instance,omitempty"`
+	Tool     string          `json:"tool"`
+	Args     json.RawMessage `json:"args"`
+	Text     string          `json:"text,omitempty"`
+	Error    string          `json:"error,omitempty"`
+	// ErrorKind is upstream, unreachable, or timeout when the error wrapped
+	// ErrUpstream, ErrUnreachable, or ErrTimeout, so replay classifies it
+	// the same way.
+	ErrorKind string `json:"errorKind,omitempty"`
+}
+
+var errorKinds = map[string]error{
+	"upstream":    ErrUpstream,
+	"unreachable": ErrUnreachable,
+	"timeout":     ErrTimeout,
+}
+
+// interactionKey matches a call to its recordings. Arguments marshal with
+// sorted keys, so equal maps give equal keys.
+func interactionKey(instance, tool string, args json.RawMessage) string {
+	return instance + "\x00" + tool + "\x00" + string(args)
+}
+
+func marshalArgs(args map[string]any) (json.RawMessage, error) {
+	if args == nil {
+		args = map[string]any{}
+	}
+	return json.Marshal(args)
+}
+
+// recorder appends every call to a recording file.
+type recorder struct {
+	mu sync.Mutex
+	f  *os.File
+}
+
+// Record makes the client write every upstream call and its outcome to
+// path, replacing the file. Recordings hold the raw requests and responses,
+// credentials included.
+func (c *Client) Record(path string) error {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return fmt.Errorf("open recording: %w", err)
+	}
+	c.recorder = &recorder{f: f}
+	return nil
+}
+
+func (r *recorder) record(instance, tool string, args map[string]any, text string, err error) {
+	if errors.Is(err, context.Canceled) {
+		return
+	}
+	in := Interaction{Instance: instance, Tool: tool, Text: text}
+	raw, mErr := marshalArgs(args)
+	if mErr != nil {
+		fmt.Fprintf(os.Stderr, "recording: %s arguments: %v\n", tool, mErr)
+		return
+	}
+	in.Args = raw
+	if err != nil {
+		in.Error = err.Error()
+		for kind, sentinel := range errorKinds {
+			if errors.Is(err, sentinel) {
+				in.ErrorKind = kind
+				break
+			}
+		}
+	}
+	line, _ := json.Marshal(in)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, wErr := r.f.Write(append(line, '\n')); wErr != nil {
+		fmt.Fprintf(os.Stderr, "recording: %v\n", wErr)
+	}
+}
+
+func (r *recorder) close() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.f.Close()
+}
+
+// replayer answers calls from a recording.
+type replayer struct {
+	mu    sync.Mutex
+	calls map[string][]Interaction
+}
+
+// Replay makes the client answer every call from a recording written by
+// Record instead of Burp; no connection is needed. Calls with the same
+// instance, tool, and arguments get their recorded outcomes in order, the
+// last repeating once the others are used up.
+func (c *Client) Replay(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("open recording: %w", err)
+	}
+	defer f.Close()
+
+	r := &replayer{calls: make(map[string][]Interaction)}
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 0, 64*1024), 64<<20)
+	for n := 1; sc.Scan(); n++ {
+		if len(sc.Bytes()) == 0 {
+			continue
+		}
+		var in Interaction
+		if err := json.Unmarshal(sc.Bytes(), &in); err != nil {
+			return fmt.Errorf("recording %s line %d: %w", path, n, err)
+		}
+		// Re-marshal so the key doesn't depend on the file's formatting.
+		var args map[string]any
+		if err := json.Unmarshal(in.Args, &args); err != nil {
+			return fmt.Errorf("recording %s line %d: args: %w", path, n, err)
+		}
+		in.Args, _ = marshalArgs(args)
+		key := interactionKey(in.Instance, in.Tool, in.Args)
+		r.calls[key] = append(r.calls[key], in)
+	}
+	if err := sc.Err(); err != nil {
+		return fmt.Errorf("read recording: %w", err)
+	}
+	c.replayer = r
+	return nil
+}
+
+func (r *replayer) call(instance, tool string, args map[string]any) (string, error) {
+	raw, err := marshalArgs(args)
+	if err != nil {
+		return "", err
+	}
+	key := interactionKey(instance, tool, raw)
+	r.mu.Lock()
+	queue := r.calls[key]
+	if len(queue) == 0 {
+		r.mu.Unlock()
+		return "", fmt.Errorf("call %s: %w: no recorded call with arguments %s", tool, ErrNotRecorded, raw)
+	}
+	in := queue[0]
+	if len(queue) > 1 {
+		r.calls[key] = queue[1:]
+	}
+	r.mu.Unlock()
+
+	if in.Error != "" {
+		return in.Text, &replayedError{msg: in.Error, kind: errorKinds[in.ErrorKind]}
+	}
+	return in.Text, nil
+}
+
+// replayedError reproduces a recorded error's message and classification.
+type replayedError struct {
+	msg  string
+	kind error
+}
+
+func (e *replayedError) Error() string { return e.msg }
+func (e *replayedError) Unwrap() error { return e.kind }
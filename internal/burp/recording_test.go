@@ -0,0 +1,60 @@
+package burp
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestRecordReplay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "burp.jsonl")
+	var calls atomic.Int32
+	c, _ := NewClient("in-memory")
+	c.SetRetryPolicy(RetryPolicy{})
+	if err := c.Record(path); err != nil {
+		t.Fatal(err)
+	}
+	connectTestServer(t, c, func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		n := calls.Add(1)
+		if n == 3 {
+			return &mcp.CallToolResult{IsError: true, Content: []mcp.Content{&mcp.TextContent{Text: "invalid host"}}}, nil
+		}
+		text := "HTTP/1.1 200 OK\r\n\r\nfirst"
+		if n == 2 {
+			text = "HTTP/1.1 200 OK\r\n\r\nsecond"
+		}
+		return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: text}}}, nil
+	})
+
+	args := map[string]any{"targetHostname": "a.test", "targetPort": 443}
+	ctx := context.Background()
+	first, _ := c.CallTool(ctx, "send_http1_request", args)
+	second, _ := c.CallTool(ctx, "send_http1_request", args)
+	_, recordedErr := c.CallTool(ctx, "send_http1_request", map[string]any{"targetHostname": "bad"})
+	c.Close()
+
+	r, _ := NewClient("http://127.0.0.1:1/sse") // never connected
+	if err := r.Replay(path); err != nil {
+		t.Fatal(err)
+	}
+	// Same arguments in another order replay in recorded order, the last
+	// repeating.
+	same := map[string]any{"targetPort": 443, "targetHostname": "a.test"}
+	for i, want := range []string{first, second, second} {
+		got, err := r.CallTool(ctx, "send_http1_request", same)
+		if err != nil || got != want {
+			t.Errorf("replay %d = %q, %v; want %q", i, got, err, want)
+		}
+	}
+	_, err := r.CallTool(ctx, "send_http1_request", map[string]any{"targetHostname": "bad"})
+	if !errors.Is(err, ErrUpstream) || err.Error() != recordedErr.Error() {
+		t.Errorf("replayed error = %v, want %v classified as ErrUpstream", err, recordedErr)
+	}
+	if _, err := r.CallTool(ctx, "get_scanner_issues", nil); !errors.Is(err, ErrNotRecorded) {
+		t.Errorf("unrecorded call err = %v", err)
+	}
+}
@@ -0,0 +1,187 @@
+package burp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"regexp"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// Retry classes select which failures a RetryPolicy retries.
+const (
+	RetryTimeout        = "timeout"         // a Burp call that sends no traffic exceeded its timeout
+	RetryTrafficTimeout = "traffic_timeout" // a request through Burp exceeded its timeout
+	RetryConnection     = "connection"      // the SSE connection failed even after reconnecting
+	RetryBadGateway     = "bad_gateway"     // Burp answered with a 502 response
+)
+
+// RetryPolicy controls retries of failed Burp tool calls.
+// The zero value disables retries.
+type RetryPolicy struct {
+	MaxRetries     int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	RetryOn        []string
+}
+
+// DefaultRetryPolicy retries timeouts and connection failures twice.
+// Timed-out requests and 502 responses are not retried by default: the
+// request may already have reached the target, and a 502 may come from it.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxRetries:     2,
+	InitialBackoff: 500 * time.Millisecond,
+	MaxBackoff:     5 * time.Second,
+	RetryOn:        []string{RetryTimeout, RetryConnection},
+}
+
+// ValidRetryClass reports whether class is a known retry class.
+func ValidRetryClass(class string) bool {
+	switch class {
+	case RetryTimeout, RetryTrafficTimeout, RetryConnection, RetryBadGateway:
+		return true
+	}
+	return false
+}
+
+// ErrTimeout marks a call that hit its own timeout (not the caller's deadline).
+var ErrTimeout = errors.New("timed out")
+
+// badGatewayRe matches a 502 status line in Burp's (possibly wrapped) response text.
+var badGatewayRe = regexp.MustCompile(`HTTP/[\d.]+ 502\b`)
+
+// sendsTraffic reports whether the Burp tool name sends a request to a target.
+func sendsTraffic(name string) bool {
+	return strings.HasPrefix(name, "send_http")
+}
+
+// retryClass classifies the outcome of calling Burp tool name, or returns
+// "" when it isn't retryable.
+func retryClass(name, text string, err error) string {
+	switch {
+	case err == nil:
+		if badGatewayRe.MatchString(firstLine(UnwrapResponse(text))) {
+			return RetryBadGateway
+		}
+		return ""
+	case errors.Is(err, ErrTimeout):
+		if sendsTraffic(name) {
+			return RetryTrafficTimeout
+		}
+		return RetryTimeout
+	case isConnectionError(err):
+		return RetryConnection
+	}
+	return ""
+}
+
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(s, "\n")
+	return line
+}
+
+// shouldRetry reports whether the policy retries the given class after attempt (0-based).
+func (p RetryPolicy) shouldRetry(class string, attempt int) bool {
+	if class == "" || attempt >= p.MaxRetries {
+		return false
+	}
+	for _, c := range p.RetryOn {
+		if c == class {
+			return true
+		}
+	}
+	return false
+}
+
+// backoff returns the jittered exponential delay before retry number attempt+1.
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	d := p.InitialBackoff << attempt
+	if p.MaxBackoff > 0 && (d > p.MaxBackoff || d <= 0) {
+		d = p.MaxBackoff
+	}
+	if d <= 0 {
+		return 0
+	}
+	// Up to 20% jitter so parallel callers don't retry in lockstep.
+	return d - time.Duration(rand.Int64N(int64(d)/5+1))
+}
+
+// SetRetryPolicy sets the retry policy for this client and its registered instances.
+// Instances added later inherit it.
+func (c *Client) SetRetryPolicy(p RetryPolicy) {
+	c.retry = p
+	c.instMu.Lock()
+	defer c.instMu.Unlock()
+	for _, inst := range c.instances {
+		inst.retry = p
+	}
+}
+
+type noRetryKey struct{}
+
+type retryGateKey struct{}
+
+// WithRetryGate returns a context whose retried Burp calls first pass gate,
+// so a retry of a request counts against the caller's rate limits like the
+// first attempt. A gate error ends the retries with the last failure.
+func WithRetryGate(ctx context.Context, gate func() error) context.Context {
+	return context.WithValue(ctx, retryGateKey{}, gate)
+}
+
+// WithoutRetry returns a context whose Burp calls are never retried, for
+// callers that handle failure themselves (e.g. by falling back to HTTP/1.1).
+func WithoutRetry(ctx context.Context) context.Context {
+	return context.WithValue(ctx, noRetryKey{}, true)
+}
+
+// callWithRetry runs callTool under the client's retry policy.
+func (c *Client) callWithRetry(ctx context.Context, name string, args map[string]any, timeout time.Duration) (string, error) {
+	policy := c.retry
+	if ctx.Value(noRetryKey{}) != nil {
+		policy = RetryPolicy{}
+	}
+	for attempt := 0; ; attempt++ {
+		text, err := c.callTool(ctx, name, args, timeout)
+		class := retryClass(name, text, err)
+		if !policy.shouldRetry(class, attempt) || ctx.Err() != nil || !passGate(ctx) {
+			if err != nil && attempt > 0 {
+				err = fmt.Errorf("%w (after %d retries)", err, attempt)
+			}
+			return text, err
+		}
+
+		countRetry(ctx)
+		select {
+		case <-time.After(policy.backoff(attempt)):
+		case <-ctx.Done():
+			if err == nil {
+				err = ctx.Err()
+			}
+			return text, err
+		}
+	}
+}
+
+// passGate runs ctx's retry gate, if any.
+func passGate(ctx context.Context) bool {
+	gate, ok := ctx.Value(retryGateKey{}).(func() error)
+	return !ok || gate() == nil
+}
+
+type retryCounterKey struct{}
+
+// WithRetryCounter returns a context that counts retries made by Burp calls
+// under it, and a function that reports the count so far.
+func WithRetryCounter(ctx context.Context) (context.Context, func() int) {
+	n := new(atomic.Int64)
+	return context.WithValue(ctx, retryCounterKey{}, n), func() int { return int(n.Load()) }
+}
+
+func countRetry(ctx context.Context) {
+	if n, ok := ctx.Value(retryCounterKey{}).(*atomic.Int64); ok {
+		n.Add(1)
+	}
+}
@@ -0,0 +1,139 @@
+package burp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestRetryClass(t *testing.T) {
+	tests := []struct {
+		name string
+		tool string
+		text string
+		err  error
+		want string
+	}{
+		{"success", "send_http1_request", "HTTP/1.1 200 OK\r\n\r\n", nil, ""},
+		{"bad gateway", "send_http1_request", "HTTP/1.1 502 Bad Gateway\r\n\r\n", nil, RetryBadGateway},
+		{"502 in body only", "send_http1_request", "HTTP/1.1 200 OK\r\n\r\nHTTP/1.1 502", nil, ""},
+		{"timeout", "get_proxy_http_history", "", fmt.Errorf("call x: %w after 30s", ErrTimeout), RetryTimeout},
+		{"traffic timeout", "send_http2_request", "", fmt.Errorf("call x: %w after 30s", ErrTimeout), RetryTrafficTimeout},
+		{"connection", "send_http1_request", "", fmt.Errorf("connection closed"), RetryConnection},
+		{"burp error", "send_http1_request", "", fmt.Errorf("burp error: invalid host"), ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := retryClass(tt.tool, tt.text, tt.err); got != tt.want {
+				t.Errorf("retryClass = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	p := RetryPolicy{InitialBackoff: 100 * time.Millisecond, MaxBackoff: 300 * time.Millisecond}
+	for attempt, ceiling := range []time.Duration{100, 200, 300, 300} {
+		ceiling *= time.Millisecond
+		d := p.backoff(attempt)
+		if d > ceiling || d < ceiling*4/5 {
+			t.Errorf("backoff(%d) = %s, want within 20%% below %s", attempt, d, ceiling)
+		}
+	}
+	if d := (RetryPolicy{}).backoff(3); d != 0 {
+		t.Errorf("zero policy backoff = %s", d)
+	}
+}
+
+// connectTestServer connects c to an in-memory MCP server exposing one tool.
+func connectTestServer(t *testing.T, c *Client, handler mcp.ToolHandler) {
+	t.Helper()
+	server := mcp.NewServer(&mcp.Implementation{Name: "fake-burp"}, nil)
+	server.AddTool(&mcp.Tool{Name: "send_http1_request", InputSchema: map[string]any{"type": "object"}}, handler)
+
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	ctx := context.Background()
+	if _, err := server.Connect(ctx, serverTransport, nil); err != nil {
+		t.Fatal(err)
+	}
+	session, err := c.client.Connect(ctx, clientTransport, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { session.Close() })
+	c.session = session
+}
+
+func TestCallTool_RetriesBadGateway(t *testing.T) {
+	var calls atomic.Int32
+	c, _ := NewClient("in-memory")
+	c.SetRetryPolicy(RetryPolicy{MaxRetries: 3, InitialBackoff: time.Millisecond, RetryOn: []string{RetryBadGateway}})
+	connectTestServer(t, c, func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		text := "HTTP/1.1 200 OK\r\n\r\nok"
+		if calls.Add(1) <= 2 {
+			text = "HTTP/1.1 502 Bad Gateway\r\n\r\n"
+		}
+		return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: text}}}, nil
+	})
+
+	ctx, retries := WithRetryCounter(context.Background())
+	text, err := c.CallTool(ctx, "send_http1_request", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if text != "HTTP/1.1 200 OK\r\n\r\nok" || calls.Load() != 3 || retries() != 2 {
+		t.Errorf("text %q, calls %d, retries %d", text, calls.Load(), retries())
+	}
+
+	// WithoutRetry returns the first 502 as-is.
+	calls.Store(0)
+	text, err = c.CallTool(WithoutRetry(context.Background()), "send_http1_request", nil)
+	if err != nil || calls.Load() != 1 || text != "HTTP/1.1 502 Bad Gateway\r\n\r\n" {
+		t.Errorf("without retry: text %q, err %v, calls %d", text, err, calls.Load())
+	}
+}
+
+func TestCallTool_RetryGate(t *testing.T) {
+	var calls atomic.Int32
+	c, _ := NewClient("in-memory")
+	c.SetRetryPolicy(RetryPolicy{MaxRetries: 3, InitialBackoff: time.Millisecond, RetryOn: []string{RetryBadGateway}})
+	connectTestServer(t, c, func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		calls.Add(1)
+		return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: "HTTP/1.1 502 Bad Gateway\r\n\r\n"}}}, nil
+	})
+
+	// The gate allows one retry, then refuses.
+	var gated atomic.Int32
+	ctx := WithRetryGate(context.Background(), func() error {
+		if gated.Add(1) > 1 {
+			return errors.New("rate limited")
+		}
+		return nil
+	})
+	text, err := c.CallTool(ctx, "send_http1_request", nil)
+	if err != nil || calls.Load() != 2 || text != "HTTP/1.1 502 Bad Gateway\r\n\r\n" {
+		t.Errorf("text %q, err %v, calls %d; want the second 502 after one retry", text, err, calls.Load())
+	}
+}
+
+func TestCallTool_NoRetryOnBurpError(t *testing.T) {
+	var calls atomic.Int32
+	c, _ := NewClient("in-memory")
+	c.SetRetryPolicy(RetryPolicy{MaxRetries: 3, RetryOn: []string{RetryTimeout, RetryTrafficTimeout, RetryConnection, RetryBadGateway}})
+	connectTestServer(t, c, func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		calls.Add(1)
+		return &mcp.CallToolResult{IsError: true, Content: []mcp.Content{&mcp.TextContent{Text: "invalid host"}}}, nil
+	})
+
+	if _, err := c.CallTool(context.Background(), "send_http1_request", nil); !errors.Is(err, ErrUpstream) {
+		t.Errorf("err = %v, want ErrUpstream", err)
+	}
+	if calls.Load() != 1 {
+		t.Errorf("calls = %d, want 1", calls.Load())
+	}
+}
@@ -0,0 +1,142 @@
+// Package callback records out-of-band interactions for when Burp
+// Collaborator isn't available. The listen command runs HTTP and DNS
+// listeners that append to a JSON Lines log; burp_get_callbacks, in the
+// MCP server process, reads it.
+package callback
+
+import (
+	"bufio"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Interaction is one request or query a listener received.
+type Interaction struct {
+	ID       string    `json:"id"`
+	Protocol string    `json:"protocol"` // "http" or "dns"
+	Time     time.Time `json:"time"`
+	SourceIP string    `json:"sourceIp"`
+	// Host is the HTTP Host header or the DNS query name.
+	Host string `json:"host"`
+	// Summary is "METHOD /path" or the DNS query type.
+	Summary string `json:"summary"`
+	// Raw is the full HTTP request or a description of the DNS question.
+	Raw string `json:"raw"`
+}
+
+// Filter selects interactions from the log.
+type Filter struct {
+	// Contains matches case-insensitively against host, summary, and raw.
+	Contains string
+	Protocol string
+	Since    time.Time
+	// Limit keeps the newest Limit matches; zero keeps all.
+	Limit int
+}
+
+func (f Filter) match(in Interaction) bool {
+	if f.Protocol != "" && !strings.EqualFold(in.Protocol, f.Protocol) {
+		return false
+	}
+	if !f.Since.IsZero() && in.Time.Before(f.Since) {
+		return false
+	}
+	if f.Contains == "" {
+		return true
+	}
+	needle := strings.ToLower(f.Contains)
+	for _, s := range []string{in.Host, in.Summary, in.Raw} {
+		if strings.Contains(strings.ToLower(s), needle) {
+			return true
+		}
+	}
+	return false
+}
+
+// Log is an append-only JSON Lines file of interactions.
+type Log struct {
+	path string
+	mu   sync.Mutex
+}
+
+// Open returns the log at path. The file is created on first append.
+func Open(path string) *Log {
+	return &Log{path: path}
+}
+
+// Path returns the file backing the log.
+func (l *Log) Path() string {
+	return l.path
+}
+
+// Append assigns in an ID and timestamp if unset and writes it.
+func (l *Log) Append(in *Interaction) error {
+	if in.ID == "" {
+		var b [4]byte
+		if _, err := rand.Read(b[:]); err != nil {
+			return err
+		}
+		in.ID = hex.EncodeToString(b[:])
+	}
+	if in.Time.IsZero() {
+		in.Time = time.Now().UTC()
+	}
+	line, err := json.Marshal(in)
+	if err != nil {
+		return fmt.Errorf("encode interaction: %w", err)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if err := os.MkdirAll(filepath.Dir(l.path), 0o700); err != nil {
+		return fmt.Errorf("create callback log directory: %w", err)
+	}
+	f, err := os.OpenFile(l.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("open callback log: %w", err)
+	}
+	defer f.Close()
+	_, err = f.Write(append(line, '\n'))
+	return err
+}
+
+// Read returns the interactions matching f, oldest first, and how many
+// matched before Limit was applied. A missing log reads as empty.
+func (l *Log) Read(f Filter) ([]Interaction, int, error) {
+	file, err := os.Open(l.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, 0, nil
+	}
+	if err != nil {
+		return nil, 0, fmt.Errorf("open callback log: %w", err)
+	}
+	defer file.Close()
+
+	var out []Interaction
+	sc := bufio.NewScanner(file)
+	sc.Buffer(make([]byte, 64*1024), 4<<20)
+	for sc.Scan() {
+		var in Interaction
+		// A line cut short by a concurrent write is skipped, not fatal.
+		if json.Unmarshal(sc.Bytes(), &in) != nil || !f.match(in) {
+			continue
+		}
+		out = append(out, in)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, 0, fmt.Errorf("read callback log: %w", err)
+	}
+	total := len(out)
+	if f.Limit > 0 && total > f.Limit {
+		out = out[total-f.Limit:]
+	}
+	return out, total, nil
+}
@@ -0,0 +1,59 @@
+package callback
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLog_AppendAndRead(t *testing.T) {
+	log := Open(filepath.Join(t.TempDir(), "sub", "callbacks.jsonl"))
+
+	if got, total, err := log.Read(Filter{}); err != nil || total != 0 || len(got) != 0 {
+		t.Fatalf("missing log: got %v, total %d, err %v", got, total, err)
+	}
+
+	old := time.Now().Add(-time.Hour).UTC()
+	entries := []*Interaction{
+		{Protocol: "dns", Host: "abc123-00.oob.example", Summary: "A", Time: old},
+		{Protocol: "http", Host: "oob.example", Summary: "GET /abc123-01", Raw: "GET /abc123-01 HTTP/1.1"},
+		{Protocol: "http", Host: "oob.example", Summary: "POST /other"},
+	}
+	for _, in := range entries {
+		if err := log.Append(in); err != nil {
+			t.Fatal(err)
+		}
+		if in.ID == "" || in.Time.IsZero() {
+			t.Errorf("Append left ID or time unset: %+v", in)
+		}
+	}
+
+	tests := []struct {
+		name   string
+		filter Filter
+		want   []string // summaries
+		total  int
+	}{
+		{"all", Filter{}, []string{"A", "GET /abc123-01", "POST /other"}, 3},
+		{"contains ignores case", Filter{Contains: "ABC123"}, []string{"A", "GET /abc123-01"}, 2},
+		{"protocol", Filter{Protocol: "HTTP"}, []string{"GET /abc123-01", "POST /other"}, 2},
+		{"since", Filter{Since: time.Now().Add(-time.Minute)}, []string{"GET /abc123-01", "POST /other"}, 2},
+		{"limit keeps newest", Filter{Limit: 1}, []string{"POST /other"}, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, total, err := log.Read(tt.filter)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if total != tt.total || len(got) != len(tt.want) {
+				t.Fatalf("got %d of %d, want %d of %d", len(got), total, len(tt.want), tt.total)
+			}
+			for i, in := range got {
+				if in.Summary != tt.want[i] {
+					t.Errorf("got[%d] = %q, want %q", i, in.Summary, tt.want[i])
+				}
+			}
+		})
+	}
+}
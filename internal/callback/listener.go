@@ -0,0 +1,178 @@
+package callback
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"strings"
+)
+
+// maxRecordedBody caps how much of an HTTP request body is logged.
+const maxRecordedBody = 64 * 1024
+
+// HTTPHandler logs every request and answers 200 with an empty body.
+// errs receives logging failures; it may be nil.
+func HTTPHandler(log *Log, errs func(error)) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.Body = io.NopCloser(io.LimitReader(r.Body, maxRecordedBody))
+		raw, err := httputil.DumpRequest(r, true)
+		if err != nil {
+			raw = []byte(fmt.Sprintf("%s %s %s\r\nHost: %s\r\n\r\n", r.Method, r.RequestURI, r.Proto, r.Host))
+		}
+		ip, _, _ := net.SplitHostPort(r.RemoteAddr)
+		err = log.Append(&Interaction{
+			Protocol: "http",
+			SourceIP: ip,
+			Host:     r.Host,
+			Summary:  r.Method + " " + r.RequestURI,
+			Raw:      string(raw),
+		})
+		if err != nil && errs != nil {
+			errs(err)
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+}
+
+// DNS record types and classes used by the listener.
+const (
+	dnsTypeA    = 1
+	dnsClassIN  = 1
+	dnsHeaderSz = 12
+)
+
+var dnsTypeNames = map[uint16]string{
+	1: "A", 2: "NS", 5: "CNAME", 6: "SOA", 12: "PTR", 15: "MX", 16: "TXT", 28: "AAAA", 33: "SRV", 65: "HTTPS", 255: "ANY",
+}
+
+// dnsQuestion is the first question of a query.
+type dnsQuestion struct {
+	name        string
+	qtype       uint16
+	end         int // offset just past the question
+	id          uint16
+	flags       uint16
+	recursionOK bool
+}
+
+// parseDNSQuery reads the header and first question of a DNS query.
+// Compression pointers are not expected in questions and are rejected.
+func parseDNSQuery(msg []byte) (dnsQuestion, error) {
+	var q dnsQuestion
+	if len(msg) < dnsHeaderSz {
+		return q, errors.New("short DNS message")
+	}
+	q.id = binary.BigEndian.Uint16(msg)
+	q.flags = binary.BigEndian.Uint16(msg[2:])
+	if q.flags&0x8000 != 0 || binary.BigEndian.Uint16(msg[4:]) == 0 {
+		return q, errors.New("not a DNS query")
+	}
+	q.recursionOK = q.flags&0x0100 != 0
+	var labels []string
+	i := dnsHeaderSz
+	for {
+		if i >= len(msg) {
+			return q, errors.New("truncated DNS name")
+		}
+		n := int(msg[i])
+		i++
+		if n == 0 {
+			break
+		}
+		if n&0xc0 != 0 || i+n > len(msg) {
+			return q, errors.New("bad DNS label")
+		}
+		labels = append(labels, string(msg[i:i+n]))
+		i += n
+	}
+	if i+4 > len(msg) {
+		return q, errors.New("truncated DNS question")
+	}
+	q.name = strings.Join(labels, ".")
+	q.qtype = binary.BigEndian.Uint16(msg[i:])
+	q.end = i + 4
+	return q, nil
+}
+
+// dnsResponse answers q authoritatively: an A record with answer for A
+// queries when answer is set, otherwise an empty NOERROR reply.
+func dnsResponse(query []byte, q dnsQuestion, answer net.IP) []byte {
+	resp := make([]byte, q.end, q.end+16)
+	copy(resp, query[:q.end])
+	// QR, AA, opcode 0, RD copied, RCODE 0.
+	flags := uint16(0x8400) | q.flags&0x0100
+	binary.BigEndian.PutUint16(resp[2:], flags)
+	binary.BigEndian.PutUint16(resp[4:], 1) // QDCOUNT
+	binary.BigEndian.PutUint16(resp[8:], 0) // NSCOUNT
+	binary.BigEndian.PutUint16(resp[10:], 0)
+	v4 := answer.To4()
+	if q.qtype != dnsTypeA || v4 == nil {
+		binary.BigEndian.PutUint16(resp[6:], 0)
+		return resp
+	}
+	binary.BigEndian.PutUint16(resp[6:], 1) // ANCOUNT
+	resp = append(resp, 0xc0, dnsHeaderSz)  // pointer to the question name
+	resp = binary.BigEndian.AppendUint16(resp, dnsTypeA)
+	resp = binary.BigEndian.AppendUint16(resp, dnsClassIN)
+	resp = binary.BigEndian.AppendUint32(resp, 60) // TTL
+	resp = binary.BigEndian.AppendUint16(resp, 4)
+	return append(resp, v4...)
+}
+
+// dnsDescribe renders a query the way a Collaborator DNS entry reads.
+func dnsDescribe(q dnsQuestion, typ string) string {
+	kind := "iterative"
+	if q.recursionOK {
+		kind = "recursive"
+	}
+	return fmt.Sprintf("%s %s query for %s (id %d)", typ, kind, q.name, q.id)
+}
+
+// ServeDNS answers and logs queries on conn until ctx ends or conn fails.
+func ServeDNS(ctx context.Context, conn net.PacketConn, log *Log, answer net.IP, errs func(error)) error {
+	go func() {
+		<-ctx.Done()
+		conn.Close()
+	}()
+	buf := make([]byte, 1500)
+	for {
+		n, addr, err := conn.ReadFrom(buf)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		msg := buf[:n]
+		q, err := parseDNSQuery(msg)
+		if err != nil {
+			continue
+		}
+		typ := dnsTypeNames[q.qtype]
+		if typ == "" {
+			typ = fmt.Sprintf("TYPE%d", q.qtype)
+		}
+		ip := addr.String()
+		if h, _, err := net.SplitHostPort(ip); err == nil {
+			ip = h
+		}
+		err = log.Append(&Interaction{
+			Protocol: "dns",
+			SourceIP: ip,
+			Host:     strings.ToLower(q.name),
+			Summary:  typ,
+			Raw:      dnsDescribe(q, typ),
+		})
+		if err != nil && errs != nil {
+			errs(err)
+		}
+		if _, err := conn.WriteTo(dnsResponse(msg, q, answer), addr); err != nil && errs != nil {
+			errs(err)
+		}
+	}
+}
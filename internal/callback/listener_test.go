@@ -0,0 +1,126 @@
+package callback
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHTTPHandler(t *testing.T) {
+	log := Open(filepath.Join(t.TempDir(), "callbacks.jsonl"))
+	srv := httptest.NewServer(HTTPHandler(log, func(err error) { t.Error(err) }))
+	defer srv.Close()
+
+	resp, err := http.Post(srv.URL+"/tok-03?x=1", "text/plain", strings.NewReader("secret=42"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("status = %d", resp.StatusCode)
+	}
+
+	got, _, err := log.Read(Filter{})
+	if err != nil || len(got) != 1 {
+		t.Fatalf("got %+v, err %v", got, err)
+	}
+	in := got[0]
+	if in.Protocol != "http" || in.SourceIP != "127.0.0.1" || in.Summary != "POST /tok-03?x=1" {
+		t.Errorf("interaction = %+v", in)
+	}
+	if !strings.Contains(in.Raw, "secret=42") || !strings.Contains(in.Raw, "Content-Type: text/plain") {
+		t.Errorf("raw missing body or headers: %q", in.Raw)
+	}
+}
+
+// dnsQuery builds a query for name with the given type and RD set.
+func dnsQuery(id uint16, name string, qtype uint16) []byte {
+	msg := []byte{byte(id >> 8), byte(id), 0x01, 0x00, 0, 1, 0, 0, 0, 0, 0, 0}
+	for _, label := range strings.Split(name, ".") {
+		msg = append(msg, byte(len(label)))
+		msg = append(msg, label...)
+	}
+	return append(msg, 0, byte(qtype>>8), byte(qtype), 0, 1)
+}
+
+func TestParseDNSQuery(t *testing.T) {
+	q, err := parseDNSQuery(dnsQuery(7, "Tok-01.oob.example", 28))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if q.id != 7 || q.name != "Tok-01.oob.example" || q.qtype != 28 || !q.recursionOK {
+		t.Errorf("q = %+v", q)
+	}
+
+	bad := map[string][]byte{
+		"short":     {0, 1, 2},
+		"response":  append([]byte{0, 1, 0x81, 0x80}, dnsQuery(1, "a.b", 1)[4:]...),
+		"truncated": dnsQuery(1, "a.b", 1)[:15],
+		"pointer":   append(dnsQuery(1, "a", 1)[:12], 0xc0, 0x0c, 0, 0, 1, 0, 1),
+	}
+	for name, msg := range bad {
+		if _, err := parseDNSQuery(msg); err == nil {
+			t.Errorf("%s: parsed without error", name)
+		}
+	}
+}
+
+func TestServeDNS(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	log := Open(filepath.Join(t.TempDir(), "callbacks.jsonl"))
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- ServeDNS(ctx, conn, log, net.ParseIP("203.0.113.9"), func(err error) { t.Error(err) }) }()
+
+	client, err := net.Dial("udp", conn.LocalAddr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+	client.SetDeadline(time.Now().Add(5 * time.Second))
+
+	exchange := func(qtype uint16) []byte {
+		t.Helper()
+		if _, err := client.Write(dnsQuery(42, "tok-02.oob.example", qtype)); err != nil {
+			t.Fatal(err)
+		}
+		buf := make([]byte, 512)
+		n, err := client.Read(buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return buf[:n]
+	}
+
+	resp := exchange(dnsTypeA)
+	if resp[0] != 0 || resp[1] != 42 || resp[2]&0x80 == 0 || resp[7] != 1 {
+		t.Fatalf("A response header = % x", resp[:12])
+	}
+	if ip := net.IP(resp[len(resp)-4:]); !ip.Equal(net.ParseIP("203.0.113.9")) {
+		t.Errorf("answer = %v", ip)
+	}
+	if resp := exchange(16); resp[7] != 0 {
+		t.Errorf("TXT query got %d answers", resp[7])
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("ServeDNS = %v", err)
+	}
+
+	got, _, err := log.Read(Filter{Protocol: "dns"})
+	if err != nil || len(got) != 2 {
+		t.Fatalf("got %+v, err %v", got, err)
+	}
+	if got[0].Host != "tok-02.oob.example" || got[0].Summary != "A" || got[1].Summary != "TXT" || got[0].SourceIP != "127.0.0.1" {
+		t.Errorf("interactions = %+v", got)
+	}
+}
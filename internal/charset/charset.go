@@ -0,0 +1,223 @@
+// Package charset detects the character encoding of HTTP bodies and decodes
+// the common non-UTF-8 ones (ISO-8859-1, windows-1252, UTF-16, Shift_JIS,
+// and GBK) to UTF-8. The double-byte tables are generated from Python's
+// codecs by gen_tables.py rather than pulling in golang.org/x/text.
+package charset
+
+import (
+	_ "embed"
+	"encoding/binary"
+	"mime"
+	"regexp"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// Canonical charset names returned by Detect.
+const (
+	UTF8        = "utf-8"
+	Windows1252 = "windows-1252"
+	ISO88591    = "iso-8859-1"
+	UTF16LE     = "utf-16le"
+	UTF16BE     = "utf-16be"
+	ShiftJIS    = "shift_jis"
+	GBK         = "gbk"
+)
+
+// aliases maps the labels servers use to canonical names.
+var aliases = map[string]string{
+	"utf-8": UTF8, "utf8": UTF8, "unicode-1-1-utf-8": UTF8,
+	"iso-8859-1": ISO88591, "iso8859-1": ISO88591, "iso_8859-1": ISO88591, "latin1": ISO88591, "l1": ISO88591,
+	"windows-1252": Windows1252, "cp1252": Windows1252, "x-cp1252": Windows1252, "us-ascii": Windows1252, "ascii": Windows1252,
+	"utf-16": UTF16LE, "utf-16le": UTF16LE, "utf-16be": UTF16BE,
+	"shift_jis": ShiftJIS, "shift-jis": ShiftJIS, "sjis": ShiftJIS, "x-sjis": ShiftJIS, "ms_kanji": ShiftJIS,
+	"windows-31j": ShiftJIS, "cp932": ShiftJIS,
+	"gbk": GBK, "gb2312": GBK, "gb_2312-80": GBK, "cp936": GBK, "windows-936": GBK, "x-gbk": GBK, "euc-cn": GBK,
+	"gb18030": GBK,
+}
+
+var (
+	metaCharset = regexp.MustCompile(`(?i)<meta[^>]+charset\s*=\s*["']?\s*([\w.:-]+)`)
+	xmlEncoding = regexp.MustCompile(`^\s*<\?xml[^>]*encoding\s*=\s*["']([\w.:-]+)`)
+)
+
+// PrescanBytes is how far into a body Detect looks for a meta tag, as
+// browsers do.
+const PrescanBytes = 1024
+
+// Detect returns the charset of body: from a byte order mark, the charset
+// parameter of contentType, an HTML meta tag, or an XML declaration, in that
+// order. Known labels come back canonical, unknown ones lowercased; "" means
+// nothing declared one.
+func Detect(contentType, body string) string {
+	switch {
+	case strings.HasPrefix(body, "\xEF\xBB\xBF"):
+		return UTF8
+	case strings.HasPrefix(body, "\xFF\xFE"):
+		return UTF16LE
+	case strings.HasPrefix(body, "\xFE\xFF"):
+		return UTF16BE
+	}
+	if _, params, err := mime.ParseMediaType(contentType); err == nil && params["charset"] != "" {
+		return canonical(params["charset"])
+	}
+	head := body[:min(len(body), PrescanBytes)]
+	if m := xmlEncoding.FindStringSubmatch(head); m != nil {
+		return canonical(m[1])
+	}
+	if m := metaCharset.FindStringSubmatch(head); m != nil {
+		return canonical(m[1])
+	}
+	return ""
+}
+
+func canonical(label string) string {
+	label = strings.ToLower(strings.Trim(strings.TrimSpace(label), `"'`))
+	if name, ok := aliases[label]; ok {
+		return name
+	}
+	return label
+}
+
+// Decode returns body, in the named charset, as UTF-8. Bytes that aren't
+// valid in the charset become U+FFFD. It returns false for charsets it
+// can't decode.
+func Decode(name, body string) (string, bool) {
+	switch canonical(name) {
+	case UTF8:
+		return strings.TrimPrefix(body, "\xEF\xBB\xBF"), true
+	case ISO88591, Windows1252:
+		// Browsers read ISO-8859-1 as windows-1252, and servers labeling
+		// cp1252 text latin1 is common enough to follow them.
+		return decodeWindows1252(body), true
+	case UTF16LE:
+		return decodeUTF16(strings.TrimPrefix(body, "\xFF\xFE"), binary.LittleEndian), true
+	case UTF16BE:
+		return decodeUTF16(strings.TrimPrefix(body, "\xFE\xFF"), binary.BigEndian), true
+	case ShiftJIS:
+		return decodeShiftJIS(body), true
+	case GBK:
+		return decodeGBK(body), true
+	}
+	return "", false
+}
+
+// windows1252 maps 0x80-0x9F; the other bytes are their own code points.
+var windows1252 = [32]rune{
+	'€', '\u0081', '‚', 'ƒ', '„', '…', '†', '‡', 'ˆ', '‰', 'Š', '‹', 'Œ', '\u008D', 'Ž', '\u008F',
+	'\u0090', '‘', '’', '“', '”', '•', '–', '—', '˜', '™', 'š', '›', 'œ', '\u009D', 'ž', 'Ÿ',
+}
+
+func decodeWindows1252(body string) string {
+	var sb strings.Builder
+	sb.Grow(len(body))
+	for i := 0; i < len(body); i++ {
+		c := body[i]
+		switch {
+		case c < 0x80:
+			sb.WriteByte(c)
+		case c < 0xA0:
+			sb.WriteRune(windows1252[c-0x80])
+		default:
+			sb.WriteRune(rune(c))
+		}
+	}
+	return sb.String()
+}
+
+func decodeUTF16(body string, order binary.ByteOrder) string {
+	units := make([]uint16, len(body)/2)
+	for i := range units {
+		units[i] = order.Uint16([]byte(body[2*i:]))
+	}
+	s := string(utf16.Decode(units))
+	if len(body)%2 == 1 {
+		s += string(utf8.RuneError)
+	}
+	return s
+}
+
+var (
+	//go:embed shift_jis.bin
+	shiftJISTable string
+	//go:embed gbk.bin
+	gbkTable string
+)
+
+// Table layout, see gen_tables.py.
+const (
+	firstLead, firstTrail, lastTrail = 0x81, 0x40, 0xFE
+	trailsPerLead                    = lastTrail - firstTrail + 1
+)
+
+// lookup returns the code point of a double-byte pair, or 0.
+func lookup(table string, lead, trail byte) rune {
+	if lead < firstLead || lead == 0xFF || trail < firstTrail || trail > lastTrail {
+		return 0
+	}
+	i := 2 * (int(lead-firstLead)*trailsPerLead + int(trail-firstTrail))
+	return rune(binary.LittleEndian.Uint16([]byte(table[i : i+2])))
+}
+
+// decodeDoubleByte decodes a charset of ASCII bytes and double-byte pairs
+// from table. single handles the other lone bytes, returning false for a
+// lead byte. An invalid pair yields U+FFFD, and its trail byte is decoded
+// again when it's ASCII, so one bad lead doesn't eat a delimiter.
+func decodeDoubleByte(body, table string, single func(c byte) (rune, bool)) string {
+	var sb strings.Builder
+	sb.Grow(len(body) * 3 / 2)
+	for i := 0; i < len(body); i++ {
+		c := body[i]
+		if c < 0x80 {
+			sb.WriteByte(c)
+			continue
+		}
+		if r, ok := single(c); ok {
+			sb.WriteRune(r)
+			continue
+		}
+		if i+1 < len(body) {
+			if r := lookup(table, c, body[i+1]); r != 0 {
+				sb.WriteRune(r)
+				i++
+				continue
+			}
+			if body[i+1] >= 0x80 {
+				i++
+			}
+		}
+		sb.WriteRune(utf8.RuneError)
+	}
+	return sb.String()
+}
+
+// decodeShiftJIS decodes Windows-31J, the Shift_JIS browsers use: single
+// bytes 0xA1-0xDF are halfwidth katakana.
+func decodeShiftJIS(body string) string {
+	return decodeDoubleByte(body, shiftJISTable, func(c byte) (rune, bool) {
+		switch {
+		case c == 0x80:
+			return 0x80, true
+		case c >= 0xA1 && c <= 0xDF:
+			return 0xFF61 + rune(c-0xA1), true
+		case c == 0xA0 || c >= 0xFD:
+			return utf8.RuneError, true
+		}
+		return 0, false
+	})
+}
+
+// decodeGBK decodes GBK, which covers GB2312. GB18030's four-byte sequences
+// aren't in the table: each comes out as U+FFFD around its two ASCII digits.
+func decodeGBK(body string) string {
+	return decodeDoubleByte(body, gbkTable, func(c byte) (rune, bool) {
+		switch c {
+		case 0x80:
+			return '€', true
+		case 0xFF:
+			return utf8.RuneError, true
+		}
+		return 0, false
+	})
+}
@@ -0,0 +1,46 @@
+package charset
+
+import "testing"
+
+func TestDetect(t *testing.T) {
+	for _, tc := range []struct {
+		contentType, body, want string
+	}{
+		{"text/html; charset=Shift_JIS", "<html>", ShiftJIS},
+		{`text/html; charset="windows-31j"`, "", ShiftJIS},
+		{"text/html;charset=GB2312", "", GBK},
+		{"text/plain; charset=latin1", "", ISO88591},
+		{"text/html", `<html><head><meta charset="gbk"></head>`, GBK},
+		{"text/html", `<meta http-equiv="Content-Type" content="text/html; charset=EUC-KR">`, "euc-kr"},
+		{"application/xml", `<?xml version="1.0" encoding="ISO-8859-1"?><a/>`, ISO88591},
+		{"text/html; charset=iso-8859-1", "\xEF\xBB\xBFhi", UTF8},
+		{"text/plain", "\xFF\xFEh\x00", UTF16LE},
+		{"text/html", "<html>no declaration</html>", ""},
+	} {
+		if got := Detect(tc.contentType, tc.body); got != tc.want {
+			t.Errorf("Detect(%q, %q) = %q, want %q", tc.contentType, tc.body, got, tc.want)
+		}
+	}
+}
+
+func TestDecode(t *testing.T) {
+	for _, tc := range []struct {
+		charset, body, want string
+	}{
+		{ShiftJIS, "\x82\xb1\x82\xf1\x82\xc9\x82\xbf\x82\xcd \xb1\xb2\xb3 \x95\\", "こんにちは ｱｲｳ 表"},
+		{"sjis", "<p>\x82</p>", "<p>�</p>"},
+		{GBK, "\xc4\xe3\xba\xc3\xa3\xac\xca\xc0\xbd\xe7 \x80", "你好，世界 €"},
+		{ISO88591, "caf\xe9 \x93q\x94", "café “q”"},
+		{UTF16BE, "\xFE\xFF\x00h\x00i", "hi"},
+		{UTF16LE, "h\x00i\x00!", "hi�"},
+		{UTF8, "\xEF\xBB\xBFok", "ok"},
+	} {
+		got, ok := Decode(tc.charset, tc.body)
+		if !ok || got != tc.want {
+			t.Errorf("Decode(%s, %q) = %q, %v, want %q", tc.charset, tc.body, got, ok, tc.want)
+		}
+	}
+	if _, ok := Decode("euc-kr", "x"); ok {
+		t.Error("euc-kr decoded")
+	}
+}
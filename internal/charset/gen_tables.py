@@ -0,0 +1,30 @@
+#!/usr/bin/env python3
+"""Generates the double-byte tables embedded by charset.go from Python's codecs.
+
+Each table holds one little-endian uint16 code point per lead byte 0x81-0xFE
+and trail byte 0x40-0xFE, 0 where the pair is undefined. Run from this
+directory: python3 gen_tables.py
+"""
+
+import struct
+
+LEADS = range(0x81, 0xFF)
+TRAILS = range(0x40, 0xFF)
+
+
+def table(codec):
+    out = bytearray()
+    for lead in LEADS:
+        for trail in TRAILS:
+            try:
+                ch = bytes([lead, trail]).decode(codec)
+            except UnicodeDecodeError:
+                ch = ""
+            cp = ord(ch) if len(ch) == 1 and ord(ch) <= 0xFFFF else 0
+            out += struct.pack("<H", cp)
+    return bytes(out)
+
+
+for codec, name in (("cp932", "shift_jis.bin"), ("gbk", "gbk.bin")):
+    with open(name, "wb") as f:
+        f.write(table(codec))
@@ -0,0 +1,747 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// DefaultProfile is the header profile applied when a tool call doesn't name one.
+const DefaultProfile = "default"
+
+// Config is the on-disk server configuration (JSON).
+// Every section is optional; a zero Config means built-in defaults everywhere.
+type Config struct {
+	// Instances maps a name to an additional Burp MCP SSE endpoint.
+	// Tools select one with their "instance" parameter.
+	Instances map[string]string `json:"instances,omitempty"`
+
+	// HeaderProfiles maps a profile name to header rules applied to every
+	// outbound request. The "default" profile is used when none is named.
+	HeaderProfiles map[string]HeaderRules `json:"headerProfiles,omitempty"`
+
+	// AuthProfiles maps a profile name to credentials that traffic tools
+	// inject at send time when a call names it in authProfile.
+	AuthProfiles map[string]AuthProfile `json:"authProfiles,omitempty"`
+
+	// DirectTLS configures TLS for direct (non-Burp) connections such as races.
+	// Per-call tlsConfig options override these field by field.
+	DirectTLS TLSOptions `json:"directTLS,omitempty"`
+
+	// Store is the path of the local engagement store (findings, retests).
+	// Defaults to store.json next to the default config file.
+	Store string `json:"store,omitempty"`
+
+	// Retry overrides the retry policy for transient Burp call failures.
+	Retry *RetryConfig `json:"retry,omitempty"`
+
+	// RateLimit caps outbound request rates from traffic-generating tools.
+	RateLimit RateLimits `json:"rateLimit,omitempty"`
+
+	// Concurrency caps in-flight requests per target host.
+	Concurrency ConcurrencyLimits `json:"concurrency,omitempty"`
+
+	// BodyLimits sets default response body limits for calls that don't pass bodyLimit.
+	BodyLimits BodyLimits `json:"bodyLimits,omitempty"`
+
+	// Scope lists the targets traffic tools may reach: hostnames, subdomain
+	// wildcards ("*.example.com"), IP addresses, or CIDRs. Empty allows any target.
+	Scope []string `json:"scope,omitempty"`
+
+	// DryRun makes traffic tools return the requests they would send instead
+	// of sending them. Tools that need live responses refuse to run.
+	DryRun bool `json:"dryRun,omitempty"`
+
+	// Approval makes the listed tools wait for a human to approve each call.
+	Approval *ApprovalConfig `json:"approval,omitempty"`
+
+	// MaxOutputBytes caps the serialized size of every tool result. Larger
+	// results have their longest strings cut to fit. Zero means no cap.
+	MaxOutputBytes int `json:"maxOutputBytes,omitempty"`
+
+	// SchemaVersion pins the shape of tool outputs to an older version, so
+	// agent prompts written against it keep working after an upgrade. Zero
+	// means the current version. Clients can also pin single calls with
+	// _meta.schemaVersion.
+	SchemaVersion int `json:"schemaVersion,omitempty"`
+
+	// MetricsAddr is the local address ("127.0.0.1:9464") where the serve
+	// command publishes metrics at /metrics, and where the stats command
+	// reads them. Empty means no metrics endpoint.
+	MetricsAddr string `json:"metricsAddr,omitempty"`
+
+	// Listen configures the out-of-band callback listeners of the listen command.
+	Listen *ListenConfig `json:"listen,omitempty"`
+
+	// Auth maps a target host ("intranet.corp", or "*.corp" for its
+	// subdomains) to HTTP authentication used by direct-mode requests.
+	// Per-call auth options override these field by field.
+	Auth map[string]AuthOptions `json:"auth,omitempty"`
+
+	// Nuclei configures the nuclei binary run by burp_run_nuclei.
+	Nuclei *NucleiConfig `json:"nuclei,omitempty"`
+
+	// Sqlmap configures the sqlmap handoff of burp_export_sqlmap.
+	Sqlmap *SqlmapConfig `json:"sqlmap,omitempty"`
+
+	// PassiveChecks configures the rules burp_run_passive_checks applies.
+	PassiveChecks *PassiveChecksConfig `json:"passiveChecks,omitempty"`
+
+	// Secrets configures burp_scan_secrets.
+	Secrets *SecretsConfig `json:"secrets,omitempty"`
+}
+
+// SecretsConfig configures burp_scan_secrets.
+type SecretsConfig struct {
+	// Allowlist holds regular expressions for known or test values; a
+	// secret any of them matches is skipped on every scan.
+	Allowlist []string `json:"allowlist,omitempty"`
+}
+
+// PassiveChecksConfig configures burp_run_passive_checks.
+type PassiveChecksConfig struct {
+	// Rules is a YAML file of extra rules, read on every run so edits apply
+	// without a restart.
+	Rules string `json:"rules,omitempty"`
+	// NoDefaults drops the built-in rules, leaving only those in Rules.
+	NoDefaults bool `json:"noDefaults,omitempty"`
+}
+
+// SqlmapConfig configures burp_export_sqlmap.
+type SqlmapConfig struct {
+	// Binary is the sqlmap executable, a path or a name looked up in PATH
+	// (default "sqlmap").
+	Binary string `json:"binary,omitempty"`
+	// Args are extra arguments added to every suggested command.
+	Args []string `json:"args,omitempty"`
+	// Dir holds request files and sqlmap's output; defaults to "sqlmap"
+	// next to the store.
+	Dir string `json:"dir,omitempty"`
+	// TimeoutSeconds caps a launched run (default 3600).
+	TimeoutSeconds int `json:"timeoutSeconds,omitempty"`
+}
+
+// NucleiConfig configures burp_run_nuclei.
+type NucleiConfig struct {
+	// Binary is the nuclei executable, a path or a name looked up in PATH
+	// (default "nuclei").
+	Binary string `json:"binary,omitempty"`
+	// Args are extra arguments passed on every run, e.g. a custom
+	// templates directory with "-ud".
+	Args []string `json:"args,omitempty"`
+	// TimeoutSeconds caps a run when the call doesn't set its own (default 600).
+	TimeoutSeconds int `json:"timeoutSeconds,omitempty"`
+}
+
+// AuthTypes are the supported HTTP authentication schemes.
+var AuthTypes = []string{"basic", "digest", "ntlm", "negotiate"}
+
+// AuthOptions configures HTTP authentication for direct requests.
+type AuthOptions struct {
+	Type        string `json:"type,omitempty" jsonschema:"Scheme: basic, digest, ntlm, or negotiate (NTLM inside Negotiate; Kerberos is not supported)"`
+	Username    string `json:"username,omitempty" jsonschema:"User name; DOMAIN\\user and user@domain set the NTLM domain too"`
+	Password    string `json:"password,omitempty" jsonschema:"Password"`
+	PasswordEnv string `json:"passwordEnv,omitempty" jsonschema:"Environment variable holding the password, instead of password"`
+	Domain      string `json:"domain,omitempty" jsonschema:"NTLM domain (default: from username)"`
+	Workstation string `json:"workstation,omitempty" jsonschema:"NTLM workstation name (default: none)"`
+}
+
+// Merge returns o with every non-empty field of override applied on top.
+func (o AuthOptions) Merge(override *AuthOptions) AuthOptions {
+	if override == nil {
+		return o
+	}
+	if override.Type != "" {
+		o.Type = override.Type
+	}
+	if override.Username != "" {
+		o.Username = override.Username
+	}
+	if override.Password != "" || override.PasswordEnv != "" {
+		o.Password, o.PasswordEnv = override.Password, override.PasswordEnv
+	}
+	if override.Domain != "" {
+		o.Domain = override.Domain
+	}
+	if override.Workstation != "" {
+		o.Workstation = override.Workstation
+	}
+	return o
+}
+
+// Secret returns the password, read from PasswordEnv when that is set.
+func (o AuthOptions) Secret() string {
+	if o.PasswordEnv != "" {
+		return os.Getenv(o.PasswordEnv)
+	}
+	return o.Password
+}
+
+// Validate checks the scheme and that a user name is set.
+func (o AuthOptions) Validate() error {
+	if !slices.Contains(AuthTypes, strings.ToLower(o.Type)) {
+		return fmt.Errorf("type %q: want one of %s", o.Type, strings.Join(AuthTypes, ", "))
+	}
+	if o.Username == "" {
+		return fmt.Errorf("username is required")
+	}
+	return nil
+}
+
+// AuthFor returns the configured auth for host: an exact entry, else the
+// longest matching "*.domain" entry, else nil.
+func (c *Config) AuthFor(host string) *AuthOptions {
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	var best string
+	for pattern := range c.Auth {
+		p := strings.ToLower(pattern)
+		switch {
+		case p == host:
+			a := c.Auth[pattern]
+			return &a
+		case strings.HasPrefix(p, "*.") && strings.HasSuffix(host, p[1:]) && len(p) > len(best):
+			best = pattern
+		}
+	}
+	if best == "" {
+		return nil
+	}
+	a := c.Auth[best]
+	return &a
+}
+
+// ListenConfig configures the callback listeners. Flags of the listen
+// command override these.
+type ListenConfig struct {
+	// HTTP is the HTTP listener address, e.g. ":8080".
+	HTTP string `json:"http,omitempty"`
+	// DNS is the UDP address of the DNS listener, e.g. ":53". Empty disables it.
+	DNS string `json:"dns,omitempty"`
+	// DNSAnswer is the IPv4 address returned for A queries. Without it
+	// queries get an empty answer.
+	DNSAnswer string `json:"dnsAnswer,omitempty"`
+	// Log is the interaction log shared with burp_get_callbacks.
+	// Defaults to callbacks.jsonl next to the store.
+	Log string `json:"log,omitempty"`
+}
+
+// ApprovalConfig configures the human approval gate.
+type ApprovalConfig struct {
+	// Tools lists tool names that need approval. "dangerous" stands for
+	// every tool the server marks dangerous (race, Intruder, credential
+	// tests, IDOR sweeps).
+	Tools []string `json:"tools"`
+	// TimeoutSeconds is how long a call waits for a decision (default 300).
+	TimeoutSeconds int `json:"timeoutSeconds,omitempty"`
+	// Dir holds pending approvals; defaults to "approvals" next to the store.
+	Dir string `json:"dir,omitempty"`
+}
+
+// BodyLimits configures default response body limits in bytes. A limit of 0
+// omits the body and -1 returns it in full. ContentTypes takes precedence
+// over Tools, which takes precedence over Default.
+type BodyLimits struct {
+	// Default applies to every tool without a more specific limit.
+	Default *int `json:"default,omitempty"`
+	// Tools sets limits per tool name (e.g. burp_race_request).
+	Tools map[string]int `json:"tools,omitempty"`
+	// ContentTypes sets limits per response media type. Keys are exact types
+	// ("application/json"), wildcards ("image/*"), or "binary" for any type
+	// that isn't text, JSON, XML, JavaScript, or form data.
+	ContentTypes map[string]int `json:"contentTypes,omitempty"`
+}
+
+// Limit returns the body limit for a response of contentType returned to
+// tool, or fallback when nothing is configured.
+func (b BodyLimits) Limit(tool, contentType string, fallback int) int {
+	if len(b.ContentTypes) > 0 && contentType != "" {
+		mt := strings.ToLower(strings.TrimSpace(strings.SplitN(contentType, ";", 2)[0]))
+		if l, ok := b.ContentTypes[mt]; ok {
+			return l
+		}
+		if i := strings.IndexByte(mt, '/'); i > 0 {
+			if l, ok := b.ContentTypes[mt[:i]+"/*"]; ok {
+				return l
+			}
+		}
+		if l, ok := b.ContentTypes["binary"]; ok && !isTextMediaType(mt) {
+			return l
+		}
+	}
+	if l, ok := b.Tools[tool]; ok {
+		return l
+	}
+	if b.Default != nil {
+		return *b.Default
+	}
+	return fallback
+}
+
+// isTextMediaType reports whether a lowercase media type carries readable text.
+func isTextMediaType(mt string) bool {
+	if strings.HasPrefix(mt, "text/") || mt == "application/x-www-form-urlencoded" {
+		return true
+	}
+	for _, s := range []string{"json", "xml", "javascript", "ecmascript"} {
+		if strings.Contains(mt, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// RateLimits configures token-bucket limits. A request must fit every limit
+// that applies to it: global, its target host, and the tool that sent it.
+type RateLimits struct {
+	// Global limits all outbound requests together.
+	Global *RateLimit `json:"global,omitempty"`
+	// PerHost applies to each target host separately, unless Hosts names it.
+	PerHost *RateLimit `json:"perHost,omitempty"`
+	// Hosts sets limits for specific hosts (e.g. production), overriding PerHost.
+	Hosts map[string]RateLimit `json:"hosts,omitempty"`
+	// Tools sets limits per tool name (e.g. burp_race_request).
+	Tools map[string]RateLimit `json:"tools,omitempty"`
+}
+
+// RateLimit is a token bucket: RPS tokens per second, holding at most Burst.
+// Burst defaults to RPS rounded up (minimum 1).
+type RateLimit struct {
+	RPS   float64 `json:"rps"`
+	Burst int     `json:"burst,omitempty"`
+}
+
+// ConcurrencyLimits caps how many requests run against one target host at
+// once. Requests over the cap wait in a queue of at most MaxQueue; when the
+// queue is full they fail instead of waiting.
+type ConcurrencyLimits struct {
+	// PerHost is the cap for each host unless Hosts names it (0 = unlimited).
+	PerHost int `json:"perHost,omitempty"`
+	// Hosts sets caps for specific hosts, overriding PerHost.
+	Hosts map[string]int `json:"hosts,omitempty"`
+	// MaxQueue is how many requests may wait per host (default 50).
+	MaxQueue int `json:"maxQueue,omitempty"`
+}
+
+// DefaultMaxQueue is the per-host queue length when MaxQueue is unset.
+const DefaultMaxQueue = 50
+
+// Limit returns the cap for host, or 0 for none.
+func (c ConcurrencyLimits) Limit(host string) int {
+	if n, ok := c.Hosts[strings.ToLower(host)]; ok {
+		return n
+	}
+	return c.PerHost
+}
+
+func (c ConcurrencyLimits) validate() error {
+	if c.PerHost < 0 || c.MaxQueue < 0 {
+		return fmt.Errorf("concurrency: values must not be negative")
+	}
+	for host, n := range c.Hosts {
+		if n < 0 {
+			return fmt.Errorf("concurrency.hosts.%s: must not be negative", host)
+		}
+	}
+	return nil
+}
+
+// RetryConfig configures retries of failed Burp tool calls. Omitted fields
+// keep the defaults; RetryOn lists failure classes: timeout, traffic_timeout, connection, bad_gateway.
+type RetryConfig struct {
+	MaxRetries   *int     `json:"maxRetries,omitempty"`
+	BackoffMs    int      `json:"backoffMs,omitempty"`
+	MaxBackoffMs int      `json:"maxBackoffMs,omitempty"`
+	RetryOn      []string `json:"retryOn,omitempty"`
+}
+
+// TLSOptions configures TLS for direct connections.
+// Certificate fields accept a file path or inline PEM.
+type TLSOptions struct {
+	ClientCert string   `json:"clientCert,omitempty" jsonschema:"Client certificate (PEM file path or inline PEM) for mutual TLS"`
+	ClientKey  string   `json:"clientKey,omitempty" jsonschema:"Client private key (PEM file path or inline PEM)"`
+	CABundle   string   `json:"caBundle,omitempty" jsonschema:"CA bundle (PEM file path or inline PEM); enables server certificate verification"`
+	MinVersion string   `json:"minVersion,omitempty" jsonschema:"Minimum TLS version: 1.0, 1.1, 1.2, or 1.3"`
+	MaxVersion string   `json:"maxVersion,omitempty" jsonschema:"Maximum TLS version: 1.0, 1.1, 1.2, or 1.3"`
+	Ciphers    []string `json:"ciphers,omitempty" jsonschema:"Allowed cipher suites by IANA name (TLS 1.0-1.2 only)"`
+}
+
+// Merge returns o with every non-empty field of override applied on top.
+func (o TLSOptions) Merge(override *TLSOptions) TLSOptions {
+	if override == nil {
+		return o
+	}
+	if override.ClientCert != "" {
+		o.ClientCert = override.ClientCert
+	}
+	if override.ClientKey != "" {
+		o.ClientKey = override.ClientKey
+	}
+	if override.CABundle != "" {
+		o.CABundle = override.CABundle
+	}
+	if override.MinVersion != "" {
+		o.MinVersion = override.MinVersion
+	}
+	if override.MaxVersion != "" {
+		o.MaxVersion = override.MaxVersion
+	}
+	if len(override.Ciphers) > 0 {
+		o.Ciphers = override.Ciphers
+	}
+	return o
+}
+
+// HeaderRules describes header edits applied to an outbound request.
+// Strip runs first, then Set, then Add.
+type HeaderRules struct {
+	// Add sets headers only if the request doesn't already carry them.
+	Add map[string]string `json:"add,omitempty"`
+	// Set overrides headers, replacing every existing occurrence.
+	Set map[string]string `json:"set,omitempty"`
+	// Strip removes headers by name (case-insensitive).
+	Strip []string `json:"strip,omitempty"`
+}
+
+// AuthProfile is a named set of credentials. String values may reference
+// environment variables as {{env:NAME}}, so secrets stay out of the file;
+// Bearer, Cookies, and Headers may also reference {{var}} extracted by the
+// login steps.
+type AuthProfile struct {
+	// Bearer is sent as "Authorization: Bearer <token>".
+	Bearer string `json:"bearer,omitempty"`
+	// Cookies are merged into the request's Cookie header by name.
+	Cookies map[string]string `json:"cookies,omitempty"`
+	// Headers override request headers of the same name.
+	Headers map[string]string `json:"headers,omitempty"`
+	// Login is a macro run in order before the first request that uses the
+	// profile. Cookies the steps set are kept and sent with later steps and
+	// with every request using the profile.
+	Login []LoginStep `json:"login,omitempty"`
+	// LoginTTLSeconds re-runs the login once its result is this old.
+	// Zero keeps it until the server restarts.
+	LoginTTLSeconds int `json:"loginTtlSeconds,omitempty"`
+	// LoggedOut recognizes a response meaning the session ended. Such a
+	// response makes the tool run the login again and resend once.
+	LoggedOut *LoggedOutRule `json:"loggedOut,omitempty"`
+}
+
+// LoggedOutRule matches a logged-out response. Any field that is set and
+// matches is enough.
+type LoggedOutRule struct {
+	// Status codes that mean logged out, e.g. 401.
+	Status []int `json:"status,omitempty"`
+	// Location is a substring of a redirect target, e.g. "/login".
+	Location string `json:"location,omitempty"`
+	// Regex is matched against the response headers and body.
+	Regex string `json:"regex,omitempty"`
+}
+
+// LoginStep is one request of a login macro.
+type LoginStep struct {
+	// Raw is the request; {{var}} takes values extracted by earlier steps.
+	Raw  string `json:"raw"`
+	Host string `json:"host,omitempty"`
+	Port int    `json:"port,omitempty"`
+	TLS  *bool  `json:"tls,omitempty"`
+	// Extract maps a variable name to a regex matched against the response
+	// headers and body. The first group is the value, or the whole match
+	// without one.
+	Extract map[string]string `json:"extract,omitempty"`
+	// ExtractJSON maps a variable name to a JSONPath into the response
+	// body, e.g. "data.token", "items[0].id", or "$..csrf"; the first
+	// match is the value.
+	ExtractJSON map[string]string `json:"extractJson,omitempty"`
+}
+
+func (p AuthProfile) validate() error {
+	if p.Bearer == "" && len(p.Cookies) == 0 && len(p.Headers) == 0 && len(p.Login) == 0 {
+		return fmt.Errorf("set at least one of bearer, cookies, headers, or login")
+	}
+	if p.LoginTTLSeconds < 0 {
+		return fmt.Errorf("loginTtlSeconds must not be negative")
+	}
+	if r := p.LoggedOut; r != nil {
+		if len(p.Login) == 0 {
+			return fmt.Errorf("loggedOut needs login steps to run again")
+		}
+		if len(r.Status) == 0 && r.Location == "" && r.Regex == "" {
+			return fmt.Errorf("loggedOut: set at least one of status, location, or regex")
+		}
+		if _, err := regexp.Compile(r.Regex); err != nil {
+			return fmt.Errorf("loggedOut.regex: %w", err)
+		}
+	}
+	for h := range p.Headers {
+		if h == "" {
+			return fmt.Errorf("empty header name")
+		}
+	}
+	for i, step := range p.Login {
+		if strings.TrimSpace(step.Raw) == "" {
+			return fmt.Errorf("login[%d]: raw is required", i)
+		}
+		for name, expr := range step.Extract {
+			re, err := regexp.Compile(expr)
+			if err != nil {
+				return fmt.Errorf("login[%d].extract.%s: %w", i, name, err)
+			}
+			if re.NumSubexp() > 1 {
+				return fmt.Errorf("login[%d].extract.%s: use at most one capture group", i, name)
+			}
+		}
+		for name, path := range step.ExtractJSON {
+			if path == "" {
+				return fmt.Errorf("login[%d].extractJson.%s: empty path", i, name)
+			}
+		}
+	}
+	return nil
+}
+
+// DefaultPath returns the default config location (~/.config/burp-mcp-server/config.json).
+func DefaultPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "burp-mcp-server", "config.json")
+}
+
+// StorePath returns the configured store path, or the default location.
+func (c *Config) StorePath() string {
+	if c.Store != "" {
+		return c.Store
+	}
+	if p := DefaultPath(); p != "" {
+		return filepath.Join(filepath.Dir(p), "store.json")
+	}
+	return "burp-mcp-store.json"
+}
+
+// ApprovalDir returns the directory holding pending approvals.
+func (c *Config) ApprovalDir() string {
+	if c.Approval != nil && c.Approval.Dir != "" {
+		return c.Approval.Dir
+	}
+	return filepath.Join(filepath.Dir(c.StorePath()), "approvals")
+}
+
+// CallbackLog returns the path of the callback interaction log.
+func (c *Config) CallbackLog() string {
+	if c.Listen != nil && c.Listen.Log != "" {
+		return c.Listen.Log
+	}
+	return filepath.Join(filepath.Dir(c.StorePath()), "callbacks.jsonl")
+}
+
+// SqlmapDir returns the directory for sqlmap request files and output.
+func (c *Config) SqlmapDir() string {
+	if c.Sqlmap != nil && c.Sqlmap.Dir != "" {
+		return c.Sqlmap.Dir
+	}
+	return filepath.Join(filepath.Dir(c.StorePath()), "sqlmap")
+}
+
+// Load reads and validates the config file at path.
+func Load(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read config: %w", err)
+	}
+	var cfg Config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("parse config %s: %w", path, err)
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config %s: %w", path, err)
+	}
+	return &cfg, nil
+}
+
+// Validate checks the config for values that would fail at request time.
+func (c *Config) Validate() error {
+	for name, url := range c.Instances {
+		if name == "" || name == "default" {
+			return fmt.Errorf("instances: name %q is reserved", name)
+		}
+		if url == "" {
+			return fmt.Errorf("instances.%s: empty endpoint URL", name)
+		}
+	}
+	if r := c.Retry; r != nil && (r.MaxRetries != nil && *r.MaxRetries < 0 || r.BackoffMs < 0 || r.MaxBackoffMs < 0) {
+		return fmt.Errorf("retry: values must not be negative")
+	}
+	if err := c.RateLimit.validate(); err != nil {
+		return err
+	}
+	if err := c.Concurrency.validate(); err != nil {
+		return err
+	}
+	if err := c.BodyLimits.validate(); err != nil {
+		return err
+	}
+	if c.MaxOutputBytes < 0 {
+		return fmt.Errorf("maxOutputBytes: must not be negative")
+	}
+	if c.SchemaVersion < 0 {
+		return fmt.Errorf("schemaVersion: must not be negative")
+	}
+	if a := c.Approval; a != nil && a.TimeoutSeconds < 0 {
+		return fmt.Errorf("approval: timeoutSeconds must not be negative")
+	}
+	if l := c.Listen; l != nil && l.DNSAnswer != "" {
+		if ip := net.ParseIP(l.DNSAnswer); ip == nil || ip.To4() == nil {
+			return fmt.Errorf("listen.dnsAnswer: %q is not an IPv4 address", l.DNSAnswer)
+		}
+	}
+	if n := c.Nuclei; n != nil && n.TimeoutSeconds < 0 {
+		return fmt.Errorf("nuclei: timeoutSeconds must not be negative")
+	}
+	if s := c.Sqlmap; s != nil && s.TimeoutSeconds < 0 {
+		return fmt.Errorf("sqlmap: timeoutSeconds must not be negative")
+	}
+	for host, a := range c.Auth {
+		if host == "" || strings.Contains(host[1:], "*") || strings.HasPrefix(host, "*") && !strings.HasPrefix(host, "*.") {
+			return fmt.Errorf("auth: %q is not a hostname or *.domain", host)
+		}
+		if err := a.Validate(); err != nil {
+			return fmt.Errorf("auth.%s: %w", host, err)
+		}
+	}
+	if sc := c.Secrets; sc != nil {
+		for _, expr := range sc.Allowlist {
+			if _, err := regexp.Compile(expr); err != nil {
+				return fmt.Errorf("secrets.allowlist: %w", err)
+			}
+		}
+	}
+	for _, entry := range c.Scope {
+		if err := ValidateScopeEntry(entry); err != nil {
+			return fmt.Errorf("scope: %w", err)
+		}
+	}
+	for name, p := range c.AuthProfiles {
+		if err := p.validate(); err != nil {
+			return fmt.Errorf("authProfiles.%s: %w", name, err)
+		}
+	}
+	for name, rules := range c.HeaderProfiles {
+		for _, h := range rules.Strip {
+			if h == "" {
+				return fmt.Errorf("headerProfiles.%s: empty header name in strip", name)
+			}
+		}
+		for h := range rules.Set {
+			if h == "" {
+				return fmt.Errorf("headerProfiles.%s: empty header name in set", name)
+			}
+		}
+		for h := range rules.Add {
+			if h == "" {
+				return fmt.Errorf("headerProfiles.%s: empty header name in add", name)
+			}
+		}
+	}
+	return nil
+}
+
+// HeaderProfile returns the named header rules. An empty name selects the
+// default profile, which may be absent (no rules). Unknown explicit names are an error.
+func (c *Config) HeaderProfile(name string) (HeaderRules, error) {
+	if name == "" {
+		return c.HeaderProfiles[DefaultProfile], nil
+	}
+	rules, ok := c.HeaderProfiles[name]
+	if !ok {
+		return HeaderRules{}, fmt.Errorf("unknown header profile %q", name)
+	}
+	return rules, nil
+}
+
+// AuthProfile returns the named auth profile.
+func (c *Config) AuthProfile(name string) (AuthProfile, error) {
+	p, ok := c.AuthProfiles[name]
+	if !ok {
+		return AuthProfile{}, fmt.Errorf("unknown auth profile %q", name)
+	}
+	return p, nil
+}
+
+func (r RateLimits) validate() error {
+	check := func(scope string, l RateLimit) error {
+		if l.RPS <= 0 {
+			return fmt.Errorf("rateLimit.%s: rps must be positive", scope)
+		}
+		if l.Burst < 0 {
+			return fmt.Errorf("rateLimit.%s: burst must not be negative", scope)
+		}
+		return nil
+	}
+	if r.Global != nil {
+		if err := check("global", *r.Global); err != nil {
+			return err
+		}
+	}
+	if r.PerHost != nil {
+		if err := check("perHost", *r.PerHost); err != nil {
+			return err
+		}
+	}
+	for host, l := range r.Hosts {
+		if err := check("hosts."+host, l); err != nil {
+			return err
+		}
+	}
+	for tool, l := range r.Tools {
+		if err := check("tools."+tool, l); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (b BodyLimits) validate() error {
+	if b.Default != nil && *b.Default < -1 {
+		return fmt.Errorf("bodyLimits.default: must be -1 (unlimited) or more")
+	}
+	for tool, l := range b.Tools {
+		if l < -1 {
+			return fmt.Errorf("bodyLimits.tools.%s: must be -1 (unlimited) or more", tool)
+		}
+	}
+	for ct, l := range b.ContentTypes {
+		if l < -1 {
+			return fmt.Errorf("bodyLimits.contentTypes.%s: must be -1 (unlimited) or more", ct)
+		}
+		if ct != strings.ToLower(ct) {
+			return fmt.Errorf("bodyLimits.contentTypes.%s: media types must be lowercase", ct)
+		}
+	}
+	return nil
+}
+
+// ValidateScopeEntry checks one scope entry: a hostname, "*." followed by a
+// hostname, an IP address, or a CIDR.
+func ValidateScopeEntry(entry string) error {
+	switch {
+	case entry == "":
+		return fmt.Errorf("empty entry")
+	case strings.Contains(entry, "/"):
+		if _, _, err := net.ParseCIDR(entry); err != nil {
+			return fmt.Errorf("invalid CIDR %q", entry)
+		}
+	case strings.Contains(strings.TrimPrefix(entry, "*."), "*"):
+		return fmt.Errorf("invalid entry %q: only a leading \"*.\" wildcard is supported", entry)
+	case strings.ContainsAny(entry, " :") && net.ParseIP(entry) == nil:
+		return fmt.Errorf("invalid entry %q: want a hostname, IP, or CIDR without port", entry)
+	}
+	return nil
+}
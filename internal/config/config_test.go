@@ -0,0 +1,288 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoad_HeaderProfiles(t *testing.T) {
+	path := writeConfig(t, `{
+		"headerProfiles": {
+			"default": {"set": {"X-Bug-Bounty": "tester"}, "strip": ["If-None-Match"]},
+			"quiet": {"add": {"User-Agent": "curl/8.0"}}
+		}
+	}`)
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rules, err := cfg.HeaderProfile("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rules.Set["X-Bug-Bounty"] != "tester" || len(rules.Strip) != 1 {
+		t.Errorf("default profile = %+v", rules)
+	}
+	quiet, err := cfg.HeaderProfile("quiet")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if quiet.Add["User-Agent"] != "curl/8.0" {
+		t.Errorf("quiet profile = %+v", quiet)
+	}
+}
+
+func TestLoad_InvalidJSON(t *testing.T) {
+	path := writeConfig(t, `{"headerProfiles": `)
+	if _, err := Load(path); err == nil {
+		t.Error("expected error for invalid JSON")
+	}
+}
+
+func TestLoad_EmptyHeaderName(t *testing.T) {
+	path := writeConfig(t, `{"headerProfiles": {"default": {"strip": [""]}}}`)
+	if _, err := Load(path); err == nil {
+		t.Error("expected validation error for empty header name")
+	}
+}
+
+func TestHeaderProfile_Unknown(t *testing.T) {
+	cfg := &Config{}
+	if _, err := cfg.HeaderProfile("missing"); err == nil {
+		t.Error("expected error for unknown profile")
+	}
+	rules, err := cfg.HeaderProfile("")
+	if err != nil {
+		t.Fatalf("default profile should be optional: %v", err)
+	}
+	if len(rules.Set)+len(rules.Add)+len(rules.Strip) != 0 {
+		t.Errorf("absent default profile should be empty, got %+v", rules)
+	}
+}
+
+func TestLoad_Retry(t *testing.T) {
+	cfg, err := Load(writeConfig(t, `{"retry": {"retryOn": ["timeout", "bad_gateway"]}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Retry.MaxRetries != nil || len(cfg.Retry.RetryOn) != 2 {
+		t.Errorf("retry = %+v", cfg.Retry)
+	}
+	if _, err := Load(writeConfig(t, `{"retry": {"maxRetries": -1}}`)); err == nil {
+		t.Error("expected error for negative maxRetries")
+	}
+}
+
+func TestLoad_RateLimitValidation(t *testing.T) {
+	if _, err := Load(writeConfig(t, `{"rateLimit": {"global": {"rps": 10}, "hosts": {"prod.example": {"rps": 0.5}}}}`)); err != nil {
+		t.Errorf("valid rate limits: %v", err)
+	}
+	if _, err := Load(writeConfig(t, `{"rateLimit": {"tools": {"burp_race_request": {"rps": 0}}}}`)); err == nil {
+		t.Error("expected error for zero rps")
+	}
+}
+
+func TestLoad_Concurrency(t *testing.T) {
+	cfg, err := Load(writeConfig(t, `{"concurrency": {"perHost": 4, "hosts": {"prod.example": 1}}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := cfg.Concurrency.Limit("PROD.example"); got != 1 {
+		t.Errorf("Limit(prod.example) = %d, want 1", got)
+	}
+	if got := cfg.Concurrency.Limit("other.example"); got != 4 {
+		t.Errorf("Limit(other.example) = %d, want 4", got)
+	}
+	if _, err := Load(writeConfig(t, `{"concurrency": {"maxQueue": -1}}`)); err == nil {
+		t.Error("expected error for negative maxQueue")
+	}
+}
+
+func TestBodyLimits_Limit(t *testing.T) {
+	cfg, err := Load(writeConfig(t, `{"bodyLimits": {
+		"default": 4000,
+		"tools": {"burp_race_request": 500},
+		"contentTypes": {"application/json": 8192, "text/*": 2048, "binary": 0}
+	}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	b := cfg.BodyLimits
+	tests := []struct {
+		tool, contentType string
+		want              int
+	}{
+		{"burp_send_request", "application/json; charset=utf-8", 8192},
+		{"burp_race_request", "application/JSON", 8192},
+		{"burp_send_request", "text/html", 2048},
+		{"burp_send_request", "image/png", 0},
+		{"burp_send_request", "application/problem+json", 4000},
+		{"burp_race_request", "", 500},
+		{"burp_get_request", "", 4000},
+	}
+	for _, tt := range tests {
+		if got := b.Limit(tt.tool, tt.contentType, 10000); got != tt.want {
+			t.Errorf("Limit(%q, %q) = %d, want %d", tt.tool, tt.contentType, got, tt.want)
+		}
+	}
+	if got := (BodyLimits{}).Limit("burp_send_request", "text/html", 10000); got != 10000 {
+		t.Errorf("unconfigured limit = %d, want fallback", got)
+	}
+}
+
+func TestLoad_BodyLimitValidation(t *testing.T) {
+	if _, err := Load(writeConfig(t, `{"bodyLimits": {"tools": {"burp_send_request": -2}}}`)); err == nil {
+		t.Error("expected error for limit below -1")
+	}
+	if _, err := Load(writeConfig(t, `{"bodyLimits": {"contentTypes": {"Text/HTML": 100}}}`)); err == nil {
+		t.Error("expected error for uppercase media type")
+	}
+}
+
+func TestLoad_Scope(t *testing.T) {
+	if _, err := Load(writeConfig(t, `{"scope": ["example.com", "*.example.com", "10.0.0.0/8", "::1"]}`)); err != nil {
+		t.Errorf("valid scope: %v", err)
+	}
+	for _, bad := range []string{`""`, `"10.0.0.0/33"`, `"api.*.example.com"`, `"example.com:443"`} {
+		if _, err := Load(writeConfig(t, `{"scope": [`+bad+`]}`)); err == nil {
+			t.Errorf("expected error for scope entry %s", bad)
+		}
+	}
+}
+
+func TestLoad_Listen(t *testing.T) {
+	cfg, err := Load(writeConfig(t, `{"store": "/tmp/eng/store.json", "listen": {"http": ":8080", "dnsAnswer": "203.0.113.9"}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := cfg.CallbackLog(); got != "/tmp/eng/callbacks.jsonl" {
+		t.Errorf("CallbackLog() = %q", got)
+	}
+	if _, err := Load(writeConfig(t, `{"listen": {"dnsAnswer": "::1"}}`)); err == nil {
+		t.Error("expected error for IPv6 dnsAnswer")
+	}
+}
+
+func TestLoad_Auth(t *testing.T) {
+	t.Setenv("CORP_PASS", "s3cret")
+	cfg, err := Load(writeConfig(t, `{"auth": {
+		"*.corp.example": {"type": "ntlm", "username": "CORP\\alice", "passwordEnv": "CORP_PASS"},
+		"*.hr.corp.example": {"type": "negotiate", "username": "bob"},
+		"wiki.corp.example": {"type": "basic", "username": "carol", "password": "x"}
+	}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := map[string]string{
+		"wiki.corp.example":       "carol",
+		"WIKI.corp.example:443":   "carol",
+		"intranet.corp.example":   "CORP\\alice",
+		"payroll.hr.corp.example": "bob",
+		"corp.example":            "",
+		"other.example":           "",
+	}
+	for host, want := range tests {
+		got := cfg.AuthFor(host)
+		if want == "" && got != nil || want != "" && (got == nil || got.Username != want) {
+			t.Errorf("AuthFor(%q) = %+v, want user %q", host, got, want)
+		}
+	}
+	if got := cfg.AuthFor("intranet.corp.example").Secret(); got != "s3cret" {
+		t.Errorf("Secret() = %q", got)
+	}
+	merged := cfg.AuthFor("wiki.corp.example").Merge(&AuthOptions{Type: "digest", PasswordEnv: "CORP_PASS"})
+	if merged.Type != "digest" || merged.Username != "carol" || merged.Secret() != "s3cret" {
+		t.Errorf("Merge = %+v", merged)
+	}
+
+	for _, bad := range []string{
+		`{"auth": {"a.example": {"type": "kerberos", "username": "u"}}}`,
+		`{"auth": {"a.example": {"type": "basic"}}}`,
+		`{"auth": {"a.*.example": {"type": "basic", "username": "u"}}}`,
+	} {
+		if _, err := Load(writeConfig(t, bad)); err == nil {
+			t.Errorf("accepted %s", bad)
+		}
+	}
+}
+
+func TestLoad_AuthProfiles(t *testing.T) {
+	cfg, err := Load(writeConfig(t, `{"authProfiles": {
+		"api": {"bearer": "{{env:API_TOKEN}}"},
+		"app": {
+			"login": [
+				{"raw": "GET /login HTTP/1.1\r\nHost: app\r\n\r\n", "extract": {"csrf": "name=\"csrf\" value=\"([^\"]+)\""}},
+				{"raw": "POST /login HTTP/1.1\r\nHost: app\r\n\r\ncsrf={{csrf}}"}
+			],
+			"headers": {"X-CSRF-Token": "{{csrf}}"},
+			"loginTtlSeconds": 600
+		}
+	}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := cfg.AuthProfile("app")
+	if err != nil || len(p.Login) != 2 || p.LoginTTLSeconds != 600 {
+		t.Errorf("AuthProfile(app) = %+v, %v", p, err)
+	}
+	if _, err := cfg.AuthProfile("missing"); err == nil {
+		t.Error("expected error for unknown profile")
+	}
+
+	for _, bad := range []string{
+		`{"authProfiles": {"empty": {}}}`,
+		`{"authProfiles": {"x": {"login": [{"raw": ""}]}}}`,
+		`{"authProfiles": {"x": {"login": [{"raw": "GET / HTTP/1.1", "extract": {"v": "("}}]}}}`,
+		`{"authProfiles": {"x": {"login": [{"raw": "GET / HTTP/1.1", "extract": {"v": "(a)(b)"}}]}}}`,
+		`{"authProfiles": {"x": {"bearer": "t", "loginTtlSeconds": -1}}}`,
+	} {
+		if _, err := Load(writeConfig(t, bad)); err == nil {
+			t.Errorf("accepted %s", bad)
+		}
+	}
+}
+
+func TestLoad_AuthProfileLoggedOut(t *testing.T) {
+	cfg, err := Load(writeConfig(t, `{"authProfiles": {"app": {
+		"login": [{"raw": "POST /login HTTP/1.1", "extractJson": {"token": "data.token"}}],
+		"bearer": "{{token}}",
+		"loggedOut": {"status": [401], "location": "/login"}
+	}}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r := cfg.AuthProfiles["app"].LoggedOut; r == nil || r.Location != "/login" {
+		t.Errorf("loggedOut = %+v", r)
+	}
+
+	for _, bad := range []string{
+		`{"authProfiles": {"x": {"bearer": "t", "loggedOut": {"status": [401]}}}}`,
+		`{"authProfiles": {"x": {"login": [{"raw": "GET / HTTP/1.1"}], "loggedOut": {}}}}`,
+		`{"authProfiles": {"x": {"login": [{"raw": "GET / HTTP/1.1"}], "loggedOut": {"regex": "("}}}}`,
+		`{"authProfiles": {"x": {"login": [{"raw": "GET / HTTP/1.1", "extractJson": {"v": ""}}]}}}`,
+	} {
+		if _, err := Load(writeConfig(t, bad)); err == nil {
+			t.Errorf("accepted %s", bad)
+		}
+	}
+}
+
+func TestLoad_SecretsAllowlist(t *testing.T) {
+	cfg, err := Load(writeConfig(t, `{"secrets": {"allowlist": ["EXAMPLE$", "^test_"]}}`))
+	if err != nil || len(cfg.Secrets.Allowlist) != 2 {
+		t.Fatalf("cfg = %+v, %v", cfg, err)
+	}
+	if _, err := Load(writeConfig(t, `{"secrets": {"allowlist": ["("]}}`)); err == nil {
+		t.Error("expected error for an invalid allowlist regex")
+	}
+}
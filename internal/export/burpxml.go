@@ -0,0 +1,183 @@
+package export
+
+import (
+	"encoding/base64"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/c0tton-fluff/burp-mcp-server/internal/store"
+)
+
+func init() { Register(burpXMLExporter{}) }
+
+// burpXMLExporter writes Burp's issue report XML, so findings can be loaded by
+// tooling that already parses Burp scanner exports.
+type burpXMLExporter struct{}
+
+func (burpXMLExporter) Name() string        { return "burpxml" }
+func (burpXMLExporter) Description() string { return "Burp Suite issues XML (scanner report format)" }
+func (burpXMLExporter) Extension() string   { return ".xml" }
+
+// burpExtensionIssueType is Burp's type ID for extension-generated issues.
+const burpExtensionIssueType = 134217728
+
+type burpIssues struct {
+	XMLName     xml.Name    `xml:"issues"`
+	BurpVersion string      `xml:"burpVersion,attr"`
+	ExportTime  string      `xml:"exportTime,attr"`
+	Issues      []burpIssue `xml:"issue"`
+}
+
+type burpIssue struct {
+	SerialNumber          string            `xml:"serialNumber"`
+	Type                  int               `xml:"type"`
+	Name                  string            `xml:"name"`
+	Host                  burpHost          `xml:"host"`
+	Path                  string            `xml:"path"`
+	Location              string            `xml:"location"`
+	Severity              string            `xml:"severity"`
+	Confidence            string            `xml:"confidence"`
+	IssueBackground       string            `xml:"issueBackground,omitempty"`
+	RemediationBackground string            `xml:"remediationBackground,omitempty"`
+	IssueDetail           string            `xml:"issueDetail,omitempty"`
+	RemediationDetail     string            `xml:"remediationDetail,omitempty"`
+	RequestResponse       []burpRequestResp `xml:"requestresponse"`
+}
+
+type burpHost struct {
+	IP    string `xml:"ip,attr"`
+	Value string `xml:",chardata"`
+}
+
+type burpRequestResp struct {
+	Request  burpRequest   `xml:"request"`
+	Response *burpResponse `xml:"response,omitempty"`
+}
+
+type burpRequest struct {
+	Method string `xml:"method,attr"`
+	Base64 bool   `xml:"base64,attr"`
+	Value  string `xml:",chardata"`
+}
+
+type burpResponse struct {
+	Base64 bool   `xml:"base64,attr"`
+	Value  string `xml:",chardata"`
+}
+
+func (burpXMLExporter) Export(w io.Writer, findings []store.Finding) error {
+	doc := burpIssues{BurpVersion: "burp-mcp-server", ExportTime: time.Now().UTC().Format(time.RFC1123)}
+	for i, f := range findings {
+		u := findingURL(f)
+		issue := burpIssue{
+			SerialNumber: strconv.Itoa(i + 1),
+			Type:         burpExtensionIssueType,
+			Name:         f.Title,
+			Host:         burpHost{Value: u.Scheme + "://" + u.Host},
+			Path:         u.EscapedPath(),
+			Location:     u.RequestURI(),
+			Severity:     burpSeverity(f.Severity),
+			Confidence:   "Firm",
+			IssueDetail:  f.Description,
+		}
+		if status(f) == store.VerdictVulnerable {
+			issue.Confidence = "Certain"
+		}
+		for _, s := range f.Steps {
+			_, parsed := stepURL(s)
+			issue.RequestResponse = append(issue.RequestResponse, burpRequestResp{Request: burpRequest{
+				Method: parsed.Method,
+				Base64: true,
+				Value:  base64.StdEncoding.EncodeToString([]byte(s.Raw)),
+			}})
+		}
+		doc.Issues = append(doc.Issues, issue)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// burpSeverity maps severity to Burp's High/Medium/Low/Information scale.
+func burpSeverity(severity string) string {
+	switch s := severityTitle(severity); s {
+	case "Critical":
+		return "High"
+	case "Info":
+		return "Information"
+	default:
+		return s
+	}
+}
+
+// ParseBurpXML reads a Burp issue report, as exported from Burp's GUI
+// (Target > Issues > Report issues, XML), into store issues. source is
+// recorded on each issue, usually the report's file name.
+func ParseBurpXML(r io.Reader, source string) ([]store.Issue, error) {
+	var doc burpIssues
+	if err := xml.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("parse Burp issue report: %w", err)
+	}
+	issues := make([]store.Issue, 0, len(doc.Issues))
+	for n, bi := range doc.Issues {
+		issue := store.Issue{
+			SerialNumber:          strings.TrimSpace(bi.SerialNumber),
+			Name:                  strings.TrimSpace(bi.Name),
+			Host:                  strings.TrimSpace(bi.Host.Value),
+			Path:                  bi.Path,
+			Location:              bi.Location,
+			Severity:              bi.Severity,
+			Confidence:            bi.Confidence,
+			IssueBackground:       bi.IssueBackground,
+			RemediationBackground: bi.RemediationBackground,
+			IssueDetail:           bi.IssueDetail,
+			RemediationDetail:     bi.RemediationDetail,
+			Source:                source,
+		}
+		if bi.Type != 0 {
+			issue.Type = strconv.Itoa(bi.Type)
+		}
+		if issue.Name == "" {
+			return nil, fmt.Errorf("issue %d has no name", n+1)
+		}
+		for _, rr := range bi.RequestResponse {
+			var ev store.IssueEvidence
+			var err error
+			if ev.Request, err = burpMessage(rr.Request.Value, rr.Request.Base64); err != nil {
+				return nil, fmt.Errorf("issue %d request: %w", n+1, err)
+			}
+			if rr.Response != nil {
+				if ev.Response, err = burpMessage(rr.Response.Value, rr.Response.Base64); err != nil {
+					return nil, fmt.Errorf("issue %d response: %w", n+1, err)
+				}
+			}
+			if ev != (store.IssueEvidence{}) {
+				issue.Evidence = append(issue.Evidence, ev)
+			}
+		}
+		issues = append(issues, issue)
+	}
+	return issues, nil
+}
+
+// burpMessage decodes a request or response body from the report, which Burp
+// base64-encodes unless the export was made with that option off.
+func burpMessage(value string, isBase64 bool) (string, error) {
+	if !isBase64 {
+		return value, nil
+	}
+	b, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(value), ""))
+	return string(b), err
+}
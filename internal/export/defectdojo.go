@@ -0,0 +1,86 @@
+package export
+
+import (
+	"encoding/json"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/c0tton-fluff/burp-mcp-server/internal/store"
+)
+
+func init() { Register(defectDojoExporter{}) }
+
+// defectDojoExporter writes DefectDojo's "Generic Findings Import" JSON.
+type defectDojoExporter struct{}
+
+func (defectDojoExporter) Name() string        { return "defectdojo" }
+func (defectDojoExporter) Description() string { return "DefectDojo Generic Findings Import JSON" }
+func (defectDojoExporter) Extension() string   { return ".json" }
+
+type dojoFinding struct {
+	Title            string         `json:"title"`
+	Description      string         `json:"description"`
+	Severity         string         `json:"severity"`
+	Date             string         `json:"date"`
+	Active           bool           `json:"active"`
+	Verified         bool           `json:"verified"`
+	Mitigated        string         `json:"mitigated,omitempty"`
+	StaticFinding    bool           `json:"static_finding"`
+	DynamicFinding   bool           `json:"dynamic_finding"`
+	UniqueIDFromTool string         `json:"unique_id_from_tool"`
+	StepsToReproduce string         `json:"steps_to_reproduce"`
+	Endpoints        []dojoEndpoint `json:"endpoints"`
+}
+
+type dojoEndpoint struct {
+	Protocol string `json:"protocol"`
+	Host     string `json:"host"`
+	Port     int    `json:"port,omitempty"`
+	Path     string `json:"path,omitempty"`
+}
+
+func (defectDojoExporter) Export(w io.Writer, findings []store.Finding) error {
+	out := struct {
+		Findings []dojoFinding `json:"findings"`
+	}{Findings: []dojoFinding{}}
+
+	for _, f := range findings {
+		d := dojoFinding{
+			Title:            f.Title,
+			Description:      f.Description,
+			Severity:         severityTitle(f.Severity),
+			Date:             f.CreatedAt.Format(time.DateOnly),
+			Active:           true,
+			DynamicFinding:   true,
+			UniqueIDFromTool: f.ID,
+			Endpoints:        []dojoEndpoint{},
+		}
+		if d.Description == "" {
+			d.Description = f.Title
+		}
+		if n := len(f.Retests); n > 0 {
+			last := f.Retests[n-1]
+			d.Verified = last.Verdict == store.VerdictVulnerable
+			if last.Verdict == store.VerdictFixed {
+				d.Active = false
+				d.Mitigated = last.At.Format(time.DateTime)
+			}
+		}
+
+		var steps []string
+		for i, s := range f.Steps {
+			u, _ := stepURL(s)
+			port, _ := strconv.Atoi(u.Port())
+			d.Endpoints = append(d.Endpoints, dojoEndpoint{Protocol: u.Scheme, Host: u.Hostname(), Port: port, Path: u.Path})
+			steps = append(steps, strconv.Itoa(i+1)+". "+stepLabel(s)+"\n\n"+s.Raw)
+		}
+		d.StepsToReproduce = strings.Join(steps, "\n\n")
+		out.Findings = append(out.Findings, d)
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(out)
+}
@@ -0,0 +1,133 @@
+// Package export renders stored findings in report and interchange formats.
+// Each format is an Exporter registered by name; adding a format is a new
+// file that calls Register from init.
+package export
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/c0tton-fluff/burp-mcp-server/internal/burp"
+	"github.com/c0tton-fluff/burp-mcp-server/internal/store"
+)
+
+// Exporter renders findings in one output format.
+type Exporter interface {
+	// Name is the registry key, e.g. "sarif".
+	Name() string
+	// Description is a one-line summary shown when listing formats.
+	Description() string
+	// Extension is the conventional file extension, including the dot.
+	Extension() string
+	// Export writes findings to w.
+	Export(w io.Writer, findings []store.Finding) error
+}
+
+var (
+	mu        sync.RWMutex
+	exporters = make(map[string]Exporter)
+)
+
+// Register adds an exporter. It panics if the name is already taken,
+// since that is a programming error caught at startup.
+func Register(e Exporter) {
+	mu.Lock()
+	defer mu.Unlock()
+	if _, dup := exporters[e.Name()]; dup {
+		panic("export: duplicate format " + e.Name())
+	}
+	exporters[e.Name()] = e
+}
+
+// Get returns the exporter registered under name.
+func Get(name string) (Exporter, error) {
+	mu.RLock()
+	defer mu.RUnlock()
+	e, ok := exporters[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown export format %q (available: %s)", name, strings.Join(namesLocked(), ", "))
+	}
+	return e, nil
+}
+
+// Formats returns all registered exporters sorted by name.
+func Formats() []Exporter {
+	mu.RLock()
+	defer mu.RUnlock()
+	out := make([]Exporter, 0, len(exporters))
+	for _, name := range namesLocked() {
+		out = append(out, exporters[name])
+	}
+	return out
+}
+
+func namesLocked() []string {
+	names := make([]string, 0, len(exporters))
+	for name := range exporters {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// stepURL reconstructs the absolute URL of a reproduction step.
+func stepURL(s store.Step) (*url.URL, *burp.ParsedHTTPRequest) {
+	parsed := burp.ParseRawRequest(s.Raw)
+	u := &url.URL{Scheme: "https"}
+	if s.TLS != nil && !*s.TLS {
+		u.Scheme = "http"
+	}
+	u.Host = s.Host
+	if u.Host == "" {
+		u.Host = parsed.Host
+	}
+	if s.Port != 0 {
+		host := u.Host
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+		u.Host = net.JoinHostPort(host, strconv.Itoa(s.Port))
+	}
+	if ref, err := url.ParseRequestURI(parsed.Path); err == nil {
+		u.Path, u.RawPath, u.RawQuery = ref.Path, ref.RawPath, ref.RawQuery
+	}
+	return u, parsed
+}
+
+// findingURL is the URL of the finding's last step, where assertions apply.
+func findingURL(f store.Finding) *url.URL {
+	if len(f.Steps) == 0 {
+		return &url.URL{}
+	}
+	u, _ := stepURL(f.Steps[len(f.Steps)-1])
+	return u
+}
+
+// severityTitle normalizes a free-form severity to Critical/High/Medium/Low/Info.
+func severityTitle(s string) string {
+	switch strings.ToLower(s) {
+	case "critical":
+		return "Critical"
+	case "high":
+		return "High"
+	case "medium":
+		return "Medium"
+	case "low":
+		return "Low"
+	}
+	return "Info"
+}
+
+// status is the finding's latest retest verdict, or "untested".
+func status(f store.Finding) string {
+	if n := len(f.Retests); n > 0 {
+		return f.Retests[n-1].Verdict
+	}
+	return "untested"
+}
@@ -0,0 +1,190 @@
+package export
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/c0tton-fluff/burp-mcp-server/internal/store"
+)
+
+var testFindings = []store.Finding{
+	{
+		ID:          "F-1",
+		Title:       "IDOR on orders",
+		Severity:    "high",
+		Description: "Any user can read any order.",
+		Steps: []store.Step{{
+			Raw:  "POST /api/orders/2?view=full HTTP/1.1\r\nHost: shop.example\r\nContent-Type: application/json\r\n\r\n{}",
+			Port: 8443,
+		}},
+		Assertions: []store.Assertion{{Type: "status", Value: "200"}},
+		CreatedAt:  time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC),
+		Retests:    []store.Retest{{At: time.Date(2026, 10, 2, 12, 0, 0, 0, time.UTC), Verdict: store.VerdictFixed}},
+	},
+	{ID: "F-2", Title: "Verbose errors", Steps: []store.Step{{Raw: "GET /x HTTP/1.1\r\nHost: shop.example\r\n\r\n"}}},
+}
+
+func TestFormats_Registered(t *testing.T) {
+	var names []string
+	for _, e := range Formats() {
+		names = append(names, e.Name())
+	}
+	if got := strings.Join(names, ","); got != "burpxml,defectdojo,har,markdown,sarif" {
+		t.Errorf("formats = %s", got)
+	}
+	if _, err := Get("pdf"); err == nil || !strings.Contains(err.Error(), "sarif") {
+		t.Errorf("unknown format error should list formats: %v", err)
+	}
+}
+
+func TestStepURL(t *testing.T) {
+	u, parsed := stepURL(testFindings[0].Steps[0])
+	if u.String() != "https://shop.example:8443/api/orders/2?view=full" || parsed.Method != "POST" {
+		t.Errorf("url = %s, method = %s", u, parsed.Method)
+	}
+}
+
+func export(t *testing.T, name string) []byte {
+	t.Helper()
+	e, err := Get(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := e.Export(&buf, testFindings); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestExport_JSONFormatsAreValid(t *testing.T) {
+	for _, name := range []string{"sarif", "defectdojo", "har"} {
+		var v map[string]any
+		if err := json.Unmarshal(export(t, name), &v); err != nil {
+			t.Errorf("%s: invalid JSON: %v", name, err)
+		}
+	}
+}
+
+func TestExport_SARIF(t *testing.T) {
+	var log sarifLog
+	if err := json.Unmarshal(export(t, "sarif"), &log); err != nil {
+		t.Fatal(err)
+	}
+	r := log.Runs[0].Results
+	if len(r) != 2 || r[0].Level != "error" || r[1].Level != "note" || r[0].Properties["status"] != "fixed" {
+		t.Errorf("results = %+v", r)
+	}
+}
+
+func TestExport_DefectDojo(t *testing.T) {
+	var out struct {
+		Findings []dojoFinding `json:"findings"`
+	}
+	if err := json.Unmarshal(export(t, "defectdojo"), &out); err != nil {
+		t.Fatal(err)
+	}
+	f := out.Findings[0]
+	if f.Severity != "High" || f.Active || f.Mitigated == "" || f.Endpoints[0].Port != 8443 {
+		t.Errorf("finding = %+v", f)
+	}
+	if !out.Findings[1].Active || out.Findings[1].Severity != "Info" {
+		t.Errorf("untested finding = %+v", out.Findings[1])
+	}
+}
+
+func TestExport_HAR(t *testing.T) {
+	var out struct {
+		Log struct {
+			Entries []harEntry `json:"entries"`
+		} `json:"log"`
+	}
+	if err := json.Unmarshal(export(t, "har"), &out); err != nil {
+		t.Fatal(err)
+	}
+	e := out.Log.Entries[0]
+	if e.Request.Method != "POST" || e.Request.PostData == nil || e.Request.PostData.MimeType != "application/json" || e.Request.QueryString[0].Value != "full" {
+		t.Errorf("entry = %+v", e.Request)
+	}
+}
+
+func TestExport_BurpXML(t *testing.T) {
+	var doc burpIssues
+	if err := xml.Unmarshal(export(t, "burpxml"), &doc); err != nil {
+		t.Fatal(err)
+	}
+	if len(doc.Issues) != 2 || doc.Issues[0].Host.Value != "https://shop.example:8443" || doc.Issues[1].Severity != "Information" {
+		t.Errorf("issues = %+v", doc.Issues)
+	}
+}
+
+const burpReport = `<?xml version="1.0"?>
+<!DOCTYPE issues [
+<!ELEMENT issues (issue*)>
+<!ATTLIST issues burpVersion CDATA "">
+]>
+<issues burpVersion="2024.8" exportTime="Tue Oct 01 12:00:00 UTC 2026">
+  <issue>
+    <serialNumber>4711</serialNumber>
+    <type>1049088</type>
+    <name>SQL injection</name>
+    <host ip="10.0.0.5">https://shop.example</host>
+    <path><![CDATA[/search]]></path>
+    <location><![CDATA[/search [q parameter]]]></location>
+    <severity>High</severity>
+    <confidence>Firm</confidence>
+    <issueBackground><![CDATA[<p>SQL injection background.</p>]]></issueBackground>
+    <issueDetail><![CDATA[The <b>q</b> parameter appears to be vulnerable.]]></issueDetail>
+    <requestresponse>
+      <request base64="true" method="GET"><![CDATA[R0VUIC9zZWFyY2g/cT0nIEhUVFAvMS4xDQoNCg==]]></request>
+      <response base64="false"><![CDATA[HTTP/1.1 500 Internal Server Error]]></response>
+      <responseRedirected>false</responseRedirected>
+    </requestresponse>
+  </issue>
+</issues>`
+
+func TestParseBurpXML(t *testing.T) {
+	issues, err := ParseBurpXML(strings.NewReader(burpReport), "report.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(issues) != 1 {
+		t.Fatalf("issues = %+v", issues)
+	}
+	i := issues[0]
+	if i.SerialNumber != "4711" || i.Type != "1049088" || i.Host != "https://shop.example" || i.Location != "/search [q parameter]" ||
+		i.IssueBackground != "<p>SQL injection background.</p>" || i.Source != "report.xml" {
+		t.Errorf("issue = %+v", i)
+	}
+	if len(i.Evidence) != 1 || i.Evidence[0].Request != "GET /search?q=' HTTP/1.1\r\n\r\n" || i.Evidence[0].Response != "HTTP/1.1 500 Internal Server Error" {
+		t.Errorf("evidence = %+v", i.Evidence)
+	}
+
+	// Our own export reads back in.
+	issues, err = ParseBurpXML(bytes.NewReader(export(t, "burpxml")), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(issues) != 2 || issues[0].Name != "IDOR on orders" || !strings.HasPrefix(issues[0].Evidence[0].Request, "POST /api/orders/2") {
+		t.Errorf("round trip = %+v", issues)
+	}
+
+	for _, bad := range []string{"<findings/>", "<issues><issue><name></name></issue></issues>", `<issues><issue><name>x</name><requestresponse><request base64="true">!!</request></requestresponse></issue></issues>`} {
+		if _, err := ParseBurpXML(strings.NewReader(bad), ""); err == nil {
+			t.Errorf("%q: expected error", bad)
+		}
+	}
+}
+
+func TestExport_Markdown(t *testing.T) {
+	md := string(export(t, "markdown"))
+	for _, want := range []string{"| F-1 | High | IDOR on orders | fixed |", "## F-2: Verbose errors", "- status is 200", "```http\nPOST /api/orders/2"} {
+		if !strings.Contains(md, want) {
+			t.Errorf("markdown missing %q", want)
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package export
+
+import (
+	"encoding/json"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/c0tton-fluff/burp-mcp-server/internal/burp"
+	"github.com/c0tton-fluff/burp-mcp-server/internal/store"
+)
+
+func init() { Register(harExporter{}) }
+
+// harExporter writes each finding's reproduction requests as HAR 1.2 entries.
+// Responses are not stored, so they are left empty.
+type harExporter struct{}
+
+func (harExporter) Name() string { return "har" }
+func (harExporter) Description() string {
+	return "HAR 1.2 of reproduction requests (import into browsers or proxies)"
+}
+func (harExporter) Extension() string { return ".har" }
+
+type harNameValue struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type harPostData struct {
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+}
+
+type harRequest struct {
+	Method      string         `json:"method"`
+	URL         string         `json:"url"`
+	HTTPVersion string         `json:"httpVersion"`
+	Cookies     []harNameValue `json:"cookies"`
+	Headers     []harNameValue `json:"headers"`
+	QueryString []harNameValue `json:"queryString"`
+	PostData    *harPostData   `json:"postData,omitempty"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int            `json:"bodySize"`
+}
+
+type harResponse struct {
+	Status      int            `json:"status"`
+	StatusText  string         `json:"statusText"`
+	HTTPVersion string         `json:"httpVersion"`
+	Cookies     []harNameValue `json:"cookies"`
+	Headers     []harNameValue `json:"headers"`
+	Content     struct {
+		Size     int    `json:"size"`
+		MimeType string `json:"mimeType"`
+	} `json:"content"`
+	RedirectURL string `json:"redirectURL"`
+	HeadersSize int    `json:"headersSize"`
+	BodySize    int    `json:"bodySize"`
+}
+
+type harEntry struct {
+	StartedDateTime string         `json:"startedDateTime"`
+	Time            int            `json:"time"`
+	Request         harRequest     `json:"request"`
+	Response        harResponse    `json:"response"`
+	Cache           struct{}       `json:"cache"`
+	Timings         map[string]int `json:"timings"`
+	Comment         string         `json:"comment"`
+}
+
+func (harExporter) Export(w io.Writer, findings []store.Finding) error {
+	entries := []harEntry{}
+	for _, f := range findings {
+		for i, s := range f.Steps {
+			entries = append(entries, harEntryFor(f, i, s))
+		}
+	}
+
+	var out struct {
+		Log struct {
+			Version string `json:"version"`
+			Creator struct {
+				Name    string `json:"name"`
+				Version string `json:"version"`
+			} `json:"creator"`
+			Entries []harEntry `json:"entries"`
+		} `json:"log"`
+	}
+	out.Log.Version = "1.2"
+	out.Log.Creator.Name = "burp-mcp-server"
+	out.Log.Creator.Version = "1"
+	out.Log.Entries = entries
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(out)
+}
+
+func harEntryFor(f store.Finding, i int, s store.Step) harEntry {
+	u, parsed := stepURL(s)
+
+	proto := "HTTP/1.1"
+	if line, _, _ := strings.Cut(strings.ReplaceAll(s.Raw, "\r\n", "\n"), "\n"); strings.Count(line, " ") >= 2 {
+		proto = line[strings.LastIndex(line, " ")+1:]
+	}
+
+	req := harRequest{
+		Method:      parsed.Method,
+		URL:         u.String(),
+		HTTPVersion: proto,
+		Cookies:     []harNameValue{},
+		Headers:     []harNameValue{},
+		QueryString: []harNameValue{},
+		HeadersSize: -1,
+		BodySize:    len(parsed.Body),
+	}
+	for _, h := range parsed.HeaderList {
+		req.Headers = append(req.Headers, harNameValue{Name: h.Name, Value: h.Value})
+	}
+	for name, values := range u.Query() {
+		for _, v := range values {
+			req.QueryString = append(req.QueryString, harNameValue{Name: name, Value: v})
+		}
+	}
+	slices.SortStableFunc(req.QueryString, func(a, b harNameValue) int { return strings.Compare(a.Name, b.Name) })
+	if parsed.Body != "" {
+		req.PostData = &harPostData{MimeType: burp.GetHeader(parsed.Headers, "Content-Type"), Text: parsed.Body}
+	}
+
+	resp := harResponse{Cookies: []harNameValue{}, Headers: []harNameValue{}, HeadersSize: -1, BodySize: -1}
+	return harEntry{
+		StartedDateTime: f.CreatedAt.Format(time.RFC3339),
+		Request:         req,
+		Response:        resp,
+		Timings:         map[string]int{"send": 0, "wait": 0, "receive": 0},
+		Comment:         f.ID + " step " + strconv.Itoa(i+1) + ": " + f.Title,
+	}
+}
@@ -0,0 +1,87 @@
+package export
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/c0tton-fluff/burp-mcp-server/internal/store"
+)
+
+func init() { Register(markdownExporter{}) }
+
+type markdownExporter struct{}
+
+func (markdownExporter) Name() string        { return "markdown" }
+func (markdownExporter) Description() string { return "Human-readable findings report" }
+func (markdownExporter) Extension() string   { return ".md" }
+
+func (markdownExporter) Export(w io.Writer, findings []store.Finding) error {
+	b := bufio.NewWriter(w)
+	fmt.Fprintf(b, "# Findings\n\n")
+	if len(findings) == 0 {
+		fmt.Fprintf(b, "No findings recorded.\n")
+		return b.Flush()
+	}
+
+	fmt.Fprintf(b, "| ID | Severity | Title | Status |\n|----|----------|-------|--------|\n")
+	for _, f := range findings {
+		fmt.Fprintf(b, "| %s | %s | %s | %s |\n", f.ID, severityTitle(f.Severity), escapeCell(f.Title), status(f))
+	}
+
+	for _, f := range findings {
+		fmt.Fprintf(b, "\n## %s: %s\n\n", f.ID, f.Title)
+		fmt.Fprintf(b, "- **Severity:** %s\n", severityTitle(f.Severity))
+		fmt.Fprintf(b, "- **URL:** %s\n", findingURL(f))
+		fmt.Fprintf(b, "- **Recorded:** %s\n", f.CreatedAt.Format(time.DateOnly))
+		if n := len(f.Retests); n > 0 {
+			last := f.Retests[n-1]
+			fmt.Fprintf(b, "- **Status:** %s (retested %s)\n", last.Verdict, last.At.Format(time.RFC3339))
+		} else {
+			fmt.Fprintf(b, "- **Status:** untested\n")
+		}
+		if f.Description != "" {
+			fmt.Fprintf(b, "\n%s\n", f.Description)
+		}
+
+		fmt.Fprintf(b, "\n### Reproduction\n")
+		for i, s := range f.Steps {
+			fmt.Fprintf(b, "\n%d. `%s`\n\n```http\n%s\n```\n", i+1, stepLabel(s), strings.TrimRight(strings.ReplaceAll(s.Raw, "\r\n", "\n"), "\n"))
+		}
+
+		fmt.Fprintf(b, "\n### Expected (vulnerable) behavior\n\n")
+		for _, a := range f.Assertions {
+			fmt.Fprintf(b, "- %s\n", describeAssertion(a))
+		}
+	}
+	return b.Flush()
+}
+
+func stepLabel(s store.Step) string {
+	u, parsed := stepURL(s)
+	return parsed.Method + " " + u.String()
+}
+
+func describeAssertion(a store.Assertion) string {
+	switch a.Type {
+	case "status":
+		return "status is " + a.Value
+	case "body_contains":
+		return fmt.Sprintf("body contains `%s`", a.Value)
+	case "body_not_contains":
+		return fmt.Sprintf("body does not contain `%s`", a.Value)
+	case "body_regex":
+		return fmt.Sprintf("body matches `%s`", a.Value)
+	case "header_contains":
+		return fmt.Sprintf("%s header contains `%s`", a.Header, a.Value)
+	case "header_absent":
+		return a.Header + " header is absent"
+	}
+	return a.Type + " " + a.Value
+}
+
+func escapeCell(s string) string {
+	return strings.ReplaceAll(s, "|", `\|`)
+}
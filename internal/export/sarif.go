@@ -0,0 +1,113 @@
+package export
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+
+	"github.com/c0tton-fluff/burp-mcp-server/internal/store"
+)
+
+func init() { Register(sarifExporter{}) }
+
+type sarifExporter struct{}
+
+func (sarifExporter) Name() string        { return "sarif" }
+func (sarifExporter) Description() string { return "SARIF 2.1.0 for code scanning dashboards" }
+func (sarifExporter) Extension() string   { return ".sarif" }
+
+type sarifLog struct {
+	Version string     `json:"version"`
+	Schema  string     `json:"$schema"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name  string      `json:"name"`
+	Rules []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string       `json:"id"`
+	Name             string       `json:"name"`
+	ShortDescription sarifMessage `json:"shortDescription"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID     string            `json:"ruleId"`
+	Level      string            `json:"level"`
+	Message    sarifMessage      `json:"message"`
+	Locations  []sarifLocation   `json:"locations"`
+	Properties map[string]string `json:"properties"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation struct {
+		ArtifactLocation struct {
+			URI string `json:"uri"`
+		} `json:"artifactLocation"`
+	} `json:"physicalLocation"`
+}
+
+func (sarifExporter) Export(w io.Writer, findings []store.Finding) error {
+	run := sarifRun{
+		Tool:    sarifTool{Driver: sarifDriver{Name: "burp-mcp-server", Rules: []sarifRule{}}},
+		Results: []sarifResult{},
+	}
+	for _, f := range findings {
+		run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, sarifRule{
+			ID:               f.ID,
+			Name:             f.Title,
+			ShortDescription: sarifMessage{Text: f.Title},
+		})
+
+		msg := f.Title
+		if f.Description != "" {
+			msg += "\n\n" + f.Description
+		}
+		var loc sarifLocation
+		loc.PhysicalLocation.ArtifactLocation.URI = findingURL(f).String()
+		run.Results = append(run.Results, sarifResult{
+			RuleID:    f.ID,
+			Level:     sarifLevel(f.Severity),
+			Message:   sarifMessage{Text: msg},
+			Locations: []sarifLocation{loc},
+			Properties: map[string]string{
+				"severity": severityTitle(f.Severity),
+				"status":   status(f),
+			},
+		})
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(sarifLog{
+		Version: "2.1.0",
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Runs:    []sarifRun{run},
+	})
+}
+
+// sarifLevel maps severity to SARIF's error/warning/note levels.
+func sarifLevel(severity string) string {
+	switch strings.ToLower(severity) {
+	case "critical", "high":
+		return "error"
+	case "medium":
+		return "warning"
+	}
+	return "note"
+}
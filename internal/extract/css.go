@@ -0,0 +1,389 @@
+package extract
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// compound is a run of simple selectors on one element: div#main.a[href].
+type compound struct {
+	tag     string // "" or "*" matches any element
+	id      string
+	classes []string
+	attrs   []attrSelector
+	pseudos []pseudoSelector
+}
+
+type attrSelector struct {
+	name, op, value string // op "" tests presence
+}
+
+type pseudoSelector struct {
+	name string
+	n    int
+	not  *compound
+}
+
+// complexSelector is compounds joined by combinators: combs[i] sits
+// between parts[i] and parts[i+1] and is ' ', '>', '+', or '~'.
+type complexSelector struct {
+	parts []compound
+	combs []byte
+}
+
+// Select returns the elements of doc matching a CSS selector, in document
+// order. Supported: type, *, #id, .class, [attr] with =, ~=, |=, ^=, $=,
+// and *=, the descendant, >, +, and ~ combinators, comma groups, and the
+// :first-child, :last-child, :nth-child(n), :first-of-type, :last-of-type,
+// and :not(compound) pseudo-classes.
+func Select(doc *Node, selector string) ([]*Node, error) {
+	group, err := parseSelectorGroup(selector)
+	if err != nil {
+		return nil, err
+	}
+	var out []*Node
+	var walk func(*Node)
+	walk = func(n *Node) {
+		for _, c := range n.Children {
+			if c.Tag == "" {
+				continue
+			}
+			for _, sel := range group {
+				if sel.match(c, len(sel.parts)-1) {
+					out = append(out, c)
+					break
+				}
+			}
+			walk(c)
+		}
+	}
+	walk(doc)
+	return out, nil
+}
+
+// match reports whether n matches sel.parts[i] and what precedes it.
+func (sel complexSelector) match(n *Node, i int) bool {
+	if !sel.parts[i].match(n) {
+		return false
+	}
+	if i == 0 {
+		return true
+	}
+	switch sel.combs[i-1] {
+	case '>':
+		return n.Parent != nil && sel.match(n.Parent, i-1)
+	case '+':
+		prev := previousElement(n)
+		return prev != nil && sel.match(prev, i-1)
+	case '~':
+		for prev := previousElement(n); prev != nil; prev = previousElement(prev) {
+			if sel.match(prev, i-1) {
+				return true
+			}
+		}
+	default:
+		for p := n.Parent; p != nil; p = p.Parent {
+			if sel.match(p, i-1) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func previousElement(n *Node) *Node {
+	if n.Parent == nil {
+		return nil
+	}
+	siblings := n.Parent.elements()
+	if i := slices.Index(siblings, n); i > 0 {
+		return siblings[i-1]
+	}
+	return nil
+}
+
+func (c *compound) match(n *Node) bool {
+	if n.Tag == "" || n.Tag == "#document" {
+		return false
+	}
+	if c.tag != "" && c.tag != "*" && c.tag != n.Tag {
+		return false
+	}
+	if c.id != "" {
+		if id, _ := n.Attr("id"); id != c.id {
+			return false
+		}
+	}
+	if len(c.classes) > 0 {
+		class, _ := n.Attr("class")
+		have := strings.Fields(class)
+		for _, want := range c.classes {
+			if !slices.Contains(have, want) {
+				return false
+			}
+		}
+	}
+	for _, a := range c.attrs {
+		if !a.match(n) {
+			return false
+		}
+	}
+	for _, p := range c.pseudos {
+		if !p.match(n) {
+			return false
+		}
+	}
+	return true
+}
+
+func (a attrSelector) match(n *Node) bool {
+	v, ok := n.Attr(a.name)
+	if !ok {
+		return false
+	}
+	switch a.op {
+	case "":
+		return true
+	case "=":
+		return v == a.value
+	case "~=":
+		return slices.Contains(strings.Fields(v), a.value)
+	case "|=":
+		return v == a.value || strings.HasPrefix(v, a.value+"-")
+	case "^=":
+		return a.value != "" && strings.HasPrefix(v, a.value)
+	case "$=":
+		return a.value != "" && strings.HasSuffix(v, a.value)
+	default: // *=
+		return a.value != "" && strings.Contains(v, a.value)
+	}
+}
+
+func (p pseudoSelector) match(n *Node) bool {
+	if p.name == "not" {
+		return !p.not.match(n)
+	}
+	var siblings []*Node
+	if n.Parent != nil {
+		for _, s := range n.Parent.elements() {
+			if !strings.HasSuffix(p.name, "-of-type") || s.Tag == n.Tag {
+				siblings = append(siblings, s)
+			}
+		}
+	}
+	i := slices.Index(siblings, n)
+	switch p.name {
+	case "first-child", "first-of-type":
+		return i == 0
+	case "last-child", "last-of-type":
+		return i >= 0 && i == len(siblings)-1
+	default: // nth-child
+		return i+1 == p.n
+	}
+}
+
+// selectorParser is a cursor over a selector string.
+type selectorParser struct {
+	s string
+	i int
+}
+
+func parseSelectorGroup(s string) ([]complexSelector, error) {
+	p := &selectorParser{s: s}
+	var group []complexSelector
+	for {
+		sel, err := p.complex()
+		if err != nil {
+			return nil, err
+		}
+		group = append(group, sel)
+		p.skipSpace()
+		if p.i >= len(p.s) {
+			return group, nil
+		}
+		if p.s[p.i] != ',' {
+			return nil, p.errorf("unexpected %q", p.s[p.i])
+		}
+		p.i++
+	}
+}
+
+func (p *selectorParser) complex() (complexSelector, error) {
+	var sel complexSelector
+	p.skipSpace()
+	for {
+		c, err := p.compound()
+		if err != nil {
+			return sel, err
+		}
+		sel.parts = append(sel.parts, c)
+
+		spaced := p.skipSpace()
+		if p.i >= len(p.s) || p.s[p.i] == ',' {
+			return sel, nil
+		}
+		comb := byte(' ')
+		switch p.s[p.i] {
+		case '>', '+', '~':
+			comb = p.s[p.i]
+			p.i++
+			p.skipSpace()
+		default:
+			if !spaced {
+				return sel, p.errorf("unexpected %q", p.s[p.i])
+			}
+		}
+		sel.combs = append(sel.combs, comb)
+	}
+}
+
+func (p *selectorParser) compound() (compound, error) {
+	var c compound
+	start := p.i
+	if p.i < len(p.s) && p.s[p.i] == '*' {
+		c.tag = "*"
+		p.i++
+	} else if name := p.ident(); name != "" {
+		c.tag = strings.ToLower(name)
+	}
+	for p.i < len(p.s) {
+		switch p.s[p.i] {
+		case '#':
+			p.i++
+			if c.id = p.ident(); c.id == "" {
+				return c, p.errorf("expected id after #")
+			}
+		case '.':
+			p.i++
+			class := p.ident()
+			if class == "" {
+				return c, p.errorf("expected class after .")
+			}
+			c.classes = append(c.classes, class)
+		case '[':
+			a, err := p.attr()
+			if err != nil {
+				return c, err
+			}
+			c.attrs = append(c.attrs, a)
+		case ':':
+			ps, err := p.pseudo()
+			if err != nil {
+				return c, err
+			}
+			c.pseudos = append(c.pseudos, ps)
+		default:
+			if p.i == start {
+				return c, p.errorf("unexpected %q", p.s[p.i])
+			}
+			return c, nil
+		}
+	}
+	if p.i == start {
+		return c, p.errorf("empty selector")
+	}
+	return c, nil
+}
+
+func (p *selectorParser) attr() (attrSelector, error) {
+	p.i++ // [
+	p.skipSpace()
+	a := attrSelector{name: strings.ToLower(p.ident())}
+	if a.name == "" {
+		return a, p.errorf("expected attribute name")
+	}
+	p.skipSpace()
+	if p.i < len(p.s) && p.s[p.i] != ']' {
+		for _, op := range []string{"=", "~=", "|=", "^=", "$=", "*="} {
+			if strings.HasPrefix(p.s[p.i:], op) {
+				a.op = op
+				p.i += len(op)
+				break
+			}
+		}
+		if a.op == "" {
+			return a, p.errorf("bad attribute operator")
+		}
+		p.skipSpace()
+		if p.i < len(p.s) && (p.s[p.i] == '"' || p.s[p.i] == '\'') {
+			q := p.s[p.i]
+			end := strings.IndexByte(p.s[p.i+1:], q)
+			if end < 0 {
+				return a, p.errorf("unterminated string")
+			}
+			a.value = p.s[p.i+1 : p.i+1+end]
+			p.i += end + 2
+		} else {
+			a.value = p.ident()
+		}
+		p.skipSpace()
+	}
+	if p.i >= len(p.s) || p.s[p.i] != ']' {
+		return a, p.errorf("expected ]")
+	}
+	p.i++
+	return a, nil
+}
+
+func (p *selectorParser) pseudo() (pseudoSelector, error) {
+	p.i++ // :
+	ps := pseudoSelector{name: strings.ToLower(p.ident())}
+	switch ps.name {
+	case "first-child", "last-child", "first-of-type", "last-of-type":
+		return ps, nil
+	case "nth-child", "not":
+	default:
+		return ps, p.errorf("unsupported pseudo-class :%s", ps.name)
+	}
+	if p.i >= len(p.s) || p.s[p.i] != '(' {
+		return ps, p.errorf("expected ( after :%s", ps.name)
+	}
+	end := strings.IndexByte(p.s[p.i:], ')')
+	if end < 0 {
+		return ps, p.errorf("expected )")
+	}
+	arg := strings.TrimSpace(p.s[p.i+1 : p.i+end])
+	p.i += end + 1
+	if ps.name == "not" {
+		inner := &selectorParser{s: arg}
+		c, err := inner.compound()
+		if err != nil || inner.i != len(arg) {
+			return ps, p.errorf("bad :not(%s)", arg)
+		}
+		ps.not = &c
+		return ps, nil
+	}
+	n, err := strconv.Atoi(arg)
+	if err != nil || n < 1 {
+		return ps, p.errorf("nth-child takes a positive number, got %q", arg)
+	}
+	ps.n = n
+	return ps, nil
+}
+
+// ident reads a CSS identifier (letters, digits, -, _, and non-ASCII).
+func (p *selectorParser) ident() string {
+	start := p.i
+	for p.i < len(p.s) {
+		c := p.s[p.i]
+		if !isLetter(c) && (c < '0' || c > '9') && c != '-' && c != '_' && c < 0x80 {
+			break
+		}
+		p.i++
+	}
+	return p.s[start:p.i]
+}
+
+func (p *selectorParser) skipSpace() bool {
+	start := p.i
+	for p.i < len(p.s) && isSpace(p.s[p.i]) {
+		p.i++
+	}
+	return p.i > start
+}
+
+func (p *selectorParser) errorf(format string, args ...any) error {
+	return fmt.Errorf("css: %s at offset %d in %q", fmt.Sprintf(format, args...), p.i, p.s)
+}
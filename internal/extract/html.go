@@ -0,0 +1,247 @@
+package extract
+
+import (
+	"html"
+	"slices"
+	"strings"
+)
+
+// Node is an element or text node of a parsed HTML document. The document
+// itself is an element named "#document".
+type Node struct {
+	Tag      string // lower case; empty for text nodes
+	Attrs    []Attr
+	Text     string // text nodes only, entities decoded
+	Parent   *Node
+	Children []*Node
+
+	pos int // document order
+}
+
+// Attr is an element attribute, name in lower case and value decoded.
+type Attr struct {
+	Name, Value string
+}
+
+// Attr returns the named attribute's value.
+func (n *Node) Attr(name string) (string, bool) {
+	for _, a := range n.Attrs {
+		if a.Name == name {
+			return a.Value, true
+		}
+	}
+	return "", false
+}
+
+// TextContent returns the text below n with runs of whitespace collapsed.
+func (n *Node) TextContent() string {
+	if n.Tag == "" {
+		return strings.Join(strings.Fields(n.Text), " ")
+	}
+	var b strings.Builder
+	var walk func(*Node)
+	walk = func(n *Node) {
+		if n.Tag == "" {
+			b.WriteString(n.Text)
+			b.WriteByte(' ')
+		}
+		for _, c := range n.Children {
+			walk(c)
+		}
+	}
+	walk(n)
+	return strings.Join(strings.Fields(b.String()), " ")
+}
+
+// elements returns n's element children.
+func (n *Node) elements() []*Node {
+	var out []*Node
+	for _, c := range n.Children {
+		if c.Tag != "" {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// voidElements never have content or an end tag.
+var voidElements = map[string]bool{
+	"area": true, "base": true, "br": true, "col": true, "embed": true, "hr": true, "img": true,
+	"input": true, "link": true, "meta": true, "param": true, "source": true, "track": true, "wbr": true,
+}
+
+// rawTextElements hold text up to their end tag, markup included.
+var rawTextElements = map[string]bool{"script": true, "style": true, "textarea": true, "title": true}
+
+// impliedEnds lists, per start tag, the open elements it closes when one of
+// them is current: a new <li> ends the previous one, <tr> ends a cell.
+var impliedEnds = map[string][]string{
+	"li":     {"li"},
+	"option": {"option"},
+	"dt":     {"dt", "dd"},
+	"dd":     {"dt", "dd"},
+	"p":      {"p"},
+	"tr":     {"td", "th", "tr"},
+	"td":     {"td", "th"},
+	"th":     {"td", "th"},
+}
+
+// ParseHTML builds a tree from s. It is forgiving the way browsers are:
+// stray end tags are dropped, unclosed elements close with their parent,
+// and anything that doesn't parse as a tag is text.
+func ParseHTML(s string) *Node {
+	doc := &Node{Tag: "#document"}
+	stack := []*Node{doc}
+	pos := 1
+	add := func(n *Node) {
+		parent := stack[len(stack)-1]
+		n.Parent, n.pos = parent, pos
+		pos++
+		parent.Children = append(parent.Children, n)
+	}
+	text := func(t string) {
+		if t != "" {
+			add(&Node{Text: html.UnescapeString(t)})
+		}
+	}
+
+	for len(s) > 0 {
+		lt := strings.IndexByte(s, '<')
+		if lt < 0 {
+			text(s)
+			break
+		}
+		text(s[:lt])
+		s = s[lt:]
+
+		switch {
+		case strings.HasPrefix(s, "<!--"):
+			end := strings.Index(s[4:], "-->")
+			if end < 0 {
+				return doc
+			}
+			s = s[4+end+3:]
+		case strings.HasPrefix(s, "<!") || strings.HasPrefix(s, "<?"):
+			end := strings.IndexByte(s, '>')
+			if end < 0 {
+				return doc
+			}
+			s = s[end+1:]
+		case strings.HasPrefix(s, "</"):
+			end := strings.IndexByte(s, '>')
+			if end < 0 {
+				return doc
+			}
+			name := strings.ToLower(strings.TrimSpace(s[2:end]))
+			s = s[end+1:]
+			for i := len(stack) - 1; i > 0; i-- {
+				if stack[i].Tag == name {
+					stack = stack[:i]
+					break
+				}
+			}
+		case len(s) > 1 && isLetter(s[1]):
+			n, rest, selfClosing := parseTag(s)
+			s = rest
+			if ends := impliedEnds[n.Tag]; ends != nil {
+				for len(stack) > 1 && slices.Contains(ends, stack[len(stack)-1].Tag) {
+					stack = stack[:len(stack)-1]
+				}
+			}
+			add(n)
+			if rawTextElements[n.Tag] {
+				end := strings.Index(strings.ToLower(s), "</"+n.Tag)
+				if end < 0 {
+					end = len(s)
+				}
+				if end > 0 {
+					stack = append(stack, n)
+					raw := s[:end]
+					if n.Tag == "script" || n.Tag == "style" {
+						add(&Node{Text: raw})
+					} else {
+						text(raw)
+					}
+					stack = stack[:len(stack)-1]
+				}
+				s = s[end:]
+				if gt := strings.IndexByte(s, '>'); gt >= 0 {
+					s = s[gt+1:]
+				}
+				continue
+			}
+			if !selfClosing && !voidElements[n.Tag] {
+				stack = append(stack, n)
+			}
+		default:
+			text("<")
+			s = s[1:]
+		}
+	}
+	return doc
+}
+
+// parseTag parses the start tag at the beginning of s.
+func parseTag(s string) (n *Node, rest string, selfClosing bool) {
+	i := 1
+	for i < len(s) && !isSpace(s[i]) && s[i] != '>' && s[i] != '/' {
+		i++
+	}
+	n = &Node{Tag: strings.ToLower(s[1:i])}
+	for i < len(s) {
+		for i < len(s) && isSpace(s[i]) {
+			i++
+		}
+		if i >= len(s) {
+			break
+		}
+		switch {
+		case s[i] == '>':
+			return n, s[i+1:], selfClosing
+		case s[i] == '/':
+			selfClosing = true
+			i++
+			continue
+		}
+		selfClosing = false
+		j := i
+		for j < len(s) && !isSpace(s[j]) && s[j] != '=' && s[j] != '>' && (s[j] != '/' || j == i) {
+			j++
+		}
+		a := Attr{Name: strings.ToLower(s[i:j])}
+		i = j
+		for i < len(s) && isSpace(s[i]) {
+			i++
+		}
+		if i < len(s) && s[i] == '=' {
+			i++
+			for i < len(s) && isSpace(s[i]) {
+				i++
+			}
+			if i < len(s) && (s[i] == '"' || s[i] == '\'') {
+				end := strings.IndexByte(s[i+1:], s[i])
+				if end < 0 {
+					end = len(s) - i - 1
+				}
+				a.Value = s[i+1 : i+1+end]
+				i += end + 2
+			} else {
+				j := i
+				for j < len(s) && !isSpace(s[j]) && s[j] != '>' {
+					j++
+				}
+				a.Value = s[i:j]
+				i = j
+			}
+			a.Value = html.UnescapeString(a.Value)
+		}
+		if _, dup := n.Attr(a.Name); !dup {
+			n.Attrs = append(n.Attrs, a)
+		}
+	}
+	return n, "", selfClosing
+}
+
+func isLetter(c byte) bool { return c|0x20 >= 'a' && c|0x20 <= 'z' }
+
+func isSpace(c byte) bool { return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f' }
@@ -0,0 +1,133 @@
+package extract
+
+import (
+	"strings"
+	"testing"
+)
+
+const page = `<!DOCTYPE html>
+<html><head><title>Account &amp; settings</title>
+<meta name="csrf-token" content="m3ta">
+<script>if (a < b) { document.write("<p>not a tag</p>") }</script>
+</head>
+<body>
+<!-- <input name="csrf" value="commented"> -->
+<div id="main" class="content wide">
+  <form action="/items" method=post>
+    <input type="hidden" name="csrf" value="t&#111;k1">
+    <input name=qty value=3 disabled>
+  </form>
+  <ul class="items">
+    <li data-id="101"><a href="/items/101">First</a>
+    <li data-id="102" class="sold"><a href="/items/102">Second</a>
+    <li data-id="103"><a href='/items/103'>Third</a>
+  </ul>
+  <p>One<p>Two <b>bold</b></p>
+  </span>
+</div>
+</body></html>`
+
+func selectValues(t *testing.T, sel, attr string) string {
+	t.Helper()
+	nodes, err := Select(ParseHTML(page), sel)
+	if err != nil {
+		t.Fatalf("%s: %v", sel, err)
+	}
+	return values(nodes, attr)
+}
+
+func values(nodes []*Node, attr string) string {
+	out := make([]string, len(nodes))
+	for i, n := range nodes {
+		if attr != "" {
+			out[i], _ = n.Attr(attr)
+		} else {
+			out[i] = n.TextContent()
+		}
+	}
+	return strings.Join(out, "|")
+}
+
+func TestParseHTML(t *testing.T) {
+	doc := ParseHTML(page)
+	scripts, _ := Select(doc, "script")
+	if len(scripts) != 1 || !strings.Contains(scripts[0].TextContent(), `"<p>not a tag</p>"`) {
+		t.Errorf("script = %q", values(scripts, ""))
+	}
+	if got := selectValues(t, "title", ""); got != "Account & settings" {
+		t.Errorf("title = %q", got)
+	}
+	// Unclosed <li> and <p> close at the next sibling; the stray </span> is dropped.
+	if got := selectValues(t, "ul > li", "data-id"); got != "101|102|103" {
+		t.Errorf("li = %q", got)
+	}
+	if got := selectValues(t, "div > p", ""); got != "One|Two bold" {
+		t.Errorf("p = %q", got)
+	}
+	if got := selectValues(t, "input", "value"); got != "tok1|3" {
+		t.Errorf("input values = %q (comment not skipped or entity not decoded)", got)
+	}
+}
+
+func TestSelect(t *testing.T) {
+	tests := []struct{ sel, attr, want string }{
+		{"input[name=csrf]", "value", "tok1"},
+		{`input[name="csrf"]`, "value", "tok1"},
+		{"meta[name$=token]", "content", "m3ta"},
+		{"#main.content form", "action", "/items"},
+		{"div.wide .items li.sold a", "", "Second"},
+		{"li:not(.sold) > a", "href", "/items/101|/items/103"},
+		{"li:first-child a, li:last-child a", "", "First|Third"},
+		{"li:nth-child(2)", "data-id", "102"},
+		{"li + li", "data-id", "102|103"},
+		{"li.sold ~ li", "data-id", "103"},
+		{"a[href^='/items/1'][href*=0]", "", "First|Second|Third"},
+		{"input[disabled]", "name", "qty"},
+		{"[class~=wide]", "id", "main"},
+		{"table", "", ""},
+	}
+	for _, tt := range tests {
+		if got := selectValues(t, tt.sel, tt.attr); got != tt.want {
+			t.Errorf("%s = %q, want %q", tt.sel, got, tt.want)
+		}
+	}
+	for _, bad := range []string{"", "div >", "a[href", "li:hover", "li:nth-child(odd)", "a,,b", "div $ p"} {
+		if _, err := Select(ParseHTML(page), bad); err == nil {
+			t.Errorf("%q: expected error", bad)
+		}
+	}
+}
+
+func TestXPath(t *testing.T) {
+	doc := ParseHTML(page)
+	tests := []struct{ expr, want string }{
+		{"//input[@name='csrf']/@value", "tok1"},
+		{"//meta[@name=\"csrf-token\"]/@content", "m3ta"},
+		{"/html/head/title/text()", "Account & settings"},
+		{"//li[2]/a/@href", "/items/102"},
+		{"//li[last()]/@data-id", "103"},
+		{"//li[position() != 1 and not(@class)]/@data-id", "103"},
+		{"//a[contains(@href, '10') and starts-with(., 'S')]", "Second"},
+		{"//li[a='Third']/@data-id", "103"},
+		{"//a[text()='First']/../@data-id", "101"},
+		{"//div[@id='main']//form/@*", "/items|post"},
+		{"//ul/li[@class='sold' or @data-id='101']/@data-id", "101|102"},
+		{"//p[normalize-space()='Two bold']/b", "bold"},
+		{"//table", ""},
+	}
+	for _, tt := range tests {
+		nodes, err := XPath(doc, tt.expr)
+		if err != nil {
+			t.Errorf("%s: %v", tt.expr, err)
+			continue
+		}
+		if got := values(nodes, ""); got != tt.want {
+			t.Errorf("%s = %q, want %q", tt.expr, got, tt.want)
+		}
+	}
+	for _, bad := range []string{"", "//", "//a[", "//a/@href/b", "//a[foo()]", "//a[contains(@href)]", "//a['x"} {
+		if _, err := XPath(doc, bad); err == nil {
+			t.Errorf("%q: expected error", bad)
+		}
+	}
+}
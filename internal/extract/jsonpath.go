@@ -0,0 +1,348 @@
+// Package extract pulls values out of response bodies: JSONPath for JSON,
+// CSS selectors and XPath for HTML. Each covers the subset that locating a
+// token, ID, or link needs, with no dependencies beyond the standard library.
+package extract
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// DecodeJSON decodes body keeping numbers as json.Number, so large IDs
+// survive extraction unrounded.
+func DecodeJSON(body string) (any, error) {
+	dec := json.NewDecoder(strings.NewReader(body))
+	dec.UseNumber()
+	var doc any
+	if err := dec.Decode(&doc); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// String renders an extracted JSON value: strings as they are, anything
+// else as JSON.
+func String(v any) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+	b, _ := json.Marshal(v)
+	return string(b)
+}
+
+type jsonStepKind int
+
+const (
+	stepName jsonStepKind = iota
+	stepIndex
+	stepWildcard
+	stepFilter
+)
+
+// jsonStep is one segment of a JSONPath. recursive marks a ".." segment,
+// applied to the current values and everything below them.
+type jsonStep struct {
+	kind      jsonStepKind
+	name      string
+	index     int
+	filter    *jsonFilter
+	recursive bool
+}
+
+// jsonFilter is a [?(@.path op literal)] test, or [?(@.path)] for existence
+// when op is empty.
+type jsonFilter struct {
+	path []jsonStep
+	op   string
+	lit  any
+}
+
+// JSONPath returns the values at path in doc. The leading "$" is optional.
+// Supported: .name and ['name'], [n] (negative counts from the end), .* and
+// [*], ..name recursive descent, and [?(@.field op value)] filters with
+// ==, !=, <, <=, >, >=, or none to test that the field exists.
+func JSONPath(doc any, path string) ([]any, error) {
+	steps, err := parseJSONPath(path)
+	if err != nil {
+		return nil, err
+	}
+	return evalJSONPath([]any{doc}, steps), nil
+}
+
+func parseJSONPath(path string) ([]jsonStep, error) {
+	p := strings.TrimPrefix(strings.TrimSpace(path), "$")
+	if p != "" && p[0] != '.' && p[0] != '[' {
+		p = "." + p
+	}
+	var steps []jsonStep
+	for i := 0; i < len(p); {
+		recursive := false
+		if p[i] == '.' {
+			i++
+			if i < len(p) && p[i] == '.' {
+				recursive = true
+				i++
+			}
+			if i >= len(p) || p[i] != '[' {
+				j := i
+				for j < len(p) && p[j] != '.' && p[j] != '[' {
+					j++
+				}
+				name := p[i:j]
+				if name == "" {
+					return nil, fmt.Errorf("jsonpath: empty name at offset %d", i)
+				}
+				st := jsonStep{kind: stepName, name: name, recursive: recursive}
+				if name == "*" {
+					st.kind = stepWildcard
+				}
+				steps = append(steps, st)
+				i = j
+				continue
+			}
+		}
+		if p[i] != '[' {
+			return nil, fmt.Errorf("jsonpath: unexpected %q at offset %d", p[i], i)
+		}
+		st, n, err := parseJSONBracket(p[i:])
+		if err != nil {
+			return nil, err
+		}
+		st.recursive = recursive
+		steps = append(steps, st)
+		i += n
+	}
+	return steps, nil
+}
+
+// parseJSONBracket parses the [...] segment at the start of s and returns it
+// with the number of bytes it spans.
+func parseJSONBracket(s string) (jsonStep, int, error) {
+	if strings.HasPrefix(s, "[?(") {
+		end := closingParen(s, 2)
+		if end < 0 || end+1 >= len(s) || s[end+1] != ']' {
+			return jsonStep{}, 0, fmt.Errorf("jsonpath: unterminated filter in %q", s)
+		}
+		f, err := parseJSONFilter(s[3:end])
+		if err != nil {
+			return jsonStep{}, 0, err
+		}
+		return jsonStep{kind: stepFilter, filter: f}, end + 2, nil
+	}
+	if len(s) > 1 && (s[1] == '\'' || s[1] == '"') {
+		end := strings.IndexByte(s[2:], s[1])
+		if end < 0 || 2+end+1 >= len(s) || s[2+end+1] != ']' {
+			return jsonStep{}, 0, fmt.Errorf("jsonpath: unterminated name in %q", s)
+		}
+		return jsonStep{kind: stepName, name: s[2 : 2+end]}, 2 + end + 2, nil
+	}
+	end := strings.IndexByte(s, ']')
+	if end < 0 {
+		return jsonStep{}, 0, fmt.Errorf("jsonpath: missing ] in %q", s)
+	}
+	inner := strings.TrimSpace(s[1:end])
+	if inner == "*" {
+		return jsonStep{kind: stepWildcard}, end + 1, nil
+	}
+	n, err := strconv.Atoi(inner)
+	if err != nil {
+		return jsonStep{}, 0, fmt.Errorf("jsonpath: bad index %q", inner)
+	}
+	return jsonStep{kind: stepIndex, index: n}, end + 1, nil
+}
+
+// closingParen returns the index of the parenthesis closing the one at
+// s[open], skipping quoted strings, or -1.
+func closingParen(s string, open int) int {
+	depth := 0
+	var quote byte
+	for i := open; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case c == '(':
+			depth++
+		case c == ')':
+			if depth--; depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+var filterOps = []string{"==", "!=", "<=", ">=", "<", ">"}
+
+func parseJSONFilter(expr string) (*jsonFilter, error) {
+	expr = strings.TrimSpace(expr)
+	left, op, right := expr, "", ""
+	var quote byte
+scan:
+	for i := 0; i < len(expr); i++ {
+		c := expr[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		default:
+			for _, o := range filterOps {
+				if strings.HasPrefix(expr[i:], o) {
+					left, op, right = strings.TrimSpace(expr[:i]), o, strings.TrimSpace(expr[i+len(o):])
+					break scan
+				}
+			}
+		}
+	}
+	if !strings.HasPrefix(left, "@") {
+		return nil, fmt.Errorf("jsonpath: filter %q must start with @", expr)
+	}
+	path, err := parseJSONPath(left[1:])
+	if err != nil {
+		return nil, err
+	}
+	f := &jsonFilter{path: path, op: op}
+	if op == "" {
+		return f, nil
+	}
+	if len(right) >= 2 && right[0] == '\'' && right[len(right)-1] == '\'' {
+		f.lit = right[1 : len(right)-1]
+		return f, nil
+	}
+	lit, err := DecodeJSON(right)
+	if err != nil {
+		return nil, fmt.Errorf("jsonpath: bad filter value %q", right)
+	}
+	f.lit = lit
+	return f, nil
+}
+
+func evalJSONPath(nodes []any, steps []jsonStep) []any {
+	for _, st := range steps {
+		if st.recursive {
+			var all []any
+			for _, n := range nodes {
+				all = appendDescendants(all, n)
+			}
+			nodes = all
+		}
+		var next []any
+		for _, n := range nodes {
+			switch st.kind {
+			case stepName:
+				if m, ok := n.(map[string]any); ok {
+					if v, ok := m[st.name]; ok {
+						next = append(next, v)
+					}
+				}
+			case stepIndex:
+				if a, ok := n.([]any); ok {
+					i := st.index
+					if i < 0 {
+						i += len(a)
+					}
+					if i >= 0 && i < len(a) {
+						next = append(next, a[i])
+					}
+				}
+			case stepWildcard:
+				next = append(next, jsonChildren(n)...)
+			case stepFilter:
+				for _, c := range jsonChildren(n) {
+					if st.filter.match(c) {
+						next = append(next, c)
+					}
+				}
+			}
+		}
+		nodes = next
+	}
+	return nodes
+}
+
+// jsonChildren returns an array's elements or an object's values, the
+// latter in key order.
+func jsonChildren(n any) []any {
+	switch v := n.(type) {
+	case []any:
+		return v
+	case map[string]any:
+		out := make([]any, 0, len(v))
+		for _, k := range slices.Sorted(maps.Keys(v)) {
+			out = append(out, v[k])
+		}
+		return out
+	}
+	return nil
+}
+
+func appendDescendants(out []any, n any) []any {
+	out = append(out, n)
+	for _, c := range jsonChildren(n) {
+		out = appendDescendants(out, c)
+	}
+	return out
+}
+
+func (f *jsonFilter) match(n any) bool {
+	vals := evalJSONPath([]any{n}, f.path)
+	if f.op == "" {
+		return len(vals) > 0
+	}
+	// As in RFC 9535, a missing field is unequal to everything.
+	if len(vals) == 0 {
+		return f.op == "!="
+	}
+	v := vals[0]
+	if a, ok := number(v); ok {
+		if b, ok := number(f.lit); ok {
+			return compare(f.op, a < b, a == b)
+		}
+	}
+	if a, ok := v.(string); ok {
+		if b, ok := f.lit.(string); ok {
+			return compare(f.op, a < b, a == b)
+		}
+	}
+	eq := String(v) == String(f.lit)
+	return (f.op == "==" && eq) || (f.op == "!=" && !eq)
+}
+
+func compare(op string, less, equal bool) bool {
+	switch op {
+	case "==":
+		return equal
+	case "!=":
+		return !equal
+	case "<":
+		return less
+	case "<=":
+		return less || equal
+	case ">":
+		return !less && !equal
+	default:
+		return !less
+	}
+}
+
+func number(v any) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	}
+	return 0, false
+}
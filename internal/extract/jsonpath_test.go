@@ -0,0 +1,62 @@
+package extract
+
+import (
+	"strings"
+	"testing"
+)
+
+const storeJSON = `{
+  "store": {
+    "book": [
+      {"id": 9007199254740993, "title": "Sayings", "price": 8.95, "tags": ["a"]},
+      {"id": 2, "title": "Sword", "price": 12.99, "isbn": "0-553"},
+      {"id": 3, "title": "Moby", "price": 8.99, "isbn": "0-395"}
+    ],
+    "bicycle": {"color": "red", "price": 19.95}
+  },
+  "csrf": "tok"
+}`
+
+func TestJSONPath(t *testing.T) {
+	doc, err := DecodeJSON(storeJSON)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := map[string]string{
+		"$.csrf":                              "tok",
+		"csrf":                                "tok",
+		"$['store']['bicycle'].color":         "red",
+		"$.store.book[0].id":                  "9007199254740993",
+		"$.store.book[-1].title":              "Moby",
+		"$.store.book[*].title":               "Sayings,Sword,Moby",
+		"$..isbn":                             "0-553,0-395",
+		"$.store.*.color":                     "red",
+		"$.store.book[?(@.price < 10)].title": "Sayings,Moby",
+		"$.store.book[?(@.isbn)].id":          "2,3",
+		"$.store.book[?(@.title == 'Sword')].price": "12.99",
+		`$..book[?(@.isbn != "0-553")].title`:       "Sayings,Moby",
+		"$.store.book[0].tags":                      `["a"]`,
+		"$.missing":                                 "",
+		"$.store.book[7]":                           "",
+	}
+	for path, want := range tests {
+		vals, err := JSONPath(doc, path)
+		if err != nil {
+			t.Errorf("%s: %v", path, err)
+			continue
+		}
+		got := make([]string, len(vals))
+		for i, v := range vals {
+			got[i] = String(v)
+		}
+		if strings.Join(got, ",") != want {
+			t.Errorf("%s = %q, want %q", path, strings.Join(got, ","), want)
+		}
+	}
+
+	for _, bad := range []string{"$.a[x]", "$.a[?(@.b == )]", "$.a[?(b)]", "$.a[", "$..", "$['a"} {
+		if _, err := JSONPath(doc, bad); err == nil {
+			t.Errorf("%s: expected error", bad)
+		}
+	}
+}
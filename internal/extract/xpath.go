@@ -0,0 +1,496 @@
+package extract
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+type xstepKind int
+
+const (
+	xElement xstepKind = iota // name or *
+	xText                     // text()
+	xNode                     // node()
+	xSelf                     // .
+	xParent                   // ..
+	xAttr                     // @name or @*
+)
+
+// xstep is one location step; descendant marks a step after "//".
+type xstep struct {
+	descendant bool
+	kind       xstepKind
+	name       string
+	preds      []xexpr
+}
+
+// XPath returns the nodes an XPath expression selects from doc, in document
+// order. Attribute steps (@name) select text nodes holding the attribute
+// value, parented to the element. Supported: absolute and relative paths
+// with / and //, name and * tests, text(), node(), ., .., @name, @*, and
+// predicates with positions, last(), position(), = and !=, and, or,
+// contains(), starts-with(), normalize-space(), and not().
+func XPath(doc *Node, expr string) ([]*Node, error) {
+	steps, err := parseXPath(expr)
+	if err != nil {
+		return nil, err
+	}
+	nodes := []*Node{doc}
+	for _, st := range steps {
+		if st.descendant {
+			var all []*Node
+			for _, n := range nodes {
+				all = appendSubtree(all, n)
+			}
+			nodes = all
+		}
+		var next []*Node
+		for _, n := range nodes {
+			next = append(next, st.apply(n)...)
+		}
+		nodes = documentOrder(next)
+	}
+	return nodes, nil
+}
+
+func appendSubtree(out []*Node, n *Node) []*Node {
+	out = append(out, n)
+	for _, c := range n.Children {
+		if c.Tag != "" {
+			out = appendSubtree(out, c)
+		}
+	}
+	return out
+}
+
+// documentOrder sorts nodes by position and drops duplicates.
+func documentOrder(nodes []*Node) []*Node {
+	slices.SortStableFunc(nodes, func(a, b *Node) int { return a.pos - b.pos })
+	out := nodes[:0]
+	seen := map[*Node]bool{}
+	for _, n := range nodes {
+		if !seen[n] {
+			seen[n] = true
+			out = append(out, n)
+		}
+	}
+	return out
+}
+
+// apply selects st's candidates from n and filters them through each
+// predicate in turn, positions counted within the candidates.
+func (st xstep) apply(n *Node) []*Node {
+	var cands []*Node
+	switch st.kind {
+	case xSelf:
+		cands = []*Node{n}
+	case xParent:
+		if n.Parent != nil {
+			cands = []*Node{n.Parent}
+		}
+	case xAttr:
+		for _, a := range n.Attrs {
+			if st.name == "*" || a.Name == st.name {
+				cands = append(cands, &Node{Text: a.Value, Parent: n, pos: n.pos})
+			}
+		}
+	default:
+		for _, c := range n.Children {
+			switch {
+			case st.kind == xNode,
+				st.kind == xText && c.Tag == "",
+				st.kind == xElement && c.Tag != "" && (st.name == "*" || st.name == c.Tag):
+				cands = append(cands, c)
+			}
+		}
+	}
+	for _, pred := range st.preds {
+		var kept []*Node
+		for i, c := range cands {
+			v := pred.eval(xcontext{c, i + 1, len(cands)})
+			if v.kind == xNumber {
+				if v.num == float64(i+1) {
+					kept = append(kept, c)
+				}
+			} else if v.boolean() {
+				kept = append(kept, c)
+			}
+		}
+		cands = kept
+	}
+	return cands
+}
+
+type xcontext struct {
+	node      *Node
+	pos, size int
+}
+
+type xvalueKind int
+
+const (
+	xString xvalueKind = iota
+	xNumber
+	xBool
+	xStrings // a node set, as string values
+)
+
+type xvalue struct {
+	kind xvalueKind
+	str  string
+	num  float64
+	b    bool
+	strs []string
+}
+
+func (v xvalue) boolean() bool {
+	switch v.kind {
+	case xNumber:
+		return v.num != 0
+	case xBool:
+		return v.b
+	case xStrings:
+		return len(v.strs) > 0
+	}
+	return v.str != ""
+}
+
+func (v xvalue) string() string {
+	switch v.kind {
+	case xNumber:
+		return strconv.FormatFloat(v.num, 'f', -1, 64)
+	case xBool:
+		return strconv.FormatBool(v.b)
+	case xStrings:
+		if len(v.strs) == 0 {
+			return ""
+		}
+		return v.strs[0]
+	}
+	return v.str
+}
+
+// values lists the strings v stands for in a comparison.
+func (v xvalue) values() []string {
+	if v.kind == xStrings {
+		return v.strs
+	}
+	return []string{v.string()}
+}
+
+// xexpr is a predicate expression.
+type xexpr interface {
+	eval(xcontext) xvalue
+}
+
+type (
+	xliteral struct{ v xvalue }
+	xbinary  struct {
+		op          string
+		left, right xexpr
+	}
+	xcall struct {
+		name string
+		args []xexpr
+	}
+	// xoperand is a relative path inside a predicate: @name, text(), ., or
+	// a child element name.
+	xoperand struct{ step xstep }
+)
+
+func (e xliteral) eval(xcontext) xvalue { return e.v }
+
+func (e xbinary) eval(c xcontext) xvalue {
+	switch e.op {
+	case "and":
+		return xvalue{kind: xBool, b: e.left.eval(c).boolean() && e.right.eval(c).boolean()}
+	case "or":
+		return xvalue{kind: xBool, b: e.left.eval(c).boolean() || e.right.eval(c).boolean()}
+	}
+	l, r := e.left.eval(c), e.right.eval(c)
+	match := false
+	for _, a := range l.values() {
+		for _, b := range r.values() {
+			if (a == b) == (e.op == "=") {
+				match = true
+			}
+		}
+	}
+	return xvalue{kind: xBool, b: match}
+}
+
+func (e xoperand) eval(c xcontext) xvalue {
+	v := xvalue{kind: xStrings}
+	for _, n := range e.step.apply(c.node) {
+		if n.Tag == "" {
+			v.strs = append(v.strs, n.Text)
+		} else {
+			v.strs = append(v.strs, n.TextContent())
+		}
+	}
+	return v
+}
+
+func (e xcall) eval(c xcontext) xvalue {
+	arg := func(i int) xvalue { return e.args[i].eval(c) }
+	switch e.name {
+	case "last":
+		return xvalue{kind: xNumber, num: float64(c.size)}
+	case "position":
+		return xvalue{kind: xNumber, num: float64(c.pos)}
+	case "not":
+		return xvalue{kind: xBool, b: !arg(0).boolean()}
+	case "normalize-space":
+		s := c.node.TextContent()
+		if len(e.args) > 0 {
+			s = strings.Join(strings.Fields(arg(0).string()), " ")
+		}
+		return xvalue{kind: xString, str: s}
+	}
+	hay, needle := arg(0), arg(1).string()
+	for _, s := range hay.values() {
+		if (e.name == "contains" && strings.Contains(s, needle)) || (e.name == "starts-with" && strings.HasPrefix(s, needle)) {
+			return xvalue{kind: xBool, b: true}
+		}
+	}
+	return xvalue{kind: xBool}
+}
+
+// xpathFuncs maps supported functions to their argument counts; -1 is 0 or 1.
+var xpathFuncs = map[string]int{"last": 0, "position": 0, "not": 1, "normalize-space": -1, "contains": 2, "starts-with": 2}
+
+// xpathParser parses over tokens: punctuation, names, and quoted strings
+// (kept with their quote).
+type xpathParser struct {
+	expr string
+	toks []string
+	i    int
+}
+
+func parseXPath(expr string) ([]xstep, error) {
+	toks, err := xpathTokens(expr)
+	if err != nil {
+		return nil, err
+	}
+	p := &xpathParser{expr: expr, toks: toks}
+	if len(toks) == 0 {
+		return nil, p.errorf("empty expression")
+	}
+	var steps []xstep
+	for first := true; p.i < len(p.toks); first = false {
+		descendant := false
+		switch p.peek() {
+		case "/":
+			p.i++
+		case "//":
+			p.i++
+			descendant = true
+		default:
+			if !first {
+				return nil, p.errorf("expected / before %q", p.peek())
+			}
+		}
+		st, err := p.step()
+		if err != nil {
+			return nil, err
+		}
+		st.descendant = descendant
+		if len(steps) > 0 && steps[len(steps)-1].kind == xAttr {
+			return nil, p.errorf("nothing can follow an attribute step")
+		}
+		steps = append(steps, st)
+	}
+	return steps, nil
+}
+
+func (p *xpathParser) peek() string {
+	if p.i < len(p.toks) {
+		return p.toks[p.i]
+	}
+	return ""
+}
+
+func (p *xpathParser) next() string {
+	t := p.peek()
+	p.i++
+	return t
+}
+
+func (p *xpathParser) step() (xstep, error) {
+	var st xstep
+	switch t := p.next(); {
+	case t == ".":
+		st.kind = xSelf
+	case t == "..":
+		st.kind = xParent
+	case t == "@":
+		st.kind, st.name = xAttr, strings.ToLower(p.next())
+		if st.name != "*" && !isXPathName(st.name) {
+			return st, p.errorf("expected attribute name after @")
+		}
+	case t == "*":
+		st.name = "*"
+	case isXPathName(t):
+		if p.peek() == "(" {
+			p.i++
+			if p.next() != ")" {
+				return st, p.errorf("expected ) after %s(", t)
+			}
+			switch t {
+			case "text":
+				st.kind = xText
+			case "node":
+				st.kind = xNode
+			default:
+				return st, p.errorf("unsupported node test %s()", t)
+			}
+			break
+		}
+		st.name = strings.ToLower(t)
+	default:
+		return st, p.errorf("unexpected %q", t)
+	}
+	for p.peek() == "[" {
+		p.i++
+		e, err := p.or()
+		if err != nil {
+			return st, err
+		}
+		if p.next() != "]" {
+			return st, p.errorf("expected ]")
+		}
+		st.preds = append(st.preds, e)
+	}
+	return st, nil
+}
+
+func (p *xpathParser) or() (xexpr, error) {
+	left, err := p.and()
+	for err == nil && p.peek() == "or" {
+		p.i++
+		var right xexpr
+		right, err = p.and()
+		left = xbinary{op: "or", left: left, right: right}
+	}
+	return left, err
+}
+
+func (p *xpathParser) and() (xexpr, error) {
+	left, err := p.compare()
+	for err == nil && p.peek() == "and" {
+		p.i++
+		var right xexpr
+		right, err = p.compare()
+		left = xbinary{op: "and", left: left, right: right}
+	}
+	return left, err
+}
+
+func (p *xpathParser) compare() (xexpr, error) {
+	left, err := p.operand()
+	if err != nil {
+		return nil, err
+	}
+	if op := p.peek(); op == "=" || op == "!=" {
+		p.i++
+		right, err := p.operand()
+		if err != nil {
+			return nil, err
+		}
+		return xbinary{op: op, left: left, right: right}, nil
+	}
+	return left, nil
+}
+
+func (p *xpathParser) operand() (xexpr, error) {
+	t := p.peek()
+	switch {
+	case t == "(":
+		p.i++
+		e, err := p.or()
+		if err == nil && p.next() != ")" {
+			err = p.errorf("expected )")
+		}
+		return e, err
+	case strings.HasPrefix(t, `"`) || strings.HasPrefix(t, "'"):
+		p.i++
+		return xliteral{xvalue{kind: xString, str: t[1 : len(t)-1]}}, nil
+	case t != "" && t[0] >= '0' && t[0] <= '9':
+		p.i++
+		n, err := strconv.ParseFloat(t, 64)
+		if err != nil {
+			return nil, p.errorf("bad number %q", t)
+		}
+		return xliteral{xvalue{kind: xNumber, num: n}}, nil
+	}
+	if argc, ok := xpathFuncs[t]; ok && p.i+1 < len(p.toks) && p.toks[p.i+1] == "(" {
+		p.i += 2
+		call := xcall{name: t}
+		for p.peek() != ")" {
+			if len(call.args) > 0 && p.next() != "," {
+				return nil, p.errorf("expected , in %s()", t)
+			}
+			a, err := p.or()
+			if err != nil {
+				return nil, err
+			}
+			call.args = append(call.args, a)
+		}
+		p.i++
+		if (argc >= 0 && len(call.args) != argc) || (argc < 0 && len(call.args) > 1) {
+			return nil, p.errorf("wrong number of arguments to %s()", t)
+		}
+		return call, nil
+	}
+	st, err := p.step()
+	if err != nil {
+		return nil, err
+	}
+	return xoperand{st}, nil
+}
+
+func (p *xpathParser) errorf(format string, args ...any) error {
+	return fmt.Errorf("xpath: %s in %q", fmt.Sprintf(format, args...), p.expr)
+}
+
+func isXPathName(t string) bool {
+	return t != "" && (isLetter(t[0]) || t[0] == '_' || t[0] >= 0x80)
+}
+
+func xpathTokens(expr string) ([]string, error) {
+	var toks []string
+	for i := 0; i < len(expr); {
+		c := expr[i]
+		switch {
+		case isSpace(c):
+			i++
+		case c == '"' || c == '\'':
+			end := strings.IndexByte(expr[i+1:], c)
+			if end < 0 {
+				return nil, fmt.Errorf("xpath: unterminated string in %q", expr)
+			}
+			toks = append(toks, expr[i:i+end+2])
+			i += end + 2
+		case strings.HasPrefix(expr[i:], "//"), strings.HasPrefix(expr[i:], ".."), strings.HasPrefix(expr[i:], "!="):
+			toks = append(toks, expr[i:i+2])
+			i += 2
+		case strings.IndexByte("/[]()@,=*.", c) >= 0:
+			toks = append(toks, expr[i:i+1])
+			i++
+		default:
+			j := i
+			for j < len(expr) && (isLetter(expr[j]) || expr[j] >= '0' && expr[j] <= '9' || expr[j] == '-' || expr[j] == '_' || expr[j] >= 0x80 ||
+				(expr[j] == '.' && j > i && expr[i] >= '0' && expr[i] <= '9')) {
+				j++
+			}
+			if j == i {
+				return nil, fmt.Errorf("xpath: unexpected %q in %q", c, expr)
+			}
+			toks = append(toks, expr[i:j])
+			i = j
+		}
+	}
+	return toks, nil
+}
@@ -0,0 +1,98 @@
+package graphql
+
+import (
+	"fmt"
+	"strings"
+)
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokPunct
+	tokName
+	tokNumber
+	tokString
+)
+
+type token struct {
+	kind tokenKind
+	text string // strings keep their quotes
+	pos  int
+}
+
+// is reports whether t is the punctuator or name s.
+func (t token) is(s string) bool {
+	return (t.kind == tokPunct || t.kind == tokName) && t.text == s
+}
+
+type lexer struct {
+	src string
+	pos int
+}
+
+func (l *lexer) next() (token, error) {
+	// Commas are insignificant in GraphQL, like whitespace.
+	for l.pos < len(l.src) {
+		c := l.src[l.pos]
+		if c == '#' {
+			for l.pos < len(l.src) && l.src[l.pos] != '\n' {
+				l.pos++
+			}
+			continue
+		}
+		if c != ' ' && c != '\t' && c != '\n' && c != '\r' && c != ',' {
+			break
+		}
+		l.pos++
+	}
+	start := l.pos
+	if l.pos >= len(l.src) {
+		return token{kind: tokEOF, pos: start}, nil
+	}
+
+	c := l.src[l.pos]
+	switch {
+	case strings.HasPrefix(l.src[l.pos:], "..."):
+		l.pos += 3
+		return token{kind: tokPunct, text: "...", pos: start}, nil
+	case strings.ContainsRune("!$&():=@[]{}|", rune(c)):
+		l.pos++
+		return token{kind: tokPunct, text: string(c), pos: start}, nil
+	case c == '_' || isLetter(c):
+		for l.pos < len(l.src) && (l.src[l.pos] == '_' || isLetter(l.src[l.pos]) || isDigit(l.src[l.pos])) {
+			l.pos++
+		}
+		return token{kind: tokName, text: l.src[start:l.pos], pos: start}, nil
+	case c == '-' || isDigit(c):
+		l.pos++
+		for l.pos < len(l.src) && (isDigit(l.src[l.pos]) || strings.ContainsRune(".eE+-", rune(l.src[l.pos]))) {
+			l.pos++
+		}
+		return token{kind: tokNumber, text: l.src[start:l.pos], pos: start}, nil
+	case strings.HasPrefix(l.src[l.pos:], `"""`):
+		end := strings.Index(l.src[l.pos+3:], `"""`)
+		if end < 0 {
+			return token{}, fmt.Errorf("offset %d: unterminated block string", start)
+		}
+		l.pos += 3 + end + 3
+		return token{kind: tokString, text: l.src[start:l.pos], pos: start}, nil
+	case c == '"':
+		l.pos++
+		for l.pos < len(l.src) && l.src[l.pos] != '"' && l.src[l.pos] != '\n' {
+			if l.src[l.pos] == '\\' {
+				l.pos++
+			}
+			l.pos++
+		}
+		if l.pos >= len(l.src) || l.src[l.pos] != '"' {
+			return token{}, fmt.Errorf("offset %d: unterminated string", start)
+		}
+		l.pos++
+		return token{kind: tokString, text: l.src[start:l.pos], pos: start}, nil
+	}
+	return token{}, fmt.Errorf("offset %d: unexpected character %q", start, c)
+}
+
+func isLetter(c byte) bool { return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' }
+func isDigit(c byte) bool  { return c >= '0' && c <= '9' }
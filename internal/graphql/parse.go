@@ -0,0 +1,425 @@
+// Package graphql parses GraphQL documents and introspection results into
+// the shapes an attacker cares about: operations, selections, variables,
+// and a condensed schema to build queries from.
+package graphql
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Document is a parsed GraphQL document.
+type Document struct {
+	Operations []Operation
+	Fragments  []Fragment
+}
+
+// Operation is a query, mutation, or subscription.
+type Operation struct {
+	Type       string
+	Name       string
+	Variables  []string
+	Directives []string
+	Selections []Selection
+}
+
+// Fragment is a named fragment definition.
+type Fragment struct {
+	Name       string
+	On         string
+	Selections []Selection
+}
+
+// Selection is a field, a fragment spread ("...Name"), or an inline
+// fragment ("... on Type").
+type Selection struct {
+	Name       string
+	Alias      string
+	Arguments  []string
+	Directives []string
+	Selections []Selection
+}
+
+// Stats counts what matters for complexity and batching attacks.
+type Stats struct {
+	Depth   int `json:"depth"`
+	Fields  int `json:"fields"`
+	Aliases int `json:"aliases"`
+}
+
+// Stats returns the document's deepest selection nesting and its field and
+// alias counts, not following fragment spreads.
+func (d *Document) Stats() Stats {
+	var s Stats
+	var walk func(sels []Selection, depth int)
+	walk = func(sels []Selection, depth int) {
+		for _, sel := range sels {
+			if !strings.HasPrefix(sel.Name, "...") {
+				s.Fields++
+				s.Depth = max(s.Depth, depth)
+			}
+			if sel.Alias != "" {
+				s.Aliases++
+			}
+			walk(sel.Selections, depth+1)
+		}
+	}
+	for _, op := range d.Operations {
+		walk(op.Selections, 1)
+	}
+	for _, f := range d.Fragments {
+		walk(f.Selections, 1)
+	}
+	return s
+}
+
+// FieldPaths flattens a selection tree into dotted paths, one per
+// selection, such as "user(id: $id).posts.title" or "a: user.name".
+func FieldPaths(sels []Selection) []string {
+	var out []string
+	var walk func(sels []Selection, prefix string)
+	walk = func(sels []Selection, prefix string) {
+		for _, sel := range sels {
+			label := sel.Name
+			if sel.Alias != "" {
+				label = sel.Alias + ": " + label
+			}
+			if len(sel.Arguments) > 0 {
+				label += "(" + strings.Join(sel.Arguments, ", ") + ")"
+			}
+			for _, d := range sel.Directives {
+				label += " " + d
+			}
+			out = append(out, prefix+label)
+			walk(sel.Selections, prefix+label+".")
+		}
+	}
+	walk(sels, "")
+	return out
+}
+
+// Parse parses a GraphQL executable document. Type system definitions
+// aren't supported; they never appear in requests.
+func Parse(query string) (*Document, error) {
+	p := &parser{lex: lexer{src: strings.TrimPrefix(query, "\ufeff")}}
+	p.next()
+	doc := &Document{}
+	for p.tok.kind != tokEOF {
+		switch {
+		case p.tok.is("{"):
+			sels, err := p.selectionSet()
+			if err != nil {
+				return doc, err
+			}
+			doc.Operations = append(doc.Operations, Operation{Type: "query", Selections: sels})
+		case p.tok.is("query"), p.tok.is("mutation"), p.tok.is("subscription"):
+			op, err := p.operation()
+			if err != nil {
+				return doc, err
+			}
+			doc.Operations = append(doc.Operations, op)
+		case p.tok.is("fragment"):
+			f, err := p.fragment()
+			if err != nil {
+				return doc, err
+			}
+			doc.Fragments = append(doc.Fragments, f)
+		default:
+			return doc, p.errorf("expected an operation or fragment")
+		}
+	}
+	return doc, nil
+}
+
+type parser struct {
+	lex lexer
+	tok token
+	err error
+}
+
+func (p *parser) next() {
+	if p.err != nil {
+		p.tok = token{kind: tokEOF}
+		return
+	}
+	p.tok, p.err = p.lex.next()
+}
+
+func (p *parser) errorf(format string, args ...any) error {
+	if p.err != nil {
+		return p.err
+	}
+	return fmt.Errorf("offset %d: %s, got %q", p.tok.pos, fmt.Sprintf(format, args...), p.tok.text)
+}
+
+// expect consumes the punctuator or keyword s.
+func (p *parser) expect(s string) error {
+	if !p.tok.is(s) {
+		return p.errorf("expected %q", s)
+	}
+	p.next()
+	return nil
+}
+
+func (p *parser) name() (string, error) {
+	if p.tok.kind != tokName {
+		return "", p.errorf("expected a name")
+	}
+	n := p.tok.text
+	p.next()
+	return n, nil
+}
+
+func (p *parser) operation() (Operation, error) {
+	op := Operation{Type: p.tok.text}
+	p.next()
+	if p.tok.kind == tokName {
+		op.Name = p.tok.text
+		p.next()
+	}
+	if p.tok.is("(") {
+		p.next()
+		for !p.tok.is(")") {
+			if err := p.expect("$"); err != nil {
+				return op, err
+			}
+			name, err := p.name()
+			if err != nil {
+				return op, err
+			}
+			if err := p.expect(":"); err != nil {
+				return op, err
+			}
+			typ, err := p.typeRef()
+			if err != nil {
+				return op, err
+			}
+			def := "$" + name + ": " + typ
+			if p.tok.is("=") {
+				p.next()
+				v, err := p.value()
+				if err != nil {
+					return op, err
+				}
+				def += " = " + v
+			}
+			if _, err := p.directives(); err != nil {
+				return op, err
+			}
+			op.Variables = append(op.Variables, def)
+		}
+		p.next()
+	}
+	var err error
+	if op.Directives, err = p.directives(); err != nil {
+		return op, err
+	}
+	op.Selections, err = p.selectionSet()
+	return op, err
+}
+
+func (p *parser) fragment() (Fragment, error) {
+	p.next()
+	var f Fragment
+	var err error
+	if f.Name, err = p.name(); err != nil {
+		return f, err
+	}
+	if err := p.expect("on"); err != nil {
+		return f, err
+	}
+	if f.On, err = p.name(); err != nil {
+		return f, err
+	}
+	if _, err := p.directives(); err != nil {
+		return f, err
+	}
+	f.Selections, err = p.selectionSet()
+	return f, err
+}
+
+func (p *parser) selectionSet() ([]Selection, error) {
+	if err := p.expect("{"); err != nil {
+		return nil, err
+	}
+	sels := []Selection{}
+	for !p.tok.is("}") {
+		if p.tok.kind == tokEOF {
+			return sels, p.errorf("unclosed selection set")
+		}
+		sel, err := p.selection()
+		if err != nil {
+			return sels, err
+		}
+		sels = append(sels, sel)
+	}
+	p.next()
+	return sels, nil
+}
+
+func (p *parser) selection() (Selection, error) {
+	var sel Selection
+	var err error
+	if p.tok.is("...") {
+		p.next()
+		switch {
+		case p.tok.is("on"):
+			p.next()
+			typ, err := p.name()
+			if err != nil {
+				return sel, err
+			}
+			sel.Name = "... on " + typ
+		case p.tok.kind == tokName:
+			sel.Name = "..." + p.tok.text
+			p.next()
+			sel.Directives, err = p.directives()
+			return sel, err
+		default:
+			// Inline fragment without a type condition.
+			sel.Name = "..."
+		}
+		if sel.Directives, err = p.directives(); err != nil {
+			return sel, err
+		}
+		sel.Selections, err = p.selectionSet()
+		return sel, err
+	}
+
+	if sel.Name, err = p.name(); err != nil {
+		return sel, err
+	}
+	if p.tok.is(":") {
+		p.next()
+		sel.Alias = sel.Name
+		if sel.Name, err = p.name(); err != nil {
+			return sel, err
+		}
+	}
+	if sel.Arguments, err = p.arguments(); err != nil {
+		return sel, err
+	}
+	if sel.Directives, err = p.directives(); err != nil {
+		return sel, err
+	}
+	if p.tok.is("{") {
+		sel.Selections, err = p.selectionSet()
+	}
+	return sel, err
+}
+
+// arguments parses an optional "(name: value, ...)" list into "name: value" strings.
+func (p *parser) arguments() ([]string, error) {
+	if !p.tok.is("(") {
+		return nil, nil
+	}
+	p.next()
+	var args []string
+	for !p.tok.is(")") {
+		name, err := p.name()
+		if err != nil {
+			return args, err
+		}
+		if err := p.expect(":"); err != nil {
+			return args, err
+		}
+		v, err := p.value()
+		if err != nil {
+			return args, err
+		}
+		args = append(args, name+": "+v)
+	}
+	p.next()
+	return args, nil
+}
+
+func (p *parser) directives() ([]string, error) {
+	var dirs []string
+	for p.tok.is("@") {
+		p.next()
+		name, err := p.name()
+		if err != nil {
+			return dirs, err
+		}
+		args, err := p.arguments()
+		if err != nil {
+			return dirs, err
+		}
+		d := "@" + name
+		if len(args) > 0 {
+			d += "(" + strings.Join(args, ", ") + ")"
+		}
+		dirs = append(dirs, d)
+	}
+	return dirs, nil
+}
+
+// typeRef parses a type reference such as [ID!]! and returns it as written.
+func (p *parser) typeRef() (string, error) {
+	var t string
+	if p.tok.is("[") {
+		p.next()
+		inner, err := p.typeRef()
+		if err != nil {
+			return "", err
+		}
+		if err := p.expect("]"); err != nil {
+			return "", err
+		}
+		t = "[" + inner + "]"
+	} else {
+		name, err := p.name()
+		if err != nil {
+			return "", err
+		}
+		t = name
+	}
+	if p.tok.is("!") {
+		p.next()
+		t += "!"
+	}
+	return t, nil
+}
+
+// value parses a literal, variable, list, or object and returns it as text.
+func (p *parser) value() (string, error) {
+	switch {
+	case p.tok.is("$"):
+		p.next()
+		name, err := p.name()
+		return "$" + name, err
+	case p.tok.is("["), p.tok.is("{"):
+		open := p.tok.text
+		closer := map[string]string{"[": "]", "{": "}"}[open]
+		p.next()
+		var items []string
+		for !p.tok.is(closer) {
+			if p.tok.kind == tokEOF {
+				return "", p.errorf("unclosed %s", open)
+			}
+			item := ""
+			if open == "{" {
+				name, err := p.name()
+				if err != nil {
+					return "", err
+				}
+				if err := p.expect(":"); err != nil {
+					return "", err
+				}
+				item = name + ": "
+			}
+			v, err := p.value()
+			if err != nil {
+				return "", err
+			}
+			items = append(items, item+v)
+		}
+		p.next()
+		return open + strings.Join(items, ", ") + closer, nil
+	case p.tok.kind == tokName, p.tok.kind == tokNumber, p.tok.kind == tokString:
+		v := p.tok.text
+		p.next()
+		return v, nil
+	}
+	return "", p.errorf("expected a value")
+}
@@ -0,0 +1,92 @@
+package graphql
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	doc, err := Parse(`
+# fetch a user
+query GetUser($id: ID!, $first: Int = 10) @cached {
+  a: user(id: $id) {
+    name
+    posts(first: $first, filter: {tags: ["x", "y"]}) { title }
+    ...UserExtra
+    ... on Admin @include(if: true) { permissions }
+  }
+}
+fragment UserExtra on User { email }`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(doc.Operations) != 1 || len(doc.Fragments) != 1 {
+		t.Fatalf("got %d operations, %d fragments", len(doc.Operations), len(doc.Fragments))
+	}
+	op := doc.Operations[0]
+	if op.Type != "query" || op.Name != "GetUser" {
+		t.Errorf("operation = %s %s", op.Type, op.Name)
+	}
+	if want := []string{"$id: ID!", "$first: Int = 10"}; !slices.Equal(op.Variables, want) {
+		t.Errorf("variables = %q, want %q", op.Variables, want)
+	}
+	if want := []string{"@cached"}; !slices.Equal(op.Directives, want) {
+		t.Errorf("directives = %q", op.Directives)
+	}
+
+	want := []string{
+		"a: user(id: $id)",
+		"a: user(id: $id).name",
+		`a: user(id: $id).posts(first: $first, filter: {tags: ["x", "y"]})`,
+		`a: user(id: $id).posts(first: $first, filter: {tags: ["x", "y"]}).title`,
+		"a: user(id: $id)....UserExtra",
+		"a: user(id: $id).... on Admin @include(if: true)",
+		"a: user(id: $id).... on Admin @include(if: true).permissions",
+	}
+	if got := FieldPaths(op.Selections); !slices.Equal(got, want) {
+		t.Errorf("paths =\n%q\nwant\n%q", got, want)
+	}
+
+	if got := doc.Stats(); got != (Stats{Depth: 3, Fields: 6, Aliases: 1}) {
+		t.Errorf("stats = %+v", got)
+	}
+	if f := doc.Fragments[0]; f.Name != "UserExtra" || f.On != "User" {
+		t.Errorf("fragment = %+v", f)
+	}
+}
+
+func TestParse_Shorthand(t *testing.T) {
+	doc, err := Parse(`{ a: me { id } b: me { id } }`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(doc.Operations) != 1 || doc.Operations[0].Type != "query" {
+		t.Fatalf("operations = %+v", doc.Operations)
+	}
+	if got := doc.Stats().Aliases; got != 2 {
+		t.Errorf("aliases = %d, want 2", got)
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	for _, q := range []string{
+		`query { user(id: 1) { name }`,
+		`query { user(id: ) }`,
+		`{ name(s: "unterminated) }`,
+		`type User { id: ID }`,
+	} {
+		if _, err := Parse(q); err == nil {
+			t.Errorf("Parse(%q) succeeded", q)
+		}
+	}
+}
+
+func TestParse_PartialDocument(t *testing.T) {
+	doc, err := Parse(`query A { a } query B { b`)
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if len(doc.Operations) != 1 || doc.Operations[0].Name != "A" {
+		t.Errorf("operations = %+v", doc.Operations)
+	}
+}
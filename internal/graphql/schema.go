@@ -0,0 +1,304 @@
+package graphql
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// IntrospectionQuery asks for every type with its fields, arguments, and
+// input fields: enough to condense the schema and build queries, without
+// descriptions or directives to keep the response small.
+const IntrospectionQuery = `query IntrospectionQuery {
+  __schema {
+    queryType { name }
+    mutationType { name }
+    subscriptionType { name }
+    types {
+      kind name
+      fields(includeDeprecated: true) { name args { name type { ...TypeRef } } type { ...TypeRef } }
+      inputFields { name type { ...TypeRef } }
+      enumValues(includeDeprecated: true) { name }
+      possibleTypes { name }
+    }
+  }
+}
+fragment TypeRef on __Type {
+  kind name ofType { kind name ofType { kind name ofType { kind name ofType { kind name } } } }
+}`
+
+// typeRef is an introspection type reference, wrapped in LIST and NON_NULL.
+type typeRef struct {
+	Kind   string   `json:"kind"`
+	Name   string   `json:"name"`
+	OfType *typeRef `json:"ofType"`
+}
+
+// String renders the reference as GraphQL type syntax, e.g. [ID!]!.
+func (t *typeRef) String() string {
+	if t == nil {
+		return "?"
+	}
+	switch t.Kind {
+	case "NON_NULL":
+		return t.OfType.String() + "!"
+	case "LIST":
+		return "[" + t.OfType.String() + "]"
+	}
+	return t.Name
+}
+
+// named unwraps LIST and NON_NULL to the underlying named type.
+func (t *typeRef) named() *typeRef {
+	for t != nil && t.OfType != nil && (t.Kind == "NON_NULL" || t.Kind == "LIST") {
+		t = t.OfType
+	}
+	return t
+}
+
+type inputValue struct {
+	Name string   `json:"name"`
+	Type *typeRef `json:"type"`
+}
+
+type field struct {
+	Name string       `json:"name"`
+	Args []inputValue `json:"args"`
+	Type *typeRef     `json:"type"`
+}
+
+// signature renders the field as name(arg: Type, ...): Type.
+func (f field) signature() string {
+	s := f.Name
+	if len(f.Args) > 0 {
+		args := make([]string, len(f.Args))
+		for i, a := range f.Args {
+			args[i] = a.Name + ": " + a.Type.String()
+		}
+		s += "(" + strings.Join(args, ", ") + ")"
+	}
+	return s + ": " + f.Type.String()
+}
+
+type fullType struct {
+	Kind          string                  `json:"kind"`
+	Name          string                  `json:"name"`
+	Fields        []field                 `json:"fields"`
+	InputFields   []inputValue            `json:"inputFields"`
+	EnumValues    []struct{ Name string } `json:"enumValues"`
+	PossibleTypes []struct{ Name string } `json:"possibleTypes"`
+}
+
+type introspectionResponse struct {
+	Data *struct {
+		Schema *struct {
+			QueryType        *struct{ Name string } `json:"queryType"`
+			MutationType     *struct{ Name string } `json:"mutationType"`
+			SubscriptionType *struct{ Name string } `json:"subscriptionType"`
+			Types            []fullType             `json:"types"`
+		} `json:"__schema"`
+	} `json:"data"`
+	Errors []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+// TypeSummary is one user-defined type in a condensed schema.
+type TypeSummary struct {
+	Name   string   `json:"name"`
+	Kind   string   `json:"kind"`
+	Fields []string `json:"fields,omitempty"`
+}
+
+// Schema is a condensed introspection result.
+type Schema struct {
+	Queries       []string      `json:"queries,omitempty"`
+	Mutations     []string      `json:"mutations,omitempty"`
+	Subscriptions []string      `json:"subscriptions,omitempty"`
+	Types         []TypeSummary `json:"types,omitempty"`
+
+	types     map[string]*fullType
+	queryType string
+	mutType   string
+}
+
+// ErrIntrospectionDisabled means the server answered without a schema.
+var ErrIntrospectionDisabled = errors.New("introspection disabled")
+
+// ParseIntrospection condenses an introspection response body.
+func ParseIntrospection(body []byte) (*Schema, error) {
+	var resp introspectionResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("not a GraphQL response: %w", err)
+	}
+	if resp.Data == nil || resp.Data.Schema == nil {
+		var msgs []string
+		for _, e := range resp.Errors {
+			msgs = append(msgs, e.Message)
+		}
+		if len(msgs) == 0 {
+			return nil, ErrIntrospectionDisabled
+		}
+		return nil, fmt.Errorf("%w: %s", ErrIntrospectionDisabled, strings.Join(msgs, "; "))
+	}
+
+	raw := resp.Data.Schema
+	s := &Schema{types: make(map[string]*fullType)}
+	for i := range raw.Types {
+		s.types[raw.Types[i].Name] = &raw.Types[i]
+	}
+	root := func(r *struct{ Name string }) string {
+		if r == nil {
+			return ""
+		}
+		return r.Name
+	}
+	s.queryType, s.mutType = root(raw.QueryType), root(raw.MutationType)
+	subType := root(raw.SubscriptionType)
+	s.Queries = s.signatures(s.queryType)
+	s.Mutations = s.signatures(s.mutType)
+	s.Subscriptions = s.signatures(subType)
+
+	for _, t := range raw.Types {
+		if strings.HasPrefix(t.Name, "__") || t.Kind == "SCALAR" && builtinScalars[t.Name] ||
+			t.Name == s.queryType || t.Name == s.mutType || t.Name == subType {
+			continue
+		}
+		ts := TypeSummary{Name: t.Name, Kind: t.Kind}
+		for _, f := range t.Fields {
+			ts.Fields = append(ts.Fields, f.signature())
+		}
+		for _, f := range t.InputFields {
+			ts.Fields = append(ts.Fields, f.Name+": "+f.Type.String())
+		}
+		for _, v := range t.EnumValues {
+			ts.Fields = append(ts.Fields, v.Name)
+		}
+		for _, v := range t.PossibleTypes {
+			ts.Fields = append(ts.Fields, v.Name)
+		}
+		s.Types = append(s.Types, ts)
+	}
+	return s, nil
+}
+
+var builtinScalars = map[string]bool{"String": true, "Int": true, "Float": true, "Boolean": true, "ID": true}
+
+func (s *Schema) signatures(typeName string) []string {
+	t := s.types[typeName]
+	if t == nil {
+		return nil
+	}
+	out := make([]string, len(t.Fields))
+	for i, f := range t.Fields {
+		out[i] = f.signature()
+	}
+	return out
+}
+
+// sensitiveName matches field names worth a closer look.
+var sensitiveName = regexp.MustCompile(`(?i)passw|secret|token|api_?key|private|ssn|credit|card|salary|admin|role|permission|internal|debug|hash|salt|otp|mfa|reset|impersonat`)
+
+// Interesting lists fields and mutations whose names suggest sensitive data
+// or privileged actions, as Type.field (or mutation.field).
+func (s *Schema) Interesting() []string {
+	var out []string
+	for _, name := range slices.Sorted(maps.Keys(s.types)) {
+		t := s.types[name]
+		if strings.HasPrefix(name, "__") {
+			continue
+		}
+		label := name
+		if name == s.mutType {
+			label = "mutation"
+		} else if name == s.queryType {
+			label = "query"
+		}
+		for _, f := range t.Fields {
+			if sensitiveName.MatchString(f.Name) {
+				out = append(out, label+"."+f.Name)
+			}
+		}
+	}
+	return out
+}
+
+// SuggestQuery builds an operation that calls the root query or mutation
+// field fieldName, passes every argument as a variable, and selects every
+// field without required arguments, depth levels deep. It's a starting point for IDOR and
+// data-exposure testing: fill in the variables and send.
+func (s *Schema) SuggestQuery(fieldName string, depth int) (string, error) {
+	opType, f := "query", s.rootField(s.queryType, fieldName)
+	if f == nil {
+		opType, f = "mutation", s.rootField(s.mutType, fieldName)
+	}
+	if f == nil {
+		return "", fmt.Errorf("no root query or mutation field %q", fieldName)
+	}
+
+	var sb strings.Builder
+	sb.WriteString(opType + " Suggested")
+	if len(f.Args) > 0 {
+		vars := make([]string, len(f.Args))
+		args := make([]string, len(f.Args))
+		for i, a := range f.Args {
+			vars[i] = "$" + a.Name + ": " + a.Type.String()
+			args[i] = a.Name + ": $" + a.Name
+		}
+		sb.WriteString("(" + strings.Join(vars, ", ") + ")")
+		sb.WriteString(" {\n  " + f.Name + "(" + strings.Join(args, ", ") + ")")
+	} else {
+		sb.WriteString(" {\n  " + f.Name)
+	}
+	s.writeSelection(&sb, f.Type, depth, "  ")
+	sb.WriteString("\n}")
+	return sb.String(), nil
+}
+
+func (s *Schema) rootField(typeName, name string) *field {
+	if t := s.types[typeName]; t != nil {
+		for i := range t.Fields {
+			if t.Fields[i].Name == name {
+				return &t.Fields[i]
+			}
+		}
+	}
+	return nil
+}
+
+// writeSelection writes the selection set for a value of type ref, if it
+// needs one.
+func (s *Schema) writeSelection(sb *strings.Builder, ref *typeRef, depth int, indent string) {
+	t := s.types[ref.named().Name]
+	if t == nil || t.Kind == "SCALAR" || t.Kind == "ENUM" {
+		return
+	}
+	sb.WriteString(" {")
+	inner := indent + "  "
+	wrote := false
+	if t.Kind == "OBJECT" || t.Kind == "INTERFACE" {
+		for _, f := range t.Fields {
+			if len(f.Args) > 0 && slices.ContainsFunc(f.Args, func(a inputValue) bool { return a.Type.Kind == "NON_NULL" }) {
+				continue
+			}
+			nt := s.types[f.Type.named().Name]
+			leaf := nt == nil || nt.Kind == "SCALAR" || nt.Kind == "ENUM"
+			if !leaf && depth <= 1 {
+				continue
+			}
+			sb.WriteString("\n" + inner + f.Name)
+			if !leaf {
+				s.writeSelection(sb, f.Type, depth-1, inner)
+			}
+			wrote = true
+		}
+	}
+	if !wrote {
+		sb.WriteString("\n" + inner + "__typename")
+	}
+	sb.WriteString("\n" + indent + "}")
+}
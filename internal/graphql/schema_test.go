@@ -0,0 +1,108 @@
+package graphql
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func named(kind, name string) string {
+	return `{"kind":"` + kind + `","name":"` + name + `","ofType":null}`
+}
+
+func nonNull(inner string) string {
+	return `{"kind":"NON_NULL","name":null,"ofType":` + inner + `}`
+}
+
+func listOf(inner string) string {
+	return `{"kind":"LIST","name":null,"ofType":` + inner + `}`
+}
+
+var introspectionFixture = `{"data":{"__schema":{
+  "queryType":{"name":"Query"},"mutationType":{"name":"Mutation"},"subscriptionType":null,
+  "types":[
+    {"kind":"OBJECT","name":"Query","fields":[
+      {"name":"user","args":[{"name":"id","type":` + nonNull(named("SCALAR", "ID")) + `}],"type":` + named("OBJECT", "User") + `},
+      {"name":"me","args":[],"type":` + named("OBJECT", "User") + `}]},
+    {"kind":"OBJECT","name":"Mutation","fields":[
+      {"name":"resetPassword","args":[{"name":"email","type":` + nonNull(named("SCALAR", "String")) + `}],"type":` + named("SCALAR", "Boolean") + `}]},
+    {"kind":"OBJECT","name":"User","fields":[
+      {"name":"id","args":[],"type":` + nonNull(named("SCALAR", "ID")) + `},
+      {"name":"apiKey","args":[],"type":` + named("SCALAR", "String") + `},
+      {"name":"role","args":[],"type":` + named("ENUM", "Role") + `},
+      {"name":"posts","args":[],"type":` + listOf(named("OBJECT", "Post")) + `},
+      {"name":"search","args":[{"name":"q","type":` + nonNull(named("SCALAR", "String")) + `}],"type":` + named("SCALAR", "String") + `}]},
+    {"kind":"OBJECT","name":"Post","fields":[
+      {"name":"title","args":[],"type":` + named("SCALAR", "String") + `},
+      {"name":"author","args":[],"type":` + named("OBJECT", "User") + `}]},
+    {"kind":"ENUM","name":"Role","enumValues":[{"name":"USER"},{"name":"ADMIN"}]},
+    {"kind":"SCALAR","name":"String"},
+    {"kind":"OBJECT","name":"__Type","fields":[]}
+  ]}}}`
+
+func TestParseIntrospection(t *testing.T) {
+	s, err := ParseIntrospection([]byte(introspectionFixture))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"user(id: ID!): User", "me: User"}; !slices.Equal(s.Queries, want) {
+		t.Errorf("queries = %q", s.Queries)
+	}
+	if want := []string{"resetPassword(email: String!): Boolean"}; !slices.Equal(s.Mutations, want) {
+		t.Errorf("mutations = %q", s.Mutations)
+	}
+	var names []string
+	for _, ts := range s.Types {
+		names = append(names, ts.Name)
+	}
+	if want := []string{"User", "Post", "Role"}; !slices.Equal(names, want) {
+		t.Errorf("types = %q, want %q", names, want)
+	}
+	if got := s.Types[0].Fields[3]; got != "posts: [Post]" {
+		t.Errorf("User.posts = %q", got)
+	}
+	if want := []string{"mutation.resetPassword", "User.apiKey", "User.role"}; !slices.Equal(s.Interesting(), want) {
+		t.Errorf("interesting = %q", s.Interesting())
+	}
+}
+
+func TestParseIntrospection_Disabled(t *testing.T) {
+	_, err := ParseIntrospection([]byte(`{"errors":[{"message":"GraphQL introspection is not allowed"}]}`))
+	if !errors.Is(err, ErrIntrospectionDisabled) || !strings.Contains(err.Error(), "not allowed") {
+		t.Errorf("err = %v", err)
+	}
+	if _, err := ParseIntrospection([]byte("<html>")); err == nil || errors.Is(err, ErrIntrospectionDisabled) {
+		t.Errorf("non-JSON err = %v", err)
+	}
+}
+
+func TestSuggestQuery(t *testing.T) {
+	s, err := ParseIntrospection([]byte(introspectionFixture))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := s.SuggestQuery("user", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "query Suggested($id: ID!) {\n" +
+		"  user(id: $id) {\n" +
+		"    id\n" +
+		"    apiKey\n" +
+		"    role\n" +
+		"    posts {\n" +
+		"      title\n" +
+		"    }\n" +
+		"  }\n" +
+		"}"
+	if got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+	if got, _ := s.SuggestQuery("resetPassword", 2); !strings.HasPrefix(got, "mutation Suggested($email: String!) {\n  resetPassword(email: $email)\n}") {
+		t.Errorf("mutation = %q", got)
+	}
+	if _, err := s.SuggestQuery("nope", 2); err == nil {
+		t.Error("expected an error for an unknown field")
+	}
+}
@@ -0,0 +1,93 @@
+package httpauth
+
+import (
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"slices"
+	"strings"
+)
+
+// DigestRequest is the request a Digest response is computed for.
+type DigestRequest struct {
+	Method, URI, Body string
+	// CNonce and NC are the client nonce and nonce count.
+	CNonce string
+	NC     int
+}
+
+// Digest returns the Authorization value answering a Digest challenge
+// (RFC 7616, and RFC 2069 when no qop is offered). qop auth is preferred
+// over auth-int.
+func Digest(c Challenge, user, password string, r DigestRequest) (string, error) {
+	p := c.Params
+	if p["nonce"] == "" {
+		return "", fmt.Errorf("digest challenge without nonce")
+	}
+	algorithm := p["algorithm"]
+	if algorithm == "" {
+		algorithm = "MD5"
+	}
+	var newHash func() hash.Hash
+	switch strings.TrimSuffix(strings.ToUpper(algorithm), "-SESS") {
+	case "MD5":
+		newHash = md5.New
+	case "SHA-256":
+		newHash = sha256.New
+	default:
+		return "", fmt.Errorf("unsupported digest algorithm %q", algorithm)
+	}
+	h := func(s string) string {
+		d := newHash()
+		d.Write([]byte(s))
+		return hex.EncodeToString(d.Sum(nil))
+	}
+
+	var qop string
+	offered := strings.Split(strings.ReplaceAll(p["qop"], " ", ""), ",")
+	switch {
+	case slices.Contains(offered, "auth"):
+		qop = "auth"
+	case slices.Contains(offered, "auth-int"):
+		qop = "auth-int"
+	case p["qop"] != "":
+		return "", fmt.Errorf("unsupported digest qop %q", p["qop"])
+	}
+	nc := fmt.Sprintf("%08x", max(r.NC, 1))
+
+	ha1 := h(user + ":" + p["realm"] + ":" + password)
+	if strings.HasSuffix(strings.ToUpper(algorithm), "-SESS") {
+		ha1 = h(ha1 + ":" + p["nonce"] + ":" + r.CNonce)
+	}
+	a2 := r.Method + ":" + r.URI
+	if qop == "auth-int" {
+		a2 += ":" + h(r.Body)
+	}
+	ha2 := h(a2)
+	var response string
+	if qop == "" {
+		response = h(ha1 + ":" + p["nonce"] + ":" + ha2)
+	} else {
+		response = h(ha1 + ":" + p["nonce"] + ":" + nc + ":" + r.CNonce + ":" + qop + ":" + ha2)
+	}
+
+	fields := []string{
+		fmt.Sprintf(`username=%q`, user),
+		fmt.Sprintf(`realm=%q`, p["realm"]),
+		fmt.Sprintf(`nonce=%q`, p["nonce"]),
+		fmt.Sprintf(`uri=%q`, r.URI),
+	}
+	if p["algorithm"] != "" {
+		fields = append(fields, "algorithm="+algorithm)
+	}
+	if qop != "" {
+		fields = append(fields, "qop="+qop, "nc="+nc, fmt.Sprintf(`cnonce=%q`, r.CNonce))
+	}
+	fields = append(fields, fmt.Sprintf(`response=%q`, response))
+	if p["opaque"] != "" {
+		fields = append(fields, fmt.Sprintf(`opaque=%q`, p["opaque"]))
+	}
+	return "Digest " + strings.Join(fields, ", "), nil
+}
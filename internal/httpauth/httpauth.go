@@ -0,0 +1,106 @@
+// Package httpauth builds HTTP Authorization headers for Basic, Digest,
+// and NTLM (directly or inside Negotiate). It only computes header values;
+// callers own the connections, which NTLM needs kept open between legs.
+package httpauth
+
+import (
+	"encoding/base64"
+	"strings"
+)
+
+// Challenge is one scheme offered in a WWW-Authenticate header.
+type Challenge struct {
+	// Scheme is lowercase: basic, digest, ntlm, negotiate, ...
+	Scheme string
+	// Token is the base64 blob of NTLM and Negotiate challenges.
+	Token string
+	// Params are the auth-params of Basic and Digest challenges.
+	Params map[string]string
+}
+
+// ParseChallenges parses WWW-Authenticate header values. A value may hold
+// several comma-separated challenges; parameters attach to the scheme
+// before them.
+func ParseChallenges(values []string) []Challenge {
+	var out []Challenge
+	for _, v := range values {
+		for _, item := range splitParams(v) {
+			item = strings.TrimSpace(item)
+			if item == "" {
+				continue
+			}
+			name, rest, _ := strings.Cut(item, " ")
+			if eq := strings.IndexByte(name, '='); eq >= 0 && len(out) > 0 {
+				// A further param of the current challenge.
+				key, val := parseParam(item)
+				out[len(out)-1].Params[key] = val
+				continue
+			}
+			c := Challenge{Scheme: strings.ToLower(name), Params: map[string]string{}}
+			rest = strings.TrimSpace(rest)
+			if strings.Contains(rest, "=") && !isToken68(rest) {
+				key, val := parseParam(rest)
+				c.Params[key] = val
+			} else {
+				c.Token = rest
+			}
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// Find returns the first challenge with scheme, if any.
+func Find(challenges []Challenge, scheme string) (Challenge, bool) {
+	for _, c := range challenges {
+		if c.Scheme == scheme {
+			return c, true
+		}
+	}
+	return Challenge{}, false
+}
+
+// isToken68 reports whether s is a bare base64 token (possibly padded
+// with "="), not a key=value param.
+func isToken68(s string) bool {
+	trimmed := strings.TrimRight(s, "=")
+	return trimmed != "" && !strings.ContainsAny(trimmed, "=\" ,")
+}
+
+// splitParams splits on commas outside quoted strings.
+func splitParams(s string) []string {
+	var parts []string
+	quoted, start := false, 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			if quoted {
+				i++
+			}
+		case '"':
+			quoted = !quoted
+		case ',':
+			if !quoted {
+				parts = append(parts, s[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(parts, s[start:])
+}
+
+// parseParam splits key=value, unquoting the value.
+func parseParam(s string) (string, string) {
+	key, val, _ := strings.Cut(strings.TrimSpace(s), "=")
+	val = strings.TrimSpace(val)
+	if len(val) >= 2 && val[0] == '"' && val[len(val)-1] == '"' {
+		val = val[1 : len(val)-1]
+		val = strings.NewReplacer(`\"`, `"`, `\\`, `\`).Replace(val)
+	}
+	return strings.ToLower(strings.TrimSpace(key)), val
+}
+
+// Basic returns the Authorization value for Basic auth.
+func Basic(user, password string) string {
+	return "Basic " + base64.StdEncoding.EncodeToString([]byte(user+":"+password))
+}
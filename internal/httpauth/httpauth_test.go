@@ -0,0 +1,179 @@
+package httpauth
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMD4(t *testing.T) {
+	cases := map[string]string{
+		"":    "31d6cfe0d16ae931b73c59d7e0c089c0",
+		"abc": "a448017aaf21d8525fc10ae87aa6729d",
+		"12345678901234567890123456789012345678901234567890123456789012345678901234567890": "e33b4ddc9c38f2199c3e7b164fcc0536",
+	}
+	for in, want := range cases {
+		sum := md4Sum([]byte(in))
+		if got := hex.EncodeToString(sum[:]); got != want {
+			t.Errorf("md4(%q) = %s, want %s", in, got, want)
+		}
+	}
+	nt := md4Sum(encodeUTF16("Password"))
+	if got := hex.EncodeToString(nt[:]); got != "a4f49c406510bdcab6824ee7c30fd852" {
+		t.Errorf("NT hash = %s", got)
+	}
+}
+
+// MS-NLMP 4.2.4: NTLMv2 authentication.
+func TestNTLMv2Vector(t *testing.T) {
+	ntowf := ntowfv2("Password", "User", "Domain")
+	if got := hex.EncodeToString(ntowf); got != "0c868a403bfd7a93a3001ef22ef02e3f" {
+		t.Fatalf("NTOWFv2 = %s", got)
+	}
+	serverChallenge, _ := hex.DecodeString("0123456789abcdef")
+	clientChallenge := []byte(strings.Repeat("\xaa", 8))
+	info := avPair(2, encodeUTF16("Domain"))
+	info = append(info, avPair(1, encodeUTF16("Server"))...)
+	info = append(info, 0, 0, 0, 0)
+
+	nt, lm := ntlmv2Response(ntowf, serverChallenge, clientChallenge, make([]byte, 8), info)
+	if got := hex.EncodeToString(nt[:16]); got != "68cd0ab851e51c96aabc927bebef6a1c" {
+		t.Errorf("NTProofStr = %s", got)
+	}
+	if got := hex.EncodeToString(lm); got != "86c35097ac9cec102554764a57cccc19aaaaaaaaaaaaaaaa" {
+		t.Errorf("LMv2 = %s", got)
+	}
+}
+
+func avPair(id uint16, value []byte) []byte {
+	b := binary.LittleEndian.AppendUint16(nil, id)
+	b = binary.LittleEndian.AppendUint16(b, uint16(len(value)))
+	return append(b, value...)
+}
+
+func TestNTLMRoundTrip(t *testing.T) {
+	neg := NTLMNegotiate()
+	if string(neg[:8]) != "NTLMSSP\x00" || binary.LittleEndian.Uint32(neg[8:]) != 1 {
+		t.Fatalf("negotiate = %x", neg)
+	}
+
+	// Build a type 2 message carrying a target name and a timestamp.
+	name := encodeUTF16("CORP")
+	info := avPair(2, name)
+	info = append(info, avPair(ntlmAvTimestamp, []byte{1, 2, 3, 4, 5, 6, 7, 8})...)
+	info = append(info, 0, 0, 0, 0)
+	msg := make([]byte, 48)
+	copy(msg, "NTLMSSP\x00")
+	binary.LittleEndian.PutUint32(msg[8:], 2)
+	binary.LittleEndian.PutUint16(msg[12:], uint16(len(name)))
+	binary.LittleEndian.PutUint32(msg[16:], 48)
+	binary.LittleEndian.PutUint32(msg[20:], ntlmNegotiateDefaults)
+	copy(msg[24:], "\x01\x02\x03\x04\x05\x06\x07\x08")
+	binary.LittleEndian.PutUint16(msg[40:], uint16(len(info)))
+	binary.LittleEndian.PutUint32(msg[44:], uint32(48+len(name)))
+	msg = append(append(msg, name...), info...)
+
+	// A SPNEGO-style prefix must be skipped.
+	c, err := ParseNTLMChallenge(append([]byte{0xa1, 0x81, 0x00}, msg...))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.TargetName != "CORP" || string(c.TargetInfo) != string(info) {
+		t.Fatalf("challenge = %+v", c)
+	}
+	ts, ok := c.timestamp()
+	if !ok || ts[0] != 1 {
+		t.Fatalf("timestamp = %x, %v", ts, ok)
+	}
+
+	auth := NTLMAuthenticate(c, NTLMCredentials{User: `CORP\alice`, Password: "pw"}, time.Unix(0, 0), [8]byte{})
+	if binary.LittleEndian.Uint32(auth[8:]) != 3 {
+		t.Fatalf("type = %d", binary.LittleEndian.Uint32(auth[8:]))
+	}
+	lm, _ := ntlmField(auth, 12)
+	if string(lm) != string(make([]byte, 24)) {
+		t.Errorf("LM response with server timestamp = %x, want zeros", lm)
+	}
+	domain, _ := ntlmField(auth, 28)
+	user, _ := ntlmField(auth, 36)
+	if decodeUTF16(domain) != "CORP" || decodeUTF16(user) != "alice" {
+		t.Errorf("domain/user = %q/%q", decodeUTF16(domain), decodeUTF16(user))
+	}
+	nt, _ := ntlmField(auth, 20)
+	if !strings.Contains(string(nt), string(info)) {
+		t.Error("NT response doesn't carry the target info")
+	}
+}
+
+func TestParseNTLMChallenge_Errors(t *testing.T) {
+	if _, err := ParseNTLMChallenge([]byte("garbage")); err == nil {
+		t.Error("expected error without signature")
+	}
+	if _, err := ParseNTLMChallenge(NTLMNegotiate()); err == nil {
+		t.Error("expected error for type 1 message")
+	}
+}
+
+func TestParseChallenges(t *testing.T) {
+	got := ParseChallenges([]string{
+		`Digest realm="test realm", nonce="abc", qop="auth,auth-int", Basic realm="x"`,
+		"NTLM TlRMTVNTUAACAAAA==",
+		"Negotiate",
+	})
+	if len(got) != 4 {
+		t.Fatalf("challenges = %+v", got)
+	}
+	d := got[0]
+	if d.Scheme != "digest" || d.Params["realm"] != "test realm" || d.Params["nonce"] != "abc" || d.Params["qop"] != "auth,auth-int" {
+		t.Errorf("digest = %+v", d)
+	}
+	if got[1].Scheme != "basic" || got[1].Params["realm"] != "x" {
+		t.Errorf("basic = %+v", got[1])
+	}
+	if got[2].Scheme != "ntlm" || got[2].Token != "TlRMTVNTUAACAAAA==" {
+		t.Errorf("ntlm = %+v", got[2])
+	}
+	if c, ok := Find(got, "negotiate"); !ok || c.Token != "" {
+		t.Errorf("negotiate = %+v, %v", c, ok)
+	}
+	if _, ok := Find(got, "bearer"); ok {
+		t.Error("found bearer")
+	}
+}
+
+func TestBasic(t *testing.T) {
+	got := Basic("Aladdin", "open sesame")
+	if got != "Basic "+base64.StdEncoding.EncodeToString([]byte("Aladdin:open sesame")) {
+		t.Errorf("Basic = %s", got)
+	}
+}
+
+// RFC 2617 section 3.5.
+func TestDigest(t *testing.T) {
+	c := ParseChallenges([]string{`Digest realm="testrealm@host.com", qop="auth,auth-int", nonce="dcd98b7102dd2f0e8b11d0f600bfb0c093", opaque="5ccc069c403ebaf9f0171e9517f40e41"`})[0]
+	got, err := Digest(c, "Mufasa", "Circle Of Life", DigestRequest{Method: "GET", URI: "/dir/index.html", CNonce: "0a4f113b", NC: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		`response="6629fae49393a05397450978507c4ef1"`,
+		"qop=auth,", "nc=00000001",
+		`opaque="5ccc069c403ebaf9f0171e9517f40e41"`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("digest %s missing %s", got, want)
+		}
+	}
+
+	c.Params["algorithm"] = "SHA-512"
+	if _, err := Digest(c, "u", "p", DigestRequest{}); err == nil {
+		t.Error("expected error for unsupported algorithm")
+	}
+	delete(c.Params, "nonce")
+	if _, err := Digest(c, "u", "p", DigestRequest{}); err == nil {
+		t.Error("expected error without nonce")
+	}
+}
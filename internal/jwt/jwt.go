@@ -0,0 +1,196 @@
+// Package jwt decodes JSON Web Tokens without verifying them and flags the
+// claims and header parameters worth testing, including the OpenID Connect
+// id_token rules.
+package jwt
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Token is a decoded, unverified JWT.
+type Token struct {
+	Header    map[string]any
+	Claims    map[string]any
+	Signature []byte
+	// Encrypted marks a JWE: only the header can be read.
+	Encrypted bool
+}
+
+// pattern matches compact JWS (three segments) and JWE (five) tokens. Both
+// start with a base64url-encoded JSON header, hence "eyJ".
+var pattern = regexp.MustCompile(`eyJ[A-Za-z0-9_-]*(?:\.[A-Za-z0-9_-]*){2}(?:\.[A-Za-z0-9_-]*){0,2}`)
+
+// Find returns the JWTs in text, such as an Authorization header, a cookie,
+// or a token endpoint response, at most limit of them.
+func Find(text string, limit int) []string {
+	return pattern.FindAllString(text, limit)
+}
+
+// Decode splits and decodes a compact JWT.
+func Decode(token string) (*Token, error) {
+	parts := strings.Split(strings.TrimSpace(token), ".")
+	if len(parts) != 3 && len(parts) != 5 {
+		return nil, fmt.Errorf("a JWT has 3 segments (5 for JWE), got %d", len(parts))
+	}
+	t := &Token{Encrypted: len(parts) == 5}
+	if err := decodeSegment(parts[0], &t.Header); err != nil {
+		return nil, fmt.Errorf("header: %w", err)
+	}
+	if t.Encrypted {
+		return t, nil
+	}
+	if err := decodeSegment(parts[1], &t.Claims); err != nil {
+		return t, fmt.Errorf("claims: %w", err)
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[2], "="))
+	if err != nil {
+		return t, fmt.Errorf("signature: %w", err)
+	}
+	t.Signature = sig
+	return t, nil
+}
+
+func decodeSegment(seg string, v any) error {
+	data, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(seg, "="))
+	if err != nil {
+		return err
+	}
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.UseNumber()
+	return d.Decode(v)
+}
+
+// Alg returns the header's alg parameter.
+func (t *Token) Alg() string {
+	alg, _ := t.Header["alg"].(string)
+	return alg
+}
+
+// Time returns a NumericDate claim such as exp, and whether it is present.
+func (t *Token) Time(claim string) (time.Time, bool) {
+	n, ok := t.Claims[claim].(json.Number)
+	if !ok {
+		return time.Time{}, false
+	}
+	f, err := n.Float64()
+	if err != nil {
+		return time.Time{}, false
+	}
+	return time.Unix(int64(f), 0).UTC(), true
+}
+
+// Audience returns the aud claim, which may be a string or an array.
+func (t *Token) Audience() []string {
+	switch aud := t.Claims["aud"].(type) {
+	case string:
+		return []string{aud}
+	case []any:
+		var out []string
+		for _, a := range aud {
+			if s, ok := a.(string); ok {
+				out = append(out, s)
+			}
+		}
+		return out
+	}
+	return nil
+}
+
+// IsIDToken reports whether the claims look like an OpenID Connect id_token:
+// iss, sub, aud, exp, and iat are all required there.
+func (t *Token) IsIDToken() bool {
+	for _, c := range []string{"iss", "sub", "aud", "exp", "iat"} {
+		if _, ok := t.Claims[c]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// Expectations are what a relying party should check an id_token against.
+// Empty fields aren't checked.
+type Expectations struct {
+	Issuer   string
+	Audience string
+	Nonce    string
+}
+
+// Check lists the problems with t at time now: signature and header issues
+// for any JWT, and the OpenID Connect id_token validation rules (issuer,
+// audience, authorized party, expiry, nonce) when oidc is set.
+func (t *Token) Check(now time.Time, oidc bool, want Expectations) []string {
+	var issues []string
+	alg := t.Alg()
+	switch {
+	case strings.EqualFold(alg, "none"):
+		issues = append(issues, `alg is "none": the token is unsigned; check whether the server accepts it`)
+	case len(t.Signature) == 0 && !t.Encrypted:
+		issues = append(issues, "empty signature; check whether the server accepts it")
+	case strings.HasPrefix(alg, "HS"):
+		issues = append(issues, fmt.Sprintf("alg %s uses a shared secret; try cracking it offline and RS256-to-HS256 key confusion", alg))
+	}
+	for _, p := range []string{"jku", "x5u", "jwk", "kid"} {
+		if v, ok := t.Header[p]; ok {
+			issues = append(issues, fmt.Sprintf("header %s=%v selects the verification key; test injection and path traversal", p, v))
+		}
+	}
+	if t.Encrypted {
+		return issues
+	}
+
+	exp, hasExp := t.Time("exp")
+	switch {
+	case !hasExp:
+		issues = append(issues, "no exp claim: the token never expires")
+	case now.After(exp):
+		issues = append(issues, fmt.Sprintf("expired at %s; check whether the server still accepts it", exp.Format(time.RFC3339)))
+	}
+	if nbf, ok := t.Time("nbf"); ok && now.Before(nbf) {
+		issues = append(issues, "nbf is in the future: the token isn't valid yet")
+	}
+	if iat, ok := t.Time("iat"); ok && iat.After(now.Add(5*time.Minute)) {
+		issues = append(issues, "iat is in the future")
+	}
+	if !oidc {
+		return issues
+	}
+
+	for _, c := range []string{"iss", "sub", "aud", "exp", "iat"} {
+		if _, ok := t.Claims[c]; !ok {
+			issues = append(issues, "id_token is missing the required "+c+" claim")
+		}
+	}
+	iss, _ := t.Claims["iss"].(string)
+	if iss != "" && !strings.HasPrefix(iss, "https://") {
+		issues = append(issues, fmt.Sprintf("iss %q is not an https URL", iss))
+	}
+	if want.Issuer != "" && iss != want.Issuer {
+		issues = append(issues, fmt.Sprintf("iss %q doesn't match the expected issuer %q", iss, want.Issuer))
+	}
+	aud := t.Audience()
+	if want.Audience != "" && !slices.Contains(aud, want.Audience) {
+		issues = append(issues, fmt.Sprintf("aud %q doesn't include the expected client %q", aud, want.Audience))
+	}
+	azp, hasAzp := t.Claims["azp"].(string)
+	if len(aud) > 1 && !hasAzp {
+		issues = append(issues, "multiple audiences without azp: any listed client could replay this token")
+	}
+	if hasAzp && want.Audience != "" && azp != want.Audience {
+		issues = append(issues, fmt.Sprintf("azp %q isn't the expected client %q", azp, want.Audience))
+	}
+	nonce, hasNonce := t.Claims["nonce"].(string)
+	switch {
+	case want.Nonce != "" && nonce != want.Nonce:
+		issues = append(issues, fmt.Sprintf("nonce %q doesn't match the expected %q", nonce, want.Nonce))
+	case !hasNonce:
+		issues = append(issues, "no nonce claim: implicit and hybrid flows need one to stop replay")
+	}
+	return issues
+}
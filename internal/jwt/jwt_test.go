@@ -0,0 +1,101 @@
+package jwt
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+	"time"
+)
+
+func makeToken(header, claims, sig string) string {
+	enc := base64.RawURLEncoding.EncodeToString
+	return enc([]byte(header)) + "." + enc([]byte(claims)) + "." + sig
+}
+
+func TestFindAndDecode(t *testing.T) {
+	tok := makeToken(`{"alg":"RS256","kid":"k1"}`, `{"sub":"42","exp":1700000000}`, "c2ln")
+	found := Find("Authorization: Bearer "+tok+"\r\nCookie: a=b", 10)
+	if len(found) != 1 || found[0] != tok {
+		t.Fatalf("Find = %q", found)
+	}
+	d, err := Decode(found[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.Alg() != "RS256" || d.Claims["sub"] != "42" || string(d.Signature) != "sig" {
+		t.Errorf("decoded = %+v", d)
+	}
+	if exp, ok := d.Time("exp"); !ok || !exp.Equal(time.Unix(1700000000, 0)) {
+		t.Errorf("exp = %v, %v", exp, ok)
+	}
+	if d.IsIDToken() {
+		t.Error("access-style token reported as an id_token")
+	}
+
+	if _, err := Decode("a.b"); err == nil {
+		t.Error("expected an error for two segments")
+	}
+	jwe, err := Decode(makeToken(`{"alg":"RSA-OAEP","enc":"A256GCM"}`, "x", "y") + ".z.w")
+	if err != nil || !jwe.Encrypted || jwe.Claims != nil {
+		t.Errorf("JWE = %+v, %v", jwe, err)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	tests := []struct {
+		name  string
+		token string
+		oidc  bool
+		want  Expectations
+		flags []string
+	}{
+		{
+			name:  "alg none",
+			token: makeToken(`{"alg":"none"}`, `{"exp":1800000000}`, ""),
+			flags: []string{`alg is "none"`},
+		},
+		{
+			name:  "hs256 expired with jku",
+			token: makeToken(`{"alg":"HS256","jku":"https://x/jwks"}`, `{"exp":1600000000}`, "c2ln"),
+			flags: []string{"shared secret", "header jku", "expired at 2020"},
+		},
+		{
+			name:  "no exp",
+			token: makeToken(`{"alg":"RS256"}`, `{}`, "c2ln"),
+			flags: []string{"never expires"},
+		},
+		{
+			name: "oidc mismatches",
+			token: makeToken(`{"alg":"RS256"}`,
+				`{"iss":"http://idp.example","sub":"1","aud":["app","other"],"exp":1800000000,"iat":1699999999}`, "c2ln"),
+			oidc:  true,
+			want:  Expectations{Issuer: "https://idp.example", Audience: "mine", Nonce: "n1"},
+			flags: []string{"not an https URL", "expected issuer", "expected client", "without azp", "nonce"},
+		},
+		{
+			name: "oidc clean",
+			token: makeToken(`{"alg":"RS256"}`,
+				`{"iss":"https://idp.example","sub":"1","aud":"app","exp":1800000000,"iat":1699999999,"nonce":"n1"}`, "c2ln"),
+			oidc: true,
+			want: Expectations{Issuer: "https://idp.example", Audience: "app", Nonce: "n1"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, err := Decode(tt.token)
+			if err != nil {
+				t.Fatal(err)
+			}
+			issues := d.Check(now, tt.oidc, tt.want)
+			if len(issues) != len(tt.flags) {
+				t.Fatalf("issues = %q, want %d", issues, len(tt.flags))
+			}
+			for i, f := range tt.flags {
+				if !strings.Contains(issues[i], f) {
+					t.Errorf("issue %d = %q, want it to mention %q", i, issues[i], f)
+				}
+			}
+		})
+	}
+}
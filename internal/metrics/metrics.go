@@ -0,0 +1,205 @@
+// Package metrics keeps the server's counters and latency histograms and
+// exposes them in the Prometheus text format. The serve command publishes
+// them on an optional local port; the stats command reads them back and
+// summarizes them.
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// family is one named metric with its samples.
+type family interface {
+	write(w io.Writer)
+}
+
+// Registry holds metric families in registration order.
+type Registry struct {
+	mu       sync.Mutex
+	families []family
+}
+
+// Default is the registry the server records into.
+var Default = &Registry{}
+
+func (r *Registry) register(f family) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.families = append(r.families, f)
+}
+
+// WriteText writes every family in the Prometheus text exposition format.
+func (r *Registry) WriteText(w io.Writer) {
+	r.mu.Lock()
+	families := slices.Clone(r.families)
+	r.mu.Unlock()
+	for _, f := range families {
+		f.write(w)
+	}
+}
+
+// Handler serves the registry at any path.
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+		r.WriteText(w)
+	})
+}
+
+// vec is the label handling shared by counters and histograms.
+type vec struct {
+	name, help string
+	labels     []string
+}
+
+// key joins label values into a map key.
+func (v vec) key(values []string) string {
+	if len(values) != len(v.labels) {
+		panic(fmt.Sprintf("metrics: %s takes %d label values, got %d", v.name, len(v.labels), len(values)))
+	}
+	return strings.Join(values, "\x00")
+}
+
+// labelText renders {a="x",b="y"} for the values in key, plus extra pairs.
+func (v vec) labelText(key string, extra ...string) string {
+	var pairs []string
+	if len(v.labels) > 0 {
+		for i, value := range strings.Split(key, "\x00") {
+			pairs = append(pairs, v.labels[i]+`="`+escape(value)+`"`)
+		}
+	}
+	for i := 0; i+1 < len(extra); i += 2 {
+		pairs = append(pairs, extra[i]+`="`+escape(extra[i+1])+`"`)
+	}
+	if len(pairs) == 0 {
+		return ""
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+func (v vec) header(w io.Writer, kind string) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", v.name, v.help, v.name, kind)
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escape(s string) string { return labelEscaper.Replace(s) }
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+// CounterVec is a counter partitioned by labels.
+type CounterVec struct {
+	vec
+	mu     sync.Mutex
+	values map[string]float64
+}
+
+// NewCounterVec registers a counter with the given label names.
+func (r *Registry) NewCounterVec(name, help string, labels ...string) *CounterVec {
+	c := &CounterVec{vec: vec{name, help, labels}, values: make(map[string]float64)}
+	r.register(c)
+	return c
+}
+
+// Add adds v to the counter for the label values.
+func (c *CounterVec) Add(v float64, labelValues ...string) {
+	k := c.key(labelValues)
+	c.mu.Lock()
+	c.values[k] += v
+	c.mu.Unlock()
+}
+
+func (c *CounterVec) write(w io.Writer) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.header(w, "counter")
+	for _, k := range slices.Sorted(maps.Keys(c.values)) {
+		fmt.Fprintf(w, "%s%s %s\n", c.name, c.labelText(k), formatFloat(c.values[k]))
+	}
+}
+
+// HistogramVec is a histogram partitioned by labels.
+type HistogramVec struct {
+	vec
+	buckets []float64
+	mu      sync.Mutex
+	values  map[string]*histogram
+}
+
+type histogram struct {
+	counts []uint64 // per bucket, not cumulative
+	sum    float64
+	count  uint64
+}
+
+// DefaultBuckets are latency bucket bounds in seconds, from 5ms to 2min.
+var DefaultBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60, 120}
+
+// NewHistogramVec registers a histogram with the given bucket upper
+// bounds (ascending) and label names.
+func (r *Registry) NewHistogramVec(name, help string, buckets []float64, labels ...string) *HistogramVec {
+	h := &HistogramVec{vec: vec{name, help, labels}, buckets: buckets, values: make(map[string]*histogram)}
+	r.register(h)
+	return h
+}
+
+// Observe records v for the label values.
+func (h *HistogramVec) Observe(v float64, labelValues ...string) {
+	k := h.key(labelValues)
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	hist, ok := h.values[k]
+	if !ok {
+		hist = &histogram{counts: make([]uint64, len(h.buckets))}
+		h.values[k] = hist
+	}
+	if i, _ := slices.BinarySearch(h.buckets, v); i < len(h.buckets) {
+		hist.counts[i]++
+	}
+	hist.sum += v
+	hist.count++
+}
+
+func (h *HistogramVec) write(w io.Writer) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.header(w, "histogram")
+	for _, k := range slices.Sorted(maps.Keys(h.values)) {
+		hist := h.values[k]
+		var cumulative uint64
+		for i, bound := range h.buckets {
+			cumulative += hist.counts[i]
+			fmt.Fprintf(w, "%s_bucket%s %d\n", h.name, h.labelText(k, "le", formatFloat(bound)), cumulative)
+		}
+		fmt.Fprintf(w, "%s_bucket%s %d\n", h.name, h.labelText(k, "le", "+Inf"), hist.count)
+		fmt.Fprintf(w, "%s_sum%s %s\n", h.name, h.labelText(k), formatFloat(hist.sum))
+		fmt.Fprintf(w, "%s_count%s %d\n", h.name, h.labelText(k), hist.count)
+	}
+}
+
+// GaugeFunc is a gauge whose value is read when the registry is written.
+type GaugeFunc struct {
+	vec
+	value func() float64
+}
+
+// NewGaugeFunc registers a gauge that reports value().
+func (r *Registry) NewGaugeFunc(name, help string, value func() float64) *GaugeFunc {
+	g := &GaugeFunc{vec: vec{name: name, help: help}, value: value}
+	r.register(g)
+	return g
+}
+
+func (g *GaugeFunc) write(w io.Writer) {
+	g.header(w, "gauge")
+	fmt.Fprintf(w, "%s %s\n", g.name, formatFloat(g.value()))
+}
@@ -0,0 +1,74 @@
+package metrics
+
+import (
+	"bytes"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestRegistry_RoundTrip(t *testing.T) {
+	r := &Registry{}
+	calls := r.NewCounterVec(ToolCalls, "calls", "tool", "outcome")
+	errs := r.NewCounterVec(ToolErrors, "errors", "tool", "code")
+	dur := r.NewHistogramVec(ToolDuration, "latency", []float64{0.1, 1, 10}, "tool")
+	sent := r.NewCounterVec(TargetBytesSent, "sent", "target")
+	reqs := r.NewCounterVec(TargetRequests, "requests", "target")
+	r.NewGaugeFunc(StartTime, "start", func() float64 { return 1700000000 })
+
+	for i := 0; i < 19; i++ {
+		calls.Add(1, "send", "ok")
+		dur.Observe(0.05, "send")
+	}
+	calls.Add(1, "send", "error")
+	errs.Add(1, "send", "scope_violation")
+	dur.Observe(5, "send")
+	calls.Add(2, `we"ird\tool`, "ok")
+	reqs.Add(3, "https://a.test:443")
+	sent.Add(300, "https://a.test:443")
+
+	var buf bytes.Buffer
+	r.WriteText(&buf)
+	if !strings.Contains(buf.String(), `tool="we\"ird\\tool"`) {
+		t.Errorf("label not escaped:\n%s", buf.String())
+	}
+	samples, err := Parse(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := Summarize(samples)
+
+	if s.Since.Unix() != 1700000000 {
+		t.Errorf("Since = %v", s.Since)
+	}
+	if len(s.Tools) != 2 || s.Tools[0].Tool != "send" || s.Tools[1].Tool != `we"ird\tool` {
+		t.Fatalf("Tools = %+v", s.Tools)
+	}
+	send := s.Tools[0]
+	if send.Calls != 20 || send.Errors != 1 || send.ErrorRate != 0.05 || send.ErrorCodes["scope_violation"] != 1 {
+		t.Errorf("send = %+v", send)
+	}
+	if want := (19*0.05 + 5) / 20; math.Abs(send.MeanSeconds-want) > 1e-9 {
+		t.Errorf("MeanSeconds = %v, want %v", send.MeanSeconds, want)
+	}
+	// The 19th of 20 observations is the 95th percentile; it falls in the
+	// first bucket.
+	if math.Abs(send.P95Seconds-0.1) > 1e-9 {
+		t.Errorf("P95Seconds = %v, want 0.1", send.P95Seconds)
+	}
+	if len(s.Targets) != 1 || s.Targets[0] != (TargetStats{Target: "https://a.test:443", Requests: 3, BytesSent: 300}) {
+		t.Errorf("Targets = %+v", s.Targets)
+	}
+}
+
+func TestParse_Malformed(t *testing.T) {
+	for _, text := range []string{
+		"name_only",
+		`x{a="1" 2`,
+		"x notanumber",
+	} {
+		if _, err := Parse(strings.NewReader(text)); err == nil {
+			t.Errorf("Parse(%q) succeeded", text)
+		}
+	}
+}
@@ -0,0 +1,234 @@
+package metrics
+
+import (
+	"bufio"
+	"cmp"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Names of the metrics the server records.
+const (
+	ToolCalls           = "burp_mcp_tool_calls_total"            // tool, outcome (ok or error)
+	ToolErrors          = "burp_mcp_tool_errors_total"           // tool, code
+	ToolDuration        = "burp_mcp_tool_duration_seconds"       // tool
+	TargetRequests      = "burp_mcp_target_requests_total"       // target
+	TargetBytesSent     = "burp_mcp_target_bytes_sent_total"     // target
+	TargetBytesReceived = "burp_mcp_target_bytes_received_total" // target
+	StartTime           = "burp_mcp_start_time_seconds"
+)
+
+// Sample is one line of the text format.
+type Sample struct {
+	Name   string
+	Labels map[string]string
+	Value  float64
+}
+
+// Parse reads samples in the Prometheus text format, skipping comments.
+func Parse(r io.Reader) ([]Sample, error) {
+	var samples []Sample
+	sc := bufio.NewScanner(r)
+	for n := 1; sc.Scan(); n++ {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		s, err := parseSample(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", n, err)
+		}
+		samples = append(samples, s)
+	}
+	return samples, sc.Err()
+}
+
+func parseSample(line string) (Sample, error) {
+	s := Sample{Labels: map[string]string{}}
+	i := strings.IndexAny(line, "{ ")
+	if i <= 0 {
+		return s, fmt.Errorf("malformed sample %q", line)
+	}
+	s.Name, line = line[:i], line[i:]
+	if strings.HasPrefix(line, "{") {
+		rest, err := parseLabels(line[1:], s.Labels)
+		if err != nil {
+			return s, err
+		}
+		line = rest
+	}
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return s, fmt.Errorf("sample %s has no value", s.Name)
+	}
+	v, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return s, fmt.Errorf("sample %s: %w", s.Name, err)
+	}
+	s.Value = v
+	return s, nil
+}
+
+// parseLabels reads name="value" pairs up to the closing brace into labels
+// and returns the rest of the line.
+func parseLabels(s string, labels map[string]string) (string, error) {
+	for {
+		s = strings.TrimLeft(s, " ,")
+		if strings.HasPrefix(s, "}") {
+			return s[1:], nil
+		}
+		eq := strings.Index(s, `="`)
+		if eq <= 0 {
+			return "", fmt.Errorf("malformed labels")
+		}
+		name := s[:eq]
+		s = s[eq+2:]
+		var value strings.Builder
+		for {
+			if s == "" {
+				return "", fmt.Errorf("unterminated label %s", name)
+			}
+			c := s[0]
+			s = s[1:]
+			if c == '"' {
+				break
+			}
+			if c == '\\' && s != "" {
+				c, s = s[0], s[1:]
+				if c == 'n' {
+					c = '\n'
+				}
+			}
+			value.WriteByte(c)
+		}
+		labels[name] = value.String()
+	}
+}
+
+// ToolStats summarizes one tool's calls.
+type ToolStats struct {
+	Tool       string         `json:"tool"`
+	Calls      int            `json:"calls"`
+	Errors     int            `json:"errors"`
+	ErrorRate  float64        `json:"errorRate"`
+	ErrorCodes map[string]int `json:"errorCodes,omitempty"`
+	// MeanSeconds and P95Seconds are call latencies; the 95th percentile
+	// is interpolated within its histogram bucket.
+	MeanSeconds float64 `json:"meanSeconds"`
+	P95Seconds  float64 `json:"p95Seconds"`
+}
+
+// TargetStats summarizes the traffic to one target origin.
+type TargetStats struct {
+	Target        string `json:"target"`
+	Requests      int    `json:"requests"`
+	BytesSent     int64  `json:"bytesSent"`
+	BytesReceived int64  `json:"bytesReceived"`
+}
+
+// Summary is the digest of a metrics scrape the stats command prints.
+type Summary struct {
+	Since   time.Time     `json:"since"`
+	Tools   []ToolStats   `json:"tools"`
+	Targets []TargetStats `json:"targets"`
+}
+
+// Summarize digests the samples of the server's metrics: tools by call
+// count, targets by request count.
+func Summarize(samples []Sample) Summary {
+	tools := map[string]*ToolStats{}
+	tool := func(name string) *ToolStats {
+		if t, ok := tools[name]; ok {
+			return t
+		}
+		t := &ToolStats{Tool: name}
+		tools[name] = t
+		return t
+	}
+	targets := map[string]*TargetStats{}
+	target := func(name string) *TargetStats {
+		if t, ok := targets[name]; ok {
+			return t
+		}
+		t := &TargetStats{Target: name}
+		targets[name] = t
+		return t
+	}
+	type bucket struct{ le, count float64 }
+	buckets := map[string][]bucket{}
+	sums := map[string]float64{}
+
+	var summary Summary
+	for _, s := range samples {
+		switch s.Name {
+		case StartTime:
+			summary.Since = time.Unix(int64(s.Value), 0).UTC()
+		case ToolCalls:
+			t := tool(s.Labels["tool"])
+			t.Calls += int(s.Value)
+			if s.Labels["outcome"] == "error" {
+				t.Errors += int(s.Value)
+			}
+		case ToolErrors:
+			t := tool(s.Labels["tool"])
+			if t.ErrorCodes == nil {
+				t.ErrorCodes = map[string]int{}
+			}
+			t.ErrorCodes[s.Labels["code"]] += int(s.Value)
+		case ToolDuration + "_bucket":
+			le, err := strconv.ParseFloat(s.Labels["le"], 64)
+			if err == nil {
+				buckets[s.Labels["tool"]] = append(buckets[s.Labels["tool"]], bucket{le, s.Value})
+			}
+		case ToolDuration + "_sum":
+			sums[s.Labels["tool"]] = s.Value
+		case TargetRequests:
+			target(s.Labels["target"]).Requests += int(s.Value)
+		case TargetBytesSent:
+			target(s.Labels["target"]).BytesSent += int64(s.Value)
+		case TargetBytesReceived:
+			target(s.Labels["target"]).BytesReceived += int64(s.Value)
+		}
+	}
+
+	for name, t := range tools {
+		if t.Calls > 0 {
+			t.ErrorRate = float64(t.Errors) / float64(t.Calls)
+		}
+		b := buckets[name]
+		slices.SortFunc(b, func(x, y bucket) int { return cmp.Compare(x.le, y.le) })
+		if n := len(b); n > 0 && b[n-1].count > 0 {
+			total := b[n-1].count
+			t.MeanSeconds = sums[name] / total
+			rank := 0.95 * total
+			lower, below := 0.0, 0.0
+			for _, bk := range b {
+				if bk.count >= rank {
+					if math.IsInf(bk.le, 1) {
+						t.P95Seconds = lower
+					} else {
+						t.P95Seconds = lower + (bk.le-lower)*(rank-below)/(bk.count-below)
+					}
+					break
+				}
+				lower, below = bk.le, bk.count
+			}
+		}
+		summary.Tools = append(summary.Tools, *t)
+	}
+	for _, t := range targets {
+		summary.Targets = append(summary.Targets, *t)
+	}
+	slices.SortFunc(summary.Tools, func(x, y ToolStats) int {
+		return cmp.Or(cmp.Compare(y.Calls, x.Calls), cmp.Compare(x.Tool, y.Tool))
+	})
+	slices.SortFunc(summary.Targets, func(x, y TargetStats) int {
+		return cmp.Or(cmp.Compare(y.Requests, x.Requests), cmp.Compare(x.Target, y.Target))
+	})
+	return summary
+}
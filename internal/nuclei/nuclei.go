@@ -0,0 +1,177 @@
+// Package nuclei builds command lines for the nuclei scanner and converts
+// its JSONL results into store issues.
+package nuclei
+
+import (
+	"bufio"
+	"bytes"
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/c0tton-fluff/burp-mcp-server/internal/store"
+)
+
+// Source is recorded on every issue converted from nuclei output.
+const Source = "nuclei"
+
+// Options selects what a nuclei run scans and with which templates.
+type Options struct {
+	Target      string
+	Templates   []string // -t: template files or directories
+	Tags        []string
+	ExcludeTags []string
+	Severities  []string
+	Headers     map[string]string
+	Proxy       string
+	RateLimit   int // requests per second, 0 for nuclei's default
+	Extra       []string
+}
+
+// Args returns the nuclei arguments for o. Output is always JSONL on stdout
+// without the banner or update check, so it can be parsed.
+func Args(o Options) []string {
+	args := []string{"-u", o.Target, "-jsonl", "-silent", "-no-color", "-disable-update-check"}
+	for _, t := range o.Templates {
+		args = append(args, "-t", t)
+	}
+	if len(o.Tags) > 0 {
+		args = append(args, "-tags", strings.Join(o.Tags, ","))
+	}
+	if len(o.ExcludeTags) > 0 {
+		args = append(args, "-etags", strings.Join(o.ExcludeTags, ","))
+	}
+	if len(o.Severities) > 0 {
+		args = append(args, "-severity", strings.Join(o.Severities, ","))
+	}
+	for _, name := range slices.Sorted(maps.Keys(o.Headers)) {
+		args = append(args, "-H", name+": "+o.Headers[name])
+	}
+	if o.Proxy != "" {
+		args = append(args, "-proxy", o.Proxy)
+	}
+	if o.RateLimit > 0 {
+		args = append(args, "-rl", strconv.Itoa(o.RateLimit))
+	}
+	return append(args, o.Extra...)
+}
+
+// Result is one line of nuclei's JSONL output. Only the fields used for
+// issues are decoded.
+type Result struct {
+	TemplateID  string   `json:"template-id"`
+	Info        Info     `json:"info"`
+	Type        string   `json:"type"`
+	Host        string   `json:"host"`
+	MatchedAt   string   `json:"matched-at"`
+	MatcherName string   `json:"matcher-name"`
+	Extracted   []string `json:"extracted-results"`
+	Request     string   `json:"request"`
+	Response    string   `json:"response"`
+}
+
+// Info is a template's metadata.
+type Info struct {
+	Name        string `json:"name"`
+	Severity    string `json:"severity"`
+	Description string `json:"description"`
+	Remediation string `json:"remediation"`
+	Reference   any    `json:"reference"` // a string or a list of strings
+}
+
+// Parse reads nuclei JSONL output. Lines that aren't JSON objects, such as
+// stray log lines, are skipped; a malformed object is an error.
+func Parse(r io.Reader) ([]Result, error) {
+	var results []Result
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64*1024), 16<<20)
+	for n := 1; sc.Scan(); n++ {
+		line := bytes.TrimSpace(sc.Bytes())
+		if len(line) == 0 || line[0] != '{' {
+			continue
+		}
+		var res Result
+		if err := json.Unmarshal(line, &res); err != nil {
+			return results, fmt.Errorf("nuclei output line %d: %w", n, err)
+		}
+		results = append(results, res)
+	}
+	return results, sc.Err()
+}
+
+// Issue converts r into a store issue. The template's description becomes
+// the background and what matched becomes the detail, mirroring how Burp
+// splits its own issues.
+func (r Result) Issue() store.Issue {
+	name := cmp.Or(r.Info.Name, r.TemplateID)
+	if r.MatcherName != "" {
+		name += " [" + r.MatcherName + "]"
+	}
+	issue := store.Issue{
+		Type:                  Source + ":" + r.TemplateID,
+		Name:                  name,
+		Host:                  r.Host,
+		Location:              r.MatchedAt,
+		Severity:              Severity(r.Info.Severity),
+		Confidence:            "Firm",
+		IssueBackground:       r.Info.Description,
+		RemediationBackground: r.Info.Remediation,
+		Source:                Source,
+	}
+	if u, err := url.Parse(r.MatchedAt); err == nil && u.Scheme != "" && u.Host != "" {
+		issue.Host = u.Scheme + "://" + u.Host
+		issue.Path = u.EscapedPath()
+	}
+
+	detail := fmt.Sprintf("Template %s matched at %s.", r.TemplateID, r.MatchedAt)
+	if len(r.Extracted) > 0 {
+		detail += " Extracted: " + strings.Join(r.Extracted, ", ")
+	}
+	if refs := references(r.Info.Reference); len(refs) > 0 {
+		detail += "\nReferences: " + strings.Join(refs, " ")
+	}
+	issue.IssueDetail = detail
+	if r.Request != "" || r.Response != "" {
+		issue.Evidence = []store.IssueEvidence{{Request: r.Request, Response: r.Response}}
+	}
+	return issue
+}
+
+// Severity maps a nuclei severity onto Burp's names. Nuclei's critical has
+// no Burp equivalent and is kept.
+func Severity(s string) string {
+	switch strings.ToLower(s) {
+	case "critical":
+		return "Critical"
+	case "high":
+		return "High"
+	case "medium":
+		return "Medium"
+	case "low":
+		return "Low"
+	default:
+		return "Information"
+	}
+}
+
+func references(v any) []string {
+	switch v := v.(type) {
+	case string:
+		return strings.Fields(v)
+	case []any:
+		var refs []string
+		for _, r := range v {
+			if s, ok := r.(string); ok && s != "" {
+				refs = append(refs, s)
+			}
+		}
+		return refs
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package nuclei
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestArgs(t *testing.T) {
+	got := Args(Options{
+		Target:     "https://shop.example",
+		Templates:  []string{"http/exposures/"},
+		Tags:       []string{"cve", "misconfig"},
+		Severities: []string{"high", "critical"},
+		Headers:    map[string]string{"X-B": "2", "Authorization": "Bearer t"},
+		Proxy:      "http://127.0.0.1:8080",
+		RateLimit:  5,
+		Extra:      []string{"-ud", "/opt/templates"},
+	})
+	want := []string{
+		"-u", "https://shop.example", "-jsonl", "-silent", "-no-color", "-disable-update-check",
+		"-t", "http/exposures/", "-tags", "cve,misconfig", "-severity", "high,critical",
+		"-H", "Authorization: Bearer t", "-H", "X-B: 2",
+		"-proxy", "http://127.0.0.1:8080", "-rl", "5", "-ud", "/opt/templates",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("args = %q", got)
+	}
+}
+
+const output = `[INF] stray log line
+{"template-id":"git-config","info":{"name":"Git Config Disclosure","severity":"medium","description":"Git config exposed.","reference":["https://example.com/a","https://example.com/b"]},"type":"http","host":"https://shop.example","matched-at":"https://shop.example/.git/config","request":"GET /.git/config HTTP/1.1\r\n\r\n","response":"HTTP/1.1 200 OK\r\n\r\n[core]"}
+
+{"template-id":"tls-version","info":{"name":"TLS Version","severity":"info"},"type":"ssl","host":"shop.example","matched-at":"shop.example:443","matcher-name":"tls12","extracted-results":["tls12"]}
+`
+
+func TestParse(t *testing.T) {
+	results, err := Parse(strings.NewReader(output))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("results = %+v", results)
+	}
+
+	git := results[0].Issue()
+	if git.Name != "Git Config Disclosure" || git.Type != "nuclei:git-config" || git.Host != "https://shop.example" || git.Path != "/.git/config" ||
+		git.Severity != "Medium" || git.IssueBackground != "Git config exposed." || git.Source != Source || len(git.Evidence) != 1 {
+		t.Errorf("git issue = %+v", git)
+	}
+	if want := "Template git-config matched at https://shop.example/.git/config.\nReferences: https://example.com/a https://example.com/b"; git.IssueDetail != want {
+		t.Errorf("detail = %q", git.IssueDetail)
+	}
+
+	tls := results[1].Issue()
+	if tls.Name != "TLS Version [tls12]" || tls.Host != "shop.example" || tls.Path != "" || tls.Location != "shop.example:443" ||
+		tls.Severity != "Information" || !strings.HasSuffix(tls.IssueDetail, "Extracted: tls12") || tls.Evidence != nil {
+		t.Errorf("tls issue = %+v", tls)
+	}
+
+	if _, err := Parse(strings.NewReader("{\"template-id\": 1}\n")); err == nil {
+		t.Error("expected error for malformed result")
+	}
+}
@@ -0,0 +1,501 @@
+// Package openapi reads OpenAPI 3.x and Swagger 2.0 documents, in JSON or
+// YAML, into an endpoint inventory with example values for every parameter
+// and request body, so requests can be built without hand-reading the spec.
+package openapi
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/c0tton-fluff/burp-mcp-server/internal/yaml"
+)
+
+// maxRefDepth bounds $ref chains and nested schemas when building examples;
+// recursive schemas stop there.
+const maxRefDepth = 8
+
+var methods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+// Spec is a parsed API description.
+type Spec struct {
+	Title     string     `json:"title,omitempty"`
+	Version   string     `json:"version,omitempty"`
+	Format    string     `json:"format"`
+	Servers   []string   `json:"servers,omitempty"`
+	Endpoints []Endpoint `json:"-"`
+
+	doc map[string]any
+}
+
+// Param is one operation parameter with an example value.
+type Param struct {
+	Name     string `json:"name"`
+	In       string `json:"in"`
+	Required bool   `json:"required,omitempty"`
+	Type     string `json:"type,omitempty"`
+	Example  any    `json:"example,omitempty"`
+}
+
+// Endpoint is one operation: a method on a path.
+type Endpoint struct {
+	Method      string   `json:"method"`
+	Path        string   `json:"path"`
+	OperationID string   `json:"operationId,omitempty"`
+	Summary     string   `json:"summary,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+	Deprecated  bool     `json:"deprecated,omitempty"`
+	Params      []Param  `json:"parameters,omitempty"`
+	ContentType string   `json:"contentType,omitempty"`
+	// Body is an example request body, as decoded JSON values.
+	Body any `json:"-"`
+	// Security lists the security scheme names the operation accepts.
+	Security []string `json:"security,omitempty"`
+}
+
+// SecurityScheme is how a named scheme carries its credential.
+type SecurityScheme struct {
+	Type   string // apiKey, http, oauth2, openIdConnect, basic
+	Scheme string // bearer or basic, for http
+	In     string // header, query, or cookie, for apiKey
+	Name   string // the header, query, or cookie name, for apiKey
+}
+
+// Parse reads a JSON or YAML OpenAPI 3.x or Swagger 2.0 document.
+func Parse(data string) (*Spec, error) {
+	var doc any
+	var err error
+	if trimmed := strings.TrimSpace(data); strings.HasPrefix(trimmed, "{") {
+		dec := json.NewDecoder(strings.NewReader(trimmed))
+		dec.UseNumber()
+		err = dec.Decode(&doc)
+	} else {
+		doc, err = yaml.Decode(data)
+	}
+	if err != nil {
+		return nil, err
+	}
+	root, ok := doc.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("not an OpenAPI document: top level is not an object")
+	}
+
+	s := &Spec{doc: root}
+	switch {
+	case str(root["openapi"]) != "":
+		s.Format = "openapi " + str(root["openapi"])
+		for _, sv := range list(root["servers"]) {
+			s.Servers = append(s.Servers, serverURL(obj(sv)))
+		}
+	case str(root["swagger"]) != "":
+		s.Format = "swagger " + str(root["swagger"])
+		if host := str(root["host"]); host != "" {
+			schemes := list(root["schemes"])
+			if len(schemes) == 0 {
+				schemes = []any{"https"}
+			}
+			for _, sc := range schemes {
+				s.Servers = append(s.Servers, str(sc)+"://"+host+str(root["basePath"]))
+			}
+		} else if base := str(root["basePath"]); base != "" {
+			s.Servers = []string{base}
+		}
+	default:
+		return nil, fmt.Errorf("not an OpenAPI document: no openapi or swagger version field")
+	}
+	info := obj(root["info"])
+	s.Title, s.Version = str(info["title"]), str(info["version"])
+
+	paths := obj(root["paths"])
+	for _, path := range slices.Sorted(maps.Keys(paths)) {
+		item := s.resolve(obj(paths[path]), 0)
+		shared := list(item["parameters"])
+		for _, m := range methods {
+			op := obj(item[m])
+			if op == nil {
+				continue
+			}
+			s.Endpoints = append(s.Endpoints, s.endpoint(strings.ToUpper(m), path, op, shared))
+		}
+	}
+	return s, nil
+}
+
+// serverURL fills an OpenAPI 3 server's variables with their defaults.
+func serverURL(server map[string]any) string {
+	u := str(server["url"])
+	for name, v := range obj(server["variables"]) {
+		u = strings.ReplaceAll(u, "{"+name+"}", str(obj(v)["default"]))
+	}
+	return u
+}
+
+// SecuritySchemes returns the document's named security schemes.
+func (s *Spec) SecuritySchemes() map[string]SecurityScheme {
+	defs := obj(obj(s.doc["components"])["securitySchemes"])
+	if defs == nil {
+		defs = obj(s.doc["securityDefinitions"])
+	}
+	out := make(map[string]SecurityScheme, len(defs))
+	for name, d := range defs {
+		def := s.resolve(obj(d), 0)
+		out[name] = SecurityScheme{Type: str(def["type"]), Scheme: strings.ToLower(str(def["scheme"])), In: str(def["in"]), Name: str(def["name"])}
+	}
+	return out
+}
+
+func (s *Spec) endpoint(method, path string, op map[string]any, shared []any) Endpoint {
+	e := Endpoint{Method: method, Path: path, OperationID: str(op["operationId"]), Summary: str(op["summary"])}
+	for _, t := range list(op["tags"]) {
+		e.Tags = append(e.Tags, str(t))
+	}
+	e.Deprecated, _ = op["deprecated"].(bool)
+
+	// Operation parameters override path-level ones with the same name and location.
+	var params []map[string]any
+	seen := map[string]bool{}
+	for _, p := range list(op["parameters"]) {
+		param := s.resolve(obj(p), 0)
+		seen[str(param["in"])+" "+str(param["name"])] = true
+		params = append(params, param)
+	}
+	for _, p := range shared {
+		param := s.resolve(obj(p), 0)
+		if !seen[str(param["in"])+" "+str(param["name"])] {
+			params = append(params, param)
+		}
+	}
+
+	var form map[string]any
+	for _, p := range params {
+		in := str(p["in"])
+		switch in {
+		case "body":
+			// Swagger 2 carries the request body as a parameter.
+			e.Body = s.example(obj(p["schema"]), 0)
+			continue
+		case "formData":
+			if form == nil {
+				form = map[string]any{}
+			}
+			form[str(p["name"])] = s.paramExample(p)
+			if str(p["type"]) == "file" {
+				e.ContentType = "multipart/form-data"
+			}
+			continue
+		}
+		required, _ := p["required"].(bool)
+		e.Params = append(e.Params, Param{Name: str(p["name"]), In: in, Required: required || in == "path", Type: paramType(p), Example: s.paramExample(p)})
+	}
+
+	if rb := s.resolve(obj(op["requestBody"]), 0); rb != nil {
+		content := obj(rb["content"])
+		if ct := pickContentType(slices.Collect(maps.Keys(content))); ct != "" {
+			e.ContentType = ct
+			media := obj(content[ct])
+			e.Body = mediaExample(media)
+			if e.Body == nil {
+				e.Body = s.example(obj(media["schema"]), 0)
+			}
+		}
+	} else if e.Body != nil || form != nil {
+		consumes := list(op["consumes"])
+		if len(consumes) == 0 {
+			consumes = list(s.doc["consumes"])
+		}
+		types := make([]string, len(consumes))
+		for i, c := range consumes {
+			types[i] = str(c)
+		}
+		switch {
+		case form != nil:
+			e.Body = form
+			if e.ContentType == "" {
+				e.ContentType = cmp.Or(pickContentType(slices.DeleteFunc(types, func(t string) bool { return !strings.Contains(t, "form") })), "application/x-www-form-urlencoded")
+			}
+		default:
+			e.ContentType = cmp.Or(pickContentType(types), "application/json")
+		}
+	}
+
+	security, ok := op["security"]
+	if !ok {
+		security = s.doc["security"]
+	}
+	for _, req := range list(security) {
+		for _, name := range slices.Sorted(maps.Keys(obj(req))) {
+			if !slices.Contains(e.Security, name) {
+				e.Security = append(e.Security, name)
+			}
+		}
+	}
+	return e
+}
+
+// pickContentType prefers JSON, then forms, then whatever sorts first.
+func pickContentType(types []string) string {
+	slices.Sort(types)
+	for _, want := range []func(string) bool{
+		func(t string) bool { return t == "application/json" },
+		func(t string) bool { return strings.HasSuffix(t, "+json") || strings.Contains(t, "/json") },
+		func(t string) bool { return t == "application/x-www-form-urlencoded" },
+		func(t string) bool { return t == "multipart/form-data" },
+	} {
+		if i := slices.IndexFunc(types, want); i >= 0 {
+			return types[i]
+		}
+	}
+	if len(types) > 0 {
+		return types[0]
+	}
+	return ""
+}
+
+// mediaExample returns a media type's own example, or its first named one.
+func mediaExample(media map[string]any) any {
+	if v, ok := media["example"]; ok {
+		return v
+	}
+	examples := obj(media["examples"])
+	for _, name := range slices.Sorted(maps.Keys(examples)) {
+		if v, ok := obj(examples[name])["value"]; ok {
+			return v
+		}
+	}
+	return nil
+}
+
+func paramType(p map[string]any) string {
+	schema := obj(p["schema"])
+	if schema == nil {
+		schema = p // Swagger 2 puts the type on the parameter
+	}
+	t := schemaType(schema)
+	if t == "array" {
+		if it := schemaType(obj(schema["items"])); it != "" {
+			return "array of " + it
+		}
+	}
+	return t
+}
+
+func (s *Spec) paramExample(p map[string]any) any {
+	if v := mediaExample(p); v != nil {
+		return v
+	}
+	if v, ok := p["x-example"]; ok {
+		return v
+	}
+	if schema := obj(p["schema"]); schema != nil {
+		return s.example(schema, 0)
+	}
+	if content := obj(p["content"]); content != nil {
+		for _, ct := range slices.Sorted(maps.Keys(content)) {
+			return s.example(obj(obj(content[ct])["schema"]), 0)
+		}
+	}
+	return s.example(p, 0)
+}
+
+// resolve follows a local $ref to what it points at.
+func (s *Spec) resolve(m map[string]any, depth int) map[string]any {
+	for depth < maxRefDepth {
+		ref, ok := m["$ref"].(string)
+		if !ok {
+			return m
+		}
+		target, ok := s.lookup(ref)
+		if !ok {
+			return nil
+		}
+		m = target
+		depth++
+	}
+	return nil
+}
+
+// lookup finds a "#/a/b" reference in the document.
+func (s *Spec) lookup(ref string) (map[string]any, bool) {
+	if !strings.HasPrefix(ref, "#/") {
+		return nil, false // external references aren't fetched
+	}
+	var cur any = s.doc
+	for _, part := range strings.Split(ref[2:], "/") {
+		part = strings.ReplaceAll(strings.ReplaceAll(part, "~1", "/"), "~0", "~")
+		if p, err := url.PathUnescape(part); err == nil {
+			part = p
+		}
+		m, ok := cur.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		if cur, ok = m[part]; !ok {
+			return nil, false
+		}
+	}
+	m, ok := cur.(map[string]any)
+	return m, ok
+}
+
+// schemaType is a schema's type, the first non-null one when it lists
+// several, or object or array when only their keywords say so.
+func schemaType(schema map[string]any) string {
+	switch t := schema["type"].(type) {
+	case string:
+		return t
+	case []any:
+		for _, v := range t {
+			if str(v) != "null" {
+				return str(v)
+			}
+		}
+	}
+	switch {
+	case schema["properties"] != nil || schema["additionalProperties"] != nil:
+		return "object"
+	case schema["items"] != nil:
+		return "array"
+	}
+	return ""
+}
+
+// example builds an example value for schema: its own example, default,
+// or first enum value, or else one synthesized from its type and format.
+func (s *Spec) example(schema map[string]any, depth int) any {
+	if depth > maxRefDepth {
+		return nil
+	}
+	if schema = s.resolve(schema, 0); schema == nil {
+		return nil
+	}
+	for _, key := range []string{"example", "default", "const"} {
+		if v, ok := schema[key]; ok {
+			return v
+		}
+	}
+	if ex := list(schema["examples"]); len(ex) > 0 {
+		return ex[0]
+	}
+	if enum := list(schema["enum"]); len(enum) > 0 {
+		return enum[0]
+	}
+	if all := list(schema["allOf"]); len(all) > 0 {
+		merged := map[string]any{}
+		for _, sub := range all {
+			v := s.example(obj(sub), depth+1)
+			if m, ok := v.(map[string]any); ok {
+				maps.Copy(merged, m)
+			} else if v != nil && len(all) == 1 {
+				return v
+			}
+		}
+		return merged
+	}
+	for _, key := range []string{"oneOf", "anyOf"} {
+		if alts := list(schema[key]); len(alts) > 0 {
+			return s.example(obj(alts[0]), depth+1)
+		}
+	}
+
+	switch schemaType(schema) {
+	case "object":
+		out := map[string]any{}
+		for name, prop := range obj(schema["properties"]) {
+			if ro, _ := obj(prop)["readOnly"].(bool); ro {
+				continue
+			}
+			out[name] = s.example(obj(prop), depth+1)
+		}
+		if extra := obj(schema["additionalProperties"]); len(out) == 0 && extra != nil {
+			out["key"] = s.example(extra, depth+1)
+		}
+		return out
+	case "array":
+		item := s.example(obj(schema["items"]), depth+1)
+		if item == nil {
+			return []any{}
+		}
+		return []any{item}
+	case "integer":
+		if v, ok := schema["minimum"]; ok {
+			return v
+		}
+		return json.Number("1")
+	case "number":
+		if v, ok := schema["minimum"]; ok {
+			return v
+		}
+		return json.Number("1.5")
+	case "boolean":
+		return true
+	case "string", "file":
+		return stringExample(str(schema["format"]))
+	}
+	return nil
+}
+
+func stringExample(format string) string {
+	switch format {
+	case "date-time":
+		return "2024-01-01T00:00:00Z"
+	case "date":
+		return "2024-01-01"
+	case "time":
+		return "00:00:00"
+	case "email":
+		return "user@example.com"
+	case "uuid":
+		return "00000000-0000-4000-8000-000000000000"
+	case "uri", "url":
+		return "https://example.com/"
+	case "hostname":
+		return "example.com"
+	case "ipv4":
+		return "192.0.2.1"
+	case "ipv6":
+		return "2001:db8::1"
+	case "byte":
+		return "ZXhhbXBsZQ=="
+	case "password":
+		return "Password1!"
+	case "binary":
+		return "example"
+	}
+	return "string"
+}
+
+// String renders an example value for a path, query, header, or form field.
+func String(v any) string {
+	switch x := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return x
+	}
+	b, _ := json.Marshal(v)
+	return string(b)
+}
+
+func obj(v any) map[string]any {
+	m, _ := v.(map[string]any)
+	return m
+}
+
+func list(v any) []any {
+	l, _ := v.([]any)
+	return l
+}
+
+func str(v any) string {
+	switch x := v.(type) {
+	case string:
+		return x
+	case json.Number:
+		return x.String()
+	}
+	return ""
+}
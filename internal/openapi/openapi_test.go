@@ -0,0 +1,142 @@
+package openapi
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+const petstore = `openapi: 3.0.0
+info: {title: Petstore, version: "2"}
+servers:
+  - url: https://{env}.example.com/v1
+    variables:
+      env: {default: api}
+security:
+  - bearer: []
+components:
+  securitySchemes:
+    bearer: {type: http, scheme: bearer}
+    key: {type: apiKey, in: header, name: X-API-Key}
+  parameters:
+    PetID: {name: petId, in: path, required: true, schema: {type: integer, format: int64}}
+  schemas:
+    Pet:
+      type: object
+      required: [name]
+      properties:
+        id: {type: integer, readOnly: true}
+        name: {type: string, example: Rex}
+        tags: {type: array, items: {type: string}}
+        owner: {$ref: '#/components/schemas/Owner'}
+    Owner:
+      allOf:
+        - {type: object, properties: {email: {type: string, format: email}}}
+        - {type: object, properties: {pet: {$ref: '#/components/schemas/Pet'}}}
+paths:
+  /pets/{petId}:
+    parameters:
+      - $ref: '#/components/parameters/PetID'
+    get:
+      operationId: getPet
+      tags: [pets]
+      parameters:
+        - {name: fields, in: query, schema: {type: array, items: {type: string, enum: [name, tags]}}}
+        - {name: X-Trace, in: header, example: t1}
+      security:
+        - key: []
+    put:
+      requestBody:
+        content:
+          application/xml: {schema: {type: string}}
+          application/json:
+            schema: {$ref: '#/components/schemas/Pet'}
+`
+
+func TestParse_OpenAPI3(t *testing.T) {
+	s, err := Parse(petstore)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Title != "Petstore" || s.Version != "2" || s.Format != "openapi 3.0.0" || !reflect.DeepEqual(s.Servers, []string{"https://api.example.com/v1"}) {
+		t.Errorf("spec = %+v", s)
+	}
+	if len(s.Endpoints) != 2 {
+		t.Fatalf("endpoints = %+v", s.Endpoints)
+	}
+
+	get := s.Endpoints[0]
+	wantParams := []Param{
+		{Name: "fields", In: "query", Type: "array of string", Example: []any{"name"}},
+		{Name: "X-Trace", In: "header", Example: "t1"},
+		{Name: "petId", In: "path", Required: true, Type: "integer", Example: json.Number("1")},
+	}
+	if get.Method != "GET" || get.OperationID != "getPet" || !reflect.DeepEqual(get.Params, wantParams) || !reflect.DeepEqual(get.Security, []string{"key"}) {
+		t.Errorf("get = %+v", get)
+	}
+
+	put := s.Endpoints[1]
+	if put.ContentType != "application/json" || !reflect.DeepEqual(put.Security, []string{"bearer"}) {
+		t.Errorf("put = %+v", put)
+	}
+	body, _ := json.Marshal(put.Body)
+	// The Pet -> Owner -> Pet cycle stops at the depth limit rather than looping.
+	var decoded map[string]any
+	json.Unmarshal(body, &decoded)
+	if decoded["name"] != "Rex" || decoded["id"] != nil || decoded["owner"].(map[string]any)["email"] != "user@example.com" {
+		t.Errorf("body = %s", body)
+	}
+
+	schemes := s.SecuritySchemes()
+	if schemes["key"] != (SecurityScheme{Type: "apiKey", In: "header", Name: "X-API-Key"}) || schemes["bearer"].Scheme != "bearer" {
+		t.Errorf("schemes = %+v", schemes)
+	}
+}
+
+func TestParse_Swagger2(t *testing.T) {
+	s, err := Parse(`{
+  "swagger": "2.0",
+  "info": {"title": "Legacy", "version": "1"},
+  "host": "legacy.example.com",
+  "basePath": "/api",
+  "schemes": ["http"],
+  "consumes": ["application/json"],
+  "paths": {
+    "/users": {
+      "post": {
+        "parameters": [{"name": "user", "in": "body", "schema": {"$ref": "#/definitions/User"}}]
+      }
+    },
+    "/upload": {
+      "post": {
+        "parameters": [
+          {"name": "file", "in": "formData", "type": "file"},
+          {"name": "note", "in": "formData", "type": "string", "default": "hi"}
+        ]
+      }
+    }
+  },
+  "definitions": {"User": {"properties": {"age": {"type": "integer", "minimum": 18}}}}
+}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(s.Servers, []string{"http://legacy.example.com/api"}) || len(s.Endpoints) != 2 {
+		t.Fatalf("spec = %+v", s)
+	}
+	upload, users := s.Endpoints[0], s.Endpoints[1]
+	if upload.ContentType != "multipart/form-data" || !reflect.DeepEqual(upload.Body, map[string]any{"file": "string", "note": "hi"}) {
+		t.Errorf("upload = %+v", upload)
+	}
+	if users.ContentType != "application/json" || !reflect.DeepEqual(users.Body, map[string]any{"age": json.Number("18")}) {
+		t.Errorf("users = %+v", users)
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	for _, doc := range []string{`{"info": {}}`, `[1]`, `{`, "a: [b"} {
+		if _, err := Parse(doc); err == nil {
+			t.Errorf("%q: expected error", doc)
+		}
+	}
+}
@@ -0,0 +1,249 @@
+// Package passive checks proxy history entries against regex rules, read
+// from YAML, and turns matches into scanner issues: findings Burp's own
+// passive scanner doesn't report, such as stack traces or internal
+// addresses leaking into responses.
+package passive
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/c0tton-fluff/burp-mcp-server/internal/store"
+	"github.com/c0tton-fluff/burp-mcp-server/internal/yaml"
+)
+
+// Source is recorded on every issue a rule emits.
+const Source = "passive"
+
+// maxEvidence caps the matched text quoted in an issue detail.
+const maxEvidence = 200
+
+// Parts of an entry a rule can match: a whole message, or its URL (the
+// request line's target), headers, or body.
+var Parts = []string{"request", "request.url", "request.headers", "request.body", "response", "response.headers", "response.body"}
+
+// Rule emits an issue for every entry whose part matches Pattern.
+type Rule struct {
+	Name       string `json:"name"`
+	Severity   string `json:"severity"`             // information, low, medium, or high
+	Confidence string `json:"confidence,omitempty"` // certain, firm (default), or tentative
+	In         string `json:"in"`                   // one of Parts
+	// Header limits a headers part to the named header.
+	Header  string `json:"header,omitempty"`
+	Pattern string `json:"pattern"`
+	Detail  string `json:"detail,omitempty"`
+
+	re *regexp.Regexp
+}
+
+// Defaults are the built-in rules, run unless the config turns them off.
+var Defaults = mustCompile([]Rule{
+	{
+		Name:     "Verbose stack trace",
+		Severity: "low",
+		In:       "response.body",
+		Pattern: `\bat [\w$.<>]+\([\w$]+\.(?:java|kt|scala):\d+\)` + // JVM
+			`|\bat [\w.<>\x60]+\(.*\) in .+:line \d+` + // .NET
+			`|Traceback \(most recent call last\)` + // Python
+			`|(?:Fatal error|Parse error|Warning)(?:</b>)?: .+ on line (?:<b>)?\d+` + // PHP
+			`|\bat (?:\S+ )?\(?/[^\s:()]+\.[cm]?js:\d+:\d+\)?` + // Node
+			`|goroutine \d+ \[running\]` + // Go
+			`|\.rb:\d+:in \x60`, // Ruby
+		Detail: "The response contains a stack trace, disclosing source paths, frameworks, and internal structure.",
+	},
+	{
+		Name:       "Internal IP address disclosure",
+		Severity:   "information",
+		Confidence: "certain",
+		In:         "response",
+		Pattern:    `\b(?:10\.\d{1,3}|172\.(?:1[6-9]|2\d|3[01])|192\.168)\.\d{1,3}\.\d{1,3}\b`,
+		Detail:     "The response discloses an RFC 1918 address, revealing internal network layout.",
+	},
+	{
+		Name:     "Session token in URL",
+		Severity: "medium",
+		In:       "request.url",
+		Pattern:  `(?i)[?&;](?:jsessionid|phpsessid|aspsessionid\w*|asp\.net_sessionid|sessionid|session_id|sid|access_token|auth_token|api_?key)=[^&#;\s]{8,}`,
+		Detail:   "The URL carries a session token or credential, which leaks through logs, browser history, and Referer headers.",
+	},
+})
+
+func mustCompile(rules []Rule) []Rule {
+	if err := Compile(rules); err != nil {
+		panic(err)
+	}
+	return rules
+}
+
+// Compile validates rules and compiles their patterns, filling in defaults.
+func Compile(rules []Rule) error {
+	for i := range rules {
+		r := &rules[i]
+		if r.Name == "" {
+			return fmt.Errorf("rule %d: name is required", i+1)
+		}
+		r.Severity = strings.ToLower(r.Severity)
+		if !slices.Contains([]string{"information", "low", "medium", "high"}, r.Severity) {
+			return fmt.Errorf("rule %q: severity must be information, low, medium, or high", r.Name)
+		}
+		r.Confidence = strings.ToLower(r.Confidence)
+		if r.Confidence == "" {
+			r.Confidence = "firm"
+		}
+		if !slices.Contains([]string{"certain", "firm", "tentative"}, r.Confidence) {
+			return fmt.Errorf("rule %q: confidence must be certain, firm, or tentative", r.Name)
+		}
+		if !slices.Contains(Parts, r.In) {
+			return fmt.Errorf("rule %q: in must be one of %s", r.Name, strings.Join(Parts, ", "))
+		}
+		if r.Header != "" && !strings.HasSuffix(r.In, ".headers") {
+			return fmt.Errorf("rule %q: header applies to request.headers or response.headers", r.Name)
+		}
+		re, err := regexp.Compile(r.Pattern)
+		if err != nil {
+			return fmt.Errorf("rule %q: pattern: %w", r.Name, err)
+		}
+		if r.Pattern == "" || re.MatchString("") {
+			return fmt.Errorf("rule %q: pattern matches empty text", r.Name)
+		}
+		r.re = re
+	}
+	return nil
+}
+
+// ruleFile is the layout of a rules file.
+type ruleFile struct {
+	Rules []Rule `json:"rules"`
+}
+
+// Load reads and compiles a YAML (or JSON) rules file:
+//
+//	rules:
+//	  - name: Debug header
+//	    severity: low
+//	    in: response.headers
+//	    header: X-Debug-Token
+//	    pattern: .
+func Load(path string) ([]Rule, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read passive rules: %w", err)
+	}
+	doc, err := yaml.Decode(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("passive rules %s: %w", path, err)
+	}
+	// Decode gives encoding/json values, so a round trip fills the structs.
+	raw, err := json.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("passive rules %s: %w", path, err)
+	}
+	var f ruleFile
+	if err := json.Unmarshal(raw, &f); err != nil {
+		return nil, fmt.Errorf("passive rules %s: %w", path, err)
+	}
+	if err := Compile(f.Rules); err != nil {
+		return nil, fmt.Errorf("passive rules %s: %w", path, err)
+	}
+	return f.Rules, nil
+}
+
+// Match is a rule that matched an entry, with the text it matched.
+type Match struct {
+	Rule     *Rule
+	Evidence string
+}
+
+// Check runs rules over one request and its response, returning a match
+// per rule at most.
+func Check(rules []Rule, request, response string) []Match {
+	var matches []Match
+	for i := range rules {
+		r := &rules[i]
+		if m := r.re.FindString(r.part(request, response)); m != "" {
+			if len(m) > maxEvidence {
+				m = m[:maxEvidence] + "..."
+			}
+			matches = append(matches, Match{Rule: r, Evidence: m})
+		}
+	}
+	return matches
+}
+
+// part returns the text of the part r matches.
+func (r *Rule) part(request, response string) string {
+	msg, part, _ := strings.Cut(r.In, ".")
+	raw := request
+	if msg == "response" {
+		raw = response
+	}
+	head, body := splitMessage(raw)
+	switch part {
+	case "url":
+		line, _, _ := strings.Cut(head, "\n")
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			return ""
+		}
+		return fields[1]
+	case "headers":
+		_, headers, _ := strings.Cut(head, "\n")
+		if r.Header == "" {
+			return headers
+		}
+		var values []string
+		for _, line := range strings.Split(headers, "\n") {
+			if name, value, ok := strings.Cut(line, ":"); ok && strings.EqualFold(strings.TrimSpace(name), r.Header) {
+				values = append(values, strings.TrimSpace(value))
+			}
+		}
+		return strings.Join(values, "\n")
+	case "body":
+		return body
+	}
+	return raw
+}
+
+// splitMessage splits a raw HTTP message at the blank line after its headers.
+func splitMessage(raw string) (head, body string) {
+	if i := strings.Index(raw, "\r\n\r\n"); i >= 0 {
+		return strings.ReplaceAll(raw[:i], "\r\n", "\n"), raw[i+4:]
+	}
+	if i := strings.Index(raw, "\n\n"); i >= 0 {
+		return raw[:i], raw[i+2:]
+	}
+	return raw, ""
+}
+
+// Issue converts m, found in the entry for rawURL, into a store issue.
+// Issues key on name, host, and path, so a rule reports each endpoint once.
+func (m Match) Issue(rawURL, request, response string) store.Issue {
+	r := m.Rule
+	issue := store.Issue{
+		Type:        Source + ":" + r.Name,
+		Name:        r.Name,
+		Host:        rawURL,
+		Severity:    severityNames[r.Severity],
+		Confidence:  strings.ToUpper(r.Confidence[:1]) + r.Confidence[1:],
+		IssueDetail: strings.TrimSpace(fmt.Sprintf("%s Matched %q in the %s.", r.Detail, m.Evidence, strings.ReplaceAll(r.In, ".", " "))),
+		Evidence:    []store.IssueEvidence{{Request: request, Response: response}},
+		Source:      Source,
+	}
+	if u, err := url.Parse(rawURL); err == nil && u.Scheme != "" && u.Host != "" {
+		issue.Host = u.Scheme + "://" + u.Host
+		issue.Path = u.EscapedPath()
+	}
+	return issue
+}
+
+var severityNames = map[string]string{
+	"information": "Information",
+	"low":         "Low",
+	"medium":      "Medium",
+	"high":        "High",
+}
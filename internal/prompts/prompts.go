@@ -0,0 +1,186 @@
+// Package prompts registers MCP prompts: pentest playbooks that walk a
+// client through multi-step workflows over this server's tools.
+package prompts
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// playbook is one prompt. render turns the prompt's arguments into the
+// instructions sent to the model; it has checked required arguments.
+type playbook struct {
+	prompt *mcp.Prompt
+	render func(args map[string]string) (string, error)
+}
+
+var playbooks = []playbook{
+	{
+		prompt: &mcp.Prompt{
+			Name:        "triage-proxy-history",
+			Title:       "Triage proxy history",
+			Description: "Walk recent proxy history, group it by endpoint, and pick the requests worth testing first.",
+			Arguments: []*mcp.PromptArgument{
+				{Name: "host", Description: "Only consider requests to this host"},
+				{Name: "count", Description: "How many recent entries to review (default 50)"},
+			},
+		},
+		render: triageProxyHistory,
+	},
+	{
+		prompt: &mcp.Prompt{
+			Name:        "test-auth-on-endpoint",
+			Title:       "Test authorization on an endpoint",
+			Description: "Check one captured request for broken access control: replay it as other identities, strip its credentials, and sweep its identifiers.",
+			Arguments: []*mcp.PromptArgument{
+				{Name: "index", Description: "Proxy history index (1-based) of the request", Required: true},
+				{Name: "identities", Description: "Comma-separated auth profiles from config, most privileged first, e.g. admin,user"},
+			},
+		},
+		render: testAuthOnEndpoint,
+	},
+	{
+		prompt: &mcp.Prompt{
+			Name:        "analyze-scanner-issue",
+			Title:       "Analyze a scanner issue",
+			Description: "Confirm or dismiss one scanner issue by reproducing it, and record it as a finding when it holds.",
+			Arguments: []*mcp.PromptArgument{
+				{Name: "offset", Description: "Position of the issue in burp_get_scanner_issues (0-based)", Required: true},
+				{Name: "source", Description: "live (default) or imported"},
+			},
+		},
+		render: analyzeScannerIssue,
+	},
+	{
+		prompt: &mcp.Prompt{
+			Name:        "retest-findings",
+			Title:       "Retest recorded findings",
+			Description: "Replay every open finding and report which are fixed, still vulnerable, or could not be replayed.",
+		},
+		render: retestFindings,
+	},
+}
+
+// Register adds the playbook prompts to server.
+func Register(server *mcp.Server) {
+	for _, p := range playbooks {
+		server.AddPrompt(p.prompt, handler(p))
+	}
+}
+
+func handler(p playbook) mcp.PromptHandler {
+	return func(_ context.Context, req *mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+		args := req.Params.Arguments
+		for _, a := range p.prompt.Arguments {
+			if a.Required && strings.TrimSpace(args[a.Name]) == "" {
+				return nil, fmt.Errorf("argument %q is required", a.Name)
+			}
+		}
+		text, err := p.render(args)
+		if err != nil {
+			return nil, err
+		}
+		return &mcp.GetPromptResult{
+			Description: p.prompt.Description,
+			Messages:    []*mcp.PromptMessage{{Role: "user", Content: &mcp.TextContent{Text: text}}},
+		}, nil
+	}
+}
+
+// intArg parses an optional non-negative integer argument.
+func intArg(args map[string]string, name string, def int) (int, error) {
+	v := strings.TrimSpace(args[name])
+	if v == "" {
+		return def, nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("argument %q must be a non-negative integer", name)
+	}
+	return n, nil
+}
+
+func triageProxyHistory(args map[string]string) (string, error) {
+	count, err := intArg(args, "count", 50)
+	if err != nil {
+		return "", err
+	}
+	host := strings.TrimSpace(args["host"])
+	focus := "all hosts"
+	if host != "" {
+		focus = "requests to " + host + " only"
+	}
+	return fmt.Sprintf(`Triage the most recent %d entries of Burp's proxy history (%s) and decide what to test first.
+
+1. Call burp_get_proxy_history with count=%[1]d, paging with offset if needed. Group the entries by method and path, collapsing IDs in paths.
+2. For each group, fetch one representative with burp_get_request (index). Note authentication (cookies, Authorization), identifiers in the path, query, or body, file or URL parameters, and state-changing methods.
+3. Run burp_audit_headers and burp_analyze_cookies on an authenticated response, and burp_grep_responses over the indexes for secrets, stack traces, and internal hostnames.
+4. Rank the groups by likely impact: object identifiers (IDOR), privileged functions, user-controlled URLs (SSRF), file handling, and reflected input.
+
+Reply with a table of endpoint, why it is interesting, and the next tool to use (for example burp_authz_matrix, burp_idor_sweep, burp_ssrf_probe, burp_xss_verify). Do not send attack traffic during triage.`, count, focus), nil
+}
+
+func testAuthOnEndpoint(args map[string]string) (string, error) {
+	index, err := intArg(args, "index", 0)
+	if err != nil {
+		return "", err
+	}
+	if index == 0 {
+		return "", fmt.Errorf("argument %q must be a proxy history index (1-based)", "index")
+	}
+	identities := "the request as captured, then anonymous"
+	if v := strings.TrimSpace(args["identities"]); v != "" {
+		var names []string
+		for _, name := range strings.Split(v, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				names = append(names, name)
+			}
+		}
+		identities = fmt.Sprintf("the auth profiles %s in that order, then anonymous", strings.Join(names, ", "))
+	}
+	return fmt.Sprintf(`Test authorization on the request at proxy history index %d.
+
+1. Fetch it with burp_get_request (index=%[1]d). Identify the credentials it carries, the resource it touches, and any object identifiers.
+2. Call burp_authz_matrix with requests=[{index: %[1]d}] and identities for %s. Treat "bypass" and "unexpected" cells as leads, not findings.
+3. Confirm each lead with burp_send_request, comparing the body with the reference identity's, not just the status code.
+4. If the request names an object by ID, run burp_idor_sweep with the ID replaced by {{id}} and a notFound rule or baselineId taken from a known-missing object.
+5. If a 403 or 401 stands, try burp_forbidden_bypass and burp_method_probe on the same request.
+
+For every confirmed issue call burp_record_finding with the reproducing steps and an assertion that holds only while the issue is present. Finish with a short table of identity, outcome, and evidence.`, index, identities), nil
+}
+
+func analyzeScannerIssue(args map[string]string) (string, error) {
+	offset, err := intArg(args, "offset", 0)
+	if err != nil {
+		return "", err
+	}
+	source := strings.TrimSpace(args["source"])
+	if source == "" {
+		source = "live"
+	}
+	if source != "live" && source != "imported" {
+		return "", fmt.Errorf("argument %q must be live or imported", "source")
+	}
+	return fmt.Sprintf(`Decide whether one scanner issue is real.
+
+1. Call burp_get_scanner_issues with source=%s, count=1, offset=%d, and detailLimit=-1. Read the issue's name, URL, severity, confidence, and detail.
+2. Find the affected request in proxy history (burp_get_proxy_history, then burp_get_request) or build it with burp_from_curl or burp_modify_request.
+3. Reproduce the issue with the narrowest tool: burp_send_request for a single check, or the matching probe (burp_xss_verify, burp_ssti_probe, burp_ssrf_probe with burp_get_callbacks, burp_traversal_probe, burp_cors_probe, burp_cache_probe, burp_host_header_probe). For SQL injection, hand off with burp_export_sqlmap.
+4. Judge it: confirmed, false positive, or needs manual work. Explain the evidence and rate severity for this application, not the scanner's default.
+
+If confirmed, call burp_record_finding with the reproducing steps and assertions so burp_retest_finding can check the fix later.`, source, offset), nil
+}
+
+func retestFindings(map[string]string) (string, error) {
+	return `Retest every open finding and report where each stands.
+
+1. Call burp_engagement_summary and take findings.open: each has an id, title, severity, and last verdict.
+2. Call burp_retest_finding with each findingId. The verdict is fixed, vulnerable, or error; for errors, read the step errors and check whether the session or target changed.
+3. For findings still vulnerable, note whether severity changed.
+
+Reply with a table of finding, severity, previous verdict, new verdict, and a one-line note.`, nil
+}
@@ -0,0 +1,101 @@
+package prompts
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// registeredTools reads the tool names registered in internal/tools.
+func registeredTools(t *testing.T) map[string]bool {
+	t.Helper()
+	files, err := filepath.Glob("../tools/*.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	name := regexp.MustCompile(`Name:\s+"(burp_\w+)"`)
+	tools := map[string]bool{}
+	for _, f := range files {
+		data, err := os.ReadFile(f)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, m := range name.FindAllStringSubmatch(string(data), -1) {
+			tools[m[1]] = true
+		}
+	}
+	return tools
+}
+
+func TestPlaybooksNameRealTools(t *testing.T) {
+	tools := registeredTools(t)
+	mention := regexp.MustCompile(`burp_\w+`)
+	for _, p := range playbooks {
+		args := map[string]string{}
+		for _, a := range p.prompt.Arguments {
+			if a.Required {
+				args[a.Name] = "1"
+			}
+		}
+		text, err := p.render(args)
+		if err != nil {
+			t.Fatalf("%s: %v", p.prompt.Name, err)
+		}
+		for _, m := range mention.FindAllString(text, -1) {
+			if !tools[m] {
+				t.Errorf("%s mentions unknown tool %s", p.prompt.Name, m)
+			}
+		}
+	}
+}
+
+func TestGetPrompt(t *testing.T) {
+	server := mcp.NewServer(&mcp.Implementation{Name: "test"}, nil)
+	Register(server)
+	client := mcp.NewClient(&mcp.Implementation{Name: "client"}, nil)
+	ct, st := mcp.NewInMemoryTransports()
+	ctx := context.Background()
+	ss, err := server.Connect(ctx, st, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ss.Close()
+	cs, err := client.Connect(ctx, ct, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cs.Close()
+
+	list, err := cs.ListPrompts(ctx, nil)
+	if err != nil || len(list.Prompts) != len(playbooks) {
+		t.Fatalf("prompts = %v, %v", list, err)
+	}
+
+	res, err := cs.GetPrompt(ctx, &mcp.GetPromptParams{
+		Name:      "test-auth-on-endpoint",
+		Arguments: map[string]string{"index": "7", "identities": "admin, user"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	text := res.Messages[0].Content.(*mcp.TextContent).Text
+	if !strings.Contains(text, "requests=[{index: 7}]") || !strings.Contains(text, "admin, user in that order") {
+		t.Errorf("text = %s", text)
+	}
+
+	for _, params := range []*mcp.GetPromptParams{
+		{Name: "test-auth-on-endpoint"},
+		{Name: "test-auth-on-endpoint", Arguments: map[string]string{"index": "x"}},
+		{Name: "analyze-scanner-issue", Arguments: map[string]string{"offset": "0", "source": "other"}},
+		{Name: "triage-proxy-history", Arguments: map[string]string{"count": "-1"}},
+	} {
+		if _, err := cs.GetPrompt(ctx, params); err == nil {
+			t.Errorf("%s %v: expected error", params.Name, params.Arguments)
+		}
+	}
+}
@@ -0,0 +1,340 @@
+// Package saml decodes SAML protocol messages as they travel through the
+// browser and summarizes what matters for testing an SSO integration:
+// who issued what to whom, and which parts are signed.
+package saml
+
+import (
+	"bytes"
+	"compress/flate"
+	"encoding/base64"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Decode turns a SAMLRequest or SAMLResponse parameter value into XML. The
+// value may still be URL-encoded; the HTTP-Redirect binding also deflates
+// it, which deflated reports.
+func Decode(value string) (doc []byte, deflated bool, err error) {
+	value = strings.TrimSpace(value)
+	if strings.Contains(value, "%") {
+		if v, err := url.QueryUnescape(value); err == nil {
+			value = v
+		}
+	}
+	// A '+' that went through form decoding comes back as a space.
+	value = strings.ReplaceAll(value, " ", "+")
+	value = strings.Join(strings.Fields(value), "")
+
+	data, err := base64.StdEncoding.DecodeString(value)
+	if err != nil {
+		if data, err = base64.RawStdEncoding.DecodeString(strings.TrimRight(value, "=")); err != nil {
+			return nil, false, fmt.Errorf("not base64: %w", err)
+		}
+	}
+	if inflated, err := io.ReadAll(flate.NewReader(bytes.NewReader(data))); err == nil && looksLikeXML(inflated) {
+		return inflated, true, nil
+	}
+	if looksLikeXML(data) {
+		return data, false, nil
+	}
+	return nil, false, errors.New("decoded value is neither XML nor deflated XML")
+}
+
+func looksLikeXML(b []byte) bool {
+	return bytes.HasPrefix(bytes.TrimSpace(bytes.TrimPrefix(b, []byte("\xef\xbb\xbf"))), []byte("<"))
+}
+
+// Indent pretty-prints doc two spaces per level, keeping namespace prefixes
+// as written. Whitespace-only text is dropped; comments and DOCTYPEs are
+// kept, since a comment inside a NameID or a DOCTYPE is itself a finding.
+func Indent(doc []byte) (string, error) {
+	d := xml.NewDecoder(bytes.NewReader(doc))
+	var sb strings.Builder
+	depth := 0
+	// lineOpen means the current line has content and no newline yet, so
+	// an end tag or text continues it.
+	lineOpen := false
+	newline := func() {
+		if lineOpen {
+			sb.WriteString("\n")
+			lineOpen = false
+		}
+	}
+	for {
+		tok, err := d.RawToken()
+		if err == io.EOF {
+			return strings.TrimSuffix(sb.String(), "\n"), nil
+		}
+		if err != nil {
+			return sb.String(), err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			newline()
+			sb.WriteString(strings.Repeat("  ", depth) + "<" + qname(t.Name))
+			for _, a := range t.Attr {
+				sb.WriteString(" " + qname(a.Name) + `="`)
+				xml.EscapeText(&sb, []byte(a.Value))
+				sb.WriteString(`"`)
+			}
+			sb.WriteString(">")
+			depth++
+			lineOpen = true
+		case xml.EndElement:
+			depth--
+			if !lineOpen {
+				sb.WriteString(strings.Repeat("  ", depth))
+			}
+			sb.WriteString("</" + qname(t.Name) + ">\n")
+			lineOpen = false
+		case xml.CharData:
+			if s := strings.TrimSpace(string(t)); s != "" {
+				xml.EscapeText(&sb, []byte(s))
+				lineOpen = true
+			}
+		case xml.Comment:
+			newline()
+			sb.WriteString(strings.Repeat("  ", depth) + "<!--" + string(t) + "-->\n")
+		case xml.ProcInst:
+			newline()
+			sb.WriteString("<?" + t.Target + " " + string(t.Inst) + "?>\n")
+		case xml.Directive:
+			newline()
+			sb.WriteString("<!" + string(t) + ">\n")
+		}
+	}
+}
+
+func qname(n xml.Name) string {
+	if n.Space == "" {
+		return n.Local
+	}
+	return n.Space + ":" + n.Local
+}
+
+type signatureXML struct {
+	SignedInfo struct {
+		SignatureMethod struct {
+			Algorithm string `xml:"Algorithm,attr"`
+		} `xml:"SignatureMethod"`
+		Reference []struct {
+			URI          string `xml:"URI,attr"`
+			DigestMethod struct {
+				Algorithm string `xml:"Algorithm,attr"`
+			} `xml:"DigestMethod"`
+		} `xml:"Reference"`
+	} `xml:"SignedInfo"`
+}
+
+type nameIDXML struct {
+	Format string `xml:"Format,attr"`
+	Value  string `xml:",chardata"`
+}
+
+type assertionXML struct {
+	ID        string        `xml:"ID,attr"`
+	Issuer    string        `xml:"Issuer"`
+	Signature *signatureXML `xml:"Signature"`
+	Subject   struct {
+		NameID              nameIDXML `xml:"NameID"`
+		SubjectConfirmation []struct {
+			Data struct {
+				NotOnOrAfter string `xml:"NotOnOrAfter,attr"`
+				Recipient    string `xml:"Recipient,attr"`
+			} `xml:"SubjectConfirmationData"`
+		} `xml:"SubjectConfirmation"`
+	} `xml:"Subject"`
+	Conditions struct {
+		NotBefore    string   `xml:"NotBefore,attr"`
+		NotOnOrAfter string   `xml:"NotOnOrAfter,attr"`
+		Audiences    []string `xml:"AudienceRestriction>Audience"`
+	} `xml:"Conditions"`
+	Attributes []struct {
+		Name   string   `xml:"Name,attr"`
+		Values []string `xml:"AttributeValue"`
+	} `xml:"AttributeStatement>Attribute"`
+}
+
+type messageXML struct {
+	XMLName      xml.Name
+	ID           string        `xml:"ID,attr"`
+	Destination  string        `xml:"Destination,attr"`
+	IssueInstant string        `xml:"IssueInstant,attr"`
+	InResponseTo string        `xml:"InResponseTo,attr"`
+	ACSURL       string        `xml:"AssertionConsumerServiceURL,attr"`
+	Issuer       string        `xml:"Issuer"`
+	Signature    *signatureXML `xml:"Signature"`
+	Status       struct {
+		Code struct {
+			Value string `xml:"Value,attr"`
+		} `xml:"StatusCode"`
+	} `xml:"Status"`
+	Assertions []assertionXML `xml:"Assertion"`
+	Encrypted  []struct{}     `xml:"EncryptedAssertion"`
+}
+
+// Assertion summarizes one saml:Assertion.
+type Assertion struct {
+	ID           string              `json:"id,omitempty"`
+	Issuer       string              `json:"issuer,omitempty"`
+	Signed       bool                `json:"signed"`
+	NameID       string              `json:"nameId,omitempty"`
+	NameIDFormat string              `json:"nameIdFormat,omitempty"`
+	Recipient    string              `json:"recipient,omitempty"`
+	NotBefore    string              `json:"notBefore,omitempty"`
+	NotOnOrAfter string              `json:"notOnOrAfter,omitempty"`
+	Audiences    []string            `json:"audiences,omitempty"`
+	Attributes   map[string][]string `json:"attributes,omitempty"`
+}
+
+// Message summarizes a SAML protocol message.
+type Message struct {
+	Type                string      `json:"type"`
+	ID                  string      `json:"id,omitempty"`
+	Issuer              string      `json:"issuer,omitempty"`
+	Destination         string      `json:"destination,omitempty"`
+	IssueInstant        string      `json:"issueInstant,omitempty"`
+	InResponseTo        string      `json:"inResponseTo,omitempty"`
+	ACSURL              string      `json:"assertionConsumerServiceUrl,omitempty"`
+	Status              string      `json:"status,omitempty"`
+	Signed              bool        `json:"signed"`
+	Assertions          []Assertion `json:"assertions,omitempty"`
+	EncryptedAssertions int         `json:"encryptedAssertions,omitempty"`
+	Issues              []string    `json:"issues,omitempty"`
+}
+
+// Analyze summarizes doc and lists what's worth testing at time now:
+// unsigned messages and assertions, signature wrapping setups, weak
+// algorithms, expired conditions, and comments inside a NameID.
+func Analyze(doc []byte, now time.Time) (*Message, error) {
+	var m messageXML
+	if err := xml.Unmarshal(doc, &m); err != nil {
+		return nil, fmt.Errorf("not a SAML message: %w", err)
+	}
+	out := &Message{
+		Type:                m.XMLName.Local,
+		ID:                  m.ID,
+		Issuer:              strings.TrimSpace(m.Issuer),
+		Destination:         m.Destination,
+		IssueInstant:        m.IssueInstant,
+		InResponseTo:        m.InResponseTo,
+		ACSURL:              m.ACSURL,
+		Status:              strings.TrimPrefix(m.Status.Code.Value, "urn:oasis:names:tc:SAML:2.0:status:"),
+		Signed:              m.Signature != nil,
+		EncryptedAssertions: len(m.Encrypted),
+	}
+	issues := signatureIssues(out.Type, m.ID, m.Signature)
+
+	for _, a := range m.Assertions {
+		s := Assertion{
+			ID:           a.ID,
+			Issuer:       strings.TrimSpace(a.Issuer),
+			Signed:       a.Signature != nil,
+			NameID:       strings.TrimSpace(a.Subject.NameID.Value),
+			NameIDFormat: a.Subject.NameID.Format,
+			NotBefore:    a.Conditions.NotBefore,
+			NotOnOrAfter: a.Conditions.NotOnOrAfter,
+			Audiences:    a.Conditions.Audiences,
+		}
+		for _, sc := range a.Subject.SubjectConfirmation {
+			if s.Recipient == "" {
+				s.Recipient = sc.Data.Recipient
+			}
+			if s.NotOnOrAfter == "" {
+				s.NotOnOrAfter = sc.Data.NotOnOrAfter
+			}
+		}
+		for _, attr := range a.Attributes {
+			if s.Attributes == nil {
+				s.Attributes = make(map[string][]string)
+			}
+			s.Attributes[attr.Name] = append(s.Attributes[attr.Name], attr.Values...)
+		}
+		out.Assertions = append(out.Assertions, s)
+
+		label := "assertion " + a.ID
+		switch {
+		case a.Signature == nil && m.Signature == nil:
+			issues = append(issues, label+" is unsigned and so is the response: try forging any NameID")
+		case a.Signature == nil:
+			issues = append(issues, label+" is unsigned (only the response is signed): check the SP requires a signed assertion")
+		default:
+			issues = append(issues, signatureIssues("assertion", a.ID, a.Signature)...)
+		}
+		if t, err := time.Parse(time.RFC3339, s.NotOnOrAfter); err == nil && now.After(t) {
+			issues = append(issues, fmt.Sprintf("%s expired at %s: check whether the SP replays it", label, s.NotOnOrAfter))
+		}
+		if len(s.Audiences) == 0 {
+			issues = append(issues, label+" has no AudienceRestriction: it may be accepted by other SPs")
+		}
+	}
+	if len(m.Assertions) > 1 {
+		issues = append(issues, fmt.Sprintf("%d assertions in one message: the layout signature wrapping (XSW) relies on", len(m.Assertions)))
+	}
+	if out.Type == "Response" && len(m.Assertions) == 0 && len(m.Encrypted) == 0 {
+		issues = append(issues, "response carries no assertion")
+	}
+	if out.Status != "" && out.Status != "Success" {
+		issues = append(issues, "status is "+out.Status)
+	}
+	if bytes.Contains(doc, []byte("<!DOCTYPE")) {
+		issues = append(issues, "message has a DOCTYPE: if the SP parses it, test XXE")
+	}
+	if nameIDComment(doc) {
+		issues = append(issues, "an XML comment inside a NameID: canonicalization drops it, so the SP may read a truncated identity")
+	}
+	out.Issues = issues
+	return out, nil
+}
+
+func signatureIssues(what, id string, sig *signatureXML) []string {
+	if sig == nil {
+		if what == "AuthnRequest" || what == "LogoutRequest" {
+			return []string{what + " has no embedded signature (the Redirect binding signs in the query string instead)"}
+		}
+		if what == "Response" {
+			return []string{"response is unsigned"}
+		}
+		return nil
+	}
+	var issues []string
+	if alg := sig.SignedInfo.SignatureMethod.Algorithm; strings.Contains(strings.ToLower(alg), "sha1") {
+		issues = append(issues, fmt.Sprintf("%s %s is signed with SHA-1 (%s)", what, id, alg))
+	}
+	for _, ref := range sig.SignedInfo.Reference {
+		if strings.Contains(strings.ToLower(ref.DigestMethod.Algorithm), "sha1") {
+			issues = append(issues, fmt.Sprintf("%s %s uses a SHA-1 digest", what, id))
+		}
+		if ref.URI != "" && ref.URI != "#"+id {
+			issues = append(issues, fmt.Sprintf("%s %s signature references %s, not its own ID: check what the SP verifies", what, id, ref.URI))
+		}
+	}
+	return issues
+}
+
+// nameIDComment reports whether any NameID element contains a comment.
+func nameIDComment(doc []byte) bool {
+	d := xml.NewDecoder(bytes.NewReader(doc))
+	inNameID := false
+	for {
+		tok, err := d.RawToken()
+		if err != nil {
+			return false
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			inNameID = t.Name.Local == "NameID"
+		case xml.EndElement:
+			inNameID = false
+		case xml.Comment:
+			if inNameID {
+				return true
+			}
+		}
+	}
+}
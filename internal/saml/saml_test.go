@@ -0,0 +1,114 @@
+package saml
+
+import (
+	"bytes"
+	"compress/flate"
+	"encoding/base64"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testResponse = `<samlp:Response xmlns:samlp="urn:oasis:names:tc:SAML:2.0:protocol" xmlns:saml="urn:oasis:names:tc:SAML:2.0:assertion" ID="r1" Destination="https://sp.example/acs" InResponseTo="req1">
+  <saml:Issuer>https://idp.example</saml:Issuer>
+  <ds:Signature xmlns:ds="http://www.w3.org/2000/09/xmldsig#">
+    <ds:SignedInfo>
+      <ds:SignatureMethod Algorithm="http://www.w3.org/2000/09/xmldsig#rsa-sha1"/>
+      <ds:Reference URI="#r1"><ds:DigestMethod Algorithm="http://www.w3.org/2001/04/xmlenc#sha256"/></ds:Reference>
+    </ds:SignedInfo>
+  </ds:Signature>
+  <samlp:Status><samlp:StatusCode Value="urn:oasis:names:tc:SAML:2.0:status:Success"/></samlp:Status>
+  <saml:Assertion ID="a1">
+    <saml:Issuer>https://idp.example</saml:Issuer>
+    <saml:Subject>
+      <saml:NameID Format="urn:oasis:names:tc:SAML:1.1:nameid-format:emailAddress">admin@example.com<!---->.evil.com</saml:NameID>
+      <saml:SubjectConfirmation Method="urn:oasis:names:tc:SAML:2.0:cm:bearer">
+        <saml:SubjectConfirmationData Recipient="https://sp.example/acs" NotOnOrAfter="2020-01-01T00:00:00Z"/>
+      </saml:SubjectConfirmation>
+    </saml:Subject>
+    <saml:Conditions NotBefore="2019-12-31T00:00:00Z" NotOnOrAfter="2020-01-01T00:00:00Z">
+      <saml:AudienceRestriction><saml:Audience>https://sp.example</saml:Audience></saml:AudienceRestriction>
+    </saml:Conditions>
+    <saml:AttributeStatement>
+      <saml:Attribute Name="role"><saml:AttributeValue>admin</saml:AttributeValue><saml:AttributeValue>user</saml:AttributeValue></saml:Attribute>
+    </saml:AttributeStatement>
+  </saml:Assertion>
+</samlp:Response>`
+
+func TestDecode(t *testing.T) {
+	var buf bytes.Buffer
+	w, _ := flate.NewWriter(&buf, flate.DefaultCompression)
+	w.Write([]byte(`<samlp:AuthnRequest ID="req1"/>`))
+	w.Close()
+	redirect := url.QueryEscape(base64.StdEncoding.EncodeToString(buf.Bytes()))
+
+	doc, deflated, err := Decode(redirect)
+	if err != nil || !deflated || string(doc) != `<samlp:AuthnRequest ID="req1"/>` {
+		t.Errorf("redirect binding: %q, %v, %v", doc, deflated, err)
+	}
+
+	post := base64.StdEncoding.EncodeToString([]byte(testResponse))
+	// Form decoding turns an unencoded '+' into a space.
+	doc, deflated, err = Decode(strings.ReplaceAll(post, "+", " "))
+	if err != nil || deflated || string(doc) != testResponse {
+		t.Errorf("post binding: deflated=%v err=%v", deflated, err)
+	}
+
+	if _, _, err := Decode(base64.StdEncoding.EncodeToString([]byte("hello"))); err == nil {
+		t.Error("expected an error for non-XML")
+	}
+}
+
+func TestAnalyze(t *testing.T) {
+	m, err := Analyze([]byte(testResponse), time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Type != "Response" || m.Issuer != "https://idp.example" || m.Status != "Success" || !m.Signed {
+		t.Errorf("message = %+v", m)
+	}
+	if len(m.Assertions) != 1 {
+		t.Fatalf("assertions = %+v", m.Assertions)
+	}
+	a := m.Assertions[0]
+	if a.Signed || a.NameID != "admin@example.com.evil.com" || a.Recipient != "https://sp.example/acs" ||
+		len(a.Audiences) != 1 || len(a.Attributes["role"]) != 2 {
+		t.Errorf("assertion = %+v", a)
+	}
+
+	want := []string{"SHA-1", "only the response is signed", "expired at", "comment inside a NameID"}
+	if len(m.Issues) != len(want) {
+		t.Fatalf("issues = %q", m.Issues)
+	}
+	for i, w := range want {
+		if !strings.Contains(m.Issues[i], w) {
+			t.Errorf("issue %d = %q, want it to mention %q", i, m.Issues[i], w)
+		}
+	}
+}
+
+func TestAnalyze_UnsignedAndWrapped(t *testing.T) {
+	doc := `<Response><Assertion ID="a1"><Subject><NameID>x</NameID></Subject></Assertion><Assertion ID="a2"/></Response>`
+	m, err := Analyze([]byte(doc), time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	joined := strings.Join(m.Issues, "\n")
+	for _, w := range []string{"response is unsigned", "assertion a1 is unsigned and so is the response", "no AudienceRestriction", "2 assertions"} {
+		if !strings.Contains(joined, w) {
+			t.Errorf("issues missing %q:\n%s", w, joined)
+		}
+	}
+}
+
+func TestIndent(t *testing.T) {
+	got, err := Indent([]byte(`<a:x k="v&amp;"><a:y>text</a:y><!-- c --><a:z/></a:x>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "<a:x k=\"v&amp;\">\n  <a:y>text</a:y>\n  <!-- c -->\n  <a:z></a:z>\n</a:x>"
+	if got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+}
@@ -0,0 +1,97 @@
+// Package secrets finds credentials in HTTP traffic: cloud and SaaS keys by
+// their known formats, private key blocks, and high-entropy values assigned
+// to secret-sounding names.
+package secrets
+
+import (
+	"math"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// Rule detects one kind of secret. The secret is the pattern's first
+// capture group, or the whole match if it has none.
+type Rule struct {
+	ID          string
+	Description string
+	re          *regexp.Regexp
+	// minEntropy, when set, drops candidates with fewer bits per character
+	// and candidates without both letters and digits.
+	minEntropy float64
+}
+
+// Rules are the built-in rules, specific formats first: a value several
+// rules match is reported under the first.
+var Rules = []Rule{
+	{ID: "aws-access-key-id", Description: "AWS access key ID", re: regexp.MustCompile(`\b((?:AKIA|ASIA|ABIA|ACCA)[0-9A-Z]{16})\b`)},
+	{ID: "aws-secret-access-key", Description: "AWS secret access key", re: regexp.MustCompile(`(?i)aws.{0,20}?(?:secret|private).{0,20}?[\s"'=:]([A-Za-z0-9/+]{40})(?:[^A-Za-z0-9/+=]|$)`)},
+	{ID: "github-token", Description: "GitHub token", re: regexp.MustCompile(`\b(gh[pousr]_[A-Za-z0-9]{36,255}|github_pat_[A-Za-z0-9_]{82})\b`)},
+	{ID: "gitlab-token", Description: "GitLab personal access token", re: regexp.MustCompile(`\b(glpat-[A-Za-z0-9_-]{20})\b`)},
+	{ID: "slack-token", Description: "Slack token", re: regexp.MustCompile(`\b(xox[baprs]-[A-Za-z0-9-]{10,})\b`)},
+	{ID: "slack-webhook", Description: "Slack incoming webhook", re: regexp.MustCompile(`https://hooks\.slack\.com/services/[A-Za-z0-9/]{20,}`)},
+	{ID: "stripe-key", Description: "Stripe live secret key", re: regexp.MustCompile(`\b((?:sk|rk)_live_[A-Za-z0-9]{24,})\b`)},
+	{ID: "google-api-key", Description: "Google API key", re: regexp.MustCompile(`\b(AIza[0-9A-Za-z_-]{35})\b`)},
+	{ID: "private-key", Description: "Private key block", re: regexp.MustCompile(`-----BEGIN[A-Z ]*PRIVATE KEY-----(?:[\s\S]*?-----END[A-Z ]*PRIVATE KEY-----)?`)},
+	{
+		ID:          "generic-high-entropy",
+		Description: "High-entropy value assigned to a secret-like name",
+		re:          regexp.MustCompile(`(?i)(?:secret|token|passw(?:or)?d|api[_-]?key|access[_-]?key|private[_-]?key|credential)[\w-]*["']?\s*[:=]\s*["']?([A-Za-z0-9+/=_.~-]{16,})`),
+		minEntropy:  3.5,
+	},
+}
+
+// Hit is one secret found in a text.
+type Hit struct {
+	Rule   *Rule
+	Secret string
+}
+
+// Find returns the secrets in text, each value once, in order of appearance
+// per rule.
+func Find(text string) []Hit {
+	var hits []Hit
+	seen := make(map[string]bool)
+	for i := range Rules {
+		r := &Rules[i]
+		for _, m := range r.re.FindAllStringSubmatch(text, -1) {
+			secret := m[0]
+			if len(m) > 1 {
+				secret = m[1]
+			}
+			if seen[secret] || r.minEntropy > 0 && !random(secret, r.minEntropy) {
+				continue
+			}
+			seen[secret] = true
+			hits = append(hits, Hit{Rule: r, Secret: secret})
+		}
+	}
+	return hits
+}
+
+// random reports whether s looks generated rather than written: letters
+// and digits mixed, with at least minEntropy bits per character.
+func random(s string, minEntropy float64) bool {
+	hasLetter := strings.IndexFunc(s, unicode.IsLetter) >= 0
+	hasDigit := strings.IndexFunc(s, unicode.IsDigit) >= 0
+	return hasLetter && hasDigit && Entropy(s) >= minEntropy
+}
+
+// Entropy returns the Shannon entropy of s in bits per character.
+func Entropy(s string) float64 {
+	if s == "" {
+		return 0
+	}
+	counts := make(map[rune]int)
+	n := 0
+	for _, c := range s {
+		counts[c]++
+		n++
+	}
+	var h float64
+	for _, c := range counts {
+		p := float64(c) / float64(n)
+		h -= p * math.Log2(p)
+	}
+	return h
+}
@@ -0,0 +1,317 @@
+package selftest
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/c0tton-fluff/burp-mcp-server/internal/burp"
+	"github.com/c0tton-fluff/burp-mcp-server/internal/config"
+	"github.com/c0tton-fluff/burp-mcp-server/internal/store"
+	"github.com/c0tton-fluff/burp-mcp-server/internal/tools"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// burpConnectTimeout bounds the doctor's connection attempt to Burp.
+const burpConnectTimeout = 10 * time.Second
+
+// Report prints doctor results as they are found and counts them.
+type Report struct {
+	w                               io.Writer
+	Passed, Warned, Failed, Skipped int
+}
+
+// NewReport returns a report that writes to w.
+func NewReport(w io.Writer) *Report {
+	return &Report{w: w}
+}
+
+// Section starts a group of results.
+func (r *Report) Section(title string) {
+	if r.Passed+r.Warned+r.Failed+r.Skipped > 0 {
+		fmt.Fprintln(r.w)
+	}
+	fmt.Fprintln(r.w, title)
+}
+
+func (r *Report) line(status, name, detail string) {
+	if detail == "" {
+		fmt.Fprintf(r.w, "%-4s  %s\n", status, name)
+		return
+	}
+	fmt.Fprintf(r.w, "%-4s  %s: %s\n", status, name, detail)
+}
+
+// Pass records a check that succeeded.
+func (r *Report) Pass(name, detail string) {
+	r.Passed++
+	r.line("PASS", name, detail)
+}
+
+// Warn records a setting that works but often explains surprising behavior.
+func (r *Report) Warn(name, detail string) {
+	r.Warned++
+	r.line("WARN", name, detail)
+}
+
+// Fail records a broken check and, when known, how to fix it.
+func (r *Report) Fail(name, detail, hint string) {
+	r.Failed++
+	r.line("FAIL", name, detail)
+	if hint != "" {
+		fmt.Fprintf(r.w, "      hint: %s\n", hint)
+	}
+}
+
+// Skip records a check that could not run.
+func (r *Report) Skip(name, reason string) {
+	r.Skipped++
+	fmt.Fprintf(r.w, "SKIP  %s (%s)\n", name, reason)
+}
+
+// Summary prints the totals.
+func (r *Report) Summary() {
+	fmt.Fprintf(r.w, "\n%d passed, %d warnings, %d failed, %d skipped\n", r.Passed, r.Warned, r.Failed, r.Skipped)
+}
+
+// CheckConfig reports on the config loaded from path ("" when no file
+// exists) and returns the config to continue with: cfg, or the defaults
+// when loading failed with err.
+func CheckConfig(r *Report, path string, cfg *config.Config, err error) *config.Config {
+	r.Section("Config")
+	switch {
+	case err != nil:
+		r.Fail("config", err.Error(), "fix the file, or point --config or BURP_MCP_CONFIG at another one")
+		cfg = &config.Config{}
+	case path == "":
+		r.Pass("config", "no config file, using defaults")
+	default:
+		r.Pass("config", "loaded "+path)
+	}
+
+	storePath := cfg.StorePath()
+	if _, err := store.Open(storePath); err != nil {
+		r.Fail("store", err.Error(), "move the damaged file aside; findings and views start empty")
+	} else if err := checkWritable(filepath.Dir(storePath)); err != nil {
+		r.Fail("store", err.Error(), "set store in the config to a writable path")
+	} else {
+		r.Pass("store", storePath)
+	}
+
+	CheckScope(r, cfg.Scope)
+	if cfg.DryRun {
+		r.Warn("dry run", "enabled; live tools return previews and send nothing")
+	}
+	if a := cfg.Approval; a != nil && len(a.Tools) > 0 {
+		r.Warn("approval", fmt.Sprintf("%s wait for 'burp-mcp-server approve' before sending", strings.Join(a.Tools, ", ")))
+	}
+	return cfg
+}
+
+// checkWritable reports whether files can be created in dir, creating it
+// if needed.
+func checkWritable(dir string) error {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(dir, ".doctor-*")
+	if err != nil {
+		return err
+	}
+	f.Close()
+	return os.Remove(f.Name())
+}
+
+// CheckScope reports invalid scope entries, and redundant ones that hint
+// at a typo.
+func CheckScope(r *Report, entries []string) {
+	if len(entries) == 0 {
+		r.Warn("scope", "not set; tools may send to any host")
+		return
+	}
+	ok := true
+	seen := map[string]bool{}
+	for _, e := range entries {
+		if err := config.ValidateScopeEntry(e); err != nil {
+			r.Fail("scope", err.Error(), `use a hostname, "*.domain", an IP, or a CIDR`)
+			ok = false
+			continue
+		}
+		e = strings.ToLower(e)
+		if seen[e] {
+			r.Warn("scope", fmt.Sprintf("%s is listed twice", e))
+			ok = false
+		}
+		seen[e] = true
+	}
+	keys := slices.Sorted(maps.Keys(seen))
+	for _, e := range keys {
+		for _, w := range keys {
+			if strings.HasPrefix(w, "*.") && w != e && strings.HasSuffix(e, w[1:]) {
+				r.Warn("scope", fmt.Sprintf("%s is already covered by %s", e, w))
+				ok = false
+			}
+		}
+	}
+	if ok {
+		r.Pass("scope", strings.Join(entries, ", "))
+	}
+}
+
+// CheckBurp connects client to Burp's MCP extension at url and reports
+// whether it succeeded.
+func CheckBurp(ctx context.Context, r *Report, client *burp.Client, url string) bool {
+	r.Section("Burp")
+	ctx, cancel := context.WithTimeout(ctx, burpConnectTimeout)
+	defer cancel()
+	if _, err := client.Connect(ctx); err != nil {
+		r.Fail("connection", fmt.Sprintf("%s: %v", url, err),
+			"start Burp with the MCP Server extension enabled, and check its SSE URL (--burp-url or BURP_MCP_URL)")
+		return false
+	}
+	r.Pass("connection", url)
+	return true
+}
+
+// upstreamTool is a Burp MCP extension tool the wrappers call.
+type upstreamTool struct {
+	name   string
+	usedBy string
+	pro    bool // needs Burp Professional
+}
+
+var upstreamTools = []upstreamTool{
+	{name: "send_http1_request", usedBy: "burp_send_request and the probes"},
+	{name: "send_http2_request", usedBy: "burp_send_request with HTTP/2 targets"},
+	{name: "get_proxy_http_history", usedBy: "burp_get_proxy_history and burp_get_request"},
+	{name: "get_scanner_issues", usedBy: "burp_get_scanner_issues and burp_get_issue_evidence"},
+	{name: "create_repeater_tab", usedBy: "burp_create_repeater_tab"},
+	{name: "send_to_intruder", usedBy: "burp_send_to_intruder"},
+	{name: "send_to_organizer", usedBy: "burp_send_to_organizer"},
+	{name: "generate_collaborator_payload", usedBy: "the out-of-band probes", pro: true},
+	{name: "get_collaborator_interactions", usedBy: "the out-of-band probes", pro: true},
+}
+
+// CheckUpstream lists the tools Burp's extension offers over session and
+// reports the ones the wrappers need but are missing.
+func CheckUpstream(ctx context.Context, r *Report, session *mcp.ClientSession) {
+	var names []string
+	for tool, err := range session.Tools(ctx, nil) {
+		if err != nil {
+			r.Fail("upstream tools", err.Error(), "the extension may be outdated; update it from the BApp Store")
+			return
+		}
+		names = append(names, tool.Name)
+	}
+	slices.Sort(names)
+	r.Pass("upstream tools", fmt.Sprintf("%d listed: %s", len(names), strings.Join(names, ", ")))
+	for _, u := range upstreamTools {
+		switch {
+		case slices.Contains(names, u.name):
+		case u.pro:
+			r.Warn("upstream tools", fmt.Sprintf("%s missing; %s need Burp Professional", u.name, u.usedBy))
+		default:
+			r.Fail("upstream tools", fmt.Sprintf("%s missing; %s will fail", u.name, u.usedBy),
+				"update the MCP Server extension and enable its tools in its settings tab")
+		}
+	}
+}
+
+// wrapperCheck is one harmless call to a wrapper against the echo endpoint.
+type wrapperCheck struct {
+	name string
+	burp bool // needs a live Burp connection
+	run  func(ctx context.Context, s *mcp.ClientSession, t *Target) (string, error)
+}
+
+var wrapperChecks = []wrapperCheck{
+	{name: "burp_send_request (direct)", run: func(ctx context.Context, s *mcp.ClientSession, t *Target) (string, error) {
+		return checkEcho(ctx, s, t, true)
+	}},
+	{name: "burp_send_request (via Burp)", burp: true, run: func(ctx context.Context, s *mcp.ClientSession, t *Target) (string, error) {
+		return checkEcho(ctx, s, t, false)
+	}},
+	{name: "burp_race_request", run: checkEchoRace},
+	{name: "burp_get_proxy_history", burp: true, run: func(ctx context.Context, s *mcp.ClientSession, _ *Target) (string, error) {
+		var out tools.GetProxyHistoryOutput
+		if err := callTool(ctx, s, "burp_get_proxy_history", map[string]any{"count": 1}, &out); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%d entries read", out.Count), nil
+	}},
+	{name: "burp_get_scanner_issues", burp: true, run: func(ctx context.Context, s *mcp.ClientSession, _ *Target) (string, error) {
+		var out tools.GetScannerIssuesOutput
+		if err := callTool(ctx, s, "burp_get_scanner_issues", map[string]any{"count": 1}, &out); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%d issues read", out.Count), nil
+	}},
+	{name: "burp_list_instances", run: func(ctx context.Context, s *mcp.ClientSession, _ *Target) (string, error) {
+		var out tools.ListInstancesOutput
+		if err := callTool(ctx, s, "burp_list_instances", map[string]any{}, &out); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%d instances", len(out.Instances)), nil
+	}},
+	{name: "burp_encode", run: func(ctx context.Context, s *mcp.ClientSession, _ *Target) (string, error) {
+		var out tools.EncodeOutput
+		if err := callTool(ctx, s, "burp_encode", map[string]any{"content": "doctor", "type": "base64"}, &out); err != nil {
+			return "", err
+		}
+		if out.Encoded != "ZG9jdG9y" {
+			return "", fmt.Errorf("encoded %q, want ZG9jdG9y", out.Encoded)
+		}
+		return "", nil
+	}},
+}
+
+// echoRequest is the harmless request the wrapper checks send.
+const echoRequest = "POST /echo HTTP/1.1\r\nHost: doctor\r\nContent-Length: 6\r\n\r\ndoctor"
+
+func checkEcho(ctx context.Context, s *mcp.ClientSession, t *Target, direct bool) (string, error) {
+	start := time.Now()
+	var out tools.SendRequestOutput
+	if err := callTool(ctx, s, "burp_send_request", targetArgs(t, map[string]any{"raw": echoRequest, "direct": direct}), &out); err != nil {
+		return "", err
+	}
+	if out.StatusCode != 200 || !strings.Contains(out.Body, `body="doctor"`) {
+		return "", fmt.Errorf("status %d, body %q; want the echoed request body", out.StatusCode, out.Body)
+	}
+	return time.Since(start).Round(time.Millisecond).String(), nil
+}
+
+func checkEchoRace(ctx context.Context, s *mcp.ClientSession, t *Target) (string, error) {
+	var out tools.RaceRequestOutput
+	if err := callTool(ctx, s, "burp_race_request", targetArgs(t, map[string]any{"raw": echoRequest, "count": 2}), &out); err != nil {
+		return "", err
+	}
+	if len(out.Groups) != 1 || out.Groups[0].StatusCode != 200 || out.Groups[0].Count != 2 {
+		return "", fmt.Errorf("want 2 identical 200 responses, got %s", out.Summary)
+	}
+	return "", nil
+}
+
+// CheckWrappers calls each wrapper through session with a harmless request
+// to t's echo endpoint. Calls that go through Burp are skipped unless
+// withBurp is set.
+func CheckWrappers(ctx context.Context, r *Report, session *mcp.ClientSession, t *Target, withBurp bool) {
+	r.Section("Tools")
+	for _, c := range wrapperChecks {
+		if c.burp && !withBurp {
+			r.Skip(c.name, "Burp not connected")
+			continue
+		}
+		detail, err := c.run(ctx, session, t)
+		if err != nil {
+			r.Fail(c.name, err.Error(), "")
+			continue
+		}
+		r.Pass(c.name, detail)
+	}
+}
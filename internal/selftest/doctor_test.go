@@ -0,0 +1,122 @@
+package selftest
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/c0tton-fluff/burp-mcp-server/internal/burp"
+	"github.com/c0tton-fluff/burp-mcp-server/internal/config"
+	"github.com/c0tton-fluff/burp-mcp-server/internal/tools"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestCheckScope(t *testing.T) {
+	var out bytes.Buffer
+	r := NewReport(&out)
+	CheckScope(r, []string{"*.example.com", "api.example.com", "10.0.0.0/8", "10.0.0.0/8", "bad:host"})
+	for _, want := range []string{
+		"WARN  scope: api.example.com is already covered by *.example.com",
+		"WARN  scope: 10.0.0.0/8 is listed twice",
+		`FAIL  scope: invalid entry "bad:host"`,
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("missing %q in:\n%s", want, out.String())
+		}
+	}
+	if r.Passed != 0 || r.Failed != 1 || r.Warned != 2 {
+		t.Errorf("counts = %+v", r)
+	}
+
+	out.Reset()
+	r = NewReport(&out)
+	CheckScope(r, []string{"*.example.com", "other.test"})
+	if r.Passed != 1 || r.Warned+r.Failed != 0 {
+		t.Errorf("valid scope: %s", out.String())
+	}
+}
+
+func TestCheckConfig(t *testing.T) {
+	var out bytes.Buffer
+	r := NewReport(&out)
+	cfg := CheckConfig(r, "/x/config.json", nil, errors.New("invalid config /x/config.json: scope: empty entry"))
+	if cfg == nil || r.Failed != 1 || !strings.Contains(out.String(), "hint:") {
+		t.Errorf("broken config: %s", out.String())
+	}
+
+	out.Reset()
+	r = NewReport(&out)
+	CheckConfig(r, "/x/config.json", &config.Config{
+		Store:    filepath.Join(t.TempDir(), "store.json"),
+		Scope:    []string{"example.com"},
+		DryRun:   true,
+		Approval: &config.ApprovalConfig{Tools: []string{"burp_race_request"}},
+	}, nil)
+	if r.Failed != 0 || r.Passed != 3 || r.Warned != 2 {
+		t.Errorf("counts = %+v:\n%s", r, out.String())
+	}
+}
+
+// connectTools serves tools on an in-memory MCP server and returns a
+// session to it.
+func connectTools(t *testing.T, server *mcp.Server) *mcp.ClientSession {
+	t.Helper()
+	ctx := context.Background()
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	if _, err := server.Connect(ctx, serverTransport, nil); err != nil {
+		t.Fatal(err)
+	}
+	session, err := mcp.NewClient(&mcp.Implementation{Name: "doctor"}, nil).Connect(ctx, clientTransport, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { session.Close() })
+	return session
+}
+
+func TestCheckUpstream(t *testing.T) {
+	upstream := mcp.NewServer(&mcp.Implementation{Name: "burp"}, nil)
+	for _, u := range upstreamTools {
+		if u.name == "send_to_organizer" || u.pro {
+			continue
+		}
+		mcp.AddTool(upstream, &mcp.Tool{Name: u.name}, func(context.Context, *mcp.CallToolRequest, struct{}) (*mcp.CallToolResult, any, error) {
+			return nil, nil, nil
+		})
+	}
+
+	var out bytes.Buffer
+	r := NewReport(&out)
+	CheckUpstream(context.Background(), r, connectTools(t, upstream))
+	if r.Failed != 1 || !strings.Contains(out.String(), "FAIL  upstream tools: send_to_organizer missing") {
+		t.Errorf("missing tool not failed:\n%s", out.String())
+	}
+	if r.Warned != 2 {
+		t.Errorf("Collaborator tools should warn:\n%s", out.String())
+	}
+}
+
+func TestCheckWrappers_Direct(t *testing.T) {
+	target, err := StartTarget()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { target.Close() })
+
+	client, _ := burp.NewClient("http://127.0.0.1:1/sse") // never connected
+	server := mcp.NewServer(&mcp.Implementation{Name: "burp-mcp-server"}, nil)
+	tools.RegisterSendRequestTool(server, client)
+	tools.RegisterRaceRequestTool(server)
+	tools.RegisterListInstancesTool(server, client)
+	tools.RegisterEncodeTool(server)
+
+	var out bytes.Buffer
+	r := NewReport(&out)
+	CheckWrappers(context.Background(), r, connectTools(t, server), target, false)
+	if r.Failed != 0 || r.Skipped != 3 || r.Passed != 4 {
+		t.Errorf("counts = %+v:\n%s", r, out.String())
+	}
+}
@@ -0,0 +1,179 @@
+package selftest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/c0tton-fluff/burp-mcp-server/internal/tools"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// check is one end-to-end scenario run through the MCP tool layer.
+type check struct {
+	name string
+	burp bool // needs a live Burp connection
+	run  func(ctx context.Context, s *mcp.ClientSession, t *Target) error
+}
+
+var checks = []check{
+	{name: "reflected parameter via direct send", run: checkReflectionDirect},
+	{name: "race window on single-use coupon", run: checkRace},
+	{name: "ambiguous CL/TE framing delivered intact", run: checkFraming},
+	{name: "range probe", run: checkRangeProbe},
+	{name: "dry run sends nothing", run: checkDryRun},
+	{name: "reflected parameter via Burp", burp: true, run: checkReflectionBurp},
+}
+
+// Run executes every check against t through session, printing one
+// PASS/FAIL/SKIP line per check to w. Checks that need Burp are skipped
+// unless withBurp is set. It returns the number of failed checks.
+func Run(ctx context.Context, session *mcp.ClientSession, t *Target, w io.Writer, withBurp bool) int {
+	failed := 0
+	for _, c := range checks {
+		if c.burp && !withBurp {
+			fmt.Fprintf(w, "SKIP  %s (needs --burp)\n", c.name)
+			continue
+		}
+		if err := c.run(ctx, session, t); err != nil {
+			failed++
+			fmt.Fprintf(w, "FAIL  %s: %v\n", c.name, err)
+			continue
+		}
+		fmt.Fprintf(w, "PASS  %s\n", c.name)
+	}
+	return failed
+}
+
+// callTool calls a tool and decodes its structured output into out.
+func callTool(ctx context.Context, s *mcp.ClientSession, name string, args map[string]any, out any) error {
+	res, err := s.CallTool(ctx, &mcp.CallToolParams{Name: name, Arguments: args})
+	if err != nil {
+		return err
+	}
+	if res.IsError {
+		var msgs []string
+		for _, c := range res.Content {
+			if tc, ok := c.(*mcp.TextContent); ok {
+				msgs = append(msgs, tc.Text)
+			}
+		}
+		return fmt.Errorf("%s: %s", name, strings.Join(msgs, "; "))
+	}
+	raw, err := json.Marshal(res.StructuredContent)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(raw, out)
+}
+
+// targetArgs returns the host/port/tls arguments for t plus extra.
+func targetArgs(t *Target, extra map[string]any) map[string]any {
+	args := map[string]any{"host": t.Host(), "port": t.Port(), "tls": false}
+	for k, v := range extra {
+		args[k] = v
+	}
+	return args
+}
+
+func checkReflection(ctx context.Context, s *mcp.ClientSession, t *Target, direct bool) error {
+	const canary = "mcp<selftest>"
+	var out tools.SendRequestOutput
+	err := callTool(ctx, s, "burp_send_request", targetArgs(t, map[string]any{
+		"raw":    "GET /search?q=mcp%3Cselftest%3E HTTP/1.1\r\nHost: selftest\r\n\r\n",
+		"direct": direct,
+	}), &out)
+	if err != nil {
+		return err
+	}
+	if out.StatusCode != 200 || !strings.Contains(out.Body, canary) {
+		return fmt.Errorf("status %d, canary not reflected in %q", out.StatusCode, out.Body)
+	}
+	return nil
+}
+
+func checkReflectionDirect(ctx context.Context, s *mcp.ClientSession, t *Target) error {
+	return checkReflection(ctx, s, t, true)
+}
+
+func checkReflectionBurp(ctx context.Context, s *mcp.ClientSession, t *Target) error {
+	return checkReflection(ctx, s, t, false)
+}
+
+func checkRace(ctx context.Context, s *mcp.ClientSession, t *Target) error {
+	var out tools.RaceRequestOutput
+	err := callTool(ctx, s, "burp_race_request", targetArgs(t, map[string]any{
+		"raw":   "POST /redeem HTTP/1.1\r\nHost: selftest\r\nContent-Length: 11\r\n\r\ncode=SAVE10",
+		"count": 10,
+	}), &out)
+	if err != nil {
+		return err
+	}
+	wins := 0
+	for _, g := range out.Groups {
+		if g.StatusCode == 200 {
+			wins += g.Count
+		}
+	}
+	if wins < 2 {
+		return fmt.Errorf("coupon redeemed %d times, want at least 2 (%s)", wins, out.Summary)
+	}
+	return nil
+}
+
+func checkFraming(ctx context.Context, s *mcp.ClientSession, t *Target) error {
+	var out tools.SendRequestOutput
+	err := callTool(ctx, s, "burp_send_request", targetArgs(t, map[string]any{
+		"raw":    "POST /echo HTTP/1.1\r\nHost: selftest\r\nContent-Length: 3\r\nTransfer-Encoding: chunked\r\n\r\n0\r\n\r\n",
+		"direct": true,
+	}), &out)
+	if err != nil {
+		return err
+	}
+	if !strings.Contains(out.Body, `framing=content-length body="0\r\n"`) {
+		return fmt.Errorf("target saw %q, want Content-Length framing of the first 3 bytes", out.Body)
+	}
+	return nil
+}
+
+func checkRangeProbe(ctx context.Context, s *mcp.ClientSession, t *Target) error {
+	var out tools.RangeProbeOutput
+	err := callTool(ctx, s, "burp_range_probe", targetArgs(t, map[string]any{
+		"raw": "GET /search?q=static HTTP/1.1\r\nHost: selftest\r\n\r\n",
+	}), &out)
+	if err != nil {
+		return err
+	}
+	for _, c := range out.Checks {
+		if c.Error != "" {
+			return fmt.Errorf("%s: %s", c.Name, c.Error)
+		}
+	}
+	if len(out.Checks) == 0 {
+		return errors.New("no range checks returned")
+	}
+	return nil
+}
+
+func checkDryRun(ctx context.Context, s *mcp.ClientSession, t *Target) error {
+	before := t.Requests()
+	var out tools.SendRequestOutput
+	err := callTool(ctx, s, "burp_send_request", targetArgs(t, map[string]any{
+		"raw":    "DELETE /search HTTP/1.1\r\nHost: selftest\r\n\r\n",
+		"direct": true,
+		"dryRun": true,
+	}), &out)
+	if err != nil {
+		return err
+	}
+	if out.Preview == nil || !strings.HasPrefix(out.Preview.Raw, "DELETE /search HTTP/1.1\r\n") {
+		return fmt.Errorf("no preview returned")
+	}
+	if n := t.Requests() - before; n != 0 {
+		return fmt.Errorf("target received %d requests", n)
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+package selftest
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/c0tton-fluff/burp-mcp-server/internal/burp"
+	"github.com/c0tton-fluff/burp-mcp-server/internal/tools"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestRun_DirectChecks(t *testing.T) {
+	target, err := StartTarget()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { target.Close() })
+
+	client, _ := burp.NewClient("http://127.0.0.1:1/sse") // never connected
+	server := mcp.NewServer(&mcp.Implementation{Name: "burp-mcp-server"}, nil)
+	tools.RegisterSendRequestTool(server, client)
+	tools.RegisterRaceRequestTool(server)
+	tools.RegisterRangeProbeTool(server)
+
+	ctx := context.Background()
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	if _, err := server.Connect(ctx, serverTransport, nil); err != nil {
+		t.Fatal(err)
+	}
+	session, err := mcp.NewClient(&mcp.Implementation{Name: "selftest"}, nil).Connect(ctx, clientTransport, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { session.Close() })
+
+	var out bytes.Buffer
+	if failed := Run(ctx, session, target, &out, false); failed != 0 {
+		t.Errorf("%d checks failed:\n%s", failed, out.String())
+	}
+	if !strings.Contains(out.String(), "SKIP  reflected parameter via Burp") {
+		t.Errorf("Burp check should be skipped:\n%s", out.String())
+	}
+}
@@ -0,0 +1,322 @@
+// Package selftest runs the attack tools end-to-end against a small,
+// deliberately vulnerable HTTP server bundled with the binary, and
+// diagnoses the setup (config, Burp connection, upstream tools) for the
+// doctor command.
+package selftest
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// raceWindow is how long /redeem waits between checking and spending the
+// coupon, wide enough for a last-byte-synced race to land inside it.
+const raceWindow = 100 * time.Millisecond
+
+// maxDelay caps the wait /delay will do.
+const maxDelay = 30 * time.Second
+
+// Target is the vulnerable test server. It speaks HTTP/1.1 through its own
+// lenient parser rather than net/http, which would reject the ambiguous
+// requests it exists to accept. Every response is deterministic.
+//
+//	GET  /search?q=X      reflects X unescaped
+//	POST /redeem          spends a single-use coupon with a check-then-act race
+//	POST /echo            reports how the body was framed and what it contained;
+//	                      Content-Length wins over Transfer-Encoding (CL.TE)
+//	GET  /delay?ms=N      answers after N milliseconds (at most 30s)
+//	GET  /redirect?n=N    redirects N times, then answers 200
+//	GET  /chunked?n=N     sends its body in N chunks (default 3)
+//	GET  /gzip            sends a gzip-compressed body
+//	GET  /counter         reports the counter
+//	POST /counter         increments the counter with a check-then-act race
+//	DELETE /counter       resets the counter and the coupon
+type Target struct {
+	ln       net.Listener
+	requests atomic.Int64
+
+	mu       sync.Mutex
+	redeemed bool
+	counter  int
+}
+
+// StartTarget listens on a random loopback port and serves until Close.
+func StartTarget() (*Target, error) {
+	return ListenTarget("127.0.0.1:0")
+}
+
+// ListenTarget listens on addr and serves until Close.
+func ListenTarget(addr string) (*Target, error) {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	t := &Target{ln: ln}
+	go t.serve()
+	return t, nil
+}
+
+// Host returns the listener's IP address.
+func (t *Target) Host() string {
+	return t.ln.Addr().(*net.TCPAddr).IP.String()
+}
+
+// Port returns the listener's port.
+func (t *Target) Port() int {
+	return t.ln.Addr().(*net.TCPAddr).Port
+}
+
+// Requests returns how many requests the target has answered.
+func (t *Target) Requests() int {
+	return int(t.requests.Load())
+}
+
+// Close stops the listener.
+func (t *Target) Close() error {
+	return t.ln.Close()
+}
+
+func (t *Target) serve() {
+	for {
+		conn, err := t.ln.Accept()
+		if err != nil {
+			return
+		}
+		go t.handleConn(conn)
+	}
+}
+
+// response is what a route answers. The body is sent with Content-Length,
+// or split into chunks pieces with chunked encoding when chunks is set.
+type response struct {
+	status  int
+	headers []string // extra "Name: value" lines
+	body    string
+	chunks  int
+}
+
+func text(status int, body string) response {
+	return response{status: status, body: body}
+}
+
+// request is what the lenient parser extracts.
+type request struct {
+	method, target string
+	headers        map[string]string // lowercase names, last value wins
+	body           string
+	framing        string
+}
+
+func (t *Target) handleConn(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	for {
+		conn.SetReadDeadline(time.Now().Add(10 * time.Second))
+		req, err := readRequest(r)
+		if err != nil {
+			return
+		}
+		t.requests.Add(1)
+		writeResponse(conn, t.route(req))
+	}
+}
+
+func writeResponse(w io.Writer, resp response) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "HTTP/1.1 %d %s\r\nContent-Type: text/html\r\n", resp.status, statusText(resp.status))
+	for _, h := range resp.headers {
+		b.WriteString(h + "\r\n")
+	}
+	if resp.chunks == 0 {
+		fmt.Fprintf(&b, "Content-Length: %d\r\n\r\n%s", len(resp.body), resp.body)
+		io.WriteString(w, b.String())
+		return
+	}
+	b.WriteString("Transfer-Encoding: chunked\r\n\r\n")
+	body := resp.body
+	size := (len(body) + resp.chunks - 1) / resp.chunks
+	for body != "" {
+		n := min(size, len(body))
+		fmt.Fprintf(&b, "%x\r\n%s\r\n", n, body[:n])
+		body = body[n:]
+	}
+	b.WriteString("0\r\n\r\n")
+	io.WriteString(w, b.String())
+}
+
+func (t *Target) route(req *request) response {
+	u, err := url.Parse(req.target)
+	if err != nil {
+		return text(400, "bad request target")
+	}
+	q := u.Query()
+	switch {
+	case u.Path == "/search":
+		return text(200, "<p>Results for "+q.Get("q")+"</p>")
+	case u.Path == "/redeem" && req.method == "POST":
+		t.mu.Lock()
+		used := t.redeemed
+		t.mu.Unlock()
+		if used {
+			return text(409, "coupon already redeemed")
+		}
+		time.Sleep(raceWindow)
+		t.mu.Lock()
+		t.redeemed = true
+		t.mu.Unlock()
+		return text(200, "coupon redeemed")
+	case u.Path == "/echo":
+		return text(200, fmt.Sprintf("framing=%s body=%q", req.framing, req.body))
+	case u.Path == "/delay":
+		ms, _ := strconv.Atoi(q.Get("ms"))
+		d := min(max(time.Duration(ms)*time.Millisecond, 0), maxDelay)
+		time.Sleep(d)
+		return text(200, fmt.Sprintf("delayed %d ms", d.Milliseconds()))
+	case u.Path == "/redirect":
+		n, _ := strconv.Atoi(q.Get("n"))
+		if n <= 0 {
+			return text(200, "redirect chain done")
+		}
+		return response{status: 302, headers: []string{fmt.Sprintf("Location: /redirect?n=%d", n-1)}, body: "redirecting"}
+	case u.Path == "/chunked":
+		n, err := strconv.Atoi(q.Get("n"))
+		if err != nil || n <= 0 {
+			n = 3
+		}
+		var body strings.Builder
+		for i := 1; i <= n; i++ {
+			fmt.Fprintf(&body, "chunk %d\n", i)
+		}
+		return response{status: 200, body: body.String(), chunks: n}
+	case u.Path == "/gzip":
+		var buf bytes.Buffer
+		zw := gzip.NewWriter(&buf)
+		io.WriteString(zw, strings.Repeat("compressed body\n", 8))
+		zw.Close()
+		return response{status: 200, headers: []string{"Content-Encoding: gzip"}, body: buf.String()}
+	case u.Path == "/counter":
+		return t.routeCounter(req.method)
+	}
+	return text(404, "not found")
+}
+
+// routeCounter serves /counter. POST reads, waits raceWindow, then writes
+// back the incremented value, so concurrent increments are lost.
+func (t *Target) routeCounter(method string) response {
+	t.mu.Lock()
+	n := t.counter
+	t.mu.Unlock()
+	switch method {
+	case "POST":
+		time.Sleep(raceWindow)
+		t.mu.Lock()
+		t.counter = n + 1
+		t.mu.Unlock()
+		return text(200, fmt.Sprintf("counter=%d", n+1))
+	case "DELETE":
+		t.mu.Lock()
+		t.counter, t.redeemed = 0, false
+		t.mu.Unlock()
+		return text(200, "counter=0")
+	}
+	return text(200, fmt.Sprintf("counter=%d", n))
+}
+
+// readRequest parses one request. Unlike a strict parser it accepts both
+// Content-Length and Transfer-Encoding, preferring Content-Length, and
+// leaves any bytes past the body for the next request on the connection.
+func readRequest(r *bufio.Reader) (*request, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	parts := strings.Fields(line)
+	if len(parts) < 2 {
+		return nil, fmt.Errorf("malformed request line %q", line)
+	}
+	req := &request{method: parts[0], target: parts[1], headers: make(map[string]string), framing: "none"}
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		line = strings.TrimRight(line, "\r\n")
+		if line == "" {
+			break
+		}
+		if name, value, ok := strings.Cut(line, ":"); ok {
+			req.headers[strings.ToLower(strings.TrimSpace(name))] = strings.TrimSpace(value)
+		}
+	}
+
+	if cl, ok := req.headers["content-length"]; ok {
+		n, err := strconv.Atoi(cl)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("bad content-length %q", cl)
+		}
+		buf := make([]byte, n)
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return nil, err
+		}
+		req.body, req.framing = string(buf), "content-length"
+	} else if strings.Contains(strings.ToLower(req.headers["transfer-encoding"]), "chunked") {
+		body, err := readChunked(r)
+		if err != nil {
+			return nil, err
+		}
+		req.body, req.framing = body, "chunked"
+	}
+	return req, nil
+}
+
+func readChunked(r *bufio.Reader) (string, error) {
+	var sb strings.Builder
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return "", err
+		}
+		sizeField, _, _ := strings.Cut(strings.TrimSpace(line), ";")
+		size, err := strconv.ParseInt(sizeField, 16, 64)
+		if err != nil || size < 0 {
+			return "", fmt.Errorf("bad chunk size %q", line)
+		}
+		if size == 0 {
+			// Trailers end at the first empty line.
+			for {
+				line, err := r.ReadString('\n')
+				if err != nil || strings.TrimSpace(line) == "" {
+					return sb.String(), err
+				}
+			}
+		}
+		if _, err := io.CopyN(&sb, r, size); err != nil {
+			return "", err
+		}
+		r.ReadString('\n')
+	}
+}
+
+func statusText(code int) string {
+	switch code {
+	case 200:
+		return "OK"
+	case 302:
+		return "Found"
+	case 400:
+		return "Bad Request"
+	case 409:
+		return "Conflict"
+	}
+	return "Not Found"
+}
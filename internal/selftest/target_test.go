@@ -0,0 +1,81 @@
+package selftest
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func startTestTarget(t *testing.T) (*Target, string) {
+	t.Helper()
+	target, err := StartTarget()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { target.Close() })
+	return target, fmt.Sprintf("http://%s:%d", target.Host(), target.Port())
+}
+
+func get(t *testing.T, method, url string) (*http.Response, string) {
+	t.Helper()
+	req, _ := http.NewRequest(method, url, nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp, string(body)
+}
+
+func TestTarget_Endpoints(t *testing.T) {
+	_, base := startTestTarget(t)
+
+	if resp, body := get(t, "GET", base+"/redirect?n=3"); resp.StatusCode != 200 || body != "redirect chain done" || resp.Request.URL.RawQuery != "n=0" {
+		t.Errorf("redirect: %d %q at %s", resp.StatusCode, body, resp.Request.URL)
+	}
+	if resp, body := get(t, "GET", base+"/chunked?n=2"); len(resp.TransferEncoding) != 1 || body != "chunk 1\nchunk 2\n" {
+		t.Errorf("chunked: %v %q", resp.TransferEncoding, body)
+	}
+	if resp, body := get(t, "GET", base+"/gzip"); !resp.Uncompressed || !strings.HasPrefix(body, "compressed body\n") {
+		t.Errorf("gzip: uncompressed=%v %q", resp.Uncompressed, body)
+	}
+	if _, body := get(t, "GET", base+"/delay?ms=20"); body != "delayed 20 ms" {
+		t.Errorf("delay: %q", body)
+	}
+	if resp, _ := get(t, "GET", base+"/missing"); resp.StatusCode != 404 {
+		t.Errorf("missing: %d", resp.StatusCode)
+	}
+}
+
+func TestTarget_CounterLosesConcurrentIncrements(t *testing.T) {
+	_, base := startTestTarget(t)
+
+	var wg sync.WaitGroup
+	for range 5 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if resp, err := http.Post(base+"/counter", "", nil); err == nil {
+				resp.Body.Close()
+			}
+		}()
+	}
+	wg.Wait()
+	if _, body := get(t, "GET", base+"/counter"); body == "counter=5" {
+		t.Errorf("concurrent increments all landed: %s", body)
+	}
+	if _, body := get(t, "DELETE", base+"/counter"); body != "counter=0" {
+		t.Errorf("reset: %s", body)
+	}
+	get(t, "POST", base+"/counter")
+	if _, body := get(t, "GET", base+"/counter"); body != "counter=1" {
+		t.Errorf("after reset and one increment: %s", body)
+	}
+}
@@ -0,0 +1,199 @@
+package store
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Endpoint sources.
+const (
+	EndpointFromHistory = "history" // seen in proxy history
+	EndpointFromCrawl   = "crawl"   // found by burp_crawl
+	EndpointFromOpenAPI = "openapi" // listed by an imported API spec
+	EndpointFromTraffic = "traffic" // first seen in a request sent by a tool
+)
+
+// Endpoint is one method and templated path on an origin, with the tools
+// and auth profiles that have sent requests to it.
+type Endpoint struct {
+	Method string `json:"method"`
+	Origin string `json:"origin"` // scheme://host[:port], default ports omitted
+	// Path is templated: "/users/{id}" covers "/users/42" and "/users/7".
+	Path    string        `json:"path"`
+	Sources []string      `json:"sources"`
+	Uses    []EndpointUse `json:"uses,omitempty"`
+	AddedAt time.Time     `json:"addedAt"`
+}
+
+// EndpointUse counts the requests one tool sent to an endpoint under one
+// auth profile ("" for none).
+type EndpointUse struct {
+	Tool        string    `json:"tool"`
+	AuthProfile string    `json:"authProfile,omitempty"`
+	Requests    int       `json:"requests"`
+	LastAt      time.Time `json:"lastAt"`
+}
+
+var (
+	uuidSegment  = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+	hashSegment  = regexp.MustCompile(`^[0-9a-fA-F]{16,}$`)
+	tokenSegment = regexp.MustCompile(`^[A-Za-z0-9_-]{20,}$`)
+	placeholder  = regexp.MustCompile(`^(?:\{[^/{}]*\}|:[A-Za-z_]\w*)$`)
+)
+
+// TemplatePath replaces the identifiers in a URL path (numbers, UUIDs,
+// hashes, and long random tokens) with placeholders and drops the query.
+func TemplatePath(path string) string {
+	path, _, _ = strings.Cut(path, "?")
+	path, _, _ = strings.Cut(path, "#")
+	if path == "" {
+		return "/"
+	}
+	segs := strings.Split(path, "/")
+	for i, seg := range segs {
+		switch {
+		case seg == "":
+		case strings.Trim(seg, "0123456789") == "":
+			segs[i] = "{id}"
+		case uuidSegment.MatchString(seg):
+			segs[i] = "{uuid}"
+		case hashSegment.MatchString(seg) && strings.ContainsAny(seg, "0123456789"):
+			segs[i] = "{hash}"
+		case tokenSegment.MatchString(seg) && strings.ContainsAny(seg, "0123456789") && strings.IndexFunc(seg, isLetter) >= 0:
+			segs[i] = "{token}"
+		}
+	}
+	return strings.Join(segs, "/")
+}
+
+func isLetter(r rune) bool { return r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' }
+
+// endpointKey identifies an endpoint whatever its placeholders are named,
+// so "/pets/{petId}" from a spec and "/pets/{id}" from traffic are one.
+func endpointKey(method, origin, path string) string {
+	segs := strings.Split(path, "/")
+	for i, seg := range segs {
+		if placeholder.MatchString(seg) {
+			segs[i] = "{}"
+		}
+	}
+	return strings.ToUpper(method) + " " + strings.ToLower(origin) + strings.Join(segs, "/")
+}
+
+func (s *Store) endpointIndexLocked(method, origin, path string) int {
+	key := endpointKey(method, origin, path)
+	return slices.IndexFunc(s.data.Endpoints, func(e *Endpoint) bool { return endpointKey(e.Method, e.Origin, e.Path) == key })
+}
+
+// cloneEndpointsLocked copies the endpoints deeply enough that mutating the
+// copy leaves the original intact for rollback.
+func (s *Store) cloneEndpointsLocked() []*Endpoint {
+	out := make([]*Endpoint, len(s.data.Endpoints))
+	for i, e := range s.data.Endpoints {
+		c := *e
+		c.Sources = slices.Clone(e.Sources)
+		c.Uses = slices.Clone(e.Uses)
+		out[i] = &c
+	}
+	return out
+}
+
+// AddEndpoints merges eps into the inventory: new endpoints are added and
+// known ones gain the new sources. OpenAPI placeholder names replace
+// generated ones. It returns how many endpoints were new.
+func (s *Store) AddEndpoints(eps []Endpoint) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	prev := s.data.Endpoints
+	s.data.Endpoints = s.cloneEndpointsLocked()
+	added, changed := 0, false
+	now := time.Now().UTC()
+	for _, ep := range eps {
+		ep.Method = strings.ToUpper(ep.Method)
+		ep.Origin = strings.ToLower(ep.Origin)
+		if i := s.endpointIndexLocked(ep.Method, ep.Origin, ep.Path); i >= 0 {
+			e := s.data.Endpoints[i]
+			for _, src := range ep.Sources {
+				if !slices.Contains(e.Sources, src) {
+					e.Sources = append(e.Sources, src)
+					changed = true
+					if src == EndpointFromOpenAPI && e.Path != ep.Path {
+						e.Path = ep.Path
+					}
+				}
+			}
+			continue
+		}
+		ep.AddedAt = now
+		ep.Sources = slices.Clone(ep.Sources)
+		ep.Uses = slices.Clone(ep.Uses)
+		s.data.Endpoints = append(s.data.Endpoints, &ep)
+		added++
+	}
+	if added == 0 && !changed {
+		s.data.Endpoints = prev
+		return 0, nil
+	}
+	if err := s.save(); err != nil {
+		s.data.Endpoints = prev
+		return 0, err
+	}
+	return added, nil
+}
+
+// RecordEndpointUses adds request counts to the endpoints the uses name,
+// adding endpoints not in the inventory yet with source traffic. Each
+// element of eps carries one endpoint and its new uses.
+func (s *Store) RecordEndpointUses(eps []Endpoint) error {
+	if len(eps) == 0 {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	prev := s.data.Endpoints
+	s.data.Endpoints = s.cloneEndpointsLocked()
+	now := time.Now().UTC()
+	for _, ep := range eps {
+		i := s.endpointIndexLocked(ep.Method, ep.Origin, ep.Path)
+		if i < 0 {
+			s.data.Endpoints = append(s.data.Endpoints, &Endpoint{
+				Method:  strings.ToUpper(ep.Method),
+				Origin:  strings.ToLower(ep.Origin),
+				Path:    ep.Path,
+				Sources: []string{EndpointFromTraffic},
+				AddedAt: now,
+			})
+			i = len(s.data.Endpoints) - 1
+		}
+		e := s.data.Endpoints[i]
+		for _, u := range ep.Uses {
+			j := slices.IndexFunc(e.Uses, func(x EndpointUse) bool { return x.Tool == u.Tool && x.AuthProfile == u.AuthProfile })
+			if j < 0 {
+				e.Uses = append(e.Uses, EndpointUse{Tool: u.Tool, AuthProfile: u.AuthProfile})
+				j = len(e.Uses) - 1
+			}
+			e.Uses[j].Requests += u.Requests
+			e.Uses[j].LastAt = u.LastAt
+		}
+	}
+	if err := s.save(); err != nil {
+		s.data.Endpoints = prev
+		return err
+	}
+	return nil
+}
+
+// Endpoints returns the inventory in the order endpoints were found.
+func (s *Store) Endpoints() []Endpoint {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]Endpoint, len(s.data.Endpoints))
+	for i, e := range s.data.Endpoints {
+		out[i] = *e
+		out[i].Sources = slices.Clone(e.Sources)
+		out[i].Uses = slices.Clone(e.Uses)
+	}
+	return out
+}
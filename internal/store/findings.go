@@ -0,0 +1,122 @@
+package store
+
+import (
+	"fmt"
+	"time"
+)
+
+// Retest verdicts.
+const (
+	VerdictVulnerable = "vulnerable" // every assertion still holds
+	VerdictFixed      = "fixed"      // at least one assertion no longer holds
+	VerdictError      = "error"      // the reproduction could not be replayed
+)
+
+// Step is one request in a finding's reproduction sequence.
+type Step struct {
+	Raw  string `json:"raw" jsonschema:"required,Raw HTTP request"`
+	Host string `json:"host,omitempty" jsonschema:"Target host (overrides Host header)"`
+	Port int    `json:"port,omitempty" jsonschema:"Target port (default based on TLS)"`
+	TLS  *bool  `json:"tls,omitempty" jsonschema:"Use HTTPS (default true)"`
+}
+
+// Assertion describes vulnerable behavior observed in the final response.
+// A finding reproduces when all of its assertions hold.
+type Assertion struct {
+	Type   string `json:"type" jsonschema:"required,One of: status, body_contains, body_not_contains, body_regex, header_contains, header_absent"`
+	Header string `json:"header,omitempty" jsonschema:"Header name for header_contains and header_absent"`
+	Value  string `json:"value,omitempty" jsonschema:"Status code, substring, or regex to match"`
+}
+
+// AssertionResult is the outcome of one assertion during a retest.
+type AssertionResult struct {
+	Assertion
+	Passed bool   `json:"passed"`
+	Actual string `json:"actual,omitempty"`
+}
+
+// Retest is one replay of a finding's reproduction.
+type Retest struct {
+	At         time.Time         `json:"at"`
+	Verdict    string            `json:"verdict"`
+	StatusCode int               `json:"statusCode,omitempty"`
+	Results    []AssertionResult `json:"results,omitempty"`
+	Error      string            `json:"error,omitempty"`
+}
+
+// Finding is a recorded vulnerability with enough detail to reproduce it.
+type Finding struct {
+	ID          string      `json:"id"`
+	Title       string      `json:"title"`
+	Severity    string      `json:"severity,omitempty"`
+	Description string      `json:"description,omitempty"`
+	Steps       []Step      `json:"steps"`
+	Assertions  []Assertion `json:"assertions"`
+	CreatedAt   time.Time   `json:"createdAt"`
+	Retests     []Retest    `json:"retests,omitempty"`
+}
+
+// AddFinding assigns an ID and creation time to f, persists it, and returns the stored copy.
+func (s *Store) AddFinding(f Finding) (Finding, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.data.NextFindingID++
+	f.ID = fmt.Sprintf("F-%d", s.data.NextFindingID)
+	f.CreatedAt = time.Now().UTC()
+	f.Retests = nil
+	s.data.Findings = append(s.data.Findings, &f)
+	if err := s.save(); err != nil {
+		s.data.Findings = s.data.Findings[:len(s.data.Findings)-1]
+		s.data.NextFindingID--
+		return Finding{}, err
+	}
+	return f, nil
+}
+
+// Finding returns the finding with the given ID.
+func (s *Store) Finding(id string) (Finding, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	f, err := s.findingLocked(id)
+	if err != nil {
+		return Finding{}, err
+	}
+	return *f, nil
+}
+
+// Findings returns all findings in creation order.
+func (s *Store) Findings() []Finding {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]Finding, len(s.data.Findings))
+	for i, f := range s.data.Findings {
+		out[i] = *f
+	}
+	return out
+}
+
+// AddRetest appends a retest result to the finding's history and persists it.
+func (s *Store) AddRetest(id string, r Retest) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	f, err := s.findingLocked(id)
+	if err != nil {
+		return err
+	}
+	f.Retests = append(f.Retests, r)
+	if err := s.save(); err != nil {
+		f.Retests = f.Retests[:len(f.Retests)-1]
+		return err
+	}
+	return nil
+}
+
+func (s *Store) findingLocked(id string) (*Finding, error) {
+	for _, f := range s.data.Findings {
+		if f.ID == id {
+			return f, nil
+		}
+	}
+	return nil, fmt.Errorf("finding %q not found", id)
+}
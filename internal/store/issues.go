@@ -0,0 +1,119 @@
+package store
+
+import (
+	"fmt"
+	"time"
+)
+
+// Issue is a scanner issue imported from a Burp issue report, kept so it can
+// be served after Burp's own scanner results are gone.
+type Issue struct {
+	SerialNumber          string          `json:"serialNumber,omitempty"`
+	Type                  string          `json:"type,omitempty"`
+	Name                  string          `json:"name"`
+	Host                  string          `json:"host"`
+	Path                  string          `json:"path,omitempty"`
+	Location              string          `json:"location,omitempty"`
+	Severity              string          `json:"severity,omitempty"`
+	Confidence            string          `json:"confidence,omitempty"`
+	IssueBackground       string          `json:"issueBackground,omitempty"`
+	RemediationBackground string          `json:"remediationBackground,omitempty"`
+	IssueDetail           string          `json:"issueDetail,omitempty"`
+	RemediationDetail     string          `json:"remediationDetail,omitempty"`
+	Evidence              []IssueEvidence `json:"evidence,omitempty"`
+	Source                string          `json:"source,omitempty"` // report file the issue came from
+	ImportedAt            time.Time       `json:"importedAt"`
+	// Verification is the latest replay of the issue's evidence.
+	Verification *IssueVerification `json:"verification,omitempty"`
+}
+
+// Issue verification verdicts.
+const (
+	IssueReproduced   = "reproduced"   // the vulnerable behavior still shows
+	IssueStale        = "stale"        // it no longer does; the issue is likely fixed
+	IssueInconclusive = "inconclusive" // the replay can't tell either way
+)
+
+// IssueVerification is the outcome of replaying an issue's evidence.
+type IssueVerification struct {
+	At         time.Time `json:"at"`
+	Verdict    string    `json:"verdict"`
+	Reason     string    `json:"reason,omitempty"`
+	StatusCode int       `json:"statusCode,omitempty"`
+}
+
+// IssueEvidence is one request/response pair attached to an issue.
+type IssueEvidence struct {
+	Request  string `json:"request,omitempty"`
+	Response string `json:"response,omitempty"`
+}
+
+// key identifies an issue across imports: Burp's serial number when the
+// report has one, else what the issue is and where it was found.
+func (i *Issue) key() string {
+	if i.SerialNumber != "" {
+		return "serial:" + i.SerialNumber
+	}
+	return i.Name + "\x00" + i.Host + "\x00" + i.Path + "\x00" + i.Location
+}
+
+// ImportIssues adds the issues not already in the store, stamping them with
+// the import time, and returns how many were added. Re-importing the same
+// report is a no-op.
+func (s *Store) ImportIssues(issues []Issue) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	seen := make(map[string]bool, len(s.data.Issues))
+	for _, i := range s.data.Issues {
+		seen[i.key()] = true
+	}
+	n := len(s.data.Issues)
+	now := time.Now().UTC()
+	for _, i := range issues {
+		if seen[i.key()] {
+			continue
+		}
+		seen[i.key()] = true
+		i.ImportedAt = now
+		s.data.Issues = append(s.data.Issues, &i)
+	}
+	added := len(s.data.Issues) - n
+	if added == 0 {
+		return 0, nil
+	}
+	if err := s.save(); err != nil {
+		s.data.Issues = s.data.Issues[:n]
+		return 0, err
+	}
+	return added, nil
+}
+
+// Issues returns all imported issues in import order.
+func (s *Store) Issues() []Issue {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]Issue, len(s.data.Issues))
+	for i, issue := range s.data.Issues {
+		out[i] = *issue
+	}
+	return out
+}
+
+// SetIssueVerification records v on the i'th issue (0-based, in import
+// order). Issues are never removed, so positions are stable.
+func (s *Store) SetIssueVerification(i int, v IssueVerification) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if i < 0 || i >= len(s.data.Issues) {
+		return fmt.Errorf("no imported issue %d", i+1)
+	}
+	issue := s.data.Issues[i]
+	prev := issue.Verification
+	issue.Verification = &v
+	if err := s.save(); err != nil {
+		issue.Verification = prev
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,31 @@
+package store
+
+// PassiveCursor returns how many proxy history entries of instance ("" for
+// the default connection) passive checks have already run over.
+func (s *Store) PassiveCursor(instance string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.data.PassiveCursors[instance]
+}
+
+// SetPassiveCursor records that passive checks have run over the first n
+// proxy history entries of instance. Zero starts over, as after history is
+// cleared.
+func (s *Store) SetPassiveCursor(instance string, n int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	prev, had := s.data.PassiveCursors[instance]
+	if s.data.PassiveCursors == nil {
+		s.data.PassiveCursors = make(map[string]int)
+	}
+	s.data.PassiveCursors[instance] = n
+	if err := s.save(); err != nil {
+		if had {
+			s.data.PassiveCursors[instance] = prev
+		} else {
+			delete(s.data.PassiveCursors, instance)
+		}
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package store
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// HistoryEntry is one proxy history item as captured in a snapshot.
+type HistoryEntry struct {
+	ID       int    `json:"id"` // 1-based position in Burp's history when captured
+	Request  string `json:"request"`
+	Response string `json:"response,omitempty"`
+}
+
+// Snapshot is a labelled copy of Burp's proxy history, kept as evidence
+// after the history is cleared.
+type Snapshot struct {
+	Label     string         `json:"label"`
+	Instance  string         `json:"instance,omitempty"`
+	CreatedAt time.Time      `json:"createdAt"`
+	Entries   []HistoryEntry `json:"entries"`
+}
+
+// SnapshotInfo describes a snapshot without its entries.
+type SnapshotInfo struct {
+	Label     string    `json:"label"`
+	Instance  string    `json:"instance,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+	Entries   int       `json:"entries"`
+}
+
+// SaveSnapshot stores snap under its label and returns its description.
+// Labels are compared case-insensitively and never reused, so evidence is
+// not overwritten.
+func (s *Store) SaveSnapshot(snap Snapshot) (SnapshotInfo, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.snapshotIndexLocked(snap.Label) >= 0 {
+		return SnapshotInfo{}, fmt.Errorf("snapshot %q already exists", snap.Label)
+	}
+	snap.CreatedAt = time.Now().UTC()
+	prev := slices.Clone(s.data.Snapshots)
+	s.data.Snapshots = append(s.data.Snapshots, &snap)
+	if err := s.save(); err != nil {
+		s.data.Snapshots = prev
+		return SnapshotInfo{}, err
+	}
+	return snap.Info(), nil
+}
+
+// Snapshot returns the snapshot with the given label.
+func (s *Store) Snapshot(label string) (Snapshot, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	i := s.snapshotIndexLocked(label)
+	if i < 0 {
+		return Snapshot{}, fmt.Errorf("snapshot %q not found", label)
+	}
+	snap := *s.data.Snapshots[i]
+	snap.Entries = slices.Clone(snap.Entries)
+	return snap, nil
+}
+
+// Snapshots describes all snapshots, oldest first.
+func (s *Store) Snapshots() []SnapshotInfo {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]SnapshotInfo, len(s.data.Snapshots))
+	for i, snap := range s.data.Snapshots {
+		out[i] = snap.Info()
+	}
+	return out
+}
+
+// Info describes the snapshot.
+func (snap *Snapshot) Info() SnapshotInfo {
+	return SnapshotInfo{Label: snap.Label, Instance: snap.Instance, CreatedAt: snap.CreatedAt, Entries: len(snap.Entries)}
+}
+
+func (s *Store) snapshotIndexLocked(label string) int {
+	return slices.IndexFunc(s.data.Snapshots, func(snap *Snapshot) bool { return strings.EqualFold(snap.Label, label) })
+}
@@ -0,0 +1,79 @@
+// Package store persists engagement data that Burp doesn't keep, such as
+// recorded findings and their retest history, scanner issues imported from
+// Burp reports, triage statuses of scanner issues, saved proxy history views,
+// snapshots of proxy history, how far passive checks have run over it, and
+// the endpoint inventory with the coverage of each endpoint, in a single JSON
+// file.
+package store
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// data is the on-disk layout of the store file.
+type data struct {
+	NextFindingID int            `json:"nextFindingId"`
+	Findings      []*Finding     `json:"findings"`
+	Issues        []*Issue       `json:"issues,omitempty"`
+	Views         []*View        `json:"views,omitempty"`
+	Snapshots     []*Snapshot    `json:"snapshots,omitempty"`
+	Triage        []*IssueTriage `json:"triage,omitempty"`
+	// PassiveCursors maps an instance to the proxy history entries passive
+	// checks have covered.
+	PassiveCursors map[string]int `json:"passiveCursors,omitempty"`
+	Endpoints      []*Endpoint    `json:"endpoints,omitempty"`
+}
+
+// Store is a JSON file-backed store. All methods are safe for concurrent use.
+// Every mutation is written through to disk before returning.
+type Store struct {
+	mu   sync.Mutex
+	path string
+	data data
+}
+
+// Open loads the store at path. A missing file yields an empty store;
+// the file and its directory are created on the first write.
+func Open(path string) (*Store, error) {
+	s := &Store{path: path}
+	raw, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read store: %w", err)
+	}
+	if err := json.Unmarshal(raw, &s.data); err != nil {
+		return nil, fmt.Errorf("parse store %s: %w", path, err)
+	}
+	return s, nil
+}
+
+// Path returns the file backing the store.
+func (s *Store) Path() string {
+	return s.path
+}
+
+// save writes the store atomically (temp file + rename). Callers hold s.mu.
+func (s *Store) save() error {
+	raw, err := json.MarshalIndent(&s.data, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode store: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o700); err != nil {
+		return fmt.Errorf("create store directory: %w", err)
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, raw, 0o600); err != nil {
+		return fmt.Errorf("write store: %w", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return fmt.Errorf("write store: %w", err)
+	}
+	return nil
+}
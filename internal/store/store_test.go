@@ -0,0 +1,290 @@
+package store
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestOpen_Missing(t *testing.T) {
+	s, err := Open(filepath.Join(t.TempDir(), "nested", "store.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(s.Findings()) != 0 {
+		t.Error("expected empty store")
+	}
+}
+
+func TestFindings_PersistAndReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "store.json")
+	s, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := s.AddFinding(Finding{
+		Title:      "IDOR on /api/orders",
+		Steps:      []Step{{Raw: "GET /api/orders/2 HTTP/1.1\r\nHost: example.com\r\n\r\n"}},
+		Assertions: []Assertion{{Type: "status", Value: "200"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.ID != "F-1" || f.CreatedAt.IsZero() {
+		t.Errorf("got %+v", f)
+	}
+	if err := s.AddRetest(f.ID, Retest{At: time.Now(), Verdict: VerdictFixed}); err != nil {
+		t.Fatal(err)
+	}
+
+	reloaded, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := reloaded.Finding("F-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Title != f.Title || len(got.Retests) != 1 || got.Retests[0].Verdict != VerdictFixed {
+		t.Errorf("reloaded finding = %+v", got)
+	}
+
+	next, err := reloaded.AddFinding(Finding{Title: "second"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if next.ID != "F-2" {
+		t.Errorf("next ID = %s, want F-2", next.ID)
+	}
+}
+
+func TestFinding_Unknown(t *testing.T) {
+	s, _ := Open(filepath.Join(t.TempDir(), "store.json"))
+	if _, err := s.Finding("F-9"); err == nil {
+		t.Error("expected error for unknown finding")
+	}
+	if err := s.AddRetest("F-9", Retest{}); err == nil {
+		t.Error("expected error for unknown finding")
+	}
+}
+
+func TestImportIssues_Dedupes(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "store.json")
+	s, _ := Open(path)
+	issues := []Issue{
+		{SerialNumber: "1", Name: "SQL injection", Host: "https://shop.example", Path: "/search"},
+		{Name: "Cookie without HttpOnly", Host: "https://shop.example", Path: "/"},
+	}
+	added, err := s.ImportIssues(issues)
+	if err != nil || added != 2 {
+		t.Fatalf("added = %d, err = %v", added, err)
+	}
+
+	reloaded, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Same report again plus one new issue: only the new one is added.
+	added, err = reloaded.ImportIssues(append(issues, Issue{SerialNumber: "2", Name: "XSS", Host: "https://shop.example"}))
+	if err != nil || added != 1 {
+		t.Fatalf("re-import added = %d, err = %v", added, err)
+	}
+	got := reloaded.Issues()
+	if len(got) != 3 || got[0].Name != "SQL injection" || got[0].ImportedAt.IsZero() || got[2].SerialNumber != "2" {
+		t.Errorf("issues = %+v", got)
+	}
+}
+
+func TestViews_SaveReplaceDelete(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "store.json")
+	s, _ := Open(path)
+	if _, err := s.SaveView(View{Name: "api", Filter: ViewFilter{Host: "shop.example", URLRegex: "/api/"}}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.SaveView(View{Name: "errors", Filter: ViewFilter{StatusCodes: []int{500}}}); err != nil {
+		t.Fatal(err)
+	}
+	// Saving under an existing name, in any case, replaces the view in place.
+	if _, err := s.SaveView(View{Name: "API", Filter: ViewFilter{Methods: []string{"POST"}}}); err != nil {
+		t.Fatal(err)
+	}
+
+	reloaded, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	views := reloaded.Views()
+	if len(views) != 2 || views[0].Name != "API" || views[0].Filter.Host != "" || views[0].UpdatedAt.IsZero() {
+		t.Fatalf("views = %+v", views)
+	}
+	if v, err := reloaded.View("api"); err != nil || v.Filter.Methods[0] != "POST" {
+		t.Errorf("view = %+v, err = %v", v, err)
+	}
+	if err := reloaded.DeleteView("errors"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := reloaded.View("errors"); err == nil {
+		t.Error("expected error for deleted view")
+	}
+	if err := reloaded.DeleteView("errors"); err == nil {
+		t.Error("expected error deleting a missing view")
+	}
+}
+
+func TestSnapshots_SaveAndReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "store.json")
+	s, _ := Open(path)
+	entries := []HistoryEntry{{ID: 1, Request: "GET / HTTP/1.1\r\nHost: a\r\n\r\n", Response: "HTTP/1.1 200 OK\r\n\r\n"}}
+	info, err := s.SaveSnapshot(Snapshot{Label: "phase1", Entries: entries})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Entries != 1 || info.CreatedAt.IsZero() {
+		t.Errorf("info = %+v", info)
+	}
+	// Labels are never reused, in any case.
+	if _, err := s.SaveSnapshot(Snapshot{Label: "Phase1"}); err == nil {
+		t.Error("expected error reusing a label")
+	}
+
+	reloaded, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if snaps := reloaded.Snapshots(); len(snaps) != 1 || snaps[0].Label != "phase1" {
+		t.Fatalf("snapshots = %+v", snaps)
+	}
+	snap, err := reloaded.Snapshot("PHASE1")
+	if err != nil || len(snap.Entries) != 1 || snap.Entries[0].Response != entries[0].Response {
+		t.Errorf("snapshot = %+v, err = %v", snap, err)
+	}
+	if _, err := reloaded.Snapshot("phase2"); err == nil {
+		t.Error("expected error for a missing snapshot")
+	}
+}
+
+func TestSetIssueVerification_Persists(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "store.json")
+	s, _ := Open(path)
+	if _, err := s.ImportIssues([]Issue{{Name: "SQL injection", Host: "https://a"}, {Name: "XSS", Host: "https://a"}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SetIssueVerification(1, IssueVerification{Verdict: IssueStale, Reason: "gone"}); err != nil {
+		t.Fatal(err)
+	}
+	reloaded, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	issues := reloaded.Issues()
+	if issues[0].Verification != nil || issues[1].Verification == nil || issues[1].Verification.Verdict != IssueStale {
+		t.Errorf("issues = %+v", issues)
+	}
+}
+
+func TestIssueTriage_SetReplace(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "store.json")
+	s, _ := Open(path)
+	if _, err := s.SetIssueTriage(IssueTriage{Name: "XSS", URL: "https://a/", Status: "maybe"}); err == nil {
+		t.Error("expected error for an unknown status")
+	}
+	if _, err := s.SetIssueTriage(IssueTriage{Name: "XSS", URL: "https://a/", Status: TriageNeedsReview}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.SetIssueTriage(IssueTriage{Name: "XSS", URL: "https://a/", Status: TriageConfirmed, Note: "alert fired"}); err != nil {
+		t.Fatal(err)
+	}
+	reloaded, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if all := reloaded.IssueTriages(); len(all) != 1 || all[0].Status != TriageConfirmed || all[0].UpdatedAt.IsZero() {
+		t.Errorf("triage = %+v", all)
+	}
+	if _, ok := reloaded.IssueTriage("XSS", "https://b/"); ok {
+		t.Error("status matched another URL")
+	}
+}
+
+func TestPassiveCursor_PerInstance(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "store.json")
+	s, _ := Open(path)
+	if err := s.SetPassiveCursor("", 12); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SetPassiveCursor("client-a", 3); err != nil {
+		t.Fatal(err)
+	}
+	reloaded, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := reloaded.PassiveCursor(""); got != 12 {
+		t.Errorf("default cursor = %d", got)
+	}
+	if got := reloaded.PassiveCursor("client-a"); got != 3 {
+		t.Errorf("client-a cursor = %d", got)
+	}
+	if got := reloaded.PassiveCursor("client-b"); got != 0 {
+		t.Errorf("unknown instance cursor = %d", got)
+	}
+}
+
+func TestTemplatePath(t *testing.T) {
+	for in, want := range map[string]string{
+		"/users/42/orders?page=2":                     "/users/{id}/orders",
+		"/files/3f2504e0-4f89-11d3-9a0c-0305e82c3301": "/files/{uuid}",
+		"/blob/9f86d081884c7d659a2feaa0c55ad015":      "/blob/{hash}",
+		"/reset/aB3dE5fG7hJ9kL1mN3pQ5rS7":             "/reset/{token}",
+		"/api/v2/users/me":                            "/api/v2/users/me",
+		"/static/application-configuration-page.html": "/static/application-configuration-page.html",
+		"":      "/",
+		"/a/b/": "/a/b/",
+	} {
+		if got := TemplatePath(in); got != want {
+			t.Errorf("TemplatePath(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestEndpoints_MergeAndUses(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "store.json")
+	s, _ := Open(path)
+	added, err := s.AddEndpoints([]Endpoint{
+		{Method: "get", Origin: "https://A.test", Path: "/users/{id}", Sources: []string{EndpointFromHistory}},
+		{Method: "POST", Origin: "https://a.test", Path: "/login", Sources: []string{EndpointFromCrawl}},
+	})
+	if err != nil || added != 2 {
+		t.Fatalf("added = %d, %v", added, err)
+	}
+	// The spec's placeholder name replaces the generated one.
+	added, err = s.AddEndpoints([]Endpoint{{Method: "GET", Origin: "https://a.test", Path: "/users/{userId}", Sources: []string{EndpointFromOpenAPI}}})
+	if err != nil || added != 0 {
+		t.Fatalf("re-added = %d, %v", added, err)
+	}
+	err = s.RecordEndpointUses([]Endpoint{
+		{Method: "GET", Origin: "https://a.test", Path: "/users/{id}", Uses: []EndpointUse{{Tool: "burp_send_request", AuthProfile: "alice", Requests: 2}}},
+		{Method: "GET", Origin: "https://a.test", Path: "/users/{id}", Uses: []EndpointUse{{Tool: "burp_send_request", AuthProfile: "alice", Requests: 1}}},
+		{Method: "DELETE", Origin: "https://a.test", Path: "/users/{id}", Uses: []EndpointUse{{Tool: "burp_idor_sweep", Requests: 5}}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reloaded, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	eps := reloaded.Endpoints()
+	if len(eps) != 3 {
+		t.Fatalf("endpoints = %+v", eps)
+	}
+	if e := eps[0]; e.Method != "GET" || e.Origin != "https://a.test" || e.Path != "/users/{userId}" || len(e.Sources) != 2 ||
+		len(e.Uses) != 1 || e.Uses[0].Requests != 3 || e.Uses[0].AuthProfile != "alice" {
+		t.Errorf("merged endpoint = %+v", e)
+	}
+	if e := eps[2]; e.Method != "DELETE" || len(e.Sources) != 1 || e.Sources[0] != EndpointFromTraffic || e.AddedAt.IsZero() {
+		t.Errorf("traffic endpoint = %+v", e)
+	}
+}
@@ -0,0 +1,76 @@
+package store
+
+import (
+	"fmt"
+	"slices"
+	"time"
+)
+
+// Triage statuses of scanner issues.
+const (
+	TriageConfirmed     = "confirmed"
+	TriageFalsePositive = "false_positive"
+	TriageNeedsReview   = "needs_review"
+)
+
+// TriageStatuses lists the valid triage statuses.
+var TriageStatuses = []string{TriageConfirmed, TriageFalsePositive, TriageNeedsReview}
+
+// IssueTriage is an analyst's status for a scanner issue. It is matched to
+// live and imported issues by name and URL, so it applies to the same issue
+// whichever way it is read.
+type IssueTriage struct {
+	Name      string    `json:"name"`
+	URL       string    `json:"url"`
+	Status    string    `json:"status"`
+	Note      string    `json:"note,omitempty"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// SetIssueTriage stores t, replacing the status of the same issue, and
+// returns the stored copy.
+func (s *Store) SetIssueTriage(t IssueTriage) (IssueTriage, error) {
+	if !slices.Contains(TriageStatuses, t.Status) {
+		return IssueTriage{}, fmt.Errorf("status must be one of %v, got %q", TriageStatuses, t.Status)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t.UpdatedAt = time.Now().UTC()
+	prev := slices.Clone(s.data.Triage)
+	if i := s.triageIndexLocked(t.Name, t.URL); i >= 0 {
+		s.data.Triage[i] = &t
+	} else {
+		s.data.Triage = append(s.data.Triage, &t)
+	}
+	if err := s.save(); err != nil {
+		s.data.Triage = prev
+		return IssueTriage{}, err
+	}
+	return t, nil
+}
+
+// IssueTriage returns the status of the issue with the given name and URL.
+func (s *Store) IssueTriage(name, url string) (IssueTriage, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	i := s.triageIndexLocked(name, url)
+	if i < 0 {
+		return IssueTriage{}, false
+	}
+	return *s.data.Triage[i], true
+}
+
+// IssueTriages returns every issue status in the order first set.
+func (s *Store) IssueTriages() []IssueTriage {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]IssueTriage, len(s.data.Triage))
+	for i, t := range s.data.Triage {
+		out[i] = *t
+	}
+	return out
+}
+
+func (s *Store) triageIndexLocked(name, url string) int {
+	return slices.IndexFunc(s.data.Triage, func(t *IssueTriage) bool { return t.Name == name && t.URL == url })
+}
@@ -0,0 +1,87 @@
+package store
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// ViewFilter selects proxy history entries. Empty fields match everything;
+// an entry must match every field that is set.
+type ViewFilter struct {
+	Host        string   `json:"host,omitempty"`
+	Methods     []string `json:"methods,omitempty"`
+	URLRegex    string   `json:"urlRegex,omitempty"`
+	StatusCodes []int    `json:"statusCodes,omitempty"`
+}
+
+// View is a named, saved filter over proxy history.
+type View struct {
+	Name        string     `json:"name"`
+	Description string     `json:"description,omitempty"`
+	Filter      ViewFilter `json:"filter"`
+	UpdatedAt   time.Time  `json:"updatedAt"`
+}
+
+// SaveView stores v under its name, replacing any view with the same name
+// (compared case-insensitively), and returns the stored copy.
+func (s *Store) SaveView(v View) (View, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	v.UpdatedAt = time.Now().UTC()
+	prev := slices.Clone(s.data.Views)
+	if i := s.viewIndexLocked(v.Name); i >= 0 {
+		s.data.Views[i] = &v
+	} else {
+		s.data.Views = append(s.data.Views, &v)
+	}
+	if err := s.save(); err != nil {
+		s.data.Views = prev
+		return View{}, err
+	}
+	return v, nil
+}
+
+// View returns the view with the given name.
+func (s *Store) View(name string) (View, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	i := s.viewIndexLocked(name)
+	if i < 0 {
+		return View{}, fmt.Errorf("view %q not found", name)
+	}
+	return *s.data.Views[i], nil
+}
+
+// Views returns all views in the order they were first saved.
+func (s *Store) Views() []View {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]View, len(s.data.Views))
+	for i, v := range s.data.Views {
+		out[i] = *v
+	}
+	return out
+}
+
+// DeleteView removes the view with the given name.
+func (s *Store) DeleteView(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	i := s.viewIndexLocked(name)
+	if i < 0 {
+		return fmt.Errorf("view %q not found", name)
+	}
+	prev := slices.Clone(s.data.Views)
+	s.data.Views = slices.Delete(s.data.Views, i, i+1)
+	if err := s.save(); err != nil {
+		s.data.Views = prev
+		return err
+	}
+	return nil
+}
+
+func (s *Store) viewIndexLocked(name string) int {
+	return slices.IndexFunc(s.data.Views, func(v *View) bool { return strings.EqualFold(v.Name, name) })
+}
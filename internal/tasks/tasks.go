@@ -0,0 +1,275 @@
+// Package tasks runs long operations in the background so a tool call can
+// return at once and the caller polls for progress and output.
+package tasks
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+)
+
+// Task states.
+const (
+	StatusRunning  = "running"
+	StatusDone     = "done"
+	StatusFailed   = "failed"
+	StatusCanceled = "canceled"
+)
+
+// maxOutput is how much output a task keeps. Older bytes are dropped, but
+// offsets keep counting from the start so polling stays consistent.
+const maxOutput = 256 << 10
+
+// Snapshot is a task's state at one point in time.
+type Snapshot struct {
+	ID        string     `json:"id"`
+	Kind      string     `json:"kind"`
+	Summary   string     `json:"summary,omitempty"`
+	Status    string     `json:"status"`
+	StartedAt time.Time  `json:"startedAt"`
+	EndedAt   *time.Time `json:"endedAt,omitempty"`
+	Error     string     `json:"error,omitempty"`
+	ErrorCode string     `json:"errorCode,omitempty"` // when the error has an ErrorCode method
+	Progress  *Progress  `json:"progress,omitempty"`
+	// Result is the operation's result: final once the task is done,
+	// partial while it runs if the operation publishes one.
+	Result any `json:"result,omitempty"`
+	// Output is the task's output from the requested offset on.
+	Output string `json:"output,omitempty"`
+	// OutputBytes is the total output written so far; pass it as the next
+	// offset to read only what is new.
+	OutputBytes int `json:"outputBytes"`
+	// Dropped is set when output before the requested offset was discarded
+	// to stay within the size cap.
+	Dropped bool `json:"dropped,omitempty"`
+}
+
+// Progress is how far a task has come.
+type Progress struct {
+	Done    int    `json:"done"`
+	Total   int    `json:"total,omitempty"` // 0 when unknown
+	Current string `json:"current,omitempty"`
+}
+
+// Task is one background operation. It is an io.Writer for its output.
+type Task struct {
+	mu        sync.Mutex
+	id, kind  string
+	summary   string
+	status    string
+	startedAt time.Time
+	endedAt   time.Time
+	err       error
+	progress  *Progress
+	result    any
+	out       []byte
+	written   int // total bytes ever written; out holds the tail
+	cancel    context.CancelFunc
+	canceled  bool
+	done      chan struct{}
+}
+
+type taskKey struct{}
+
+// FromContext returns the task whose operation ctx belongs to, or nil
+// outside a task.
+func FromContext(ctx context.Context) *Task {
+	t, _ := ctx.Value(taskKey{}).(*Task)
+	return t
+}
+
+// ID returns the task's ID.
+func (t *Task) ID() string {
+	return t.id
+}
+
+// SetProgress records how far the operation has come.
+func (t *Task) SetProgress(done, total int, current string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.progress = &Progress{Done: done, Total: total, Current: current}
+}
+
+// SetResult records the operation's result, partial or final. v is copied
+// through JSON, so the caller may keep changing it.
+func (t *Task) SetResult(v any) error {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("encode task result: %w", err)
+	}
+	var copied any
+	if err := json.Unmarshal(raw, &copied); err != nil {
+		return fmt.Errorf("encode task result: %w", err)
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.result = copied
+	return nil
+}
+
+// Write appends to the task's output, dropping the oldest bytes past the cap.
+func (t *Task) Write(p []byte) (int, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.out = append(t.out, p...)
+	t.written += len(p)
+	if over := len(t.out) - maxOutput; over > 0 {
+		t.out = append(t.out[:0], t.out[over:]...)
+	}
+	return len(p), nil
+}
+
+// Snapshot returns the task's state with output from offset on.
+func (t *Task) Snapshot(offset int) Snapshot {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	s := Snapshot{
+		ID:          t.id,
+		Kind:        t.kind,
+		Summary:     t.summary,
+		Status:      t.status,
+		StartedAt:   t.startedAt,
+		Result:      t.result,
+		OutputBytes: t.written,
+	}
+	if t.progress != nil {
+		p := *t.progress
+		s.Progress = &p
+	}
+	if !t.endedAt.IsZero() {
+		ended := t.endedAt
+		s.EndedAt = &ended
+	}
+	if t.err != nil {
+		s.Error = t.err.Error()
+		var coded interface{ ErrorCode() string }
+		if errors.As(t.err, &coded) {
+			s.ErrorCode = coded.ErrorCode()
+		}
+	}
+	first := t.written - len(t.out) // offset of out[0]
+	offset = min(max(offset, 0), t.written)
+	if offset < first {
+		s.Dropped = true
+		offset = first
+	}
+	s.Output = string(t.out[offset-first:])
+	return s
+}
+
+// Wait blocks until the task ends or ctx is done.
+func (t *Task) Wait(ctx context.Context) error {
+	select {
+	case <-t.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Manager starts tasks and looks them up by ID. It is safe for concurrent use.
+type Manager struct {
+	mu     sync.Mutex
+	nextID int
+	tasks  map[string]*Task
+	order  []*Task
+}
+
+// NewManager returns an empty manager.
+func NewManager() *Manager {
+	return &Manager{tasks: make(map[string]*Task)}
+}
+
+// Start runs fn in the background and returns the task at once. fn's
+// context keeps ctx's values but not its cancellation, so the task outlives
+// the call that started it; it ends after timeout (0 for none) or on
+// Cancel. fn writes its output to the task; its error, if any, fails the task.
+func (m *Manager) Start(ctx context.Context, kind, summary string, timeout time.Duration, fn func(ctx context.Context, t *Task) error) *Task {
+	ctx = context.WithoutCancel(ctx)
+	var cancel context.CancelFunc
+	if timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+	} else {
+		ctx, cancel = context.WithCancel(ctx)
+	}
+
+	m.mu.Lock()
+	m.nextID++
+	t := &Task{
+		id:        fmt.Sprintf("T-%d", m.nextID),
+		kind:      kind,
+		summary:   summary,
+		status:    StatusRunning,
+		startedAt: time.Now().UTC(),
+		cancel:    cancel,
+		done:      make(chan struct{}),
+	}
+	m.tasks[t.id] = t
+	m.order = append(m.order, t)
+	m.mu.Unlock()
+	ctx = context.WithValue(ctx, taskKey{}, t)
+
+	go func() {
+		defer close(t.done)
+		defer cancel()
+		err := fn(ctx, t)
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		t.endedAt = time.Now().UTC()
+		switch {
+		case t.canceled:
+			t.status = StatusCanceled
+		case errors.Is(ctx.Err(), context.DeadlineExceeded):
+			t.status, t.err = StatusFailed, fmt.Errorf("timed out after %s", timeout)
+		case err != nil:
+			t.status, t.err = StatusFailed, err
+		default:
+			t.status = StatusDone
+		}
+	}()
+	return t
+}
+
+// Get returns the task with the given ID.
+func (m *Manager) Get(id string) (*Task, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	t, ok := m.tasks[id]
+	if !ok {
+		return nil, fmt.Errorf("task %q not found", id)
+	}
+	return t, nil
+}
+
+// Cancel stops a running task. Its operation sees its context canceled and
+// the task ends as canceled once the operation returns.
+func (m *Manager) Cancel(id string) error {
+	t, err := m.Get(id)
+	if err != nil {
+		return err
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.status != StatusRunning {
+		return fmt.Errorf("task %s already %s", id, t.status)
+	}
+	t.canceled = true
+	t.cancel()
+	return nil
+}
+
+// List returns every task in start order, without output.
+func (m *Manager) List() []Snapshot {
+	m.mu.Lock()
+	order := append([]*Task(nil), m.order...)
+	m.mu.Unlock()
+	out := make([]Snapshot, len(order))
+	for i, t := range order {
+		out[i] = t.Snapshot(math.MaxInt)
+	}
+	return out
+}
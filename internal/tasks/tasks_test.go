@@ -0,0 +1,135 @@
+package tasks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestManager_RunAndPoll(t *testing.T) {
+	m := NewManager()
+	release := make(chan struct{})
+	task := m.Start(context.Background(), "demo", "two lines", 0, func(ctx context.Context, t *Task) error {
+		fmt.Fprintln(t, "first")
+		<-release
+		fmt.Fprintln(t, "second")
+		return nil
+	})
+
+	got, err := m.Get(task.Snapshot(0).ID)
+	if err != nil || got != task {
+		t.Fatalf("Get = %v, %v", got, err)
+	}
+	for task.Snapshot(0).OutputBytes == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	s := task.Snapshot(0)
+	if s.ID != "T-1" || s.Status != StatusRunning || s.Output != "first\n" || s.EndedAt != nil {
+		t.Errorf("running snapshot = %+v", s)
+	}
+
+	close(release)
+	if err := task.Wait(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	s = task.Snapshot(s.OutputBytes)
+	if s.Status != StatusDone || s.Output != "second\n" || s.OutputBytes != 13 || s.EndedAt == nil {
+		t.Errorf("done snapshot = %+v", s)
+	}
+	if _, err := m.Get("T-9"); err == nil {
+		t.Error("expected error for unknown task")
+	}
+}
+
+func TestManager_FailureAndTimeout(t *testing.T) {
+	m := NewManager()
+	failed := m.Start(context.Background(), "demo", "", 0, func(context.Context, *Task) error { return errors.New("boom") })
+	slow := m.Start(context.Background(), "demo", "", 10*time.Millisecond, func(ctx context.Context, _ *Task) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	failed.Wait(context.Background())
+	slow.Wait(context.Background())
+	if s := failed.Snapshot(0); s.Status != StatusFailed || s.Error != "boom" {
+		t.Errorf("failed = %+v", s)
+	}
+	if s := slow.Snapshot(0); s.Status != StatusFailed || !strings.HasPrefix(s.Error, "timed out") || s.ErrorCode != "" {
+		t.Errorf("slow = %+v", s)
+	}
+
+	coded := m.Start(context.Background(), "demo", "", 0, func(context.Context, *Task) error {
+		return fmt.Errorf("step 2: %w", codedError{})
+	})
+	coded.Wait(context.Background())
+	if s := coded.Snapshot(0); s.ErrorCode != "parse_failure" {
+		t.Errorf("coded = %+v", s)
+	}
+}
+
+type codedError struct{}
+
+func (codedError) Error() string     { return "bad response" }
+func (codedError) ErrorCode() string { return "parse_failure" }
+
+func TestTask_OutputCap(t *testing.T) {
+	task := &Task{}
+	chunk := strings.Repeat("x", maxOutput/2)
+	for range 3 {
+		task.Write([]byte(chunk))
+	}
+	s := task.Snapshot(0)
+	if !s.Dropped || len(s.Output) != maxOutput || s.OutputBytes != 3*len(chunk) {
+		t.Errorf("dropped = %v, len = %d, bytes = %d", s.Dropped, len(s.Output), s.OutputBytes)
+	}
+	if s := task.Snapshot(s.OutputBytes - 1); s.Dropped || s.Output != "x" {
+		t.Errorf("tail = %+v", s)
+	}
+}
+
+type ctxKey struct{}
+
+func TestManager_CancelListAndResult(t *testing.T) {
+	m := NewManager()
+	parent, cancelParent := context.WithCancel(context.WithValue(context.Background(), ctxKey{}, "caller"))
+	task := m.Start(parent, "scan", "partial", 0, func(ctx context.Context, t *Task) error {
+		if ctx.Value(ctxKey{}) != "caller" || FromContext(ctx) != t {
+			return errors.New("context values not carried over")
+		}
+		t.SetProgress(1, 3, "/a")
+		t.SetResult(map[string]int{"pages": 1})
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	// The caller's context ending doesn't stop the task.
+	cancelParent()
+	for task.Snapshot(0).Progress == nil {
+		time.Sleep(time.Millisecond)
+	}
+	s := task.Snapshot(0)
+	if s.Status != StatusRunning || *s.Progress != (Progress{Done: 1, Total: 3, Current: "/a"}) || s.Result.(map[string]any)["pages"] != 1.0 {
+		t.Errorf("running = %+v", s)
+	}
+
+	if err := m.Cancel(task.ID()); err != nil {
+		t.Fatal(err)
+	}
+	task.Wait(context.Background())
+	if s := task.Snapshot(0); s.Status != StatusCanceled || s.Error != "" || s.Result == nil {
+		t.Errorf("canceled = %+v", s)
+	}
+	if err := m.Cancel(task.ID()); err == nil {
+		t.Error("canceling a finished task should fail")
+	}
+
+	m.Start(context.Background(), "scan", "second", 0, func(_ context.Context, t *Task) error {
+		fmt.Fprint(t, "output")
+		return nil
+	}).Wait(context.Background())
+	list := m.List()
+	if len(list) != 2 || list[0].ID != "T-1" || list[1].Summary != "second" || list[1].Output != "" || list[1].OutputBytes != 6 {
+		t.Errorf("list = %+v", list)
+	}
+}
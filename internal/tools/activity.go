@@ -0,0 +1,165 @@
+package tools
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"net"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/c0tton-fluff/burp-mcp-server/internal/burp"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// activityLog counts tool calls and outbound requests since server start.
+type activityLog struct {
+	mu        sync.Mutex
+	started   time.Time
+	toolCalls map[string]int
+	requests  map[string]int // outbound requests per tool
+	hosts     map[string]int // scheme://host:port -> requests
+	endpoints map[string]int // "METHOD scheme://host:port/path" -> requests
+}
+
+func newActivityLog() *activityLog {
+	return &activityLog{
+		started:   time.Now().UTC(),
+		toolCalls: make(map[string]int),
+		requests:  make(map[string]int),
+		hosts:     make(map[string]int),
+		endpoints: make(map[string]int),
+	}
+}
+
+// activity is the server-wide activity log.
+var activity = newActivityLog()
+
+type toolNameKey struct{}
+
+// toolName returns the name of the tool call that ctx belongs to, if any.
+func toolName(ctx context.Context) string {
+	name, _ := ctx.Value(toolNameKey{}).(string)
+	return name
+}
+
+type authProfileKey struct{}
+
+// withAuthProfile tags ctx with the auth profile its requests are sent as.
+func withAuthProfile(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, authProfileKey{}, name)
+}
+
+// authProfileName returns the auth profile requests on ctx are sent as, or "".
+func authProfileName(ctx context.Context) string {
+	name, _ := ctx.Value(authProfileKey{}).(string)
+	return name
+}
+
+// ActivityMiddleware counts tool calls and tags their context with the tool
+// name and authProfile argument, so outbound requests can be attributed to
+// the tool and identity that sent them.
+func ActivityMiddleware() mcp.Middleware {
+	return func(next mcp.MethodHandler) mcp.MethodHandler {
+		return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+			if params, ok := req.GetParams().(*mcp.CallToolParamsRaw); ok && method == "tools/call" {
+				activity.mu.Lock()
+				activity.toolCalls[params.Name]++
+				activity.mu.Unlock()
+				ctx = context.WithValue(ctx, toolNameKey{}, params.Name)
+				var args struct {
+					AuthProfile string `json:"authProfile"`
+				}
+				if json.Unmarshal(params.Arguments, &args) == nil && args.AuthProfile != "" {
+					ctx = withAuthProfile(ctx, args.AuthProfile)
+				}
+			}
+			return next(ctx, method, req)
+		}
+	}
+}
+
+// recordRequests logs n outbound copies of rawNorm sent to t.
+func recordRequests(ctx context.Context, t resolvedTarget, rawNorm string, n int) {
+	parsed := burp.ParseRawRequest(rawNorm)
+	origin := t.origin()
+	path, _, _ := strings.Cut(parsed.Path, "?")
+	targetRequestsMetric.Add(float64(n), origin)
+	bytesSentMetric.Add(float64(n*len(rawNorm)), origin)
+
+	tool := toolName(ctx)
+	if tool == "" {
+		tool = "unknown"
+	}
+
+	recordEndpointUse(ctx, t, parsed.Method, path, tool, n)
+
+	activity.mu.Lock()
+	defer activity.mu.Unlock()
+	activity.requests[tool] += n
+	activity.hosts[origin] += n
+	activity.endpoints[parsed.Method+" "+origin+path] += n
+}
+
+// origin renders t as scheme://host:port.
+func (t resolvedTarget) origin() string {
+	scheme := "http"
+	if t.UseTLS {
+		scheme = "https"
+	}
+	return scheme + "://" + net.JoinHostPort(t.Host, strconv.Itoa(t.Port))
+}
+
+// HostActivity is the request count for one origin.
+type HostActivity struct {
+	Origin   string `json:"origin"`
+	Requests int    `json:"requests"`
+}
+
+// EndpointActivity is the request count for one method + URL (without query).
+type EndpointActivity struct {
+	Endpoint string `json:"endpoint"`
+	Requests int    `json:"requests"`
+}
+
+// activitySnapshot is a consistent copy of the activity log.
+type activitySnapshot struct {
+	Since          time.Time
+	ToolCalls      map[string]int
+	RequestsByTool map[string]int
+	Hosts          []HostActivity
+	Endpoints      []EndpointActivity
+}
+
+func (a *activityLog) snapshot() activitySnapshot {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	s := activitySnapshot{
+		Since:          a.started,
+		ToolCalls:      make(map[string]int, len(a.toolCalls)),
+		RequestsByTool: make(map[string]int, len(a.requests)),
+	}
+	for k, v := range a.toolCalls {
+		s.ToolCalls[k] = v
+	}
+	for k, v := range a.requests {
+		s.RequestsByTool[k] = v
+	}
+	for origin, n := range a.hosts {
+		s.Hosts = append(s.Hosts, HostActivity{Origin: origin, Requests: n})
+	}
+	for ep, n := range a.endpoints {
+		s.Endpoints = append(s.Endpoints, EndpointActivity{Endpoint: ep, Requests: n})
+	}
+	slices.SortFunc(s.Hosts, func(x, y HostActivity) int {
+		return cmp.Or(cmp.Compare(y.Requests, x.Requests), cmp.Compare(x.Origin, y.Origin))
+	})
+	slices.SortFunc(s.Endpoints, func(x, y EndpointActivity) int {
+		return cmp.Or(cmp.Compare(y.Requests, x.Requests), cmp.Compare(x.Endpoint, y.Endpoint))
+	})
+	return s
+}
@@ -0,0 +1,85 @@
+package tools
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/c0tton-fluff/burp-mcp-server/internal/store"
+	"github.com/c0tton-fluff/burp-mcp-server/internal/tasks"
+)
+
+func TestRecordRequests(t *testing.T) {
+	orig := activity
+	activity = newActivityLog()
+	t.Cleanup(func() { activity = orig })
+
+	ctx := context.WithValue(context.Background(), toolNameKey{}, "burp_send_request")
+	target := resolvedTarget{Host: "example.com", Port: 443, UseTLS: true}
+	recordRequests(ctx, target, "GET /a?x=1 HTTP/1.1\r\nHost: example.com\r\n\r\n", 1)
+	recordRequests(ctx, target, "GET /a?x=2 HTTP/1.1\r\nHost: example.com\r\n\r\n", 1)
+	recordRequests(context.Background(), resolvedTarget{Host: "api.example", Port: 8080}, "POST /b HTTP/1.1\r\n\r\n", 5)
+
+	snap := activity.snapshot()
+	if snap.RequestsByTool["burp_send_request"] != 2 || snap.RequestsByTool["unknown"] != 5 {
+		t.Errorf("requests by tool = %v", snap.RequestsByTool)
+	}
+	if len(snap.Hosts) != 2 || snap.Hosts[0].Origin != "http://api.example:8080" {
+		t.Errorf("hosts = %+v", snap.Hosts)
+	}
+	if len(snap.Endpoints) != 2 || snap.Endpoints[1] != (EndpointActivity{Endpoint: "GET https://example.com:443/a", Requests: 2}) {
+		t.Errorf("endpoints = %+v", snap.Endpoints)
+	}
+}
+
+func TestSummarizeFindings(t *testing.T) {
+	s := summarizeFindings([]store.Finding{
+		{ID: "F-1", Severity: "high"},
+		{ID: "F-2", Severity: "high", Retests: []store.Retest{{At: time.Now(), Verdict: store.VerdictFixed}}},
+		{ID: "F-3", Retests: []store.Retest{{Verdict: store.VerdictFixed}, {Verdict: store.VerdictVulnerable}}},
+	})
+	if s.Total != 3 || s.BySeverity["high"] != 2 || s.BySeverity["unrated"] != 1 {
+		t.Errorf("by severity = %+v", s.BySeverity)
+	}
+	if s.ByVerdict["untested"] != 1 || s.ByVerdict["fixed"] != 1 || s.ByVerdict["vulnerable"] != 1 {
+		t.Errorf("by verdict = %+v", s.ByVerdict)
+	}
+	if len(s.Open) != 2 || s.Open[1].ID != "F-3" {
+		t.Errorf("open = %+v", s.Open)
+	}
+}
+
+func TestEngagementSummary_OpenJobs(t *testing.T) {
+	st, err := store.Open(filepath.Join(t.TempDir(), "store.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	release := make(chan struct{})
+	held := taskManager.Start(context.Background(), "test", "held", 0, func(context.Context, *tasks.Task) error {
+		<-release
+		return nil
+	})
+	done := taskManager.Start(context.Background(), "test", "done", 0, func(context.Context, *tasks.Task) error {
+		return nil
+	})
+	t.Cleanup(func() { close(release) })
+	if err := done.Wait(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	_, out, err := engagementSummaryHandler(st)(context.Background(), nil, EngagementSummaryInput{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var found bool
+	for _, j := range out.OpenJobs {
+		if j.ID == done.ID() || j.Status != tasks.StatusRunning {
+			t.Errorf("finished task listed: %+v", j)
+		}
+		found = found || j.ID == held.ID()
+	}
+	if !found {
+		t.Errorf("openJobs = %+v, want %s", out.OpenJobs, held.ID())
+	}
+}
@@ -0,0 +1,303 @@
+package tools
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/c0tton-fluff/burp-mcp-server/internal/burp"
+	"github.com/c0tton-fluff/burp-mcp-server/internal/jwt"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const (
+	// minSessionEntropyBits is OWASP's floor for session identifiers.
+	minSessionEntropyBits = 64
+	// cookieDecodedPreview caps how much of a decoded value is shown.
+	cookieDecodedPreview = 200
+)
+
+// AnalyzeCookiesInput is the input for burp_analyze_cookies.
+type AnalyzeCookiesInput struct {
+	Response string `json:"response,omitempty" jsonschema:"Raw HTTP response (or use index)"`
+	Index    int    `json:"index,omitempty" jsonschema:"Proxy history index (1-based) whose response to analyze"`
+	Instance string `json:"instance,omitempty" jsonschema:"Named Burp instance from config (default: the --burp-url connection)"`
+}
+
+// CookieReport describes one Set-Cookie header.
+type CookieReport struct {
+	Name        string `json:"name"`
+	Value       string `json:"value"`
+	Domain      string `json:"domain,omitempty"`
+	Path        string `json:"path,omitempty"`
+	Expires     string `json:"expires,omitempty"`
+	MaxAge      int    `json:"maxAge,omitempty"`
+	Secure      bool   `json:"secure"`
+	HttpOnly    bool   `json:"httpOnly"`
+	SameSite    string `json:"sameSite,omitempty"`
+	Partitioned bool   `json:"partitioned,omitempty"`
+	Deleted     bool   `json:"deleted,omitempty"`
+
+	Format      string   `json:"format"`
+	Decoded     string   `json:"decoded,omitempty"`
+	EntropyBits float64  `json:"entropyBits"`
+	Session     bool     `json:"session,omitempty"`
+	Issues      []string `json:"issues,omitempty"`
+}
+
+// AnalyzeCookiesOutput is the output of burp_analyze_cookies.
+type AnalyzeCookiesOutput struct {
+	Cookies []CookieReport `json:"cookies"`
+	Errors  []string       `json:"errors,omitempty"`
+}
+
+// sessionCookieName matches cookie names that usually carry a session.
+var sessionCookieName = regexp.MustCompile(`(?i)sess|sid$|^sid|token|auth|jwt|login|remember|^id$|aspxauth|aspnetcore|phpsessid|jsessionid`)
+
+var (
+	uuidValue  = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-([0-9a-fA-F])[0-9a-fA-F]{3}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+	hexValue   = regexp.MustCompile(`^[0-9a-fA-F]+$`)
+	digitValue = regexp.MustCompile(`^[0-9]+$`)
+	// railsSigned is base64 data, "--", and an HMAC digest.
+	railsSigned = regexp.MustCompile(`^[A-Za-z0-9+/=%]+--[0-9a-fA-F]{40,}$`)
+	// flaskSession is itsdangerous: payload.timestamp.signature, with a
+	// leading dot when the payload is zlib-compressed.
+	flaskSession = regexp.MustCompile(`^\.?eyJ[A-Za-z0-9_-]*\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+$`)
+)
+
+// cookieFormat identifies well-known token formats and decodes the ones
+// that carry readable data.
+func cookieFormat(name, value string) (format, decoded string) {
+	v := value
+	if u, err := url.QueryUnescape(value); err == nil {
+		v = u
+	}
+	// Flask sessions look like JWTs too, but their first segment has no alg.
+	if tokens := jwt.Find(v, 1); len(tokens) == 1 && tokens[0] == v {
+		if t, err := jwt.Decode(v); err == nil && t.Alg() != "" {
+			claims, _ := json.Marshal(t.Claims)
+			return "jwt (" + t.Alg() + ")", string(claims)
+		}
+	}
+	lname := strings.ToLower(name)
+	switch {
+	case flaskSession.MatchString(v):
+		return "flask-session (itsdangerous)", decodeBase64Text(strings.SplitN(strings.TrimPrefix(v, "."), ".", 2)[0])
+	case strings.HasPrefix(v, "s:") && strings.Contains(lname, "sid"):
+		return "express-session (signed)", ""
+	case strings.HasPrefix(v, "CfDJ8"):
+		return "aspnetcore-dataprotection (encrypted)", ""
+	case lname == "asp.net_sessionid":
+		return "aspnet-session", ""
+	case lname == ".aspxauth" || strings.HasPrefix(lname, ".aspnet"):
+		return "aspnet-forms-auth (encrypted)", ""
+	case lname == "phpsessid":
+		return "php-session", ""
+	case lname == "jsessionid":
+		return "java-session", ""
+	case railsSigned.MatchString(v):
+		data, _, _ := strings.Cut(v, "--")
+		return "rails-signed", decodeBase64Text(data)
+	case uuidValue.MatchString(v):
+		return "uuid v" + uuidValue.FindStringSubmatch(v)[1], ""
+	case digitValue.MatchString(v):
+		return "numeric", ""
+	case hexValue.MatchString(v) && len(v) >= 16:
+		return "hex", ""
+	}
+	if d := decodeBase64Text(v); d != "" {
+		if json.Valid([]byte(d)) && (strings.HasPrefix(d, "{") || strings.HasPrefix(d, "[")) {
+			return "base64-json", d
+		}
+		return "base64-text", d
+	}
+	return "opaque", ""
+}
+
+// decodeBase64Text decodes s in any base64 alphabet and returns the text
+// it holds, or "" when it isn't base64 of printable text.
+func decodeBase64Text(s string) string {
+	if len(s) < 8 {
+		return ""
+	}
+	for _, enc := range []*base64.Encoding{base64.StdEncoding, base64.URLEncoding, base64.RawStdEncoding, base64.RawURLEncoding} {
+		data, err := enc.DecodeString(s)
+		if err != nil || burp.IsBinary(string(data)) || !utf8.Valid(data) {
+			continue
+		}
+		return cutBody(string(data), cookieDecodedPreview)
+	}
+	return ""
+}
+
+// entropyBits estimates a value's entropy as its Shannon entropy per
+// character times its length. It overestimates structured values, so a
+// low figure is meaningful and a high one is only an upper bound.
+func entropyBits(s string) float64 {
+	if s == "" {
+		return 0
+	}
+	counts := make(map[rune]int)
+	n := 0
+	for _, r := range s {
+		counts[r]++
+		n++
+	}
+	var h float64
+	for _, c := range counts {
+		p := float64(c) / float64(n)
+		h -= p * math.Log2(p)
+	}
+	return math.Round(h*float64(n)*10) / 10
+}
+
+func sameSiteName(s http.SameSite) string {
+	switch s {
+	case http.SameSiteLaxMode:
+		return "Lax"
+	case http.SameSiteStrictMode:
+		return "Strict"
+	case http.SameSiteNoneMode:
+		return "None"
+	case http.SameSiteDefaultMode:
+		return "(invalid)"
+	}
+	return ""
+}
+
+// analyzeCookie reports on one parsed Set-Cookie at time now.
+func analyzeCookie(c *http.Cookie, now time.Time) CookieReport {
+	r := CookieReport{
+		Name:        c.Name,
+		Value:       cutBody(c.Value, cookieDecodedPreview),
+		Domain:      c.Domain,
+		Path:        c.Path,
+		MaxAge:      c.MaxAge,
+		Secure:      c.Secure,
+		HttpOnly:    c.HttpOnly,
+		SameSite:    sameSiteName(c.SameSite),
+		Partitioned: c.Partitioned,
+		EntropyBits: entropyBits(c.Value),
+	}
+	if !c.Expires.IsZero() {
+		r.Expires = c.Expires.UTC().Format(time.RFC3339)
+	}
+	r.Deleted = c.MaxAge < 0 || (!c.Expires.IsZero() && c.Expires.Before(now)) || c.Value == ""
+	if r.Deleted {
+		// Clearing a cookie says nothing about how it is set.
+		r.Format = "deleted"
+		return r
+	}
+	r.Format, r.Decoded = cookieFormat(c.Name, c.Value)
+	r.Session = sessionCookieName.MatchString(c.Name)
+
+	var issues []string
+	if !c.Secure {
+		issues = append(issues, "missing Secure: sent over plain HTTP")
+	}
+	if !c.HttpOnly {
+		if r.Session {
+			issues = append(issues, "missing HttpOnly on a session cookie: readable by any XSS")
+		} else {
+			issues = append(issues, "missing HttpOnly: readable by JavaScript")
+		}
+	}
+	switch r.SameSite {
+	case "":
+		issues = append(issues, "no SameSite: Chrome defaults to Lax, other browsers may send it on cross-site requests")
+	case "None":
+		if !c.Secure {
+			issues = append(issues, "SameSite=None without Secure: browsers reject the cookie")
+		} else {
+			issues = append(issues, "SameSite=None: sent on cross-site requests, so CSRF protection must come from elsewhere")
+		}
+	case "(invalid)":
+		issues = append(issues, "SameSite has an unrecognized value; browsers fall back to their default")
+	}
+	if strings.HasPrefix(c.Name, "__Host-") && (!c.Secure || c.Path != "/" || c.Domain != "") {
+		issues = append(issues, "__Host- prefix requires Secure, Path=/, and no Domain: browsers reject it")
+	}
+	if strings.HasPrefix(c.Name, "__Secure-") && !c.Secure {
+		issues = append(issues, "__Secure- prefix requires Secure: browsers reject it")
+	}
+	if c.Domain != "" {
+		issues = append(issues, fmt.Sprintf("Domain=%s shares the cookie with every subdomain", c.Domain))
+	}
+	if r.Session {
+		if c.MaxAge > 30*24*3600 || !c.Expires.IsZero() && c.Expires.Sub(now) > 30*24*time.Hour {
+			issues = append(issues, "session cookie persists for over 30 days")
+		}
+		if r.Decoded == "" && r.EntropyBits < minSessionEntropyBits && !strings.HasSuffix(r.Format, "(encrypted)") {
+			issues = append(issues, fmt.Sprintf("about %.0f bits of entropy, under the %d-bit minimum for session IDs: check predictability", r.EntropyBits, minSessionEntropyBits))
+		}
+	}
+	switch {
+	case r.Format == "numeric":
+		issues = append(issues, "numeric value: try neighbouring values")
+	case r.Format == "uuid v1":
+		issues = append(issues, "UUID v1 is time-based and predictable")
+	case r.Format == "base64-json" || r.Format == "base64-text":
+		issues = append(issues, "readable data under base64: check whether editing it is accepted")
+	case strings.HasPrefix(r.Format, "jwt"):
+		issues = append(issues, "JWT: check it with burp_decode_jwt")
+	}
+	r.Issues = issues
+	return r
+}
+
+func analyzeCookiesHandler(client *burp.Client) func(context.Context, *mcp.CallToolRequest, AnalyzeCookiesInput) (*mcp.CallToolResult, AnalyzeCookiesOutput, error) {
+	return func(ctx context.Context, _ *mcp.CallToolRequest, input AnalyzeCookiesInput) (*mcp.CallToolResult, AnalyzeCookiesOutput, error) {
+		ctx = burp.WithInstance(ctx, input.Instance)
+
+		respRaw := input.Response
+		switch {
+		case input.Index > 0 && respRaw != "":
+			return nil, AnalyzeCookiesOutput{}, fmt.Errorf("provide response or index, not both")
+		case input.Index > 0:
+			var err error
+			if _, respRaw, err = historyEntry(ctx, client, input.Index); err != nil {
+				return nil, AnalyzeCookiesOutput{}, err
+			}
+		case respRaw == "":
+			return nil, AnalyzeCookiesOutput{}, fmt.Errorf("response or index is required")
+		}
+
+		resp := burp.ParseHTTPResponse(respRaw, 0, 0)
+		if resp == nil {
+			return nil, AnalyzeCookiesOutput{}, parseFailure("failed to parse response")
+		}
+		out := AnalyzeCookiesOutput{Cookies: []CookieReport{}}
+		now := time.Now()
+		for name, values := range resp.Headers {
+			if !strings.EqualFold(name, "Set-Cookie") {
+				continue
+			}
+			for _, v := range values {
+				c, err := http.ParseSetCookie(v)
+				if err != nil {
+					out.Errors = append(out.Errors, fmt.Sprintf("%q: %v", v, err))
+					continue
+				}
+				out.Cookies = append(out.Cookies, analyzeCookie(c, now))
+			}
+		}
+		return nil, out, nil
+	}
+}
+
+// RegisterAnalyzeCookiesTool registers the burp_analyze_cookies tool.
+func RegisterAnalyzeCookiesTool(server *mcp.Server, client *burp.Client) {
+	addTool(server, &mcp.Tool{
+		Name: "burp_analyze_cookies",
+		Description: `Audit every Set-Cookie in a response (raw or proxy history index): attributes, missing Secure/HttpOnly/SameSite, prefix rules, token format (JWT, base64 JSON, ASP.NET, PHP, Java, Flask, Rails, Express), and estimated entropy. ` +
+			`Returns {cookies: [{name, value, domain, path, expires, secure, httpOnly, sameSite, format, decoded, entropyBits, session, issues}], errors}.`,
+	}, analyzeCookiesHandler(client))
+}
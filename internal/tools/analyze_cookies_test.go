@@ -0,0 +1,80 @@
+package tools
+
+import (
+	"context"
+	"encoding/base64"
+	"strings"
+	"testing"
+)
+
+func TestCookieFormat(t *testing.T) {
+	enc := base64.RawURLEncoding.EncodeToString
+	tests := []struct {
+		name, value, want, decoded string
+	}{
+		{"auth", enc([]byte(`{"alg":"HS256"}`)) + "." + enc([]byte(`{"sub":"1"}`)) + ".c2ln", "jwt (HS256)", `{"sub":"1"}`},
+		{"session", enc([]byte(`{"user_id":1}`)) + ".ZmQ2Ng.c2lnbmF0dXJl", "flask-session (itsdangerous)", `{"user_id":1}`},
+		{"prefs", base64.StdEncoding.EncodeToString([]byte(`{"role":"user","admin":false}`)), "base64-json", `{"role":"user","admin":false}`},
+		{"remember", base64.StdEncoding.EncodeToString([]byte("alice:1700000000")), "base64-text", "alice:1700000000"},
+		{"ASP.NET_SessionId", "kq1nq2ylqx0b4w3k5ygbz5mf", "aspnet-session", ""},
+		{".AspNetCore.Cookies", "CfDJ8Abc", "aspnetcore-dataprotection (encrypted)", ""},
+		{"connect.sid", "s%3Aabc.def", "express-session (signed)", ""},
+		{"uid", "1042", "numeric", ""},
+		{"track", "6ba7b810-9dad-11d1-80b4-00c04fd430c8", "uuid v1", ""},
+		{"x", "a1b2c3d4e5f60718", "hex", ""},
+		{"x", "!!opaque!!", "opaque", ""},
+	}
+	for _, tt := range tests {
+		format, decoded := cookieFormat(tt.name, tt.value)
+		if format != tt.want || decoded != tt.decoded {
+			t.Errorf("cookieFormat(%s) = %q, %q; want %q, %q", tt.name, format, decoded, tt.want, tt.decoded)
+		}
+	}
+}
+
+func TestEntropyBits(t *testing.T) {
+	if got := entropyBits("aaaaaaaa"); got != 0 {
+		t.Errorf("entropyBits(aaaaaaaa) = %v", got)
+	}
+	if got := entropyBits("0123456789abcdef"); got != 64 {
+		t.Errorf("entropyBits(16 distinct) = %v, want 64", got)
+	}
+}
+
+func TestAnalyzeCookiesHandler(t *testing.T) {
+	resp := "HTTP/1.1 200 OK\r\n" +
+		"Set-Cookie: SESSIONID=1234; Path=/; Domain=.example.com\r\n" +
+		"Set-Cookie: __Host-csrf=8f2a9c1d7e6b5a4f3e2d1c0b9a8f7e6d; Path=/; Secure; HttpOnly; SameSite=Strict\r\n" +
+		"Set-Cookie: old=; Max-Age=0\r\n" +
+		"Set-Cookie: =bad\r\n" +
+		"Content-Length: 0\r\n\r\n"
+	_, out, err := analyzeCookiesHandler(nil)(context.Background(), nil, AnalyzeCookiesInput{Response: resp})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(out.Cookies) != 3 || len(out.Errors) != 1 {
+		t.Fatalf("cookies = %+v, errors = %q", out.Cookies, out.Errors)
+	}
+
+	sess := out.Cookies[0]
+	if !sess.Session || sess.Format != "numeric" {
+		t.Errorf("SESSIONID = %+v", sess)
+	}
+	joined := strings.Join(sess.Issues, "\n")
+	for _, want := range []string{"missing Secure", "missing HttpOnly on a session cookie", "no SameSite", "every subdomain", "bits of entropy", "numeric value"} {
+		if !strings.Contains(joined, want) {
+			t.Errorf("SESSIONID issues missing %q:\n%s", want, joined)
+		}
+	}
+
+	if host := out.Cookies[1]; len(host.Issues) != 0 || host.SameSite != "Strict" {
+		t.Errorf("__Host-csrf = %+v", host)
+	}
+	if old := out.Cookies[2]; !old.Deleted || len(old.Issues) != 0 {
+		t.Errorf("old = %+v", old)
+	}
+
+	if _, _, err := analyzeCookiesHandler(nil)(context.Background(), nil, AnalyzeCookiesInput{}); err == nil {
+		t.Error("expected an error without response or index")
+	}
+}
@@ -0,0 +1,93 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/c0tton-fluff/burp-mcp-server/internal/approval"
+	"github.com/c0tton-fluff/burp-mcp-server/internal/config"
+)
+
+// dangerousTools are the tools selected by "dangerous" in config approval.tools.
+var dangerousTools = []string{"burp_race_request", "burp_send_to_intruder", "burp_credential_test", "burp_idor_sweep"}
+
+const defaultApprovalTimeout = 5 * time.Minute
+
+// ApprovalError is returned when a gated call is denied or not approved in
+// time. Nothing is sent.
+type ApprovalError struct {
+	Tool    string
+	ID      string
+	Timeout time.Duration
+	Err     error // approval.ErrDenied, approval.ErrExpired, or a context error
+}
+
+func (e *ApprovalError) Error() string {
+	reason := "was denied"
+	if errors.Is(e.Err, approval.ErrExpired) {
+		reason = fmt.Sprintf("was not approved within %s", e.Timeout)
+	} else if !errors.Is(e.Err, approval.ErrDenied) {
+		reason = "was cancelled while waiting for approval"
+	}
+	return fmt.Sprintf("approval_required: %s call %s %s; nothing was sent", e.Tool, e.ID, reason)
+}
+
+func (e *ApprovalError) Unwrap() error { return e.Err }
+
+// approvalGate holds the tools that need approval and the queue they wait in.
+type approvalGate struct {
+	tools   map[string]bool
+	timeout time.Duration
+	queue   *approval.Queue
+}
+
+func newApprovalGate(cfg *config.ApprovalConfig, dir string) *approvalGate {
+	g := &approvalGate{tools: make(map[string]bool), timeout: defaultApprovalTimeout, queue: approval.Open(dir)}
+	if cfg == nil {
+		return g
+	}
+	for _, name := range cfg.Tools {
+		if name == "dangerous" {
+			for _, d := range dangerousTools {
+				g.tools[d] = true
+			}
+			continue
+		}
+		g.tools[name] = true
+	}
+	if cfg.TimeoutSeconds > 0 {
+		g.timeout = time.Duration(cfg.TimeoutSeconds) * time.Second
+	}
+	return g
+}
+
+// gate is the server-wide approval gate, rebuilt by Configure.
+var gate = newApprovalGate(nil, "")
+
+// requireApproval blocks a call to tool until a human approves it with the
+// approve command, or returns an *ApprovalError. Tools not listed in config
+// approval.tools pass straight through.
+func requireApproval(ctx context.Context, tool string, t resolvedTarget, summary, raw string) error {
+	if !gate.tools[tool] {
+		return nil
+	}
+	// Don't ask a human to approve something the scope would refuse anyway.
+	// Calls with no target (clearing history) have nothing to check.
+	if t.Host != "" {
+		if err := checkScope(ctx, t.Host); err != nil {
+			return err
+		}
+	}
+	req := &approval.Request{Tool: tool, Summary: summary, Target: t.Host, Raw: raw}
+	if err := gate.queue.Submit(req, gate.timeout); err != nil {
+		return fmt.Errorf("request approval: %w", err)
+	}
+	fmt.Fprintf(os.Stderr, "Approval needed for %s (%s): run 'burp-mcp-server approve %s'\n", tool, summary, req.ID)
+	if err := gate.queue.Wait(ctx, req.ID, gate.timeout); err != nil {
+		return &ApprovalError{Tool: tool, ID: req.ID, Timeout: gate.timeout, Err: err}
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/c0tton-fluff/burp-mcp-server/internal/approval"
+	"github.com/c0tton-fluff/burp-mcp-server/internal/config"
+)
+
+// decideWhenPending approves or denies the first request to show up in q.
+func decideWhenPending(t *testing.T, q *approval.Queue, approve bool) {
+	t.Helper()
+	go func() {
+		for i := 0; i < 200; i++ {
+			if pending, _ := q.Pending(); len(pending) > 0 {
+				if err := q.Decide(pending[0].ID, approve); err != nil {
+					t.Error(err)
+				}
+				return
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+		t.Error("no approval request appeared")
+	}()
+}
+
+func TestRequireApproval(t *testing.T) {
+	t.Cleanup(func() { Configure(nil) })
+	dir := t.TempDir()
+	Configure(&config.Config{Approval: &config.ApprovalConfig{Tools: []string{"dangerous"}, Dir: dir}})
+	q := approval.Open(dir)
+	target := resolvedTarget{Host: "shop.example", Port: 443, UseTLS: true}
+
+	if err := requireApproval(context.Background(), "burp_send_request", target, "", ""); err != nil {
+		t.Errorf("ungated tool: %v", err)
+	}
+
+	decideWhenPending(t, q, true)
+	if err := requireApproval(context.Background(), "burp_send_to_intruder", target, "Intruder attack", "GET / HTTP/1.1\r\n\r\n"); err != nil {
+		t.Errorf("approved call: %v", err)
+	}
+
+	decideWhenPending(t, q, false)
+	_, _, err := raceRequestHandler()(context.Background(), nil, RaceRequestInput{Raw: "GET / HTTP/1.1\r\nHost: shop.example\r\n\r\n"})
+	var ae *ApprovalError
+	if !errors.As(err, &ae) || !errors.Is(err, approval.ErrDenied) || !strings.Contains(err.Error(), "burp_race_request call "+ae.ID+" was denied") {
+		t.Errorf("denied race: %v", err)
+	}
+}
+
+func TestRequireApproval_Timeout(t *testing.T) {
+	t.Cleanup(func() { Configure(nil) })
+	Configure(&config.Config{Approval: &config.ApprovalConfig{Tools: []string{"burp_race_request"}, Dir: t.TempDir()}})
+	gate.timeout = 30 * time.Millisecond
+
+	err := requireApproval(context.Background(), "burp_race_request", resolvedTarget{Host: "shop.example"}, "race", "")
+	if !errors.Is(err, approval.ErrExpired) || !strings.Contains(err.Error(), "not approved within 30ms; nothing was sent") {
+		t.Errorf("got %v", err)
+	}
+}
@@ -0,0 +1,71 @@
+package tools
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/c0tton-fluff/burp-mcp-server/internal/burp"
+	"github.com/c0tton-fluff/burp-mcp-server/internal/store"
+)
+
+// validateAssertions rejects unknown types and values that can't be evaluated.
+func validateAssertions(assertions []store.Assertion) error {
+	if len(assertions) == 0 {
+		return fmt.Errorf("at least one assertion is required")
+	}
+	for i, a := range assertions {
+		switch a.Type {
+		case "status":
+			if _, err := strconv.Atoi(a.Value); err != nil {
+				return fmt.Errorf("assertion %d: status value must be a number, got %q", i, a.Value)
+			}
+		case "body_contains", "body_not_contains":
+			if a.Value == "" {
+				return fmt.Errorf("assertion %d: %s requires a value", i, a.Type)
+			}
+		case "body_regex":
+			if _, err := regexp.Compile(a.Value); err != nil {
+				return fmt.Errorf("assertion %d: invalid regex: %w", i, err)
+			}
+		case "header_contains", "header_absent":
+			if a.Header == "" {
+				return fmt.Errorf("assertion %d: %s requires a header name", i, a.Type)
+			}
+		default:
+			return fmt.Errorf("assertion %d: unknown type %q", i, a.Type)
+		}
+	}
+	return nil
+}
+
+// evaluateAssertions checks each assertion against resp. Assertions must be valid.
+func evaluateAssertions(resp *burp.ParsedHTTPResponse, assertions []store.Assertion) ([]store.AssertionResult, bool) {
+	results := make([]store.AssertionResult, len(assertions))
+	all := true
+	for i, a := range assertions {
+		r := store.AssertionResult{Assertion: a}
+		switch a.Type {
+		case "status":
+			want, _ := strconv.Atoi(a.Value)
+			r.Passed = resp.StatusCode == want
+			r.Actual = strconv.Itoa(resp.StatusCode)
+		case "body_contains":
+			r.Passed = strings.Contains(resp.Body, a.Value)
+		case "body_not_contains":
+			r.Passed = !strings.Contains(resp.Body, a.Value)
+		case "body_regex":
+			r.Passed = regexp.MustCompile(a.Value).MatchString(resp.Body)
+		case "header_contains":
+			r.Actual = burp.GetHeader(resp.Headers, a.Header)
+			r.Passed = r.Actual != "" && strings.Contains(r.Actual, a.Value)
+		case "header_absent":
+			r.Actual = burp.GetHeader(resp.Headers, a.Header)
+			r.Passed = r.Actual == ""
+		}
+		all = all && r.Passed
+		results[i] = r
+	}
+	return results, all
+}
@@ -0,0 +1,325 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/c0tton-fluff/burp-mcp-server/internal/burp"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// Severity and confidence values, as Burp's scanner reports them.
+const (
+	sevHigh   = "High"
+	sevMedium = "Medium"
+	sevLow    = "Low"
+	sevInfo   = "Information"
+
+	confCertain   = "Certain"
+	confFirm      = "Firm"
+	confTentative = "Tentative"
+)
+
+// minHSTSMaxAge is 180 days, the minimum most baselines accept.
+const minHSTSMaxAge = 180 * 24 * 3600
+
+// cspBypassHosts serve JSONP endpoints or old script libraries that turn an
+// allowlisted source into a script-src bypass.
+var cspBypassHosts = []string{
+	"*.googleapis.com", "ajax.googleapis.com", "www.google.com", "*.google.com",
+	"cdnjs.cloudflare.com", "*.cloudflare.com", "cdn.jsdelivr.net", "unpkg.com",
+	"*.amazonaws.com", "*.cloudfront.net", "*.azureedge.net", "*.herokuapp.com",
+	"*.firebaseapp.com", "raw.githubusercontent.com", "*.githubusercontent.com",
+}
+
+// AuditHeadersInput is the input for burp_audit_headers.
+type AuditHeadersInput struct {
+	Response string `json:"response,omitempty" jsonschema:"Raw HTTP response (or use index)"`
+	Index    int    `json:"index,omitempty" jsonschema:"Proxy history index (1-based) to audit; also lets CORS be checked against the request's Origin"`
+	TLS      *bool  `json:"tls,omitempty" jsonschema:"Whether the response came over HTTPS, for the HSTS checks (default true)"`
+	Instance string `json:"instance,omitempty" jsonschema:"Named Burp instance from config (default: the --burp-url connection)"`
+}
+
+// AuditHeadersOutput is the output of burp_audit_headers.
+type AuditHeadersOutput struct {
+	URL      string              `json:"url,omitempty"`
+	CSP      map[string][]string `json:"csp,omitempty"`
+	Findings []burp.ScannerIssue `json:"findings"`
+}
+
+// parseCSP splits a Content-Security-Policy into directives. As in
+// browsers, names are case-insensitive and only the first occurrence of a
+// directive counts.
+func parseCSP(policy string) map[string][]string {
+	csp := make(map[string][]string)
+	for _, d := range strings.Split(policy, ";") {
+		fields := strings.Fields(d)
+		if len(fields) == 0 {
+			continue
+		}
+		name := strings.ToLower(fields[0])
+		if _, seen := csp[name]; !seen {
+			csp[name] = fields[1:]
+		}
+	}
+	return csp
+}
+
+// headerAudit collects findings for one response.
+type headerAudit struct {
+	url      string
+	findings []burp.ScannerIssue
+}
+
+func (a *headerAudit) add(name, severity, confidence, detail string) {
+	a.findings = append(a.findings, burp.ScannerIssue{
+		Name:        name,
+		Severity:    severity,
+		Confidence:  confidence,
+		URL:         a.url,
+		IssueDetail: detail,
+	})
+}
+
+func (a *headerAudit) checkCSP(headers map[string][]string) map[string][]string {
+	policy := burp.GetHeader(headers, "Content-Security-Policy")
+	if policy == "" {
+		if burp.GetHeader(headers, "Content-Security-Policy-Report-Only") != "" {
+			a.add("Content-Security-Policy is report-only", sevLow, confCertain,
+				"Only Content-Security-Policy-Report-Only is set: violations are reported, nothing is blocked.")
+		} else {
+			a.add("Content-Security-Policy missing", sevLow, confCertain,
+				"No CSP: injected scripts run with nothing to stop them, so any XSS is fully exploitable.")
+		}
+		return nil
+	}
+	csp := parseCSP(policy)
+
+	scripts, directive := csp["script-src"], "script-src"
+	if scripts == nil {
+		scripts, directive = csp["default-src"], "default-src"
+	}
+	if scripts == nil {
+		a.add("CSP does not restrict scripts", sevMedium, confCertain,
+			"Neither script-src nor default-src is set, so scripts load from anywhere.")
+	} else {
+		a.checkScriptSources(directive, scripts)
+	}
+	if csp["object-src"] == nil && csp["default-src"] == nil {
+		a.add("CSP does not restrict plugins", sevLow, confCertain,
+			"Neither object-src nor default-src is set; object-src 'none' blocks plugin-based script execution.")
+	}
+	if csp["base-uri"] == nil {
+		a.add("CSP missing base-uri", sevLow, confFirm,
+			"Without base-uri an injected <base> tag can repoint relative script URLs, bypassing nonce-based policies.")
+	}
+	return csp
+}
+
+func (a *headerAudit) checkScriptSources(directive string, sources []string) {
+	lower := make([]string, len(sources))
+	for i, s := range sources {
+		lower[i] = strings.ToLower(s)
+	}
+	// 'strict-dynamic' makes browsers ignore host and scheme allowlists, and
+	// a nonce or hash makes them ignore 'unsafe-inline'.
+	strictDynamic := slices.Contains(lower, "'strict-dynamic'")
+	nonceOrHash := slices.ContainsFunc(lower, func(s string) bool {
+		return strings.HasPrefix(s, "'nonce-") || strings.HasPrefix(s, "'sha")
+	})
+
+	if slices.Contains(lower, "'unsafe-inline'") && !nonceOrHash {
+		a.add("CSP allows unsafe-inline scripts", sevMedium, confCertain,
+			fmt.Sprintf("%s includes 'unsafe-inline' with no nonce or hash: inline <script> and event handlers run, so the policy doesn't stop XSS.", directive))
+	}
+	if slices.Contains(lower, "'unsafe-eval'") {
+		a.add("CSP allows unsafe-eval", sevLow, confCertain,
+			fmt.Sprintf("%s includes 'unsafe-eval': eval() and string timers run, which script gadgets in allowed libraries can exploit.", directive))
+	}
+	if strictDynamic {
+		return
+	}
+	for _, s := range lower {
+		switch {
+		case s == "*":
+			a.add("CSP allows scripts from any origin", sevMedium, confCertain,
+				fmt.Sprintf("%s includes the wildcard *: an attacker can load a script from a host they control.", directive))
+		case s == "http:" || s == "https:" || s == "data:" || s == "blob:":
+			a.add("CSP allows scripts from a whole scheme", sevMedium, confCertain,
+				fmt.Sprintf("%s includes %s, which matches any host (or, for data:, inline content).", directive, s))
+		case s == "http://*" || s == "https://*":
+			a.add("CSP allows scripts from any origin", sevMedium, confCertain,
+				fmt.Sprintf("%s includes %s, which matches any host.", directive, s))
+		default:
+			for _, h := range cspBypassHosts {
+				if s == h || strings.TrimPrefix(strings.TrimPrefix(s, "https://"), "http://") == h {
+					a.add("CSP allowlists a host with known bypasses", sevLow, confFirm,
+						fmt.Sprintf("%s allows %s, which hosts JSONP endpoints or script libraries usable to bypass the policy.", directive, s))
+					break
+				}
+			}
+		}
+	}
+}
+
+func (a *headerAudit) checkHSTS(headers map[string][]string) {
+	hsts := burp.GetHeader(headers, "Strict-Transport-Security")
+	if hsts == "" {
+		a.add("Strict-Transport-Security missing", sevLow, confCertain,
+			"Without HSTS a network attacker can downgrade the first request to HTTP and strip TLS.")
+		return
+	}
+	maxAge := -1
+	includeSub := false
+	for _, d := range strings.Split(hsts, ";") {
+		name, value, _ := strings.Cut(strings.TrimSpace(d), "=")
+		switch strings.ToLower(name) {
+		case "max-age":
+			if n, err := strconv.Atoi(strings.Trim(value, `"`)); err == nil {
+				maxAge = n
+			}
+		case "includesubdomains":
+			includeSub = true
+		}
+	}
+	switch {
+	case maxAge < 0:
+		a.add("Strict-Transport-Security is invalid", sevLow, confCertain, "HSTS has no valid max-age, so browsers ignore it.")
+	case maxAge == 0:
+		a.add("Strict-Transport-Security disabled", sevLow, confCertain, "max-age=0 tells browsers to forget HSTS for this host.")
+	case maxAge < minHSTSMaxAge:
+		a.add("Strict-Transport-Security max-age is short", sevInfo, confCertain,
+			fmt.Sprintf("max-age=%d is under 180 days, so protection lapses between visits.", maxAge))
+	}
+	if maxAge > 0 && !includeSub {
+		a.add("Strict-Transport-Security without includeSubDomains", sevInfo, confCertain,
+			"Subdomains aren't covered, so cookies scoped to the parent domain can leak over HTTP from a subdomain.")
+	}
+}
+
+func (a *headerAudit) checkFraming(headers map[string][]string, csp map[string][]string) {
+	xfo := strings.ToUpper(strings.TrimSpace(burp.GetHeader(headers, "X-Frame-Options")))
+	_, ancestors := csp["frame-ancestors"]
+	switch {
+	case ancestors:
+		if slices.Contains(csp["frame-ancestors"], "*") {
+			a.add("Frameable response (potential clickjacking)", sevLow, confCertain,
+				"CSP frame-ancestors * lets any site frame this page.")
+		}
+	case xfo == "":
+		a.add("Frameable response (potential clickjacking)", sevLow, confFirm,
+			"Neither X-Frame-Options nor CSP frame-ancestors is set, so any site can frame this page.")
+	case strings.HasPrefix(xfo, "ALLOW-FROM"):
+		a.add("X-Frame-Options ALLOW-FROM is unsupported", sevLow, confCertain,
+			"Current browsers ignore ALLOW-FROM, leaving the page frameable; use CSP frame-ancestors.")
+	case xfo != "DENY" && xfo != "SAMEORIGIN":
+		a.add("X-Frame-Options is invalid", sevLow, confCertain,
+			fmt.Sprintf("X-Frame-Options %q isn't DENY or SAMEORIGIN, so browsers ignore it.", xfo))
+	}
+}
+
+func (a *headerAudit) checkMisc(headers map[string][]string) {
+	if !strings.EqualFold(strings.TrimSpace(burp.GetHeader(headers, "X-Content-Type-Options")), "nosniff") {
+		a.add("X-Content-Type-Options missing", sevLow, confCertain,
+			"Without nosniff, browsers may sniff an uploaded or reflected file as HTML or script.")
+	}
+	switch rp := strings.ToLower(burp.GetHeader(headers, "Referrer-Policy")); {
+	case rp == "":
+		a.add("Referrer-Policy missing", sevInfo, confCertain,
+			"Browsers default to strict-origin-when-cross-origin, which is reasonable, but older ones send full URLs, including tokens in query strings.")
+	case strings.Contains(rp, "unsafe-url") || strings.Contains(rp, "no-referrer-when-downgrade"):
+		a.add("Referrer-Policy leaks full URLs", sevLow, confCertain,
+			fmt.Sprintf("Referrer-Policy %s sends the full URL, with any tokens in its query, to other sites.", rp))
+	}
+	if burp.GetHeader(headers, "Permissions-Policy") == "" && burp.GetHeader(headers, "Feature-Policy") == "" {
+		a.add("Permissions-Policy missing", sevInfo, confCertain,
+			"No Permissions-Policy: framed or injected content can request camera, microphone, geolocation, and other features.")
+	}
+}
+
+// checkCORS reviews the CORS headers, against the request's Origin when
+// known.
+func (a *headerAudit) checkCORS(headers map[string][]string, origin string) {
+	acao := strings.TrimSpace(burp.GetHeader(headers, "Access-Control-Allow-Origin"))
+	if acao == "" {
+		return
+	}
+	creds := strings.EqualFold(strings.TrimSpace(burp.GetHeader(headers, "Access-Control-Allow-Credentials")), "true")
+	switch {
+	case acao == "null":
+		sev := sevLow
+		if creds {
+			sev = sevHigh
+		}
+		a.add("CORS trusts the null origin", sev, confCertain,
+			"Access-Control-Allow-Origin: null is matched by sandboxed iframes and data: URLs, which any attacker can create.")
+	case acao == "*" && creds:
+		a.add("CORS wildcard with credentials", sevInfo, confCertain,
+			"Browsers refuse credentials with a wildcard origin, but the intent suggests other endpoints may reflect origins.")
+	case acao == "*":
+		a.add("CORS allows any origin", sevInfo, confCertain,
+			"Any site can read this response without credentials; fine for public data only.")
+	case origin != "" && acao == origin && creds:
+		a.add("CORS reflects the request Origin with credentials", sevMedium, confTentative,
+			fmt.Sprintf("Allow-Origin echoes %s and allows credentials. Resend with Origin: https://evil.example; if that is echoed too, any site can read authenticated responses.", origin))
+	}
+}
+
+func auditHeadersHandler(client *burp.Client) func(context.Context, *mcp.CallToolRequest, AuditHeadersInput) (*mcp.CallToolResult, AuditHeadersOutput, error) {
+	return func(ctx context.Context, _ *mcp.CallToolRequest, input AuditHeadersInput) (*mcp.CallToolResult, AuditHeadersOutput, error) {
+		ctx = burp.WithInstance(ctx, input.Instance)
+
+		respRaw, origin := input.Response, ""
+		audit := &headerAudit{}
+		switch {
+		case input.Index > 0 && respRaw != "":
+			return nil, AuditHeadersOutput{}, fmt.Errorf("provide response or index, not both")
+		case input.Index > 0:
+			reqRaw, resp, err := historyEntry(ctx, client, input.Index)
+			if err != nil {
+				return nil, AuditHeadersOutput{}, err
+			}
+			respRaw = resp
+			req := burp.ParseRawRequest(reqRaw)
+			origin = burp.GetHeader(req.Headers, "Origin")
+			scheme := "https"
+			if input.TLS != nil && !*input.TLS {
+				scheme = "http"
+			}
+			audit.url = scheme + "://" + req.Host + req.Path
+		case respRaw == "":
+			return nil, AuditHeadersOutput{}, fmt.Errorf("response or index is required")
+		}
+
+		resp := burp.ParseHTTPResponse(respRaw, 0, 0)
+		if resp == nil {
+			return nil, AuditHeadersOutput{}, parseFailure("failed to parse response")
+		}
+
+		csp := audit.checkCSP(resp.Headers)
+		if input.TLS == nil || *input.TLS {
+			audit.checkHSTS(resp.Headers)
+		}
+		audit.checkFraming(resp.Headers, csp)
+		audit.checkMisc(resp.Headers)
+		audit.checkCORS(resp.Headers, origin)
+
+		out := AuditHeadersOutput{URL: audit.url, CSP: csp, Findings: audit.findings}
+		if out.Findings == nil {
+			out.Findings = []burp.ScannerIssue{}
+		}
+		return nil, out, nil
+	}
+}
+
+// RegisterAuditHeadersTool registers the burp_audit_headers tool.
+func RegisterAuditHeadersTool(server *mcp.Server, client *burp.Client) {
+	addTool(server, &mcp.Tool{
+		Name: "burp_audit_headers",
+		Description: `Audit a response's security headers (raw or proxy history index): CSP parsed into directives with unsafe-inline, wildcard, and bypassable sources flagged, HSTS, framing, nosniff, Referrer-Policy, Permissions-Policy, and CORS. ` +
+			`Returns {url, csp, findings: [{name, severity, confidence, url, issueDetail}]} in the burp_get_scanner_issues format.`,
+	}, auditHeadersHandler(client))
+}
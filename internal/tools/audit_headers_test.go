@@ -0,0 +1,87 @@
+package tools
+
+import (
+	"context"
+	"slices"
+	"testing"
+)
+
+func findingNames(t *testing.T, resp string, tls *bool) ([]string, AuditHeadersOutput) {
+	t.Helper()
+	_, out, err := auditHeadersHandler(nil)(context.Background(), nil, AuditHeadersInput{Response: resp, TLS: tls})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, f := range out.Findings {
+		names = append(names, f.Name)
+	}
+	return names, out
+}
+
+func TestAuditHeaders_Weak(t *testing.T) {
+	resp := "HTTP/1.1 200 OK\r\n" +
+		"Content-Security-Policy: default-src 'self'; script-src 'self' 'unsafe-inline' https: cdnjs.cloudflare.com; SCRIPT-SRC *\r\n" +
+		"Strict-Transport-Security: max-age=3600\r\n" +
+		"X-Frame-Options: ALLOW-FROM https://a.example\r\n" +
+		"Referrer-Policy: unsafe-url\r\n" +
+		"Access-Control-Allow-Origin: null\r\n" +
+		"Access-Control-Allow-Credentials: true\r\n\r\n"
+	names, out := findingNames(t, resp, nil)
+	want := []string{
+		"CSP allows unsafe-inline scripts",
+		"CSP allows scripts from a whole scheme",
+		"CSP allowlists a host with known bypasses",
+		"CSP missing base-uri",
+		"Strict-Transport-Security max-age is short",
+		"Strict-Transport-Security without includeSubDomains",
+		"X-Frame-Options ALLOW-FROM is unsupported",
+		"X-Content-Type-Options missing",
+		"Referrer-Policy leaks full URLs",
+		"Permissions-Policy missing",
+		"CORS trusts the null origin",
+	}
+	if !slices.Equal(names, want) {
+		t.Errorf("findings =\n%q\nwant\n%q", names, want)
+	}
+	if got := out.CSP["script-src"]; !slices.Equal(got, []string{"'self'", "'unsafe-inline'", "https:", "cdnjs.cloudflare.com"}) {
+		t.Errorf("script-src = %q (the second SCRIPT-SRC must be ignored)", got)
+	}
+	if out.Findings[len(out.Findings)-1].Severity != sevHigh {
+		t.Errorf("null origin with credentials = %+v", out.Findings[len(out.Findings)-1])
+	}
+}
+
+func TestAuditHeaders_Strong(t *testing.T) {
+	resp := "HTTP/1.1 200 OK\r\n" +
+		"Content-Security-Policy: default-src 'none'; script-src 'nonce-abc' 'strict-dynamic' 'unsafe-inline' https:; base-uri 'none'; frame-ancestors 'none'\r\n" +
+		"Strict-Transport-Security: max-age=63072000; includeSubDomains; preload\r\n" +
+		"X-Content-Type-Options: nosniff\r\n" +
+		"Referrer-Policy: strict-origin-when-cross-origin\r\n" +
+		"Permissions-Policy: camera=()\r\n\r\n"
+	if names, _ := findingNames(t, resp, nil); len(names) != 0 {
+		t.Errorf("findings = %q, want none", names)
+	}
+}
+
+func TestAuditHeaders_PlainHTTP(t *testing.T) {
+	tls := false
+	names, _ := findingNames(t, "HTTP/1.1 200 OK\r\nX-Frame-Options: DENY\r\n\r\n", &tls)
+	if slices.Contains(names, "Strict-Transport-Security missing") {
+		t.Error("HSTS checked on a plain HTTP response")
+	}
+	if !slices.Contains(names, "Content-Security-Policy missing") {
+		t.Errorf("findings = %q", names)
+	}
+}
+
+func TestHeaderAudit_CORSReflection(t *testing.T) {
+	a := &headerAudit{}
+	a.checkCORS(map[string][]string{
+		"Access-Control-Allow-Origin":      {"https://partner.example"},
+		"Access-Control-Allow-Credentials": {"true"},
+	}, "https://partner.example")
+	if len(a.findings) != 1 || a.findings[0].Confidence != confTentative {
+		t.Errorf("findings = %+v", a.findings)
+	}
+}
@@ -0,0 +1,288 @@
+package tools
+
+import (
+	"fmt"
+	"maps"
+	"net/http"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/c0tton-fluff/burp-mcp-server/internal/burp"
+	"github.com/c0tton-fluff/burp-mcp-server/internal/config"
+	"github.com/c0tton-fluff/burp-mcp-server/internal/extract"
+)
+
+// profileVar matches {{env:NAME}} and {{name}} references in auth profile
+// values and login steps.
+var profileVar = regexp.MustCompile(`\{\{(env:)?([A-Za-z0-9_]+)\}\}`)
+
+// loginSender sends one login step the way the calling tool sends traffic
+// (through Burp, or direct).
+type loginSender func(rawNorm string, t resolvedTarget) (string, error)
+
+// authLogin is the result of a profile's login macro.
+type authLogin struct {
+	vars    map[string]string
+	cookies map[string]string
+	at      time.Time
+}
+
+// authLogins caches login results by profile name. The lock is held for
+// the whole login, so parallel requests (batches) log in once.
+var authLogins = struct {
+	sync.Mutex
+	m map[string]*authLogin
+}{m: make(map[string]*authLogin)}
+
+// applyAuthProfile injects the named profile's credentials into rawNorm,
+// running its login macro first if the cached result is missing or stale.
+// An empty name leaves the request alone.
+func applyAuthProfile(rawNorm, name string, send loginSender) (string, error) {
+	if name == "" {
+		return rawNorm, nil
+	}
+	p, err := settings.AuthProfile(name)
+	if err != nil {
+		return "", err
+	}
+	login, err := profileLogin(name, p, send)
+	if err != nil {
+		return "", fmt.Errorf("auth profile %s: %w", name, err)
+	}
+
+	set := make(map[string]string, len(p.Headers)+1)
+	for h, v := range p.Headers {
+		if set[h], err = expandProfileValue(v, login.vars); err != nil {
+			return "", fmt.Errorf("auth profile %s: header %s: %w", name, h, err)
+		}
+	}
+	if p.Bearer != "" {
+		token, err := expandProfileValue(p.Bearer, login.vars)
+		if err != nil {
+			return "", fmt.Errorf("auth profile %s: bearer: %w", name, err)
+		}
+		set["Authorization"] = "Bearer " + token
+	}
+	cookies := make(map[string]string, len(login.cookies)+len(p.Cookies))
+	maps.Copy(cookies, login.cookies)
+	for c, v := range p.Cookies {
+		if cookies[c], err = expandProfileValue(v, login.vars); err != nil {
+			return "", fmt.Errorf("auth profile %s: cookie %s: %w", name, c, err)
+		}
+	}
+	return mergeCookies(applyHeaderRules(rawNorm, config.HeaderRules{Set: set}), cookies), nil
+}
+
+// profileLogin returns the profile's login result, running the macro when
+// it has steps and no fresh result is cached.
+func profileLogin(name string, p config.AuthProfile, send loginSender) (*authLogin, error) {
+	if len(p.Login) == 0 {
+		return &authLogin{}, nil
+	}
+	authLogins.Lock()
+	defer authLogins.Unlock()
+	if l, ok := authLogins.m[name]; ok && (p.LoginTTLSeconds == 0 || time.Since(l.at) < time.Duration(p.LoginTTLSeconds)*time.Second) {
+		return l, nil
+	}
+	l, err := runLogin(p.Login, send)
+	if err != nil {
+		return nil, err
+	}
+	authLogins.m[name] = l
+	return l, nil
+}
+
+// runLogin sends the steps in order. Cookies set along the way ride on
+// later steps, like a browser's jar scoped to the whole macro.
+func runLogin(steps []config.LoginStep, send loginSender) (*authLogin, error) {
+	l := &authLogin{vars: map[string]string{}, cookies: map[string]string{}, at: time.Now()}
+	for i, step := range steps {
+		raw, err := expandProfileValue(step.Raw, l.vars)
+		if err != nil {
+			return nil, fmt.Errorf("login[%d]: %w", i, err)
+		}
+		rawNorm, parsed, err := prepareRequest(raw, "")
+		if err != nil {
+			return nil, fmt.Errorf("login[%d]: %w", i, err)
+		}
+		t, err := resolveTarget(step.Host, step.Port, step.TLS, parsed.Host)
+		if err != nil {
+			return nil, fmt.Errorf("login[%d]: %w", i, err)
+		}
+		text, err := send(fixContentLength(mergeCookies(rawNorm, l.cookies)), t)
+		if err != nil {
+			return nil, fmt.Errorf("login[%d]: %w", i, err)
+		}
+
+		if resp := burp.ParseHTTPResponse(text, 0, 0); resp != nil {
+			for k, values := range resp.Headers {
+				if !strings.EqualFold(k, "Set-Cookie") {
+					continue
+				}
+				for _, v := range values {
+					c, err := http.ParseSetCookie(v)
+					if err != nil {
+						continue
+					}
+					if c.MaxAge < 0 || c.Value == "" {
+						delete(l.cookies, c.Name)
+					} else {
+						l.cookies[c.Name] = c.Value
+					}
+				}
+			}
+		}
+		for name, expr := range step.Extract {
+			m := regexp.MustCompile(expr).FindStringSubmatch(text)
+			if m == nil {
+				return nil, fmt.Errorf("login[%d]: extract %s: no match", i, name)
+			}
+			l.vars[name] = m[len(m)-1]
+		}
+		if len(step.ExtractJSON) > 0 {
+			_, body, _ := strings.Cut(text, "\r\n\r\n")
+			doc, err := extract.DecodeJSON(body)
+			if err != nil {
+				return nil, fmt.Errorf("login[%d]: extractJson: response body is not JSON", i)
+			}
+			for name, path := range step.ExtractJSON {
+				v, ok := jsonPathValue(doc, path)
+				if !ok {
+					return nil, fmt.Errorf("login[%d]: extractJson %s: no value at %s", i, name, path)
+				}
+				l.vars[name] = v
+			}
+		}
+	}
+	return l, nil
+}
+
+// refreshAuthProfile checks resp against the profile's loggedOut rule.
+// On a match it drops the login used for a request sent at sentAt and
+// returns base with the profile applied again from a fresh login; ok
+// reports whether the caller should resend.
+func refreshAuthProfile(base, name, resp string, sentAt time.Time, send loginSender) (raw string, ok bool, err error) {
+	if name == "" {
+		return "", false, nil
+	}
+	p, err := settings.AuthProfile(name)
+	if err != nil || p.LoggedOut == nil || !loggedOut(*p.LoggedOut, resp) {
+		return "", false, nil
+	}
+	authLogins.Lock()
+	// A login newer than the request came from a concurrent refresh; reuse it.
+	if l, cached := authLogins.m[name]; cached && !l.at.After(sentAt) {
+		delete(authLogins.m, name)
+	}
+	authLogins.Unlock()
+
+	if raw, err = applyAuthProfile(base, name, send); err != nil {
+		return "", false, err
+	}
+	return raw, true, nil
+}
+
+// loggedOut reports whether resp matches any part of r.
+func loggedOut(r config.LoggedOutRule, resp string) bool {
+	parsed := burp.ParseHTTPResponse(resp, 0, 0)
+	if parsed == nil {
+		return false
+	}
+	if slices.Contains(r.Status, parsed.StatusCode) {
+		return true
+	}
+	if r.Location != "" && strings.Contains(burp.GetHeader(parsed.Headers, "Location"), r.Location) {
+		return true
+	}
+	return r.Regex != "" && regexp.MustCompile(r.Regex).MatchString(resp)
+}
+
+// jsonPathValue returns the first value at a JSONPath ("data.items[0].id",
+// optionally prefixed with "$.") in doc. Strings come back as is; other
+// values as JSON.
+func jsonPathValue(doc any, path string) (string, bool) {
+	vals, err := extract.JSONPath(doc, path)
+	if err != nil || len(vals) == 0 {
+		return "", false
+	}
+	return extract.String(vals[0]), true
+}
+
+// expandProfileValue fills in {{env:NAME}}, {{name}} from vars, and
+// {{session:name}} tokens. Anything unresolved is an error, so a literal
+// marker never goes out in place of a credential.
+func expandProfileValue(s string, vars map[string]string) (string, error) {
+	var missing string
+	s = profileVar.ReplaceAllStringFunc(s, func(m string) string {
+		sub := profileVar.FindStringSubmatch(m)
+		if sub[1] != "" {
+			if v, ok := os.LookupEnv(sub[2]); ok {
+				return v
+			}
+			missing = "environment variable " + sub[2]
+			return m
+		}
+		if v, ok := vars[sub[2]]; ok {
+			return v
+		}
+		missing = "variable " + sub[2]
+		return m
+	})
+	if missing != "" {
+		return "", fmt.Errorf("%s is not set", missing)
+	}
+	return expandSessions(s)
+}
+
+// mergeCookies sets cookies by name in the request's Cookie header, keeping
+// the others, or adds the header when the request has none.
+func mergeCookies(rawNorm string, cookies map[string]string) string {
+	if len(cookies) == 0 {
+		return rawNorm
+	}
+	head, body, ok := strings.Cut(rawNorm, "\r\n\r\n")
+	if !ok {
+		return rawNorm
+	}
+	lines := strings.Split(head, "\r\n")
+	names := slices.Sorted(maps.Keys(cookies))
+
+	for i, line := range lines[1:] {
+		name, value, ok := strings.Cut(line, ":")
+		if !ok || !strings.EqualFold(strings.TrimSpace(name), "Cookie") {
+			continue
+		}
+		var pairs []string
+		seen := map[string]bool{}
+		for _, pair := range strings.Split(value, ";") {
+			pair = strings.TrimSpace(pair)
+			if pair == "" {
+				continue
+			}
+			k, _, _ := strings.Cut(pair, "=")
+			if v, ok := cookies[k]; ok {
+				pair = k + "=" + v
+				seen[k] = true
+			}
+			pairs = append(pairs, pair)
+		}
+		for _, k := range names {
+			if !seen[k] {
+				pairs = append(pairs, k+"="+cookies[k])
+			}
+		}
+		lines[i+1] = name + ": " + strings.Join(pairs, "; ")
+		return strings.Join(lines, "\r\n") + "\r\n\r\n" + body
+	}
+
+	pairs := make([]string, len(names))
+	for i, k := range names {
+		pairs[i] = k + "=" + cookies[k]
+	}
+	lines = append(lines, "Cookie: "+strings.Join(pairs, "; "))
+	return strings.Join(lines, "\r\n") + "\r\n\r\n" + body
+}
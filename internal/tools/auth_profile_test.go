@@ -0,0 +1,214 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/c0tton-fluff/burp-mcp-server/internal/config"
+)
+
+func directSender(raw string, t resolvedTarget) (string, error) {
+	return sendDirect(context.Background(), t, []byte(raw), directOptions{})
+}
+
+func TestApplyAuthProfile_Static(t *testing.T) {
+	t.Setenv("TEST_API_TOKEN", "tok")
+	Configure(&config.Config{AuthProfiles: map[string]config.AuthProfile{
+		"api": {
+			Bearer:  "{{env:TEST_API_TOKEN}}",
+			Cookies: map[string]string{"sid": "abc"},
+			Headers: map[string]string{"X-Tenant": "t1"},
+		},
+		"broken": {Bearer: "{{env:TEST_UNSET_VAR}}"},
+	}})
+	t.Cleanup(func() { Configure(nil) })
+
+	raw := "GET / HTTP/1.1\r\nHost: app\r\nAuthorization: Bearer old\r\nCookie: theme=dark; sid=old\r\n\r\n"
+	got, err := applyAuthProfile(raw, "api", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"Authorization: Bearer tok\r\n", "Cookie: theme=dark; sid=abc\r\n", "X-Tenant: t1\r\n"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("request missing %q:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "Bearer old") {
+		t.Errorf("old token kept:\n%s", got)
+	}
+
+	if got, _ := applyAuthProfile(raw, "", nil); got != raw {
+		t.Error("empty profile changed the request")
+	}
+	if _, err := applyAuthProfile(raw, "missing", nil); err == nil {
+		t.Error("expected error for unknown profile")
+	}
+	if _, err := applyAuthProfile(raw, "broken", nil); err == nil || !strings.Contains(err.Error(), "TEST_UNSET_VAR") {
+		t.Errorf("err = %v", err)
+	}
+}
+
+func TestApplyAuthProfile_Login(t *testing.T) {
+	var logins atomic.Int32
+	target := startTestTarget(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/login":
+			if r.Method == http.MethodGet {
+				http.SetCookie(w, &http.Cookie{Name: "pre", Value: "1"})
+				fmt.Fprint(w, `<input name="csrf" value="c5rf">`)
+				return
+			}
+			r.ParseForm()
+			if c, _ := r.Cookie("pre"); c == nil || r.PostForm.Get("csrf") != "c5rf" || r.PostForm.Get("password") != "pw" {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			logins.Add(1)
+			http.SetCookie(w, &http.Cookie{Name: "pre", MaxAge: -1})
+			http.SetCookie(w, &http.Cookie{Name: "session", Value: "s1"})
+			fmt.Fprint(w, `{"token":"jwt123"}`)
+		}
+	})
+	host := net.JoinHostPort(target.Host, strconv.Itoa(target.Port))
+	t.Setenv("TEST_LOGIN_PASSWORD", "pw")
+	Configure(&config.Config{AuthProfiles: map[string]config.AuthProfile{
+		"app": {
+			Login: []config.LoginStep{
+				{Raw: "GET /login HTTP/1.1\r\nHost: " + host + "\r\n\r\n", Extract: map[string]string{"csrf": `name="csrf" value="([^"]+)"`}},
+				{
+					Raw:     "POST /login HTTP/1.1\r\nHost: " + host + "\r\nContent-Type: application/x-www-form-urlencoded\r\n\r\ncsrf={{csrf}}&password={{env:TEST_LOGIN_PASSWORD}}&",
+					Extract: map[string]string{"token": `"token":"([^"]+)"`},
+				},
+			},
+			Bearer: "{{token}}",
+		},
+	}})
+	t.Cleanup(func() {
+		Configure(nil)
+		clear(authLogins.m)
+	})
+
+	for range 2 {
+		got, err := applyAuthProfile("GET /api HTTP/1.1\r\nHost: app\r\n\r\n", "app", func(raw string, _ resolvedTarget) (string, error) {
+			return directSender(raw, target)
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(got, "Authorization: Bearer jwt123\r\n") || !strings.Contains(got, "Cookie: session=s1\r\n") {
+			t.Errorf("request:\n%s", got)
+		}
+	}
+	if n := logins.Load(); n != 1 {
+		t.Errorf("logged in %d times, want 1 (cached)", n)
+	}
+}
+
+func TestMergeCookies(t *testing.T) {
+	tests := []struct{ raw, want string }{
+		{"GET / HTTP/1.1\r\nHost: a\r\n\r\n", "Cookie: a=1; b=2\r\n"},
+		{"GET / HTTP/1.1\r\ncookie: b=old; c=3\r\n\r\n", "cookie: b=2; c=3; a=1\r\n"},
+		{"GET / HTTP/1.1\r\nCookie: \r\n\r\n", "Cookie: a=1; b=2\r\n"},
+	}
+	for _, tt := range tests {
+		if got := mergeCookies(tt.raw, map[string]string{"a": "1", "b": "2"}); !strings.Contains(got, tt.want) {
+			t.Errorf("mergeCookies(%q) = %q, want %q", tt.raw, got, tt.want)
+		}
+	}
+}
+
+func TestJSONPathValue(t *testing.T) {
+	var doc any
+	json.Unmarshal([]byte(`{"data":{"token":"abc","items":[{"id":7},{"id":8}],"ok":true}}`), &doc)
+	tests := map[string]string{
+		"data.token":       "abc",
+		"$.data.token":     "abc",
+		"data.items[1].id": "8",
+		"data.ok":          "true",
+		"data.items[0]":    `{"id":7}`,
+	}
+	for path, want := range tests {
+		if got, ok := jsonPathValue(doc, path); !ok || got != want {
+			t.Errorf("jsonPathValue(%q) = %q, %v; want %q", path, got, ok, want)
+		}
+	}
+	for _, path := range []string{"data.missing", "data.items[5].id", "data.token.x", "data.items[x]"} {
+		if got, ok := jsonPathValue(doc, path); ok {
+			t.Errorf("jsonPathValue(%q) = %q, want no value", path, got)
+		}
+	}
+}
+
+func TestLoggedOut(t *testing.T) {
+	rule := config.LoggedOutRule{Status: []int{401}, Location: "/login", Regex: `(?i)session expired`}
+	tests := map[string]bool{
+		"HTTP/1.1 401 Unauthorized\r\n\r\n":                         true,
+		"HTTP/1.1 302 Found\r\nLocation: /login?next=/a\r\n\r\n":    true,
+		"HTTP/1.1 200 OK\r\n\r\n<p>Your session expired</p>":        true,
+		"HTTP/1.1 302 Found\r\nLocation: /dashboard\r\n\r\n":        false,
+		"HTTP/1.1 200 OK\r\nContent-Type: text/html\r\n\r\nwelcome": false,
+	}
+	for resp, want := range tests {
+		if got := loggedOut(rule, resp); got != want {
+			t.Errorf("loggedOut(%q) = %v, want %v", resp, got, want)
+		}
+	}
+}
+
+func TestRefreshAuthProfile(t *testing.T) {
+	// Each login issues a new token; only the newest is accepted.
+	var current atomic.Int32
+	target := startTestTarget(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/login" {
+			fmt.Fprintf(w, `{"data":{"token":"t%d"}}`, current.Add(1))
+			return
+		}
+		if r.Header.Get("Authorization") != fmt.Sprintf("Bearer t%d", current.Load()) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		fmt.Fprint(w, "ok")
+	})
+	host := net.JoinHostPort(target.Host, strconv.Itoa(target.Port))
+	Configure(&config.Config{AuthProfiles: map[string]config.AuthProfile{
+		"api": {
+			Login:     []config.LoginStep{{Raw: "POST /login HTTP/1.1\r\nHost: " + host + "\r\n\r\n", ExtractJSON: map[string]string{"token": "data.token"}}},
+			Bearer:    "{{token}}",
+			LoggedOut: &config.LoggedOutRule{Status: []int{401}},
+		},
+	}})
+	t.Cleanup(func() {
+		Configure(nil)
+		clear(authLogins.m)
+	})
+	send := func(raw string, _ resolvedTarget) (string, error) { return directSender(raw, target) }
+
+	base := "GET /data HTTP/1.1\r\nHost: " + host + "\r\n\r\n"
+	raw, err := applyAuthProfile(base, "api", send)
+	if err != nil {
+		t.Fatal(err)
+	}
+	current.Add(1) // the server rotates the token: the session is gone
+	sentAt := time.Now()
+	resp, _ := send(raw, target)
+	refreshed, ok, err := refreshAuthProfile(base, "api", resp, sentAt, send)
+	if err != nil || !ok {
+		t.Fatalf("refresh = %v, %v", ok, err)
+	}
+	if resp, _ := send(refreshed, target); !strings.HasPrefix(resp, "HTTP/1.1 200") {
+		t.Errorf("resent request got %q", resp)
+	}
+
+	// A response that doesn't look logged out needs no refresh.
+	if _, ok, _ := refreshAuthProfile(base, "api", "HTTP/1.1 200 OK\r\n\r\n", time.Now(), send); ok {
+		t.Error("refreshed on a 200")
+	}
+}
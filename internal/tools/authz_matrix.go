@@ -0,0 +1,332 @@
+package tools
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/c0tton-fluff/burp-mcp-server/internal/burp"
+	"github.com/c0tton-fluff/burp-mcp-server/internal/config"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const (
+	maxAuthzRequests          = 20
+	maxAuthzIdentities        = 6
+	defaultAuthzLengthPercent = 10
+)
+
+// Cell verdicts, from the point of view of the first (reference) identity.
+const (
+	authzReference   = "reference"
+	authzBypass      = "bypass"       // 2xx with the reference's content
+	authzDiffers     = "differs"      // 2xx with other content: review
+	authzDenied      = "denied"       // 3xx or 4xx
+	authzServerError = "server-error" // 5xx
+	authzUnexpected  = "unexpected"   // 2xx where the reference was refused
+	authzError       = "error"        // the request failed
+)
+
+// authzCredentialHeaders are removed before an identity's credentials go on.
+var authzCredentialHeaders = []string{"Authorization", "Cookie"}
+
+// AuthzRequest is one request to replay, from proxy history or raw.
+type AuthzRequest struct {
+	Index int    `json:"index,omitempty" jsonschema:"Proxy history index (1-based); use this or raw"`
+	Raw   string `json:"raw,omitempty" jsonschema:"Raw HTTP request"`
+	Host  string `json:"host,omitempty" jsonschema:"Target host (overrides Host header)"`
+	Port  int    `json:"port,omitempty" jsonschema:"Target port (default based on TLS)"`
+	TLS   *bool  `json:"tls,omitempty" jsonschema:"Use HTTPS (default true)"`
+	Tag   string `json:"tag,omitempty" jsonschema:"Label for the row (default: method and path)"`
+}
+
+// AuthzIdentity is one column of the matrix.
+type AuthzIdentity struct {
+	Name        string `json:"name" jsonschema:"required,Column label, e.g. admin, user, anonymous"`
+	AuthProfile string `json:"authProfile,omitempty" jsonschema:"Auth profile from config supplying this identity's credentials"`
+	Anonymous   bool   `json:"anonymous,omitempty" jsonschema:"Send without credentials"`
+}
+
+// AuthzMatrixInput is the input for burp_authz_matrix.
+type AuthzMatrixInput struct {
+	Requests      []AuthzRequest  `json:"requests" jsonschema:"required,Requests to replay (max 20)"`
+	Identities    []AuthzIdentity `json:"identities" jsonschema:"required,Identities from most to least privileged (2-6); the first is the reference. One without authProfile or anonymous sends the request as captured"`
+	StripHeaders  []string        `json:"stripHeaders,omitempty" jsonschema:"More credential headers to remove for profile and anonymous identities, e.g. X-Api-Key (Authorization and Cookie always are)"`
+	LengthPercent int             `json:"lengthPercent,omitempty" jsonschema:"Body length difference from the reference still counted as the same content (default 10)"`
+	HeaderProfile string          `json:"headerProfile,omitempty" jsonschema:"Header rule profile from config (default: 'default')"`
+	Instance      string          `json:"instance,omitempty" jsonschema:"Named Burp instance from config (default: the --burp-url connection)"`
+}
+
+// AuthzCell is one request sent as one identity.
+type AuthzCell struct {
+	Identity   string `json:"identity"`
+	StatusCode int    `json:"statusCode,omitempty"`
+	Length     int    `json:"length"`
+	Verdict    string `json:"verdict"`
+	Identical  bool   `json:"identical,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+// AuthzRow is one request across all identities.
+type AuthzRow struct {
+	Request string      `json:"request"`
+	Cells   []AuthzCell `json:"cells"`
+}
+
+// AuthzMatrixOutput is the output of burp_authz_matrix.
+type AuthzMatrixOutput struct {
+	Identities []string   `json:"identities"`
+	Rows       []AuthzRow `json:"rows"`
+	Matrix     string     `json:"matrix"`
+	Findings   []string   `json:"findings,omitempty"`
+	Summary    string     `json:"summary"`
+}
+
+// authzResult is what a cell's response looked like.
+type authzResult struct {
+	status, length int
+	hash           [32]byte
+	err            error
+}
+
+func authzMatrixHandler(client *burp.Client) func(context.Context, *mcp.CallToolRequest, AuthzMatrixInput) (*mcp.CallToolResult, AuthzMatrixOutput, error) {
+	return func(ctx context.Context, _ *mcp.CallToolRequest, input AuthzMatrixInput) (*mcp.CallToolResult, AuthzMatrixOutput, error) {
+		ctx = burp.WithInstance(ctx, input.Instance)
+
+		if len(input.Requests) == 0 || len(input.Requests) > maxAuthzRequests {
+			return nil, AuthzMatrixOutput{}, fmt.Errorf("requests: want 1 to %d", maxAuthzRequests)
+		}
+		if len(input.Identities) < 2 || len(input.Identities) > maxAuthzIdentities {
+			return nil, AuthzMatrixOutput{}, fmt.Errorf("identities: want 2 to %d", maxAuthzIdentities)
+		}
+		names := make([]string, len(input.Identities))
+		for i, id := range input.Identities {
+			if id.Name == "" {
+				return nil, AuthzMatrixOutput{}, fmt.Errorf("identities[%d]: name is required", i)
+			}
+			if id.AuthProfile != "" {
+				if id.Anonymous {
+					return nil, AuthzMatrixOutput{}, fmt.Errorf("identity %s: authProfile and anonymous are exclusive", id.Name)
+				}
+				if _, err := settings.AuthProfile(id.AuthProfile); err != nil {
+					return nil, AuthzMatrixOutput{}, fmt.Errorf("identity %s: %w", id.Name, err)
+				}
+			}
+			names[i] = id.Name
+		}
+		percent := input.LengthPercent
+		if percent <= 0 {
+			percent = defaultAuthzLengthPercent
+		}
+		strip := config.HeaderRules{Strip: append(append([]string{}, authzCredentialHeaders...), input.StripHeaders...)}
+
+		// Resolve every request first so a bad one fails before any traffic.
+		type prepared struct {
+			label, rawNorm string
+			t              resolvedTarget
+		}
+		reqs := make([]prepared, len(input.Requests))
+		for i, r := range input.Requests {
+			raw := r.Raw
+			if r.Index > 0 {
+				if raw != "" {
+					return nil, AuthzMatrixOutput{}, fmt.Errorf("requests[%d]: use index or raw, not both", i)
+				}
+				var err error
+				if raw, _, err = historyEntry(ctx, client, r.Index); err != nil {
+					return nil, AuthzMatrixOutput{}, fmt.Errorf("requests[%d]: %w", i, err)
+				}
+			}
+			rawNorm, parsed, err := prepareRequest(raw, input.HeaderProfile)
+			if err != nil {
+				return nil, AuthzMatrixOutput{}, fmt.Errorf("requests[%d]: %w", i, err)
+			}
+			t, err := resolveTarget(r.Host, r.Port, r.TLS, parsed.Host)
+			if err != nil {
+				return nil, AuthzMatrixOutput{}, fmt.Errorf("requests[%d]: %w", i, err)
+			}
+			label := r.Tag
+			if label == "" {
+				label = parsed.Method + " " + parsed.Path
+			}
+			reqs[i] = prepared{label: label, rawNorm: rawNorm, t: t}
+		}
+
+		// Each identity's requests are attributed to its auth profile for coverage.
+		sendAs := func(profile string) loginSender {
+			ctx := withAuthProfile(ctx, profile)
+			return func(raw string, next resolvedTarget) (string, error) {
+				return sendWithFallback(ctx, client, raw, burp.ParseRawRequest(raw), next)
+			}
+		}
+		nIDs := len(input.Identities)
+		results := make([]authzResult, len(reqs)*nIDs)
+		parallel(len(results), func(k int) {
+			r, id := reqs[k/nIDs], input.Identities[k%nIDs]
+			results[k] = authzSend(r.rawNorm, r.t, id, strip, sendAs(id.AuthProfile))
+		})
+
+		out := AuthzMatrixOutput{Identities: names, Rows: make([]AuthzRow, len(reqs))}
+		for i, r := range reqs {
+			row := AuthzRow{Request: r.label, Cells: make([]AuthzCell, nIDs)}
+			ref := results[i*nIDs]
+			for j := range nIDs {
+				res := results[i*nIDs+j]
+				cell := AuthzCell{Identity: names[j], StatusCode: res.status, Length: res.length}
+				if res.err != nil {
+					cell.Error = res.err.Error()
+				}
+				if j == 0 {
+					cell.Verdict = authzReference
+					if res.err != nil {
+						cell.Verdict = authzError
+					}
+				} else {
+					cell.Verdict, cell.Identical = authzVerdict(ref, res, percent)
+				}
+				row.Cells[j] = cell
+			}
+			out.Findings = append(out.Findings, authzRowFindings(row)...)
+			out.Rows[i] = row
+		}
+		out.Matrix = authzTable(out)
+		out.Summary = fmt.Sprintf("%d requests x %d identities, %d findings", len(reqs), nIDs, len(out.Findings))
+		return nil, out, nil
+	}
+}
+
+// authzSend sends rawNorm as id. Profile and anonymous identities lose the
+// captured credentials first.
+func authzSend(rawNorm string, t resolvedTarget, id AuthzIdentity, strip config.HeaderRules, send loginSender) authzResult {
+	base := rawNorm
+	if id.Anonymous || id.AuthProfile != "" {
+		base = applyHeaderRules(rawNorm, strip)
+	}
+	raw, err := applyAuthProfile(base, id.AuthProfile, send)
+	if err != nil {
+		return authzResult{err: err}
+	}
+	sentAt := time.Now()
+	text, err := send(raw, t)
+	if err == nil {
+		var refreshed string
+		var again bool
+		if refreshed, again, err = refreshAuthProfile(base, id.AuthProfile, text, sentAt, send); err == nil && again {
+			text, err = send(refreshed, t)
+		}
+	}
+	if err != nil {
+		return authzResult{err: err}
+	}
+	resp := burp.ParseHTTPResponse(text, 0, 0)
+	if resp == nil {
+		return authzResult{err: parseFailure("failed to parse response")}
+	}
+	return authzResult{status: resp.StatusCode, length: resp.BodySize, hash: sha256.Sum256([]byte(resp.Body))}
+}
+
+// authzVerdict classifies a lower-privileged cell against the reference.
+func authzVerdict(ref, res authzResult, percent int) (string, bool) {
+	if res.err != nil {
+		return authzError, false
+	}
+	ok := res.status >= 200 && res.status < 300
+	diff := res.length - ref.length
+	if diff < 0 {
+		diff = -diff
+	}
+	refOK := ref.err == nil && ref.status >= 200 && ref.status < 300
+	switch {
+	case ok && !refOK:
+		return authzUnexpected, false
+	case ok && res.hash == ref.hash:
+		return authzBypass, true
+	case ok && diff*100 <= percent*max(ref.length, 1):
+		return authzBypass, false
+	case ok:
+		return authzDiffers, false
+	case res.status >= 500:
+		return authzServerError, false
+	default:
+		return authzDenied, false
+	}
+}
+
+// authzRowFindings describes the flagged cells of a row. When every
+// identity, anonymous included, gets the reference's response, the
+// endpoint is probably meant to be public and that is said instead.
+func authzRowFindings(row AuthzRow) []string {
+	ref := row.Cells[0]
+	var findings []string
+	allBypass := true
+	for _, c := range row.Cells[1:] {
+		switch c.Verdict {
+		case authzBypass:
+			how := "a similar response"
+			if c.Identical {
+				how = "an identical response"
+			}
+			findings = append(findings, fmt.Sprintf("%s: %s got %d (%d bytes), %s to %s's %d (%d bytes)", row.Request, c.Identity, c.StatusCode, c.Length, how, ref.Identity, ref.StatusCode, ref.Length))
+		case authzUnexpected:
+			allBypass = false
+			findings = append(findings, fmt.Sprintf("%s: %s got %d (%d bytes) where %s got %d", row.Request, c.Identity, c.StatusCode, c.Length, ref.Identity, ref.StatusCode))
+		default:
+			allBypass = false
+		}
+	}
+	if allBypass && len(row.Cells) > 1 {
+		return []string{fmt.Sprintf("%s: every identity got %s's response; if the endpoint isn't public, it has no access control", row.Request, ref.Identity)}
+	}
+	return findings
+}
+
+// authzTable renders the matrix as aligned text: status/length per cell,
+// "!" marking flagged cells.
+func authzTable(out AuthzMatrixOutput) string {
+	header := append([]string{"request"}, out.Identities...)
+	rows := [][]string{header}
+	for _, r := range out.Rows {
+		line := []string{r.Request}
+		for _, c := range r.Cells {
+			cell := fmt.Sprintf("%d/%d", c.StatusCode, c.Length)
+			switch c.Verdict {
+			case authzError:
+				cell = "error"
+			case authzBypass, authzUnexpected:
+				cell += " !"
+			}
+			line = append(line, cell)
+		}
+		rows = append(rows, line)
+	}
+	widths := make([]int, len(header))
+	for _, r := range rows {
+		for i, c := range r {
+			widths[i] = max(widths[i], len(c))
+		}
+	}
+	var b strings.Builder
+	for _, r := range rows {
+		for i, c := range r {
+			if i > 0 {
+				b.WriteString("  ")
+			}
+			b.WriteString(c + strings.Repeat(" ", widths[i]-len(c)))
+		}
+		b.WriteString("\n")
+	}
+	return strings.TrimRight(b.String(), " \n")
+}
+
+// RegisterAuthzMatrixTool registers the burp_authz_matrix tool.
+func RegisterAuthzMatrixTool(server *mcp.Server, client *burp.Client) {
+	addTool(server, &mcp.Tool{
+		Name: "burp_authz_matrix",
+		Description: `Replay requests (proxy history indexes or raw) as several identities, from most to least privileged, and compare each cell with the first identity's response. ` +
+			`Identities use config auth profiles, send anonymously, or keep the captured credentials; Authorization and Cookie (plus stripHeaders) are removed before a profile applies. ` +
+			`A lower identity getting 2xx with the same content (hash, or length within lengthPercent) is a bypass; 2xx where the reference was refused is unexpected. ` +
+			`Returns {identities, rows: [{request, cells: [{identity, statusCode, length, verdict, identical, error}]}], matrix, findings, summary}.`,
+	}, authzMatrixHandler(client))
+}
@@ -0,0 +1,97 @@
+package tools
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/c0tton-fluff/burp-mcp-server/internal/config"
+)
+
+func TestAuthzVerdict(t *testing.T) {
+	body := func(s string) authzResult {
+		return authzResult{status: 200, length: len(s), hash: sha256.Sum256([]byte(s))}
+	}
+	ref := body("<h1>all users</h1>")
+	tests := []struct {
+		name      string
+		ref, res  authzResult
+		want      string
+		identical bool
+	}{
+		{"identical", ref, body("<h1>all users</h1>"), authzBypass, true},
+		{"similar length", ref, body("<h1>all usersX</h1>"), authzBypass, false},
+		{"other content", ref, body("<h1>welcome back, user</h1>"), authzDiffers, false},
+		{"forbidden", ref, authzResult{status: 403}, authzDenied, false},
+		{"redirect", ref, authzResult{status: 302}, authzDenied, false},
+		{"server error", ref, authzResult{status: 500}, authzServerError, false},
+		{"failed", ref, authzResult{err: fmt.Errorf("timeout")}, authzError, false},
+		{"reference refused", authzResult{status: 403}, body("x"), authzUnexpected, false},
+		{"both refused", authzResult{status: 403}, authzResult{status: 403}, authzDenied, false},
+	}
+	for _, tt := range tests {
+		got, identical := authzVerdict(tt.ref, tt.res, defaultAuthzLengthPercent)
+		if got != tt.want || identical != tt.identical {
+			t.Errorf("%s: verdict = %s, %v; want %s, %v", tt.name, got, identical, tt.want, tt.identical)
+		}
+	}
+}
+
+func TestAuthzRowFindings(t *testing.T) {
+	row := AuthzRow{Request: "GET /admin", Cells: []AuthzCell{
+		{Identity: "admin", StatusCode: 200, Length: 100, Verdict: authzReference},
+		{Identity: "user", StatusCode: 200, Length: 100, Verdict: authzBypass, Identical: true},
+		{Identity: "anonymous", StatusCode: 401, Verdict: authzDenied},
+	}}
+	findings := authzRowFindings(row)
+	if len(findings) != 1 || !strings.Contains(findings[0], "user got 200 (100 bytes), an identical response") {
+		t.Errorf("findings = %q", findings)
+	}
+
+	row.Cells[2] = AuthzCell{Identity: "anonymous", StatusCode: 200, Length: 101, Verdict: authzBypass}
+	findings = authzRowFindings(row)
+	if len(findings) != 1 || !strings.Contains(findings[0], "every identity") {
+		t.Errorf("public endpoint findings = %q", findings)
+	}
+
+	table := authzTable(AuthzMatrixOutput{Identities: []string{"admin", "user", "anonymous"}, Rows: []AuthzRow{row}})
+	want := "request     admin    user       anonymous\nGET /admin  200/100  200/100 !  200/101 !"
+	if table != want {
+		t.Errorf("table =\n%s\nwant\n%s", table, want)
+	}
+}
+
+func TestAuthzSend(t *testing.T) {
+	target := startTestTarget(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") == "" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		fmt.Fprintf(w, "hello %s, key %q", r.Header.Get("Authorization"), r.Header.Get("X-Api-Key"))
+	})
+	Configure(&config.Config{AuthProfiles: map[string]config.AuthProfile{"user": {Bearer: "usertoken"}}})
+	t.Cleanup(func() { Configure(nil) })
+
+	raw := "GET /users HTTP/1.1\r\nHost: app\r\nAuthorization: Bearer admintoken\r\nX-Api-Key: k1\r\n\r\n"
+	strip := config.HeaderRules{Strip: append(append([]string{}, authzCredentialHeaders...), "X-Api-Key")}
+
+	captured := authzSend(raw, target, AuthzIdentity{Name: "admin"}, strip, directSender)
+	user := authzSend(raw, target, AuthzIdentity{Name: "user", AuthProfile: "user"}, strip, directSender)
+	anon := authzSend(raw, target, AuthzIdentity{Name: "anonymous", Anonymous: true}, strip, directSender)
+	for _, r := range []authzResult{captured, user, anon} {
+		if r.err != nil {
+			t.Fatal(r.err)
+		}
+	}
+	if captured.status != 200 || captured.hash != sha256.Sum256([]byte(`hello Bearer admintoken, key "k1"`)) {
+		t.Errorf("captured = %+v", captured)
+	}
+	if user.status != 200 || user.hash != sha256.Sum256([]byte(`hello Bearer usertoken, key ""`)) {
+		t.Errorf("user = %+v", user)
+	}
+	if anon.status != 401 {
+		t.Errorf("anonymous status = %d, want 401", anon.status)
+	}
+}
@@ -1,10 +1,13 @@
 package tools
 
 import (
+	"cmp"
 	"context"
 	"fmt"
 	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/c0tton-fluff/burp-mcp-server/internal/burp"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
@@ -24,8 +27,17 @@ type BatchRequest struct {
 // BatchSendInput is the input for burp_batch_send.
 type BatchSendInput struct {
 	Requests   []BatchRequest `json:"requests" jsonschema:"required,Array of requests to send in parallel"`
-	BodyLimit  int            `json:"bodyLimit,omitempty" jsonschema:"Response body limit per response (default 10000)"`
+	BodyLimit  int            `json:"bodyLimit,omitempty" jsonschema:"Response body limit per response (default 10000 or config bodyLimits, -1 = unlimited)"`
 	AllHeaders bool           `json:"allHeaders,omitempty" jsonschema:"Return all headers (default: security-relevant only)"`
+
+	BodyEncoding string `json:"bodyEncoding,omitempty" jsonschema:"Response body encoding: auto (default: utf8 for text, base64 for binary), utf8, base64, or hex"`
+
+	Relevance *RelevanceOptions `json:"relevance,omitempty" jsonschema:"Keep the relevant parts of over-limit bodies (focus strings, error messages, matching JSON fields) instead of their first bodyLimit bytes"`
+	DryRun    bool              `json:"dryRun,omitempty" jsonschema:"Return the exact requests that would be sent, without sending them"`
+
+	HeaderProfile string `json:"headerProfile,omitempty" jsonschema:"Header rule profile from config (default: 'default')"`
+	AuthProfile   string `json:"authProfile,omitempty" jsonschema:"Auth profile from config whose credentials are injected at send time"`
+	Instance      string `json:"instance,omitempty" jsonschema:"Named Burp instance from config (default: the --burp-url connection)"`
 }
 
 // BatchResponseEntry is one response in the batch output.
@@ -33,10 +45,17 @@ type BatchResponseEntry struct {
 	Tag        string         `json:"tag,omitempty"`
 	StatusCode int            `json:"statusCode"`
 	Headers    map[string]any `json:"headers,omitempty"`
+	HeaderList []burp.Header  `json:"headerList,omitempty"`
 	Body       string         `json:"body,omitempty"`
-	BodySize   int            `json:"bodySize"`
-	Truncated  bool           `json:"truncated,omitempty"`
-	Error      string         `json:"error,omitempty"`
+	BodyEnvelope
+	Retries int `json:"retries,omitempty"`
+	// QueueWaitMs is the time spent waiting for a per-host concurrency slot.
+	QueueWaitMs int64  `json:"queueWaitMs,omitempty"`
+	Error       string `json:"error,omitempty"`
+	// Reauthenticated is set when the request was resent after a fresh login.
+	Reauthenticated bool `json:"reauthenticated,omitempty"`
+
+	Preview *RequestPreview `json:"preview,omitempty"`
 }
 
 // BatchSendOutput is the output of burp_batch_send.
@@ -47,28 +66,30 @@ type BatchSendOutput struct {
 
 func batchSendHandler(client *burp.Client) func(context.Context, *mcp.CallToolRequest, BatchSendInput) (*mcp.CallToolResult, BatchSendOutput, error) {
 	return func(ctx context.Context, _ *mcp.CallToolRequest, input BatchSendInput) (*mcp.CallToolResult, BatchSendOutput, error) {
+		ctx = burp.WithInstance(ctx, input.Instance)
+
 		if len(input.Requests) == 0 {
 			return nil, BatchSendOutput{}, fmt.Errorf("requests array is required")
 		}
 		if len(input.Requests) > maxBatchSize {
 			return nil, BatchSendOutput{}, fmt.Errorf("max %d requests per batch", maxBatchSize)
 		}
-
-		bodyLimit := input.BodyLimit
-		if bodyLimit == 0 {
-			bodyLimit = defaultBodyLimit
+		if err := checkBodyEncoding(input.BodyEncoding); err != nil {
+			return nil, BatchSendOutput{}, err
 		}
 
 		responses := make([]BatchResponseEntry, len(input.Requests))
 		var wg sync.WaitGroup
+		var done atomic.Int64
 
 		for i, req := range input.Requests {
 			wg.Add(1)
 			go func(idx int, r BatchRequest) {
 				defer wg.Done()
 				responses[idx] = executeSingleRequest(
-					ctx, client, r, bodyLimit, input.AllHeaders,
+					ctx, client, r, input.BodyLimit, input.Relevance, input.BodyEncoding, input.AllHeaders, input.HeaderProfile, input.AuthProfile, dryRun(input.DryRun),
 				)
+				reportProgress(ctx, int(done.Add(1)), len(input.Requests), cmp.Or(r.Tag, fmt.Sprintf("request %d", idx+1)))
 			}(i, req)
 		}
 		wg.Wait()
@@ -93,6 +114,9 @@ func batchSendHandler(client *burp.Client) func(context.Context, *mcp.CallToolRe
 			"%d requests, responses: %s",
 			len(input.Requests), strings.Join(parts, ", "),
 		)
+		if dryRun(input.DryRun) {
+			summary = fmt.Sprintf("dry run: %d requests previewed, none sent", len(input.Requests)-errCount)
+		}
 
 		return nil, BatchSendOutput{
 			Responses: responses,
@@ -106,53 +130,101 @@ func executeSingleRequest(
 	client *burp.Client,
 	req BatchRequest,
 	bodyLimit int,
+	relevance *RelevanceOptions,
+	encoding string,
 	allHeaders bool,
+	headerProfile string,
+	authProfile string,
+	preview bool,
 ) BatchResponseEntry {
 	entry := BatchResponseEntry{Tag: req.Tag}
 
-	if err := validateRawRequest(req.Raw); err != nil {
+	rawNorm, parsed, err := prepareRequest(req.Raw, headerProfile)
+	if err != nil {
 		entry.Error = err.Error()
 		return entry
 	}
 
-	parsed := burp.ParseRawRequest(req.Raw)
-
 	t, err := resolveTarget(req.Host, req.Port, req.TLS, parsed.Host)
 	if err != nil {
 		entry.Error = err.Error()
 		return entry
 	}
 
-	rawNorm := normalizeRawRequest(req.Raw)
+	loginSend := func(raw string, next resolvedTarget) (string, error) {
+		return sendWithFallback(ctx, client, raw, burp.ParseRawRequest(raw), next)
+	}
+	if preview {
+		if entry.Preview, err = previewRequest(ctx, t, rawNorm, nil); err == nil {
+			err = previewAuthProfile(entry.Preview, authProfile)
+		}
+		if err != nil {
+			entry.Preview, entry.Error = nil, err.Error()
+		}
+		return entry
+	}
+
+	base := rawNorm
+	rawNorm, err = applyAuthProfile(base, authProfile, loginSend)
+	if err != nil {
+		entry.Error = err.Error()
+		return entry
+	}
+	parsed = burp.ParseRawRequest(rawNorm)
+
+	ctx, retries := burp.WithRetryCounter(ctx)
+	ctx, queueWait := withQueueWait(ctx)
+	sentAt := time.Now()
 	responseText, err := sendWithFallback(ctx, client, rawNorm, parsed, t)
+	if err == nil {
+		var refreshed string
+		if refreshed, entry.Reauthenticated, err = refreshAuthProfile(base, authProfile, responseText, sentAt, loginSend); err == nil && entry.Reauthenticated {
+			responseText, err = sendWithFallback(ctx, client, refreshed, burp.ParseRawRequest(refreshed), t)
+		}
+	}
+	entry.Retries = retries()
+	entry.QueueWaitMs = queueWait().Milliseconds()
 	if err != nil {
 		entry.Error = err.Error()
 		return entry
 	}
 
-	resp := burp.ParseHTTPResponse(responseText, 0, bodyLimit)
+	resp := parseRelevantResponse(ctx, responseText, 0, bodyLimit, defaultBodyLimit, relevance)
 	if resp == nil {
 		entry.Error = "failed to parse response"
 		return entry
 	}
+	cs := bodyCharset(responseText)
+	alignWindow(cs, resp)
 
 	entry.StatusCode = resp.StatusCode
-	entry.Body = resp.Body
-	entry.BodySize = resp.BodySize
-	entry.Truncated = resp.Truncated
+	entry.BodyEnvelope = bodyEnvelope(resp, 0, "resend it with burp_send_request", relevance)
+	entry.Body = encodeBody(&entry.BodyEnvelope, resp.Body, cs, 0, encoding, false)
 
-	headers := resp.Headers
-	if !allHeaders {
-		headers = burp.FilterHeaders(headers)
-	}
-	entry.Headers = burp.FlattenHeaders(headers)
+	entry.Headers, entry.HeaderList = outputHeaders(resp, allHeaders)
 
 	return entry
 }
 
+// parallel calls fn for 0..n-1, at most maxBatchSize at a time, and waits.
+func parallel(n int, fn func(i int)) {
+	sem := make(chan struct{}, maxBatchSize)
+	var wg sync.WaitGroup
+	for i := range n {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			fn(i)
+		}()
+	}
+	wg.Wait()
+}
+
 // RegisterBatchSendTool registers the burp_batch_send tool.
 func RegisterBatchSendTool(server *mcp.Server, client *burp.Client) {
-	mcp.AddTool(server, &mcp.Tool{
+	addTool(server, &mcp.Tool{
 		Name: "burp_batch_send",
 		Description: `Send multiple HTTP requests in parallel. Max 10. ` +
 			`Input: {requests: [{raw, host, port, tls, tag}], bodyLimit, allHeaders}. ` +
@@ -0,0 +1,116 @@
+package tools
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/c0tton-fluff/burp-mcp-server/internal/burp"
+	"github.com/c0tton-fluff/burp-mcp-server/internal/charset"
+)
+
+// Body encodings accepted by bodyEncoding and reported in outputs.
+const (
+	encodingAuto   = "auto"
+	encodingUTF8   = "utf8"
+	encodingBase64 = "base64"
+	encodingHex    = "hex"
+)
+
+// hexdumpPreviewBytes is how much of a body the hexdump preview covers.
+const hexdumpPreviewBytes = 256
+
+// checkBodyEncoding rejects unknown bodyEncoding values before anything is sent.
+func checkBodyEncoding(encoding string) error {
+	switch encoding {
+	case "", encodingAuto, encodingUTF8, encodingBase64, encodingHex:
+		return nil
+	}
+	return fmt.Errorf("bodyEncoding must be auto, utf8, base64, or hex, got %q", encoding)
+}
+
+// encodeBody returns body, which starts at bodyOffset of the response, in the
+// requested encoding and records the encoding in env. Auto keeps text as
+// UTF-8 and base64-encodes binary bodies, whose file type is identified from
+// their magic bytes when the body starts at offset 0. For auto and utf8, text
+// in another charset cs (see bodyCharset) is transcoded to UTF-8 and the
+// charset recorded.
+func encodeBody(env *BodyEnvelope, body, cs string, bodyOffset int, encoding string, hexdump bool) string {
+	if hexdump && body != "" {
+		env.Hexdump = burp.Hexdump([]byte(body[:min(len(body), hexdumpPreviewBytes)]), bodyOffset)
+	}
+	if body == "" {
+		return ""
+	}
+	if encoding == "" || encoding == encodingAuto || encoding == encodingUTF8 {
+		body = transcodeBody(env, body, cs)
+	}
+	binary := burp.IsBinary(body)
+	if binary && bodyOffset == 0 {
+		env.FileType = burp.SniffFileType(body)
+	}
+	if encoding == "" || encoding == encodingAuto {
+		encoding = encodingUTF8
+		if binary {
+			encoding = encodingBase64
+		}
+	}
+	env.BodyEncoding = encoding
+	switch encoding {
+	case encodingBase64:
+		return base64.StdEncoding.EncodeToString([]byte(body))
+	case encodingHex:
+		return hex.EncodeToString([]byte(body))
+	}
+	return body
+}
+
+// transcodeBody decodes body from charset cs to UTF-8, recording the
+// charset in env. Bodies in UTF-8 or no declared charset are left alone, as
+// are bodies labeled ISO-8859-1 or windows-1252 that are valid UTF-8 with
+// non-ASCII text: the label is wrong, and decoding would garble them.
+func transcodeBody(env *BodyEnvelope, body, cs string) string {
+	if cs == "" || cs == charset.UTF8 {
+		return body
+	}
+	singleByte := cs == charset.ISO88591 || cs == charset.Windows1252
+	if singleByte && utf8.ValidString(body) && strings.IndexFunc(body, func(r rune) bool { return r >= utf8.RuneSelf }) >= 0 {
+		return body
+	}
+	text, ok := charset.Decode(cs, body)
+	if !ok {
+		return body
+	}
+	env.Charset = cs
+	return text
+}
+
+// bodyCharset returns the charset of raw's body. BOMs, meta tags, and XML
+// declarations sit at the start of the body, so it is detected there rather
+// than on a window cut from bodyOffset, which would have lost them.
+func bodyCharset(raw string) string {
+	resp := burp.ParseHTTPResponse(raw, 0, charset.PrescanBytes)
+	if resp == nil {
+		return ""
+	}
+	return charset.Detect(burp.GetHeader(resp.Headers, "Content-Type"), resp.Body)
+}
+
+// alignOffset moves bodyOffset back to the start of a UTF-16 code unit, so
+// a window of a UTF-16 body doesn't start halfway through one.
+func alignOffset(cs string, bodyOffset int) int {
+	if cs == charset.UTF16LE || cs == charset.UTF16BE {
+		return bodyOffset &^ 1
+	}
+	return bodyOffset
+}
+
+// alignWindow drops the half code unit a body limit left at the end of a cut
+// UTF-16 window, so it decodes cleanly and the next window starts aligned.
+func alignWindow(cs string, resp *burp.ParsedHTTPResponse) {
+	if (cs == charset.UTF16LE || cs == charset.UTF16BE) && resp.Truncated && len(resp.Body)%2 == 1 {
+		resp.Body = resp.Body[:len(resp.Body)-1]
+	}
+}
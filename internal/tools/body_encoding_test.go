@@ -0,0 +1,100 @@
+package tools
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/c0tton-fluff/burp-mcp-server/internal/charset"
+)
+
+func TestEncodeBody(t *testing.T) {
+	png := "\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR"
+	tests := []struct {
+		name     string
+		body     string
+		offset   int
+		encoding string
+		wantBody string
+		wantEnv  BodyEnvelope
+	}{
+		{"text auto", "hello", 0, "", "hello", BodyEnvelope{BodyEncoding: "utf8"}},
+		{"binary auto", png, 0, "auto", "iVBORw0KGgoAAAANSUhEUg==", BodyEnvelope{BodyEncoding: "base64", FileType: "image/png"}},
+		{"binary at offset", png[4:], 4, "", "DQoaCgAAAA1JSERS", BodyEnvelope{BodyEncoding: "base64"}},
+		{"forced hex", "hi", 0, "hex", "6869", BodyEnvelope{BodyEncoding: "hex"}},
+		{"forced utf8", png, 0, "utf8", png, BodyEnvelope{BodyEncoding: "utf8", FileType: "image/png"}},
+		{"empty", "", 0, "hex", "", BodyEnvelope{}},
+	}
+	for _, tt := range tests {
+		var env BodyEnvelope
+		if got := encodeBody(&env, tt.body, "", tt.offset, tt.encoding, false); got != tt.wantBody {
+			t.Errorf("%s: body = %q, want %q", tt.name, got, tt.wantBody)
+		}
+		if env != tt.wantEnv {
+			t.Errorf("%s: envelope = %+v, want %+v", tt.name, env, tt.wantEnv)
+		}
+	}
+	if err := checkBodyEncoding("latin1"); err == nil {
+		t.Error("expected error for unknown encoding")
+	}
+}
+
+func TestEncodeBody_Hexdump(t *testing.T) {
+	var env BodyEnvelope
+	encodeBody(&env, strings.Repeat("A", 300), "", 16, "", true)
+	lines := strings.Split(strings.TrimSuffix(env.Hexdump, "\n"), "\n")
+	if len(lines) != 16 || !strings.HasPrefix(lines[0], "00000010  41 41") {
+		t.Errorf("hexdump has %d lines, first %q", len(lines), lines[0])
+	}
+}
+
+func TestEncodeBody_Charset(t *testing.T) {
+	sjis := "<p>\x82\xb1\x82\xf1\x82\xc9\x82\xbf\x82\xcd</p>"
+	tests := []struct {
+		name, body, contentType, encoding, wantBody string
+		wantEnv                                     BodyEnvelope
+	}{
+		{"shift_jis header", sjis, "text/html; charset=Shift_JIS", "", "<p>こんにちは</p>", BodyEnvelope{BodyEncoding: "utf8", Charset: "shift_jis"}},
+		{"gbk meta", `<meta charset="gb2312">` + "\xc4\xe3\xba\xc3", "text/html", "utf8", `<meta charset="gb2312">你好`, BodyEnvelope{BodyEncoding: "utf8", Charset: "gbk"}},
+		{"latin1", "caf\xe9", "text/plain; charset=ISO-8859-1", "", "café", BodyEnvelope{BodyEncoding: "utf8", Charset: "iso-8859-1"}},
+		{"utf8 mislabeled latin1", "café", "text/plain; charset=ISO-8859-1", "", "café", BodyEnvelope{BodyEncoding: "utf8"}},
+		{"base64 keeps bytes", "\xe9", "text/plain; charset=latin1", "base64", "6Q==", BodyEnvelope{BodyEncoding: "base64"}},
+		{"unsupported charset", "a \xb0\xa1 b", "text/html; charset=euc-kr", "", "YSCwoSBi", BodyEnvelope{BodyEncoding: "base64"}},
+	}
+	for _, tt := range tests {
+		var env BodyEnvelope
+		if got := encodeBody(&env, tt.body, charset.Detect(tt.contentType, tt.body), 0, tt.encoding, false); got != tt.wantBody {
+			t.Errorf("%s: body = %q, want %q", tt.name, got, tt.wantBody)
+		}
+		if env != tt.wantEnv {
+			t.Errorf("%s: envelope = %+v, want %+v", tt.name, env, tt.wantEnv)
+		}
+	}
+}
+
+func TestEncodeBody_CharsetAtOffset(t *testing.T) {
+	ctx := context.Background()
+	gbk := "HTTP/1.1 200 OK\r\nContent-Type: text/html\r\n\r\n" +
+		`<html><head><meta charset="gb2312"></head><body>` + strings.Repeat("\xc4\xe3\xba\xc3", 10) + "</body></html>"
+	cs := bodyCharset(gbk)
+	if cs != charset.GBK {
+		t.Fatalf("charset = %q", cs)
+	}
+	resp := parseResponse(ctx, gbk, 52, 8, defaultBodyLimit)
+	var env BodyEnvelope
+	if got := encodeBody(&env, resp.Body, cs, 52, "", false); got != "你好你好" || env.Charset != charset.GBK || env.BodyEncoding != encodingUTF8 {
+		t.Errorf("body = %q %+v", got, env)
+	}
+
+	// "héllo" in UTF-16LE after a BOM: an odd offset and limit would split
+	// code units.
+	utf16 := "HTTP/1.1 200 OK\r\n\r\n\xff\xfeh\x00\xe9\x00l\x00l\x00o\x00"
+	cs = bodyCharset(utf16)
+	offset := alignOffset(cs, 5)
+	resp = parseResponse(ctx, utf16, offset, 5, defaultBodyLimit)
+	alignWindow(cs, resp)
+	env = BodyEnvelope{}
+	if got := encodeBody(&env, resp.Body, cs, offset, "", false); offset != 4 || got != "él" || env.Charset != charset.UTF16LE {
+		t.Errorf("utf-16 at %d = %q %+v", offset, got, env)
+	}
+}
@@ -0,0 +1,117 @@
+package tools
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Content-Length modes of BodyFraming; any other value is sent verbatim.
+const (
+	contentLengthAuto = "auto" // match the body as sent
+	contentLengthKeep = "keep" // leave the header as written
+	contentLengthOmit = "omit" // remove the header
+)
+
+// BodyFraming controls how a request body is delimited on the wire, for
+// desync testing: a Content-Length that disagrees with the body, or a body
+// re-encoded as chunked, optionally alongside a Content-Length.
+type BodyFraming struct {
+	ContentLength string   `json:"contentLength,omitempty" jsonschema:"auto: match the body as sent, keep: leave as written, omit: remove the header, or any other value sent verbatim (e.g. 6, +6, 0x6). Default: keep for send, auto for race, omit when chunked"`
+	Chunked       bool     `json:"chunked,omitempty" jsonschema:"Re-encode the body with Transfer-Encoding: chunked"`
+	ChunkSize     int      `json:"chunkSize,omitempty" jsonschema:"Chunked: bytes per chunk (default: the whole body in one chunk)"`
+	Trailers      []string `json:"trailers,omitempty" jsonschema:"Chunked: trailer fields sent after the last chunk, as 'Name: value'"`
+}
+
+// custom reports whether f changes the body's framing from the default.
+func (f *BodyFraming) custom() bool {
+	return f != nil && (f.ContentLength != "" || f.Chunked)
+}
+
+// apply frames the body of rawNorm, a CRLF request, as f describes.
+// contentLength is the tool's default mode when f does not set one; a nil
+// f applies only that default. Normalization ends a request with CRLFCRLF,
+// so for a normalized request that terminator is not chunked as body data.
+func (f *BodyFraming) apply(rawNorm, contentLength string, normalized bool) (string, error) {
+	var opts BodyFraming
+	if f != nil {
+		opts = *f
+	}
+	if opts.ContentLength != "" {
+		contentLength = opts.ContentLength
+	} else if opts.Chunked {
+		contentLength = contentLengthOmit
+	}
+	if strings.ContainsAny(contentLength, "\r\n") {
+		return "", fmt.Errorf("contentLength must not contain line breaks")
+	}
+	if !opts.Chunked && (opts.ChunkSize != 0 || len(opts.Trailers) > 0) {
+		return "", fmt.Errorf("chunkSize and trailers require chunked: true")
+	}
+	if opts.ChunkSize < 0 {
+		return "", fmt.Errorf("chunkSize must not be negative")
+	}
+	if !opts.Chunked && contentLength == contentLengthKeep {
+		return rawNorm, nil
+	}
+
+	if !opts.Chunked && contentLength == contentLengthAuto {
+		return fixContentLength(rawNorm), nil
+	}
+
+	// Edit the headers in place; the request line is kept as written.
+	head, body, ok := strings.Cut(rawNorm, "\r\n\r\n")
+	if !ok {
+		return "", fmt.Errorf("request has no end of headers")
+	}
+	line, headers, _ := strings.Cut(head, "\r\n")
+	r := &rawRequest{body: body}
+	if headers != "" {
+		r.headers = strings.Split(headers, "\r\n")
+	}
+	if opts.Chunked {
+		if normalized {
+			r.body = strings.TrimSuffix(r.body, "\r\n\r\n")
+		}
+		body, err := chunkBody(r.body, opts.ChunkSize, opts.Trailers)
+		if err != nil {
+			return "", err
+		}
+		r.body = body
+		r.setHeader("Transfer-Encoding", "chunked")
+	}
+
+	switch contentLength {
+	case contentLengthKeep:
+	case contentLengthOmit:
+		r.removeHeader("Content-Length")
+	case contentLengthAuto:
+		r.setHeader("Content-Length", strconv.Itoa(len(r.body)))
+	default:
+		r.setHeader("Content-Length", contentLength)
+	}
+	return strings.Join(append([]string{line}, r.headers...), "\r\n") + "\r\n\r\n" + r.body, nil
+}
+
+// chunkBody encodes body in chunks of size bytes (0: one chunk), followed
+// by the last chunk and trailers.
+func chunkBody(body string, size int, trailers []string) (string, error) {
+	if size <= 0 {
+		size = max(len(body), 1)
+	}
+	var b strings.Builder
+	for rest := body; rest != ""; {
+		chunk := rest[:min(size, len(rest))]
+		rest = rest[len(chunk):]
+		fmt.Fprintf(&b, "%x\r\n%s\r\n", len(chunk), chunk)
+	}
+	b.WriteString("0\r\n")
+	for _, t := range trailers {
+		if headerName(t) == "" || strings.ContainsAny(t, "\r\n") {
+			return "", fmt.Errorf("trailer %q must be a single 'Name: value' line", t)
+		}
+		b.WriteString(t + "\r\n")
+	}
+	b.WriteString("\r\n")
+	return b.String(), nil
+}
@@ -0,0 +1,75 @@
+package tools
+
+import (
+	"context"
+	"testing"
+)
+
+func TestBodyFraming_Apply(t *testing.T) {
+	const post = "POST /x HTTP/1.1\r\nHost: x\r\nContent-Length: 99\r\n\r\nabcdef"
+	tests := []struct {
+		name    string
+		framing *BodyFraming
+		def     string
+		want    string
+	}{
+		{"nil keep", nil, contentLengthKeep, post},
+		{"nil auto", nil, contentLengthAuto, "POST /x HTTP/1.1\r\nHost: x\r\nContent-Length: 6\r\n\r\nabcdef"},
+		{"verbatim", &BodyFraming{ContentLength: "+4"}, contentLengthAuto, "POST /x HTTP/1.1\r\nHost: x\r\nContent-Length: +4\r\n\r\nabcdef"},
+		{"omit", &BodyFraming{ContentLength: "omit"}, contentLengthKeep, "POST /x HTTP/1.1\r\nHost: x\r\n\r\nabcdef"},
+		{
+			"chunked",
+			&BodyFraming{Chunked: true, ChunkSize: 4},
+			contentLengthAuto,
+			"POST /x HTTP/1.1\r\nHost: x\r\nTransfer-Encoding: chunked\r\n\r\n4\r\nabcd\r\n2\r\nef\r\n0\r\n\r\n",
+		},
+		{
+			"chunked with length and trailer",
+			&BodyFraming{Chunked: true, ContentLength: "auto", Trailers: []string{"X-Sig: 1"}},
+			contentLengthKeep,
+			"POST /x HTTP/1.1\r\nHost: x\r\nContent-Length: 26\r\nTransfer-Encoding: chunked\r\n\r\n6\r\nabcdef\r\n0\r\nX-Sig: 1\r\n\r\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.framing.apply(post, tt.def, false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("apply() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	for name, f := range map[string]*BodyFraming{
+		"trailers without chunked": {Trailers: []string{"X: 1"}},
+		"negative chunk size":      {Chunked: true, ChunkSize: -1},
+		"malformed trailer":        {Chunked: true, Trailers: []string{"no colon"}},
+		"line break":               {ContentLength: "1\r\nX: y"},
+	} {
+		if _, err := f.apply(post, contentLengthKeep, false); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+func TestRaceRequest_Chunked(t *testing.T) {
+	target, received := startRawTarget(t)
+	noTLS := false
+	_, _, err := raceRequestHandler()(context.Background(), nil, RaceRequestInput{
+		Raw:     "POST / HTTP/1.1\r\nHost: x\r\nContent-Length: 3\r\n\r\nabc",
+		Framing: &BodyFraming{Chunked: true, ContentLength: "3"},
+		Host:    target.Host,
+		Port:    target.Port,
+		TLS:     &noTLS,
+		Count:   1,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "POST / HTTP/1.1\r\nHost: x\r\nContent-Length: 3\r\nTransfer-Encoding: chunked\r\n\r\n3\r\nabc\r\n0\r\n\r\n"
+	if got := string(<-received); got != want {
+		t.Errorf("sent %q, want %q", got, want)
+	}
+}
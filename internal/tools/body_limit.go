@@ -0,0 +1,78 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/c0tton-fluff/burp-mcp-server/internal/burp"
+)
+
+// parseResponse parses raw and limits its body. A non-zero limit is the
+// caller's explicit bodyLimit and wins; otherwise the configured bodyLimits
+// for the response's content type and the calling tool apply, then fallback.
+func parseResponse(ctx context.Context, raw string, bodyOffset, limit, fallback int) *burp.ParsedHTTPResponse {
+	return parseRelevantResponse(ctx, raw, bodyOffset, limit, fallback, nil)
+}
+
+// parseRelevantResponse is parseResponse, but when rel is set an over-limit
+// body keeps its most relevant parts (see relevantBody) instead of its head.
+func parseRelevantResponse(ctx context.Context, raw string, bodyOffset, limit, fallback int, rel *RelevanceOptions) *burp.ParsedHTTPResponse {
+	if limit != 0 && rel == nil {
+		return burp.ParseHTTPResponse(raw, bodyOffset, limit)
+	}
+	resp := burp.ParseHTTPResponse(raw, bodyOffset, 0)
+	if resp == nil {
+		return nil
+	}
+	contentType := burp.GetHeader(resp.Headers, "Content-Type")
+	if limit == 0 {
+		limit = settings.BodyLimits.Limit(toolName(ctx), contentType, fallback)
+	}
+	if limit < 0 || len(resp.Body) <= limit {
+		return resp
+	}
+	// Relevance windows and markers would corrupt binary bodies.
+	if rel != nil && limit > 0 && !burp.IsBinary(resp.Body) {
+		resp.Body = relevantBody(resp.Body, contentType, *rel, limit, bodyOffset)
+	} else {
+		resp.Body = resp.Body[:limit]
+	}
+	resp.Truncated = true
+	return resp
+}
+
+// BodyEnvelope reports how much of a response body an output carries, so a
+// caller can tell a short body from a cut one and knows how to get the rest,
+// and how the body is encoded. Sizes count raw body bytes, not encoded ones.
+type BodyEnvelope struct {
+	BodySize         int    `json:"bodySize"`
+	ReturnedBytes    int    `json:"returnedBytes"`
+	Truncated        bool   `json:"truncated,omitempty"`
+	ContinuationHint string `json:"continuationHint,omitempty"`
+
+	BodyEncoding string `json:"bodyEncoding,omitempty"`
+	FileType     string `json:"fileType,omitempty"`
+	Hexdump      string `json:"hexdump,omitempty"`
+	// Charset is the body's original charset when it was transcoded to UTF-8.
+	Charset string `json:"charset,omitempty"`
+	// BodyView is set when the body is an HTML view rather than the body.
+	BodyView string       `json:"bodyView,omitempty"`
+	Page     *PageOutline `json:"page,omitempty"`
+}
+
+// bodyEnvelope describes resp's body as returned from bodyOffset. When it was
+// cut, the hint tells the caller to repeat the call (again, e.g. "call again")
+// with the offset of the first byte not returned. Relevance excerpts aren't
+// contiguous, so their hint points at the offsets in the skip markers instead.
+func bodyEnvelope(resp *burp.ParsedHTTPResponse, bodyOffset int, again string, rel *RelevanceOptions) BodyEnvelope {
+	env := BodyEnvelope{BodySize: resp.BodySize, ReturnedBytes: len(resp.Body), Truncated: resp.Truncated}
+	switch {
+	case !resp.Truncated:
+	case rel != nil:
+		env.ContinuationHint = fmt.Sprintf("body holds excerpts; %s with a bodyOffset from a skip marker, or bodyLimit=-1 for all %d bytes", again, resp.BodySize)
+	default:
+		next := bodyOffset + len(resp.Body)
+		env.ContinuationHint = fmt.Sprintf("%s with bodyOffset=%d for the remaining %d bytes", again, next, resp.BodySize-next)
+	}
+	return env
+}
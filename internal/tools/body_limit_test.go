@@ -0,0 +1,61 @@
+package tools
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/c0tton-fluff/burp-mcp-server/internal/config"
+)
+
+func TestParseResponse_ConfiguredLimits(t *testing.T) {
+	t.Cleanup(func() { Configure(nil) })
+	Configure(&config.Config{BodyLimits: config.BodyLimits{
+		Tools:        map[string]int{"burp_race_request": 5},
+		ContentTypes: map[string]int{"application/json": 8, "binary": 0},
+	}})
+
+	body := strings.Repeat("x", 100)
+	response := func(ct string) string {
+		return "HTTP/1.1 200 OK\r\nContent-Type: " + ct + "\r\n\r\n" + body
+	}
+	race := context.WithValue(context.Background(), toolNameKey{}, "burp_race_request")
+
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		ct       string
+		limit    int
+		wantBody int
+	}{
+		{"content type", context.Background(), "application/json", 0, 8},
+		{"binary omitted", context.Background(), "image/png", 0, 0},
+		{"tool default", race, "text/html", 0, 5},
+		{"built-in fallback", context.Background(), "text/html", 0, 20},
+		{"explicit limit wins", race, "application/json", 50, 50},
+		{"explicit unlimited", context.Background(), "image/png", -1, 100},
+	}
+	for _, tt := range tests {
+		resp := parseResponse(tt.ctx, response(tt.ct), 0, tt.limit, 20)
+		if len(resp.Body) != tt.wantBody || resp.BodySize != 100 {
+			t.Errorf("%s: body = %d bytes (size %d), want %d", tt.name, len(resp.Body), resp.BodySize, tt.wantBody)
+		}
+		if resp.Truncated != (tt.wantBody < 100) {
+			t.Errorf("%s: truncated = %v", tt.name, resp.Truncated)
+		}
+	}
+}
+
+func TestBodyEnvelope(t *testing.T) {
+	resp := parseResponse(context.Background(), "HTTP/1.1 200 OK\r\n\r\n"+strings.Repeat("x", 100), 30, 20, 20)
+	env := bodyEnvelope(resp, 30, "call again", nil)
+	want := BodyEnvelope{BodySize: 100, ReturnedBytes: 20, Truncated: true, ContinuationHint: "call again with bodyOffset=50 for the remaining 50 bytes"}
+	if env != want {
+		t.Errorf("got %+v, want %+v", env, want)
+	}
+
+	resp = parseResponse(context.Background(), "HTTP/1.1 200 OK\r\n\r\nshort", 0, 0, 20)
+	if env := bodyEnvelope(resp, 0, "call again", nil); env != (BodyEnvelope{BodySize: 5, ReturnedBytes: 5}) {
+		t.Errorf("complete body: %+v", env)
+	}
+}
@@ -0,0 +1,243 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/c0tton-fluff/burp-mcp-server/internal/burp"
+	"github.com/c0tton-fluff/burp-mcp-server/internal/charset"
+	"github.com/c0tton-fluff/burp-mcp-server/internal/extract"
+)
+
+// Body views accepted by bodyView.
+const (
+	bodyViewRaw           = "raw"
+	bodyViewText          = "text"
+	bodyViewTitleAndForms = "title-and-forms"
+)
+
+// maxOutlineHeadings caps the headings in a page outline.
+const maxOutlineHeadings = 50
+
+// checkBodyView rejects unknown bodyView values before anything is sent.
+func checkBodyView(view string) error {
+	switch view {
+	case "", bodyViewRaw, bodyViewText, bodyViewTitleAndForms:
+		return nil
+	}
+	return fmt.Errorf("bodyView must be raw, text, or title-and-forms, got %q", view)
+}
+
+// PageForm is a form in a page outline.
+type PageForm struct {
+	Action  string       `json:"action"`
+	Method  string       `json:"method"`
+	Enctype string       `json:"enctype,omitempty"`
+	Fields  []CrawlField `json:"fields"`
+}
+
+// PageOutline is what bodyView title-and-forms returns instead of the body.
+type PageOutline struct {
+	Title    string     `json:"title,omitempty"`
+	Headings []string   `json:"headings,omitempty"` // "h2: Account settings"
+	Forms    []PageForm `json:"forms"`
+	// Inputs are named controls outside any form, as single-page apps use.
+	Inputs []CrawlField `json:"inputs,omitempty"`
+}
+
+// isHTML reports whether a body is an HTML page, by content type or, when
+// there is none, by its start.
+func isHTML(contentType, body string) bool {
+	if contentType != "" {
+		return strings.Contains(strings.ToLower(contentType), "html")
+	}
+	start := strings.ToLower(strings.TrimSpace(body[:min(len(body), 512)]))
+	return strings.HasPrefix(start, "<!doctype html") || strings.HasPrefix(start, "<html")
+}
+
+// hiddenElements hold no readable text.
+var hiddenElements = map[string]bool{"script": true, "style": true, "noscript": true, "template": true, "svg": true, "head": true}
+
+// blockElements start a new line in the text view.
+var blockElements = map[string]bool{
+	"address": true, "article": true, "aside": true, "blockquote": true, "br": true, "dd": true, "div": true, "dl": true,
+	"dt": true, "fieldset": true, "figcaption": true, "figure": true, "footer": true, "form": true, "header": true,
+	"hr": true, "li": true, "main": true, "nav": true, "ol": true, "option": true, "p": true, "pre": true, "section": true,
+	"table": true, "title": true, "tr": true, "ul": true,
+}
+
+// htmlText renders a page as readable text: one line per block, headings
+// marked "#" to "######", list items "- ", scripts and styles dropped.
+func htmlText(body string) string {
+	doc := extract.ParseHTML(body)
+	var sb strings.Builder
+	var walk func(n *extract.Node)
+	walk = func(n *extract.Node) {
+		if n.Tag == "" {
+			sb.WriteString(n.Text)
+			return
+		}
+		// The title is the one part of head worth keeping.
+		if n.Tag == "head" {
+			for _, c := range n.Children {
+				if c.Tag == "title" {
+					walk(c)
+				}
+			}
+			return
+		}
+		if hiddenElements[n.Tag] {
+			return
+		}
+		block := blockElements[n.Tag]
+		switch {
+		case len(n.Tag) == 2 && n.Tag[0] == 'h' && n.Tag[1] >= '1' && n.Tag[1] <= '6':
+			block = true
+			sb.WriteString("\n" + strings.Repeat("#", int(n.Tag[1]-'0')) + " ")
+		case n.Tag == "li":
+			sb.WriteString("\n- ")
+		case n.Tag == "td" || n.Tag == "th":
+			sb.WriteString(" ")
+		case block:
+			sb.WriteString("\n")
+		}
+		for _, c := range n.Children {
+			walk(c)
+		}
+		if block {
+			sb.WriteString("\n")
+		}
+	}
+	walk(doc)
+
+	var lines []string
+	for _, line := range strings.Split(sb.String(), "\n") {
+		if line = collapseSpace(line); line != "" && line != "-" {
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func collapseSpace(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// fieldType is a form control's type: the type attribute of inputs and
+// buttons, defaulting to text for inputs, else the tag.
+func fieldType(tag, typeAttr string) string {
+	if tag == "input" || tag == "button" {
+		if t := strings.ToLower(typeAttr); t != "" {
+			return t
+		}
+		if tag == "input" {
+			return "text"
+		}
+	}
+	return tag
+}
+
+// htmlOutline returns a page's title, headings, forms, and the named
+// controls outside forms. Actions are as written, not resolved.
+func htmlOutline(body string) *PageOutline {
+	out := &PageOutline{Forms: []PageForm{}}
+	var walk func(n *extract.Node, form *PageForm)
+	walk = func(n *extract.Node, form *PageForm) {
+		switch n.Tag {
+		case "":
+			return
+		case "script", "style", "template":
+			return
+		case "title":
+			if out.Title == "" {
+				out.Title = collapseSpace(n.TextContent())
+			}
+			return
+		case "h1", "h2", "h3", "h4", "h5", "h6":
+			if text := collapseSpace(n.TextContent()); text != "" && len(out.Headings) < maxOutlineHeadings {
+				out.Headings = append(out.Headings, n.Tag+": "+text)
+			}
+		case "form":
+			action, _ := n.Attr("action")
+			method, _ := n.Attr("method")
+			enctype, _ := n.Attr("enctype")
+			if method = strings.ToUpper(method); method == "" {
+				method = "GET"
+			}
+			out.Forms = append(out.Forms, PageForm{Action: action, Method: method, Enctype: enctype, Fields: []CrawlField{}})
+			form = &out.Forms[len(out.Forms)-1]
+		case "input", "select", "textarea", "button":
+			if name, _ := n.Attr("name"); name != "" {
+				typ, _ := n.Attr("type")
+				value, _ := n.Attr("value")
+				if n.Tag == "textarea" {
+					value = n.TextContent()
+				}
+				field := CrawlField{Name: name, Type: fieldType(n.Tag, typ), Value: value}
+				if form != nil {
+					form.Fields = append(form.Fields, field)
+				} else {
+					out.Inputs = append(out.Inputs, field)
+				}
+			}
+			if n.Tag != "select" {
+				return
+			}
+		}
+		for _, c := range n.Children {
+			// Forms can't nest; a form pointer into out.Forms stays valid
+			// only until the next form is appended, so look it up again.
+			walk(c, form)
+			if form != nil && n.Tag == "form" {
+				form = &out.Forms[len(out.Forms)-1]
+			}
+		}
+	}
+	walk(extract.ParseHTML(body), nil)
+	return out
+}
+
+// viewBody renders the HTML response in raw as view. It works on the whole
+// body, so a text view of a large page isn't cut before the markup is
+// dropped; bodyOffset and limit (0 for the configured default) then apply
+// to the text. It returns false when view is raw or the body isn't HTML,
+// leaving the caller to return the body as usual.
+func viewBody(ctx context.Context, raw, view string, bodyOffset, limit int, again string) (string, BodyEnvelope, bool) {
+	if view == "" || view == bodyViewRaw {
+		return "", BodyEnvelope{}, false
+	}
+	full := burp.ParseHTTPResponse(raw, 0, 0)
+	if full == nil || full.Body == "" {
+		return "", BodyEnvelope{}, false
+	}
+	contentType := burp.GetHeader(full.Headers, "Content-Type")
+	var env BodyEnvelope
+	body := transcodeBody(&env, full.Body, charset.Detect(contentType, full.Body))
+	if !isHTML(contentType, body) {
+		return "", BodyEnvelope{}, false
+	}
+
+	if view == bodyViewTitleAndForms {
+		env.BodySize, env.BodyView, env.Page = full.BodySize, view, htmlOutline(body)
+		return "", env, true
+	}
+	text := htmlText(body)
+	bodyOffset = max(bodyOffset, 0)
+	if limit == 0 {
+		limit = settings.BodyLimits.Limit(toolName(ctx), contentType, defaultBodyLimit)
+	}
+	cut := &burp.ParsedHTTPResponse{Body: text[min(bodyOffset, len(text)):], BodySize: len(text)}
+	if limit > 0 && len(cut.Body) > limit {
+		end := limit
+		for end > 0 && !utf8.RuneStart(cut.Body[end]) {
+			end--
+		}
+		cut.Body, cut.Truncated = cut.Body[:end], true
+	}
+	charset := env.Charset
+	env = bodyEnvelope(cut, bodyOffset, again, nil)
+	env.BodyEncoding, env.Charset, env.BodyView = encodingUTF8, charset, view
+	return cut.Body, env, true
+}
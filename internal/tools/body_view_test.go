@@ -0,0 +1,106 @@
+package tools
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const viewPage = `<!DOCTYPE html>
+<html><head><title>Sign in</title><script>var token = "x";</script><style>p { color: red }</style></head>
+<body>
+<nav><ul><li><a href="/">Home</a></li><li><a href="/help">Help</a></li></ul></nav>
+<h1>Welcome   back</h1>
+<p>Please <b>sign</b> in.</p>
+<form action="/login" method="post">
+  <input name="user"><input type="password" name="pass">
+  <input type="hidden" name="csrf" value="abc">
+  <select name="lang"><option>en</option><option>de</option></select>
+  <textarea name="note">hi</textarea>
+  <button>Go</button>
+</form>
+<table><tr><td>a</td><td>b</td></tr></table>
+<input name="q" type="search">
+<form><input name="s"></form>
+</body></html>`
+
+func TestHTMLText(t *testing.T) {
+	want := "Sign in\n- Home\n- Help\n# Welcome back\nPlease sign in.\nen\nde\nhi\nGo\na b"
+	if got := htmlText(viewPage); got != want {
+		t.Errorf("htmlText =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestHTMLOutline(t *testing.T) {
+	want := &PageOutline{
+		Title:    "Sign in",
+		Headings: []string{"h1: Welcome back"},
+		Forms: []PageForm{
+			{Action: "/login", Method: "POST", Fields: []CrawlField{
+				{Name: "user", Type: "text"},
+				{Name: "pass", Type: "password"},
+				{Name: "csrf", Type: "hidden", Value: "abc"},
+				{Name: "lang", Type: "select"},
+				{Name: "note", Type: "textarea", Value: "hi"},
+			}},
+			{Method: "GET", Fields: []CrawlField{{Name: "s", Type: "text"}}},
+		},
+		Inputs: []CrawlField{{Name: "q", Type: "search"}},
+	}
+	got := htmlOutline(viewPage)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("htmlOutline = %+v, want %+v", got, want)
+	}
+}
+
+func TestViewBody(t *testing.T) {
+	ctx := context.Background()
+	resp := "HTTP/1.1 200 OK\r\nContent-Type: text/html; charset=Shift_JIS\r\n\r\n" +
+		"<title>\x82\xb1\x82\xf1</title><p>" + strings.Repeat("x", 20) + "</p>"
+
+	body, env, ok := viewBody(ctx, resp, bodyViewText, 0, 10, "resend")
+	if !ok || body != "こん\nxxx" || env.BodySize != 27 || !env.Truncated || env.BodyView != bodyViewText || env.Charset != "shift_jis" {
+		t.Errorf("text view = %q %+v %v", body, env, ok)
+	}
+	if !strings.Contains(env.ContinuationHint, "bodyOffset=10") {
+		t.Errorf("hint = %q", env.ContinuationHint)
+	}
+
+	body, env, ok = viewBody(ctx, resp, bodyViewText, -1, 3, "resend")
+	if !ok || body != "こ" || env.ReturnedBytes != 3 {
+		t.Errorf("negative offset = %q %+v %v", body, env, ok)
+	}
+
+	body, env, ok = viewBody(ctx, resp, bodyViewTitleAndForms, 0, 0, "resend")
+	if !ok || body != "" || env.Page == nil || env.Page.Title != "こん" || env.BodySize != 46 {
+		t.Errorf("title-and-forms view = %q %+v %v", body, env, ok)
+	}
+
+	for _, tt := range []struct{ name, resp, view string }{
+		{"raw", resp, bodyViewRaw},
+		{"json", "HTTP/1.1 200 OK\r\nContent-Type: application/json\r\n\r\n{\"a\":1}", bodyViewText},
+		{"empty", "HTTP/1.1 204 No Content\r\n\r\n", bodyViewText},
+	} {
+		if _, _, ok := viewBody(ctx, tt.resp, tt.view, 0, 0, "resend"); ok {
+			t.Errorf("%s: view applied", tt.name)
+		}
+	}
+}
+
+func TestIsHTML(t *testing.T) {
+	if !isHTML("", "  <!DOCTYPE html><p>") || !isHTML("application/xhtml+xml", "") || isHTML("", "<?xml?>") || isHTML("text/plain", "<html>") {
+		t.Error("isHTML misclassified")
+	}
+}
+
+func TestCheckBodyView(t *testing.T) {
+	for _, v := range []string{"", "raw", "text", "title-and-forms"} {
+		if err := checkBodyView(v); err != nil {
+			t.Errorf("%q: %v", v, err)
+		}
+	}
+	if checkBodyView("markdown") == nil {
+		t.Error("unknown view accepted")
+	}
+}
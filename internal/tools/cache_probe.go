@@ -0,0 +1,291 @@
+package tools
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/c0tton-fluff/burp-mcp-server/internal/burp"
+	"github.com/c0tton-fluff/burp-mcp-server/internal/config"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// maxCacheHeaders caps the extra unkeyed-header candidates per probe.
+const maxCacheHeaders = 20
+
+// cacheCandidates are headers commonly left out of the cache key while
+// still changing the response. {canary} is replaced per request.
+var cacheCandidates = []struct{ header, value string }{
+	{"X-Forwarded-Host", "{canary}." + defaultAttackerDomain},
+	{"X-Host", "{canary}." + defaultAttackerDomain},
+	{"X-Forwarded-Server", "{canary}." + defaultAttackerDomain},
+	{"X-Original-Host", "{canary}." + defaultAttackerDomain},
+	{"Forwarded", "host={canary}." + defaultAttackerDomain},
+	{"X-Forwarded-Scheme", "http"},
+	{"X-Forwarded-Proto", "http"},
+	{"X-Forwarded-Port", "1337"},
+	{"X-Forwarded-Prefix", "/{canary}"},
+	{"X-Original-URL", "/{canary}"},
+	{"X-Rewrite-URL", "/{canary}"},
+	{"Origin", "https://{canary}." + defaultAttackerDomain},
+}
+
+// cacheStatusHeaders report hit or miss, depending on the CDN or proxy.
+var cacheStatusHeaders = []string{"X-Cache", "X-Cache-Status", "CF-Cache-Status", "X-Proxy-Cache", "X-Cache-Lookup", "X-Drupal-Cache", "Cache-Status"}
+
+// deceptionSuffixes make a dynamic page look like a static file to caches
+// that key their rules on the extension.
+var deceptionSuffixes = []string{"/{canary}.css", ";{canary}.css", "%2f{canary}.css"}
+
+// CacheProbeInput is the input for burp_cache_probe.
+type CacheProbeInput struct {
+	Raw           string   `json:"raw" jsonschema:"required,Raw HTTP request for a cacheable page; include the session to test cache deception"`
+	Headers       []string `json:"headers,omitempty" jsonschema:"Extra header names to test as unkeyed inputs besides the built-in list"`
+	BusterParam   string   `json:"busterParam,omitempty" jsonschema:"Query parameter used as a cache buster (default: cb)"`
+	Host          string   `json:"host,omitempty" jsonschema:"Target host (overrides Host header)"`
+	Port          int      `json:"port,omitempty" jsonschema:"Target port (default based on TLS)"`
+	TLS           *bool    `json:"tls,omitempty" jsonschema:"Use HTTPS (default true)"`
+	HeaderProfile string   `json:"headerProfile,omitempty" jsonschema:"Header rule profile from config (default: 'default')"`
+	Instance      string   `json:"instance,omitempty" jsonschema:"Named Burp instance from config (default: the --burp-url connection)"`
+}
+
+// CacheCheck is the outcome of one poisoning or deception attempt.
+type CacheCheck struct {
+	Name       string `json:"name"`
+	Change     string `json:"change"`
+	StatusCode int    `json:"statusCode,omitempty"`
+	// Reflected means the input changed the response it was sent with.
+	Reflected bool `json:"reflected"`
+	// Hit means the repeat fetch without the input was served from cache.
+	Hit bool `json:"hit"`
+	// Poisoned means the repeat fetch still carried the input's effect.
+	Poisoned bool   `json:"poisoned"`
+	Evidence string `json:"evidence,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+// CacheProbeOutput is the output of burp_cache_probe.
+type CacheProbeOutput struct {
+	Cached        bool         `json:"cached"`
+	CacheEvidence string       `json:"cacheEvidence,omitempty"`
+	Checks        []CacheCheck `json:"checks"`
+	Deception     []CacheCheck `json:"deception,omitempty"`
+	Findings      []string     `json:"findings"`
+}
+
+// cacheToken returns a random token for cache busters and canaries.
+func cacheToken() string {
+	var b [4]byte
+	rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
+
+// cacheHit reports whether resp was served from cache, with the header
+// that says so.
+func cacheHit(resp *burp.ParsedHTTPResponse) (bool, string) {
+	for _, name := range cacheStatusHeaders {
+		v := burp.GetHeader(resp.Headers, name)
+		if strings.Contains(strings.ToLower(v), "hit") {
+			return true, name + ": " + v
+		}
+	}
+	if age := burp.GetHeader(resp.Headers, "Age"); age != "" {
+		if n, err := strconv.Atoi(strings.TrimSpace(age)); err == nil && n > 0 {
+			return true, "Age: " + age
+		}
+	}
+	return false, ""
+}
+
+// responseContains reports whether canary appears in resp's headers or body.
+func responseContains(resp *burp.ParsedHTTPResponse, canary string) bool {
+	for _, values := range resp.Headers {
+		for _, v := range values {
+			if strings.Contains(v, canary) {
+				return true
+			}
+		}
+	}
+	return strings.Contains(resp.Body, canary)
+}
+
+// withBuster sets the cache buster parameter so each check gets its own
+// cache entry and no real user is served a poisoned response.
+func withBuster(raw, param, token string) (string, error) {
+	req, _, err := injectParam(raw, param, token, "query")
+	return req, err
+}
+
+func cacheProbeHandler(client *burp.Client) func(context.Context, *mcp.CallToolRequest, CacheProbeInput) (*mcp.CallToolResult, CacheProbeOutput, error) {
+	return func(ctx context.Context, _ *mcp.CallToolRequest, input CacheProbeInput) (*mcp.CallToolResult, CacheProbeOutput, error) {
+		ctx = burp.WithInstance(ctx, input.Instance)
+
+		if len(input.Headers) > maxCacheHeaders {
+			return nil, CacheProbeOutput{}, fmt.Errorf("max %d headers", maxCacheHeaders)
+		}
+		buster := input.BusterParam
+		if buster == "" {
+			buster = "cb"
+		}
+		rawNorm, parsed, err := prepareRequest(input.Raw, input.HeaderProfile)
+		if err != nil {
+			return nil, CacheProbeOutput{}, err
+		}
+		t, err := resolveTarget(input.Host, input.Port, input.TLS, parsed.Host)
+		if err != nil {
+			return nil, CacheProbeOutput{}, err
+		}
+
+		// Fetch the same busted URL twice to see whether it is cached at all.
+		first, err := withBuster(rawNorm, buster, cacheToken())
+		if err != nil {
+			return nil, CacheProbeOutput{}, err
+		}
+		baseline, err := sendParsed(ctx, client, first, t, 0)
+		if err != nil {
+			return nil, CacheProbeOutput{}, fmt.Errorf("baseline request failed: %w", err)
+		}
+		var out CacheProbeOutput
+		if repeat, err := sendParsed(ctx, client, first, t, 1); err == nil {
+			out.Cached, out.CacheEvidence = cacheHit(repeat)
+		}
+
+		candidates := slices.Clone(cacheCandidates)
+		for _, h := range input.Headers {
+			candidates = append(candidates, struct{ header, value string }{h, "{canary}." + defaultAttackerDomain})
+		}
+		out.Checks = make([]CacheCheck, len(candidates))
+		parallel(len(candidates), func(i int) {
+			c := candidates[i]
+			token := cacheToken()
+			canary := "cp" + cacheToken()
+			value := strings.ReplaceAll(c.value, "{canary}", canary)
+			check := CacheCheck{Name: strings.ToLower(c.header), Change: c.header + ": " + value}
+			defer func() { out.Checks[i] = check }()
+
+			clean, err := withBuster(rawNorm, buster, token)
+			if err != nil {
+				check.Error = err.Error()
+				return
+			}
+			poisoned, err := sendParsed(ctx, client, applyHeaderRules(clean, config.HeaderRules{Set: map[string]string{c.header: value}}), t, 0)
+			if err != nil {
+				check.Error = err.Error()
+				return
+			}
+			check.StatusCode = poisoned.StatusCode
+			marked := strings.Contains(c.value, "{canary}") && responseContains(poisoned, canary)
+			check.Reflected = marked || poisoned.StatusCode != baseline.StatusCode
+
+			again, err := sendParsed(ctx, client, clean, t, 0)
+			if err != nil {
+				check.Error = err.Error()
+				return
+			}
+			hit, evidence := cacheHit(again)
+			check.Hit = hit
+			switch {
+			case marked && responseContains(again, canary):
+				check.Poisoned = true
+				check.Evidence = fmt.Sprintf("%s in a response fetched without the header", canary)
+			case !marked && check.Reflected && again.StatusCode == poisoned.StatusCode:
+				check.Poisoned = true
+				check.Evidence = fmt.Sprintf("status %d (baseline %d) served to a request without the header", again.StatusCode, baseline.StatusCode)
+			}
+			if evidence != "" {
+				check.Evidence = strings.TrimPrefix(check.Evidence+"; "+evidence, "; ")
+			}
+		})
+
+		out.Deception = cacheDeception(ctx, client, rawNorm, parsed, t)
+		out.Findings = cacheFindings(out)
+		return nil, out, nil
+	}
+}
+
+// cacheDeception requests the page with static-looking suffixes using the
+// session, then again without Cookie and Authorization. The anonymous
+// request getting the session's page from cache is web cache deception.
+// It returns nil when the request carries no session.
+func cacheDeception(ctx context.Context, client *burp.Client, rawNorm string, parsed *burp.ParsedHTTPRequest, t resolvedTarget) []CacheCheck {
+	if burp.GetHeader(parsed.Headers, "Cookie") == "" && burp.GetHeader(parsed.Headers, "Authorization") == "" {
+		return nil
+	}
+	checks := make([]CacheCheck, len(deceptionSuffixes))
+	parallel(len(deceptionSuffixes), func(i int) {
+		suffix := strings.ReplaceAll(deceptionSuffixes[i], "{canary}", "cd"+cacheToken())
+		check := CacheCheck{Name: "deception " + deceptionSuffixes[i], Change: "path suffix " + suffix}
+		defer func() { checks[i] = check }()
+
+		r, err := splitRawRequest(rawNorm)
+		if err != nil {
+			check.Error = err.Error()
+			return
+		}
+		path, query, _ := strings.Cut(r.target, "?")
+		r.target = joinTarget(path+suffix, query)
+		authed, err := sendParsed(ctx, client, r.String(), t, 0)
+		if err != nil {
+			check.Error = err.Error()
+			return
+		}
+		check.StatusCode = authed.StatusCode
+		check.Reflected = authed.StatusCode >= 200 && authed.StatusCode < 300
+		if !check.Reflected {
+			return
+		}
+		anon, err := sendParsed(ctx, client, applyHeaderRules(r.String(), config.HeaderRules{Strip: []string{"Cookie", "Authorization"}}), t, 0)
+		if err != nil {
+			check.Error = err.Error()
+			return
+		}
+		check.Hit, check.Evidence = cacheHit(anon)
+		check.Poisoned = anon.StatusCode == authed.StatusCode && anon.Body == authed.Body && check.Hit
+	})
+	return checks
+}
+
+// cacheFindings reports poisoned entries first, then inputs that change the
+// response but weren't seen cached, then cache deception.
+func cacheFindings(out CacheProbeOutput) []string {
+	findings := []string{}
+	if out.Cached {
+		findings = append(findings, "Responses are cached ("+out.CacheEvidence+"); the cache key includes the query string")
+	} else {
+		findings = append(findings, "No cache hit on a repeat fetch: the page may not be cached, or the cache does not report hits")
+	}
+	for _, c := range out.Checks {
+		if c.Poisoned {
+			findings = append(findings, fmt.Sprintf("%s: unkeyed input poisons the cache: a request without it got the injected response (%s)", c.Change, c.Evidence))
+		}
+	}
+	for _, c := range out.Checks {
+		if c.Reflected && !c.Poisoned && c.Error == "" {
+			findings = append(findings, fmt.Sprintf("%s: changes the response (status %d) but the repeat fetch was not poisoned: candidate unkeyed input if the page is cached under other conditions", c.Change, c.StatusCode))
+		}
+	}
+	for _, c := range out.Deception {
+		if c.Poisoned {
+			findings = append(findings, fmt.Sprintf("%s: web cache deception: the authenticated page was served from cache to a request without the session (%s)", c.Change, c.Evidence))
+		}
+	}
+	if out.Deception == nil {
+		findings = append(findings, "Cache deception not tested: the request carries no Cookie or Authorization header")
+	}
+	return findings
+}
+
+// RegisterCacheProbeTool registers the burp_cache_probe tool.
+func RegisterCacheProbeTool(server *mcp.Server, client *burp.Client) {
+	addTool(server, &mcp.Tool{
+		Name: "burp_cache_probe",
+		Description: `Test for web cache poisoning and deception. Each unkeyed-header candidate (X-Forwarded-Host, X-Forwarded-Scheme, X-Original-URL, Origin, ...) is sent with its own cache buster, ` +
+			`then the same URL is fetched again without the header and checked for the injected value and a cache hit (X-Cache, CF-Cache-Status, Age). ` +
+			`With a session in the request, static-looking suffixes (/x.css, ;x.css) are fetched with and without it to detect cache deception. ` +
+			`Returns {cached, cacheEvidence, checks: [{name, change, statusCode, reflected, hit, poisoned, evidence}], deception, findings}.`,
+	}, cacheProbeHandler(client))
+}
@@ -0,0 +1,70 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/c0tton-fluff/burp-mcp-server/internal/burp"
+)
+
+func TestCacheHit(t *testing.T) {
+	for _, tc := range []struct {
+		resp, evidence string
+		hit            bool
+	}{
+		{"HTTP/1.1 200 OK\r\nX-Cache: Hit from cloudfront\r\n\r\n", "X-Cache: Hit from cloudfront", true},
+		{"HTTP/1.1 200 OK\r\nCF-Cache-Status: HIT\r\n\r\n", "CF-Cache-Status: HIT", true},
+		{"HTTP/1.1 200 OK\r\nAge: 42\r\n\r\n", "Age: 42", true},
+		{"HTTP/1.1 200 OK\r\nX-Cache: MISS\r\nAge: 0\r\n\r\n", "", false},
+		{"HTTP/1.1 200 OK\r\n\r\n", "", false},
+	} {
+		hit, evidence := cacheHit(burp.ParseHTTPResponse(tc.resp, 0, 0))
+		if hit != tc.hit || evidence != tc.evidence {
+			t.Errorf("%q: got %v %q, want %v %q", tc.resp, hit, evidence, tc.hit, tc.evidence)
+		}
+	}
+}
+
+func TestWithBuster(t *testing.T) {
+	got, err := withBuster("GET /page?a=1 HTTP/1.1\r\nHost: a.com\r\n\r\n", "cb", "abcd")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "GET /page?a=1&cb=abcd HTTP/1.1\r\nHost: a.com\r\n\r\n"; got != want {
+		t.Errorf("got %q\nwant %q", got, want)
+	}
+}
+
+func TestCacheFindings(t *testing.T) {
+	out := CacheProbeOutput{
+		Cached:        true,
+		CacheEvidence: "X-Cache: HIT",
+		Checks: []CacheCheck{
+			{Name: "x-forwarded-host", Change: "X-Forwarded-Host: cp1.evil.example", StatusCode: 200, Reflected: true, Hit: true, Poisoned: true, Evidence: "cp1 in a response fetched without the header"},
+			{Name: "x-forwarded-scheme", Change: "X-Forwarded-Scheme: http", StatusCode: 301, Reflected: true},
+			{Name: "x-host", Change: "X-Host: cp2.evil.example", StatusCode: 200},
+		},
+		Deception: []CacheCheck{
+			{Name: "deception /{canary}.css", Change: "path suffix /cd1.css", StatusCode: 200, Reflected: true, Hit: true, Poisoned: true, Evidence: "Age: 3"},
+		},
+	}
+	got := strings.Join(cacheFindings(out), "\n")
+	for _, want := range []string{
+		"Responses are cached (X-Cache: HIT)",
+		"X-Forwarded-Host: cp1.evil.example: unkeyed input poisons the cache",
+		"X-Forwarded-Scheme: http: changes the response (status 301)",
+		"path suffix /cd1.css: web cache deception",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("missing %q in:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "X-Host") {
+		t.Errorf("unexpected X-Host finding:\n%s", got)
+	}
+
+	out.Deception = nil
+	if got := strings.Join(cacheFindings(out), "\n"); !strings.Contains(got, "Cache deception not tested") {
+		t.Errorf("missing deception note in:\n%s", got)
+	}
+}
@@ -0,0 +1,131 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/c0tton-fluff/burp-mcp-server/internal/burp"
+)
+
+// collabPollInterval is the gap between Collaborator polls.
+const collabPollInterval = 3 * time.Second
+
+// CollaboratorInteraction is one out-of-band interaction Burp Collaborator
+// recorded: a DNS lookup, HTTP request, or SMTP message.
+type CollaboratorInteraction struct {
+	ID        string `json:"id"`
+	Type      string `json:"type"`
+	Timestamp string `json:"timestamp,omitempty"`
+	ClientIP  string `json:"clientIp,omitempty"`
+	// Detail is the DNS query, HTTP request, or SMTP conversation.
+	Detail string `json:"detail,omitempty"`
+}
+
+// generateCollaboratorPayload asks Burp for a fresh Collaborator domain.
+// Collaborator needs Burp Professional.
+func generateCollaboratorPayload(ctx context.Context, client *burp.Client) (payload, id string, err error) {
+	text, err := client.CallTool(ctx, "generate_collaborator_payload", map[string]any{})
+	if err != nil {
+		return "", "", fmt.Errorf("generate Collaborator payload (needs Burp Professional): %w", err)
+	}
+	for _, line := range strings.Split(text, "\n") {
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		switch strings.TrimSpace(key) {
+		case "Payload":
+			payload = strings.TrimSpace(value)
+		case "Payload ID":
+			id = strings.TrimSpace(value)
+		}
+	}
+	if payload == "" {
+		return "", "", fmt.Errorf("unexpected Collaborator response: %s", text)
+	}
+	return payload, id, nil
+}
+
+// collaboratorInteractions polls Burp for interactions with the payload id.
+func collaboratorInteractions(ctx context.Context, client *burp.Client, id string) ([]CollaboratorInteraction, error) {
+	args := map[string]any{}
+	if id != "" {
+		args["payloadId"] = id
+	}
+	text, err := client.CallTool(ctx, "get_collaborator_interactions", args)
+	if err != nil {
+		return nil, fmt.Errorf("poll Collaborator: %w", err)
+	}
+	return parseCollaboratorInteractions(text), nil
+}
+
+// parseCollaboratorInteractions splits Burp's interaction listing. Each
+// interaction starts at an "Interaction ID:" line; lines other than the
+// summary fields, HTTP requests included, are kept as detail.
+func parseCollaboratorInteractions(text string) []CollaboratorInteraction {
+	var out []CollaboratorInteraction
+	var detail []string
+	flush := func() {
+		if len(out) > 0 {
+			out[len(out)-1].Detail = strings.TrimSpace(strings.Join(detail, "\n"))
+		}
+		detail = nil
+	}
+	for _, line := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		key, value, _ := strings.Cut(line, ":")
+		value = strings.TrimSpace(value)
+		switch key {
+		case "Interaction ID":
+			flush()
+			out = append(out, CollaboratorInteraction{ID: value})
+			continue
+		}
+		if len(out) == 0 {
+			continue
+		}
+		cur := &out[len(out)-1]
+		switch key {
+		case "Type":
+			cur.Type = value
+		case "Timestamp":
+			cur.Timestamp = value
+		case "Client IP":
+			cur.ClientIP = value
+		case "Client Port":
+			// Ephemeral; not worth reporting.
+		default:
+			detail = append(detail, line)
+		}
+	}
+	flush()
+	return out
+}
+
+// pollInteractions calls fetch, e.g. collaboratorInteractions, until wait
+// elapses or done reports every expected interaction has arrived.
+// Interactions are accumulated across polls, since a poll may return only
+// what is new.
+func pollInteractions(ctx context.Context, fetch func(context.Context) ([]CollaboratorInteraction, error), wait time.Duration, done func([]CollaboratorInteraction) bool) ([]CollaboratorInteraction, error) {
+	deadline := time.Now().Add(wait)
+	var all []CollaboratorInteraction
+	seen := map[CollaboratorInteraction]bool{}
+	for {
+		hits, err := fetch(ctx)
+		for _, h := range hits {
+			if !seen[h] {
+				seen[h] = true
+				all = append(all, h)
+			}
+		}
+		if err != nil || done(all) || time.Now().Add(collabPollInterval).After(deadline) {
+			return all, err
+		}
+		select {
+		case <-ctx.Done():
+			return all, ctx.Err()
+		case <-time.After(collabPollInterval):
+		}
+	}
+}
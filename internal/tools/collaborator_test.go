@@ -0,0 +1,42 @@
+package tools
+
+import "testing"
+
+func TestParseCollaboratorInteractions(t *testing.T) {
+	text := "Interaction ID: abc123\n" +
+		"Type: DNS\n" +
+		"Timestamp: 2026-10-15T06:00:00Z\n" +
+		"Client IP: 198.51.100.7\n" +
+		"Client Port: 53124\n" +
+		"DNS Query Type: A\n" +
+		"DNS Query: k1-00.abc123.oastify.com\n" +
+		"\n" +
+		"Interaction ID: abc123\n" +
+		"Type: HTTP\n" +
+		"Client IP: 198.51.100.8\n" +
+		"HTTP Protocol: HTTP\n" +
+		"HTTP Request: GET /k1-01 HTTP/1.1\r\n" +
+		"Host: k1-01.abc123.oastify.com\r\n" +
+		"\r\n"
+	got := parseCollaboratorInteractions(text)
+	if len(got) != 2 {
+		t.Fatalf("got %d interactions: %+v", len(got), got)
+	}
+	want0 := CollaboratorInteraction{
+		ID:        "abc123",
+		Type:      "DNS",
+		Timestamp: "2026-10-15T06:00:00Z",
+		ClientIP:  "198.51.100.7",
+		Detail:    "DNS Query Type: A\nDNS Query: k1-00.abc123.oastify.com",
+	}
+	if got[0] != want0 {
+		t.Errorf("got[0] = %+v\nwant %+v", got[0], want0)
+	}
+	if got[1].Type != "HTTP" || got[1].Detail != "HTTP Protocol: HTTP\nHTTP Request: GET /k1-01 HTTP/1.1\nHost: k1-01.abc123.oastify.com" {
+		t.Errorf("got[1] = %+v", got[1])
+	}
+
+	if got := parseCollaboratorInteractions("No interactions detected"); len(got) != 0 {
+		t.Errorf("empty poll parsed as %+v", got)
+	}
+}
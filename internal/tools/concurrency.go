@@ -0,0 +1,112 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/c0tton-fluff/burp-mcp-server/internal/config"
+)
+
+// QueueFullError is returned when a host's concurrency cap is reached and
+// its queue is full. Nothing is sent.
+type QueueFullError struct {
+	Host  string
+	Limit int
+	Queue int
+}
+
+func (e *QueueFullError) Error() string {
+	return fmt.Sprintf("rate_limited: %d requests to %s in flight and %d queued; retry later", e.Limit, e.Host, e.Queue)
+}
+
+// hostSlots is one host's semaphore and the number of requests waiting on it.
+type hostSlots struct {
+	slots   chan struct{}
+	waiting int
+}
+
+// hostGates enforces the configured per-host concurrency caps.
+type hostGates struct {
+	mu    sync.Mutex
+	cfg   config.ConcurrencyLimits
+	hosts map[string]*hostSlots
+}
+
+func newHostGates(cfg config.ConcurrencyLimits) *hostGates {
+	return &hostGates{cfg: cfg, hosts: make(map[string]*hostSlots)}
+}
+
+// concurrency is the server-wide concurrency gate, rebuilt by Configure.
+var concurrency = newHostGates(config.ConcurrencyLimits{})
+
+// acquire takes a slot for a request to host, waiting in the host's queue
+// while the cap is reached. The returned release gives the slot back.
+func (g *hostGates) acquire(ctx context.Context, host string) (func(), error) {
+	host = strings.ToLower(host)
+	limit := g.cfg.Limit(host)
+	if limit <= 0 {
+		return func() {}, nil
+	}
+
+	g.mu.Lock()
+	h, ok := g.hosts[host]
+	if !ok {
+		h = &hostSlots{slots: make(chan struct{}, limit)}
+		g.hosts[host] = h
+	}
+	release := func() { <-h.slots }
+	select {
+	case h.slots <- struct{}{}:
+		g.mu.Unlock()
+		return release, nil
+	default:
+	}
+	maxQueue := g.cfg.MaxQueue
+	if maxQueue == 0 {
+		maxQueue = config.DefaultMaxQueue
+	}
+	if h.waiting >= maxQueue {
+		g.mu.Unlock()
+		return nil, &QueueFullError{Host: host, Limit: limit, Queue: h.waiting}
+	}
+	h.waiting++
+	g.mu.Unlock()
+
+	start := time.Now()
+	defer func() {
+		g.mu.Lock()
+		h.waiting--
+		g.mu.Unlock()
+		addQueueWait(ctx, time.Since(start))
+	}()
+	select {
+	case h.slots <- struct{}{}:
+		return release, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// acquireHost takes a concurrency slot for a request to t.
+func acquireHost(ctx context.Context, t resolvedTarget) (func(), error) {
+	return concurrency.acquire(ctx, t.Host)
+}
+
+type queueWaitKey struct{}
+
+// withQueueWait returns a context that sums the time requests under it
+// spent queued for a concurrency slot, and a function that reports it.
+func withQueueWait(ctx context.Context) (context.Context, func() time.Duration) {
+	d := new(atomic.Int64)
+	return context.WithValue(ctx, queueWaitKey{}, d), func() time.Duration { return time.Duration(d.Load()) }
+}
+
+func addQueueWait(ctx context.Context, d time.Duration) {
+	if total, ok := ctx.Value(queueWaitKey{}).(*atomic.Int64); ok {
+		total.Add(int64(d))
+	}
+}
@@ -0,0 +1,85 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/c0tton-fluff/burp-mcp-server/internal/config"
+)
+
+func TestHostGates_Queue(t *testing.T) {
+	g := newHostGates(config.ConcurrencyLimits{PerHost: 1, MaxQueue: 1})
+	release, err := g.acquire(context.Background(), "a.test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The second request queues until the first releases its slot.
+	ctx, queueWait := withQueueWait(context.Background())
+	acquired := make(chan func())
+	go func() {
+		r, err := g.acquire(ctx, "A.test")
+		if err != nil {
+			t.Error(err)
+		}
+		acquired <- r
+	}()
+	for {
+		g.mu.Lock()
+		waiting := g.hosts["a.test"].waiting
+		g.mu.Unlock()
+		if waiting == 1 {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	// The queue holds one: a third request fails fast.
+	_, err = g.acquire(context.Background(), "a.test")
+	var qf *QueueFullError
+	if !errors.As(err, &qf) || qf.Limit != 1 || qf.Queue != 1 {
+		t.Fatalf("expected QueueFullError, got %v", err)
+	}
+	if classifyError(err).Code != CodeRateLimited {
+		t.Errorf("code = %s, want %s", classifyError(err).Code, CodeRateLimited)
+	}
+
+	// Other hosts are not affected.
+	other, err := g.acquire(context.Background(), "b.test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	other()
+
+	time.Sleep(20 * time.Millisecond)
+	release()
+	(<-acquired)()
+	if w := queueWait(); w < 20*time.Millisecond {
+		t.Errorf("queue wait = %v, want at least 20ms", w)
+	}
+}
+
+func TestHostGates_Canceled(t *testing.T) {
+	g := newHostGates(config.ConcurrencyLimits{PerHost: 1})
+	release, err := g.acquire(context.Background(), "a.test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer release()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := g.acquire(ctx, "a.test"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected deadline exceeded, got %v", err)
+	}
+}
+
+func TestHostGates_Unlimited(t *testing.T) {
+	g := newHostGates(config.ConcurrencyLimits{Hosts: map[string]int{"a.test": 1}})
+	for range 3 {
+		if _, err := g.acquire(context.Background(), "b.test"); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,222 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/c0tton-fluff/burp-mcp-server/internal/burp"
+	"github.com/c0tton-fluff/burp-mcp-server/internal/config"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// mismatchedETag is an ETag no resource has, to see whether If-None-Match
+// values are compared at all.
+const mismatchedETag = `"burp-mcp-mismatch"`
+
+// conditionalHeaders are stripped from the baseline so it captures fresh validators.
+var conditionalHeaders = []string{"If-None-Match", "If-Modified-Since", "If-Match", "If-Unmodified-Since"}
+
+// ConditionalProbeInput is the input for burp_conditional_probe.
+type ConditionalProbeInput struct {
+	Raw           string `json:"raw" jsonschema:"required,Raw HTTP request for the resource under test"`
+	OtherRaw      string `json:"otherRaw,omitempty" jsonschema:"Same resource requested as a different user, to test validators across user boundaries"`
+	Host          string `json:"host,omitempty" jsonschema:"Target host (overrides Host header)"`
+	Port          int    `json:"port,omitempty" jsonschema:"Target port (default based on TLS)"`
+	TLS           *bool  `json:"tls,omitempty" jsonschema:"Use HTTPS (default true)"`
+	HeaderProfile string `json:"headerProfile,omitempty" jsonschema:"Header rule profile from config (default: 'default')"`
+	Instance      string `json:"instance,omitempty" jsonschema:"Named Burp instance from config (default: the --burp-url connection)"`
+}
+
+// ConditionalValidators are the cache validators captured from the baseline response.
+type ConditionalValidators struct {
+	ETag         string `json:"etag,omitempty"`
+	WeakETag     bool   `json:"weakEtag,omitempty"`
+	LastModified string `json:"lastModified,omitempty"`
+}
+
+// ConditionalCheck is one replayed request and its outcome.
+type ConditionalCheck struct {
+	Name       string `json:"name"`
+	Header     string `json:"header,omitempty"`
+	StatusCode int    `json:"statusCode"`
+	BodySize   int    `json:"bodySize"`
+	ETag       string `json:"etag,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+// ConditionalProbeOutput is the output of burp_conditional_probe.
+type ConditionalProbeOutput struct {
+	Validators ConditionalValidators `json:"validators"`
+	Checks     []ConditionalCheck    `json:"checks"`
+	Findings   []string              `json:"findings"`
+}
+
+func conditionalProbeHandler(client *burp.Client) func(context.Context, *mcp.CallToolRequest, ConditionalProbeInput) (*mcp.CallToolResult, ConditionalProbeOutput, error) {
+	return func(ctx context.Context, _ *mcp.CallToolRequest, input ConditionalProbeInput) (*mcp.CallToolResult, ConditionalProbeOutput, error) {
+		ctx = burp.WithInstance(ctx, input.Instance)
+
+		rawNorm, parsed, err := prepareRequest(input.Raw, input.HeaderProfile)
+		if err != nil {
+			return nil, ConditionalProbeOutput{}, err
+		}
+		t, err := resolveTarget(input.Host, input.Port, input.TLS, parsed.Host)
+		if err != nil {
+			return nil, ConditionalProbeOutput{}, err
+		}
+
+		base := applyHeaderRules(rawNorm, config.HeaderRules{Strip: conditionalHeaders})
+		baseResp, err := sendParsed(ctx, client, base, t, 1)
+		if err != nil {
+			return nil, ConditionalProbeOutput{}, fmt.Errorf("baseline request failed: %w", err)
+		}
+
+		v := parseValidators(baseResp.Headers)
+		checks := []ConditionalCheck{checkFromResponse("baseline", "", baseResp)}
+
+		send := func(name, raw, header, value string) ConditionalCheck {
+			req := applyHeaderRules(raw, config.HeaderRules{Set: map[string]string{header: value}})
+			resp, err := sendParsed(ctx, client, req, t, 1)
+			if err != nil {
+				return ConditionalCheck{Name: name, Header: header + ": " + value, Error: err.Error()}
+			}
+			return checkFromResponse(name, header+": "+value, resp)
+		}
+
+		if v.ETag != "" {
+			checks = append(checks, send("if-none-match", base, "If-None-Match", v.ETag))
+			checks = append(checks, send("if-none-match-flipped-weakness", base, "If-None-Match", flipWeakness(v.ETag)))
+			checks = append(checks, send("if-none-match-mismatch", base, "If-None-Match", mismatchedETag))
+		}
+		checks = append(checks, send("if-none-match-star", base, "If-None-Match", "*"))
+		if v.LastModified != "" {
+			checks = append(checks, send("if-modified-since", base, "If-Modified-Since", v.LastModified))
+		}
+
+		var other []ConditionalCheck
+		if input.OtherRaw != "" {
+			otherNorm, _, err := prepareRequest(input.OtherRaw, input.HeaderProfile)
+			if err != nil {
+				return nil, ConditionalProbeOutput{}, fmt.Errorf("otherRaw: %w", err)
+			}
+			otherBase := applyHeaderRules(otherNorm, config.HeaderRules{Strip: conditionalHeaders})
+			if resp, err := sendParsed(ctx, client, otherBase, t, 1); err != nil {
+				other = append(other, ConditionalCheck{Name: "other-baseline", Error: err.Error()})
+			} else {
+				other = append(other, checkFromResponse("other-baseline", "", resp))
+			}
+			if v.ETag != "" {
+				other = append(other, send("other-if-none-match", otherBase, "If-None-Match", v.ETag))
+			}
+			checks = append(checks, other...)
+		}
+
+		return nil, ConditionalProbeOutput{
+			Validators: v,
+			Checks:     checks,
+			Findings:   conditionalFindings(v, checks, other),
+		}, nil
+	}
+}
+
+// parseValidators extracts ETag and Last-Modified from response headers.
+func parseValidators(headers map[string][]string) ConditionalValidators {
+	etag := burp.GetHeader(headers, "ETag")
+	return ConditionalValidators{
+		ETag:         etag,
+		WeakETag:     strings.HasPrefix(etag, "W/"),
+		LastModified: burp.GetHeader(headers, "Last-Modified"),
+	}
+}
+
+// flipWeakness turns a strong ETag into its weak form and vice versa.
+func flipWeakness(etag string) string {
+	if strings.HasPrefix(etag, "W/") {
+		return strings.TrimPrefix(etag, "W/")
+	}
+	return "W/" + etag
+}
+
+func checkFromResponse(name, header string, resp *burp.ParsedHTTPResponse) ConditionalCheck {
+	return ConditionalCheck{
+		Name:       name,
+		Header:     header,
+		StatusCode: resp.StatusCode,
+		BodySize:   resp.BodySize,
+		ETag:       burp.GetHeader(resp.Headers, "ETag"),
+	}
+}
+
+// conditionalFindings interprets the checks. other holds the cross-user checks, if any.
+func conditionalFindings(v ConditionalValidators, checks, other []ConditionalCheck) []string {
+	findings := []string{}
+	byName := make(map[string]ConditionalCheck, len(checks))
+	for _, c := range checks {
+		byName[c.Name] = c
+	}
+
+	// A GET or HEAD of an existing resource fails If-None-Match: *, so a
+	// successful baseline should turn into a 304.
+	base := byName["baseline"]
+	if c, ok := byName["if-none-match-star"]; ok && c.Error == "" && base.StatusCode/100 == 2 && c.StatusCode/100 == 2 {
+		findings = append(findings, fmt.Sprintf("If-None-Match: * returned %d, not 304, though the resource exists: the wildcard is ignored", c.StatusCode))
+	}
+	if v.ETag == "" && v.LastModified == "" {
+		return append(findings, "No validators (ETag/Last-Modified) on the baseline response")
+	}
+
+	exact, ok := byName["if-none-match"]
+	if ok && exact.StatusCode != 304 && exact.Error == "" {
+		findings = append(findings, fmt.Sprintf("If-None-Match with the current ETag returned %d, not 304: validators are ignored or the ETag is unstable", exact.StatusCode))
+	}
+	// If-None-Match compares weakly, so W/"x" and "x" should both match.
+	if c, ok := byName["if-none-match-flipped-weakness"]; ok && c.Error == "" && exact.StatusCode == 304 && c.StatusCode != 304 {
+		findings = append(findings, fmt.Sprintf("If-None-Match with the ETag's weakness flipped (%s) returned %d while the exact ETag got 304: the server compares strongly, so caches holding the other form always refetch", c.Header, c.StatusCode))
+	}
+	if c, ok := byName["if-none-match-mismatch"]; ok && c.Error == "" && c.StatusCode == 304 {
+		findings = append(findings, "If-None-Match with an ETag the resource doesn't have returned 304: the server answers conditionals without comparing validators, so clients keep stale content and 304s prove nothing about a representation")
+	}
+	if c, ok := byName["if-modified-since"]; ok && c.StatusCode != 304 && c.Error == "" {
+		findings = append(findings, fmt.Sprintf("If-Modified-Since with Last-Modified returned %d, not 304", c.StatusCode))
+	}
+	if v.WeakETag {
+		findings = append(findings, "ETag is weak (W/): semantically equivalent representations share a validator, so per-user differences may not change it")
+	}
+
+	if len(other) == 0 {
+		return findings
+	}
+	var otherBase, otherCond *ConditionalCheck
+	for i := range other {
+		switch other[i].Name {
+		case "other-baseline":
+			otherBase = &other[i]
+		case "other-if-none-match":
+			otherCond = &other[i]
+		}
+	}
+	if otherBase != nil && otherBase.Error == "" && v.ETag != "" && otherBase.ETag == v.ETag {
+		if otherBase.BodySize != base.BodySize {
+			findings = append(findings, fmt.Sprintf("Both users received ETag %s for bodies of different size (%d vs %d): the validator doesn't vary by user, so caches can serve one user's content to another", v.ETag, base.BodySize, otherBase.BodySize))
+		} else {
+			findings = append(findings, fmt.Sprintf("Both users received identical ETag %s: confirm the content is not user-specific", v.ETag))
+		}
+	}
+	// A 304 is only an oracle when the other user's own representation
+	// differs; for a shared resource it is the right answer.
+	if otherCond != nil && otherCond.StatusCode == 304 && otherBase != nil && otherBase.Error == "" &&
+		(otherBase.ETag != v.ETag || otherBase.BodySize != base.BodySize) {
+		findings = append(findings, "The other user got 304 for the first user's ETag: validators act as an oracle confirming another user's representation")
+	}
+	return findings
+}
+
+// RegisterConditionalProbeTool registers the burp_conditional_probe tool.
+func RegisterConditionalProbeTool(server *mcp.Server, client *burp.Client) {
+	addTool(server, &mcp.Tool{
+		Name: "burp_conditional_probe",
+		Description: `Test ETag/If-None-Match and Last-Modified/If-Modified-Since handling for an endpoint. ` +
+			`Captures validators, replays conditionals (the exact ETag, its weakness flipped, a mismatched ETag, *, and Last-Modified), and with otherRaw (same resource as another user) tests validators across user boundaries. ` +
+			`Returns {validators, checks: [{name, header, statusCode, bodySize, etag}], findings}.`,
+	}, conditionalProbeHandler(client))
+}
@@ -0,0 +1,121 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseValidators(t *testing.T) {
+	v := parseValidators(map[string][]string{
+		"etag":          {`W/"abc"`},
+		"Last-Modified": {"Wed, 21 Oct 2015 07:28:00 GMT"},
+	})
+	if v.ETag != `W/"abc"` || !v.WeakETag || v.LastModified == "" {
+		t.Errorf("got %+v", v)
+	}
+}
+
+func TestFlipWeakness(t *testing.T) {
+	if got := flipWeakness(`"abc"`); got != `W/"abc"` {
+		t.Errorf("strong -> %q", got)
+	}
+	if got := flipWeakness(`W/"abc"`); got != `"abc"` {
+		t.Errorf("weak -> %q", got)
+	}
+}
+
+func TestConditionalFindings_NoValidators(t *testing.T) {
+	got := conditionalFindings(ConditionalValidators{}, nil, nil)
+	if len(got) != 1 || !strings.Contains(got[0], "No validators") {
+		t.Errorf("got %v", got)
+	}
+}
+
+func TestConditionalFindings_CrossUserOracle(t *testing.T) {
+	v := ConditionalValidators{ETag: `"abc"`}
+	checks := []ConditionalCheck{
+		{Name: "baseline", StatusCode: 200, BodySize: 100, ETag: `"abc"`},
+		{Name: "if-none-match", StatusCode: 304},
+	}
+	other := []ConditionalCheck{
+		{Name: "other-baseline", StatusCode: 200, BodySize: 80, ETag: `"abc"`},
+		{Name: "other-if-none-match", StatusCode: 304},
+	}
+	got := strings.Join(conditionalFindings(v, append(checks, other...), other), "\n")
+	if !strings.Contains(got, "doesn't vary by user") {
+		t.Errorf("missing shared-validator finding: %s", got)
+	}
+	if !strings.Contains(got, "oracle") {
+		t.Errorf("missing oracle finding: %s", got)
+	}
+}
+
+func TestConditionalFindings_SharedResource(t *testing.T) {
+	v := ConditionalValidators{ETag: `"abc"`}
+	checks := []ConditionalCheck{
+		{Name: "baseline", StatusCode: 200, BodySize: 100, ETag: `"abc"`},
+		{Name: "if-none-match", StatusCode: 304},
+	}
+	other := []ConditionalCheck{
+		{Name: "other-baseline", StatusCode: 200, BodySize: 100, ETag: `"abc"`},
+		{Name: "other-if-none-match", StatusCode: 304},
+	}
+	got := strings.Join(conditionalFindings(v, append(checks, other...), other), "\n")
+	if strings.Contains(got, "oracle") {
+		t.Errorf("oracle reported for a shared resource: %s", got)
+	}
+
+	other[0].ETag = `"def"`
+	got = strings.Join(conditionalFindings(v, append(checks, other...), other), "\n")
+	if !strings.Contains(got, "oracle") {
+		t.Errorf("missing oracle finding for a per-user ETag: %s", got)
+	}
+}
+
+func TestConditionalFindings_IgnoredValidator(t *testing.T) {
+	v := ConditionalValidators{ETag: `"abc"`}
+	checks := []ConditionalCheck{
+		{Name: "baseline", StatusCode: 200},
+		{Name: "if-none-match", StatusCode: 200},
+	}
+	got := conditionalFindings(v, checks, nil)
+	if len(got) != 1 || !strings.Contains(got[0], "not 304") {
+		t.Errorf("got %v", got)
+	}
+}
+
+func TestConditionalFindings_ComparisonChecks(t *testing.T) {
+	v := ConditionalValidators{ETag: `"abc"`}
+	checks := []ConditionalCheck{
+		{Name: "baseline", StatusCode: 200},
+		{Name: "if-none-match", StatusCode: 304},
+		{Name: "if-none-match-flipped-weakness", Header: `If-None-Match: W/"abc"`, StatusCode: 200},
+		{Name: "if-none-match-mismatch", StatusCode: 304},
+		{Name: "if-none-match-star", StatusCode: 200},
+	}
+	got := strings.Join(conditionalFindings(v, checks, nil), "\n")
+	for _, want := range []string{"compares strongly", "without comparing validators", "wildcard is ignored"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("missing %q finding: %s", want, got)
+		}
+	}
+
+	checks = []ConditionalCheck{
+		{Name: "baseline", StatusCode: 200},
+		{Name: "if-none-match", StatusCode: 304},
+		{Name: "if-none-match-flipped-weakness", StatusCode: 304},
+		{Name: "if-none-match-mismatch", StatusCode: 200},
+		{Name: "if-none-match-star", StatusCode: 304},
+	}
+	if got := conditionalFindings(v, checks, nil); len(got) != 0 {
+		t.Errorf("compliant server: %v", got)
+	}
+
+	got = strings.Join(conditionalFindings(ConditionalValidators{}, []ConditionalCheck{
+		{Name: "baseline", StatusCode: 200},
+		{Name: "if-none-match-star", StatusCode: 200},
+	}, nil), "\n")
+	if !strings.Contains(got, "wildcard is ignored") || !strings.Contains(got, "No validators") {
+		t.Errorf("without validators: %s", got)
+	}
+}
@@ -0,0 +1,19 @@
+package tools
+
+import "github.com/c0tton-fluff/burp-mcp-server/internal/config"
+
+// settings is the server-wide configuration used by all tools.
+// Installed once at startup via Configure, before any tool is registered.
+var settings = &config.Config{}
+
+// Configure installs the server configuration. A nil config resets to defaults.
+func Configure(cfg *config.Config) {
+	if cfg == nil {
+		cfg = &config.Config{}
+	}
+	settings = cfg
+	limiter = newRateLimiter(cfg.RateLimit)
+	concurrency = newHostGates(cfg.Concurrency)
+	targetScope = newScopeMatcher(cfg.Scope)
+	gate = newApprovalGate(cfg.Approval, cfg.ApprovalDir())
+}
@@ -0,0 +1,145 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/c0tton-fluff/burp-mcp-server/internal/burp"
+	"github.com/c0tton-fluff/burp-mcp-server/internal/config"
+)
+
+const (
+	// maxIdlePerTarget caps the kept-alive connections per pool key.
+	maxIdlePerTarget = 4
+	// poolIdleTimeout is how long an unused connection stays in the pool.
+	// Servers often close idle connections sooner; a send that finds its
+	// connection closed retries on a fresh one.
+	poolIdleTimeout = 30 * time.Second
+)
+
+// poolKey identifies connections that can carry each other's requests:
+// same target, connect address, TLS server name, and TLS options.
+type poolKey struct {
+	target           resolvedTarget
+	addr, serverName string
+	tls              string
+}
+
+func newPoolKey(t resolvedTarget, addr, serverName string, opts config.TLSOptions) poolKey {
+	key := poolKey{target: t, addr: addr, serverName: serverName}
+	if t.UseTLS {
+		key.tls = fmt.Sprintf("%q", opts)
+	}
+	return key
+}
+
+type idleConn struct {
+	c     *directConn
+	since time.Time
+}
+
+// connPool keeps direct connections alive between sends, so iterative
+// loops skip the TCP and TLS handshakes and tests can rely on connection
+// state. Each connection serves one request at a time.
+type connPool struct {
+	mu   sync.Mutex
+	idle map[poolKey][]idleConn
+}
+
+var directPool = &connPool{idle: make(map[poolKey][]idleConn)}
+
+// get takes the most recently used live connection for key, bound to ctx,
+// or returns nil.
+func (p *connPool) get(ctx context.Context, key poolKey) *directConn {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	conns := p.idle[key]
+	for len(conns) > 0 {
+		ic := conns[len(conns)-1]
+		conns = conns[:len(conns)-1]
+		if time.Since(ic.since) > poolIdleTimeout {
+			ic.c.conn.Close()
+			continue
+		}
+		p.idle[key] = conns
+		ic.c.bind(ctx)
+		return ic.c
+	}
+	delete(p.idle, key)
+	return nil
+}
+
+// put returns c to the pool after a round trip of raw that gave resp and
+// err, or closes it if the exchange leaves it unusable.
+func (p *connPool) put(key poolKey, c *directConn, raw []byte, resp string, err error) {
+	if !c.stop() || err != nil || !keepAlive(raw, resp, c.framed) || c.reader.Buffered() > 0 {
+		c.conn.Close()
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	conns := append(p.idle[key], idleConn{c: c, since: time.Now()})
+	for len(conns) > maxIdlePerTarget {
+		conns[0].c.conn.Close()
+		conns = conns[1:]
+	}
+	p.idle[key] = conns
+}
+
+// closeIdle closes every pooled connection.
+func (p *connPool) closeIdle() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for key, conns := range p.idle {
+		for _, ic := range conns {
+			ic.c.conn.Close()
+		}
+		delete(p.idle, key)
+	}
+}
+
+// keepAlive reports whether the connection is reusable after the exchange:
+// neither side asked to close it, and the response ended where its framing
+// says, so the next read starts at the next response.
+func keepAlive(raw []byte, resp string, framed bool) bool {
+	req := burp.ParseRawRequest(string(raw))
+	if hasToken(burp.GetHeader(req.Headers, "Connection"), "close") {
+		return false
+	}
+	parsed := burp.ParseHTTPResponse(resp, 0, 0)
+	if parsed == nil || parsed.StatusCode == 0 {
+		return false
+	}
+	conn := burp.GetHeader(parsed.Headers, "Connection")
+	if hasToken(conn, "close") || (strings.HasPrefix(resp, "HTTP/1.0") && !hasToken(conn, "keep-alive")) {
+		return false
+	}
+	if framed {
+		return true
+	}
+	// Responses without a body need no framing.
+	return req.Method == "HEAD" || parsed.StatusCode == 204 || parsed.StatusCode == 304
+}
+
+// hasToken reports whether the comma-separated header value lists token.
+func hasToken(value, token string) bool {
+	for _, v := range strings.Split(value, ",") {
+		if strings.EqualFold(strings.TrimSpace(v), token) {
+			return true
+		}
+	}
+	return false
+}
+
+// staleConn reports whether err means a pooled connection was closed by
+// the server while idle.
+func staleConn(err error) bool {
+	return errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.EPIPE)
+}
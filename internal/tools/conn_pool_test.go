@@ -0,0 +1,152 @@
+package tools
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSendDirect_ReusesConnection(t *testing.T) {
+	t.Cleanup(directPool.closeIdle)
+	var mu sync.Mutex
+	addrs := map[string]int{}
+	target := startTestTarget(t, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		addrs[r.RemoteAddr]++
+		mu.Unlock()
+		if r.URL.Path == "/close" {
+			w.Header().Set("Connection", "close")
+		}
+		w.Write([]byte("ok"))
+	})
+	send := func(path string, opts directOptions) {
+		t.Helper()
+		resp, err := sendDirect(context.Background(), target, []byte("GET "+path+" HTTP/1.1\r\nHost: x\r\n\r\n"), opts)
+		if err != nil {
+			t.Fatalf("send %s: %q, %v", path, resp, err)
+		}
+	}
+	conns := func() int {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(addrs)
+	}
+
+	send("/a", directOptions{})
+	send("/b", directOptions{})
+	if n := conns(); n != 1 {
+		t.Fatalf("two sends used %d connections, want 1", n)
+	}
+	send("/c", directOptions{NewConnection: true})
+	if n := conns(); n != 2 {
+		t.Fatalf("newConnection used %d connections in total, want 2", n)
+	}
+
+	// A response that closes the connection keeps it out of the pool.
+	send("/close", directOptions{})
+	send("/d", directOptions{})
+	if n := conns(); n != 3 {
+		t.Errorf("send after Connection: close used %d connections in total, want 3", n)
+	}
+
+	// Exact bytes and custom framing may leave a desync behind.
+	send("/e", directOptions{NoReuse: true})
+	send("/f", directOptions{})
+	if n := conns(); n != 4 {
+		t.Errorf("send after a noReuse send used %d connections in total, want 4", n)
+	}
+}
+
+func TestSendDirect_StalePooledConnection(t *testing.T) {
+	t.Cleanup(directPool.closeIdle)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	}))
+	t.Cleanup(srv.Close)
+	addr := srv.Listener.Addr().(*net.TCPAddr)
+	target := resolvedTarget{Host: addr.IP.String(), Port: addr.Port}
+
+	raw := []byte("GET / HTTP/1.1\r\nHost: x\r\n\r\n")
+	if _, err := sendDirect(context.Background(), target, raw, directOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	// The server drops the idle connection left in the pool.
+	srv.CloseClientConnections()
+	time.Sleep(50 * time.Millisecond)
+
+	resp, err := sendDirect(context.Background(), target, raw, directOptions{})
+	if err != nil || !strings.HasSuffix(resp, "ok") {
+		t.Fatalf("send after the server closed the pooled connection = %q, %v; want a retry on a fresh one", resp, err)
+	}
+}
+
+func TestSendDirect_StaleConnectionNotRetriedForPOST(t *testing.T) {
+	t.Cleanup(directPool.closeIdle)
+	var mu sync.Mutex
+	posts := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "POST" {
+			mu.Lock()
+			posts++
+			mu.Unlock()
+		}
+		w.Write([]byte("ok"))
+	}))
+	t.Cleanup(srv.Close)
+	addr := srv.Listener.Addr().(*net.TCPAddr)
+	target := resolvedTarget{Host: addr.IP.String(), Port: addr.Port}
+
+	if _, err := sendDirect(context.Background(), target, []byte("GET / HTTP/1.1\r\nHost: x\r\n\r\n"), directOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	srv.CloseClientConnections()
+	time.Sleep(50 * time.Millisecond)
+
+	// The server may have handled a request before dropping the connection,
+	// so a POST isn't sent again.
+	if _, err := sendDirect(context.Background(), target, []byte("POST /pay HTTP/1.1\r\nHost: x\r\nContent-Length: 0\r\n\r\n"), directOptions{}); err == nil {
+		t.Error("POST on a stale pooled connection was retried")
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if posts != 0 {
+		t.Errorf("server saw %d POSTs, want 0", posts)
+	}
+}
+
+func TestIdempotent(t *testing.T) {
+	for raw, want := range map[string]bool{"GET / HTTP/1.1": true, "HEAD /": true, "OPTIONS *": true, "POST /": false, "DELETE /x": false, "": false} {
+		if got := idempotent([]byte(raw)); got != want {
+			t.Errorf("idempotent(%q) = %v, want %v", raw, got, want)
+		}
+	}
+}
+
+func TestKeepAlive(t *testing.T) {
+	get := []byte("GET / HTTP/1.1\r\nHost: x\r\n\r\n")
+	tests := []struct {
+		name   string
+		raw    []byte
+		resp   string
+		framed bool
+		want   bool
+	}{
+		{"framed", get, "HTTP/1.1 200 OK\r\nContent-Length: 2\r\n\r\nok", true, true},
+		{"unframed", get, "HTTP/1.1 200 OK\r\n\r\nok", false, false},
+		{"no content", get, "HTTP/1.1 204 No Content\r\n\r\n", false, true},
+		{"response close", get, "HTTP/1.1 200 OK\r\nConnection: close\r\nContent-Length: 0\r\n\r\n", true, false},
+		{"request close", []byte("GET / HTTP/1.1\r\nConnection: close\r\n\r\n"), "HTTP/1.1 200 OK\r\nContent-Length: 0\r\n\r\n", true, false},
+		{"http/1.0", get, "HTTP/1.0 200 OK\r\nContent-Length: 0\r\n\r\n", true, false},
+		{"http/1.0 keep-alive", get, "HTTP/1.0 200 OK\r\nConnection: Keep-Alive\r\nContent-Length: 0\r\n\r\n", true, true},
+	}
+	for _, tt := range tests {
+		if got := keepAlive(tt.raw, tt.resp, tt.framed); got != tt.want {
+			t.Errorf("%s: keepAlive() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
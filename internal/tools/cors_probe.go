@@ -0,0 +1,232 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+
+	"github.com/c0tton-fluff/burp-mcp-server/internal/burp"
+	"github.com/c0tton-fluff/burp-mcp-server/internal/config"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// defaultAttackerDomain is the origin the CORS matrix is built around.
+const defaultAttackerDomain = "evil.example"
+
+// CORSProbeInput is the input for burp_cors_probe.
+type CORSProbeInput struct {
+	Raw            string `json:"raw" jsonschema:"required,Raw HTTP request for the endpoint under test, with the cookies or token it needs"`
+	Host           string `json:"host,omitempty" jsonschema:"Target host (overrides Host header)"`
+	Port           int    `json:"port,omitempty" jsonschema:"Target port (default based on TLS)"`
+	TLS            *bool  `json:"tls,omitempty" jsonschema:"Use HTTPS (default true)"`
+	AttackerDomain string `json:"attackerDomain,omitempty" jsonschema:"Domain the attacker controls (default: evil.example)"`
+	HeaderProfile  string `json:"headerProfile,omitempty" jsonschema:"Header rule profile from config (default: 'default')"`
+	Instance       string `json:"instance,omitempty" jsonschema:"Named Burp instance from config (default: the --burp-url connection)"`
+}
+
+// CORSCheck is one Origin value and how the server answered it.
+type CORSCheck struct {
+	Name             string `json:"name"`
+	Origin           string `json:"origin"`
+	StatusCode       int    `json:"statusCode,omitempty"`
+	AllowOrigin      string `json:"allowOrigin,omitempty"`
+	AllowCredentials bool   `json:"allowCredentials,omitempty"`
+	Reflected        bool   `json:"reflected,omitempty"`
+	Vary             string `json:"vary,omitempty"`
+	Evidence         string `json:"evidence,omitempty"`
+	Error            string `json:"error,omitempty"`
+}
+
+// CORSProbeOutput is the output of burp_cors_probe.
+type CORSProbeOutput struct {
+	Checks   []CORSCheck `json:"checks"`
+	Findings []string    `json:"findings"`
+}
+
+// corsOrigin is one Origin value in the probe matrix.
+type corsOrigin struct {
+	name   string
+	origin string
+}
+
+// corsOrigins builds the Origin matrix for host: the target's own origin as a
+// baseline, an unrelated domain, null, and the prefix, suffix, and regex
+// mistakes that allow-lists commonly make.
+func corsOrigins(host, attacker string, useTLS bool) []corsOrigin {
+	scheme := "http"
+	if useTLS {
+		scheme = "https"
+	}
+	origins := []corsOrigin{
+		{"baseline", scheme + "://" + host},
+		{"attacker", "https://" + attacker},
+		{"null", "null"},
+		// host.evil.example passes a startsWith check on the trusted host.
+		{"trusted-prefix", "https://" + host + "." + attacker},
+		// evilexample.com passes an endsWith check without a leading dot.
+		{"trusted-suffix", "https://" + strings.SplitN(attacker, ".", 2)[0] + host},
+		{"subdomain", "https://" + strings.SplitN(attacker, ".", 2)[0] + "." + host},
+		// Browsers accept an underscore in a host label; lax matchers treat
+		// it as the end of the trusted host.
+		{"special-char", "https://" + host + "_." + attacker},
+	}
+	if i := strings.Index(host, "."); i > 0 {
+		// wwwxexample.com matches an unescaped regex like www.example.com.
+		origins = append(origins, corsOrigin{"unescaped-dot", "https://" + host[:i] + "x" + host[i+1:]})
+	}
+	if useTLS {
+		origins = append(origins, corsOrigin{"http-scheme", "http://" + host})
+	}
+	return origins
+}
+
+func corsProbeHandler(client *burp.Client) func(context.Context, *mcp.CallToolRequest, CORSProbeInput) (*mcp.CallToolResult, CORSProbeOutput, error) {
+	return func(ctx context.Context, _ *mcp.CallToolRequest, input CORSProbeInput) (*mcp.CallToolResult, CORSProbeOutput, error) {
+		ctx = burp.WithInstance(ctx, input.Instance)
+
+		rawNorm, parsed, err := prepareRequest(input.Raw, input.HeaderProfile)
+		if err != nil {
+			return nil, CORSProbeOutput{}, err
+		}
+		t, err := resolveTarget(input.Host, input.Port, input.TLS, parsed.Host)
+		if err != nil {
+			return nil, CORSProbeOutput{}, err
+		}
+		attacker := strings.TrimSuffix(strings.TrimSpace(input.AttackerDomain), ".")
+		if attacker == "" {
+			attacker = defaultAttackerDomain
+		}
+		if strings.Contains(attacker, "://") || strings.Contains(attacker, "/") {
+			return nil, CORSProbeOutput{}, fmt.Errorf("attackerDomain must be a bare domain, got %q", input.AttackerDomain)
+		}
+
+		host := parsed.Host
+		if host == "" {
+			host = t.Host
+		}
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+
+		var checks []CORSCheck
+		for _, o := range corsOrigins(host, attacker, t.UseTLS) {
+			req := applyHeaderRules(rawNorm, config.HeaderRules{Set: map[string]string{"Origin": o.origin}})
+			resp, err := sendParsed(ctx, client, req, t, 1)
+			if err != nil {
+				if o.name == "baseline" {
+					return nil, CORSProbeOutput{}, fmt.Errorf("baseline request failed: %w", err)
+				}
+				checks = append(checks, CORSCheck{Name: o.name, Origin: o.origin, Error: err.Error()})
+				continue
+			}
+			checks = append(checks, corsCheck(o, resp))
+		}
+
+		return nil, CORSProbeOutput{Checks: checks, Findings: corsFindings(checks)}, nil
+	}
+}
+
+// corsCheck summarizes the response to one Origin.
+func corsCheck(o corsOrigin, resp *burp.ParsedHTTPResponse) CORSCheck {
+	acao := strings.TrimSpace(burp.GetHeader(resp.Headers, "Access-Control-Allow-Origin"))
+	return CORSCheck{
+		Name:             o.name,
+		Origin:           o.origin,
+		StatusCode:       resp.StatusCode,
+		AllowOrigin:      acao,
+		AllowCredentials: strings.EqualFold(strings.TrimSpace(burp.GetHeader(resp.Headers, "Access-Control-Allow-Credentials")), "true"),
+		Reflected:        acao == o.origin,
+		Vary:             burp.GetHeader(resp.Headers, "Vary"),
+		Evidence:         corsEvidence(resp),
+	}
+}
+
+// corsEvidence is the status line plus the CORS and Vary headers, as sent.
+func corsEvidence(resp *burp.ParsedHTTPResponse) string {
+	var lines []string
+	for name, values := range resp.Headers {
+		lower := strings.ToLower(name)
+		if !strings.HasPrefix(lower, "access-control-") && lower != "vary" {
+			continue
+		}
+		for _, v := range values {
+			lines = append(lines, name+": "+v)
+		}
+	}
+	sort.Strings(lines)
+	return strings.Join(append([]string{resp.StatusLine}, lines...), "\n")
+}
+
+// corsFindings flags untrusted origins the server allowed. Reflection with
+// credentials lets the attacker's page read authenticated responses.
+func corsFindings(checks []CORSCheck) []string {
+	findings := []string{}
+	var baseline CORSCheck
+	for _, c := range checks {
+		if c.Name == "baseline" {
+			baseline = c
+		}
+	}
+
+	wildcard := false
+	for _, c := range checks {
+		if c.Name == "baseline" || c.Error != "" {
+			continue
+		}
+		if c.AllowOrigin == "*" {
+			wildcard = true
+			continue
+		}
+		if !c.Reflected {
+			continue
+		}
+		switch {
+		case c.AllowCredentials && c.Name == "http-scheme":
+			findings = append(findings, fmt.Sprintf("%s: %s is trusted with credentials: a network attacker can inject script into the plain-HTTP origin and read responses", c.Name, c.Origin))
+		case c.AllowCredentials && c.Name == "subdomain":
+			findings = append(findings, fmt.Sprintf("%s: every subdomain is trusted with credentials (%s): XSS or takeover on any subdomain reads authenticated responses", c.Name, c.Origin))
+		case c.AllowCredentials:
+			findings = append(findings, fmt.Sprintf("%s: %s is reflected with Access-Control-Allow-Credentials: true: an attacker's page can read authenticated responses", c.Name, c.Origin))
+		default:
+			findings = append(findings, fmt.Sprintf("%s: %s is reflected without credentials: only unauthenticated responses are readable", c.Name, c.Origin))
+		}
+	}
+	if wildcard {
+		findings = append(findings, "Access-Control-Allow-Origin: * for untrusted origins: public data only, browsers refuse it with credentials")
+	}
+	if baseline.StatusCode != 0 && baseline.AllowOrigin == "" && len(findings) == 0 {
+		findings = append(findings, "No Access-Control-Allow-Origin even for the target's own origin: CORS appears disabled for this endpoint")
+	}
+	if corsVariesByOrigin(checks) {
+		findings = append(findings, "Access-Control-Allow-Origin changes with the Origin but the response has no Vary: Origin: shared caches can serve one origin's grant to another")
+	}
+	return findings
+}
+
+// corsVariesByOrigin reports whether different origins got different
+// Access-Control-Allow-Origin values without a Vary: Origin to key caches on.
+func corsVariesByOrigin(checks []CORSCheck) bool {
+	seen := map[string]bool{}
+	for _, c := range checks {
+		if c.Error != "" {
+			continue
+		}
+		if strings.Contains(strings.ToLower(c.Vary), "origin") || c.Vary == "*" {
+			return false
+		}
+		seen[c.AllowOrigin] = true
+	}
+	return len(seen) > 1
+}
+
+// RegisterCORSProbeTool registers the burp_cors_probe tool.
+func RegisterCORSProbeTool(server *mcp.Server, client *burp.Client) {
+	addTool(server, &mcp.Tool{
+		Name: "burp_cors_probe",
+		Description: `Replay a request with a matrix of Origin values (attacker domain, null, trusted-host prefix/suffix and subdomain tricks, unescaped-dot regex, http scheme) ` +
+			`and report which are reflected in Access-Control-Allow-Origin, with or without credentials. ` +
+			`Returns {checks: [{name, origin, statusCode, allowOrigin, allowCredentials, reflected, evidence}], findings}.`,
+	}, corsProbeHandler(client))
+}
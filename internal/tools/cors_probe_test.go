@@ -0,0 +1,88 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/c0tton-fluff/burp-mcp-server/internal/burp"
+)
+
+func TestCORSOrigins(t *testing.T) {
+	got := map[string]string{}
+	for _, o := range corsOrigins("www.example.com", "evil.example", true) {
+		got[o.name] = o.origin
+	}
+	want := map[string]string{
+		"baseline":       "https://www.example.com",
+		"attacker":       "https://evil.example",
+		"null":           "null",
+		"trusted-prefix": "https://www.example.com.evil.example",
+		"trusted-suffix": "https://evilwww.example.com",
+		"subdomain":      "https://evil.www.example.com",
+		"unescaped-dot":  "https://wwwxexample.com",
+		"http-scheme":    "http://www.example.com",
+	}
+	for name, origin := range want {
+		if got[name] != origin {
+			t.Errorf("%s = %q, want %q", name, got[name], origin)
+		}
+	}
+
+	for _, o := range corsOrigins("localhost", "evil.example", false) {
+		if o.name == "unescaped-dot" || o.name == "http-scheme" {
+			t.Errorf("unexpected %s for a dotless plain-HTTP host", o.name)
+		}
+	}
+}
+
+func TestCORSCheck_Evidence(t *testing.T) {
+	resp := burp.ParseHTTPResponse("HTTP/1.1 200 OK\r\nAccess-Control-Allow-Origin: null\r\nAccess-Control-Allow-Credentials: true\r\nVary: Origin\r\nContent-Type: text/html\r\n\r\nhi", 0, 1)
+	c := corsCheck(corsOrigin{"null", "null"}, resp)
+	if !c.Reflected || !c.AllowCredentials || c.Vary != "Origin" {
+		t.Errorf("got %+v", c)
+	}
+	want := "HTTP/1.1 200 OK\nAccess-Control-Allow-Credentials: true\nAccess-Control-Allow-Origin: null\nVary: Origin"
+	if c.Evidence != want {
+		t.Errorf("evidence = %q, want %q", c.Evidence, want)
+	}
+}
+
+func TestCORSFindings(t *testing.T) {
+	checks := []CORSCheck{
+		{Name: "baseline", Origin: "https://a.com", StatusCode: 200, AllowOrigin: "https://a.com", AllowCredentials: true, Reflected: true},
+		{Name: "attacker", Origin: "https://evil.example", StatusCode: 200},
+		{Name: "null", Origin: "null", StatusCode: 200, AllowOrigin: "null", AllowCredentials: true, Reflected: true},
+		{Name: "subdomain", Origin: "https://evil.a.com", StatusCode: 200, AllowOrigin: "https://evil.a.com", Reflected: true},
+		{Name: "trusted-prefix", Origin: "https://a.com.evil.example", Error: "timeout"},
+	}
+	got := strings.Join(corsFindings(checks), "\n")
+	for _, want := range []string{"null: null is reflected with Access-Control-Allow-Credentials", "subdomain: https://evil.a.com is reflected without credentials", "no Vary: Origin"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("missing %q in:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "baseline") || strings.Contains(got, "trusted-prefix") {
+		t.Errorf("baseline or failed check reported:\n%s", got)
+	}
+}
+
+func TestCORSFindings_Disabled(t *testing.T) {
+	checks := []CORSCheck{
+		{Name: "baseline", Origin: "https://a.com", StatusCode: 200},
+		{Name: "attacker", Origin: "https://evil.example", StatusCode: 200},
+	}
+	got := corsFindings(checks)
+	if len(got) != 1 || !strings.Contains(got[0], "CORS appears disabled") {
+		t.Errorf("got %v", got)
+	}
+}
+
+func TestCORSVariesByOrigin_WithVary(t *testing.T) {
+	checks := []CORSCheck{
+		{Name: "baseline", AllowOrigin: "https://a.com", Vary: "Accept-Encoding, Origin"},
+		{Name: "attacker"},
+	}
+	if corsVariesByOrigin(checks) {
+		t.Error("Vary: Origin present, want false")
+	}
+}
@@ -0,0 +1,261 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"net"
+	"net/url"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/c0tton-fluff/burp-mcp-server/internal/burp"
+	"github.com/c0tton-fluff/burp-mcp-server/internal/store"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// useKey is an endpoint a tool sent requests to under an auth profile.
+type useKey struct {
+	method, origin, path string
+	tool, authProfile    string
+}
+
+// useLog holds endpoint uses not yet written to the store.
+type useLog struct {
+	mu      sync.Mutex
+	pending map[useKey]int
+	last    map[useKey]time.Time
+}
+
+// uses is the server-wide log of endpoint uses, flushed to the store by
+// CoverageMiddleware after each tool call.
+var uses = &useLog{pending: make(map[useKey]int), last: make(map[useKey]time.Time)}
+
+func (l *useLog) add(k useKey, n int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.pending[k] += n
+	l.last[k] = time.Now().UTC()
+}
+
+// flush writes the pending uses to st. Uses that fail to save are kept for
+// the next flush.
+func (l *useLog) flush(st *store.Store) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if len(l.pending) == 0 {
+		return nil
+	}
+	var eps []store.Endpoint
+	for k, n := range l.pending {
+		eps = append(eps, store.Endpoint{Method: k.method, Origin: k.origin, Path: k.path, Uses: []store.EndpointUse{
+			{Tool: k.tool, AuthProfile: k.authProfile, Requests: n, LastAt: l.last[k]},
+		}})
+	}
+	if err := st.RecordEndpointUses(eps); err != nil {
+		return err
+	}
+	clear(l.pending)
+	clear(l.last)
+	return nil
+}
+
+// endpointOrigin renders t as an inventory origin: scheme://host, with the
+// port only when it isn't the scheme's default.
+func endpointOrigin(useTLS bool, host string, port int) string {
+	scheme, defaultPort := "http", 80
+	if useTLS {
+		scheme, defaultPort = "https", 443
+	}
+	host = strings.ToLower(host)
+	if port == 0 || port == defaultPort {
+		if strings.Contains(host, ":") {
+			return scheme + "://[" + host + "]"
+		}
+		return scheme + "://" + host
+	}
+	return scheme + "://" + net.JoinHostPort(host, strconv.Itoa(port))
+}
+
+// urlEndpoint returns the inventory origin and templated path of rawURL.
+func urlEndpoint(rawURL string) (origin, path string, ok bool) {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "", "", false
+	}
+	port, _ := strconv.Atoi(u.Port())
+	return endpointOrigin(u.Scheme == "https", u.Hostname(), port), store.TemplatePath(u.EscapedPath()), true
+}
+
+// recordEndpointUse logs n requests of method to path on t for coverage.
+func recordEndpointUse(ctx context.Context, t resolvedTarget, method, path, tool string, n int) {
+	uses.add(useKey{
+		method:      strings.ToUpper(method),
+		origin:      endpointOrigin(t.UseTLS, t.Host, t.Port),
+		path:        store.TemplatePath(path),
+		tool:        tool,
+		authProfile: authProfileName(ctx),
+	}, n)
+}
+
+// CoverageMiddleware writes the endpoint uses recorded during each tool
+// call to st once the call returns.
+func CoverageMiddleware(st *store.Store) mcp.Middleware {
+	return func(next mcp.MethodHandler) mcp.MethodHandler {
+		return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+			result, err := next(ctx, method, req)
+			if method == "tools/call" {
+				if fErr := uses.flush(st); fErr != nil {
+					fmt.Fprintf(os.Stderr, "coverage: %v\n", fErr)
+				}
+			}
+			return result, err
+		}
+	}
+}
+
+// addInventory merges endpoints found by a tool into st's inventory,
+// reporting a failure on stderr rather than failing the tool.
+func addInventory(st *store.Store, eps []store.Endpoint) {
+	if st == nil || len(eps) == 0 {
+		return
+	}
+	if _, err := st.AddEndpoints(eps); err != nil {
+		fmt.Fprintf(os.Stderr, "coverage: %v\n", err)
+	}
+}
+
+// GetCoverageInput is the input for burp_get_coverage.
+type GetCoverageInput struct {
+	History    bool   `json:"history,omitempty" jsonschema:"First add the endpoints in live proxy history to the inventory"`
+	MaxEntries int    `json:"maxEntries,omitempty" jsonschema:"Most history entries to read with history=true (default 1000, max 5000)"`
+	Host       string `json:"host,omitempty" jsonschema:"Only endpoints on this host"`
+	Untested   bool   `json:"untested,omitempty" jsonschema:"List only endpoints no tool has sent a request to"`
+	Instance   string `json:"instance,omitempty" jsonschema:"Named Burp instance from config (default: the --burp-url connection)"`
+}
+
+// CoverageEndpoint is one inventory endpoint and how it has been exercised.
+type CoverageEndpoint struct {
+	Method  string              `json:"method"`
+	URL     string              `json:"url"`
+	Sources []string            `json:"sources"`
+	Uses    []store.EndpointUse `json:"uses"`
+	// MissingProfiles are the configured auth profiles no tool has used on
+	// the endpoint.
+	MissingProfiles []string `json:"missingProfiles,omitempty"`
+}
+
+// GetCoverageOutput is the output of burp_get_coverage.
+type GetCoverageOutput struct {
+	Total     int                `json:"total"`
+	Exercised int                `json:"exercised"`
+	Untested  int                `json:"untested"`
+	Endpoints []CoverageEndpoint `json:"endpoints"`
+	// HistoryAdded counts endpoints new to the inventory from history=true.
+	HistoryAdded int `json:"historyAdded,omitempty"`
+}
+
+// historyEndpoint parses a history entry into an inventory endpoint.
+func historyEndpoint(raw string, id int) (*store.Endpoint, error) {
+	req, _ := burp.ExtractRequestResponse(raw)
+	parsed := burp.ParseRawRequest(req)
+	if parsed.Method == "" || parsed.Host == "" {
+		return &store.Endpoint{}, nil
+	}
+	host, port := parsed.Host, 0
+	if h, p, err := net.SplitHostPort(parsed.Host); err == nil {
+		host = h
+		port, _ = strconv.Atoi(p)
+	}
+	// History doesn't record the scheme; like burp_get_proxy_history, assume HTTPS.
+	return &store.Endpoint{
+		Method:  parsed.Method,
+		Origin:  endpointOrigin(true, host, port),
+		Path:    store.TemplatePath(parsed.Path),
+		Sources: []string{store.EndpointFromHistory},
+	}, nil
+}
+
+func getCoverageHandler(client *burp.Client, st *store.Store) func(context.Context, *mcp.CallToolRequest, GetCoverageInput) (*mcp.CallToolResult, GetCoverageOutput, error) {
+	return func(ctx context.Context, _ *mcp.CallToolRequest, input GetCoverageInput) (*mcp.CallToolResult, GetCoverageOutput, error) {
+		ctx = burp.WithInstance(ctx, input.Instance)
+		if err := uses.flush(st); err != nil {
+			return nil, GetCoverageOutput{}, fmt.Errorf("record coverage: %w", err)
+		}
+
+		var out GetCoverageOutput
+		if input.History {
+			maxEntries := input.MaxEntries
+			if maxEntries <= 0 {
+				maxEntries = defaultSnapshotEntries
+			}
+			maxEntries = min(maxEntries, maxSnapshotEntries)
+			var eps []store.Endpoint
+			for read := 0; read < maxEntries; {
+				n := min(snapshotBatch, maxEntries-read)
+				batch, err := fetchHistory(ctx, client, read, n, historyEndpoint)
+				if err != nil {
+					return nil, GetCoverageOutput{}, fmt.Errorf("failed to read proxy history entry %d: %w", read+len(batch)+1, err)
+				}
+				read += len(batch)
+				for _, ep := range batch {
+					if ep.Method != "" {
+						eps = append(eps, ep)
+					}
+				}
+				if len(batch) < n {
+					break
+				}
+			}
+			added, err := st.AddEndpoints(eps)
+			if err != nil {
+				return nil, GetCoverageOutput{}, fmt.Errorf("add history endpoints: %w", err)
+			}
+			out.HistoryAdded = added
+		}
+
+		profiles := slices.Sorted(maps.Keys(settings.AuthProfiles))
+		out.Endpoints = []CoverageEndpoint{}
+		for _, e := range st.Endpoints() {
+			if input.Host != "" {
+				if u, err := url.Parse(e.Origin); err != nil || !strings.EqualFold(u.Hostname(), input.Host) {
+					continue
+				}
+			}
+			out.Total++
+			if len(e.Uses) > 0 {
+				out.Exercised++
+			} else {
+				out.Untested++
+			}
+			if input.Untested && len(e.Uses) > 0 {
+				continue
+			}
+			ce := CoverageEndpoint{Method: e.Method, URL: e.Origin + e.Path, Sources: e.Sources, Uses: e.Uses}
+			if ce.Uses == nil {
+				ce.Uses = []store.EndpointUse{}
+			}
+			for _, p := range profiles {
+				if !slices.ContainsFunc(e.Uses, func(u store.EndpointUse) bool { return u.AuthProfile == p }) {
+					ce.MissingProfiles = append(ce.MissingProfiles, p)
+				}
+			}
+			out.Endpoints = append(out.Endpoints, ce)
+		}
+		return nil, out, nil
+	}
+}
+
+// RegisterGetCoverageTool registers the burp_get_coverage tool.
+func RegisterGetCoverageTool(server *mcp.Server, client *burp.Client, st *store.Store) {
+	addTool(server, &mcp.Tool{
+		Name: "burp_get_coverage",
+		Description: `Report test coverage of the endpoint inventory: every method and templated path (/users/{id}) found by burp_crawl, burp_import_openapi, proxy history (with history=true), or tool traffic, ` +
+			`with the tools and auth profiles that have sent requests to it, so what's left untested is visible. untested=true lists only endpoints nothing has exercised; missingProfiles names configured auth profiles not yet tried on an endpoint. ` +
+			`Returns {total, exercised, untested, endpoints: [{method, url, sources, uses: [{tool, authProfile, requests, lastAt}], missingProfiles}], historyAdded}.`,
+	}, getCoverageHandler(client, st))
+}
@@ -0,0 +1,87 @@
+package tools
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/c0tton-fluff/burp-mcp-server/internal/config"
+	"github.com/c0tton-fluff/burp-mcp-server/internal/store"
+)
+
+func TestEndpointOrigin(t *testing.T) {
+	tests := []struct {
+		tls  bool
+		host string
+		port int
+		want string
+	}{
+		{true, "A.test", 443, "https://a.test"},
+		{false, "a.test", 80, "http://a.test"},
+		{true, "a.test", 8443, "https://a.test:8443"},
+		{false, "::1", 8080, "http://[::1]:8080"},
+		{true, "::1", 0, "https://[::1]"},
+	}
+	for _, tt := range tests {
+		if got := endpointOrigin(tt.tls, tt.host, tt.port); got != tt.want {
+			t.Errorf("endpointOrigin(%v, %q, %d) = %q, want %q", tt.tls, tt.host, tt.port, got, tt.want)
+		}
+	}
+}
+
+func TestGetCoverage(t *testing.T) {
+	t.Cleanup(func() { Configure(nil) })
+	Configure(&config.Config{AuthProfiles: map[string]config.AuthProfile{
+		"alice": {Headers: map[string]string{"Authorization": "Bearer a"}},
+		"bob":   {Headers: map[string]string{"Authorization": "Bearer b"}},
+	}})
+	st, err := store.Open(filepath.Join(t.TempDir(), "store.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := &fakeBurp{}
+	f.add(historyEntryJSON("GET", "cov.test", "/users/17?tab=1", 200), "")
+	f.add(historyEntryJSON("GET", "cov.test", "/users/18", 200), "")
+	f.add(historyEntryJSON("POST", "cov.test", "/login", 302), "")
+	f.add(historyEntryJSON("GET", "other.test", "/", 200), "")
+	client := startFakeBurp(t, f)
+	ctx := context.Background()
+
+	addInventory(st, crawlEndpoints(CrawlOutput{
+		Pages: []CrawlPage{{URL: "https://cov.test/about"}, {URL: "https://cov.test/broken", Error: "timeout"}},
+		Forms: []CrawlForm{{Action: "https://cov.test/search", Method: "get"}},
+	}))
+	target := resolvedTarget{Host: "cov.test", Port: 443, UseTLS: true}
+	recordEndpointUse(withAuthProfile(ctx, "alice"), target, "GET", "/users/99", "burp_send_request", 2)
+	recordEndpointUse(ctx, target, "DELETE", "/users/99", "burp_idor_sweep", 1)
+
+	get := getCoverageHandler(client, st)
+	_, out, err := get(ctx, nil, GetCoverageInput{History: true, Host: "cov.test"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// about, search, GET /users/{id}, DELETE /users/{id}, POST /login
+	if out.Total != 5 || out.Exercised != 2 || out.Untested != 3 || out.HistoryAdded != 2 {
+		t.Fatalf("coverage = %+v", out)
+	}
+	var users *CoverageEndpoint
+	for i, e := range out.Endpoints {
+		if e.Method == "GET" && e.URL == "https://cov.test/users/{id}" {
+			users = &out.Endpoints[i]
+		}
+	}
+	if users == nil || len(users.Uses) != 1 || users.Uses[0].AuthProfile != "alice" || users.Uses[0].Requests != 2 ||
+		len(users.MissingProfiles) != 1 || users.MissingProfiles[0] != "bob" || len(users.Sources) != 2 {
+		t.Errorf("users endpoint = %+v", users)
+	}
+
+	_, out, err = get(ctx, nil, GetCoverageInput{Host: "cov.test", Untested: true})
+	if err != nil || len(out.Endpoints) != 3 || out.Total != 5 {
+		t.Fatalf("untested = %+v, %v", out, err)
+	}
+	for _, e := range out.Endpoints {
+		if len(e.Uses) != 0 {
+			t.Errorf("untested listing includes %+v", e)
+		}
+	}
+}
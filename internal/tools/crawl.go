@@ -0,0 +1,438 @@
+package tools
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"html"
+	"maps"
+	"net/url"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+	"sync/atomic"
+
+	"github.com/c0tton-fluff/burp-mcp-server/internal/burp"
+	"github.com/c0tton-fluff/burp-mcp-server/internal/config"
+	"github.com/c0tton-fluff/burp-mcp-server/internal/store"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const (
+	defaultCrawlDepth = 2
+	maxCrawlDepth     = 5
+	defaultCrawlPages = 50
+	maxCrawlPages     = 500
+	// maxCrawlExternal caps the out-of-scope links listed.
+	maxCrawlExternal = 100
+)
+
+// crawlStaticExt are listed in the tree but never fetched.
+var crawlStaticExt = []string{".png", ".jpg", ".jpeg", ".gif", ".svg", ".ico", ".webp", ".css", ".js", ".mjs", ".map",
+	".woff", ".woff2", ".ttf", ".eot", ".pdf", ".zip", ".gz", ".mp4", ".mp3", ".webm"}
+
+var (
+	crawlLinkRe   = regexp.MustCompile(`(?i)<(?:a|area|link)\b[^>]*?\shref\s*=\s*(?:"([^"]*)"|'([^']*)'|([^\s>]+))`)
+	crawlFrameRe  = regexp.MustCompile(`(?i)<i?frame\b[^>]*?\ssrc\s*=\s*(?:"([^"]*)"|'([^']*)'|([^\s>]+))`)
+	crawlScriptRe = regexp.MustCompile(`(?i)<script\b[^>]*?\ssrc\s*=\s*(?:"([^"]*)"|'([^']*)'|([^\s>]+))`)
+	crawlBaseRe   = regexp.MustCompile(`(?i)<base\b[^>]*?\shref\s*=\s*(?:"([^"]*)"|'([^']*)'|([^\s>]+))`)
+	crawlFormRe   = regexp.MustCompile(`(?is)<form\b([^>]*)>(.*?)</form>`)
+	crawlFieldRe  = regexp.MustCompile(`(?i)<(input|select|textarea|button)\b([^>]*)>`)
+	crawlTitleRe  = regexp.MustCompile(`(?is)<title[^>]*>(.*?)</title>`)
+)
+
+// CrawlInput is the input for burp_crawl.
+type CrawlInput struct {
+	URL           string            `json:"url" jsonschema:"required,Start URL (http or https)"`
+	Depth         int               `json:"depth,omitempty" jsonschema:"Link depth to follow from the start page (default 2, max 5)"`
+	MaxPages      int               `json:"maxPages,omitempty" jsonschema:"Page budget (default 50, max 500)"`
+	Headers       map[string]string `json:"headers,omitempty" jsonschema:"Headers sent with every request, e.g. Cookie or Authorization"`
+	HeaderProfile string            `json:"headerProfile,omitempty" jsonschema:"Header rule profile from config (default: 'default')"`
+
+	TLSConfig *config.TLSOptions `json:"tlsConfig,omitempty" jsonschema:"TLS client certificate, CA bundle, version, and cipher options"`
+
+	Background bool `json:"background,omitempty" jsonschema:"Run as a background task and return its taskId at once; poll with burp_get_task, which shows pages and forms found so far"`
+}
+
+// CrawlPage is one fetched page.
+type CrawlPage struct {
+	URL         string `json:"url"`
+	Depth       int    `json:"depth"`
+	StatusCode  int    `json:"statusCode,omitempty"`
+	ContentType string `json:"contentType,omitempty"`
+	Length      int    `json:"length"`
+	Title       string `json:"title,omitempty"`
+	Error       string `json:"error,omitempty"`
+}
+
+// CrawlField is a named form control.
+type CrawlField struct {
+	Name  string `json:"name"`
+	Type  string `json:"type"`
+	Value string `json:"value,omitempty"`
+}
+
+// CrawlForm is a form and the page it was found on.
+type CrawlForm struct {
+	Page   string       `json:"page"`
+	Action string       `json:"action"`
+	Method string       `json:"method"`
+	Fields []CrawlField `json:"fields"`
+}
+
+// CrawlOutput is the output of burp_crawl.
+type CrawlOutput struct {
+	Tree     string      `json:"tree"`
+	Pages    []CrawlPage `json:"pages"`
+	Forms    []CrawlForm `json:"forms"`
+	Scripts  []string    `json:"scripts,omitempty"`
+	External []string    `json:"external,omitempty"`
+	// Unvisited counts in-scope links left when the depth or page budget ran out.
+	Unvisited int    `json:"unvisited"`
+	TaskID    string `json:"taskId,omitempty"`
+}
+
+// attrValue returns the first non-empty capture of a quoted/unquoted
+// attribute regexp match, unescaped.
+func attrValue(m []string) string {
+	for _, v := range m[1:] {
+		if v != "" {
+			return html.UnescapeString(strings.TrimSpace(v))
+		}
+	}
+	return ""
+}
+
+// htmlAttr extracts attribute name from a tag's attribute text.
+func htmlAttr(attrs, name string) string {
+	re := regexp.MustCompile(`(?i)(?:^|\s)` + regexp.QuoteMeta(name) + `\s*=\s*(?:"([^"]*)"|'([^']*)'|([^\s>]+))`)
+	if m := re.FindStringSubmatch(attrs); m != nil {
+		return attrValue(m)
+	}
+	return ""
+}
+
+// crawlExtract returns the links, scripts, and forms in an HTML page,
+// resolved against base (or the page's <base href>).
+func crawlExtract(base *url.URL, body string) (links, scripts []string, forms []CrawlForm) {
+	page := base.String()
+	if m := crawlBaseRe.FindStringSubmatch(body); m != nil {
+		if b, err := base.Parse(attrValue(m)); err == nil {
+			base = b
+		}
+	}
+	resolve := func(ref string) string {
+		if ref == "" || strings.HasPrefix(ref, "#") {
+			return ""
+		}
+		u, err := base.Parse(ref)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			return ""
+		}
+		u.Fragment = ""
+		return u.String()
+	}
+
+	for _, re := range []*regexp.Regexp{crawlLinkRe, crawlFrameRe} {
+		for _, m := range re.FindAllStringSubmatch(body, -1) {
+			if u := resolve(attrValue(m)); u != "" {
+				links = append(links, u)
+			}
+		}
+	}
+	for _, m := range crawlScriptRe.FindAllStringSubmatch(body, -1) {
+		if u := resolve(attrValue(m)); u != "" {
+			scripts = append(scripts, u)
+		}
+	}
+	for _, m := range crawlFormRe.FindAllStringSubmatch(body, -1) {
+		action := htmlAttr(m[1], "action")
+		target := resolve(action)
+		if action == "" {
+			target = resolve(page)
+		}
+		if target == "" {
+			continue
+		}
+		method := strings.ToUpper(htmlAttr(m[1], "method"))
+		if method == "" {
+			method = "GET"
+		}
+		form := CrawlForm{Page: page, Action: target, Method: method, Fields: []CrawlField{}}
+		for _, f := range crawlFieldRe.FindAllStringSubmatch(m[2], -1) {
+			name := htmlAttr(f[2], "name")
+			if name == "" {
+				continue
+			}
+			typ := fieldType(strings.ToLower(f[1]), htmlAttr(f[2], "type"))
+			form.Fields = append(form.Fields, CrawlField{Name: name, Type: typ, Value: htmlAttr(f[2], "value")})
+		}
+		forms = append(forms, form)
+		if method == "GET" {
+			links = append(links, target)
+		}
+	}
+	return links, scripts, forms
+}
+
+// siteTree renders urls as an indented tree per origin, one path segment per
+// level. Queries are dropped.
+func siteTree(urls []string) string {
+	type node map[string]node
+	roots := map[string]node{}
+	for _, raw := range urls {
+		u, err := url.Parse(raw)
+		if err != nil {
+			continue
+		}
+		origin := u.Scheme + "://" + u.Host
+		if roots[origin] == nil {
+			roots[origin] = node{}
+		}
+		n := roots[origin]
+		p := strings.TrimPrefix(u.EscapedPath(), "/")
+		if p == "" {
+			continue
+		}
+		segs := strings.Split(p, "/")
+		for i, seg := range segs {
+			if i < len(segs)-1 || strings.HasSuffix(p, "/") {
+				seg += "/"
+			}
+			if seg == "/" {
+				continue
+			}
+			if n[seg] == nil {
+				n[seg] = node{}
+			}
+			n = n[seg]
+		}
+	}
+
+	var b strings.Builder
+	var walk func(n node, indent string)
+	walk = func(n node, indent string) {
+		for _, name := range slices.Sorted(maps.Keys(n)) {
+			b.WriteString(indent + name + "\n")
+			walk(n[name], indent+"  ")
+		}
+	}
+	for _, origin := range slices.Sorted(maps.Keys(roots)) {
+		b.WriteString(origin + "/\n")
+		walk(roots[origin], "  ")
+	}
+	return b.String()
+}
+
+// crawlFetchable reports whether u looks like a page worth parsing.
+func crawlFetchable(u *url.URL) bool {
+	return !slices.Contains(crawlStaticExt, strings.ToLower(path.Ext(u.Path)))
+}
+
+// directGet fetches u directly with a plain GET carrying headers.
+func directGet(ctx context.Context, u *url.URL, headers map[string]string, headerProfile string, opts directOptions) (*burp.ParsedHTTPResponse, error) {
+	raw := fmt.Sprintf("GET %s HTTP/1.1\r\nHost: %s\r\nAccept: text/html,application/xhtml+xml,*/*;q=0.8\r\nConnection: close\r\n\r\n", u.RequestURI(), u.Host)
+	raw = applyHeaderRules(raw, config.HeaderRules{Set: headers})
+	rawNorm, _, err := prepareRequest(raw, headerProfile)
+	if err != nil {
+		return nil, err
+	}
+	useTLS := u.Scheme == "https"
+	t, err := resolveTarget(u.Host, 0, &useTLS, "")
+	if err != nil {
+		return nil, err
+	}
+	text, err := sendDirect(ctx, t, []byte(rawNorm), opts)
+	if err != nil {
+		return nil, err
+	}
+	resp := burp.ParseHTTPResponse(text, 0, 0)
+	if resp == nil || resp.StatusCode == 0 {
+		return nil, parseFailure("empty or unparseable response")
+	}
+	return resp, nil
+}
+
+// crawlFound is what one page links to.
+type crawlFound struct {
+	links, scripts []string
+	forms          []CrawlForm
+}
+
+// formKey identifies a form by method, action, and field names, so the same
+// form on many pages is reported once.
+func formKey(f CrawlForm) string {
+	names := make([]string, len(f.Fields))
+	for i, field := range f.Fields {
+		names[i] = field.Name
+	}
+	return f.Method + " " + f.Action + " " + strings.Join(names, ",")
+}
+
+func crawlHandler(st *store.Store) func(context.Context, *mcp.CallToolRequest, CrawlInput) (*mcp.CallToolResult, CrawlOutput, error) {
+	return func(ctx context.Context, _ *mcp.CallToolRequest, input CrawlInput) (*mcp.CallToolResult, CrawlOutput, error) {
+		depth := input.Depth
+		if depth == 0 {
+			depth = defaultCrawlDepth
+		}
+		if depth < 0 || depth > maxCrawlDepth {
+			return nil, CrawlOutput{}, fmt.Errorf("depth must be 0-%d", maxCrawlDepth)
+		}
+		budget := input.MaxPages
+		if budget == 0 {
+			budget = defaultCrawlPages
+		}
+		if budget < 1 || budget > maxCrawlPages {
+			return nil, CrawlOutput{}, fmt.Errorf("maxPages must be 1-%d", maxCrawlPages)
+		}
+		start, err := url.Parse(input.URL)
+		if err != nil || (start.Scheme != "http" && start.Scheme != "https") || start.Host == "" {
+			return nil, CrawlOutput{}, fmt.Errorf("url must be an absolute http or https URL")
+		}
+		start.Fragment = ""
+		if err := checkScope(ctx, start.Hostname()); err != nil {
+			return nil, CrawlOutput{}, err
+		}
+		if input.Background {
+			input.Background = false
+			id := startToolTask(ctx, "crawl", "crawl of "+start.String(), func(ctx context.Context) (any, error) {
+				_, out, err := crawlHandler(st)(ctx, nil, input)
+				return out, err
+			})
+			return nil, CrawlOutput{Pages: []CrawlPage{}, Forms: []CrawlForm{}, TaskID: id}, nil
+		}
+
+		// With no scope configured, stay on the start host.
+		inScope := func(u *url.URL) bool {
+			if targetScope.empty() {
+				return strings.EqualFold(u.Hostname(), start.Hostname())
+			}
+			return checkScope(ctx, u.Hostname()) == nil
+		}
+		opts := newDirectOptions(input.TLSConfig)
+		out := CrawlOutput{Pages: []CrawlPage{}, Forms: []CrawlForm{}}
+		seen := map[string]bool{start.String(): true}
+		tree := []string{start.String()}
+		scripts := map[string]bool{}
+		external := map[string]bool{}
+		forms := map[string]bool{}
+		level := []*url.URL{start}
+
+		var fetched atomic.Int64
+		for d := 0; len(level) > 0; d++ {
+			if d > depth || len(out.Pages) >= budget || ctx.Err() != nil {
+				out.Unvisited += len(level)
+				break
+			}
+			if room := budget - len(out.Pages); len(level) > room {
+				out.Unvisited += len(level) - room
+				level = level[:room]
+			}
+			pages := make([]CrawlPage, len(level))
+			found := make([]crawlFound, len(level))
+			parallel(len(level), func(i int) {
+				u := level[i]
+				pages[i] = CrawlPage{URL: u.String(), Depth: d}
+				resp, err := directGet(ctx, u, input.Headers, input.HeaderProfile, opts)
+				reportProgress(ctx, int(fetched.Add(1)), len(out.Pages)+len(level), u.String())
+				if err != nil {
+					pages[i].Error = err.Error()
+					return
+				}
+				pages[i].StatusCode = resp.StatusCode
+				pages[i].Length = resp.BodySize
+				pages[i].ContentType = burp.GetHeader(resp.Headers, "Content-Type")
+				if loc := burp.GetHeader(resp.Headers, "Location"); loc != "" && isRedirectStatus(resp.StatusCode) {
+					if next, err := u.Parse(loc); err == nil {
+						next.Fragment = ""
+						found[i].links = append(found[i].links, next.String())
+					}
+				}
+				ct := strings.ToLower(pages[i].ContentType)
+				if ct != "" && !strings.Contains(ct, "html") {
+					return
+				}
+				if m := crawlTitleRe.FindStringSubmatch(resp.Body); m != nil {
+					pages[i].Title = strings.Join(strings.Fields(html.UnescapeString(m[1])), " ")
+				}
+				links, srcs, fs := crawlExtract(u, resp.Body)
+				found[i].links = append(found[i].links, links...)
+				found[i].scripts, found[i].forms = srcs, fs
+			})
+			out.Pages = append(out.Pages, pages...)
+
+			var next []*url.URL
+			for _, f := range found {
+				for _, s := range f.scripts {
+					scripts[s] = true
+				}
+				for _, form := range f.forms {
+					if key := formKey(form); !forms[key] {
+						forms[key] = true
+						out.Forms = append(out.Forms, form)
+					}
+				}
+				for _, link := range f.links {
+					if seen[link] {
+						continue
+					}
+					seen[link] = true
+					u, err := url.Parse(link)
+					if err != nil {
+						continue
+					}
+					if !inScope(u) {
+						external[link] = true
+						continue
+					}
+					tree = append(tree, link)
+					if crawlFetchable(u) {
+						next = append(next, u)
+					}
+				}
+			}
+			level = next
+			reportPartial(ctx, out)
+		}
+
+		out.Tree = siteTree(tree)
+		addInventory(st, crawlEndpoints(out))
+		out.Scripts = slices.Sorted(maps.Keys(scripts))
+		out.External = slices.Sorted(maps.Keys(external))
+		if len(out.External) > maxCrawlExternal {
+			out.External = out.External[:maxCrawlExternal]
+		}
+		return nil, out, nil
+	}
+}
+
+// crawlEndpoints lists the pages fetched and the forms found by a crawl as
+// inventory endpoints.
+func crawlEndpoints(out CrawlOutput) []store.Endpoint {
+	var eps []store.Endpoint
+	add := func(method, rawURL string) {
+		if origin, tmpl, ok := urlEndpoint(rawURL); ok {
+			eps = append(eps, store.Endpoint{Method: method, Origin: origin, Path: tmpl, Sources: []string{store.EndpointFromCrawl}})
+		}
+	}
+	for _, p := range out.Pages {
+		if p.Error == "" {
+			add("GET", p.URL)
+		}
+	}
+	for _, f := range out.Forms {
+		add(cmp.Or(strings.ToUpper(f.Method), "GET"), f.Action)
+	}
+	return eps
+}
+
+// RegisterCrawlTool registers the burp_crawl tool.
+func RegisterCrawlTool(server *mcp.Server, st *store.Store) {
+	addTool(server, &mcp.Tool{
+		Name: "burp_crawl",
+		Description: `Crawl a site from a start URL, fetching pages directly (not through Burp) breadth-first up to a depth and page budget. ` +
+			`Extracts links, frames, scripts, and forms from HTML and follows redirects. Only in-scope hosts are fetched; with no scope configured, only the start host. ` +
+			`Returns {tree, pages: [{url, depth, statusCode, contentType, length, title}], forms: [{page, action, method, fields: [{name, type, value}]}], scripts, external, unvisited}; with background=true, {taskId} at once.`,
+	}, crawlHandler(st))
+}
@@ -0,0 +1,94 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestCrawlExtract(t *testing.T) {
+	base, _ := url.Parse("https://a.com/shop/index.html")
+	body := `<html><a href="item?id=1&amp;x=2">x</a> <a href='/about#team'>about</a> <a href="mailto:a@b.c">m</a>
+<a href=https://other.com/>o</a> <script src="/static/app.js"></script>
+<form action="/login" method="post"><input name="user"><input type="password" name="pass"><input type="submit" value="Go"><select name="lang"></select></form>
+<form><input type="hidden" name="q" value="a&amp;b"></form></html>`
+	links, scripts, forms := crawlExtract(base, body)
+
+	wantLinks := []string{"https://a.com/shop/item?id=1&x=2", "https://a.com/about", "https://other.com/", "https://a.com/shop/index.html"}
+	if strings.Join(links, " ") != strings.Join(wantLinks, " ") {
+		t.Errorf("links = %v, want %v", links, wantLinks)
+	}
+	if len(scripts) != 1 || scripts[0] != "https://a.com/static/app.js" {
+		t.Errorf("scripts = %v", scripts)
+	}
+	if len(forms) != 2 {
+		t.Fatalf("forms = %+v", forms)
+	}
+	if f := forms[0]; f.Action != "https://a.com/login" || f.Method != "POST" || fmt.Sprint(f.Fields) != "[{user text } {pass password } {lang select }]" {
+		t.Errorf("form 0 = %+v", f)
+	}
+	if f := forms[1]; f.Action != "https://a.com/shop/index.html" || f.Method != "GET" || f.Fields[0].Value != "a&b" {
+		t.Errorf("form 1 = %+v", f)
+	}
+}
+
+func TestSiteTree(t *testing.T) {
+	got := siteTree([]string{"https://a.com/", "https://a.com/api/v1/users?id=1", "https://a.com/api/", "https://a.com/about"})
+	want := "https://a.com/\n  about\n  api/\n    v1/\n      users\n"
+	if got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestCrawl(t *testing.T) {
+	target := startTestTarget(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		switch r.URL.Path {
+		case "/":
+			fmt.Fprint(w, `<title>Home</title><a href="/a">a</a><a href="/old">old</a><a href="http://elsewhere.example/">x</a><link rel="stylesheet" href="/site.css">`)
+		case "/a":
+			fmt.Fprint(w, `<a href="/a/deep">deep</a><form method="post" action="/a/save"><input name="n"></form>`)
+		case "/old":
+			http.Redirect(w, r, "/new", http.StatusFound)
+		case "/new", "/a/deep":
+			fmt.Fprint(w, `<a href="/a/deeper">deeper</a>`)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+	start := fmt.Sprintf("http://%s:%d/", target.Host, target.Port)
+
+	_, out, err := crawlHandler(nil)(context.Background(), nil, CrawlInput{URL: start, Depth: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var visited []string
+	for _, p := range out.Pages {
+		visited = append(visited, strings.TrimPrefix(p.URL, strings.TrimSuffix(start, "/")))
+	}
+	if got := strings.Join(visited, " "); got != "/ /a /old /a/deep /new" {
+		t.Errorf("visited %s", got)
+	}
+	if out.Pages[0].Title != "Home" || out.Pages[2].StatusCode != 302 {
+		t.Errorf("pages = %+v", out.Pages)
+	}
+	if out.Unvisited != 1 {
+		t.Errorf("unvisited = %d, want 1 (/a/deeper)", out.Unvisited)
+	}
+	if len(out.External) != 1 || out.External[0] != "http://elsewhere.example/" {
+		t.Errorf("external = %v", out.External)
+	}
+	if len(out.Forms) != 1 || out.Forms[0].Method != "POST" || out.Forms[0].Fields[0].Name != "n" {
+		t.Errorf("forms = %+v", out.Forms)
+	}
+	if !strings.Contains(out.Tree, "  a/\n    deep\n") || !strings.Contains(out.Tree, "  site.css\n") {
+		t.Errorf("tree:\n%s", out.Tree)
+	}
+
+	if _, out, _ := crawlHandler(nil)(context.Background(), nil, CrawlInput{URL: start, Depth: 2, MaxPages: 2}); len(out.Pages) != 2 || out.Unvisited == 0 {
+		t.Errorf("budget: %d pages, %d unvisited", len(out.Pages), out.Unvisited)
+	}
+}
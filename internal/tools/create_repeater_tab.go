@@ -10,11 +10,12 @@ import (
 
 // CreateRepeaterTabInput is the input for burp_create_repeater_tab.
 type CreateRepeaterTabInput struct {
-	Raw     string `json:"raw" jsonschema:"required,Raw HTTP request"`
-	Host    string `json:"host" jsonschema:"required,Target hostname"`
-	Port    int    `json:"port,omitempty" jsonschema:"Target port (default based on TLS)"`
-	TLS     *bool  `json:"tls,omitempty" jsonschema:"Use HTTPS (default true)"`
-	TabName string `json:"tabName,omitempty" jsonschema:"Repeater tab name"`
+	Raw      string `json:"raw" jsonschema:"required,Raw HTTP request"`
+	Host     string `json:"host" jsonschema:"required,Target hostname"`
+	Port     int    `json:"port,omitempty" jsonschema:"Target port (default based on TLS)"`
+	TLS      *bool  `json:"tls,omitempty" jsonschema:"Use HTTPS (default true)"`
+	TabName  string `json:"tabName,omitempty" jsonschema:"Repeater tab name"`
+	Instance string `json:"instance,omitempty" jsonschema:"Named Burp instance from config (default: the --burp-url connection)"`
 }
 
 // CreateRepeaterTabOutput is the output.
@@ -24,6 +25,8 @@ type CreateRepeaterTabOutput struct {
 
 func createRepeaterTabHandler(client *burp.Client) func(context.Context, *mcp.CallToolRequest, CreateRepeaterTabInput) (*mcp.CallToolResult, CreateRepeaterTabOutput, error) {
 	return func(ctx context.Context, _ *mcp.CallToolRequest, input CreateRepeaterTabInput) (*mcp.CallToolResult, CreateRepeaterTabOutput, error) {
+		ctx = burp.WithInstance(ctx, input.Instance)
+
 		if err := validateRawRequest(input.Raw); err != nil {
 			return nil, CreateRepeaterTabOutput{}, err
 		}
@@ -63,7 +66,7 @@ func createRepeaterTabHandler(client *burp.Client) func(context.Context, *mcp.Ca
 
 // RegisterCreateRepeaterTabTool registers the burp_create_repeater_tab tool.
 func RegisterCreateRepeaterTabTool(server *mcp.Server, client *burp.Client) {
-	mcp.AddTool(server, &mcp.Tool{
+	addTool(server, &mcp.Tool{
 		Name:        "burp_create_repeater_tab",
 		Description: `Create a Repeater tab with an HTTP request. Params: raw (request), host, port, tls, tabName.`,
 	}, createRepeaterTabHandler(client))
@@ -0,0 +1,342 @@
+package tools
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/c0tton-fluff/burp-mcp-server/internal/burp"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const (
+	defaultCredentialAttempts = 50
+	maxCredentialAttempts     = 500
+	defaultCredentialDelay    = 500 * time.Millisecond
+
+	modePitchfork   = "pitchfork"
+	modeClusterbomb = "clusterbomb"
+)
+
+// Markers in the request template. {{basic}} becomes base64(username:password)
+// for Authorization: Basic.
+const (
+	markerUsername = "{{username}}"
+	markerPassword = "{{password}}"
+	markerBasic    = "{{basic}}"
+)
+
+// lockoutPattern matches account lockout, throttling, and CAPTCHA pages.
+var lockoutPattern = regexp.MustCompile(`(?i)locked|too many (attempts|requests|failed)|temporarily (disabled|blocked|suspended)|try again (later|in)|captcha|rate limit`)
+
+// CredentialPair is one username and password to try.
+type CredentialPair struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+// CredentialRule matches a login response. Every field that is set must match.
+type CredentialRule struct {
+	Status    []int  `json:"status,omitempty" jsonschema:"Status codes that match"`
+	Regex     string `json:"regex,omitempty" jsonschema:"Regular expression the response headers or body must match"`
+	MinLength int    `json:"minLength,omitempty" jsonschema:"Minimum body length"`
+	MaxLength int    `json:"maxLength,omitempty" jsonschema:"Maximum body length"`
+}
+
+// CredentialTestInput is the input for burp_credential_test.
+type CredentialTestInput struct {
+	Raw           string           `json:"raw" jsonschema:"required,Login request template with {{username}} and {{password}} (or {{basic}} for a Basic auth token) markers"`
+	Credentials   []CredentialPair `json:"credentials,omitempty" jsonschema:"Username/password pairs to try (credential stuffing)"`
+	Usernames     []string         `json:"usernames,omitempty" jsonschema:"Username wordlist, combined with passwords according to mode"`
+	Passwords     []string         `json:"passwords,omitempty" jsonschema:"Password wordlist"`
+	Mode          string           `json:"mode,omitempty" jsonschema:"How usernames and passwords combine: clusterbomb (every pair, default) or pitchfork (line by line)"`
+	Success       *CredentialRule  `json:"success,omitempty" jsonschema:"Rule a successful login matches"`
+	Failure       *CredentialRule  `json:"failure,omitempty" jsonschema:"Rule a failed login matches; anything else counts as success. One of success or failure is required"`
+	DelayMs       *int             `json:"delayMs,omitempty" jsonschema:"Delay between attempts in milliseconds (default 500)"`
+	MaxAttempts   int              `json:"maxAttempts,omitempty" jsonschema:"Stop after this many attempts (default 50, max 500)"`
+	StopOnSuccess bool             `json:"stopOnSuccess,omitempty" jsonschema:"Stop at the first successful login"`
+	Host          string           `json:"host,omitempty" jsonschema:"Target host (overrides Host header)"`
+	Port          int              `json:"port,omitempty" jsonschema:"Target port (default based on TLS)"`
+	TLS           *bool            `json:"tls,omitempty" jsonschema:"Use HTTPS (default true)"`
+	HeaderProfile string           `json:"headerProfile,omitempty" jsonschema:"Header rule profile from config (default: 'default')"`
+	Instance      string           `json:"instance,omitempty" jsonschema:"Named Burp instance from config (default: the --burp-url connection)"`
+	Background    bool             `json:"background,omitempty" jsonschema:"Run as a background task and return its taskId at once; poll with burp_get_task, which shows successes so far"`
+}
+
+// CredentialResult is one successful login.
+type CredentialResult struct {
+	Username   string `json:"username"`
+	Password   string `json:"password"`
+	StatusCode int    `json:"statusCode"`
+	BodySize   int    `json:"bodySize"`
+}
+
+// CredentialTestOutput is the output of burp_credential_test.
+type CredentialTestOutput struct {
+	Successes []CredentialResult `json:"successes"`
+	Attempts  int                `json:"attempts"`
+	Planned   int                `json:"planned"`
+	Lockout   []string           `json:"lockout,omitempty"`
+	Stopped   string             `json:"stopped,omitempty"`
+	Summary   string             `json:"summary"`
+	TaskID    string             `json:"taskId,omitempty"`
+}
+
+// compiledRule is a CredentialRule with its regex compiled.
+type compiledRule struct {
+	CredentialRule
+	re *regexp.Regexp
+}
+
+func compileRule(r *CredentialRule, name string) (*compiledRule, error) {
+	if r == nil {
+		return nil, nil
+	}
+	c := &compiledRule{CredentialRule: *r}
+	if r.Regex != "" {
+		re, err := regexp.Compile(r.Regex)
+		if err != nil {
+			return nil, fmt.Errorf("%s.regex: %w", name, err)
+		}
+		c.re = re
+	}
+	return c, nil
+}
+
+// match reports whether resp satisfies every field set on the rule. text is
+// the raw response, headers included.
+func (r *compiledRule) match(resp *burp.ParsedHTTPResponse, text string) bool {
+	if len(r.Status) > 0 && !slices.Contains(r.Status, resp.StatusCode) {
+		return false
+	}
+	if r.re != nil && !r.re.MatchString(text) {
+		return false
+	}
+	if r.MinLength > 0 && resp.BodySize < r.MinLength {
+		return false
+	}
+	if r.MaxLength > 0 && resp.BodySize > r.MaxLength {
+		return false
+	}
+	return true
+}
+
+// credentialPairs combines the input lists. Explicit pairs come first.
+func credentialPairs(input CredentialTestInput) ([]CredentialPair, error) {
+	pairs := append([]CredentialPair(nil), input.Credentials...)
+	if len(input.Usernames) == 0 && len(input.Passwords) == 0 {
+		return pairs, nil
+	}
+	if len(input.Usernames) == 0 || len(input.Passwords) == 0 {
+		return nil, fmt.Errorf("usernames and passwords must be given together")
+	}
+	switch input.Mode {
+	case "", modeClusterbomb:
+		for _, u := range input.Usernames {
+			for _, p := range input.Passwords {
+				pairs = append(pairs, CredentialPair{u, p})
+			}
+		}
+	case modePitchfork:
+		for i := range min(len(input.Usernames), len(input.Passwords)) {
+			pairs = append(pairs, CredentialPair{input.Usernames[i], input.Passwords[i]})
+		}
+	default:
+		return nil, fmt.Errorf("mode must be %s or %s", modeClusterbomb, modePitchfork)
+	}
+	return pairs, nil
+}
+
+// fillCredentials substitutes a pair into the template, encoded for where
+// each marker sits: query-escaped in the request line and form bodies,
+// JSON-escaped in JSON bodies, verbatim in headers and other bodies.
+func fillCredentials(rawNorm string, c CredentialPair) string {
+	head, body, hasBody := strings.Cut(rawNorm, "\r\n\r\n")
+	line, headers, _ := strings.Cut(head, "\r\n")
+
+	basic := base64.StdEncoding.EncodeToString([]byte(c.Username + ":" + c.Password))
+	verbatim := strings.NewReplacer(markerUsername, c.Username, markerPassword, c.Password, markerBasic, basic)
+	query := strings.NewReplacer(markerUsername, url.QueryEscape(c.Username), markerPassword, url.QueryEscape(c.Password), markerBasic, url.QueryEscape(basic))
+
+	out := query.Replace(line) + "\r\n" + verbatim.Replace(headers)
+	if !hasBody {
+		return out
+	}
+	contentType := strings.ToLower(burp.GetHeader(burp.ParseRawRequest(rawNorm).Headers, "Content-Type"))
+	switch {
+	case strings.Contains(contentType, "x-www-form-urlencoded"):
+		body = query.Replace(body)
+	case strings.Contains(contentType, "json"):
+		body = strings.NewReplacer(markerUsername, jsonEscape(c.Username), markerPassword, jsonEscape(c.Password), markerBasic, basic).Replace(body)
+	default:
+		body = verbatim.Replace(body)
+	}
+	return fixContentLength(out + "\r\n\r\n" + body)
+}
+
+// jsonEscape escapes s for use inside a JSON string literal.
+func jsonEscape(s string) string {
+	var b strings.Builder
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	enc.Encode(s)
+	quoted := strings.TrimSuffix(b.String(), "\n")
+	return quoted[1 : len(quoted)-1]
+}
+
+// lockoutIndicators lists signs that the target is locking accounts or
+// throttling attempts.
+func lockoutIndicators(resp *burp.ParsedHTTPResponse) []string {
+	var signs []string
+	switch resp.StatusCode {
+	case 423, 429:
+		signs = append(signs, fmt.Sprintf("status %d", resp.StatusCode))
+	}
+	if ra := burp.GetHeader(resp.Headers, "Retry-After"); ra != "" {
+		signs = append(signs, "Retry-After: "+ra)
+	}
+	if m := lockoutPattern.FindString(resp.Body); m != "" {
+		signs = append(signs, fmt.Sprintf("body mentions %q", m))
+	}
+	return signs
+}
+
+func credentialTestHandler(client *burp.Client) func(context.Context, *mcp.CallToolRequest, CredentialTestInput) (*mcp.CallToolResult, CredentialTestOutput, error) {
+	return func(ctx context.Context, _ *mcp.CallToolRequest, input CredentialTestInput) (*mcp.CallToolResult, CredentialTestOutput, error) {
+		ctx = burp.WithInstance(ctx, input.Instance)
+
+		if !strings.Contains(input.Raw, markerUsername) && !strings.Contains(input.Raw, markerPassword) && !strings.Contains(input.Raw, markerBasic) {
+			return nil, CredentialTestOutput{}, fmt.Errorf("raw has no %s, %s, or %s marker", markerUsername, markerPassword, markerBasic)
+		}
+		if input.Success == nil && input.Failure == nil {
+			return nil, CredentialTestOutput{}, fmt.Errorf("one of success or failure is required")
+		}
+		success, err := compileRule(input.Success, "success")
+		if err != nil {
+			return nil, CredentialTestOutput{}, err
+		}
+		failure, err := compileRule(input.Failure, "failure")
+		if err != nil {
+			return nil, CredentialTestOutput{}, err
+		}
+		pairs, err := credentialPairs(input)
+		if err != nil {
+			return nil, CredentialTestOutput{}, err
+		}
+		if len(pairs) == 0 {
+			return nil, CredentialTestOutput{}, fmt.Errorf("credentials or usernames and passwords are required")
+		}
+		maxAttempts := input.MaxAttempts
+		if maxAttempts == 0 {
+			maxAttempts = defaultCredentialAttempts
+		}
+		if maxAttempts < 0 || maxAttempts > maxCredentialAttempts {
+			return nil, CredentialTestOutput{}, fmt.Errorf("maxAttempts must be 1-%d", maxCredentialAttempts)
+		}
+		delay := defaultCredentialDelay
+		if input.DelayMs != nil {
+			if *input.DelayMs < 0 {
+				return nil, CredentialTestOutput{}, fmt.Errorf("delayMs must not be negative")
+			}
+			delay = time.Duration(*input.DelayMs) * time.Millisecond
+		}
+
+		rawNorm, parsed, err := prepareRequest(input.Raw, input.HeaderProfile)
+		if err != nil {
+			return nil, CredentialTestOutput{}, err
+		}
+		t, err := resolveTarget(input.Host, input.Port, input.TLS, parsed.Host)
+		if err != nil {
+			return nil, CredentialTestOutput{}, err
+		}
+
+		out := CredentialTestOutput{Successes: []CredentialResult{}, Planned: len(pairs)}
+		if len(pairs) > maxAttempts {
+			pairs = pairs[:maxAttempts]
+			out.Stopped = fmt.Sprintf("maxAttempts reached: %d of %d pairs tried", maxAttempts, out.Planned)
+		}
+		summary := fmt.Sprintf("%d login attempts against %s:%d", len(pairs), t.Host, t.Port)
+		if input.Background {
+			input.Background = false
+			id := startToolTask(ctx, "credential_test", summary, func(ctx context.Context) (any, error) {
+				_, out, err := credentialTestHandler(client)(ctx, nil, input)
+				return out, err
+			})
+			return nil, CredentialTestOutput{Successes: []CredentialResult{}, Planned: out.Planned, TaskID: id}, nil
+		}
+		if err := requireApproval(ctx, "burp_credential_test", t, summary, rawNorm); err != nil {
+			return nil, CredentialTestOutput{}, err
+		}
+
+		statuses := map[int]int{}
+		for i, pair := range pairs {
+			if i > 0 && delay > 0 {
+				select {
+				case <-ctx.Done():
+					out.Stopped = "cancelled"
+					return nil, finishCredentialTest(out, statuses), nil
+				case <-time.After(delay):
+				}
+			}
+			req := fillCredentials(rawNorm, pair)
+			text, err := sendWithFallback(ctx, client, req, burp.ParseRawRequest(req), t)
+			if err != nil {
+				// Scope, rate limits, and dry run refuse every later attempt too.
+				out.Stopped = fmt.Sprintf("attempt %d failed: %v", i+1, err)
+				break
+			}
+			out.Attempts++
+			reportProgress(ctx, out.Attempts, len(pairs), pair.Username)
+			resp := burp.ParseHTTPResponse(text, 0, 0)
+			if resp == nil {
+				continue
+			}
+			statuses[resp.StatusCode]++
+
+			ok := success == nil || success.match(resp, text)
+			if failure != nil && failure.match(resp, text) {
+				ok = false
+			}
+			if ok {
+				out.Successes = append(out.Successes, CredentialResult{pair.Username, pair.Password, resp.StatusCode, resp.BodySize})
+				reportPartial(ctx, finishCredentialTest(out, statuses))
+			}
+			if signs := lockoutIndicators(resp); len(signs) > 0 && !ok {
+				out.Lockout = append(out.Lockout, fmt.Sprintf("attempt %d (%s): %s", i+1, pair.Username, strings.Join(signs, ", ")))
+				out.Stopped = "lockout or throttling detected"
+				break
+			}
+			if ok && input.StopOnSuccess {
+				out.Stopped = "stopOnSuccess"
+				break
+			}
+		}
+		return nil, finishCredentialTest(out, statuses), nil
+	}
+}
+
+func finishCredentialTest(out CredentialTestOutput, statuses map[int]int) CredentialTestOutput {
+	var parts []string
+	for _, code := range slices.Sorted(maps.Keys(statuses)) {
+		parts = append(parts, fmt.Sprintf("%dx %d", statuses[code], code))
+	}
+	out.Summary = fmt.Sprintf("%d of %d attempts sent, %d successful, responses: %s", out.Attempts, out.Planned, len(out.Successes), strings.Join(parts, ", "))
+	return out
+}
+
+// RegisterCredentialTestTool registers the burp_credential_test tool.
+func RegisterCredentialTestTool(server *mcp.Server, client *burp.Client) {
+	addTool(server, &mcp.Tool{
+		Name: "burp_credential_test",
+		Description: `Try credentials against a login request template with {{username}}/{{password}} (or {{basic}}) markers: a credential list, or username and password wordlists in clusterbomb or pitchfork mode. ` +
+			`Success is decided by success/failure rules (status, regex, length). Attempts are sequential with delayMs between them (default 500), capped by maxAttempts (default 50), and stop on lockout or throttling signs. ` +
+			`Returns {successes: [{username, password, statusCode, bodySize}], attempts, planned, lockout, stopped, summary}; with background=true, {taskId} at once.`,
+	}, credentialTestHandler(client))
+}
@@ -0,0 +1,90 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/c0tton-fluff/burp-mcp-server/internal/burp"
+)
+
+func TestCredentialPairs(t *testing.T) {
+	in := CredentialTestInput{
+		Credentials: []CredentialPair{{"admin", "admin"}},
+		Usernames:   []string{"a", "b"},
+		Passwords:   []string{"1", "2", "3"},
+	}
+	pairs, err := credentialPairs(in)
+	if err != nil || len(pairs) != 7 || pairs[0].Username != "admin" || pairs[3] != (CredentialPair{"a", "3"}) {
+		t.Errorf("clusterbomb: %v %v", pairs, err)
+	}
+
+	in.Mode = modePitchfork
+	pairs, _ = credentialPairs(in)
+	if len(pairs) != 3 || pairs[2] != (CredentialPair{"b", "2"}) {
+		t.Errorf("pitchfork: %v", pairs)
+	}
+
+	if _, err := credentialPairs(CredentialTestInput{Usernames: []string{"a"}}); err == nil {
+		t.Error("want error for usernames without passwords")
+	}
+	if _, err := credentialPairs(CredentialTestInput{Usernames: []string{"a"}, Passwords: []string{"b"}, Mode: "sniper"}); err == nil {
+		t.Error("want error for unknown mode")
+	}
+}
+
+func TestFillCredentials(t *testing.T) {
+	pair := CredentialPair{"a&b", `p"w d`}
+
+	form := "POST /login?u={{username}} HTTP/1.1\r\nHost: x\r\nContent-Type: application/x-www-form-urlencoded\r\nContent-Length: 0\r\n\r\nuser={{username}}&pass={{password}}"
+	got := fillCredentials(form, pair)
+	if !strings.HasPrefix(got, "POST /login?u=a%26b HTTP/1.1\r\n") || !strings.HasSuffix(got, "\r\n\r\nuser=a%26b&pass=p%22w+d") || !strings.Contains(got, "Content-Length: 23\r\n") {
+		t.Errorf("form: %q", got)
+	}
+
+	js := "POST /login HTTP/1.1\r\nHost: x\r\nContent-Type: application/json\r\n\r\n{\"u\":\"{{username}}\",\"p\":\"{{password}}\"}"
+	if got := fillCredentials(js, pair); !strings.HasSuffix(got, `{"u":"a&b","p":"p\"w d"}`) {
+		t.Errorf("json: %q", got)
+	}
+
+	basic := "GET / HTTP/1.1\r\nHost: x\r\nAuthorization: Basic {{basic}}\r\n\r\n"
+	if got := fillCredentials(basic, CredentialPair{"u", "p"}); !strings.Contains(got, "Authorization: Basic dTpw\r\n") {
+		t.Errorf("basic: %q", got)
+	}
+}
+
+func TestCredentialRuleMatch(t *testing.T) {
+	text := "HTTP/1.1 302 Found\r\nLocation: /dashboard\r\n\r\nredirecting"
+	resp := burp.ParseHTTPResponse(text, 0, 0)
+
+	rule, err := compileRule(&CredentialRule{Status: []int{302}, Regex: "Location: /dashboard"}, "success")
+	if err != nil || !rule.match(resp, text) {
+		t.Errorf("want match, err=%v", err)
+	}
+	rule, _ = compileRule(&CredentialRule{Status: []int{302}, MinLength: 100}, "success")
+	if rule.match(resp, text) {
+		t.Error("want no match below minLength")
+	}
+	if _, err := compileRule(&CredentialRule{Regex: "("}, "failure"); err == nil || !strings.HasPrefix(err.Error(), "failure.regex") {
+		t.Errorf("got %v", err)
+	}
+}
+
+func TestLockoutIndicators(t *testing.T) {
+	resp := burp.ParseHTTPResponse("HTTP/1.1 429 Too Many Requests\r\nRetry-After: 60\r\n\r\nToo many attempts, try again later", 0, 0)
+	got := strings.Join(lockoutIndicators(resp), "; ")
+	for _, want := range []string{"status 429", "Retry-After: 60", "body mentions"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("missing %q in %q", want, got)
+		}
+	}
+	if got := lockoutIndicators(burp.ParseHTTPResponse("HTTP/1.1 200 OK\r\n\r\nInvalid password", 0, 0)); len(got) != 0 {
+		t.Errorf("got %v", got)
+	}
+}
+
+func TestFinishCredentialTest(t *testing.T) {
+	out := finishCredentialTest(CredentialTestOutput{Attempts: 3, Planned: 5, Successes: []CredentialResult{{}}}, map[int]int{302: 1, 200: 2})
+	if out.Summary != "3 of 5 attempts sent, 1 successful, responses: 2x 200, 1x 302" {
+		t.Errorf("got %q", out.Summary)
+	}
+}
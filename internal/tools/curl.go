@@ -0,0 +1,679 @@
+package tools
+
+import (
+	"cmp"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/c0tton-fluff/burp-mcp-server/internal/burp"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// ToCurlInput is the input for burp_to_curl.
+type ToCurlInput struct {
+	Index    int    `json:"index,omitempty" jsonschema:"Proxy history index (1-based) of the request; use this or raw"`
+	Raw      string `json:"raw,omitempty" jsonschema:"Raw HTTP request"`
+	Host     string `json:"host,omitempty" jsonschema:"Target host (overrides Host header)"`
+	Port     int    `json:"port,omitempty" jsonschema:"Target port (default based on TLS)"`
+	TLS      *bool  `json:"tls,omitempty" jsonschema:"Use HTTPS (default true)"`
+	Insecure *bool  `json:"insecure,omitempty" jsonschema:"Add --insecure for HTTPS targets (default true)"`
+	Proxy    string `json:"proxy,omitempty" jsonschema:"Proxy for curl's --proxy, e.g. http://127.0.0.1:8080 to go through Burp"`
+	Instance string `json:"instance,omitempty" jsonschema:"Named Burp instance from config (default: the --burp-url connection)"`
+}
+
+// ToCurlOutput is the output of burp_to_curl.
+type ToCurlOutput struct {
+	Command string `json:"command"`
+	URL     string `json:"url"`
+}
+
+// FromCurlInput is the input for burp_from_curl.
+type FromCurlInput struct {
+	Command string `json:"command" jsonschema:"required,curl command line, as copied from a browser or script (line continuations allowed)"`
+}
+
+// FromCurlOutput is the output of burp_from_curl.
+type FromCurlOutput struct {
+	Raw   string   `json:"raw"`
+	URL   string   `json:"url"`
+	Host  string   `json:"host"`
+	Port  int      `json:"port"`
+	TLS   bool     `json:"tls"`
+	Notes []string `json:"notes,omitempty"`
+}
+
+// shellQuote single-quotes s for a POSIX shell.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// printfQuote renders data as a single-quoted printf format that
+// reproduces it byte for byte, for bodies a shell argument can't carry.
+func printfQuote(data string) string {
+	var b strings.Builder
+	b.WriteByte('\'')
+	for i := 0; i < len(data); i++ {
+		c := data[i]
+		switch {
+		case c == '%':
+			b.WriteString("%%")
+		case c == '\\' || c == '\'' || c < 0x20 || c > 0x7e:
+			fmt.Fprintf(&b, `\%03o`, c)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	b.WriteByte('\'')
+	return b.String()
+}
+
+// curlURL builds the URL curl is given for r sent to t. An absolute-form
+// request target is used as it is.
+func curlURL(r *rawRequest, t resolvedTarget) string {
+	if strings.Contains(r.target, "://") {
+		return r.target
+	}
+	scheme := "https"
+	if !t.UseTLS {
+		scheme = "http"
+	}
+	host := t.Host
+	if strings.Contains(host, ":") {
+		host = "[" + host + "]"
+	}
+	if (t.UseTLS && t.Port != 443) || (!t.UseTLS && t.Port != 80) {
+		host += ":" + strconv.Itoa(t.Port)
+	}
+	return scheme + "://" + host + r.target
+}
+
+// toCurl renders r as a curl command line sending the same request to t.
+// Content-Length is left to curl, and Host too when the URL implies it.
+func toCurl(r *rawRequest, t resolvedTarget, insecure bool, proxy string) (command, target string) {
+	target = curlURL(r, t)
+	args := []string{"curl"}
+	switch {
+	case r.method == "HEAD" && r.body == "":
+		args = append(args, "--head")
+	case r.method == "GET" && r.body == "":
+	case r.method == "POST" && r.body != "":
+	default:
+		args = append(args, "-X", shellQuote(r.method))
+	}
+	switch strings.ToUpper(r.version) {
+	case "HTTP/1.0":
+		args = append(args, "--http1.0")
+	case "HTTP/2", "HTTP/2.0":
+		args = append(args, "--http2")
+	default:
+		args = append(args, "--http1.1")
+	}
+	if t.UseTLS && insecure {
+		args = append(args, "--insecure")
+	}
+	if proxy != "" {
+		args = append(args, "--proxy", shellQuote(proxy))
+	}
+	if strings.Contains(r.target, "/.") {
+		args = append(args, "--path-as-is")
+	}
+
+	implied := strings.TrimPrefix(strings.TrimPrefix(target, "https://"), "http://")
+	implied, _, _ = strings.Cut(implied, "/")
+	for _, line := range r.headers {
+		name := headerName(line)
+		if name == "" {
+			continue
+		}
+		_, value, _ := strings.Cut(line, ":")
+		value = strings.TrimSpace(value)
+		switch {
+		case strings.EqualFold(name, "Content-Length"):
+			continue
+		case strings.EqualFold(name, "Host") && strings.EqualFold(value, implied):
+			continue
+		case value == "":
+			// "Name;" is curl's spelling of a header with an empty value.
+			args = append(args, "-H", shellQuote(name+";"))
+		default:
+			args = append(args, "-H", shellQuote(name+": "+value))
+		}
+	}
+
+	binary := strings.IndexByte(r.body, 0) >= 0 || !utf8.ValidString(r.body)
+	switch {
+	case r.body == "":
+	case binary:
+		args = append(args, "--data-binary", "@-")
+	default:
+		args = append(args, "--data-binary", shellQuote(r.body))
+	}
+	args = append(args, shellQuote(target))
+	command = strings.Join(args, " ")
+	if r.body != "" && binary {
+		command = "printf " + printfQuote(r.body) + " | " + command
+	}
+	return command, target
+}
+
+// shellWords splits a shell command line into words, handling single and
+// double quotes, $'...' escapes, backslashes, and line continuations.
+func shellWords(line string) ([]string, error) {
+	var words []string
+	var cur strings.Builder
+	inWord := false
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case c == '\\' && i+1 < len(line):
+			i++
+			if line[i] == '\n' || (line[i] == '\r' && i+1 < len(line) && line[i+1] == '\n') {
+				if line[i] == '\r' {
+					i++
+				}
+				continue
+			}
+			cur.WriteByte(line[i])
+			inWord = true
+		case c == '\'':
+			end := strings.IndexByte(line[i+1:], '\'')
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated single quote")
+			}
+			cur.WriteString(line[i+1 : i+1+end])
+			i += end + 1
+			inWord = true
+		case c == '$' && i+1 < len(line) && line[i+1] == '\'':
+			n, err := ansiCQuote(&cur, line[i+2:])
+			if err != nil {
+				return nil, err
+			}
+			i += n + 1
+			inWord = true
+		case c == '"':
+			i++
+			for ; i < len(line) && line[i] != '"'; i++ {
+				if line[i] == '\\' && i+1 < len(line) && strings.IndexByte("\"\\$`\n", line[i+1]) >= 0 {
+					i++
+					if line[i] == '\n' {
+						continue
+					}
+				}
+				cur.WriteByte(line[i])
+			}
+			if i >= len(line) {
+				return nil, fmt.Errorf("unterminated double quote")
+			}
+			inWord = true
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			if inWord {
+				words = append(words, cur.String())
+				cur.Reset()
+				inWord = false
+			}
+		default:
+			cur.WriteByte(c)
+			inWord = true
+		}
+	}
+	if inWord {
+		words = append(words, cur.String())
+	}
+	return words, nil
+}
+
+// ansiCQuote decodes the body of a $'...' word from s into b and returns
+// how many bytes of s it used, the closing quote included.
+func ansiCQuote(b *strings.Builder, s string) (int, error) {
+	simple := map[byte]byte{'n': '\n', 'r': '\r', 't': '\t', '\\': '\\', '\'': '\'', '"': '"', 'a': 7, 'b': 8, 'e': 27, 'f': 12, 'v': 11}
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c == '\'' {
+			return i + 1, nil
+		}
+		if c != '\\' || i+1 >= len(s) {
+			b.WriteByte(c)
+			continue
+		}
+		i++
+		switch e := s[i]; {
+		case e == 'x':
+			j := i + 1
+			for j < len(s) && j < i+3 && strings.IndexByte("0123456789abcdefABCDEF", s[j]) >= 0 {
+				j++
+			}
+			v, err := strconv.ParseUint(s[i+1:j], 16, 8)
+			if err != nil {
+				return 0, fmt.Errorf("bad \\x escape in $'...'")
+			}
+			b.WriteByte(byte(v))
+			i = j - 1
+		case e >= '0' && e <= '7':
+			j := i
+			for j < len(s) && j < i+3 && s[j] >= '0' && s[j] <= '7' {
+				j++
+			}
+			v, _ := strconv.ParseUint(s[i:j], 8, 8)
+			b.WriteByte(byte(v))
+			i = j - 1
+		case simple[e] != 0:
+			b.WriteByte(simple[e])
+		default:
+			b.WriteByte('\\')
+			b.WriteByte(e)
+		}
+	}
+	return 0, fmt.Errorf("unterminated $'...' quote")
+}
+
+// curlValueOptions maps the curl options that take a value to a canonical
+// name, short forms included.
+var curlValueOptions = map[string]string{
+	"-X": "request", "--request": "request",
+	"-H": "header", "--header": "header",
+	"-d": "data", "--data": "data", "--data-ascii": "data",
+	"--data-raw": "data-raw", "--data-binary": "data-binary",
+	"--data-urlencode": "data-urlencode", "--json": "json",
+	"-F": "form", "--form": "form", "--form-string": "form-string",
+	"-b": "cookie", "--cookie": "cookie",
+	"-A": "user-agent", "--user-agent": "user-agent",
+	"-e": "referer", "--referer": "referer",
+	"-u": "user", "--user": "user",
+	"--url": "url",
+	// Options that don't change the request bytes; their values are skipped.
+	"-x": "", "--proxy": "", "-U": "", "--proxy-user": "", "-o": "", "--output": "",
+	"-m": "", "--max-time": "", "--connect-timeout": "", "-w": "", "--write-out": "",
+	"--resolve": "", "--connect-to": "", "--cacert": "", "-E": "", "--cert": "", "--key": "",
+	"--retry": "", "--max-redirs": "", "--limit-rate": "", "-c": "", "--cookie-jar": "",
+	"-K": "", "--config": "", "-r": "", "--range": "", "-T": "", "--upload-file": "",
+}
+
+// curlFlagOptions maps the value-less curl options that change the request.
+var curlFlagOptions = map[string]string{
+	"-G": "get", "--get": "get",
+	"-I": "head", "--head": "head",
+	"-0": "http1.0", "--http1.0": "http1.0", "--http1.1": "http1.1",
+	"--http2": "http2", "--http2-prior-knowledge": "http2",
+	"--compressed": "compressed", "--path-as-is": "path-as-is",
+}
+
+// curlRequest collects what a curl command line says about its request.
+type curlRequest struct {
+	method, url, version string
+	headers              []string
+	data                 []string
+	form                 []burp.MultipartPart
+	json, get, head      bool
+	compressed, pathAsIs bool
+	userAgent, referer   string
+	cookie, user         string
+	notes                []string
+}
+
+// parseCurl reads the options of a curl command line.
+func parseCurl(command string) (*curlRequest, error) {
+	words, err := shellWords(command)
+	if err != nil {
+		return nil, err
+	}
+	if len(words) > 0 && (words[0] == "curl" || strings.HasSuffix(words[0], "/curl") || strings.EqualFold(words[0], "curl.exe")) {
+		words = words[1:]
+	}
+
+	c := &curlRequest{}
+	for i := 0; i < len(words); i++ {
+		w := words[i]
+		if !strings.HasPrefix(w, "-") || w == "-" {
+			if c.url != "" {
+				return nil, fmt.Errorf("more than one URL: %q and %q", c.url, w)
+			}
+			c.url = w
+			continue
+		}
+
+		// Split bundled short options (-sSLk, -XPOST) into one option and
+		// its attached value, applying the leading flags as they go.
+		opt, attached := w, ""
+		if !strings.HasPrefix(w, "--") && len(w) > 2 {
+			for j := 1; j < len(w); j++ {
+				short := "-" + string(w[j])
+				if _, ok := curlValueOptions[short]; ok {
+					opt, attached = short, w[j+1:]
+					break
+				}
+				if j == len(w)-1 {
+					opt = short
+					break
+				}
+				c.flag(short)
+			}
+		}
+
+		name, takesValue := curlValueOptions[opt]
+		if !takesValue {
+			c.flag(opt)
+			continue
+		}
+		value := attached
+		if value == "" {
+			if i+1 >= len(words) {
+				return nil, fmt.Errorf("%s needs a value", opt)
+			}
+			i++
+			value = words[i]
+		}
+		if err := c.option(opt, name, value); err != nil {
+			return nil, err
+		}
+	}
+	if c.url == "" {
+		return nil, fmt.Errorf("no URL in the curl command")
+	}
+	return c, nil
+}
+
+// flag applies a value-less option, noting ones that don't shape the request.
+func (c *curlRequest) flag(opt string) {
+	switch curlFlagOptions[opt] {
+	case "get":
+		c.get = true
+	case "head":
+		c.head = true
+	case "compressed":
+		c.compressed = true
+	case "http1.0":
+		c.version = "HTTP/1.0"
+	case "http1.1":
+		c.version = "HTTP/1.1"
+	case "http2":
+		c.version = "HTTP/2"
+	case "path-as-is":
+		c.pathAsIs = true
+	default:
+		c.notes = append(c.notes, "ignored "+opt)
+	}
+}
+
+// option applies an option with its value.
+func (c *curlRequest) option(opt, name, value string) error {
+	fromFile := func() error {
+		return fmt.Errorf("%s %s reads a file; paste its content with --data-raw or -H instead", opt, value)
+	}
+	switch name {
+	case "":
+		c.notes = append(c.notes, fmt.Sprintf("ignored %s %s", opt, value))
+	case "request":
+		c.method = value
+	case "header":
+		if strings.HasPrefix(value, "@") {
+			return fromFile()
+		}
+		c.headers = append(c.headers, value)
+	case "data", "data-binary", "json":
+		if strings.HasPrefix(value, "@") {
+			return fromFile()
+		}
+		if name == "data" {
+			// -d drops newlines, as curl does for its file form too.
+			value = strings.NewReplacer("\r", "", "\n", "").Replace(value)
+		}
+		c.data = append(c.data, value)
+		c.json = c.json || name == "json"
+	case "data-raw":
+		c.data = append(c.data, value)
+	case "data-urlencode":
+		key, content, hasEq := strings.Cut(value, "=")
+		switch {
+		case !hasEq && strings.Contains(value, "@"):
+			return fromFile()
+		case !hasEq:
+			c.data = append(c.data, url.QueryEscape(value))
+		case key == "":
+			c.data = append(c.data, url.QueryEscape(content))
+		default:
+			c.data = append(c.data, key+"="+url.QueryEscape(content))
+		}
+	case "form", "form-string":
+		key, content, ok := strings.Cut(value, "=")
+		if !ok {
+			return fmt.Errorf("%s %q: expected name=content", opt, value)
+		}
+		part := burp.MultipartPart{Name: key, Data: content}
+		if name == "form" {
+			if strings.HasPrefix(content, "@") || strings.HasPrefix(content, "<") {
+				return fmt.Errorf("%s %s uploads a file; build the body with burp_build_multipart instead", opt, value)
+			}
+			if v, typ, ok := strings.Cut(content, ";type="); ok {
+				part.Data, part.ContentType = v, typ
+			}
+		}
+		c.form = append(c.form, part)
+	case "cookie":
+		if !strings.Contains(value, "=") {
+			c.notes = append(c.notes, fmt.Sprintf("ignored %s %s: a cookie file, not cookies", opt, value))
+			return nil
+		}
+		c.cookie = value
+	case "user-agent":
+		c.userAgent = value
+	case "referer":
+		c.referer = value
+	case "user":
+		c.user = value
+	case "url":
+		if c.url != "" {
+			return fmt.Errorf("more than one URL: %q and %q", c.url, value)
+		}
+		c.url = value
+	}
+	return nil
+}
+
+// build assembles the raw request curl would send, with its target.
+func (c *curlRequest) build() (string, resolvedTarget, error) {
+	u := c.url
+	scheme := "http"
+	if s, rest, ok := strings.Cut(u, "://"); ok {
+		scheme, u = strings.ToLower(s), rest
+	}
+	if scheme != "http" && scheme != "https" {
+		return "", resolvedTarget{}, fmt.Errorf("unsupported scheme %q", scheme)
+	}
+	authority, path := u, "/"
+	if i := strings.IndexAny(u, "/?#"); i >= 0 {
+		authority, path = u[:i], u[i:]
+		if path[0] != '/' {
+			path = "/" + path
+		}
+	}
+	path, _, _ = strings.Cut(path, "#")
+	if at := strings.LastIndexByte(authority, '@'); at >= 0 {
+		if c.user == "" {
+			c.user, _ = url.PathUnescape(authority[:at])
+		}
+		authority = authority[at+1:]
+	}
+	useTLS := scheme == "https"
+	t, err := resolveTarget("", 0, &useTLS, authority)
+	if err != nil {
+		return "", resolvedTarget{}, err
+	}
+	if strings.HasPrefix(authority, "[") && !strings.Contains(authority, "]:") {
+		t.Host = strings.Trim(authority, "[]")
+	}
+	if !c.pathAsIs && (strings.Contains(path, "/./") || strings.Contains(path, "/../") || strings.HasSuffix(path, "/.") || strings.HasSuffix(path, "/..")) {
+		c.notes = append(c.notes, "curl squashes dot segments in the path without --path-as-is; the path is kept as written")
+	}
+
+	body := strings.Join(c.data, "&")
+	if c.get && body != "" {
+		sep := "?"
+		if strings.Contains(path, "?") {
+			sep = "&"
+		}
+		path += sep + body
+		body = ""
+	}
+	var contentType string
+	switch {
+	case len(c.form) > 0:
+		if body != "" {
+			return "", resolvedTarget{}, fmt.Errorf("-d and -F can't be combined")
+		}
+		if body, contentType, err = burp.BuildMultipart(c.form); err != nil {
+			return "", resolvedTarget{}, err
+		}
+	case c.json:
+		contentType = "application/json"
+	case body != "":
+		contentType = "application/x-www-form-urlencoded"
+	}
+
+	method := c.method
+	switch {
+	case method != "":
+	case c.head:
+		method = "HEAD"
+	case body != "":
+		method = "POST"
+	default:
+		method = "GET"
+	}
+
+	r := &rawRequest{method: method, target: path, version: cmp.Or(c.version, "HTTP/1.1")}
+	hostValue := authority
+	if h, p, err := net.SplitHostPort(authority); err == nil && ((useTLS && p == "443") || (!useTLS && p == "80")) {
+		hostValue = h
+		if strings.Contains(h, ":") {
+			hostValue = "[" + h + "]"
+		}
+	}
+	r.headers = []string{"Host: " + hostValue}
+	removed := map[string]bool{}
+	for _, h := range c.headers {
+		name, value, ok := strings.Cut(h, ":")
+		switch {
+		case ok && strings.TrimSpace(value) == "":
+			// "Name:" removes a header curl would add.
+			removed[strings.ToLower(strings.TrimSpace(name))] = true
+			r.removeHeader(strings.TrimSpace(name))
+		case ok && strings.EqualFold(strings.TrimSpace(name), "Host"):
+			r.setHeader("Host", strings.TrimSpace(value))
+		case ok:
+			r.headers = append(r.headers, strings.TrimSpace(name)+": "+strings.TrimSpace(value))
+		case strings.HasSuffix(h, ";"):
+			r.headers = append(r.headers, strings.TrimSuffix(h, ";")+":")
+		default:
+			c.notes = append(c.notes, fmt.Sprintf("ignored malformed header %q", h))
+		}
+	}
+	has := func(name string) bool {
+		if removed[strings.ToLower(name)] {
+			return true
+		}
+		for _, line := range r.headers {
+			if strings.EqualFold(headerName(line), name) {
+				return true
+			}
+		}
+		return false
+	}
+	add := func(name, value string) {
+		if value != "" && !has(name) {
+			r.headers = append(r.headers, name+": "+value)
+		}
+	}
+	add("User-Agent", c.userAgent)
+	add("Referer", c.referer)
+	add("Cookie", c.cookie)
+	if c.user != "" {
+		add("Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte(c.user)))
+	}
+	if c.json {
+		add("Accept", "application/json")
+	}
+	add("Accept", "*/*")
+	if c.compressed {
+		add("Accept-Encoding", "deflate, gzip")
+	}
+	add("Content-Type", contentType)
+	r.body = body
+	return fixContentLength(r.String()), t, nil
+}
+
+func toCurlHandler(client *burp.Client) func(context.Context, *mcp.CallToolRequest, ToCurlInput) (*mcp.CallToolResult, ToCurlOutput, error) {
+	return func(ctx context.Context, _ *mcp.CallToolRequest, input ToCurlInput) (*mcp.CallToolResult, ToCurlOutput, error) {
+		ctx = burp.WithInstance(ctx, input.Instance)
+
+		raw := input.Raw
+		switch {
+		case input.Index > 0 && raw != "":
+			return nil, ToCurlOutput{}, fmt.Errorf("use index or raw, not both")
+		case input.Index > 0:
+			req, _, err := historyEntry(ctx, client, input.Index)
+			if err != nil {
+				return nil, ToCurlOutput{}, err
+			}
+			raw = req
+		case raw == "":
+			return nil, ToCurlOutput{}, fmt.Errorf("index or raw is required")
+		}
+		if err := validateRawRequest(raw); err != nil {
+			return nil, ToCurlOutput{}, err
+		}
+		r, err := splitRawRequest(raw)
+		if err != nil {
+			return nil, ToCurlOutput{}, err
+		}
+		t, err := resolveTarget(input.Host, input.Port, input.TLS, burp.ParseRawRequest(raw).Host)
+		if err != nil {
+			return nil, ToCurlOutput{}, err
+		}
+		insecure := input.Insecure == nil || *input.Insecure
+		command, target := toCurl(r, t, insecure, input.Proxy)
+		return nil, ToCurlOutput{Command: command, URL: target}, nil
+	}
+}
+
+func fromCurlHandler() func(context.Context, *mcp.CallToolRequest, FromCurlInput) (*mcp.CallToolResult, FromCurlOutput, error) {
+	return func(_ context.Context, _ *mcp.CallToolRequest, input FromCurlInput) (*mcp.CallToolResult, FromCurlOutput, error) {
+		if strings.TrimSpace(input.Command) == "" {
+			return nil, FromCurlOutput{}, fmt.Errorf("command is required")
+		}
+		c, err := parseCurl(input.Command)
+		if err != nil {
+			return nil, FromCurlOutput{}, err
+		}
+		raw, t, err := c.build()
+		if err != nil {
+			return nil, FromCurlOutput{}, err
+		}
+		return nil, FromCurlOutput{Raw: raw, URL: c.url, Host: t.Host, Port: t.Port, TLS: t.UseTLS, Notes: c.notes}, nil
+	}
+}
+
+// RegisterToCurlTool registers the burp_to_curl tool.
+func RegisterToCurlTool(server *mcp.Server, client *burp.Client) {
+	addTool(server, &mcp.Tool{
+		Name: "burp_to_curl",
+		Description: `Convert a proxy history entry (index) or raw request into an equivalent curl command: method, HTTP version, headers, and body byte for byte (binary bodies are piped in with printf). ` +
+			`Adds --insecure for HTTPS (insecure=false drops it), --proxy when proxy is given, and --path-as-is for dot segments. ` +
+			`Returns {command, url}.`,
+	}, toCurlHandler(client))
+}
+
+// RegisterFromCurlTool registers the burp_from_curl tool.
+func RegisterFromCurlTool(server *mcp.Server) {
+	addTool(server, &mcp.Tool{
+		Name: "burp_from_curl",
+		Description: `Parse a curl command line (e.g. a browser's "Copy as cURL") into the raw request curl would send, ready for burp_send_request. ` +
+			`Handles -X, -H, -d/--data-raw/--data-binary/--data-urlencode/--json, -F fields, -G, -I, -b, -A, -e, -u, --compressed, and HTTP version flags; options that don't change the request are listed in notes. File arguments (@file) are refused. ` +
+			`Returns {raw, url, host, port, tls, notes}.`,
+	}, fromCurlHandler())
+}
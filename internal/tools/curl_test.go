@@ -0,0 +1,142 @@
+package tools
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestShellWords(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []string
+	}{
+		{`curl 'https://x/a b' -H "A: \"q\" \$x"`, []string{"curl", "https://x/a b", "-H", `A: "q" $x`}},
+		{"curl \\\n  -d 'a'\\''b' x", []string{"curl", "-d", "a'b", "x"}},
+		{`curl --data-binary $'a\r\n\x00\101' u`, []string{"curl", "--data-binary", "a\r\n\x00A", "u"}},
+		{`a\ b c""d`, []string{"a b", "cd"}},
+	}
+	for _, tt := range tests {
+		got, err := shellWords(tt.in)
+		if err != nil {
+			t.Errorf("%q: %v", tt.in, err)
+			continue
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("%q = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+	for _, in := range []string{`curl 'x`, `curl "x`, `curl $'x`} {
+		if _, err := shellWords(in); err == nil {
+			t.Errorf("%q: expected error", in)
+		}
+	}
+}
+
+func TestToCurl(t *testing.T) {
+	r, _ := splitRawRequest("PUT /api/v1/items?id=1 HTTP/1.1\r\nHost: example.com:8443\r\nContent-Type: application/json\r\nContent-Length: 13\r\nX-Empty:\r\n\r\n{\"n\":\"it's\"}")
+	cmd, url := toCurl(r, resolvedTarget{Host: "example.com", Port: 8443, UseTLS: true}, true, "http://127.0.0.1:8080")
+	want := `curl -X 'PUT' --http1.1 --insecure --proxy 'http://127.0.0.1:8080' -H 'Content-Type: application/json' -H 'X-Empty;' --data-binary '{"n":"it'\''s"}' 'https://example.com:8443/api/v1/items?id=1'`
+	if cmd != want || url != "https://example.com:8443/api/v1/items?id=1" {
+		t.Errorf("command = %s\nwant      %s", cmd, want)
+	}
+
+	r, _ = splitRawRequest("POST /../etc HTTP/2\r\nHost: other\r\n\r\n\x00\xff%")
+	cmd, _ = toCurl(r, resolvedTarget{Host: "example.com", Port: 80}, true, "")
+	want = `printf '\000\377%%' | curl --http2 --path-as-is -H 'Host: other' --data-binary @- 'http://example.com/../etc'`
+	if cmd != want {
+		t.Errorf("command = %s\nwant      %s", cmd, want)
+	}
+}
+
+func TestFromCurl(t *testing.T) {
+	tests := []struct {
+		command string
+		want    string
+		host    string
+		port    int
+		tls     bool
+	}{
+		{
+			`curl 'https://example.com/api?x=1' -H 'Authorization: Bearer t' --data-raw '{"a":1}' -H 'Content-Type: application/json' --compressed -sk`,
+			"POST /api?x=1 HTTP/1.1\r\nHost: example.com\r\nAuthorization: Bearer t\r\nContent-Type: application/json\r\nAccept: */*\r\nAccept-Encoding: deflate, gzip\r\nContent-Length: 7\r\n\r\n{\"a\":1}",
+			"example.com", 443, true,
+		},
+		{
+			`curl -G -d q=a -d 'r=b' --data-urlencode 's=a b' -u user:pw -A ua -b 'c=1' http://h:8080/s#frag`,
+			"GET /s?q=a&r=b&s=a+b HTTP/1.1\r\nHost: h:8080\r\nUser-Agent: ua\r\nCookie: c=1\r\nAuthorization: Basic dXNlcjpwdw==\r\nAccept: */*\r\n\r\n",
+			"h", 8080, false,
+		},
+		{
+			`curl -XDELETE --http2 -H 'Accept:' https://[::1]/r`,
+			"DELETE /r HTTP/2\r\nHost: [::1]\r\n\r\n",
+			"::1", 443, true,
+		},
+		{
+			`curl -I example.com`,
+			"HEAD / HTTP/1.1\r\nHost: example.com\r\nAccept: */*\r\n\r\n",
+			"example.com", 80, false,
+		},
+	}
+	for _, tt := range tests {
+		c, err := parseCurl(tt.command)
+		if err != nil {
+			t.Errorf("%s: %v", tt.command, err)
+			continue
+		}
+		raw, target, err := c.build()
+		if err != nil {
+			t.Errorf("%s: %v", tt.command, err)
+			continue
+		}
+		if raw != tt.want {
+			t.Errorf("%s:\nraw  %q\nwant %q", tt.command, raw, tt.want)
+		}
+		if target.Host != tt.host || target.Port != tt.port || target.UseTLS != tt.tls {
+			t.Errorf("%s: target = %+v", tt.command, target)
+		}
+	}
+
+	c, err := parseCurl(`curl -F name=value -F 'f=<x;type=text/plain' u`)
+	if err == nil {
+		t.Errorf("file form part: expected error, got %+v", c)
+	}
+	for _, cmd := range []string{`curl -d @body.json u`, `curl`, `curl a b`, `curl -H`, `curl ftp://x`} {
+		c, err := parseCurl(cmd)
+		if err == nil {
+			_, _, err = c.build()
+		}
+		if err == nil {
+			t.Errorf("%s: expected error", cmd)
+		}
+	}
+
+	c, _ = parseCurl(`curl -F name=value -v -o out https://x/up`)
+	raw, _, err := c.build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(raw, "Content-Type: multipart/form-data; boundary=") || !strings.Contains(raw, "name=\"name\"\r\n\r\nvalue\r\n") {
+		t.Errorf("multipart raw = %q", raw)
+	}
+	if !slices.Equal(c.notes, []string{"ignored -v", "ignored -o out"}) {
+		t.Errorf("notes = %q", c.notes)
+	}
+}
+
+func TestCurlRoundTrip(t *testing.T) {
+	raw := "PATCH /a/b?c=d HTTP/1.1\r\nHost: example.com\r\nX-Token: a'b\"c\r\nAccept: */*\r\nContent-Type: text/plain\r\nContent-Length: 11\r\n\r\nline1\nline2"
+	r, _ := splitRawRequest(raw)
+	cmd, _ := toCurl(r, resolvedTarget{Host: "example.com", Port: 443, UseTLS: true}, true, "")
+	c, err := parseCurl(cmd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, _, err := c.build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != raw {
+		t.Errorf("round trip:\ngot  %q\nwant %q", got, raw)
+	}
+}
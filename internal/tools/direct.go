@@ -0,0 +1,170 @@
+package tools
+
+import (
+	"bufio"
+	"cmp"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+)
+
+// directTimeout bounds a single direct (non-Burp) request.
+const directTimeout = 30 * time.Second
+
+// sendDirect writes raw bytes straight to the target, bypassing Burp, and
+// returns the raw response. It reuses a kept-alive connection to the same
+// target from directPool when there is one, and opens a fresh TCP/TLS
+// connection otherwise. Used where exact bytes matter and Burp would
+// rewrite the request.
+//
+// A pooled connection the server closed while idle fails with EOF or a
+// reset, but so can one that dropped after handling the request, so only
+// idempotent requests are retried on a fresh connection, as net/http does.
+func sendDirect(ctx context.Context, t resolvedTarget, raw []byte, opts directOptions) (string, error) {
+	key, err := checkDirect(ctx, t, opts)
+	if err != nil {
+		return "", err
+	}
+	if !opts.NewConnection {
+		if c := directPool.get(ctx, key); c != nil {
+			resp, err := c.roundTrip(raw)
+			if !staleConn(err) || !idempotent(raw) {
+				releaseDirect(key, c, raw, resp, err, opts)
+				return resp, err
+			}
+			// The server closed the idle connection; retry on a fresh one.
+			c.Close()
+		}
+	}
+	c, err := dialDirect(ctx, t, opts, key)
+	if err != nil {
+		return "", err
+	}
+	resp, err := c.roundTrip(raw)
+	releaseDirect(key, c, raw, resp, err, opts)
+	return resp, err
+}
+
+// releaseDirect pools c after a send unless opts rule out reuse.
+func releaseDirect(key poolKey, c *directConn, raw []byte, resp string, err error, opts directOptions) {
+	if opts.NewConnection || opts.NoReuse {
+		c.Close()
+		return
+	}
+	directPool.put(key, c, raw, resp, err)
+}
+
+// idempotent reports whether raw's method is safe to send twice.
+func idempotent(raw []byte) bool {
+	method, _, _ := strings.Cut(string(raw[:min(len(raw), 16)]), " ")
+	switch method {
+	case "GET", "HEAD", "OPTIONS":
+		return true
+	}
+	return false
+}
+
+// directConn is an open direct connection. Several requests can share it
+// when the server keeps it alive, which connection-bound auth needs.
+type directConn struct {
+	ctx    context.Context
+	t      resolvedTarget
+	conn   net.Conn
+	reader *bufio.Reader
+	stop   func() bool
+	// framed is set when the last response was delimited and read to its
+	// end, so the connection can carry another request.
+	framed bool
+}
+
+// openDirect connects to the target after the dry-run and scope checks.
+func openDirect(ctx context.Context, t resolvedTarget, opts directOptions) (*directConn, error) {
+	key, err := checkDirect(ctx, t, opts)
+	if err != nil {
+		return nil, err
+	}
+	return dialDirect(ctx, t, opts, key)
+}
+
+// checkDirect runs the dry-run and scope checks for a direct send and
+// returns the pool key of its connection.
+func checkDirect(ctx context.Context, t resolvedTarget, opts directOptions) (poolKey, error) {
+	if err := checkDryRun(ctx); err != nil {
+		return poolKey{}, err
+	}
+	addr, serverName := opts.endpoints(t.Host, t.Port)
+	if err := checkScope(ctx, t.Host, addr); err != nil {
+		return poolKey{}, err
+	}
+	return newPoolKey(t, addr, serverName, opts.TLS), nil
+}
+
+// dialDirect opens a connection for key.
+func dialDirect(ctx context.Context, t resolvedTarget, opts directOptions, key poolKey) (*directConn, error) {
+	addr, serverName := key.addr, key.serverName
+
+	var tlsCfg *tls.Config
+	if t.UseTLS {
+		var err error
+		if tlsCfg, err = buildTLSConfig(serverName, opts.TLS); err != nil {
+			return nil, err
+		}
+	}
+
+	deadline := time.Now().Add(directTimeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+
+	conn, err := dialConn(ctx, addr, tlsCfg, deadline)
+	if err != nil {
+		return nil, fmt.Errorf("connect %s: %w", addr, err)
+	}
+	c := &directConn{t: t, conn: conn, reader: bufio.NewReaderSize(conn, 32*1024)}
+	c.bind(ctx)
+	return c, nil
+}
+
+// bind ties c to the call in ctx: closing the connection when ctx ends
+// interrupts a pending read, so a canceled call or task doesn't wait out
+// the timeout on a slow target.
+func (c *directConn) bind(ctx context.Context) {
+	deadline := time.Now().Add(directTimeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	c.conn.SetDeadline(deadline)
+	c.ctx = ctx
+	c.stop = context.AfterFunc(ctx, func() { c.conn.Close() })
+}
+
+// roundTrip writes one request and reads its response.
+func (c *directConn) roundTrip(raw []byte) (string, error) {
+	if err := checkRateLimit(c.ctx, c.t, 1); err != nil {
+		return "", err
+	}
+	release, err := acquireHost(c.ctx, c.t)
+	if err != nil {
+		return "", err
+	}
+	defer release()
+	recordRequests(c.ctx, c.t, string(raw), 1)
+	if _, err := c.conn.Write(raw); err != nil {
+		return "", fmt.Errorf("write: %w", cmp.Or(c.ctx.Err(), err))
+	}
+	resp, framed, err := readResponse(c.reader)
+	recordResponse(c.t, len(resp))
+	if err != nil && c.ctx.Err() != nil {
+		return "", c.ctx.Err()
+	}
+	c.framed = framed
+	return resp, err
+}
+
+func (c *directConn) Close() error {
+	c.stop()
+	return c.conn.Close()
+}
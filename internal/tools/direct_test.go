@@ -0,0 +1,197 @@
+package tools
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// startTestTarget starts a plain HTTP test server and returns its resolved target.
+func startTestTarget(t *testing.T, handler http.HandlerFunc) resolvedTarget {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	host, port, err := net.SplitHostPort(strings.TrimPrefix(srv.URL, "http://"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, _ := strconv.Atoi(port)
+	return resolvedTarget{Host: host, Port: p, UseTLS: false}
+}
+
+// startRawTarget starts a TCP server that records the bytes of each
+// connection until the client goes quiet, then answers 200 ok.
+func startRawTarget(t *testing.T) (resolvedTarget, <-chan []byte) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+	received := make(chan []byte, 64)
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				var got []byte
+				buf := make([]byte, 4096)
+				for {
+					conn.SetReadDeadline(time.Now().Add(200 * time.Millisecond))
+					n, err := conn.Read(buf)
+					got = append(got, buf[:n]...)
+					if err != nil {
+						break
+					}
+				}
+				received <- got
+				conn.Write([]byte("HTTP/1.1 200 OK\r\nContent-Length: 2\r\nConnection: close\r\n\r\nok"))
+			}()
+		}
+	}()
+	return resolvedTarget{Host: "127.0.0.1", Port: ln.Addr().(*net.TCPAddr).Port}, received
+}
+
+func TestSendRequest_Exact(t *testing.T) {
+	target, received := startRawTarget(t)
+	noTLS, no := false, false
+	input := SendRequestInput{Host: target.Host, Port: target.Port, TLS: &noTLS, Direct: true}
+
+	exact := []struct {
+		name, raw, b64, want string
+	}{
+		{"bare LF, no blank line", "GET /a HTTP/1.1\nHost: x\nX: y", "", "GET /a HTTP/1.1\nHost: x\nX: y"},
+		{"base64 with CR and NUL", "", "R0VUIC9iIEhUVFAvMS4xDUhvc3Q6IHgNCgANCg0K", "GET /b HTTP/1.1\rHost: x\r\n\x00\r\n\r\n"},
+	}
+	for _, tt := range exact {
+		in := input
+		in.Raw, in.RawBase64 = tt.raw, tt.b64
+		if tt.raw != "" {
+			in.Normalize = &no
+		}
+		_, out, err := sendRequestHandler(nil)(context.Background(), nil, in)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if got := string(<-received); got != tt.want || out.StatusCode != 200 {
+			t.Errorf("%s: sent %q (status %d), want %q", tt.name, got, out.StatusCode, tt.want)
+		}
+	}
+
+	in := input
+	in.Raw = "GET /c HTTP/1.1\nHost: x\n"
+	if _, _, err := sendRequestHandler(nil)(context.Background(), nil, in); err != nil {
+		t.Fatal(err)
+	}
+	if got := string(<-received); got != "GET /c HTTP/1.1\r\nHost: x\r\n\r\n" {
+		t.Errorf("normalized request sent as %q", got)
+	}
+
+	for name, in := range map[string]SendRequestInput{
+		"without direct": {Raw: "GET / HTTP/1.1\nHost: x", Normalize: &no},
+		"header profile": {Raw: "GET / HTTP/1.1\nHost: x", Normalize: &no, Direct: true, HeaderProfile: "default"},
+	} {
+		if _, _, err := sendRequestHandler(nil)(context.Background(), nil, in); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+func TestSendDirect_ExactBytes(t *testing.T) {
+	target := startTestTarget(t, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "%s %s %s", r.Method, r.URL.Path, r.Header.Get("X-Test"))
+	})
+
+	raw := "GET /echo HTTP/1.1\r\nHost: example.com\r\nX-Test: yes\r\n\r\n"
+	resp, err := sendDirect(context.Background(), target, []byte(raw), directOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(resp, "HTTP/1.1 200") || !strings.HasSuffix(resp, "GET /echo yes") {
+		t.Errorf("unexpected response: %q", resp)
+	}
+}
+
+func TestSendDirect_ConnectError(t *testing.T) {
+	target := resolvedTarget{Host: "127.0.0.1", Port: 1}
+	if _, err := sendDirect(context.Background(), target, []byte("GET / HTTP/1.1\r\n\r\n"), directOptions{}); err == nil {
+		t.Error("expected connect error")
+	}
+}
+
+func TestSendDirect_ConnectHost(t *testing.T) {
+	target := startTestTarget(t, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, r.Host)
+	})
+	addr := net.JoinHostPort(target.Host, strconv.Itoa(target.Port))
+
+	// The logical target doesn't resolve; connectHost routes the TCP connection.
+	fronted := resolvedTarget{Host: "backend.invalid", Port: target.Port}
+	raw := "GET / HTTP/1.1\r\nHost: internal.example\r\n\r\n"
+	resp, err := sendDirect(context.Background(), fronted, []byte(raw), directOptions{ConnectHost: addr})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasSuffix(resp, "internal.example") {
+		t.Errorf("Host header should be untouched: %q", resp)
+	}
+}
+
+func TestSendDirect_SNI(t *testing.T) {
+	var gotSNI string
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, r.Host)
+	}))
+	srv.TLS = &tls.Config{GetConfigForClient: func(hello *tls.ClientHelloInfo) (*tls.Config, error) {
+		gotSNI = hello.ServerName
+		return nil, nil
+	}}
+	srv.StartTLS()
+	defer srv.Close()
+
+	addr := strings.TrimPrefix(srv.URL, "https://")
+	_, port, _ := net.SplitHostPort(addr)
+	p, _ := strconv.Atoi(port)
+	target := resolvedTarget{Host: "origin.example", Port: p, UseTLS: true}
+	raw := "GET / HTTP/1.1\r\nHost: hidden.example\r\n\r\n"
+
+	resp, err := sendDirect(context.Background(), target, []byte(raw), directOptions{SNI: "front.example", ConnectHost: addr})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gotSNI != "front.example" {
+		t.Errorf("SNI = %q, want front.example", gotSNI)
+	}
+	if !strings.HasSuffix(resp, "hidden.example") {
+		t.Errorf("unexpected response: %q", resp)
+	}
+}
+
+func TestDirectOptionsEndpoints(t *testing.T) {
+	tests := []struct {
+		opts     directOptions
+		wantAddr string
+		wantSNI  string
+	}{
+		{directOptions{}, "example.com:443", "example.com"},
+		{directOptions{SNI: "cdn.example"}, "example.com:443", "cdn.example"},
+		{directOptions{ConnectHost: "10.0.0.1"}, "10.0.0.1:443", "example.com"},
+		{directOptions{ConnectHost: "10.0.0.1:8443"}, "10.0.0.1:8443", "example.com"},
+	}
+	for _, tt := range tests {
+		addr, sni := tt.opts.endpoints("example.com", 443)
+		if addr != tt.wantAddr || sni != tt.wantSNI {
+			t.Errorf("%+v: got %s/%s, want %s/%s", tt.opts, addr, sni, tt.wantAddr, tt.wantSNI)
+		}
+	}
+}
@@ -0,0 +1,263 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// dnsTimeout bounds all lookups of one burp_dns_lookup call.
+const dnsTimeout = 15 * time.Second
+
+// dnsTypes are the record types looked up when none are given.
+var dnsTypes = []string{"A", "AAAA", "CNAME", "MX", "TXT", "NS"}
+
+// dnsResolver is the subset of *net.Resolver the tool uses.
+type dnsResolver interface {
+	LookupIP(ctx context.Context, network, host string) ([]net.IP, error)
+	LookupCNAME(ctx context.Context, host string) (string, error)
+	LookupMX(ctx context.Context, name string) ([]*net.MX, error)
+	LookupTXT(ctx context.Context, name string) ([]string, error)
+	LookupNS(ctx context.Context, name string) ([]*net.NS, error)
+	LookupAddr(ctx context.Context, addr string) ([]string, error)
+}
+
+// newDNSResolver returns the system resolver, or one that sends every query
+// to server (host or host:port, port 53 by default); replaced in tests.
+var newDNSResolver = func(server string) dnsResolver {
+	if server == "" {
+		return net.DefaultResolver
+	}
+	if _, _, err := net.SplitHostPort(server); err != nil {
+		server = net.JoinHostPort(strings.Trim(server, "[]"), "53")
+	}
+	return &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+			var d net.Dialer
+			return d.DialContext(ctx, network, server)
+		},
+	}
+}
+
+// takeoverServices are CNAME fragments of hosting services whose resources
+// can be claimed by anyone once the owner deletes them.
+var takeoverServices = []struct{ fragment, service string }{
+	{".s3.amazonaws.com", "AWS S3"},
+	{".s3-website", "AWS S3 website"},
+	{".elasticbeanstalk.com", "AWS Elastic Beanstalk"},
+	{".cloudfront.net", "Amazon CloudFront"},
+	{".azurewebsites.net", "Azure App Service"},
+	{".cloudapp.net", "Azure Cloud Services"},
+	{".cloudapp.azure.com", "Azure VM"},
+	{".trafficmanager.net", "Azure Traffic Manager"},
+	{".blob.core.windows.net", "Azure Blob Storage"},
+	{".azureedge.net", "Azure CDN"},
+	{".herokuapp.com", "Heroku"},
+	{".herokudns.com", "Heroku"},
+	{".github.io", "GitHub Pages"},
+	{".gitlab.io", "GitLab Pages"},
+	{".bitbucket.io", "Bitbucket"},
+	{".netlify.app", "Netlify"},
+	{".netlify.com", "Netlify"},
+	{".vercel.app", "Vercel"},
+	{".surge.sh", "Surge"},
+	{".pantheonsite.io", "Pantheon"},
+	{".ghost.io", "Ghost"},
+	{".myshopify.com", "Shopify"},
+	{".zendesk.com", "Zendesk"},
+	{".freshdesk.com", "Freshdesk"},
+	{".helpscoutdocs.com", "Help Scout"},
+	{".readme.io", "ReadMe"},
+	{".wordpress.com", "WordPress.com"},
+	{".wpengine.com", "WP Engine"},
+	{".fly.dev", "Fly.io"},
+	{".unbouncepages.com", "Unbounce"},
+	{".webflow.io", "Webflow"},
+}
+
+// DNSLookupInput is the input for burp_dns_lookup.
+type DNSLookupInput struct {
+	Name     string   `json:"name" jsonschema:"required,Hostname to resolve, or an IP address for a reverse (PTR) lookup"`
+	Types    []string `json:"types,omitempty" jsonschema:"Record types: A, AAAA, CNAME, MX, TXT, NS (default: all)"`
+	Reverse  bool     `json:"reverse,omitempty" jsonschema:"Also reverse-resolve every A and AAAA address"`
+	Resolver string   `json:"resolver,omitempty" jsonschema:"DNS server as host or host:port, e.g. 1.1.1.1 or ns1.example.com:53 (default: system resolver)"`
+}
+
+// MXRecord is one mail exchanger.
+type MXRecord struct {
+	Host string `json:"host"`
+	Pref uint16 `json:"pref"`
+}
+
+// TakeoverHint flags a CNAME into a service that allows subdomain takeover.
+type TakeoverHint struct {
+	CNAME   string `json:"cname"`
+	Service string `json:"service"`
+	// Dangling is set when the CNAME target does not resolve, the usual
+	// precondition for claiming it.
+	Dangling bool   `json:"dangling"`
+	Note     string `json:"note"`
+}
+
+// DNSLookupOutput is the output of burp_dns_lookup.
+type DNSLookupOutput struct {
+	Name     string              `json:"name"`
+	Resolver string              `json:"resolver"`
+	A        []string            `json:"a,omitempty"`
+	AAAA     []string            `json:"aaaa,omitempty"`
+	CNAME    string              `json:"cname,omitempty"`
+	MX       []MXRecord          `json:"mx,omitempty"`
+	TXT      []string            `json:"txt,omitempty"`
+	NS       []string            `json:"ns,omitempty"`
+	PTR      map[string][]string `json:"ptr,omitempty"`
+	Takeover *TakeoverHint       `json:"takeover,omitempty"`
+	// Errors maps record type to the lookup error; "no such host" and
+	// empty answers are not errors.
+	Errors map[string]string `json:"errors,omitempty"`
+}
+
+// isNotFound reports whether err is an NXDOMAIN or empty answer.
+func isNotFound(err error) bool {
+	var dnsErr *net.DNSError
+	return errors.As(err, &dnsErr) && dnsErr.IsNotFound
+}
+
+// takeoverService returns the takeover-prone service a CNAME target
+// belongs to, or "".
+func takeoverService(cname string) string {
+	cname = strings.ToLower(strings.TrimSuffix(cname, "."))
+	for _, s := range takeoverServices {
+		if strings.Contains(cname, s.fragment) {
+			return s.service
+		}
+	}
+	return ""
+}
+
+func dnsLookupHandler() func(context.Context, *mcp.CallToolRequest, DNSLookupInput) (*mcp.CallToolResult, DNSLookupOutput, error) {
+	return func(ctx context.Context, _ *mcp.CallToolRequest, input DNSLookupInput) (*mcp.CallToolResult, DNSLookupOutput, error) {
+		name := strings.TrimSuffix(strings.TrimSpace(input.Name), ".")
+		if name == "" {
+			return nil, DNSLookupOutput{}, fmt.Errorf("name is required")
+		}
+		types := dnsTypes
+		if len(input.Types) > 0 {
+			types = nil
+			for _, t := range input.Types {
+				t = strings.ToUpper(strings.TrimSpace(t))
+				if !slices.Contains(dnsTypes, t) {
+					return nil, DNSLookupOutput{}, fmt.Errorf("unsupported record type %q (want one of %s)", t, strings.Join(dnsTypes, ", "))
+				}
+				types = append(types, t)
+			}
+		}
+		ctx, cancel := context.WithTimeout(ctx, dnsTimeout)
+		defer cancel()
+
+		r := newDNSResolver(input.Resolver)
+		out := DNSLookupOutput{Name: name, Resolver: input.Resolver, Errors: map[string]string{}}
+		if out.Resolver == "" {
+			out.Resolver = "system"
+		}
+		fail := func(typ string, err error) {
+			if err != nil && !isNotFound(err) {
+				out.Errors[typ] = err.Error()
+			}
+		}
+		ptr := func(addrs ...string) {
+			for _, a := range addrs {
+				names, err := r.LookupAddr(ctx, a)
+				fail("PTR", err)
+				if len(names) > 0 {
+					if out.PTR == nil {
+						out.PTR = map[string][]string{}
+					}
+					out.PTR[a] = names
+				}
+			}
+		}
+
+		if net.ParseIP(name) != nil {
+			// An address only has a PTR record.
+			ptr(name)
+			types = nil
+		}
+		for _, typ := range types {
+			switch typ {
+			case "A", "AAAA":
+				network := map[string]string{"A": "ip4", "AAAA": "ip6"}[typ]
+				ips, err := r.LookupIP(ctx, network, name)
+				fail(typ, err)
+				for _, ip := range ips {
+					if typ == "A" {
+						out.A = append(out.A, ip.String())
+					} else {
+						out.AAAA = append(out.AAAA, ip.String())
+					}
+				}
+			case "CNAME":
+				cname, err := r.LookupCNAME(ctx, name)
+				fail(typ, err)
+				// LookupCNAME returns the name itself when there is no CNAME.
+				if cname = strings.TrimSuffix(cname, "."); cname != "" && !strings.EqualFold(cname, name) {
+					out.CNAME = cname
+				}
+			case "MX":
+				mxs, err := r.LookupMX(ctx, name)
+				fail(typ, err)
+				for _, mx := range mxs {
+					out.MX = append(out.MX, MXRecord{Host: strings.TrimSuffix(mx.Host, "."), Pref: mx.Pref})
+				}
+			case "TXT":
+				txt, err := r.LookupTXT(ctx, name)
+				fail(typ, err)
+				out.TXT = txt
+			case "NS":
+				nss, err := r.LookupNS(ctx, name)
+				fail(typ, err)
+				for _, ns := range nss {
+					out.NS = append(out.NS, strings.TrimSuffix(ns.Host, "."))
+				}
+			}
+		}
+		if input.Reverse {
+			ptr(slices.Concat(out.A, out.AAAA)...)
+		}
+
+		if service := takeoverService(out.CNAME); service != "" {
+			hint := &TakeoverHint{CNAME: out.CNAME, Service: service}
+			_, err := r.LookupIP(ctx, "ip", out.CNAME)
+			switch {
+			case isNotFound(err):
+				hint.Dangling = true
+				hint.Note = "CNAME target does not resolve; check whether the " + service + " resource can be claimed"
+			case err != nil:
+				hint.Note = "could not resolve CNAME target: " + err.Error()
+			default:
+				hint.Note = "CNAME target resolves; fetch the host and look for the " + service + " unclaimed-resource page"
+			}
+			out.Takeover = hint
+		}
+		if len(out.Errors) == 0 {
+			out.Errors = nil
+		}
+		return nil, out, nil
+	}
+}
+
+// RegisterDNSLookupTool registers the burp_dns_lookup tool.
+func RegisterDNSLookupTool(server *mcp.Server) {
+	addTool(server, &mcp.Tool{
+		Name: "burp_dns_lookup",
+		Description: `Resolve A, AAAA, CNAME, MX, TXT, and NS records for a hostname, or reverse-resolve an IP address, through the system resolver or a custom DNS server. ` +
+			`Flags CNAMEs into hosting services prone to subdomain takeover and whether their target dangles. Use it to resolve targets or verify that a callback domain resolves. ` +
+			`Returns {name, resolver, a, aaaa, cname, mx: [{host, pref}], txt, ns, ptr: {ip: [names]}, takeover: {cname, service, dangling, note}, errors: {type: message}}.`,
+	}, dnsLookupHandler())
+}
@@ -0,0 +1,134 @@
+package tools
+
+import (
+	"context"
+	"net"
+	"testing"
+)
+
+// fakeResolver answers from fixed tables; missing names are NXDOMAIN.
+type fakeResolver struct {
+	ips   map[string][]net.IP
+	cname map[string]string
+	txt   map[string][]string
+	ptr   map[string][]string
+}
+
+func notFound(name string) error {
+	return &net.DNSError{Err: "no such host", Name: name, IsNotFound: true}
+}
+
+func (f fakeResolver) LookupIP(_ context.Context, network, host string) ([]net.IP, error) {
+	var ips []net.IP
+	for _, ip := range f.ips[host] {
+		if network == "ip" || (network == "ip4") == (ip.To4() != nil) {
+			ips = append(ips, ip)
+		}
+	}
+	if len(ips) == 0 {
+		return nil, notFound(host)
+	}
+	return ips, nil
+}
+
+func (f fakeResolver) LookupCNAME(_ context.Context, host string) (string, error) {
+	if c, ok := f.cname[host]; ok {
+		return c + ".", nil
+	}
+	return host + ".", nil
+}
+
+func (f fakeResolver) LookupMX(_ context.Context, name string) ([]*net.MX, error) {
+	return nil, notFound(name)
+}
+
+func (f fakeResolver) LookupTXT(_ context.Context, name string) ([]string, error) {
+	if txt, ok := f.txt[name]; ok {
+		return txt, nil
+	}
+	return nil, notFound(name)
+}
+
+func (f fakeResolver) LookupNS(_ context.Context, name string) ([]*net.NS, error) {
+	return nil, &net.DNSError{Err: "server misbehaving", Name: name}
+}
+
+func (f fakeResolver) LookupAddr(_ context.Context, addr string) ([]string, error) {
+	if names, ok := f.ptr[addr]; ok {
+		return names, nil
+	}
+	return nil, notFound(addr)
+}
+
+func TestDNSLookup(t *testing.T) {
+	fake := fakeResolver{
+		ips: map[string][]net.IP{
+			"www.example.com": {net.ParseIP("192.0.2.10"), net.ParseIP("2001:db8::10")},
+		},
+		cname: map[string]string{"assets.example.com": "example-assets.s3.amazonaws.com"},
+		txt:   map[string][]string{"www.example.com": {"v=spf1 -all"}},
+		ptr:   map[string][]string{"192.0.2.10": {"web1.example.com."}},
+	}
+	var server string
+	orig := newDNSResolver
+	t.Cleanup(func() { newDNSResolver = orig })
+	newDNSResolver = func(s string) dnsResolver {
+		server = s
+		return fake
+	}
+	handler := dnsLookupHandler()
+
+	_, out, err := handler(context.Background(), nil, DNSLookupInput{Name: "www.example.com.", Reverse: true, Resolver: "1.1.1.1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if server != "1.1.1.1" || out.Resolver != "1.1.1.1" {
+		t.Errorf("resolver = %q, output %q", server, out.Resolver)
+	}
+	if len(out.A) != 1 || out.A[0] != "192.0.2.10" || len(out.AAAA) != 1 || out.AAAA[0] != "2001:db8::10" {
+		t.Errorf("A = %v, AAAA = %v", out.A, out.AAAA)
+	}
+	if out.CNAME != "" || len(out.TXT) != 1 || out.Takeover != nil {
+		t.Errorf("out = %+v", out)
+	}
+	if len(out.PTR["192.0.2.10"]) != 1 || len(out.PTR) != 1 {
+		t.Errorf("PTR = %v", out.PTR)
+	}
+	// NXDOMAIN for MX is not an error; the NS failure is.
+	if len(out.Errors) != 1 || out.Errors["NS"] == "" {
+		t.Errorf("errors = %v", out.Errors)
+	}
+
+	_, out, err = handler(context.Background(), nil, DNSLookupInput{Name: "assets.example.com", Types: []string{"cname"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out.Resolver != "system" || out.CNAME != "example-assets.s3.amazonaws.com" {
+		t.Errorf("out = %+v", out)
+	}
+	if out.Takeover == nil || out.Takeover.Service != "AWS S3" || !out.Takeover.Dangling {
+		t.Errorf("takeover = %+v", out.Takeover)
+	}
+
+	_, out, err = handler(context.Background(), nil, DNSLookupInput{Name: "192.0.2.10"})
+	if err != nil || out.PTR["192.0.2.10"][0] != "web1.example.com." || out.A != nil {
+		t.Errorf("reverse: %+v, %v", out, err)
+	}
+
+	if _, _, err := handler(context.Background(), nil, DNSLookupInput{Name: "a.com", Types: []string{"SRV"}}); err == nil {
+		t.Error("SRV accepted")
+	}
+}
+
+func TestTakeoverService(t *testing.T) {
+	for cname, want := range map[string]string{
+		"foo.herokuapp.com.":                        "Heroku",
+		"bucket.s3-website-us-east-1.amazonaws.com": "AWS S3 website",
+		"org.github.io":                             "GitHub Pages",
+		"origin.example.net":                        "",
+	} {
+		if got := takeoverService(cname); got != want {
+			t.Errorf("takeoverService(%q) = %q, want %q", cname, got, want)
+		}
+	}
+}
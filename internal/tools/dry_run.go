@@ -0,0 +1,95 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+)
+
+// RequestPreview is a request rendered by dry-run mode instead of being sent.
+type RequestPreview struct {
+	URL       string `json:"url"`
+	Via       string `json:"via"` // "burp" or "direct"
+	ConnectTo string `json:"connectTo,omitempty"`
+	SNI       string `json:"sni,omitempty"`
+	Count     int    `json:"count,omitempty"`
+	Raw       string `json:"raw"`
+	// AuthProfile names the profile injected at send time; Raw leaves it out.
+	AuthProfile string `json:"authProfile,omitempty"`
+}
+
+// DryRunError is returned by tools that need live responses (probes,
+// retests) when dry-run mode is on. Nothing is sent.
+type DryRunError struct {
+	Tool string
+}
+
+func (e *DryRunError) Error() string {
+	tool := e.Tool
+	if tool == "" {
+		tool = "this tool"
+	}
+	return fmt.Sprintf("dry_run: %s needs live responses, so nothing was sent (dry-run mode is on)", tool)
+}
+
+// dryRun reports whether a call should preview instead of sending. The
+// per-call flag can only turn previews on; config dryRun forces them for
+// every call so an agent can't opt out.
+func dryRun(perCall bool) bool {
+	return perCall || settings.DryRun
+}
+
+// checkDryRun refuses to send when dry-run mode is on. Tools that can
+// preview return before reaching a send path; this guards the rest.
+func checkDryRun(ctx context.Context) error {
+	if settings.DryRun {
+		return &DryRunError{Tool: toolName(ctx)}
+	}
+	return nil
+}
+
+// previewAuthProfile notes on p that the named auth profile would be applied.
+// Previews are rendered without it, so its credentials never reach the
+// output and its login macro doesn't run for a request that isn't sent.
+func previewAuthProfile(p *RequestPreview, name string) error {
+	if name == "" {
+		return nil
+	}
+	if _, err := settings.AuthProfile(name); err != nil {
+		return err
+	}
+	p.AuthProfile = name
+	return nil
+}
+
+// previewRequest renders rawNorm as it would be sent to t. Scope is still
+// enforced, so an out-of-scope preview fails the same way a send would.
+func previewRequest(ctx context.Context, t resolvedTarget, rawNorm string, direct *directOptions) (*RequestPreview, error) {
+	if err := checkScope(ctx, t.Host); err != nil {
+		return nil, err
+	}
+	scheme := "http"
+	if t.UseTLS {
+		scheme = "https"
+	}
+	p := &RequestPreview{
+		URL: scheme + "://" + net.JoinHostPort(t.Host, strconv.Itoa(t.Port)),
+		Via: "burp",
+		Raw: rawNorm,
+	}
+	if direct != nil {
+		p.Via = "direct"
+		addr, serverName := direct.endpoints(t.Host, t.Port)
+		if err := checkScope(ctx, addr); err != nil {
+			return nil, err
+		}
+		if direct.ConnectHost != "" {
+			p.ConnectTo = addr
+		}
+		if t.UseTLS && serverName != t.Host {
+			p.SNI = serverName
+		}
+	}
+	return p, nil
+}
@@ -0,0 +1,118 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/c0tton-fluff/burp-mcp-server/internal/config"
+)
+
+func TestSendRequest_DryRun(t *testing.T) {
+	// A nil client proves nothing reaches Burp.
+	_, out, err := sendRequestHandler(nil)(context.Background(), nil, SendRequestInput{
+		Raw:    "POST /login HTTP/1.1\nHost: shop.example\n\nuser=a",
+		DryRun: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := out.Preview
+	if p == nil || p.URL != "https://shop.example:443" || p.Via != "burp" || !strings.HasPrefix(p.Raw, "POST /login HTTP/1.1\r\nHost: shop.example\r\n") {
+		t.Errorf("preview = %+v", p)
+	}
+
+	_, out, err = sendRequestHandler(nil)(context.Background(), nil, SendRequestInput{
+		Raw:         "GET / HTTP/1.1\r\nHost: shop.example\r\n\r\n",
+		Direct:      true,
+		ConnectHost: "10.0.0.5",
+		SNI:         "cdn.example",
+		DryRun:      true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p := out.Preview; p.Via != "direct" || p.ConnectTo != "10.0.0.5:443" || p.SNI != "cdn.example" {
+		t.Errorf("direct preview = %+v", p)
+	}
+}
+
+func TestRaceRequest_DryRunFixesContentLength(t *testing.T) {
+	_, out, err := raceRequestHandler()(context.Background(), nil, RaceRequestInput{
+		Raw:    "POST /redeem HTTP/1.1\r\nHost: shop.example\r\nContent-Length: 1\r\n\r\ncode=X",
+		Count:  20,
+		DryRun: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out.Preview == nil || out.Preview.Count != 20 || strings.Contains(out.Preview.Raw, "Content-Length: 1\r\n") || len(out.Groups) != 0 {
+		t.Errorf("output = %+v", out)
+	}
+}
+
+func TestDryRun_AuthProfileNotRendered(t *testing.T) {
+	t.Cleanup(func() { Configure(nil) })
+	Configure(&config.Config{AuthProfiles: map[string]config.AuthProfile{
+		"admin": {Bearer: "SUPERSECRET", Cookies: map[string]string{"sid": "SECRETSID"}},
+	}})
+	raw := "GET /admin HTTP/1.1\r\nHost: shop.example\r\n\r\n"
+
+	_, out, err := sendRequestHandler(nil)(context.Background(), nil, SendRequestInput{Raw: raw, AuthProfile: "admin", DryRun: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, race, err := raceRequestHandler()(context.Background(), nil, RaceRequestInput{Raw: raw, Count: 2, AuthProfile: "admin", DryRun: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, batch, err := batchSendHandler(nil)(context.Background(), nil, BatchSendInput{Requests: []BatchRequest{{Raw: raw}}, AuthProfile: "admin", DryRun: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, p := range map[string]*RequestPreview{"send": out.Preview, "race": race.Preview, "batch": batch.Responses[0].Preview} {
+		if p == nil || p.AuthProfile != "admin" || strings.Contains(p.Raw, "SECRET") {
+			t.Errorf("%s preview = %+v", name, p)
+		}
+	}
+
+	if _, _, err := sendRequestHandler(nil)(context.Background(), nil, SendRequestInput{Raw: raw, AuthProfile: "nope", DryRun: true}); err == nil {
+		t.Error("unknown auth profile previewed")
+	}
+}
+
+func TestDryRun_GlobalRefusesLiveTools(t *testing.T) {
+	t.Cleanup(func() { Configure(nil) })
+	Configure(&config.Config{DryRun: true})
+
+	target := startTestTarget(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Error("request reached the target")
+	})
+	_, err := sendDirect(context.Background(), target, []byte("GET / HTTP/1.1\r\nHost: x\r\n\r\n"), directOptions{})
+	var dr *DryRunError
+	if !errors.As(err, &dr) {
+		t.Fatalf("expected DryRunError, got %v", err)
+	}
+
+	// Preview-capable tools preview even without the per-call flag.
+	_, out, err := sendRequestHandler(nil)(context.Background(), nil, SendRequestInput{Raw: "GET / HTTP/1.1\r\nHost: shop.example\r\n\r\n"})
+	if err != nil || out.Preview == nil {
+		t.Errorf("preview = %+v, err = %v", out.Preview, err)
+	}
+}
+
+func TestDryRun_PreviewChecksScope(t *testing.T) {
+	t.Cleanup(func() { Configure(nil) })
+	Configure(&config.Config{Scope: []string{"shop.example"}})
+
+	_, _, err := sendRequestHandler(nil)(context.Background(), nil, SendRequestInput{
+		Raw:    "GET / HTTP/1.1\r\nHost: evil.example\r\n\r\n",
+		DryRun: true,
+	})
+	var se *ScopeError
+	if !errors.As(err, &se) {
+		t.Errorf("expected ScopeError, got %v", err)
+	}
+}
@@ -85,7 +85,7 @@ func decodeHandler() func(context.Context, *mcp.CallToolRequest, DecodeInput) (*
 
 // RegisterEncodeTool registers the burp_encode tool.
 func RegisterEncodeTool(server *mcp.Server) {
-	mcp.AddTool(server, &mcp.Tool{
+	addTool(server, &mcp.Tool{
 		Name:        "burp_encode",
 		Description: `Encode content locally. Params: content, type (url|base64). Returns {encoded}.`,
 	}, encodeHandler())
@@ -93,7 +93,7 @@ func RegisterEncodeTool(server *mcp.Server) {
 
 // RegisterDecodeTool registers the burp_decode tool.
 func RegisterDecodeTool(server *mcp.Server) {
-	mcp.AddTool(server, &mcp.Tool{
+	addTool(server, &mcp.Tool{
 		Name:        "burp_decode",
 		Description: `Decode content locally. Params: content, type (url|base64). Returns {decoded}.`,
 	}, decodeHandler())
@@ -0,0 +1,147 @@
+package tools
+
+import (
+	"context"
+	"time"
+
+	"github.com/c0tton-fluff/burp-mcp-server/internal/store"
+	"github.com/c0tton-fluff/burp-mcp-server/internal/tasks"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const defaultSummaryEndpoints = 50
+
+// EngagementSummaryInput is the input for burp_engagement_summary.
+type EngagementSummaryInput struct {
+	EndpointLimit int `json:"endpointLimit,omitempty" jsonschema:"Maximum endpoints to list, busiest first (default 50)"`
+}
+
+// FindingRef is a short reference to a finding that is still open.
+type FindingRef struct {
+	ID          string `json:"id"`
+	Title       string `json:"title"`
+	Severity    string `json:"severity,omitempty"`
+	LastVerdict string `json:"lastVerdict"`
+}
+
+// FindingsSummary aggregates the recorded findings.
+type FindingsSummary struct {
+	Total      int            `json:"total"`
+	BySeverity map[string]int `json:"bySeverity"`
+	ByVerdict  map[string]int `json:"byVerdict"`
+	Open       []FindingRef   `json:"open"`
+}
+
+// IssueTriageSummary aggregates the triage statuses of scanner issues.
+type IssueTriageSummary struct {
+	ByStatus  map[string]int      `json:"byStatus"`
+	Confirmed []store.IssueTriage `json:"confirmed"`
+}
+
+// EngagementSummaryOutput is the output of burp_engagement_summary.
+type EngagementSummaryOutput struct {
+	Since          time.Time          `json:"since"`
+	ToolCalls      map[string]int     `json:"toolCalls"`
+	RequestsByTool map[string]int     `json:"requestsByTool"`
+	TotalRequests  int                `json:"totalRequests"`
+	Hosts          []HostActivity     `json:"hosts"`
+	EndpointCount  int                `json:"endpointCount"`
+	Endpoints      []EndpointActivity `json:"endpoints"`
+	Findings       FindingsSummary    `json:"findings"`
+	IssueTriage    IssueTriageSummary `json:"issueTriage"`
+	OpenJobs       []tasks.Snapshot   `json:"openJobs"`
+}
+
+func engagementSummaryHandler(st *store.Store) func(context.Context, *mcp.CallToolRequest, EngagementSummaryInput) (*mcp.CallToolResult, EngagementSummaryOutput, error) {
+	return func(_ context.Context, _ *mcp.CallToolRequest, input EngagementSummaryInput) (*mcp.CallToolResult, EngagementSummaryOutput, error) {
+		limit := input.EndpointLimit
+		if limit <= 0 {
+			limit = defaultSummaryEndpoints
+		}
+
+		snap := activity.snapshot()
+		out := EngagementSummaryOutput{
+			Since:          snap.Since,
+			ToolCalls:      snap.ToolCalls,
+			RequestsByTool: snap.RequestsByTool,
+			Hosts:          snap.Hosts,
+			EndpointCount:  len(snap.Endpoints),
+			Endpoints:      snap.Endpoints[:min(limit, len(snap.Endpoints))],
+			Findings:       summarizeFindings(st.Findings()),
+			IssueTriage:    summarizeTriage(st.IssueTriages()),
+			OpenJobs:       openJobs(),
+		}
+		for _, n := range snap.RequestsByTool {
+			out.TotalRequests += n
+		}
+		if out.Hosts == nil {
+			out.Hosts = []HostActivity{}
+		}
+		if out.Endpoints == nil {
+			out.Endpoints = []EndpointActivity{}
+		}
+		return nil, out, nil
+	}
+}
+
+// openJobs lists the background tasks still running, without results.
+func openJobs() []tasks.Snapshot {
+	jobs := []tasks.Snapshot{}
+	for _, s := range taskManager.List() {
+		if s.Status == tasks.StatusRunning {
+			s.Result = nil
+			jobs = append(jobs, s)
+		}
+	}
+	return jobs
+}
+
+// summarizeFindings counts findings by severity and latest retest verdict.
+// Findings never retested count as "untested"; anything not fixed is open.
+func summarizeFindings(findings []store.Finding) FindingsSummary {
+	s := FindingsSummary{
+		Total:      len(findings),
+		BySeverity: make(map[string]int),
+		ByVerdict:  make(map[string]int),
+		Open:       []FindingRef{},
+	}
+	for _, f := range findings {
+		severity := f.Severity
+		if severity == "" {
+			severity = "unrated"
+		}
+		s.BySeverity[severity]++
+
+		verdict := "untested"
+		if n := len(f.Retests); n > 0 {
+			verdict = f.Retests[n-1].Verdict
+		}
+		s.ByVerdict[verdict]++
+		if verdict != store.VerdictFixed {
+			s.Open = append(s.Open, FindingRef{ID: f.ID, Title: f.Title, Severity: f.Severity, LastVerdict: verdict})
+		}
+	}
+	return s
+}
+
+// summarizeTriage counts triaged issues by status and lists the confirmed
+// ones for the report.
+func summarizeTriage(triage []store.IssueTriage) IssueTriageSummary {
+	s := IssueTriageSummary{ByStatus: make(map[string]int), Confirmed: []store.IssueTriage{}}
+	for _, t := range triage {
+		s.ByStatus[t.Status]++
+		if t.Status == store.TriageConfirmed {
+			s.Confirmed = append(s.Confirmed, t)
+		}
+	}
+	return s
+}
+
+// RegisterEngagementSummaryTool registers the burp_engagement_summary tool.
+func RegisterEngagementSummaryTool(server *mcp.Server, st *store.Store) {
+	addTool(server, &mcp.Tool{
+		Name: "burp_engagement_summary",
+		Description: `Engagement overview: tool calls and requests sent per tool since server start, hosts and endpoints touched, recorded findings by severity and retest verdict, scanner issues by triage status, and background tasks still running. ` +
+			`Returns {since, toolCalls, requestsByTool, totalRequests, hosts, endpointCount, endpoints, findings: {total, bySeverity, byVerdict, open}, issueTriage: {byStatus, confirmed}, openJobs: [{id, kind, summary, startedAt, progress}]}.`,
+	}, engagementSummaryHandler(st))
+}
@@ -0,0 +1,176 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"reflect"
+
+	"github.com/c0tton-fluff/burp-mcp-server/internal/burp"
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/jsonrpc"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// Error codes carried by failed tool results.
+const (
+	CodeBurpUnreachable  = "burp_unreachable"    // Burp's MCP extension could not be reached
+	CodeUpstreamTool     = "upstream_tool_error" // Burp's tool ran and failed
+	CodeTargetTimeout    = "target_timeout"      // the target (or Burp on its behalf) did not answer in time
+	CodeParseFailure     = "parse_failure"       // a response from Burp or the target could not be parsed
+	CodeScopeViolation   = "scope_violation"     // the target is outside the engagement scope
+	CodeApprovalRequired = "approval_required"   // the call was denied or not approved in time
+	CodeRateLimited      = "rate_limited"        // a configured rate limit was reached
+	CodeDryRun           = "dry_run"             // the tool needs live responses and dry-run mode is on
+	CodeToolError        = "tool_error"          // anything else, usually invalid arguments
+)
+
+// ToolError is a tool failure with a machine-readable code. Failed tool
+// results carry it as {"error": {code, message, details}}.
+type ToolError struct {
+	Code    string         `json:"code"`
+	Message string         `json:"message"`
+	Details map[string]any `json:"details,omitempty"`
+	err     error
+}
+
+func (e *ToolError) Error() string { return e.Message }
+
+func (e *ToolError) Unwrap() error { return e.err }
+
+// ErrorCode returns e's code; background tasks report it next to the error.
+func (e *ToolError) ErrorCode() string { return e.Code }
+
+// parseFailure returns a parse_failure error for a response that could not be parsed.
+func parseFailure(format string, args ...any) error {
+	err := fmt.Errorf(format, args...)
+	return &ToolError{Code: CodeParseFailure, Message: err.Error(), err: errors.Unwrap(err)}
+}
+
+// classifyError gives err a code from the error types it wraps.
+func classifyError(err error) *ToolError {
+	var te *ToolError
+	if errors.As(err, &te) {
+		if te != err {
+			// Keep the context the error gathered on its way up.
+			return &ToolError{Code: te.Code, Message: err.Error(), Details: te.Details, err: err}
+		}
+		return te
+	}
+	te = &ToolError{Code: CodeToolError, Message: err.Error(), err: err}
+
+	var (
+		scopeErr    *ScopeError
+		approvalErr *ApprovalError
+		rateErr     *RateLimitError
+		queueErr    *QueueFullError
+		dryRunErr   *DryRunError
+		netErr      net.Error
+		syntaxErr   *json.SyntaxError
+		typeErr     *json.UnmarshalTypeError
+	)
+	switch {
+	case errors.As(err, &scopeErr):
+		te.Code, te.Details = CodeScopeViolation, map[string]any{"host": scopeErr.Host}
+	case errors.As(err, &approvalErr):
+		te.Code, te.Details = CodeApprovalRequired, map[string]any{"tool": approvalErr.Tool, "approvalId": approvalErr.ID}
+	case errors.As(err, &rateErr):
+		te.Code, te.Details = CodeRateLimited, map[string]any{"scope": rateErr.Scope, "retryAfterSeconds": rateErr.RetryAfter.Seconds()}
+		if rateErr.Key != "" {
+			te.Details["key"] = rateErr.Key
+		}
+	case errors.As(err, &queueErr):
+		te.Code, te.Details = CodeRateLimited, map[string]any{"scope": "concurrency", "host": queueErr.Host}
+	case errors.As(err, &dryRunErr):
+		te.Code = CodeDryRun
+	case errors.Is(err, burp.ErrUnreachable):
+		te.Code = CodeBurpUnreachable
+	case errors.Is(err, burp.ErrTimeout):
+		te.Code, te.Details = CodeTargetTimeout, map[string]any{"via": "burp"}
+	case errors.Is(err, burp.ErrUpstream):
+		te.Code = CodeUpstreamTool
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &netErr) && netErr.Timeout():
+		te.Code, te.Details = CodeTargetTimeout, map[string]any{"via": "direct"}
+	case errors.Is(err, burp.ErrUnknownFormat), errors.As(err, &syntaxErr), errors.As(err, &typeErr):
+		te.Code = CodeParseFailure
+	}
+	return te
+}
+
+// addTool is mcp.AddTool for this package's tools: handler errors are
+// classified and, under ErrorMiddleware, returned as an error envelope, and
+// the output schema lists the schemaVersion SchemaVersionMiddleware adds.
+func addTool[In, Out any](server *mcp.Server, t *mcp.Tool, h mcp.ToolHandlerFor[In, Out]) {
+	if t.OutputSchema == nil {
+		rt := reflect.TypeFor[Out]()
+		if rt.Kind() == reflect.Pointer {
+			rt = rt.Elem()
+		}
+		if s, err := jsonschema.ForType(rt, &jsonschema.ForOptions{}); err == nil && s.Type == "object" {
+			if s.Properties == nil {
+				s.Properties = map[string]*jsonschema.Schema{}
+			}
+			s.Properties["schemaVersion"] = &jsonschema.Schema{Type: "integer", Description: "Version of this output's shape"}
+			t.OutputSchema = s
+		}
+	}
+	mcp.AddTool(server, t, func(ctx context.Context, req *mcp.CallToolRequest, in In) (*mcp.CallToolResult, Out, error) {
+		res, out, err := h(ctx, req, in)
+		if err == nil {
+			return res, out, nil
+		}
+		var wireErr *jsonrpc.Error
+		if errors.As(err, &wireErr) {
+			return res, out, err
+		}
+		te := classifyError(err)
+		if slot, ok := ctx.Value(errorSlotKey{}).(*errorSlot); ok {
+			slot.err = te
+		}
+		return res, out, te
+	})
+}
+
+type errorSlotKey struct{}
+
+// errorSlot carries a tool call's classified error from addTool back out
+// to ErrorMiddleware and MetricsMiddleware; the SDK keeps only the error
+// text, and middleware between them may rewrite the structured content.
+type errorSlot struct {
+	err *ToolError
+}
+
+// withErrorSlot returns ctx's error slot, adding one if there is none, so
+// every middleware on a call shares the slot addTool fills.
+func withErrorSlot(ctx context.Context) (context.Context, *errorSlot) {
+	if slot, ok := ctx.Value(errorSlotKey{}).(*errorSlot); ok {
+		return ctx, slot
+	}
+	slot := &errorSlot{}
+	return context.WithValue(ctx, errorSlotKey{}, slot), slot
+}
+
+// ErrorMiddleware gives failed tool results a structured error envelope,
+// {"error": {code, message, details}}, as both structured content and text,
+// so clients can branch on the code instead of the message.
+func ErrorMiddleware() mcp.Middleware {
+	return func(next mcp.MethodHandler) mcp.MethodHandler {
+		return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+			if method != "tools/call" {
+				return next(ctx, method, req)
+			}
+			ctx, slot := withErrorSlot(ctx)
+			result, err := next(ctx, method, req)
+			if res, ok := result.(*mcp.CallToolResult); ok && err == nil && res.IsError && slot.err != nil {
+				envelope := map[string]*ToolError{"error": slot.err}
+				if data, err := json.Marshal(envelope); err == nil {
+					res.StructuredContent = envelope
+					res.Content = []mcp.Content{&mcp.TextContent{Text: string(data)}}
+				}
+			}
+			return result, err
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/c0tton-fluff/burp-mcp-server/internal/burp"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestClassifyError(t *testing.T) {
+	tests := []struct {
+		err  error
+		code string
+	}{
+		{fmt.Errorf("send: %w", &ScopeError{Host: "evil.test"}), CodeScopeViolation},
+		{&RateLimitError{Scope: "host", Key: "a.test", RetryAfter: time.Second}, CodeRateLimited},
+		{&DryRunError{Tool: "burp_xss_verify"}, CodeDryRun},
+		{fmt.Errorf("call x: %w: EOF", burp.ErrUnreachable), CodeBurpUnreachable},
+		{fmt.Errorf("%w: invalid host", burp.ErrUpstream), CodeUpstreamTool},
+		{fmt.Errorf("call x: %w after 30s", burp.ErrTimeout), CodeTargetTimeout},
+		{context.DeadlineExceeded, CodeTargetTimeout},
+		{json.Unmarshal([]byte("{"), new(any)), CodeParseFailure},
+		{parseFailure("failed to parse response"), CodeParseFailure},
+		{errors.New("target is required"), CodeToolError},
+	}
+	for _, tt := range tests {
+		if got := classifyError(tt.err); got.Code != tt.code || got.Message != tt.err.Error() {
+			t.Errorf("classifyError(%v) = %+v, want code %s", tt.err, got, tt.code)
+		}
+	}
+	wrapped := fmt.Errorf("failed to get scanner issues: %w", parseFailure("scanner issues: %w", burp.ErrUnknownFormat))
+	if got := classifyError(wrapped); got.Code != CodeParseFailure || got.Message != wrapped.Error() {
+		t.Errorf("wrapped tool error = %+v", got)
+	}
+	if d := classifyError(&ScopeError{Host: "evil.test"}).Details; d["host"] != "evil.test" {
+		t.Errorf("scope details = %v", d)
+	}
+}
+
+func TestErrorMiddleware(t *testing.T) {
+	server := mcp.NewServer(&mcp.Implementation{Name: "test"}, nil)
+	server.AddReceivingMiddleware(ErrorMiddleware())
+	type in struct {
+		Fail bool `json:"fail,omitempty"`
+	}
+	addTool(server, &mcp.Tool{Name: "t"}, func(_ context.Context, _ *mcp.CallToolRequest, in in) (*mcp.CallToolResult, any, error) {
+		if in.Fail {
+			return nil, nil, fmt.Errorf("send: %w", &ScopeError{Host: "evil.test"})
+		}
+		return nil, map[string]bool{"ok": true}, nil
+	})
+
+	ct, st := mcp.NewInMemoryTransports()
+	ctx := context.Background()
+	ss, err := server.Connect(ctx, st, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ss.Close()
+	cs, err := mcp.NewClient(&mcp.Implementation{Name: "client"}, nil).Connect(ctx, ct, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cs.Close()
+
+	res, err := cs.CallTool(ctx, &mcp.CallToolParams{Name: "t", Arguments: map[string]any{"fail": true}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var envelope struct {
+		Error ToolError `json:"error"`
+	}
+	data, _ := json.Marshal(res.StructuredContent)
+	if err := json.Unmarshal(data, &envelope); err != nil || !res.IsError {
+		t.Fatalf("result = %+v, %v", res, err)
+	}
+	if e := envelope.Error; e.Code != CodeScopeViolation || e.Details["host"] != "evil.test" || e.Message == "" {
+		t.Errorf("envelope = %+v", e)
+	}
+	var fromText struct {
+		Error ToolError `json:"error"`
+	}
+	if err := json.Unmarshal([]byte(res.Content[0].(*mcp.TextContent).Text), &fromText); err != nil || fromText.Error.Code != CodeScopeViolation {
+		t.Errorf("text content = %+v, %v; want the envelope", res.Content[0], err)
+	}
+
+	res, err = cs.CallTool(ctx, &mcp.CallToolParams{Name: "t", Arguments: map[string]any{}})
+	if err != nil || res.IsError {
+		t.Errorf("success = %+v, %v", res, err)
+	}
+}
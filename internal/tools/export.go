@@ -0,0 +1,94 @@
+package tools
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/c0tton-fluff/burp-mcp-server/internal/export"
+	"github.com/c0tton-fluff/burp-mcp-server/internal/store"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// ExportInput is the input for burp_export.
+type ExportInput struct {
+	Format     string   `json:"format" jsonschema:"required,Export format name (see tool description)"`
+	FindingIDs []string `json:"findingIds,omitempty" jsonschema:"Only export these findings (default: all)"`
+	Path       string   `json:"path,omitempty" jsonschema:"Write to this file instead of returning the content"`
+}
+
+// ExportOutput is the output of burp_export.
+type ExportOutput struct {
+	Format   string `json:"format"`
+	Findings int    `json:"findings"`
+	Bytes    int    `json:"bytes"`
+	Path     string `json:"path,omitempty"`
+	Content  string `json:"content,omitempty"`
+}
+
+func exportHandler(st *store.Store) func(context.Context, *mcp.CallToolRequest, ExportInput) (*mcp.CallToolResult, ExportOutput, error) {
+	return func(_ context.Context, _ *mcp.CallToolRequest, input ExportInput) (*mcp.CallToolResult, ExportOutput, error) {
+		e, err := export.Get(input.Format)
+		if err != nil {
+			return nil, ExportOutput{}, err
+		}
+		findings, err := selectFindings(st, input.FindingIDs)
+		if err != nil {
+			return nil, ExportOutput{}, err
+		}
+
+		var buf bytes.Buffer
+		if err := e.Export(&buf, findings); err != nil {
+			return nil, ExportOutput{}, fmt.Errorf("export %s: %w", e.Name(), err)
+		}
+
+		out := ExportOutput{Format: e.Name(), Findings: len(findings), Bytes: buf.Len()}
+		if input.Path == "" {
+			out.Content = buf.String()
+			return nil, out, nil
+		}
+		if err := os.WriteFile(input.Path, buf.Bytes(), 0o600); err != nil {
+			return nil, ExportOutput{}, fmt.Errorf("write export: %w", err)
+		}
+		out.Path = input.Path
+		return nil, out, nil
+	}
+}
+
+// selectFindings returns the findings with the given IDs, or all when ids is empty.
+func selectFindings(st *store.Store, ids []string) ([]store.Finding, error) {
+	all := st.Findings()
+	if len(ids) == 0 {
+		return all, nil
+	}
+	var out []store.Finding
+	for _, id := range ids {
+		i := slices.IndexFunc(all, func(f store.Finding) bool { return f.ID == id })
+		if i < 0 {
+			return nil, fmt.Errorf("finding %q not found", id)
+		}
+		out = append(out, all[i])
+	}
+	return out, nil
+}
+
+// exportFormatList describes the registered formats, e.g. "sarif (SARIF 2.1.0 ...)".
+func exportFormatList() string {
+	var parts []string
+	for _, e := range export.Formats() {
+		parts = append(parts, e.Name()+" ("+e.Description()+")")
+	}
+	return strings.Join(parts, ", ")
+}
+
+// RegisterExportTool registers the burp_export tool.
+func RegisterExportTool(server *mcp.Server, st *store.Store) {
+	addTool(server, &mcp.Tool{
+		Name: "burp_export",
+		Description: `Export recorded findings. Formats: ` + exportFormatList() + `. ` +
+			`Returns {format, findings, bytes, content} or, with path, writes the file and returns {format, findings, bytes, path}.`,
+	}, exportHandler(st))
+}
@@ -0,0 +1,294 @@
+package tools
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/c0tton-fluff/burp-mcp-server/internal/burp"
+	"github.com/c0tton-fluff/burp-mcp-server/internal/config"
+	"github.com/c0tton-fluff/burp-mcp-server/internal/tasks"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const (
+	defaultSqlmapTimeout = 3600
+	maxSqlmapTimeout     = 24 * 3600
+)
+
+// ExportSqlmapInput is the input for burp_export_sqlmap.
+type ExportSqlmapInput struct {
+	Index    int    `json:"index,omitempty" jsonschema:"Proxy history index (1-based) of the request; use this or raw"`
+	Raw      string `json:"raw,omitempty" jsonschema:"Raw HTTP request"`
+	Host     string `json:"host,omitempty" jsonschema:"Target host (overrides Host header)"`
+	Port     int    `json:"port,omitempty" jsonschema:"Target port (default based on TLS)"`
+	TLS      *bool  `json:"tls,omitempty" jsonschema:"Use HTTPS (default true)"`
+	Instance string `json:"instance,omitempty" jsonschema:"Named Burp instance from config (default: the --burp-url connection)"`
+
+	Param string   `json:"param,omitempty" jsonschema:"Parameter to test (-p); a header name other than User-Agent, Referer, or Host is marked with * instead (default: every parameter)"`
+	Level int      `json:"level,omitempty" jsonschema:"sqlmap --level 1-5 (default: suggested from where the tested parameters are)"`
+	Risk  int      `json:"risk,omitempty" jsonschema:"sqlmap --risk 1-3 (default 1)"`
+	DBMS  string   `json:"dbms,omitempty" jsonschema:"Back-end DBMS if known, e.g. MySQL, PostgreSQL"`
+	Proxy string   `json:"proxy,omitempty" jsonschema:"Proxy for sqlmap's traffic, e.g. Burp's listener http://127.0.0.1:8080"`
+	Args  []string `json:"args,omitempty" jsonschema:"Extra sqlmap arguments, e.g. --technique=BT"`
+
+	HeaderProfile string `json:"headerProfile,omitempty" jsonschema:"Header rule profile applied to the written request (default: 'default')"`
+
+	Launch         bool `json:"launch,omitempty" jsonschema:"Also start sqlmap as a background task; poll it with burp_get_task"`
+	TimeoutSeconds int  `json:"timeoutSeconds,omitempty" jsonschema:"Stop a launched run after this long (default 3600 or config sqlmap.timeoutSeconds)"`
+}
+
+// SqlmapParam is a candidate injection point found in the request.
+type SqlmapParam struct {
+	Name string `json:"name"`
+	In   string `json:"in"` // query, body, json, cookie, or header
+}
+
+// ExportSqlmapOutput is the output of burp_export_sqlmap.
+type ExportSqlmapOutput struct {
+	RequestFile string        `json:"requestFile"`
+	Command     string        `json:"command"`
+	Params      []SqlmapParam `json:"params"`
+	Level       int           `json:"level"`
+	Risk        int           `json:"risk"`
+	Notes       []string      `json:"notes,omitempty"`
+	TaskID      string        `json:"taskId,omitempty"`
+}
+
+// sqlmapParams lists the parameters sqlmap can test in req: query and form
+// or top-level JSON body parameters, then cookies.
+func sqlmapParams(req *burp.ParsedHTTPRequest) []SqlmapParam {
+	params := []SqlmapParam{}
+	pairs := func(s, sep, in string) {
+		for _, pair := range strings.Split(s, sep) {
+			name, _, _ := strings.Cut(strings.TrimSpace(pair), "=")
+			if p := (SqlmapParam{Name: name, In: in}); name != "" && !slices.Contains(params, p) {
+				params = append(params, p)
+			}
+		}
+	}
+	if _, query, ok := strings.Cut(req.Path, "?"); ok {
+		pairs(query, "&", "query")
+	}
+	contentType := strings.ToLower(burp.GetHeader(req.Headers, "Content-Type"))
+	switch {
+	case strings.HasPrefix(contentType, "application/x-www-form-urlencoded"):
+		pairs(req.Body, "&", "body")
+	case strings.Contains(contentType, "json"):
+		var obj map[string]any
+		if json.Unmarshal([]byte(req.Body), &obj) == nil {
+			for _, k := range slices.Sorted(maps.Keys(obj)) {
+				params = append(params, SqlmapParam{Name: k, In: "json"})
+			}
+		}
+	}
+	for name, values := range req.Headers {
+		if strings.EqualFold(name, "Cookie") {
+			for _, v := range values {
+				pairs(v, ";", "cookie")
+			}
+		}
+	}
+	return params
+}
+
+// sqlmapLevel is the lowest --level at which sqlmap tests p: GET and POST
+// parameters at 1, cookies at 2, User-Agent and Referer at 3, Host at 5.
+func sqlmapLevel(p SqlmapParam) int {
+	switch {
+	case p.In == "cookie":
+		return 2
+	case p.In != "header":
+		return 1
+	case strings.EqualFold(p.Name, "Host"):
+		return 5
+	}
+	return 3
+}
+
+func exportSqlmapHandler(client *burp.Client) func(context.Context, *mcp.CallToolRequest, ExportSqlmapInput) (*mcp.CallToolResult, ExportSqlmapOutput, error) {
+	return func(ctx context.Context, _ *mcp.CallToolRequest, input ExportSqlmapInput) (*mcp.CallToolResult, ExportSqlmapOutput, error) {
+		ctx = burp.WithInstance(ctx, input.Instance)
+
+		raw := input.Raw
+		switch {
+		case input.Index > 0 && raw != "":
+			return nil, ExportSqlmapOutput{}, fmt.Errorf("use index or raw, not both")
+		case input.Index > 0:
+			req, _, err := historyEntry(ctx, client, input.Index)
+			if err != nil {
+				return nil, ExportSqlmapOutput{}, err
+			}
+			raw = req
+		case raw == "":
+			return nil, ExportSqlmapOutput{}, fmt.Errorf("index or raw is required")
+		}
+		if input.Level < 0 || input.Level > 5 || input.Risk < 0 || input.Risk > 3 {
+			return nil, ExportSqlmapOutput{}, fmt.Errorf("level must be 1-5 and risk 1-3")
+		}
+		if input.TimeoutSeconds < 0 || input.TimeoutSeconds > maxSqlmapTimeout {
+			return nil, ExportSqlmapOutput{}, fmt.Errorf("timeoutSeconds must be 0-%d", maxSqlmapTimeout)
+		}
+		rawNorm, parsed, err := prepareRequest(raw, input.HeaderProfile)
+		if err != nil {
+			return nil, ExportSqlmapOutput{}, err
+		}
+		t, err := resolveTarget(input.Host, input.Port, input.TLS, parsed.Host)
+		if err != nil {
+			return nil, ExportSqlmapOutput{}, err
+		}
+		r, err := splitRawRequest(rawNorm)
+		if err != nil {
+			return nil, ExportSqlmapOutput{}, err
+		}
+		// Normalization ends the request with a blank line, which would become
+		// part of a body that lacked one; sqlmap sends the file's body as is.
+		if !strings.HasSuffix(strings.ReplaceAll(raw, "\r\n", "\n"), "\n\n") {
+			r.body = strings.TrimSuffix(r.body, "\r\n\r\n")
+		}
+		// sqlmap takes the target from the Host header, so it must carry any
+		// non-default port.
+		hostHeader := t.Host
+		if (t.UseTLS && t.Port != 443) || (!t.UseTLS && t.Port != 80) {
+			hostHeader = net.JoinHostPort(t.Host, strconv.Itoa(t.Port))
+		}
+		r.setHeader("Host", hostHeader)
+
+		sc := settings.Sqlmap
+		if sc == nil {
+			sc = &config.SqlmapConfig{}
+		}
+		out := ExportSqlmapOutput{Params: sqlmapParams(parsed), Risk: cmp.Or(input.Risk, 1)}
+		tested := out.Params
+		var args []string
+		if input.Param != "" {
+			i := slices.IndexFunc(out.Params, func(p SqlmapParam) bool { return p.Name == input.Param })
+			headerIdx := slices.IndexFunc(r.headers, func(line string) bool { return strings.EqualFold(headerName(line), input.Param) })
+			switch {
+			case i >= 0:
+				tested = []SqlmapParam{out.Params[i]}
+				args = append(args, "-p", input.Param)
+			case headerIdx >= 0:
+				p := SqlmapParam{Name: headerName(r.headers[headerIdx]), In: "header"}
+				tested = []SqlmapParam{p}
+				if !slices.ContainsFunc([]string{"User-Agent", "Referer", "Host"}, func(h string) bool { return strings.EqualFold(h, p.Name) }) {
+					// sqlmap only tests a few headers by name; mark others as a
+					// custom injection point.
+					r.headers[headerIdx] += "*"
+					tested = nil
+					out.Notes = append(out.Notes, fmt.Sprintf("%s is marked with * as a custom injection point", p.Name))
+				} else {
+					args = append(args, "-p", p.Name)
+				}
+			default:
+				names := make([]string, len(out.Params))
+				for i, p := range out.Params {
+					names[i] = p.Name
+				}
+				return nil, ExportSqlmapOutput{}, fmt.Errorf("parameter %q not found in the request; candidates: %s", input.Param, strings.Join(names, ", "))
+			}
+		} else if len(out.Params) == 0 {
+			out.Notes = append(out.Notes, "no parameters found; mark an injection point with * in the request file")
+		}
+
+		out.Level = input.Level
+		if out.Level == 0 {
+			out.Level = 1
+			for _, p := range tested {
+				out.Level = max(out.Level, sqlmapLevel(p))
+			}
+		}
+		if out.Level > 1 && input.Level == 0 {
+			out.Notes = append(out.Notes, fmt.Sprintf("level %d is needed to test cookie or header parameters; higher levels send many more requests", out.Level))
+		}
+		if input.Risk == 0 {
+			out.Notes = append(out.Notes, "risk 1 avoids heavy time-based and OR-based payloads; risk 3 can modify data through UPDATE statements, so raise it only with permission")
+		}
+
+		dir := settings.SqlmapDir()
+		if err := os.MkdirAll(dir, 0o700); err != nil {
+			return nil, ExportSqlmapOutput{}, fmt.Errorf("create sqlmap directory: %w", err)
+		}
+		f, err := os.CreateTemp(dir, t.Host+"-*.req")
+		if err != nil {
+			return nil, ExportSqlmapOutput{}, fmt.Errorf("write request file: %w", err)
+		}
+		_, err = f.WriteString(fixContentLength(r.String()))
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			return nil, ExportSqlmapOutput{}, fmt.Errorf("write request file: %w", err)
+		}
+		out.RequestFile = f.Name()
+
+		args = append([]string{"-r", out.RequestFile}, args...)
+		if t.UseTLS {
+			args = append(args, "--force-ssl")
+		}
+		args = append(args, "--level", strconv.Itoa(out.Level), "--risk", strconv.Itoa(out.Risk), "--batch")
+		if input.DBMS != "" {
+			args = append(args, "--dbms", input.DBMS)
+		}
+		if input.Proxy != "" {
+			args = append(args, "--proxy", input.Proxy)
+		}
+		if rps := limiter.maxRPS(toolName(ctx), t.Host); rps > 0 {
+			args = append(args, "--delay", strconv.FormatFloat(1/rps, 'f', -1, 64))
+		}
+		args = append(args, "--output-dir", filepath.Join(dir, "output"))
+		args = append(append(args, sc.Args...), input.Args...)
+		binary := cmp.Or(sc.Binary, "sqlmap")
+		out.Command = shellJoin(append([]string{binary}, args...))
+
+		if !input.Launch {
+			return nil, out, nil
+		}
+		if err := checkScope(ctx, t.Host); err != nil {
+			return nil, ExportSqlmapOutput{}, err
+		}
+		if err := checkDryRun(ctx); err != nil {
+			return nil, ExportSqlmapOutput{}, err
+		}
+		summary := "sqlmap against " + hostHeader
+		if input.Param != "" {
+			summary += " (" + input.Param + ")"
+		}
+		if err := requireApproval(ctx, "burp_export_sqlmap", t, summary, r.String()); err != nil {
+			return nil, ExportSqlmapOutput{}, err
+		}
+		path, err := exec.LookPath(binary)
+		if err != nil {
+			return nil, ExportSqlmapOutput{}, fmt.Errorf("sqlmap binary %q not found; install sqlmap or set sqlmap.binary in config: %w", binary, err)
+		}
+		timeout := time.Duration(cmp.Or(input.TimeoutSeconds, sc.TimeoutSeconds, defaultSqlmapTimeout)) * time.Second
+		task := taskManager.Start(ctx, "sqlmap", summary, timeout, func(ctx context.Context, t *tasks.Task) error {
+			cmd := exec.CommandContext(ctx, path, args...)
+			cmd.Stdout, cmd.Stderr = t, t
+			return cmd.Run()
+		})
+		out.TaskID = task.ID()
+		return nil, out, nil
+	}
+}
+
+// RegisterExportSqlmapTool registers the burp_export_sqlmap tool.
+func RegisterExportSqlmapTool(server *mcp.Server, client *burp.Client) {
+	addTool(server, &mcp.Tool{
+		Name: "burp_export_sqlmap",
+		Description: `Hand a request off to sqlmap: write a proxy history entry (index) or raw request to a request file and return the exact sqlmap command (-r, -p, --level, --risk, --batch, --force-ssl for HTTPS). ` +
+			`Lists the candidate parameters and suggests the level their location needs (cookies 2, User-Agent and Referer 3, Host 5); risk defaults to 1. ` +
+			`With launch, also starts sqlmap as a background task to poll with burp_get_task. ` +
+			`Returns {requestFile, command, params: [{name, in}], level, risk, notes, taskId}.`,
+	}, exportSqlmapHandler(client))
+}
@@ -0,0 +1,112 @@
+package tools
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/c0tton-fluff/burp-mcp-server/internal/config"
+	"github.com/c0tton-fluff/burp-mcp-server/internal/tasks"
+)
+
+func TestExportSqlmap(t *testing.T) {
+	dir := t.TempDir()
+	t.Cleanup(func() { Configure(nil) })
+	Configure(&config.Config{Sqlmap: &config.SqlmapConfig{Dir: dir}})
+	handler := exportSqlmapHandler(nil)
+
+	raw := "POST /search?cat=1 HTTP/1.1\r\nHost: shop.example\r\nCookie: sid=abc; theme=dark\r\nX-Forwarded-For: 10.0.0.1\r\nContent-Type: application/x-www-form-urlencoded\r\n\r\nq=shoes&page=2"
+	_, out, err := handler(context.Background(), nil, ExportSqlmapInput{Raw: raw})
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantParams := []SqlmapParam{{"cat", "query"}, {"q", "body"}, {"page", "body"}, {"sid", "cookie"}, {"theme", "cookie"}}
+	if !reflect.DeepEqual(out.Params, wantParams) || out.Level != 2 || out.Risk != 1 {
+		t.Errorf("out = %+v", out)
+	}
+	if filepath.Dir(out.RequestFile) != dir {
+		t.Errorf("request file = %s", out.RequestFile)
+	}
+	want := "'sqlmap' '-r' '" + out.RequestFile + "' '--force-ssl' '--level' '2' '--risk' '1' '--batch' '--output-dir' '" + filepath.Join(dir, "output") + "'"
+	if out.Command != want {
+		t.Errorf("command = %s\nwant      %s", out.Command, want)
+	}
+	written, _ := os.ReadFile(out.RequestFile)
+	if !strings.HasPrefix(string(written), "POST /search?cat=1 HTTP/1.1\r\nHost: shop.example\r\n") || !strings.HasSuffix(string(written), "\r\n\r\nq=shoes&page=2") {
+		t.Errorf("request file content = %q", written)
+	}
+
+	// A named body parameter needs only level 1; a plain-HTTP target on a
+	// custom port moves into the Host header.
+	_, out, err = handler(context.Background(), nil, ExportSqlmapInput{Raw: raw, Param: "q", Port: 8080, TLS: new(bool), DBMS: "MySQL"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	written, _ = os.ReadFile(out.RequestFile)
+	if out.Level != 1 || !strings.Contains(out.Command, "'-p' 'q'") || strings.Contains(out.Command, "--force-ssl") || !strings.Contains(out.Command, "'--dbms' 'MySQL'") ||
+		!strings.Contains(string(written), "Host: shop.example:8080\r\n") {
+		t.Errorf("param out = %+v\nfile = %q", out, written)
+	}
+
+	// Headers sqlmap doesn't test by name get a custom injection marker.
+	_, out, err = handler(context.Background(), nil, ExportSqlmapInput{Raw: raw, Param: "x-forwarded-for"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	written, _ = os.ReadFile(out.RequestFile)
+	if strings.Contains(out.Command, "'-p'") || !strings.Contains(string(written), "X-Forwarded-For: 10.0.0.1*\r\n") || out.Level != 1 {
+		t.Errorf("header out = %+v\nfile = %q", out, written)
+	}
+	if _, out, err = handler(context.Background(), nil, ExportSqlmapInput{Raw: raw, Param: "User-Agent"}); err == nil {
+		t.Errorf("missing header should fail: %+v", out)
+	}
+
+	for _, bad := range []ExportSqlmapInput{{}, {Raw: raw, Index: 1}, {Raw: raw, Param: "nope"}, {Raw: raw, Risk: 4}} {
+		if _, _, err := handler(context.Background(), nil, bad); err == nil {
+			t.Errorf("%+v: expected error", bad)
+		}
+	}
+}
+
+func TestExportSqlmap_Launch(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("needs a POSIX shell")
+	}
+	dir := t.TempDir()
+	binary := filepath.Join(dir, "sqlmap")
+	if err := os.WriteFile(binary, []byte("#!/bin/sh\necho \"sqlmap $*\"\necho '[INFO] GET parameter id is vulnerable'\n"), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { Configure(nil) })
+	Configure(&config.Config{Sqlmap: &config.SqlmapConfig{Binary: binary, Dir: dir}})
+
+	_, out, err := exportSqlmapHandler(nil)(context.Background(), nil, ExportSqlmapInput{
+		Raw:    "GET /item?id=1 HTTP/1.1\r\nHost: shop.example\r\n\r\n",
+		Launch: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out.TaskID == "" {
+		t.Fatalf("out = %+v", out)
+	}
+	_, task, err := getTaskHandler()(context.Background(), nil, GetTaskInput{TaskID: out.TaskID, WaitSeconds: 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if task.Status != tasks.StatusDone || task.Kind != "sqlmap" || !strings.Contains(task.Output, "-r "+out.RequestFile) || !strings.Contains(task.Output, "is vulnerable") {
+		t.Errorf("task = %+v", task)
+	}
+	_, tail, _ := getTaskHandler()(context.Background(), nil, GetTaskInput{TaskID: out.TaskID, Offset: task.OutputBytes})
+	if tail.Output != "" {
+		t.Errorf("output past offset = %q", tail.Output)
+	}
+
+	if _, _, err := getTaskHandler()(context.Background(), nil, GetTaskInput{TaskID: "T-999"}); err == nil {
+		t.Error("expected error for unknown task")
+	}
+}
@@ -0,0 +1,165 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/c0tton-fluff/burp-mcp-server/internal/burp"
+	"github.com/c0tton-fluff/burp-mcp-server/internal/extract"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const defaultExtractLimit = 50
+
+// ExtractInput is the input for burp_extract.
+type ExtractInput struct {
+	Index    int    `json:"index,omitempty" jsonschema:"Proxy history index (1-based) whose response to read; use this or response"`
+	Response string `json:"response,omitempty" jsonschema:"Raw HTTP response, or a bare body"`
+	JSONPath string `json:"jsonPath,omitempty" jsonschema:"JSONPath for JSON bodies, e.g. $.data.items[*].id, $..csrf, or $.users[?(@.role=='admin')].id"`
+	CSS      string `json:"css,omitempty" jsonschema:"CSS selector for HTML bodies, e.g. input[name=csrf] or a.next"`
+	XPath    string `json:"xpath,omitempty" jsonschema:"XPath for HTML bodies, e.g. //input[@name='csrf']/@value"`
+	Attr     string `json:"attr,omitempty" jsonschema:"With css or xpath: return this attribute of each element (default: text, or value for input and content for meta)"`
+	Limit    int    `json:"limit,omitempty" jsonschema:"Maximum values returned (default 50)"`
+	Instance string `json:"instance,omitempty" jsonschema:"Named Burp instance from config (default: the --burp-url connection)"`
+}
+
+// ExtractOutput is the output of burp_extract.
+type ExtractOutput struct {
+	Value  string   `json:"value,omitempty"`
+	Values []string `json:"values"`
+	Count  int      `json:"count"`
+}
+
+// extractBody returns the body of a raw response, or text itself when it
+// isn't one.
+func extractBody(text string) string {
+	if !strings.HasPrefix(text, "HTTP/") {
+		return text
+	}
+	if resp := burp.ParseHTTPResponse(text, 0, 0); resp != nil {
+		return resp.Body
+	}
+	return text
+}
+
+// extractValues applies the one expression set in input to body.
+func extractValues(body string, input ExtractInput) ([]string, error) {
+	set := 0
+	for _, e := range []string{input.JSONPath, input.CSS, input.XPath} {
+		if e != "" {
+			set++
+		}
+	}
+	if set != 1 {
+		return nil, fmt.Errorf("exactly one of jsonPath, css, or xpath is required")
+	}
+
+	if input.JSONPath != "" {
+		if input.Attr != "" {
+			return nil, fmt.Errorf("attr applies to css and xpath only")
+		}
+		doc, err := extract.DecodeJSON(body)
+		if err != nil {
+			return nil, fmt.Errorf("body is not JSON: %w", err)
+		}
+		vals, err := extract.JSONPath(doc, input.JSONPath)
+		if err != nil {
+			return nil, err
+		}
+		out := make([]string, len(vals))
+		for i, v := range vals {
+			out[i] = extract.String(v)
+		}
+		return out, nil
+	}
+
+	doc := extract.ParseHTML(body)
+	var nodes []*extract.Node
+	var err error
+	if input.CSS != "" {
+		nodes, err = extract.Select(doc, input.CSS)
+	} else {
+		nodes, err = extract.XPath(doc, input.XPath)
+	}
+	if err != nil {
+		return nil, err
+	}
+	out := make([]string, 0, len(nodes))
+	for _, n := range nodes {
+		if v, ok := nodeValue(n, input.Attr); ok {
+			out = append(out, v)
+		}
+	}
+	return out, nil
+}
+
+// nodeValue is what an HTML match stands for: attr when given (elements
+// without it are skipped), a text or attribute node's own text, the value
+// of an input, the content of a meta, or an element's text.
+func nodeValue(n *extract.Node, attr string) (string, bool) {
+	if n.Tag == "" {
+		return strings.TrimSpace(n.Text), true
+	}
+	if attr != "" {
+		return n.Attr(strings.ToLower(attr))
+	}
+	switch n.Tag {
+	case "input":
+		if v, ok := n.Attr("value"); ok {
+			return v, true
+		}
+	case "meta":
+		if v, ok := n.Attr("content"); ok {
+			return v, true
+		}
+	}
+	return n.TextContent(), true
+}
+
+func extractHandler(client *burp.Client) func(context.Context, *mcp.CallToolRequest, ExtractInput) (*mcp.CallToolResult, ExtractOutput, error) {
+	return func(ctx context.Context, _ *mcp.CallToolRequest, input ExtractInput) (*mcp.CallToolResult, ExtractOutput, error) {
+		ctx = burp.WithInstance(ctx, input.Instance)
+
+		text := input.Response
+		switch {
+		case input.Index > 0 && text != "":
+			return nil, ExtractOutput{}, fmt.Errorf("use index or response, not both")
+		case input.Index > 0:
+			_, resp, err := historyEntry(ctx, client, input.Index)
+			if err != nil {
+				return nil, ExtractOutput{}, err
+			}
+			if resp == "" {
+				return nil, ExtractOutput{}, fmt.Errorf("history entry %d has no response", input.Index)
+			}
+			text = resp
+		case text == "":
+			return nil, ExtractOutput{}, fmt.Errorf("index or response is required")
+		}
+
+		vals, err := extractValues(extractBody(text), input)
+		if err != nil {
+			return nil, ExtractOutput{}, err
+		}
+		limit := input.Limit
+		if limit <= 0 {
+			limit = defaultExtractLimit
+		}
+		out := ExtractOutput{Values: vals[:min(len(vals), limit)], Count: len(vals)}
+		if len(vals) > 0 {
+			out.Value = vals[0]
+		}
+		return nil, out, nil
+	}
+}
+
+// RegisterExtractTool registers the burp_extract tool.
+func RegisterExtractTool(server *mcp.Server, client *burp.Client) {
+	addTool(server, &mcp.Tool{
+		Name: "burp_extract",
+		Description: `Pull values out of a response body, given raw or by proxy history index: a JSONPath for JSON ($.a.b[0], [*], $..name, [?(@.x=='y')] filters), or a CSS selector or XPath for HTML. ` +
+			`HTML matches return attr, or else an input's value, a meta's content, or the element's text; XPath @attr and text() steps return their text. Use it to carry a CSRF token or object ID into the next request. ` +
+			`Returns {value (first match), values, count}.`,
+	}, extractHandler(client))
+}
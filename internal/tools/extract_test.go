@@ -0,0 +1,51 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestExtractValues(t *testing.T) {
+	html := extractBody("HTTP/1.1 200 OK\r\nContent-Type: text/html\r\n\r\n" +
+		`<form><input name="csrf" value="abc"><input name="q"></form><meta name="x" content="m"><a href="/next">Next page</a>`)
+	json := extractBody(`{"items":[{"id":1,"owner":"me"},{"id":2,"owner":"you"}]}`)
+
+	tests := []struct {
+		body string
+		in   ExtractInput
+		want string
+	}{
+		{html, ExtractInput{CSS: "input[name=csrf]"}, "abc"},
+		{html, ExtractInput{CSS: "input"}, "abc|"},
+		{html, ExtractInput{CSS: "input", Attr: "value"}, "abc"},
+		{html, ExtractInput{CSS: "meta"}, "m"},
+		{html, ExtractInput{CSS: "a"}, "Next page"},
+		{html, ExtractInput{XPath: "//a/@href"}, "/next"},
+		{html, ExtractInput{XPath: "//a", Attr: "HREF"}, "/next"},
+		{json, ExtractInput{JSONPath: "$.items[*].id"}, "1|2"},
+		{json, ExtractInput{JSONPath: "$.items[?(@.owner=='you')].id"}, "2"},
+	}
+	for _, tt := range tests {
+		got, err := extractValues(tt.body, tt.in)
+		if err != nil {
+			t.Errorf("%+v: %v", tt.in, err)
+			continue
+		}
+		if strings.Join(got, "|") != tt.want {
+			t.Errorf("%+v = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+
+	for _, in := range []ExtractInput{
+		{},
+		{CSS: "a", XPath: "//a"},
+		{JSONPath: "$.a", Attr: "href"},
+	} {
+		if _, err := extractValues(json, in); err == nil {
+			t.Errorf("%+v: expected error", in)
+		}
+	}
+	if _, err := extractValues(html, ExtractInput{JSONPath: "$.a"}); err == nil || !strings.Contains(err.Error(), "not JSON") {
+		t.Errorf("err = %v", err)
+	}
+}
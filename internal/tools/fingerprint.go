@@ -0,0 +1,230 @@
+package tools
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"math/bits"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/c0tton-fluff/burp-mcp-server/internal/burp"
+	"github.com/c0tton-fluff/burp-mcp-server/internal/config"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// FingerprintInput is the input for burp_fingerprint.
+type FingerprintInput struct {
+	Response   string `json:"response,omitempty" jsonschema:"Raw HTTP response (or use index)"`
+	Index      int    `json:"index,omitempty" jsonschema:"Proxy history index (1-based) whose response to fingerprint"`
+	FaviconURL string `json:"faviconUrl,omitempty" jsonschema:"Favicon URL to fetch directly and hash (Shodan http.favicon.hash format)"`
+	Instance   string `json:"instance,omitempty" jsonschema:"Named Burp instance from config (default: the --burp-url connection)"`
+
+	TLSConfig *config.TLSOptions `json:"tlsConfig,omitempty" jsonschema:"TLS client certificate, CA bundle, version, and cipher options for faviconUrl"`
+}
+
+// Technology is one identified product.
+type Technology struct {
+	Name       string   `json:"name"`
+	Version    string   `json:"version,omitempty"`
+	Category   string   `json:"category"`
+	Confidence string   `json:"confidence"`
+	Evidence   []string `json:"evidence"`
+}
+
+// FingerprintOutput is the output of burp_fingerprint.
+type FingerprintOutput struct {
+	Technologies []Technology `json:"technologies"`
+	FaviconHash  *int32       `json:"faviconHash,omitempty"`
+	Errors       []string     `json:"errors,omitempty"`
+}
+
+// murmur3 is 32-bit MurmurHash3 (x86) with seed 0, as Python's mmh3.hash.
+func murmur3(data []byte) int32 {
+	const c1, c2 = 0xcc9e2d51, 0x1b873593
+	var h uint32
+	n := len(data) / 4 * 4
+	for i := 0; i < n; i += 4 {
+		k := binary.LittleEndian.Uint32(data[i:])
+		k *= c1
+		k = bits.RotateLeft32(k, 15)
+		k *= c2
+		h ^= k
+		h = bits.RotateLeft32(h, 13)
+		h = h*5 + 0xe6546b64
+	}
+	var k uint32
+	switch len(data) & 3 {
+	case 3:
+		k ^= uint32(data[n+2]) << 16
+		fallthrough
+	case 2:
+		k ^= uint32(data[n+1]) << 8
+		fallthrough
+	case 1:
+		k ^= uint32(data[n])
+		k *= c1
+		k = bits.RotateLeft32(k, 15)
+		k *= c2
+		h ^= k
+	}
+	h ^= uint32(len(data))
+	h ^= h >> 16
+	h *= 0x85ebca6b
+	h ^= h >> 13
+	h *= 0xc2b2ae35
+	h ^= h >> 16
+	return int32(h)
+}
+
+// faviconHash is Shodan's http.favicon.hash: mmh3 over base64 with a
+// newline every 76 characters and at the end, as Python's encodebytes.
+func faviconHash(data []byte) int32 {
+	enc := base64.StdEncoding.EncodeToString(data)
+	var b strings.Builder
+	for len(enc) > 76 {
+		b.WriteString(enc[:76] + "\n")
+		enc = enc[76:]
+	}
+	b.WriteString(enc + "\n")
+	return murmur3([]byte(b.String()))
+}
+
+// fingerprint matches resp, and the favicon hash if known, against
+// techSignatures. Two or more matching rules make a match certain.
+func fingerprint(resp *burp.ParsedHTTPResponse, favicon *int32) []Technology {
+	var cookies []string
+	for name, values := range resp.Headers {
+		if !strings.EqualFold(name, "Set-Cookie") {
+			continue
+		}
+		for _, v := range values {
+			if c, err := http.ParseSetCookie(v); err == nil {
+				cookies = append(cookies, c.Name)
+			}
+		}
+	}
+
+	rank := map[string]int{confCertain: 0, confFirm: 1, confTentative: 2}
+	techs := []Technology{}
+	for _, sig := range techSignatures {
+		t := Technology{Name: sig.name, Category: sig.category, Confidence: confTentative}
+		matched := 0
+		hit := func(version, confidence, evidence string) {
+			matched++
+			if t.Version == "" {
+				t.Version = version
+			}
+			if rank[confidence] < rank[t.Confidence] {
+				t.Confidence = confidence
+			}
+			t.Evidence = append(t.Evidence, evidence)
+		}
+		for _, r := range sig.rules {
+			switch {
+			case strings.HasPrefix(r.in, "header:"):
+				name := strings.TrimPrefix(r.in, "header:")
+				v := burp.GetHeader(resp.Headers, name)
+				if m := r.re.FindStringSubmatch(v); v != "" && m != nil {
+					hit(submatch(m), r.confidence, name+": "+v)
+				}
+			case r.in == "cookie":
+				for _, c := range cookies {
+					if r.re.MatchString(c) {
+						hit("", r.confidence, "cookie "+c)
+						break
+					}
+				}
+			case r.in == "body":
+				if m := r.re.FindStringSubmatch(resp.Body); m != nil {
+					hit(submatch(m), r.confidence, "body: "+truncateEvidence(m[0]))
+				}
+			}
+		}
+		if favicon != nil && slices.Contains(sig.favicons, *favicon) {
+			hit("", confFirm, fmt.Sprintf("favicon hash %d", *favicon))
+		}
+		if matched == 0 {
+			continue
+		}
+		if matched >= 2 {
+			t.Confidence = confCertain
+		}
+		techs = append(techs, t)
+	}
+	slices.SortStableFunc(techs, func(a, b Technology) int {
+		return rank[a.Confidence] - rank[b.Confidence]
+	})
+	return techs
+}
+
+func submatch(m []string) string {
+	if len(m) > 1 {
+		return m[1]
+	}
+	return ""
+}
+
+// truncateEvidence shortens a body match for the evidence list.
+func truncateEvidence(s string) string {
+	if len(s) > 80 {
+		return s[:80] + "..."
+	}
+	return s
+}
+
+func fingerprintHandler(client *burp.Client) func(context.Context, *mcp.CallToolRequest, FingerprintInput) (*mcp.CallToolResult, FingerprintOutput, error) {
+	return func(ctx context.Context, _ *mcp.CallToolRequest, input FingerprintInput) (*mcp.CallToolResult, FingerprintOutput, error) {
+		ctx = burp.WithInstance(ctx, input.Instance)
+
+		respRaw := input.Response
+		switch {
+		case input.Index > 0 && respRaw != "":
+			return nil, FingerprintOutput{}, fmt.Errorf("provide response or index, not both")
+		case input.Index > 0:
+			var err error
+			if _, respRaw, err = historyEntry(ctx, client, input.Index); err != nil {
+				return nil, FingerprintOutput{}, err
+			}
+		case respRaw == "":
+			return nil, FingerprintOutput{}, fmt.Errorf("response or index is required")
+		}
+		resp := burp.ParseHTTPResponse(respRaw, 0, 0)
+		if resp == nil {
+			return nil, FingerprintOutput{}, parseFailure("failed to parse response")
+		}
+
+		var out FingerprintOutput
+		if input.FaviconURL != "" {
+			u, err := url.Parse(input.FaviconURL)
+			if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+				return nil, FingerprintOutput{}, fmt.Errorf("faviconUrl must be an absolute http or https URL")
+			}
+			icon, err := directGet(ctx, u, nil, "", newDirectOptions(input.TLSConfig))
+			switch {
+			case err != nil:
+				out.Errors = append(out.Errors, "favicon: "+err.Error())
+			case icon.StatusCode != 200 || icon.Body == "":
+				out.Errors = append(out.Errors, fmt.Sprintf("favicon: status %d", icon.StatusCode))
+			default:
+				h := faviconHash([]byte(icon.Body))
+				out.FaviconHash = &h
+			}
+		}
+		out.Technologies = fingerprint(resp, out.FaviconHash)
+		return nil, out, nil
+	}
+}
+
+// RegisterFingerprintTool registers the burp_fingerprint tool.
+func RegisterFingerprintTool(server *mcp.Server, client *burp.Client) {
+	addTool(server, &mcp.Tool{
+		Name: "burp_fingerprint",
+		Description: `Identify server software, languages, frameworks, CMSs, JavaScript libraries, CDNs, and WAFs in a response (raw or proxy history index) from headers, cookie names, body markers, ` +
+			`and optionally the favicon hash (fetched directly, Shodan format), using a built-in signature database. ` +
+			`Returns {technologies: [{name, version, category, confidence, evidence}], faviconHash}.`,
+	}, fingerprintHandler(client))
+}
@@ -0,0 +1,145 @@
+package tools
+
+import "regexp"
+
+// Technology categories.
+const (
+	techServer    = "server"
+	techLanguage  = "language"
+	techFramework = "framework"
+	techCMS       = "cms"
+	techJS        = "js-library"
+	techCDN       = "cdn"
+	techWAF       = "waf"
+)
+
+// techRule matches one place in a response. in is "header:<Name>",
+// "cookie" (matched against Set-Cookie names), or "body". The first
+// capture group, if any, is the version.
+type techRule struct {
+	in         string
+	re         *regexp.Regexp
+	confidence string
+}
+
+// techSignature identifies one technology.
+type techSignature struct {
+	name     string
+	category string
+	rules    []techRule
+	// favicons are Shodan-style favicon hashes (mmh3 of base64).
+	favicons []int32
+}
+
+func techHeader(name, pattern string) techRule {
+	return techRule{"header:" + name, regexp.MustCompile(`(?i)` + pattern), confCertain}
+}
+
+func techCookie(pattern string) techRule {
+	return techRule{"cookie", regexp.MustCompile(pattern), confFirm}
+}
+
+func techBody(pattern, confidence string) techRule {
+	return techRule{"body", regexp.MustCompile(pattern), confidence}
+}
+
+// techSignatures is the fingerprint database. Header rules naming the
+// product are certain, cookies firm, body markers as set per rule.
+var techSignatures = []techSignature{
+	// Servers
+	{name: "nginx", category: techServer, rules: []techRule{techHeader("Server", `^nginx(?:/([\d.]+))?`)}},
+	{name: "Apache HTTP Server", category: techServer, rules: []techRule{techHeader("Server", `^Apache(?:/([\d.]+))?`)}},
+	{name: "Microsoft IIS", category: techServer, rules: []techRule{techHeader("Server", `^Microsoft-IIS(?:/([\d.]+))?`)}},
+	{name: "LiteSpeed", category: techServer, rules: []techRule{techHeader("Server", `^LiteSpeed`)}},
+	{name: "OpenResty", category: techServer, rules: []techRule{techHeader("Server", `^openresty(?:/([\d.]+))?`)}},
+	{name: "Caddy", category: techServer, rules: []techRule{techHeader("Server", `^Caddy`)}},
+	{name: "Envoy", category: techServer, rules: []techRule{techHeader("Server", `^envoy`), techHeader("X-Envoy-Upstream-Service-Time", `.`)}},
+	{name: "Kestrel", category: techServer, rules: []techRule{techHeader("Server", `^Kestrel`)}},
+	{name: "Gunicorn", category: techServer, rules: []techRule{techHeader("Server", `^gunicorn(?:/([\d.]+))?`)}},
+	{name: "Werkzeug", category: techServer, rules: []techRule{techHeader("Server", `Werkzeug(?:/([\d.]+))?`)}},
+	{name: "Jetty", category: techServer, rules: []techRule{techHeader("Server", `Jetty(?:\(([\d.v]+))?`)}},
+	{name: "Apache Tomcat", category: techServer, rules: []techRule{
+		techHeader("Server", `Apache-Coyote(?:/([\d.]+))?`),
+		techBody(`Apache Tomcat/([\d.]+)`, confCertain),
+	}},
+
+	// Languages and runtimes
+	{name: "PHP", category: techLanguage, rules: []techRule{techHeader("X-Powered-By", `PHP(?:/([\d.]+))?`), techCookie(`^PHPSESSID$`)}},
+	{name: "ASP.NET", category: techLanguage, rules: []techRule{
+		techHeader("X-AspNet-Version", `([\d.]+)`),
+		techHeader("X-Powered-By", `ASP\.NET`),
+		techCookie(`^ASP\.NET_SessionId$|^\.AspNetCore\.`),
+		techBody(`name="__VIEWSTATE"`, confFirm),
+	}},
+	{name: "Java", category: techLanguage, rules: []techRule{techCookie(`^JSESSIONID$`)}},
+
+	// Frameworks
+	{name: "Express", category: techFramework, rules: []techRule{techHeader("X-Powered-By", `^Express`), techCookie(`^connect\.sid$`)}},
+	{name: "Django", category: techFramework, rules: []techRule{techCookie(`^csrftoken$|^django_language$`), techBody(`name="csrfmiddlewaretoken"`, confFirm)}},
+	{name: "Ruby on Rails", category: techFramework, rules: []techRule{
+		techHeader("X-Runtime", `^[\d.]+$`),
+		techCookie(`^_[\w-]+_session$`),
+		techBody(`<meta name="csrf-param" content="authenticity_token"`, confFirm),
+	}},
+	{name: "Laravel", category: techFramework, rules: []techRule{techCookie(`^laravel_session$`)}},
+	{name: "Spring", category: techFramework, rules: []techRule{
+		techHeader("X-Application-Context", `.`),
+		techBody(`Whitelabel Error Page`, confCertain),
+	}, favicons: []int32{116323821}},
+	{name: "Next.js", category: techFramework, rules: []techRule{
+		techHeader("X-Powered-By", `^Next\.js(?: ([\d.]+))?`),
+		techBody(`id="__NEXT_DATA__"|/_next/static/`, confFirm),
+	}},
+	{name: "Nuxt", category: techFramework, rules: []techRule{techBody(`window\.__NUXT__|/_nuxt/`, confFirm)}},
+	{name: "Angular", category: techFramework, rules: []techRule{techBody(`ng-version="([\d.]+)"`, confCertain)}},
+
+	// JavaScript libraries
+	{name: "React", category: techJS, rules: []techRule{techBody(`data-reactroot|react-dom(?:\.production)?(?:\.min)?\.js`, confFirm)}},
+	{name: "Vue.js", category: techJS, rules: []techRule{techBody(`\sdata-v-[0-9a-f]{8}|vue(?:@([\d.]+))?(?:\.runtime)?(?:\.min)?\.js`, confFirm)}},
+	{name: "jQuery", category: techJS, rules: []techRule{techBody(`jquery[.-]([\d.]+?)(?:\.slim)?(?:\.min)?\.js`, confFirm)}},
+
+	// CMS and platforms
+	{name: "WordPress", category: techCMS, rules: []techRule{
+		techBody(`<meta name="generator" content="WordPress ?([\d.]*)"`, confCertain),
+		techBody(`/wp-content/|/wp-includes/`, confFirm),
+		techCookie(`^wordpress_|^wp-settings-`),
+	}},
+	{name: "Drupal", category: techCMS, rules: []techRule{
+		techHeader("X-Generator", `Drupal(?: (\d+))?`),
+		techHeader("X-Drupal-Cache", `.`),
+		techBody(`Drupal\.settings|data-drupal-selector`, confFirm),
+	}},
+	{name: "Joomla", category: techCMS, rules: []techRule{techBody(`<meta name="generator" content="Joomla!?[^"]*"`, confCertain)}},
+	{name: "Magento", category: techCMS, rules: []techRule{techBody(`Mage\.Cookies|/static/version\d+/frontend/`, confFirm)}},
+	{name: "Shopify", category: techCMS, rules: []techRule{techHeader("X-ShopId", `.`), techBody(`cdn\.shopify\.com`, confFirm)}},
+	{name: "Ghost", category: techCMS, rules: []techRule{techBody(`<meta name="generator" content="Ghost ([\d.]+)"`, confCertain)}},
+	{name: "Jenkins", category: techFramework, rules: []techRule{techHeader("X-Jenkins", `([\d.]+)`)}, favicons: []int32{81586312}},
+	{name: "GitLab", category: techFramework, rules: []techRule{techCookie(`^_gitlab_session$`)}, favicons: []int32{1278323681}},
+
+	// CDNs and edges
+	{name: "Cloudflare", category: techCDN, rules: []techRule{techHeader("Server", `^cloudflare`), techHeader("CF-RAY", `.`), techCookie(`^__cf_bm$|^__cfruid$`)}},
+	{name: "Amazon CloudFront", category: techCDN, rules: []techRule{techHeader("X-Amz-Cf-Id", `.`), techHeader("Via", `CloudFront`)}},
+	{name: "Akamai", category: techCDN, rules: []techRule{techHeader("Server", `^AkamaiGHost`), techHeader("X-Akamai-Transformed", `.`)}},
+	{name: "Fastly", category: techCDN, rules: []techRule{techHeader("X-Served-By", `^cache-`), techHeader("X-Fastly-Request-ID", `.`)}},
+	{name: "Varnish", category: techCDN, rules: []techRule{techHeader("X-Varnish", `.`), techHeader("Via", `varnish`)}},
+	{name: "Vercel", category: techCDN, rules: []techRule{techHeader("Server", `^Vercel`), techHeader("X-Vercel-Id", `.`)}},
+	{name: "Netlify", category: techCDN, rules: []techRule{techHeader("Server", `^Netlify`), techHeader("X-NF-Request-ID", `.`)}},
+	{name: "Azure Front Door", category: techCDN, rules: []techRule{techHeader("X-Azure-Ref", `.`)}},
+	{name: "Google Cloud", category: techCDN, rules: []techRule{techHeader("Via", `^1\.1 google$`)}},
+
+	// WAFs
+	{name: "AWS WAF", category: techWAF, rules: []techRule{techCookie(`^aws-waf-token$`), techHeader("X-Amzn-Waf-Action", `.`)}},
+	{name: "Imperva Incapsula", category: techWAF, rules: []techRule{
+		techHeader("X-CDN", `Incapsula|Imperva`),
+		techHeader("X-Iinfo", `.`),
+		techCookie(`^incap_ses_|^visid_incap_`),
+	}},
+	{name: "Sucuri", category: techWAF, rules: []techRule{techHeader("Server", `^Sucuri`), techHeader("X-Sucuri-ID", `.`)}},
+	{name: "F5 BIG-IP", category: techWAF, rules: []techRule{techCookie(`^BIGipServer|^TS01[0-9a-f]{4,}$|^F5_`), techHeader("Server", `^BigIP`)}},
+	{name: "Barracuda", category: techWAF, rules: []techRule{techCookie(`^barra_counter_session$|^BNI__BARRACUDA_LB_COOKIE$`)}},
+	{name: "FortiWeb", category: techWAF, rules: []techRule{techCookie(`^FORTIWAFSID$`)}},
+	{name: "Citrix NetScaler", category: techWAF, rules: []techRule{techCookie(`^NSC_|^citrix_ns_id$`), techHeader("Via", `NS-CACHE`)}},
+	{name: "ModSecurity", category: techWAF, rules: []techRule{techHeader("Server", `mod_security|NOYB`), techBody(`Mod_Security|This error was generated by Mod_Security`, confCertain)}},
+	{name: "DDoS-Guard", category: techWAF, rules: []techRule{techHeader("Server", `^ddos-guard`)}},
+	{name: "Wallarm", category: techWAF, rules: []techRule{techHeader("Server", `nginx-wallarm`)}},
+}
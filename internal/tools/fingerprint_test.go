@@ -0,0 +1,61 @@
+package tools
+
+import (
+	"testing"
+
+	"github.com/c0tton-fluff/burp-mcp-server/internal/burp"
+)
+
+func TestMurmur3(t *testing.T) {
+	for _, tc := range []struct {
+		in   string
+		want int32
+	}{
+		{"", 0},
+		{"hello", 613153351},
+		{"The quick brown fox jumps over the lazy dog", 776992547},
+	} {
+		if got := murmur3([]byte(tc.in)); got != tc.want {
+			t.Errorf("murmur3(%q) = %d, want %d", tc.in, got, tc.want)
+		}
+	}
+}
+
+func TestFingerprint(t *testing.T) {
+	resp := burp.ParseHTTPResponse("HTTP/1.1 200 OK\r\n"+
+		"Server: nginx/1.18.0\r\n"+
+		"X-Powered-By: PHP/8.1.2\r\n"+
+		"Set-Cookie: PHPSESSID=abc; path=/\r\n"+
+		"CF-RAY: 7d1a-LHR\r\n"+
+		"Content-Type: text/html\r\n\r\n"+
+		`<html><link rel="stylesheet" href="/wp-content/themes/x/style.css"><script src="/js/jquery-3.6.0.min.js"></script></html>`, 0, 0)
+	spring := int32(116323821)
+	got := map[string]Technology{}
+	for _, tech := range fingerprint(resp, &spring) {
+		got[tech.Name] = tech
+	}
+
+	for _, want := range []Technology{
+		{Name: "nginx", Version: "1.18.0", Category: techServer, Confidence: confCertain},
+		{Name: "PHP", Version: "8.1.2", Category: techLanguage, Confidence: confCertain},
+		{Name: "WordPress", Category: techCMS, Confidence: confFirm},
+		{Name: "jQuery", Version: "3.6.0", Category: techJS, Confidence: confFirm},
+		{Name: "Cloudflare", Category: techCDN, Confidence: confCertain},
+		{Name: "Spring", Category: techFramework, Confidence: confFirm},
+	} {
+		g, ok := got[want.Name]
+		if !ok {
+			t.Errorf("%s not identified", want.Name)
+			continue
+		}
+		if g.Version != want.Version || g.Category != want.Category || g.Confidence != want.Confidence {
+			t.Errorf("%s = %+v, want version %q category %s confidence %s", want.Name, g, want.Version, want.Category, want.Confidence)
+		}
+	}
+	if len(got["PHP"].Evidence) != 2 {
+		t.Errorf("PHP evidence = %v", got["PHP"].Evidence)
+	}
+	if _, ok := got["Apache HTTP Server"]; ok {
+		t.Error("unexpected Apache match")
+	}
+}
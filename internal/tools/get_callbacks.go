@@ -0,0 +1,88 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/c0tton-fluff/burp-mcp-server/internal/callback"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const (
+	defaultCallbackLimit = 50
+	maxCallbackLimit     = 500
+)
+
+// GetCallbacksInput is the input for burp_get_callbacks.
+type GetCallbacksInput struct {
+	Contains     string `json:"contains,omitempty" jsonschema:"Only interactions whose host, request line, or raw request contains this (case-insensitive), e.g. a probe label"`
+	Protocol     string `json:"protocol,omitempty" jsonschema:"Only http or dns interactions"`
+	SinceSeconds int    `json:"sinceSeconds,omitempty" jsonschema:"Only interactions from the last N seconds"`
+	Limit        int    `json:"limit,omitempty" jsonschema:"Newest interactions to return (default 50, max 500)"`
+}
+
+// GetCallbacksOutput is the output of burp_get_callbacks.
+type GetCallbacksOutput struct {
+	Log          string                 `json:"log"`
+	Interactions []callback.Interaction `json:"interactions"`
+	// Total counts every match; Truncated is set when Limit dropped some.
+	Total     int  `json:"total"`
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+func getCallbacksHandler(_ context.Context, _ *mcp.CallToolRequest, input GetCallbacksInput) (*mcp.CallToolResult, GetCallbacksOutput, error) {
+	if input.Protocol != "" && input.Protocol != "http" && input.Protocol != "dns" {
+		return nil, GetCallbacksOutput{}, fmt.Errorf("protocol must be http or dns, got %q", input.Protocol)
+	}
+	limit := input.Limit
+	if limit <= 0 {
+		limit = defaultCallbackLimit
+	}
+	limit = min(limit, maxCallbackLimit)
+	f := callback.Filter{Contains: input.Contains, Protocol: input.Protocol, Limit: limit}
+	if input.SinceSeconds > 0 {
+		f.Since = time.Now().Add(-time.Duration(input.SinceSeconds) * time.Second)
+	}
+
+	log := callback.Open(settings.CallbackLog())
+	found, total, err := log.Read(f)
+	if err != nil {
+		return nil, GetCallbacksOutput{}, err
+	}
+	if found == nil {
+		found = []callback.Interaction{}
+	}
+	return nil, GetCallbacksOutput{Log: log.Path(), Interactions: found, Total: total, Truncated: total > len(found)}, nil
+}
+
+// localCallbacks returns the interactions in the callback log since t as
+// Collaborator interactions, for tools that correlate either source.
+func localCallbacks(since time.Time) ([]CollaboratorInteraction, error) {
+	found, _, err := callback.Open(settings.CallbackLog()).Read(callback.Filter{Since: since})
+	out := make([]CollaboratorInteraction, 0, len(found))
+	for _, in := range found {
+		detail := in.Raw
+		if in.Protocol == "dns" {
+			detail = in.Host + "\n" + in.Raw
+		}
+		out = append(out, CollaboratorInteraction{
+			ID:        in.ID,
+			Type:      in.Protocol,
+			Timestamp: in.Time.Format(time.RFC3339),
+			ClientIP:  in.SourceIP,
+			Detail:    detail,
+		})
+	}
+	return out, err
+}
+
+// RegisterGetCallbacksTool registers the burp_get_callbacks tool.
+func RegisterGetCallbacksTool(server *mcp.Server) {
+	addTool(server, &mcp.Tool{
+		Name: "burp_get_callbacks",
+		Description: `Read out-of-band interactions recorded by the burp-mcp-server listen command, the self-hosted alternative to Burp Collaborator: every HTTP request (full raw request) and DNS query its listeners received, with source IP and timestamp. ` +
+			`Filter by a substring such as a probe label, by protocol, or by age. ` +
+			`Returns {log, interactions: [{id, protocol, time, sourceIp, host, summary, raw}], total, truncated}.`,
+	}, getCallbacksHandler)
+}
@@ -0,0 +1,61 @@
+package tools
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/c0tton-fluff/burp-mcp-server/internal/callback"
+	"github.com/c0tton-fluff/burp-mcp-server/internal/config"
+)
+
+func TestGetCallbacks(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "callbacks.jsonl")
+	Configure(&config.Config{Listen: &config.ListenConfig{Log: path}})
+	t.Cleanup(func() { Configure(nil) })
+
+	_, out, err := getCallbacksHandler(context.Background(), nil, GetCallbacksInput{})
+	if err != nil || out.Total != 0 || out.Interactions == nil || out.Log != path {
+		t.Fatalf("empty log: out = %+v, err = %v", out, err)
+	}
+
+	log := callback.Open(path)
+	for i, in := range []*callback.Interaction{
+		{Protocol: "dns", Host: "abcd1234-03.oob.example", Summary: "A", Raw: "A recursive query for abcd1234-03.oob.example", Time: time.Now().Add(-time.Hour)},
+		{Protocol: "http", Host: "oob.example", Summary: "GET /abcd1234-00", Raw: "GET /abcd1234-00 HTTP/1.1\r\nHost: oob.example\r\n\r\n"},
+		{Protocol: "http", Host: "oob.example", Summary: "GET /favicon.ico"},
+	} {
+		in.SourceIP = "198.51.100." + string(rune('1'+i))
+		if err := log.Append(in); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	_, out, err = getCallbacksHandler(context.Background(), nil, GetCallbacksInput{Contains: "ABCD1234"})
+	if err != nil || out.Total != 2 {
+		t.Fatalf("contains: out = %+v, err = %v", out, err)
+	}
+	_, out, _ = getCallbacksHandler(context.Background(), nil, GetCallbacksInput{SinceSeconds: 60, Protocol: "http", Limit: 1})
+	if out.Total != 2 || !out.Truncated || len(out.Interactions) != 1 || out.Interactions[0].Summary != "GET /favicon.ico" {
+		t.Errorf("since+limit: out = %+v", out)
+	}
+	if _, _, err := getCallbacksHandler(context.Background(), nil, GetCallbacksInput{Protocol: "smtp"}); err == nil {
+		t.Error("unknown protocol accepted")
+	}
+
+	// The SSRF probe correlates local callbacks by label, DNS names included.
+	hits, err := localCallbacks(time.Now().Add(-2 * time.Hour))
+	if err != nil || len(hits) != 3 {
+		t.Fatalf("localCallbacks = %+v, %v", hits, err)
+	}
+	variants := ssrfVariants("oob.example", "abcd1234", "", "")
+	matched, unmatched := matchInteractions(variants, hits)
+	if len(matched["abcd1234-00"]) != 1 || len(matched["abcd1234-03"]) != 1 || len(unmatched) != 1 {
+		t.Errorf("matched = %+v, unmatched = %+v", matched, unmatched)
+	}
+	if !strings.HasPrefix(hits[0].ClientIP, "198.51.100.") || hits[0].Type != "dns" {
+		t.Errorf("hit = %+v", hits[0])
+	}
+}
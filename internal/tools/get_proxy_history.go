@@ -6,13 +6,16 @@ import (
 	"strings"
 
 	"github.com/c0tton-fluff/burp-mcp-server/internal/burp"
+	"github.com/c0tton-fluff/burp-mcp-server/internal/store"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 )
 
 // GetProxyHistoryInput is the input for burp_get_proxy_history.
 type GetProxyHistoryInput struct {
-	Count  int `json:"count,omitempty" jsonschema:"Number of entries to return (default 10)"`
-	Offset int `json:"offset,omitempty" jsonschema:"Offset for pagination (default 0)"`
+	Count    int    `json:"count,omitempty" jsonschema:"Number of entries to return (default 10)"`
+	Offset   int    `json:"offset,omitempty" jsonschema:"Offset for pagination (default 0)"`
+	View     string `json:"view,omitempty" jsonschema:"Saved view (burp_save_view) to filter by; offset then counts scanned entries, and nextOffset continues the scan"`
+	Instance string `json:"instance,omitempty" jsonschema:"Named Burp instance from config (default: the --burp-url connection)"`
 }
 
 // ProxyHistorySummary is a lean proxy history entry.
@@ -27,13 +30,19 @@ type ProxyHistorySummary struct {
 type GetProxyHistoryOutput struct {
 	Entries []ProxyHistorySummary `json:"entries"`
 	Count   int                   `json:"count"`
+	// Set when filtering by a view: entries examined, and the offset to pass
+	// next to continue (0 once the end of history is reached).
+	Scanned    int `json:"scanned,omitempty"`
+	NextOffset int `json:"nextOffset,omitempty"`
 }
 
 // fetchConcurrency controls how many proxy history entries are fetched in parallel.
 const fetchConcurrency = 5
 
-func getProxyHistoryHandler(client *burp.Client) func(context.Context, *mcp.CallToolRequest, GetProxyHistoryInput) (*mcp.CallToolResult, GetProxyHistoryOutput, error) {
+func getProxyHistoryHandler(client *burp.Client, st *store.Store) func(context.Context, *mcp.CallToolRequest, GetProxyHistoryInput) (*mcp.CallToolResult, GetProxyHistoryOutput, error) {
 	return func(ctx context.Context, _ *mcp.CallToolRequest, input GetProxyHistoryInput) (*mcp.CallToolResult, GetProxyHistoryOutput, error) {
+		ctx = burp.WithInstance(ctx, input.Instance)
+
 		count := input.Count
 		if count <= 0 {
 			count = 10
@@ -42,65 +51,19 @@ func getProxyHistoryHandler(client *burp.Client) func(context.Context, *mcp.Call
 			count = 50
 		}
 
-		// Fetch entries with bounded parallelism.
-		// Burp serializes full request+response per entry, so count=1 per call
-		// avoids crashing the SSE transport (count=5+ causes SSE payload overflow).
-		type result struct {
-			idx   int
-			entry *ProxyHistorySummary
-			err   error
-		}
-		results := make(chan result, count)
-		sem := make(chan struct{}, fetchConcurrency)
-
-		for i := 0; i < count; i++ {
-			sem <- struct{}{}
-			go func(idx int) {
-				defer func() { <-sem }()
-				offset := input.Offset + idx
-
-				args := map[string]any{
-					"count":  1,
-					"offset": offset,
-				}
-
-				raw, err := client.CallTool(ctx, "get_proxy_http_history", args)
-				if err != nil {
-					results <- result{idx: idx, err: err}
-					return
-				}
-
-				raw = trimEndMarker(raw)
-				if raw == "" {
-					results <- result{idx: idx}
-					return
-				}
-
-				entry := parseSingleHistoryEntry(raw, offset+1)
-				results <- result{idx: idx, entry: entry}
-			}(i)
-		}
-
-		// Collect results in order
-		ordered := make([]*ProxyHistorySummary, count)
-		var firstErr error
-		for i := 0; i < count; i++ {
-			r := <-results
-			if r.err != nil && firstErr == nil {
-				firstErr = r.err
+		if input.View != "" {
+			view, err := loadView(st, input.View)
+			if err != nil {
+				return nil, GetProxyHistoryOutput{}, err
 			}
-			ordered[r.idx] = r.entry
-		}
-
-		// Build entries slice preserving order, stopping at first gap
-		var entries []ProxyHistorySummary
-		for _, e := range ordered {
-			if e == nil {
-				break
+			out, err := scanHistoryView(ctx, client, view, input.Offset, count)
+			if err != nil {
+				return nil, GetProxyHistoryOutput{}, fmt.Errorf("failed to get proxy history: %w", err)
 			}
-			entries = append(entries, *e)
+			return nil, out, nil
 		}
 
+		entries, firstErr := fetchHistorySummaries(ctx, client, input.Offset, count)
 		if len(entries) == 0 && firstErr != nil {
 			return nil, GetProxyHistoryOutput{}, fmt.Errorf("failed to get proxy history: %w", firstErr)
 		}
@@ -116,25 +79,106 @@ func getProxyHistoryHandler(client *burp.Client) func(context.Context, *mcp.Call
 	}
 }
 
+// fetchHistorySummaries fetches up to count entries from offset (0-based) on.
+// The result stops at the first missing entry; err is the failure that
+// caused it, if any.
+func fetchHistorySummaries(ctx context.Context, client *burp.Client, offset, count int) ([]ProxyHistorySummary, error) {
+	return fetchHistory(ctx, client, offset, count, parseSingleHistoryEntry)
+}
+
+// fetchHistory fetches up to count entries from offset (0-based) on and
+// converts each with parse, which gets the entry's raw text and 1-based id
+// and returns nil for an entry to treat as missing. The result stops at the
+// first missing entry; err is the failure that caused it, if any.
+func fetchHistory[T any](ctx context.Context, client *burp.Client, offset, count int, parse func(raw string, id int) (*T, error)) ([]T, error) {
+	// Fetch entries with bounded parallelism.
+	// Burp serializes full request+response per entry, so count=1 per call
+	// avoids crashing the SSE transport (count=5+ causes SSE payload overflow).
+	type result struct {
+		idx   int
+		entry *T
+		err   error
+	}
+	results := make(chan result, count)
+	sem := make(chan struct{}, fetchConcurrency)
+
+	for i := 0; i < count; i++ {
+		sem <- struct{}{}
+		go func(idx int) {
+			defer func() { <-sem }()
+			offset := offset + idx
+
+			args := map[string]any{
+				"count":  1,
+				"offset": offset,
+			}
+
+			raw, err := client.CallTool(ctx, "get_proxy_http_history", args)
+			if err != nil {
+				results <- result{idx: idx, err: err}
+				return
+			}
+
+			raw = trimEndMarker(raw)
+			if raw == "" {
+				results <- result{idx: idx}
+				return
+			}
+
+			entry, err := parse(raw, offset+1)
+			results <- result{idx: idx, entry: entry, err: err}
+		}(i)
+	}
+
+	// Collect results in order
+	ordered := make([]result, count)
+	for i := 0; i < count; i++ {
+		r := <-results
+		ordered[r.idx] = r
+	}
+
+	// Build entries slice preserving order, stopping at first gap. Failures
+	// past the gap don't matter: those entries are after the end of history.
+	var entries []T
+	for _, r := range ordered {
+		if r.entry == nil {
+			return entries, r.err
+		}
+		entries = append(entries, *r.entry)
+	}
+	return entries, nil
+}
+
 // parseSingleHistoryEntry parses a single proxy history entry from Burp's
-// response (JSON or wrapper format) into a lean summary.
-func parseSingleHistoryEntry(raw string, id int) *ProxyHistorySummary {
-	jsonEntries := burp.ParseProxyHistory(raw)
-	if len(jsonEntries) > 0 {
-		e := jsonEntries[0]
+// response (JSON or wrapper format) into a lean summary. Output in a format
+// no parser recognizes is an error rather than a missing entry.
+func parseSingleHistoryEntry(raw string, id int) (*ProxyHistorySummary, error) {
+	entries, _, err := burp.ParseProxyHistoryFormat(raw)
+	if len(entries) > 0 {
+		e := entries[0]
 		return &ProxyHistorySummary{
 			ID:         id,
 			Method:     e.Method,
 			URL:        e.URL,
 			StatusCode: e.StatusCode,
-		}
+		}, nil
 	}
 
+	// A bare HTTP request, with or without its response.
 	reqRaw, respRaw := burp.ExtractRequestResponse(raw)
-	if reqRaw == "" {
-		return nil
+	requestLine, _, _ := strings.Cut(reqRaw, "\n")
+	if !strings.Contains(requestLine, " HTTP/") {
+		if err != nil {
+			return nil, parseFailure("history entry %d: %w", id, err)
+		}
+		return nil, nil
 	}
 
+	return summarizeHistoryEntry(reqRaw, respRaw, id), nil
+}
+
+// summarizeHistoryEntry summarizes a raw request and its response, if any.
+func summarizeHistoryEntry(reqRaw, respRaw string, id int) *ProxyHistorySummary {
 	parsed := burp.ParseRawRequest(reqRaw)
 	summary := &ProxyHistorySummary{
 		ID:     id,
@@ -172,10 +216,28 @@ func trimEndMarker(raw string) string {
 	return raw
 }
 
+// historyEntry fetches the raw request and response of the proxy history
+// entry at index (1-based).
+func historyEntry(ctx context.Context, client *burp.Client, index int) (reqRaw, respRaw string, err error) {
+	raw, err := client.CallTool(ctx, "get_proxy_http_history", map[string]any{
+		"count":  1,
+		"offset": index - 1,
+	})
+	if err != nil {
+		return "", "", fmt.Errorf("failed to get request: %w", err)
+	}
+	raw = trimEndMarker(raw)
+	if raw == "" {
+		return "", "", fmt.Errorf("no entry at index %d", index)
+	}
+	reqRaw, respRaw = burp.ExtractRequestResponse(raw)
+	return reqRaw, respRaw, nil
+}
+
 // RegisterGetProxyHistoryTool registers the burp_get_proxy_history tool.
-func RegisterGetProxyHistoryTool(server *mcp.Server, client *burp.Client) {
-	mcp.AddTool(server, &mcp.Tool{
+func RegisterGetProxyHistoryTool(server *mcp.Server, client *burp.Client, st *store.Store) {
+	addTool(server, &mcp.Tool{
 		Name:        "burp_get_proxy_history",
-		Description: `Get proxy HTTP history summaries. Returns {id, method, url, statusCode} per entry.`,
-	}, getProxyHistoryHandler(client))
+		Description: `Get proxy HTTP history summaries, optionally only those matching a saved view. Returns {entries: [{id, method, url, statusCode}], count, scanned, nextOffset}.`,
+	}, getProxyHistoryHandler(client, st))
 }
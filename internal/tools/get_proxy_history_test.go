@@ -1,6 +1,9 @@
 package tools
 
-import "testing"
+import (
+	"context"
+	"testing"
+)
 
 func TestTrimEndMarker_ExactMatch(t *testing.T) {
 	got := trimEndMarker("Reached end of items")
@@ -58,3 +61,25 @@ func TestTrimEndMarker_Empty(t *testing.T) {
 		t.Errorf("got %q, want empty", got)
 	}
 }
+
+func TestGetProxyHistory_UnknownFormat(t *testing.T) {
+	f := &fakeBurp{}
+	f.add(historyJSON("a.test", "/"), "")
+	f.add("<html><body>Extension error</body></html>", "")
+	f.add("GET /raw HTTP/1.1\r\nHost: a.test\r\n\r\n", "")
+	client := startFakeBurp(t, f)
+	handler := getProxyHistoryHandler(client, nil)
+
+	_, out, err := handler(context.Background(), nil, GetProxyHistoryInput{Count: 1})
+	if err != nil || out.Count != 1 || out.Entries[0].Method != "GET" {
+		t.Fatalf("known format = %+v, %v", out, err)
+	}
+	_, out, err = handler(context.Background(), nil, GetProxyHistoryInput{Count: 1, Offset: 2})
+	if err != nil || out.Count != 1 || out.Entries[0].URL != "https://a.test/raw" {
+		t.Errorf("bare request = %+v, %v", out, err)
+	}
+	_, _, err = handler(context.Background(), nil, GetProxyHistoryInput{Count: 1, Offset: 1})
+	if err == nil || classifyError(err).Code != CodeParseFailure {
+		t.Errorf("unknown format: err = %v, want a parse failure", err)
+	}
+}
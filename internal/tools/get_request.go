@@ -11,9 +11,15 @@ import (
 // GetRequestInput is the input for burp_get_request.
 type GetRequestInput struct {
 	Index      int  `json:"index" jsonschema:"required,Proxy history index (1-based)"`
-	BodyLimit  int  `json:"bodyLimit,omitempty" jsonschema:"Response body byte limit (default 10000)"`
+	BodyLimit  int  `json:"bodyLimit,omitempty" jsonschema:"Response body byte limit (default 10000 or config bodyLimits, -1 = unlimited)"`
 	BodyOffset int  `json:"bodyOffset,omitempty" jsonschema:"Response body byte offset"`
 	AllHeaders bool `json:"allHeaders,omitempty" jsonschema:"Return all headers (default: security-relevant only)"`
+
+	BodyEncoding string `json:"bodyEncoding,omitempty" jsonschema:"Response body encoding: auto (default: utf8 for text, base64 for binary), utf8, base64, or hex"`
+	Hexdump      bool   `json:"hexdump,omitempty" jsonschema:"Add a hexdump of the first 256 returned body bytes"`
+	BodyView     string `json:"bodyView,omitempty" jsonschema:"HTML body view: raw (default), text (readable text without tags, scripts, and styles; bodyOffset, bodyLimit, and bodySize count the text), or title-and-forms (page with title, headings, forms, and inputs instead of the body)"`
+
+	Instance string `json:"instance,omitempty" jsonschema:"Named Burp instance from config (default: the --burp-url connection)"`
 }
 
 // GetRequestOutput is the output of burp_get_request.
@@ -24,31 +30,40 @@ type GetRequestOutput struct {
 
 // RequestSummary is the request portion.
 type RequestSummary struct {
-	Method  string              `json:"method"`
-	Path    string              `json:"path"`
-	Host    string              `json:"host,omitempty"`
-	Headers map[string][]string `json:"headers,omitempty"`
-	Body    string              `json:"body,omitempty"`
+	Method     string              `json:"method"`
+	Path       string              `json:"path"`
+	Host       string              `json:"host,omitempty"`
+	Headers    map[string][]string `json:"headers,omitempty"`
+	HeaderList []burp.Header       `json:"headerList,omitempty"`
+	Body       string              `json:"body,omitempty"`
+
+	Parts    []MultipartPartSummary `json:"parts,omitempty"`
+	Protobuf *ProtobufSummary       `json:"protobuf,omitempty"`
 }
 
 // ResponseSummary is the response portion.
 type ResponseSummary struct {
 	StatusCode int            `json:"statusCode"`
 	Headers    map[string]any `json:"headers,omitempty"`
+	HeaderList []burp.Header  `json:"headerList,omitempty"`
 	Body       string         `json:"body,omitempty"`
-	BodySize   int            `json:"bodySize"`
-	Truncated  bool           `json:"truncated,omitempty"`
+	BodyEnvelope
+
+	Protobuf *ProtobufSummary `json:"protobuf,omitempty"`
 }
 
 func getRequestHandler(client *burp.Client) func(context.Context, *mcp.CallToolRequest, GetRequestInput) (*mcp.CallToolResult, GetRequestOutput, error) {
 	return func(ctx context.Context, _ *mcp.CallToolRequest, input GetRequestInput) (*mcp.CallToolResult, GetRequestOutput, error) {
+		ctx = burp.WithInstance(ctx, input.Instance)
+
 		if input.Index < 1 {
 			return nil, GetRequestOutput{}, fmt.Errorf("index must be >= 1")
 		}
-
-		bodyLimit := input.BodyLimit
-		if bodyLimit == 0 {
-			bodyLimit = defaultBodyLimit
+		if err := checkBodyEncoding(input.BodyEncoding); err != nil {
+			return nil, GetRequestOutput{}, err
+		}
+		if err := checkBodyView(input.BodyView); err != nil {
+			return nil, GetRequestOutput{}, err
 		}
 
 		args := map[string]any{
@@ -69,27 +84,39 @@ func getRequestHandler(client *burp.Client) func(context.Context, *mcp.CallToolR
 		parsedReq := burp.ParseRawRequest(reqRaw)
 
 		reqSummary := RequestSummary{
-			Method:  parsedReq.Method,
-			Path:    parsedReq.Path,
-			Host:    parsedReq.Host,
-			Headers: parsedReq.Headers,
-			Body:    parsedReq.Body,
+			Method:     parsedReq.Method,
+			Path:       parsedReq.Path,
+			Host:       parsedReq.Host,
+			Headers:    parsedReq.Headers,
+			HeaderList: parsedReq.HeaderList,
+			Body:       parsedReq.Body,
+			Parts:      summarizeParts(parsedReq.Parts),
+			Protobuf:   summarizeProtobuf(parsedReq.Headers, parsedReq.Body),
 		}
 
-		parsedResp := burp.ParseHTTPResponse(respRaw, input.BodyOffset, bodyLimit)
+		cs := bodyCharset(respRaw)
+		bodyOffset := alignOffset(cs, input.BodyOffset)
+		parsedResp := parseResponse(ctx, respRaw, bodyOffset, input.BodyLimit, defaultBodyLimit)
 
 		var respSummary ResponseSummary
 		if parsedResp != nil {
-			headers := parsedResp.Headers
-			if !input.AllHeaders {
-				headers = burp.FilterHeaders(headers)
-			}
+			alignWindow(cs, parsedResp)
+			headers, headerList := outputHeaders(parsedResp, input.AllHeaders)
 			respSummary = ResponseSummary{
-				StatusCode: parsedResp.StatusCode,
-				Headers:    burp.FlattenHeaders(headers),
-				Body:       parsedResp.Body,
-				BodySize:   parsedResp.BodySize,
-				Truncated:  parsedResp.Truncated,
+				StatusCode:   parsedResp.StatusCode,
+				Headers:      headers,
+				HeaderList:   headerList,
+				BodyEnvelope: bodyEnvelope(parsedResp, bodyOffset, "call again", nil),
+			}
+			if body, env, ok := viewBody(ctx, respRaw, input.BodyView, input.BodyOffset, input.BodyLimit, "call again"); ok {
+				respSummary.Body, respSummary.BodyEnvelope = body, env
+			} else {
+				respSummary.Body = encodeBody(&respSummary.BodyEnvelope, parsedResp.Body, cs, bodyOffset, input.BodyEncoding, input.Hexdump)
+			}
+			// Decode the whole body: a frame cut by the body limit won't parse.
+			if burp.IsProtobuf(burp.GetHeader(parsedResp.Headers, "Content-Type")) {
+				full := burp.ParseHTTPResponse(respRaw, 0, 0)
+				respSummary.Protobuf = summarizeProtobuf(full.Headers, full.Body)
 			}
 		}
 
@@ -102,9 +129,9 @@ func getRequestHandler(client *burp.Client) func(context.Context, *mcp.CallToolR
 
 // RegisterGetRequestTool registers the burp_get_request tool.
 func RegisterGetRequestTool(server *mcp.Server, client *burp.Client) {
-	mcp.AddTool(server, &mcp.Tool{
+	addTool(server, &mcp.Tool{
 		Name: "burp_get_request",
-		Description: `Get full request+response from proxy history by index. ` +
-			`Returns {request: {method, path, host, headers, body}, response: {statusCode, headers, body, bodySize}}.`,
+		Description: `Get full request+response from proxy history by index. gRPC and protobuf bodies are also decoded (protobuf: {messages: [{size, decoded}]}). bodyView text or title-and-forms condenses an HTML response to its readable text or to page: {title, headings, forms, inputs}. ` +
+			`Returns {request: {method, path, host, headers, body, parts, protobuf}, response: {statusCode, headers, body, bodySize, returnedBytes, truncated, continuationHint, bodyEncoding, charset, fileType, bodyView, page, protobuf}}.`,
 	}, getRequestHandler(client))
 }
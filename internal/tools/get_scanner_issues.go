@@ -1,28 +1,68 @@
 package tools
 
 import (
+	"cmp"
 	"context"
 	"fmt"
 
 	"github.com/c0tton-fluff/burp-mcp-server/internal/burp"
+	"github.com/c0tton-fluff/burp-mcp-server/internal/store"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 )
 
 // GetScannerIssuesInput is the input for burp_get_scanner_issues.
 type GetScannerIssuesInput struct {
-	Count       int `json:"count,omitempty" jsonschema:"Number of issues to return (default 10)"`
-	Offset      int `json:"offset,omitempty" jsonschema:"Offset for pagination (default 0)"`
-	DetailLimit int `json:"detailLimit,omitempty" jsonschema:"Max characters per issue detail (default 500, -1 = unlimited)"`
+	Count       int    `json:"count,omitempty" jsonschema:"Number of issues to return (default 10)"`
+	Offset      int    `json:"offset,omitempty" jsonschema:"Offset for pagination (default 0)"`
+	DetailLimit int    `json:"detailLimit,omitempty" jsonschema:"Max characters per issue detail (default 500, -1 = unlimited)"`
+	Source      string `json:"source,omitempty" jsonschema:"live (default): Burp's current scanner results; imported: issues loaded from Burp XML reports with 'burp-mcp-server import issues', found by burp_run_nuclei, or reported by burp_run_passive_checks"`
+	Instance    string `json:"instance,omitempty" jsonschema:"Named Burp instance from config (default: the --burp-url connection)"`
 }
 
 // GetScannerIssuesOutput is the output of burp_get_scanner_issues.
 type GetScannerIssuesOutput struct {
 	Issues []burp.ScannerIssue `json:"issues"`
 	Count  int                 `json:"count"`
+	Error  string              `json:"error,omitempty"` // a later page failed; Issues holds what came before it
+	Note   string              `json:"note,omitempty"`
 }
 
-func getScannerIssuesHandler(client *burp.Client) func(context.Context, *mcp.CallToolRequest, GetScannerIssuesInput) (*mcp.CallToolResult, GetScannerIssuesOutput, error) {
+// importedScannerIssues pages through the store's imported issues in the
+// same shape as live ones.
+func importedScannerIssues(st *store.Store, count, offset, detailLimit int) []burp.ScannerIssue {
+	issues := st.Issues()
+	out := []burp.ScannerIssue{}
+	for _, i := range issues[min(max(offset, 0), len(issues)):] {
+		if len(out) == count {
+			break
+		}
+		issue := scannerIssue(i, detailLimit)
+		triageIssue(st, &issue)
+		out = append(out, issue)
+	}
+	return out
+}
+
+// scannerIssue converts a stored issue to the shape Burp's scanner issues
+// are returned in, cutting the detail to detailLimit characters (0 = unlimited).
+func scannerIssue(i store.Issue, detailLimit int) burp.ScannerIssue {
+	issue := burp.ScannerIssue{
+		Name:       i.Name,
+		Severity:   i.Severity,
+		Confidence: i.Confidence,
+		URL:        i.Host + i.Path,
+	}
+	issue.SetDetail(cmp.Or(i.IssueDetail, i.IssueBackground), detailLimit)
+	if i.Verification != nil {
+		issue.Verdict = i.Verification.Verdict
+	}
+	return issue
+}
+
+func getScannerIssuesHandler(client *burp.Client, st *store.Store) func(context.Context, *mcp.CallToolRequest, GetScannerIssuesInput) (*mcp.CallToolResult, GetScannerIssuesOutput, error) {
 	return func(ctx context.Context, _ *mcp.CallToolRequest, input GetScannerIssuesInput) (*mcp.CallToolResult, GetScannerIssuesOutput, error) {
+		ctx = burp.WithInstance(ctx, input.Instance)
+
 		count := input.Count
 		if count <= 0 {
 			count = 10
@@ -31,16 +71,6 @@ func getScannerIssuesHandler(client *burp.Client) func(context.Context, *mcp.Cal
 			count = 50
 		}
 
-		args := map[string]any{
-			"count":  count,
-			"offset": input.Offset,
-		}
-
-		raw, err := client.CallTool(ctx, "get_scanner_issues", args)
-		if err != nil {
-			return nil, GetScannerIssuesOutput{}, fmt.Errorf("failed to get scanner issues: %w", err)
-		}
-
 		detailLimit := input.DetailLimit
 		if detailLimit == 0 {
 			detailLimit = 500
@@ -49,13 +79,48 @@ func getScannerIssuesHandler(client *burp.Client) func(context.Context, *mcp.Cal
 			detailLimit = 0
 		}
 
-		issues := burp.ParseScannerIssues(raw, detailLimit)
+		switch input.Source {
+		case "", "live":
+		case "imported":
+			issues := importedScannerIssues(st, count, input.Offset, detailLimit)
+			return nil, GetScannerIssuesOutput{Issues: issues, Count: len(issues)}, nil
+		default:
+			return nil, GetScannerIssuesOutput{}, fmt.Errorf("source must be live or imported")
+		}
+
+		issues, err := fetchChunked(ctx, count, input.Offset, func(ctx context.Context, n, offset int) ([]burp.ScannerIssue, error) {
+			raw, err := client.CallTool(ctx, "get_scanner_issues", map[string]any{
+				"count":  n,
+				"offset": offset,
+			})
+			if err != nil {
+				return nil, err
+			}
+			issues, _, err := burp.ParseScannerIssuesFormat(trimEndMarker(raw), detailLimit)
+			if err != nil {
+				return nil, parseFailure("%w", err)
+			}
+			return issues, nil
+		})
+		if err != nil && len(issues) == 0 {
+			return nil, GetScannerIssuesOutput{}, fmt.Errorf("failed to get scanner issues: %w", err)
+		}
+
+		for i := range issues {
+			triageIssue(st, &issues[i])
+		}
 		output := GetScannerIssuesOutput{
 			Issues: issues,
 			Count:  len(issues),
 		}
+		if err != nil {
+			output.Error = err.Error()
+		}
 		if output.Issues == nil {
 			output.Issues = []burp.ScannerIssue{}
+			if n := len(st.Issues()); n > 0 && input.Offset == 0 {
+				output.Note = fmt.Sprintf("Burp has no scanner issues; %d imported issues are available with source=imported", n)
+			}
 		}
 
 		return nil, output, nil
@@ -63,9 +128,9 @@ func getScannerIssuesHandler(client *burp.Client) func(context.Context, *mcp.Cal
 }
 
 // RegisterGetScannerIssuesTool registers the burp_get_scanner_issues tool.
-func RegisterGetScannerIssuesTool(server *mcp.Server, client *burp.Client) {
-	mcp.AddTool(server, &mcp.Tool{
+func RegisterGetScannerIssuesTool(server *mcp.Server, client *burp.Client, st *store.Store) {
+	addTool(server, &mcp.Tool{
 		Name:        "burp_get_scanner_issues",
-		Description: `Get scanner findings. Returns structured issues: {name, severity, confidence, url, issueDetail}.`,
-	}, getScannerIssuesHandler(client))
+		Description: `Get scanner findings, live from Burp or, with source=imported, from Burp XML issue reports imported into the local store. Returns structured issues: {name, severity, confidence, url, issueDetail, detailSize, detailTruncated, verdict, status, statusNote}, detailSize and detailTruncated being set when the detail was cut at detailLimit, verdict an imported issue's latest burp_verify_issue result and status its burp_set_issue_status triage.`,
+	}, getScannerIssuesHandler(client, st))
 }
@@ -0,0 +1,63 @@
+package tools
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/c0tton-fluff/burp-mcp-server/internal/burp"
+	"github.com/c0tton-fluff/burp-mcp-server/internal/store"
+)
+
+func TestGetScannerIssues_Imported(t *testing.T) {
+	st, err := store.Open(filepath.Join(t.TempDir(), "store.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := st.ImportIssues([]store.Issue{
+		{SerialNumber: "1", Name: "SQL injection", Host: "https://shop.example", Path: "/search", Severity: "High", IssueDetail: "The q parameter is injectable."},
+		{SerialNumber: "2", Name: "Strict transport security not enforced", Host: "https://shop.example", Path: "/", IssueBackground: "HSTS is missing."},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	handler := getScannerIssuesHandler(nil, st)
+	_, out, err := handler(context.Background(), nil, GetScannerIssuesInput{Source: "imported", DetailLimit: 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := burp.ScannerIssue{Name: "SQL injection", Severity: "High", URL: "https://shop.example/search", IssueDetail: "The q...", DetailSize: 30, DetailTruncated: true}
+	if out.Count != 2 || out.Issues[0] != want || out.Issues[1].IssueDetail != "HSTS ..." {
+		t.Errorf("out = %+v", out)
+	}
+
+	_, out, err = handler(context.Background(), nil, GetScannerIssuesInput{Source: "imported", Offset: 1, Count: 5})
+	if err != nil || out.Count != 1 || out.Issues[0].URL != "https://shop.example/" {
+		t.Errorf("offset page = %+v, %v", out, err)
+	}
+	_, out, err = handler(context.Background(), nil, GetScannerIssuesInput{Source: "imported", Offset: 9})
+	if err != nil || out.Count != 0 || out.Issues == nil {
+		t.Errorf("past the end = %+v, %v", out, err)
+	}
+
+	if _, _, err := handler(context.Background(), nil, GetScannerIssuesInput{Source: "archive"}); err == nil {
+		t.Error("expected error for unknown source")
+	}
+}
+
+func TestGetScannerIssues_Replay(t *testing.T) {
+	client, _ := burp.NewClient("http://127.0.0.1:1/sse") // never connected
+	if err := client.Replay("testdata/replay/scanner_issues.jsonl"); err != nil {
+		t.Fatal(err)
+	}
+	st, _ := store.Open(filepath.Join(t.TempDir(), "store.json"))
+
+	// The recorded 4-issue page timed out; the handler splits it in two.
+	_, out, err := getScannerIssuesHandler(client, st)(context.Background(), nil, GetScannerIssuesInput{Count: 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out.Count != 3 || out.Issues[0].Name != "SQL injection" || out.Issues[2].Severity != "Low" || out.Error != "" {
+		t.Errorf("out = %+v", out)
+	}
+}
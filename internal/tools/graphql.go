@@ -0,0 +1,242 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/c0tton-fluff/burp-mcp-server/internal/burp"
+	"github.com/c0tton-fluff/burp-mcp-server/internal/graphql"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const (
+	defaultSuggestDepth = 2
+	maxSuggestDepth     = 5
+)
+
+// graphQLPayload is one GraphQL request as carried in a body or query string.
+type graphQLPayload struct {
+	Query         string         `json:"query"`
+	OperationName string         `json:"operationName,omitempty"`
+	Variables     map[string]any `json:"variables,omitempty"`
+}
+
+// extractGraphQL pulls the GraphQL requests out of raw: an application/graphql
+// body, a JSON body (one object, or an array for batching), or the
+// query/operationName/variables parameters of a GET.
+func extractGraphQL(raw string) (payloads []graphQLPayload, batched bool, err error) {
+	parsed := burp.ParseRawRequest(raw)
+	body := strings.TrimSpace(parsed.Body)
+	switch {
+	case body != "" && strings.Contains(burp.GetHeader(parsed.Headers, "Content-Type"), "graphql"):
+		return []graphQLPayload{{Query: body}}, false, nil
+	case strings.HasPrefix(body, "["):
+		if err := json.Unmarshal([]byte(body), &payloads); err != nil {
+			return nil, false, fmt.Errorf("batched GraphQL body: %w", err)
+		}
+		return payloads, true, nil
+	case strings.HasPrefix(body, "{"):
+		var p graphQLPayload
+		if err := json.Unmarshal([]byte(body), &p); err != nil {
+			return nil, false, fmt.Errorf("GraphQL body: %w", err)
+		}
+		return []graphQLPayload{p}, false, nil
+	}
+
+	_, rawQuery, _ := strings.Cut(parsed.Path, "?")
+	params, err := url.ParseQuery(rawQuery)
+	if err != nil || params.Get("query") == "" {
+		return nil, false, fmt.Errorf("no GraphQL query in the body or query string")
+	}
+	p := graphQLPayload{Query: params.Get("query"), OperationName: params.Get("operationName")}
+	if v := params.Get("variables"); v != "" {
+		if err := json.Unmarshal([]byte(v), &p.Variables); err != nil {
+			return nil, false, fmt.Errorf("variables parameter: %w", err)
+		}
+	}
+	return []graphQLPayload{p}, false, nil
+}
+
+// GraphQLParseInput is the input for burp_graphql_parse.
+type GraphQLParseInput struct {
+	Raw   string `json:"raw,omitempty" jsonschema:"Raw HTTP request carrying GraphQL (JSON body, batch array, application/graphql, or GET query string)"`
+	Query string `json:"query,omitempty" jsonschema:"A bare GraphQL document, instead of raw"`
+}
+
+// GraphQLOperation is one operation in a parsed document.
+type GraphQLOperation struct {
+	Type       string   `json:"type"`
+	Name       string   `json:"name,omitempty"`
+	Variables  []string `json:"variables,omitempty"`
+	Directives []string `json:"directives,omitempty"`
+	Fields     []string `json:"fields"`
+}
+
+// GraphQLFragment is one fragment definition in a parsed document.
+type GraphQLFragment struct {
+	Name   string   `json:"name"`
+	On     string   `json:"on"`
+	Fields []string `json:"fields"`
+}
+
+// GraphQLRequestInfo describes one GraphQL request.
+type GraphQLRequestInfo struct {
+	OperationName string             `json:"operationName,omitempty"`
+	Variables     map[string]any     `json:"variables,omitempty"`
+	Operations    []GraphQLOperation `json:"operations,omitempty"`
+	Fragments     []GraphQLFragment  `json:"fragments,omitempty"`
+	Stats         graphql.Stats      `json:"stats"`
+	Error         string             `json:"error,omitempty"`
+}
+
+// GraphQLParseOutput is the output of burp_graphql_parse.
+type GraphQLParseOutput struct {
+	Requests []GraphQLRequestInfo `json:"requests"`
+	Batched  bool                 `json:"batched,omitempty"`
+}
+
+func graphQLParseHandler() func(context.Context, *mcp.CallToolRequest, GraphQLParseInput) (*mcp.CallToolResult, GraphQLParseOutput, error) {
+	return func(_ context.Context, _ *mcp.CallToolRequest, input GraphQLParseInput) (*mcp.CallToolResult, GraphQLParseOutput, error) {
+		var payloads []graphQLPayload
+		var batched bool
+		switch {
+		case input.Raw != "" && input.Query != "":
+			return nil, GraphQLParseOutput{}, fmt.Errorf("raw and query are mutually exclusive")
+		case input.Query != "":
+			payloads = []graphQLPayload{{Query: input.Query}}
+		case input.Raw != "":
+			if err := validateRawRequest(input.Raw); err != nil {
+				return nil, GraphQLParseOutput{}, err
+			}
+			var err error
+			if payloads, batched, err = extractGraphQL(input.Raw); err != nil {
+				return nil, GraphQLParseOutput{}, err
+			}
+		default:
+			return nil, GraphQLParseOutput{}, fmt.Errorf("raw or query is required")
+		}
+
+		out := GraphQLParseOutput{Requests: make([]GraphQLRequestInfo, len(payloads)), Batched: batched}
+		for i, p := range payloads {
+			info := GraphQLRequestInfo{OperationName: p.OperationName, Variables: p.Variables}
+			// A document that fails to parse still reports what parsed before the error.
+			doc, err := graphql.Parse(p.Query)
+			if err != nil {
+				info.Error = err.Error()
+			}
+			for _, op := range doc.Operations {
+				info.Operations = append(info.Operations, GraphQLOperation{
+					Type:       op.Type,
+					Name:       op.Name,
+					Variables:  op.Variables,
+					Directives: op.Directives,
+					Fields:     graphql.FieldPaths(op.Selections),
+				})
+			}
+			for _, f := range doc.Fragments {
+				info.Fragments = append(info.Fragments, GraphQLFragment{Name: f.Name, On: f.On, Fields: graphql.FieldPaths(f.Selections)})
+			}
+			info.Stats = doc.Stats()
+			out.Requests[i] = info
+		}
+		return nil, out, nil
+	}
+}
+
+// GraphQLIntrospectInput is the input for burp_graphql_introspect.
+type GraphQLIntrospectInput struct {
+	Raw  string `json:"raw" jsonschema:"required,A request to the GraphQL endpoint (e.g. from proxy history); its body is replaced by the introspection query, headers such as auth are kept"`
+	Host string `json:"host,omitempty" jsonschema:"Target host (overrides Host header)"`
+	Port int    `json:"port,omitempty" jsonschema:"Target port (default based on TLS)"`
+	TLS  *bool  `json:"tls,omitempty" jsonschema:"Use HTTPS (default true)"`
+
+	Field string `json:"field,omitempty" jsonschema:"Root query or mutation field to build a ready-to-fill attack query for"`
+	Depth int    `json:"depth,omitempty" jsonschema:"Selection depth of the suggested query (default 2, max 5)"`
+
+	HeaderProfile string `json:"headerProfile,omitempty" jsonschema:"Header rule profile from config (default: 'default')"`
+	Instance      string `json:"instance,omitempty" jsonschema:"Named Burp instance from config (default: the --burp-url connection)"`
+}
+
+// GraphQLIntrospectOutput is the output of burp_graphql_introspect.
+type GraphQLIntrospectOutput struct {
+	graphql.Schema
+	Interesting    []string `json:"interesting,omitempty"`
+	SuggestedQuery string   `json:"suggestedQuery,omitempty"`
+}
+
+func graphQLIntrospectHandler(client *burp.Client) func(context.Context, *mcp.CallToolRequest, GraphQLIntrospectInput) (*mcp.CallToolResult, GraphQLIntrospectOutput, error) {
+	return func(ctx context.Context, _ *mcp.CallToolRequest, input GraphQLIntrospectInput) (*mcp.CallToolResult, GraphQLIntrospectOutput, error) {
+		ctx = burp.WithInstance(ctx, input.Instance)
+		if err := validateRawRequest(input.Raw); err != nil {
+			return nil, GraphQLIntrospectOutput{}, err
+		}
+
+		r, err := splitRawRequest(input.Raw)
+		if err != nil {
+			return nil, GraphQLIntrospectOutput{}, err
+		}
+		body, _ := json.Marshal(graphQLPayload{Query: graphql.IntrospectionQuery, OperationName: "IntrospectionQuery"})
+		r.method, r.body = "POST", string(body)
+		r.setHeader("Content-Type", "application/json")
+
+		rawNorm, parsed, err := prepareRequest(fixContentLength(r.String()), input.HeaderProfile)
+		if err != nil {
+			return nil, GraphQLIntrospectOutput{}, err
+		}
+		t, err := resolveTarget(input.Host, input.Port, input.TLS, parsed.Host)
+		if err != nil {
+			return nil, GraphQLIntrospectOutput{}, err
+		}
+		responseText, err := sendWithFallback(ctx, client, rawNorm, parsed, t)
+		if err != nil {
+			return nil, GraphQLIntrospectOutput{}, err
+		}
+		resp := burp.ParseHTTPResponse(responseText, 0, 0)
+		if resp == nil {
+			return nil, GraphQLIntrospectOutput{}, parseFailure("failed to parse response")
+		}
+
+		schema, err := graphql.ParseIntrospection([]byte(resp.Body))
+		if errors.Is(err, graphql.ErrIntrospectionDisabled) {
+			return nil, GraphQLIntrospectOutput{}, fmt.Errorf("HTTP %d, %w; try field-suggestion probing (misspelled fields often get \"Did you mean\" hints)", resp.StatusCode, err)
+		}
+		if err != nil {
+			return nil, GraphQLIntrospectOutput{}, fmt.Errorf("HTTP %d: %w", resp.StatusCode, err)
+		}
+
+		out := GraphQLIntrospectOutput{Schema: *schema, Interesting: schema.Interesting()}
+		if input.Field != "" {
+			depth := input.Depth
+			if depth <= 0 {
+				depth = defaultSuggestDepth
+			}
+			if out.SuggestedQuery, err = schema.SuggestQuery(input.Field, min(depth, maxSuggestDepth)); err != nil {
+				return nil, GraphQLIntrospectOutput{}, err
+			}
+		}
+		return nil, out, nil
+	}
+}
+
+// RegisterGraphQLParseTool registers the burp_graphql_parse tool.
+func RegisterGraphQLParseTool(server *mcp.Server) {
+	addTool(server, &mcp.Tool{
+		Name: "burp_graphql_parse",
+		Description: `Parse the GraphQL in a request locally: JSON body, batch array, application/graphql, or GET query string. ` +
+			`Returns {requests: [{operationName, variables, operations: [{type, name, variables, fields}], fragments, stats: {depth, fields, aliases}}], batched}.`,
+	}, graphQLParseHandler())
+}
+
+// RegisterGraphQLIntrospectTool registers the burp_graphql_introspect tool.
+func RegisterGraphQLIntrospectTool(server *mcp.Server, client *burp.Client) {
+	addTool(server, &mcp.Tool{
+		Name: "burp_graphql_introspect",
+		Description: `Send an introspection query via Burp, reusing a captured request's endpoint and headers, and condense the schema. ` +
+			`field builds a query for that root field selecting everything reachable to depth. ` +
+			`Returns {queries, mutations, subscriptions, types: [{name, kind, fields}], interesting, suggestedQuery}.`,
+	}, graphQLIntrospectHandler(client))
+}
@@ -0,0 +1,90 @@
+package tools
+
+import (
+	"context"
+	"slices"
+	"testing"
+)
+
+func TestExtractGraphQL(t *testing.T) {
+	tests := []struct {
+		name    string
+		raw     string
+		want    int
+		batched bool
+		opName  string
+	}{
+		{
+			name:   "json body",
+			raw:    "POST /graphql HTTP/1.1\r\nHost: a\r\nContent-Type: application/json\r\n\r\n" + `{"query":"query Q { me { id } }","operationName":"Q","variables":{"id":1}}`,
+			want:   1,
+			opName: "Q",
+		},
+		{
+			name:    "batch",
+			raw:     "POST /graphql HTTP/1.1\r\nHost: a\r\n\r\n" + `[{"query":"{ a }"},{"query":"{ b }"}]`,
+			want:    2,
+			batched: true,
+		},
+		{
+			name: "application/graphql",
+			raw:  "POST /graphql HTTP/1.1\r\nHost: a\r\nContent-Type: application/graphql\r\n\r\n{ me { id } }",
+			want: 1,
+		},
+		{
+			name:   "get",
+			raw:    "GET /graphql?query=%7B%20me%20%7D&operationName=Me&variables=%7B%22x%22%3A1%7D HTTP/1.1\r\nHost: a\r\n\r\n",
+			want:   1,
+			opName: "Me",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, batched, err := extractGraphQL(tt.raw)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(got) != tt.want || batched != tt.batched {
+				t.Fatalf("got %d payloads, batched=%v", len(got), batched)
+			}
+			if got[0].OperationName != tt.opName || got[0].Query == "" {
+				t.Errorf("payload = %+v", got[0])
+			}
+		})
+	}
+
+	if _, _, err := extractGraphQL("GET / HTTP/1.1\r\nHost: a\r\n\r\n"); err == nil {
+		t.Error("expected an error without a query")
+	}
+}
+
+func TestGraphQLParseHandler(t *testing.T) {
+	handler := graphQLParseHandler()
+	_, out, err := handler(context.Background(), nil, GraphQLParseInput{
+		Raw: "POST /graphql HTTP/1.1\r\nHost: a\r\nContent-Type: application/json\r\n\r\n" +
+			`[{"query":"query Q($id: ID!) { user(id: $id) { name } }","variables":{"id":"2"}},{"query":"{ broken"}]`,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !out.Batched || len(out.Requests) != 2 {
+		t.Fatalf("out = %+v", out)
+	}
+	first := out.Requests[0]
+	if len(first.Operations) != 1 || first.Operations[0].Name != "Q" {
+		t.Fatalf("operations = %+v", first.Operations)
+	}
+	if want := []string{"user(id: $id)", "user(id: $id).name"}; !slices.Equal(first.Operations[0].Fields, want) {
+		t.Errorf("fields = %q", first.Operations[0].Fields)
+	}
+	if first.Variables["id"] != "2" || first.Stats.Depth != 2 {
+		t.Errorf("request = %+v", first)
+	}
+	if out.Requests[1].Error == "" {
+		t.Error("expected a parse error for the second request")
+	}
+
+	if _, _, err := handler(context.Background(), nil, GraphQLParseInput{}); err == nil {
+		t.Error("expected an error without raw or query")
+	}
+}
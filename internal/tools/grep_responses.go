@@ -0,0 +1,245 @@
+package tools
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/c0tton-fluff/burp-mcp-server/internal/burp"
+	"github.com/c0tton-fluff/burp-mcp-server/internal/store"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const (
+	maxGrepSources        = 50
+	defaultGrepMatchesPer = 10
+	// maxGrepValues caps the distinct values listed per field.
+	maxGrepValues = 100
+
+	grepScopeBody    = "body"
+	grepScopeHeaders = "headers"
+	grepScopeAll     = "all"
+)
+
+// GrepResponsesInput is the input for burp_grep_responses.
+type GrepResponsesInput struct {
+	Regex      string               `json:"regex" jsonschema:"required,Regular expression (RE2); named groups (?P<name>...) become record fields"`
+	Indexes    []int                `json:"indexes,omitempty" jsonschema:"Proxy history indexes (1-based) whose responses to search"`
+	View       string               `json:"view,omitempty" jsonschema:"Saved view (burp_save_view) instead of indexes: search the newest matching history entries, up to 50"`
+	Responses  []BatchResponseEntry `json:"responses,omitempty" jsonschema:"The responses array of a burp_batch_send result, as returned"`
+	Raw        []string             `json:"raw,omitempty" jsonschema:"Raw HTTP responses"`
+	Scope      string               `json:"scope,omitempty" jsonschema:"What to search: body (default), headers, or all"`
+	MaxMatches int                  `json:"maxMatches,omitempty" jsonschema:"Matches kept per response (default 10)"`
+	Instance   string               `json:"instance,omitempty" jsonschema:"Named Burp instance from config (default: the --burp-url connection)"`
+}
+
+// GrepRecord is the matches in one response.
+type GrepRecord struct {
+	Source     string              `json:"source"`
+	StatusCode int                 `json:"statusCode,omitempty"`
+	Matches    []map[string]string `json:"matches"`
+	Truncated  bool                `json:"truncated,omitempty"`
+	Error      string              `json:"error,omitempty"`
+}
+
+// GrepResponsesOutput is the output of burp_grep_responses.
+type GrepResponsesOutput struct {
+	Fields  []string            `json:"fields"`
+	Records []GrepRecord        `json:"records"`
+	Values  map[string][]string `json:"values,omitempty"`
+	Summary string              `json:"summary"`
+}
+
+// grepSource is one response to search, split into headers and body.
+type grepSource struct {
+	name, headers, body string
+	status              int
+	err                 string
+}
+
+// grepFields names the record fields for re: its named groups, or "match"
+// and the numbered groups when it has none.
+func grepFields(re *regexp.Regexp) []string {
+	var named []string
+	for _, n := range re.SubexpNames()[1:] {
+		if n != "" {
+			named = append(named, n)
+		}
+	}
+	if len(named) > 0 {
+		return named
+	}
+	fields := []string{"match"}
+	for i := 1; i <= re.NumSubexp(); i++ {
+		fields = append(fields, strconv.Itoa(i))
+	}
+	return fields
+}
+
+// grepRecord runs re over text, keeping at most limit matches.
+func grepRecord(re *regexp.Regexp, text string, limit int) ([]map[string]string, bool) {
+	all := re.FindAllStringSubmatch(text, limit+1)
+	named := slices.ContainsFunc(re.SubexpNames()[1:], func(n string) bool { return n != "" })
+	matches := make([]map[string]string, 0, min(len(all), limit))
+	for _, m := range all[:min(len(all), limit)] {
+		rec := map[string]string{}
+		for i, name := range re.SubexpNames() {
+			switch {
+			case i == 0 && !named:
+				rec["match"] = m[0]
+			case i > 0 && named && name != "":
+				rec[name] = m[i]
+			case i > 0 && !named:
+				rec[strconv.Itoa(i)] = m[i]
+			}
+		}
+		matches = append(matches, rec)
+	}
+	return matches, len(all) > limit
+}
+
+// headerText renders a response's headers back into header lines, in their
+// original order when the ordered list is present.
+func headerText(headers map[string]any, list []burp.Header) string {
+	var b strings.Builder
+	if len(list) > 0 {
+		for _, h := range list {
+			fmt.Fprintf(&b, "%s: %s\r\n", h.Name, h.Value)
+		}
+		return b.String()
+	}
+	for _, k := range slices.Sorted(maps.Keys(headers)) {
+		switch v := headers[k].(type) {
+		case []any:
+			for _, s := range v {
+				fmt.Fprintf(&b, "%s: %v\r\n", k, s)
+			}
+		case []string:
+			for _, s := range v {
+				fmt.Fprintf(&b, "%s: %s\r\n", k, s)
+			}
+		default:
+			fmt.Fprintf(&b, "%s: %v\r\n", k, v)
+		}
+	}
+	return b.String()
+}
+
+// rawGrepSource splits a raw response for searching.
+func rawGrepSource(name, raw string) grepSource {
+	head, body, _ := strings.Cut(raw, "\r\n\r\n")
+	src := grepSource{name: name, headers: head, body: body}
+	if resp := burp.ParseHTTPResponse(raw, 0, 0); resp != nil {
+		src.status = resp.StatusCode
+	}
+	return src
+}
+
+func grepResponsesHandler(client *burp.Client, st *store.Store) func(context.Context, *mcp.CallToolRequest, GrepResponsesInput) (*mcp.CallToolResult, GrepResponsesOutput, error) {
+	return func(ctx context.Context, _ *mcp.CallToolRequest, input GrepResponsesInput) (*mcp.CallToolResult, GrepResponsesOutput, error) {
+		ctx = burp.WithInstance(ctx, input.Instance)
+
+		re, err := regexp.Compile(input.Regex)
+		if err != nil {
+			return nil, GrepResponsesOutput{}, fmt.Errorf("regex: %w", err)
+		}
+		scope := cmp.Or(input.Scope, grepScopeBody)
+		if scope != grepScopeBody && scope != grepScopeHeaders && scope != grepScopeAll {
+			return nil, GrepResponsesOutput{}, fmt.Errorf("scope must be %s, %s, or %s", grepScopeBody, grepScopeHeaders, grepScopeAll)
+		}
+		if input.View != "" {
+			if len(input.Indexes) > 0 {
+				return nil, GrepResponsesOutput{}, fmt.Errorf("set indexes or view, not both")
+			}
+			view, err := loadView(st, input.View)
+			if err != nil {
+				return nil, GrepResponsesOutput{}, err
+			}
+			input.Indexes, err = newestViewIndexes(ctx, client, view, max(0, maxGrepSources-len(input.Responses)-len(input.Raw)))
+			if err != nil {
+				return nil, GrepResponsesOutput{}, err
+			}
+		}
+		n := len(input.Indexes) + len(input.Responses) + len(input.Raw)
+		if n == 0 {
+			if input.View != "" {
+				return nil, GrepResponsesOutput{}, fmt.Errorf("no history entries match view %q", input.View)
+			}
+			return nil, GrepResponsesOutput{}, fmt.Errorf("indexes, view, responses, or raw is required")
+		}
+		if n > maxGrepSources {
+			return nil, GrepResponsesOutput{}, fmt.Errorf("too many responses: %d, max %d", n, maxGrepSources)
+		}
+		limit := input.MaxMatches
+		if limit <= 0 {
+			limit = defaultGrepMatchesPer
+		}
+
+		sources := make([]grepSource, len(input.Indexes), n)
+		parallel(len(input.Indexes), func(i int) {
+			name := fmt.Sprintf("history %d", input.Indexes[i])
+			_, resp, err := historyEntry(ctx, client, input.Indexes[i])
+			switch {
+			case err != nil:
+				sources[i] = grepSource{name: name, err: err.Error()}
+			case resp == "":
+				sources[i] = grepSource{name: name, err: "no response"}
+			default:
+				sources[i] = rawGrepSource(name, resp)
+			}
+		})
+		for i, r := range input.Responses {
+			name := cmp.Or(r.Tag, fmt.Sprintf("response %d", i+1))
+			sources = append(sources, grepSource{name: name, headers: headerText(r.Headers, r.HeaderList), body: r.Body, status: r.StatusCode, err: r.Error})
+		}
+		for i, raw := range input.Raw {
+			sources = append(sources, rawGrepSource(fmt.Sprintf("raw %d", i+1), raw))
+		}
+
+		fields := grepFields(re)
+		out := GrepResponsesOutput{Fields: fields, Records: make([]GrepRecord, 0, n), Values: map[string][]string{}}
+		total, hit := 0, 0
+		for _, src := range sources {
+			rec := GrepRecord{Source: src.name, StatusCode: src.status, Matches: []map[string]string{}, Error: src.err}
+			if src.err == "" {
+				text := src.body
+				switch scope {
+				case grepScopeHeaders:
+					text = src.headers
+				case grepScopeAll:
+					text = src.headers + "\r\n\r\n" + src.body
+				}
+				rec.Matches, rec.Truncated = grepRecord(re, text, limit)
+			}
+			if len(rec.Matches) > 0 {
+				hit++
+			}
+			total += len(rec.Matches)
+			for _, m := range rec.Matches {
+				for _, f := range fields {
+					if v := m[f]; v != "" && len(out.Values[f]) < maxGrepValues && !slices.Contains(out.Values[f], v) {
+						out.Values[f] = append(out.Values[f], v)
+					}
+				}
+			}
+			out.Records = append(out.Records, rec)
+		}
+		out.Summary = fmt.Sprintf("%d matches in %d of %d responses", total, hit, len(sources))
+		return nil, out, nil
+	}
+}
+
+// RegisterGrepResponsesTool registers the burp_grep_responses tool.
+func RegisterGrepResponsesTool(server *mcp.Server, client *burp.Client, st *store.Store) {
+	addTool(server, &mcp.Tool{
+		Name: "burp_grep_responses",
+		Description: `Grep-extract across responses, like Intruder's grep extract: run a regex over proxy history entries (indexes, or the newest entries of a saved view), a burp_batch_send result (responses), or raw responses, and return one record per response with a field per named group. ` +
+			`scope picks body (default), headers, or all. Without named groups, records hold match and numbered groups. ` +
+			`Returns {fields, records: [{source, statusCode, matches, truncated, error}], values (distinct values per field), summary}.`,
+	}, grepResponsesHandler(client, st))
+}
@@ -0,0 +1,47 @@
+package tools
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/c0tton-fluff/burp-mcp-server/internal/burp"
+)
+
+func TestGrepRecord(t *testing.T) {
+	body := `<tr><td>alice</td><td>admin</td></tr><tr><td>bob</td><td>user</td></tr><tr><td>eve</td><td>user</td></tr>`
+
+	re := regexp.MustCompile(`<td>(?P<user>\w+)</td><td>(?P<role>\w+)</td>`)
+	if f := grepFields(re); strings.Join(f, ",") != "user,role" {
+		t.Errorf("fields = %v", f)
+	}
+	matches, truncated := grepRecord(re, body, 2)
+	if len(matches) != 2 || !truncated || matches[1]["user"] != "bob" || matches[1]["role"] != "user" {
+		t.Errorf("matches = %v, truncated = %v", matches, truncated)
+	}
+
+	re = regexp.MustCompile(`<td>(\w+)</td><td>admin`)
+	if f := grepFields(re); strings.Join(f, ",") != "match,1" {
+		t.Errorf("fields = %v", f)
+	}
+	matches, truncated = grepRecord(re, body, 10)
+	if len(matches) != 1 || truncated || matches[0]["1"] != "alice" || matches[0]["match"] != "<td>alice</td><td>admin" {
+		t.Errorf("unnamed matches = %v", matches)
+	}
+}
+
+func TestGrepSources(t *testing.T) {
+	src := rawGrepSource("raw 1", "HTTP/1.1 302 Found\r\nLocation: /home\r\nSet-Cookie: sid=1\r\n\r\nbody")
+	if src.status != 302 || !strings.Contains(src.headers, "Set-Cookie: sid=1") || src.body != "body" {
+		t.Errorf("source = %+v", src)
+	}
+
+	got := headerText(map[string]any{"Set-Cookie": []any{"a=1", "b=2"}, "Location": "/x"}, nil)
+	if got != "Location: /x\r\nSet-Cookie: a=1\r\nSet-Cookie: b=2\r\n" {
+		t.Errorf("headerText = %q", got)
+	}
+	got = headerText(nil, []burp.Header{{Name: "Set-Cookie", Value: "a=1"}, {Name: "Location", Value: "/x"}, {Name: "set-cookie", Value: "b=2"}})
+	if got != "Set-Cookie: a=1\r\nLocation: /x\r\nset-cookie: b=2\r\n" {
+		t.Errorf("headerText from list = %q", got)
+	}
+}
@@ -0,0 +1,92 @@
+package tools
+
+import (
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/c0tton-fluff/burp-mcp-server/internal/burp"
+	"github.com/c0tton-fluff/burp-mcp-server/internal/config"
+)
+
+// prepareRequest validates a raw request, normalizes it, applies the
+// selected header profile, and fills in {{session:name}} tokens. Every
+// traffic-generating tool goes through here so outbound requests are shaped
+// consistently.
+func prepareRequest(raw, headerProfile string) (string, *burp.ParsedHTTPRequest, error) {
+	if err := validateRawRequest(raw); err != nil {
+		return "", nil, err
+	}
+
+	rules, err := settings.HeaderProfile(headerProfile)
+	if err != nil {
+		return "", nil, err
+	}
+
+	rawNorm, err := expandSessions(applyHeaderRules(normalizeRawRequest(raw), rules))
+	if err != nil {
+		return "", nil, err
+	}
+	return rawNorm, burp.ParseRawRequest(rawNorm), nil
+}
+
+// prepareExactRequest is prepareRequest for a request sent byte for byte:
+// it is validated and parsed for its target, but not normalized, shaped by
+// header rules, or expanded.
+func prepareExactRequest(raw string) (string, *burp.ParsedHTTPRequest, error) {
+	if err := validateRawRequest(raw); err != nil {
+		return "", nil, err
+	}
+	return raw, burp.ParseRawRequest(raw), nil
+}
+
+// applyHeaderRules strips, overrides, and adds headers on a normalized (CRLF) request.
+// The request line and body are left untouched.
+func applyHeaderRules(rawNorm string, rules config.HeaderRules) string {
+	if len(rules.Strip) == 0 && len(rules.Set) == 0 && len(rules.Add) == 0 {
+		return rawNorm
+	}
+
+	const sep = "\r\n\r\n"
+	idx := strings.Index(rawNorm, sep)
+	if idx < 0 {
+		return rawNorm
+	}
+	lines := strings.Split(rawNorm[:idx], "\r\n")
+	body := rawNorm[idx+len(sep):]
+
+	drop := make(map[string]bool, len(rules.Strip)+len(rules.Set))
+	for _, h := range rules.Strip {
+		drop[strings.ToLower(h)] = true
+	}
+	for h := range rules.Set {
+		drop[strings.ToLower(h)] = true
+	}
+
+	kept := []string{lines[0]}
+	present := make(map[string]bool)
+	for _, line := range lines[1:] {
+		name := line
+		if colon := strings.Index(line, ":"); colon > 0 {
+			name = strings.TrimSpace(line[:colon])
+		}
+		if drop[strings.ToLower(name)] {
+			continue
+		}
+		present[strings.ToLower(name)] = true
+		kept = append(kept, line)
+	}
+
+	for _, h := range slices.Sorted(maps.Keys(rules.Set)) {
+		kept = append(kept, h+": "+rules.Set[h])
+		present[strings.ToLower(h)] = true
+	}
+	for _, h := range slices.Sorted(maps.Keys(rules.Add)) {
+		if present[strings.ToLower(h)] {
+			continue
+		}
+		kept = append(kept, h+": "+rules.Add[h])
+	}
+
+	return strings.Join(kept, "\r\n") + sep + body
+}
@@ -0,0 +1,61 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/c0tton-fluff/burp-mcp-server/internal/config"
+)
+
+func TestApplyHeaderRules_StripSetAdd(t *testing.T) {
+	raw := "GET / HTTP/1.1\r\nHost: example.com\r\nIf-None-Match: \"abc\"\r\nUser-Agent: agent\r\nX-Id: old\r\n\r\n"
+	rules := config.HeaderRules{
+		Strip: []string{"if-none-match"},
+		Set:   map[string]string{"X-Id": "new"},
+		Add:   map[string]string{"User-Agent": "ignored", "X-Bug-Bounty": "tester"},
+	}
+	got := applyHeaderRules(raw, rules)
+	want := "GET / HTTP/1.1\r\nHost: example.com\r\nUser-Agent: agent\r\nX-Id: new\r\nX-Bug-Bounty: tester\r\n\r\n"
+	if got != want {
+		t.Errorf("applyHeaderRules() = %q, want %q", got, want)
+	}
+}
+
+func TestApplyHeaderRules_PreservesBody(t *testing.T) {
+	raw := "POST / HTTP/1.1\r\nHost: example.com\r\n\r\nIf-None-Match: body-text"
+	got := applyHeaderRules(raw, config.HeaderRules{Strip: []string{"If-None-Match"}})
+	if !strings.HasSuffix(got, "\r\n\r\nIf-None-Match: body-text") {
+		t.Errorf("body should be untouched, got %q", got)
+	}
+}
+
+func TestApplyHeaderRules_NoRules(t *testing.T) {
+	raw := "GET / HTTP/1.1\r\nHost: example.com\r\n\r\n"
+	if got := applyHeaderRules(raw, config.HeaderRules{}); got != raw {
+		t.Errorf("should return unchanged, got %q", got)
+	}
+}
+
+func TestPrepareRequest_DefaultProfile(t *testing.T) {
+	Configure(&config.Config{HeaderProfiles: map[string]config.HeaderRules{
+		config.DefaultProfile: {Set: map[string]string{"X-Bug-Bounty": "tester"}},
+	}})
+	defer Configure(nil)
+
+	rawNorm, parsed, err := prepareRequest("GET / HTTP/1.1\nHost: example.com\n", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(rawNorm, "X-Bug-Bounty: tester\r\n") {
+		t.Errorf("default profile not applied: %q", rawNorm)
+	}
+	if parsed.Headers["X-Bug-Bounty"][0] != "tester" {
+		t.Errorf("parsed headers should reflect rules, got %v", parsed.Headers)
+	}
+}
+
+func TestPrepareRequest_UnknownProfile(t *testing.T) {
+	if _, _, err := prepareRequest("GET / HTTP/1.1\r\nHost: example.com\r\n\r\n", "nope"); err == nil {
+		t.Error("expected error for unknown profile")
+	}
+}
@@ -0,0 +1,106 @@
+package tools
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/c0tton-fluff/burp-mcp-server/internal/burp"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const (
+	defaultHexdumpLength = 512
+	maxHexdumpLength     = 8192
+)
+
+// HexdumpInput is the input for burp_hexdump.
+type HexdumpInput struct {
+	Content  string `json:"content" jsonschema:"required,Data to dump"`
+	Encoding string `json:"encoding,omitempty" jsonschema:"How content is encoded: utf8 (default), base64, or hex"`
+	Offset   int    `json:"offset,omitempty" jsonschema:"First byte to dump"`
+	Length   int    `json:"length,omitempty" jsonschema:"Bytes to dump (default 512, max 8192)"`
+}
+
+// HexdumpOutput is the output of burp_hexdump.
+type HexdumpOutput struct {
+	Dump       string `json:"dump"`
+	Offset     int    `json:"offset"`
+	Length     int    `json:"length"`
+	TotalBytes int    `json:"totalBytes"`
+	FileType   string `json:"fileType,omitempty"`
+	Next       int    `json:"next,omitempty"`
+}
+
+func hexdumpHandler() func(context.Context, *mcp.CallToolRequest, HexdumpInput) (*mcp.CallToolResult, HexdumpOutput, error) {
+	return func(_ context.Context, _ *mcp.CallToolRequest, input HexdumpInput) (*mcp.CallToolResult, HexdumpOutput, error) {
+		if input.Content == "" {
+			return nil, HexdumpOutput{}, fmt.Errorf("content is required")
+		}
+		data, err := decodeContent(input.Content, input.Encoding)
+		if err != nil {
+			return nil, HexdumpOutput{}, err
+		}
+		if input.Offset < 0 || input.Length < 0 {
+			return nil, HexdumpOutput{}, fmt.Errorf("offset and length must not be negative")
+		}
+		if input.Offset > len(data) {
+			return nil, HexdumpOutput{}, fmt.Errorf("offset %d is past the end of the %d-byte input", input.Offset, len(data))
+		}
+
+		length := input.Length
+		if length == 0 {
+			length = defaultHexdumpLength
+		}
+		length = min(length, maxHexdumpLength, len(data)-input.Offset)
+		window := data[input.Offset : input.Offset+length]
+
+		out := HexdumpOutput{
+			Dump:       burp.Hexdump(window, input.Offset),
+			Offset:     input.Offset,
+			Length:     length,
+			TotalBytes: len(data),
+			FileType:   burp.SniffFileType(string(data)),
+		}
+		if end := input.Offset + length; end < len(data) {
+			out.Next = end
+		}
+		return nil, out, nil
+	}
+}
+
+// decodeContent turns tool input in the given encoding into raw bytes.
+func decodeContent(content, encoding string) ([]byte, error) {
+	switch encoding {
+	case "", encodingUTF8:
+		return []byte(content), nil
+	case encodingBase64:
+		content = strings.Join(strings.Fields(content), "")
+		b, err := base64.StdEncoding.DecodeString(content)
+		if err != nil {
+			// Try URL-safe base64 as fallback
+			if b, err = base64.URLEncoding.DecodeString(content); err != nil {
+				return nil, fmt.Errorf("base64 decode: %w", err)
+			}
+		}
+		return b, nil
+	case encodingHex:
+		b, err := hex.DecodeString(strings.Join(strings.Fields(content), ""))
+		if err != nil {
+			return nil, fmt.Errorf("hex decode: %w", err)
+		}
+		return b, nil
+	}
+	return nil, fmt.Errorf("encoding must be utf8, base64, or hex, got %q", encoding)
+}
+
+// RegisterHexdumpTool registers the burp_hexdump tool.
+func RegisterHexdumpTool(server *mcp.Server) {
+	addTool(server, &mcp.Tool{
+		Name: "burp_hexdump",
+		Description: `Render data as an offset/hex/ASCII dump locally. Params: content, encoding (utf8|base64|hex, e.g. a base64 body), offset, length (default 512). ` +
+			`Returns {dump, offset, length, totalBytes, fileType, next}; next is the offset to continue from when more remains.`,
+	}, hexdumpHandler())
+}
@@ -0,0 +1,50 @@
+package tools
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestHexdumpHandler(t *testing.T) {
+	handler := hexdumpHandler()
+	_, out, err := handler(context.Background(), nil, HexdumpInput{
+		Content:  "iVBORw0KGgoAAAANSUhEUgAAAAEAAAAB",
+		Encoding: "base64",
+		Offset:   8,
+		Length:   8,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "00000008  00 00 00 0d 49 48 44 52                           |....IHDR|\n"
+	if out.Dump != want {
+		t.Errorf("dump = %q, want %q", out.Dump, want)
+	}
+	if out.TotalBytes != 24 || out.Next != 16 || out.FileType != "image/png" {
+		t.Errorf("got %+v", out)
+	}
+}
+
+func TestHexdumpHandler_Window(t *testing.T) {
+	handler := hexdumpHandler()
+	_, out, err := handler(context.Background(), nil, HexdumpInput{Content: strings.Repeat("A", 600)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out.Length != defaultHexdumpLength || out.Next != defaultHexdumpLength {
+		t.Errorf("default window: %+v", out)
+	}
+
+	_, out, err = handler(context.Background(), nil, HexdumpInput{Content: "48 54 54 50", Encoding: "hex", Length: 100})
+	if err != nil || out.Length != 4 || out.Next != 0 || !strings.HasSuffix(out.Dump, "|HTTP|\n") {
+		t.Errorf("hex input: %+v, %v", out, err)
+	}
+
+	if _, _, err := handler(context.Background(), nil, HexdumpInput{Content: "abc", Offset: 4}); err == nil {
+		t.Error("expected error for offset past end")
+	}
+	if _, _, err := handler(context.Background(), nil, HexdumpInput{Content: "abc", Encoding: "rot13"}); err == nil {
+		t.Error("expected error for unknown encoding")
+	}
+}
@@ -0,0 +1,291 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/c0tton-fluff/burp-mcp-server/internal/burp"
+	"github.com/c0tton-fluff/burp-mcp-server/internal/store"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// clearHistoryTool is the upstream tool that clears proxy history. Burp's
+// MCP extension doesn't offer it in every version.
+const clearHistoryTool = "clear_proxy_http_history"
+
+const (
+	defaultSnapshotEntries = 1000
+	maxSnapshotEntries     = 5000
+	snapshotBatch          = 50
+)
+
+// SnapshotHistoryInput is the input for burp_snapshot_history.
+type SnapshotHistoryInput struct {
+	Label      string `json:"label" jsonschema:"Name for the snapshot, e.g. phase1-auth; labels can't be reused"`
+	MaxEntries int    `json:"maxEntries,omitempty" jsonschema:"Most entries to copy, oldest first (default 1000, max 5000)"`
+	Instance   string `json:"instance,omitempty" jsonschema:"Named Burp instance from config (default: the --burp-url connection)"`
+}
+
+// SnapshotHistoryOutput is the output of burp_snapshot_history.
+type SnapshotHistoryOutput struct {
+	Snapshot store.SnapshotInfo `json:"snapshot"`
+	// Truncated is set when history had more than maxEntries entries.
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+// snapshotHistory copies up to maxEntries proxy history entries into st
+// under label. A failed read saves nothing, so a snapshot is never silently
+// incomplete.
+func snapshotHistory(ctx context.Context, client *burp.Client, st *store.Store, label, instance string, maxEntries int) (SnapshotHistoryOutput, error) {
+	label = strings.TrimSpace(label)
+	if label == "" {
+		return SnapshotHistoryOutput{}, fmt.Errorf("label is required")
+	}
+	if _, err := st.Snapshot(label); err == nil {
+		return SnapshotHistoryOutput{}, fmt.Errorf("snapshot %q already exists", label)
+	}
+	if maxEntries <= 0 {
+		maxEntries = defaultSnapshotEntries
+	}
+	maxEntries = min(maxEntries, maxSnapshotEntries)
+
+	var out SnapshotHistoryOutput
+	entries := []store.HistoryEntry{}
+	for len(entries) < maxEntries {
+		n := min(snapshotBatch, maxEntries-len(entries))
+		batch, err := fetchHistory(ctx, client, len(entries), n, parseSnapshotEntry)
+		if err != nil {
+			return SnapshotHistoryOutput{}, fmt.Errorf("failed to read proxy history entry %d: %w", len(entries)+len(batch)+1, err)
+		}
+		entries = append(entries, batch...)
+		if len(batch) < n {
+			break
+		}
+		if len(entries) == maxEntries {
+			more, err := fetchHistory(ctx, client, len(entries), 1, parseSnapshotEntry)
+			out.Truncated = len(more) > 0 || err != nil
+		}
+	}
+
+	info, err := st.SaveSnapshot(store.Snapshot{Label: label, Instance: instance, Entries: entries})
+	if err != nil {
+		return SnapshotHistoryOutput{}, err
+	}
+	out.Snapshot = info
+	return out, nil
+}
+
+// visitTraffic calls visit with every entry of the snapshot named label, or
+// of every snapshot when label is empty; with live, it reads up to
+// maxEntries entries of live proxy history instead, and more reports
+// whether history goes on.
+func visitTraffic(ctx context.Context, client *burp.Client, st *store.Store, label string, live bool, maxEntries int, visit func(snapshot string, e store.HistoryEntry)) (more bool, err error) {
+	label = strings.TrimSpace(label)
+	if !live {
+		if maxEntries != 0 {
+			return false, fmt.Errorf("maxEntries applies to live=true")
+		}
+		var snaps []store.Snapshot
+		if label != "" {
+			snap, err := st.Snapshot(label)
+			if err != nil {
+				return false, err
+			}
+			snaps = append(snaps, snap)
+		} else {
+			for _, info := range st.Snapshots() {
+				snap, err := st.Snapshot(info.Label)
+				if err != nil {
+					return false, err
+				}
+				snaps = append(snaps, snap)
+			}
+		}
+		if len(snaps) == 0 {
+			return false, fmt.Errorf("no history snapshots; save one with burp_snapshot_history or set live=true")
+		}
+		for _, snap := range snaps {
+			for _, e := range snap.Entries {
+				visit(snap.Label, e)
+			}
+		}
+		return false, nil
+	}
+
+	if label != "" {
+		return false, fmt.Errorf("set snapshot or live, not both")
+	}
+	if maxEntries <= 0 {
+		maxEntries = defaultSnapshotEntries
+	}
+	maxEntries = min(maxEntries, maxSnapshotEntries)
+	for read := 0; read < maxEntries; {
+		n := min(snapshotBatch, maxEntries-read)
+		batch, err := fetchHistory(ctx, client, read, n, parseSnapshotEntry)
+		if err != nil {
+			return false, fmt.Errorf("failed to read proxy history entry %d: %w", read+len(batch)+1, err)
+		}
+		for _, e := range batch {
+			visit("", e)
+		}
+		read += len(batch)
+		if len(batch) < n {
+			break
+		}
+		if read == maxEntries {
+			next, _ := fetchHistory(ctx, client, read, 1, parseSnapshotEntry)
+			more = len(next) > 0
+		}
+	}
+	return more, nil
+}
+
+func parseSnapshotEntry(raw string, id int) (*store.HistoryEntry, error) {
+	req, resp := burp.ExtractRequestResponse(raw)
+	return &store.HistoryEntry{ID: id, Request: req, Response: resp}, nil
+}
+
+func snapshotHistoryHandler(client *burp.Client, st *store.Store) func(context.Context, *mcp.CallToolRequest, SnapshotHistoryInput) (*mcp.CallToolResult, SnapshotHistoryOutput, error) {
+	return func(ctx context.Context, _ *mcp.CallToolRequest, input SnapshotHistoryInput) (*mcp.CallToolResult, SnapshotHistoryOutput, error) {
+		ctx = burp.WithInstance(ctx, input.Instance)
+		out, err := snapshotHistory(ctx, client, st, input.Label, input.Instance, input.MaxEntries)
+		if err != nil {
+			return nil, SnapshotHistoryOutput{}, err
+		}
+		return nil, out, nil
+	}
+}
+
+// GetSnapshotInput is the input for burp_get_snapshot.
+type GetSnapshotInput struct {
+	Label string `json:"label,omitempty" jsonschema:"Snapshot to read; omit to list snapshots"`
+	ID    int    `json:"id,omitempty" jsonschema:"Entry id to return in full; omit for summaries of every entry"`
+}
+
+// GetSnapshotOutput is the output of burp_get_snapshot.
+type GetSnapshotOutput struct {
+	Snapshots []store.SnapshotInfo  `json:"snapshots,omitempty"`
+	Snapshot  *store.SnapshotInfo   `json:"snapshot,omitempty"`
+	Entries   []ProxyHistorySummary `json:"entries,omitempty"`
+	Entry     *store.HistoryEntry   `json:"entry,omitempty"`
+}
+
+func getSnapshotHandler(st *store.Store) func(context.Context, *mcp.CallToolRequest, GetSnapshotInput) (*mcp.CallToolResult, GetSnapshotOutput, error) {
+	return func(_ context.Context, _ *mcp.CallToolRequest, input GetSnapshotInput) (*mcp.CallToolResult, GetSnapshotOutput, error) {
+		if strings.TrimSpace(input.Label) == "" {
+			return nil, GetSnapshotOutput{Snapshots: st.Snapshots()}, nil
+		}
+		snap, err := st.Snapshot(strings.TrimSpace(input.Label))
+		if err != nil {
+			return nil, GetSnapshotOutput{}, err
+		}
+		info := snap.Info()
+		out := GetSnapshotOutput{Snapshot: &info}
+		if input.ID != 0 {
+			for _, e := range snap.Entries {
+				if e.ID == input.ID {
+					out.Entry = &e
+					return nil, out, nil
+				}
+			}
+			return nil, GetSnapshotOutput{}, fmt.Errorf("snapshot %q has no entry %d", snap.Label, input.ID)
+		}
+		out.Entries = make([]ProxyHistorySummary, len(snap.Entries))
+		for i, e := range snap.Entries {
+			out.Entries[i] = *summarizeHistoryEntry(e.Request, e.Response, e.ID)
+		}
+		return nil, out, nil
+	}
+}
+
+// DeleteProxyHistoryInput is the input for burp_delete_proxy_history.
+type DeleteProxyHistoryInput struct {
+	Label        string `json:"label,omitempty" jsonschema:"Snapshot label to save the history under before clearing it; required unless skipSnapshot is set"`
+	SkipSnapshot bool   `json:"skipSnapshot,omitempty" jsonschema:"Clear without saving a snapshot first; the history is lost"`
+	MaxEntries   int    `json:"maxEntries,omitempty" jsonschema:"Most entries to snapshot (default 1000, max 5000); clearing refuses to run if history is longer"`
+	Instance     string `json:"instance,omitempty" jsonschema:"Named Burp instance from config (default: the --burp-url connection)"`
+}
+
+// DeleteProxyHistoryOutput is the output of burp_delete_proxy_history.
+type DeleteProxyHistoryOutput struct {
+	Snapshot *store.SnapshotInfo `json:"snapshot,omitempty"`
+	Cleared  bool                `json:"cleared"`
+}
+
+func deleteProxyHistoryHandler(client *burp.Client, st *store.Store) func(context.Context, *mcp.CallToolRequest, DeleteProxyHistoryInput) (*mcp.CallToolResult, DeleteProxyHistoryOutput, error) {
+	return func(ctx context.Context, _ *mcp.CallToolRequest, input DeleteProxyHistoryInput) (*mcp.CallToolResult, DeleteProxyHistoryOutput, error) {
+		ctx = burp.WithInstance(ctx, input.Instance)
+		if input.SkipSnapshot == (strings.TrimSpace(input.Label) != "") {
+			return nil, DeleteProxyHistoryOutput{}, fmt.Errorf("set label to snapshot history before clearing it, or skipSnapshot to clear without one")
+		}
+		if err := checkDryRun(ctx); err != nil {
+			return nil, DeleteProxyHistoryOutput{}, err
+		}
+		summary := fmt.Sprintf("clear proxy history after saving snapshot %q", input.Label)
+		if input.SkipSnapshot {
+			summary = "clear proxy history without a snapshot; it can't be recovered"
+		}
+		if input.Instance != "" {
+			summary += " on instance " + input.Instance
+		}
+		if err := requireApproval(ctx, "burp_delete_proxy_history", resolvedTarget{}, summary, ""); err != nil {
+			return nil, DeleteProxyHistoryOutput{}, err
+		}
+
+		var out DeleteProxyHistoryOutput
+		if !input.SkipSnapshot {
+			snap, err := snapshotHistory(ctx, client, st, input.Label, input.Instance, input.MaxEntries)
+			if err != nil {
+				return nil, DeleteProxyHistoryOutput{}, fmt.Errorf("history not cleared: %w", err)
+			}
+			if snap.Truncated {
+				return nil, DeleteProxyHistoryOutput{}, fmt.Errorf("history not cleared: it has more than the %d entries saved in snapshot %q; raise maxEntries and use a new label",
+					snap.Snapshot.Entries, snap.Snapshot.Label)
+			}
+			out.Snapshot = &snap.Snapshot
+		}
+
+		if _, err := client.CallTool(ctx, clearHistoryTool, map[string]any{}); err != nil {
+			if out.Snapshot != nil {
+				return nil, DeleteProxyHistoryOutput{}, fmt.Errorf("history saved as snapshot %q but not cleared (the MCP extension may not offer %s; clear it in Burp under Proxy > HTTP history): %w",
+					out.Snapshot.Label, clearHistoryTool, err)
+			}
+			return nil, DeleteProxyHistoryOutput{}, fmt.Errorf("history not cleared (the MCP extension may not offer %s; clear it in Burp under Proxy > HTTP history): %w", clearHistoryTool, err)
+		}
+		out.Cleared = true
+		// History starts over, so passive checks must too.
+		if err := st.SetPassiveCursor(input.Instance, 0); err != nil {
+			return nil, DeleteProxyHistoryOutput{}, fmt.Errorf("history cleared, but resetting passive check progress failed: %w", err)
+		}
+		return nil, out, nil
+	}
+}
+
+// RegisterSnapshotHistoryTool registers the burp_snapshot_history tool.
+func RegisterSnapshotHistoryTool(server *mcp.Server, client *burp.Client, st *store.Store) {
+	addTool(server, &mcp.Tool{
+		Name: "burp_snapshot_history",
+		Description: `Copy proxy history, raw requests and responses, into the local store under a label, preserving evidence before history is cleared between test phases. ` +
+			`Read it back with burp_get_snapshot. Returns {snapshot: {label, instance, createdAt, entries}, truncated}.`,
+	}, snapshotHistoryHandler(client, st))
+}
+
+// RegisterGetSnapshotTool registers the burp_get_snapshot tool.
+func RegisterGetSnapshotTool(server *mcp.Server, st *store.Store) {
+	addTool(server, &mcp.Tool{
+		Name: "burp_get_snapshot",
+		Description: `List proxy history snapshots, summarize one snapshot's entries, or return one entry in full. ` +
+			`Returns {snapshots: [{label, instance, createdAt, entries}], snapshot, entries: [{id, method, url, statusCode}], entry: {id, request, response}}.`,
+	}, getSnapshotHandler(st))
+}
+
+// RegisterDeleteProxyHistoryTool registers the burp_delete_proxy_history tool.
+func RegisterDeleteProxyHistoryTool(server *mcp.Server, client *burp.Client, st *store.Store) {
+	addTool(server, &mcp.Tool{
+		Name: "burp_delete_proxy_history",
+		Description: `Clear Burp's proxy history, first saving it as a snapshot under label (see burp_snapshot_history) unless skipSnapshot is set. ` +
+			`Nothing is cleared if the snapshot fails. Needs an MCP extension version that can clear history; otherwise the snapshot is kept and history must be cleared in Burp. ` +
+			`Returns {snapshot: {label, instance, createdAt, entries}, cleared}.`,
+	}, deleteProxyHistoryHandler(client, st))
+}
@@ -0,0 +1,156 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/c0tton-fluff/burp-mcp-server/internal/approval"
+	"github.com/c0tton-fluff/burp-mcp-server/internal/config"
+	"github.com/c0tton-fluff/burp-mcp-server/internal/store"
+)
+
+func TestSnapshotHistory(t *testing.T) {
+	st, err := store.Open(filepath.Join(t.TempDir(), "store.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := &fakeBurp{}
+	for i := range 7 {
+		f.add(historyEntryJSON("GET", "a.test", fmt.Sprintf("/item/%d", i), 200+i), "")
+	}
+	client := startFakeBurp(t, f)
+	ctx := context.Background()
+
+	snapshot := snapshotHistoryHandler(client, st)
+	_, out, err := snapshot(ctx, nil, SnapshotHistoryInput{Label: "phase1", MaxEntries: 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out.Snapshot.Entries != 5 || !out.Truncated {
+		t.Errorf("capped snapshot = %+v", out)
+	}
+	_, out, err = snapshot(ctx, nil, SnapshotHistoryInput{Label: "phase1-all"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out.Snapshot.Entries != 7 || out.Truncated || out.Snapshot.CreatedAt.IsZero() {
+		t.Errorf("full snapshot = %+v", out)
+	}
+	if _, _, err := snapshot(ctx, nil, SnapshotHistoryInput{Label: "PHASE1"}); err == nil {
+		t.Error("snapshot label was reused")
+	}
+
+	get := getSnapshotHandler(st)
+	_, list, err := get(ctx, nil, GetSnapshotInput{})
+	if err != nil || len(list.Snapshots) != 2 || list.Snapshots[0].Label != "phase1" {
+		t.Fatalf("list = %+v, %v", list, err)
+	}
+	_, got, err := get(ctx, nil, GetSnapshotInput{Label: "phase1-all"})
+	if err != nil || len(got.Entries) != 7 {
+		t.Fatalf("get = %+v, %v", got, err)
+	}
+	if e := got.Entries[6]; e.ID != 7 || e.Method != "GET" || e.URL != "https://a.test/item/6" || e.StatusCode != 206 {
+		t.Errorf("entry 7 summary = %+v", e)
+	}
+	_, got, err = get(ctx, nil, GetSnapshotInput{Label: "phase1-all", ID: 3})
+	if err != nil || got.Entry == nil || !strings.Contains(got.Entry.Request, "/item/2") || !strings.Contains(got.Entry.Response, "token=202") {
+		t.Fatalf("get entry = %+v, %v", got.Entry, err)
+	}
+	if _, _, err := get(ctx, nil, GetSnapshotInput{Label: "phase1-all", ID: 8}); err == nil {
+		t.Error("expected error for a missing entry")
+	}
+}
+
+func TestDeleteProxyHistory(t *testing.T) {
+	st, err := store.Open(filepath.Join(t.TempDir(), "store.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := &fakeBurp{clearable: true}
+	for i := range 3 {
+		f.add(historyEntryJSON("POST", "a.test", fmt.Sprintf("/login/%d", i), 302), "")
+	}
+	client := startFakeBurp(t, f)
+	ctx := context.Background()
+	del := deleteProxyHistoryHandler(client, st)
+
+	if _, _, err := del(ctx, nil, DeleteProxyHistoryInput{}); err == nil {
+		t.Error("cleared without a label or skipSnapshot")
+	}
+	if _, _, err := del(ctx, nil, DeleteProxyHistoryInput{Label: "x", SkipSnapshot: true}); err == nil {
+		t.Error("accepted both a label and skipSnapshot")
+	}
+
+	t.Cleanup(func() { Configure(nil) })
+	Configure(&config.Config{DryRun: true})
+	_, _, err = del(ctx, nil, DeleteProxyHistoryInput{Label: "before"})
+	var dry *DryRunError
+	if !errors.As(err, &dry) || len(st.Snapshots()) != 0 {
+		t.Fatalf("dry run: err = %v, snapshots = %+v", err, st.Snapshots())
+	}
+	Configure(nil)
+
+	if _, _, err := del(ctx, nil, DeleteProxyHistoryInput{Label: "short", MaxEntries: 2}); err == nil || !strings.Contains(err.Error(), "not cleared") {
+		t.Errorf("truncated snapshot: err = %v", err)
+	}
+	if len(f.history) != 3 {
+		t.Fatal("history cleared after a truncated snapshot")
+	}
+
+	_, out, err := del(ctx, nil, DeleteProxyHistoryInput{Label: "before"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !out.Cleared || out.Snapshot == nil || out.Snapshot.Entries != 3 || len(f.history) != 0 {
+		t.Errorf("out = %+v, history = %d", out, len(f.history))
+	}
+	if snap, err := st.Snapshot("before"); err != nil || !strings.Contains(snap.Entries[2].Request, "/login/2") {
+		t.Errorf("snapshot = %+v, %v", snap, err)
+	}
+}
+
+func TestDeleteProxyHistory_Approval(t *testing.T) {
+	st, err := store.Open(filepath.Join(t.TempDir(), "store.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := &fakeBurp{clearable: true}
+	f.add(historyEntryJSON("GET", "a.test", "/", 200), "")
+	client := startFakeBurp(t, f)
+
+	t.Cleanup(func() { Configure(nil) })
+	dir := t.TempDir()
+	Configure(&config.Config{
+		Scope:    []string{"a.test"},
+		Approval: &config.ApprovalConfig{Tools: []string{"burp_delete_proxy_history"}, Dir: dir},
+	})
+	decideWhenPending(t, approval.Open(dir), false)
+	_, _, err = deleteProxyHistoryHandler(client, st)(context.Background(), nil, DeleteProxyHistoryInput{SkipSnapshot: true})
+	var ae *ApprovalError
+	if !errors.As(err, &ae) || len(f.history) != 1 {
+		t.Errorf("denied clear: err = %v, history = %d", err, len(f.history))
+	}
+}
+
+func TestDeleteProxyHistory_Unsupported(t *testing.T) {
+	st, err := store.Open(filepath.Join(t.TempDir(), "store.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := &fakeBurp{}
+	f.add(historyEntryJSON("GET", "a.test", "/", 200), "")
+	client := startFakeBurp(t, f)
+
+	// Without the upstream tool the snapshot is still kept.
+	_, _, err = deleteProxyHistoryHandler(client, st)(context.Background(), nil, DeleteProxyHistoryInput{Label: "kept"})
+	if err == nil || !strings.Contains(err.Error(), `saved as snapshot "kept"`) {
+		t.Fatalf("err = %v", err)
+	}
+	if snaps := st.Snapshots(); len(snaps) != 1 || snaps[0].Entries != 1 {
+		t.Errorf("snapshots = %+v", snaps)
+	}
+}
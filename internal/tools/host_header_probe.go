@@ -0,0 +1,261 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/c0tton-fluff/burp-mcp-server/internal/burp"
+	"github.com/c0tton-fluff/burp-mcp-server/internal/config"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// hostExcerptRadius is how much body context to keep around a reflection.
+const hostExcerptRadius = 80
+
+// hostVariant is one Host manipulation. apply edits the request and returns
+// the string to look for in the response.
+type hostVariant struct {
+	name   string
+	change string
+	apply  func(r *rawRequest, host, attacker, scheme string) string
+}
+
+// hostVariants covers the routing ambiguities between front ends and the
+// application: a replaced Host, an absolute-URI request line that disagrees
+// with it, duplicate Host headers, override headers, and port injection.
+var hostVariants = []hostVariant{
+	{"host", "Host: {attacker}", func(r *rawRequest, _, attacker, _ string) string {
+		r.setHeader("Host", attacker)
+		return attacker
+	}},
+	{"absolute-uri", "request line {scheme}://{host}/..., Host: {attacker}", func(r *rawRequest, host, attacker, scheme string) string {
+		r.target = scheme + "://" + host + r.target
+		r.setHeader("Host", attacker)
+		return attacker
+	}},
+	{"absolute-uri-attacker", "request line {scheme}://{attacker}/..., Host: {host}", func(r *rawRequest, _, attacker, scheme string) string {
+		r.target = scheme + "://" + attacker + r.target
+		return attacker
+	}},
+	{"duplicate-host", "second Host: {attacker}", func(r *rawRequest, _, attacker, _ string) string {
+		r.headers = append(r.headers, "Host: "+attacker)
+		return attacker
+	}},
+	{"indented-host", "folded \" Host: {attacker}\" line", func(r *rawRequest, _, attacker, _ string) string {
+		r.headers = append([]string{" Host: " + attacker}, r.headers...)
+		return attacker
+	}},
+	{"x-forwarded-host", "X-Forwarded-Host: {attacker}", overrideHeader("X-Forwarded-Host")},
+	{"x-host", "X-Host: {attacker}", overrideHeader("X-Host")},
+	{"x-forwarded-server", "X-Forwarded-Server: {attacker}", overrideHeader("X-Forwarded-Server")},
+	{"x-http-host-override", "X-HTTP-Host-Override: {attacker}", overrideHeader("X-HTTP-Host-Override")},
+	{"forwarded", "Forwarded: host={attacker}", func(r *rawRequest, _, attacker, _ string) string {
+		r.setHeader("Forwarded", "host="+attacker)
+		return attacker
+	}},
+	{"port-injection", "Host: {host}:{attacker}", func(r *rawRequest, host, attacker, _ string) string {
+		r.setHeader("Host", host+":"+attacker)
+		return host + ":" + attacker
+	}},
+}
+
+func overrideHeader(name string) func(r *rawRequest, host, attacker, scheme string) string {
+	return func(r *rawRequest, _, attacker, _ string) string {
+		r.setHeader(name, attacker)
+		return attacker
+	}
+}
+
+// HostHeaderProbeInput is the input for burp_host_header_probe.
+type HostHeaderProbeInput struct {
+	Raw            string `json:"raw" jsonschema:"required,Raw HTTP request, ideally one that renders absolute links or sends mail (password reset)"`
+	AttackerDomain string `json:"attackerDomain,omitempty" jsonschema:"Domain to inject (default: evil.example); use a collaborator domain to catch server-side lookups"`
+	Host           string `json:"host,omitempty" jsonschema:"Target host to connect to (overrides Host header)"`
+	Port           int    `json:"port,omitempty" jsonschema:"Target port (default based on TLS)"`
+	TLS            *bool  `json:"tls,omitempty" jsonschema:"Use HTTPS (default true)"`
+	HeaderProfile  string `json:"headerProfile,omitempty" jsonschema:"Header rule profile from config (default: 'default')"`
+
+	TLSConfig *config.TLSOptions `json:"tlsConfig,omitempty" jsonschema:"TLS client certificate, CA bundle, version, and cipher options"`
+}
+
+// HostHeaderCheck is the outcome of one Host manipulation.
+type HostHeaderCheck struct {
+	Name       string   `json:"name"`
+	Change     string   `json:"change"`
+	StatusCode int      `json:"statusCode,omitempty"`
+	BodySize   int      `json:"bodySize,omitempty"`
+	Reflected  []string `json:"reflected,omitempty"`
+	Evidence   string   `json:"evidence,omitempty"`
+	Error      string   `json:"error,omitempty"`
+}
+
+// HostHeaderProbeOutput is the output of burp_host_header_probe.
+type HostHeaderProbeOutput struct {
+	Baseline HostHeaderCheck   `json:"baseline"`
+	Checks   []HostHeaderCheck `json:"checks"`
+	Findings []string          `json:"findings"`
+}
+
+var (
+	// linkContext matches attribute and URL syntax just before a reflection.
+	linkContext = regexp.MustCompile(`(?i)(href|src|action|content|url)\s*=\s*["']?[^"'\s>]*$|url\(\s*["']?[^"')]*$|https?://[^"'\s<>]*$|//$`)
+	// resetContent marks pages and mails that carry account links.
+	resetContent = regexp.MustCompile(`(?i)reset|forgot|password|verify|confirm|activate|token`)
+)
+
+// hostReflections lists where canary appears in resp: response headers by
+// name, "link" for URLs in the body, "password-reset" for account mail style
+// content, and "body" otherwise. It also returns an excerpt of the first
+// reflection.
+func hostReflections(resp *burp.ParsedHTTPResponse, canary string) ([]string, string) {
+	var where []string
+	evidence := ""
+	for _, name := range slices.Sorted(maps.Keys(resp.Headers)) {
+		for _, v := range resp.Headers[name] {
+			if strings.Contains(strings.ToLower(v), strings.ToLower(canary)) {
+				where = append(where, "header "+name)
+				if evidence == "" {
+					evidence = name + ": " + v
+				}
+				break
+			}
+		}
+	}
+
+	body := resp.Body
+	idx := strings.Index(strings.ToLower(body), strings.ToLower(canary))
+	if idx < 0 {
+		return where, evidence
+	}
+	if evidence == "" {
+		start := max(0, idx-hostExcerptRadius)
+		evidence = body[start:min(len(body), idx+len(canary)+hostExcerptRadius)]
+	}
+	inLink := linkContext.MatchString(body[max(0, idx-hostExcerptRadius):idx])
+	switch {
+	case inLink && resetContent.MatchString(body):
+		where = append(where, "link", "password-reset")
+	case inLink:
+		where = append(where, "link")
+	default:
+		where = append(where, "body")
+	}
+	return where, evidence
+}
+
+func hostHeaderProbeHandler() func(context.Context, *mcp.CallToolRequest, HostHeaderProbeInput) (*mcp.CallToolResult, HostHeaderProbeOutput, error) {
+	return func(ctx context.Context, _ *mcp.CallToolRequest, input HostHeaderProbeInput) (*mcp.CallToolResult, HostHeaderProbeOutput, error) {
+		rawNorm, parsed, err := prepareRequest(input.Raw, input.HeaderProfile)
+		if err != nil {
+			return nil, HostHeaderProbeOutput{}, err
+		}
+		t, err := resolveTarget(input.Host, input.Port, input.TLS, parsed.Host)
+		if err != nil {
+			return nil, HostHeaderProbeOutput{}, err
+		}
+		attacker := strings.TrimSuffix(strings.TrimSpace(input.AttackerDomain), ".")
+		if attacker == "" {
+			attacker = defaultAttackerDomain
+		}
+		host := parsed.Host
+		if host == "" {
+			host = t.Host
+		}
+		scheme := "http"
+		if t.UseTLS {
+			scheme = "https"
+		}
+
+		opts := newDirectOptions(input.TLSConfig)
+		send := func(name, change, raw, canary string) HostHeaderCheck {
+			check := HostHeaderCheck{Name: name, Change: change}
+			text, err := sendDirect(ctx, t, []byte(raw), opts)
+			if err != nil {
+				check.Error = err.Error()
+				return check
+			}
+			resp := burp.ParseHTTPResponse(text, 0, 0)
+			if resp == nil || resp.StatusCode == 0 {
+				check.Error = "empty or unparseable response"
+				return check
+			}
+			check.StatusCode, check.BodySize = resp.StatusCode, resp.BodySize
+			check.Reflected, check.Evidence = hostReflections(resp, canary)
+			return check
+		}
+
+		// The baseline shows whether the real host is echoed at all.
+		baseline := send("baseline", "", rawNorm, host)
+		if baseline.Error != "" {
+			return nil, HostHeaderProbeOutput{}, fmt.Errorf("baseline request failed: %s", baseline.Error)
+		}
+
+		checks := make([]HostHeaderCheck, len(hostVariants))
+		parallel(len(hostVariants), func(i int) {
+			v := hostVariants[i]
+			change := strings.NewReplacer("{attacker}", attacker, "{host}", host, "{scheme}", scheme).Replace(v.change)
+			r, err := splitRawRequest(rawNorm)
+			if err != nil {
+				checks[i] = HostHeaderCheck{Name: v.name, Change: change, Error: err.Error()}
+				return
+			}
+			canary := v.apply(r, host, attacker, scheme)
+			checks[i] = send(v.name, change, r.String(), canary)
+		})
+
+		return nil, HostHeaderProbeOutput{
+			Baseline: baseline,
+			Checks:   checks,
+			Findings: hostHeaderFindings(baseline, checks),
+		}, nil
+	}
+}
+
+// hostHeaderFindings flags reflections by severity: password reset links
+// first, then redirects and links, then plain body echoes.
+func hostHeaderFindings(baseline HostHeaderCheck, checks []HostHeaderCheck) []string {
+	findings := []string{}
+	for _, c := range checks {
+		if c.Error != "" || len(c.Reflected) == 0 {
+			continue
+		}
+		where := strings.Join(c.Reflected, ", ")
+		switch {
+		case slices.Contains(c.Reflected, "password-reset"):
+			findings = append(findings, fmt.Sprintf("%s (%s): injected host in a link on an account page (%s): password reset poisoning candidate; trigger the mail and check the link", c.Name, c.Change, where))
+		case slices.Contains(c.Reflected, "header Location"):
+			findings = append(findings, fmt.Sprintf("%s (%s): injected host in the Location header: redirect to the attacker host, and cache poisoning if the response is cached", c.Name, c.Change))
+		case slices.Contains(c.Reflected, "link"):
+			findings = append(findings, fmt.Sprintf("%s (%s): injected host in generated links (%s): cache poisoning or link hijacking if the response is cached or mailed", c.Name, c.Change, where))
+		default:
+			findings = append(findings, fmt.Sprintf("%s (%s): injected host reflected (%s)", c.Name, c.Change, where))
+		}
+	}
+	for _, c := range checks {
+		if c.Error == "" && c.StatusCode != 0 && c.StatusCode != baseline.StatusCode && len(c.Reflected) == 0 && c.StatusCode < 400 {
+			findings = append(findings, fmt.Sprintf("%s (%s): status %d vs %d baseline without reflection: the header changes routing; try internal hostnames", c.Name, c.Change, c.StatusCode, baseline.StatusCode))
+		}
+	}
+	if len(findings) == 0 {
+		if len(baseline.Reflected) == 0 {
+			findings = append(findings, "The host is not echoed even in the baseline: probe a page that renders absolute URLs or sends mail")
+		} else {
+			findings = append(findings, "No injected host was reflected")
+		}
+	}
+	return findings
+}
+
+// RegisterHostHeaderProbeTool registers the burp_host_header_probe tool.
+func RegisterHostHeaderProbeTool(server *mcp.Server) {
+	addTool(server, &mcp.Tool{
+		Name: "burp_host_header_probe",
+		Description: `Replay a request with manipulated Host routing, sent directly for exact bytes: replaced Host, absolute-URI request line, duplicate and folded Host, X-Forwarded-Host and other override headers, port injection. ` +
+			`Flags responses that reflect the injected host in headers (Location), links, or password-reset style content. ` +
+			`Returns {baseline, checks: [{name, change, statusCode, bodySize, reflected, evidence}], findings}.`,
+	}, hostHeaderProbeHandler())
+}
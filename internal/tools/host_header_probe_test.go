@@ -0,0 +1,77 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/c0tton-fluff/burp-mcp-server/internal/burp"
+)
+
+func TestHostVariants(t *testing.T) {
+	raw := "GET /reset?x=1 HTTP/1.1\r\nHost: a.com\r\nAccept: */*\r\n\r\n"
+	want := map[string]string{
+		"host":                  "GET /reset?x=1 HTTP/1.1\r\nHost: evil.example\r\nAccept: */*\r\n\r\n",
+		"absolute-uri":          "GET https://a.com/reset?x=1 HTTP/1.1\r\nHost: evil.example\r\nAccept: */*\r\n\r\n",
+		"absolute-uri-attacker": "GET https://evil.example/reset?x=1 HTTP/1.1\r\nHost: a.com\r\nAccept: */*\r\n\r\n",
+		"duplicate-host":        "GET /reset?x=1 HTTP/1.1\r\nHost: a.com\r\nAccept: */*\r\nHost: evil.example\r\n\r\n",
+		"indented-host":         "GET /reset?x=1 HTTP/1.1\r\n Host: evil.example\r\nHost: a.com\r\nAccept: */*\r\n\r\n",
+		"x-forwarded-host":      "GET /reset?x=1 HTTP/1.1\r\nHost: a.com\r\nAccept: */*\r\nX-Forwarded-Host: evil.example\r\n\r\n",
+		"port-injection":        "GET /reset?x=1 HTTP/1.1\r\nHost: a.com:evil.example\r\nAccept: */*\r\n\r\n",
+	}
+	for _, v := range hostVariants {
+		r, err := splitRawRequest(raw)
+		if err != nil {
+			t.Fatal(err)
+		}
+		canary := v.apply(r, "a.com", "evil.example", "https")
+		if w, ok := want[v.name]; ok && r.String() != w {
+			t.Errorf("%s:\n got %q\nwant %q", v.name, r.String(), w)
+		}
+		if !strings.Contains(canary, "evil.example") {
+			t.Errorf("%s: canary %q", v.name, canary)
+		}
+	}
+}
+
+func TestHostReflections(t *testing.T) {
+	resp := burp.ParseHTTPResponse("HTTP/1.1 200 OK\r\nContent-Type: text/html\r\n\r\n<p>Click to reset your password: <a href=\"https://evil.example/reset?token=abc\">reset</a></p>", 0, 0)
+	where, evidence := hostReflections(resp, "evil.example")
+	if strings.Join(where, ",") != "link,password-reset" || !strings.Contains(evidence, `href="https://evil.example`) {
+		t.Errorf("where=%v evidence=%q", where, evidence)
+	}
+
+	resp = burp.ParseHTTPResponse("HTTP/1.1 302 Found\r\nLocation: https://EVIL.example/login\r\n\r\n", 0, 0)
+	where, evidence = hostReflections(resp, "evil.example")
+	if strings.Join(where, ",") != "header Location" || evidence != "Location: https://EVIL.example/login" {
+		t.Errorf("where=%v evidence=%q", where, evidence)
+	}
+
+	resp = burp.ParseHTTPResponse("HTTP/1.1 404 Not Found\r\n\r\nNo site configured for evil.example", 0, 0)
+	if where, _ := hostReflections(resp, "evil.example"); strings.Join(where, ",") != "body" {
+		t.Errorf("where=%v", where)
+	}
+}
+
+func TestHostHeaderFindings(t *testing.T) {
+	baseline := HostHeaderCheck{Name: "baseline", StatusCode: 200, Reflected: []string{"link"}}
+	checks := []HostHeaderCheck{
+		{Name: "host", Change: "Host: evil.example", StatusCode: 421},
+		{Name: "x-forwarded-host", Change: "X-Forwarded-Host: evil.example", StatusCode: 200, Reflected: []string{"link", "password-reset"}},
+		{Name: "absolute-uri", Change: "request line", StatusCode: 302, Reflected: []string{"header Location"}},
+		{Name: "x-host", Change: "X-Host: evil.example", StatusCode: 302},
+	}
+	got := strings.Join(hostHeaderFindings(baseline, checks), "\n")
+	for _, want := range []string{"x-forwarded-host (X-Forwarded-Host: evil.example): injected host in a link on an account page", "absolute-uri (request line): injected host in the Location header", "x-host (X-Host: evil.example): status 302 vs 200"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("missing %q in:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "host (Host: evil.example)") {
+		t.Errorf("rejected Host reported:\n%s", got)
+	}
+
+	none := hostHeaderFindings(HostHeaderCheck{StatusCode: 200}, []HostHeaderCheck{{Name: "host", StatusCode: 200}})
+	if len(none) != 1 || !strings.Contains(none[0], "not echoed even in the baseline") {
+		t.Errorf("got %v", none)
+	}
+}
@@ -0,0 +1,283 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/c0tton-fluff/burp-mcp-server/internal/burp"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// Which copy of a duplicated parameter the server used.
+const (
+	hppFirst        = "first"
+	hppLast         = "last"
+	hppConcatenated = "concatenated"
+	hppBoth         = "both"    // both values reflected, apart
+	hppNeither      = "neither" // a response unlike either control
+	hppUnknown      = "unknown" // controls alike and nothing reflected
+)
+
+// HPPProbeInput is the input for burp_hpp_probe.
+type HPPProbeInput struct {
+	Raw           string `json:"raw" jsonschema:"required,Raw HTTP request with the parameter to pollute"`
+	Param         string `json:"param" jsonschema:"required,Name of the parameter to duplicate"`
+	In            string `json:"in,omitempty" jsonschema:"Where the parameter lives: query or body (form-encoded). Default: wherever it already is, else query"`
+	First         string `json:"first,omitempty" jsonschema:"Value of the first copy (default: a random marker). Set first and second to two valid values, e.g. two account IDs, to tell them apart by behavior"`
+	Second        string `json:"second,omitempty" jsonschema:"Value of the second copy (default: a random marker)"`
+	Host          string `json:"host,omitempty" jsonschema:"Target host (overrides Host header)"`
+	Port          int    `json:"port,omitempty" jsonschema:"Target port (default based on TLS)"`
+	TLS           *bool  `json:"tls,omitempty" jsonschema:"Use HTTPS (default true)"`
+	HeaderProfile string `json:"headerProfile,omitempty" jsonschema:"Header rule profile from config (default: 'default')"`
+	Instance      string `json:"instance,omitempty" jsonschema:"Named Burp instance from config (default: the --burp-url connection)"`
+}
+
+// HPPControl is the response to the parameter sent once with one value.
+type HPPControl struct {
+	Value      string `json:"value"`
+	StatusCode int    `json:"statusCode,omitempty"`
+	BodySize   int    `json:"bodySize"`
+	Reflected  bool   `json:"reflected"`
+	Error      string `json:"error,omitempty"`
+}
+
+// HPPVariant is one way of sending the parameter twice and its outcome.
+type HPPVariant struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	StatusCode  int    `json:"statusCode,omitempty"`
+	BodySize    int    `json:"bodySize"`
+	Honored     string `json:"honored,omitempty"`
+	Evidence    string `json:"evidence,omitempty"`
+	Error       string `json:"error,omitempty"`
+}
+
+// HPPProbeOutput is the output of burp_hpp_probe.
+type HPPProbeOutput struct {
+	Endpoint string       `json:"endpoint"`
+	Param    string       `json:"param"`
+	In       string       `json:"in"`
+	Controls []HPPControl `json:"controls"`
+	Variants []HPPVariant `json:"variants"`
+	Findings []string     `json:"findings"`
+}
+
+// hppVariant is a request with param sent twice, first then second.
+type hppVariant struct {
+	name, description, raw string
+}
+
+// hppVariants builds the duplicated-parameter requests. first and second are
+// already query-encoded. The query+body variant needs a form body, so it's
+// only built when the request already has one.
+func hppVariants(rawNorm, param, in, first, second string) ([]hppVariant, error) {
+	r, err := splitRawRequest(rawNorm)
+	if err != nil {
+		return nil, err
+	}
+	encodedName := fmt.Sprintf("%%%02X", param[0]) + url.QueryEscape(param[1:])
+	name := url.QueryEscape(param)
+	// set puts pairs, verbatim, in place of the parameter's copies in the
+	// query or body of c.
+	set := func(c *rawRequest, where, pairs string) {
+		switch where {
+		case "query":
+			path, query, _ := strings.Cut(c.target, "?")
+			if query = setQuery(query, nil, []string{param}); query != "" {
+				query += "&"
+			}
+			c.target = path + "?" + query + pairs
+		case "body":
+			if c.body = setQuery(c.body, nil, []string{param}); c.body != "" {
+				c.body += "&"
+			}
+			c.body += pairs
+		}
+	}
+	variant := func(name, description string, pairs ...string) hppVariant {
+		c := *r
+		for i := 0; i < len(pairs); i += 2 {
+			set(&c, pairs[i], pairs[i+1])
+		}
+		return hppVariant{name, description, fixContentLength(c.String())}
+	}
+
+	variants := []hppVariant{
+		variant("duplicate", fmt.Sprintf("%s=first&%s=second in the %s", param, param, in),
+			in, name+"="+first+"&"+name+"="+second),
+		variant("encoded-name", fmt.Sprintf("%s=first&%s=second, the second name percent-encoded", param, encodedName),
+			in, name+"="+first+"&"+encodedName+"="+second),
+		variant("semicolon", fmt.Sprintf("%s=first;%s=second, split on ';' by some frameworks", param, param),
+			in, name+"="+first+";"+name+"="+second),
+		variant("encoded-separator", fmt.Sprintf("%s=first%%26%s%%3Dsecond, one value hiding a second copy", param, param),
+			in, name+"="+first+url.QueryEscape("&"+param+"="+second)),
+	}
+	contentType := burp.GetHeader(burp.ParseRawRequest(rawNorm).Headers, "Content-Type")
+	if strings.HasPrefix(strings.ToLower(contentType), "application/x-www-form-urlencoded") {
+		variants = append(variants, variant("query-and-body", fmt.Sprintf("%s=first in the query, %s=second in the body", param, param),
+			"query", name+"="+first, "body", name+"="+second))
+	}
+	return variants, nil
+}
+
+// hppResponse is a response with both test values masked, so responses that
+// only echo different values compare equal.
+type hppResponse struct {
+	status int
+	body   string
+}
+
+func maskHPP(resp *burp.ParsedHTTPResponse, first, second string) hppResponse {
+	body := strings.ReplaceAll(resp.Body, first, "\x00")
+	body = strings.ReplaceAll(body, second, "\x00")
+	return hppResponse{resp.StatusCode, body}
+}
+
+// hppHonored decides which value the server used: by reflection when it
+// echoes the values, else by comparing the response with the two controls.
+func hppHonored(resp *burp.ParsedHTTPResponse, first, second string, controls [2]*hppResponse) (string, string) {
+	hasFirst, hasSecond := strings.Contains(resp.Body, first), strings.Contains(resp.Body, second)
+	for _, sep := range []string{",", ", ", "", " ", ";", "|"} {
+		if joined := first + sep + second; strings.Contains(resp.Body, joined) {
+			return hppConcatenated, fmt.Sprintf("body reflects %q", joined)
+		}
+	}
+	switch {
+	case hasFirst && hasSecond:
+		return hppBoth, "body reflects both values separately"
+	case hasFirst:
+		return hppFirst, fmt.Sprintf("body reflects %q only", first)
+	case hasSecond:
+		return hppLast, fmt.Sprintf("body reflects %q only", second)
+	}
+	if controls[0] == nil || controls[1] == nil {
+		return hppUnknown, "nothing reflected and a control request failed"
+	}
+	if *controls[0] == *controls[1] {
+		return hppUnknown, "nothing reflected and both values give the same response"
+	}
+	got := maskHPP(resp, first, second)
+	switch got {
+	case *controls[0]:
+		return hppFirst, "response matches the first value's control"
+	case *controls[1]:
+		return hppLast, "response matches the second value's control"
+	}
+	return hppNeither, fmt.Sprintf("status %d and %d-byte body match neither control", resp.StatusCode, resp.BodySize)
+}
+
+// hppFindings summarizes the variants. Servers that read different copies
+// depending on how they are sent are what pollution attacks exploit: a
+// filter checking the first copy while the application uses the last.
+func hppFindings(param string, variants []HPPVariant) []string {
+	findings := []string{}
+	seen := map[string]bool{}
+	for _, v := range variants {
+		// The encoded separator normally stays one value: an echo of it
+		// reflects both markers, and only the second alone shows the hidden
+		// copy was parsed.
+		if v.Name == "encoded-separator" {
+			if v.Honored == hppLast {
+				findings = append(findings, fmt.Sprintf("%s: an encoded & in %s's value became a second parameter (%s): the value is decoded twice, so a copy can be smuggled past a front end that sees one value.", v.Name, param, v.Evidence))
+			}
+			continue
+		}
+		seen[v.Honored] = true
+		switch {
+		case v.Name == "encoded-name" && v.Honored == hppLast:
+			findings = append(findings, fmt.Sprintf("%s: the percent-encoded name overrode %s (%s): filters matching the literal name miss it.", v.Name, param, v.Evidence))
+		case v.Honored == hppConcatenated:
+			findings = append(findings, fmt.Sprintf("%s: the server joins duplicate %s values (%s): split payloads across copies to evade per-value filters.", v.Name, param, v.Evidence))
+		case v.Honored == hppNeither:
+			findings = append(findings, fmt.Sprintf("%s: the duplicated %s changed the response (%s).", v.Name, param, v.Evidence))
+		}
+	}
+	if seen[hppFirst] && seen[hppLast] {
+		findings = append(findings, fmt.Sprintf("The server uses the first copy of %s in some variants and the last in others: components parsing the request differently can disagree on its value.", param))
+	}
+	return findings
+}
+
+func hppProbeHandler(client *burp.Client) func(context.Context, *mcp.CallToolRequest, HPPProbeInput) (*mcp.CallToolResult, HPPProbeOutput, error) {
+	return func(ctx context.Context, _ *mcp.CallToolRequest, input HPPProbeInput) (*mcp.CallToolResult, HPPProbeOutput, error) {
+		ctx = burp.WithInstance(ctx, input.Instance)
+
+		if input.Param == "" {
+			return nil, HPPProbeOutput{}, fmt.Errorf("param is required")
+		}
+		rawNorm, parsed, err := prepareRequest(input.Raw, input.HeaderProfile)
+		if err != nil {
+			return nil, HPPProbeOutput{}, err
+		}
+		t, err := resolveTarget(input.Host, input.Port, input.TLS, parsed.Host)
+		if err != nil {
+			return nil, HPPProbeOutput{}, err
+		}
+		first, second := input.First, input.Second
+		if first == "" || second == "" {
+			token := cacheToken()
+			first, second = "hppa"+token, "hppb"+token
+		}
+		if first == second {
+			return nil, HPPProbeOutput{}, fmt.Errorf("first and second must differ")
+		}
+		_, in, err := injectParam(rawNorm, input.Param, "", input.In)
+		if err != nil {
+			return nil, HPPProbeOutput{}, err
+		}
+		variants, err := hppVariants(rawNorm, input.Param, in, url.QueryEscape(first), url.QueryEscape(second))
+		if err != nil {
+			return nil, HPPProbeOutput{}, err
+		}
+
+		out := HPPProbeOutput{
+			Endpoint: requestEndpoint(parsed),
+			Param:    input.Param,
+			In:       in,
+			Controls: make([]HPPControl, 2),
+			Variants: make([]HPPVariant, len(variants)),
+		}
+		var controls [2]*hppResponse
+		parallel(2, func(i int) {
+			value := []string{first, second}[i]
+			out.Controls[i] = HPPControl{Value: value}
+			raw, _, err := injectParam(rawNorm, input.Param, url.QueryEscape(value), in)
+			if err == nil {
+				var resp *burp.ParsedHTTPResponse
+				if resp, err = sendParsed(ctx, client, raw, t, 0); err == nil {
+					out.Controls[i].StatusCode, out.Controls[i].BodySize = resp.StatusCode, resp.BodySize
+					out.Controls[i].Reflected = strings.Contains(resp.Body, value)
+					masked := maskHPP(resp, first, second)
+					controls[i] = &masked
+					return
+				}
+			}
+			out.Controls[i].Error = err.Error()
+		})
+		parallel(len(variants), func(i int) {
+			v := variants[i]
+			out.Variants[i] = HPPVariant{Name: v.name, Description: v.description}
+			resp, err := sendParsed(ctx, client, v.raw, t, 0)
+			if err != nil {
+				out.Variants[i].Error = err.Error()
+				return
+			}
+			out.Variants[i].StatusCode, out.Variants[i].BodySize = resp.StatusCode, resp.BodySize
+			out.Variants[i].Honored, out.Variants[i].Evidence = hppHonored(resp, first, second, controls)
+		})
+		out.Findings = hppFindings(input.Param, out.Variants)
+		return nil, out, nil
+	}
+}
+
+// RegisterHPPProbeTool registers the burp_hpp_probe tool.
+func RegisterHPPProbeTool(server *mcp.Server, client *burp.Client) {
+	addTool(server, &mcp.Tool{
+		Name: "burp_hpp_probe",
+		Description: `Probe HTTP parameter pollution on one endpoint: send a parameter twice with two values (first, second) as a plain duplicate, with a percent-encoded name, split by ';', hidden behind an encoded '&', and across query and form body, ` +
+			`then report per variant which copy the server used (first, last, concatenated, both), by reflection or by comparing against single-value control requests. ` +
+			`Returns {endpoint, param, in, controls: [{value, statusCode, bodySize, reflected}], variants: [{name, description, statusCode, bodySize, honored, evidence}], findings}.`,
+	}, hppProbeHandler(client))
+}
@@ -0,0 +1,86 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/c0tton-fluff/burp-mcp-server/internal/burp"
+)
+
+func TestHPPVariants(t *testing.T) {
+	raw := "GET /search?q=x&page=2 HTTP/1.1\r\nHost: a.com\r\n\r\n"
+	variants, err := hppVariants(raw, "q", "query", "A", "B")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{
+		"duplicate":         "GET /search?page=2&q=A&q=B ",
+		"encoded-name":      "GET /search?page=2&q=A&%71=B ",
+		"semicolon":         "GET /search?page=2&q=A;q=B ",
+		"encoded-separator": "GET /search?page=2&q=A%26q%3DB ",
+	}
+	if len(variants) != len(want) {
+		t.Fatalf("got %d variants, want %d (no form body, so no query-and-body)", len(variants), len(want))
+	}
+	for _, v := range variants {
+		if !strings.HasPrefix(v.raw, want[v.name]) {
+			t.Errorf("%s: got %q", v.name, v.raw)
+		}
+	}
+
+	form := "POST /transfer HTTP/1.1\r\nHost: a.com\r\nContent-Type: application/x-www-form-urlencoded\r\nContent-Length: 15\r\n\r\nto=bob&amount=1"
+	variants, err = hppVariants(form, "to", "body", "A", "B")
+	if err != nil {
+		t.Fatal(err)
+	}
+	last := variants[len(variants)-1]
+	if last.name != "query-and-body" || !strings.HasPrefix(last.raw, "POST /transfer?to=A ") || !strings.HasSuffix(last.raw, "\r\n\r\namount=1&to=B") {
+		t.Errorf("query-and-body: %q", last.raw)
+	}
+	if !strings.HasSuffix(variants[0].raw, "\r\n\r\namount=1&to=A&to=B") || !strings.Contains(variants[0].raw, "Content-Length: 18\r\n") {
+		t.Errorf("body duplicate: %q", variants[0].raw)
+	}
+}
+
+func TestHPPHonored(t *testing.T) {
+	resp := func(status int, body string) *burp.ParsedHTTPResponse {
+		return &burp.ParsedHTTPResponse{StatusCode: status, Body: body, BodySize: len(body)}
+	}
+	for body, want := range map[string]string{
+		"Results for hppa1,hppb1": hppConcatenated,
+		"Results for hppb1":       hppLast,
+		"Results for hppa1":       hppFirst,
+		"hppa1 ... hppb1":         hppBoth,
+	} {
+		if got, _ := hppHonored(resp(200, body), "hppa1", "hppb1", [2]*hppResponse{}); got != want {
+			t.Errorf("%q: got %s, want %s", body, got, want)
+		}
+	}
+
+	alice, bob := maskHPP(resp(200, "balance 10"), "alice", "bob"), maskHPP(resp(200, "balance 99"), "alice", "bob")
+	controls := [2]*hppResponse{&alice, &bob}
+	if got, _ := hppHonored(resp(200, "balance 99"), "alice", "bob", controls); got != hppLast {
+		t.Errorf("behavior: got %s", got)
+	}
+	if got, _ := hppHonored(resp(400, "bad request"), "alice", "bob", controls); got != hppNeither {
+		t.Errorf("error response: got %s", got)
+	}
+	if got, _ := hppHonored(resp(200, "ok"), "alice", "bob", [2]*hppResponse{&alice, &alice}); got != hppUnknown {
+		t.Errorf("same controls: got %s", got)
+	}
+}
+
+func TestHPPFindings(t *testing.T) {
+	got := hppFindings("q", []HPPVariant{
+		{Name: "duplicate", Honored: hppFirst},
+		{Name: "semicolon", Honored: hppLast},
+		{Name: "encoded-separator", Honored: hppBoth},
+	})
+	if len(got) != 1 || !strings.Contains(got[0], "first copy of q in some variants") {
+		t.Errorf("got %q", got)
+	}
+	got = hppFindings("q", []HPPVariant{{Name: "encoded-separator", Honored: hppLast, Evidence: "x"}})
+	if len(got) != 1 || !strings.HasPrefix(got[0], "encoded-separator:") {
+		t.Errorf("got %q", got)
+	}
+}
@@ -0,0 +1,158 @@
+package tools
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/c0tton-fluff/burp-mcp-server/internal/burp"
+	"github.com/c0tton-fluff/burp-mcp-server/internal/config"
+	"github.com/c0tton-fluff/burp-mcp-server/internal/httpauth"
+)
+
+// directAuth returns the auth for a direct request to next: the config
+// entry for its host, with the per-call options layered on top for the
+// original target only. nil means send unauthenticated.
+func directAuth(next, original resolvedTarget, perCall *config.AuthOptions) (*config.AuthOptions, error) {
+	var auth config.AuthOptions
+	if a := settings.AuthFor(next.Host); a != nil {
+		auth = *a
+	}
+	if next == original {
+		auth = auth.Merge(perCall)
+	}
+	if auth == (config.AuthOptions{}) {
+		return nil, nil
+	}
+	if err := auth.Validate(); err != nil {
+		return nil, fmt.Errorf("auth: %w", err)
+	}
+	return &auth, nil
+}
+
+// sendDirectAuth sends rawNorm directly and authenticates it. Basic goes out
+// preemptively. Digest answers the 401 challenge with a second request.
+// NTLM and Negotiate run the three-leg handshake on one kept-alive
+// connection, since the server binds the login to it.
+func sendDirectAuth(ctx context.Context, t resolvedTarget, rawNorm string, opts directOptions, auth config.AuthOptions) (string, error) {
+	user, password := auth.Username, auth.Secret()
+	switch scheme := strings.ToLower(auth.Type); scheme {
+	case "basic":
+		return sendDirect(ctx, t, []byte(withAuthorization(rawNorm, httpauth.Basic(user, password), false)), opts)
+	case "digest":
+		return sendDigest(ctx, t, rawNorm, opts, user, password)
+	case "ntlm", "negotiate":
+		cred := httpauth.NTLMCredentials{User: user, Password: password, Domain: auth.Domain, Workstation: auth.Workstation}
+		return sendNTLM(ctx, t, rawNorm, opts, scheme, cred)
+	default:
+		return "", fmt.Errorf("unsupported auth type %q", auth.Type)
+	}
+}
+
+func sendDigest(ctx context.Context, t resolvedTarget, rawNorm string, opts directOptions, user, password string) (string, error) {
+	resp, err := sendDirect(ctx, t, []byte(rawNorm), opts)
+	if err != nil {
+		return "", err
+	}
+	challenge, ok, err := authChallenge(resp, "digest")
+	if err != nil || !ok {
+		return resp, err
+	}
+
+	head, body, _ := strings.Cut(rawNorm, "\r\n\r\n")
+	requestLine, _, _ := strings.Cut(head, "\r\n")
+	fields := strings.Fields(requestLine)
+	if len(fields) < 2 {
+		return "", fmt.Errorf("digest auth: malformed request line %q", requestLine)
+	}
+	cnonce := make([]byte, 8)
+	rand.Read(cnonce)
+	value, err := httpauth.Digest(challenge, user, password, httpauth.DigestRequest{
+		Method: fields[0], URI: fields[1], Body: body,
+		CNonce: hex.EncodeToString(cnonce), NC: 1,
+	})
+	if err != nil {
+		return "", fmt.Errorf("digest auth: %w", err)
+	}
+	return sendDirect(ctx, t, []byte(withAuthorization(rawNorm, value, false)), opts)
+}
+
+func sendNTLM(ctx context.Context, t resolvedTarget, rawNorm string, opts directOptions, scheme string, cred httpauth.NTLMCredentials) (string, error) {
+	c, err := openDirect(ctx, t, opts)
+	if err != nil {
+		return "", err
+	}
+	defer c.Close()
+
+	label := "NTLM"
+	if scheme == "negotiate" {
+		label = "Negotiate"
+	}
+	negotiate := label + " " + base64.StdEncoding.EncodeToString(httpauth.NTLMNegotiate())
+	resp, err := c.roundTrip([]byte(withAuthorization(rawNorm, negotiate, true)))
+	if err != nil {
+		return "", err
+	}
+	challenge, ok, err := authChallenge(resp, scheme)
+	if err != nil || !ok {
+		return resp, err
+	}
+	token, err := base64.StdEncoding.DecodeString(challenge.Token)
+	if err != nil || len(token) == 0 {
+		return "", fmt.Errorf("%s auth: server sent no challenge token", scheme)
+	}
+	msg, err := httpauth.ParseNTLMChallenge(token)
+	if err != nil {
+		// Negotiate without NTLMSSP is Kerberos, which isn't supported.
+		return "", fmt.Errorf("%s auth: %w", scheme, err)
+	}
+	parsed := burp.ParseHTTPResponse(resp, 0, 0)
+	if strings.EqualFold(burp.GetHeader(parsed.Headers, "Connection"), "close") {
+		return "", fmt.Errorf("%s auth: server closed the connection after its challenge", scheme)
+	}
+
+	var clientChallenge [8]byte
+	rand.Read(clientChallenge[:])
+	authenticate := httpauth.NTLMAuthenticate(msg, cred, time.Now(), clientChallenge)
+	return c.roundTrip([]byte(withAuthorization(rawNorm, label+" "+base64.StdEncoding.EncodeToString(authenticate), false)))
+}
+
+// authChallenge returns the scheme's challenge from a 401 response. ok is
+// false when the response isn't a 401, which the caller returns as is.
+func authChallenge(resp, scheme string) (httpauth.Challenge, bool, error) {
+	parsed := burp.ParseHTTPResponse(resp, 0, 0)
+	if parsed == nil || parsed.StatusCode != http.StatusUnauthorized {
+		return httpauth.Challenge{}, false, nil
+	}
+	var values []string
+	for k, v := range parsed.Headers {
+		if strings.EqualFold(k, "WWW-Authenticate") {
+			values = append(values, v...)
+		}
+	}
+	challenges := httpauth.ParseChallenges(values)
+	c, ok := httpauth.Find(challenges, scheme)
+	if !ok {
+		offered := make([]string, len(challenges))
+		for i, c := range challenges {
+			offered[i] = c.Scheme
+		}
+		return httpauth.Challenge{}, false, fmt.Errorf("%s auth: the 401 carries no %s challenge (offered: %q)", scheme, scheme, strings.Join(offered, ", "))
+	}
+	return c, true, nil
+}
+
+// withAuthorization sets the Authorization header, and Connection:
+// keep-alive when the next leg must reuse the connection.
+func withAuthorization(rawNorm, value string, keepAlive bool) string {
+	set := map[string]string{"Authorization": value}
+	if keepAlive {
+		set["Connection"] = "keep-alive"
+	}
+	return applyHeaderRules(rawNorm, config.HeaderRules{Set: set})
+}
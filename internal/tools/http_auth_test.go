@@ -0,0 +1,146 @@
+package tools
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/c0tton-fluff/burp-mcp-server/internal/config"
+	"github.com/c0tton-fluff/burp-mcp-server/internal/httpauth"
+)
+
+const authRequest = "GET /secret HTTP/1.1\r\nHost: intranet\r\n\r\n"
+
+func TestSendDirectAuth_Basic(t *testing.T) {
+	target := startTestTarget(t, func(w http.ResponseWriter, r *http.Request) {
+		if user, pass, ok := r.BasicAuth(); !ok || user != "alice" || pass != "s3cret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte("welcome"))
+	})
+	t.Setenv("TEST_AUTH_PASSWORD", "s3cret")
+	auth := config.AuthOptions{Type: "basic", Username: "alice", PasswordEnv: "TEST_AUTH_PASSWORD"}
+	resp, err := sendDirectAuth(context.Background(), target, authRequest, directOptions{}, auth)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(resp, "HTTP/1.1 200") {
+		t.Errorf("response = %q", resp)
+	}
+}
+
+func TestSendDirectAuth_Digest(t *testing.T) {
+	challenge := `Digest realm="intranet", nonce="n0nce", qop="auth", opaque="op"`
+	target := startTestTarget(t, func(w http.ResponseWriter, r *http.Request) {
+		got := r.Header.Get("Authorization")
+		if got == "" {
+			w.Header().Set("WWW-Authenticate", challenge)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		// Recompute the expected response with the client's cnonce.
+		sent := httpauth.ParseChallenges([]string{got})[0].Params
+		want, _ := httpauth.Digest(httpauth.ParseChallenges([]string{challenge})[0], "alice", "s3cret",
+			httpauth.DigestRequest{Method: r.Method, URI: r.RequestURI, CNonce: sent["cnonce"], NC: 1})
+		if got != want {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		w.Write([]byte("welcome"))
+	})
+	auth := config.AuthOptions{Type: "digest", Username: "alice", Password: "s3cret"}
+	resp, err := sendDirectAuth(context.Background(), target, authRequest, directOptions{}, auth)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(resp, "HTTP/1.1 200") {
+		t.Errorf("response = %q", resp)
+	}
+}
+
+func TestSendDirectAuth_NTLM(t *testing.T) {
+	// The challenge and the authenticate message must share a connection.
+	var challengedOn string
+	target := startTestTarget(t, func(w http.ResponseWriter, r *http.Request) {
+		scheme, token, _ := strings.Cut(r.Header.Get("Authorization"), " ")
+		msg, _ := base64.StdEncoding.DecodeString(token)
+		if scheme != "Negotiate" || len(msg) < 12 {
+			w.Header().Set("WWW-Authenticate", "Negotiate")
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch binary.LittleEndian.Uint32(msg[8:]) {
+		case 1:
+			challengedOn = r.RemoteAddr
+			w.Header().Set("WWW-Authenticate", "Negotiate "+base64.StdEncoding.EncodeToString(testNTLMChallenge()))
+			w.WriteHeader(http.StatusUnauthorized)
+		case 3:
+			if r.RemoteAddr != challengedOn {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			w.Write([]byte("welcome"))
+		}
+	})
+	auth := config.AuthOptions{Type: "negotiate", Username: `CORP\alice`, Password: "s3cret"}
+	resp, err := sendDirectAuth(context.Background(), target, authRequest, directOptions{}, auth)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(resp, "HTTP/1.1 200") {
+		t.Errorf("response = %q", resp)
+	}
+}
+
+// testNTLMChallenge is a minimal type 2 message without target info.
+func testNTLMChallenge() []byte {
+	msg := make([]byte, 48)
+	copy(msg, "NTLMSSP\x00")
+	binary.LittleEndian.PutUint32(msg[8:], 2)
+	binary.LittleEndian.PutUint32(msg[16:], 48)
+	binary.LittleEndian.PutUint32(msg[20:], 0x00088201)
+	copy(msg[24:], "challeng")
+	binary.LittleEndian.PutUint32(msg[44:], 48)
+	return msg
+}
+
+func TestSendDirectAuth_WrongScheme(t *testing.T) {
+	target := startTestTarget(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("WWW-Authenticate", `Basic realm="x"`)
+		w.WriteHeader(http.StatusUnauthorized)
+	})
+	auth := config.AuthOptions{Type: "ntlm", Username: "alice"}
+	_, err := sendDirectAuth(context.Background(), target, authRequest, directOptions{}, auth)
+	if err == nil || !strings.Contains(err.Error(), `offered: "basic"`) {
+		t.Errorf("err = %v", err)
+	}
+}
+
+func TestDirectAuth(t *testing.T) {
+	Configure(&config.Config{Auth: map[string]config.AuthOptions{
+		"*.corp": {Type: "ntlm", Username: `CORP\svc`, Password: "pw"},
+	}})
+	t.Cleanup(func() { Configure(nil) })
+
+	original := resolvedTarget{Host: "app.corp", Port: 443, UseTLS: true}
+	a, err := directAuth(original, original, &config.AuthOptions{Username: `CORP\alice`, Password: "other"})
+	if err != nil || a == nil || a.Type != "ntlm" || a.Username != `CORP\alice` {
+		t.Errorf("merged auth = %+v, %v", a, err)
+	}
+
+	// A redirect hop gets its host's config entry, not the per-call options.
+	hop := resolvedTarget{Host: "sso.corp", Port: 443, UseTLS: true}
+	if a, _ := directAuth(hop, original, &config.AuthOptions{Username: "alice"}); a == nil || a.Username != `CORP\svc` {
+		t.Errorf("hop auth = %+v", a)
+	}
+	if a, _ := directAuth(resolvedTarget{Host: "example.com"}, original, nil); a != nil {
+		t.Errorf("unconfigured host auth = %+v", a)
+	}
+	if _, err := directAuth(resolvedTarget{Host: "example.com"}, resolvedTarget{Host: "example.com"}, &config.AuthOptions{Type: "kerberos", Username: "a"}); err == nil {
+		t.Error("expected error for unsupported type")
+	}
+}
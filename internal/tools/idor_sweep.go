@@ -0,0 +1,236 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	"github.com/c0tton-fluff/burp-mcp-server/internal/burp"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const (
+	markerID               = "{{id}}"
+	maxIDORIDs             = 1000
+	defaultIDORLengthDelta = 5
+)
+
+// IDORRange is a numeric identifier range.
+type IDORRange struct {
+	From int64 `json:"from"`
+	To   int64 `json:"to"`
+	Step int64 `json:"step,omitempty" jsonschema:"Increment (default 1)"`
+	Pad  int   `json:"pad,omitempty" jsonschema:"Zero-pad identifiers to this width, e.g. 6 for 000042"`
+}
+
+// IDORSweepInput is the input for burp_idor_sweep.
+type IDORSweepInput struct {
+	Raw           string          `json:"raw" jsonschema:"required,Request template with an {{id}} marker where the identifier goes"`
+	IDs           []string        `json:"ids,omitempty" jsonschema:"Identifiers to try (UUIDs, usernames, numbers)"`
+	Range         *IDORRange      `json:"range,omitempty" jsonschema:"Numeric range to try, after ids"`
+	NotFound      *CredentialRule `json:"notFound,omitempty" jsonschema:"Rule the 'not found' response matches (status, regex, length); responses that don't match are hits"`
+	BaselineID    string          `json:"baselineId,omitempty" jsonschema:"Identifier known not to exist; its response is the 'not found' signature when notFound isn't given"`
+	LengthPercent int             `json:"lengthPercent,omitempty" jsonschema:"With baselineId: body length difference from the baseline still counted as 'not found' (default 5)"`
+	Host          string          `json:"host,omitempty" jsonschema:"Target host (overrides Host header)"`
+	Port          int             `json:"port,omitempty" jsonschema:"Target port (default based on TLS)"`
+	TLS           *bool           `json:"tls,omitempty" jsonschema:"Use HTTPS (default true)"`
+	HeaderProfile string          `json:"headerProfile,omitempty" jsonschema:"Header rule profile from config (default: 'default')"`
+	AuthProfile   string          `json:"authProfile,omitempty" jsonschema:"Auth profile from config whose credentials are injected at send time"`
+	Instance      string          `json:"instance,omitempty" jsonschema:"Named Burp instance from config (default: the --burp-url connection)"`
+}
+
+// IDORBaseline is the response to baselineId.
+type IDORBaseline struct {
+	ID         string `json:"id"`
+	StatusCode int    `json:"statusCode"`
+	BodySize   int    `json:"bodySize"`
+}
+
+// IDORHit is an identifier whose response differed from "not found".
+type IDORHit struct {
+	ID         string `json:"id"`
+	StatusCode int    `json:"statusCode"`
+	BodySize   int    `json:"bodySize"`
+	Reason     string `json:"reason"`
+}
+
+// IDORSweepOutput is the output of burp_idor_sweep.
+type IDORSweepOutput struct {
+	Baseline *IDORBaseline `json:"baseline,omitempty"`
+	Hits     []IDORHit     `json:"hits"`
+	Sent     int           `json:"sent"`
+	Errors   []string      `json:"errors,omitempty"`
+	Summary  string        `json:"summary"`
+}
+
+// idorIDs lists the identifiers to try: ids first, then the range.
+func idorIDs(input IDORSweepInput) ([]string, error) {
+	ids := append([]string(nil), input.IDs...)
+	if r := input.Range; r != nil {
+		step := r.Step
+		if step == 0 {
+			step = 1
+		}
+		if step < 0 || r.To < r.From {
+			return nil, fmt.Errorf("range: want from <= to and a positive step")
+		}
+		if n := (r.To-r.From)/step + 1; n > maxIDORIDs-int64(len(ids)) {
+			return nil, fmt.Errorf("too many identifiers: %d, max %d", n+int64(len(ids)), maxIDORIDs)
+		}
+		for v := r.From; v <= r.To; v += step {
+			id := strconv.FormatInt(v, 10)
+			if pad := r.Pad - len(id); pad > 0 && v >= 0 {
+				id = strings.Repeat("0", pad) + id
+			}
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 {
+		return nil, fmt.Errorf("ids or range is required")
+	}
+	if len(ids) > maxIDORIDs {
+		return nil, fmt.Errorf("too many identifiers: %d, max %d", len(ids), maxIDORIDs)
+	}
+	return ids, nil
+}
+
+// fillID substitutes id for every marker in the template.
+func fillID(rawNorm, id string) string {
+	return fixContentLength(strings.ReplaceAll(rawNorm, markerID, id))
+}
+
+// idorDiff explains how the response to id differs from the baseline, or
+// returns "" when it looks the same. Echoes of the identifier in the body
+// are measured as if they were the baseline's, so a "no user 12345" page
+// doesn't read as a hit against "no user 0".
+func idorDiff(base IDORBaseline, resp *burp.ParsedHTTPResponse, id string, percent int) string {
+	if resp.StatusCode != base.StatusCode {
+		return fmt.Sprintf("status %d, baseline %d", resp.StatusCode, base.StatusCode)
+	}
+	size := resp.BodySize + strings.Count(resp.Body, id)*(len(base.ID)-len(id))
+	diff := size - base.BodySize
+	if diff < 0 {
+		diff = -diff
+	}
+	if diff*100 > percent*max(base.BodySize, 1) {
+		return fmt.Sprintf("body %d bytes, baseline %d", resp.BodySize, base.BodySize)
+	}
+	return ""
+}
+
+func idorSweepHandler(client *burp.Client) func(context.Context, *mcp.CallToolRequest, IDORSweepInput) (*mcp.CallToolResult, IDORSweepOutput, error) {
+	return func(ctx context.Context, _ *mcp.CallToolRequest, input IDORSweepInput) (*mcp.CallToolResult, IDORSweepOutput, error) {
+		ctx = burp.WithInstance(ctx, input.Instance)
+
+		if !strings.Contains(input.Raw, markerID) {
+			return nil, IDORSweepOutput{}, fmt.Errorf("raw has no %s marker", markerID)
+		}
+		if (input.NotFound == nil) == (input.BaselineID == "") {
+			return nil, IDORSweepOutput{}, fmt.Errorf("one of notFound or baselineId is required")
+		}
+		notFound, err := compileRule(input.NotFound, "notFound")
+		if err != nil {
+			return nil, IDORSweepOutput{}, err
+		}
+		ids, err := idorIDs(input)
+		if err != nil {
+			return nil, IDORSweepOutput{}, err
+		}
+		percent := input.LengthPercent
+		if percent <= 0 {
+			percent = defaultIDORLengthDelta
+		}
+
+		rawNorm, parsed, err := prepareRequest(input.Raw, input.HeaderProfile)
+		if err != nil {
+			return nil, IDORSweepOutput{}, err
+		}
+		t, err := resolveTarget(input.Host, input.Port, input.TLS, parsed.Host)
+		if err != nil {
+			return nil, IDORSweepOutput{}, err
+		}
+		send := func(raw string, next resolvedTarget) (string, error) {
+			return sendWithFallback(ctx, client, raw, burp.ParseRawRequest(raw), next)
+		}
+		if rawNorm, err = applyAuthProfile(rawNorm, input.AuthProfile, send); err != nil {
+			return nil, IDORSweepOutput{}, err
+		}
+		summary := fmt.Sprintf("%d identifiers against %s:%d", len(ids), t.Host, t.Port)
+		if err := requireApproval(ctx, "burp_idor_sweep", t, summary, rawNorm); err != nil {
+			return nil, IDORSweepOutput{}, err
+		}
+
+		out := IDORSweepOutput{Hits: []IDORHit{}}
+		if input.BaselineID != "" {
+			resp, err := sendParsed(ctx, client, fillID(rawNorm, input.BaselineID), t, 0)
+			if err != nil {
+				return nil, IDORSweepOutput{}, fmt.Errorf("baseline: %w", err)
+			}
+			out.Baseline = &IDORBaseline{ID: input.BaselineID, StatusCode: resp.StatusCode, BodySize: resp.BodySize}
+		}
+
+		hits := make([]*IDORHit, len(ids))
+		statuses := make([]int, len(ids))
+		errs := make([]string, len(ids))
+		var done atomic.Int64
+		parallel(len(ids), func(i int) {
+			defer func() { reportProgress(ctx, int(done.Add(1)), len(ids), ids[i]) }()
+			req := fillID(rawNorm, ids[i])
+			text, err := send(req, t)
+			if err != nil {
+				errs[i] = fmt.Sprintf("%s: %v", ids[i], err)
+				return
+			}
+			resp := burp.ParseHTTPResponse(text, 0, 0)
+			if resp == nil {
+				errs[i] = fmt.Sprintf("%s: failed to parse response", ids[i])
+				return
+			}
+			statuses[i] = resp.StatusCode
+			var reason string
+			if notFound != nil {
+				if !notFound.match(resp, text) {
+					reason = "does not match notFound"
+				}
+			} else {
+				reason = idorDiff(*out.Baseline, resp, ids[i], percent)
+			}
+			if reason != "" {
+				hits[i] = &IDORHit{ID: ids[i], StatusCode: resp.StatusCode, BodySize: resp.BodySize, Reason: reason}
+			}
+		})
+
+		counts := map[int]int{}
+		for i := range ids {
+			if errs[i] != "" {
+				out.Errors = append(out.Errors, errs[i])
+				continue
+			}
+			out.Sent++
+			counts[statuses[i]]++
+			if hits[i] != nil {
+				out.Hits = append(out.Hits, *hits[i])
+			}
+		}
+		var parts []string
+		for _, code := range slices.Sorted(maps.Keys(counts)) {
+			parts = append(parts, fmt.Sprintf("%dx %d", counts[code], code))
+		}
+		out.Summary = fmt.Sprintf("%d of %d identifiers sent, %d differed from not found, responses: %s", out.Sent, len(ids), len(out.Hits), strings.Join(parts, ", "))
+		return nil, out, nil
+	}
+}
+
+// RegisterIDORSweepTool registers the burp_idor_sweep tool.
+func RegisterIDORSweepTool(server *mcp.Server, client *burp.Client) {
+	addTool(server, &mcp.Tool{
+		Name: "burp_idor_sweep",
+		Description: `Enumerate object identifiers: substitute each of ids and/or a numeric range (max 1000) into the {{id}} marker of a request template and send them in parallel. ` +
+			`The "not found" signature is a notFound rule (status, regex, length) or the response to baselineId (same status and body length within lengthPercent, with echoed identifiers discounted). authProfile sends as a given identity. ` +
+			`Returns {baseline, hits: [{id, statusCode, bodySize, reason}], sent, errors, summary}.`,
+	}, idorSweepHandler(client))
+}
@@ -0,0 +1,53 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/c0tton-fluff/burp-mcp-server/internal/burp"
+)
+
+func TestIDORIDs(t *testing.T) {
+	ids, err := idorIDs(IDORSweepInput{IDs: []string{"a1b2"}, Range: &IDORRange{From: 8, To: 12, Step: 2, Pad: 3}})
+	if err != nil || strings.Join(ids, ",") != "a1b2,008,010,012" {
+		t.Errorf("ids = %v, %v", ids, err)
+	}
+
+	for _, in := range []IDORSweepInput{
+		{},
+		{Range: &IDORRange{From: 5, To: 1}},
+		{Range: &IDORRange{From: 1, To: 5, Step: -1}},
+		{Range: &IDORRange{From: 1, To: maxIDORIDs + 1}},
+		{IDs: []string{"x"}, Range: &IDORRange{From: 1, To: maxIDORIDs}},
+	} {
+		if _, err := idorIDs(in); err == nil {
+			t.Errorf("expected error for %+v", in)
+		}
+	}
+}
+
+func TestFillID(t *testing.T) {
+	raw := "POST /api/users/{{id}} HTTP/1.1\r\nHost: x\r\nContent-Length: 0\r\n\r\n{\"id\":{{id}}}"
+	got := fillID(raw, "1234")
+	if !strings.HasPrefix(got, "POST /api/users/1234 HTTP/1.1\r\n") || !strings.HasSuffix(got, `{"id":1234}`) || !strings.Contains(got, "Content-Length: 11\r\n") {
+		t.Errorf("got %q", got)
+	}
+}
+
+func TestIDORDiff(t *testing.T) {
+	base := IDORBaseline{ID: "0", StatusCode: 404, BodySize: len(`{"error":"no user 0"}`)}
+	tests := []struct {
+		resp, id string
+		hit      bool
+	}{
+		{"HTTP/1.1 404 Not Found\r\n\r\n{\"error\":\"no user 123456\"}", "123456", false},
+		{"HTTP/1.1 200 OK\r\n\r\n{\"error\":\"no user 7\"}", "7", true},
+		{"HTTP/1.1 404 Not Found\r\n\r\n{\"error\":\"no user 7\",\"hint\":\"deleted account\"}", "7", true},
+	}
+	for _, tt := range tests {
+		resp := burp.ParseHTTPResponse(tt.resp, 0, 0)
+		if got := idorDiff(base, resp, tt.id, defaultIDORLengthDelta); (got != "") != tt.hit {
+			t.Errorf("idorDiff(%q) = %q, want hit %v", tt.resp, got, tt.hit)
+		}
+	}
+}
@@ -0,0 +1,278 @@
+package tools
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/c0tton-fluff/burp-mcp-server/internal/burp"
+	"github.com/c0tton-fluff/burp-mcp-server/internal/config"
+	"github.com/c0tton-fluff/burp-mcp-server/internal/openapi"
+	"github.com/c0tton-fluff/burp-mcp-server/internal/store"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const (
+	defaultOpenAPILimit = 100
+	maxOpenAPILimit     = 500
+)
+
+// ImportOpenAPIInput is the input for burp_import_openapi.
+type ImportOpenAPIInput struct {
+	URL        string            `json:"url,omitempty" jsonschema:"URL of the spec (JSON or YAML), fetched directly; use this or spec"`
+	Spec       string            `json:"spec,omitempty" jsonschema:"The spec document itself, JSON or YAML"`
+	BaseURL    string            `json:"baseUrl,omitempty" jsonschema:"API base URL for the requests (default: the spec's first server, resolved against url)"`
+	Headers    map[string]string `json:"headers,omitempty" jsonschema:"Headers set on every generated request, e.g. a real Authorization replacing the placeholder; also sent when fetching url"`
+	Tag        string            `json:"tag,omitempty" jsonschema:"Only operations with this tag"`
+	PathPrefix string            `json:"pathPrefix,omitempty" jsonschema:"Only paths starting with this prefix"`
+	Limit      int               `json:"limit,omitempty" jsonschema:"Maximum endpoints returned (default 100, max 500)"`
+
+	HeaderProfile string             `json:"headerProfile,omitempty" jsonschema:"Header rule profile from config for fetching url (default: 'default')"`
+	TLSConfig     *config.TLSOptions `json:"tlsConfig,omitempty" jsonschema:"TLS client certificate, CA bundle, version, and cipher options for fetching url"`
+}
+
+// OpenAPIEndpoint is one operation with a request ready to send.
+type OpenAPIEndpoint struct {
+	openapi.Endpoint
+	Raw string `json:"raw"`
+}
+
+// ImportOpenAPIOutput is the output of burp_import_openapi.
+type ImportOpenAPIOutput struct {
+	Title           string            `json:"title,omitempty"`
+	Version         string            `json:"version,omitempty"`
+	Format          string            `json:"format"`
+	BaseURL         string            `json:"baseUrl"`
+	SecuritySchemes map[string]string `json:"securitySchemes,omitempty"`
+	Endpoints       []OpenAPIEndpoint `json:"endpoints"`
+	Total           int               `json:"total"`
+	Truncated       bool              `json:"truncated,omitempty"`
+}
+
+// openAPIBase picks the URL requests are built against: baseURL, else the
+// spec's first server, else the origin the spec came from. Relative server
+// URLs resolve against specURL.
+func openAPIBase(spec *openapi.Spec, baseURL, specURL string) (*url.URL, error) {
+	ref := baseURL
+	if ref == "" && len(spec.Servers) > 0 {
+		ref = spec.Servers[0]
+	}
+	if ref == "" {
+		ref = "/"
+	}
+	u, err := url.Parse(ref)
+	if err != nil {
+		return nil, fmt.Errorf("base URL %q: %w", ref, err)
+	}
+	if !u.IsAbs() {
+		if specURL == "" {
+			return nil, fmt.Errorf("the spec's server %q is relative and there is no url to resolve it against; pass baseUrl", ref)
+		}
+		from, err := url.Parse(specURL)
+		if err != nil {
+			return nil, err
+		}
+		u = from.ResolveReference(u)
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("base URL %q must be an absolute http or https URL", u)
+	}
+	return u, nil
+}
+
+// describeScheme renders a security scheme for the inventory.
+func describeScheme(s openapi.SecurityScheme) string {
+	switch {
+	case s.Type == "apiKey":
+		return fmt.Sprintf("apiKey in %s %q", s.In, s.Name)
+	case s.Type == "http" && s.Scheme != "":
+		return "http " + s.Scheme
+	}
+	return s.Type
+}
+
+// openAPIRequest builds the raw request for e against base, filling every
+// parameter with its example and the first security scheme with a
+// placeholder credential.
+func openAPIRequest(base *url.URL, e openapi.Endpoint, schemes map[string]openapi.SecurityScheme) (string, error) {
+	path := strings.TrimSuffix(base.Path, "/") + e.Path
+	var query, cookies []string
+	headers := []string{"Host: " + base.Host}
+	for _, p := range e.Params {
+		values := []string{openapi.String(p.Example)}
+		if l, ok := p.Example.([]any); ok && p.In == "query" {
+			values = values[:0]
+			for _, v := range l {
+				values = append(values, openapi.String(v))
+			}
+		}
+		switch p.In {
+		case "path":
+			path = strings.ReplaceAll(path, "{"+p.Name+"}", url.PathEscape(cmp.Or(values[0], "1")))
+		case "query":
+			for _, v := range values {
+				query = append(query, url.QueryEscape(p.Name)+"="+url.QueryEscape(v))
+			}
+		case "header":
+			headers = append(headers, p.Name+": "+values[0])
+		case "cookie":
+			cookies = append(cookies, p.Name+"="+values[0])
+		}
+	}
+
+	if len(e.Security) > 0 {
+		s := schemes[e.Security[0]]
+		switch {
+		case s.Type == "apiKey" && s.In == "header":
+			headers = append(headers, s.Name+": <api-key>")
+		case s.Type == "apiKey" && s.In == "query":
+			query = append(query, url.QueryEscape(s.Name)+"=%3Capi-key%3E")
+		case s.Type == "apiKey" && s.In == "cookie":
+			cookies = append(cookies, s.Name+"=<api-key>")
+		case s.Type == "basic" || (s.Type == "http" && s.Scheme == "basic"):
+			headers = append(headers, "Authorization: Basic <base64 user:pass>")
+		case s.Type == "http" || s.Type == "oauth2" || s.Type == "openIdConnect":
+			headers = append(headers, "Authorization: Bearer <token>")
+		}
+	}
+	if len(cookies) > 0 {
+		headers = append(headers, "Cookie: "+strings.Join(cookies, "; "))
+	}
+
+	body, contentType, err := openAPIBody(e)
+	if err != nil {
+		return "", err
+	}
+	if contentType != "" {
+		headers = append(headers, "Content-Type: "+contentType)
+	}
+	target := path
+	if len(query) > 0 {
+		target += "?" + strings.Join(query, "&")
+	}
+	raw := e.Method + " " + target + " HTTP/1.1\r\n" + strings.Join(headers, "\r\n") + "\r\n\r\n" + body
+	return fixContentLength(raw), nil
+}
+
+// openAPIBody renders e's example body for its content type, returning the
+// Content-Type to send (multipart gains its boundary).
+func openAPIBody(e openapi.Endpoint) (string, string, error) {
+	if e.Body == nil {
+		if e.ContentType == "" {
+			return "", "", nil
+		}
+		return "", e.ContentType, nil
+	}
+	fields, isObject := e.Body.(map[string]any)
+	switch {
+	case strings.Contains(e.ContentType, "json"):
+		b, err := json.Marshal(e.Body)
+		return string(b), e.ContentType, err
+	case e.ContentType == "application/x-www-form-urlencoded" && isObject:
+		var pairs []string
+		for _, k := range slices.Sorted(maps.Keys(fields)) {
+			pairs = append(pairs, url.QueryEscape(k)+"="+url.QueryEscape(openapi.String(fields[k])))
+		}
+		return strings.Join(pairs, "&"), e.ContentType, nil
+	case e.ContentType == "multipart/form-data" && isObject:
+		var parts []burp.MultipartPart
+		for _, k := range slices.Sorted(maps.Keys(fields)) {
+			parts = append(parts, burp.MultipartPart{Name: k, Data: openapi.String(fields[k])})
+		}
+		return burp.BuildMultipart(parts)
+	}
+	return openapi.String(e.Body), e.ContentType, nil
+}
+
+func importOpenAPIHandler(st *store.Store) func(context.Context, *mcp.CallToolRequest, ImportOpenAPIInput) (*mcp.CallToolResult, ImportOpenAPIOutput, error) {
+	return func(ctx context.Context, _ *mcp.CallToolRequest, input ImportOpenAPIInput) (*mcp.CallToolResult, ImportOpenAPIOutput, error) {
+		doc := input.Spec
+		switch {
+		case input.URL != "" && doc != "":
+			return nil, ImportOpenAPIOutput{}, fmt.Errorf("use url or spec, not both")
+		case input.URL != "":
+			u, err := url.Parse(input.URL)
+			if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+				return nil, ImportOpenAPIOutput{}, fmt.Errorf("url must be an absolute http or https URL")
+			}
+			resp, err := directGet(ctx, u, input.Headers, input.HeaderProfile, newDirectOptions(input.TLSConfig))
+			if err != nil {
+				return nil, ImportOpenAPIOutput{}, fmt.Errorf("fetching spec: %w", err)
+			}
+			if resp.StatusCode != 200 {
+				return nil, ImportOpenAPIOutput{}, fmt.Errorf("fetching spec: status %d", resp.StatusCode)
+			}
+			doc = resp.Body
+		case doc == "":
+			return nil, ImportOpenAPIOutput{}, fmt.Errorf("url or spec is required")
+		}
+		limit := input.Limit
+		if limit <= 0 {
+			limit = defaultOpenAPILimit
+		}
+		if limit > maxOpenAPILimit {
+			return nil, ImportOpenAPIOutput{}, fmt.Errorf("limit must be 1-%d", maxOpenAPILimit)
+		}
+
+		spec, err := openapi.Parse(doc)
+		if err != nil {
+			return nil, ImportOpenAPIOutput{}, err
+		}
+		base, err := openAPIBase(spec, input.BaseURL, input.URL)
+		if err != nil {
+			return nil, ImportOpenAPIOutput{}, err
+		}
+		schemes := spec.SecuritySchemes()
+
+		out := ImportOpenAPIOutput{Title: spec.Title, Version: spec.Version, Format: spec.Format, BaseURL: base.String(), Endpoints: []OpenAPIEndpoint{}}
+		if len(schemes) > 0 {
+			out.SecuritySchemes = make(map[string]string, len(schemes))
+			for name, s := range schemes {
+				out.SecuritySchemes[name] = describeScheme(s)
+			}
+		}
+		origin, _, _ := urlEndpoint(base.String())
+		var inventory []store.Endpoint
+		for _, e := range spec.Endpoints {
+			if (input.Tag != "" && !slices.Contains(e.Tags, input.Tag)) || !strings.HasPrefix(e.Path, input.PathPrefix) {
+				continue
+			}
+			out.Total++
+			inventory = append(inventory, store.Endpoint{
+				Method:  e.Method,
+				Origin:  origin,
+				Path:    strings.TrimSuffix(base.Path, "/") + e.Path,
+				Sources: []string{store.EndpointFromOpenAPI},
+			})
+			if len(out.Endpoints) >= limit {
+				out.Truncated = true
+				continue
+			}
+			raw, err := openAPIRequest(base, e, schemes)
+			if err != nil {
+				return nil, ImportOpenAPIOutput{}, fmt.Errorf("%s %s: %w", e.Method, e.Path, err)
+			}
+			if len(input.Headers) > 0 {
+				raw = fixContentLength(applyHeaderRules(raw, config.HeaderRules{Set: input.Headers}))
+			}
+			out.Endpoints = append(out.Endpoints, OpenAPIEndpoint{Endpoint: e, Raw: raw})
+		}
+		addInventory(st, inventory)
+		return nil, out, nil
+	}
+}
+
+// RegisterImportOpenAPITool registers the burp_import_openapi tool.
+func RegisterImportOpenAPITool(server *mcp.Server, st *store.Store) {
+	addTool(server, &mcp.Tool{
+		Name: "burp_import_openapi",
+		Description: `Import an OpenAPI 3.x or Swagger 2.0 spec (JSON or YAML), fetched directly from url or given as spec, into an endpoint inventory with a ready-to-send raw request per operation. ` +
+			`Path, query, header, and cookie parameters and request bodies are filled from the spec's examples, defaults, and enums, or synthesized from schemas ($refs resolved); the first security scheme gets a placeholder credential unless headers set a real one. ` +
+			`Filter with tag or pathPrefix. Returns {title, version, format, baseUrl, securitySchemes, endpoints: [{method, path, operationId, summary, tags, parameters: [{name, in, required, type, example}], contentType, security, raw}], total, truncated}.`,
+	}, importOpenAPIHandler(st))
+}
@@ -0,0 +1,95 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+const testOpenAPISpec = `openapi: 3.1.0
+info: {title: Shop, version: "1"}
+servers: [{url: /v1}]
+components:
+  securitySchemes:
+    key: {type: apiKey, in: query, name: api_key}
+paths:
+  /orders/{id}:
+    get:
+      tags: [orders]
+      security: [{key: []}]
+      parameters:
+        - {name: id, in: path, schema: {type: string, format: uuid}}
+        - {name: expand, in: query, schema: {type: boolean}}
+        - {name: session, in: cookie, schema: {type: string}}
+  /orders:
+    post:
+      tags: [orders]
+      requestBody:
+        content:
+          application/x-www-form-urlencoded:
+            schema:
+              properties:
+                sku: {type: string, example: A 1}
+                qty: {type: integer}
+  /health:
+    get: {}
+`
+
+func TestImportOpenAPI(t *testing.T) {
+	target := startTestTarget(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/spec.yaml" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprint(w, testOpenAPISpec)
+	})
+	specURL := fmt.Sprintf("http://%s:%d/spec.yaml", target.Host, target.Port)
+
+	_, out, err := importOpenAPIHandler(nil)(context.Background(), nil, ImportOpenAPIInput{URL: specURL, Tag: "orders"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	host := fmt.Sprintf("%s:%d", target.Host, target.Port)
+	if out.Title != "Shop" || out.BaseURL != "http://"+host+"/v1" || out.Total != 2 || len(out.Endpoints) != 2 {
+		t.Fatalf("out = %+v", out)
+	}
+	if out.SecuritySchemes["key"] != `apiKey in query "api_key"` {
+		t.Errorf("securitySchemes = %v", out.SecuritySchemes)
+	}
+
+	get := out.Endpoints[1].Raw
+	want := "GET /v1/orders/00000000-0000-4000-8000-000000000000?expand=true&api_key=%3Capi-key%3E HTTP/1.1\r\nHost: " + host + "\r\nCookie: session=string\r\n\r\n"
+	if get != want {
+		t.Errorf("get raw = %q\nwant      %q", get, want)
+	}
+	post := out.Endpoints[0].Raw
+	if !strings.HasPrefix(post, "POST /v1/orders HTTP/1.1\r\n") || !strings.Contains(post, "Content-Type: application/x-www-form-urlencoded\r\n") || !strings.HasSuffix(post, "\r\n\r\nqty=1&sku=A+1") {
+		t.Errorf("post raw = %q", post)
+	}
+
+	_, out, err = importOpenAPIHandler(nil)(context.Background(), nil, ImportOpenAPIInput{
+		Spec:    testOpenAPISpec,
+		BaseURL: "https://api.example.com/",
+		Headers: map[string]string{"Authorization": "Bearer real"},
+		Limit:   1,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out.Total != 3 || !out.Truncated || len(out.Endpoints) != 1 || !strings.HasPrefix(out.Endpoints[0].Raw, "GET /health HTTP/1.1\r\nHost: api.example.com\r\n") || !strings.Contains(out.Endpoints[0].Raw, "Authorization: Bearer real") {
+		t.Errorf("out = %+v", out)
+	}
+
+	for _, in := range []ImportOpenAPIInput{
+		{},
+		{URL: specURL, Spec: testOpenAPISpec},
+		{Spec: testOpenAPISpec},
+		{URL: fmt.Sprintf("http://%s/missing.yaml", host)},
+	} {
+		if _, _, err := importOpenAPIHandler(nil)(context.Background(), nil, in); err == nil {
+			t.Errorf("%+v: expected error", in)
+		}
+	}
+}
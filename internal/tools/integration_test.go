@@ -6,6 +6,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"slices"
 	"strings"
 	"testing"
 	"time"
@@ -186,7 +187,7 @@ func TestIntegration_RaceRequest(t *testing.T) {
 	rawNorm := normalizeRawRequest(rawReq)
 	rawNorm = fixContentLength(rawNorm)
 
-	results, err := executeRace(ctx, testTarget, 443, true, []byte(rawNorm), count, bodyLimit)
+	results, err := executeRace(ctx, testTarget, 443, true, slices.Repeat([][]byte{[]byte(rawNorm)}, count), nil, bodyLimit, nil, directOptions{})
 	if err != nil {
 		t.Fatalf("executeRace: %v", err)
 	}
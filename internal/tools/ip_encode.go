@@ -0,0 +1,348 @@
+package tools
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// defaultRebindWith is the public address paired with the target in
+// rebinding hostnames; it only has to pass the allowlist check.
+const defaultRebindWith = "1.1.1.1"
+
+// ipRange is a named network.
+type ipRange struct {
+	net  *net.IPNet
+	name string
+}
+
+// internalRanges are the networks an SSRF filter should refuse.
+var internalRanges = func() []ipRange {
+	var ranges []ipRange
+	for _, r := range [][2]string{
+		{"0.0.0.0/8", "this network"},
+		{"10.0.0.0/8", "private"},
+		{"100.64.0.0/10", "carrier-grade NAT"},
+		{"127.0.0.0/8", "loopback"},
+		{"169.254.0.0/16", "link-local (cloud metadata)"},
+		{"172.16.0.0/12", "private"},
+		{"192.168.0.0/16", "private"},
+		{"198.18.0.0/15", "benchmarking"},
+		{"::/128", "unspecified"},
+		{"::1/128", "loopback"},
+		{"fc00::/7", "unique local"},
+		{"fe80::/10", "link-local"},
+	} {
+		_, n, _ := net.ParseCIDR(r[0])
+		ranges = append(ranges, ipRange{n, r[1]})
+	}
+	return ranges
+}()
+
+// internalRange names the internal network ip falls in, or "".
+func internalRange(ip net.IP) string {
+	if v4 := ip.To4(); v4 != nil {
+		ip = v4
+	}
+	for _, r := range internalRanges {
+		if r.net.Contains(ip) {
+			return r.name
+		}
+	}
+	return ""
+}
+
+// enclosedDigits are the circled forms of 0-9 that NFKC folds back to ASCII.
+var enclosedDigits = []rune("⓪①②③④⑤⑥⑦⑧⑨")
+
+// foldHost undoes the Unicode tricks ipEncodings produces: circled digits
+// and ideographic full stops, which IDNA normalization maps to ASCII.
+func foldHost(host string) string {
+	return strings.Map(func(r rune) rune {
+		for i, d := range enclosedDigits {
+			if r == d {
+				return '0' + rune(i)
+			}
+		}
+		if r == '。' || r == '．' || r == '｡' {
+			return '.'
+		}
+		return r
+	}, host)
+}
+
+// parseInetAton parses an IPv4 address the permissive way C's inet_aton
+// does: 1 to 4 parts, each decimal, octal (leading 0), or hex (0x), with
+// the last part filling the remaining bytes. This is how most HTTP clients
+// read "127.1" or "0x7f000001", which is why the forms bypass filters.
+func parseInetAton(s string) net.IP {
+	parts := strings.Split(s, ".")
+	if len(parts) > 4 {
+		return nil
+	}
+	vals := make([]uint64, len(parts))
+	for i, p := range parts {
+		base := 10
+		switch {
+		case strings.HasPrefix(strings.ToLower(p), "0x"):
+			base, p = 16, p[2:]
+		case len(p) > 1 && p[0] == '0':
+			base, p = 8, p[1:]
+		}
+		if p == "" && base != 8 {
+			return nil
+		}
+		if p == "" {
+			p = "0"
+		}
+		v, err := strconv.ParseUint(p, base, 32)
+		if err != nil {
+			return nil
+		}
+		vals[i] = v
+	}
+	var n uint64
+	for i, v := range vals[:len(vals)-1] {
+		if v > 0xff {
+			return nil
+		}
+		n |= v << (24 - 8*i)
+	}
+	last := vals[len(vals)-1]
+	if last >= 1<<(8*(5-len(vals))) {
+		return nil
+	}
+	n |= last
+	ip := make(net.IP, 4)
+	binary.BigEndian.PutUint32(ip, uint32(n))
+	return ip
+}
+
+// IPEncoding is one way of writing the address.
+type IPEncoding struct {
+	Format string `json:"format"`
+	Value  string `json:"value"`
+}
+
+// ipEncodings lists the SSRF filter bypass forms of an IPv4 address.
+func ipEncodings(ip net.IP) []IPEncoding {
+	v4 := ip.To4()
+	if v4 == nil {
+		return []IPEncoding{
+			{"ipv6", "[" + ip.String() + "]"},
+			{"ipv6-expanded", "[" + expandIPv6(ip) + "]"},
+		}
+	}
+	n := binary.BigEndian.Uint32(v4)
+	a, b, c, d := v4[0], v4[1], v4[2], v4[3]
+	circled := func(s string) string {
+		return strings.Map(func(r rune) rune {
+			if r >= '0' && r <= '9' {
+				return enclosedDigits[r-'0']
+			}
+			return r
+		}, s)
+	}
+	dotted := v4.String()
+	encs := []IPEncoding{
+		{"dotted-decimal", dotted},
+		{"decimal", strconv.FormatUint(uint64(n), 10)},
+		{"hex", fmt.Sprintf("0x%08x", n)},
+		{"octal", fmt.Sprintf("0%o", n)},
+		{"dotted-hex", fmt.Sprintf("0x%02x.0x%02x.0x%02x.0x%02x", a, b, c, d)},
+		{"dotted-octal", fmt.Sprintf("0%o.0%o.0%o.0%o", a, b, c, d)},
+		{"mixed", fmt.Sprintf("0x%02x.%d.0%o.%d", a, b, c, d)},
+		{"short-3-part", fmt.Sprintf("%d.%d.%d", a, b, uint16(c)<<8|uint16(d))},
+		{"short-2-part", fmt.Sprintf("%d.%d", a, n&0xffffff)},
+		{"padded", fmt.Sprintf("%03d.%03d.%03d.%03d", a, b, c, d)},
+		{"ipv6-mapped", "[::ffff:" + dotted + "]"},
+		{"ipv6-mapped-hex", fmt.Sprintf("[::ffff:%x:%x]", uint16(a)<<8|uint16(b), uint16(c)<<8|uint16(d))},
+		{"ipv6-expanded-mapped", "[0:0:0:0:0:ffff:" + dotted + "]"},
+		{"enclosed-alphanumeric", circled(dotted)},
+		{"ideographic-dots", strings.ReplaceAll(dotted, ".", "。")},
+		{"url-encoded", urlEncodeAll(dotted)},
+	}
+	if a == 127 {
+		encs = append(encs, IPEncoding{"localhost", "localhost"})
+	}
+	if n == 0x7f000001 {
+		encs = append(encs, IPEncoding{"unspecified", "0.0.0.0"}, IPEncoding{"zero", "0"})
+	}
+	return encs
+}
+
+func expandIPv6(ip net.IP) string {
+	ip = ip.To16()
+	groups := make([]string, 8)
+	for i := range groups {
+		groups[i] = fmt.Sprintf("%04x", binary.BigEndian.Uint16(ip[2*i:]))
+	}
+	return strings.Join(groups, ":")
+}
+
+func urlEncodeAll(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		fmt.Fprintf(&b, "%%%02X", s[i])
+	}
+	return b.String()
+}
+
+// rebindHosts are wildcard-DNS names that resolve to ip, some alternating
+// with public so a check-then-fetch filter sees the public address first.
+func rebindHosts(ip, public net.IP) []string {
+	v4, p4 := ip.To4(), public.To4()
+	if v4 == nil {
+		return []string{}
+	}
+	dashed := strings.ReplaceAll(v4.String(), ".", "-")
+	hosts := []string{
+		v4.String() + ".nip.io",
+		dashed + ".sslip.io",
+	}
+	if p4 != nil {
+		hosts = append(hosts,
+			fmt.Sprintf("%08x.%08x.rbndr.us", binary.BigEndian.Uint32(p4), binary.BigEndian.Uint32(v4)),
+			fmt.Sprintf("make-%s-rebind-%s-rr.1u.ms", p4, v4),
+		)
+	}
+	return hosts
+}
+
+// IPEncodeInput is the input for burp_ip_encode.
+type IPEncodeInput struct {
+	Target     string   `json:"target,omitempty" jsonschema:"IP address or hostname to encode (hostnames are resolved; default: none)"`
+	RebindWith string   `json:"rebindWith,omitempty" jsonschema:"Public IPv4 paired with the target in rebinding hostnames (default 1.1.1.1)"`
+	Check      []string `json:"check,omitempty" jsonschema:"Candidate URLs or hosts to check for resolving into internal ranges"`
+}
+
+// HostCheck reports where a candidate host really points.
+type HostCheck struct {
+	Input     string   `json:"input"`
+	Host      string   `json:"host"`
+	Addresses []string `json:"addresses,omitempty"`
+	Internal  bool     `json:"internal"`
+	// Ranges names the internal network of each internal address.
+	Ranges []string `json:"ranges,omitempty"`
+	Error  string   `json:"error,omitempty"`
+}
+
+// IPEncodeOutput is the output of burp_ip_encode.
+type IPEncodeOutput struct {
+	IP          string       `json:"ip,omitempty"`
+	Range       string       `json:"range,omitempty"`
+	Encodings   []IPEncoding `json:"encodings,omitempty"`
+	RebindHosts []string     `json:"rebindHosts,omitempty"`
+	Checks      []HostCheck  `json:"checks,omitempty"`
+}
+
+// checkHost resolves a candidate URL or host the way a lenient HTTP client
+// would and reports whether any address is internal.
+func checkHost(ctx context.Context, input string) HostCheck {
+	hc := HostCheck{Input: input}
+	// Split by hand: url.Parse rejects the percent-encoded hosts filters miss.
+	host := input
+	if _, rest, ok := strings.Cut(host, "://"); ok {
+		host = rest
+	}
+	if i := strings.IndexAny(host, "/?#\\"); i >= 0 {
+		host = host[:i]
+	}
+	if i := strings.LastIndex(host, "@"); i >= 0 {
+		host = host[i+1:]
+	}
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	if h, err := url.PathUnescape(host); err == nil {
+		host = h
+	}
+	host = strings.TrimSuffix(strings.Trim(foldHost(host), "[]"), ".")
+	hc.Host = host
+
+	var ips []net.IP
+	if ip := net.ParseIP(host); ip != nil {
+		ips = []net.IP{ip}
+	} else if ip := parseInetAton(host); ip != nil {
+		ips = []net.IP{ip}
+	} else {
+		addrs, err := lookupIPAddr(ctx, host)
+		if err != nil {
+			hc.Error = err.Error()
+			return hc
+		}
+		for _, a := range addrs {
+			ips = append(ips, a.IP)
+		}
+	}
+	for _, ip := range ips {
+		hc.Addresses = append(hc.Addresses, ip.String())
+		if r := internalRange(ip); r != "" {
+			hc.Internal = true
+			hc.Ranges = append(hc.Ranges, ip.String()+": "+r)
+		}
+	}
+	return hc
+}
+
+func ipEncodeHandler() func(context.Context, *mcp.CallToolRequest, IPEncodeInput) (*mcp.CallToolResult, IPEncodeOutput, error) {
+	return func(ctx context.Context, _ *mcp.CallToolRequest, input IPEncodeInput) (*mcp.CallToolResult, IPEncodeOutput, error) {
+		target := strings.Trim(strings.TrimSpace(input.Target), "[]")
+		if target == "" && len(input.Check) == 0 {
+			return nil, IPEncodeOutput{}, fmt.Errorf("target or check is required")
+		}
+		public := net.ParseIP(defaultRebindWith)
+		if input.RebindWith != "" {
+			if public = net.ParseIP(input.RebindWith); public == nil || public.To4() == nil {
+				return nil, IPEncodeOutput{}, fmt.Errorf("rebindWith must be an IPv4 address")
+			}
+		}
+
+		var out IPEncodeOutput
+		if target != "" {
+			ip := net.ParseIP(target)
+			if ip == nil {
+				ip = parseInetAton(target)
+			}
+			if ip == nil {
+				addrs, err := lookupIPAddr(ctx, target)
+				if err != nil {
+					return nil, IPEncodeOutput{}, fmt.Errorf("resolve %s: %w", target, err)
+				}
+				// Prefer IPv4: it has far more alternate spellings.
+				for _, a := range addrs {
+					if ip == nil || (ip.To4() == nil && a.IP.To4() != nil) {
+						ip = a.IP
+					}
+				}
+				if ip == nil {
+					return nil, IPEncodeOutput{}, fmt.Errorf("resolve %s: no addresses", target)
+				}
+			}
+			out.IP = ip.String()
+			out.Range = internalRange(ip)
+			out.Encodings = ipEncodings(ip)
+			out.RebindHosts = rebindHosts(ip, public)
+		}
+		for _, c := range input.Check {
+			out.Checks = append(out.Checks, checkHost(ctx, strings.TrimSpace(c)))
+		}
+		return nil, out, nil
+	}
+}
+
+// RegisterIPEncodeTool registers the burp_ip_encode tool.
+func RegisterIPEncodeTool(server *mcp.Server) {
+	addTool(server, &mcp.Tool{
+		Name: "burp_ip_encode",
+		Description: `Generate SSRF filter bypass spellings of an IP or hostname: decimal, hex, octal, dotted and short forms, IPv6-mapped, enclosed alphanumerics, and wildcard-DNS and rebinding hostnames (nip.io, sslip.io, rbndr.us, 1u.ms). ` +
+			`Also checks candidate URLs or hosts, parsing numeric forms as inet_aton does and resolving names, and reports whether they land in loopback, private, link-local/metadata, or other internal ranges. ` +
+			`Returns {ip, range, encodings: [{format, value}], rebindHosts, checks: [{input, host, addresses, internal, ranges}]}.`,
+	}, ipEncodeHandler())
+}
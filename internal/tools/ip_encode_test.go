@@ -0,0 +1,120 @@
+package tools
+
+import (
+	"context"
+	"net"
+	"testing"
+)
+
+func TestParseInetAton(t *testing.T) {
+	for in, want := range map[string]string{
+		"127.0.0.1":           "127.0.0.1",
+		"2130706433":          "127.0.0.1",
+		"0x7f000001":          "127.0.0.1",
+		"017700000001":        "127.0.0.1",
+		"0x7f.0x0.0x0.0x1":    "127.0.0.1",
+		"0177.00.00.01":       "127.0.0.1",
+		"127.1":               "127.0.0.1",
+		"127.0.1":             "127.0.0.1",
+		"169.254.43518":       "169.254.169.254",
+		"0":                   "0.0.0.0",
+		"256.0.0.1":           "",
+		"127.0.0.256":         "",
+		"1.2.3.4.5":           "",
+		"08.0.0.1":            "",
+		"example.com":         "",
+		"0x":                  "",
+		"4294967296":          "",
+		"0xa9.0376.0xa9.0376": "169.254.169.254",
+	} {
+		got := ""
+		if ip := parseInetAton(in); ip != nil {
+			got = ip.String()
+		}
+		if got != want {
+			t.Errorf("parseInetAton(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestIPEncodings(t *testing.T) {
+	// Every numeric form must parse back to the address.
+	ip := net.ParseIP("169.254.169.254")
+	got := map[string]string{}
+	for _, e := range ipEncodings(ip) {
+		got[e.Format] = e.Value
+		switch e.Format {
+		case "ipv6-mapped", "ipv6-mapped-hex", "ipv6-expanded-mapped":
+			if p := net.ParseIP(e.Value[1 : len(e.Value)-1]); !p.Equal(ip) {
+				t.Errorf("%s %q parses to %v", e.Format, e.Value, p)
+			}
+		case "url-encoded":
+		default:
+			if p := parseInetAton(foldHost(e.Value)); !p.Equal(ip) {
+				t.Errorf("%s %q parses to %v", e.Format, e.Value, p)
+			}
+		}
+	}
+	for format, want := range map[string]string{
+		"decimal":               "2852039166",
+		"hex":                   "0xa9fea9fe",
+		"dotted-octal":          "0251.0376.0251.0376",
+		"short-2-part":          "169.16689662",
+		"enclosed-alphanumeric": "①⑥⑨.②⑤④.①⑥⑨.②⑤④",
+	} {
+		if got[format] != want {
+			t.Errorf("%s = %q, want %q", format, got[format], want)
+		}
+	}
+	if _, ok := got["localhost"]; ok {
+		t.Error("localhost offered for a non-loopback address")
+	}
+
+	hosts := rebindHosts(net.ParseIP("127.0.0.1"), net.ParseIP("1.1.1.1"))
+	want := []string{"127.0.0.1.nip.io", "127-0-0-1.sslip.io", "01010101.7f000001.rbndr.us", "make-1.1.1.1-rebind-127.0.0.1-rr.1u.ms"}
+	if len(hosts) != len(want) {
+		t.Fatalf("rebindHosts = %v", hosts)
+	}
+	for i := range want {
+		if hosts[i] != want[i] {
+			t.Errorf("rebindHosts[%d] = %q, want %q", i, hosts[i], want[i])
+		}
+	}
+}
+
+func TestCheckHost(t *testing.T) {
+	orig := lookupIPAddr
+	t.Cleanup(func() { lookupIPAddr = orig })
+	lookupIPAddr = func(_ context.Context, host string) ([]net.IPAddr, error) {
+		switch host {
+		case "127.0.0.1.nip.io":
+			return []net.IPAddr{{IP: net.ParseIP("127.0.0.1")}}, nil
+		case "example.com":
+			return []net.IPAddr{{IP: net.ParseIP("93.184.215.14")}}, nil
+		}
+		return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+	}
+
+	for in, want := range map[string]string{
+		"http://0x7f000001/admin":                  "127.0.0.1: loopback",
+		"http://[::ffff:169.254.169.254]/":         "169.254.169.254: link-local (cloud metadata)",
+		"http://①⑨②。①⑥⑧。⓪。①/":                      "192.168.0.1: private",
+		"127.0.0.1.nip.io:8080":                    "127.0.0.1: loopback",
+		"http://%31%30.0.0.1/":                     "10.0.0.1: private",
+		"https://example.com/":                     "",
+		"http://user@evil.example@10.1.2.3:8080/x": "10.1.2.3: private",
+		"http://[fd00:ec2::254]/latest/":           "fd00:ec2::254: unique local",
+	} {
+		hc := checkHost(context.Background(), in)
+		got := ""
+		if len(hc.Ranges) > 0 {
+			got = hc.Ranges[0]
+		}
+		if got != want || hc.Internal != (want != "") {
+			t.Errorf("checkHost(%q) = %+v, want %q", in, hc, want)
+		}
+	}
+	if hc := checkHost(context.Background(), "nope.invalid"); hc.Error == "" {
+		t.Errorf("unresolvable host: %+v", hc)
+	}
+}
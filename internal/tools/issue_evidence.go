@@ -0,0 +1,303 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/c0tton-fluff/burp-mcp-server/internal/burp"
+	"github.com/c0tton-fluff/burp-mcp-server/internal/store"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const defaultEvidenceResponseLimit = 2000
+
+// GetIssueEvidenceInput is the input for burp_get_issue_evidence.
+type GetIssueEvidenceInput struct {
+	Index         int    `json:"index,omitempty" jsonschema:"Issue position (1-based) in burp_get_scanner_issues with the same source"`
+	IssueID       string `json:"issueId,omitempty" jsonschema:"Serial number of an imported issue, instead of index; implies source=imported"`
+	Source        string `json:"source,omitempty" jsonschema:"live (default) or imported, as in burp_get_scanner_issues"`
+	ResponseLimit int    `json:"responseLimit,omitempty" jsonschema:"Max characters per response (default 2000, -1 = unlimited)"`
+	Instance      string `json:"instance,omitempty" jsonschema:"Named Burp instance from config (default: the --burp-url connection)"`
+}
+
+// InsertionPoint is a request parameter the issue names, located in the
+// raw request.
+type InsertionPoint struct {
+	Name  string `json:"name"`
+	In    string `json:"in"` // query, body, json, cookie, or header
+	Value string `json:"value"`
+	// Start and End are the byte offsets of the value in the request.
+	Start int `json:"start"`
+	End   int `json:"end"`
+}
+
+// IssueEvidencePair is one request/response pair attached to an issue.
+type IssueEvidencePair struct {
+	Request         string           `json:"request"`
+	Response        string           `json:"response,omitempty"`
+	InsertionPoints []InsertionPoint `json:"insertionPoints"`
+	// ResponseSize is the full response's length when Response was cut.
+	ResponseSize int  `json:"responseSize,omitempty"`
+	Truncated    bool `json:"truncated,omitempty"`
+}
+
+// GetIssueEvidenceOutput is the output of burp_get_issue_evidence.
+type GetIssueEvidenceOutput struct {
+	Issue burp.ScannerIssue `json:"issue"`
+	// Host, Port, and TLS are the issue's target, to pass to
+	// burp_send_request with an evidence request.
+	Host     string              `json:"host,omitempty"`
+	Port     int                 `json:"port,omitempty"`
+	TLS      bool                `json:"tls"`
+	Evidence []IssueEvidencePair `json:"evidence"`
+	Note     string              `json:"note,omitempty"`
+}
+
+// issueTarget splits an issue URL into the target burp_send_request takes.
+func issueTarget(rawURL string) (host string, port int, useTLS bool) {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Hostname() == "" {
+		return "", 0, false
+	}
+	useTLS = !strings.EqualFold(u.Scheme, "http")
+	port, _ = strconv.Atoi(u.Port())
+	if port == 0 {
+		port = 80
+		if useTLS {
+			port = 443
+		}
+	}
+	return u.Hostname(), port, useTLS
+}
+
+// requestParams lists the query, form body, JSON body, cookie, and header
+// parameters of raw with the offsets of their values.
+func requestParams(raw string) []InsertionPoint {
+	var params []InsertionPoint
+	pairs := func(start, end int, sep byte, in string) {
+		for start < end {
+			segEnd := strings.IndexByte(raw[start:end], sep)
+			if segEnd < 0 {
+				segEnd = end
+			} else {
+				segEnd += start
+			}
+			for start < segEnd && raw[start] == ' ' {
+				start++
+			}
+			name, value := raw[start:segEnd], ""
+			valueStart := segEnd
+			if eq := strings.IndexByte(name, '='); eq >= 0 {
+				name, value = name[:eq], name[eq+1:]
+				valueStart = start + eq + 1
+			}
+			if in != "cookie" {
+				if unescaped, err := url.QueryUnescape(name); err == nil {
+					name = unescaped
+				}
+			}
+			if name != "" {
+				params = append(params, InsertionPoint{Name: name, In: in, Value: value, Start: valueStart, End: segEnd})
+			}
+			start = segEnd + 1
+		}
+	}
+
+	headEnd, bodyStart := len(raw), len(raw)
+	if i := strings.Index(raw, "\r\n\r\n"); i >= 0 {
+		headEnd, bodyStart = i, i+4
+	} else if i := strings.Index(raw, "\n\n"); i >= 0 {
+		headEnd, bodyStart = i, i+2
+	}
+	var contentType string
+	for lineStart, first := 0, true; lineStart < headEnd; first = false {
+		lineEnd := strings.IndexByte(raw[lineStart:headEnd], '\n')
+		if lineEnd < 0 {
+			lineEnd = headEnd
+		} else {
+			lineEnd += lineStart
+		}
+		line := strings.TrimRight(raw[lineStart:lineEnd], "\r ")
+		switch {
+		case first:
+			if q := strings.IndexByte(line, '?'); q >= 0 {
+				end := len(line)
+				if v := strings.LastIndex(line, " HTTP/"); v > q {
+					end = v
+				}
+				pairs(lineStart+q+1, lineStart+end, '&', "query")
+			}
+		default:
+			name, value, ok := strings.Cut(line, ":")
+			if !ok {
+				break
+			}
+			valueStart := lineStart + len(name) + 1
+			for valueStart < lineStart+len(line) && raw[valueStart] == ' ' {
+				valueStart++
+			}
+			value = strings.TrimSpace(value)
+			name = strings.TrimSpace(name)
+			switch {
+			case strings.EqualFold(name, "Cookie"):
+				pairs(valueStart, lineStart+len(line), ';', "cookie")
+			default:
+				if strings.EqualFold(name, "Content-Type") {
+					contentType = strings.ToLower(value)
+				}
+				params = append(params, InsertionPoint{Name: name, In: "header", Value: value, Start: valueStart, End: lineStart + len(line)})
+			}
+		}
+		lineStart = lineEnd + 1
+	}
+
+	switch {
+	case strings.HasPrefix(contentType, "application/x-www-form-urlencoded"):
+		pairs(bodyStart, len(raw), '&', "body")
+	case strings.Contains(contentType, "json"):
+		for _, m := range jsonMemberRegex.FindAllStringSubmatchIndex(raw[bodyStart:], -1) {
+			start, end := bodyStart+m[4], bodyStart+m[5]
+			if raw[start] == '"' {
+				start, end = start+1, end-1
+			}
+			params = append(params, InsertionPoint{Name: raw[bodyStart+m[2] : bodyStart+m[3]], In: "json", Value: raw[start:end], Start: start, End: end})
+		}
+	}
+	return params
+}
+
+// jsonMemberRegex matches a JSON member with a scalar value.
+var jsonMemberRegex = regexp.MustCompile(`"((?:[^"\\]|\\.)*)"\s*:\s*("(?:[^"\\]|\\.)*"|[-\w.+]+)`)
+
+// detailNames reports whether an issue detail names a parameter. Burp's
+// details bold it ("the value of the <b>q</b> request parameter"); details
+// without markup are searched for the name as a word.
+func detailNames(detail, name string) bool {
+	if strings.Contains(detail, "<b>") {
+		return strings.Contains(detail, "<b>"+html.EscapeString(name)+"</b>")
+	}
+	re := regexp.MustCompile(`(^|[^\w-])` + regexp.QuoteMeta(name) + `($|[^\w-])`)
+	return re.MatchString(detail)
+}
+
+// issueInsertionPoints returns the parameters of request that detail names.
+func issueInsertionPoints(request, detail string) []InsertionPoint {
+	points := []InsertionPoint{}
+	if detail == "" {
+		return points
+	}
+	for _, p := range requestParams(request) {
+		if detailNames(detail, p.Name) {
+			points = append(points, p)
+		}
+	}
+	return points
+}
+
+// issueRef is a scanner issue looked up for burp_get_issue_evidence or
+// burp_verify_issue.
+type issueRef struct {
+	issue    burp.ScannerIssue
+	evidence []burp.IssueEvidence
+	stored   int // position among the store's imported issues; -1 for a live issue
+}
+
+// findIssue looks up a live or imported issue by its 1-based index, or an
+// imported one by serial number, with its evidence.
+func findIssue(ctx context.Context, client *burp.Client, st *store.Store, index int, issueID, source string) (issueRef, error) {
+	if issueID != "" {
+		if source == "live" || index != 0 {
+			return issueRef{}, fmt.Errorf("issueId selects an imported issue; don't combine it with index or source=live")
+		}
+		source = "imported"
+	} else if index <= 0 {
+		return issueRef{}, fmt.Errorf("index or issueId is required")
+	}
+
+	switch source {
+	case "", "live":
+		raw, err := client.CallTool(ctx, "get_scanner_issues", map[string]any{"count": 1, "offset": index - 1})
+		if err != nil {
+			return issueRef{}, fmt.Errorf("failed to get scanner issue: %w", err)
+		}
+		issue, evidence, err := burp.ParseScannerIssueEvidence(trimEndMarker(raw))
+		if err != nil {
+			return issueRef{}, parseFailure("%w", err)
+		}
+		if issue.Name == "" {
+			return issueRef{}, fmt.Errorf("no scanner issue at index %d", index)
+		}
+		triageIssue(st, &issue)
+		return issueRef{issue: issue, evidence: evidence, stored: -1}, nil
+	case "imported":
+		issues := st.Issues()
+		for i, issue := range issues {
+			if issueID != "" && issue.SerialNumber != issueID || issueID == "" && i != index-1 {
+				continue
+			}
+			evidence := make([]burp.IssueEvidence, len(issue.Evidence))
+			for j, e := range issue.Evidence {
+				evidence[j] = burp.IssueEvidence{Request: e.Request, Response: e.Response}
+			}
+			ref := issueRef{issue: scannerIssue(issue, 0), evidence: evidence, stored: i}
+			triageIssue(st, &ref.issue)
+			return ref, nil
+		}
+		if issueID != "" {
+			return issueRef{}, fmt.Errorf("no imported issue with serial number %s", issueID)
+		}
+		return issueRef{}, fmt.Errorf("no imported issue at index %d (%d imported)", index, len(issues))
+	}
+	return issueRef{}, fmt.Errorf("source must be live or imported")
+}
+
+func getIssueEvidenceHandler(client *burp.Client, st *store.Store) func(context.Context, *mcp.CallToolRequest, GetIssueEvidenceInput) (*mcp.CallToolResult, GetIssueEvidenceOutput, error) {
+	return func(ctx context.Context, _ *mcp.CallToolRequest, input GetIssueEvidenceInput) (*mcp.CallToolResult, GetIssueEvidenceOutput, error) {
+		ctx = burp.WithInstance(ctx, input.Instance)
+
+		responseLimit := input.ResponseLimit
+		if responseLimit == 0 {
+			responseLimit = defaultEvidenceResponseLimit
+		}
+
+		ref, err := findIssue(ctx, client, st, input.Index, input.IssueID, input.Source)
+		if err != nil {
+			return nil, GetIssueEvidenceOutput{}, err
+		}
+
+		issue := ref.issue
+		out := GetIssueEvidenceOutput{Issue: issue, Evidence: []IssueEvidencePair{}}
+		out.Host, out.Port, out.TLS = issueTarget(issue.URL)
+		for _, e := range ref.evidence {
+			pair := IssueEvidencePair{
+				Request:         e.Request,
+				Response:        e.Response,
+				InsertionPoints: issueInsertionPoints(e.Request, issue.IssueDetail),
+			}
+			if responseLimit > 0 && len(pair.Response) > responseLimit {
+				pair.ResponseSize, pair.Truncated = len(pair.Response), true
+				pair.Response = pair.Response[:responseLimit] + "..."
+			}
+			out.Evidence = append(out.Evidence, pair)
+		}
+		if len(out.Evidence) == 0 {
+			out.Note = "the issue has no request/response evidence; older versions of Burp's MCP extension omit it, as do issues cut at its size limit"
+		}
+		return nil, out, nil
+	}
+}
+
+// RegisterGetIssueEvidenceTool registers the burp_get_issue_evidence tool.
+func RegisterGetIssueEvidenceTool(server *mcp.Server, client *burp.Client, st *store.Store) {
+	addTool(server, &mcp.Tool{
+		Name: "burp_get_issue_evidence",
+		Description: `Get the request/response evidence behind a scanner issue, live or imported, with the insertion points its detail names located in each request, ` +
+			`so the finding can be reproduced with burp_send_request. ` +
+			`Returns {issue: {name, severity, confidence, url, issueDetail, detailSize, detailTruncated}, host, port, tls, evidence: [{request, response, insertionPoints: [{name, in, value, start, end}], responseSize, truncated}], note}.`,
+	}, getIssueEvidenceHandler(client, st))
+}
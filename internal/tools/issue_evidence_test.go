@@ -0,0 +1,152 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/c0tton-fluff/burp-mcp-server/internal/store"
+)
+
+func TestRequestParams(t *testing.T) {
+	raw := "POST /search?q=shoes&page=2 HTTP/1.1\r\nHost: shop.example\r\nCookie: session=abc; theme=dark\r\nContent-Type: application/x-www-form-urlencoded\r\n\r\nsort%5Bby%5D=price&limit=10"
+	got := map[string]InsertionPoint{}
+	for _, p := range requestParams(raw) {
+		if raw[p.Start:p.End] != p.Value {
+			t.Errorf("%s: offsets %d-%d give %q, want %q", p.Name, p.Start, p.End, raw[p.Start:p.End], p.Value)
+		}
+		got[p.In+":"+p.Name] = p
+	}
+	for key, value := range map[string]string{
+		"query:q":             "shoes",
+		"query:page":          "2",
+		"cookie:session":      "abc",
+		"cookie:theme":        "dark",
+		"header:Host":         "shop.example",
+		"body:sort[by]":       "price",
+		"body:limit":          "10",
+		"header:Content-Type": "application/x-www-form-urlencoded",
+	} {
+		if got[key].Value != value {
+			t.Errorf("%s = %q, want %q", key, got[key].Value, value)
+		}
+	}
+
+	raw = "PUT /api/user HTTP/1.1\nContent-Type: application/json\n\n{\"name\": \"bob\", \"age\": 31, \"tags\": [\"a\"]}"
+	var names []string
+	for _, p := range requestParams(raw) {
+		if p.In == "json" {
+			names = append(names, p.Name+"="+raw[p.Start:p.End])
+		}
+	}
+	if strings.Join(names, ",") != "name=bob,age=31" {
+		t.Errorf("json params = %v", names)
+	}
+}
+
+func TestDetailNames(t *testing.T) {
+	tests := []struct {
+		detail, name string
+		want         bool
+	}{
+		{"The value of the <b>q</b> request parameter is copied into the page.", "q", true},
+		{"The value of the <b>q</b> request parameter is copied into the page.", "page", false},
+		{"The <b>User-Agent</b> HTTP header appears to be vulnerable.", "User-Agent", true},
+		{"The q parameter is injectable.", "q", true},
+		{"The query parameter is injectable.", "q", false},
+		{"", "q", false},
+	}
+	for _, tt := range tests {
+		if got := detailNames(tt.detail, tt.name); got != tt.want {
+			t.Errorf("detailNames(%q, %q) = %v, want %v", tt.detail, tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestGetIssueEvidence_Live(t *testing.T) {
+	issue, _ := json.Marshal(map[string]any{
+		"name":     "SQL injection",
+		"detail":   "The <b>q</b> parameter appears to be vulnerable to SQL injection attacks.",
+		"baseUrl":  "http://shop.example:8080/search",
+		"severity": "HIGH",
+		"requestResponses": []map[string]string{{
+			"request":  "GET /search?q=shoes' HTTP/1.1\r\nHost: shop.example:8080\r\n\r\n",
+			"response": "HTTP/1.1 500 Internal Server Error\r\n\r\n" + strings.Repeat("SQL syntax error ", 20),
+		}},
+	})
+	f := &fakeBurp{}
+	f.add("", `{"name":"Cookie without HttpOnly flag set","severity":"LOW"}`)
+	f.add("", string(issue))
+	client := startFakeBurp(t, f)
+	st, err := store.Open(filepath.Join(t.TempDir(), "store.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	handler := getIssueEvidenceHandler(client, st)
+
+	_, out, err := handler(context.Background(), nil, GetIssueEvidenceInput{Index: 2, ResponseLimit: 50})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out.Issue.Name != "SQL injection" || out.Host != "shop.example" || out.Port != 8080 || out.TLS {
+		t.Errorf("issue = %+v, target = %s:%d tls=%v", out.Issue, out.Host, out.Port, out.TLS)
+	}
+	if len(out.Evidence) != 1 || len(out.Evidence[0].Response) != 53 || !out.Evidence[0].Truncated || out.Evidence[0].ResponseSize != 378 {
+		t.Fatalf("evidence = %+v", out.Evidence)
+	}
+	points := out.Evidence[0].InsertionPoints
+	if len(points) != 1 || points[0].Name != "q" || points[0].In != "query" || points[0].Value != "shoes'" {
+		t.Errorf("insertion points = %+v", points)
+	}
+
+	_, out, err = handler(context.Background(), nil, GetIssueEvidenceInput{Index: 1})
+	if err != nil || len(out.Evidence) != 0 || out.Note == "" || out.Port != 0 {
+		t.Errorf("issue without evidence = %+v, %v", out, err)
+	}
+	if _, _, err := handler(context.Background(), nil, GetIssueEvidenceInput{Index: 3}); err == nil {
+		t.Error("expected error past the last issue")
+	}
+	if _, _, err := handler(context.Background(), nil, GetIssueEvidenceInput{}); err == nil {
+		t.Error("expected error without index or issueId")
+	}
+}
+
+func TestGetIssueEvidence_Imported(t *testing.T) {
+	st, err := store.Open(filepath.Join(t.TempDir(), "store.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := st.ImportIssues([]store.Issue{
+		{SerialNumber: "100", Name: "Strict transport security not enforced", Host: "https://shop.example", Path: "/"},
+		{
+			SerialNumber: "200", Name: "Cross-site scripting (reflected)", Host: "https://shop.example", Path: "/greet",
+			IssueDetail: "The value of the <b>name</b> cookie is copied into the HTML document.",
+			Evidence:    []store.IssueEvidence{{Request: "GET /greet HTTP/1.1\r\nHost: shop.example\r\nCookie: id=1; name=<script>\r\n\r\n", Response: "HTTP/1.1 200 OK\r\n\r\nHello <script>"}},
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	handler := getIssueEvidenceHandler(nil, st)
+
+	for _, input := range []GetIssueEvidenceInput{{IssueID: "200"}, {Index: 2, Source: "imported"}} {
+		_, out, err := handler(context.Background(), nil, input)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if out.Host != "shop.example" || out.Port != 443 || !out.TLS || len(out.Evidence) != 1 {
+			t.Fatalf("%+v: out = %+v", input, out)
+		}
+		points := out.Evidence[0].InsertionPoints
+		if len(points) != 1 || points[0].In != "cookie" || points[0].Value != "<script>" {
+			t.Errorf("%+v: insertion points = %+v", input, points)
+		}
+	}
+	if _, _, err := handler(context.Background(), nil, GetIssueEvidenceInput{IssueID: "999"}); err == nil {
+		t.Error("expected error for an unknown serial number")
+	}
+	if _, _, err := handler(context.Background(), nil, GetIssueEvidenceInput{IssueID: "200", Source: "live"}); err == nil {
+		t.Error("expected error combining issueId with source=live")
+	}
+}
@@ -0,0 +1,67 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/c0tton-fluff/burp-mcp-server/internal/burp"
+	"github.com/c0tton-fluff/burp-mcp-server/internal/store"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// SetIssueStatusInput is the input for burp_set_issue_status.
+type SetIssueStatusInput struct {
+	Index    int    `json:"index,omitempty" jsonschema:"Issue position (1-based) in burp_get_scanner_issues with the same source"`
+	IssueID  string `json:"issueId,omitempty" jsonschema:"Serial number of an imported issue, instead of index; implies source=imported"`
+	Source   string `json:"source,omitempty" jsonschema:"live (default) or imported, as in burp_get_scanner_issues"`
+	Status   string `json:"status" jsonschema:"confirmed, false_positive, or needs_review"`
+	Note     string `json:"note,omitempty" jsonschema:"Why, e.g. the evidence that rules it in or out"`
+	Instance string `json:"instance,omitempty" jsonschema:"Named Burp instance from config (default: the --burp-url connection)"`
+}
+
+// SetIssueStatusOutput is the output of burp_set_issue_status.
+type SetIssueStatusOutput struct {
+	Issue          burp.ScannerIssue `json:"issue"`
+	PreviousStatus string            `json:"previousStatus,omitempty"`
+}
+
+// triageIssue adds the stored triage status, if any, to issue.
+func triageIssue(st *store.Store, issue *burp.ScannerIssue) {
+	if t, ok := st.IssueTriage(issue.Name, issue.URL); ok {
+		issue.Status, issue.StatusNote = t.Status, t.Note
+	}
+}
+
+func setIssueStatusHandler(client *burp.Client, st *store.Store) func(context.Context, *mcp.CallToolRequest, SetIssueStatusInput) (*mcp.CallToolResult, SetIssueStatusOutput, error) {
+	return func(ctx context.Context, _ *mcp.CallToolRequest, input SetIssueStatusInput) (*mcp.CallToolResult, SetIssueStatusOutput, error) {
+		ctx = burp.WithInstance(ctx, input.Instance)
+
+		status := strings.ToLower(strings.TrimSpace(input.Status))
+		if !slices.Contains(store.TriageStatuses, status) {
+			return nil, SetIssueStatusOutput{}, fmt.Errorf("status must be confirmed, false_positive, or needs_review")
+		}
+		ref, err := findIssue(ctx, client, st, input.Index, input.IssueID, input.Source)
+		if err != nil {
+			return nil, SetIssueStatusOutput{}, err
+		}
+		saved, err := st.SetIssueTriage(store.IssueTriage{Name: ref.issue.Name, URL: ref.issue.URL, Status: status, Note: input.Note})
+		if err != nil {
+			return nil, SetIssueStatusOutput{}, err
+		}
+		out := SetIssueStatusOutput{Issue: ref.issue, PreviousStatus: ref.issue.Status}
+		out.Issue.Status, out.Issue.StatusNote = saved.Status, saved.Note
+		return nil, out, nil
+	}
+}
+
+// RegisterSetIssueStatusTool registers the burp_set_issue_status tool.
+func RegisterSetIssueStatusTool(server *mcp.Server, client *burp.Client, st *store.Store) {
+	addTool(server, &mcp.Tool{
+		Name: "burp_set_issue_status",
+		Description: `Triage a scanner issue, live or imported, as confirmed, false_positive, or needs_review with a note. ` +
+			`The status is kept in the local store and shown with the issue by burp_get_scanner_issues across sessions. ` +
+			`Returns {issue: {name, severity, confidence, url, issueDetail, status, statusNote}, previousStatus}.`,
+	}, setIssueStatusHandler(client, st))
+}
@@ -0,0 +1,65 @@
+package tools
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/c0tton-fluff/burp-mcp-server/internal/store"
+)
+
+func TestSetIssueStatus(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "store.json")
+	st, err := store.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := &fakeBurp{}
+	f.add("", `{"name":"SQL injection","baseUrl":"https://shop.example/search","severity":"HIGH"}`)
+	f.add("", `{"name":"Email addresses disclosed","baseUrl":"https://shop.example/about","severity":"INFORMATION"}`)
+	client := startFakeBurp(t, f)
+	if _, err := st.ImportIssues([]store.Issue{{SerialNumber: "9", Name: "SQL injection", Host: "https://shop.example", Path: "/search"}}); err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	set := setIssueStatusHandler(client, st)
+
+	if _, _, err := set(ctx, nil, SetIssueStatusInput{Index: 1, Status: "wontfix"}); err == nil {
+		t.Error("expected error for an unknown status")
+	}
+	_, out, err := set(ctx, nil, SetIssueStatusInput{Index: 2, Status: "False_Positive", Note: "support address on a public page"})
+	if err != nil || out.Issue.Status != store.TriageFalsePositive || out.PreviousStatus != "" {
+		t.Fatalf("set = %+v, %v", out, err)
+	}
+	if _, out, err = set(ctx, nil, SetIssueStatusInput{Index: 1, Status: "needs_review"}); err != nil {
+		t.Fatal(err)
+	}
+	// The imported copy of the same issue shares its status.
+	_, out, err = set(ctx, nil, SetIssueStatusInput{IssueID: "9", Status: "confirmed", Note: "error-based, dumped version()"})
+	if err != nil || out.PreviousStatus != store.TriageNeedsReview {
+		t.Fatalf("set imported = %+v, %v", out, err)
+	}
+
+	// Statuses survive a restart and show in both sources.
+	st, err = store.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	get := getScannerIssuesHandler(client, st)
+	_, live, err := get(ctx, nil, GetScannerIssuesInput{})
+	if err != nil || live.Count != 2 {
+		t.Fatalf("live = %+v, %v", live, err)
+	}
+	if live.Issues[0].Status != store.TriageConfirmed || live.Issues[1].Status != store.TriageFalsePositive || live.Issues[1].StatusNote == "" {
+		t.Errorf("live issues = %+v", live.Issues)
+	}
+	_, imported, err := get(ctx, nil, GetScannerIssuesInput{Source: "imported"})
+	if err != nil || imported.Issues[0].Status != store.TriageConfirmed {
+		t.Errorf("imported = %+v, %v", imported, err)
+	}
+
+	summary := summarizeTriage(st.IssueTriages())
+	if summary.ByStatus[store.TriageConfirmed] != 1 || summary.ByStatus[store.TriageFalsePositive] != 1 || len(summary.Confirmed) != 1 {
+		t.Errorf("summary = %+v", summary)
+	}
+}
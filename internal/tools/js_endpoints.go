@@ -0,0 +1,290 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"net/url"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/c0tton-fluff/burp-mcp-server/internal/burp"
+	"github.com/c0tton-fluff/burp-mcp-server/internal/config"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// maxJSSources caps the files per call, URLs and history entries combined.
+const maxJSSources = 20
+
+var (
+	// jsStringRe matches string and template literals.
+	jsStringRe = regexp.MustCompile(`"((?:\\.|[^"\\\n])*)"|'((?:\\.|[^'\\\n])*)'|` + "`([^`]*)`")
+	// jsTemplateRe matches ${...} substitutions in template literals.
+	jsTemplateRe = regexp.MustCompile(`\$\{([^}]*)\}`)
+	jsIdentRe    = regexp.MustCompile(`[A-Za-z_$][\w$]*`)
+	jsURLRe      = regexp.MustCompile(`^(?:https?:)?//[\w.-]+(?::\d+)?(?:/\S*)?$`)
+	jsPathRe     = regexp.MustCompile(`^(?:/|\./|\.\./|[A-Za-z][\w-]*/)[\w\-./{}~%:@!$&'()*+,;=?]*$`)
+	jsMimeRe     = regexp.MustCompile(`^(?:application|text|image|audio|video|font|multipart|message|model)/[\w.+-]+$`)
+	jsDateRe     = regexp.MustCompile(`^[\dMDYmdy]{1,4}/[\dMDYmdy]{1,4}(?:/[\dMDYmdy]{1,4})?$`)
+	jsQueryKeyRe = regexp.MustCompile(`[?&]([A-Za-z_][\w\-\[\].]{0,40})=`)
+
+	// Call sites whose string argument is a URL, checked against the text
+	// just before the literal.
+	jsMethodCallRe = regexp.MustCompile(`(?i)\.(get|post|put|patch|delete|head|options)\s*\(\s*$`)
+	jsXHROpenRe    = regexp.MustCompile(`\.open\(\s*["'](\w+)["']\s*,\s*$`)
+	jsFetchRe      = regexp.MustCompile(`\bfetch\s*\(\s*$|\burl\s*:\s*$`)
+	// jsMethodOptRe finds method/type options near fetch() or $.ajax({url}).
+	jsMethodOptRe = regexp.MustCompile(`\b(?:method|type)\s*:\s*["'](\w+)["']`)
+
+	// Parameter names read or written through request helpers.
+	jsParamCallRe = regexp.MustCompile(`(?i)(?:params|query|data|form|body)\s*\.\s*(?:append|set|get|getAll|has)\(\s*["']([A-Za-z_][\w\-\[\]]{0,40})["']`)
+	jsParamObjRe  = regexp.MustCompile(`(?:JSON\.stringify\(\s*|\b(?:params|data|body|query|json)\s*:\s*)\{([^{}]{0,500})\}`)
+	jsObjKeyRe    = regexp.MustCompile(`(?:^|[,{\s])["']?([A-Za-z_$][\w$]{0,40})["']?\s*:`)
+)
+
+// jsStaticExt are asset references, not endpoints.
+var jsStaticExt = []string{".png", ".jpg", ".jpeg", ".gif", ".svg", ".ico", ".webp", ".css", ".woff", ".woff2", ".ttf", ".eot", ".mp4", ".mp3"}
+
+// JSEndpointsInput is the input for burp_extract_js_endpoints.
+type JSEndpointsInput struct {
+	URLs           []string          `json:"urls,omitempty" jsonschema:"JavaScript URLs to fetch directly"`
+	HistoryIndexes []int             `json:"historyIndexes,omitempty" jsonschema:"Proxy history indexes (1-based) whose responses are JavaScript"`
+	Headers        map[string]string `json:"headers,omitempty" jsonschema:"Headers sent when fetching urls, e.g. Cookie"`
+	HeaderProfile  string            `json:"headerProfile,omitempty" jsonschema:"Header rule profile from config (default: 'default')"`
+	Instance       string            `json:"instance,omitempty" jsonschema:"Named Burp instance from config for historyIndexes (default: the --burp-url connection)"`
+
+	TLSConfig *config.TLSOptions `json:"tlsConfig,omitempty" jsonschema:"TLS client certificate, CA bundle, version, and cipher options"`
+}
+
+// JSSource is one analyzed file.
+type JSSource struct {
+	Source    string `json:"source"`
+	Size      int    `json:"size"`
+	Endpoints int    `json:"endpoints"`
+	Error     string `json:"error,omitempty"`
+}
+
+// JSEndpoint is a path or URL found in one or more files.
+type JSEndpoint struct {
+	Path    string   `json:"path"`
+	Methods []string `json:"methods,omitempty"`
+	Sources []string `json:"sources"`
+}
+
+// JSEndpointsOutput is the output of burp_extract_js_endpoints.
+type JSEndpointsOutput struct {
+	Files     []JSSource   `json:"files"`
+	Endpoints []JSEndpoint `json:"endpoints"`
+	Params    []string     `json:"params"`
+}
+
+// jsHit is an endpoint and the HTTP method its call site implies, if any.
+type jsHit struct {
+	path, method string
+}
+
+// jsEndpoint reports whether a string literal looks like a URL or path,
+// returning it with ${expr} substitutions replaced by {name}.
+func jsEndpoint(s string) (string, []string, bool) {
+	var params []string
+	s = jsTemplateRe.ReplaceAllStringFunc(s, func(m string) string {
+		idents := jsIdentRe.FindAllString(m[2:len(m)-1], -1)
+		if len(idents) == 0 {
+			return "{}"
+		}
+		name := idents[len(idents)-1]
+		params = append(params, name)
+		return "{" + name + "}"
+	})
+	s = strings.TrimSpace(s)
+	if len(s) < 2 || len(s) > 300 || strings.ContainsAny(s, " \t\\<>\"") || !strings.ContainsAny(s, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ") {
+		return "", nil, false
+	}
+	if jsMimeRe.MatchString(s) || jsDateRe.MatchString(s) || strings.HasPrefix(s, "/*") || strings.HasPrefix(s, "//") && !jsURLRe.MatchString(s) {
+		return "", nil, false
+	}
+	if !jsURLRe.MatchString(s) && !jsPathRe.MatchString(s) {
+		return "", nil, false
+	}
+	p, _, _ := strings.Cut(s, "?")
+	if slices.Contains(jsStaticExt, strings.ToLower(path.Ext(p))) {
+		return "", nil, false
+	}
+	return s, params, true
+}
+
+// extractJSEndpoints finds endpoints and parameter names in JavaScript
+// source. String literals that look like paths or URLs are endpoints; the
+// call site around them (axios.post, xhr.open, fetch with a method option)
+// gives the method. Parameters come from query strings, template
+// substitutions, URLSearchParams/FormData calls, and request body objects.
+func extractJSEndpoints(src string) ([]jsHit, []string) {
+	var hits []jsHit
+	params := map[string]bool{}
+	for _, loc := range jsStringRe.FindAllStringSubmatchIndex(src, -1) {
+		var lit string
+		for g := 1; g <= 3; g++ {
+			if loc[2*g] >= 0 {
+				lit = src[loc[2*g]:loc[2*g+1]]
+				break
+			}
+		}
+		for _, m := range jsQueryKeyRe.FindAllStringSubmatch(lit, -1) {
+			params[m[1]] = true
+		}
+		ep, names, ok := jsEndpoint(lit)
+		if !ok {
+			continue
+		}
+		for _, n := range names {
+			params[n] = true
+		}
+		before := src[max(0, loc[0]-60):loc[0]]
+		after := src[loc[1]:min(len(src), loc[1]+200)]
+		method := ""
+		switch {
+		case jsMethodCallRe.MatchString(before):
+			method = strings.ToUpper(jsMethodCallRe.FindStringSubmatch(before)[1])
+		case jsXHROpenRe.MatchString(before):
+			method = strings.ToUpper(jsXHROpenRe.FindStringSubmatch(before)[1])
+		case jsFetchRe.MatchString(before):
+			// The method option sits in fetch's own call or in the object
+			// holding url, so look no further than the statement or object.
+			scope := after
+			if end := strings.Index(scope, ";"); end >= 0 {
+				scope = scope[:end]
+			}
+			if strings.HasSuffix(strings.TrimSpace(before), ":") {
+				head := src[max(0, loc[0]-200):loc[0]]
+				scope = head[strings.LastIndex(head, "{")+1:] + scope
+				if end := strings.Index(scope, "}"); end >= 0 {
+					scope = scope[:end]
+				}
+			}
+			method = "GET"
+			if m := jsMethodOptRe.FindStringSubmatch(scope); m != nil {
+				method = strings.ToUpper(m[1])
+			}
+		}
+		hits = append(hits, jsHit{ep, method})
+	}
+	for _, m := range jsParamCallRe.FindAllStringSubmatch(src, -1) {
+		params[m[1]] = true
+	}
+	for _, m := range jsParamObjRe.FindAllStringSubmatch(src, -1) {
+		for _, k := range jsObjKeyRe.FindAllStringSubmatch(m[1], -1) {
+			params[k[1]] = true
+		}
+	}
+	return hits, slices.Sorted(maps.Keys(params))
+}
+
+func jsEndpointsHandler(client *burp.Client) func(context.Context, *mcp.CallToolRequest, JSEndpointsInput) (*mcp.CallToolResult, JSEndpointsOutput, error) {
+	return func(ctx context.Context, _ *mcp.CallToolRequest, input JSEndpointsInput) (*mcp.CallToolResult, JSEndpointsOutput, error) {
+		ctx = burp.WithInstance(ctx, input.Instance)
+
+		n := len(input.URLs) + len(input.HistoryIndexes)
+		if n == 0 {
+			return nil, JSEndpointsOutput{}, fmt.Errorf("urls or historyIndexes is required")
+		}
+		if n > maxJSSources {
+			return nil, JSEndpointsOutput{}, fmt.Errorf("max %d sources per call", maxJSSources)
+		}
+		for _, idx := range input.HistoryIndexes {
+			if idx < 1 {
+				return nil, JSEndpointsOutput{}, fmt.Errorf("history index must be >= 1")
+			}
+		}
+
+		opts := newDirectOptions(input.TLSConfig)
+		files := make([]JSSource, n)
+		bodies := make([]string, n)
+		parallel(n, func(i int) {
+			if i < len(input.URLs) {
+				files[i].Source = input.URLs[i]
+				u, err := url.Parse(input.URLs[i])
+				if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+					files[i].Error = "not an absolute http or https URL"
+					return
+				}
+				resp, err := directGet(ctx, u, input.Headers, input.HeaderProfile, opts)
+				if err != nil {
+					files[i].Error = err.Error()
+					return
+				}
+				if resp.StatusCode != 200 {
+					files[i].Error = fmt.Sprintf("status %d", resp.StatusCode)
+					return
+				}
+				bodies[i] = resp.Body
+				return
+			}
+			idx := input.HistoryIndexes[i-len(input.URLs)]
+			files[i].Source = fmt.Sprintf("history #%d", idx)
+			reqRaw, respRaw, err := historyEntry(ctx, client, idx)
+			if err != nil {
+				files[i].Error = err.Error()
+				return
+			}
+			req := burp.ParseRawRequest(reqRaw)
+			files[i].Source = fmt.Sprintf("history #%d %s%s", idx, req.Host, req.Path)
+			resp := burp.ParseHTTPResponse(respRaw, 0, 0)
+			if resp == nil {
+				files[i].Error = "no response"
+				return
+			}
+			bodies[i] = resp.Body
+		})
+
+		type agg struct{ methods, sources map[string]bool }
+		endpoints := map[string]*agg{}
+		params := map[string]bool{}
+		for i, body := range bodies {
+			files[i].Size = len(body)
+			hits, names := extractJSEndpoints(body)
+			for _, name := range names {
+				params[name] = true
+			}
+			counted := map[string]bool{}
+			for _, h := range hits {
+				a := endpoints[h.path]
+				if a == nil {
+					a = &agg{methods: map[string]bool{}, sources: map[string]bool{}}
+					endpoints[h.path] = a
+				}
+				if h.method != "" {
+					a.methods[h.method] = true
+				}
+				a.sources[files[i].Source] = true
+				if !counted[h.path] {
+					counted[h.path] = true
+					files[i].Endpoints++
+				}
+			}
+		}
+
+		out := JSEndpointsOutput{Files: files, Endpoints: []JSEndpoint{}, Params: slices.Sorted(maps.Keys(params))}
+		for _, p := range slices.Sorted(maps.Keys(endpoints)) {
+			a := endpoints[p]
+			out.Endpoints = append(out.Endpoints, JSEndpoint{
+				Path:    p,
+				Methods: slices.Sorted(maps.Keys(a.methods)),
+				Sources: slices.Sorted(maps.Keys(a.sources)),
+			})
+		}
+		return nil, out, nil
+	}
+}
+
+// RegisterJSEndpointsTool registers the burp_extract_js_endpoints tool.
+func RegisterJSEndpointsTool(server *mcp.Server, client *burp.Client) {
+	addTool(server, &mcp.Tool{
+		Name: "burp_extract_js_endpoints",
+		Description: `Statically extract URL paths, API routes, and parameter names from JavaScript files fetched directly by URL or taken from proxy history. ` +
+			`String and template literals that look like paths are endpoints, with the method inferred from call sites (axios.post, xhr.open, fetch with method). ` +
+			`Parameters come from query strings, template substitutions, URLSearchParams/FormData calls, and request body objects. Deduplicated across files. ` +
+			`Returns {files: [{source, size, endpoints, error}], endpoints: [{path, methods, sources}], params}.`,
+	}, jsEndpointsHandler(client))
+}
@@ -0,0 +1,60 @@
+package tools
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestExtractJSEndpoints(t *testing.T) {
+	src := `
+const API = "/api/v2";
+axios.post("/api/v2/users/login", JSON.stringify({username: u, "password": p}));
+fetch(` + "`/api/v2/orders/${order.id}/items?expand=true`" + `, {method: "DELETE"});
+fetch("https://api.example.com/graphql");
+xhr.open("PUT", "/profile/avatar");
+$.ajax({type: "POST", url: "/legacy/save.php"});
+const params = new URLSearchParams(); params.append("token", t);
+el.setAttribute("type", "text/html");
+const d = "12/31/2024"; const logo = "/img/logo.png"; const re = "a\\/b";
+const msg = "Hello world";
+`
+	hits, params := extractJSEndpoints(src)
+	got := map[string]string{}
+	for _, h := range hits {
+		got[h.path] = h.method
+	}
+	want := map[string]string{
+		"/api/v2":                               "",
+		"/api/v2/users/login":                   "POST",
+		"/api/v2/orders/{id}/items?expand=true": "DELETE",
+		"https://api.example.com/graphql":       "GET",
+		"/profile/avatar":                       "PUT",
+		"/legacy/save.php":                      "POST",
+	}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("hits:\n got %v\nwant %v", got, want)
+	}
+	for _, p := range []string{"username", "password", "id", "expand", "token"} {
+		if !slices.Contains(params, p) {
+			t.Errorf("missing param %q in %v", p, params)
+		}
+	}
+}
+
+func TestJSEndpoint(t *testing.T) {
+	for _, s := range []string{"application/json", "text/plain", "/", "//", "12/01", "a b/c", "/static/font.woff2", "/* comment"} {
+		if ep, _, ok := jsEndpoint(s); ok {
+			t.Errorf("jsEndpoint(%q) = %q, want rejected", s, ep)
+		}
+	}
+	for _, s := range []string{"/api/users", "api/v1/items", "./config.json", "//cdn.example.com/x", "https://a.com"} {
+		if _, _, ok := jsEndpoint(s); !ok {
+			t.Errorf("jsEndpoint(%q) rejected", s)
+		}
+	}
+	if ep, params, _ := jsEndpoint("/u/${user.id}/p/${ x }"); ep != "/u/{id}/p/{x}" || strings.Join(params, ",") != "id,x" {
+		t.Errorf("got %q %v", ep, params)
+	}
+}
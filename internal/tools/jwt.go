@@ -0,0 +1,88 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/c0tton-fluff/burp-mcp-server/internal/jwt"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// maxJWTs caps how many tokens one burp_decode_jwt call decodes.
+const maxJWTs = 10
+
+// DecodeJWTInput is the input for burp_decode_jwt.
+type DecodeJWTInput struct {
+	Content string `json:"content" jsonschema:"required,A JWT, or text containing JWTs such as an Authorization header, cookie, or token endpoint response"`
+	OIDC    *bool  `json:"oidc,omitempty" jsonschema:"Apply the OpenID Connect id_token checks (default: when the claims look like an id_token)"`
+
+	ExpectedIssuer   string `json:"expectedIssuer,omitempty" jsonschema:"Issuer the relying party should require"`
+	ExpectedAudience string `json:"expectedAudience,omitempty" jsonschema:"Client ID the token should be issued to (aud and azp)"`
+	ExpectedNonce    string `json:"expectedNonce,omitempty" jsonschema:"Nonce sent in the authorization request"`
+}
+
+// JWTInfo is one decoded token.
+type JWTInfo struct {
+	Header    map[string]any `json:"header"`
+	Claims    map[string]any `json:"claims,omitempty"`
+	Alg       string         `json:"alg,omitempty"`
+	Encrypted bool           `json:"encrypted,omitempty"`
+	IDToken   bool           `json:"idToken,omitempty"`
+	ExpiresAt string         `json:"expiresAt,omitempty"`
+	IssuedAt  string         `json:"issuedAt,omitempty"`
+	Issues    []string       `json:"issues,omitempty"`
+	Error     string         `json:"error,omitempty"`
+}
+
+// DecodeJWTOutput is the output of burp_decode_jwt.
+type DecodeJWTOutput struct {
+	Tokens []JWTInfo `json:"tokens"`
+}
+
+func decodeJWTHandler() func(context.Context, *mcp.CallToolRequest, DecodeJWTInput) (*mcp.CallToolResult, DecodeJWTOutput, error) {
+	return func(_ context.Context, _ *mcp.CallToolRequest, input DecodeJWTInput) (*mcp.CallToolResult, DecodeJWTOutput, error) {
+		found := jwt.Find(input.Content, maxJWTs)
+		if len(found) == 0 {
+			return nil, DecodeJWTOutput{}, fmt.Errorf("no JWT found in content")
+		}
+		want := jwt.Expectations{Issuer: input.ExpectedIssuer, Audience: input.ExpectedAudience, Nonce: input.ExpectedNonce}
+		now := time.Now()
+
+		out := DecodeJWTOutput{Tokens: make([]JWTInfo, len(found))}
+		for i, s := range found {
+			t, err := jwt.Decode(s)
+			if t == nil {
+				out.Tokens[i] = JWTInfo{Error: err.Error()}
+				continue
+			}
+			info := JWTInfo{Header: t.Header, Claims: t.Claims, Alg: t.Alg(), Encrypted: t.Encrypted}
+			if err != nil {
+				info.Error = err.Error()
+			}
+			info.IDToken = t.IsIDToken()
+			if input.OIDC != nil {
+				info.IDToken = *input.OIDC
+			}
+			if exp, ok := t.Time("exp"); ok {
+				info.ExpiresAt = exp.Format(time.RFC3339)
+			}
+			if iat, ok := t.Time("iat"); ok {
+				info.IssuedAt = iat.Format(time.RFC3339)
+			}
+			info.Issues = t.Check(now, info.IDToken, want)
+			out.Tokens[i] = info
+		}
+		return nil, out, nil
+	}
+}
+
+// RegisterDecodeJWTTool registers the burp_decode_jwt tool.
+func RegisterDecodeJWTTool(server *mcp.Server) {
+	addTool(server, &mcp.Tool{
+		Name: "burp_decode_jwt",
+		Description: `Decode the JWTs in some text without verifying them and flag alg none, shared-secret algs, key-selecting headers, and expiry. ` +
+			`OIDC id_tokens are also checked for issuer, audience, azp, and nonce (expectedIssuer, expectedAudience, expectedNonce). ` +
+			`Returns {tokens: [{header, claims, alg, encrypted, idToken, expiresAt, issuedAt, issues}]}.`,
+	}, decodeJWTHandler())
+}
@@ -0,0 +1,36 @@
+package tools
+
+import (
+	"context"
+	"encoding/base64"
+	"testing"
+)
+
+func TestDecodeJWTHandler(t *testing.T) {
+	enc := base64.RawURLEncoding.EncodeToString
+	idToken := enc([]byte(`{"alg":"RS256"}`)) + "." +
+		enc([]byte(`{"iss":"https://idp.example","sub":"1","aud":"app","exp":4102444800,"iat":1700000000,"nonce":"n"}`)) + ".c2ln"
+	access := enc([]byte(`{"alg":"none"}`)) + "." + enc([]byte(`{"scope":"read"}`)) + "."
+
+	_, out, err := decodeJWTHandler()(context.Background(), nil, DecodeJWTInput{
+		Content:          `{"id_token":"` + idToken + `","access_token":"` + access + `"}`,
+		ExpectedAudience: "other",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(out.Tokens) != 2 {
+		t.Fatalf("tokens = %+v", out.Tokens)
+	}
+	id := out.Tokens[0]
+	if !id.IDToken || id.Alg != "RS256" || id.ExpiresAt != "2100-01-01T00:00:00Z" || len(id.Issues) != 1 {
+		t.Errorf("id_token = %+v", id)
+	}
+	if at := out.Tokens[1]; at.IDToken || at.Alg != "none" || len(at.Issues) != 2 {
+		t.Errorf("access token = %+v", at)
+	}
+
+	if _, _, err := decodeJWTHandler()(context.Background(), nil, DecodeJWTInput{Content: "no tokens here"}); err == nil {
+		t.Error("expected an error without a JWT")
+	}
+}
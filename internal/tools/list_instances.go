@@ -0,0 +1,30 @@
+package tools
+
+import (
+	"context"
+
+	"github.com/c0tton-fluff/burp-mcp-server/internal/burp"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// ListInstancesInput is the input for burp_list_instances (no parameters).
+type ListInstancesInput struct{}
+
+// ListInstancesOutput is the output of burp_list_instances.
+type ListInstancesOutput struct {
+	Instances []burp.InstanceHealth `json:"instances"`
+}
+
+func listInstancesHandler(client *burp.Client) func(context.Context, *mcp.CallToolRequest, ListInstancesInput) (*mcp.CallToolResult, ListInstancesOutput, error) {
+	return func(_ context.Context, _ *mcp.CallToolRequest, _ ListInstancesInput) (*mcp.CallToolResult, ListInstancesOutput, error) {
+		return nil, ListInstancesOutput{Instances: client.Health()}, nil
+	}
+}
+
+// RegisterListInstancesTool registers the burp_list_instances tool.
+func RegisterListInstancesTool(server *mcp.Server, client *burp.Client) {
+	addTool(server, &mcp.Tool{
+		Name:        "burp_list_instances",
+		Description: `List configured Burp instances with connection health. Returns {instances: [{name, endpoint, connected, lastSuccess, lastError, consecutiveFailures}]}.`,
+	}, listInstancesHandler(client))
+}
@@ -0,0 +1,180 @@
+package tools
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/c0tton-fluff/burp-mcp-server/internal/burp"
+	"github.com/c0tton-fluff/burp-mcp-server/internal/store"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const (
+	defaultParamExamples  = 5
+	defaultParamEndpoints = 10
+	maxParamValueChars    = 100
+)
+
+// paramLocations are the places burp_list_parameters reports.
+var paramLocations = []string{"query", "body", "json", "cookie", "header"}
+
+// boringHeaders are request headers every client sends; they aren't listed
+// unless allHeaders is set.
+var boringHeaders = []string{
+	"accept", "accept-encoding", "accept-language", "cache-control", "connection", "content-length", "content-type",
+	"dnt", "host", "if-modified-since", "if-none-match", "origin", "pragma", "priority", "referer", "te",
+	"upgrade-insecure-requests", "user-agent",
+}
+
+// ListParametersInput is the input for burp_list_parameters.
+type ListParametersInput struct {
+	Snapshot    string   `json:"snapshot,omitempty" jsonschema:"Read one saved history snapshot (default: every snapshot)"`
+	Live        bool     `json:"live,omitempty" jsonschema:"Read live proxy history instead of saved snapshots"`
+	MaxEntries  int      `json:"maxEntries,omitempty" jsonschema:"Most live entries to read, oldest first (default 1000, max 5000)"`
+	Host        string   `json:"host,omitempty" jsonschema:"Only requests to this host"`
+	In          []string `json:"in,omitempty" jsonschema:"Only these locations: query, body, json, cookie, header (default: all)"`
+	Match       string   `json:"match,omitempty" jsonschema:"Only parameter names matching this regex"`
+	AllHeaders  bool     `json:"allHeaders,omitempty" jsonschema:"Include standard headers such as User-Agent and Accept"`
+	Examples    int      `json:"examples,omitempty" jsonschema:"Distinct example values per parameter (default 5)"`
+	EndpointMax int      `json:"endpointMax,omitempty" jsonschema:"Endpoints listed per parameter (default 10)"`
+	Instance    string   `json:"instance,omitempty" jsonschema:"Named Burp instance from config (default: the --burp-url connection)"`
+}
+
+// ObservedParameter is one parameter name in one location across traffic.
+type ObservedParameter struct {
+	Name      string   `json:"name"`
+	In        string   `json:"in"`
+	Count     int      `json:"count"`
+	Examples  []string `json:"examples"`
+	Endpoints []string `json:"endpoints"`
+	// EndpointCount is the number of distinct endpoints, listed or not.
+	EndpointCount int `json:"endpointCount"`
+}
+
+// ListParametersOutput is the output of burp_list_parameters.
+type ListParametersOutput struct {
+	Scanned    int                 `json:"scanned"`
+	Parameters []ObservedParameter `json:"parameters"`
+	// More is set when live history has entries past maxEntries.
+	More bool `json:"more,omitempty"`
+}
+
+// requestEndpoint renders a request as "METHOD origin/templated-path", the
+// form burp_get_coverage lists endpoints in.
+func requestEndpoint(parsed *burp.ParsedHTTPRequest) string {
+	host, port := parsed.Host, 0
+	if h, p, err := net.SplitHostPort(parsed.Host); err == nil {
+		host = h
+		port, _ = strconv.Atoi(p)
+	}
+	return parsed.Method + " " + endpointOrigin(true, host, port) + store.TemplatePath(parsed.Path)
+}
+
+func listParametersHandler(client *burp.Client, st *store.Store) func(context.Context, *mcp.CallToolRequest, ListParametersInput) (*mcp.CallToolResult, ListParametersOutput, error) {
+	return func(ctx context.Context, _ *mcp.CallToolRequest, input ListParametersInput) (*mcp.CallToolResult, ListParametersOutput, error) {
+		ctx = burp.WithInstance(ctx, input.Instance)
+		for _, in := range input.In {
+			if !slices.Contains(paramLocations, in) {
+				return nil, ListParametersOutput{}, fmt.Errorf("in must be among %s", strings.Join(paramLocations, ", "))
+			}
+		}
+		var match *regexp.Regexp
+		if input.Match != "" {
+			var err error
+			if match, err = regexp.Compile(input.Match); err != nil {
+				return nil, ListParametersOutput{}, fmt.Errorf("invalid match regex: %w", err)
+			}
+		}
+		examples := cmp.Or(input.Examples, defaultParamExamples)
+		endpointMax := cmp.Or(input.EndpointMax, defaultParamEndpoints)
+
+		type paramKey struct{ name, in string }
+		params := make(map[paramKey]*ObservedParameter)
+		endpoints := make(map[paramKey]map[string]bool)
+		out := ListParametersOutput{Parameters: []ObservedParameter{}}
+		visit := func(_ string, e store.HistoryEntry) {
+			parsed := burp.ParseRawRequest(e.Request)
+			if parsed.Method == "" {
+				return
+			}
+			if input.Host != "" {
+				host, _, err := net.SplitHostPort(parsed.Host)
+				if err != nil {
+					host = parsed.Host
+				}
+				if !strings.EqualFold(host, input.Host) {
+					return
+				}
+			}
+			out.Scanned++
+			endpoint := requestEndpoint(parsed)
+			for _, p := range requestParams(e.Request) {
+				if len(input.In) > 0 && !slices.Contains(input.In, p.In) ||
+					p.In == "header" && !input.AllHeaders && slices.Contains(boringHeaders, strings.ToLower(p.Name)) ||
+					match != nil && !match.MatchString(p.Name) {
+					continue
+				}
+				k := paramKey{p.Name, p.In}
+				if p.In == "header" {
+					k.name = strings.ToLower(p.Name)
+				}
+				op := params[k]
+				if op == nil {
+					op = &ObservedParameter{Name: p.Name, In: p.In, Examples: []string{}, Endpoints: []string{}}
+					params[k] = op
+					endpoints[k] = make(map[string]bool)
+				}
+				op.Count++
+				value := p.Value
+				if p.In == "query" || p.In == "body" {
+					if unescaped, err := url.QueryUnescape(value); err == nil {
+						value = unescaped
+					}
+				}
+				if len(value) > maxParamValueChars {
+					value = value[:maxParamValueChars] + "..."
+				}
+				if value != "" && len(op.Examples) < examples && !slices.Contains(op.Examples, value) {
+					op.Examples = append(op.Examples, value)
+				}
+				if !endpoints[k][endpoint] {
+					endpoints[k][endpoint] = true
+					op.EndpointCount++
+					if len(op.Endpoints) < endpointMax {
+						op.Endpoints = append(op.Endpoints, endpoint)
+					}
+				}
+			}
+		}
+		var err error
+		out.More, err = visitTraffic(ctx, client, st, input.Snapshot, input.Live, input.MaxEntries, visit)
+		if err != nil {
+			return nil, ListParametersOutput{}, err
+		}
+
+		for _, op := range params {
+			out.Parameters = append(out.Parameters, *op)
+		}
+		slices.SortFunc(out.Parameters, func(a, b ObservedParameter) int {
+			return cmp.Or(cmp.Compare(b.EndpointCount, a.EndpointCount), cmp.Compare(b.Count, a.Count), cmp.Compare(a.Name, b.Name), cmp.Compare(a.In, b.In))
+		})
+		return nil, out, nil
+	}
+}
+
+// RegisterListParametersTool registers the burp_list_parameters tool.
+func RegisterListParametersTool(server *mcp.Server, client *burp.Client, st *store.Store) {
+	addTool(server, &mcp.Tool{
+		Name: "burp_list_parameters",
+		Description: `List every parameter name seen in requests of saved history snapshots, or live proxy history with live=true: query, form body, JSON body keys, cookies, and non-standard headers, ` +
+			`each with distinct example values and the endpoints (method + templated path) it appears on, most widespread first. Use it to pick injection targets and parameters for pollution tests. ` +
+			`Returns {scanned, parameters: [{name, in, count, examples, endpoints, endpointCount}], more}.`,
+	}, listParametersHandler(client, st))
+}
@@ -0,0 +1,66 @@
+package tools
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/c0tton-fluff/burp-mcp-server/internal/store"
+)
+
+func TestListParameters(t *testing.T) {
+	st, err := store.Open(filepath.Join(t.TempDir(), "store.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := &fakeBurp{}
+	f.add(rawHistoryJSON("GET /users/42?q=a%20b&page=1 HTTP/1.1\r\nHost: a.test\r\nUser-Agent: x\r\nX-Tenant: acme\r\nCookie: sid=s1\r\n\r\n", "HTTP/1.1 200 OK\r\n\r\n"), "")
+	f.add(rawHistoryJSON("GET /users/7?q=c HTTP/1.1\r\nHost: a.test\r\nCookie: sid=s2\r\n\r\n", "HTTP/1.1 200 OK\r\n\r\n"), "")
+	f.add(rawHistoryJSON("POST /search HTTP/1.1\r\nHost: a.test\r\nContent-Type: application/json\r\n\r\n{\"q\":\"d\",\"filter\":{\"role\":\"admin\"}}", "HTTP/1.1 200 OK\r\n\r\n"), "")
+	f.add(rawHistoryJSON("GET /?q=e HTTP/1.1\r\nHost: b.test\r\n\r\n", "HTTP/1.1 200 OK\r\n\r\n"), "")
+	client := startFakeBurp(t, f)
+	ctx := context.Background()
+	list := listParametersHandler(client, st)
+
+	_, out, err := list(ctx, nil, ListParametersInput{Live: true, Host: "a.test"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out.Scanned != 3 {
+		t.Fatalf("scanned = %d", out.Scanned)
+	}
+	byName := make(map[string]ObservedParameter)
+	for _, p := range out.Parameters {
+		byName[p.In+":"+p.Name] = p
+	}
+	q := byName["query:q"]
+	if q.Count != 2 || len(q.Examples) != 2 || q.Examples[0] != "a b" || len(q.Endpoints) != 1 || q.Endpoints[0] != "GET https://a.test/users/{id}" {
+		t.Errorf("query q = %+v", q)
+	}
+	if sid := byName["cookie:sid"]; sid.Count != 2 {
+		t.Errorf("cookie sid = %+v", sid)
+	}
+	if _, ok := byName["header:X-Tenant"]; !ok {
+		t.Errorf("custom header missing from %+v", out.Parameters)
+	}
+	if _, ok := byName["header:User-Agent"]; ok {
+		t.Error("standard header listed without allHeaders")
+	}
+	if _, ok := byName["json:q"]; !ok {
+		t.Errorf("json key missing from %+v", out.Parameters)
+	}
+
+	_, out, err = list(ctx, nil, ListParametersInput{Live: true, In: []string{"query"}, Match: "^q$", Examples: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(out.Parameters) != 1 || out.Parameters[0].Count != 3 || out.Parameters[0].EndpointCount != 2 || len(out.Parameters[0].Examples) != 1 {
+		t.Errorf("filtered = %+v", out.Parameters)
+	}
+	if _, _, err := list(ctx, nil, ListParametersInput{Live: true, In: []string{"path"}}); err == nil {
+		t.Error("expected error for an unknown location")
+	}
+	if _, _, err := list(ctx, nil, ListParametersInput{Live: true, Match: "("}); err == nil {
+		t.Error("expected error for a bad match regex")
+	}
+}
@@ -0,0 +1,293 @@
+package tools
+
+import (
+	"bufio"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/c0tton-fluff/burp-mcp-server/internal/burp"
+	"github.com/c0tton-fluff/burp-mcp-server/internal/config"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// Caps on sitemap traversal: index files point at more sitemaps.
+const (
+	maxSitemapFiles = 10
+	maxSitemapURLs  = 1000
+)
+
+// MetaFilesInput is the input for burp_fetch_meta_files.
+type MetaFilesInput struct {
+	URL           string            `json:"url" jsonschema:"required,Site origin, e.g. https://example.com (any path is ignored)"`
+	Headers       map[string]string `json:"headers,omitempty" jsonschema:"Headers sent with every request"`
+	HeaderProfile string            `json:"headerProfile,omitempty" jsonschema:"Header rule profile from config (default: 'default')"`
+
+	TLSConfig *config.TLSOptions `json:"tlsConfig,omitempty" jsonschema:"TLS client certificate, CA bundle, version, and cipher options"`
+}
+
+// RobotsTxt is the parsed robots.txt.
+type RobotsTxt struct {
+	StatusCode int      `json:"statusCode,omitempty"`
+	Disallowed []string `json:"disallowed"`
+	Allowed    []string `json:"allowed,omitempty"`
+	Sitemaps   []string `json:"sitemaps,omitempty"`
+	Error      string   `json:"error,omitempty"`
+}
+
+// SitemapURL is one <url> entry.
+type SitemapURL struct {
+	Loc     string `json:"loc"`
+	LastMod string `json:"lastmod,omitempty"`
+}
+
+// Sitemap is the union of every sitemap fetched.
+type Sitemap struct {
+	Files     []string     `json:"files"`
+	URLs      []SitemapURL `json:"urls"`
+	Truncated bool         `json:"truncated,omitempty"`
+	Errors    []string     `json:"errors,omitempty"`
+}
+
+// SecurityTxt is the parsed security.txt.
+type SecurityTxt struct {
+	Location string              `json:"location,omitempty"`
+	Fields   map[string][]string `json:"fields,omitempty"`
+	Error    string              `json:"error,omitempty"`
+}
+
+// MetaFilesOutput is the output of burp_fetch_meta_files.
+type MetaFilesOutput struct {
+	Robots   RobotsTxt   `json:"robots"`
+	Sitemap  Sitemap     `json:"sitemap"`
+	Security SecurityTxt `json:"security"`
+	// Paths are the same-origin paths from all three, deduplicated.
+	Paths []string `json:"paths"`
+}
+
+// parseRobots extracts Disallow, Allow, and Sitemap lines for every
+// user agent; hidden paths matter whichever crawler they target.
+func parseRobots(body string) RobotsTxt {
+	r := RobotsTxt{Disallowed: []string{}}
+	sc := bufio.NewScanner(strings.NewReader(body))
+	for sc.Scan() {
+		line, _, _ := strings.Cut(sc.Text(), "#")
+		key, value, ok := strings.Cut(line, ":")
+		value = strings.TrimSpace(value)
+		if !ok || value == "" {
+			continue
+		}
+		switch strings.ToLower(strings.TrimSpace(key)) {
+		case "disallow":
+			if !slices.Contains(r.Disallowed, value) {
+				r.Disallowed = append(r.Disallowed, value)
+			}
+		case "allow":
+			if !slices.Contains(r.Allowed, value) {
+				r.Allowed = append(r.Allowed, value)
+			}
+		case "sitemap":
+			if !slices.Contains(r.Sitemaps, value) {
+				r.Sitemaps = append(r.Sitemaps, value)
+			}
+		}
+	}
+	return r
+}
+
+// parseSitemap returns the <url> entries of a urlset and the <sitemap>
+// locations of a sitemap index.
+func parseSitemap(body string) ([]SitemapURL, []string, error) {
+	var doc struct {
+		XMLName xml.Name
+		URLs    []struct {
+			Loc     string `xml:"loc"`
+			LastMod string `xml:"lastmod"`
+		} `xml:"url"`
+		Sitemaps []struct {
+			Loc string `xml:"loc"`
+		} `xml:"sitemap"`
+	}
+	if err := xml.Unmarshal([]byte(body), &doc); err != nil {
+		return nil, nil, parseFailure("parse sitemap: %w", err)
+	}
+	var urls []SitemapURL
+	for _, u := range doc.URLs {
+		if loc := strings.TrimSpace(u.Loc); loc != "" {
+			urls = append(urls, SitemapURL{Loc: loc, LastMod: strings.TrimSpace(u.LastMod)})
+		}
+	}
+	var nested []string
+	for _, s := range doc.Sitemaps {
+		if loc := strings.TrimSpace(s.Loc); loc != "" {
+			nested = append(nested, loc)
+		}
+	}
+	return urls, nested, nil
+}
+
+// parseSecurityTxt collects "Field: value" lines (RFC 9116), skipping
+// comments and PGP signature armor.
+func parseSecurityTxt(body string) map[string][]string {
+	fields := map[string][]string{}
+	sc := bufio.NewScanner(strings.NewReader(body))
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "-----") {
+			continue
+		}
+		key, value, ok := strings.Cut(line, ":")
+		if !ok || strings.ContainsAny(key, " \t") {
+			continue
+		}
+		fields[key] = append(fields[key], strings.TrimSpace(value))
+	}
+	return fields
+}
+
+// isTextFile reports whether resp is a real text file rather than an HTML
+// catch-all page served for every path.
+func isTextFile(resp *burp.ParsedHTTPResponse) bool {
+	ct := strings.ToLower(burp.GetHeader(resp.Headers, "Content-Type"))
+	return resp.StatusCode == 200 && !strings.Contains(ct, "html")
+}
+
+func metaFilesHandler() func(context.Context, *mcp.CallToolRequest, MetaFilesInput) (*mcp.CallToolResult, MetaFilesOutput, error) {
+	return func(ctx context.Context, _ *mcp.CallToolRequest, input MetaFilesInput) (*mcp.CallToolResult, MetaFilesOutput, error) {
+		origin, err := url.Parse(input.URL)
+		if err != nil || (origin.Scheme != "http" && origin.Scheme != "https") || origin.Host == "" {
+			return nil, MetaFilesOutput{}, fmt.Errorf("url must be an absolute http or https URL")
+		}
+		origin = &url.URL{Scheme: origin.Scheme, Host: origin.Host, Path: "/"}
+		opts := newDirectOptions(input.TLSConfig)
+		get := func(ref string) (*burp.ParsedHTTPResponse, error) {
+			u, err := origin.Parse(ref)
+			if err != nil {
+				return nil, err
+			}
+			return directGet(ctx, u, input.Headers, input.HeaderProfile, opts)
+		}
+
+		var out MetaFilesOutput
+		if resp, err := get("/robots.txt"); err != nil {
+			out.Robots = RobotsTxt{Disallowed: []string{}, Error: err.Error()}
+		} else if !isTextFile(resp) {
+			out.Robots = RobotsTxt{Disallowed: []string{}, StatusCode: resp.StatusCode, Error: "not found"}
+		} else {
+			out.Robots = parseRobots(resp.Body)
+			out.Robots.StatusCode = resp.StatusCode
+		}
+
+		out.Sitemap = fetchSitemaps(origin, out.Robots.Sitemaps, get)
+
+		for _, loc := range []string{"/.well-known/security.txt", "/security.txt"} {
+			resp, err := get(loc)
+			if err != nil {
+				out.Security.Error = err.Error()
+				break
+			}
+			if isTextFile(resp) {
+				out.Security = SecurityTxt{Location: loc, Fields: parseSecurityTxt(resp.Body)}
+				break
+			}
+			out.Security.Error = "not found"
+		}
+
+		out.Paths = metaPaths(origin, out)
+		return nil, out, nil
+	}
+}
+
+// fetchSitemaps reads /sitemap.xml and any sitemaps named in robots.txt,
+// following sitemap indexes breadth-first within the file and URL caps.
+func fetchSitemaps(origin *url.URL, fromRobots []string, get func(string) (*burp.ParsedHTTPResponse, error)) Sitemap {
+	sm := Sitemap{Files: []string{}, URLs: []SitemapURL{}}
+	queue := append([]string{origin.JoinPath("sitemap.xml").String()}, fromRobots...)
+	seen := map[string]bool{}
+	for len(queue) > 0 {
+		loc := queue[0]
+		queue = queue[1:]
+		if seen[loc] {
+			continue
+		}
+		seen[loc] = true
+		if len(sm.Files) == maxSitemapFiles {
+			sm.Truncated = true
+			break
+		}
+		resp, err := get(loc)
+		if err != nil {
+			sm.Errors = append(sm.Errors, fmt.Sprintf("%s: %v", loc, err))
+			continue
+		}
+		if !isTextFile(resp) {
+			// A missing default sitemap is normal; only report named ones.
+			if len(sm.Files) > 0 || slices.Contains(fromRobots, loc) {
+				sm.Errors = append(sm.Errors, fmt.Sprintf("%s: not found (status %d)", loc, resp.StatusCode))
+			}
+			continue
+		}
+		sm.Files = append(sm.Files, loc)
+		urls, nested, err := parseSitemap(resp.Body)
+		if err != nil {
+			sm.Errors = append(sm.Errors, fmt.Sprintf("%s: %v", loc, err))
+			continue
+		}
+		queue = append(queue, nested...)
+		for _, u := range urls {
+			if len(sm.URLs) == maxSitemapURLs {
+				sm.Truncated = true
+				break
+			}
+			sm.URLs = append(sm.URLs, u)
+		}
+	}
+	return sm
+}
+
+// metaPaths lists the same-origin paths from robots.txt rules, sitemap
+// entries, and security.txt links. Robots wildcards are cut at the first * or $.
+func metaPaths(origin *url.URL, out MetaFilesOutput) []string {
+	paths := []string{}
+	add := func(p string) {
+		if p != "" && !slices.Contains(paths, p) {
+			paths = append(paths, p)
+		}
+	}
+	for _, rule := range slices.Concat(out.Robots.Disallowed, out.Robots.Allowed) {
+		if i := strings.IndexAny(rule, "*$"); i >= 0 {
+			rule = rule[:i]
+		}
+		if strings.HasPrefix(rule, "/") && rule != "/" {
+			add(rule)
+		}
+	}
+	sameOrigin := func(raw string) {
+		u, err := url.Parse(raw)
+		if err == nil && strings.EqualFold(u.Host, origin.Host) {
+			add(u.RequestURI())
+		}
+	}
+	for _, u := range out.Sitemap.URLs {
+		sameOrigin(u.Loc)
+	}
+	for _, key := range []string{"Policy", "Acknowledgments", "Hiring", "Canonical"} {
+		for _, v := range out.Security.Fields[key] {
+			sameOrigin(v)
+		}
+	}
+	return paths
+}
+
+// RegisterMetaFilesTool registers the burp_fetch_meta_files tool.
+func RegisterMetaFilesTool(server *mcp.Server) {
+	addTool(server, &mcp.Tool{
+		Name: "burp_fetch_meta_files",
+		Description: `Fetch and parse robots.txt (Disallow/Allow rules, Sitemap lines), sitemap.xml and the sitemaps it or robots.txt names (following indexes), and .well-known/security.txt for a site, sent directly. ` +
+			`HTML catch-all pages are not mistaken for the files. ` +
+			`Returns {robots: {disallowed, allowed, sitemaps}, sitemap: {files, urls: [{loc, lastmod}], truncated}, security: {location, fields}, paths} where paths are same-origin paths ready for burp_crawl or content discovery.`,
+	}, metaFilesHandler())
+}
@@ -0,0 +1,62 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestParseRobots(t *testing.T) {
+	r := parseRobots("User-agent: *\nDisallow: /admin/ # staff only\nDisallow:\nAllow: /admin/public\n\nUser-agent: Googlebot\nDisallow: /admin/\nDisallow: /*.bak$\nSitemap: https://a.com/s1.xml\n")
+	if got := strings.Join(r.Disallowed, " "); got != "/admin/ /*.bak$" {
+		t.Errorf("disallowed = %s", got)
+	}
+	if len(r.Allowed) != 1 || len(r.Sitemaps) != 1 || r.Sitemaps[0] != "https://a.com/s1.xml" {
+		t.Errorf("got %+v", r)
+	}
+}
+
+func TestParseSecurityTxt(t *testing.T) {
+	fields := parseSecurityTxt("-----BEGIN PGP SIGNED MESSAGE-----\n# comment\nContact: mailto:sec@a.com\nContact: https://a.com/report\nExpires: 2030-01-01T00:00:00.000Z\n")
+	if len(fields["Contact"]) != 2 || fields["Expires"][0] != "2030-01-01T00:00:00.000Z" || len(fields) != 2 {
+		t.Errorf("got %v", fields)
+	}
+}
+
+func TestFetchMetaFiles(t *testing.T) {
+	var base string
+	target := startTestTarget(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/robots.txt":
+			fmt.Fprint(w, "User-agent: *\nDisallow: /internal/\nDisallow: /*.sql$\nSitemap: "+base+"/sitemap_index.xml\n")
+		case "/sitemap_index.xml":
+			fmt.Fprint(w, `<?xml version="1.0"?><sitemapindex xmlns="http://www.sitemaps.org/schemas/sitemap/0.9"><sitemap><loc>`+base+`/pages.xml</loc></sitemap></sitemapindex>`)
+		case "/pages.xml":
+			fmt.Fprint(w, `<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9"><url><loc>`+base+`/blog?p=1</loc><lastmod>2024-05-01</lastmod></url><url><loc>https://cdn.example/x</loc></url></urlset>`)
+		default:
+			// Catch-all HTML, as many apps serve for unknown paths.
+			w.Header().Set("Content-Type", "text/html")
+			fmt.Fprint(w, "<html>home</html>")
+		}
+	})
+	base = fmt.Sprintf("http://%s:%d", target.Host, target.Port)
+
+	_, out, err := metaFilesHandler()(context.Background(), nil, MetaFilesInput{URL: base + "/ignored"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out.Robots.StatusCode != 200 || len(out.Robots.Disallowed) != 2 {
+		t.Errorf("robots = %+v", out.Robots)
+	}
+	if len(out.Sitemap.Files) != 2 || len(out.Sitemap.URLs) != 2 || out.Sitemap.URLs[0].LastMod != "2024-05-01" {
+		t.Errorf("sitemap = %+v", out.Sitemap)
+	}
+	if out.Security.Error != "not found" || out.Security.Fields != nil {
+		t.Errorf("security = %+v", out.Security)
+	}
+	if got := strings.Join(out.Paths, " "); got != "/internal/ /blog?p=1" {
+		t.Errorf("paths = %s", got)
+	}
+}
@@ -0,0 +1,217 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/c0tton-fluff/burp-mcp-server/internal/burp"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// Caps on custom verbs per probe.
+const (
+	maxExtraMethods    = 20
+	maxOverrideMethods = 5
+)
+
+// probeMethods are sent as-is. FOOBAR and the lowercase get catch servers
+// that treat unknown verbs as GET, which defeats method-based access rules.
+var probeMethods = []string{"GET", "HEAD", "POST", "OPTIONS", "PUT", "DELETE", "PATCH", "TRACE", "CONNECT", "PROPFIND", "FOOBAR", "get"}
+
+// methodOverrideHeaders tunnel a verb through POST.
+var methodOverrideHeaders = []string{"X-HTTP-Method-Override", "X-HTTP-Method", "X-Method-Override"}
+
+// dangerousMethods change server state or leak request data when allowed.
+var dangerousMethods = []string{"PUT", "DELETE", "PATCH", "TRACE", "CONNECT", "PROPFIND", "PROPPATCH", "MKCOL", "COPY", "MOVE", "LOCK", "UNLOCK"}
+
+// MethodProbeInput is the input for burp_method_probe.
+type MethodProbeInput struct {
+	Raw             string   `json:"raw" jsonschema:"required,Raw HTTP request for the endpoint under test"`
+	Methods         []string `json:"methods,omitempty" jsonschema:"Extra verbs to try besides the built-in list"`
+	OverrideMethods []string `json:"overrideMethods,omitempty" jsonschema:"Verbs to tunnel through POST with X-HTTP-Method-Override and similar headers (default: PUT, DELETE, PATCH)"`
+	Host            string   `json:"host,omitempty" jsonschema:"Target host (overrides Host header)"`
+	Port            int      `json:"port,omitempty" jsonschema:"Target port (default based on TLS)"`
+	TLS             *bool    `json:"tls,omitempty" jsonschema:"Use HTTPS (default true)"`
+	HeaderProfile   string   `json:"headerProfile,omitempty" jsonschema:"Header rule profile from config (default: 'default')"`
+	Instance        string   `json:"instance,omitempty" jsonschema:"Named Burp instance from config (default: the --burp-url connection)"`
+}
+
+// MethodCheck is one row of the method table.
+type MethodCheck struct {
+	Method     string `json:"method"`
+	Override   string `json:"override,omitempty"`
+	StatusCode int    `json:"statusCode,omitempty"`
+	Length     int    `json:"length"`
+	Allow      string `json:"allow,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+// MethodProbeOutput is the output of burp_method_probe.
+type MethodProbeOutput struct {
+	Original string        `json:"original"`
+	Checks   []MethodCheck `json:"checks"`
+	Findings []string      `json:"findings"`
+}
+
+// withMethod rewrites the request verb, adding the override header if set.
+// Bodyless requests that now use a body method get Content-Length: 0 so
+// servers don't answer 411.
+func withMethod(rawNorm, method, overrideHeader, override string) (string, error) {
+	r, err := splitRawRequest(rawNorm)
+	if err != nil {
+		return "", err
+	}
+	r.method = method
+	if overrideHeader != "" {
+		r.setHeader(overrideHeader, override)
+	}
+	if r.body == "" && slices.Contains([]string{"POST", "PUT", "PATCH"}, method) {
+		r.setHeader("Content-Length", "0")
+	}
+	return r.String(), nil
+}
+
+func methodProbeHandler(client *burp.Client) func(context.Context, *mcp.CallToolRequest, MethodProbeInput) (*mcp.CallToolResult, MethodProbeOutput, error) {
+	return func(ctx context.Context, _ *mcp.CallToolRequest, input MethodProbeInput) (*mcp.CallToolResult, MethodProbeOutput, error) {
+		ctx = burp.WithInstance(ctx, input.Instance)
+
+		rawNorm, parsed, err := prepareRequest(input.Raw, input.HeaderProfile)
+		if err != nil {
+			return nil, MethodProbeOutput{}, err
+		}
+		t, err := resolveTarget(input.Host, input.Port, input.TLS, parsed.Host)
+		if err != nil {
+			return nil, MethodProbeOutput{}, err
+		}
+
+		overrides := input.OverrideMethods
+		if len(overrides) == 0 {
+			overrides = []string{"PUT", "DELETE", "PATCH"}
+		}
+		if len(input.Methods) > maxExtraMethods || len(overrides) > maxOverrideMethods {
+			return nil, MethodProbeOutput{}, fmt.Errorf("max %d methods and %d overrideMethods", maxExtraMethods, maxOverrideMethods)
+		}
+		type row struct{ method, header, override string }
+		var rows []row
+		for _, m := range append(slices.Clone(probeMethods), input.Methods...) {
+			if strings.TrimSpace(m) == "" || strings.ContainsAny(m, " \r\n") {
+				return nil, MethodProbeOutput{}, fmt.Errorf("invalid method %q", m)
+			}
+			rows = append(rows, row{method: m})
+		}
+		for _, m := range overrides {
+			for _, h := range methodOverrideHeaders {
+				rows = append(rows, row{"POST", h, m})
+			}
+		}
+
+		checks := make([]MethodCheck, len(rows))
+		parallel(len(rows), func(i int) {
+			r := rows[i]
+			checks[i] = MethodCheck{Method: r.method}
+			if r.header != "" {
+				checks[i].Override = r.header + ": " + r.override
+			}
+			req, err := withMethod(rawNorm, r.method, r.header, r.override)
+			if err != nil {
+				checks[i].Error = err.Error()
+				return
+			}
+			resp, err := sendParsed(ctx, client, req, t, 1)
+			if err != nil {
+				checks[i].Error = err.Error()
+				return
+			}
+			checks[i].StatusCode = resp.StatusCode
+			checks[i].Length = resp.BodySize
+			checks[i].Allow = burp.GetHeader(resp.Headers, "Allow")
+		})
+
+		return nil, MethodProbeOutput{
+			Original: parsed.Method,
+			Checks:   checks,
+			Findings: methodFindings(parsed.Method, checks),
+		}, nil
+	}
+}
+
+// methodFindings flags dangerous methods the server accepts, TRACE, verb
+// tampering, and override headers that change the outcome.
+func methodFindings(original string, checks []MethodCheck) []string {
+	findings := []string{}
+	plain := map[string]MethodCheck{}
+	for _, c := range checks {
+		if c.Override == "" && c.Error == "" {
+			plain[c.Method] = c
+		}
+	}
+	ok := func(c MethodCheck) bool { return c.StatusCode >= 200 && c.StatusCode < 300 }
+
+	if c, found := plain["OPTIONS"]; found && c.Allow != "" {
+		var risky []string
+		for _, m := range strings.Split(c.Allow, ",") {
+			if m = strings.ToUpper(strings.TrimSpace(m)); slices.Contains(dangerousMethods, m) {
+				risky = append(risky, m)
+			}
+		}
+		if len(risky) > 0 {
+			findings = append(findings, fmt.Sprintf("OPTIONS advertises %s (Allow: %s)", strings.Join(risky, ", "), c.Allow))
+		}
+	}
+	for _, m := range dangerousMethods {
+		c, found := plain[m]
+		if !found || !ok(c) {
+			continue
+		}
+		if m == "TRACE" {
+			findings = append(findings, fmt.Sprintf("TRACE returns %d: the request may be echoed back, including cookies and auth headers (cross-site tracing)", c.StatusCode))
+			continue
+		}
+		findings = append(findings, fmt.Sprintf("%s returns %d (%d bytes): confirm whether it changes state", m, c.StatusCode, c.Length))
+	}
+
+	base, hasBase := plain[strings.ToUpper(original)]
+	for _, m := range []string{"FOOBAR", "get"} {
+		c, found := plain[m]
+		if found && hasBase && c.StatusCode == base.StatusCode && c.Length == base.Length {
+			findings = append(findings, fmt.Sprintf("%s is handled like %s (%d, %d bytes): arbitrary verbs fall through to the default handler, so method-based access rules may be bypassed", m, base.Method, c.StatusCode, c.Length))
+		}
+	}
+	if get, found := plain["GET"]; found && get.StatusCode >= 400 {
+		if head, found := plain["HEAD"]; found && ok(head) {
+			findings = append(findings, fmt.Sprintf("GET is refused (%d) but HEAD succeeds (%d): HEAD may skip the access check while still running the handler", get.StatusCode, head.StatusCode))
+		}
+	}
+
+	post, hasPost := plain["POST"]
+	for _, c := range checks {
+		if c.Override == "" || c.Error != "" || !hasPost {
+			continue
+		}
+		_, verb, _ := strings.Cut(c.Override, ": ")
+		if c.StatusCode != post.StatusCode || c.Length != post.Length {
+			target, found := plain[verb]
+			detail := ""
+			if found && c.StatusCode == target.StatusCode {
+				detail = fmt.Sprintf(", matching a real %s", verb)
+			}
+			findings = append(findings, fmt.Sprintf("POST with %s returns %d (%d bytes) vs %d for plain POST%s: the override header is honored", c.Override, c.StatusCode, c.Length, post.StatusCode, detail))
+		}
+	}
+	if len(findings) == 0 {
+		findings = append(findings, "No dangerous methods, verb tampering, or honored override headers")
+	}
+	return findings
+}
+
+// RegisterMethodProbeTool registers the burp_method_probe tool.
+func RegisterMethodProbeTool(server *mcp.Server, client *burp.Client) {
+	addTool(server, &mcp.Tool{
+		Name: "burp_method_probe",
+		Description: `Replay a request with GET, HEAD, POST, OPTIONS, PUT, DELETE, PATCH, TRACE, CONNECT, PROPFIND, arbitrary verbs, and POST with X-HTTP-Method-Override style headers. ` +
+			`Flags dangerous methods that succeed, TRACE, verb tampering, and honored override headers. ` +
+			`Returns {original, checks: [{method, override, statusCode, length, allow}], findings}.`,
+	}, methodProbeHandler(client))
+}
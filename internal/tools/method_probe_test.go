@@ -0,0 +1,52 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWithMethod(t *testing.T) {
+	raw := "GET /api/item/1 HTTP/1.1\r\nHost: a.com\r\n\r\n"
+	got, err := withMethod(raw, "POST", "X-HTTP-Method-Override", "DELETE")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "POST /api/item/1 HTTP/1.1\r\nHost: a.com\r\nX-HTTP-Method-Override: DELETE\r\nContent-Length: 0\r\n\r\n"
+	if got != want {
+		t.Errorf("got %q\nwant %q", got, want)
+	}
+	if got, _ := withMethod(raw, "TRACE", "", ""); got != "TRACE /api/item/1 HTTP/1.1\r\nHost: a.com\r\n\r\n" {
+		t.Errorf("got %q", got)
+	}
+}
+
+func TestMethodFindings(t *testing.T) {
+	checks := []MethodCheck{
+		{Method: "GET", StatusCode: 403, Length: 10},
+		{Method: "HEAD", StatusCode: 200},
+		{Method: "POST", StatusCode: 405, Length: 20},
+		{Method: "OPTIONS", StatusCode: 204, Allow: "GET, HEAD, PUT, DELETE"},
+		{Method: "PUT", StatusCode: 201, Length: 0},
+		{Method: "DELETE", StatusCode: 405, Length: 20},
+		{Method: "TRACE", StatusCode: 200, Length: 120},
+		{Method: "FOOBAR", StatusCode: 403, Length: 10},
+		{Method: "POST", Override: "X-HTTP-Method-Override: PUT", StatusCode: 201},
+		{Method: "POST", Override: "X-HTTP-Method: DELETE", StatusCode: 405, Length: 20},
+	}
+	got := strings.Join(methodFindings("GET", checks), "\n")
+	for _, want := range []string{
+		"OPTIONS advertises PUT, DELETE",
+		"PUT returns 201",
+		"TRACE returns 200",
+		"FOOBAR is handled like GET",
+		"GET is refused (403) but HEAD succeeds (200)",
+		"POST with X-HTTP-Method-Override: PUT returns 201 (0 bytes) vs 405 for plain POST, matching a real PUT",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("missing %q in:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "DELETE returns") || strings.Contains(got, "X-HTTP-Method: DELETE") {
+		t.Errorf("unexpected DELETE finding:\n%s", got)
+	}
+}
@@ -0,0 +1,63 @@
+package tools
+
+import (
+	"context"
+	"time"
+
+	"github.com/c0tton-fluff/burp-mcp-server/internal/metrics"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+var serverStarted = time.Now()
+
+var (
+	toolCallsMetric      = metrics.Default.NewCounterVec(metrics.ToolCalls, "Tool calls by tool and outcome.", "tool", "outcome")
+	toolErrorsMetric     = metrics.Default.NewCounterVec(metrics.ToolErrors, "Failed tool calls by tool and error code.", "tool", "code")
+	toolDurationMetric   = metrics.Default.NewHistogramVec(metrics.ToolDuration, "Tool call latency in seconds.", metrics.DefaultBuckets, "tool")
+	targetRequestsMetric = metrics.Default.NewCounterVec(metrics.TargetRequests, "Requests sent per target origin.", "target")
+	bytesSentMetric      = metrics.Default.NewCounterVec(metrics.TargetBytesSent, "Request bytes sent per target origin.", "target")
+	bytesReceivedMetric  = metrics.Default.NewCounterVec(metrics.TargetBytesReceived, "Response bytes received per target origin.", "target")
+	_                    = metrics.Default.NewGaugeFunc(metrics.StartTime, "Unix time the server started.", func() float64 {
+		return float64(serverStarted.Unix())
+	})
+)
+
+// MetricsMiddleware records the count, latency, and error code of every
+// tool call. The code comes from the error slot addTool fills, not the
+// envelope, which SchemaVersionMiddleware re-encodes on the way out.
+func MetricsMiddleware() mcp.Middleware {
+	return func(next mcp.MethodHandler) mcp.MethodHandler {
+		return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+			params, ok := req.GetParams().(*mcp.CallToolParamsRaw)
+			if !ok || method != "tools/call" {
+				return next(ctx, method, req)
+			}
+			ctx, slot := withErrorSlot(ctx)
+			start := time.Now()
+			result, err := next(ctx, method, req)
+			toolDurationMetric.Observe(time.Since(start).Seconds(), params.Name)
+
+			code := ""
+			if res, ok := result.(*mcp.CallToolResult); err != nil {
+				code = "protocol_error"
+			} else if ok && res.IsError {
+				code = CodeToolError
+				if slot.err != nil {
+					code = slot.err.Code
+				}
+			}
+			if code == "" {
+				toolCallsMetric.Add(1, params.Name, "ok")
+			} else {
+				toolCallsMetric.Add(1, params.Name, "error")
+				toolErrorsMetric.Add(1, params.Name, code)
+			}
+			return result, err
+		}
+	}
+}
+
+// recordResponse counts n response bytes received from t.
+func recordResponse(t resolvedTarget, n int) {
+	bytesReceivedMetric.Add(float64(n), t.origin())
+}
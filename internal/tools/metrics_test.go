@@ -0,0 +1,89 @@
+package tools
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/c0tton-fluff/burp-mcp-server/internal/metrics"
+	"github.com/c0tton-fluff/burp-mcp-server/internal/store"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestMetricsMiddleware(t *testing.T) {
+	st, err := store.Open(filepath.Join(t.TempDir(), "store.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The serve chain: SchemaVersionMiddleware re-encodes the error
+	// envelope between ErrorMiddleware and MetricsMiddleware.
+	server := mcp.NewServer(&mcp.Implementation{Name: "test"}, nil)
+	server.AddReceivingMiddleware(Middleware(st)...)
+	type in struct {
+		Fail bool `json:"fail,omitempty"`
+	}
+	addTool(server, &mcp.Tool{Name: "metrics_test_tool"}, func(_ context.Context, _ *mcp.CallToolRequest, in in) (*mcp.CallToolResult, any, error) {
+		if in.Fail {
+			return nil, nil, &ScopeError{Host: "evil.test"}
+		}
+		return nil, map[string]bool{"ok": true}, nil
+	})
+
+	ct, sTransport := mcp.NewInMemoryTransports()
+	ctx := context.Background()
+	ss, err := server.Connect(ctx, sTransport, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ss.Close()
+	cs, err := mcp.NewClient(&mcp.Implementation{Name: "client"}, nil).Connect(ctx, ct, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cs.Close()
+	for _, fail := range []bool{false, false, true} {
+		if _, err := cs.CallTool(ctx, &mcp.CallToolParams{Name: "metrics_test_tool", Arguments: map[string]any{"fail": fail}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var buf bytes.Buffer
+	metrics.Default.WriteText(&buf)
+	samples, err := metrics.Parse(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got *metrics.ToolStats
+	for _, ts := range metrics.Summarize(samples).Tools {
+		if ts.Tool == "metrics_test_tool" {
+			got = &ts
+		}
+	}
+	if got == nil || got.Calls != 3 || got.Errors != 1 || got.ErrorCodes[CodeScopeViolation] != 1 {
+		t.Errorf("stats = %+v", got)
+	}
+}
+
+func TestRecordRequests_Bytes(t *testing.T) {
+	target := resolvedTarget{Host: "metrics.test", Port: 8443, UseTLS: true}
+	const raw = "GET / HTTP/1.1\r\nHost: metrics.test\r\n\r\n"
+	recordRequests(context.Background(), target, raw, 2)
+	recordResponse(target, 10)
+
+	var buf bytes.Buffer
+	metrics.Default.WriteText(&buf)
+	samples, err := metrics.Parse(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, ts := range metrics.Summarize(samples).Targets {
+		if ts.Target == "https://metrics.test:8443" {
+			if ts.Requests != 2 || ts.BytesSent != int64(2*len(raw)) || ts.BytesReceived != 10 {
+				t.Errorf("target stats = %+v", ts)
+			}
+			return
+		}
+	}
+	t.Error("target not recorded")
+}
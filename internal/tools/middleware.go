@@ -0,0 +1,20 @@
+package tools
+
+import (
+	"github.com/c0tton-fluff/burp-mcp-server/internal/store"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// Middleware returns the server's receiving middleware, outermost first.
+// ErrorMiddleware is innermost so every other layer sees the error envelope.
+func Middleware(st *store.Store) []mcp.Middleware {
+	return []mcp.Middleware{
+		ActivityMiddleware(),
+		CoverageMiddleware(st),
+		MetricsMiddleware(),
+		ProgressMiddleware(),
+		OutputCapMiddleware(),
+		SchemaVersionMiddleware(),
+		ErrorMiddleware(),
+	}
+}
@@ -0,0 +1,277 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// ModifyRequestInput is the input for burp_modify_request.
+type ModifyRequestInput struct {
+	Raw string `json:"raw" jsonschema:"required,Raw HTTP request to modify"`
+
+	Method      string `json:"method,omitempty" jsonschema:"New request method"`
+	Path        string `json:"path,omitempty" jsonschema:"New path, keeping the query string unless it includes one"`
+	HTTPVersion string `json:"httpVersion,omitempty" jsonschema:"New protocol version: HTTP/1.0, HTTP/1.1, or HTTP/2"`
+
+	SetQuery    map[string]string `json:"setQuery,omitempty" jsonschema:"Query parameters to set or add, values written verbatim (encode them yourself)"`
+	RemoveQuery []string          `json:"removeQuery,omitempty" jsonschema:"Query parameters to remove"`
+
+	RenameHeaders map[string]string `json:"renameHeaders,omitempty" jsonschema:"Headers to rename, old name to new name"`
+	RemoveHeaders []string          `json:"removeHeaders,omitempty" jsonschema:"Headers to remove (every occurrence)"`
+	SetHeaders    map[string]string `json:"setHeaders,omitempty" jsonschema:"Headers to set in place, replacing every occurrence, or append if absent"`
+
+	Body      *string        `json:"body,omitempty" jsonschema:"New body, replacing the old one"`
+	JSONMerge map[string]any `json:"jsonMerge,omitempty" jsonschema:"JSON merge patch (RFC 7386) applied to a JSON object body; null deletes a key"`
+
+	KeepContentLength bool `json:"keepContentLength,omitempty" jsonschema:"Leave Content-Length alone after a body change (for desync testing)"`
+}
+
+// ModifyRequestOutput is the output of burp_modify_request.
+type ModifyRequestOutput struct {
+	Raw     string   `json:"raw"`
+	Changes []string `json:"changes"`
+}
+
+// rawRequest is a request split into editable pieces. Line endings in the
+// head are normalized to CRLF; the body is kept byte for byte.
+type rawRequest struct {
+	method, target, version string
+	headers                 []string
+	body                    string
+}
+
+func splitRawRequest(raw string) (*rawRequest, error) {
+	head, body, found := strings.Cut(raw, "\r\n\r\n")
+	if lfHead, lfBody, ok := strings.Cut(raw, "\n\n"); ok && (!found || len(lfHead) < len(head)) {
+		head, body = lfHead, lfBody
+	}
+	lines := strings.Split(strings.TrimRight(strings.ReplaceAll(head, "\r\n", "\n"), "\n"), "\n")
+	fields := strings.Fields(lines[0])
+	if len(fields) < 2 {
+		return nil, fmt.Errorf("malformed request line %q", lines[0])
+	}
+	r := &rawRequest{method: fields[0], target: fields[1], headers: lines[1:], body: body}
+	if len(fields) > 2 {
+		r.version = fields[2]
+	}
+	return r, nil
+}
+
+func (r *rawRequest) String() string {
+	line := r.method + " " + r.target
+	if r.version != "" {
+		line += " " + r.version
+	}
+	return strings.Join(append([]string{line}, r.headers...), "\r\n") + "\r\n\r\n" + r.body
+}
+
+// headerName returns the name of a header line, or "" for a malformed one.
+func headerName(line string) string {
+	name, _, ok := strings.Cut(line, ":")
+	if !ok {
+		return ""
+	}
+	return strings.TrimSpace(name)
+}
+
+// setHeader replaces the first occurrence of name in place and drops the
+// rest, or appends the header if it is absent. It reports whether name was present.
+func (r *rawRequest) setHeader(name, value string) bool {
+	var kept []string
+	found := false
+	for _, line := range r.headers {
+		if !strings.EqualFold(headerName(line), name) {
+			kept = append(kept, line)
+			continue
+		}
+		if !found {
+			kept = append(kept, name+": "+value)
+			found = true
+		}
+	}
+	if !found {
+		kept = append(kept, name+": "+value)
+	}
+	r.headers = kept
+	return found
+}
+
+// removeHeader drops every occurrence of name and returns how many there were.
+func (r *rawRequest) removeHeader(name string) int {
+	n := len(r.headers)
+	r.headers = slices.DeleteFunc(r.headers, func(line string) bool {
+		return strings.EqualFold(headerName(line), name)
+	})
+	return n - len(r.headers)
+}
+
+// renameHeader renames every occurrence of from, keeping values and positions.
+func (r *rawRequest) renameHeader(from, to string) int {
+	n := 0
+	for i, line := range r.headers {
+		if strings.EqualFold(headerName(line), from) {
+			_, value, _ := strings.Cut(line, ":")
+			r.headers[i] = to + ":" + value
+			n++
+		}
+	}
+	return n
+}
+
+// setQuery sets raw query parameters, keeping the order and encoding of the
+// others. An existing parameter keeps its position; new ones are appended.
+func setQuery(query string, set map[string]string, remove []string) string {
+	var params []string
+	if query != "" {
+		params = strings.Split(query, "&")
+	}
+	matches := func(param, name string) bool {
+		key, _, _ := strings.Cut(param, "=")
+		if k, err := url.QueryUnescape(key); err == nil {
+			key = k
+		}
+		return key == name
+	}
+	for _, name := range remove {
+		params = slices.DeleteFunc(params, func(p string) bool { return matches(p, name) })
+	}
+	for _, name := range slices.Sorted(maps.Keys(set)) {
+		i := slices.IndexFunc(params, func(p string) bool { return matches(p, name) })
+		if i < 0 {
+			params = append(params, name+"="+set[name])
+		} else {
+			params[i] = name + "=" + set[name]
+		}
+	}
+	return strings.Join(params, "&")
+}
+
+// mergePatch applies an RFC 7386 JSON merge patch to target.
+func mergePatch(target, patch map[string]any) map[string]any {
+	if target == nil {
+		target = make(map[string]any)
+	}
+	for k, v := range patch {
+		if v == nil {
+			delete(target, k)
+			continue
+		}
+		if p, ok := v.(map[string]any); ok {
+			t, _ := target[k].(map[string]any)
+			target[k] = mergePatch(t, p)
+			continue
+		}
+		target[k] = v
+	}
+	return target
+}
+
+func modifyRequestHandler() func(context.Context, *mcp.CallToolRequest, ModifyRequestInput) (*mcp.CallToolResult, ModifyRequestOutput, error) {
+	return func(_ context.Context, _ *mcp.CallToolRequest, input ModifyRequestInput) (*mcp.CallToolResult, ModifyRequestOutput, error) {
+		if err := validateRawRequest(input.Raw); err != nil {
+			return nil, ModifyRequestOutput{}, err
+		}
+		if input.Body != nil && input.JSONMerge != nil {
+			return nil, ModifyRequestOutput{}, fmt.Errorf("body and jsonMerge are mutually exclusive")
+		}
+		switch input.HTTPVersion {
+		case "", "HTTP/1.0", "HTTP/1.1", "HTTP/2":
+		default:
+			return nil, ModifyRequestOutput{}, fmt.Errorf("httpVersion must be HTTP/1.0, HTTP/1.1, or HTTP/2")
+		}
+
+		r, err := splitRawRequest(input.Raw)
+		if err != nil {
+			return nil, ModifyRequestOutput{}, err
+		}
+		var changes []string
+
+		if input.Method != "" {
+			changes = append(changes, fmt.Sprintf("method %s -> %s", r.method, input.Method))
+			r.method = input.Method
+		}
+		if input.HTTPVersion != "" {
+			changes = append(changes, fmt.Sprintf("version %s -> %s", r.version, input.HTTPVersion))
+			r.version = input.HTTPVersion
+		}
+		path, query, hasQuery := strings.Cut(r.target, "?")
+		if input.Path != "" {
+			changes = append(changes, fmt.Sprintf("path %s -> %s", path, input.Path))
+			if p, q, ok := strings.Cut(input.Path, "?"); ok {
+				path, query, hasQuery = p, q, true
+			} else {
+				path = input.Path
+			}
+		}
+		if len(input.SetQuery) > 0 || len(input.RemoveQuery) > 0 {
+			query = setQuery(query, input.SetQuery, input.RemoveQuery)
+			hasQuery = query != ""
+			changes = append(changes, "query "+query)
+		}
+		r.target = path
+		if hasQuery {
+			r.target += "?" + query
+		}
+
+		for _, from := range slices.Sorted(maps.Keys(input.RenameHeaders)) {
+			n := r.renameHeader(from, input.RenameHeaders[from])
+			changes = append(changes, fmt.Sprintf("renamed %s -> %s (%d)", from, input.RenameHeaders[from], n))
+		}
+		for _, name := range input.RemoveHeaders {
+			changes = append(changes, fmt.Sprintf("removed %s (%d)", name, r.removeHeader(name)))
+		}
+		for _, name := range slices.Sorted(maps.Keys(input.SetHeaders)) {
+			verb := "added"
+			if r.setHeader(name, input.SetHeaders[name]) {
+				verb = "set"
+			}
+			changes = append(changes, verb+" "+name)
+		}
+
+		bodyChanged := false
+		if input.Body != nil {
+			r.body, bodyChanged = *input.Body, true
+			changes = append(changes, fmt.Sprintf("body replaced (%d bytes)", len(r.body)))
+		}
+		if input.JSONMerge != nil {
+			var doc map[string]any
+			if strings.TrimSpace(r.body) != "" {
+				if err := json.Unmarshal([]byte(r.body), &doc); err != nil {
+					return nil, ModifyRequestOutput{}, fmt.Errorf("jsonMerge needs a JSON object body: %w", err)
+				}
+			}
+			merged, err := json.Marshal(mergePatch(doc, input.JSONMerge))
+			if err != nil {
+				return nil, ModifyRequestOutput{}, err
+			}
+			r.body, bodyChanged = string(merged), true
+			changes = append(changes, fmt.Sprintf("body JSON-merged (%d bytes)", len(r.body)))
+		}
+
+		out := r.String()
+		if bodyChanged && !input.KeepContentLength {
+			out = fixContentLength(out)
+		}
+		if changes == nil {
+			changes = []string{"no changes"}
+		}
+		return nil, ModifyRequestOutput{Raw: out, Changes: changes}, nil
+	}
+}
+
+// RegisterModifyRequestTool registers the burp_modify_request tool.
+func RegisterModifyRequestTool(server *mcp.Server) {
+	addTool(server, &mcp.Tool{
+		Name: "burp_modify_request",
+		Description: `Apply a declarative patch to a raw request locally and return the new request. ` +
+			`Params: raw, method, path, httpVersion, setQuery, removeQuery, renameHeaders, removeHeaders, setHeaders, body, jsonMerge (RFC 7386). ` +
+			`Content-Length follows body changes unless keepContentLength. Returns {raw, changes}.`,
+	}, modifyRequestHandler())
+}
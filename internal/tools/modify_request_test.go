@@ -0,0 +1,75 @@
+package tools
+
+import (
+	"context"
+	"testing"
+)
+
+func TestModifyRequestHandler(t *testing.T) {
+	handler := modifyRequestHandler()
+	body := `{"user":{"id":1,"role":"user"},"debug":true}`
+	_, out, err := handler(context.Background(), nil, ModifyRequestInput{
+		Raw: "POST /api/users?id=1&sort=asc HTTP/1.1\n" +
+			"Host: shop.example\n" +
+			"X-Old: a\n" +
+			"Cookie: s=1\n" +
+			"Content-Type: application/json\n" +
+			"Content-Length: 45\n\n" + body,
+		Method:        "PUT",
+		HTTPVersion:   "HTTP/1.0",
+		Path:          "/api/admin",
+		SetQuery:      map[string]string{"id": "2", "new": "%27"},
+		RemoveQuery:   []string{"sort"},
+		RenameHeaders: map[string]string{"X-Old": "X-New"},
+		RemoveHeaders: []string{"cookie"},
+		SetHeaders:    map[string]string{"Host": "admin.example", "X-Forwarded-For": "127.0.0.1"},
+		JSONMerge:     map[string]any{"user": map[string]any{"role": "admin"}, "debug": nil},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "PUT /api/admin?id=2&new=%27 HTTP/1.0\r\n" +
+		"Host: admin.example\r\n" +
+		"X-New: a\r\n" +
+		"Content-Type: application/json\r\n" +
+		"Content-Length: 32\r\n" +
+		"X-Forwarded-For: 127.0.0.1\r\n\r\n" +
+		`{"user":{"id":1,"role":"admin"}}`
+	if out.Raw != want {
+		t.Errorf("got\n%q\nwant\n%q", out.Raw, want)
+	}
+	if len(out.Changes) != 9 {
+		t.Errorf("changes = %q", out.Changes)
+	}
+}
+
+func TestModifyRequestHandler_KeepContentLength(t *testing.T) {
+	handler := modifyRequestHandler()
+	body := "0\r\n\r\nGET /admin HTTP/1.1\r\n\r\n"
+	_, out, err := handler(context.Background(), nil, ModifyRequestInput{
+		Raw:               "POST / HTTP/1.1\r\nHost: a\r\nContent-Length: 4\r\n\r\nx=1",
+		Body:              &body,
+		KeepContentLength: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out.Raw != "POST / HTTP/1.1\r\nHost: a\r\nContent-Length: 4\r\n\r\n"+body {
+		t.Errorf("got %q", out.Raw)
+	}
+}
+
+func TestModifyRequestHandler_Errors(t *testing.T) {
+	handler := modifyRequestHandler()
+	body := "x"
+	for name, in := range map[string]ModifyRequestInput{
+		"body and merge": {Raw: "GET / HTTP/1.1\r\n\r\n", Body: &body, JSONMerge: map[string]any{"a": 1}},
+		"bad version":    {Raw: "GET / HTTP/1.1\r\n\r\n", HTTPVersion: "HTTP/3"},
+		"non-JSON merge": {Raw: "POST / HTTP/1.1\r\n\r\na=b", JSONMerge: map[string]any{"a": 1}},
+		"bad line":       {Raw: "garbage\r\n\r\n"},
+	} {
+		if _, _, err := handler(context.Background(), nil, in); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
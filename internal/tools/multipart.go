@@ -0,0 +1,122 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/c0tton-fluff/burp-mcp-server/internal/burp"
+	"github.com/c0tton-fluff/burp-mcp-server/internal/config"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// partPreviewBytes is how much of a text part's content a summary shows.
+const partPreviewBytes = 120
+
+// MultipartPartSummary describes one part of a multipart/form-data body.
+type MultipartPartSummary struct {
+	Name        string `json:"name"`
+	Filename    string `json:"filename,omitempty"`
+	ContentType string `json:"contentType,omitempty"`
+	Size        int    `json:"size"`
+	Preview     string `json:"preview,omitempty"`
+	FileType    string `json:"fileType,omitempty"`
+}
+
+// summarizeParts previews text parts and identifies binary ones by magic bytes.
+func summarizeParts(parts []burp.MultipartPart) []MultipartPartSummary {
+	var out []MultipartPartSummary
+	for _, p := range parts {
+		s := MultipartPartSummary{Name: p.Name, Filename: p.Filename, ContentType: p.ContentType, Size: len(p.Data)}
+		if burp.IsBinary(p.Data) {
+			s.FileType = burp.SniffFileType(p.Data)
+		} else {
+			s.Preview = cutBody(p.Data, partPreviewBytes)
+		}
+		out = append(out, s)
+	}
+	return out
+}
+
+// MultipartPartInput is one part for burp_build_multipart.
+type MultipartPartInput struct {
+	Name        string `json:"name" jsonschema:"required,Form field name"`
+	Filename    string `json:"filename,omitempty" jsonschema:"Filename for file parts, written verbatim (quotes, traversal, null bytes allowed)"`
+	ContentType string `json:"contentType,omitempty" jsonschema:"Part Content-Type"`
+	Content     string `json:"content,omitempty" jsonschema:"Part content"`
+	Encoding    string `json:"encoding,omitempty" jsonschema:"How content is encoded: utf8 (default), base64, or hex"`
+}
+
+// BuildMultipartInput is the input for burp_build_multipart.
+type BuildMultipartInput struct {
+	Parts []MultipartPartInput `json:"parts" jsonschema:"required,Parts in order"`
+	Raw   string               `json:"raw,omitempty" jsonschema:"Raw request to put the body into; its Content-Type and Content-Length are replaced"`
+}
+
+// BuildMultipartOutput is the output of burp_build_multipart.
+type BuildMultipartOutput struct {
+	ContentType  string `json:"contentType"`
+	Body         string `json:"body"`
+	BodyEncoding string `json:"bodyEncoding"`
+	Raw          string `json:"raw,omitempty"`
+	Note         string `json:"note,omitempty"`
+}
+
+func buildMultipartHandler() func(context.Context, *mcp.CallToolRequest, BuildMultipartInput) (*mcp.CallToolResult, BuildMultipartOutput, error) {
+	return func(_ context.Context, _ *mcp.CallToolRequest, input BuildMultipartInput) (*mcp.CallToolResult, BuildMultipartOutput, error) {
+		if len(input.Parts) == 0 {
+			return nil, BuildMultipartOutput{}, fmt.Errorf("parts is required")
+		}
+		parts := make([]burp.MultipartPart, len(input.Parts))
+		for i, p := range input.Parts {
+			if p.Name == "" {
+				return nil, BuildMultipartOutput{}, fmt.Errorf("parts[%d]: name is required", i)
+			}
+			data, err := decodeContent(p.Content, p.Encoding)
+			if err != nil {
+				return nil, BuildMultipartOutput{}, fmt.Errorf("parts[%d]: %w", i, err)
+			}
+			parts[i] = burp.MultipartPart{Name: p.Name, Filename: p.Filename, ContentType: p.ContentType, Data: string(data)}
+		}
+
+		body, contentType, err := burp.BuildMultipart(parts)
+		if err != nil {
+			return nil, BuildMultipartOutput{}, err
+		}
+		var env BodyEnvelope
+		out := BuildMultipartOutput{ContentType: contentType}
+		out.Body = encodeBody(&env, body, "", 0, encodingAuto, false)
+		out.BodyEncoding = env.BodyEncoding
+
+		if input.Raw != "" {
+			if env.BodyEncoding != encodingUTF8 {
+				out.Note = "the body has binary parts, which a raw request string can't carry; raw omitted"
+				return nil, out, nil
+			}
+			if err := validateRawRequest(input.Raw); err != nil {
+				return nil, BuildMultipartOutput{}, err
+			}
+			out.Raw = replaceBody(input.Raw, body, contentType)
+		}
+		return nil, out, nil
+	}
+}
+
+// replaceBody swaps the body of raw for body, setting Content-Type to
+// contentType and Content-Length to the new body's size.
+func replaceBody(raw, body, contentType string) string {
+	rawNorm := normalizeRawRequest(raw)
+	head, _, _ := strings.Cut(rawNorm, "\r\n\r\n")
+	head = applyHeaderRules(head+"\r\n\r\n", config.HeaderRules{Set: map[string]string{"Content-Type": contentType}})
+	return fixContentLength(head + body)
+}
+
+// RegisterBuildMultipartTool registers the burp_build_multipart tool.
+func RegisterBuildMultipartTool(server *mcp.Server) {
+	addTool(server, &mcp.Tool{
+		Name: "burp_build_multipart",
+		Description: `Build a multipart/form-data body from parts with a fresh boundary, locally. ` +
+			`Params: parts [{name, filename, contentType, content, encoding}], raw (optional request to put the body into). ` +
+			`Returns {contentType, body, bodyEncoding, raw}. For file-upload testing.`,
+	}, buildMultipartHandler())
+}
@@ -0,0 +1,64 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/c0tton-fluff/burp-mcp-server/internal/burp"
+)
+
+func TestBuildMultipartHandler(t *testing.T) {
+	handler := buildMultipartHandler()
+	_, out, err := handler(context.Background(), nil, BuildMultipartInput{
+		Parts: []MultipartPartInput{
+			{Name: "title", Content: "holiday"},
+			{Name: "file", Filename: "shell.php", ContentType: "image/png", Content: "<?php echo 1; ?>"},
+		},
+		Raw: "POST /upload HTTP/1.1\r\nHost: shop.example\r\nContent-Type: application/x-www-form-urlencoded\r\nContent-Length: 3\r\n\r\na=b",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out.BodyEncoding != "utf8" || !strings.Contains(out.Body, `filename="shell.php"`) {
+		t.Errorf("body = %q (%s)", out.Body, out.BodyEncoding)
+	}
+	parsed := burp.ParseRawRequest(out.Raw)
+	if ct := burp.GetHeader(parsed.Headers, "Content-Type"); ct != out.ContentType {
+		t.Errorf("raw Content-Type = %q, want %q", ct, out.ContentType)
+	}
+	if !strings.Contains(out.Raw, fmt.Sprintf("\r\nContent-Length: %d\r\n", len(out.Body))) || strings.Contains(out.Raw, "a=b") {
+		t.Errorf("raw not rebuilt:\n%s", out.Raw)
+	}
+	if len(parsed.Parts) != 2 {
+		t.Errorf("raw has %d parts", len(parsed.Parts))
+	}
+}
+
+func TestBuildMultipartHandler_Binary(t *testing.T) {
+	handler := buildMultipartHandler()
+	_, out, err := handler(context.Background(), nil, BuildMultipartInput{
+		Parts: []MultipartPartInput{{Name: "img", Filename: "x.png", Content: "iVBORw0KGgo=", Encoding: "base64"}},
+		Raw:   "POST /upload HTTP/1.1\r\nHost: shop.example\r\n\r\n",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out.BodyEncoding != "base64" || out.Raw != "" || out.Note == "" {
+		t.Errorf("got %+v", out)
+	}
+}
+
+func TestSummarizeParts(t *testing.T) {
+	got := summarizeParts([]burp.MultipartPart{
+		{Name: "note", Data: strings.Repeat("n", 200)},
+		{Name: "img", Filename: "a.png", Data: "\x89PNG\r\n\x1a\n\x00\x00"},
+	})
+	if len(got[0].Preview) != partPreviewBytes || got[0].Size != 200 {
+		t.Errorf("text part: %+v", got[0])
+	}
+	if got[1].Preview != "" || got[1].FileType != "image/png" {
+		t.Errorf("binary part: %+v", got[1])
+	}
+}
@@ -1,6 +1,10 @@
 package tools
 
-import "strings"
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+)
 
 // normalizeRawRequest normalizes line endings in a raw HTTP request for Burp.
 // Converts all line endings to \r\n and ensures the request ends with \r\n\r\n.
@@ -16,3 +20,30 @@ func normalizeRawRequest(raw string) string {
 	}
 	return s
 }
+
+// rawRequestText returns the request from a tool's raw or rawBase64 input
+// and whether it is sent exactly as given. rawBase64 carries bytes a JSON
+// string cannot (bare CR, NUL, invalid UTF-8) and is exact unless normalize
+// is true; raw is normalized unless normalize is false.
+func rawRequestText(raw, rawBase64 string, normalize *bool) (string, bool, error) {
+	if rawBase64 == "" {
+		return raw, normalize != nil && !*normalize, nil
+	}
+	if raw != "" {
+		return "", false, fmt.Errorf("raw and rawBase64 are mutually exclusive")
+	}
+	b, err := base64.StdEncoding.DecodeString(rawBase64)
+	if err != nil {
+		return "", false, fmt.Errorf("rawBase64: %w", err)
+	}
+	return string(b), normalize == nil || !*normalize, nil
+}
+
+// checkExactRequest rejects the options that would rewrite a request sent
+// byte for byte.
+func checkExactRequest(headerProfile, authProfile string) error {
+	if headerProfile != "" || authProfile != "" {
+		return fmt.Errorf("headerProfile and authProfile rewrite the request and cannot be used with normalize: false or rawBase64")
+	}
+	return nil
+}
@@ -49,3 +49,34 @@ func TestNormalizeRawRequest(t *testing.T) {
 		})
 	}
 }
+
+func TestRawRequestText(t *testing.T) {
+	no, yes := false, true
+	tests := []struct {
+		name      string
+		raw, b64  string
+		normalize *bool
+		want      string
+		exact     bool
+	}{
+		{"raw", "GET / HTTP/1.1\n", "", nil, "GET / HTTP/1.1\n", false},
+		{"raw exact", "GET / HTTP/1.1\n", "", &no, "GET / HTTP/1.1\n", true},
+		{"base64", "", "R0VUIC8NAA==", nil, "GET /\r\x00", true},
+		{"base64 normalized", "", "R0VUIC8NAA==", &yes, "GET /\r\x00", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, exact, err := rawRequestText(tt.raw, tt.b64, tt.normalize)
+			if err != nil || got != tt.want || exact != tt.exact {
+				t.Errorf("rawRequestText() = %q, %v, %v, want %q, %v", got, exact, err, tt.want, tt.exact)
+			}
+		})
+	}
+
+	if _, _, err := rawRequestText("GET / HTTP/1.1", "R0VU", nil); err == nil {
+		t.Error("expected error for raw and rawBase64 together")
+	}
+	if _, _, err := rawRequestText("", "not base64!", nil); err == nil {
+		t.Error("expected error for invalid base64")
+	}
+}
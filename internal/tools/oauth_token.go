@@ -0,0 +1,267 @@
+package tools
+
+import (
+	"cmp"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/c0tton-fluff/burp-mcp-server/internal/burp"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const (
+	grantClientCredentials = "client_credentials"
+	grantPassword          = "password"
+	grantRefreshToken      = "refresh_token"
+
+	defaultSessionName = "default"
+)
+
+// sessionName restricts names to what {{session:name}} can reference.
+var sessionName = regexp.MustCompile(`^[A-Za-z0-9_.-]+$`)
+
+// OAuthTokenInput is the input for burp_oauth_token.
+type OAuthTokenInput struct {
+	TokenURL        string            `json:"tokenUrl,omitempty" jsonschema:"Token endpoint URL (default for refresh_token: the session's endpoint)"`
+	GrantType       string            `json:"grantType,omitempty" jsonschema:"client_credentials (default), password, or refresh_token"`
+	ClientID        string            `json:"clientId,omitempty" jsonschema:"OAuth client ID"`
+	ClientSecret    string            `json:"clientSecret,omitempty" jsonschema:"OAuth client secret"`
+	ClientSecretEnv string            `json:"clientSecretEnv,omitempty" jsonschema:"Environment variable holding the client secret, instead of clientSecret"`
+	ClientAuth      string            `json:"clientAuth,omitempty" jsonschema:"How the client authenticates: basic (Authorization header, default) or body (client_id and client_secret form fields)"`
+	Username        string            `json:"username,omitempty" jsonschema:"Resource owner user name (password grant)"`
+	Password        string            `json:"password,omitempty" jsonschema:"Resource owner password (password grant)"`
+	PasswordEnv     string            `json:"passwordEnv,omitempty" jsonschema:"Environment variable holding the password, instead of password"`
+	RefreshToken    string            `json:"refreshToken,omitempty" jsonschema:"Refresh token (refresh_token grant; default: the session's)"`
+	Scope           string            `json:"scope,omitempty" jsonschema:"Space-separated scopes to request"`
+	Params          map[string]string `json:"params,omitempty" jsonschema:"Extra form parameters, e.g. audience or resource"`
+	Session         string            `json:"session,omitempty" jsonschema:"Session name to store the token under (default 'default')"`
+	Instance        string            `json:"instance,omitempty" jsonschema:"Named Burp instance from config (default: the --burp-url connection)"`
+}
+
+// OAuthTokenOutput is the output of burp_oauth_token. The token itself is
+// never returned; requests reference it by session.
+type OAuthTokenOutput struct {
+	Session         string `json:"session"`
+	Placeholder     string `json:"placeholder"`
+	TokenType       string `json:"tokenType,omitempty"`
+	Scope           string `json:"scope,omitempty"`
+	ExpiresIn       int    `json:"expiresIn,omitempty"`
+	ExpiresAt       string `json:"expiresAt,omitempty"`
+	HasRefreshToken bool   `json:"hasRefreshToken"`
+	TokenPrefix     string `json:"tokenPrefix"`
+}
+
+func oauthTokenHandler(client *burp.Client) func(context.Context, *mcp.CallToolRequest, OAuthTokenInput) (*mcp.CallToolResult, OAuthTokenOutput, error) {
+	return func(ctx context.Context, _ *mcp.CallToolRequest, input OAuthTokenInput) (*mcp.CallToolResult, OAuthTokenOutput, error) {
+		ctx = burp.WithInstance(ctx, input.Instance)
+
+		name := input.Session
+		if name == "" {
+			name = defaultSessionName
+		}
+		if !sessionName.MatchString(name) {
+			return nil, OAuthTokenOutput{}, fmt.Errorf("session %q: use letters, digits, '_', '.', and '-'", name)
+		}
+		prev, _ := getSession(name)
+
+		session, form, err := oauthGrant(input, prev)
+		if err != nil {
+			return nil, OAuthTokenOutput{}, err
+		}
+		u, err := url.Parse(session.TokenURL)
+		if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+			return nil, OAuthTokenOutput{}, fmt.Errorf("tokenUrl must be an absolute http(s) URL")
+		}
+
+		raw := oauthRequest(u, form, session)
+		rawNorm, _, err := prepareRequest(raw, "")
+		if err != nil {
+			return nil, OAuthTokenOutput{}, err
+		}
+		useTLS := u.Scheme == "https"
+		t, err := resolveTarget(u.Host, 0, &useTLS, "")
+		if err != nil {
+			return nil, OAuthTokenOutput{}, err
+		}
+		resp, err := sendParsed(ctx, client, rawNorm, t, 0)
+		if err != nil {
+			return nil, OAuthTokenOutput{}, err
+		}
+		if err := parseTokenResponse(resp, session, time.Now()); err != nil {
+			return nil, OAuthTokenOutput{}, err
+		}
+		if session.RefreshToken == "" && prev != nil && input.GrantType == grantRefreshToken {
+			// Servers that don't rotate refresh tokens omit them on refresh.
+			session.RefreshToken = prev.RefreshToken
+		}
+		putSession(name, session)
+
+		out := OAuthTokenOutput{
+			Session:         name,
+			Placeholder:     "{{session:" + name + "}}",
+			TokenType:       session.TokenType,
+			Scope:           session.Scope,
+			HasRefreshToken: session.RefreshToken != "",
+			TokenPrefix:     session.AccessToken[:min(len(session.AccessToken), 8)],
+		}
+		if !session.Expires.IsZero() {
+			out.ExpiresIn = int(time.Until(session.Expires).Round(time.Second).Seconds())
+			out.ExpiresAt = session.Expires.UTC().Format(time.RFC3339)
+		}
+		return nil, out, nil
+	}
+}
+
+// oauthGrant builds the session-to-be and the token request form. prev is
+// the session being replaced, whose endpoint, client, and refresh token a
+// refresh_token grant falls back to.
+func oauthGrant(input OAuthTokenInput, prev *tokenSession) (*tokenSession, url.Values, error) {
+	s := &tokenSession{
+		TokenURL:     input.TokenURL,
+		ClientID:     input.ClientID,
+		ClientSecret: input.ClientSecret,
+		ClientAuth:   input.ClientAuth,
+	}
+	if input.ClientSecretEnv != "" {
+		s.ClientSecret = os.Getenv(input.ClientSecretEnv)
+	}
+
+	grant := input.GrantType
+	if grant == "" {
+		grant = grantClientCredentials
+	}
+	form := url.Values{"grant_type": {grant}}
+	switch grant {
+	case grantClientCredentials:
+		if s.ClientID == "" {
+			return nil, nil, fmt.Errorf("clientId is required for the client_credentials grant")
+		}
+	case grantPassword:
+		password := input.Password
+		if input.PasswordEnv != "" {
+			password = os.Getenv(input.PasswordEnv)
+		}
+		if input.Username == "" {
+			return nil, nil, fmt.Errorf("username is required for the password grant")
+		}
+		form.Set("username", input.Username)
+		form.Set("password", password)
+	case grantRefreshToken:
+		refresh := input.RefreshToken
+		if prev != nil {
+			if refresh == "" {
+				refresh = prev.RefreshToken
+			}
+			if s.TokenURL == "" {
+				s.TokenURL = prev.TokenURL
+			}
+			if s.ClientID == "" {
+				s.ClientID, s.ClientSecret, s.ClientAuth = prev.ClientID, prev.ClientSecret, prev.ClientAuth
+			}
+		}
+		if refresh == "" {
+			return nil, nil, fmt.Errorf("refresh_token grant needs refreshToken or a session holding one")
+		}
+		form.Set("refresh_token", refresh)
+	default:
+		return nil, nil, fmt.Errorf("grantType %q: want client_credentials, password, or refresh_token", grant)
+	}
+	if s.TokenURL == "" {
+		return nil, nil, fmt.Errorf("tokenUrl is required")
+	}
+
+	switch s.ClientAuth {
+	case "", "basic":
+		s.ClientAuth = "basic"
+		if s.ClientSecret == "" && s.ClientID != "" {
+			// A public client has no secret to put in a header.
+			form.Set("client_id", s.ClientID)
+		}
+	case "body":
+		if s.ClientID != "" {
+			form.Set("client_id", s.ClientID)
+		}
+		if s.ClientSecret != "" {
+			form.Set("client_secret", s.ClientSecret)
+		}
+	default:
+		return nil, nil, fmt.Errorf("clientAuth %q: want basic or body", s.ClientAuth)
+	}
+	if input.Scope != "" {
+		form.Set("scope", input.Scope)
+	}
+	for k, v := range input.Params {
+		form.Set(k, v)
+	}
+	return s, form, nil
+}
+
+// oauthRequest renders the token request. With basic client auth the ID
+// and secret are form-encoded before base64, as RFC 6749 2.3.1 requires.
+func oauthRequest(u *url.URL, form url.Values, s *tokenSession) string {
+	body := form.Encode()
+	var b strings.Builder
+	fmt.Fprintf(&b, "POST %s HTTP/1.1\r\nHost: %s\r\n", u.RequestURI(), u.Host)
+	b.WriteString("Content-Type: application/x-www-form-urlencoded\r\nAccept: application/json\r\n")
+	if s.ClientAuth == "basic" && s.ClientSecret != "" {
+		creds := url.QueryEscape(s.ClientID) + ":" + url.QueryEscape(s.ClientSecret)
+		b.WriteString("Authorization: Basic " + base64.StdEncoding.EncodeToString([]byte(creds)) + "\r\n")
+	}
+	fmt.Fprintf(&b, "Content-Length: %d\r\n\r\n%s", len(body), body)
+	return b.String()
+}
+
+// parseTokenResponse fills s from a token endpoint response, or returns the
+// OAuth error it carries.
+func parseTokenResponse(resp *burp.ParsedHTTPResponse, s *tokenSession, now time.Time) error {
+	var body map[string]any
+	if err := json.Unmarshal([]byte(resp.Body), &body); err != nil {
+		return fmt.Errorf("token endpoint returned HTTP %d without a JSON body", resp.StatusCode)
+	}
+	str := func(key string) string {
+		switch v := body[key].(type) {
+		case string:
+			return v
+		case float64:
+			return strconv.FormatFloat(v, 'f', -1, 64)
+		}
+		return ""
+	}
+	if e := str("error"); e != "" || resp.StatusCode >= 300 {
+		if desc := str("error_description"); desc != "" {
+			e += ": " + desc
+		}
+		return fmt.Errorf("token endpoint returned HTTP %d: %s", resp.StatusCode, cmp.Or(e, "no access_token"))
+	}
+	s.AccessToken = str("access_token")
+	if s.AccessToken == "" {
+		return fmt.Errorf("token endpoint returned HTTP %d without an access_token", resp.StatusCode)
+	}
+	s.TokenType = str("token_type")
+	s.Scope = str("scope")
+	s.RefreshToken = str("refresh_token")
+	// expires_in is a number, though some servers send it as a string.
+	if secs, err := strconv.Atoi(str("expires_in")); err == nil && secs > 0 {
+		s.Expires = now.Add(time.Duration(secs) * time.Second)
+	}
+	return nil
+}
+
+// RegisterOAuthTokenTool registers the burp_oauth_token tool.
+func RegisterOAuthTokenTool(server *mcp.Server, client *burp.Client) {
+	addTool(server, &mcp.Tool{
+		Name: "burp_oauth_token",
+		Description: `Acquire an OAuth2 access token (client_credentials, password, or refresh_token grant) through Burp and store it in a named session. ` +
+			`Requests to any tool can then reference it as {{session:name}}, e.g. "Authorization: Bearer {{session:default}}", so the token never enters the conversation. ` +
+			`Secrets can come from clientSecretEnv and passwordEnv. A refresh_token grant on an existing session reuses its endpoint, client, and refresh token. Sessions last until the server restarts. ` +
+			`Returns {session, placeholder, tokenType, scope, expiresIn, expiresAt, hasRefreshToken, tokenPrefix}.`,
+	}, oauthTokenHandler(client))
+}
@@ -0,0 +1,115 @@
+package tools
+
+import (
+	"encoding/base64"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/c0tton-fluff/burp-mcp-server/internal/burp"
+)
+
+func resetSessions(t *testing.T) {
+	t.Helper()
+	clear(tokenSessions.m)
+	t.Cleanup(func() { clear(tokenSessions.m) })
+}
+
+func TestExpandSessions(t *testing.T) {
+	resetSessions(t)
+	putSession("api", &tokenSession{AccessToken: "tok123"})
+
+	got, err := expandSessions("GET / HTTP/1.1\r\nAuthorization: Bearer {{session:api}}\r\n\r\n")
+	if err != nil || !strings.Contains(got, "Bearer tok123\r\n") {
+		t.Errorf("expanded = %q, %v", got, err)
+	}
+	if _, err := expandSessions("X: {{session:nope}}"); err == nil || !strings.Contains(err.Error(), `"nope"`) {
+		t.Errorf("err = %v", err)
+	}
+	// Other markers are left alone.
+	if got, _ := expandSessions("{{username}}"); got != "{{username}}" {
+		t.Errorf("got %q", got)
+	}
+}
+
+func TestPrepareRequest_Session(t *testing.T) {
+	resetSessions(t)
+	putSession("default", &tokenSession{AccessToken: "abc"})
+	rawNorm, _, err := prepareRequest("GET / HTTP/1.1\nHost: x\nAuthorization: Bearer {{session:default}}\n\n", "")
+	if err != nil || !strings.Contains(rawNorm, "Bearer abc") {
+		t.Errorf("rawNorm = %q, %v", rawNorm, err)
+	}
+}
+
+func TestOAuthGrant(t *testing.T) {
+	t.Setenv("TEST_CLIENT_SECRET", "s3cret")
+	s, form, err := oauthGrant(OAuthTokenInput{
+		TokenURL: "https://auth.example.com/token", ClientID: "cli", ClientSecretEnv: "TEST_CLIENT_SECRET",
+		Scope: "read write", Params: map[string]string{"audience": "api"},
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.ClientSecret != "s3cret" || s.ClientAuth != "basic" || form.Get("grant_type") != "client_credentials" ||
+		form.Get("scope") != "read write" || form.Get("audience") != "api" || form.Has("client_secret") {
+		t.Errorf("session = %+v, form = %v", s, form)
+	}
+
+	u, _ := url.Parse(s.TokenURL)
+	raw := oauthRequest(u, form, s)
+	want := "Authorization: Basic " + base64.StdEncoding.EncodeToString([]byte("cli:s3cret"))
+	if !strings.HasPrefix(raw, "POST /token HTTP/1.1\r\nHost: auth.example.com\r\n") || !strings.Contains(raw, want) {
+		t.Errorf("raw = %q", raw)
+	}
+
+	_, form, err = oauthGrant(OAuthTokenInput{TokenURL: "https://a/t", GrantType: "password", ClientID: "cli", ClientSecret: "x", ClientAuth: "body", Username: "u", Password: "p"}, nil)
+	if err != nil || form.Get("username") != "u" || form.Get("client_secret") != "x" {
+		t.Errorf("password form = %v, %v", form, err)
+	}
+
+	// Refresh falls back to the session's endpoint, client, and refresh token.
+	prev := &tokenSession{TokenURL: "https://a/t", ClientID: "cli", ClientSecret: "x", ClientAuth: "basic", RefreshToken: "r1"}
+	s, form, err = oauthGrant(OAuthTokenInput{GrantType: "refresh_token"}, prev)
+	if err != nil || s.TokenURL != "https://a/t" || s.ClientSecret != "x" || form.Get("refresh_token") != "r1" {
+		t.Errorf("refresh = %+v, %v, %v", s, form, err)
+	}
+
+	for _, in := range []OAuthTokenInput{
+		{TokenURL: "https://a/t"},
+		{TokenURL: "https://a/t", GrantType: "password"},
+		{TokenURL: "https://a/t", GrantType: "refresh_token"},
+		{TokenURL: "https://a/t", GrantType: "implicit"},
+		{GrantType: "password", Username: "u"},
+		{TokenURL: "https://a/t", ClientID: "c", ClientAuth: "jwt"},
+	} {
+		if _, _, err := oauthGrant(in, nil); err == nil {
+			t.Errorf("expected error for %+v", in)
+		}
+	}
+}
+
+func TestParseTokenResponse(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	var s tokenSession
+	resp := &burp.ParsedHTTPResponse{StatusCode: 200, Body: `{"access_token":"at","token_type":"Bearer","expires_in":"3600","refresh_token":"rt","scope":"read"}`}
+	if err := parseTokenResponse(resp, &s, now); err != nil {
+		t.Fatal(err)
+	}
+	if s.AccessToken != "at" || s.RefreshToken != "rt" || s.TokenType != "Bearer" || !s.Expires.Equal(now.Add(time.Hour)) {
+		t.Errorf("session = %+v", s)
+	}
+
+	resp = &burp.ParsedHTTPResponse{StatusCode: 400, Body: `{"error":"invalid_client","error_description":"bad secret"}`}
+	if err := parseTokenResponse(resp, &s, now); err == nil || !strings.Contains(err.Error(), "invalid_client: bad secret") {
+		t.Errorf("err = %v", err)
+	}
+	resp = &burp.ParsedHTTPResponse{StatusCode: 200, Body: `<html>login</html>`}
+	if err := parseTokenResponse(resp, &s, now); err == nil {
+		t.Error("expected error for non-JSON body")
+	}
+	resp = &burp.ParsedHTTPResponse{StatusCode: 200, Body: `{"token_type":"Bearer"}`}
+	if err := parseTokenResponse(resp, &s, now); err == nil {
+		t.Error("expected error without access_token")
+	}
+}
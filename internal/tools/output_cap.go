@@ -0,0 +1,170 @@
+package tools
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"unicode/utf8"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// minCappedString is the shortest a string is cut to before the cap moves
+// on to the next-longest one.
+const minCappedString = 64
+
+// OutputCapMiddleware enforces the configured maxOutputBytes on tool results.
+// Over-cap results keep their shape: the longest strings are cut until the
+// JSON fits, and cut response bodies update their envelope (returnedBytes,
+// truncated, continuationHint) so the caller knows how to read on.
+func OutputCapMiddleware() mcp.Middleware {
+	return func(next mcp.MethodHandler) mcp.MethodHandler {
+		return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+			result, err := next(ctx, method, req)
+			if res, ok := result.(*mcp.CallToolResult); ok && err == nil && settings.MaxOutputBytes > 0 {
+				capToolResult(res, settings.MaxOutputBytes)
+			}
+			return result, err
+		}
+	}
+}
+
+// capToolResult shrinks res's structured content and text to at most limit bytes.
+func capToolResult(res *mcp.CallToolResult, limit int) {
+	if res.StructuredContent != nil {
+		data, err := json.Marshal(res.StructuredContent)
+		if err != nil || len(data) <= limit {
+			return
+		}
+		capped, err := capJSON(data, limit)
+		if err != nil {
+			return
+		}
+		res.StructuredContent = json.RawMessage(capped)
+		// The SDK mirrors structured output as text; keep the two in step.
+		for _, c := range res.Content {
+			if tc, ok := c.(*mcp.TextContent); ok && tc.Text == string(data) {
+				tc.Text = string(capped)
+			}
+		}
+		return
+	}
+	for _, c := range res.Content {
+		if tc, ok := c.(*mcp.TextContent); ok && len(tc.Text) > limit {
+			tc.Text = cutString(tc.Text, limit-100, limit)
+		}
+	}
+}
+
+// cappedString is a string value inside a decoded JSON document, with a way
+// to replace it and, for response bodies, the object holding its envelope.
+type cappedString struct {
+	value    string
+	set      func(string)
+	envelope map[string]any
+}
+
+// capJSON cuts the longest strings in the JSON document data, each at most
+// once, until it marshals to at most limit bytes or no string is worth cutting.
+func capJSON(data []byte, limit int) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var doc any
+	if err := dec.Decode(&doc); err != nil {
+		return nil, err
+	}
+	var strs []*cappedString
+	collectStrings(doc, &strs)
+
+	for {
+		excess := len(data) - limit
+		if excess <= 0 {
+			return data, nil
+		}
+		sort.SliceStable(strs, func(i, j int) bool { return len(strs[i].value) > len(strs[j].value) })
+		if len(strs) == 0 || len(strs[0].value) <= minCappedString {
+			return data, nil
+		}
+		s := strs[0]
+		strs = strs[1:]
+		// Cut a little past the excess: the marker and JSON escaping take room too.
+		keep := len(s.value) - excess - 128
+		if keep < minCappedString {
+			keep = minCappedString
+		}
+		if s.envelope != nil {
+			kept, raw := cutEncodedBody(s.value, keep, s.envelope["bodyEncoding"])
+			s.set(kept)
+			s.envelope["returnedBytes"] = raw
+			s.envelope["truncated"] = true
+			s.envelope["continuationHint"] = fmt.Sprintf("the server's %d-byte output cap cut this body to %d bytes; read on with bodyOffset advanced by %d and a smaller bodyLimit", limit, raw, raw)
+		} else {
+			s.set(cutString(s.value, keep, limit))
+		}
+		var err error
+		if data, err = json.Marshal(doc); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// collectStrings records every string in v that can be replaced in place.
+// A "body" string next to a "bodySize" key belongs to a BodyEnvelope.
+func collectStrings(v any, out *[]*cappedString) {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, child := range v {
+			if s, ok := child.(string); ok {
+				cs := &cappedString{value: s, set: func(s string) { v[k] = s }}
+				if _, ok := v["bodySize"]; ok && k == "body" {
+					cs.envelope = v
+				}
+				*out = append(*out, cs)
+				continue
+			}
+			collectStrings(child, out)
+		}
+	case []any:
+		for i, child := range v {
+			if s, ok := child.(string); ok {
+				*out = append(*out, &cappedString{value: s, set: func(s string) { v[i] = s }})
+				continue
+			}
+			collectStrings(child, out)
+		}
+	}
+}
+
+// cutBody returns the first n bytes of s, backed off to a rune boundary.
+func cutBody(s string, n int) string {
+	if n >= len(s) {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
+
+// cutEncodedBody keeps at most n characters of a body in the given encoding,
+// on a boundary that still decodes, and returns them with the raw byte count.
+func cutEncodedBody(body string, n int, encoding any) (string, int) {
+	switch encoding {
+	case encodingBase64:
+		n -= n % 4
+		return body[:n], n / 4 * 3
+	case encodingHex:
+		n -= n % 2
+		return body[:n], n / 2
+	}
+	kept := cutBody(body, n)
+	return kept, len(kept)
+}
+
+// cutString keeps the first n bytes of s and marks what the cap removed.
+func cutString(s string, n, limit int) string {
+	kept := cutBody(s, max(n, 0))
+	return kept + fmt.Sprintf("[... %d bytes cut by the %d-byte output cap ...]", len(s)-len(kept), limit)
+}
@@ -0,0 +1,57 @@
+package tools
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestCapToolResult(t *testing.T) {
+	out := BatchSendOutput{
+		Responses: []BatchResponseEntry{
+			{Tag: "a", StatusCode: 200, Body: strings.Repeat("a", 5000), BodyEnvelope: BodyEnvelope{BodySize: 5000, ReturnedBytes: 5000}},
+			{Tag: "b", StatusCode: 200, Body: "short", BodyEnvelope: BodyEnvelope{BodySize: 5, ReturnedBytes: 5}},
+		},
+		Summary: strings.Repeat("s", 3000),
+	}
+	data, _ := json.Marshal(out)
+	res := &mcp.CallToolResult{
+		Content:           []mcp.Content{&mcp.TextContent{Text: string(data)}},
+		StructuredContent: json.RawMessage(data),
+	}
+
+	capToolResult(res, 2000)
+
+	capped := res.StructuredContent.(json.RawMessage)
+	if len(capped) > 2000 {
+		t.Fatalf("capped output is %d bytes", len(capped))
+	}
+	if text := res.Content[0].(*mcp.TextContent).Text; text != string(capped) {
+		t.Error("text content not updated to match structured content")
+	}
+	var got BatchSendOutput
+	if err := json.Unmarshal(capped, &got); err != nil {
+		t.Fatal(err)
+	}
+	a := got.Responses[0]
+	if !a.Truncated || a.ReturnedBytes != len(a.Body) || a.BodySize != 5000 || !strings.Contains(a.ContinuationHint, "output cap") {
+		t.Errorf("cut body envelope: %+v", a.BodyEnvelope)
+	}
+	if got.Responses[1].Body != "short" || got.Responses[1].Truncated {
+		t.Errorf("short body changed: %+v", got.Responses[1])
+	}
+	if !strings.Contains(got.Summary, "bytes cut by the 2000-byte output cap") {
+		t.Errorf("summary not marked: %q", got.Summary)
+	}
+}
+
+func TestCapToolResult_UnderCap(t *testing.T) {
+	data := json.RawMessage(`{"summary":"ok"}`)
+	res := &mcp.CallToolResult{StructuredContent: data}
+	capToolResult(res, 100)
+	if string(res.StructuredContent.(json.RawMessage)) != string(data) {
+		t.Errorf("got %s", res.StructuredContent)
+	}
+}
@@ -0,0 +1,49 @@
+package tools
+
+import (
+	"context"
+	"errors"
+
+	"github.com/c0tton-fluff/burp-mcp-server/internal/burp"
+)
+
+// upstreamPageSize is the largest page requested from Burp in one call.
+// Big pages on large projects can take longer than the tool timeout to
+// serialize, so larger counts are split into several calls.
+const upstreamPageSize = 10
+
+// pageFetcher fetches up to count items starting at offset from Burp.
+type pageFetcher[T any] func(ctx context.Context, count, offset int) ([]T, error)
+
+// fetchChunked retrieves count items starting at offset in upstream pages
+// of at most upstreamPageSize, halving the page whenever a call times out.
+// A short page marks the end of the items. If a single-item page still times
+// out, the items fetched so far are returned along with the error.
+func fetchChunked[T any](ctx context.Context, count, offset int, fetch pageFetcher[T]) ([]T, error) {
+	page := min(count, upstreamPageSize)
+	var items []T
+	for len(items) < count {
+		n := min(page, count-len(items))
+		callCtx := ctx
+		if n > 1 {
+			// A timeout is answered by splitting, not by retrying the same page.
+			callCtx = burp.WithoutRetry(ctx)
+		}
+		got, err := fetch(callCtx, n, offset+len(items))
+		if err != nil {
+			if errors.Is(err, burp.ErrTimeout) && n > 1 && ctx.Err() == nil {
+				page = n / 2
+				continue
+			}
+			return items, err
+		}
+		items = append(items, got...)
+		if len(got) < n {
+			break
+		}
+	}
+	if len(items) > count {
+		items = items[:count]
+	}
+	return items, nil
+}
@@ -0,0 +1,79 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/c0tton-fluff/burp-mcp-server/internal/burp"
+)
+
+// fakePages serves items [0, total) and times out any page larger than maxPage.
+func fakePages(total, maxPage int, calls *[]int) pageFetcher[int] {
+	return func(_ context.Context, count, offset int) ([]int, error) {
+		*calls = append(*calls, count)
+		if count > maxPage {
+			return nil, fmt.Errorf("call get_scanner_issues: %w after 30s", burp.ErrTimeout)
+		}
+		var out []int
+		for i := offset; i < offset+count && i < total; i++ {
+			out = append(out, i)
+		}
+		return out, nil
+	}
+}
+
+func TestFetchChunked_SplitsLargeCounts(t *testing.T) {
+	var calls []int
+	items, err := fetchChunked(context.Background(), 25, 5, fakePages(100, 50, &calls))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 25 || items[0] != 5 || items[24] != 29 {
+		t.Errorf("items = %v", items)
+	}
+	if !slices.Equal(calls, []int{10, 10, 5}) {
+		t.Errorf("calls = %v", calls)
+	}
+}
+
+func TestFetchChunked_HalvesOnTimeout(t *testing.T) {
+	var calls []int
+	items, err := fetchChunked(context.Background(), 10, 0, fakePages(100, 3, &calls))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 10 || items[9] != 9 {
+		t.Errorf("items = %v", items)
+	}
+	if !slices.Equal(calls, []int{10, 5, 2, 2, 2, 2, 2}) {
+		t.Errorf("calls = %v", calls)
+	}
+}
+
+func TestFetchChunked_StopsAtEnd(t *testing.T) {
+	var calls []int
+	items, err := fetchChunked(context.Background(), 50, 0, fakePages(12, 50, &calls))
+	if err != nil || len(items) != 12 {
+		t.Fatalf("items = %v, err = %v", items, err)
+	}
+	if !slices.Equal(calls, []int{10, 10}) {
+		t.Errorf("calls = %v", calls)
+	}
+}
+
+func TestFetchChunked_PartialOnError(t *testing.T) {
+	boom := errors.New("connection reset")
+	fetch := func(_ context.Context, count, offset int) ([]int, error) {
+		if offset >= 10 {
+			return nil, boom
+		}
+		return make([]int, count), nil
+	}
+	items, err := fetchChunked(context.Background(), 20, 0, fetch)
+	if !errors.Is(err, boom) || len(items) != 10 {
+		t.Errorf("items = %d, err = %v", len(items), err)
+	}
+}
@@ -0,0 +1,164 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/c0tton-fluff/burp-mcp-server/internal/burp"
+	"github.com/c0tton-fluff/burp-mcp-server/internal/passive"
+	"github.com/c0tton-fluff/burp-mcp-server/internal/store"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// RunPassiveChecksInput is the input for burp_run_passive_checks.
+type RunPassiveChecksInput struct {
+	Snapshot   string `json:"snapshot,omitempty" jsonschema:"Check a saved history snapshot instead of live proxy history"`
+	Rescan     bool   `json:"rescan,omitempty" jsonschema:"Check live history from the first entry, not from where the last run stopped"`
+	MaxEntries int    `json:"maxEntries,omitempty" jsonschema:"Most live entries to check in one run (default 1000, max 5000); run again for the rest"`
+	Instance   string `json:"instance,omitempty" jsonschema:"Named Burp instance from config (default: the --burp-url connection)"`
+}
+
+// PassiveMatch is one rule matching one history entry.
+type PassiveMatch struct {
+	ID       int    `json:"id"`
+	URL      string `json:"url"`
+	Rule     string `json:"rule"`
+	Severity string `json:"severity"`
+	Evidence string `json:"evidence"`
+}
+
+// RunPassiveChecksOutput is the output of burp_run_passive_checks.
+type RunPassiveChecksOutput struct {
+	Rules   int            `json:"rules"`
+	From    int            `json:"from"` // id of the first entry checked
+	Checked int            `json:"checked"`
+	Matches []PassiveMatch `json:"matches"`
+	Stored  int            `json:"stored"` // issues new to the issues store
+	// More is set when live history has entries past maxEntries.
+	More  bool   `json:"more,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+// passiveRules returns the built-in rules and the configured rules file's.
+func passiveRules() ([]passive.Rule, error) {
+	var rules []passive.Rule
+	cfg := settings.PassiveChecks
+	if cfg == nil || !cfg.NoDefaults {
+		rules = append(rules, passive.Defaults...)
+	}
+	if cfg != nil && cfg.Rules != "" {
+		extra, err := passive.Load(cfg.Rules)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, extra...)
+	}
+	if len(rules) == 0 {
+		return nil, fmt.Errorf("no passive rules: passiveChecks.noDefaults is set and passiveChecks.rules names none")
+	}
+	return rules, nil
+}
+
+// checkEntries runs rules over entries and stores an issue per new match.
+func checkEntries(st *store.Store, rules []passive.Rule, entries []store.HistoryEntry, out *RunPassiveChecksOutput) error {
+	var issues []store.Issue
+	for _, e := range entries {
+		matches := passive.Check(rules, e.Request, e.Response)
+		if len(matches) == 0 {
+			continue
+		}
+		url := summarizeHistoryEntry(e.Request, e.Response, e.ID).URL
+		for _, m := range matches {
+			issue := m.Issue(url, e.Request, e.Response)
+			issues = append(issues, issue)
+			out.Matches = append(out.Matches, PassiveMatch{ID: e.ID, URL: url, Rule: m.Rule.Name, Severity: issue.Severity, Evidence: m.Evidence})
+		}
+	}
+	out.Checked += len(entries)
+	stored, err := st.ImportIssues(issues)
+	if err != nil {
+		return fmt.Errorf("store passive issues: %w", err)
+	}
+	out.Stored += stored
+	return nil
+}
+
+func runPassiveChecksHandler(client *burp.Client, st *store.Store) func(context.Context, *mcp.CallToolRequest, RunPassiveChecksInput) (*mcp.CallToolResult, RunPassiveChecksOutput, error) {
+	return func(ctx context.Context, _ *mcp.CallToolRequest, input RunPassiveChecksInput) (*mcp.CallToolResult, RunPassiveChecksOutput, error) {
+		ctx = burp.WithInstance(ctx, input.Instance)
+		rules, err := passiveRules()
+		if err != nil {
+			return nil, RunPassiveChecksOutput{}, err
+		}
+		out := RunPassiveChecksOutput{Rules: len(rules), Matches: []PassiveMatch{}}
+
+		if label := strings.TrimSpace(input.Snapshot); label != "" {
+			if input.Rescan || input.MaxEntries != 0 {
+				return nil, RunPassiveChecksOutput{}, fmt.Errorf("rescan and maxEntries apply to live history, not snapshots")
+			}
+			snap, err := st.Snapshot(label)
+			if err != nil {
+				return nil, RunPassiveChecksOutput{}, err
+			}
+			out.From = 1
+			if err := checkEntries(st, rules, snap.Entries, &out); err != nil {
+				return nil, RunPassiveChecksOutput{}, err
+			}
+			return nil, out, nil
+		}
+
+		maxEntries := input.MaxEntries
+		if maxEntries <= 0 {
+			maxEntries = defaultSnapshotEntries
+		}
+		maxEntries = min(maxEntries, maxSnapshotEntries)
+		start := 0
+		if !input.Rescan {
+			start = st.PassiveCursor(input.Instance)
+		}
+		out.From = start + 1
+
+		var entries []store.HistoryEntry
+		for len(entries) < maxEntries {
+			n := min(snapshotBatch, maxEntries-len(entries))
+			batch, err := fetchHistory(ctx, client, start+len(entries), n, parseSnapshotEntry)
+			entries = append(entries, batch...)
+			if err != nil {
+				if len(entries) == 0 {
+					return nil, RunPassiveChecksOutput{}, fmt.Errorf("failed to read proxy history entry %d: %w", start+1, err)
+				}
+				out.Error = fmt.Sprintf("stopped at proxy history entry %d: %v; run again to continue", start+len(entries)+1, err)
+				break
+			}
+			if len(batch) < n {
+				break
+			}
+			if len(entries) == maxEntries {
+				more, _ := fetchHistory(ctx, client, start+len(entries), 1, parseSnapshotEntry)
+				out.More = len(more) > 0
+			}
+		}
+
+		if err := checkEntries(st, rules, entries, &out); err != nil {
+			return nil, RunPassiveChecksOutput{}, err
+		}
+		if len(entries) > 0 {
+			if err := st.SetPassiveCursor(input.Instance, start+len(entries)); err != nil {
+				return nil, RunPassiveChecksOutput{}, fmt.Errorf("record passive check progress: %w", err)
+			}
+		}
+		return nil, out, nil
+	}
+}
+
+// RegisterRunPassiveChecksTool registers the burp_run_passive_checks tool.
+func RegisterRunPassiveChecksTool(server *mcp.Server, client *burp.Client, st *store.Store) {
+	addTool(server, &mcp.Tool{
+		Name: "burp_run_passive_checks",
+		Description: `Run regex rules over proxy history entries not checked before, or over a saved snapshot, and store an issue for each rule matching an endpoint: ` +
+			`built-in rules find verbose stack traces, internal IP disclosure, and session tokens in URLs, and config passiveChecks.rules adds YAML rules. ` +
+			`Issues are served by burp_get_scanner_issues with source=imported. ` +
+			`Returns {rules, from, checked, matches: [{id, url, rule, severity, evidence}], stored, more, error}.`,
+	}, runPassiveChecksHandler(client, st))
+}
@@ -0,0 +1,103 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/c0tton-fluff/burp-mcp-server/internal/config"
+	"github.com/c0tton-fluff/burp-mcp-server/internal/store"
+)
+
+func rawHistoryJSON(request, response string) string {
+	data, _ := json.Marshal(map[string]string{"request": request, "response": response})
+	return string(data)
+}
+
+func TestRunPassiveChecks(t *testing.T) {
+	st, err := store.Open(filepath.Join(t.TempDir(), "store.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := &fakeBurp{clearable: true}
+	f.add(historyEntryJSON("GET", "a.test", "/", 200), "")
+	f.add(rawHistoryJSON("GET /login;jsessionid=0123456789ABCDEF HTTP/1.1\r\nHost: a.test\r\n\r\n", "HTTP/1.1 200 OK\r\n\r\nok"), "")
+	trace := "HTTP/1.1 500 Error\r\n\r\nException\n\tat com.acme.Api.get(Api.java:12)"
+	f.add(rawHistoryJSON("GET /api/1 HTTP/1.1\r\nHost: a.test\r\n\r\n", trace), "")
+	client := startFakeBurp(t, f)
+	ctx := context.Background()
+	run := runPassiveChecksHandler(client, st)
+
+	_, out, err := run(ctx, nil, RunPassiveChecksInput{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out.From != 1 || out.Checked != 3 || out.Stored != 2 || len(out.Matches) != 2 {
+		t.Fatalf("first run = %+v", out)
+	}
+	if m := out.Matches[1]; m.ID != 3 || m.URL != "https://a.test/api/1" || m.Rule != "Verbose stack trace" || m.Severity != "Low" {
+		t.Errorf("match = %+v", m)
+	}
+	if issues := st.Issues(); len(issues) != 2 || issues[0].Name != "Session token in URL" || issues[0].Source != "passive" {
+		t.Errorf("stored issues = %+v", issues)
+	}
+
+	// Only new entries are checked, and an endpoint already reported isn't stored again.
+	f.add(rawHistoryJSON("GET /api/1?x=2 HTTP/1.1\r\nHost: a.test\r\n\r\n", trace), "")
+	_, out, err = run(ctx, nil, RunPassiveChecksInput{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out.From != 4 || out.Checked != 1 || len(out.Matches) != 1 || out.Stored != 0 {
+		t.Errorf("second run = %+v", out)
+	}
+	_, out, err = run(ctx, nil, RunPassiveChecksInput{})
+	if err != nil || out.Checked != 0 {
+		t.Errorf("third run = %+v, %v", out, err)
+	}
+	_, out, err = run(ctx, nil, RunPassiveChecksInput{Rescan: true, MaxEntries: 2})
+	if err != nil || out.Checked != 2 || !out.More {
+		t.Errorf("rescan = %+v, %v", out, err)
+	}
+
+	// Clearing history resets the cursor.
+	if _, _, err := deleteProxyHistoryHandler(client, st)(ctx, nil, DeleteProxyHistoryInput{Label: "before-clear"}); err != nil {
+		t.Fatal(err)
+	}
+	if n := st.PassiveCursor(""); n != 0 {
+		t.Errorf("cursor after clearing = %d", n)
+	}
+	_, out, err = run(ctx, nil, RunPassiveChecksInput{Snapshot: "before-clear"})
+	if err != nil || out.Checked != 4 || len(out.Matches) != 3 || out.Stored != 0 {
+		t.Errorf("snapshot run = %+v, %v", out, err)
+	}
+}
+
+func TestPassiveRulesConfig(t *testing.T) {
+	t.Cleanup(func() { Configure(nil) })
+	path := filepath.Join(t.TempDir(), "rules.yaml")
+	src := "rules:\n  - name: Server banner\n    severity: information\n    in: response.headers\n    header: Server\n    pattern: '[\\d.]+'\n"
+	if err := os.WriteFile(path, []byte(src), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	Configure(&config.Config{PassiveChecks: &config.PassiveChecksConfig{Rules: path}})
+	rules, err := passiveRules()
+	if err != nil || len(rules) != 4 || rules[3].Name != "Server banner" {
+		t.Fatalf("rules = %+v, %v", rules, err)
+	}
+	Configure(&config.Config{PassiveChecks: &config.PassiveChecksConfig{Rules: path, NoDefaults: true}})
+	if rules, err := passiveRules(); err != nil || len(rules) != 1 {
+		t.Errorf("rules without defaults = %+v, %v", rules, err)
+	}
+	Configure(&config.Config{PassiveChecks: &config.PassiveChecksConfig{NoDefaults: true}})
+	if _, err := passiveRules(); err == nil {
+		t.Error("expected error with no rules")
+	}
+	Configure(&config.Config{PassiveChecks: &config.PassiveChecksConfig{Rules: filepath.Join(t.TempDir(), "missing.yaml")}})
+	if _, err := passiveRules(); err == nil {
+		t.Error("expected error for a missing rules file")
+	}
+}
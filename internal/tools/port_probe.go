@@ -0,0 +1,282 @@
+package tools
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// Port probe limits. The tool is for checking a handful of ports on a
+// known target, not for sweeping.
+const (
+	maxProbePorts           = 100
+	defaultPortConcurrency  = 10
+	maxPortConcurrency      = 50
+	defaultPortTimeoutMs    = 2000
+	maxPortTimeoutMs        = 10000
+	maxBannerBytes          = 512
+	serverFirstBannerWindow = 800 * time.Millisecond
+)
+
+// Port states.
+const (
+	portOpen     = "open"
+	portClosed   = "closed"
+	portFiltered = "filtered"
+)
+
+// bannerServices maps server-first banner prefixes to a service name.
+var bannerServices = []struct{ prefix, service string }{
+	{"SSH-", "ssh"},
+	{"220", "ftp/smtp"},
+	{"+OK", "pop3"},
+	{"* OK", "imap"},
+	{"RFB ", "vnc"},
+	{"HTTP/", "http"},
+}
+
+// httpsOnlyMarkers are plain-HTTP error pages from servers that only
+// speak TLS on the port (Go, nginx, Apache).
+var httpsOnlyMarkers = []string{
+	"HTTP request to an HTTPS server",
+	"plain HTTP request was sent to HTTPS port",
+	"speaking plain HTTP to an SSL-enabled server",
+}
+
+// PortProbeInput is the input for burp_port_probe.
+type PortProbeInput struct {
+	Host        string `json:"host" jsonschema:"required,Target host or IP; must be in the configured scope"`
+	Ports       []int  `json:"ports" jsonschema:"required,TCP ports to check (max 100)"`
+	Concurrency int    `json:"concurrency,omitempty" jsonschema:"Connections in flight at once (default 10, max 50)"`
+	TimeoutMs   int    `json:"timeoutMs,omitempty" jsonschema:"Connect and banner timeout per port in milliseconds (default 2000, max 10000)"`
+	NoBanner    bool   `json:"noBanner,omitempty" jsonschema:"Only connect; skip banner grabs and HTTP/TLS detection"`
+}
+
+// PortHTTP is what an HTTP-speaking port answered to HEAD /.
+type PortHTTP struct {
+	URL      string `json:"url"`
+	Status   string `json:"status"`
+	Server   string `json:"server,omitempty"`
+	Location string `json:"location,omitempty"`
+}
+
+// PortResult is the outcome for one port.
+type PortResult struct {
+	Port    int       `json:"port"`
+	State   string    `json:"state"`
+	Service string    `json:"service,omitempty"`
+	Banner  string    `json:"banner,omitempty"`
+	HTTP    *PortHTTP `json:"http,omitempty"`
+	Error   string    `json:"error,omitempty"`
+}
+
+// PortProbeOutput is the output of burp_port_probe.
+type PortProbeOutput struct {
+	Host    string       `json:"host"`
+	Open    []int        `json:"open"`
+	Results []PortResult `json:"results"`
+	// URLs are base URLs for the ports that spoke HTTP, ready for the send tools.
+	URLs []string `json:"urls"`
+}
+
+// portState classifies a connect error.
+func portState(err error) string {
+	switch {
+	case err == nil:
+		return portOpen
+	case errors.Is(err, syscall.ECONNREFUSED):
+		return portClosed
+	}
+	return portFiltered
+}
+
+// printableBanner keeps the first line of a banner, minus control bytes.
+func printableBanner(b []byte) string {
+	line, _, _ := bytes.Cut(b, []byte("\n"))
+	return strings.Map(func(r rune) rune {
+		if r < 0x20 || r == 0x7f {
+			return -1
+		}
+		return r
+	}, string(line))
+}
+
+// readBanner reads whatever the peer sends before deadline.
+func readBanner(conn net.Conn, deadline time.Time) []byte {
+	conn.SetReadDeadline(deadline)
+	buf := make([]byte, maxBannerBytes)
+	n, _ := conn.Read(buf)
+	return buf[:n]
+}
+
+// headHTTP sends HEAD / on conn and parses the reply, or returns nil if
+// the peer doesn't answer in HTTP or wants TLS.
+func headHTTP(conn net.Conn, host string, deadline time.Time) *PortHTTP {
+	conn.SetDeadline(deadline)
+	fmt.Fprintf(conn, "HEAD / HTTP/1.0\r\nHost: %s\r\nUser-Agent: Mozilla/5.0\r\n\r\n", host)
+	resp := string(readBanner(conn, deadline))
+	if !strings.HasPrefix(resp, "HTTP/") {
+		return nil
+	}
+	for _, m := range httpsOnlyMarkers {
+		if strings.Contains(resp, m) {
+			return nil
+		}
+	}
+	head, _, _ := strings.Cut(resp, "\r\n\r\n")
+	lines := strings.Split(head, "\r\n")
+	h := &PortHTTP{Status: lines[0]}
+	for _, l := range lines[1:] {
+		name, value, _ := strings.Cut(l, ":")
+		switch http.CanonicalHeaderKey(strings.TrimSpace(name)) {
+		case "Server":
+			h.Server = strings.TrimSpace(value)
+		case "Location":
+			h.Location = strings.TrimSpace(value)
+		}
+	}
+	return h
+}
+
+// probePort connects to one port and, if open, identifies what listens:
+// a server-first banner, plain HTTP, or HTTP over TLS.
+func probePort(ctx context.Context, host string, port int, timeout time.Duration, banner bool) PortResult {
+	r := PortResult{Port: port}
+	addr := net.JoinHostPort(host, strconv.Itoa(port))
+	dialer := &net.Dialer{Timeout: timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	r.State = portState(err)
+	if err != nil {
+		if r.State == portFiltered && !isTimeout(err) {
+			r.Error = err.Error()
+		}
+		return r
+	}
+	defer conn.Close()
+	if !banner {
+		return r
+	}
+
+	if b := readBanner(conn, time.Now().Add(min(timeout, serverFirstBannerWindow))); len(b) > 0 {
+		r.Banner = printableBanner(b)
+		for _, s := range bannerServices {
+			if strings.HasPrefix(r.Banner, s.prefix) {
+				r.Service = s.service
+				break
+			}
+		}
+		return r
+	}
+	if h := headHTTP(conn, addr, time.Now().Add(timeout)); h != nil {
+		h.URL = "http://" + addr
+		r.Service, r.HTTP = "http", h
+		return r
+	}
+
+	tlsConn, err := dialConn(ctx, addr, &tls.Config{ServerName: host, InsecureSkipVerify: true, NextProtos: []string{"http/1.1"}}, time.Now().Add(timeout))
+	if err != nil {
+		return r
+	}
+	defer tlsConn.Close()
+	r.Service = "tls"
+	if h := headHTTP(tlsConn, addr, time.Now().Add(timeout)); h != nil {
+		h.URL = "https://" + addr
+		r.Service, r.HTTP = "https", h
+	}
+	return r
+}
+
+func isTimeout(err error) bool {
+	var ne net.Error
+	return errors.As(err, &ne) && ne.Timeout()
+}
+
+func portProbeHandler() func(context.Context, *mcp.CallToolRequest, PortProbeInput) (*mcp.CallToolResult, PortProbeOutput, error) {
+	return func(ctx context.Context, _ *mcp.CallToolRequest, input PortProbeInput) (*mcp.CallToolResult, PortProbeOutput, error) {
+		host := strings.Trim(strings.TrimSpace(input.Host), "[]")
+		if host == "" {
+			return nil, PortProbeOutput{}, fmt.Errorf("host is required")
+		}
+		if len(input.Ports) == 0 {
+			return nil, PortProbeOutput{}, fmt.Errorf("ports is required")
+		}
+		ports := slices.Compact(slices.Sorted(slices.Values(input.Ports)))
+		if len(ports) > maxProbePorts {
+			return nil, PortProbeOutput{}, fmt.Errorf("at most %d ports per call, got %d", maxProbePorts, len(ports))
+		}
+		if ports[0] < 1 || ports[len(ports)-1] > 65535 {
+			return nil, PortProbeOutput{}, fmt.Errorf("ports must be between 1 and 65535")
+		}
+		concurrency := input.Concurrency
+		if concurrency <= 0 {
+			concurrency = defaultPortConcurrency
+		}
+		concurrency = min(concurrency, maxPortConcurrency)
+		timeoutMs := input.TimeoutMs
+		if timeoutMs <= 0 {
+			timeoutMs = defaultPortTimeoutMs
+		}
+		timeout := time.Duration(min(timeoutMs, maxPortTimeoutMs)) * time.Millisecond
+
+		// Unlike the send tools, scanning is refused outright without a scope.
+		if targetScope.empty() {
+			return nil, PortProbeOutput{}, fmt.Errorf("burp_port_probe needs a configured scope; add the target to scope in config")
+		}
+		if err := checkDryRun(ctx); err != nil {
+			return nil, PortProbeOutput{}, err
+		}
+		if err := checkScope(ctx, host); err != nil {
+			return nil, PortProbeOutput{}, err
+		}
+		if err := checkRateLimit(ctx, resolvedTarget{Host: host}, len(ports)); err != nil {
+			return nil, PortProbeOutput{}, err
+		}
+
+		results := make([]PortResult, len(ports))
+		sem := make(chan struct{}, concurrency)
+		var wg sync.WaitGroup
+		for i, port := range ports {
+			wg.Add(1)
+			sem <- struct{}{}
+			go func() {
+				defer wg.Done()
+				defer func() { <-sem }()
+				results[i] = probePort(ctx, host, port, timeout, !input.NoBanner)
+			}()
+		}
+		wg.Wait()
+
+		out := PortProbeOutput{Host: host, Open: []int{}, Results: results, URLs: []string{}}
+		for _, r := range results {
+			if r.State == portOpen {
+				out.Open = append(out.Open, r.Port)
+			}
+			if r.HTTP != nil {
+				out.URLs = append(out.URLs, r.HTTP.URL)
+			}
+		}
+		return nil, out, nil
+	}
+}
+
+// RegisterPortProbeTool registers the burp_port_probe tool.
+func RegisterPortProbeTool(server *mcp.Server) {
+	addTool(server, &mcp.Tool{
+		Name: "burp_port_probe",
+		Description: `TCP connect check of an explicit port list (max 100) on one in-scope host, sent directly; refuses to run without a configured scope. ` +
+			`Classifies each port open, closed, or filtered, and for open ports grabs server-first banners (SSH, FTP, SMTP...) or detects HTTP and HTTPS with a HEAD request. ` +
+			`Returns {host, open, results: [{port, state, service, banner, http: {url, status, server, location}}], urls} where urls are base URLs for the HTTP ports.`,
+	}, portProbeHandler())
+}
@@ -0,0 +1,89 @@
+package tools
+
+import (
+	"context"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/c0tton-fluff/burp-mcp-server/internal/config"
+)
+
+func listenPort(t *testing.T) (net.Listener, int) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ln, ln.Addr().(*net.TCPAddr).Port
+}
+
+func TestPortProbe(t *testing.T) {
+	t.Cleanup(func() { Configure(nil) })
+	Configure(&config.Config{Scope: []string{"127.0.0.1"}})
+
+	web := startTestTarget(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Server", "test-httpd")
+	})
+	tlsSrv := httptest.NewUnstartedServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	tlsSrv.Config.ErrorLog = log.New(io.Discard, "", 0)
+	tlsSrv.StartTLS()
+	defer tlsSrv.Close()
+	_, p, _ := net.SplitHostPort(strings.TrimPrefix(tlsSrv.URL, "https://"))
+	tlsPort, _ := strconv.Atoi(p)
+
+	ssh, sshPort := listenPort(t)
+	defer ssh.Close()
+	go func() {
+		for {
+			c, err := ssh.Accept()
+			if err != nil {
+				return
+			}
+			io.WriteString(c, "SSH-2.0-OpenSSH_9.6\r\n")
+			c.Close()
+		}
+	}()
+	closed, closedPort := listenPort(t)
+	closed.Close()
+
+	_, out, err := portProbeHandler()(context.Background(), nil, PortProbeInput{
+		Host:  "127.0.0.1",
+		Ports: []int{web.Port, tlsPort, sshPort, closedPort, web.Port},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(out.Results) != 4 || len(out.Open) != 3 || len(out.URLs) != 2 {
+		t.Fatalf("out = %+v", out)
+	}
+	byPort := map[int]PortResult{}
+	for _, r := range out.Results {
+		byPort[r.Port] = r
+	}
+	if r := byPort[web.Port]; r.Service != "http" || r.HTTP == nil || r.HTTP.Server != "test-httpd" || !strings.HasPrefix(r.HTTP.Status, "HTTP/1.0 200") {
+		t.Errorf("http port = %+v %+v", r, r.HTTP)
+	}
+	if r := byPort[tlsPort]; r.Service != "https" || r.HTTP == nil || r.HTTP.URL != "https://127.0.0.1:"+p {
+		t.Errorf("https port = %+v %+v", r, r.HTTP)
+	}
+	if r := byPort[sshPort]; r.Service != "ssh" || r.Banner != "SSH-2.0-OpenSSH_9.6" {
+		t.Errorf("ssh port = %+v", r)
+	}
+	if r := byPort[closedPort]; r.State != portClosed {
+		t.Errorf("closed port = %+v", r)
+	}
+
+	if _, _, err := portProbeHandler()(context.Background(), nil, PortProbeInput{Host: "10.0.0.1", Ports: []int{80}}); err == nil {
+		t.Error("out-of-scope host probed")
+	}
+	Configure(nil)
+	if _, _, err := portProbeHandler()(context.Background(), nil, PortProbeInput{Host: "127.0.0.1", Ports: []int{web.Port}}); err == nil || !strings.Contains(err.Error(), "scope") {
+		t.Errorf("probe without scope: %v", err)
+	}
+}
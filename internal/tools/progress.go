@@ -0,0 +1,78 @@
+package tools
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/c0tton-fluff/burp-mcp-server/internal/tasks"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// minProgressInterval spaces progress notifications so a long attack doesn't
+// flood the client; the final one is always sent.
+const minProgressInterval = 250 * time.Millisecond
+
+// progressSink sends MCP progress notifications for one tool call.
+type progressSink struct {
+	session *mcp.ServerSession
+	token   any
+
+	mu   sync.Mutex
+	last int // highest progress sent; notifications must increase
+	sent time.Time
+}
+
+type progressKey struct{}
+
+// ProgressMiddleware lets tool calls that carry a progress token report
+// progress: reportProgress then sends notifications/progress to the caller.
+func ProgressMiddleware() mcp.Middleware {
+	return func(next mcp.MethodHandler) mcp.MethodHandler {
+		return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+			if params, ok := req.GetParams().(*mcp.CallToolParamsRaw); ok && method == "tools/call" {
+				session, _ := req.GetSession().(*mcp.ServerSession)
+				if token := params.GetProgressToken(); token != nil && session != nil {
+					ctx = context.WithValue(ctx, progressKey{}, &progressSink{session: session, token: token})
+				}
+			}
+			return next(ctx, method, req)
+		}
+	}
+}
+
+// withoutProgress detaches ctx from its call's progress token. Background
+// tasks use it: their call has returned, so the token is no longer valid.
+func withoutProgress(ctx context.Context) context.Context {
+	return context.WithValue(ctx, progressKey{}, (*progressSink)(nil))
+}
+
+// notify sends done of total (0 when unknown) with current as the message.
+func (p *progressSink) notify(ctx context.Context, done, total int, current string) {
+	// Sending under the lock keeps concurrent reports in order on the wire.
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if done <= p.last || (done != total && time.Since(p.sent) < minProgressInterval) {
+		return
+	}
+	p.last, p.sent = done, time.Now()
+	// Progress is advisory; a client that went away doesn't fail the call.
+	p.session.NotifyProgress(ctx, &mcp.ProgressNotificationParams{
+		ProgressToken: p.token,
+		Progress:      float64(done),
+		Total:         float64(total),
+		Message:       current,
+	})
+}
+
+// reportProgress records that done of total items (0 when unknown) are
+// complete, current being the one just finished. It updates the task ctx
+// belongs to, if any, and notifies a caller that asked for progress.
+func reportProgress(ctx context.Context, done, total int, current string) {
+	if t := tasks.FromContext(ctx); t != nil {
+		t.SetProgress(done, total, current)
+	}
+	if p, _ := ctx.Value(progressKey{}).(*progressSink); p != nil {
+		p.notify(ctx, done, total, current)
+	}
+}
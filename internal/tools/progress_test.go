@@ -0,0 +1,94 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestProgressMiddleware(t *testing.T) {
+	target := startTestTarget(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		if r.URL.Path == "/" {
+			fmt.Fprint(w, `<a href="/a">a</a><a href="/b">b</a>`)
+		}
+	})
+	server := mcp.NewServer(&mcp.Implementation{Name: "test"}, nil)
+	server.AddReceivingMiddleware(ActivityMiddleware(), ProgressMiddleware())
+	RegisterCrawlTool(server, nil)
+
+	var mu sync.Mutex
+	var got []*mcp.ProgressNotificationParams
+	client := mcp.NewClient(&mcp.Implementation{Name: "client"}, &mcp.ClientOptions{
+		ProgressNotificationHandler: func(_ context.Context, req *mcp.ProgressNotificationClientRequest) {
+			mu.Lock()
+			defer mu.Unlock()
+			got = append(got, req.Params)
+		},
+	})
+	ct, st := mcp.NewInMemoryTransports()
+	ctx := context.Background()
+	ss, err := server.Connect(ctx, st, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ss.Close()
+	cs, err := client.Connect(ctx, ct, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cs.Close()
+
+	call := func(token any) {
+		params := &mcp.CallToolParams{
+			Name:      "burp_crawl",
+			Arguments: map[string]any{"url": fmt.Sprintf("http://%s:%d/", target.Host, target.Port)},
+		}
+		if token != nil {
+			// SetProgressToken drops the token when Meta is nil.
+			params.Meta = mcp.Meta{"progressToken": token}
+		}
+		res, err := cs.CallTool(ctx, params)
+		if err != nil || res.IsError {
+			t.Fatalf("call: %v %+v", err, res)
+		}
+	}
+
+	call(nil)
+	call("crawl-1")
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		mu.Lock()
+		n := len(got)
+		var last mcp.ProgressNotificationParams
+		if n > 0 {
+			last = *got[n-1]
+		}
+		mu.Unlock()
+		if n > 0 && last.Progress == 3 {
+			if last.ProgressToken != "crawl-1" || last.Total != 3 || last.Message == "" {
+				t.Errorf("last notification = %+v", last)
+			}
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("notifications = %d, last %+v", n, last)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	for i := range got {
+		if got[i].ProgressToken != "crawl-1" {
+			t.Errorf("notification %d has token %v", i, got[i].ProgressToken)
+		}
+		if i > 0 && got[i].Progress <= got[i-1].Progress {
+			t.Errorf("progress went from %v to %v", got[i-1].Progress, got[i].Progress)
+		}
+	}
+}
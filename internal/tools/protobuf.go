@@ -0,0 +1,90 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/c0tton-fluff/burp-mcp-server/internal/burp"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// ProtobufMessageSummary is one decoded gRPC or protobuf message.
+type ProtobufMessageSummary struct {
+	Size       int    `json:"size"`
+	Compressed bool   `json:"compressed,omitempty"`
+	Trailers   string `json:"trailers,omitempty"`
+	Decoded    string `json:"decoded,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+// ProtobufSummary is the wire-format decode of a gRPC or protobuf body.
+type ProtobufSummary struct {
+	Messages []ProtobufMessageSummary `json:"messages"`
+	Error    string                   `json:"error,omitempty"`
+}
+
+// summarizeProtobuf decodes body when headers say it's gRPC or protobuf,
+// and returns nil otherwise.
+func summarizeProtobuf(headers map[string][]string, body string) *ProtobufSummary {
+	contentType := burp.GetHeader(headers, "Content-Type")
+	if !burp.IsProtobuf(contentType) {
+		return nil
+	}
+	return decodeProtobuf(contentType, burp.GetHeader(headers, "Grpc-Encoding"), body)
+}
+
+func decodeProtobuf(contentType, grpcEncoding, body string) *ProtobufSummary {
+	msgs, err := burp.DecodeProtobufBody(contentType, grpcEncoding, body)
+	s := &ProtobufSummary{Messages: make([]ProtobufMessageSummary, len(msgs))}
+	if err != nil {
+		s.Error = err.Error()
+	}
+	for i, m := range msgs {
+		s.Messages[i] = ProtobufMessageSummary{Size: m.Size, Compressed: m.Compressed, Trailers: m.Trailers, Decoded: m.Decoded}
+		if m.Err != nil {
+			s.Messages[i].Error = m.Err.Error()
+		}
+	}
+	return s
+}
+
+// DecodeProtobufInput is the input for burp_decode_protobuf.
+type DecodeProtobufInput struct {
+	Content      string `json:"content" jsonschema:"required,Body to decode"`
+	Encoding     string `json:"encoding,omitempty" jsonschema:"How content is encoded: base64 (default), hex, or utf8"`
+	ContentType  string `json:"contentType,omitempty" jsonschema:"Body Content-Type: application/grpc (default, length-prefixed frames), application/grpc-web-text, or application/x-protobuf (one bare message)"`
+	GRPCEncoding string `json:"grpcEncoding,omitempty" jsonschema:"grpc-encoding header value for compressed frames (gzip)"`
+}
+
+func decodeProtobufHandler() func(context.Context, *mcp.CallToolRequest, DecodeProtobufInput) (*mcp.CallToolResult, ProtobufSummary, error) {
+	return func(_ context.Context, _ *mcp.CallToolRequest, input DecodeProtobufInput) (*mcp.CallToolResult, ProtobufSummary, error) {
+		if input.Content == "" {
+			return nil, ProtobufSummary{}, fmt.Errorf("content is required")
+		}
+		encoding := input.Encoding
+		if encoding == "" {
+			encoding = encodingBase64
+		}
+		contentType := input.ContentType
+		if contentType == "" {
+			contentType = "application/grpc"
+		}
+		if !burp.IsProtobuf(contentType) {
+			return nil, ProtobufSummary{}, fmt.Errorf("contentType %q is not a gRPC or protobuf type", contentType)
+		}
+		data, err := decodeContent(input.Content, encoding)
+		if err != nil {
+			return nil, ProtobufSummary{}, err
+		}
+		return nil, *decodeProtobuf(contentType, input.GRPCEncoding, string(data)), nil
+	}
+}
+
+// RegisterDecodeProtobufTool registers the burp_decode_protobuf tool.
+func RegisterDecodeProtobufTool(server *mcp.Server) {
+	addTool(server, &mcp.Tool{
+		Name: "burp_decode_protobuf",
+		Description: `Decode a gRPC or protobuf body without its schema: unframe gRPC messages and show each field by number and wire type, with nested messages (protoc --decode_raw style). ` +
+			`Returns {messages: [{size, compressed, trailers, decoded, error}], error}.`,
+	}, decodeProtobufHandler())
+}
@@ -0,0 +1,45 @@
+package tools
+
+import (
+	"context"
+	"encoding/hex"
+	"testing"
+)
+
+func TestDecodeProtobufHandler(t *testing.T) {
+	handler := decodeProtobufHandler()
+	// One gRPC frame holding {1: 150, 2: "hi"}.
+	_, out, err := handler(context.Background(), nil, DecodeProtobufInput{
+		Content:  "AAAAAAcIlgESAmhp",
+		Encoding: "base64",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(out.Messages) != 1 || out.Messages[0].Decoded != "1: 150\n2: \"hi\"" || out.Messages[0].Size != 7 {
+		t.Errorf("out = %+v", out)
+	}
+
+	_, out, err = handler(context.Background(), nil, DecodeProtobufInput{
+		Content:     hex.EncodeToString([]byte{0x08, 0x96, 0x01}),
+		Encoding:    "hex",
+		ContentType: "application/x-protobuf",
+	})
+	if err != nil || len(out.Messages) != 1 || out.Messages[0].Decoded != "1: 150" {
+		t.Errorf("bare message: %+v, %v", out, err)
+	}
+
+	if _, _, err := handler(context.Background(), nil, DecodeProtobufInput{Content: "AA==", ContentType: "text/plain"}); err == nil {
+		t.Error("expected an error for a non-protobuf contentType")
+	}
+}
+
+func TestSummarizeProtobuf(t *testing.T) {
+	if s := summarizeProtobuf(map[string][]string{"Content-Type": {"application/json"}}, "{}"); s != nil {
+		t.Errorf("json body summarized: %+v", s)
+	}
+	s := summarizeProtobuf(map[string][]string{"content-type": {"application/grpc"}}, "\x00\x00\x00\x00\x02\x08\x01\x00")
+	if s == nil || len(s.Messages) != 1 || s.Error == "" {
+		t.Errorf("summary = %+v", s)
+	}
+}
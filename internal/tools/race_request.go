@@ -2,30 +2,49 @@ package tools
 
 import (
 	"bufio"
+	"cmp"
 	"context"
 	"crypto/tls"
 	"fmt"
+	"maps"
 	"net"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/c0tton-fluff/burp-mcp-server/internal/burp"
+	"github.com/c0tton-fluff/burp-mcp-server/internal/config"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 )
 
+// markerPayload is replaced in raw by each racer's entry from payloads.
+const markerPayload = "{{payload}}"
+
 const (
 	raceTimeout    = 30 * time.Second
 	maxRaceCount   = 50
 	defaultRaceCount = 10
 	defaultRaceBodyLimit = 500
+	maxRaceRounds     = 10
+	defaultRoundDelay = time.Second
 )
 
 // RaceRequestInput is the input for the burp_race_request tool.
 type RaceRequestInput struct {
 	// Raw HTTP request (request line + headers + body)
-	Raw string `json:"raw" jsonschema:"required,Raw HTTP request including headers and body"`
+	Raw string `json:"raw,omitempty" jsonschema:"Raw HTTP request including headers and body (required unless raws or rawBase64 is given)"`
+	// Raw request as base64 bytes, sent exactly as decoded
+	RawBase64 string `json:"rawBase64,omitempty" jsonschema:"The request as base64 bytes, raced exactly as decoded (for bare CR, NUL, or invalid UTF-8); replaces raw"`
+	// Normalize line endings and Content-Length (default true for raw)
+	Normalize *bool `json:"normalize,omitempty" jsonschema:"Rewrite line endings to CRLF, end the headers with CRLFCRLF, and fix Content-Length (default true for raw and raws, false for rawBase64); false races the bytes exactly, without header rules"`
+	// Different requests per racer, assigned round-robin
+	Raws []string `json:"raws,omitempty" jsonschema:"Different raw requests to race against each other, assigned to connections round-robin (e.g. redeem and check-balance interleaved)"`
+	// Values for the {{payload}} marker in raw, one per racer
+	Payloads []string `json:"payloads,omitempty" jsonschema:"Values substituted for the {{payload}} marker in raw, assigned to connections round-robin"`
+	// Request sent on each connection before the race
+	Warmup string `json:"warmup,omitempty" jsonschema:"Raw HTTP request sent on each connection before the race (e.g. GET / on the same host), so every connection is routed and warm when the race fires"`
 	// Target host (overrides Host header)
 	Host string `json:"host,omitempty" jsonschema:"Target host (overrides Host header)"`
 	// Target port
@@ -33,42 +52,112 @@ type RaceRequestInput struct {
 	// Use HTTPS
 	TLS *bool `json:"tls,omitempty" jsonschema:"Use HTTPS (default true)"`
 	// Number of concurrent requests (default 10, max 50)
-	Count int `json:"count,omitempty" jsonschema:"Number of concurrent requests (default 10, max 50)"`
-	// Body limit in bytes per response (default 500)
-	BodyLimit int `json:"bodyLimit,omitempty" jsonschema:"Response body byte limit per response (default 500)"`
+	Count int `json:"count,omitempty" jsonschema:"Number of concurrent requests (default 10, or one per raws/payloads entry; max 50)"`
+	// Repeat the race this many times (default 1, max 10)
+	Rounds int `json:"rounds,omitempty" jsonschema:"Times to repeat the race, aggregating outcomes across rounds (default 1, max 10)"`
+	// Delay between rounds in milliseconds
+	RoundDelayMs *int `json:"roundDelayMs,omitempty" jsonschema:"Delay between rounds in milliseconds (default 1000)"`
+	// Body limit in bytes per response (default 500 or config bodyLimits)
+	BodyLimit int `json:"bodyLimit,omitempty" jsonschema:"Response body byte limit per response (default 500 or config bodyLimits, -1 = unlimited)"`
+	// Which parts of over-limit bodies to keep
+	Relevance *RelevanceOptions `json:"relevance,omitempty" jsonschema:"Keep the relevant parts of over-limit bodies (focus strings, error messages, matching JSON fields) instead of their first bodyLimit bytes"`
 	// Return all individual responses (default: deduplicated groups)
 	Raw_ bool `json:"showAll,omitempty" jsonschema:"Return all individual responses instead of deduped groups"`
+	// Content-Length and chunked encoding of the raced requests
+	Framing *BodyFraming `json:"framing,omitempty" jsonschema:"Content-Length override and chunked body encoding for desync races (default: Content-Length matches the body)"`
+	// Header rule profile from config
+	HeaderProfile string `json:"headerProfile,omitempty" jsonschema:"Header rule profile from config (default: 'default')"`
+	// Auth profile from config
+	AuthProfile string `json:"authProfile,omitempty" jsonschema:"Auth profile from config whose credentials are injected at send time"`
+	// TLS options for the direct connections (override config directTLS)
+	TLSConfig *config.TLSOptions `json:"tlsConfig,omitempty" jsonschema:"TLS client certificate, CA bundle, version, and cipher options"`
+	// TLS server name, if different from the target host
+	SNI string `json:"sni,omitempty" jsonschema:"TLS SNI server name (default: target host)"`
+	// TCP connect address, if different from the target host
+	ConnectHost string `json:"connectHost,omitempty" jsonschema:"TCP connect address as host or host:port (default: target host and port)"`
+	// Preview the request instead of racing it
+	DryRun bool `json:"dryRun,omitempty" jsonschema:"Return the exact bytes that would be sent, without sending"`
 }
 
 // RaceResponseEntry holds a single response from the race attack.
+// With several rounds, Index counts across them: connection i of round r is
+// (r-1)*count + i.
 type RaceResponseEntry struct {
 	Index      int    `json:"index"`
+	Round      int    `json:"round,omitempty"`
+	// WarmupStatus is the status of the connection's warm-up response.
+	WarmupStatus int  `json:"warmupStatus,omitempty"`
+	// Request is the 1-based raws/payloads entry this connection sent.
+	Request    int    `json:"request,omitempty"`
 	StatusCode int    `json:"statusCode"`
 	Body       string `json:"body,omitempty"`
+	BodyEnvelope
 }
 
 // RaceGroupEntry holds a deduplicated group of identical responses.
 type RaceGroupEntry struct {
+	Request    int    `json:"request,omitempty"`
 	StatusCode int    `json:"statusCode"`
 	Body       string `json:"body,omitempty"`
-	Count      int    `json:"count"`
-	Indices    []int  `json:"indices"`
+	BodyEnvelope
+	Count   int   `json:"count"`
+	Indices []int `json:"indices"`
+	// Rounds lists the rounds the response came back in.
+	Rounds []int `json:"rounds,omitempty"`
+}
+
+// RaceRoundStats summarizes one round of a multi-round race.
+type RaceRoundStats struct {
+	Round    int    `json:"round"`
+	Statuses string `json:"statuses"`
+	Outcomes int    `json:"outcomes"`
 }
 
 // RaceRequestOutput is the output from burp_race_request.
 type RaceRequestOutput struct {
 	Groups  []RaceGroupEntry    `json:"groups,omitempty"`
 	Results []RaceResponseEntry `json:"results,omitempty"`
+	Rounds    []RaceRoundStats  `json:"rounds,omitempty"`
+	Anomalies []string          `json:"anomalies,omitempty"`
+	Stopped   string            `json:"stopped,omitempty"`
+	// QueueWaitMs is the time spent waiting for a per-host concurrency slot.
+	QueueWaitMs int64 `json:"queueWaitMs,omitempty"`
 	Summary string              `json:"summary"`
+	Preview *RequestPreview     `json:"preview,omitempty"`
 }
 
 func raceRequestHandler() func(context.Context, *mcp.CallToolRequest, RaceRequestInput) (*mcp.CallToolResult, RaceRequestOutput, error) {
 	return func(ctx context.Context, _ *mcp.CallToolRequest, input RaceRequestInput) (*mcp.CallToolResult, RaceRequestOutput, error) {
-		if err := validateRawRequest(input.Raw); err != nil {
+		ctx, queueWait := withQueueWait(ctx)
+		raw, exact, err := rawRequestText(input.Raw, input.RawBase64, input.Normalize)
+		if err != nil {
 			return nil, RaceRequestOutput{}, err
 		}
+		input.Raw = raw
+		raws, err := raceRaws(input)
+		if err != nil {
+			return nil, RaceRequestOutput{}, err
+		}
+		mixed := len(input.Raws) > 0 || len(input.Payloads) > 0
+
+		// Exact requests skip normalization, header rules, auth profiles, and
+		// the Content-Length fix; the warm-up request is still normalized.
+		prepare := func(raw string) (string, *burp.ParsedHTTPRequest, error) {
+			return prepareRequest(raw, input.HeaderProfile)
+		}
+		if exact {
+			if err := checkExactRequest(input.HeaderProfile, input.AuthProfile); err != nil {
+				return nil, RaceRequestOutput{}, err
+			}
+			prepare = func(raw string) (string, *burp.ParsedHTTPRequest, error) {
+				return prepareExactRequest(raw)
+			}
+		}
 
-		parsed := burp.ParseRawRequest(input.Raw)
+		rawNorm, parsed, err := prepare(raws[0])
+		if err != nil {
+			return nil, RaceRequestOutput{}, err
+		}
 
 		t, err := resolveTarget(input.Host, input.Port, input.TLS, parsed.Host)
 		if err != nil {
@@ -79,47 +168,207 @@ func raceRequestHandler() func(context.Context, *mcp.CallToolRequest, RaceReques
 		count := input.Count
 		if count <= 0 {
 			count = defaultRaceCount
+			if mixed {
+				count = len(raws)
+			}
 		}
 		if count > maxRaceCount {
 			count = maxRaceCount
 		}
-
-		// Body limit defaults
-		bodyLimit := input.BodyLimit
-		if bodyLimit == 0 {
-			bodyLimit = defaultRaceBodyLimit
+		if count < len(raws) {
+			return nil, RaceRequestOutput{}, fmt.Errorf("count %d is less than the %d distinct requests", count, len(raws))
+		}
+		rounds := max(input.Rounds, 1)
+		if rounds > maxRaceRounds {
+			return nil, RaceRequestOutput{}, fmt.Errorf("rounds must be 1-%d", maxRaceRounds)
+		}
+		delay := defaultRoundDelay
+		if input.RoundDelayMs != nil {
+			if *input.RoundDelayMs < 0 {
+				return nil, RaceRequestOutput{}, fmt.Errorf("roundDelayMs must not be negative")
+			}
+			delay = time.Duration(*input.RoundDelayMs) * time.Millisecond
 		}
 
-		// Normalize the raw request and fix Content-Length
-		rawNorm := normalizeRawRequest(input.Raw)
-		rawNorm = fixContentLength(rawNorm)
-		rawBytes := []byte(rawNorm)
+		preview := dryRun(input.DryRun)
+
+		// Every request goes down connections to the same target
+		prepared := make([]string, len(raws))
+		for i, raw := range raws {
+			if i > 0 {
+				var p *burp.ParsedHTTPRequest
+				if rawNorm, p, err = prepare(raw); err != nil {
+					return nil, RaceRequestOutput{}, fmt.Errorf("raws[%d]: %w", i, err)
+				}
+				if input.Host == "" && !strings.EqualFold(p.Host, parsed.Host) {
+					return nil, RaceRequestOutput{}, fmt.Errorf("raws[%d]: Host %q differs from %q; races use one target", i, p.Host, parsed.Host)
+				}
+			}
+			contentLength := contentLengthKeep
+			if !exact {
+				// Previews leave the profile out; see previewAuthProfile.
+				if !preview {
+					rawNorm, err = applyAuthProfile(rawNorm, input.AuthProfile, func(raw string, next resolvedTarget) (string, error) {
+						return sendDirect(ctx, next, []byte(raw), newDirectOptions(input.TLSConfig))
+					})
+					if err != nil {
+						return nil, RaceRequestOutput{}, err
+					}
+				}
+				// Fix Content-Length on the normalized request
+				contentLength = contentLengthAuto
+			}
+			if prepared[i], err = input.Framing.apply(rawNorm, contentLength, !exact); err != nil {
+				return nil, RaceRequestOutput{}, err
+			}
+		}
+		var warmup []byte
+		if input.Warmup != "" {
+			w, p, err := prepareRequest(input.Warmup, input.HeaderProfile)
+			if err != nil {
+				return nil, RaceRequestOutput{}, fmt.Errorf("warmup: %w", err)
+			}
+			if input.Host == "" && !strings.EqualFold(p.Host, parsed.Host) {
+				return nil, RaceRequestOutput{}, fmt.Errorf("warmup: Host %q differs from %q; races use one target", p.Host, parsed.Host)
+			}
+			if !preview {
+				w, err = applyAuthProfile(w, input.AuthProfile, func(raw string, next resolvedTarget) (string, error) {
+					return sendDirect(ctx, next, []byte(raw), newDirectOptions(input.TLSConfig))
+				})
+				if err != nil {
+					return nil, RaceRequestOutput{}, err
+				}
+			}
+			// The race goes down the same connection afterwards
+			w = applyHeaderRules(w, config.HeaderRules{Set: map[string]string{"Connection": "keep-alive"}})
+			warmup = []byte(fixContentLength(w))
+		}
+		requests := make([][]byte, count)
+		for i := range requests {
+			requests[i] = []byte(prepared[i%len(prepared)])
+		}
 
 		// Execute the single-packet race attack
-		results, err := executeRace(ctx, t.Host, t.Port, t.UseTLS, rawBytes, count, bodyLimit)
-		if err != nil {
-			return nil, RaceRequestOutput{}, fmt.Errorf("race attack failed: %w", err)
+		opts := newDirectOptions(input.TLSConfig)
+		opts.SNI, opts.ConnectHost = input.SNI, input.ConnectHost
+		if preview {
+			p, err := previewRequest(ctx, t, prepared[0], &opts)
+			if err != nil {
+				return nil, RaceRequestOutput{}, err
+			}
+			if !exact {
+				if err := previewAuthProfile(p, input.AuthProfile); err != nil {
+					return nil, RaceRequestOutput{}, err
+				}
+			}
+			p.Count = count
+			summary := fmt.Sprintf("dry run: %d requests not sent", count)
+			if mixed {
+				summary = fmt.Sprintf("dry run: %d requests (%d distinct, the first previewed) not sent", count, len(prepared))
+			}
+			if rounds > 1 {
+				summary += fmt.Sprintf(", in each of %d rounds", rounds)
+			}
+			if warmup != nil {
+				summary += ", each after a warm-up request"
+			}
+			return nil, RaceRequestOutput{Summary: summary, Preview: p}, nil
+		}
+		approvalSummary := fmt.Sprintf("race %dx %s %s", count, parsed.Method, parsed.Path)
+		if mixed {
+			approvalSummary = fmt.Sprintf("race %dx across %d distinct requests to %s", count, len(prepared), t.Host)
+		}
+		if rounds > 1 {
+			approvalSummary += fmt.Sprintf(", %d rounds", rounds)
+		}
+		if warmup != nil {
+			approvalSummary += ", warmed up"
+		}
+		if err := requireApproval(ctx, "burp_race_request", t, approvalSummary, strings.Join(append(prepared, string(warmup)), "\n")); err != nil {
+			return nil, RaceRequestOutput{}, err
+		}
+
+		// Run the rounds; a failure after the first keeps what came back
+		var results []RaceResponseEntry
+		var stats []RaceRoundStats
+		stopped := ""
+	roundLoop:
+		for round := 1; round <= rounds; round++ {
+			if round > 1 && delay > 0 {
+				select {
+				case <-ctx.Done():
+					stopped = fmt.Sprintf("cancelled before round %d", round)
+					break roundLoop
+				case <-time.After(delay):
+				}
+			}
+			res, err := executeRace(ctx, t.Host, t.Port, t.UseTLS, requests, warmup, input.BodyLimit, input.Relevance, opts)
+			if err != nil {
+				if round == 1 {
+					return nil, RaceRequestOutput{}, fmt.Errorf("race attack failed: %w", err)
+				}
+				stopped = fmt.Sprintf("round %d failed: %v", round, err)
+				break
+			}
+			for i := range res {
+				if mixed {
+					res[i].Request = i%len(prepared) + 1
+				}
+				if rounds > 1 {
+					res[i].Round = round
+					res[i].Index += (round - 1) * count
+				}
+			}
+			if rounds > 1 {
+				stats = append(stats, RaceRoundStats{Round: round, Statuses: raceStatusCounts(res, 0), Outcomes: len(dedupeRaceResults(res))})
+			}
+			results = append(results, res...)
+			reportProgress(ctx, len(results), rounds*count, fmt.Sprintf("round %d of %d", round, rounds))
 		}
 
 		// Build summary
-		statusCounts := make(map[int]int)
-		for _, r := range results {
-			statusCounts[r.StatusCode]++
+		summary := fmt.Sprintf("%d requests sent, responses: %s", len(results), raceStatusCounts(results, 0))
+		if rounds > 1 {
+			summary = fmt.Sprintf("%d rounds of %d requests sent, responses: %s", len(stats), count, raceStatusCounts(results, 0))
+		}
+		if mixed {
+			parts := make([]string, len(prepared))
+			for i := range prepared {
+				parts[i] = fmt.Sprintf("request %d: %s", i+1, raceStatusCounts(results, i+1))
+			}
+			summary += "; " + strings.Join(parts, "; ")
 		}
-		var summaryParts []string
-		for code, cnt := range statusCounts {
-			summaryParts = append(summaryParts, fmt.Sprintf("%dx %d", cnt, code))
+
+		if warmup != nil {
+			warm := make(map[int]int)
+			for _, r := range results {
+				if r.WarmupStatus > 0 {
+					warm[r.WarmupStatus]++
+				}
+			}
+			var parts []string
+			for _, code := range slices.Sorted(maps.Keys(warm)) {
+				parts = append(parts, fmt.Sprintf("%dx %d", warm[code], code))
+			}
+			summary += "; warm-up responses: " + cmp.Or(strings.Join(parts, ", "), "none")
 		}
-		summary := fmt.Sprintf("%d requests sent, responses: %s", count, strings.Join(summaryParts, ", "))
 
-		output := RaceRequestOutput{Summary: summary}
+		output := RaceRequestOutput{Rounds: stats, Stopped: stopped, QueueWaitMs: queueWait().Milliseconds()}
+		groups := dedupeRaceResults(results)
+		if rounds > 1 {
+			output.Anomalies = raceAnomalies(groups, len(stats))
+			if len(output.Anomalies) > 0 {
+				summary += fmt.Sprintf("; anomalies: %d", len(output.Anomalies))
+			}
+		}
+		output.Summary = summary
 
 		if input.Raw_ {
 			// Raw mode: return all individual responses
 			output.Results = results
 		} else {
 			// Default: deduplicate into groups
-			output.Groups = dedupeRaceResults(results)
+			output.Groups = groups
 		}
 
 		return nil, output, nil
@@ -136,8 +385,43 @@ type raceConn struct {
 // executeRace performs a last-byte synchronization race attack.
 // Opens N parallel TCP/TLS connections, sends all-but-last-byte on each,
 // then sends the final byte on all connections simultaneously.
-func executeRace(ctx context.Context, host string, port int, useTLS bool, rawRequest []byte, count int, bodyLimit int) ([]RaceResponseEntry, error) {
-	addr := net.JoinHostPort(host, strconv.Itoa(port))
+// Connection i sends requests[i], so racers can carry different requests.
+// A non-empty warmup is sent and answered on every connection first; a
+// connection whose warm-up fails or is closed sits out the race.
+// A bodyLimit of 0 applies the configured default (see parseResponse), and
+// rel picks what an over-limit body keeps (see parseRelevantResponse).
+func executeRace(ctx context.Context, host string, port int, useTLS bool, requests [][]byte, warmup []byte, bodyLimit int, rel *RelevanceOptions, opts directOptions) ([]RaceResponseEntry, error) {
+	count := len(requests)
+	if err := checkDryRun(ctx); err != nil {
+		return nil, err
+	}
+	target := resolvedTarget{Host: host, Port: port, UseTLS: useTLS}
+	addr, serverName := opts.endpoints(host, port)
+	if err := checkScope(ctx, host, addr); err != nil {
+		return nil, err
+	}
+	sends := count
+	if len(warmup) > 0 {
+		sends *= 2
+	}
+	if err := checkRateLimit(ctx, target, sends); err != nil {
+		return nil, err
+	}
+	// The race is one unit of work: its connections fire together, so it
+	// takes a single concurrency slot
+	release, err := acquireHost(ctx, target)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	var tlsCfg *tls.Config
+	if useTLS {
+		var err error
+		if tlsCfg, err = buildTLSConfig(serverName, opts.TLS); err != nil {
+			return nil, err
+		}
+	}
 
 	deadline := time.Now().Add(raceTimeout)
 	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
@@ -153,7 +437,7 @@ func executeRace(ctx context.Context, host string, port int, useTLS bool, rawReq
 		connWg.Add(1)
 		go func(idx int) {
 			defer connWg.Done()
-			conn, err := dialConn(ctx, addr, host, useTLS, deadline)
+			conn, err := dialConn(ctx, addr, tlsCfg, deadline)
 			if err != nil {
 				connErrors[idx] = err
 				return
@@ -187,15 +471,62 @@ func executeRace(ctx context.Context, host string, port int, useTLS bool, rawReq
 		return nil, fmt.Errorf("all %d connections failed: %v", count, connErrors[0])
 	}
 
-	// Phase 2: Send all-but-last-byte on each connection
-	prefix := rawRequest[:len(rawRequest)-1]
-	lastByte := rawRequest[len(rawRequest)-1:]
+	// Warm-up: one request and response per connection, so each is routed
+	// to its backend and past any cold start before the race
+	warmStatus := make([]int, count)
+	if len(warmup) > 0 {
+		var warmWg sync.WaitGroup
+		for i, rc := range conns {
+			if rc == nil {
+				continue
+			}
+			recordRequests(ctx, target, string(warmup), 1)
+			warmWg.Add(1)
+			go func(idx int, c *raceConn) {
+				defer warmWg.Done()
+				if _, err := c.conn.Write(warmup); err != nil {
+					connErrors[idx] = fmt.Errorf("warm-up write: %w", err)
+					return
+				}
+				resp, err := readHTTPResponse(c.reader)
+				recordResponse(target, len(resp))
+				if err != nil {
+					connErrors[idx] = fmt.Errorf("warm-up: %w", err)
+					return
+				}
+				parsed := burp.ParseHTTPResponse(resp, 0, 0)
+				if parsed == nil {
+					connErrors[idx] = fmt.Errorf("warm-up: unparseable response")
+					return
+				}
+				if strings.EqualFold(burp.GetHeader(parsed.Headers, "Connection"), "close") {
+					connErrors[idx] = fmt.Errorf("warm-up: server closed the connection (status %d)", parsed.StatusCode)
+					return
+				}
+				warmStatus[idx] = parsed.StatusCode
+			}(i, rc)
+		}
+		warmWg.Wait()
+		for i, rc := range conns {
+			if rc != nil && connErrors[i] != nil {
+				rc.conn.Close()
+				conns[i] = nil
+			}
+		}
+	}
+
+	for i, rc := range conns {
+		if rc != nil {
+			recordRequests(ctx, target, string(requests[i]), 1)
+		}
+	}
 
+	// Phase 2: Send all-but-last-byte on each connection
 	for i, rc := range conns {
 		if rc == nil {
 			continue
 		}
-		if _, err := rc.writer.Write(prefix); err != nil {
+		if _, err := rc.writer.Write(requests[i][:len(requests[i])-1]); err != nil {
 			connErrors[i] = fmt.Errorf("prefix write: %w", err)
 			rc.conn.Close()
 			conns[i] = nil
@@ -221,7 +552,7 @@ func executeRace(ctx context.Context, host string, port int, useTLS bool, rawReq
 		go func(idx int, c *raceConn) {
 			defer sendWg.Done()
 			gate.Wait() // Block until gate opens
-			c.conn.Write(lastByte)
+			c.conn.Write(requests[idx][len(requests[idx])-1:])
 		}(i, rc)
 	}
 
@@ -246,18 +577,30 @@ func executeRace(ctx context.Context, host string, port int, useTLS bool, rawReq
 		go func(idx int, c *raceConn) {
 			defer readWg.Done()
 			resp, err := readHTTPResponse(c.reader)
+			recordResponse(target, len(resp))
 			if err != nil {
 				results[idx] = RaceResponseEntry{
 					Index: idx,
 					Body:  fmt.Sprintf("read error: %s", err),
+					WarmupStatus: warmStatus[idx],
 				}
 				return
 			}
-			parsed := burp.ParseHTTPResponse(resp, 0, bodyLimit)
-			entry := RaceResponseEntry{Index: idx}
+			parsed := parseRelevantResponse(ctx, resp, 0, bodyLimit, defaultRaceBodyLimit, rel)
+			entry := RaceResponseEntry{Index: idx, WarmupStatus: warmStatus[idx]}
 			if parsed != nil {
+				cs := bodyCharset(resp)
+				alignWindow(cs, parsed)
 				entry.StatusCode = parsed.StatusCode
-				entry.Body = parsed.Body
+				entry.BodyEnvelope = bodyEnvelope(parsed, 0, "", nil)
+				entry.Body = encodeBody(&entry.BodyEnvelope, parsed.Body, cs, 0, encodingAuto, false)
+				if parsed.Truncated {
+					// A race can't be re-read; only a rerun with a larger limit shows more.
+					entry.ContinuationHint = "rerun with a larger bodyLimit (-1 = unlimited) to see more"
+					if rel != nil {
+						entry.ContinuationHint = "body holds excerpts; " + entry.ContinuationHint
+					}
+				}
 			}
 			results[idx] = entry
 		}(i, rc)
@@ -267,8 +610,8 @@ func executeRace(ctx context.Context, host string, port int, useTLS bool, rawReq
 	return results, nil
 }
 
-// dialConn opens a TCP (optionally TLS) connection.
-func dialConn(ctx context.Context, addr, host string, useTLS bool, deadline time.Time) (net.Conn, error) {
+// dialConn opens a TCP connection, wrapped in TLS when tlsCfg is non-nil.
+func dialConn(ctx context.Context, addr string, tlsCfg *tls.Config, deadline time.Time) (net.Conn, error) {
 	dialer := &net.Dialer{
 		Timeout:  10 * time.Second,
 		Deadline: deadline,
@@ -279,15 +622,11 @@ func dialConn(ctx context.Context, addr, host string, useTLS bool, deadline time
 	}
 	tcpConn.SetDeadline(deadline)
 
-	if !useTLS {
+	if tlsCfg == nil {
 		return tcpConn, nil
 	}
 
-	tlsConn := tls.Client(tcpConn, &tls.Config{
-		ServerName:         host,
-		InsecureSkipVerify: true,
-		NextProtos:         []string{"http/1.1"},
-	})
+	tlsConn := tls.Client(tcpConn, tlsCfg)
 	if err := tlsConn.HandshakeContext(ctx); err != nil {
 		tcpConn.Close()
 		return nil, err
@@ -302,12 +641,20 @@ const maxReadBody = 1 << 20 // 1 MB
 // readHTTPResponse reads a single HTTP response from the buffered reader.
 // Handles both Content-Length and chunked transfer encoding.
 func readHTTPResponse(reader *bufio.Reader) (string, error) {
+	resp, _, err := readResponse(reader)
+	return resp, err
+}
+
+// readResponse is readHTTPResponse that also reports whether the body was
+// delimited by Content-Length or chunking and read to its end, so the
+// connection is positioned at the next response.
+func readResponse(reader *bufio.Reader) (string, bool, error) {
 	var response strings.Builder
 
 	// Read status line
 	statusLine, err := reader.ReadString('\n')
 	if err != nil {
-		return "", fmt.Errorf("reading status line: %w", err)
+		return "", false, fmt.Errorf("reading status line: %w", err)
 	}
 	response.WriteString(statusLine)
 
@@ -317,7 +664,7 @@ func readHTTPResponse(reader *bufio.Reader) (string, error) {
 	for {
 		line, err := reader.ReadString('\n')
 		if err != nil {
-			return "", fmt.Errorf("reading headers: %w", err)
+			return "", false, fmt.Errorf("reading headers: %w", err)
 		}
 		response.WriteString(line)
 
@@ -346,12 +693,14 @@ func readHTTPResponse(reader *bufio.Reader) (string, error) {
 	// Read body
 	// Read body. On partial read errors we return what we have (nil error)
 	// so callers always get usable data even from interrupted connections.
+	framed := contentLength == 0
 	if chunked {
-		body, err := readChunkedBody(reader)
+		body, ended, err := readChunkedBody(reader)
 		if err != nil {
-			return response.String(), nil
+			return response.String(), false, nil
 		}
 		response.WriteString(body)
+		framed = ended
 	} else if contentLength > 0 {
 		readSize := contentLength
 		if readSize > maxReadBody {
@@ -360,35 +709,44 @@ func readHTTPResponse(reader *bufio.Reader) (string, error) {
 		body := make([]byte, readSize)
 		n, err := readFull(reader, body)
 		if err != nil && n == 0 {
-			return response.String(), nil
+			return response.String(), false, nil
 		}
 		response.Write(body[:n])
+		framed = n == contentLength
 	}
 
-	return response.String(), nil
+	return response.String(), framed, nil
 }
 
-// readChunkedBody reads a chunked transfer-encoded body.
+// readChunkedBody reads a chunked transfer-encoded body, and reports
+// whether it ended with the last chunk and trailer section.
 // Caps total bytes read at maxReadBody to prevent OOM from malicious servers.
-func readChunkedBody(reader *bufio.Reader) (string, error) {
+func readChunkedBody(reader *bufio.Reader) (string, bool, error) {
 	var body strings.Builder
 	var totalRead int64
 	for {
 		sizeLine, err := reader.ReadString('\n')
 		if err != nil {
-			return body.String(), err
+			return body.String(), false, err
 		}
 
 		sizeStr := strings.TrimSpace(sizeLine)
 		size, err := strconv.ParseInt(sizeStr, 16, 64)
 		if err != nil {
-			return body.String(), nil
+			return body.String(), false, nil
 		}
 
 		if size == 0 {
-			// Read trailing \r\n
-			reader.ReadString('\n')
-			break
+			// Skip trailers up to the closing blank line
+			for {
+				line, err := reader.ReadString('\n')
+				if err != nil {
+					return body.String(), false, nil
+				}
+				if strings.TrimSpace(line) == "" {
+					return body.String(), true, nil
+				}
+			}
 		}
 
 		// Cap individual chunk and cumulative total
@@ -407,13 +765,13 @@ func readChunkedBody(reader *bufio.Reader) (string, error) {
 		body.Write(chunk[:n])
 		totalRead += int64(n)
 		if err != nil {
-			return body.String(), err
+			return body.String(), false, err
 		}
 
 		// Read trailing \r\n after chunk
 		reader.ReadString('\n')
 	}
-	return body.String(), nil
+	return body.String(), false, nil
 }
 
 // readFull reads exactly len(buf) bytes from reader.
@@ -466,6 +824,7 @@ func fixContentLength(raw string) string {
 // dedupeRaceResults groups identical responses by (statusCode, body).
 func dedupeRaceResults(results []RaceResponseEntry) []RaceGroupEntry {
 	type key struct {
+		request    int
 		statusCode int
 		body       string
 	}
@@ -473,17 +832,25 @@ func dedupeRaceResults(results []RaceResponseEntry) []RaceGroupEntry {
 	groups := make(map[key]*RaceGroupEntry)
 
 	for _, r := range results {
-		k := key{statusCode: r.StatusCode, body: r.Body}
+		k := key{request: r.Request, statusCode: r.StatusCode, body: r.Body}
 		if g, ok := groups[k]; ok {
 			g.Count++
 			g.Indices = append(g.Indices, r.Index)
+			if r.Round > 0 && !slices.Contains(g.Rounds, r.Round) {
+				g.Rounds = append(g.Rounds, r.Round)
+			}
 		} else {
 			order = append(order, k)
 			groups[k] = &RaceGroupEntry{
-				StatusCode: r.StatusCode,
-				Body:       r.Body,
-				Count:      1,
-				Indices:    []int{r.Index},
+				Request:      r.Request,
+				StatusCode:   r.StatusCode,
+				Body:         r.Body,
+				BodyEnvelope: r.BodyEnvelope,
+				Count:        1,
+				Indices:      []int{r.Index},
+			}
+			if r.Round > 0 {
+				groups[k].Rounds = []int{r.Round}
 			}
 		}
 	}
@@ -495,13 +862,87 @@ func dedupeRaceResults(results []RaceResponseEntry) []RaceGroupEntry {
 	return out
 }
 
+// raceRaws returns the distinct requests to race: raws, raw with each
+// payload substituted, or raw alone.
+func raceRaws(input RaceRequestInput) ([]string, error) {
+	switch {
+	case len(input.Raws) > 0:
+		if input.Raw != "" || len(input.Payloads) > 0 {
+			return nil, fmt.Errorf("raws replaces raw and payloads")
+		}
+		if len(input.Raws) > maxRaceCount {
+			return nil, fmt.Errorf("at most %d raws", maxRaceCount)
+		}
+		return input.Raws, nil
+	case input.Raw == "":
+		return nil, fmt.Errorf("raw or raws is required")
+	case len(input.Payloads) > 0:
+		if !strings.Contains(input.Raw, markerPayload) {
+			return nil, fmt.Errorf("raw has no %s marker for payloads", markerPayload)
+		}
+		if len(input.Payloads) > maxRaceCount {
+			return nil, fmt.Errorf("at most %d payloads", maxRaceCount)
+		}
+		raws := make([]string, len(input.Payloads))
+		for i, p := range input.Payloads {
+			raws[i] = strings.ReplaceAll(input.Raw, markerPayload, p)
+		}
+		return raws, nil
+	}
+	return []string{input.Raw}, nil
+}
+
+// raceStatusCounts renders "3x 200, 1x 409" for the results from request
+// (1-based), or from every request when request is 0.
+func raceStatusCounts(results []RaceResponseEntry, request int) string {
+	counts := make(map[int]int)
+	for _, r := range results {
+		if request == 0 || r.Request == request {
+			counts[r.StatusCode]++
+		}
+	}
+	var parts []string
+	for _, code := range slices.Sorted(maps.Keys(counts)) {
+		parts = append(parts, fmt.Sprintf("%dx %d", counts[code], code))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// raceAnomalies flags the outcomes seen in fewer than half of the rounds:
+// the narrow windows a single race tends to miss. Each line names the
+// response and the rounds it came back in.
+func raceAnomalies(groups []RaceGroupEntry, rounds int) []string {
+	if rounds < 2 {
+		return nil
+	}
+	var out []string
+	for _, g := range groups {
+		if len(g.Rounds)*2 >= rounds {
+			continue
+		}
+		label := fmt.Sprintf("%dx %d", g.Count, g.StatusCode)
+		if g.Request > 0 {
+			label = fmt.Sprintf("request %d: %s", g.Request, label)
+		}
+		seen := make([]string, len(g.Rounds))
+		for i, r := range g.Rounds {
+			seen[i] = strconv.Itoa(r)
+		}
+		out = append(out, fmt.Sprintf("%s (indices %v) only in round %s of %d", label, g.Indices, strings.Join(seen, ", "), rounds))
+	}
+	return out
+}
+
 // RegisterRaceRequestTool registers the burp_race_request tool.
 func RegisterRaceRequestTool(server *mcp.Server) {
-	mcp.AddTool(server, &mcp.Tool{
+	addTool(server, &mcp.Tool{
 		Name: "burp_race_request",
-		Description: `Single-packet race condition attack. Sends N identical requests simultaneously. ` +
-			`Returns deduplicated {groups: [{statusCode, body, count, indices}], summary}. ` +
-			`Default: 10 requests, 500B body limit. Use showAll=true for individual responses.`,
+		Description: `Single-packet race condition attack. Sends N requests simultaneously: identical copies of raw, or mixed via raws (different requests, e.g. redeem and check-balance) or payloads (values for a {{payload}} marker in raw), assigned to connections round-robin. ` +
+			`Returns deduplicated {groups: [{request, statusCode, body, count, indices}], summary}. ` +
+			`warmup sends a request on every connection first, so load balancers pin each to a backend and cold starts don't skew the race. ` +
+			`rounds repeats the race (roundDelayMs apart) and adds {rounds: [{round, statuses, outcomes}], anomalies} naming responses seen in only some rounds, since one race often misses a narrow window. ` +
+			`Default: 10 requests, 500B body limit. Use showAll=true for individual responses. ` +
+			`normalize: false or rawBase64 races the bytes exactly as given, for smuggling and parser-differential races; framing overrides Content-Length or re-encodes the body as chunked.`,
 	}, raceRequestHandler())
 }
 
@@ -2,7 +2,13 @@ package tools
 
 import (
 	"bufio"
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 )
 
@@ -117,3 +123,163 @@ func TestReadHTTPResponse_NoBody(t *testing.T) {
 		t.Errorf("status missing: %q", resp)
 	}
 }
+
+func TestRaceRaws(t *testing.T) {
+	raws, err := raceRaws(RaceRequestInput{Raw: "POST /redeem?code={{payload}} HTTP/1.1\r\n\r\n", Payloads: []string{"A", "B"}})
+	if err != nil || len(raws) != 2 || !strings.Contains(raws[1], "code=B ") {
+		t.Errorf("payloads = %q, %v", raws, err)
+	}
+	for _, in := range []RaceRequestInput{
+		{},
+		{Raw: "GET / HTTP/1.1\r\n\r\n", Payloads: []string{"A"}},
+		{Raw: "GET / HTTP/1.1\r\n\r\n", Raws: []string{"GET / HTTP/1.1\r\n\r\n"}},
+	} {
+		if _, err := raceRaws(in); err == nil {
+			t.Errorf("expected error for %+v", in)
+		}
+	}
+}
+
+func TestExecuteRace_Mixed(t *testing.T) {
+	target := startTestTarget(t, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, r.URL.Path)
+	})
+	redeem := []byte(fixContentLength("POST /redeem HTTP/1.1\r\nHost: x\r\n\r\ncode=A"))
+	balance := []byte("GET /balance HTTP/1.1\r\nHost: x\r\n\r\n")
+
+	results, err := executeRace(context.Background(), target.Host, target.Port, false, [][]byte{redeem, balance, redeem, balance}, nil, -1, nil, directOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, r := range results {
+		want := []string{"/redeem", "/balance"}[i%2]
+		if r.StatusCode != 200 || r.Body != want {
+			t.Errorf("results[%d] = %d %q, want 200 %q", i, r.StatusCode, r.Body, want)
+		}
+	}
+}
+
+func TestExecuteRace_Relevance(t *testing.T) {
+	target := startTestTarget(t, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, strings.Repeat("a", 500)+"CANARY"+strings.Repeat("b", 500))
+	})
+	req := []byte("GET / HTTP/1.1\r\nHost: x\r\n\r\n")
+
+	rel := &RelevanceOptions{Focus: []string{"canary"}, Radius: 10}
+	results, err := executeRace(context.Background(), target.Host, target.Port, false, [][]byte{req, req}, nil, 100, rel, directOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, r := range results {
+		if !strings.Contains(r.Body, "CANARY") || !r.Truncated || !strings.HasPrefix(r.ContinuationHint, "body holds excerpts") {
+			t.Errorf("results[%d] = %q %+v", i, r.Body, r.BodyEnvelope)
+		}
+	}
+}
+
+func TestRaceRequest_Rounds(t *testing.T) {
+	var n atomic.Int32
+	target := startTestTarget(t, func(w http.ResponseWriter, r *http.Request) {
+		if n.Add(1) == 5 {
+			w.WriteHeader(http.StatusConflict)
+			fmt.Fprint(w, "won")
+			return
+		}
+		fmt.Fprint(w, "ok")
+	})
+	noTLS, noDelay := false, 0
+	_, out, err := raceRequestHandler()(context.Background(), nil, RaceRequestInput{
+		Raw:          "GET / HTTP/1.1\r\nHost: x\r\n\r\n",
+		Host:         target.Host,
+		Port:         target.Port,
+		TLS:          &noTLS,
+		Count:        2,
+		Rounds:       3,
+		RoundDelayMs: &noDelay,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(out.Rounds) != 3 || out.Rounds[2].Statuses != "1x 200, 1x 409" || out.Rounds[2].Outcomes != 2 {
+		t.Errorf("rounds = %+v", out.Rounds)
+	}
+	if len(out.Groups) != 2 || !slices.Equal(out.Groups[0].Rounds, []int{1, 2, 3}) {
+		t.Fatalf("groups = %+v", out.Groups)
+	}
+	if len(out.Anomalies) != 1 || !strings.Contains(out.Anomalies[0], "1x 409") || !strings.Contains(out.Anomalies[0], "only in round 3 of 3") {
+		t.Errorf("anomalies = %q", out.Anomalies)
+	}
+	if !strings.HasPrefix(out.Summary, "3 rounds of 2 requests sent, responses: 5x 200, 1x 409") {
+		t.Errorf("summary = %q", out.Summary)
+	}
+
+	if _, _, err := raceRequestHandler()(context.Background(), nil, RaceRequestInput{Raw: "GET / HTTP/1.1\r\nHost: x\r\n\r\n", Rounds: maxRaceRounds + 1}); err == nil {
+		t.Error("expected error for too many rounds")
+	}
+}
+
+func TestExecuteRace_Warmup(t *testing.T) {
+	var mu sync.Mutex
+	warmed := map[string]bool{}
+	target := startTestTarget(t, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch r.URL.Path {
+		case "/warm":
+			warmed[r.RemoteAddr] = true
+			w.WriteHeader(http.StatusNoContent)
+		case "/close":
+			w.Header().Set("Connection", "close")
+		default:
+			fmt.Fprint(w, warmed[r.RemoteAddr])
+		}
+	})
+	race := []byte("GET /race HTTP/1.1\r\nHost: x\r\n\r\n")
+	requests := [][]byte{race, race, race}
+
+	results, err := executeRace(context.Background(), target.Host, target.Port, false, requests, []byte("GET /warm HTTP/1.1\r\nHost: x\r\n\r\n"), -1, nil, directOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, r := range results {
+		if r.StatusCode != 200 || r.Body != "true" || r.WarmupStatus != 204 {
+			t.Errorf("results[%d] = %d %q warm-up %d, want 200 \"true\" warm-up 204", i, r.StatusCode, r.Body, r.WarmupStatus)
+		}
+	}
+
+	results, err = executeRace(context.Background(), target.Host, target.Port, false, requests, []byte("GET /close HTTP/1.1\r\nHost: x\r\n\r\n"), -1, nil, directOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, r := range results {
+		if r.StatusCode != 0 || !strings.Contains(r.Body, "closed the connection") {
+			t.Errorf("results[%d] = %d %q, want a closed warm-up connection", i, r.StatusCode, r.Body)
+		}
+	}
+}
+
+func TestRaceRequest_Exact(t *testing.T) {
+	target, received := startRawTarget(t)
+	noTLS, no := false, false
+	// A Content-Length that disagrees with the body survives exact mode.
+	raw := "POST /race HTTP/1.1\nHost: x\nContent-Length: 1\n\nabc"
+	_, out, err := raceRequestHandler()(context.Background(), nil, RaceRequestInput{
+		Raw:       raw,
+		Normalize: &no,
+		Host:      target.Host,
+		Port:      target.Port,
+		TLS:       &noTLS,
+		Count:     2,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for range 2 {
+		if got := string(<-received); got != raw {
+			t.Errorf("sent %q, want %q", got, raw)
+		}
+	}
+	if len(out.Groups) != 1 || out.Groups[0].StatusCode != 200 || out.Groups[0].Count != 2 {
+		t.Errorf("groups = %+v", out.Groups)
+	}
+}
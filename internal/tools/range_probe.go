@@ -0,0 +1,206 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/c0tton-fluff/burp-mcp-server/internal/burp"
+	"github.com/c0tton-fluff/burp-mcp-server/internal/config"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// rangePreviewLimit caps the body excerpt returned per range check.
+const rangePreviewLimit = 200
+
+// rangeCase is one Range header variant to send.
+type rangeCase struct {
+	name  string
+	value string
+}
+
+// rangeCases covers single, multi, overlapping, and absurd ranges.
+var rangeCases = []rangeCase{
+	{"single", "bytes=0-0"},
+	{"suffix", "bytes=-1"},
+	{"multi", "bytes=0-0,1-1,2-2"},
+	{"overlapping", "bytes=0-," + strings.Repeat("0-,", 48) + "0-"},
+	{"reversed", "bytes=5-1"},
+	{"unsatisfiable", "bytes=999999999-"},
+	// HTTP.sys (MS15-034) answers 416 instead of ignoring this range.
+	{"absurd-upper", "bytes=0-18446744073709551615"},
+	{"non-bytes-unit", "items=0-1"},
+}
+
+// RangeProbeInput is the input for burp_range_probe.
+type RangeProbeInput struct {
+	Raw           string `json:"raw" jsonschema:"required,Raw HTTP request for a static or cacheable resource"`
+	Host          string `json:"host,omitempty" jsonschema:"Target host (overrides Host header)"`
+	Port          int    `json:"port,omitempty" jsonschema:"Target port (default based on TLS)"`
+	TLS           *bool  `json:"tls,omitempty" jsonschema:"Use HTTPS (default true)"`
+	HeaderProfile string `json:"headerProfile,omitempty" jsonschema:"Header rule profile from config (default: 'default')"`
+
+	TLSConfig *config.TLSOptions `json:"tlsConfig,omitempty" jsonschema:"TLS client certificate, CA bundle, version, and cipher options"`
+}
+
+// RangeCheck is the outcome of one Range variant.
+type RangeCheck struct {
+	Name         string `json:"name"`
+	Range        string `json:"range,omitempty"`
+	StatusCode   int    `json:"statusCode"`
+	ContentRange string `json:"contentRange,omitempty"`
+	ContentType  string `json:"contentType,omitempty"`
+	BodySize     int    `json:"bodySize"`
+	ElapsedMs    int64  `json:"elapsedMs"`
+	Cached       bool   `json:"cached,omitempty"`
+	Preview      string `json:"preview,omitempty"`
+	Error        string `json:"error,omitempty"`
+}
+
+// RangeProbeOutput is the output of burp_range_probe.
+type RangeProbeOutput struct {
+	AcceptRanges string       `json:"acceptRanges,omitempty"`
+	Checks       []RangeCheck `json:"checks"`
+	Findings     []string     `json:"findings"`
+}
+
+func rangeProbeHandler() func(context.Context, *mcp.CallToolRequest, RangeProbeInput) (*mcp.CallToolResult, RangeProbeOutput, error) {
+	return func(ctx context.Context, _ *mcp.CallToolRequest, input RangeProbeInput) (*mcp.CallToolResult, RangeProbeOutput, error) {
+		rawNorm, parsed, err := prepareRequest(input.Raw, input.HeaderProfile)
+		if err != nil {
+			return nil, RangeProbeOutput{}, err
+		}
+		t, err := resolveTarget(input.Host, input.Port, input.TLS, parsed.Host)
+		if err != nil {
+			return nil, RangeProbeOutput{}, err
+		}
+
+		opts := newDirectOptions(input.TLSConfig)
+		base := applyHeaderRules(rawNorm, config.HeaderRules{Strip: []string{"Range", "If-Range"}})
+		baseline, baseResp := runRangeCheck(ctx, t, opts, "baseline", "", base)
+		if baseline.Error != "" {
+			return nil, RangeProbeOutput{}, fmt.Errorf("baseline request failed: %s", baseline.Error)
+		}
+
+		checks := []RangeCheck{baseline}
+		for _, rc := range rangeCases {
+			req := applyHeaderRules(base, config.HeaderRules{Set: map[string]string{"Range": rc.value}})
+			check, _ := runRangeCheck(ctx, t, opts, rc.name, rc.value, req)
+			checks = append(checks, check)
+		}
+
+		return nil, RangeProbeOutput{
+			AcceptRanges: burp.GetHeader(baseResp.Headers, "Accept-Ranges"),
+			Checks:       checks,
+			Findings:     rangeFindings(baseline, checks[1:]),
+		}, nil
+	}
+}
+
+// runRangeCheck sends one request directly and summarizes the response.
+// The parsed response is nil when the request failed.
+func runRangeCheck(ctx context.Context, t resolvedTarget, opts directOptions, name, rangeValue, rawNorm string) (RangeCheck, *burp.ParsedHTTPResponse) {
+	check := RangeCheck{Name: name, Range: rangeValue}
+	if len(check.Range) > 80 {
+		check.Range = check.Range[:80] + "..."
+	}
+
+	start := time.Now()
+	text, err := sendDirect(ctx, t, []byte(rawNorm), opts)
+	check.ElapsedMs = time.Since(start).Milliseconds()
+	if err != nil {
+		check.Error = err.Error()
+		return check, nil
+	}
+
+	resp := burp.ParseHTTPResponse(text, 0, rangePreviewLimit)
+	if resp == nil || resp.StatusCode == 0 {
+		check.Error = "empty or unparseable response"
+		return check, nil
+	}
+	check.StatusCode = resp.StatusCode
+	check.ContentRange = burp.GetHeader(resp.Headers, "Content-Range")
+	check.ContentType = burp.GetHeader(resp.Headers, "Content-Type")
+	check.BodySize = resp.BodySize
+	check.Preview = resp.Body
+	check.Cached = isCacheHit(resp.Headers)
+	return check, resp
+}
+
+// isCacheHit reports whether response headers indicate a shared-cache hit.
+func isCacheHit(headers map[string][]string) bool {
+	for _, h := range []string{"X-Cache", "CF-Cache-Status", "X-Cache-Status", "X-Proxy-Cache"} {
+		if strings.Contains(strings.ToUpper(burp.GetHeader(headers, h)), "HIT") {
+			return true
+		}
+	}
+	if age, err := strconv.Atoi(burp.GetHeader(headers, "Age")); err == nil && age > 0 {
+		return true
+	}
+	return false
+}
+
+// rangeFindings flags crash, disclosure, and cache-confusion symptoms.
+func rangeFindings(baseline RangeCheck, checks []RangeCheck) []string {
+	findings := []string{}
+	supports := false
+
+	for _, c := range checks {
+		if c.Error != "" {
+			findings = append(findings, fmt.Sprintf("%s range (%s): request failed (%s) while baseline succeeded: possible crash or connection reset", c.Name, c.Range, c.Error))
+			continue
+		}
+		if c.StatusCode >= 500 {
+			findings = append(findings, fmt.Sprintf("%s range: server error %d", c.Name, c.StatusCode))
+		}
+		if c.StatusCode == 206 {
+			supports = true
+		}
+		if c.ElapsedMs > 5*baseline.ElapsedMs+2000 {
+			findings = append(findings, fmt.Sprintf("%s range: response took %dms vs %dms baseline: resource exhaustion symptom", c.Name, c.ElapsedMs, baseline.ElapsedMs))
+		}
+
+		switch c.Name {
+		case "absurd-upper":
+			if c.StatusCode == 416 {
+				findings = append(findings, "Absurd upper bound returned 416: matches the HTTP.sys integer overflow signature (MS15-034), verify the server version")
+			}
+		case "overlapping":
+			if c.StatusCode == 206 && baseline.BodySize > 0 && c.BodySize > 2*baseline.BodySize {
+				findings = append(findings, fmt.Sprintf("Overlapping ranges were all served (%d bytes for a %d-byte resource): amplification / DoS risk (CVE-2011-3192 class)", c.BodySize, baseline.BodySize))
+			}
+		case "single":
+			if c.StatusCode == 206 && c.BodySize > 1 {
+				findings = append(findings, fmt.Sprintf("bytes=0-0 returned %d bytes instead of 1: range arithmetic error, inspect for memory disclosure", c.BodySize))
+			}
+			if c.StatusCode == 206 && c.ContentRange == "" {
+				findings = append(findings, "206 without Content-Range: caches may store the partial body as the full resource")
+			}
+		case "reversed", "unsatisfiable":
+			if c.StatusCode == 206 {
+				findings = append(findings, fmt.Sprintf("Invalid %s range (%s) was answered with 206", c.Name, c.Range))
+			}
+		}
+
+		if c.StatusCode == 206 && c.Cached {
+			findings = append(findings, fmt.Sprintf("%s range: 206 served from a shared cache: partial content may be returned for unranged requests (cache confusion)", c.Name))
+		}
+	}
+
+	if !supports {
+		findings = append(findings, "No 206 responses: Range appears unsupported or ignored for this resource")
+	}
+	return findings
+}
+
+// RegisterRangeProbeTool registers the burp_range_probe tool.
+func RegisterRangeProbeTool(server *mcp.Server) {
+	addTool(server, &mcp.Tool{
+		Name: "burp_range_probe",
+		Description: `Probe Range header handling directly (bypasses Burp for exact bytes): single, multi, overlapping, reversed, and absurd ranges. ` +
+			`Detects crash, memory-disclosure, amplification, and cache-confusion symptoms. ` +
+			`Returns {checks: [{name, range, statusCode, contentRange, bodySize, elapsedMs, cached, preview}], findings}.`,
+	}, rangeProbeHandler())
+}
@@ -0,0 +1,58 @@
+package tools
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+var fixedModTime = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func TestRunRangeCheck_PartialContent(t *testing.T) {
+	target := startTestTarget(t, func(w http.ResponseWriter, r *http.Request) {
+		http.ServeContent(w, r, "file.txt", fixedModTime, strings.NewReader("hello world"))
+	})
+
+	raw := "GET /file.txt HTTP/1.1\r\nHost: example.com\r\nRange: bytes=0-0\r\n\r\n"
+	check, resp := runRangeCheck(context.Background(), target, directOptions{}, "single", "bytes=0-0", raw)
+	if check.Error != "" || resp == nil {
+		t.Fatalf("unexpected error: %s", check.Error)
+	}
+	if check.StatusCode != 206 || check.BodySize != 1 || check.ContentRange != "bytes 0-0/11" {
+		t.Errorf("got %+v", check)
+	}
+}
+
+func TestRangeFindings_AbsurdRange416(t *testing.T) {
+	baseline := RangeCheck{Name: "baseline", StatusCode: 200, BodySize: 11}
+	checks := []RangeCheck{
+		{Name: "single", StatusCode: 206, BodySize: 1, ContentRange: "bytes 0-0/11"},
+		{Name: "absurd-upper", StatusCode: 416},
+	}
+	got := strings.Join(rangeFindings(baseline, checks), "\n")
+	if !strings.Contains(got, "MS15-034") {
+		t.Errorf("missing MS15-034 finding: %s", got)
+	}
+	if strings.Contains(got, "unsupported") {
+		t.Errorf("206 present, should not report unsupported: %s", got)
+	}
+}
+
+func TestRangeFindings_OverlappingAmplification(t *testing.T) {
+	baseline := RangeCheck{Name: "baseline", StatusCode: 200, BodySize: 100}
+	checks := []RangeCheck{{Name: "overlapping", StatusCode: 206, BodySize: 5000, Cached: true}}
+	got := strings.Join(rangeFindings(baseline, checks), "\n")
+	if !strings.Contains(got, "amplification") || !strings.Contains(got, "cache confusion") {
+		t.Errorf("got %s", got)
+	}
+}
+
+func TestRangeFindings_Unsupported(t *testing.T) {
+	baseline := RangeCheck{Name: "baseline", StatusCode: 200}
+	got := rangeFindings(baseline, []RangeCheck{{Name: "single", StatusCode: 200}})
+	if len(got) != 1 || !strings.Contains(got[0], "unsupported") {
+		t.Errorf("got %v", got)
+	}
+}
@@ -0,0 +1,161 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/c0tton-fluff/burp-mcp-server/internal/config"
+)
+
+// RateLimitError is returned when a request would exceed a configured rate limit.
+// Nothing is sent; the caller can retry after RetryAfter.
+type RateLimitError struct {
+	Scope      string // "global", "host", or "tool"
+	Key        string // host or tool name; empty for global
+	RetryAfter time.Duration
+}
+
+func (e *RateLimitError) Error() string {
+	scope := e.Scope
+	if e.Key != "" {
+		scope += " " + e.Key
+	}
+	return fmt.Sprintf("rate_limited: %s limit reached, retry after %.1fs", scope, e.RetryAfter.Seconds())
+}
+
+// tokenBucket refills at rate tokens per second up to burst.
+type tokenBucket struct {
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(l config.RateLimit, now time.Time) *tokenBucket {
+	burst := float64(l.Burst)
+	if burst == 0 {
+		burst = math.Max(1, math.Ceil(l.RPS))
+	}
+	return &tokenBucket{rate: l.RPS, burst: burst, tokens: burst, last: now}
+}
+
+func (b *tokenBucket) refill(now time.Time) {
+	b.tokens = math.Min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	b.last = now
+}
+
+// wait returns how long until n tokens are available (0 if they are now).
+// Costs above the burst size are capped, so they need a full bucket.
+func (b *tokenBucket) wait(n float64) time.Duration {
+	n = math.Min(n, b.burst)
+	if b.tokens >= n {
+		return 0
+	}
+	return time.Duration((n - b.tokens) / b.rate * float64(time.Second))
+}
+
+func (b *tokenBucket) take(n float64) {
+	b.tokens = math.Max(0, b.tokens-math.Min(n, b.burst))
+}
+
+// rateLimiter enforces the configured global, per-host, and per-tool limits.
+type rateLimiter struct {
+	mu    sync.Mutex
+	cfg   config.RateLimits
+	now   func() time.Time
+	bkts  map[string]*tokenBucket // keyed by scope + ":" + key
+	empty bool
+}
+
+func newRateLimiter(cfg config.RateLimits) *rateLimiter {
+	return &rateLimiter{
+		cfg:   cfg,
+		now:   time.Now,
+		bkts:  make(map[string]*tokenBucket),
+		empty: cfg.Global == nil && cfg.PerHost == nil && len(cfg.Hosts) == 0 && len(cfg.Tools) == 0,
+	}
+}
+
+// limiter is the server-wide rate limiter, rebuilt by Configure.
+var limiter = newRateLimiter(config.RateLimits{})
+
+// scopedLimit is a configured limit and the bucket it draws from.
+type scopedLimit struct {
+	scope, key string
+	limit      config.RateLimit
+}
+
+// applicable returns the limits that apply to a request from tool to host.
+func (r *rateLimiter) applicable(tool, host string) []scopedLimit {
+	host = strings.ToLower(host)
+	var applicable []scopedLimit
+	if r.cfg.Global != nil {
+		applicable = append(applicable, scopedLimit{"global", "", *r.cfg.Global})
+	}
+	if l, ok := r.cfg.Hosts[host]; ok {
+		applicable = append(applicable, scopedLimit{"host", host, l})
+	} else if r.cfg.PerHost != nil {
+		applicable = append(applicable, scopedLimit{"host", host, *r.cfg.PerHost})
+	}
+	if l, ok := r.cfg.Tools[tool]; ok {
+		applicable = append(applicable, scopedLimit{"tool", tool, l})
+	}
+	return applicable
+}
+
+// maxRPS returns the lowest configured rate for requests from tool to host,
+// or 0 when none applies. External scanners that send on their own are
+// started with it, since the limiter can't admit their requests one by one.
+func (r *rateLimiter) maxRPS(tool, host string) float64 {
+	var rps float64
+	for _, a := range r.applicable(tool, host) {
+		if rps == 0 || a.limit.RPS < rps {
+			rps = a.limit.RPS
+		}
+	}
+	return rps
+}
+
+// allow takes n tokens from every limit that applies to a request from tool
+// to host, or takes none and returns a *RateLimitError.
+func (r *rateLimiter) allow(tool, host string, n int) error {
+	if r.empty || n <= 0 {
+		return nil
+	}
+	applicable := r.applicable(tool, host)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := r.now()
+	buckets := make([]*tokenBucket, len(applicable))
+	var blocked *RateLimitError
+	for i, a := range applicable {
+		id := a.scope + ":" + a.key
+		b, ok := r.bkts[id]
+		if !ok {
+			b = newTokenBucket(a.limit, now)
+			r.bkts[id] = b
+		}
+		b.refill(now)
+		buckets[i] = b
+		if w := b.wait(float64(n)); w > 0 && (blocked == nil || w > blocked.RetryAfter) {
+			blocked = &RateLimitError{Scope: a.scope, Key: a.key, RetryAfter: w}
+		}
+	}
+	if blocked != nil {
+		return blocked
+	}
+	for _, b := range buckets {
+		b.take(float64(n))
+	}
+	return nil
+}
+
+// checkRateLimit admits n outbound requests to t on behalf of the tool in ctx.
+func checkRateLimit(ctx context.Context, t resolvedTarget, n int) error {
+	return limiter.allow(toolName(ctx), t.Host, n)
+}
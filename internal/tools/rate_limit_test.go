@@ -0,0 +1,106 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/c0tton-fluff/burp-mcp-server/internal/config"
+)
+
+// newTestLimiter returns a limiter driven by a manually advanced clock.
+func newTestLimiter(cfg config.RateLimits) (*rateLimiter, func(time.Duration)) {
+	now := time.Unix(0, 0)
+	r := newRateLimiter(cfg)
+	r.now = func() time.Time { return now }
+	return r, func(d time.Duration) { now = now.Add(d) }
+}
+
+func TestRateLimiter_PerHost(t *testing.T) {
+	r, advance := newTestLimiter(config.RateLimits{
+		PerHost: &config.RateLimit{RPS: 2},
+		Hosts:   map[string]config.RateLimit{"prod.example": {RPS: 1, Burst: 1}},
+	})
+
+	for i := 0; i < 2; i++ {
+		if err := r.allow("", "a.example", 1); err != nil {
+			t.Fatalf("request %d: %v", i, err)
+		}
+	}
+	err := r.allow("", "A.example", 1)
+	var rl *RateLimitError
+	if !errors.As(err, &rl) || rl.Scope != "host" || rl.Key != "a.example" || rl.RetryAfter != 500*time.Millisecond {
+		t.Fatalf("expected host rate limit, got %v", err)
+	}
+
+	// Other hosts have their own buckets; prod has a stricter override.
+	if err := r.allow("", "b.example", 1); err != nil {
+		t.Errorf("b.example: %v", err)
+	}
+	if err := r.allow("", "prod.example", 1); err != nil {
+		t.Errorf("prod first request: %v", err)
+	}
+	if err := r.allow("", "prod.example", 1); err == nil {
+		t.Error("prod second request should be limited")
+	}
+
+	advance(500 * time.Millisecond)
+	if err := r.allow("", "a.example", 1); err != nil {
+		t.Errorf("after refill: %v", err)
+	}
+}
+
+func TestRateLimiter_AllOrNothing(t *testing.T) {
+	r, _ := newTestLimiter(config.RateLimits{
+		Global: &config.RateLimit{RPS: 10},
+		Tools:  map[string]config.RateLimit{"burp_race_request": {RPS: 1, Burst: 1}},
+	})
+	if err := r.allow("burp_race_request", "x.example", 1); err != nil {
+		t.Fatal(err)
+	}
+	// The tool limit blocks, so the global bucket must not be charged.
+	if err := r.allow("burp_race_request", "x.example", 1); err == nil {
+		t.Fatal("expected tool limit")
+	}
+	for i := 0; i < 9; i++ {
+		if err := r.allow("burp_send_request", "x.example", 1); err != nil {
+			t.Fatalf("global request %d: %v", i, err)
+		}
+	}
+	if err := r.allow("burp_send_request", "x.example", 1); err == nil {
+		t.Error("expected global limit")
+	}
+}
+
+func TestRateLimiter_CostAboveBurstNeedsFullBucket(t *testing.T) {
+	r, advance := newTestLimiter(config.RateLimits{Global: &config.RateLimit{RPS: 5, Burst: 5}})
+	if err := r.allow("", "x", 30); err != nil {
+		t.Fatalf("full bucket should admit an oversized race: %v", err)
+	}
+	if err := r.allow("", "x", 30); err == nil {
+		t.Error("empty bucket should not")
+	}
+	advance(time.Second)
+	if err := r.allow("", "x", 30); err != nil {
+		t.Errorf("refilled bucket: %v", err)
+	}
+}
+
+func TestSendDirect_RateLimited(t *testing.T) {
+	orig := limiter
+	t.Cleanup(func() { limiter = orig })
+	limiter = newRateLimiter(config.RateLimits{Global: &config.RateLimit{RPS: 1, Burst: 1}})
+
+	target := startTestTarget(t, func(w http.ResponseWriter, r *http.Request) {})
+	raw := []byte("GET / HTTP/1.1\r\nHost: x\r\n\r\n")
+	if _, err := sendDirect(context.Background(), target, raw, directOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	_, err := sendDirect(context.Background(), target, raw, directOptions{})
+	var rl *RateLimitError
+	if !errors.As(err, &rl) {
+		t.Errorf("expected RateLimitError, got %v", err)
+	}
+}
@@ -0,0 +1,245 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/c0tton-fluff/burp-mcp-server/internal/burp"
+	"github.com/c0tton-fluff/burp-mcp-server/internal/config"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const (
+	defaultRateProbeCount = 30
+	maxRateProbeCount     = 200
+	defaultRateProbeRPS   = 10
+	maxRateProbeRPS       = 50
+)
+
+// RateProbeInput is the input for burp_rate_probe.
+type RateProbeInput struct {
+	Raw           string   `json:"raw" jsonschema:"required,Raw HTTP request to repeat (login, OTP check, API call)"`
+	Count         int      `json:"count,omitempty" jsonschema:"Requests per burst (default 30, max 200)"`
+	RPS           float64  `json:"rps,omitempty" jsonschema:"Send rate in requests per second (default 10, max 50)"`
+	RotateIP      bool     `json:"rotateIp,omitempty" jsonschema:"Once limited, send a second burst with a different spoofed client IP per request to test whether the limit is keyed on a header"`
+	IPHeaders     []string `json:"ipHeaders,omitempty" jsonschema:"Headers carrying the spoofed IP with rotateIp (default: X-Forwarded-For)"`
+	Host          string   `json:"host,omitempty" jsonschema:"Target host (overrides Host header)"`
+	Port          int      `json:"port,omitempty" jsonschema:"Target port (default based on TLS)"`
+	TLS           *bool    `json:"tls,omitempty" jsonschema:"Use HTTPS (default true)"`
+	HeaderProfile string   `json:"headerProfile,omitempty" jsonschema:"Header rule profile from config (default: 'default')"`
+	Instance      string   `json:"instance,omitempty" jsonschema:"Named Burp instance from config (default: the --burp-url connection)"`
+}
+
+// RateBurst is the outcome of one burst.
+type RateBurst struct {
+	Responses int `json:"responses"`
+	// LimitedAt is the 1-based request that first got 429 or 503 (0: never).
+	LimitedAt int               `json:"limitedAt,omitempty"`
+	Limited   int               `json:"limited"`
+	Statuses  []int             `json:"statuses"`
+	Headers   map[string]string `json:"headers,omitempty"`
+	Errors    []string          `json:"errors,omitempty"`
+}
+
+// RateProbeOutput is the output of burp_rate_probe.
+type RateProbeOutput struct {
+	Burst    RateBurst  `json:"burst"`
+	Rotated  *RateBurst `json:"rotated,omitempty"`
+	Findings []string   `json:"findings"`
+}
+
+// isRateLimitHeader reports whether name is a rate limit header worth reporting.
+func isRateLimitHeader(name string) bool {
+	lower := strings.ToLower(name)
+	return lower == "retry-after" || strings.HasPrefix(lower, "x-ratelimit") ||
+		strings.HasPrefix(lower, "x-rate-limit") || strings.HasPrefix(lower, "ratelimit")
+}
+
+// spoofedIP returns a distinct TEST-NET-3 address for request i.
+func spoofedIP(i int) string {
+	return fmt.Sprintf("203.0.%d.%d", 113+i/254, i%254+1)
+}
+
+// runRateBurst sends count requests at rps, each on its own schedule so a
+// slow response doesn't hold back the next. rotate, if set, rewrites request i.
+func runRateBurst(ctx context.Context, client *burp.Client, rawNorm string, t resolvedTarget, count int, rps float64, rotate func(raw string, i int) string) RateBurst {
+	statuses := make([]int, count)
+	errs := make([]string, count)
+	headers := map[string]string{}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+
+	interval := time.Duration(float64(time.Second) / rps)
+	start := time.Now()
+	for i := range count {
+		if wait := time.Until(start.Add(time.Duration(i) * interval)); wait > 0 {
+			select {
+			case <-ctx.Done():
+				errs[i] = ctx.Err().Error()
+				continue
+			case <-time.After(wait):
+			}
+		}
+		req := rawNorm
+		if rotate != nil {
+			req = rotate(rawNorm, i)
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, err := sendParsed(ctx, client, req, t, 1)
+			if err != nil {
+				errs[i] = err.Error()
+				return
+			}
+			statuses[i] = resp.StatusCode
+			mu.Lock()
+			defer mu.Unlock()
+			for name, values := range resp.Headers {
+				if _, seen := headers[name]; !seen && isRateLimitHeader(name) {
+					headers[name] = strings.Join(values, ", ")
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	b := RateBurst{Statuses: statuses, Headers: headers}
+	for i, s := range statuses {
+		if s != 0 {
+			b.Responses++
+		}
+		if s == 429 || s == 503 {
+			b.Limited++
+			if b.LimitedAt == 0 {
+				b.LimitedAt = i + 1
+			}
+		}
+		if errs[i] != "" {
+			b.Errors = append(b.Errors, fmt.Sprintf("request %d: %s", i+1, errs[i]))
+		}
+	}
+	return b
+}
+
+func rateProbeHandler(client *burp.Client) func(context.Context, *mcp.CallToolRequest, RateProbeInput) (*mcp.CallToolResult, RateProbeOutput, error) {
+	return func(ctx context.Context, _ *mcp.CallToolRequest, input RateProbeInput) (*mcp.CallToolResult, RateProbeOutput, error) {
+		ctx = burp.WithInstance(ctx, input.Instance)
+
+		count := input.Count
+		if count == 0 {
+			count = defaultRateProbeCount
+		}
+		if count < 1 || count > maxRateProbeCount {
+			return nil, RateProbeOutput{}, fmt.Errorf("count must be 1-%d", maxRateProbeCount)
+		}
+		rps := input.RPS
+		if rps == 0 {
+			rps = defaultRateProbeRPS
+		}
+		if rps <= 0 || rps > maxRateProbeRPS {
+			return nil, RateProbeOutput{}, fmt.Errorf("rps must be above 0 and at most %d", maxRateProbeRPS)
+		}
+		ipHeaders := input.IPHeaders
+		if len(ipHeaders) == 0 {
+			ipHeaders = []string{"X-Forwarded-For"}
+		}
+
+		rawNorm, parsed, err := prepareRequest(input.Raw, input.HeaderProfile)
+		if err != nil {
+			return nil, RateProbeOutput{}, err
+		}
+		t, err := resolveTarget(input.Host, input.Port, input.TLS, parsed.Host)
+		if err != nil {
+			return nil, RateProbeOutput{}, err
+		}
+		if err := checkDryRun(ctx); err != nil {
+			return nil, RateProbeOutput{}, err
+		}
+
+		out := RateProbeOutput{Burst: runRateBurst(ctx, client, rawNorm, t, count, rps, nil)}
+		if input.RotateIP && out.Burst.LimitedAt > 0 {
+			rotate := func(raw string, i int) string {
+				set := make(map[string]string, len(ipHeaders))
+				for _, h := range ipHeaders {
+					set[h] = spoofedIP(i)
+				}
+				return applyHeaderRules(raw, config.HeaderRules{Set: set})
+			}
+			rotated := runRateBurst(ctx, client, rawNorm, t, count, rps, rotate)
+			out.Rotated = &rotated
+		}
+		out.Findings = rateFindings(out.Burst, out.Rotated, rps, ipHeaders)
+		return nil, out, nil
+	}
+}
+
+// rateFindings describes when limiting began, what the server said about it,
+// and whether rotating the client IP header escaped it.
+func rateFindings(burst RateBurst, rotated *RateBurst, rps float64, ipHeaders []string) []string {
+	findings := []string{}
+	local := 0
+	for _, e := range burst.Errors {
+		if strings.Contains(e, "rate_limited:") {
+			local++
+		}
+	}
+	if local > 0 {
+		findings = append(findings, fmt.Sprintf("%d requests were held back by this server's own rate limit: raise it for this host or lower rps", local))
+	}
+
+	if burst.LimitedAt == 0 {
+		findings = append(findings, fmt.Sprintf("No 429/503 after %d requests at %.1f rps: no effective rate limit on this endpoint", burst.Responses, rps))
+	} else {
+		findings = append(findings, fmt.Sprintf("Limited from request %d (about %.1fs in at %.1f rps); %d of %d requests got 429/503",
+			burst.LimitedAt, float64(burst.LimitedAt-1)/rps, rps, burst.Limited, burst.Responses))
+		if burst.Limited < burst.Responses-burst.LimitedAt+1 {
+			findings = append(findings, "Some requests after the first limited one succeeded again: the limit is a short window or enforced by only some backends")
+		}
+		if _, ok := headerValue(burst.Headers, "Retry-After"); !ok {
+			findings = append(findings, "Limited responses carry no Retry-After")
+		}
+	}
+	if len(burst.Headers) > 0 {
+		names := slices.Sorted(maps.Keys(burst.Headers))
+		findings = append(findings, "Rate limit headers: "+strings.Join(names, ", "))
+	}
+
+	if rotated != nil {
+		hdrs := strings.Join(ipHeaders, "/")
+		switch {
+		case rotated.LimitedAt == 0:
+			findings = append(findings, fmt.Sprintf("Rotating %s bypassed the limit: all %d requests went through while the client was limited, so the limit is keyed on a spoofable header", hdrs, rotated.Responses))
+		case rotated.LimitedAt > 1:
+			findings = append(findings, fmt.Sprintf("Rotating %s reset the counter: limited again only from request %d", hdrs, rotated.LimitedAt))
+		default:
+			findings = append(findings, fmt.Sprintf("Rotating %s did not help: the limit is not keyed on it", hdrs))
+		}
+	}
+	return findings
+}
+
+// headerValue looks up a header case-insensitively in a flat header map.
+func headerValue(headers map[string]string, name string) (string, bool) {
+	for k, v := range headers {
+		if strings.EqualFold(k, name) {
+			return v, true
+		}
+	}
+	return "", false
+}
+
+// RegisterRateProbeTool registers the burp_rate_probe tool.
+func RegisterRateProbeTool(server *mcp.Server, client *burp.Client) {
+	addTool(server, &mcp.Tool{
+		Name: "burp_rate_probe",
+		Description: `Send a burst of identical requests at a fixed rate and report when 429/503 responses begin, which rate limit headers (Retry-After, X-RateLimit-*, RateLimit-*) appear, ` +
+			`and with rotateIp whether a spoofed X-Forwarded-For (or ipHeaders) per request escapes the limit. ` +
+			`Returns {burst: {responses, limitedAt, limited, statuses, headers}, rotated, findings}.`,
+	}, rateProbeHandler(client))
+}
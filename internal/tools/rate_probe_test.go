@@ -0,0 +1,62 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestIsRateLimitHeader(t *testing.T) {
+	for name, want := range map[string]bool{
+		"Retry-After":           true,
+		"X-RateLimit-Remaining": true,
+		"X-Rate-Limit-Reset":    true,
+		"RateLimit-Policy":      true,
+		"X-Request-Id":          false,
+	} {
+		if got := isRateLimitHeader(name); got != want {
+			t.Errorf("%s = %v, want %v", name, got, want)
+		}
+	}
+}
+
+func TestSpoofedIP(t *testing.T) {
+	seen := map[string]bool{}
+	for i := range maxRateProbeCount {
+		ip := spoofedIP(i)
+		if seen[ip] {
+			t.Fatalf("duplicate %s at %d", ip, i)
+		}
+		seen[ip] = true
+	}
+	if spoofedIP(0) != "203.0.113.1" {
+		t.Errorf("got %s", spoofedIP(0))
+	}
+}
+
+func TestRateFindings_Unlimited(t *testing.T) {
+	got := rateFindings(RateBurst{Responses: 30, Statuses: make([]int, 30)}, nil, 10, nil)
+	if len(got) != 1 || !strings.Contains(got[0], "No 429/503 after 30 requests at 10.0 rps") {
+		t.Errorf("got %v", got)
+	}
+}
+
+func TestRateFindings_LimitedAndBypassed(t *testing.T) {
+	burst := RateBurst{Responses: 30, LimitedAt: 11, Limited: 20, Headers: map[string]string{"X-RateLimit-Limit": "10"},
+		Errors: []string{"request 31: rate_limited: host a.com limit reached, retry after 1.0s"}}
+	rotated := &RateBurst{Responses: 30}
+	got := strings.Join(rateFindings(burst, rotated, 10, []string{"X-Forwarded-For"}), "\n")
+	for _, want := range []string{
+		"1 requests were held back by this server's own rate limit",
+		"Limited from request 11 (about 1.0s in at 10.0 rps); 20 of 30",
+		"no Retry-After",
+		"Rate limit headers: X-RateLimit-Limit",
+		"Rotating X-Forwarded-For bypassed the limit",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("missing %q in:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "succeeded again") {
+		t.Errorf("unexpected window finding:\n%s", got)
+	}
+}
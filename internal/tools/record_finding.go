@@ -0,0 +1,64 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/c0tton-fluff/burp-mcp-server/internal/store"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// RecordFindingInput is the input for burp_record_finding.
+type RecordFindingInput struct {
+	Title       string            `json:"title" jsonschema:"required,Short finding title"`
+	Severity    string            `json:"severity,omitempty" jsonschema:"Severity: critical, high, medium, low, or info"`
+	Description string            `json:"description,omitempty" jsonschema:"Impact and context"`
+	Steps       []store.Step      `json:"steps" jsonschema:"required,Reproduction requests, replayed in order; assertions apply to the last response"`
+	Assertions  []store.Assertion `json:"assertions" jsonschema:"required,Conditions that hold while the issue is present"`
+}
+
+// RecordFindingOutput is the output of burp_record_finding.
+type RecordFindingOutput struct {
+	Finding store.Finding `json:"finding"`
+}
+
+func recordFindingHandler(st *store.Store) func(context.Context, *mcp.CallToolRequest, RecordFindingInput) (*mcp.CallToolResult, RecordFindingOutput, error) {
+	return func(_ context.Context, _ *mcp.CallToolRequest, input RecordFindingInput) (*mcp.CallToolResult, RecordFindingOutput, error) {
+		if input.Title == "" {
+			return nil, RecordFindingOutput{}, fmt.Errorf("title is required")
+		}
+		if len(input.Steps) == 0 {
+			return nil, RecordFindingOutput{}, fmt.Errorf("at least one reproduction step is required")
+		}
+		for i, step := range input.Steps {
+			if err := validateRawRequest(step.Raw); err != nil {
+				return nil, RecordFindingOutput{}, fmt.Errorf("step %d: %w", i, err)
+			}
+		}
+		if err := validateAssertions(input.Assertions); err != nil {
+			return nil, RecordFindingOutput{}, err
+		}
+
+		f, err := st.AddFinding(store.Finding{
+			Title:       input.Title,
+			Severity:    input.Severity,
+			Description: input.Description,
+			Steps:       input.Steps,
+			Assertions:  input.Assertions,
+		})
+		if err != nil {
+			return nil, RecordFindingOutput{}, err
+		}
+		return nil, RecordFindingOutput{Finding: f}, nil
+	}
+}
+
+// RegisterRecordFindingTool registers the burp_record_finding tool.
+func RegisterRecordFindingTool(server *mcp.Server, st *store.Store) {
+	addTool(server, &mcp.Tool{
+		Name: "burp_record_finding",
+		Description: `Record a finding with its reproduction requests and assertions describing the vulnerable response, for later retesting. ` +
+			`Assertion types: status, body_contains, body_not_contains, body_regex, header_contains, header_absent. ` +
+			`Returns {finding: {id, title, steps, assertions, createdAt}}.`,
+	}, recordFindingHandler(st))
+}
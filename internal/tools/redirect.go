@@ -0,0 +1,132 @@
+package tools
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/c0tton-fluff/burp-mcp-server/internal/burp"
+	"github.com/c0tton-fluff/burp-mcp-server/internal/config"
+)
+
+const (
+	defaultMaxRedirects = 5
+	maxRedirectsCap     = 20
+)
+
+// RedirectHop is one 3xx response followed in a redirect chain.
+type RedirectHop struct {
+	URL        string `json:"url"`
+	StatusCode int    `json:"statusCode"`
+	Location   string `json:"location"`
+}
+
+// sendFunc sends a prepared request and returns the raw response text.
+type sendFunc func(rawNorm string, parsed *burp.ParsedHTTPRequest, t resolvedTarget) (string, error)
+
+// followRedirects chases 3xx responses starting from text, the response to rawNorm.
+// It returns the final response, the hops followed, and the final request URL.
+// When maxHops is reached the last 3xx is returned as the final response.
+func followRedirects(send sendFunc, rawNorm string, parsed *burp.ParsedHTTPRequest, t resolvedTarget, text string, maxHops int) (string, []RedirectHop, string, error) {
+	chain := []RedirectHop{}
+	current := requestURL(parsed, t)
+	for len(chain) < maxHops {
+		resp := burp.ParseHTTPResponse(text, 0, 1)
+		if resp == nil || !isRedirectStatus(resp.StatusCode) {
+			break
+		}
+		location := burp.GetHeader(resp.Headers, "Location")
+		if location == "" {
+			break
+		}
+
+		next, nextTarget, nextURL, err := redirectRequest(rawNorm, parsed, t, resp.StatusCode, location)
+		if err != nil {
+			return "", nil, "", fmt.Errorf("redirect %d: %w", len(chain)+1, err)
+		}
+		chain = append(chain, RedirectHop{URL: current.String(), StatusCode: resp.StatusCode, Location: location})
+
+		rawNorm, parsed, t, current = next, burp.ParseRawRequest(next), nextTarget, nextURL
+		if text, err = send(rawNorm, parsed, t); err != nil {
+			return "", nil, "", fmt.Errorf("redirect %d (%s): %w", len(chain), current, err)
+		}
+	}
+	return text, chain, current.String(), nil
+}
+
+func isRedirectStatus(code int) bool {
+	switch code {
+	case 301, 302, 303, 307, 308:
+		return true
+	}
+	return false
+}
+
+// requestURL reconstructs the absolute URL of a request sent to t.
+func requestURL(parsed *burp.ParsedHTTPRequest, t resolvedTarget) *url.URL {
+	u := &url.URL{Scheme: "http", Host: parsed.Host}
+	if t.UseTLS {
+		u.Scheme = "https"
+	}
+	if u.Host == "" {
+		u.Host = net.JoinHostPort(t.Host, strconv.Itoa(t.Port))
+	}
+	if ref, err := url.ParseRequestURI(parsed.Path); err == nil {
+		u.Path, u.RawPath, u.RawQuery = ref.Path, ref.RawPath, ref.RawQuery
+	}
+	return u
+}
+
+// redirectRequest builds the follow-up request for a 3xx response, the way a
+// browser would: 303 (and 301/302 after POST) become a bodyless GET, 307/308
+// keep the method and body. Credentials are dropped when the origin changes.
+func redirectRequest(rawNorm string, parsed *burp.ParsedHTTPRequest, t resolvedTarget, status int, location string) (string, resolvedTarget, *url.URL, error) {
+	base := requestURL(parsed, t)
+	loc, err := url.Parse(location)
+	if err != nil {
+		return "", t, nil, fmt.Errorf("invalid Location %q: %w", location, err)
+	}
+	next := base.ResolveReference(loc)
+	if next.Scheme != "http" && next.Scheme != "https" {
+		return "", t, nil, fmt.Errorf("unsupported redirect scheme %q", next.Scheme)
+	}
+
+	nextTarget := t
+	var rules config.HeaderRules
+	if next.Scheme != base.Scheme || next.Host != base.Host {
+		var err error
+		if nextTarget, err = resolveTarget(next.Host, 0, boolPtr(next.Scheme == "https"), ""); err != nil {
+			return "", t, nil, err
+		}
+		rules.Set = map[string]string{"Host": next.Host}
+		if next.Hostname() != base.Hostname() {
+			rules.Strip = []string{"Authorization", "Cookie"}
+		}
+	}
+
+	method := parsed.Method
+	body := true
+	if status == 303 && method != "HEAD" || (status == 301 || status == 302) && method == "POST" {
+		method, body = "GET", false
+		rules.Strip = append(rules.Strip, "Content-Length", "Content-Type", "Transfer-Encoding")
+	}
+
+	proto := "HTTP/1.1"
+	lineEnd := strings.Index(rawNorm, "\r\n")
+	if parts := strings.SplitN(rawNorm[:lineEnd], " ", 3); len(parts) == 3 {
+		proto = parts[2]
+	}
+	raw := method + " " + next.RequestURI() + " " + proto + rawNorm[lineEnd:]
+	if !body {
+		if idx := strings.Index(raw, "\r\n\r\n"); idx >= 0 {
+			raw = raw[:idx+4]
+		}
+	}
+	return applyHeaderRules(raw, rules), nextTarget, next, nil
+}
+
+func boolPtr(b bool) *bool {
+	return &b
+}
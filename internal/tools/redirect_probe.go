@@ -0,0 +1,273 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+
+	"github.com/c0tton-fluff/burp-mcp-server/internal/burp"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// maxRedirectPayloads caps default plus custom payloads per probe.
+const maxRedirectPayloads = 40
+
+// RedirectProbeInput is the input for burp_redirect_probe.
+type RedirectProbeInput struct {
+	Raw            string   `json:"raw" jsonschema:"required,Raw HTTP request with the redirect parameter"`
+	Param          string   `json:"param" jsonschema:"required,Name of the parameter to inject into (e.g. next, returnUrl)"`
+	In             string   `json:"in,omitempty" jsonschema:"Where the parameter lives: query or body (form-encoded). Default: wherever it already is, else query"`
+	Payloads       []string `json:"payloads,omitempty" jsonschema:"Extra payloads, inserted as-is (URL-encode as needed)"`
+	AttackerDomain string   `json:"attackerDomain,omitempty" jsonschema:"Domain the attacker controls (default: evil.example)"`
+	Host           string   `json:"host,omitempty" jsonschema:"Target host (overrides Host header)"`
+	Port           int      `json:"port,omitempty" jsonschema:"Target port (default based on TLS)"`
+	TLS            *bool    `json:"tls,omitempty" jsonschema:"Use HTTPS (default true)"`
+	HeaderProfile  string   `json:"headerProfile,omitempty" jsonschema:"Header rule profile from config (default: 'default')"`
+	Instance       string   `json:"instance,omitempty" jsonschema:"Named Burp instance from config (default: the --burp-url connection)"`
+}
+
+// RedirectCheck is the outcome of one payload.
+type RedirectCheck struct {
+	Payload    string `json:"payload"`
+	StatusCode int    `json:"statusCode,omitempty"`
+	Location   string `json:"location,omitempty"`
+	// Chain holds the same-host hop followed on the way to the destination.
+	Chain       []RedirectHop `json:"chain,omitempty"`
+	Destination string        `json:"destination,omitempty"`
+	External    bool          `json:"external,omitempty"`
+	Error       string        `json:"error,omitempty"`
+}
+
+// RedirectProbeOutput is the output of burp_redirect_probe.
+type RedirectProbeOutput struct {
+	Param    string          `json:"param"`
+	In       string          `json:"in"`
+	Checks   []RedirectCheck `json:"checks"`
+	Findings []string        `json:"findings"`
+}
+
+// redirectPayloads are the canonical open-redirect bypasses for a target
+// host, as query-safe strings: absolute and scheme-relative URLs, backslash
+// and tab tricks, userinfo, and trusted-host prefixes.
+func redirectPayloads(host, attacker string) []string {
+	return []string{
+		"https://" + attacker,
+		"//" + attacker,
+		"///" + attacker,
+		"/%5C" + attacker,
+		"%2F%2F" + attacker,
+		"/%09/" + attacker,
+		"https:" + attacker,
+		"//" + attacker + "%2F%2E%2E",
+		"https://" + host + "@" + attacker,
+		"https://" + host + "." + attacker,
+		"https://" + attacker + "%23." + host,
+		"https://" + attacker + "%3F." + host,
+		"javascript:alert(document.domain)//",
+	}
+}
+
+// injectParam sets param to value in the request's query or form body and
+// reports where it went. in may be empty to pick the parameter's current
+// location, falling back to the query.
+func injectParam(raw, param, value, in string) (string, string, error) {
+	r, err := splitRawRequest(raw)
+	if err != nil {
+		return "", "", err
+	}
+	path, query, _ := strings.Cut(r.target, "?")
+	if in == "" {
+		in = "query"
+		if !hasParam(query, param) && hasParam(r.body, param) {
+			in = "body"
+		}
+	}
+	switch in {
+	case "query":
+		r.target = path + "?" + setQuery(query, map[string]string{param: value}, nil)
+		return r.String(), in, nil
+	case "body":
+		r.body = setQuery(r.body, map[string]string{param: value}, nil)
+		return fixContentLength(r.String()), in, nil
+	}
+	return "", "", fmt.Errorf("in must be query or body, got %q", in)
+}
+
+// hasParam reports whether a form-encoded string has param.
+func hasParam(form, param string) bool {
+	values, err := url.ParseQuery(form)
+	return err == nil && values.Has(param)
+}
+
+func redirectProbeHandler(client *burp.Client) func(context.Context, *mcp.CallToolRequest, RedirectProbeInput) (*mcp.CallToolResult, RedirectProbeOutput, error) {
+	return func(ctx context.Context, _ *mcp.CallToolRequest, input RedirectProbeInput) (*mcp.CallToolResult, RedirectProbeOutput, error) {
+		ctx = burp.WithInstance(ctx, input.Instance)
+
+		if input.Param == "" {
+			return nil, RedirectProbeOutput{}, fmt.Errorf("param is required")
+		}
+		rawNorm, parsed, err := prepareRequest(input.Raw, input.HeaderProfile)
+		if err != nil {
+			return nil, RedirectProbeOutput{}, err
+		}
+		t, err := resolveTarget(input.Host, input.Port, input.TLS, parsed.Host)
+		if err != nil {
+			return nil, RedirectProbeOutput{}, err
+		}
+		attacker := strings.TrimSuffix(strings.TrimSpace(input.AttackerDomain), ".")
+		if attacker == "" {
+			attacker = defaultAttackerDomain
+		}
+		host := parsed.Host
+		if host == "" {
+			host = t.Host
+		}
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+
+		payloads := append(redirectPayloads(host, attacker), input.Payloads...)
+		if len(payloads) > maxRedirectPayloads {
+			return nil, RedirectProbeOutput{}, fmt.Errorf("max %d payloads, got %d", maxRedirectPayloads, len(payloads))
+		}
+		_, in, err := injectParam(rawNorm, input.Param, "", input.In)
+		if err != nil {
+			return nil, RedirectProbeOutput{}, err
+		}
+
+		checks := make([]RedirectCheck, len(payloads))
+		parallel(len(payloads), func(i int) {
+			checks[i] = runRedirectCheck(ctx, client, rawNorm, input.Param, payloads[i], in, t, host)
+		})
+
+		return nil, RedirectProbeOutput{
+			Param:    input.Param,
+			In:       in,
+			Checks:   checks,
+			Findings: redirectFindings(checks, attacker),
+		}, nil
+	}
+}
+
+// runRedirectCheck sends one payload. A redirect that stays on host is
+// followed for one hop, so redirects bounced through a local endpoint are
+// caught; external destinations are reported but never contacted.
+func runRedirectCheck(ctx context.Context, client *burp.Client, rawNorm, param, payload, in string, t resolvedTarget, host string) RedirectCheck {
+	check := RedirectCheck{Payload: payload}
+	raw, _, err := injectParam(rawNorm, param, payload, in)
+	if err != nil {
+		check.Error = err.Error()
+		return check
+	}
+	parsed := burp.ParseRawRequest(raw)
+	text, err := sendWithFallback(ctx, client, raw, parsed, t)
+	if err != nil {
+		check.Error = err.Error()
+		return check
+	}
+	resp := burp.ParseHTTPResponse(text, 0, 1)
+	if resp == nil {
+		check.Error = "failed to parse response"
+		return check
+	}
+	check.StatusCode = resp.StatusCode
+	check.Location = burp.GetHeader(resp.Headers, "Location")
+	if !isRedirectStatus(resp.StatusCode) || check.Location == "" {
+		return check
+	}
+
+	dest, err := resolveLocation(requestURL(parsed, t), check.Location)
+	if err != nil {
+		check.Error = err.Error()
+		return check
+	}
+	if isExternal(dest, host) {
+		check.Destination, check.External = dest.String(), true
+		return check
+	}
+
+	send := func(rawNorm string, parsed *burp.ParsedHTTPRequest, t resolvedTarget) (string, error) {
+		return sendWithFallback(ctx, client, rawNorm, parsed, t)
+	}
+	final, chain, finalURL, err := followRedirects(send, raw, parsed, t, text, 1)
+	if err != nil {
+		check.Error = err.Error()
+		return check
+	}
+	check.Chain = chain
+	next := burp.ParseHTTPResponse(final, 0, 1)
+	if next == nil || !isRedirectStatus(next.StatusCode) {
+		return check
+	}
+	location := burp.GetHeader(next.Headers, "Location")
+	base, err := url.Parse(finalURL)
+	if location == "" || err != nil {
+		return check
+	}
+	if dest, err := resolveLocation(base, location); err == nil && isExternal(dest, host) {
+		check.Destination, check.External = dest.String(), true
+	}
+	return check
+}
+
+// resolveLocation resolves a Location header against the request URL. Script
+// schemes are returned as-is so they can be reported.
+func resolveLocation(base *url.URL, location string) (*url.URL, error) {
+	loc, err := url.Parse(strings.TrimSpace(location))
+	if err != nil {
+		return nil, fmt.Errorf("invalid Location %q: %w", location, err)
+	}
+	return base.ResolveReference(loc), nil
+}
+
+// isExternal reports whether u leaves host: another host, or a script URL.
+func isExternal(u *url.URL, host string) bool {
+	return isScriptScheme(u) || u.Hostname() != "" && !strings.EqualFold(u.Hostname(), host)
+}
+
+func isScriptScheme(u *url.URL) bool {
+	switch strings.ToLower(u.Scheme) {
+	case "javascript", "data", "vbscript":
+		return true
+	}
+	return false
+}
+
+// redirectFindings lists the payloads that redirected off the target.
+func redirectFindings(checks []RedirectCheck, attacker string) []string {
+	findings := []string{}
+	for _, c := range checks {
+		if !c.External {
+			continue
+		}
+		dest, _ := url.Parse(c.Destination)
+		via := ""
+		if len(c.Chain) > 0 {
+			via = " via " + c.Chain[0].Location
+		}
+		switch {
+		case dest != nil && isScriptScheme(dest):
+			findings = append(findings, fmt.Sprintf("%s: Location is a %s: URL%s: XSS if a browser or client-side redirect follows it", c.Payload, strings.ToLower(dest.Scheme), via))
+		case dest != nil && (strings.EqualFold(dest.Hostname(), attacker) || strings.HasSuffix(strings.ToLower(dest.Hostname()), "."+strings.ToLower(attacker))):
+			findings = append(findings, fmt.Sprintf("%s: open redirect to %s%s", c.Payload, c.Destination, via))
+		default:
+			findings = append(findings, fmt.Sprintf("%s: redirects off-site to %s%s: check whether the destination is attacker-influenced", c.Payload, c.Destination, via))
+		}
+	}
+	if len(findings) == 0 {
+		findings = append(findings, "No payload redirected to an external host")
+	}
+	return findings
+}
+
+// RegisterRedirectProbeTool registers the burp_redirect_probe tool.
+func RegisterRedirectProbeTool(server *mcp.Server, client *burp.Client) {
+	addTool(server, &mcp.Tool{
+		Name: "burp_redirect_probe",
+		Description: `Inject canonical open-redirect payloads (//host, /\host, https:host, userinfo, trusted-host prefix, javascript:) into a query or form parameter, ` +
+			`follow a same-host first hop, and report which payloads redirect to an external host. External destinations are never contacted. ` +
+			`Returns {param, in, checks: [{payload, statusCode, location, chain, destination, external}], findings}.`,
+	}, redirectProbeHandler(client))
+}
@@ -0,0 +1,75 @@
+package tools
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestInjectParam(t *testing.T) {
+	raw := "GET /login?next=%2Fhome&x=1 HTTP/1.1\r\nHost: a.com\r\n\r\n"
+	got, in, err := injectParam(raw, "next", "//evil.example", "")
+	if err != nil || in != "query" {
+		t.Fatalf("in=%q err=%v", in, err)
+	}
+	if !strings.HasPrefix(got, "GET /login?next=//evil.example&x=1 HTTP/1.1\r\n") {
+		t.Errorf("got %q", got)
+	}
+
+	form := "POST /login HTTP/1.1\r\nHost: a.com\r\nContent-Type: application/x-www-form-urlencoded\r\nContent-Length: 20\r\n\r\nuser=a&returnUrl=%2F"
+	got, in, err = injectParam(form, "returnUrl", "https://evil.example", "")
+	if err != nil || in != "body" {
+		t.Fatalf("in=%q err=%v", in, err)
+	}
+	if !strings.HasSuffix(got, "\r\n\r\nuser=a&returnUrl=https://evil.example") || !strings.Contains(got, "Content-Length: 37\r\n") {
+		t.Errorf("got %q", got)
+	}
+
+	got, in, _ = injectParam("GET / HTTP/1.1\r\nHost: a.com\r\n\r\n", "to", "x", "")
+	if in != "query" || !strings.HasPrefix(got, "GET /?to=x ") {
+		t.Errorf("missing param: in=%q got %q", in, got)
+	}
+	if _, _, err := injectParam(raw, "next", "x", "header"); err == nil {
+		t.Error("want error for in=header")
+	}
+}
+
+func TestIsExternal(t *testing.T) {
+	base, _ := url.Parse("https://a.com/login")
+	for loc, want := range map[string]bool{
+		"/home":                       false,
+		"https://a.com/x":             false,
+		"//evil.example":              true,
+		"https://a.com@evil.example/": true,
+		"javascript:alert(1)":         true,
+		"https://A.COM/":              false,
+	} {
+		u, err := resolveLocation(base, loc)
+		if err != nil {
+			t.Fatalf("%s: %v", loc, err)
+		}
+		if got := isExternal(u, "a.com"); got != want {
+			t.Errorf("%s: external = %v, want %v", loc, got, want)
+		}
+	}
+}
+
+func TestRedirectFindings(t *testing.T) {
+	checks := []RedirectCheck{
+		{Payload: "//evil.example", StatusCode: 302, Location: "//evil.example", Destination: "https://evil.example", External: true},
+		{Payload: "javascript:alert(1)", StatusCode: 302, Location: "javascript:alert(1)", Destination: "javascript:alert(1)", External: true},
+		{Payload: "/%5Cevil.example", StatusCode: 302, Location: "/bounce", Chain: []RedirectHop{{Location: "/bounce"}}, Destination: "https://other.net/", External: true},
+		{Payload: "https://evil.example", StatusCode: 400},
+	}
+	got := strings.Join(redirectFindings(checks, "evil.example"), "\n")
+	for _, want := range []string{"//evil.example: open redirect to https://evil.example", "javascript: URL", "off-site to https://other.net/ via /bounce"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("missing %q in:\n%s", want, got)
+		}
+	}
+
+	none := redirectFindings(checks[3:], "evil.example")
+	if len(none) != 1 || !strings.Contains(none[0], "No payload") {
+		t.Errorf("got %v", none)
+	}
+}
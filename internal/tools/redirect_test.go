@@ -0,0 +1,107 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/c0tton-fluff/burp-mcp-server/internal/burp"
+)
+
+func TestRedirectRequest_SeeOtherBecomesGet(t *testing.T) {
+	raw := normalizeRawRequest("POST /login HTTP/1.1\nHost: example.com\nContent-Type: application/x-www-form-urlencoded\nContent-Length: 7\nCookie: s=1\n\nuser=me")
+	target := resolvedTarget{Host: "example.com", Port: 443, UseTLS: true}
+
+	next, nextTarget, u, err := redirectRequest(raw, burp.ParseRawRequest(raw), target, 303, "/home?x=1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(next, "GET /home?x=1 HTTP/1.1\r\n") {
+		t.Errorf("request line: %q", next)
+	}
+	if strings.Contains(next, "Content-Length") || strings.HasSuffix(next, "user=me") {
+		t.Errorf("body and entity headers should be dropped: %q", next)
+	}
+	if !strings.Contains(next, "Cookie: s=1") {
+		t.Errorf("same-origin redirect should keep cookies: %q", next)
+	}
+	if nextTarget != target || u.String() != "https://example.com/home?x=1" {
+		t.Errorf("target %+v, url %s", nextTarget, u)
+	}
+}
+
+func TestRedirectRequest_TemporaryKeepsMethod(t *testing.T) {
+	raw := normalizeRawRequest("PUT /a HTTP/1.1\nHost: example.com\nContent-Length: 2\n\nhi")
+	next, _, _, err := redirectRequest(raw, burp.ParseRawRequest(raw), resolvedTarget{Host: "example.com", Port: 443, UseTLS: true}, 307, "/b")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(next, "PUT /b HTTP/1.1\r\n") || !strings.Contains(next, "\r\n\r\nhi") {
+		t.Errorf("307 should keep method and body: %q", next)
+	}
+}
+
+func TestRedirectRequest_CrossOrigin(t *testing.T) {
+	raw := normalizeRawRequest("GET / HTTP/1.1\nHost: example.com\nAuthorization: Bearer x\nCookie: s=1\n\n")
+	next, nextTarget, _, err := redirectRequest(raw, burp.ParseRawRequest(raw), resolvedTarget{Host: "example.com", Port: 443, UseTLS: true}, 302, "http://other.example:8080/p")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if nextTarget != (resolvedTarget{Host: "other.example", Port: 8080, UseTLS: false}) {
+		t.Errorf("target = %+v", nextTarget)
+	}
+	if !strings.Contains(next, "Host: other.example:8080") || strings.Contains(next, "Authorization") || strings.Contains(next, "Cookie") {
+		t.Errorf("cross-origin request: %q", next)
+	}
+}
+
+func TestRedirectRequest_BadScheme(t *testing.T) {
+	raw := normalizeRawRequest("GET / HTTP/1.1\nHost: example.com\n\n")
+	if _, _, _, err := redirectRequest(raw, burp.ParseRawRequest(raw), resolvedTarget{Host: "example.com", Port: 443, UseTLS: true}, 302, "javascript:alert(1)"); err == nil {
+		t.Error("expected error for non-HTTP scheme")
+	}
+}
+
+func TestFollowRedirects_Chain(t *testing.T) {
+	target := startTestTarget(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/a":
+			http.Redirect(w, r, "/b", http.StatusFound)
+		case "/b":
+			http.Redirect(w, r, "/c", http.StatusMovedPermanently)
+		default:
+			fmt.Fprint(w, "final")
+		}
+	})
+	send := func(rawNorm string, _ *burp.ParsedHTTPRequest, next resolvedTarget) (string, error) {
+		return sendDirect(context.Background(), next, []byte(rawNorm), directOptions{})
+	}
+	raw := normalizeRawRequest("GET /a HTTP/1.1\nHost: example.com\n\n")
+	parsed := burp.ParseRawRequest(raw)
+
+	first, err := send(raw, parsed, target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	text, chain, finalURL, err := followRedirects(send, raw, parsed, target, first, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasSuffix(text, "final") || len(chain) != 2 || finalURL != "http://example.com/c" {
+		t.Errorf("text %q, chain %+v, final %s", text, chain, finalURL)
+	}
+	if chain[0].StatusCode != 302 || chain[0].URL != "http://example.com/a" || chain[1].StatusCode != 301 {
+		t.Errorf("chain = %+v", chain)
+	}
+
+	// A one-hop limit stops on the second redirect.
+	text, chain, _, err = followRedirects(send, raw, parsed, target, first, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(chain) != 1 || !strings.HasPrefix(text, "HTTP/1.1 301") {
+		t.Errorf("limited chain %+v, text %q", chain, text)
+	}
+}
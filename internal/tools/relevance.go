@@ -0,0 +1,161 @@
+package tools
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// RelevanceOptions picks which parts of an over-limit response body to keep.
+type RelevanceOptions struct {
+	Focus      []string `json:"focus,omitempty" jsonschema:"Strings to keep context around, e.g. a reflected canary (case-insensitive)"`
+	JSONFields []string `json:"jsonFields,omitempty" jsonschema:"JSON bodies: keep only fields whose name contains one of these (case-insensitive)"`
+	Radius     int      `json:"radius,omitempty" jsonschema:"Bytes of context kept either side of a match (default 150)"`
+}
+
+const defaultRelevanceRadius = 150
+
+// errorPattern matches text that usually explains a failure: error pages,
+// exceptions, stack frames, and database errors.
+var errorPattern = regexp.MustCompile(`(?i)\b(error|exception|traceback|stack ?trace|fatal|warning|denied|forbidden|invalid|unauthorized|syntax|sqlstate|ora-\d+)\b`)
+
+// relevantBody returns at most limit bytes of body, chosen by opts instead of
+// simply keeping the head. JSON bodies with jsonFields are reduced to the
+// matching fields; otherwise windows around focus strings, then error
+// messages, are kept in body order with markers for the skipped bytes.
+// base is the offset of body within the full response body.
+func relevantBody(body, contentType string, opts RelevanceOptions, limit, base int) string {
+	if len(opts.JSONFields) > 0 && strings.Contains(strings.ToLower(contentType), "json") {
+		if filtered, ok := filterJSONFields(body, opts.JSONFields); ok && len(filtered) <= limit {
+			return filtered
+		}
+	}
+
+	radius := opts.Radius
+	if radius <= 0 {
+		radius = defaultRelevanceRadius
+	}
+	var matches []int
+	for _, f := range opts.Focus {
+		if f == "" {
+			continue
+		}
+		for _, loc := range regexp.MustCompile("(?i)"+regexp.QuoteMeta(f)).FindAllStringIndex(body, -1) {
+			matches = append(matches, loc[0])
+		}
+	}
+	for _, loc := range errorPattern.FindAllStringIndex(body, -1) {
+		matches = append(matches, loc[0])
+	}
+	if len(matches) == 0 {
+		return body[:limit]
+	}
+
+	// Take windows in priority order (focus, then errors) until the budget
+	// runs out, then emit them in body order.
+	var windows []window
+	for _, m := range matches {
+		w := window{max(0, m-radius), min(len(body), m+radius)}
+		next := mergeWindows(append(slices.Clone(windows), w))
+		if size := coveredBytes(next); size > limit {
+			if len(windows) == 0 {
+				start := max(0, min(m-limit/2, len(body)-limit))
+				windows = []window{{start, start + limit}}
+			}
+			break
+		}
+		windows = next
+	}
+
+	var sb strings.Builder
+	pos := 0
+	for _, w := range windows {
+		if w.start > pos {
+			fmt.Fprintf(&sb, "\n[... %d bytes skipped, next excerpt at offset %d ...]\n", w.start-pos, base+w.start)
+		}
+		sb.WriteString(body[w.start:w.end])
+		pos = w.end
+	}
+	if pos < len(body) {
+		fmt.Fprintf(&sb, "\n[... %d bytes skipped ...]", len(body)-pos)
+	}
+	return sb.String()
+}
+
+// window is a byte range [start, end) of a body.
+type window struct{ start, end int }
+
+// mergeWindows sorts ws and joins overlapping or touching windows.
+func mergeWindows(ws []window) []window {
+	slices.SortFunc(ws, func(a, b window) int { return a.start - b.start })
+	var out []window
+	for _, w := range ws {
+		if n := len(out); n > 0 && w.start <= out[n-1].end {
+			out[n-1].end = max(out[n-1].end, w.end)
+			continue
+		}
+		out = append(out, w)
+	}
+	return out
+}
+
+func coveredBytes(ws []window) int {
+	n := 0
+	for _, w := range ws {
+		n += w.end - w.start
+	}
+	return n
+}
+
+// filterJSONFields reduces a JSON document to the fields whose name contains
+// one of names, as a flat object keyed by path (e.g. "data.users[0].email").
+func filterJSONFields(body string, names []string) (string, bool) {
+	var doc any
+	if err := json.Unmarshal([]byte(body), &doc); err != nil {
+		return "", false
+	}
+	found := make(map[string]any)
+	var walk func(path string, v any)
+	walk = func(path string, v any) {
+		switch t := v.(type) {
+		case map[string]any:
+			for k, child := range t {
+				p := k
+				if path != "" {
+					p = path + "." + k
+				}
+				if containsAnyFold(k, names) {
+					found[p] = child
+					continue
+				}
+				walk(p, child)
+			}
+		case []any:
+			for i, child := range t {
+				walk(path+"["+strconv.Itoa(i)+"]", child)
+			}
+		}
+	}
+	walk("", doc)
+	if len(found) == 0 {
+		return "", false
+	}
+	out, err := json.Marshal(found)
+	if err != nil {
+		return "", false
+	}
+	return string(out), true
+}
+
+func containsAnyFold(s string, subs []string) bool {
+	s = strings.ToLower(s)
+	for _, sub := range subs {
+		if sub != "" && strings.Contains(s, strings.ToLower(sub)) {
+			return true
+		}
+	}
+	return false
+}
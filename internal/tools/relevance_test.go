@@ -0,0 +1,62 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestRelevantBody_FocusAndErrors(t *testing.T) {
+	body := strings.Repeat("a", 1000) + "CANARY123" + strings.Repeat("b", 1000) + "Fatal error: mysqli_query()" + strings.Repeat("c", 1000)
+	got := relevantBody(body, "text/html", RelevanceOptions{Focus: []string{"canary123"}, Radius: 20}, 200, 0)
+
+	for _, want := range []string{"CANARY123", "Fatal error", "next excerpt at offset 980", "bytes skipped ..."} {
+		if !strings.Contains(got, want) {
+			t.Errorf("missing %q in:\n%s", want, got)
+		}
+	}
+	if strings.Index(got, "CANARY") > strings.Index(got, "Fatal") {
+		t.Error("excerpts should be in body order")
+	}
+}
+
+func TestRelevantBody_Budget(t *testing.T) {
+	body := strings.Repeat("x", 500) + "needle" + strings.Repeat("y", 500)
+	got := relevantBody(body, "text/plain", RelevanceOptions{Focus: []string{"needle"}}, 50, 0)
+	want := "\n" + strings.Repeat("x", 25) + "needle" + strings.Repeat("y", 19) + "\n"
+	if !strings.Contains(got, want) {
+		t.Errorf("window not centered within budget: %q", got)
+	}
+
+	if got := relevantBody(strings.Repeat("z", 100), "text/plain", RelevanceOptions{Focus: []string{"absent"}}, 10, 0); got != strings.Repeat("z", 10) {
+		t.Errorf("no matches should keep the head, got %q", got)
+	}
+}
+
+func TestRelevantBody_JSONFields(t *testing.T) {
+	body := `{"data":{"users":[{"id":1,"email":"a@x","bio":"` + strings.Repeat("x", 500) + `"},{"id":2,"Email":"b@x"}]}}`
+	got := relevantBody(body, "application/json", RelevanceOptions{JSONFields: []string{"email"}}, 100, 0)
+	var fields map[string]string
+	if err := json.Unmarshal([]byte(got), &fields); err != nil {
+		t.Fatalf("not JSON: %s", got)
+	}
+	if fields["data.users[0].email"] != "a@x" || fields["data.users[1].Email"] != "b@x" || len(fields) != 2 {
+		t.Errorf("fields = %v", fields)
+	}
+}
+
+func TestParseRelevantResponse(t *testing.T) {
+	raw := "HTTP/1.1 500 Internal Server Error\r\nContent-Type: text/html\r\n\r\n" +
+		strings.Repeat("<div></div>", 100) + "Traceback (most recent call last)"
+	resp := parseRelevantResponse(context.Background(), raw, 0, 100, defaultBodyLimit, &RelevanceOptions{})
+	if !resp.Truncated || !strings.Contains(resp.Body, "Traceback") {
+		t.Errorf("body = %q", resp.Body)
+	}
+
+	// Bodies within the limit are returned unchanged.
+	resp = parseRelevantResponse(context.Background(), raw, 0, -1, defaultBodyLimit, &RelevanceOptions{})
+	if resp.Truncated || len(resp.Body) != resp.BodySize {
+		t.Errorf("unlimited body was changed")
+	}
+}
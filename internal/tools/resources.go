@@ -0,0 +1,389 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/c0tton-fluff/burp-mcp-server/internal/burp"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// Resource URIs. Items are addressed by their 1-based position, the same
+// index the tools use; the collections list the newest items and can be
+// subscribed to, optionally narrowed with ?host=.
+const (
+	historyURI = "burp://history"
+	issuesURI  = "burp://issues"
+
+	// recentResourceItems is how many of the newest items a collection lists.
+	recentResourceItems = 20
+	// maxNewPerPoll bounds how many new items one poll reads per collection;
+	// the rest are picked up by the next poll.
+	maxNewPerPoll = 100
+	// resourcePollInterval is how often Burp is checked for new items while
+	// a client is subscribed.
+	resourcePollInterval = 15 * time.Second
+)
+
+// ResourceItem is one entry in a collection listing.
+type ResourceItem struct {
+	URI        string `json:"uri"`
+	Method     string `json:"method,omitempty"`
+	URL        string `json:"url,omitempty"`
+	StatusCode int    `json:"statusCode,omitempty"`
+	Name       string `json:"name,omitempty"`
+	Severity   string `json:"severity,omitempty"`
+}
+
+// ResourceCollection is the content of burp://history and burp://issues.
+type ResourceCollection struct {
+	Total int            `json:"total"`
+	Host  string         `json:"host,omitempty"`
+	Items []ResourceItem `json:"items"` // newest first
+}
+
+// HistoryResource is the content of burp://history/{index}.
+type HistoryResource struct {
+	Index    int    `json:"index"`
+	Request  string `json:"request"`
+	Response string `json:"response,omitempty"`
+}
+
+// collection fetches items of one kind from Burp.
+type collection struct {
+	uri  string
+	tool string // Burp tool listing the items
+	// item converts the raw entry at index (1-based) to a listing item.
+	item func(raw string, index int) (ResourceItem, bool)
+}
+
+var (
+	historyCollection = collection{uri: historyURI, tool: "get_proxy_http_history", item: func(raw string, index int) (ResourceItem, bool) {
+		e, _ := parseSingleHistoryEntry(raw, index)
+		if e == nil {
+			return ResourceItem{}, false
+		}
+		return ResourceItem{URI: fmt.Sprintf("%s/%d", historyURI, index), Method: e.Method, URL: e.URL, StatusCode: e.StatusCode}, true
+	}}
+	issuesCollection = collection{uri: issuesURI, tool: "get_scanner_issues", item: func(raw string, index int) (ResourceItem, bool) {
+		issues := burp.ParseScannerIssues(raw, 1)
+		if len(issues) == 0 {
+			return ResourceItem{}, false
+		}
+		i := issues[0]
+		return ResourceItem{URI: fmt.Sprintf("%s/%d", issuesURI, index), URL: i.URL, Name: i.Name, Severity: i.Severity}, true
+	}}
+)
+
+// raw returns the raw entry at offset (0-based), or "" past the end.
+func (c collection) raw(ctx context.Context, client *burp.Client, offset int) (string, error) {
+	raw, err := client.CallTool(ctx, c.tool, map[string]any{"count": 1, "offset": offset})
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(trimEndMarker(raw)), nil
+}
+
+// count returns how many items Burp holds. Entries are fetched one at a time,
+// so it probes for the end by doubling and then bisecting instead of walking.
+func (c collection) count(ctx context.Context, client *burp.Client) (int, error) {
+	exists := func(offset int) (bool, error) {
+		raw, err := c.raw(ctx, client, offset)
+		return raw != "", err
+	}
+	lo, hi := 0, 1 // items exist below lo; hi is the first offset to check
+	for {
+		ok, err := exists(hi - 1)
+		if err != nil {
+			return 0, err
+		}
+		if !ok {
+			break
+		}
+		lo, hi = hi, hi*2
+	}
+	// The count is in [lo, hi-1]: the item at lo-1 exists, the one at hi-1 doesn't.
+	hi--
+	for lo < hi {
+		mid := (lo + hi + 1) / 2
+		ok, err := exists(mid - 1)
+		if err != nil {
+			return 0, err
+		}
+		if ok {
+			lo = mid
+		} else {
+			hi = mid - 1
+		}
+	}
+	return lo, nil
+}
+
+// newItems returns up to maxNewPerPoll items after the first known. A fetch
+// error ends the scan early; the next poll resumes from what was read.
+func (c collection) newItems(ctx context.Context, client *burp.Client, known int) []ResourceItem {
+	var added []ResourceItem
+	for len(added) < maxNewPerPoll {
+		raw, err := c.raw(ctx, client, known+len(added))
+		if err != nil || raw == "" {
+			break
+		}
+		item, _ := c.item(raw, known+len(added)+1)
+		added = append(added, item)
+	}
+	return added
+}
+
+// list returns the collection with its newest items, filtered by host.
+func (c collection) list(ctx context.Context, client *burp.Client, host string) (ResourceCollection, error) {
+	total, err := c.count(ctx, client)
+	if err != nil {
+		return ResourceCollection{}, err
+	}
+	out := ResourceCollection{Total: total, Host: host, Items: []ResourceItem{}}
+	for index := total; index > 0 && index > total-recentResourceItems; index-- {
+		raw, err := c.raw(ctx, client, index-1)
+		if err != nil {
+			return ResourceCollection{}, err
+		}
+		if item, ok := c.item(raw, index); ok && hostMatches(item.URL, host) {
+			out.Items = append(out.Items, item)
+		}
+	}
+	return out, nil
+}
+
+// hostMatches reports whether rawURL is on host; an empty host matches all.
+func hostMatches(rawURL, host string) bool {
+	if host == "" {
+		return true
+	}
+	u, err := url.Parse(rawURL)
+	return err == nil && strings.EqualFold(u.Hostname(), host)
+}
+
+// parseResourceURI splits a burp:// URI into its collection, item index
+// (0 for the collection itself), and host filter.
+func parseResourceURI(uri string) (c collection, index int, host string, err error) {
+	u, err := url.Parse(uri)
+	if err != nil || u.Scheme != "burp" {
+		return collection{}, 0, "", fmt.Errorf("not a burp:// resource: %q", uri)
+	}
+	switch u.Host {
+	case "history":
+		c = historyCollection
+	case "issues":
+		c = issuesCollection
+	default:
+		return collection{}, 0, "", mcp.ResourceNotFoundError(uri)
+	}
+	if p := strings.Trim(u.Path, "/"); p != "" {
+		if index, err = strconv.Atoi(p); err != nil || index < 1 {
+			return collection{}, 0, "", mcp.ResourceNotFoundError(uri)
+		}
+	}
+	return c, index, u.Query().Get("host"), nil
+}
+
+func jsonResource(uri string, v any) (*mcp.ReadResourceResult, error) {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return &mcp.ReadResourceResult{Contents: []*mcp.ResourceContents{{URI: uri, MIMEType: "application/json", Text: string(data)}}}, nil
+}
+
+func readResourceHandler(client *burp.Client) mcp.ResourceHandler {
+	return func(ctx context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+		uri := req.Params.URI
+		c, index, host, err := parseResourceURI(uri)
+		if err != nil {
+			return nil, err
+		}
+		if index == 0 {
+			list, err := c.list(ctx, client, host)
+			if err != nil {
+				return nil, err
+			}
+			return jsonResource(uri, list)
+		}
+		raw, err := c.raw(ctx, client, index-1)
+		if err != nil {
+			return nil, err
+		}
+		if raw == "" {
+			return nil, mcp.ResourceNotFoundError(uri)
+		}
+		if c.uri == historyURI {
+			reqRaw, respRaw := burp.ExtractRequestResponse(raw)
+			return jsonResource(uri, HistoryResource{Index: index, Request: reqRaw, Response: respRaw})
+		}
+		issues := burp.ParseScannerIssues(raw, 0)
+		if len(issues) == 0 {
+			return nil, mcp.ResourceNotFoundError(uri)
+		}
+		return jsonResource(uri, issues[0])
+	}
+}
+
+// ResourceWatcher polls Burp while clients are subscribed to burp://history
+// or burp://issues and sends resource-updated notifications when new
+// matching items appear.
+type ResourceWatcher struct {
+	client   *burp.Client
+	server   *mcp.Server
+	interval time.Duration
+
+	mu     sync.Mutex
+	subs   map[string]int // subscribed URI -> subscriptions
+	known  map[string]int // collection URI -> items seen
+	cancel context.CancelFunc
+}
+
+// NewResourceWatcher returns a watcher for client. Its Subscribe and
+// Unsubscribe go in the server's options; RegisterResources attaches the server.
+func NewResourceWatcher(client *burp.Client) *ResourceWatcher {
+	return &ResourceWatcher{
+		client:   client,
+		interval: resourcePollInterval,
+		subs:     make(map[string]int),
+		known:    make(map[string]int),
+	}
+}
+
+// Subscribe starts watching a collection URI.
+func (w *ResourceWatcher) Subscribe(_ context.Context, req *mcp.SubscribeRequest) error {
+	_, index, _, err := parseResourceURI(req.Params.URI)
+	if err != nil {
+		return err
+	}
+	if index != 0 {
+		return fmt.Errorf("only %s and %s (optionally with ?host=) can be subscribed to; items don't change", historyURI, issuesURI)
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.subs[req.Params.URI]++
+	if w.cancel == nil {
+		ctx, cancel := context.WithCancel(context.Background())
+		w.cancel = cancel
+		go w.run(ctx)
+	}
+	return nil
+}
+
+// Unsubscribe stops watching a URI; polling stops with the last subscription.
+func (w *ResourceWatcher) Unsubscribe(_ context.Context, req *mcp.UnsubscribeRequest) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.subs[req.Params.URI] <= 1 {
+		delete(w.subs, req.Params.URI)
+	} else {
+		w.subs[req.Params.URI]--
+	}
+	if len(w.subs) == 0 && w.cancel != nil {
+		w.cancel()
+		w.cancel = nil
+		clear(w.known)
+	}
+	return nil
+}
+
+func (w *ResourceWatcher) run(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+	for {
+		w.poll(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// poll reads new items from each watched collection and notifies the
+// subscriptions they match. The first poll of a collection only counts it.
+func (w *ResourceWatcher) poll(ctx context.Context) {
+	for _, c := range []collection{historyCollection, issuesCollection} {
+		w.mu.Lock()
+		var uris []string
+		for uri := range w.subs {
+			if sc, _, _, err := parseResourceURI(uri); err == nil && sc.uri == c.uri {
+				uris = append(uris, uri)
+			}
+		}
+		known, counted := w.known[c.uri]
+		w.mu.Unlock()
+		if len(uris) == 0 {
+			continue
+		}
+
+		if !counted {
+			n, err := c.count(ctx, w.client)
+			if err != nil {
+				continue
+			}
+			w.setKnown(c.uri, n)
+			continue
+		}
+		added := c.newItems(ctx, w.client, known)
+		if len(added) == 0 {
+			continue
+		}
+		w.setKnown(c.uri, known+len(added))
+		for _, uri := range uris {
+			_, _, host, _ := parseResourceURI(uri)
+			for _, item := range added {
+				if hostMatches(item.URL, host) {
+					w.server.ResourceUpdated(ctx, &mcp.ResourceUpdatedNotificationParams{URI: uri})
+					break
+				}
+			}
+		}
+	}
+}
+
+func (w *ResourceWatcher) setKnown(uri string, n int) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.cancel != nil {
+		w.known[uri] = n
+	}
+}
+
+// RegisterResources registers the burp://history and burp://issues resources
+// and attaches w, whose handlers must be in server's options, to server.
+func RegisterResources(server *mcp.Server, client *burp.Client, w *ResourceWatcher) {
+	w.server = server
+	read := readResourceHandler(client)
+	server.AddResourceTemplate(&mcp.ResourceTemplate{
+		Name:        "proxy-history",
+		URITemplate: historyURI + "{?host}",
+		Description: "Newest proxy history entries (index, method, URL, status), optionally for one host. Subscribe to be notified when new matching entries arrive.",
+		MIMEType:    "application/json",
+	}, read)
+	server.AddResourceTemplate(&mcp.ResourceTemplate{
+		Name:        "proxy-history-entry",
+		URITemplate: historyURI + "/{index}",
+		Description: "Full request and response of the proxy history entry at index (1-based).",
+		MIMEType:    "application/json",
+	}, read)
+	server.AddResourceTemplate(&mcp.ResourceTemplate{
+		Name:        "scanner-issues",
+		URITemplate: issuesURI + "{?host}",
+		Description: "Newest scanner issues (name, severity, URL), optionally for one host. Subscribe to be notified when new matching issues are reported.",
+		MIMEType:    "application/json",
+	}, read)
+	server.AddResourceTemplate(&mcp.ResourceTemplate{
+		Name:        "scanner-issue",
+		URITemplate: issuesURI + "/{index}",
+		Description: "The scanner issue at index (1-based) with its full detail.",
+		MIMEType:    "application/json",
+	}, read)
+}
@@ -0,0 +1,216 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/c0tton-fluff/burp-mcp-server/internal/burp"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// fakeBurp serves Burp's history and scanner issue tools from slices that
+// tests can append to.
+type fakeBurp struct {
+	mu      sync.Mutex
+	history []string
+	issues  []string
+	// clearable offers clear_proxy_http_history, which only newer
+	// versions of the extension have.
+	clearable bool
+	// organized records the arguments of send_to_organizer calls.
+	organized []map[string]any
+}
+
+func (f *fakeBurp) add(history, issue string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if history != "" {
+		f.history = append(f.history, history)
+	}
+	if issue != "" {
+		f.issues = append(f.issues, issue)
+	}
+}
+
+type fakeBurpPage struct {
+	Count  int `json:"count"`
+	Offset int `json:"offset"`
+}
+
+func startFakeBurp(t *testing.T, f *fakeBurp) *burp.Client {
+	t.Helper()
+	server := mcp.NewServer(&mcp.Implementation{Name: "burp"}, nil)
+	page := func(items *[]string) mcp.ToolHandlerFor[fakeBurpPage, any] {
+		return func(_ context.Context, _ *mcp.CallToolRequest, in fakeBurpPage) (*mcp.CallToolResult, any, error) {
+			f.mu.Lock()
+			defer f.mu.Unlock()
+			text := "Reached end of items"
+			if in.Offset < len(*items) {
+				text = strings.Join((*items)[in.Offset:min(in.Offset+in.Count, len(*items))], "\n\n")
+			}
+			return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: text}}}, nil, nil
+		}
+	}
+	mcp.AddTool(server, &mcp.Tool{Name: "get_proxy_http_history"}, page(&f.history))
+	mcp.AddTool(server, &mcp.Tool{Name: "get_scanner_issues"}, page(&f.issues))
+	mcp.AddTool(server, &mcp.Tool{Name: "send_to_organizer"}, func(_ context.Context, _ *mcp.CallToolRequest, in map[string]any) (*mcp.CallToolResult, any, error) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		f.organized = append(f.organized, in)
+		return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: "Sent to Organizer"}}}, nil, nil
+	})
+	if f.clearable {
+		mcp.AddTool(server, &mcp.Tool{Name: "clear_proxy_http_history"}, func(context.Context, *mcp.CallToolRequest, struct{}) (*mcp.CallToolResult, any, error) {
+			f.mu.Lock()
+			defer f.mu.Unlock()
+			f.history = nil
+			return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: "Proxy history cleared"}}}, nil, nil
+		})
+	}
+	ts := httptest.NewServer(mcp.NewSSEHandler(func(*http.Request) *mcp.Server { return server }, nil))
+	t.Cleanup(ts.Close)
+
+	client, err := burp.NewClient(ts.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Connect(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(client.Close)
+	return client
+}
+
+func historyJSON(host, path string) string {
+	data, _ := json.Marshal(map[string]string{
+		"request":  fmt.Sprintf("GET %s HTTP/1.1\r\nHost: %s\r\n\r\n", path, host),
+		"response": "HTTP/1.1 200 OK\r\nContent-Length: 2\r\n\r\nok",
+	})
+	return string(data)
+}
+
+func TestCollectionCount(t *testing.T) {
+	f := &fakeBurp{}
+	client := startFakeBurp(t, f)
+	for n := range 12 {
+		got, err := historyCollection.count(context.Background(), client)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != n {
+			t.Fatalf("count = %d, want %d", got, n)
+		}
+		f.add(historyJSON("a.test", "/"), "")
+	}
+}
+
+func TestResources(t *testing.T) {
+	f := &fakeBurp{}
+	f.add(historyJSON("a.test", "/one"), "Issue: SQL injection\nSeverity: High\nConfidence: Firm\nURL: https://a.test/one\nDetail: the id parameter")
+	f.add(historyJSON("b.test", "/two"), "")
+	client := startFakeBurp(t, f)
+
+	watcher := NewResourceWatcher(client)
+	watcher.interval = 20 * time.Millisecond
+	server := mcp.NewServer(&mcp.Implementation{Name: "test"}, &mcp.ServerOptions{
+		SubscribeHandler:   watcher.Subscribe,
+		UnsubscribeHandler: watcher.Unsubscribe,
+	})
+	RegisterResources(server, client, watcher)
+
+	updated := make(chan string, 10)
+	mc := mcp.NewClient(&mcp.Implementation{Name: "client"}, &mcp.ClientOptions{
+		ResourceUpdatedHandler: func(_ context.Context, req *mcp.ResourceUpdatedNotificationRequest) {
+			updated <- req.Params.URI
+		},
+	})
+	ct, st := mcp.NewInMemoryTransports()
+	ctx := context.Background()
+	ss, err := server.Connect(ctx, st, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ss.Close()
+	cs, err := mc.Connect(ctx, ct, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cs.Close()
+
+	read := func(uri string, v any) {
+		t.Helper()
+		res, err := cs.ReadResource(ctx, &mcp.ReadResourceParams{URI: uri})
+		if err != nil {
+			t.Fatalf("%s: %v", uri, err)
+		}
+		if err := json.Unmarshal([]byte(res.Contents[0].Text), v); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var list ResourceCollection
+	read("burp://history?host=b.test", &list)
+	if list.Total != 2 || len(list.Items) != 1 || list.Items[0].URI != "burp://history/2" || list.Items[0].StatusCode != 200 {
+		t.Errorf("history = %+v", list)
+	}
+	read("burp://issues", &list)
+	if list.Total != 1 || len(list.Items) != 1 || list.Items[0].Name != "SQL injection" {
+		t.Errorf("issues = %+v", list)
+	}
+	var entry HistoryResource
+	read("burp://history/1", &entry)
+	if entry.Index != 1 || !strings.HasPrefix(entry.Request, "GET /one ") || !strings.HasSuffix(entry.Response, "ok") {
+		t.Errorf("entry = %+v", entry)
+	}
+	var issue burp.ScannerIssue
+	read("burp://issues/1", &issue)
+	if issue.Name != "SQL injection" || issue.Severity != "High" {
+		t.Errorf("issue = %+v", issue)
+	}
+	if _, err := cs.ReadResource(ctx, &mcp.ReadResourceParams{URI: "burp://history/3"}); err == nil {
+		t.Error("expected error past the end of history")
+	}
+
+	if err := cs.Subscribe(ctx, &mcp.SubscribeParams{URI: "burp://history/1"}); err == nil {
+		t.Error("expected error subscribing to an item")
+	}
+	for _, uri := range []string{"burp://history?host=a.test", "burp://history?host=c.test"} {
+		if err := cs.Subscribe(ctx, &mcp.SubscribeParams{URI: uri}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Let the first poll count the existing entries.
+	time.Sleep(100 * time.Millisecond)
+	f.add(historyJSON("a.test", "/three"), "")
+	select {
+	case uri := <-updated:
+		if uri != "burp://history?host=a.test" {
+			t.Errorf("updated %s", uri)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("no resource update")
+	}
+	select {
+	case uri := <-updated:
+		t.Errorf("unexpected update for %s", uri)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	for _, uri := range []string{"burp://history?host=a.test", "burp://history?host=c.test"} {
+		if err := cs.Unsubscribe(ctx, &mcp.UnsubscribeParams{URI: uri}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	watcher.mu.Lock()
+	stopped := watcher.cancel == nil
+	watcher.mu.Unlock()
+	if !stopped {
+		t.Error("watcher still polling after the last unsubscribe")
+	}
+}
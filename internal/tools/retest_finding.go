@@ -0,0 +1,97 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/c0tton-fluff/burp-mcp-server/internal/burp"
+	"github.com/c0tton-fluff/burp-mcp-server/internal/store"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// RetestFindingInput is the input for burp_retest_finding.
+type RetestFindingInput struct {
+	FindingID     string `json:"findingId" jsonschema:"required,Finding ID from burp_record_finding (e.g. F-3)"`
+	HeaderProfile string `json:"headerProfile,omitempty" jsonschema:"Header rule profile from config (default: 'default')"`
+	Instance      string `json:"instance,omitempty" jsonschema:"Named Burp instance from config (default: the --burp-url connection)"`
+}
+
+// RetestFindingOutput is the output of burp_retest_finding.
+type RetestFindingOutput struct {
+	FindingID       string       `json:"findingId"`
+	Title           string       `json:"title"`
+	Retest          store.Retest `json:"retest"`
+	PreviousVerdict string       `json:"previousVerdict,omitempty"`
+	Changed         bool         `json:"changed"`
+}
+
+func retestFindingHandler(client *burp.Client, st *store.Store) func(context.Context, *mcp.CallToolRequest, RetestFindingInput) (*mcp.CallToolResult, RetestFindingOutput, error) {
+	return func(ctx context.Context, _ *mcp.CallToolRequest, input RetestFindingInput) (*mcp.CallToolResult, RetestFindingOutput, error) {
+		ctx = burp.WithInstance(ctx, input.Instance)
+
+		f, err := st.Finding(input.FindingID)
+		if err != nil {
+			return nil, RetestFindingOutput{}, err
+		}
+
+		r := replayFinding(ctx, client, f, input.HeaderProfile)
+		if err := st.AddRetest(f.ID, r); err != nil {
+			return nil, RetestFindingOutput{}, fmt.Errorf("record retest: %w", err)
+		}
+
+		out := RetestFindingOutput{FindingID: f.ID, Title: f.Title, Retest: r}
+		if n := len(f.Retests); n > 0 {
+			out.PreviousVerdict = f.Retests[n-1].Verdict
+			out.Changed = out.PreviousVerdict != r.Verdict
+		}
+		return nil, out, nil
+	}
+}
+
+// replayFinding sends the finding's steps in order and evaluates its
+// assertions against the last response.
+func replayFinding(ctx context.Context, client *burp.Client, f store.Finding, headerProfile string) store.Retest {
+	r := store.Retest{At: time.Now().UTC()}
+	fail := func(err error) store.Retest {
+		r.Verdict = store.VerdictError
+		r.Error = err.Error()
+		return r
+	}
+
+	var resp *burp.ParsedHTTPResponse
+	for i, step := range f.Steps {
+		rawNorm, parsed, err := prepareRequest(step.Raw, headerProfile)
+		if err != nil {
+			return fail(fmt.Errorf("step %d: %w", i, err))
+		}
+		t, err := resolveTarget(step.Host, step.Port, step.TLS, parsed.Host)
+		if err != nil {
+			return fail(fmt.Errorf("step %d: %w", i, err))
+		}
+		if resp, err = sendParsed(ctx, client, rawNorm, t, maxReadBody); err != nil {
+			return fail(fmt.Errorf("step %d: %w", i, err))
+		}
+	}
+	if resp == nil {
+		return fail(fmt.Errorf("finding has no reproduction steps"))
+	}
+
+	r.StatusCode = resp.StatusCode
+	results, vulnerable := evaluateAssertions(resp, f.Assertions)
+	r.Results = results
+	r.Verdict = store.VerdictFixed
+	if vulnerable {
+		r.Verdict = store.VerdictVulnerable
+	}
+	return r
+}
+
+// RegisterRetestFindingTool registers the burp_retest_finding tool.
+func RegisterRetestFindingTool(server *mcp.Server, client *burp.Client, st *store.Store) {
+	addTool(server, &mcp.Tool{
+		Name: "burp_retest_finding",
+		Description: `Replay a recorded finding's reproduction through Burp, evaluate its assertions, and record a timestamped verdict (vulnerable, fixed, or error). ` +
+			`Returns {findingId, title, retest: {at, verdict, statusCode, results}, previousVerdict, changed}.`,
+	}, retestFindingHandler(client, st))
+}
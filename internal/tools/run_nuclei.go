@@ -0,0 +1,208 @@
+package tools
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/url"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/c0tton-fluff/burp-mcp-server/internal/burp"
+	"github.com/c0tton-fluff/burp-mcp-server/internal/config"
+	"github.com/c0tton-fluff/burp-mcp-server/internal/nuclei"
+	"github.com/c0tton-fluff/burp-mcp-server/internal/store"
+	"github.com/c0tton-fluff/burp-mcp-server/internal/tasks"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const (
+	defaultNucleiTimeout = 600
+	maxNucleiTimeout     = 3600
+	// nucleiStderrTail is how much of nuclei's stderr a failure reports.
+	nucleiStderrTail = 1000
+)
+
+// RunNucleiInput is the input for burp_run_nuclei.
+type RunNucleiInput struct {
+	Target         string            `json:"target" jsonschema:"URL or host[:port] to scan"`
+	Templates      []string          `json:"templates,omitempty" jsonschema:"Template files or directories (-t), e.g. http/exposures/ (default: nuclei's default set, narrowed by tags and severity)"`
+	Tags           []string          `json:"tags,omitempty" jsonschema:"Only templates with these tags, e.g. cve, misconfig"`
+	ExcludeTags    []string          `json:"excludeTags,omitempty" jsonschema:"Skip templates with these tags, e.g. dos, intrusive"`
+	Severity       []string          `json:"severity,omitempty" jsonschema:"Only these severities: info, low, medium, high, critical"`
+	Headers        map[string]string `json:"headers,omitempty" jsonschema:"Headers sent with every request, e.g. Authorization"`
+	Proxy          string            `json:"proxy,omitempty" jsonschema:"HTTP proxy for nuclei's traffic, e.g. Burp's listener http://127.0.0.1:8080 to record it in proxy history"`
+	RateLimit      int               `json:"rateLimit,omitempty" jsonschema:"Max requests per second (default: the configured rate limit for the target, else nuclei's default)"`
+	TimeoutSeconds int               `json:"timeoutSeconds,omitempty" jsonschema:"Stop the run after this long, keeping results so far (default 600 or config nuclei.timeoutSeconds, max 3600)"`
+	DetailLimit    int               `json:"detailLimit,omitempty" jsonschema:"Max characters per issue detail (default 500, -1 = unlimited)"`
+	DryRun         bool              `json:"dryRun,omitempty" jsonschema:"Return the nuclei command line without running it"`
+	Background     bool              `json:"background,omitempty" jsonschema:"Run as a background task and return its taskId at once; poll with burp_get_task, whose output shows nuclei's results as they arrive"`
+}
+
+// RunNucleiOutput is the output of burp_run_nuclei.
+type RunNucleiOutput struct {
+	Command  string              `json:"command"`
+	Findings []burp.ScannerIssue `json:"findings"`
+	Count    int                 `json:"count"`
+	Stored   int                 `json:"stored"` // findings new to the issues store
+	Error    string              `json:"error,omitempty"`
+	TaskID   string              `json:"taskId,omitempty"`
+}
+
+// nucleiTarget returns the host of a URL or host[:port] target for scope checks.
+func nucleiTarget(target string) (string, error) {
+	ref := target
+	if !strings.Contains(ref, "://") {
+		ref = "//" + ref
+	}
+	u, err := url.Parse(ref)
+	if err != nil || u.Hostname() == "" {
+		return "", fmt.Errorf("target %q is not a URL or host", target)
+	}
+	if u.Scheme != "" && u.Scheme != "http" && u.Scheme != "https" {
+		return "", fmt.Errorf("target %q: scheme must be http or https", target)
+	}
+	return u.Hostname(), nil
+}
+
+// shellJoin renders a command line for display.
+func shellJoin(args []string) string {
+	quoted := make([]string, len(args))
+	for i, a := range args {
+		quoted[i] = shellQuote(a)
+	}
+	return strings.Join(quoted, " ")
+}
+
+func runNucleiHandler(st *store.Store) func(context.Context, *mcp.CallToolRequest, RunNucleiInput) (*mcp.CallToolResult, RunNucleiOutput, error) {
+	return func(ctx context.Context, _ *mcp.CallToolRequest, input RunNucleiInput) (*mcp.CallToolResult, RunNucleiOutput, error) {
+		if input.Target == "" {
+			return nil, RunNucleiOutput{}, fmt.Errorf("target is required")
+		}
+		host, err := nucleiTarget(input.Target)
+		if err != nil {
+			return nil, RunNucleiOutput{}, err
+		}
+		if input.RateLimit < 0 || input.TimeoutSeconds < 0 {
+			return nil, RunNucleiOutput{}, fmt.Errorf("rateLimit and timeoutSeconds must not be negative")
+		}
+		nc := settings.Nuclei
+		if nc == nil {
+			nc = &config.NucleiConfig{}
+		}
+		timeout := cmp.Or(input.TimeoutSeconds, nc.TimeoutSeconds, defaultNucleiTimeout)
+		if timeout > maxNucleiTimeout {
+			return nil, RunNucleiOutput{}, fmt.Errorf("timeoutSeconds must be at most %d", maxNucleiTimeout)
+		}
+		detailLimit := input.DetailLimit
+		if detailLimit == 0 {
+			detailLimit = 500
+		}
+		if detailLimit < 0 {
+			detailLimit = 0
+		}
+
+		rateLimit := input.RateLimit
+		if rps := limiter.maxRPS(toolName(ctx), host); rps > 0 && (rateLimit == 0 || float64(rateLimit) > rps) {
+			rateLimit = max(1, int(math.Floor(rps)))
+		}
+		binary := cmp.Or(nc.Binary, "nuclei")
+		args := nuclei.Args(nuclei.Options{
+			Target:      input.Target,
+			Templates:   input.Templates,
+			Tags:        input.Tags,
+			ExcludeTags: input.ExcludeTags,
+			Severities:  input.Severity,
+			Headers:     input.Headers,
+			Proxy:       input.Proxy,
+			RateLimit:   rateLimit,
+			Extra:       nc.Args,
+		})
+		out := RunNucleiOutput{Command: shellJoin(append([]string{binary}, args...)), Findings: []burp.ScannerIssue{}}
+
+		if err := checkScope(ctx, host); err != nil {
+			return nil, RunNucleiOutput{}, err
+		}
+		if dryRun(input.DryRun) {
+			return nil, out, nil
+		}
+		if err := requireApproval(ctx, "burp_run_nuclei", resolvedTarget{Host: host}, "nuclei scan of "+input.Target, out.Command); err != nil {
+			return nil, RunNucleiOutput{}, err
+		}
+		path, err := exec.LookPath(binary)
+		if err != nil {
+			return nil, RunNucleiOutput{}, fmt.Errorf("nuclei binary %q not found; install nuclei or set nuclei.binary in config: %w", binary, err)
+		}
+		if input.Background {
+			out.TaskID = startToolTask(ctx, "nuclei", "nuclei scan of "+input.Target, func(ctx context.Context) (any, error) {
+				return runNuclei(ctx, st, path, args, timeout, detailLimit, out)
+			})
+			return nil, out, nil
+		}
+		out, err = runNuclei(ctx, st, path, args, timeout, detailLimit, out)
+		if err != nil {
+			return nil, RunNucleiOutput{}, err
+		}
+		return nil, out, nil
+	}
+}
+
+// runNuclei runs nuclei and stores its findings. Inside a task, nuclei's
+// JSONL output is also streamed to the task as it arrives.
+func runNuclei(ctx context.Context, st *store.Store, path string, args []string, timeout, detailLimit int, out RunNucleiOutput) (RunNucleiOutput, error) {
+	runCtx, cancel := context.WithTimeout(ctx, time.Duration(timeout)*time.Second)
+	defer cancel()
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(runCtx, path, args...)
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	if t := tasks.FromContext(ctx); t != nil {
+		cmd.Stdout = io.MultiWriter(&stdout, t)
+	}
+	runErr := cmd.Run()
+
+	results, parseErr := nuclei.Parse(&stdout)
+	switch {
+	case errors.Is(runCtx.Err(), context.DeadlineExceeded):
+		out.Error = fmt.Sprintf("nuclei stopped after %ds; findings so far are returned", timeout)
+	case ctx.Err() != nil:
+		out.Error = "nuclei canceled; findings so far are returned"
+	case runErr != nil:
+		tail := strings.TrimSpace(stderr.String())
+		if len(tail) > nucleiStderrTail {
+			tail = "..." + tail[len(tail)-nucleiStderrTail:]
+		}
+		if len(results) == 0 {
+			return RunNucleiOutput{}, fmt.Errorf("nuclei failed: %w: %s", runErr, tail)
+		}
+		out.Error = fmt.Sprintf("nuclei failed: %v: %s", runErr, tail)
+	case parseErr != nil:
+		out.Error = parseErr.Error()
+	}
+
+	issues := make([]store.Issue, len(results))
+	for i, r := range results {
+		issues[i] = r.Issue()
+		out.Findings = append(out.Findings, scannerIssue(issues[i], detailLimit))
+	}
+	out.Count = len(out.Findings)
+	var err error
+	if out.Stored, err = st.ImportIssues(issues); err != nil {
+		return RunNucleiOutput{}, fmt.Errorf("store findings: %w", err)
+	}
+	return out, nil
+}
+
+// RegisterRunNucleiTool registers the burp_run_nuclei tool.
+func RegisterRunNucleiTool(server *mcp.Server, st *store.Store) {
+	addTool(server, &mcp.Tool{
+		Name: "burp_run_nuclei",
+		Description: `Run the nuclei scanner (binary from config nuclei.binary or PATH) against a target with a template, tag, and severity filter. ` +
+			`Findings are converted to scanner issues and merged into the local issues store, so burp_get_scanner_issues with source=imported serves them next to imported Burp findings. ` +
+			`Nuclei sends its own traffic: set proxy to route it through Burp. Returns {command, findings: [{name, severity, confidence, url, issueDetail, detailSize, detailTruncated}], count, stored, error}; with background=true, {command, taskId} at once.`,
+	}, runNucleiHandler(st))
+}
@@ -0,0 +1,93 @@
+package tools
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/c0tton-fluff/burp-mcp-server/internal/config"
+	"github.com/c0tton-fluff/burp-mcp-server/internal/store"
+)
+
+// fakeNuclei writes a script that records its arguments and prints one
+// JSONL result, standing in for the nuclei binary.
+func fakeNuclei(t *testing.T) (binary, argsFile string) {
+	if runtime.GOOS == "windows" {
+		t.Skip("needs a POSIX shell")
+	}
+	dir := t.TempDir()
+	binary, argsFile = filepath.Join(dir, "nuclei"), filepath.Join(dir, "args")
+	script := "#!/bin/sh\n" +
+		"printf '%s\\n' \"$@\" > " + argsFile + "\n" +
+		`echo '{"template-id":"git-config","info":{"name":"Git Config Disclosure","severity":"medium"},"host":"https://shop.example","matched-at":"https://shop.example/.git/config"}'` + "\n"
+	if err := os.WriteFile(binary, []byte(script), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	return binary, argsFile
+}
+
+func TestRunNuclei(t *testing.T) {
+	binary, argsFile := fakeNuclei(t)
+	t.Cleanup(func() { Configure(nil) })
+	Configure(&config.Config{
+		Nuclei:    &config.NucleiConfig{Binary: binary},
+		RateLimit: config.RateLimits{PerHost: &config.RateLimit{RPS: 2.5}},
+	})
+	st, err := store.Open(filepath.Join(t.TempDir(), "store.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	handler := runNucleiHandler(st)
+
+	in := RunNucleiInput{Target: "https://shop.example", Tags: []string{"exposure"}, RateLimit: 100}
+	_, out, err := handler(context.Background(), nil, in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out.Count != 1 || out.Stored != 1 || out.Findings[0].URL != "https://shop.example/.git/config" || out.Findings[0].Severity != "Medium" {
+		t.Errorf("out = %+v", out)
+	}
+	args, _ := os.ReadFile(argsFile)
+	// The per-host limit of 2.5 rps caps the requested 100.
+	if !strings.Contains(string(args), "-tags\nexposure\n") || !strings.Contains(string(args), "-rl\n2\n") {
+		t.Errorf("args = %q", args)
+	}
+	if issues := st.Issues(); len(issues) != 1 || issues[0].Source != "nuclei" {
+		t.Errorf("stored issues = %+v", issues)
+	}
+
+	// A second run finds the same issue, which is already stored.
+	if _, out, err = handler(context.Background(), nil, in); err != nil || out.Count != 1 || out.Stored != 0 {
+		t.Errorf("second run = %+v, %v", out, err)
+	}
+
+	os.Remove(argsFile)
+	_, out, err = handler(context.Background(), nil, RunNucleiInput{Target: "shop.example:8443", DryRun: true})
+	if err != nil || !strings.HasPrefix(out.Command, "'"+binary+"' '-u' 'shop.example:8443'") || out.Count != 0 {
+		t.Errorf("dry run = %+v, %v", out, err)
+	}
+	if _, err := os.Stat(argsFile); err == nil {
+		t.Error("dry run ran nuclei")
+	}
+
+	for _, bad := range []RunNucleiInput{{}, {Target: "ftp://shop.example"}, {Target: "shop.example", TimeoutSeconds: 7200}} {
+		if _, _, err := handler(context.Background(), nil, bad); err == nil {
+			t.Errorf("%+v: expected error", bad)
+		}
+	}
+}
+
+func TestRunNuclei_Scope(t *testing.T) {
+	binary, _ := fakeNuclei(t)
+	t.Cleanup(func() { Configure(nil) })
+	Configure(&config.Config{Nuclei: &config.NucleiConfig{Binary: binary}, Scope: []string{"*.example.com"}})
+	st, _ := store.Open(filepath.Join(t.TempDir(), "store.json"))
+
+	_, _, err := runNucleiHandler(st)(context.Background(), nil, RunNucleiInput{Target: "https://shop.example"})
+	if _, ok := err.(*ScopeError); !ok {
+		t.Errorf("err = %v, want ScopeError", err)
+	}
+}
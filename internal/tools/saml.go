@@ -0,0 +1,95 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/c0tton-fluff/burp-mcp-server/internal/burp"
+	"github.com/c0tton-fluff/burp-mcp-server/internal/saml"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// SAMLDecodeInput is the input for burp_saml_decode.
+type SAMLDecodeInput struct {
+	Content string `json:"content,omitempty" jsonschema:"SAMLRequest or SAMLResponse parameter value (base64, optionally deflated and URL-encoded)"`
+	Raw     string `json:"raw,omitempty" jsonschema:"Raw HTTP request carrying SAMLRequest or SAMLResponse in its query string or form body, instead of content"`
+}
+
+// SAMLDecodeOutput is the output of burp_saml_decode.
+type SAMLDecodeOutput struct {
+	Parameter  string `json:"parameter,omitempty"`
+	Binding    string `json:"binding"`
+	RelayState string `json:"relayState,omitempty"`
+	XML        string `json:"xml"`
+	saml.Message
+}
+
+// samlParams finds the SAML message parameter and RelayState in a request's
+// query string or form body.
+func samlParams(raw string) (name, value, relayState string, err error) {
+	parsed := burp.ParseRawRequest(raw)
+	_, query, _ := strings.Cut(parsed.Path, "?")
+	for _, src := range []string{query, parsed.Body} {
+		// An unencoded '+' in the base64 comes back as a space; saml.Decode
+		// puts it back.
+		params, _ := url.ParseQuery(src)
+		for _, n := range []string{"SAMLResponse", "SAMLRequest"} {
+			if v := params.Get(n); v != "" {
+				return n, v, params.Get("RelayState"), nil
+			}
+		}
+	}
+	return "", "", "", fmt.Errorf("no SAMLRequest or SAMLResponse parameter in the query string or body")
+}
+
+func samlDecodeHandler() func(context.Context, *mcp.CallToolRequest, SAMLDecodeInput) (*mcp.CallToolResult, SAMLDecodeOutput, error) {
+	return func(_ context.Context, _ *mcp.CallToolRequest, input SAMLDecodeInput) (*mcp.CallToolResult, SAMLDecodeOutput, error) {
+		var out SAMLDecodeOutput
+		value := input.Content
+		switch {
+		case input.Content != "" && input.Raw != "":
+			return nil, out, fmt.Errorf("content and raw are mutually exclusive")
+		case input.Raw != "":
+			if err := validateRawRequest(input.Raw); err != nil {
+				return nil, out, err
+			}
+			var err error
+			if out.Parameter, value, out.RelayState, err = samlParams(input.Raw); err != nil {
+				return nil, out, err
+			}
+		case input.Content == "":
+			return nil, out, fmt.Errorf("content or raw is required")
+		}
+
+		doc, deflated, err := saml.Decode(value)
+		if err != nil {
+			return nil, out, err
+		}
+		out.Binding = "post"
+		if deflated {
+			out.Binding = "redirect"
+		}
+		msg, err := saml.Analyze(doc, time.Now())
+		if err != nil {
+			return nil, out, err
+		}
+		out.Message = *msg
+		if out.XML, err = saml.Indent(doc); err != nil {
+			// Analyze parsed it, so this is unlikely; fall back to the original.
+			out.XML = string(doc)
+		}
+		return nil, out, nil
+	}
+}
+
+// RegisterSAMLDecodeTool registers the burp_saml_decode tool.
+func RegisterSAMLDecodeTool(server *mcp.Server) {
+	addTool(server, &mcp.Tool{
+		Name: "burp_saml_decode",
+		Description: `Decode a SAMLRequest or SAMLResponse (base64, deflated for the Redirect binding) from a parameter value or a raw request, pretty-print the XML, and flag unsigned messages and assertions, XSW layouts, SHA-1, expiry, and NameID comments. ` +
+			`Returns {parameter, binding, relayState, xml, type, issuer, destination, status, signed, assertions: [{signed, nameId, audiences, notOnOrAfter, attributes}], issues}.`,
+	}, samlDecodeHandler())
+}
@@ -0,0 +1,29 @@
+package tools
+
+import (
+	"context"
+	"encoding/base64"
+	"net/url"
+	"testing"
+)
+
+func TestSAMLDecodeHandler_Raw(t *testing.T) {
+	resp := `<samlp:Response xmlns:samlp="urn:oasis:names:tc:SAML:2.0:protocol" ID="r1"><samlp:Status><samlp:StatusCode Value="urn:oasis:names:tc:SAML:2.0:status:Requester"/></samlp:Status></samlp:Response>`
+	body := "SAMLResponse=" + url.QueryEscape(base64.StdEncoding.EncodeToString([]byte(resp))) + "&RelayState=%2Fhome"
+	raw := "POST /acs HTTP/1.1\r\nHost: sp.example\r\nContent-Type: application/x-www-form-urlencoded\r\n\r\n" + body
+
+	_, out, err := samlDecodeHandler()(context.Background(), nil, SAMLDecodeInput{Raw: raw})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out.Parameter != "SAMLResponse" || out.Binding != "post" || out.RelayState != "/home" {
+		t.Errorf("out = %+v", out)
+	}
+	if out.Type != "Response" || out.Status != "Requester" || out.XML == "" {
+		t.Errorf("message = %+v", out.Message)
+	}
+
+	if _, _, err := samlDecodeHandler()(context.Background(), nil, SAMLDecodeInput{Raw: "GET / HTTP/1.1\r\nHost: a\r\n\r\n"}); err == nil {
+		t.Error("expected an error without a SAML parameter")
+	}
+}
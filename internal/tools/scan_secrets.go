@@ -0,0 +1,174 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/c0tton-fluff/burp-mcp-server/internal/burp"
+	"github.com/c0tton-fluff/burp-mcp-server/internal/secrets"
+	"github.com/c0tton-fluff/burp-mcp-server/internal/store"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const (
+	// maxSecretLocations caps the locations listed per secret; count has the total.
+	maxSecretLocations = 20
+	// maxSecretChars caps a reported secret, for private key blocks.
+	maxSecretChars = 200
+)
+
+// ScanSecretsInput is the input for burp_scan_secrets.
+type ScanSecretsInput struct {
+	Snapshot   string   `json:"snapshot,omitempty" jsonschema:"Scan one saved history snapshot (default: every snapshot)"`
+	Live       bool     `json:"live,omitempty" jsonschema:"Scan live proxy history instead of saved snapshots"`
+	MaxEntries int      `json:"maxEntries,omitempty" jsonschema:"Most live entries to scan, oldest first (default 1000, max 5000)"`
+	Allowlist  []string `json:"allowlist,omitempty" jsonschema:"Regexes for known or test values to skip, on top of config secrets.allowlist"`
+	Instance   string   `json:"instance,omitempty" jsonschema:"Named Burp instance from config (default: the --burp-url connection)"`
+}
+
+// SecretLocation is where a secret was seen.
+type SecretLocation struct {
+	Snapshot string `json:"snapshot,omitempty"`
+	ID       int    `json:"id"`
+	URL      string `json:"url"`
+	In       string `json:"in"` // request.headers, request.body, response.headers, or response.body
+}
+
+// SecretFinding is one secret value and everywhere it was seen.
+type SecretFinding struct {
+	Rule        string           `json:"rule"`
+	Description string           `json:"description"`
+	Secret      string           `json:"secret"`
+	Count       int              `json:"count"`
+	Locations   []SecretLocation `json:"locations"`
+}
+
+// ScanSecretsOutput is the output of burp_scan_secrets.
+type ScanSecretsOutput struct {
+	Scanned  int             `json:"scanned"`
+	Findings []SecretFinding `json:"findings"`
+	// Allowlisted counts distinct values skipped by the allowlist.
+	Allowlisted int `json:"allowlisted"`
+	// More is set when live history has entries past maxEntries.
+	More bool `json:"more,omitempty"`
+}
+
+// secretScan groups the secrets found across entries by value.
+type secretScan struct {
+	allow       []*regexp.Regexp
+	out         ScanSecretsOutput
+	byValue     map[string]int // secret value -> index in out.Findings
+	allowlisted map[string]bool
+}
+
+func newSecretScan(allowlist []string) (*secretScan, error) {
+	s := &secretScan{
+		out:         ScanSecretsOutput{Findings: []SecretFinding{}},
+		byValue:     make(map[string]int),
+		allowlisted: make(map[string]bool),
+	}
+	if sc := settings.Secrets; sc != nil {
+		allowlist = append(append([]string(nil), sc.Allowlist...), allowlist...)
+	}
+	for _, expr := range allowlist {
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid allowlist regex %q: %w", expr, err)
+		}
+		s.allow = append(s.allow, re)
+	}
+	return s, nil
+}
+
+func (s *secretScan) allowed(secret string) bool {
+	for _, re := range s.allow {
+		if re.MatchString(secret) {
+			return true
+		}
+	}
+	return false
+}
+
+// splitHTTPMessage splits a raw HTTP message at the blank line ending its
+// headers; the head keeps the request or status line.
+func splitHTTPMessage(raw string) (head, body string) {
+	if i := strings.Index(raw, "\r\n\r\n"); i >= 0 {
+		return raw[:i], raw[i+4:]
+	}
+	if i := strings.Index(raw, "\n\n"); i >= 0 {
+		return raw[:i], raw[i+2:]
+	}
+	return raw, ""
+}
+
+// scan adds the secrets in one history entry.
+func (s *secretScan) scan(snapshot string, e store.HistoryEntry) {
+	s.out.Scanned++
+	url := ""
+	reqHead, reqBody := splitHTTPMessage(e.Request)
+	respHead, respBody := splitHTTPMessage(e.Response)
+	for _, part := range []struct{ in, text string }{
+		{"request.headers", reqHead},
+		{"request.body", reqBody},
+		{"response.headers", respHead},
+		{"response.body", respBody},
+	} {
+		for _, hit := range secrets.Find(part.text) {
+			if s.allowlisted[hit.Secret] {
+				continue
+			}
+			if s.allowed(hit.Secret) {
+				s.allowlisted[hit.Secret] = true
+				s.out.Allowlisted++
+				continue
+			}
+			if url == "" {
+				url = summarizeHistoryEntry(e.Request, e.Response, e.ID).URL
+			}
+			i, ok := s.byValue[hit.Secret]
+			if !ok {
+				secret := hit.Secret
+				if len(secret) > maxSecretChars {
+					secret = secret[:maxSecretChars] + "..."
+				}
+				i = len(s.out.Findings)
+				s.byValue[hit.Secret] = i
+				s.out.Findings = append(s.out.Findings, SecretFinding{Rule: hit.Rule.ID, Description: hit.Rule.Description, Secret: secret})
+			}
+			f := &s.out.Findings[i]
+			f.Count++
+			if len(f.Locations) < maxSecretLocations {
+				f.Locations = append(f.Locations, SecretLocation{Snapshot: snapshot, ID: e.ID, URL: url, In: part.in})
+			}
+		}
+	}
+}
+
+func scanSecretsHandler(client *burp.Client, st *store.Store) func(context.Context, *mcp.CallToolRequest, ScanSecretsInput) (*mcp.CallToolResult, ScanSecretsOutput, error) {
+	return func(ctx context.Context, _ *mcp.CallToolRequest, input ScanSecretsInput) (*mcp.CallToolResult, ScanSecretsOutput, error) {
+		ctx = burp.WithInstance(ctx, input.Instance)
+		s, err := newSecretScan(input.Allowlist)
+		if err != nil {
+			return nil, ScanSecretsOutput{}, err
+		}
+
+		s.out.More, err = visitTraffic(ctx, client, st, input.Snapshot, input.Live, input.MaxEntries, s.scan)
+		if err != nil {
+			return nil, ScanSecretsOutput{}, err
+		}
+		return nil, s.out, nil
+	}
+}
+
+// RegisterScanSecretsTool registers the burp_scan_secrets tool.
+func RegisterScanSecretsTool(server *mcp.Server, client *burp.Client, st *store.Store) {
+	addTool(server, &mcp.Tool{
+		Name: "burp_scan_secrets",
+		Description: `Scan the headers and bodies of saved history snapshots, or live proxy history with live=true, for leaked secrets: ` +
+			`AWS, GitHub, GitLab, Slack, Stripe, and Google keys, private key blocks, and high-entropy values assigned to secret-like names. ` +
+			`Each value is reported once with every place it was seen; values matching the allowlist or config secrets.allowlist are skipped. ` +
+			`Returns {scanned, findings: [{rule, description, secret, count, locations: [{snapshot, id, url, in}]}], allowlisted, more}.`,
+	}, scanSecretsHandler(client, st))
+}
@@ -0,0 +1,116 @@
+package tools
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/modelcontextprotocol/go-sdk/jsonrpc"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// Tool output schema versions. Bump CurrentSchemaVersion when an output
+// changes shape, and register an adapter in schemaAdapters that turns the
+// new shape back into the old one, so clients pinned to the old version
+// keep working.
+const (
+	CurrentSchemaVersion = 1
+	MinSchemaVersion     = 1
+)
+
+// schemaAdapter rewrites a tool's decoded output in place.
+type schemaAdapter func(tool string, out map[string]any)
+
+// schemaAdapters[v] rewrites outputs from version v+1 to version v.
+var schemaAdapters = map[int]schemaAdapter{}
+
+// schemaVersionMeta is the _meta key of tools/call a client sets to pin the
+// output shape of one call.
+const schemaVersionMeta = "schemaVersion"
+
+// CheckSchemaVersion reports whether v is a version outputs can be given in;
+// zero means the current one.
+func CheckSchemaVersion(v int) error {
+	if v != 0 && (v < MinSchemaVersion || v > CurrentSchemaVersion) {
+		return fmt.Errorf("schemaVersion %d is not supported (want %d to %d)", v, MinSchemaVersion, CurrentSchemaVersion)
+	}
+	return nil
+}
+
+// requestedSchemaVersion returns the version asked for by the call's _meta,
+// else by the config, else the current one.
+func requestedSchemaVersion(params *mcp.CallToolParamsRaw) (int, error) {
+	if raw, ok := params.Meta[schemaVersionMeta]; ok {
+		f, ok := raw.(float64)
+		if !ok || f != float64(int(f)) {
+			return 0, fmt.Errorf("_meta.schemaVersion must be an integer")
+		}
+		v := int(f)
+		return v, CheckSchemaVersion(v)
+	}
+	if settings.SchemaVersion != 0 {
+		return settings.SchemaVersion, nil
+	}
+	return CurrentSchemaVersion, nil
+}
+
+// SchemaVersionMiddleware stamps every tool output, errors included, with
+// schemaVersion, first adapting it to the version the client asked for
+// with _meta.schemaVersion or the config's schemaVersion.
+func SchemaVersionMiddleware() mcp.Middleware {
+	return func(next mcp.MethodHandler) mcp.MethodHandler {
+		return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+			params, ok := req.GetParams().(*mcp.CallToolParamsRaw)
+			if !ok || method != "tools/call" {
+				return next(ctx, method, req)
+			}
+			version, err := requestedSchemaVersion(params)
+			if err != nil {
+				return nil, &jsonrpc.Error{Code: jsonrpc.CodeInvalidParams, Message: err.Error()}
+			}
+			result, err := next(ctx, method, req)
+			if res, ok := result.(*mcp.CallToolResult); ok && err == nil && res.StructuredContent != nil {
+				versionToolResult(res, params.Name, version)
+			}
+			return result, err
+		}
+	}
+}
+
+// adaptOutput rewrites out from version from down to version to.
+func adaptOutput(out map[string]any, tool string, from, to int) {
+	for v := from - 1; v >= to; v-- {
+		if adapt, ok := schemaAdapters[v]; ok {
+			adapt(tool, out)
+		}
+	}
+}
+
+// versionToolResult adapts res's structured content from the current
+// version down to version and sets its schemaVersion.
+func versionToolResult(res *mcp.CallToolResult, tool string, version int) {
+	data, err := json.Marshal(res.StructuredContent)
+	if err != nil {
+		return
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var out map[string]any
+	if err := dec.Decode(&out); err != nil || out == nil {
+		return
+	}
+	adaptOutput(out, tool, CurrentSchemaVersion, version)
+	out["schemaVersion"] = version
+	versioned, err := json.Marshal(out)
+	if err != nil {
+		return
+	}
+	res.StructuredContent = json.RawMessage(versioned)
+	// The SDK mirrors structured output as text; keep the two in step.
+	for _, c := range res.Content {
+		if tc, ok := c.(*mcp.TextContent); ok && tc.Text == string(data) {
+			tc.Text = string(versioned)
+		}
+	}
+}
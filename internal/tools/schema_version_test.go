@@ -0,0 +1,127 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/c0tton-fluff/burp-mcp-server/internal/config"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestSchemaVersionMiddleware(t *testing.T) {
+	server := mcp.NewServer(&mcp.Implementation{Name: "test"}, nil)
+	server.AddReceivingMiddleware(SchemaVersionMiddleware(), ErrorMiddleware())
+	type in struct {
+		Fail bool `json:"fail,omitempty"`
+	}
+	type out struct {
+		OK bool `json:"ok"`
+	}
+	addTool(server, &mcp.Tool{Name: "t"}, func(_ context.Context, _ *mcp.CallToolRequest, in in) (*mcp.CallToolResult, out, error) {
+		if in.Fail {
+			return nil, out{}, errors.New("bad input")
+		}
+		return nil, out{OK: true}, nil
+	})
+
+	ct, st := mcp.NewInMemoryTransports()
+	ctx := context.Background()
+	ss, err := server.Connect(ctx, st, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ss.Close()
+	cs, err := mcp.NewClient(&mcp.Implementation{Name: "client"}, nil).Connect(ctx, ct, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cs.Close()
+
+	call := func(args map[string]any, meta mcp.Meta) (map[string]any, error) {
+		res, err := cs.CallTool(ctx, &mcp.CallToolParams{Meta: meta, Name: "t", Arguments: args})
+		if err != nil {
+			return nil, err
+		}
+		var structured, text map[string]any
+		data, _ := json.Marshal(res.StructuredContent)
+		json.Unmarshal(data, &structured)
+		json.Unmarshal([]byte(res.Content[0].(*mcp.TextContent).Text), &text)
+		if text["schemaVersion"] != structured["schemaVersion"] {
+			t.Errorf("text %v and structured content %v disagree", text, structured)
+		}
+		return structured, nil
+	}
+
+	got, err := call(map[string]any{}, nil)
+	if err != nil || got["schemaVersion"] != float64(CurrentSchemaVersion) || got["ok"] != true {
+		t.Errorf("success = %v, %v", got, err)
+	}
+	got, err = call(map[string]any{"fail": true}, nil)
+	if err != nil || got["schemaVersion"] != float64(CurrentSchemaVersion) || got["error"] == nil {
+		t.Errorf("error envelope = %v, %v", got, err)
+	}
+	if _, err := call(map[string]any{}, mcp.Meta{"schemaVersion": CurrentSchemaVersion + 1}); err == nil {
+		t.Error("unsupported _meta.schemaVersion accepted")
+	}
+
+	tools, err := cs.ListTools(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	schema, _ := json.Marshal(tools.Tools[0].OutputSchema)
+	var s struct {
+		Properties map[string]any `json:"properties"`
+	}
+	if json.Unmarshal(schema, &s); s.Properties["schemaVersion"] == nil || s.Properties["ok"] == nil {
+		t.Errorf("output schema = %s", schema)
+	}
+}
+
+func TestAdaptOutput(t *testing.T) {
+	saved := schemaAdapters
+	t.Cleanup(func() { schemaAdapters = saved })
+	// Version 3 renamed "headers" to "headerMap"; version 2 made "count" a string.
+	schemaAdapters = map[int]schemaAdapter{
+		2: func(tool string, out map[string]any) {
+			out["headers"] = out["headerMap"]
+			delete(out, "headerMap")
+		},
+		1: func(tool string, out map[string]any) {
+			if tool == "burp_send_request" {
+				out["count"] = 1
+			}
+		},
+	}
+
+	out := map[string]any{"headerMap": "h", "count": "1"}
+	adaptOutput(out, "burp_send_request", 3, 2)
+	if out["headers"] != "h" || out["headerMap"] != nil || out["count"] != "1" {
+		t.Errorf("3 -> 2 = %v", out)
+	}
+	adaptOutput(out, "burp_send_request", 2, 1)
+	if out["count"] != 1 {
+		t.Errorf("2 -> 1 = %v", out)
+	}
+}
+
+func TestCheckSchemaVersion(t *testing.T) {
+	for _, tt := range []struct {
+		v  int
+		ok bool
+	}{{0, true}, {MinSchemaVersion, true}, {CurrentSchemaVersion, true}, {CurrentSchemaVersion + 1, false}, {-1, false}} {
+		if err := CheckSchemaVersion(tt.v); (err == nil) != tt.ok {
+			t.Errorf("CheckSchemaVersion(%d) = %v", tt.v, err)
+		}
+	}
+
+	Configure(&config.Config{SchemaVersion: MinSchemaVersion})
+	t.Cleanup(func() { Configure(nil) })
+	if v, err := requestedSchemaVersion(&mcp.CallToolParamsRaw{}); err != nil || v != MinSchemaVersion {
+		t.Errorf("config version = %d, %v", v, err)
+	}
+	if _, err := requestedSchemaVersion(&mcp.CallToolParamsRaw{Meta: mcp.Meta{"schemaVersion": 1.5}}); err == nil {
+		t.Error("fractional version accepted")
+	}
+}
@@ -0,0 +1,123 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+)
+
+// ScopeError is returned when a request targets a host outside the
+// engagement scope. Nothing is sent.
+type ScopeError struct {
+	Host string
+}
+
+func (e *ScopeError) Error() string {
+	return fmt.Sprintf("out_of_scope: %s is not in the engagement scope", e.Host)
+}
+
+// scopeMatcher holds the configured scope entries, split by kind.
+type scopeMatcher struct {
+	hosts    map[string]bool
+	suffixes []string // from "*.example.com", stored as ".example.com"
+	nets     []*net.IPNet
+}
+
+// newScopeMatcher builds a matcher from entries already checked by
+// config.ValidateScopeEntry. No entries means everything is in scope.
+func newScopeMatcher(entries []string) *scopeMatcher {
+	s := &scopeMatcher{hosts: make(map[string]bool)}
+	for _, e := range entries {
+		e = strings.ToLower(e)
+		switch {
+		case strings.Contains(e, "/"):
+			if _, n, err := net.ParseCIDR(e); err == nil {
+				s.nets = append(s.nets, n)
+			}
+		case strings.HasPrefix(e, "*."):
+			s.suffixes = append(s.suffixes, e[1:])
+		default:
+			if ip := net.ParseIP(e); ip != nil {
+				if ip4 := ip.To4(); ip4 != nil {
+					ip = ip4
+				}
+				s.nets = append(s.nets, &net.IPNet{IP: ip, Mask: net.CIDRMask(len(ip)*8, len(ip)*8)})
+			} else {
+				s.hosts[strings.TrimSuffix(e, ".")] = true
+			}
+		}
+	}
+	return s
+}
+
+// targetScope is the server-wide engagement scope, rebuilt by Configure.
+var targetScope = newScopeMatcher(nil)
+
+// lookupIPAddr resolves hostnames for CIDR scope checks; replaced in tests.
+var lookupIPAddr = net.DefaultResolver.LookupIPAddr
+
+func (s *scopeMatcher) empty() bool {
+	return len(s.hosts) == 0 && len(s.suffixes) == 0 && len(s.nets) == 0
+}
+
+// check returns a *ScopeError unless host (optionally with a port) is in scope.
+// A hostname that matches no name entry is in scope only if every address
+// it resolves to is inside a CIDR or IP entry.
+func (s *scopeMatcher) check(ctx context.Context, host string) error {
+	if s.empty() {
+		return nil
+	}
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	host = strings.TrimSuffix(strings.ToLower(strings.Trim(host, "[]")), ".")
+
+	if s.hosts[host] {
+		return nil
+	}
+	for _, suffix := range s.suffixes {
+		if strings.HasSuffix(host, suffix) {
+			return nil
+		}
+	}
+	if ip := net.ParseIP(host); ip != nil {
+		if s.containsIP(ip) {
+			return nil
+		}
+		return &ScopeError{Host: host}
+	}
+	if len(s.nets) == 0 {
+		return &ScopeError{Host: host}
+	}
+
+	addrs, err := lookupIPAddr(ctx, host)
+	if err != nil || len(addrs) == 0 {
+		return &ScopeError{Host: host}
+	}
+	for _, a := range addrs {
+		if !s.containsIP(a.IP) {
+			return &ScopeError{Host: host}
+		}
+	}
+	return nil
+}
+
+func (s *scopeMatcher) containsIP(ip net.IP) bool {
+	for _, n := range s.nets {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkScope returns a *ScopeError unless every host is in the engagement scope.
+func checkScope(ctx context.Context, hosts ...string) error {
+	for _, h := range hosts {
+		if err := targetScope.check(ctx, h); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"testing"
+
+	"github.com/c0tton-fluff/burp-mcp-server/internal/config"
+)
+
+func TestScopeMatcher(t *testing.T) {
+	orig := lookupIPAddr
+	t.Cleanup(func() { lookupIPAddr = orig })
+	lookupIPAddr = func(_ context.Context, host string) ([]net.IPAddr, error) {
+		switch host {
+		case "internal.corp":
+			return []net.IPAddr{{IP: net.ParseIP("10.1.2.3")}}, nil
+		case "split.corp":
+			return []net.IPAddr{{IP: net.ParseIP("10.1.2.4")}, {IP: net.ParseIP("8.8.8.8")}}, nil
+		}
+		return nil, errors.New("no such host")
+	}
+
+	s := newScopeMatcher([]string{"example.com", "*.api.example.com", "10.0.0.0/8", "192.0.2.7"})
+	tests := []struct {
+		host string
+		want bool
+	}{
+		{"example.com", true},
+		{"EXAMPLE.com.", true},
+		{"example.com:8443", true},
+		{"www.example.com", false},
+		{"v2.api.example.com", true},
+		{"api.example.com", false},
+		{"10.9.9.9", true},
+		{"192.0.2.7", true},
+		{"192.0.2.8", false},
+		{"internal.corp", true},
+		{"split.corp", false},
+		{"evil.example", false},
+	}
+	for _, tt := range tests {
+		err := s.check(context.Background(), tt.host)
+		if (err == nil) != tt.want {
+			t.Errorf("check(%q) = %v, want in scope %v", tt.host, err, tt.want)
+		}
+	}
+
+	if err := newScopeMatcher(nil).check(context.Background(), "anything.example"); err != nil {
+		t.Errorf("empty scope should allow everything: %v", err)
+	}
+}
+
+func TestSendDirect_OutOfScope(t *testing.T) {
+	t.Cleanup(func() { Configure(nil) })
+	Configure(&config.Config{Scope: []string{"in-scope.example"}})
+
+	target := startTestTarget(t, func(w http.ResponseWriter, r *http.Request) {})
+	raw := []byte("GET / HTTP/1.1\r\nHost: x\r\n\r\n")
+	_, err := sendDirect(context.Background(), target, raw, directOptions{})
+	var se *ScopeError
+	if !errors.As(err, &se) || se.Host != "127.0.0.1" {
+		t.Fatalf("expected ScopeError for 127.0.0.1, got %v", err)
+	}
+
+	// An in-scope name can't be used to reach an out-of-scope address.
+	target.Host = "in-scope.example"
+	_, err = sendDirect(context.Background(), target, raw, directOptions{ConnectHost: "127.0.0.1"})
+	if !errors.As(err, &se) {
+		t.Errorf("connectHost outside scope: got %v", err)
+	}
+
+	Configure(&config.Config{Scope: []string{"127.0.0.0/8"}})
+	target.Host = "127.0.0.1"
+	if _, err := sendDirect(context.Background(), target, raw, directOptions{}); err != nil {
+		t.Errorf("in-scope request: %v", err)
+	}
+}
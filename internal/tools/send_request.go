@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/c0tton-fluff/burp-mcp-server/internal/burp"
+	"github.com/c0tton-fluff/burp-mcp-server/internal/config"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 )
 
@@ -14,14 +15,41 @@ const http2Timeout = 15 * time.Second
 
 // SendRequestInput is the input for the burp_send_request tool.
 type SendRequestInput struct {
-	Raw         string `json:"raw" jsonschema:"required,Raw HTTP request including headers and body"`
+	Raw         string `json:"raw,omitempty" jsonschema:"Raw HTTP request including headers and body (required unless rawBase64 is given)"`
+	RawBase64   string `json:"rawBase64,omitempty" jsonschema:"Direct mode: the request as base64 bytes, sent exactly as decoded (for bare CR, NUL, or invalid UTF-8)"`
+	Normalize   *bool  `json:"normalize,omitempty" jsonschema:"Rewrite line endings to CRLF and end the headers with CRLFCRLF (default true for raw, false for rawBase64); false sends the bytes exactly, without header rules, and requires direct: true"`
 	Host        string `json:"host,omitempty" jsonschema:"Target host (overrides Host header)"`
 	Port        int    `json:"port,omitempty" jsonschema:"Target port (default based on TLS)"`
 	TLS         *bool  `json:"tls,omitempty" jsonschema:"Use HTTPS (default true)"`
-	BodyLimit   int    `json:"bodyLimit,omitempty" jsonschema:"Response body byte limit (default 10000)"`
+	BodyLimit   int    `json:"bodyLimit,omitempty" jsonschema:"Response body byte limit (default 10000 or config bodyLimits, -1 = unlimited)"`
 	BodyOffset  int    `json:"bodyOffset,omitempty" jsonschema:"Response body byte offset"`
 	AllHeaders  bool   `json:"allHeaders,omitempty" jsonschema:"Return all headers (default: security-relevant only)"`
 	HeadersOnly bool   `json:"headersOnly,omitempty" jsonschema:"Return only status and headers, skip body"`
+
+	BodyEncoding string `json:"bodyEncoding,omitempty" jsonschema:"Response body encoding: auto (default: utf8 for text, base64 for binary), utf8, base64, or hex"`
+	Hexdump      bool   `json:"hexdump,omitempty" jsonschema:"Add a hexdump of the first 256 returned body bytes"`
+	BodyView     string `json:"bodyView,omitempty" jsonschema:"HTML body view: raw (default), text (readable text without tags, scripts, and styles; bodyOffset, bodyLimit, and bodySize count the text), or title-and-forms (page with title, headings, forms, and inputs instead of the body)"`
+
+	Framing *BodyFraming `json:"framing,omitempty" jsonschema:"Content-Length override and chunked body encoding for desync testing; use with direct, since Burp's HTTP/2 attempt reframes the body"`
+
+	Relevance *RelevanceOptions `json:"relevance,omitempty" jsonschema:"Keep the relevant parts of an over-limit body (focus strings, error messages, matching JSON fields) instead of its first bodyLimit bytes"`
+
+	HeaderProfile string `json:"headerProfile,omitempty" jsonschema:"Header rule profile from config (default: 'default')"`
+	AuthProfile   string `json:"authProfile,omitempty" jsonschema:"Auth profile from config whose credentials are injected at send time"`
+	Instance      string `json:"instance,omitempty" jsonschema:"Named Burp instance from config (default: the --burp-url connection)"`
+
+	// Direct mode bypasses Burp and writes the bytes on a fresh connection.
+	Direct        bool                `json:"direct,omitempty" jsonschema:"Send directly instead of through Burp (exact bytes, no HTTP/2)"`
+	SNI           string              `json:"sni,omitempty" jsonschema:"Direct mode: TLS SNI server name (default: target host)"`
+	ConnectHost   string              `json:"connectHost,omitempty" jsonschema:"Direct mode: TCP connect address as host or host:port (default: target host and port)"`
+	NewConnection bool                `json:"newConnection,omitempty" jsonschema:"Direct mode: send on a fresh connection instead of reusing a kept-alive one to the same target"`
+	TLSConfig     *config.TLSOptions  `json:"tlsConfig,omitempty" jsonschema:"Direct mode: TLS client certificate, CA bundle, version, and cipher options"`
+	Auth          *config.AuthOptions `json:"auth,omitempty" jsonschema:"Direct mode: HTTP authentication (basic, digest, ntlm, negotiate), over the config entry for the host"`
+
+	FollowRedirects bool `json:"followRedirects,omitempty" jsonschema:"Follow 3xx redirects and return the final response with the redirect chain"`
+	MaxRedirects    int  `json:"maxRedirects,omitempty" jsonschema:"Maximum redirects to follow (default 5, max 20)"`
+
+	DryRun bool `json:"dryRun,omitempty" jsonschema:"Return the exact request that would be sent, without sending it"`
 }
 
 // defaultBodyLimit is the default response body byte limit across tools.
@@ -31,63 +59,204 @@ const defaultBodyLimit = 10000
 type SendRequestOutput struct {
 	StatusCode int            `json:"statusCode"`
 	Headers    map[string]any `json:"headers,omitempty"`
+	HeaderList []burp.Header  `json:"headerList,omitempty"`
 	Body       string         `json:"body,omitempty"`
-	BodySize   int            `json:"bodySize"`
-	Truncated  bool           `json:"truncated,omitempty"`
+	BodyEnvelope
+
+	RedirectChain []RedirectHop `json:"redirectChain,omitempty"`
+	FinalURL      string        `json:"finalUrl,omitempty"`
+	Retries       int           `json:"retries,omitempty"`
+	// QueueWaitMs is the time spent waiting for a per-host concurrency slot.
+	QueueWaitMs int64           `json:"queueWaitMs,omitempty"`
+	Preview     *RequestPreview `json:"preview,omitempty"`
+	// Reauthenticated is set when the auth profile's session had expired
+	// and the request was resent after logging in again.
+	Reauthenticated bool `json:"reauthenticated,omitempty"`
 }
 
 func sendRequestHandler(client *burp.Client) func(context.Context, *mcp.CallToolRequest, SendRequestInput) (*mcp.CallToolResult, SendRequestOutput, error) {
 	return func(ctx context.Context, _ *mcp.CallToolRequest, input SendRequestInput) (*mcp.CallToolResult, SendRequestOutput, error) {
-		if err := validateRawRequest(input.Raw); err != nil {
+		ctx = burp.WithInstance(ctx, input.Instance)
+		ctx, retries := burp.WithRetryCounter(ctx)
+		ctx, queueWait := withQueueWait(ctx)
+
+		if err := checkBodyEncoding(input.BodyEncoding); err != nil {
+			return nil, SendRequestOutput{}, err
+		}
+		if err := checkBodyView(input.BodyView); err != nil {
+			return nil, SendRequestOutput{}, err
+		}
+		if input.Relevance != nil && input.BodyView != "" && input.BodyView != bodyViewRaw {
+			return nil, SendRequestOutput{}, fmt.Errorf("relevance works on the raw body and can't be combined with bodyView %s", input.BodyView)
+		}
+		raw, exact, err := rawRequestText(input.Raw, input.RawBase64, input.Normalize)
+		if err != nil {
+			return nil, SendRequestOutput{}, err
+		}
+		var rawNorm string
+		var parsed *burp.ParsedHTTPRequest
+		if exact {
+			if !input.Direct {
+				return nil, SendRequestOutput{}, fmt.Errorf("normalize: false and rawBase64 require direct: true; Burp rebuilds the request")
+			}
+			if err := checkExactRequest(input.HeaderProfile, input.AuthProfile); err != nil {
+				return nil, SendRequestOutput{}, err
+			}
+			if input.Auth != nil {
+				return nil, SendRequestOutput{}, fmt.Errorf("auth rewrites the request and cannot be used with normalize: false or rawBase64")
+			}
+			rawNorm, parsed, err = prepareExactRequest(raw)
+		} else {
+			rawNorm, parsed, err = prepareRequest(raw, input.HeaderProfile)
+		}
+		if err != nil {
 			return nil, SendRequestOutput{}, err
 		}
-
-		parsed := burp.ParseRawRequest(input.Raw)
 
 		t, err := resolveTarget(input.Host, input.Port, input.TLS, parsed.Host)
 		if err != nil {
 			return nil, SendRequestOutput{}, err
 		}
 
-		rawNorm := normalizeRawRequest(input.Raw)
-		responseText, err := sendWithFallback(ctx, client, rawNorm, parsed, t)
+		send := func(rawNorm string, parsed *burp.ParsedHTTPRequest, next resolvedTarget) (string, error) {
+			return sendWithFallback(ctx, client, rawNorm, parsed, next)
+		}
+		var direct *directOptions
+		if input.Direct {
+			opts := newDirectOptions(input.TLSConfig)
+			opts.NewConnection = input.NewConnection
+			opts.NoReuse = exact || input.Framing.custom()
+			send = func(rawNorm string, _ *burp.ParsedHTTPRequest, next resolvedTarget) (string, error) {
+				o := opts
+				// SNI and connect overrides belong to the original target only.
+				if next == t {
+					o.SNI, o.ConnectHost = input.SNI, input.ConnectHost
+				}
+				auth, err := directAuth(next, t, input.Auth)
+				if err != nil {
+					return "", err
+				}
+				// HTTP authentication would rewrite the exact bytes.
+				if auth != nil && !(exact && next == t) {
+					return sendDirectAuth(ctx, next, rawNorm, o, *auth)
+				}
+				return sendDirect(ctx, next, []byte(rawNorm), o)
+			}
+			first := opts
+			first.SNI, first.ConnectHost = input.SNI, input.ConnectHost
+			direct = &first
+		} else if input.SNI != "" || input.ConnectHost != "" || input.TLSConfig != nil || input.Auth != nil || input.NewConnection {
+			return nil, SendRequestOutput{}, fmt.Errorf("sni, connectHost, tlsConfig, auth, and newConnection require direct: true")
+		}
+
+		loginSend := func(raw string, next resolvedTarget) (string, error) {
+			return send(raw, burp.ParseRawRequest(raw), next)
+		}
+		base := rawNorm
+		if dryRun(input.DryRun) {
+			if rawNorm, err = input.Framing.apply(base, contentLengthKeep, !exact); err != nil {
+				return nil, SendRequestOutput{}, err
+			}
+			preview, err := previewRequest(ctx, t, rawNorm, direct)
+			if err != nil {
+				return nil, SendRequestOutput{}, err
+			}
+			if err := previewAuthProfile(preview, input.AuthProfile); err != nil {
+				return nil, SendRequestOutput{}, err
+			}
+			return nil, SendRequestOutput{Preview: preview}, nil
+		}
+		rawNorm, err = applyAuthProfile(base, input.AuthProfile, loginSend)
+		if err != nil {
+			return nil, SendRequestOutput{}, err
+		}
+		if rawNorm, err = input.Framing.apply(rawNorm, contentLengthKeep, !exact); err != nil {
+			return nil, SendRequestOutput{}, err
+		}
+		parsed = burp.ParseRawRequest(rawNorm)
+
+		sentAt := time.Now()
+		responseText, err := send(rawNorm, parsed, t)
+		if err != nil {
+			return nil, SendRequestOutput{}, err
+		}
+		refreshed, reauth, err := refreshAuthProfile(base, input.AuthProfile, responseText, sentAt, loginSend)
 		if err != nil {
 			return nil, SendRequestOutput{}, err
 		}
+		if reauth {
+			if refreshed, err = input.Framing.apply(refreshed, contentLengthKeep, !exact); err != nil {
+				return nil, SendRequestOutput{}, err
+			}
+			rawNorm, parsed = refreshed, burp.ParseRawRequest(refreshed)
+			if responseText, err = send(rawNorm, parsed, t); err != nil {
+				return nil, SendRequestOutput{}, err
+			}
+		}
 
-		bodyLimit := input.BodyLimit
-		if bodyLimit == 0 {
-			bodyLimit = defaultBodyLimit
+		var chain []RedirectHop
+		var finalURL string
+		if input.FollowRedirects {
+			maxHops := input.MaxRedirects
+			if maxHops <= 0 {
+				maxHops = defaultMaxRedirects
+			}
+			maxHops = min(maxHops, maxRedirectsCap)
+			responseText, chain, finalURL, err = followRedirects(send, rawNorm, parsed, t, responseText, maxHops)
+			if err != nil {
+				return nil, SendRequestOutput{}, err
+			}
 		}
-		parseLimit := bodyLimit
+
+		parseLimit, relevance := input.BodyLimit, input.Relevance
 		if input.HeadersOnly {
-			parseLimit = 1
+			parseLimit, relevance = 1, nil
 		}
 
-		resp := burp.ParseHTTPResponse(responseText, input.BodyOffset, parseLimit)
+		cs := bodyCharset(responseText)
+		bodyOffset := alignOffset(cs, input.BodyOffset)
+		resp := parseRelevantResponse(ctx, responseText, bodyOffset, parseLimit, defaultBodyLimit, relevance)
 		if resp == nil {
-			return nil, SendRequestOutput{}, fmt.Errorf("failed to parse response")
-		}
-
-		headers := resp.Headers
-		if !input.AllHeaders {
-			headers = burp.FilterHeaders(headers)
+			return nil, SendRequestOutput{}, parseFailure("failed to parse response")
 		}
+		alignWindow(cs, resp)
 
+		headers, headerList := outputHeaders(resp, input.AllHeaders)
 		output := SendRequestOutput{
-			StatusCode: resp.StatusCode,
-			Headers:    burp.FlattenHeaders(headers),
-			BodySize:   resp.BodySize,
+			StatusCode:      resp.StatusCode,
+			Headers:         headers,
+			HeaderList:      headerList,
+			BodyEnvelope:    BodyEnvelope{BodySize: resp.BodySize},
+			RedirectChain:   chain,
+			FinalURL:        finalURL,
+			Retries:         retries(),
+			QueueWaitMs:     queueWait().Milliseconds(),
+			Reauthenticated: reauth,
+		}
+		if input.HeadersOnly {
+			return nil, output, nil
 		}
-		if !input.HeadersOnly {
-			output.Body = resp.Body
-			output.Truncated = resp.Truncated
+		if body, env, ok := viewBody(ctx, responseText, input.BodyView, input.BodyOffset, input.BodyLimit, "resend"); ok {
+			output.Body, output.BodyEnvelope = body, env
+		} else {
+			output.BodyEnvelope = bodyEnvelope(resp, bodyOffset, "resend", relevance)
+			output.Body = encodeBody(&output.BodyEnvelope, resp.Body, cs, bodyOffset, input.BodyEncoding, input.Hexdump)
 		}
 
 		return nil, output, nil
 	}
 }
 
+// outputHeaders returns resp's headers for a tool output, grouped by name
+// and as the ordered list, only the security-relevant ones unless all is set.
+func outputHeaders(resp *burp.ParsedHTTPResponse, all bool) (map[string]any, []burp.Header) {
+	list := resp.HeaderList
+	if !all {
+		list = burp.FilterHeaderList(list)
+	}
+	return burp.FlattenHeaders(burp.HeaderMap(list)), list
+}
+
 // tryHTTP2 sends the request via HTTP/2 using Burp's send_http2_request tool.
 func tryHTTP2(ctx context.Context, client *burp.Client, parsed *burp.ParsedHTTPRequest, host string, port int, tls bool) (string, error) {
 	scheme := "https"
@@ -122,7 +291,8 @@ func tryHTTP2(ctx context.Context, client *burp.Client, parsed *burp.ParsedHTTPR
 		"usesHttps":      tls,
 	}
 
-	return client.CallToolWithTimeout(ctx, "send_http2_request", args, http2Timeout)
+	// No retries: a failed HTTP/2 attempt falls back to HTTP/1.1 instead.
+	return client.CallToolWithTimeout(burp.WithoutRetry(ctx), "send_http2_request", args, http2Timeout)
 }
 
 // tryHTTP1 sends the request via HTTP/1.1 using Burp's send_http1_request tool.
@@ -139,8 +309,8 @@ func tryHTTP1(ctx context.Context, client *burp.Client, rawContent string, host
 
 // RegisterSendRequestTool registers the burp_send_request tool.
 func RegisterSendRequestTool(server *mcp.Server, client *burp.Client) {
-	mcp.AddTool(server, &mcp.Tool{
+	addTool(server, &mcp.Tool{
 		Name:        "burp_send_request",
-		Description: `Send HTTP request via Burp. Returns {statusCode, headers, body, bodySize, truncated}. Default: security headers only, 10KB body. Options: allHeaders, headersOnly, bodyLimit, bodyOffset.`,
+		Description: `Send HTTP request via Burp. Returns {statusCode, headers, body, bodySize, returnedBytes, truncated, continuationHint, bodyEncoding, charset, fileType, bodyView, page}. Default: security headers only, 10KB body; binary bodies come back base64 (bodyEncoding, hexdump to change), and Shift_JIS, GBK, Latin-1, and UTF-16 text is transcoded to UTF-8 with charset naming the original. bodyView text returns an HTML page as readable text, and title-and-forms as page: {title, headings, forms: [{action, method, fields}], inputs}, to save tokens on page-level recon. Options: allHeaders, headersOnly, bodyLimit, bodyOffset, followRedirects (adds redirectChain, finalUrl). retries counts transient Burp failures retried; queueWaitMs is time spent waiting behind the config concurrency cap for the host. direct: true bypasses Burp, reusing kept-alive connections per target unless newConnection is set, with sni/connectHost to split SNI, connect address, and Host header, and auth for Basic, Digest, NTLM, or Negotiate (NTLM only, no Kerberos) logins. With direct, normalize: false or rawBase64 sends the bytes exactly as given (bare LF, missing CRLFCRLF, conflicting lengths) for smuggling and parser-differential tests. framing overrides Content-Length or re-encodes the body as chunked, with chunk size and trailers. authProfile injects credentials from a config auth profile.`,
 	}, sendRequestHandler(client))
 }
@@ -10,11 +10,12 @@ import (
 
 // SendToIntruderInput is the input for burp_send_to_intruder.
 type SendToIntruderInput struct {
-	Raw     string `json:"raw" jsonschema:"required,Raw HTTP request"`
-	Host    string `json:"host" jsonschema:"required,Target hostname"`
-	Port    int    `json:"port,omitempty" jsonschema:"Target port (default based on TLS)"`
-	TLS     *bool  `json:"tls,omitempty" jsonschema:"Use HTTPS (default true)"`
-	TabName string `json:"tabName,omitempty" jsonschema:"Intruder tab name"`
+	Raw      string `json:"raw" jsonschema:"required,Raw HTTP request"`
+	Host     string `json:"host" jsonschema:"required,Target hostname"`
+	Port     int    `json:"port,omitempty" jsonschema:"Target port (default based on TLS)"`
+	TLS      *bool  `json:"tls,omitempty" jsonschema:"Use HTTPS (default true)"`
+	TabName  string `json:"tabName,omitempty" jsonschema:"Intruder tab name"`
+	Instance string `json:"instance,omitempty" jsonschema:"Named Burp instance from config (default: the --burp-url connection)"`
 }
 
 // SendToIntruderOutput is the output.
@@ -24,6 +25,8 @@ type SendToIntruderOutput struct {
 
 func sendToIntruderHandler(client *burp.Client) func(context.Context, *mcp.CallToolRequest, SendToIntruderInput) (*mcp.CallToolResult, SendToIntruderOutput, error) {
 	return func(ctx context.Context, _ *mcp.CallToolRequest, input SendToIntruderInput) (*mcp.CallToolResult, SendToIntruderOutput, error) {
+		ctx = burp.WithInstance(ctx, input.Instance)
+
 		if err := validateRawRequest(input.Raw); err != nil {
 			return nil, SendToIntruderOutput{}, err
 		}
@@ -32,8 +35,15 @@ func sendToIntruderHandler(client *burp.Client) func(context.Context, *mcp.CallT
 		if err != nil {
 			return nil, SendToIntruderOutput{}, err
 		}
+		if err := checkScope(ctx, t.Host); err != nil {
+			return nil, SendToIntruderOutput{}, err
+		}
 
 		rawNorm := normalizeRawRequest(input.Raw)
+		summary := fmt.Sprintf("Intruder attack on %s:%d", t.Host, t.Port)
+		if err := requireApproval(ctx, "burp_send_to_intruder", t, summary, rawNorm); err != nil {
+			return nil, SendToIntruderOutput{}, err
+		}
 
 		args := map[string]any{
 			"content":        rawNorm,
@@ -63,7 +73,7 @@ func sendToIntruderHandler(client *burp.Client) func(context.Context, *mcp.CallT
 
 // RegisterSendToIntruderTool registers the burp_send_to_intruder tool.
 func RegisterSendToIntruderTool(server *mcp.Server, client *burp.Client) {
-	mcp.AddTool(server, &mcp.Tool{
+	addTool(server, &mcp.Tool{
 		Name:        "burp_send_to_intruder",
 		Description: `Send HTTP request to Intruder. Params: raw (request), host, port, tls, tabName.`,
 	}, sendToIntruderHandler(client))
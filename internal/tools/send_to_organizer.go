@@ -0,0 +1,84 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/c0tton-fluff/burp-mcp-server/internal/burp"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// SendToOrganizerInput is the input for burp_send_to_organizer.
+type SendToOrganizerInput struct {
+	Raw      string `json:"raw,omitempty" jsonschema:"Raw HTTP request (or use index)"`
+	Response string `json:"response,omitempty" jsonschema:"Raw HTTP response to file alongside the request"`
+	Index    int    `json:"index,omitempty" jsonschema:"Proxy history index (1-based) to file instead of raw"`
+	Host     string `json:"host,omitempty" jsonschema:"Target hostname (default: Host header)"`
+	Port     int    `json:"port,omitempty" jsonschema:"Target port (default based on TLS)"`
+	TLS      *bool  `json:"tls,omitempty" jsonschema:"Use HTTPS (default true)"`
+	Note     string `json:"note,omitempty" jsonschema:"Note attached to the Organizer item"`
+	Instance string `json:"instance,omitempty" jsonschema:"Named Burp instance from config (default: the --burp-url connection)"`
+}
+
+// SendToOrganizerOutput is the output.
+type SendToOrganizerOutput struct {
+	Message string `json:"message"`
+}
+
+func sendToOrganizerHandler(client *burp.Client) func(context.Context, *mcp.CallToolRequest, SendToOrganizerInput) (*mcp.CallToolResult, SendToOrganizerOutput, error) {
+	return func(ctx context.Context, _ *mcp.CallToolRequest, input SendToOrganizerInput) (*mcp.CallToolResult, SendToOrganizerOutput, error) {
+		ctx = burp.WithInstance(ctx, input.Instance)
+
+		reqRaw, respRaw := input.Raw, input.Response
+		if input.Index > 0 {
+			if reqRaw != "" {
+				return nil, SendToOrganizerOutput{}, fmt.Errorf("provide raw or index, not both")
+			}
+			var err error
+			if reqRaw, respRaw, err = historyEntry(ctx, client, input.Index); err != nil {
+				return nil, SendToOrganizerOutput{}, err
+			}
+		}
+
+		if err := validateRawRequest(reqRaw); err != nil {
+			return nil, SendToOrganizerOutput{}, err
+		}
+
+		parsed := burp.ParseRawRequest(reqRaw)
+		t, err := resolveTarget(input.Host, input.Port, input.TLS, parsed.Host)
+		if err != nil {
+			return nil, SendToOrganizerOutput{}, err
+		}
+
+		args := map[string]any{
+			"content":        normalizeRawRequest(reqRaw),
+			"targetHostname": t.Host,
+			"targetPort":     t.Port,
+			"usesHttps":      t.UseTLS,
+		}
+		if respRaw != "" {
+			args["response"] = respRaw
+		}
+		if input.Note != "" {
+			args["notes"] = input.Note
+		}
+
+		_, err = client.CallTool(ctx, "send_to_organizer", args)
+		if err != nil {
+			return nil, SendToOrganizerOutput{}, fmt.Errorf("failed to send to organizer: %w", err)
+		}
+
+		return nil, SendToOrganizerOutput{
+			Message: fmt.Sprintf("Sent %s %s to Organizer for %s:%d", parsed.Method, parsed.Path, t.Host, t.Port),
+		}, nil
+	}
+}
+
+// RegisterSendToOrganizerTool registers the burp_send_to_organizer tool.
+func RegisterSendToOrganizerTool(server *mcp.Server, client *burp.Client) {
+	addTool(server, &mcp.Tool{
+		Name: "burp_send_to_organizer",
+		Description: `File a request (and optional response) in Burp's Organizer with a note for human triage. ` +
+			`Params: raw or index (proxy history), response, host, port, tls, note.`,
+	}, sendToOrganizerHandler(client))
+}
@@ -0,0 +1,77 @@
+package tools
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestSendToOrganizer_Raw(t *testing.T) {
+	f := &fakeBurp{}
+	client := startFakeBurp(t, f)
+	handler := sendToOrganizerHandler(client)
+
+	_, out, err := handler(context.Background(), nil, SendToOrganizerInput{
+		Raw:  "POST /login HTTP/1.1\nHost: shop.example\n\nuser=a",
+		Note: "possible SQLi in user",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out.Message != "Sent POST /login to Organizer for shop.example:443" {
+		t.Errorf("message = %q", out.Message)
+	}
+	if len(f.organized) != 1 {
+		t.Fatalf("organizer calls = %d", len(f.organized))
+	}
+	args := f.organized[0]
+	if args["notes"] != "possible SQLi in user" || args["targetHostname"] != "shop.example" || args["usesHttps"] != true || args["response"] != nil {
+		t.Errorf("args = %v", args)
+	}
+	if content, _ := args["content"].(string); !strings.HasPrefix(content, "POST /login HTTP/1.1\r\nHost: shop.example\r\n") {
+		t.Errorf("content = %q", content)
+	}
+}
+
+func TestSendToOrganizer_Index(t *testing.T) {
+	f := &fakeBurp{}
+	f.add(historyJSON("a.test", "/first"), "")
+	f.add(historyJSON("b.test", "/second"), "")
+	client := startFakeBurp(t, f)
+	handler := sendToOrganizerHandler(client)
+
+	_, out, err := handler(context.Background(), nil, SendToOrganizerInput{Index: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.Message, "GET /second") || len(f.organized) != 1 {
+		t.Fatalf("message = %q, calls = %d", out.Message, len(f.organized))
+	}
+	args := f.organized[0]
+	if args["targetHostname"] != "b.test" || !strings.Contains(args["response"].(string), "200 OK") {
+		t.Errorf("args = %v", args)
+	}
+
+	if _, _, err := handler(context.Background(), nil, SendToOrganizerInput{Index: 3}); err == nil {
+		t.Error("expected error past the end of history")
+	}
+}
+
+func TestSendToOrganizer_Errors(t *testing.T) {
+	f := &fakeBurp{}
+	f.add(historyJSON("a.test", "/"), "")
+	client := startFakeBurp(t, f)
+	handler := sendToOrganizerHandler(client)
+
+	for name, in := range map[string]SendToOrganizerInput{
+		"raw and index": {Raw: "GET / HTTP/1.1\r\nHost: a.test\r\n\r\n", Index: 1},
+		"neither":       {Note: "no request"},
+	} {
+		if _, _, err := handler(context.Background(), nil, in); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+	if len(f.organized) != 0 {
+		t.Errorf("organizer called %d times", len(f.organized))
+	}
+}
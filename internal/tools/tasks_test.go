@@ -0,0 +1,81 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/c0tton-fluff/burp-mcp-server/internal/tasks"
+)
+
+func TestCrawl_Background(t *testing.T) {
+	release := make(chan struct{})
+	target := startTestTarget(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		switch r.URL.Path {
+		case "/":
+			fmt.Fprint(w, `<a href="/a">a</a><a href="/slow">slow</a>`)
+		case "/slow":
+			select {
+			case <-release:
+			case <-r.Context().Done():
+			}
+		default:
+			fmt.Fprint(w, `ok`)
+		}
+	})
+	defer close(release)
+	start := fmt.Sprintf("http://%s:%d/", target.Host, target.Port)
+
+	_, out, err := crawlHandler(nil)(context.Background(), nil, CrawlInput{URL: start, Depth: 2, Background: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out.TaskID == "" || len(out.Pages) != 0 {
+		t.Fatalf("out = %+v", out)
+	}
+
+	// Wait for the crawl to reach the slow page.
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		_, snap, err := getTaskHandler()(context.Background(), nil, GetTaskInput{TaskID: out.TaskID})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if snap.Progress != nil && snap.Progress.Done >= 2 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("no progress: %+v", snap)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	_, listed, err := listTasksHandler()(context.Background(), nil, ListTasksInput{Status: tasks.StatusRunning})
+	if err != nil {
+		t.Fatal(err)
+	}
+	found := false
+	for _, s := range listed.Tasks {
+		found = found || s.ID == out.TaskID && s.Kind == "crawl" && s.Result == nil
+	}
+	if !found {
+		t.Fatalf("running tasks = %+v", listed.Tasks)
+	}
+
+	_, snap, err := cancelTaskHandler()(context.Background(), nil, CancelTaskInput{TaskID: out.TaskID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if snap.Status != tasks.StatusCanceled || snap.Result == nil {
+		t.Errorf("snapshot = %+v", snap)
+	}
+	if _, _, err := cancelTaskHandler()(context.Background(), nil, CancelTaskInput{TaskID: out.TaskID}); err == nil {
+		t.Error("expected error canceling a finished task")
+	}
+	if _, _, err := getTaskHandler()(context.Background(), nil, GetTaskInput{TaskID: "T-0"}); err == nil {
+		t.Error("expected error for unknown task")
+	}
+}
@@ -0,0 +1,325 @@
+package tools
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/c0tton-fluff/burp-mcp-server/internal/burp"
+	"github.com/c0tton-fluff/burp-mcp-server/internal/config"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// tlsExpiryWarning is how close to expiry a certificate gets flagged.
+const tlsExpiryWarning = 30 * 24 * time.Hour
+
+// defaultALPN are the protocols offered, and probed one by one, by default.
+var defaultALPN = []string{"h2", "http/1.1"}
+
+// TLSInfoInput is the input for burp_tls_info.
+type TLSInfoInput struct {
+	Host string   `json:"host" jsonschema:"required,Target host, or host:port"`
+	Port int      `json:"port,omitempty" jsonschema:"Target port (default 443)"`
+	SNI  string   `json:"sni,omitempty" jsonschema:"TLS server name to send (default: host)"`
+	ALPN []string `json:"alpn,omitempty" jsonschema:"ALPN protocols to offer and probe individually (default: h2, http/1.1)"`
+
+	TLSConfig *config.TLSOptions `json:"tlsConfig,omitempty" jsonschema:"TLS client certificate, CA bundle, version, and cipher options for the main handshake"`
+}
+
+// CertInfo describes one certificate of the chain.
+type CertInfo struct {
+	Subject            string    `json:"subject"`
+	Issuer             string    `json:"issuer"`
+	SANs               []string  `json:"sans,omitempty"`
+	NotBefore          time.Time `json:"notBefore"`
+	NotAfter           time.Time `json:"notAfter"`
+	Serial             string    `json:"serial"`
+	Key                string    `json:"key"`
+	SignatureAlgorithm string    `json:"signatureAlgorithm"`
+	SHA256             string    `json:"sha256"`
+	IsCA               bool      `json:"isCA,omitempty"`
+}
+
+// TLSInfoOutput is the output of burp_tls_info.
+type TLSInfoOutput struct {
+	Address     string     `json:"address"`
+	ServerName  string     `json:"serverName"`
+	Protocol    string     `json:"protocol"`
+	CipherSuite string     `json:"cipherSuite"`
+	ALPN        string     `json:"alpn,omitempty"`
+	Chain       []CertInfo `json:"chain"`
+	Trusted     bool       `json:"trusted"`
+	TrustError  string     `json:"trustError,omitempty"`
+	// Versions maps each TLS version to whether the server accepts it.
+	Versions map[string]bool `json:"versions"`
+	// ALPNSupported are the offered protocols the server selects when
+	// offered alone.
+	ALPNSupported   []string            `json:"alpnSupported"`
+	InsecureCiphers []string            `json:"insecureCiphers,omitempty"`
+	Weaknesses      []burp.ScannerIssue `json:"weaknesses"`
+}
+
+// tlsVersionName is the inverse of tlsVersions.
+func tlsVersionName(v uint16) string {
+	for name, id := range tlsVersions {
+		if id == v {
+			return "TLS " + name
+		}
+	}
+	return fmt.Sprintf("0x%04x", v)
+}
+
+// allCipherIDs is every suite Go implements, so version probes aren't
+// limited to Go's modern defaults.
+func allCipherIDs() []uint16 {
+	var ids []uint16
+	for _, s := range slices.Concat(tls.CipherSuites(), tls.InsecureCipherSuites()) {
+		ids = append(ids, s.ID)
+	}
+	return ids
+}
+
+// keyDescription names a public key type and size, e.g. "RSA 2048".
+func keyDescription(cert *x509.Certificate) (string, int) {
+	switch k := cert.PublicKey.(type) {
+	case *rsa.PublicKey:
+		return fmt.Sprintf("RSA %d", k.N.BitLen()), k.N.BitLen()
+	case *ecdsa.PublicKey:
+		return fmt.Sprintf("ECDSA %d", k.Curve.Params().BitSize), k.Curve.Params().BitSize
+	case ed25519.PublicKey:
+		return "Ed25519", 256
+	}
+	return cert.PublicKeyAlgorithm.String(), 0
+}
+
+// selfSigned reports whether cert is signed by its own key.
+func selfSigned(cert *x509.Certificate) bool {
+	return bytes.Equal(cert.RawIssuer, cert.RawSubject) &&
+		cert.CheckSignature(cert.SignatureAlgorithm, cert.RawTBSCertificate, cert.Signature) == nil
+}
+
+func certInfo(cert *x509.Certificate) CertInfo {
+	sum := sha256.Sum256(cert.Raw)
+	key, _ := keyDescription(cert)
+	info := CertInfo{
+		Subject:            cert.Subject.String(),
+		Issuer:             cert.Issuer.String(),
+		SANs:               slices.Clone(cert.DNSNames),
+		NotBefore:          cert.NotBefore,
+		NotAfter:           cert.NotAfter,
+		Serial:             cert.SerialNumber.Text(16),
+		Key:                key,
+		SignatureAlgorithm: cert.SignatureAlgorithm.String(),
+		SHA256:             hex.EncodeToString(sum[:]),
+		IsCA:               cert.IsCA,
+	}
+	for _, ip := range cert.IPAddresses {
+		info.SANs = append(info.SANs, ip.String())
+	}
+	info.SANs = append(info.SANs, cert.EmailAddresses...)
+	return info
+}
+
+// tlsWeaknesses flags certificate and configuration problems. now is
+// passed in for tests.
+func tlsWeaknesses(out TLSInfoOutput, certs []*x509.Certificate, now time.Time) []burp.ScannerIssue {
+	var issues []burp.ScannerIssue
+	add := func(name, severity, detail string) {
+		issues = append(issues, burp.ScannerIssue{Name: name, Severity: severity, Confidence: confCertain, URL: out.Address, IssueDetail: detail})
+	}
+
+	if len(certs) > 0 {
+		leaf := certs[0]
+		switch {
+		case now.After(leaf.NotAfter):
+			add("Certificate expired", sevHigh, "Expired "+leaf.NotAfter.Format(time.DateOnly)+".")
+		case now.Before(leaf.NotBefore):
+			add("Certificate not yet valid", sevMedium, "Valid from "+leaf.NotBefore.Format(time.DateOnly)+".")
+		case leaf.NotAfter.Sub(now) < tlsExpiryWarning:
+			add("Certificate expires soon", sevLow, "Expires "+leaf.NotAfter.Format(time.DateOnly)+".")
+		}
+		if err := leaf.VerifyHostname(out.ServerName); err != nil {
+			add("Certificate hostname mismatch", sevMedium, err.Error())
+		}
+		if len(certs) == 1 && selfSigned(leaf) {
+			add("Self-signed certificate", sevMedium, "The server presents a single self-signed certificate.")
+		}
+		for i, c := range certs {
+			key, bits := keyDescription(c)
+			switch c.PublicKeyAlgorithm {
+			case x509.RSA:
+				if bits < 2048 {
+					add("Weak certificate key", sevMedium, fmt.Sprintf("Certificate %d uses %s; 2048 bits is the minimum.", i, key))
+				}
+			case x509.ECDSA:
+				if bits < 256 {
+					add("Weak certificate key", sevMedium, fmt.Sprintf("Certificate %d uses %s.", i, key))
+				}
+			}
+			// Clients don't check a root's own signature.
+			if i > 0 && selfSigned(c) {
+				continue
+			}
+			switch c.SignatureAlgorithm {
+			case x509.MD5WithRSA, x509.SHA1WithRSA, x509.ECDSAWithSHA1, x509.DSAWithSHA1:
+				add("Weak certificate signature", sevMedium, fmt.Sprintf("Certificate %d is signed with %s.", i, c.SignatureAlgorithm))
+			}
+		}
+	}
+	if !out.Trusted && out.TrustError != "" {
+		add("Untrusted certificate chain", sevLow, out.TrustError)
+	}
+
+	for _, v := range []string{"1.0", "1.1"} {
+		if out.Versions[v] {
+			add("Legacy TLS "+v+" supported", sevLow, "TLS "+v+" is deprecated (RFC 8996).")
+		}
+	}
+	if accepted, probed := out.Versions["1.3"]; probed && !accepted {
+		add("TLS 1.3 not supported", sevInfo, "The server does not accept TLS 1.3.")
+	}
+	if len(out.InsecureCiphers) > 0 {
+		add("Insecure cipher suites accepted", sevMedium, "Accepted: "+strings.Join(out.InsecureCiphers, ", ")+".")
+	}
+	if out.CipherSuite != "" && !strings.Contains(out.CipherSuite, "GCM") && !strings.Contains(out.CipherSuite, "CHACHA20") && !strings.HasPrefix(out.CipherSuite, "TLS_AES_") {
+		add("Non-AEAD cipher negotiated", sevLow, out.CipherSuite+" was chosen when modern suites were offered.")
+	}
+	return issues
+}
+
+func tlsInfoHandler() func(context.Context, *mcp.CallToolRequest, TLSInfoInput) (*mcp.CallToolResult, TLSInfoOutput, error) {
+	return func(ctx context.Context, _ *mcp.CallToolRequest, input TLSInfoInput) (*mcp.CallToolResult, TLSInfoOutput, error) {
+		host, port := strings.TrimSpace(input.Host), input.Port
+		if h, p, err := net.SplitHostPort(host); err == nil {
+			n, err := strconv.Atoi(p)
+			if err != nil {
+				return nil, TLSInfoOutput{}, fmt.Errorf("invalid port in host: %q", p)
+			}
+			host, port = h, n
+		}
+		host = strings.Trim(host, "[]")
+		if host == "" {
+			return nil, TLSInfoOutput{}, fmt.Errorf("host is required")
+		}
+		if port == 0 {
+			port = 443
+		}
+		alpn := input.ALPN
+		if len(alpn) == 0 {
+			alpn = defaultALPN
+		}
+
+		if err := checkDryRun(ctx); err != nil {
+			return nil, TLSInfoOutput{}, err
+		}
+		t := resolvedTarget{Host: host, Port: port, UseTLS: true}
+		opts := newDirectOptions(input.TLSConfig)
+		opts.SNI = input.SNI
+		addr, serverName := opts.endpoints(host, port)
+		if err := checkScope(ctx, host, addr); err != nil {
+			return nil, TLSInfoOutput{}, err
+		}
+		// Main handshake, one per version, one insecure-cipher probe, one per ALPN protocol.
+		if err := checkRateLimit(ctx, t, 1+len(tlsVersions)+1+len(alpn)); err != nil {
+			return nil, TLSInfoOutput{}, err
+		}
+		cfg, err := buildTLSConfig(serverName, opts.TLS)
+		if err != nil {
+			return nil, TLSInfoOutput{}, err
+		}
+		cfg.NextProtos = alpn
+
+		handshake := func(cfg *tls.Config) (tls.ConnectionState, error) {
+			conn, err := dialConn(ctx, addr, cfg, time.Now().Add(directTimeout))
+			if err != nil {
+				return tls.ConnectionState{}, err
+			}
+			defer conn.Close()
+			return conn.(*tls.Conn).ConnectionState(), nil
+		}
+
+		state, err := handshake(cfg)
+		if err != nil {
+			return nil, TLSInfoOutput{}, fmt.Errorf("handshake with %s: %w", addr, err)
+		}
+		out := TLSInfoOutput{
+			Address:       addr,
+			ServerName:    serverName,
+			Protocol:      tlsVersionName(state.Version),
+			CipherSuite:   tls.CipherSuiteName(state.CipherSuite),
+			ALPN:          state.NegotiatedProtocol,
+			Chain:         []CertInfo{},
+			Versions:      map[string]bool{},
+			ALPNSupported: []string{},
+		}
+		for _, c := range state.PeerCertificates {
+			out.Chain = append(out.Chain, certInfo(c))
+		}
+		if len(state.PeerCertificates) > 0 {
+			intermediates := x509.NewCertPool()
+			for _, c := range state.PeerCertificates[1:] {
+				intermediates.AddCert(c)
+			}
+			_, err := state.PeerCertificates[0].Verify(x509.VerifyOptions{Roots: cfg.RootCAs, Intermediates: intermediates})
+			out.Trusted = err == nil
+			if err != nil {
+				out.TrustError = err.Error()
+			}
+		}
+
+		// Probes use the bare server name so client options don't skew them.
+		probe := func(edit func(*tls.Config)) (tls.ConnectionState, error) {
+			c := &tls.Config{ServerName: serverName, InsecureSkipVerify: true, NextProtos: []string{"http/1.1"}}
+			edit(c)
+			return handshake(c)
+		}
+		for name, v := range tlsVersions {
+			_, err := probe(func(c *tls.Config) {
+				c.MinVersion, c.MaxVersion, c.CipherSuites = v, v, allCipherIDs()
+			})
+			out.Versions[name] = err == nil
+		}
+		var insecure []uint16
+		for _, s := range tls.InsecureCipherSuites() {
+			insecure = append(insecure, s.ID)
+		}
+		if st, err := probe(func(c *tls.Config) {
+			c.MinVersion, c.MaxVersion, c.CipherSuites = tls.VersionTLS10, tls.VersionTLS12, insecure
+		}); err == nil {
+			out.InsecureCiphers = append(out.InsecureCiphers, tls.CipherSuiteName(st.CipherSuite))
+		}
+		for _, proto := range alpn {
+			if st, err := probe(func(c *tls.Config) { c.NextProtos = []string{proto} }); err == nil && st.NegotiatedProtocol == proto {
+				out.ALPNSupported = append(out.ALPNSupported, proto)
+			}
+		}
+
+		out.Weaknesses = tlsWeaknesses(out, state.PeerCertificates, time.Now())
+		if out.Weaknesses == nil {
+			out.Weaknesses = []burp.ScannerIssue{}
+		}
+		return nil, out, nil
+	}
+}
+
+// RegisterTLSInfoTool registers the burp_tls_info tool.
+func RegisterTLSInfoTool(server *mcp.Server) {
+	addTool(server, &mcp.Tool{
+		Name: "burp_tls_info",
+		Description: `Connect directly to host:port over TLS and report the certificate chain (subject, SANs, issuer, validity, key, signature), negotiated protocol, cipher, and ALPN. ` +
+			`Probes which TLS versions, insecure cipher suites, and ALPN protocols the server accepts, and flags expired, mismatched, self-signed, or weak certificates and legacy protocols. ` +
+			`Returns {address, serverName, protocol, cipherSuite, alpn, chain: [{subject, issuer, sans, notBefore, notAfter, key, signatureAlgorithm, sha256}], trusted, versions, alpnSupported, insecureCiphers, weaknesses: [{name, severity, confidence, issueDetail}]}.`,
+	}, tlsInfoHandler())
+}
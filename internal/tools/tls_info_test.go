@@ -0,0 +1,93 @@
+package tools
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"io"
+	"log"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTLSInfo(t *testing.T) {
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	srv.Config.ErrorLog = log.New(io.Discard, "", 0)
+	srv.StartTLS()
+	defer srv.Close()
+	u, _ := url.Parse(srv.URL)
+
+	_, out, err := tlsInfoHandler()(context.Background(), nil, TLSInfoInput{Host: u.Host, SNI: "example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out.Protocol != "TLS 1.3" || out.ServerName != "example.com" || out.ALPN != "http/1.1" {
+		t.Errorf("protocol %q, server name %q, alpn %q", out.Protocol, out.ServerName, out.ALPN)
+	}
+	if len(out.Chain) != 1 || !strings.Contains(strings.Join(out.Chain[0].SANs, " "), "example.com") {
+		t.Errorf("chain = %+v", out.Chain)
+	}
+	if out.Trusted || !out.Versions["1.2"] || !out.Versions["1.3"] || out.Versions["1.0"] {
+		t.Errorf("trusted %v, versions %v", out.Trusted, out.Versions)
+	}
+	if len(out.ALPNSupported) != 1 || out.ALPNSupported[0] != "http/1.1" {
+		t.Errorf("alpnSupported = %v", out.ALPNSupported)
+	}
+	names := map[string]bool{}
+	for _, w := range out.Weaknesses {
+		names[w.Name] = true
+	}
+	if !names["Untrusted certificate chain"] || names["Certificate hostname mismatch"] || names["Legacy TLS 1.0 supported"] {
+		t.Errorf("weaknesses = %+v", out.Weaknesses)
+	}
+}
+
+func TestTLSWeaknesses(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	tmpl := &x509.Certificate{
+		SerialNumber:       big.NewInt(1),
+		Subject:            pkix.Name{CommonName: "old.example"},
+		DNSNames:           []string{"old.example"},
+		NotBefore:          now.AddDate(-1, 0, 0),
+		NotAfter:           now.AddDate(0, 0, -1),
+		SignatureAlgorithm: x509.SHA256WithRSA,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, _ := x509.ParseCertificate(der)
+
+	out := TLSInfoOutput{
+		ServerName:  "www.example.com",
+		CipherSuite: "TLS_ECDHE_RSA_WITH_AES_128_CBC_SHA",
+		Versions:    map[string]bool{"1.0": true, "1.1": false, "1.2": true, "1.3": false},
+	}
+	var got []string
+	for _, w := range tlsWeaknesses(out, []*x509.Certificate{cert}, now) {
+		got = append(got, w.Name)
+	}
+	want := []string{
+		"Certificate expired",
+		"Certificate hostname mismatch",
+		"Self-signed certificate",
+		"Weak certificate key",
+		"Legacy TLS 1.0 supported",
+		"TLS 1.3 not supported",
+		"Non-AEAD cipher negotiated",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("got %q\nwant %q", got, want)
+	}
+}
@@ -0,0 +1,158 @@
+package tools
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/c0tton-fluff/burp-mcp-server/internal/config"
+)
+
+// directOptions tune how direct (non-Burp) connections are established.
+type directOptions struct {
+	TLS config.TLSOptions
+	// SNI overrides the TLS server name (default: the target host).
+	SNI string
+	// ConnectHost overrides the TCP connect address, as host or host:port
+	// (default: the target host and port). The Host header is left untouched.
+	ConnectHost string
+	// NewConnection skips the connection pool: the request goes out on a
+	// fresh connection that is closed afterwards.
+	NewConnection bool
+	// NoReuse closes the connection after the request instead of pooling
+	// it. Exact bytes and custom framing can leave smuggled bytes queued on
+	// the server, which would poison whatever request is sent next.
+	NoReuse bool
+}
+
+// endpoints returns the TCP address to dial and the TLS server name for a target.
+// SNI, connect address, and Host header can all differ (domain fronting, routing tests).
+func (o directOptions) endpoints(host string, port int) (addr, serverName string) {
+	connectHost, connectPort := host, strconv.Itoa(port)
+	if o.ConnectHost != "" {
+		connectHost = o.ConnectHost
+		if h, p, err := net.SplitHostPort(o.ConnectHost); err == nil {
+			connectHost, connectPort = h, p
+		}
+	}
+	serverName = host
+	if o.SNI != "" {
+		serverName = o.SNI
+	}
+	return net.JoinHostPort(connectHost, connectPort), serverName
+}
+
+// newDirectOptions merges per-call TLS options over the configured defaults.
+func newDirectOptions(tlsOverride *config.TLSOptions) directOptions {
+	return directOptions{TLS: settings.DirectTLS.Merge(tlsOverride)}
+}
+
+var tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// buildTLSConfig turns TLS options into a tls.Config for the given server name.
+// Without a CA bundle, server certificates are not verified (pentest default).
+func buildTLSConfig(serverName string, opts config.TLSOptions) (*tls.Config, error) {
+	cfg := &tls.Config{
+		ServerName:         serverName,
+		InsecureSkipVerify: true,
+		NextProtos:         []string{"http/1.1"},
+	}
+
+	if opts.CABundle != "" {
+		pem, err := readPEM(opts.CABundle)
+		if err != nil {
+			return nil, fmt.Errorf("caBundle: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("caBundle: no certificates found")
+		}
+		cfg.RootCAs = pool
+		cfg.InsecureSkipVerify = false
+	}
+
+	if opts.ClientCert != "" || opts.ClientKey != "" {
+		if opts.ClientCert == "" || opts.ClientKey == "" {
+			return nil, fmt.Errorf("clientCert and clientKey must be set together")
+		}
+		certPEM, err := readPEM(opts.ClientCert)
+		if err != nil {
+			return nil, fmt.Errorf("clientCert: %w", err)
+		}
+		keyPEM, err := readPEM(opts.ClientKey)
+		if err != nil {
+			return nil, fmt.Errorf("clientKey: %w", err)
+		}
+		cert, err := tls.X509KeyPair(certPEM, keyPEM)
+		if err != nil {
+			return nil, fmt.Errorf("client certificate: %w", err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+
+	if opts.MinVersion != "" {
+		v, ok := tlsVersions[opts.MinVersion]
+		if !ok {
+			return nil, fmt.Errorf("unknown minVersion %q (use 1.0, 1.1, 1.2, 1.3)", opts.MinVersion)
+		}
+		cfg.MinVersion = v
+	}
+	if opts.MaxVersion != "" {
+		v, ok := tlsVersions[opts.MaxVersion]
+		if !ok {
+			return nil, fmt.Errorf("unknown maxVersion %q (use 1.0, 1.1, 1.2, 1.3)", opts.MaxVersion)
+		}
+		cfg.MaxVersion = v
+	}
+	if cfg.MinVersion != 0 && cfg.MaxVersion != 0 && cfg.MinVersion > cfg.MaxVersion {
+		return nil, fmt.Errorf("minVersion %s is above maxVersion %s", opts.MinVersion, opts.MaxVersion)
+	}
+
+	if len(opts.Ciphers) > 0 {
+		suites, err := cipherSuiteIDs(opts.Ciphers)
+		if err != nil {
+			return nil, err
+		}
+		cfg.CipherSuites = suites
+	}
+
+	return cfg, nil
+}
+
+// cipherSuiteIDs maps IANA cipher suite names to IDs, including insecure suites.
+func cipherSuiteIDs(names []string) ([]uint16, error) {
+	known := make(map[string]uint16)
+	for _, cs := range tls.CipherSuites() {
+		known[cs.Name] = cs.ID
+	}
+	for _, cs := range tls.InsecureCipherSuites() {
+		known[cs.Name] = cs.ID
+	}
+
+	ids := make([]uint16, 0, len(names))
+	for _, name := range names {
+		id, ok := known[strings.ToUpper(strings.TrimSpace(name))]
+		if !ok {
+			return nil, fmt.Errorf("unknown cipher suite %q", name)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// readPEM returns inline PEM as-is, or reads it from a file path.
+func readPEM(value string) ([]byte, error) {
+	if strings.HasPrefix(strings.TrimSpace(value), "-----BEGIN") {
+		return []byte(value), nil
+	}
+	return os.ReadFile(value)
+}
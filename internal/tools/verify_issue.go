@@ -0,0 +1,265 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/c0tton-fluff/burp-mcp-server/internal/burp"
+	"github.com/c0tton-fluff/burp-mcp-server/internal/store"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// VerifyIssueInput is the input for burp_verify_issue.
+type VerifyIssueInput struct {
+	Index         int    `json:"index,omitempty" jsonschema:"Issue position (1-based) in burp_get_scanner_issues with the same source"`
+	IssueID       string `json:"issueId,omitempty" jsonschema:"Serial number of an imported issue, instead of index; implies source=imported"`
+	Source        string `json:"source,omitempty" jsonschema:"live (default) or imported, as in burp_get_scanner_issues"`
+	Evidence      int    `json:"evidence,omitempty" jsonschema:"Evidence pair to replay (1-based, default 1)"`
+	Host          string `json:"host,omitempty" jsonschema:"Target host (default: the issue's)"`
+	Port          int    `json:"port,omitempty" jsonschema:"Target port (default: the issue's)"`
+	TLS           *bool  `json:"tls,omitempty" jsonschema:"Use HTTPS (default: the issue's scheme)"`
+	HeaderProfile string `json:"headerProfile,omitempty" jsonschema:"Header rule profile from config (default: 'default')"`
+	Instance      string `json:"instance,omitempty" jsonschema:"Named Burp instance from config (default: the --burp-url connection)"`
+}
+
+// VerifyIssueOutput is the output of burp_verify_issue.
+type VerifyIssueOutput struct {
+	Issue           burp.ScannerIssue `json:"issue"`
+	Verdict         string            `json:"verdict"`
+	Reason          string            `json:"reason"`
+	StatusCode      int               `json:"statusCode"`
+	PreviousVerdict string            `json:"previousVerdict,omitempty"`
+	// Tagged is set when the verdict was saved on the imported issue.
+	Tagged bool   `json:"tagged"`
+	Note   string `json:"note,omitempty"`
+}
+
+// issueReplay is what an issue check decides from.
+type issueReplay struct {
+	points   []InsertionPoint
+	original *burp.ParsedHTTPResponse // the evidence response; nil if there was none
+	resp     *burp.ParsedHTTPResponse
+}
+
+// issueCheck decides whether issues of one type reproduce.
+type issueCheck struct {
+	names []string // lowercase substrings of the issue names it applies to
+	check func(r issueReplay) (verdict, reason string)
+}
+
+var issueChecks = []issueCheck{
+	{names: []string{"sql injection"}, check: checkSQLInjection},
+	{names: []string{"cross-site scripting"}, check: checkReflectedPayload},
+	{names: []string{"open redirection"}, check: checkOpenRedirect},
+	{names: []string{"strict transport security"}, check: checkHSTSIssue},
+	{names: []string{"frameable response", "clickjacking"}, check: checkFrameable},
+	{names: []string{"without httponly"}, check: func(r issueReplay) (string, string) { return checkCookieFlag(r, "HttpOnly") }},
+	{names: []string{"without secure flag"}, check: func(r issueReplay) (string, string) { return checkCookieFlag(r, "Secure") }},
+}
+
+// sqlErrorRegex matches database error messages leaking into a response.
+var sqlErrorRegex = regexp.MustCompile(`(?i)you have an error in your sql syntax|warning: mysql|sqlstate\[|ora-\d{5}|pg::[a-z]+error|unterminated quoted string|syntax error at or near|unclosed quotation mark|microsoft ole db provider|sqlite3?::|sqlite_error|quoted string not properly terminated`)
+
+func checkSQLInjection(r issueReplay) (string, string) {
+	if m := sqlErrorRegex.FindString(r.resp.Body); m != "" {
+		return store.IssueReproduced, fmt.Sprintf("database error %q in the response", m)
+	}
+	if r.original != nil {
+		if m := sqlErrorRegex.FindString(r.original.Body); m != "" {
+			return store.IssueStale, fmt.Sprintf("the database error in the evidence (%q) is gone", m)
+		}
+	}
+	return store.IssueInconclusive, "no database error; blind and time-based injection can't be confirmed from one replay"
+}
+
+// issuePayloads returns the decoded insertion point values, skipping empty ones.
+func issuePayloads(points []InsertionPoint) []string {
+	var payloads []string
+	for _, p := range points {
+		v := p.Value
+		if p.In == "query" || p.In == "body" {
+			if unescaped, err := url.QueryUnescape(v); err == nil {
+				v = unescaped
+			}
+		}
+		if v != "" {
+			payloads = append(payloads, v)
+		}
+	}
+	return payloads
+}
+
+func checkReflectedPayload(r issueReplay) (string, string) {
+	var probes []string
+	for _, p := range issuePayloads(r.points) {
+		if strings.ContainsAny(p, `<>"'`) {
+			probes = append(probes, p)
+		}
+	}
+	if len(probes) == 0 {
+		return store.IssueInconclusive, "the evidence request carries no markup to look for"
+	}
+	for _, p := range probes {
+		if strings.Contains(r.resp.Body, p) {
+			return store.IssueReproduced, fmt.Sprintf("%q is reflected unencoded", p)
+		}
+	}
+	return store.IssueStale, fmt.Sprintf("%q is no longer reflected unencoded", probes[0])
+}
+
+func checkOpenRedirect(r issueReplay) (string, string) {
+	payloads := issuePayloads(r.points)
+	if len(payloads) == 0 {
+		return store.IssueInconclusive, "the issue names no parameter of the evidence request"
+	}
+	location := burp.GetHeader(r.resp.Headers, "Location")
+	for _, p := range payloads {
+		if location != "" && strings.Contains(location, p) {
+			return store.IssueReproduced, fmt.Sprintf("Location: %s", location)
+		}
+	}
+	if location == "" {
+		return store.IssueStale, fmt.Sprintf("no redirect (status %d)", r.resp.StatusCode)
+	}
+	return store.IssueStale, fmt.Sprintf("redirects to %s, not the injected target", location)
+}
+
+func checkHSTSIssue(r issueReplay) (string, string) {
+	if v := burp.GetHeader(r.resp.Headers, "Strict-Transport-Security"); v != "" {
+		return store.IssueStale, "Strict-Transport-Security: " + v
+	}
+	return store.IssueReproduced, "no Strict-Transport-Security header"
+}
+
+func checkFrameable(r issueReplay) (string, string) {
+	if v := burp.GetHeader(r.resp.Headers, "X-Frame-Options"); v != "" {
+		return store.IssueStale, "X-Frame-Options: " + v
+	}
+	if _, ok := parseCSP(burp.GetHeader(r.resp.Headers, "Content-Security-Policy"))["frame-ancestors"]; ok {
+		return store.IssueStale, "Content-Security-Policy sets frame-ancestors"
+	}
+	return store.IssueReproduced, "no X-Frame-Options header or frame-ancestors directive"
+}
+
+func checkCookieFlag(r issueReplay, flag string) (string, string) {
+	var set []string
+	for name, values := range r.resp.Headers {
+		if !strings.EqualFold(name, "Set-Cookie") {
+			continue
+		}
+		for _, v := range values {
+			c, err := http.ParseSetCookie(v)
+			if err != nil {
+				continue
+			}
+			if flag == "HttpOnly" && !c.HttpOnly || flag == "Secure" && !c.Secure {
+				return store.IssueReproduced, fmt.Sprintf("cookie %s is set without %s", c.Name, flag)
+			}
+			set = append(set, c.Name)
+		}
+	}
+	if len(set) == 0 {
+		return store.IssueInconclusive, "the response sets no cookies"
+	}
+	return store.IssueStale, fmt.Sprintf("every cookie set (%s) has %s", strings.Join(set, ", "), flag)
+}
+
+// verifyReplay applies the check for the issue's type, falling back to
+// comparing the status with the evidence's.
+func verifyReplay(name string, r issueReplay) (string, string) {
+	lower := strings.ToLower(name)
+	for _, c := range issueChecks {
+		for _, n := range c.names {
+			if strings.Contains(lower, n) {
+				return c.check(r)
+			}
+		}
+	}
+	if r.original != nil && r.original.StatusCode != r.resp.StatusCode {
+		return store.IssueInconclusive, fmt.Sprintf("no check for this issue type; the status changed from %d to %d", r.original.StatusCode, r.resp.StatusCode)
+	}
+	return store.IssueInconclusive, "no check for this issue type; compare the response with burp_get_issue_evidence"
+}
+
+func verifyIssueHandler(client *burp.Client, st *store.Store) func(context.Context, *mcp.CallToolRequest, VerifyIssueInput) (*mcp.CallToolResult, VerifyIssueOutput, error) {
+	return func(ctx context.Context, _ *mcp.CallToolRequest, input VerifyIssueInput) (*mcp.CallToolResult, VerifyIssueOutput, error) {
+		ctx = burp.WithInstance(ctx, input.Instance)
+
+		ref, err := findIssue(ctx, client, st, input.Index, input.IssueID, input.Source)
+		if err != nil {
+			return nil, VerifyIssueOutput{}, err
+		}
+		n := input.Evidence
+		if n == 0 {
+			n = 1
+		}
+		if n < 1 || n > len(ref.evidence) || ref.evidence[n-1].Request == "" {
+			return nil, VerifyIssueOutput{}, fmt.Errorf("issue %q has no evidence request %d to replay (%d pairs)", ref.issue.Name, n, len(ref.evidence))
+		}
+		evidence := ref.evidence[n-1]
+
+		rawNorm, parsed, err := prepareRequest(evidence.Request, input.HeaderProfile)
+		if err != nil {
+			return nil, VerifyIssueOutput{}, err
+		}
+		host, port, useTLS := input.Host, input.Port, input.TLS
+		if issueHost, issuePort, issueTLS := issueTarget(ref.issue.URL); host == "" && issueHost != "" {
+			host = issueHost
+			if port == 0 {
+				port = issuePort
+			}
+			if useTLS == nil {
+				useTLS = &issueTLS
+			}
+		}
+		t, err := resolveTarget(host, port, useTLS, parsed.Host)
+		if err != nil {
+			return nil, VerifyIssueOutput{}, err
+		}
+		resp, err := sendParsed(ctx, client, rawNorm, t, maxReadBody)
+		if err != nil {
+			return nil, VerifyIssueOutput{}, err
+		}
+
+		replay := issueReplay{points: issueInsertionPoints(evidence.Request, ref.issue.IssueDetail), resp: resp}
+		if evidence.Response != "" {
+			replay.original = burp.ParseHTTPResponse(evidence.Response, 0, maxReadBody)
+		}
+		verdict, reason := verifyReplay(ref.issue.Name, replay)
+
+		out := VerifyIssueOutput{
+			Issue:           ref.issue,
+			Verdict:         verdict,
+			Reason:          reason,
+			StatusCode:      resp.StatusCode,
+			PreviousVerdict: ref.issue.Verdict,
+		}
+		if ref.stored < 0 {
+			out.Note = "live issues aren't tagged; import Burp's issue report to keep verdicts"
+			return nil, out, nil
+		}
+		v := store.IssueVerification{At: time.Now().UTC(), Verdict: verdict, Reason: reason, StatusCode: resp.StatusCode}
+		if err := st.SetIssueVerification(ref.stored, v); err != nil {
+			return nil, VerifyIssueOutput{}, fmt.Errorf("record verification: %w", err)
+		}
+		out.Issue.Verdict = verdict
+		out.Tagged = true
+		return nil, out, nil
+	}
+}
+
+// RegisterVerifyIssueTool registers the burp_verify_issue tool.
+func RegisterVerifyIssueTool(server *mcp.Server, client *burp.Client, st *store.Store) {
+	addTool(server, &mcp.Tool{
+		Name: "burp_verify_issue",
+		Description: `Replay a scanner issue's evidence request through Burp and judge whether the vulnerable behavior still reproduces, with checks per issue type ` +
+			`(SQL errors, unencoded XSS reflection, open redirects, HSTS, framing, cookie flags). ` +
+			`Imported issues are tagged with the verdict, so stale findings show in burp_get_scanner_issues. ` +
+			`Returns {issue, verdict: reproduced|stale|inconclusive, reason, statusCode, previousVerdict, tagged, note}.`,
+	}, verifyIssueHandler(client, st))
+}
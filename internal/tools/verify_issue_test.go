@@ -0,0 +1,82 @@
+package tools
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/c0tton-fluff/burp-mcp-server/internal/burp"
+	"github.com/c0tton-fluff/burp-mcp-server/internal/store"
+)
+
+func TestVerifyReplay(t *testing.T) {
+	resp := func(raw string) *burp.ParsedHTTPResponse { return burp.ParseHTTPResponse(raw, 0, 0) }
+	xssPoint := []InsertionPoint{{Name: "name", In: "query", Value: "%3Cscript%3Ealert(1)%3C%2Fscript%3E"}}
+	redirectPoint := []InsertionPoint{{Name: "next", In: "query", Value: "https%3A%2F%2Fevil.example%2F"}}
+	tests := []struct {
+		name   string
+		replay issueReplay
+		want   string
+	}{
+		{"SQL injection", issueReplay{resp: resp("HTTP/1.1 500 Error\r\n\r\nYou have an error in your SQL syntax near ''")}, store.IssueReproduced},
+		{"SQL injection", issueReplay{
+			original: resp("HTTP/1.1 500 Error\r\n\r\nORA-00933: SQL command not properly ended"),
+			resp:     resp("HTTP/1.1 200 OK\r\n\r\nno results"),
+		}, store.IssueStale},
+		{"SQL injection", issueReplay{resp: resp("HTTP/1.1 200 OK\r\n\r\nno results")}, store.IssueInconclusive},
+		{"Cross-site scripting (reflected)", issueReplay{points: xssPoint, resp: resp("HTTP/1.1 200 OK\r\n\r\nHello <script>alert(1)</script>")}, store.IssueReproduced},
+		{"Cross-site scripting (reflected)", issueReplay{points: xssPoint, resp: resp("HTTP/1.1 200 OK\r\n\r\nHello &lt;script&gt;")}, store.IssueStale},
+		{"Cross-site scripting (reflected)", issueReplay{resp: resp("HTTP/1.1 200 OK\r\n\r\n")}, store.IssueInconclusive},
+		{"Open redirection (reflected)", issueReplay{points: redirectPoint, resp: resp("HTTP/1.1 302 Found\r\nLocation: https://evil.example/\r\n\r\n")}, store.IssueReproduced},
+		{"Open redirection (reflected)", issueReplay{points: redirectPoint, resp: resp("HTTP/1.1 302 Found\r\nLocation: /home\r\n\r\n")}, store.IssueStale},
+		{"Strict transport security not enforced", issueReplay{resp: resp("HTTP/1.1 200 OK\r\n\r\n")}, store.IssueReproduced},
+		{"Strict transport security not enforced", issueReplay{resp: resp("HTTP/1.1 200 OK\r\nStrict-Transport-Security: max-age=31536000\r\n\r\n")}, store.IssueStale},
+		{"Frameable response (potential Clickjacking)", issueReplay{resp: resp("HTTP/1.1 200 OK\r\nContent-Security-Policy: default-src 'self'\r\n\r\n")}, store.IssueReproduced},
+		{"Frameable response (potential Clickjacking)", issueReplay{resp: resp("HTTP/1.1 200 OK\r\nContent-Security-Policy: frame-ancestors 'none'\r\n\r\n")}, store.IssueStale},
+		{"Cookie without HttpOnly flag set", issueReplay{resp: resp("HTTP/1.1 200 OK\r\nSet-Cookie: a=1; HttpOnly\r\nSet-Cookie: b=2\r\n\r\n")}, store.IssueReproduced},
+		{"Cookie without HttpOnly flag set", issueReplay{resp: resp("HTTP/1.1 200 OK\r\nSet-Cookie: a=1; HttpOnly\r\n\r\n")}, store.IssueStale},
+		{"TLS cookie without secure flag set", issueReplay{resp: resp("HTTP/1.1 200 OK\r\n\r\n")}, store.IssueInconclusive},
+		{"Email addresses disclosed", issueReplay{original: resp("HTTP/1.1 200 OK\r\n\r\n"), resp: resp("HTTP/1.1 404 Not Found\r\n\r\n")}, store.IssueInconclusive},
+	}
+	for _, tt := range tests {
+		verdict, reason := verifyReplay(tt.name, tt.replay)
+		if verdict != tt.want || reason == "" {
+			t.Errorf("%s: verdict %q (%s), want %q", tt.name, verdict, reason, tt.want)
+		}
+	}
+}
+
+func TestVerifyIssue_NoEvidence(t *testing.T) {
+	st, err := store.Open(filepath.Join(t.TempDir(), "store.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := st.ImportIssues([]store.Issue{{SerialNumber: "7", Name: "Strict transport security not enforced", Host: "https://shop.example", Path: "/"}}); err != nil {
+		t.Fatal(err)
+	}
+	_, _, err = verifyIssueHandler(nil, st)(context.Background(), nil, VerifyIssueInput{IssueID: "7"})
+	if err == nil || !strings.Contains(err.Error(), "no evidence request") {
+		t.Errorf("err = %v", err)
+	}
+}
+
+func TestScannerIssue_Verdict(t *testing.T) {
+	st, err := store.Open(filepath.Join(t.TempDir(), "store.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := st.ImportIssues([]store.Issue{{Name: "SQL injection", Host: "https://shop.example"}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := st.SetIssueVerification(0, store.IssueVerification{Verdict: store.IssueStale}); err != nil {
+		t.Fatal(err)
+	}
+	if err := st.SetIssueVerification(1, store.IssueVerification{Verdict: store.IssueStale}); err == nil {
+		t.Error("expected error for a missing issue")
+	}
+	_, out, err := getScannerIssuesHandler(nil, st)(context.Background(), nil, GetScannerIssuesInput{Source: "imported"})
+	if err != nil || out.Count != 1 || out.Issues[0].Verdict != store.IssueStale {
+		t.Errorf("out = %+v, %v", out, err)
+	}
+}
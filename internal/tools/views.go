@@ -0,0 +1,189 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/c0tton-fluff/burp-mcp-server/internal/burp"
+	"github.com/c0tton-fluff/burp-mcp-server/internal/store"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const (
+	// maxViewScan bounds how many history entries one call examines when
+	// filtering by a view; callers continue from nextOffset.
+	maxViewScan = 500
+	// viewScanBatch is how many entries are fetched per round of a scan.
+	viewScanBatch = 25
+)
+
+// historyView is a compiled view filter.
+type historyView struct {
+	host        string
+	methods     []string
+	url         *regexp.Regexp
+	statusCodes []int
+}
+
+// compileView checks f and prepares it for matching.
+func compileView(f store.ViewFilter) (*historyView, error) {
+	v := &historyView{host: f.Host, statusCodes: f.StatusCodes}
+	for _, m := range f.Methods {
+		v.methods = append(v.methods, strings.ToUpper(m))
+	}
+	if f.URLRegex != "" {
+		re, err := regexp.Compile(f.URLRegex)
+		if err != nil {
+			return nil, fmt.Errorf("invalid urlRegex: %w", err)
+		}
+		v.url = re
+	}
+	return v, nil
+}
+
+// loadView looks up and compiles a saved view.
+func loadView(st *store.Store, name string) (*historyView, error) {
+	saved, err := st.View(name)
+	if err != nil {
+		return nil, err
+	}
+	return compileView(saved.Filter)
+}
+
+func (v *historyView) matches(method, rawURL string, status int) bool {
+	return hostMatches(rawURL, v.host) &&
+		(len(v.methods) == 0 || slices.Contains(v.methods, strings.ToUpper(method))) &&
+		(v.url == nil || v.url.MatchString(rawURL)) &&
+		(len(v.statusCodes) == 0 || slices.Contains(v.statusCodes, status))
+}
+
+// scanHistoryView returns up to count entries matching v, scanning forward
+// from offset through at most maxViewScan entries.
+func scanHistoryView(ctx context.Context, client *burp.Client, v *historyView, offset, count int) (GetProxyHistoryOutput, error) {
+	out := GetProxyHistoryOutput{Entries: []ProxyHistorySummary{}}
+	for out.Scanned < maxViewScan && len(out.Entries) < count {
+		n := min(viewScanBatch, maxViewScan-out.Scanned)
+		batch, err := fetchHistorySummaries(ctx, client, offset+out.Scanned, n)
+		if len(batch) == 0 && err != nil {
+			return GetProxyHistoryOutput{}, err
+		}
+		for _, e := range batch {
+			out.Scanned++
+			if v.matches(e.Method, e.URL, e.StatusCode) {
+				out.Entries = append(out.Entries, e)
+				if len(out.Entries) == count {
+					break
+				}
+			}
+		}
+		if len(batch) < n {
+			out.Count = len(out.Entries)
+			return out, nil // end of history
+		}
+	}
+	out.Count = len(out.Entries)
+	out.NextOffset = offset + out.Scanned
+	return out, nil
+}
+
+// newestViewIndexes returns the indexes (1-based) of the newest entries
+// matching v, at most limit of them, looking back at most maxViewScan entries.
+func newestViewIndexes(ctx context.Context, client *burp.Client, v *historyView, limit int) ([]int, error) {
+	total, err := historyCollection.count(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+	var indexes []int
+	for end := total; end > 0 && end > total-maxViewScan && len(indexes) < limit; end -= viewScanBatch {
+		start := max(0, end-viewScanBatch, total-maxViewScan)
+		batch, err := fetchHistorySummaries(ctx, client, start, end-start)
+		if len(batch) == 0 && err != nil {
+			return nil, err
+		}
+		for i := len(batch) - 1; i >= 0 && len(indexes) < limit; i-- {
+			if e := batch[i]; v.matches(e.Method, e.URL, e.StatusCode) {
+				indexes = append(indexes, e.ID)
+			}
+		}
+	}
+	return indexes, nil
+}
+
+// SaveViewInput is the input for burp_save_view.
+type SaveViewInput struct {
+	Name        string   `json:"name" jsonschema:"View name, e.g. api-errors; saving an existing name replaces it"`
+	Description string   `json:"description,omitempty" jsonschema:"What the view is for"`
+	Host        string   `json:"host,omitempty" jsonschema:"Only entries for this host"`
+	Methods     []string `json:"methods,omitempty" jsonschema:"Only these methods, e.g. POST, PUT"`
+	URLRegex    string   `json:"urlRegex,omitempty" jsonschema:"Regex (RE2) the full URL must match, e.g. /api/v[0-9]+/"`
+	StatusCodes []int    `json:"statusCodes,omitempty" jsonschema:"Only entries with these response status codes"`
+	Delete      bool     `json:"delete,omitempty" jsonschema:"Delete the named view instead of saving it"`
+}
+
+// SaveViewOutput is the output of burp_save_view.
+type SaveViewOutput struct {
+	View    *store.View `json:"view,omitempty"`
+	Deleted bool        `json:"deleted,omitempty"`
+}
+
+func saveViewHandler(st *store.Store) func(context.Context, *mcp.CallToolRequest, SaveViewInput) (*mcp.CallToolResult, SaveViewOutput, error) {
+	return func(_ context.Context, _ *mcp.CallToolRequest, input SaveViewInput) (*mcp.CallToolResult, SaveViewOutput, error) {
+		name := strings.TrimSpace(input.Name)
+		if name == "" {
+			return nil, SaveViewOutput{}, fmt.Errorf("name is required")
+		}
+		if input.Delete {
+			if err := st.DeleteView(name); err != nil {
+				return nil, SaveViewOutput{}, err
+			}
+			return nil, SaveViewOutput{Deleted: true}, nil
+		}
+		f := store.ViewFilter{Host: input.Host, Methods: input.Methods, URLRegex: input.URLRegex, StatusCodes: input.StatusCodes}
+		if f.Host == "" && len(f.Methods) == 0 && f.URLRegex == "" && len(f.StatusCodes) == 0 {
+			return nil, SaveViewOutput{}, fmt.Errorf("set at least one of host, methods, urlRegex, or statusCodes")
+		}
+		if _, err := compileView(f); err != nil {
+			return nil, SaveViewOutput{}, err
+		}
+		saved, err := st.SaveView(store.View{Name: name, Description: input.Description, Filter: f})
+		if err != nil {
+			return nil, SaveViewOutput{}, err
+		}
+		return nil, SaveViewOutput{View: &saved}, nil
+	}
+}
+
+// ListViewsInput is the input for burp_list_views.
+type ListViewsInput struct{}
+
+// ListViewsOutput is the output of burp_list_views.
+type ListViewsOutput struct {
+	Views []store.View `json:"views"`
+}
+
+func listViewsHandler(st *store.Store) func(context.Context, *mcp.CallToolRequest, ListViewsInput) (*mcp.CallToolResult, ListViewsOutput, error) {
+	return func(context.Context, *mcp.CallToolRequest, ListViewsInput) (*mcp.CallToolResult, ListViewsOutput, error) {
+		return nil, ListViewsOutput{Views: st.Views()}, nil
+	}
+}
+
+// RegisterSaveViewTool registers the burp_save_view tool.
+func RegisterSaveViewTool(server *mcp.Server, st *store.Store) {
+	addTool(server, &mcp.Tool{
+		Name: "burp_save_view",
+		Description: `Save a named filter over proxy history (host, methods, URL regex, status codes) in the local store, or delete one. ` +
+			`Pass the name as view to burp_get_proxy_history, burp_grep_responses, or burp_watch_issues with source=history instead of repeating the filter. ` +
+			`Returns {view: {name, description, filter, updatedAt}, deleted}.`,
+	}, saveViewHandler(st))
+}
+
+// RegisterListViewsTool registers the burp_list_views tool.
+func RegisterListViewsTool(server *mcp.Server, st *store.Store) {
+	addTool(server, &mcp.Tool{
+		Name:        "burp_list_views",
+		Description: `List saved proxy history views. Returns {views: [{name, description, filter: {host, methods, urlRegex, statusCodes}, updatedAt}]}.`,
+	}, listViewsHandler(st))
+}
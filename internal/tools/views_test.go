@@ -0,0 +1,89 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/c0tton-fluff/burp-mcp-server/internal/store"
+)
+
+func historyEntryJSON(method, host, path string, status int) string {
+	data, _ := json.Marshal(map[string]string{
+		"request":  fmt.Sprintf("%s %s HTTP/1.1\r\nHost: %s\r\n\r\n", method, path, host),
+		"response": fmt.Sprintf("HTTP/1.1 %d X\r\nContent-Length: 9\r\n\r\ntoken=%03d", status, status),
+	})
+	return string(data)
+}
+
+func TestViews(t *testing.T) {
+	st, err := store.Open(filepath.Join(t.TempDir(), "store.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := &fakeBurp{}
+	for i := range 60 {
+		status := 200
+		if i%10 == 3 {
+			status = 500
+		}
+		f.add(historyEntryJSON("GET", "a.test", fmt.Sprintf("/api/items/%d", i), status), "")
+		f.add(historyEntryJSON("POST", "b.test", "/api/login", 500), "")
+	}
+	client := startFakeBurp(t, f)
+	ctx := context.Background()
+
+	save := saveViewHandler(st)
+	if _, _, err := save(ctx, nil, SaveViewInput{Name: "empty"}); err == nil {
+		t.Error("view without a filter was saved")
+	}
+	if _, _, err := save(ctx, nil, SaveViewInput{Name: "bad", URLRegex: "("}); err == nil {
+		t.Error("view with a bad regex was saved")
+	}
+	_, saved, err := save(ctx, nil, SaveViewInput{Name: "a-errors", Host: "a.test", Methods: []string{"get"}, URLRegex: "/api/", StatusCodes: []int{500}})
+	if err != nil || saved.View == nil || saved.View.Name != "a-errors" {
+		t.Fatalf("save = %+v, %v", saved, err)
+	}
+
+	history := getProxyHistoryHandler(client, st)
+	_, out, err := history(ctx, nil, GetProxyHistoryInput{View: "A-Errors", Count: 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out.Count != 4 || out.Entries[0].ID != 7 || out.Entries[3].ID != 67 || out.Scanned != 67 || out.NextOffset != 67 {
+		t.Errorf("first page = %+v", out)
+	}
+	_, out, err = history(ctx, nil, GetProxyHistoryInput{View: "a-errors", Offset: out.NextOffset, Count: 4})
+	if err != nil || out.Count != 2 || out.Entries[1].ID != 107 || out.NextOffset != 0 {
+		t.Errorf("last page = %+v, %v", out, err)
+	}
+	if _, _, err := history(ctx, nil, GetProxyHistoryInput{View: "missing"}); err == nil {
+		t.Error("unknown view accepted")
+	}
+
+	grep := grepResponsesHandler(client, st)
+	_, g, err := grep(ctx, nil, GrepResponsesInput{Regex: `token=(?P<status>\d+)`, View: "a-errors"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(g.Records) != 6 || g.Records[0].Source != "history 107" || len(g.Values["status"]) != 1 || g.Values["status"][0] != "500" {
+		t.Errorf("grep = %+v", g)
+	}
+
+	view, _ := loadView(st, "a-errors")
+	wf := watchFilter{collection: historyCollection, view: view}
+	if !wf.matches(ResourceItem{Method: "GET", URL: "https://a.test/api/x", StatusCode: 500}) ||
+		wf.matches(ResourceItem{Method: "POST", URL: "https://a.test/api/x", StatusCode: 500}) {
+		t.Error("watch filter does not apply the view")
+	}
+
+	_, listed, _ := listViewsHandler(st)(ctx, nil, ListViewsInput{})
+	if len(listed.Views) != 1 {
+		t.Errorf("views = %+v", listed.Views)
+	}
+	if _, out, err := save(ctx, nil, SaveViewInput{Name: "a-errors", Delete: true}); err != nil || !out.Deleted {
+		t.Errorf("delete = %+v, %v", out, err)
+	}
+}
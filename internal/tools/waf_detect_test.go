@@ -0,0 +1,68 @@
+package tools
+
+import (
+	"testing"
+
+	"github.com/c0tton-fluff/burp-mcp-server/internal/burp"
+)
+
+func TestWAFBlocked(t *testing.T) {
+	for _, tc := range []struct {
+		resp     string
+		blocked  bool
+		evidence string
+	}{
+		{"HTTP/1.1 403 Forbidden\r\n\r\n<title>Attention Required! | Cloudflare</title>", true, "Cloudflare block page: Attention Required! | Cloudflare"},
+		{"HTTP/1.1 406 Not Acceptable\r\n\r\nnope", true, "status 406 (benign 200)"},
+		{"HTTP/1.1 200 OK\r\n\r\nThe requested URL was rejected. Please consult with your administrator.", true, "F5 BIG-IP ASM block page: The requested URL was rejected. Please consult with your administrator"},
+		{"HTTP/1.1 500 Internal Server Error\r\n\r\nsyntax error", false, ""},
+		{"HTTP/1.1 200 OK\r\n\r\nresults", false, ""},
+	} {
+		blocked, evidence := wafBlocked(burp.ParseHTTPResponse(tc.resp, 0, 0), 200)
+		if blocked != tc.blocked || evidence != tc.evidence {
+			t.Errorf("%q: got %v %q, want %v %q", tc.resp, blocked, evidence, tc.blocked, tc.evidence)
+		}
+	}
+	// A block status the benign probe also got is not a block.
+	if blocked, _ := wafBlocked(burp.ParseHTTPResponse("HTTP/1.1 403 Forbidden\r\n\r\n", 0, 0), 403); blocked {
+		t.Error("403 against a 403 baseline reported as blocked")
+	}
+}
+
+func TestWAFVendors(t *testing.T) {
+	responses := make([]*burp.ParsedHTTPResponse, len(wafProbes))
+	responses[0] = burp.ParseHTTPResponse("HTTP/1.1 200 OK\r\nSet-Cookie: incap_ses_123=abc; path=/\r\n\r\nok", 0, 0)
+	responses[1] = burp.ParseHTTPResponse("HTTP/1.1 403 Forbidden\r\n\r\nRequest unsuccessful. Incapsula incident ID: 42", 0, 0)
+	vendors := wafVendors(responses)
+	if len(vendors) != 1 || vendors[0].Name != "Imperva Incapsula" || vendors[0].Confidence != confCertain || len(vendors[0].Evidence) != 2 {
+		t.Errorf("vendors = %+v", vendors)
+	}
+}
+
+func TestWAFEvasions(t *testing.T) {
+	results := make([]WAFProbeResult, len(wafProbes))
+	for i, p := range wafProbes {
+		results[i] = WAFProbeResult{Name: p.name, Payload: p.value}
+		results[i].Blocked = p.class == "sqli" || p.class == "scanner"
+	}
+	got := map[string]string{}
+	for _, e := range wafEvasions(results) {
+		if e.Payload != "' OR 1=1-- -" {
+			t.Errorf("%s applied to %q", e.Encoding, e.Payload)
+		}
+		got[e.Encoding] = e.Example
+	}
+	for enc, want := range map[string]string{
+		"case-variation": "' Or 1=1-- -",
+		"sql-comment":    "'/**/OR/**/1=1--/**/-",
+		"double-url":     "%2527%2520OR%25201%253D1%252D%252D%2520%252D",
+		"unicode-escape": `\u0027\u0020OR\u00201\u003d1\u002d\u002d\u0020\u002d`,
+	} {
+		if got[enc] != want {
+			t.Errorf("%s = %q, want %q", enc, got[enc], want)
+		}
+	}
+	if _, ok := got["html-entity"]; ok {
+		t.Error("html-entity recommended with no XSS probe blocked")
+	}
+}
@@ -0,0 +1,288 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/c0tton-fluff/burp-mcp-server/internal/burp"
+	"github.com/c0tton-fluff/burp-mcp-server/internal/store"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const (
+	defaultWatchInterval = 30
+	minWatchInterval     = 5
+	maxWatchInterval     = 3600
+	// watchLogger names the log messages a watch sends.
+	watchLogger = "burp-watch"
+)
+
+// watchTick is the unit of intervalSeconds; tests shorten it.
+var watchTick = time.Second
+
+// severityRank orders Burp's severities, with nuclei's critical on top.
+var severityRank = map[string]int{"information": 0, "info": 0, "low": 1, "medium": 2, "high": 3, "critical": 4}
+
+// WatchIssuesInput is the input for burp_watch_issues.
+type WatchIssuesInput struct {
+	Source          string `json:"source,omitempty" jsonschema:"What to watch: issues (default, Burp's scanner issues) or history (proxy history)"`
+	MinSeverity     string `json:"minSeverity,omitempty" jsonschema:"Issues only: lowest severity to report: information, low, medium, high, or critical"`
+	Host            string `json:"host,omitempty" jsonschema:"Only items for this host"`
+	Match           string `json:"match,omitempty" jsonschema:"Regex (RE2) an item must match: issue name and URL, or history method and URL"`
+	StatusCodes     []int  `json:"statusCodes,omitempty" jsonschema:"History only: report entries with these response status codes"`
+	View            string `json:"view,omitempty" jsonschema:"History only: report entries matching this saved view (burp_save_view), on top of the other filters"`
+	IntervalSeconds int    `json:"intervalSeconds,omitempty" jsonschema:"How often to check Burp (default 30, min 5, max 3600)"`
+	Instance        string `json:"instance,omitempty" jsonschema:"Named Burp instance from config (default: the --burp-url connection)"`
+}
+
+// WatchIssuesOutput is the output of burp_watch_issues.
+type WatchIssuesOutput struct {
+	WatchID  string `json:"watchId"`
+	Baseline int    `json:"baseline"` // items already present, which are not reported
+	Note     string `json:"note"`
+}
+
+// UnwatchInput is the input for burp_unwatch.
+type UnwatchInput struct {
+	WatchID string `json:"watchId" jsonschema:"Watch ID returned by burp_watch_issues"`
+}
+
+// UnwatchOutput is the output of burp_unwatch.
+type UnwatchOutput struct {
+	WatchID   string `json:"watchId"`
+	Reported  int    `json:"reported"` // matching items sent to the client
+	LastError string `json:"lastError,omitempty"`
+}
+
+// watchFilter selects which new items a watch reports.
+type watchFilter struct {
+	collection  collection
+	minSeverity int
+	host        string
+	match       *regexp.Regexp
+	statusCodes []int
+	view        *historyView
+}
+
+func (f watchFilter) matches(item ResourceItem) bool {
+	if !hostMatches(item.URL, f.host) {
+		return false
+	}
+	text := item.Method + " " + item.URL
+	if f.collection.uri == issuesURI {
+		if severityRank[strings.ToLower(item.Severity)] < f.minSeverity {
+			return false
+		}
+		text = item.Name + " " + item.URL
+	}
+	if f.match != nil && !f.match.MatchString(text) {
+		return false
+	}
+	if f.view != nil && !f.view.matches(item.Method, item.URL, item.StatusCode) {
+		return false
+	}
+	return len(f.statusCodes) == 0 || slices.Contains(f.statusCodes, item.StatusCode)
+}
+
+// watch is one running watch.
+type watch struct {
+	id     string
+	cancel context.CancelFunc
+
+	mu       sync.Mutex
+	reported int
+	lastErr  error
+}
+
+// watchSet holds the running watches. It is safe for concurrent use.
+type watchSet struct {
+	mu      sync.Mutex
+	nextID  int
+	watches map[string]*watch
+}
+
+var watches = &watchSet{watches: make(map[string]*watch)}
+
+// start polls every interval from known on and logs matching new items to
+// session until stopped or the session ends.
+func (s *watchSet) start(ctx context.Context, session *mcp.ServerSession, client *burp.Client, f watchFilter, known int, interval time.Duration) *watch {
+	ctx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	s.mu.Lock()
+	s.nextID++
+	w := &watch{id: fmt.Sprintf("W-%d", s.nextID), cancel: cancel}
+	s.watches[w.id] = w
+	s.mu.Unlock()
+
+	go func() {
+		session.Wait()
+		cancel()
+	}()
+	go func() {
+		defer s.remove(w.id)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			added := f.collection.newItems(ctx, client, known)
+			known += len(added)
+			for _, item := range added {
+				if f.matches(item) {
+					w.report(ctx, session, f, item)
+				}
+			}
+		}
+	}()
+	return w
+}
+
+func (s *watchSet) remove(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.watches, id)
+}
+
+func (s *watchSet) stop(id string) (*watch, error) {
+	s.mu.Lock()
+	w, ok := s.watches[id]
+	delete(s.watches, id)
+	s.mu.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("watch %q not found", id)
+	}
+	w.cancel()
+	return w, nil
+}
+
+// report sends item to the client as a log message; high and critical
+// issues are warnings so clients that filter by level still see them.
+func (w *watch) report(ctx context.Context, session *mcp.ServerSession, f watchFilter, item ResourceItem) {
+	level := mcp.LoggingLevel("info")
+	if f.collection.uri == issuesURI {
+		level = "notice"
+		if severityRank[strings.ToLower(item.Severity)] >= severityRank["high"] {
+			level = "warning"
+		}
+	}
+	err := session.Log(ctx, &mcp.LoggingMessageParams{
+		Logger: watchLogger,
+		Level:  level,
+		Data: map[string]any{
+			"watchId": w.id,
+			"item":    item,
+		},
+	})
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if err != nil {
+		w.lastErr = err
+		return
+	}
+	w.reported++
+}
+
+func watchIssuesHandler(client *burp.Client, st *store.Store) func(context.Context, *mcp.CallToolRequest, WatchIssuesInput) (*mcp.CallToolResult, WatchIssuesOutput, error) {
+	return func(ctx context.Context, req *mcp.CallToolRequest, input WatchIssuesInput) (*mcp.CallToolResult, WatchIssuesOutput, error) {
+		ctx = burp.WithInstance(ctx, input.Instance)
+		if req == nil || req.Session == nil {
+			return nil, WatchIssuesOutput{}, fmt.Errorf("burp_watch_issues needs a client session to notify")
+		}
+
+		f := watchFilter{host: input.Host, statusCodes: input.StatusCodes}
+		switch input.Source {
+		case "", "issues":
+			f.collection = issuesCollection
+			if len(input.StatusCodes) > 0 || input.View != "" {
+				return nil, WatchIssuesOutput{}, fmt.Errorf("statusCodes and view apply to source=history")
+			}
+		case "history":
+			f.collection = historyCollection
+			if input.MinSeverity != "" {
+				return nil, WatchIssuesOutput{}, fmt.Errorf("minSeverity applies to source=issues")
+			}
+		default:
+			return nil, WatchIssuesOutput{}, fmt.Errorf("source must be issues or history")
+		}
+		if input.MinSeverity != "" {
+			rank, ok := severityRank[strings.ToLower(input.MinSeverity)]
+			if !ok {
+				return nil, WatchIssuesOutput{}, fmt.Errorf("minSeverity must be information, low, medium, high, or critical")
+			}
+			f.minSeverity = rank
+		}
+		if input.View != "" {
+			view, err := loadView(st, input.View)
+			if err != nil {
+				return nil, WatchIssuesOutput{}, err
+			}
+			f.view = view
+		}
+		if input.Match != "" {
+			re, err := regexp.Compile(input.Match)
+			if err != nil {
+				return nil, WatchIssuesOutput{}, fmt.Errorf("invalid match regex: %w", err)
+			}
+			f.match = re
+		}
+		interval := input.IntervalSeconds
+		if interval == 0 {
+			interval = defaultWatchInterval
+		}
+		if interval < minWatchInterval || interval > maxWatchInterval {
+			return nil, WatchIssuesOutput{}, fmt.Errorf("intervalSeconds must be %d-%d", minWatchInterval, maxWatchInterval)
+		}
+
+		known, err := f.collection.count(ctx, client)
+		if err != nil {
+			return nil, WatchIssuesOutput{}, fmt.Errorf("count existing items: %w", err)
+		}
+		w := watches.start(ctx, req.Session, client, f, known, time.Duration(interval)*watchTick)
+		return nil, WatchIssuesOutput{
+			WatchID:  w.id,
+			Baseline: known,
+			Note:     fmt.Sprintf("new matching items arrive as %q log messages; the client must enable logging (logging/setLevel) to receive them", watchLogger),
+		}, nil
+	}
+}
+
+func unwatchHandler() func(context.Context, *mcp.CallToolRequest, UnwatchInput) (*mcp.CallToolResult, UnwatchOutput, error) {
+	return func(_ context.Context, _ *mcp.CallToolRequest, input UnwatchInput) (*mcp.CallToolResult, UnwatchOutput, error) {
+		w, err := watches.stop(input.WatchID)
+		if err != nil {
+			return nil, UnwatchOutput{}, err
+		}
+		w.mu.Lock()
+		defer w.mu.Unlock()
+		out := UnwatchOutput{WatchID: w.id, Reported: w.reported}
+		if w.lastErr != nil {
+			out.LastError = w.lastErr.Error()
+		}
+		return nil, out, nil
+	}
+}
+
+// RegisterWatchIssuesTool registers the burp_watch_issues tool.
+func RegisterWatchIssuesTool(server *mcp.Server, client *burp.Client, st *store.Store) {
+	addTool(server, &mcp.Tool{
+		Name: "burp_watch_issues",
+		Description: `Watch Burp for new scanner issues, or new proxy history with source=history, and push each match to this client as a log message (logger "burp-watch"; high and critical issues at warning level) until burp_unwatch or the session ends. ` +
+			`Items already present are the baseline and are not reported. Filter by minSeverity, host, a match regex, or for history statusCodes or a saved view. ` +
+			`Returns {watchId, baseline, note}.`,
+	}, watchIssuesHandler(client, st))
+}
+
+// RegisterUnwatchTool registers the burp_unwatch tool.
+func RegisterUnwatchTool(server *mcp.Server) {
+	addTool(server, &mcp.Tool{
+		Name:        "burp_unwatch",
+		Description: `Stop a watch started by burp_watch_issues. Returns {watchId, reported, lastError}.`,
+	}, unwatchHandler())
+}
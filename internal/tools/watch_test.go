@@ -0,0 +1,110 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestWatchIssues(t *testing.T) {
+	tick := watchTick
+	watchTick = time.Millisecond
+	t.Cleanup(func() { watchTick = tick })
+
+	f := &fakeBurp{}
+	f.add("", "Issue: Old finding\nSeverity: High\nURL: https://a.test/")
+	client := startFakeBurp(t, f)
+
+	server := mcp.NewServer(&mcp.Implementation{Name: "test"}, nil)
+	RegisterWatchIssuesTool(server, client, nil)
+	RegisterUnwatchTool(server)
+
+	logs := make(chan *mcp.LoggingMessageParams, 10)
+	mc := mcp.NewClient(&mcp.Implementation{Name: "client"}, &mcp.ClientOptions{
+		LoggingMessageHandler: func(_ context.Context, req *mcp.LoggingMessageRequest) {
+			logs <- req.Params
+		},
+	})
+	ct, st := mcp.NewInMemoryTransports()
+	ctx := context.Background()
+	ss, err := server.Connect(ctx, st, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ss.Close()
+	cs, err := mc.Connect(ctx, ct, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cs.Close()
+	if err := cs.SetLoggingLevel(ctx, &mcp.SetLoggingLevelParams{Level: "info"}); err != nil {
+		t.Fatal(err)
+	}
+
+	call := func(name string, args map[string]any, out any) *mcp.CallToolResult {
+		t.Helper()
+		res, err := cs.CallTool(ctx, &mcp.CallToolParams{Name: name, Arguments: args})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if out != nil && !res.IsError {
+			data, _ := json.Marshal(res.StructuredContent)
+			json.Unmarshal(data, out)
+		}
+		return res
+	}
+
+	for _, args := range []map[string]any{
+		{"source": "scans"},
+		{"minSeverity": "urgent"},
+		{"source": "history", "minSeverity": "high"},
+		{"statusCodes": []int{500}},
+		{"match": "("},
+		{"intervalSeconds": 1},
+	} {
+		if res := call("burp_watch_issues", args, nil); !res.IsError {
+			t.Errorf("%v: expected error", args)
+		}
+	}
+
+	var watched WatchIssuesOutput
+	call("burp_watch_issues", map[string]any{"minSeverity": "medium", "host": "a.test", "intervalSeconds": 5}, &watched)
+	if watched.WatchID == "" || watched.Baseline != 1 {
+		t.Fatalf("watch = %+v", watched)
+	}
+
+	f.add("", "Issue: Low thing\nSeverity: Low\nURL: https://a.test/x")
+	f.add("", "Issue: Other host\nSeverity: High\nURL: https://b.test/")
+	f.add("", "Issue: SQL injection\nSeverity: High\nURL: https://a.test/search")
+	select {
+	case msg := <-logs:
+		data, _ := json.Marshal(msg.Data)
+		var got struct {
+			WatchID string       `json:"watchId"`
+			Item    ResourceItem `json:"item"`
+		}
+		json.Unmarshal(data, &got)
+		if msg.Logger != watchLogger || msg.Level != "warning" || got.WatchID != watched.WatchID || got.Item.Name != "SQL injection" || got.Item.URI != "burp://issues/4" {
+			t.Errorf("log = %+v %s", msg, data)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("no notification")
+	}
+
+	var stopped UnwatchOutput
+	call("burp_unwatch", map[string]any{"watchId": watched.WatchID}, &stopped)
+	if stopped.Reported != 1 || stopped.LastError != "" {
+		t.Errorf("unwatch = %+v", stopped)
+	}
+	if res := call("burp_unwatch", map[string]any{"watchId": watched.WatchID}, nil); !res.IsError {
+		t.Error("expected error for a stopped watch")
+	}
+	select {
+	case msg := <-logs:
+		t.Errorf("unexpected log %+v", msg)
+	default:
+	}
+}
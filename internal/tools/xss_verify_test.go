@@ -0,0 +1,98 @@
+package tools
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestXSSContext(t *testing.T) {
+	tests := []struct {
+		page, ctx, tag, attr string
+	}{
+		{`<p>Hello X</p>`, "html", "", ""},
+		{`<p>a > b and X`, "html", "", ""},
+		{`<!-- note X -->`, "html-comment", "", ""},
+		{`<input value="X">`, "attribute-double", "input", "value"},
+		{`<input value='X'>`, "attribute-single", "input", "value"},
+		{`<input value=X>`, "attribute-unquoted", "input", "value"},
+		{`<input type="text" X>`, "tag", "input", ""},
+		{`<a class="x" href="X">`, "url", "a", "href"},
+		{`<a href="/search?q=X">`, "attribute-double", "a", "href"},
+		{`<div onclick="go('X')">`, "event-handler", "div", "onclick"},
+		{`<div style="color:X">`, "css", "div", "style"},
+		{`<textarea>X</textarea>`, "rcdata", "textarea", ""},
+		{`<style>body{X}</style>`, "css", "style", ""},
+		{`<script>var q = 'X';</script>`, "js-string-single", "script", ""},
+		{`<script>var q = "it's X";</script>`, "js-string-double", "script", ""},
+		{"<script>var q = `${a} X`;</script>", "js-template", "script", ""},
+		{`<script>var q = "a\"b"; f(X)</script>`, "js", "script", ""},
+		{`<script>// X</script>`, "js-comment", "script", ""},
+		{`<script>var a = "<p>";</script><b>X</b>`, "html", "", ""},
+	}
+	for _, tt := range tests {
+		ctx, tag, attr := xssContext(tt.page, strings.Index(tt.page, "X"))
+		if ctx != tt.ctx || tag != tt.tag || attr != tt.attr {
+			t.Errorf("%s: got (%s, %s, %s), want (%s, %s, %s)", tt.page, ctx, tag, attr, tt.ctx, tt.tag, tt.attr)
+		}
+	}
+}
+
+func TestXSSReflections(t *testing.T) {
+	start, end := xssCanaries("abcd1234", 1)
+	payload := `'"><img src=x onerror=alert(1)>`
+	body := `<input value="` + start + `&#39;&quot;&gt;&lt;img src=x onerror=alert(1)&gt;` + end + `">` +
+		`<p>` + start + payload + end + `</p>`
+
+	got := xssReflections(body, payload, start, end, true)
+	if len(got) != 2 {
+		t.Fatalf("got %+v", got)
+	}
+	attr, html := got[0], got[1]
+	if attr.Context != "attribute-double" || attr.Verdict != "encoded" || !slices.Contains(attr.Encoded, `"`) || !slices.Contains(attr.Survived, "(") {
+		t.Errorf("attribute reflection = %+v", attr)
+	}
+	if html.Context != "html" || html.Verdict != "executes" || len(html.Encoded) != 0 {
+		t.Errorf("html reflection = %+v", html)
+	}
+
+	// The metacharacter probe never executes, and a missing end canary
+	// marks the reflection partial.
+	probe := xssReflections(`<p>`+start+xssMetaProbe[:4], xssMetaProbe, start, end, false)
+	if len(probe) != 1 || !probe[0].Partial || probe[0].Verdict != "breakout" {
+		t.Errorf("probe = %+v", probe)
+	}
+}
+
+func TestXSSVerdicts(t *testing.T) {
+	r := XSSReflection{Context: "js-string-single", Reflected: `'-alert(1)-'`}
+	if got := xssVerdict(r, `'-alert(1)-'`, true); got != "executes" {
+		t.Errorf("intact breakout = %s", got)
+	}
+	if got := xssVerdict(r, `<svg onload=alert(1)>`, true); got != "wrong-context" {
+		t.Errorf("markup in JS string = %s", got)
+	}
+	r.Reflected = `\'-alert(1)-\'`
+	if got := xssVerdict(r, `'-alert(1)-'`, true); got != "breakout" {
+		t.Errorf("escaped quote = %s", got)
+	}
+
+	c := XSSCheck{ContentType: "application/json", Reflections: []XSSReflection{{Verdict: "executes"}}}
+	if got := xssCheckVerdict(c); got != "not-rendered" {
+		t.Errorf("JSON response = %s", got)
+	}
+	c.ContentType = "text/html; charset=utf-8"
+	c.Reflections = append(c.Reflections, XSSReflection{Verdict: "encoded"})
+	if got := xssCheckVerdict(c); got != "executes" {
+		t.Errorf("HTML response = %s", got)
+	}
+	if got := xssCheckVerdict(XSSCheck{}); got != "not-reflected" {
+		t.Errorf("no reflections = %s", got)
+	}
+
+	c.Payload, c.Verdict, c.Reflections[0].Context = "<svg onload=alert(1)>", "executes", "html"
+	findings := xssFindings([]XSSCheck{c, {Payload: "x", Verdict: "breakout", Reflections: []XSSReflection{{Context: "rcdata", Tag: "title", Verdict: "breakout", Survived: []string{"<", "/"}}}}})
+	if len(findings) != 2 || findings[0] != "<svg onload=alert(1)> executes in html context" || !strings.Contains(findings[1], "rcdata context (<title>)") {
+		t.Errorf("findings = %q", findings)
+	}
+}
@@ -0,0 +1,576 @@
+// Package yaml reads the YAML subset the server's inputs use, into the
+// values encoding/json would produce.
+package yaml
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// The reader covers what API specs and rule files use: block mappings and sequences,
+// flow collections, plain, quoted, and block scalars, and comments. Anchors,
+// aliases, tags, and complex keys are refused rather than misread.
+
+type yamlLine struct {
+	num    int    // 1-based line number
+	indent int    // leading spaces
+	text   string // content without indentation or comment; "" when blank
+	raw    string // the line as written, for block scalars
+}
+
+type yamlParser struct {
+	lines []yamlLine
+	i     int
+}
+
+// Decode parses a YAML document into the values encoding/json would
+// produce, with numbers as json.Number.
+func Decode(src string) (any, error) {
+	p := &yamlParser{}
+	for n, raw := range strings.Split(strings.ReplaceAll(src, "\r\n", "\n"), "\n") {
+		if strings.HasPrefix(raw, "---") && strings.TrimSpace(raw[3:]) == "" {
+			if len(p.lines) > 0 {
+				break // only the first document
+			}
+			continue
+		}
+		if strings.HasPrefix(raw, "%") {
+			continue // directives
+		}
+		if strings.Contains(raw, "\t") && strings.TrimLeft(raw, "\t") != raw {
+			return nil, fmt.Errorf("yaml line %d: tabs can't indent", n+1)
+		}
+		trimmed := strings.TrimLeft(raw, " ")
+		p.lines = append(p.lines, yamlLine{num: n + 1, indent: len(raw) - len(trimmed), text: stripYAMLComment(trimmed), raw: raw})
+	}
+	p.skipBlank()
+	if p.i >= len(p.lines) {
+		return nil, fmt.Errorf("yaml: empty document")
+	}
+	v, err := p.node(0)
+	if err != nil {
+		return nil, err
+	}
+	if p.skipBlank(); p.i < len(p.lines) {
+		return nil, p.errorf("unexpected content at indent %d", p.lines[p.i].indent)
+	}
+	return v, nil
+}
+
+// stripYAMLComment drops a " #" comment and trailing space, leaving quoted
+// text alone.
+func stripYAMLComment(s string) string {
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote == '\'' && c == '\'':
+			if i+1 < len(s) && s[i+1] == '\'' {
+				i++
+			} else {
+				quote = 0
+			}
+		case quote == '"' && c == '\\':
+			i++
+		case quote == '"' && c == '"':
+			quote = 0
+		case quote != 0:
+		case (c == '\'' || c == '"') && (i == 0 || strings.IndexByte(" [{,:", s[i-1]) >= 0):
+			quote = c
+		case c == '#' && (i == 0 || s[i-1] == ' '):
+			return strings.TrimRight(s[:i], " ")
+		}
+	}
+	return strings.TrimRight(s, " ")
+}
+
+func (p *yamlParser) errorf(format string, args ...any) error {
+	num := 0
+	if p.i < len(p.lines) {
+		num = p.lines[p.i].num
+	} else if len(p.lines) > 0 {
+		num = p.lines[len(p.lines)-1].num
+	}
+	return fmt.Errorf("yaml line %d: %s", num, fmt.Sprintf(format, args...))
+}
+
+func (p *yamlParser) skipBlank() {
+	for p.i < len(p.lines) && p.lines[p.i].text == "" {
+		p.i++
+	}
+}
+
+// node parses the block node starting at the current line, which is indented
+// at least minIndent.
+func (p *yamlParser) node(minIndent int) (any, error) {
+	p.skipBlank()
+	if p.i >= len(p.lines) || p.lines[p.i].indent < minIndent {
+		return nil, nil
+	}
+	l := p.lines[p.i]
+	switch {
+	case isSeqItem(l.text):
+		return p.sequence(l.indent)
+	case mapKeyEnd(l.text) >= 0:
+		return p.mapping(l.indent)
+	}
+	p.i++
+	return p.value(l.text, l.indent-1)
+}
+
+func isSeqItem(text string) bool {
+	return text == "-" || strings.HasPrefix(text, "- ")
+}
+
+// mapKeyEnd returns the index of the colon ending a mapping key in text,
+// or -1 when text isn't a "key: value" line.
+func mapKeyEnd(text string) int {
+	if text == "" || strings.IndexByte("[{|>&*!", text[0]) >= 0 || strings.HasPrefix(text, "? ") {
+		return -1
+	}
+	start := 0
+	if text[0] == '"' || text[0] == '\'' {
+		_, n, err := quotedScalar(text)
+		if err != nil {
+			return -1
+		}
+		start = n
+	}
+	for i := start; i < len(text); i++ {
+		if text[i] == ':' && (i+1 == len(text) || text[i+1] == ' ') {
+			return i
+		}
+	}
+	return -1
+}
+
+func (p *yamlParser) mapping(indent int) (any, error) {
+	m := map[string]any{}
+	for {
+		p.skipBlank()
+		if p.i >= len(p.lines) || p.lines[p.i].indent < indent {
+			return m, nil
+		}
+		l := p.lines[p.i]
+		if l.indent > indent {
+			return nil, p.errorf("bad indentation")
+		}
+		end := mapKeyEnd(l.text)
+		if end < 0 {
+			if isSeqItem(l.text) {
+				return m, nil // a sequence at the parent key's indent ends here
+			}
+			return nil, p.errorf("expected a key: %q", l.text)
+		}
+		key := strings.TrimSpace(l.text[:end])
+		if key != "" && (key[0] == '"' || key[0] == '\'') {
+			k, _, err := quotedScalar(key)
+			if err != nil {
+				return nil, p.errorf("%v", err)
+			}
+			key = k
+		}
+		if key == "<<" {
+			return nil, p.errorf("merge keys are not supported")
+		}
+		if _, dup := m[key]; dup {
+			return nil, p.errorf("duplicate key %q", key)
+		}
+		p.i++
+		rest := strings.TrimSpace(l.text[end+1:])
+		var v any
+		var err error
+		if rest == "" {
+			// The value is a nested block, or a sequence at this indent.
+			p.skipBlank()
+			if p.i < len(p.lines) && p.lines[p.i].indent == indent && isSeqItem(p.lines[p.i].text) {
+				v, err = p.sequence(indent)
+			} else {
+				v, err = p.node(indent + 1)
+			}
+		} else {
+			v, err = p.value(rest, indent)
+		}
+		if err != nil {
+			return nil, err
+		}
+		m[key] = v
+	}
+}
+
+func (p *yamlParser) sequence(indent int) (any, error) {
+	seq := []any{}
+	for {
+		p.skipBlank()
+		if p.i >= len(p.lines) || p.lines[p.i].indent != indent || !isSeqItem(p.lines[p.i].text) {
+			if p.i < len(p.lines) && p.lines[p.i].indent > indent {
+				return nil, p.errorf("bad indentation")
+			}
+			return seq, nil
+		}
+		l := p.lines[p.i]
+		rest := strings.TrimLeft(l.text[1:], " ")
+		if rest == "" {
+			p.i++
+			v, err := p.node(indent + 1)
+			if err != nil {
+				return nil, err
+			}
+			seq = append(seq, v)
+			continue
+		}
+		// "- key: v" and "- - v" open a block at the item's column: reparse
+		// the line as if the dash were indentation.
+		if mapKeyEnd(rest) >= 0 || isSeqItem(rest) {
+			p.lines[p.i].indent += len(l.text) - len(rest)
+			p.lines[p.i].text = rest
+			v, err := p.node(p.lines[p.i].indent)
+			if err != nil {
+				return nil, err
+			}
+			seq = append(seq, v)
+			continue
+		}
+		p.i++
+		v, err := p.value(rest, indent)
+		if err != nil {
+			return nil, err
+		}
+		seq = append(seq, v)
+	}
+}
+
+// value parses the inline value text of a key or item at parentIndent:
+// a block scalar header, a flow collection, or a scalar, each of which can
+// continue on more-indented lines.
+func (p *yamlParser) value(text string, parentIndent int) (any, error) {
+	switch text[0] {
+	case '&', '*', '!':
+		return nil, p.errorf("anchors, aliases, and tags are not supported")
+	case '|', '>':
+		return p.blockScalar(text, parentIndent)
+	case '[', '{':
+		// Flow collections may span lines; gather until brackets balance.
+		for !flowBalanced(text) && p.i < len(p.lines) {
+			text += " " + strings.TrimSpace(p.lines[p.i].text)
+			p.i++
+		}
+		f := &flowParser{src: text}
+		v, err := f.value()
+		if err != nil {
+			return nil, p.errorf("%v", err)
+		}
+		if f.skipSpace(); f.pos < len(f.src) {
+			return nil, p.errorf("unexpected %q after flow collection", f.src[f.pos:])
+		}
+		return v, nil
+	case '"', '\'':
+		// Quoted scalars may continue onto following lines.
+		for {
+			s, n, err := quotedScalar(text)
+			if err == nil {
+				if strings.TrimSpace(text[n:]) != "" {
+					return nil, p.errorf("unexpected %q after quoted scalar", text[n:])
+				}
+				return s, nil
+			}
+			if p.i >= len(p.lines) {
+				return nil, p.errorf("%v", err)
+			}
+			if next := strings.TrimSpace(p.lines[p.i].raw); next == "" {
+				text += "\n"
+			} else {
+				text += " " + next
+			}
+			p.i++
+		}
+	}
+	// A plain scalar folds in more-indented continuation lines.
+	for p.i < len(p.lines) {
+		l := p.lines[p.i]
+		if l.text == "" || l.indent <= parentIndent || mapKeyEnd(l.text) >= 0 || isSeqItem(l.text) {
+			break
+		}
+		text += " " + l.text
+		p.i++
+	}
+	return plainScalar(text), nil
+}
+
+// blockScalar reads a | or > scalar whose header is text.
+func (p *yamlParser) blockScalar(text string, parentIndent int) (any, error) {
+	folded := text[0] == '>'
+	chomp := byte(0)
+	indent := 0
+	for _, c := range []byte(text[1:]) {
+		switch {
+		case c == '-' || c == '+':
+			chomp = c
+		case c >= '1' && c <= '9':
+			indent = parentIndent + int(c-'0')
+		case c == ' ':
+		default:
+			return nil, p.errorf("bad block scalar header %q", text)
+		}
+	}
+	var lines []string
+	for p.i < len(p.lines) {
+		l := p.lines[p.i]
+		blank := strings.TrimSpace(l.raw) == ""
+		if !blank && l.indent <= parentIndent {
+			break
+		}
+		if indent == 0 && !blank {
+			indent = l.indent
+		}
+		if !blank && l.indent < indent {
+			break
+		}
+		if blank {
+			lines = append(lines, "")
+		} else {
+			lines = append(lines, l.raw[indent:])
+		}
+		p.i++
+	}
+	// Trailing blank lines belong to chomping, not content.
+	trailing := 0
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+		trailing++
+	}
+	var b strings.Builder
+	for i, line := range lines {
+		if i > 0 {
+			switch {
+			case !folded:
+				b.WriteByte('\n')
+			case line == "" || lines[i-1] == "" || strings.HasPrefix(line, " ") || strings.HasPrefix(lines[i-1], " "):
+				// Blank and more-indented lines keep their line breaks.
+				b.WriteByte('\n')
+			default:
+				b.WriteByte(' ')
+			}
+		}
+		b.WriteString(line)
+	}
+	s := b.String()
+	if folded {
+		// A blank line between folded lines is one line break, not two.
+		s = strings.ReplaceAll(s, "\n\n", "\n")
+	}
+	switch {
+	case len(lines) == 0:
+	case chomp == '-':
+	case chomp == '+':
+		s += "\n" + strings.Repeat("\n", trailing)
+	default:
+		s += "\n"
+	}
+	return s, nil
+}
+
+// flowBalanced reports whether text closes every bracket it opens, outside
+// quotes.
+func flowBalanced(text string) bool {
+	depth := 0
+	var quote byte
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		switch {
+		case quote != 0:
+			if c == '\\' && quote == '"' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '[' || c == '{':
+			depth++
+		case c == ']' || c == '}':
+			depth--
+		}
+	}
+	return depth <= 0
+}
+
+// quotedScalar decodes the quoted scalar at the start of s and returns it
+// with the number of bytes it spans.
+func quotedScalar(s string) (string, int, error) {
+	q := s[0]
+	var b strings.Builder
+	for i := 1; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case q == '\'' && c == '\'':
+			if i+1 < len(s) && s[i+1] == '\'' {
+				b.WriteByte('\'')
+				i++
+				continue
+			}
+			return foldQuoted(b.String()), i + 1, nil
+		case q == '"' && c == '"':
+			return foldQuoted(b.String()), i + 1, nil
+		case q == '"' && c == '\\' && i+1 < len(s):
+			i++
+			switch e := s[i]; e {
+			case 'n':
+				b.WriteByte('\n')
+			case 't':
+				b.WriteByte('\t')
+			case 'r':
+				b.WriteByte('\r')
+			case '0':
+				b.WriteByte(0)
+			case 'x', 'u', 'U':
+				n := map[byte]int{'x': 2, 'u': 4, 'U': 8}[e]
+				if i+n >= len(s) {
+					return "", 0, fmt.Errorf("short \\%c escape", e)
+				}
+				r, err := strconv.ParseUint(s[i+1:i+1+n], 16, 32)
+				if err != nil {
+					return "", 0, fmt.Errorf("bad \\%c escape", e)
+				}
+				b.WriteRune(rune(r))
+				i += n
+			default:
+				b.WriteByte(e) // \" \\ \/ and the rest stand for themselves
+			}
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return "", 0, fmt.Errorf("unterminated quoted scalar")
+}
+
+// foldQuoted applies line folding to a quoted scalar joined across lines:
+// the joins are spaces already, and a blank line is a newline.
+func foldQuoted(s string) string {
+	return strings.ReplaceAll(s, " \n", "\n")
+}
+
+// plainScalar resolves an unquoted scalar to null, a boolean, a number, or
+// a string, following the YAML 1.2 core schema.
+func plainScalar(s string) any {
+	switch s {
+	case "", "~", "null", "Null", "NULL":
+		return nil
+	case "true", "True", "TRUE":
+		return true
+	case "false", "False", "FALSE":
+		return false
+	}
+	if c := s[0]; (c == '-' || (c >= '0' && c <= '9')) && json.Valid([]byte(s)) {
+		return json.Number(s)
+	}
+	return s
+}
+
+// flowParser reads a flow collection: [a, b] or {k: v}.
+type flowParser struct {
+	src string
+	pos int
+}
+
+func (f *flowParser) skipSpace() {
+	for f.pos < len(f.src) && f.src[f.pos] == ' ' {
+		f.pos++
+	}
+}
+
+func (f *flowParser) value() (any, error) {
+	f.skipSpace()
+	if f.pos >= len(f.src) {
+		return nil, fmt.Errorf("unexpected end of flow collection")
+	}
+	switch f.src[f.pos] {
+	case '[':
+		f.pos++
+		seq := []any{}
+		for {
+			f.skipSpace()
+			if f.pos < len(f.src) && f.src[f.pos] == ']' {
+				f.pos++
+				return seq, nil
+			}
+			v, err := f.value()
+			if err != nil {
+				return nil, err
+			}
+			seq = append(seq, v)
+			if err := f.separator(']'); err != nil {
+				return nil, err
+			}
+		}
+	case '{':
+		f.pos++
+		m := map[string]any{}
+		for {
+			f.skipSpace()
+			if f.pos < len(f.src) && f.src[f.pos] == '}' {
+				f.pos++
+				return m, nil
+			}
+			k, err := f.scalar(true)
+			if err != nil {
+				return nil, err
+			}
+			f.skipSpace()
+			var v any
+			if f.pos < len(f.src) && f.src[f.pos] == ':' {
+				f.pos++
+				if v, err = f.value(); err != nil {
+					return nil, err
+				}
+			}
+			m[fmt.Sprint(k)] = v
+			if err := f.separator('}'); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return f.scalar(false)
+}
+
+// separator consumes a comma, or leaves the closing bracket for the caller.
+func (f *flowParser) separator(closing byte) error {
+	f.skipSpace()
+	if f.pos >= len(f.src) {
+		return fmt.Errorf("unterminated flow collection")
+	}
+	switch f.src[f.pos] {
+	case ',':
+		f.pos++
+		return nil
+	case closing:
+		return nil
+	}
+	return fmt.Errorf("expected , or %c at %q", closing, f.src[f.pos:])
+}
+
+func (f *flowParser) scalar(key bool) (any, error) {
+	f.skipSpace()
+	if c := f.src[f.pos]; c == '"' || c == '\'' {
+		s, n, err := quotedScalar(f.src[f.pos:])
+		if err != nil {
+			return nil, err
+		}
+		f.pos += n
+		return s, nil
+	}
+	start := f.pos
+	for f.pos < len(f.src) {
+		c := f.src[f.pos]
+		if c == ',' || c == ']' || c == '}' || (key && c == ':' && (f.pos+1 == len(f.src) || strings.IndexByte(" ,}", f.src[f.pos+1]) >= 0)) {
+			break
+		}
+		if c == ':' && f.pos+1 < len(f.src) && f.src[f.pos+1] == ' ' {
+			break
+		}
+		f.pos++
+	}
+	return plainScalar(strings.TrimSpace(f.src[start:f.pos])), nil
+}
@@ -0,0 +1,81 @@
+package yaml
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestDecode(t *testing.T) {
+	src := `---
+# comment
+openapi: 3.0.1
+info:
+  title: "Pet \"Store\""   # trailing comment
+  version: 1.0
+  description: |
+    Line one
+    Line two
+
+  summary: >-
+    folded
+    text
+tags: [pets, 'store''s', {name: x}]
+empty:
+nested:
+- name: id
+  in: path
+  required: true
+- - a
+  - b
+-
+  k: v
+list:
+- 1
+- -2.5
+- ~
+- yes # a string in YAML 1.2
+long: this plain
+  scalar continues
+url: http://example.com/#frag
+`
+	got, err := Decode(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]any{
+		"openapi": "3.0.1",
+		"info": map[string]any{
+			"title":       `Pet "Store"`,
+			"version":     json.Number("1.0"),
+			"description": "Line one\nLine two\n",
+			"summary":     "folded text",
+		},
+		"tags":  []any{"pets", "store's", map[string]any{"name": "x"}},
+		"empty": nil,
+		"nested": []any{
+			map[string]any{"name": "id", "in": "path", "required": true},
+			[]any{"a", "b"},
+			map[string]any{"k": "v"},
+		},
+		"list": []any{json.Number("1"), json.Number("-2.5"), nil, "yes"},
+		"long": "this plain scalar continues",
+		"url":  "http://example.com/#frag",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got  %#v\nwant %#v", got, want)
+	}
+
+	for _, bad := range []string{
+		"a: 1\na: 2",
+		"a: &x 1",
+		"a:\n\tb: 1",
+		"a: 'open",
+		"a: [1, 2",
+		"a: 1\n  b: 2",
+	} {
+		if _, err := Decode(bad); err == nil {
+			t.Errorf("%q: expected error", bad)
+		}
+	}
+}